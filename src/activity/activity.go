@@ -0,0 +1,231 @@
+// Package activity keeps a feed of what each user does that others may see, such as publishing a journal
+// entry, adding a certificate or starting a new role, for the activity tab of public profiles. Activities are
+// recorded from the audit log as the documents are written, and each document is read again when the feed
+// is, so documents since deleted, unpublished or hidden by their visibility rules drop out of it.
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/certificates"
+	"profile-api/experience"
+	"profile-api/journal"
+	"profile-api/store"
+	"profile-api/utils"
+	"profile-api/visibility"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// Sources are the repositories of the documents activities are about. Journals and Certificates are nil when
+// their module is disabled, leaving their activities out of feeds.
+type Sources struct {
+	Users        auth.Repository
+	Journals     journal.Repository
+	Certificates certificates.Repository
+	Experience   experience.Repository
+}
+
+var repo Repository
+var sources Sources
+
+// Configure sets where activities and the documents they are about are stored, and starts recording the
+// activities of changes to user data
+func Configure(r Repository, s Sources) {
+	repo = r
+	sources = s
+	audit.Subscribe(record)
+}
+
+// record saves the activity of a change recorded in the audit log, or removes the activity of a deleted
+// document
+func record(ctx context.Context, entry audit.Entry) {
+	kind, ok := kindOf(entry)
+	if !ok {
+		return
+	}
+	var err error
+	if entry.Action == audit.ActionDelete {
+		err = repo.Delete(ctx, entry.UserID, kind, entry.ResourceID)
+	} else {
+		err = repo.Save(ctx, Activity{UserID: entry.UserID, Kind: kind, ResourceID: entry.ResourceID, Time: entry.Time})
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Could not record activity", "kind", kind, "resource_id", entry.ResourceID, "error", err)
+	}
+}
+
+// kindOf returns the kind of activity of a change, reporting false for changes that are not activities:
+// anything but adding a certificate or role, publishing a journal entry, or deleting one of them
+func kindOf(entry audit.Entry) (string, bool) {
+	switch entry.Resource {
+	case "certificate":
+		return KindCertificate, entry.Action != audit.ActionUpdate
+	case "experience":
+		return KindRole, entry.Action != audit.ActionUpdate
+	case "journal":
+		if entry.Action == audit.ActionDelete {
+			return KindPost, true
+		}
+		var changes map[string]audit.Change
+		if err := json.Unmarshal(entry.Changes, &changes); err != nil {
+			return "", false
+		}
+		status, ok := changes["status"]
+		return KindPost, ok && status.After == journal.StatusPublic
+	}
+	return "", false
+}
+
+// GetActivity returns a page of a user's activity feed
+//
+//	@Summary		Get a user's activity
+//	@Description	Returns the user's recent public activity, newest first: journal entries they published, certificates they added and roles they added to their experience. Each item carries its document without the fields the requester may not see; documents since deleted or unpublished are left out, so a page may hold fewer items than the limit. Pass the returned next value as before to get the following page.
+//	@Tags			activity
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Param			before	query		string	false	"Only return activity from before this RFC 3339 time, as returned in next"
+//	@Param			limit	query		int		false	"Maximum number of activities to read (default 20, max 100)"
+//	@Success		200		{object}	Feed
+//	@Failure		400		{object}	apierror.Response	"Invalid before or limit"
+//	@Failure		404		{object}	apierror.Response	"User not found"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve activity"
+//	@Router			/activity/{userid} [get]
+func GetActivity(c *gin.Context) {
+	userID := c.Param("userid")
+	limit := defaultLimit
+	if l := c.Query("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil || limit < 1 || limit > maxLimit {
+			apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("limit must be between 1 and %d", maxLimit)))
+			return
+		}
+	}
+	before := time.Now()
+	if b := c.Query("before"); b != "" {
+		var err error
+		if before, err = time.Parse(time.RFC3339Nano, b); err != nil {
+			apierror.Abort(c, apierror.BadRequest("before must be an RFC 3339 time"))
+			return
+		}
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	user, err := sources.Users.FindByID(ctx, userID)
+	if err != nil || user.Disabled {
+		apierror.Abort(c, apierror.NotFound("User not found"))
+		return
+	}
+	activities, err := repo.List(ctx, userID, before, limit)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve activity"))
+		return
+	}
+	items, err := resolve(store.PublicRead(ctx), visibility.ViewerOf(c), userID, activities)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve activity"))
+		return
+	}
+
+	feed := Feed{Items: items}
+	if len(activities) == limit {
+		feed.Next = activities[len(activities)-1].Time.Format(time.RFC3339Nano)
+	}
+	c.Writer.Header().Add("Vary", "Cookie")
+	c.JSON(http.StatusOK, feed)
+}
+
+// resolve returns the items of the user's activities whose documents still exist and are public, each
+// document redacted for the viewer
+func resolve(ctx context.Context, viewer visibility.Viewer, userID string, activities []Activity) ([]Item, error) {
+	needed := map[string]bool{}
+	for _, a := range activities {
+		needed[a.Kind] = true
+	}
+	posts := map[string]any{}
+	if needed[KindPost] && sources.Journals != nil {
+		entries, err := sources.Journals.List(ctx, journal.Filter{UserID: userID, Status: journal.StatusPublic})
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			posts[entry.JournalID] = post(entry)
+		}
+	}
+	certs := map[string]any{}
+	if needed[KindCertificate] && sources.Certificates != nil {
+		list, err := sources.Certificates.List(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list {
+			doc, err := visibility.Redact(viewer, item)
+			if errors.Is(err, visibility.ErrHidden) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			certs[item.CertificateID] = doc
+		}
+	}
+	roles := map[string]any{}
+	if needed[KindRole] {
+		list, err := sources.Experience.List(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list {
+			if roles[item.ExperienceID], err = visibility.Redact(viewer, item); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	documents := map[string]map[string]any{KindPost: posts, KindCertificate: certs, KindRole: roles}
+	items := []Item{}
+	for _, a := range activities {
+		doc, ok := documents[a.Kind][a.ResourceID]
+		if !ok {
+			continue
+		}
+		items = append(items, Item{Kind: a.Kind, ResourceID: a.ResourceID, Time: a.Time, Document: doc})
+	}
+	return items, nil
+}
+
+// post returns the part of a public journal entry shown in feeds, with the title of its current version
+func post(entry journal.JournalEntry) Post {
+	p := Post{JournalID: entry.JournalID, Summary: entry.Summary, Tags: entry.Taxonomy.Tags}
+	for _, e := range entry.Entries {
+		if e.Version == entry.Version {
+			p.Title = e.Title
+		}
+	}
+	if p.Tags == nil {
+		p.Tags = []string{}
+	}
+	return p
+}
+
+// InitializeRoutes initializes the activity feed routes. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup) {
+	router.Use(auth.AuthMiddleware(sources.Users, false))
+	router.GET("/:userid", GetActivity)
+}
@@ -0,0 +1,46 @@
+package activity
+
+import "time"
+
+// Kinds of activity
+const (
+	// KindPost is a journal entry the user made public
+	KindPost = "post"
+	// KindCertificate is a certificate the user added
+	KindCertificate = "certificate"
+	// KindRole is a role the user added to their experience
+	KindRole = "role"
+)
+
+// Activity records that the user did something others may see, such as publishing a post
+type Activity struct {
+	UserID string `bson:"user_id"`
+	Kind   string `bson:"kind"`
+	// ResourceID is the ID of the journal entry, certificate or experience the activity is about
+	ResourceID string    `bson:"resource_id"`
+	Time       time.Time `bson:"time"`
+}
+
+// Item is an activity in a user's feed, with the document it is about as the requester may see it
+type Item struct {
+	Kind       string    `json:"kind"`
+	ResourceID string    `json:"resourceID"`
+	Time       time.Time `json:"time"`
+	// Document is the post, certificate or role, without the fields the requester may not see
+	Document any `json:"document" swaggertype:"object"`
+}
+
+// Feed is a page of a user's activity, newest first
+type Feed struct {
+	Items []Item `json:"items"`
+	// Next is the before parameter returning the next page, empty on the last page
+	Next string `json:"next,omitempty"`
+}
+
+// Post is the part of a public journal entry shown in a feed
+type Post struct {
+	JournalID string   `json:"journalID"`
+	Title     string   `json:"title"`
+	Summary   string   `json:"summary"`
+	Tags      []string `json:"tags"`
+}
@@ -0,0 +1,17 @@
+package activity
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores users' activities
+type Repository interface {
+	// Save records the activity unless the user already has one of the same kind about the same document, so
+	// a journal entry published again, such as after it is processed again, keeps its place in the feed
+	Save(ctx context.Context, a Activity) error
+	// List returns up to limit of the user's activities from before the time, newest first
+	List(ctx context.Context, userID string, before time.Time, limit int) ([]Activity, error)
+	// Delete removes the user's activity of the kind about the document, if any
+	Delete(ctx context.Context, userID, kind, resourceID string) error
+}
@@ -0,0 +1,53 @@
+package activity
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryRepository keeps activities in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu         sync.RWMutex
+	activities []Activity
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, a Activity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	exists := slices.ContainsFunc(r.activities, func(existing Activity) bool {
+		return existing.UserID == a.UserID && existing.Kind == a.Kind && existing.ResourceID == a.ResourceID
+	})
+	if !exists {
+		r.activities = append(r.activities, a)
+	}
+	return nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string, before time.Time, limit int) ([]Activity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []Activity
+	for _, a := range r.activities {
+		if a.UserID == userID && a.Time.Before(before) {
+			list = append(list, a)
+		}
+	}
+	slices.SortFunc(list, func(a, b Activity) int { return b.Time.Compare(a.Time) })
+	return list[:min(limit, len(list))], nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, userID, kind, resourceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.activities = slices.DeleteFunc(r.activities, func(a Activity) bool {
+		return a.UserID == userID && a.Kind == kind && a.ResourceID == resourceID
+	})
+	return nil
+}
@@ -0,0 +1,46 @@
+package activity
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores activities in the activity collection
+type MongoRepository struct {
+	activities *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{activities: db.Collection("activity")}
+}
+
+func (r *MongoRepository) Save(ctx context.Context, a Activity) error {
+	filter := bson.M{"user_id": a.UserID, "kind": a.Kind, "resource_id": a.ResourceID}
+	_, err := r.activities.UpdateOne(ctx, filter, bson.M{"$setOnInsert": bson.M{"time": a.Time}}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string, before time.Time, limit int) ([]Activity, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.activities.Find(ctx, bson.M{"user_id": userID, "time": bson.M{"$lt": before}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var list []Activity
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, userID, kind, resourceID string) error {
+	_, err := r.activities.DeleteOne(ctx, bson.M{"user_id": userID, "kind": kind, "resource_id": resourceID})
+	return err
+}
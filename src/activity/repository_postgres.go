@@ -0,0 +1,44 @@
+package activity
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository stores activities in the activity table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, a Activity) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO activity (user_id, kind, resource_id, time) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, kind, resource_id) DO NOTHING`,
+		a.UserID, a.Kind, a.ResourceID, a.Time)
+	return err
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string, before time.Time, limit int) ([]Activity, error) {
+	rows, err := r.pool.Query(ctx, `SELECT user_id, kind, resource_id, time FROM activity
+		WHERE user_id = $1 AND time < $2 ORDER BY time DESC LIMIT $3`, userID, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Activity, error) {
+		var a Activity
+		err := row.Scan(&a.UserID, &a.Kind, &a.ResourceID, &a.Time)
+		return a, err
+	})
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, userID, kind, resourceID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM activity WHERE user_id = $1 AND kind = $2 AND resource_id = $3", userID, kind, resourceID)
+	return err
+}
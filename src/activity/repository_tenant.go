@@ -0,0 +1,30 @@
+package activity
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Save(ctx context.Context, a Activity) error {
+	return r.repos.For(ctx).Save(ctx, a)
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID string, before time.Time, limit int) ([]Activity, error) {
+	return r.repos.For(ctx).List(ctx, userID, before, limit)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, userID, kind, resourceID string) error {
+	return r.repos.For(ctx).Delete(ctx, userID, kind, resourceID)
+}
@@ -0,0 +1,464 @@
+// Package activitypub federates the users' public journal entries with Mastodon and other ActivityPub
+// servers. Each user is an actor others can follow, found through WebFinger, whose outbox lists their
+// public journal entries as notes and whose followers receive each entry as it is made public.
+package activitypub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/journal"
+	"profile-api/moderation"
+	"profile-api/privacy"
+	"profile-api/profile"
+	"profile-api/sanitize"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// outboxPageSize is how many activities each page of an outbox lists
+	outboxPageSize = 20
+	// noteLength bounds the text of a journal entry quoted in its note, which links to the full entry
+	noteLength = 500
+)
+
+var repo Repository
+var users auth.Repository
+var profiles profile.Repository
+var journals journal.Repository
+
+var publicBaseURL = "http://localhost:8080"
+
+// Configure sets the repositories actors are built from and the delivery settings, registers the delivery
+// jobs and starts publishing journal entries as they are made public. It must be called before the job
+// workers start.
+func Configure(r Repository, u auth.Repository, p profile.Repository, j journal.Repository, cfg config.ActivityPubConfig) {
+	repo = r
+	users = u
+	profiles = p
+	journals = j
+	configureDelivery(cfg)
+}
+
+// SetBaseURL sets the public base URL the actors' IDs are built from
+func SetBaseURL(url string) {
+	publicBaseURL = strings.TrimSuffix(url, "/")
+}
+
+// baseURL returns the public base URL of the site the context belongs to
+func baseURL(ctx context.Context) string {
+	if t, ok := tenant.Current(ctx); ok && t.PublicBaseURL != "" {
+		return strings.TrimSuffix(t.PublicBaseURL, "/")
+	}
+	return publicBaseURL
+}
+
+// actorURL returns the ID of the user's actor
+func actorURL(ctx context.Context, userID string) string {
+	return baseURL(ctx) + "/ap/users/" + url.PathEscape(userID)
+}
+
+// noteURL returns the ID of the note of the journal entry
+func noteURL(ctx context.Context, userID, journalID string) string {
+	return actorURL(ctx, userID) + "/notes/" + url.PathEscape(journalID)
+}
+
+// keys returns the user's key pair, creating it the first time it is needed
+func keys(ctx context.Context, userID string) (KeyPair, error) {
+	pair, err := repo.GetKeys(ctx, userID)
+	if !errors.Is(err, store.ErrNotFound) {
+		return pair, err
+	}
+	pair, err = generateKeys(userID)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	// Another request may have created the user's keys meanwhile, in which case those are kept
+	return repo.ClaimKeys(ctx, pair)
+}
+
+// activeUser returns the user unless they do not exist, are disabled, their profile was hidden by moderation
+// or they restricted it, which all answer 404. Remote servers are never on a user's allowlist, so a
+// restricted profile is neither served nor delivered to them.
+func activeUser(ctx context.Context, userID string) (auth.User, error) {
+	user, err := users.FindByID(ctx, userID)
+	if err == nil && user.Disabled {
+		return auth.User{}, store.ErrNotFound
+	}
+	if err != nil {
+		return user, err
+	}
+	hidden, err := moderation.ProfileHidden(ctx, userID)
+	if err == nil && hidden {
+		return auth.User{}, store.ErrNotFound
+	}
+	if err != nil {
+		return user, err
+	}
+	restricted, err := privacy.Restricted(ctx, userID)
+	if err == nil && restricted {
+		return auth.User{}, store.ErrNotFound
+	}
+	return user, err
+}
+
+// writeActivity writes the document with the ActivityPub media type
+func writeActivity(c *gin.Context, status int, document any) {
+	writeJSON(c, status, ContentType, document)
+}
+
+// writeJSON writes the document as JSON with the given media type
+func writeJSON(c *gin.Context, status int, contentType string, document any) {
+	data, err := json.Marshal(document)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not encode response"))
+		return
+	}
+	c.Data(status, contentType+"; charset=utf-8", data)
+}
+
+// publicEntries lists the user's public journal entries, newest first
+func publicEntries(ctx context.Context, userID string) ([]journal.JournalEntry, error) {
+	entries, err := journals.List(ctx, journal.Filter{UserID: userID, Status: journal.StatusPublic})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(entries, func(a, b journal.JournalEntry) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return entries, nil
+}
+
+// note builds the note of a journal entry: its title, its summary or the start of its content, and a link
+// to the full entry, with its tags as hashtags
+func note(ctx context.Context, entry journal.JournalEntry) Note {
+	var latest journal.Entry
+	if len(entry.Entries) > 0 {
+		latest = entry.Entries[len(entry.Entries)-1]
+	}
+	text := strings.TrimSpace(entry.Summary)
+	if text == "" {
+		text = strings.TrimSpace(sanitize.StripTags(latest.Content))
+		if len(text) > noteLength {
+			text = strings.TrimSpace(strings.ToValidUTF8(text[:noteLength], "")) + "…"
+		}
+	}
+	link := baseURL(ctx) + "/api/v1/journal/" + url.PathEscape(entry.JournalID)
+
+	var content strings.Builder
+	if latest.Title != "" {
+		fmt.Fprintf(&content, "<p><strong>%s</strong></p>", html.EscapeString(latest.Title))
+	}
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			fmt.Fprintf(&content, "<p>%s</p>", strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		}
+	}
+	fmt.Fprintf(&content, `<p><a href="%s">%s</a></p>`, html.EscapeString(link), html.EscapeString(link))
+
+	tags := []Tag{}
+	for _, t := range entry.Taxonomy.Tags {
+		if name := hashtag(t); name != "" {
+			tags = append(tags, Tag{Type: "Hashtag", Name: "#" + name})
+		}
+	}
+	actor := actorURL(ctx, entry.UserID)
+	return Note{
+		ID:           noteURL(ctx, entry.UserID, entry.JournalID),
+		Type:         "Note",
+		AttributedTo: actor,
+		To:           []string{Public},
+		CC:           []string{actor + "/followers"},
+		Content:      content.String(),
+		URL:          link,
+		Published:    entry.CreatedAt.UTC(),
+		Updated:      entry.UpdatedAt.UTC(),
+		Tag:          tags,
+	}
+}
+
+// hashtag turns a tag into a hashtag name, dropping the characters hashtags cannot hold
+func hashtag(tag string) string {
+	var name strings.Builder
+	for _, r := range tag {
+		if r == '_' || ('0' <= r && r <= '9') || strings.ToLower(string(r)) != strings.ToUpper(string(r)) {
+			name.WriteRune(r)
+		}
+	}
+	return name.String()
+}
+
+// createActivity wraps the note of a journal entry in the activity publishing it
+func createActivity(ctx context.Context, entry journal.JournalEntry) Activity {
+	n := note(ctx, entry)
+	return Activity{
+		ID:        n.ID + "/activity",
+		Type:      "Create",
+		Actor:     n.AttributedTo,
+		To:        n.To,
+		CC:        n.CC,
+		Published: n.Published.Format("2006-01-02T15:04:05Z"),
+		Object:    n,
+	}
+}
+
+// WebFingerLookup resolves an account to its actor
+//
+//	@Summary		Find a user's actor
+//	@Description	Resolves acct:{userid}@{host} or the actor's URL to the user's ActivityPub actor, for Mastodon and other servers to follow them. Only available when ActivityPub federation is enabled.
+//	@Tags			activitypub
+//	@Produce		json
+//	@Param			resource	query		string	true	"The account, as acct:{userid}@{host}, or the actor's URL"
+//	@Success		200			{object}	WebFinger
+//	@Failure		400			{object}	apierror.Response	"Missing or invalid resource"
+//	@Failure		404			{object}	apierror.Response	"No such user on this site"
+//	@Failure		500			{object}	apierror.Response	"Could not find user"
+//	@Router			/.well-known/webfinger [get]
+func WebFingerLookup(c *gin.Context) {
+	resource := c.Query("resource")
+	if resource == "" {
+		apierror.Abort(c, apierror.BadRequest("resource is required"))
+		return
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	base, err := url.Parse(baseURL(ctx))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not find user"))
+		return
+	}
+
+	var userID string
+	if account, ok := strings.CutPrefix(resource, "acct:"); ok {
+		name, host, found := strings.Cut(account, "@")
+		if !found || !strings.EqualFold(host, base.Host) {
+			apierror.Abort(c, apierror.NotFound("No such user on this site"))
+			return
+		}
+		userID = name
+	} else if id, ok := strings.CutPrefix(resource, baseURL(ctx)+"/ap/users/"); ok && !strings.Contains(id, "/") {
+		userID, _ = url.PathUnescape(id)
+	} else {
+		apierror.Abort(c, apierror.NotFound("No such user on this site"))
+		return
+	}
+
+	if _, err := activeUser(ctx, userID); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not find user"))
+		return
+	}
+	actor := actorURL(ctx, userID)
+	c.Header("Access-Control-Allow-Origin", "*")
+	writeJSON(c, http.StatusOK, "application/jrd+json", WebFinger{
+		Subject: "acct:" + userID + "@" + base.Host,
+		Aliases: []string{actor},
+		Links:   []WebFingerLink{{Rel: "self", Type: ContentType, Href: actor}},
+	})
+}
+
+// GetActor returns the user's actor
+//
+//	@Summary		Get a user's actor
+//	@Description	Returns the ActivityPub Person representing the user, with their profile's name, bio and image and the key their deliveries are signed with. Only available when ActivityPub federation is enabled.
+//	@Tags			activitypub
+//	@Produce		json
+//	@Param			userid	path		string	true	"The ID of the user"
+//	@Success		200		{object}	Actor
+//	@Failure		404		{object}	apierror.Response	"User not found"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve actor"
+//	@Router			/ap/users/{userid} [get]
+func GetActor(c *gin.Context) {
+	userID := c.Param("userid")
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if _, err := activeUser(ctx, userID); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve actor"))
+		return
+	}
+	p, err := profiles.Get(ctx, userID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve actor"))
+		return
+	}
+	pair, err := keys(ctx, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve actor"))
+		return
+	}
+
+	id := actorURL(ctx, userID)
+	actor := Actor{
+		Context:           actorContext,
+		ID:                id,
+		Type:              "Person",
+		PreferredUsername: userID,
+		Inbox:             id + "/inbox",
+		Outbox:            id + "/outbox",
+		Followers:         id + "/followers",
+		PublicKey:         PublicKey{ID: id + "#main-key", Owner: id, PublicKeyPem: pair.PublicKey},
+		Discoverable:      true,
+	}
+	if p.Name != nil {
+		actor.Name = *p.Name
+	}
+	if p.Bio != nil && *p.Bio != "" {
+		actor.Summary = "<p>" + strings.ReplaceAll(html.EscapeString(*p.Bio), "\n", "<br>") + "</p>"
+	}
+	if p.ProfileImg != nil && *p.ProfileImg != "" {
+		actor.Icon = &Image{Type: "Image", URL: *p.ProfileImg}
+	}
+	writeActivity(c, http.StatusOK, actor)
+}
+
+// GetOutbox returns the user's outbox
+//
+//	@Summary		Get a user's outbox
+//	@Description	Returns the collection of the user's public journal entries as Create activities of notes. Without page it returns the collection, pointing at its first page; each page lists 20 activities, newest first. Only available when ActivityPub federation is enabled.
+//	@Tags			activitypub
+//	@Produce		json
+//	@Param			userid	path		string	true	"The ID of the user"
+//	@Param			page	query		int		false	"The page to return, from 1"
+//	@Success		200		{object}	OrderedCollectionPage	"A page of activities, or the OrderedCollection without page"
+//	@Failure		400		{object}	apierror.Response		"Invalid page"
+//	@Failure		404		{object}	apierror.Response		"User not found"
+//	@Failure		500		{object}	apierror.Response		"Could not retrieve outbox"
+//	@Router			/ap/users/{userid}/outbox [get]
+func GetOutbox(c *gin.Context) {
+	userID := c.Param("userid")
+	page := 0
+	if p := c.Query("page"); p != "" {
+		var err error
+		if page, err = strconv.Atoi(p); err != nil || page < 1 {
+			apierror.Abort(c, apierror.BadRequest("page must be a positive number"))
+			return
+		}
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if _, err := activeUser(ctx, userID); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve outbox"))
+		return
+	}
+	entries, err := publicEntries(store.PublicRead(ctx), userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve outbox"))
+		return
+	}
+
+	id := actorURL(ctx, userID) + "/outbox"
+	if page == 0 {
+		writeActivity(c, http.StatusOK, OrderedCollection{
+			Context:    objectContext,
+			ID:         id,
+			Type:       "OrderedCollection",
+			TotalItems: len(entries),
+			First:      id + "?page=1",
+		})
+		return
+	}
+	result := OrderedCollectionPage{
+		Context:      objectContext,
+		ID:           id + "?page=" + strconv.Itoa(page),
+		Type:         "OrderedCollectionPage",
+		PartOf:       id,
+		TotalItems:   len(entries),
+		OrderedItems: []Activity{},
+	}
+	start := min((page-1)*outboxPageSize, len(entries))
+	end := min(start+outboxPageSize, len(entries))
+	for _, entry := range entries[start:end] {
+		result.OrderedItems = append(result.OrderedItems, createActivity(ctx, entry))
+	}
+	if end < len(entries) {
+		result.Next = id + "?page=" + strconv.Itoa(page+1)
+	}
+	writeActivity(c, http.StatusOK, result)
+}
+
+// GetFollowers returns the size of the user's followers collection
+//
+//	@Summary		Get a user's followers
+//	@Description	Returns the collection of the user's followers, with their number only so followers are not disclosed. Only available when ActivityPub federation is enabled.
+//	@Tags			activitypub
+//	@Produce		json
+//	@Param			userid	path		string	true	"The ID of the user"
+//	@Success		200		{object}	OrderedCollection
+//	@Failure		404		{object}	apierror.Response	"User not found"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve followers"
+//	@Router			/ap/users/{userid}/followers [get]
+func GetFollowers(c *gin.Context) {
+	userID := c.Param("userid")
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if _, err := activeUser(ctx, userID); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve followers"))
+		return
+	}
+	followers, err := repo.Followers(ctx, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve followers"))
+		return
+	}
+	writeActivity(c, http.StatusOK, OrderedCollection{
+		Context:    objectContext,
+		ID:         actorURL(ctx, userID) + "/followers",
+		Type:       "OrderedCollection",
+		TotalItems: len(followers),
+	})
+}
+
+// GetNote returns the note of a public journal entry
+//
+//	@Summary		Get the note of a journal entry
+//	@Description	Returns the public journal entry as an ActivityPub note quoting its title and summary and linking to the full entry. Only available when ActivityPub federation is enabled.
+//	@Tags			activitypub
+//	@Produce		json
+//	@Param			userid		path		string	true	"The ID of the user"
+//	@Param			journalid	path		string	true	"The ID of the journal entry"
+//	@Success		200			{object}	Note
+//	@Failure		404			{object}	apierror.Response	"Journal entry not found or not public"
+//	@Failure		500			{object}	apierror.Response	"Could not retrieve note"
+//	@Router			/ap/users/{userid}/notes/{journalid} [get]
+func GetNote(c *gin.Context) {
+	userID := c.Param("userid")
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if _, err := activeUser(ctx, userID); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve note"))
+		return
+	}
+	entry, err := journals.GetOwned(ctx, c.Param("journalid"), userID)
+	if err == nil && entry.Status != journal.StatusPublic {
+		err = store.ErrNotFound
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve note"))
+		return
+	}
+	n := note(ctx, entry)
+	n.Context = objectContext
+	writeActivity(c, http.StatusOK, n)
+}
+
+// InitializeRoutes registers the WebFinger endpoint and the actors' routes on the root router
+func InitializeRoutes(router gin.IRoutes) {
+	router.GET("/.well-known/webfinger", WebFingerLookup)
+	router.GET("/ap/users/:userid", GetActor)
+	router.GET("/ap/users/:userid/outbox", GetOutbox)
+	router.GET("/ap/users/:userid/followers", GetFollowers)
+	router.GET("/ap/users/:userid/notes/:journalid", GetNote)
+	router.POST("/ap/users/:userid/inbox", PostInbox)
+}
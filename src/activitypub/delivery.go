@@ -0,0 +1,270 @@
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"profile-api/audit"
+	"profile-api/config"
+	"profile-api/events"
+	"profile-api/jobs"
+	"profile-api/journal"
+	"profile-api/netutil"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Types of the background jobs federating journal entries
+const (
+	// PublishJob sends an activity about a journal entry to every follower of its user
+	PublishJob = "activitypub.publish"
+	// DeliverJob posts an activity to a single inbox
+	DeliverJob = "activitypub.deliver"
+)
+
+// maxDocumentSize bounds the documents fetched from other servers
+const maxDocumentSize = 1 << 20
+
+var settings = config.ActivityPubConfig{Timeout: config.Duration(10 * time.Second)}
+var client = newClient(settings)
+
+type publishPayload struct {
+	UserID    string `json:"userID"`
+	JournalID string `json:"journalID"`
+	// Type is Create to publish the entry and Delete to withdraw it
+	Type string `json:"type"`
+}
+
+type deliverPayload struct {
+	UserID   string          `json:"userID"`
+	Inbox    string          `json:"inbox"`
+	Activity json.RawMessage `json:"activity"`
+}
+
+// configureDelivery registers the delivery jobs and starts publishing the journal entries made public and
+// withdrawing those made private or deleted
+func configureDelivery(cfg config.ActivityPubConfig) {
+	settings = cfg
+	client = newClient(cfg)
+
+	jobs.Register(PublishJob, func(ctx context.Context, job jobs.Job) error {
+		var payload publishPayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return err
+		}
+		return publish(ctx, payload)
+	})
+	jobs.Register(DeliverJob, func(ctx context.Context, job jobs.Job) error {
+		var payload deliverPayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return err
+		}
+		return deliver(ctx, payload)
+	})
+	events.Listen(publishStatusChange)
+	audit.Subscribe(withdrawDeleted)
+}
+
+// newClient creates the client used to fetch actors and deliver activities, refusing to connect to private
+// addresses unless allowed
+func newClient(cfg config.ActivityPubConfig) *http.Client {
+	return netutil.SafeClient(cfg.Timeout.Std(), cfg.AllowPrivateNetworks)
+}
+
+// publishStatusChange publishes a journal entry made public and withdraws one that no longer is
+func publishStatusChange(event events.Event) {
+	if event.Type != events.TypeJournalStatusChanged {
+		return
+	}
+	data, ok := event.Data.(gin.H)
+	if !ok {
+		return
+	}
+	journalID, _ := data["journalID"].(string)
+	var activity string
+	switch {
+	case data["to"] == journal.StatusPublic:
+		activity = "Create"
+	case data["from"] == journal.StatusPublic:
+		activity = "Delete"
+	default:
+		return
+	}
+	ctx, cancel := utils.WithOperationTimeout(tenant.WithID(context.Background(), event.Tenant))
+	defer cancel()
+	queuePublish(ctx, publishPayload{UserID: event.UserID, JournalID: journalID, Type: activity})
+}
+
+// withdrawDeleted withdraws a public journal entry that is deleted
+func withdrawDeleted(ctx context.Context, entry audit.Entry) {
+	if entry.Resource != "journal" || entry.Action != audit.ActionDelete {
+		return
+	}
+	var changes map[string]audit.Change
+	if err := json.Unmarshal(entry.Changes, &changes); err != nil || changes["status"].Before != journal.StatusPublic {
+		return
+	}
+	queuePublish(ctx, publishPayload{UserID: entry.UserID, JournalID: entry.ResourceID, Type: "Delete"})
+}
+
+func queuePublish(ctx context.Context, payload publishPayload) {
+	if err := jobs.Enqueue(ctx, PublishJob, payload); err != nil {
+		slog.ErrorContext(ctx, "Could not queue ActivityPub publication", "journal_id", payload.JournalID, "error", err)
+	}
+}
+
+// publish queues the delivery of the activity to every follower of the user, once for each server with a
+// shared inbox
+func publish(ctx context.Context, payload publishPayload) error {
+	followers, err := repo.Followers(ctx, payload.UserID)
+	if err != nil || len(followers) == 0 {
+		return err
+	}
+
+	var activity Activity
+	switch payload.Type {
+	case "Create":
+		entry, err := journals.GetOwned(ctx, payload.JournalID, payload.UserID)
+		if errors.Is(err, store.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if entry.Status != journal.StatusPublic {
+			// Made private again before it was published
+			return nil
+		}
+		// Nothing of a user whose profile was hidden by moderation or restricted is sent out
+		_, err = activeUser(ctx, payload.UserID)
+		if errors.Is(err, store.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		activity = createActivity(ctx, entry)
+	case "Delete":
+		id := noteURL(ctx, payload.UserID, payload.JournalID)
+		activity = Activity{
+			ID:     id + "#delete-" + strconv.FormatInt(time.Now().Unix(), 10),
+			Type:   "Delete",
+			Actor:  actorURL(ctx, payload.UserID),
+			To:     []string{Public},
+			Object: map[string]string{"id": id, "type": "Tombstone"},
+		}
+	default:
+		return fmt.Errorf("unknown activity type %s", payload.Type)
+	}
+	activity.Context = objectContext
+	data, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+
+	inboxes := map[string]bool{}
+	for _, f := range followers {
+		inbox := f.SharedInbox
+		if inbox == "" {
+			inbox = f.Inbox
+		}
+		if inboxes[inbox] {
+			continue
+		}
+		inboxes[inbox] = true
+		if err := jobs.Enqueue(ctx, DeliverJob, deliverPayload{UserID: payload.UserID, Inbox: inbox, Activity: data}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver posts the activity to the inbox, signed with the user's key. Errors the other server may recover
+// from are returned for the job queue to retry the delivery with backoff; inboxes refusing the activity are
+// given up on.
+func deliver(ctx context.Context, payload deliverPayload) error {
+	resp, err := signedRequest(ctx, http.MethodPost, payload.Inbox, payload.UserID, payload.Activity)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout:
+		return fmt.Errorf("inbox %s returned %s", payload.Inbox, resp.Status)
+	default:
+		slog.WarnContext(ctx, "ActivityPub inbox refused delivery", "user_id", payload.UserID, "inbox", payload.Inbox, "status", resp.StatusCode)
+		return nil
+	}
+}
+
+// signedRequest sends a request signed with the user's key, with the body as the activity posted
+func signedRequest(ctx context.Context, method, target, userID string, body []byte) (*http.Response, error) {
+	pair, err := keys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKey(pair.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "profile-api-activitypub")
+	if body != nil {
+		req.Header.Set("Content-Type", ContentType)
+	} else {
+		req.Header.Set("Accept", ContentType+`, application/ld+json; profile="https://www.w3.org/ns/activitystreams"`)
+	}
+	if err := sign(req, body, actorURL(ctx, userID)+"#main-key", key); err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
+// errGone is returned when the fetched document has been deleted, as happens to the keys of deleted actors
+var errGone = errors.New("document is gone")
+
+// fetchActor fetches an actor from its server, signing the request with the user's key as servers in
+// Mastodon's secure mode require. The document must be the actor with the ID fetched, so a server can't
+// pass off another actor as its own.
+func fetchActor(ctx context.Context, id, userID string) (remoteActor, error) {
+	resp, err := signedRequest(ctx, http.MethodGet, id, userID, nil)
+	if err != nil {
+		return remoteActor{}, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusGone || resp.StatusCode == http.StatusNotFound:
+		return remoteActor{}, errGone
+	case resp.StatusCode >= 300:
+		return remoteActor{}, fmt.Errorf("%s returned %s", id, resp.Status)
+	}
+	var actor remoteActor
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(&actor); err != nil {
+		return remoteActor{}, fmt.Errorf("could not decode actor %s: %w", id, err)
+	}
+	if actor.ID != id {
+		return remoteActor{}, fmt.Errorf("%s returned the actor %s", id, actor.ID)
+	}
+	return actor, nil
+}
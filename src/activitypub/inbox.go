@@ -0,0 +1,167 @@
+package activitypub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/jobs"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxActivitySize bounds the activities accepted in an inbox
+const maxActivitySize = 256 << 10
+
+// PostInbox receives an activity addressed to the user
+//
+//	@Summary		Deliver an activity to a user
+//	@Description	Receives an ActivityPub activity signed by its actor with HTTP Signatures. Follow activities add the actor to the user's followers, who are accepted straight away and receive the user's journal entries as they are made public; Undo of a Follow and Delete of the actor remove them. Other activities are ignored. Only available when ActivityPub federation is enabled.
+//	@Tags			activitypub
+//	@Accept			application/activity+json,application/ld+json,json
+//	@Param			userid		path	string	true	"The ID of the user"
+//	@Param			activity	body	object	true	"The activity, as JSON-LD"
+//	@Success		202			"Accepted"
+//	@Failure		400			{object}	apierror.Response	"Invalid activity"
+//	@Failure		401			{object}	apierror.Response	"Missing or invalid signature"
+//	@Failure		404			{object}	apierror.Response	"User not found"
+//	@Failure		500			{object}	apierror.Response	"Could not process activity"
+//	@Router			/ap/users/{userid}/inbox [post]
+func PostInbox(c *gin.Context) {
+	userID := c.Param("userid")
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxActivitySize+1))
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Could not read activity"))
+		return
+	}
+	if len(body) > maxActivitySize {
+		apierror.Abort(c, apierror.TooLarge("Activity is too large"))
+		return
+	}
+	var activity incoming
+	if err := json.Unmarshal(body, &activity); err != nil || activity.Type == "" || activity.Actor == "" {
+		apierror.Abort(c, apierror.BadRequest("Invalid activity"))
+		return
+	}
+	params, err := parseSignature(c.Request)
+	if err != nil {
+		apierror.Abort(c, apierror.Unauthorized(err.Error()))
+		return
+	}
+
+	ctx, cancel := utils.WithOperationTimeout(c.Request.Context())
+	defer cancel()
+	if _, err := activeUser(ctx, userID); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not process activity"))
+		return
+	}
+
+	// The key belongs to the actor it is listed under, which must be the activity's actor
+	keyOwner, _, _ := strings.Cut(params.KeyID, "#")
+	if !sameOrigin(keyOwner, activity.Actor) {
+		apierror.Abort(c, apierror.Unauthorized("The activity is not signed by its actor"))
+		return
+	}
+	actor, err := fetchActor(ctx, keyOwner, userID)
+	if errors.Is(err, errGone) && activity.Type == "Delete" && activity.Actor == keyOwner {
+		// A deleted actor's key can no longer be fetched, but its server confirms it is gone
+		if err := repo.RemoveFollower(ctx, userID, activity.Actor); err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not process activity"))
+			return
+		}
+		c.Status(http.StatusAccepted)
+		return
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Could not fetch the key of an ActivityPub request", "key_id", params.KeyID, "error", err)
+		apierror.Abort(c, apierror.Unauthorized("Could not fetch the signing key"))
+		return
+	}
+	if actor.PublicKey.ID != params.KeyID || actor.PublicKey.Owner != actor.ID || actor.ID != activity.Actor {
+		apierror.Abort(c, apierror.Unauthorized("The activity is not signed by its actor"))
+		return
+	}
+	key, err := parsePublicKey(actor.PublicKey.PublicKeyPem)
+	if err != nil {
+		apierror.Abort(c, apierror.Unauthorized("Invalid signing key"))
+		return
+	}
+	if err := verifySignature(c.Request, body, params, key); err != nil {
+		apierror.Abort(c, apierror.Unauthorized(err.Error()))
+		return
+	}
+
+	objectID, objectType := objectRef(activity.Object)
+	switch activity.Type {
+	case "Follow":
+		if objectID != actorURL(ctx, userID) {
+			apierror.Abort(c, apierror.BadRequest("Follow is not addressed to this actor"))
+			return
+		}
+		if actor.Inbox == "" {
+			apierror.Abort(c, apierror.BadRequest("The actor has no inbox"))
+			return
+		}
+		err = follow(ctx, userID, actor, body)
+	case "Undo":
+		if objectType == "Follow" || objectType == "" {
+			err = repo.RemoveFollower(ctx, userID, activity.Actor)
+		}
+	case "Delete":
+		if objectID == activity.Actor {
+			err = repo.RemoveFollower(ctx, userID, activity.Actor)
+		}
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not process activity"))
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// follow adds the actor to the user's followers and queues the Accept of their Follow activity
+func follow(ctx context.Context, userID string, actor remoteActor, followActivity json.RawMessage) error {
+	err := repo.AddFollower(ctx, Follower{
+		UserID:      userID,
+		ActorID:     actor.ID,
+		Inbox:       actor.Inbox,
+		SharedInbox: actor.Endpoints.SharedInbox,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	accept, err := json.Marshal(Activity{
+		Context: objectContext,
+		ID:      actorURL(ctx, userID) + "#accepts/" + utils.GenerateID(),
+		Type:    "Accept",
+		Actor:   actorURL(ctx, userID),
+		Object:  followActivity,
+	})
+	if err != nil {
+		return err
+	}
+	return jobs.Enqueue(ctx, DeliverJob, deliverPayload{UserID: userID, Inbox: actor.Inbox, Activity: accept})
+}
+
+// sameOrigin reports whether both URLs are served by the same scheme, host and port, as a server only
+// speaks for the actors it hosts
+func sameOrigin(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil || ua.Host == "" {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(ua.Scheme, ub.Scheme) && strings.EqualFold(ua.Host, ub.Host)
+}
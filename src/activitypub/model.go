@@ -0,0 +1,29 @@
+package activitypub
+
+import "time"
+
+// KeyPair is the RSA key pair a user's actor signs its requests with, PEM encoded
+type KeyPair struct {
+	UserID     string    `bson:"_id"`
+	PublicKey  string    `bson:"public_key"`
+	PrivateKey string    `bson:"private_key"`
+	CreatedAt  time.Time `bson:"created_at"`
+}
+
+// Follower is an actor on another server following a user
+type Follower struct {
+	// ID combines the user's ID and the follower's actor ID, see followerID
+	ID     string `bson:"_id"`
+	UserID string `bson:"user_id"`
+	// ActorID is the URL of the following actor
+	ActorID string `bson:"actor_id"`
+	// Inbox is where activities are delivered to the follower
+	Inbox string `bson:"inbox"`
+	// SharedInbox is where activities are delivered once for every follower on the same server, if it has one
+	SharedInbox string    `bson:"shared_inbox,omitempty"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
+
+func followerID(userID, actorID string) string {
+	return userID + " " + actorID
+}
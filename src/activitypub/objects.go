@@ -0,0 +1,146 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ContentType is the media type of ActivityPub documents
+const ContentType = "application/activity+json"
+
+// Public is the special collection addressing an activity to everyone
+const Public = "https://www.w3.org/ns/activitystreams#Public"
+
+var actorContext = []string{"https://www.w3.org/ns/activitystreams", "https://w3id.org/security/v1"}
+var objectContext = []string{"https://www.w3.org/ns/activitystreams"}
+
+// Actor is the Person representing a user to other servers
+type Actor struct {
+	Context           []string  `json:"@context"`
+	ID                string    `json:"id"`
+	Type              string    `json:"type"`
+	PreferredUsername string    `json:"preferredUsername"`
+	Name              string    `json:"name,omitempty"`
+	Summary           string    `json:"summary,omitempty"`
+	Icon              *Image    `json:"icon,omitempty"`
+	Inbox             string    `json:"inbox"`
+	Outbox            string    `json:"outbox"`
+	Followers         string    `json:"followers"`
+	PublicKey         PublicKey `json:"publicKey"`
+	// Discoverable lets Mastodon list the actor in its directory and suggest it to follow
+	Discoverable bool `json:"discoverable"`
+}
+
+// Image is an image attached to an object
+type Image struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// PublicKey is the key an actor's requests are signed with
+type PublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+// Note is a public journal entry as a post
+type Note struct {
+	Context      []string  `json:"@context,omitempty"`
+	ID           string    `json:"id"`
+	Type         string    `json:"type"`
+	AttributedTo string    `json:"attributedTo"`
+	To           []string  `json:"to"`
+	CC           []string  `json:"cc"`
+	Content      string    `json:"content"`
+	URL          string    `json:"url"`
+	Published    time.Time `json:"published"`
+	Updated      time.Time `json:"updated,omitempty"`
+	Tag          []Tag     `json:"tag"`
+}
+
+// Tag is a hashtag of a note
+type Tag struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// Activity is an action of an actor on an object, such as creating a note or following another actor
+type Activity struct {
+	Context   []string `json:"@context,omitempty"`
+	ID        string   `json:"id"`
+	Type      string   `json:"type"`
+	Actor     string   `json:"actor"`
+	To        []string `json:"to,omitempty"`
+	CC        []string `json:"cc,omitempty"`
+	Published string   `json:"published,omitempty"`
+	// Object is the object acted on, either embedded or as its ID
+	Object any `json:"object" swaggertype:"object"`
+}
+
+// OrderedCollection is a collection such as the outbox, whose items are listed in pages
+type OrderedCollection struct {
+	Context    []string `json:"@context"`
+	ID         string   `json:"id"`
+	Type       string   `json:"type"`
+	TotalItems int      `json:"totalItems"`
+	First      string   `json:"first,omitempty"`
+}
+
+// OrderedCollectionPage is a page of a collection's items, newest first
+type OrderedCollectionPage struct {
+	Context      []string   `json:"@context"`
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	PartOf       string     `json:"partOf"`
+	TotalItems   int        `json:"totalItems"`
+	OrderedItems []Activity `json:"orderedItems"`
+	Next         string     `json:"next,omitempty"`
+}
+
+// WebFinger is the JSON resource descriptor resolving an account to its actor
+type WebFinger struct {
+	Subject string          `json:"subject"`
+	Aliases []string        `json:"aliases"`
+	Links   []WebFingerLink `json:"links"`
+}
+
+// WebFingerLink links an account to one of its representations
+type WebFingerLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type"`
+	Href string `json:"href"`
+}
+
+// incoming is an activity received in an inbox, of which only what the inbox acts on is read
+type incoming struct {
+	ID     string          `json:"id"`
+	Type   string          `json:"type"`
+	Actor  string          `json:"actor"`
+	Object json.RawMessage `json:"object"`
+}
+
+// remoteActor is an actor of another server, as fetched from it
+type remoteActor struct {
+	ID        string `json:"id"`
+	Inbox     string `json:"inbox"`
+	Endpoints struct {
+		SharedInbox string `json:"sharedInbox"`
+	} `json:"endpoints"`
+	PublicKey PublicKey `json:"publicKey"`
+}
+
+// objectRef reads the ID and type of an object either embedded or given as its ID
+func objectRef(raw json.RawMessage) (id, typ string) {
+	if err := json.Unmarshal(raw, &id); err == nil {
+		return id, ""
+	}
+	var object struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return "", ""
+	}
+	return object.ID, object.Type
+}
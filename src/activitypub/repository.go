@@ -0,0 +1,17 @@
+package activitypub
+
+import "context"
+
+// Repository stores the actors' keys and followers
+type Repository interface {
+	// ClaimKeys stores the user's key pair unless they already have one, returning the stored pair
+	ClaimKeys(ctx context.Context, keys KeyPair) (KeyPair, error)
+	// GetKeys returns the user's key pair, or store.ErrNotFound
+	GetKeys(ctx context.Context, userID string) (KeyPair, error)
+	// AddFollower stores the follower, replacing the stored one if the actor already follows the user
+	AddFollower(ctx context.Context, follower Follower) error
+	// RemoveFollower removes the actor from the user's followers
+	RemoveFollower(ctx context.Context, userID, actorID string) error
+	// Followers returns the user's followers, oldest first
+	Followers(ctx context.Context, userID string) ([]Follower, error)
+}
@@ -0,0 +1,72 @@
+package activitypub
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps keys and followers in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu        sync.Mutex
+	keys      map[string]KeyPair
+	followers map[string]Follower
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{keys: map[string]KeyPair{}, followers: map[string]Follower{}}
+}
+
+func (r *MemoryRepository) ClaimKeys(ctx context.Context, keys KeyPair) (KeyPair, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.keys[keys.UserID]; ok {
+		return existing, nil
+	}
+	r.keys[keys.UserID] = keys
+	return keys, nil
+}
+
+func (r *MemoryRepository) GetKeys(ctx context.Context, userID string) (KeyPair, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys, ok := r.keys[userID]
+	if !ok {
+		return KeyPair{}, store.ErrNotFound
+	}
+	return keys, nil
+}
+
+func (r *MemoryRepository) AddFollower(ctx context.Context, follower Follower) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	follower.ID = followerID(follower.UserID, follower.ActorID)
+	if existing, ok := r.followers[follower.ID]; ok {
+		follower.CreatedAt = existing.CreatedAt
+	}
+	r.followers[follower.ID] = follower
+	return nil
+}
+
+func (r *MemoryRepository) RemoveFollower(ctx context.Context, userID, actorID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.followers, followerID(userID, actorID))
+	return nil
+}
+
+func (r *MemoryRepository) Followers(ctx context.Context, userID string) ([]Follower, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var followers []Follower
+	for _, f := range r.followers {
+		if f.UserID == userID {
+			followers = append(followers, f)
+		}
+	}
+	slices.SortFunc(followers, func(a, b Follower) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return followers, nil
+}
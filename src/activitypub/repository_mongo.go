@@ -0,0 +1,68 @@
+package activitypub
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores keys in the activitypub_keys collection and followers in activitypub_followers
+type MongoRepository struct {
+	keys      *mongo.Collection
+	followers *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{keys: db.Collection("activitypub_keys"), followers: db.Collection("activitypub_followers")}
+}
+
+func (r *MongoRepository) ClaimKeys(ctx context.Context, keys KeyPair) (KeyPair, error) {
+	_, err := r.keys.InsertOne(ctx, keys)
+	if err == nil {
+		return keys, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return KeyPair{}, err
+	}
+	return r.GetKeys(ctx, keys.UserID)
+}
+
+func (r *MongoRepository) GetKeys(ctx context.Context, userID string) (KeyPair, error) {
+	var keys KeyPair
+	err := r.keys.FindOne(ctx, bson.M{"_id": userID}).Decode(&keys)
+	return keys, store.MongoErr(err)
+}
+
+func (r *MongoRepository) AddFollower(ctx context.Context, follower Follower) error {
+	follower.ID = followerID(follower.UserID, follower.ActorID)
+	_, err := r.followers.UpdateByID(ctx, follower.ID, bson.M{
+		"$set": bson.M{
+			"user_id":      follower.UserID,
+			"actor_id":     follower.ActorID,
+			"inbox":        follower.Inbox,
+			"shared_inbox": follower.SharedInbox,
+		},
+		"$setOnInsert": bson.M{"created_at": follower.CreatedAt},
+	}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) RemoveFollower(ctx context.Context, userID, actorID string) error {
+	_, err := r.followers.DeleteOne(ctx, bson.M{"_id": followerID(userID, actorID)})
+	return err
+}
+
+func (r *MongoRepository) Followers(ctx context.Context, userID string) ([]Follower, error) {
+	cursor, err := r.followers.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var followers []Follower
+	err = cursor.All(ctx, &followers)
+	return followers, err
+}
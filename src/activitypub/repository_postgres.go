@@ -0,0 +1,64 @@
+package activitypub
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository stores keys in the activitypub_keys table and followers in activitypub_followers
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) ClaimKeys(ctx context.Context, keys KeyPair) (KeyPair, error) {
+	_, err := r.pool.Exec(ctx, `INSERT INTO activitypub_keys (user_id, public_key, private_key, created_at)
+		VALUES ($1, $2, $3, $4) ON CONFLICT (user_id) DO NOTHING`,
+		keys.UserID, keys.PublicKey, keys.PrivateKey, keys.CreatedAt)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	return r.GetKeys(ctx, keys.UserID)
+}
+
+func (r *PostgresRepository) GetKeys(ctx context.Context, userID string) (KeyPair, error) {
+	var keys KeyPair
+	err := r.pool.QueryRow(ctx, "SELECT user_id, public_key, private_key, created_at FROM activitypub_keys WHERE user_id = $1", userID).
+		Scan(&keys.UserID, &keys.PublicKey, &keys.PrivateKey, &keys.CreatedAt)
+	return keys, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) AddFollower(ctx context.Context, follower Follower) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO activitypub_followers (user_id, actor_id, inbox, shared_inbox, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, actor_id) DO UPDATE SET inbox = excluded.inbox, shared_inbox = excluded.shared_inbox`,
+		follower.UserID, follower.ActorID, follower.Inbox, follower.SharedInbox, follower.CreatedAt)
+	return err
+}
+
+func (r *PostgresRepository) RemoveFollower(ctx context.Context, userID, actorID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM activitypub_followers WHERE user_id = $1 AND actor_id = $2", userID, actorID)
+	return err
+}
+
+func (r *PostgresRepository) Followers(ctx context.Context, userID string) ([]Follower, error) {
+	rows, err := r.pool.Query(ctx, `SELECT user_id, actor_id, inbox, shared_inbox, created_at FROM activitypub_followers
+		WHERE user_id = $1 ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Follower, error) {
+		var f Follower
+		err := row.Scan(&f.UserID, &f.ActorID, &f.Inbox, &f.SharedInbox, &f.CreatedAt)
+		f.ID = followerID(f.UserID, f.ActorID)
+		return f, err
+	})
+}
@@ -0,0 +1,37 @@
+package activitypub
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) ClaimKeys(ctx context.Context, keys KeyPair) (KeyPair, error) {
+	return r.repos.For(ctx).ClaimKeys(ctx, keys)
+}
+
+func (r *TenantRepository) GetKeys(ctx context.Context, userID string) (KeyPair, error) {
+	return r.repos.For(ctx).GetKeys(ctx, userID)
+}
+
+func (r *TenantRepository) AddFollower(ctx context.Context, follower Follower) error {
+	return r.repos.For(ctx).AddFollower(ctx, follower)
+}
+
+func (r *TenantRepository) RemoveFollower(ctx context.Context, userID, actorID string) error {
+	return r.repos.For(ctx).RemoveFollower(ctx, userID, actorID)
+}
+
+func (r *TenantRepository) Followers(ctx context.Context, userID string) ([]Follower, error) {
+	return r.repos.For(ctx).Followers(ctx, userID)
+}
@@ -0,0 +1,207 @@
+package activitypub
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxClockSkew bounds how far the Date of a signed request may be from now, as Mastodon does
+const maxClockSkew = 12 * time.Hour
+
+var errNotRSAKey = errors.New("not an RSA public key")
+
+// generateKeys creates a new RSA key pair for the user's actor
+func generateKeys(userID string) (KeyPair, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	private, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	return KeyPair{
+		UserID:     userID,
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})),
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: private})),
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+func parsePrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("invalid private key PEM")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA private key")
+	}
+	return rsaKey, nil
+}
+
+func parsePublicKey(data string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("invalid public key PEM")
+	}
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errNotRSAKey
+	}
+	return rsaKey, nil
+}
+
+// digest is the value of the Digest header of a request with the body
+func digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// signingString builds the string the signature covers from the request and the listed headers
+func signingString(req *http.Request, headers []string) (string, error) {
+	lines := make([]string, len(headers))
+	for i, h := range headers {
+		switch h {
+		case "(request-target)":
+			lines[i] = h + ": " + strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			lines[i] = h + ": " + req.Host
+		default:
+			value := req.Header.Get(h)
+			if value == "" {
+				return "", fmt.Errorf("signed header %s is missing", h)
+			}
+			lines[i] = h + ": " + value
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// sign adds the Date, Digest and Signature headers to the request, signing it with the key under keyID as
+// described by the draft HTTP Signatures specification Mastodon implements
+func sign(req *http.Request, body []byte, keyID string, key *rsa.PrivateKey) error {
+	if req.Host == "" {
+		req.Host = req.URL.Host
+	}
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	headers := []string{"(request-target)", "host", "date"}
+	if body != nil {
+		req.Header.Set("Digest", digest(body))
+		headers = append(headers, "digest")
+	}
+	text, err := signingString(req, headers)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256([]byte(text))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		keyID, strings.Join(headers, " "), base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// signatureParams holds the parameters of a Signature header
+type signatureParams struct {
+	KeyID     string
+	Headers   []string
+	Signature []byte
+}
+
+// parseSignature reads the Signature header of the request
+func parseSignature(req *http.Request) (signatureParams, error) {
+	header := req.Header.Get("Signature")
+	if header == "" {
+		return signatureParams{}, errors.New("request is not signed")
+	}
+	params := signatureParams{Headers: []string{"date"}}
+	for _, part := range strings.Split(header, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		switch name {
+		case "keyId":
+			params.KeyID = value
+		case "headers":
+			params.Headers = strings.Fields(strings.ToLower(value))
+		case "signature":
+			signature, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return signatureParams{}, errors.New("signature is not valid base64")
+			}
+			params.Signature = signature
+		case "algorithm":
+			if value != "rsa-sha256" && value != "hs2019" {
+				return signatureParams{}, fmt.Errorf("unsupported signature algorithm %s", value)
+			}
+		}
+	}
+	if params.KeyID == "" || len(params.Signature) == 0 {
+		return signatureParams{}, errors.New("signature has no keyId or signature")
+	}
+	return params, nil
+}
+
+// verifySignature checks the signature covers the request target, host, date and, for requests with a
+// body, its digest, that the date is recent and the digest matches, and that the key signed it
+func verifySignature(req *http.Request, body []byte, params signatureParams, key *rsa.PublicKey) error {
+	required := []string{"(request-target)", "host", "date"}
+	if req.Method == http.MethodPost {
+		required = append(required, "digest")
+	}
+	for _, h := range required {
+		if !slices.Contains(params.Headers, h) {
+			return fmt.Errorf("signature does not cover %s", h)
+		}
+	}
+	date, err := http.ParseTime(req.Header.Get("Date"))
+	if err != nil {
+		return errors.New("invalid Date header")
+	}
+	if d := time.Since(date); d > maxClockSkew || d < -maxClockSkew {
+		return errors.New("request date is too far from now")
+	}
+	if req.Method == http.MethodPost && req.Header.Get("Digest") != digest(body) {
+		return errors.New("digest does not match the body")
+	}
+	text, err := signingString(req, params.Headers)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256([]byte(text))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], params.Signature); err != nil {
+		return errors.New("signature does not match")
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package activitypub
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	pair, err := generateKeys("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := parsePrivateKey(pair.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := generateKeys("mallory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := parsePrivateKey(other.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := parsePublicKey(pair.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"type":"Follow"}`)
+	const keyID = "https://remote.example/users/alice#main-key"
+
+	for _, tc := range []struct {
+		name string
+		// prepare signs the request, changing it before or after as the case needs
+		prepare func(t *testing.T, req *http.Request)
+		ok      bool
+	}{
+		{"valid", func(t *testing.T, req *http.Request) {
+			mustSign(t, req, body, keyID, key)
+		}, true},
+		{"signed by another key", func(t *testing.T, req *http.Request) {
+			mustSign(t, req, body, keyID, otherKey)
+		}, false},
+		{"body changed after signing", func(t *testing.T, req *http.Request) {
+			mustSign(t, req, []byte(`{"type":"Delete"}`), keyID, key)
+		}, false},
+		{"digest replaced", func(t *testing.T, req *http.Request) {
+			mustSign(t, req, body, keyID, key)
+			req.Header.Set("Digest", digest([]byte(`{"type":"Delete"}`)))
+		}, false},
+		{"target changed after signing", func(t *testing.T, req *http.Request) {
+			mustSign(t, req, body, keyID, key)
+			req.URL.Path = "/users/bob/inbox"
+		}, false},
+		{"replayed a day later", func(t *testing.T, req *http.Request) {
+			mustSign(t, req, body, keyID, key)
+			req.Header.Set("Date", time.Now().Add(-24*time.Hour).UTC().Format(http.TimeFormat))
+		}, false},
+		{"digest not covered", func(t *testing.T, req *http.Request) {
+			mustSign(t, req, nil, keyID, key)
+			req.Header.Set("Digest", digest(body))
+		}, false},
+		{"unsupported algorithm", func(t *testing.T, req *http.Request) {
+			mustSign(t, req, body, keyID, key)
+			req.Header.Set("Signature", req.Header.Get("Signature")+`,algorithm="rsa-sha1"`)
+		}, false},
+		{"unsigned", func(t *testing.T, req *http.Request) {}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "https://local.example/users/alice/inbox", bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.prepare(t, req)
+			params, err := parseSignature(req)
+			if err == nil {
+				err = verifySignature(req, body, params, public)
+			}
+			if (err == nil) != tc.ok {
+				t.Errorf("got error %v, want valid %t", err, tc.ok)
+			}
+		})
+	}
+}
+
+// mustSign signs the request, failing the test when it can't
+func mustSign(t *testing.T, req *http.Request, body []byte, keyID string, key *rsa.PrivateKey) {
+	t.Helper()
+	if err := sign(req, body, keyID, key); err != nil {
+		t.Fatal(err)
+	}
+}
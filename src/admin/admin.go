@@ -0,0 +1,307 @@
+// Package admin lets admins manage user accounts and inspect the platform's storage without connecting to
+// the database. Every route requires the admin role.
+package admin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/email"
+	"profile-api/profile"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultListLimit   = 50
+	maxListLimit       = 500
+	defaultSignupDays  = 7
+	maxSignupDays      = 365
+	passwordResetEmail = "password_reset"
+)
+
+var repo Repository
+var users auth.Repository
+
+var publicBaseURL = "http://localhost:8080"
+
+// SetBaseURL sets the public base URL the password reset email points users to
+func SetBaseURL(url string) {
+	publicBaseURL = strings.TrimSuffix(url, "/")
+}
+
+// baseURL returns the public base URL of the site the context belongs to
+func baseURL(ctx context.Context) string {
+	if t, ok := tenant.Current(ctx); ok && t.PublicBaseURL != "" {
+		return strings.TrimSuffix(t.PublicBaseURL, "/")
+	}
+	return publicBaseURL
+}
+
+// ListUsers lists registered users
+//
+//	@Summary		List users
+//	@Description	Lists registered users, most recently registered first, optionally searching their names and email addresses. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			q		query		string	false	"Text to find in the name or email address, ignoring case"
+//	@Param			limit	query		int		false	"Maximum number of users to return (default 50, max 500)"
+//	@Success		200		{array}		User
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve users"
+//	@Router			/admin/users [get]
+func ListUsers(c *gin.Context) {
+	limit := defaultListLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, maxListLimit)
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	found, err := users.List(ctx, auth.UserFilter{Query: strings.TrimSpace(c.Query("q")), Limit: limit})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve users"))
+		return
+	}
+
+	c.JSON(http.StatusOK, userViews(found))
+}
+
+// ListSignups lists the users who registered recently
+//
+//	@Summary		List recent signups
+//	@Description	Lists the users who registered within the last days, most recent first. Users registered before signup times were recorded are never included. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			days	query		int	false	"Number of days to look back (default 7, max 365)"
+//	@Param			limit	query		int	false	"Maximum number of users to return (default 50, max 500)"
+//	@Success		200		{object}	Signups
+//	@Failure		400		{object}	apierror.Response	"Invalid number of days"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve signups"
+//	@Router			/admin/signups [get]
+func ListSignups(c *gin.Context) {
+	days := defaultSignupDays
+	if d := c.Query("days"); d != "" {
+		var err error
+		days, err = strconv.Atoi(d)
+		if err != nil || days < 1 || days > maxSignupDays {
+			apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("days must be between 1 and %d", maxSignupDays)))
+			return
+		}
+	}
+	limit := defaultListLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, maxListLimit)
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	since := time.Now().AddDate(0, 0, -days)
+	found, err := users.List(ctx, auth.UserFilter{Since: since, Limit: limit})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve signups"))
+		return
+	}
+
+	views := userViews(found)
+	c.JSON(http.StatusOK, Signups{Days: days, Count: len(views), Users: views})
+}
+
+// GetRejectedSignups reports the registrations rejected as automated
+//
+//	@Summary		Get rejected signups
+//	@Description	Returns how many registrations this replica rejected as automated since it started, by reason: the hidden honeypot field filled in, a disposable email domain, the form submitted too quickly, or a missing or expired form token. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	auth.RegistrationRejections
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		403	{object}	apierror.Response	"Admin access required"
+//	@Router			/admin/signups/rejected [get]
+func GetRejectedSignups(c *gin.Context) {
+	c.JSON(http.StatusOK, auth.Rejections())
+}
+
+// GetProfileCache reports how well the full profile cache serves
+//
+//	@Summary		Get full profile cache statistics
+//	@Description	Returns the hits, misses and invalidations of the full profile cache on this replica since it started, and how many full profiles it keeps when they are kept in its memory rather than in Redis. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	profile.CacheStats
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		403	{object}	apierror.Response	"Admin access required"
+//	@Router			/admin/cache/profiles [get]
+func GetProfileCache(c *gin.Context) {
+	c.JSON(http.StatusOK, profile.AggregateCacheStats())
+}
+
+// DisableUser disables a user's account
+//
+//	@Summary		Disable a user
+//	@Description	Disables a user's account. The user can no longer log in and their existing sessions stop working. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Success		200		{string}	string				"User disabled"
+//	@Failure		400		{object}	apierror.Response	"Admins cannot disable their own account"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		404		{object}	apierror.Response	"User not found"
+//	@Router			/admin/users/{userid}/disable [post]
+func DisableUser(c *gin.Context) {
+	userID := c.Param("userid")
+	if userID == c.MustGet("userID").(string) {
+		apierror.Abort(c, apierror.BadRequest("Admins cannot disable their own account"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := users.SetDisabled(ctx, userID, true); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "User not found"))
+		return
+	}
+	slog.InfoContext(ctx, "User disabled", "user_id", userID, "admin_id", c.MustGet("userID"))
+
+	c.JSON(http.StatusOK, gin.H{"message": "User disabled"})
+}
+
+// EnableUser re-enables a disabled user's account
+//
+//	@Summary		Enable a user
+//	@Description	Re-enables a disabled user's account so they can log in again. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Success		200		{string}	string				"User enabled"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		404		{object}	apierror.Response	"User not found"
+//	@Router			/admin/users/{userid}/enable [post]
+func EnableUser(c *gin.Context) {
+	userID := c.Param("userid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := users.SetDisabled(ctx, userID, false); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "User not found"))
+		return
+	}
+	slog.InfoContext(ctx, "User enabled", "user_id", userID, "admin_id", c.MustGet("userID"))
+
+	c.JSON(http.StatusOK, gin.H{"message": "User enabled"})
+}
+
+// passwordResetData is rendered into the password reset email
+type passwordResetData struct {
+	Name     string
+	Token    string
+	ResetURL string
+}
+
+// ForcePasswordReset requires a user to choose a new password
+//
+//	@Summary		Force a password reset
+//	@Description	Requires a user to choose a new password. Their existing sessions stop working and they cannot log in until they set a new password with the token emailed to them. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Success		200		{string}	string				"Password reset required"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		404		{object}	apierror.Response	"User not found"
+//	@Failure		500		{object}	apierror.Response	"Could not send password reset email"
+//	@Router			/admin/users/{userid}/password-reset [post]
+func ForcePasswordReset(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+
+	user, err := users.FindByID(ctx, c.Param("userid"))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "User not found"))
+		return
+	}
+	token := utils.GenerateID()
+	if err := users.RequirePasswordReset(ctx, user.ID, token); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "User not found"))
+		return
+	}
+
+	msg, err := email.Render(passwordResetEmail, passwordResetData{
+		Name:     user.Name,
+		Token:    token,
+		ResetURL: baseURL(ctx) + "/api/v1/auth/password-reset",
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not send password reset email"))
+		return
+	}
+	msg.To = user.Email
+	msg.UserID = user.ID
+	if err := email.Enqueue(ctx, msg); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not send password reset email"))
+		return
+	}
+	slog.InfoContext(ctx, "Password reset required", "user_id", user.ID, "admin_id", c.MustGet("userID"))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset required"})
+}
+
+// GetStats reports the platform's storage
+//
+//	@Summary		Get platform statistics
+//	@Description	Returns the number of documents and storage used by each collection, and the storage used altogether. In-memory storage reports no collections. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	Stats
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		403	{object}	apierror.Response	"Admin access required"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve statistics"
+//	@Router			/admin/stats [get]
+func GetStats(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	stats, err := repo.Stats(ctx)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve statistics"))
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// userViews converts the users for admins
+func userViews(found []auth.User) []User {
+	views := make([]User, 0, len(found))
+	for _, u := range found {
+		views = append(views, userView(u))
+	}
+	return views
+}
+
+// InitializeRoutes registers the user management and statistics endpoints. The router must only admit admins.
+func InitializeRoutes(router *gin.RouterGroup, r Repository, u auth.Repository) {
+	repo = r
+	users = u
+
+	router.GET("/users", ListUsers)
+	router.POST("/users/:userid/disable", DisableUser)
+	router.POST("/users/:userid/enable", EnableUser)
+	router.POST("/users/:userid/password-reset", ForcePasswordReset)
+	router.GET("/signups", ListSignups)
+	router.GET("/signups/rejected", GetRejectedSignups)
+	router.GET("/cache/profiles", GetProfileCache)
+	router.GET("/stats", GetStats)
+}
@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"time"
+
+	"profile-api/auth"
+)
+
+// User is a registered user as shown to admins, without their password or reset token
+type User struct {
+	ID                    string     `json:"id"`
+	Name                  string     `json:"name"`
+	Email                 string     `json:"email"`
+	Admin                 bool       `json:"admin"`
+	Disabled              bool       `json:"disabled"`
+	PasswordResetRequired bool       `json:"passwordResetRequired"`
+	CreatedAt             *time.Time `json:"createdAt,omitempty"`
+}
+
+// Signups lists the users who registered within the requested number of days
+type Signups struct {
+	Days  int    `json:"days"`
+	Count int    `json:"count"`
+	Users []User `json:"users"`
+}
+
+// CollectionStats describes the documents stored in a single collection or table
+type CollectionStats struct {
+	Name  string `bson:"name" json:"name"`
+	Count int64  `bson:"count" json:"count"`
+	// Bytes is the storage used by the collection, including its indexes
+	Bytes int64 `bson:"bytes" json:"bytes"`
+}
+
+// Stats describes the platform's storage
+type Stats struct {
+	Collections []CollectionStats `json:"collections"`
+	// StorageBytes is the storage used by every collection together
+	StorageBytes int64 `json:"storageBytes"`
+}
+
+// userView converts a user for admins, leaving out the signup time of users registered before it was recorded
+func userView(u auth.User) User {
+	view := User{
+		ID:                    u.ID,
+		Name:                  u.Name,
+		Email:                 u.Email,
+		Admin:                 u.Admin,
+		Disabled:              u.Disabled,
+		PasswordResetRequired: u.ResetToken != "",
+	}
+	if !u.CreatedAt.IsZero() {
+		view.CreatedAt = &u.CreatedAt
+	}
+	return view
+}
@@ -0,0 +1,9 @@
+package admin
+
+import "context"
+
+// Repository reports on the storage of the platform
+type Repository interface {
+	// Stats returns the number of documents and storage used by each collection
+	Stats(ctx context.Context) (Stats, error)
+}
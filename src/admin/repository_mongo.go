@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"context"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoRepository reports on the collections of a Mongo database
+type MongoRepository struct {
+	db *mongo.Database
+}
+
+// NewMongoRepository creates a repository reporting on the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{db: db}
+}
+
+func (r *MongoRepository) Stats(ctx context.Context) (Stats, error) {
+	names, err := r.db.ListCollectionNames(ctx, bson.M{"type": "collection"})
+	if err != nil {
+		return Stats{}, err
+	}
+	slices.Sort(names)
+
+	stats := Stats{Collections: []CollectionStats{}}
+	for _, name := range names {
+		var result struct {
+			Count          int64 `bson:"count"`
+			StorageSize    int64 `bson:"storageSize"`
+			TotalIndexSize int64 `bson:"totalIndexSize"`
+		}
+		if err := r.db.RunCommand(ctx, bson.D{{Key: "collStats", Value: name}}).Decode(&result); err != nil {
+			return Stats{}, err
+		}
+		collection := CollectionStats{Name: name, Count: result.Count, Bytes: result.StorageSize + result.TotalIndexSize}
+		stats.Collections = append(stats.Collections, collection)
+		stats.StorageBytes += collection.Bytes
+	}
+	return stats, nil
+}
@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository reports on the tables of a PostgreSQL database
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository reporting on the database of the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Stats(ctx context.Context) (Stats, error) {
+	rows, err := r.pool.Query(ctx, `SELECT relname, pg_total_relation_size(relid) FROM pg_stat_user_tables
+		WHERE schemaname = current_schema() ORDER BY relname`)
+	if err != nil {
+		return Stats{}, err
+	}
+	collections, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (CollectionStats, error) {
+		var c CollectionStats
+		err := row.Scan(&c.Name, &c.Bytes)
+		return c, err
+	})
+	if err != nil {
+		return Stats{}, err
+	}
+
+	// The statistics views only estimate row counts, so count each table exactly
+	stats := Stats{Collections: []CollectionStats{}}
+	for _, c := range collections {
+		query := "SELECT count(*) FROM " + pgx.Identifier{c.Name}.Sanitize()
+		if err := r.pool.QueryRow(ctx, query).Scan(&c.Count); err != nil {
+			return Stats{}, err
+		}
+		stats.Collections = append(stats.Collections, c)
+		stats.StorageBytes += c.Bytes
+	}
+	return stats, nil
+}
@@ -0,0 +1,21 @@
+package admin
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Stats(ctx context.Context) (Stats, error) {
+	return r.repos.For(ctx).Stats(ctx)
+}
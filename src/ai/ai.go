@@ -0,0 +1,97 @@
+// Package ai sends prompts to the configured AI provider: OpenAI, any service compatible with its chat
+// completions API through base-url, or Anthropic.
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"profile-api/config"
+)
+
+// ErrDisabled is returned when no AI provider is configured
+var ErrDisabled = errors.New("no AI provider configured")
+
+var aiHTTPClient = &http.Client{Timeout: 2 * time.Minute}
+
+// Request is a prompt sent to the provider
+type Request struct {
+	// System holds the instructions the prompt is answered under
+	System string
+	Prompt string
+	// MaxTokens bounds the length of the answer, defaulting to defaultMaxTokens
+	MaxTokens int
+}
+
+const defaultMaxTokens = 4096
+
+// Provider answers prompts with a language model
+type Provider interface {
+	Complete(ctx context.Context, req Request) (string, error)
+}
+
+var provider Provider
+
+// Configure sets the provider prompts are sent to, leaving AI features disabled when none is configured,
+// and where the requests counted against each user's quota are stored
+func Configure(cfg config.AIConfig, r UsageRepository) {
+	usage = r
+	quota = cfg.Quota
+	switch cfg.Provider {
+	case "openai":
+		provider = NewOpenAIProvider(cfg)
+	case "anthropic":
+		provider = NewAnthropicProvider(cfg)
+	default:
+		provider = nil
+	}
+}
+
+// Enabled reports whether a provider is configured
+func Enabled() bool {
+	return provider != nil
+}
+
+// Complete returns the provider's answer to the prompt, or ErrDisabled
+func Complete(ctx context.Context, req Request) (string, error) {
+	if provider == nil {
+		return "", ErrDisabled
+	}
+	if req.MaxTokens == 0 {
+		req.MaxTokens = defaultMaxTokens
+	}
+	return provider.Complete(ctx, req)
+}
+
+// CompleteJSON asks for an answer in JSON and decodes it into v. The prompt should describe the JSON
+// expected.
+func CompleteJSON(ctx context.Context, req Request, v any) error {
+	req.System = strings.TrimSpace(req.System + "\n\nAnswer with a single JSON value only, without any other text.")
+	answer, err := Complete(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(extractJSON(answer)), v); err != nil {
+		return fmt.Errorf("AI provider did not answer with valid JSON: %w", err)
+	}
+	return nil
+}
+
+// extractJSON returns the JSON value in an answer, which models sometimes wrap in a Markdown code block
+// or surround with prose despite being asked not to
+func extractJSON(answer string) string {
+	start := strings.IndexAny(answer, "{[")
+	if start < 0 {
+		return answer
+	}
+	end := strings.LastIndexAny(answer, "}]")
+	if end < start {
+		return answer
+	}
+	return answer[start : end+1]
+}
@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"profile-api/config"
+)
+
+const (
+	anthropicBaseURL = "https://api.anthropic.com/v1"
+	anthropicVersion = "2023-06-01"
+)
+
+// AnthropicProvider answers prompts with Anthropic's messages API
+type AnthropicProvider struct {
+	apiKey  string
+	model   string
+	baseURL string
+}
+
+// NewAnthropicProvider creates a provider using the configured API key and model
+func NewAnthropicProvider(cfg config.AIConfig) *AnthropicProvider {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = anthropicBaseURL
+	}
+	return &AnthropicProvider{apiKey: cfg.APIKey, model: cfg.Model, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+	MaxTokens int                `json:"max_tokens"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+func (p *AnthropicProvider) Complete(ctx context.Context, req Request) (string, error) {
+	data, err := json.Marshal(anthropicRequest{
+		Model:     p.model,
+		System:    req.System,
+		Messages:  []anthropicMessage{{Role: "user", Content: req.Prompt}},
+		MaxTokens: req.MaxTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/messages", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("x-api-key", p.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := aiHTTPClient.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("anthropic returned %s: %s", resp.Status, bytes.TrimSpace(detail))
+	}
+	var answer anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return "", fmt.Errorf("could not decode anthropic response: %w", err)
+	}
+	var text strings.Builder
+	for _, block := range answer.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String(), nil
+}
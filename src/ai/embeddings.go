@@ -0,0 +1,146 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"profile-api/config"
+)
+
+const voyageBaseURL = "https://api.voyageai.com/v1"
+
+// What a text is embedded for. Some providers embed queries and the documents they search differently.
+const (
+	EmbedDocument = "document"
+	EmbedQuery    = "query"
+)
+
+// Embedder turns texts into vectors whose cosine similarity measures how close the texts are in meaning
+type Embedder interface {
+	// Embed returns the vector of each text, in order
+	Embed(ctx context.Context, texts []string, purpose string) ([][]float32, error)
+	// Model names the model the vectors come from, as vectors of different models cannot be compared
+	Model() string
+}
+
+var embedder Embedder
+
+// ConfigureEmbeddings sets the provider texts are embedded with, leaving semantic search disabled when none
+// is configured
+func ConfigureEmbeddings(cfg config.EmbeddingsConfig) {
+	switch cfg.Provider {
+	case "openai":
+		embedder = NewEmbeddingProvider(cfg, openAIBaseURL, false)
+	case "voyage":
+		embedder = NewEmbeddingProvider(cfg, voyageBaseURL, true)
+	default:
+		embedder = nil
+	}
+}
+
+// EmbeddingsEnabled reports whether an embedding provider is configured
+func EmbeddingsEnabled() bool {
+	return embedder != nil
+}
+
+// Embed returns the vector of each text from the configured provider, or ErrDisabled
+func Embed(ctx context.Context, texts []string, purpose string) ([][]float32, error) {
+	if embedder == nil {
+		return nil, ErrDisabled
+	}
+	return embedder.Embed(ctx, texts, purpose)
+}
+
+// EmbeddingModel names the model of the configured provider, or returns empty when none is configured
+func EmbeddingModel() string {
+	if embedder == nil {
+		return ""
+	}
+	return embedder.Model()
+}
+
+// EmbeddingProvider embeds texts with the embeddings API shared by OpenAI, Voyage AI and compatible
+// services
+type EmbeddingProvider struct {
+	apiKey  string
+	model   string
+	baseURL string
+	// inputType sends the purpose of the texts, which Voyage AI embeds differently
+	inputType bool
+}
+
+// NewEmbeddingProvider creates a provider using the configured API key and model, at the default base URL
+// unless another is configured
+func NewEmbeddingProvider(cfg config.EmbeddingsConfig, defaultBaseURL string, inputType bool) *EmbeddingProvider {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &EmbeddingProvider{apiKey: cfg.APIKey, model: cfg.Model, baseURL: strings.TrimSuffix(baseURL, "/"), inputType: inputType}
+}
+
+type embeddingRequest struct {
+	Model     string   `json:"model"`
+	Input     []string `json:"input"`
+	InputType string   `json:"input_type,omitempty"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+func (p *EmbeddingProvider) Model() string {
+	return p.model
+}
+
+func (p *EmbeddingProvider) Embed(ctx context.Context, texts []string, purpose string) ([][]float32, error) {
+	body := embeddingRequest{Model: p.model, Input: texts}
+	if p.inputType {
+		body.InputType = purpose
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/embeddings", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := aiHTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("embedding provider returned %s: %s", resp.Status, bytes.TrimSpace(detail))
+	}
+	var answer embeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return nil, fmt.Errorf("could not decode embedding response: %w", err)
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range answer.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding provider returned an embedding for unknown input %d", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("embedding provider returned no embedding for input %d", i)
+		}
+	}
+	return vectors, nil
+}
@@ -0,0 +1,84 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"profile-api/config"
+)
+
+const openAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIProvider answers prompts with OpenAI's chat completions API, or a compatible one at the base URL
+type OpenAIProvider struct {
+	apiKey  string
+	model   string
+	baseURL string
+}
+
+// NewOpenAIProvider creates a provider using the configured API key and model
+func NewOpenAIProvider(cfg config.AIConfig) *OpenAIProvider {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = openAIBaseURL
+	}
+	return &OpenAIProvider{apiKey: cfg.APIKey, model: cfg.Model, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model     string          `json:"model"`
+	Messages  []openAIMessage `json:"messages"`
+	MaxTokens int             `json:"max_tokens"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (p *OpenAIProvider) Complete(ctx context.Context, req Request) (string, error) {
+	body := openAIRequest{Model: p.model, MaxTokens: req.MaxTokens}
+	if req.System != "" {
+		body.Messages = append(body.Messages, openAIMessage{Role: "system", Content: req.System})
+	}
+	body.Messages = append(body.Messages, openAIMessage{Role: "user", Content: req.Prompt})
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := aiHTTPClient.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("openai returned %s: %s", resp.Status, bytes.TrimSpace(detail))
+	}
+	var answer openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return "", fmt.Errorf("could not decode openai response: %w", err)
+	}
+	if len(answer.Choices) == 0 {
+		return "", fmt.Errorf("openai returned no answer")
+	}
+	return answer.Choices[0].Message.Content, nil
+}
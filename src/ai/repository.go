@@ -0,0 +1,10 @@
+package ai
+
+import "context"
+
+// UsageRepository stores the AI requests each user made per quota period
+type UsageRepository interface {
+	// Increment counts a request by the user in the period unless they already made limit requests in it,
+	// returning their count and whether the request was counted
+	Increment(ctx context.Context, userID, period string, limit int) (int, bool, error)
+}
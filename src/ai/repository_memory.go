@@ -0,0 +1,35 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRepository keeps usage in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu    sync.Mutex
+	usage map[string]Usage
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{usage: map[string]Usage{}}
+}
+
+func (r *MemoryRepository) Increment(ctx context.Context, userID, period string, limit int) (int, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := usageID(userID, period)
+	u, ok := r.usage[id]
+	if !ok {
+		u = Usage{ID: id, UserID: userID, Period: period}
+	}
+	if u.Requests >= limit {
+		return u.Requests, false, nil
+	}
+	u.Requests++
+	u.UpdatedAt = time.Now()
+	r.usage[id] = u
+	return u.Requests, true, nil
+}
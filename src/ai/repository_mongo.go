@@ -0,0 +1,39 @@
+package ai
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores usage in the ai_usage collection
+type MongoRepository struct {
+	usage *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{usage: db.Collection("ai_usage")}
+}
+
+func (r *MongoRepository) Increment(ctx context.Context, userID, period string, limit int) (int, bool, error) {
+	filter := bson.M{"_id": usageID(userID, period), "requests": bson.M{"$lt": limit}}
+	update := bson.M{
+		"$inc": bson.M{"requests": 1},
+		"$set": bson.M{"user_id": userID, "period": period, "updated_at": time.Now()},
+	}
+	var u Usage
+	err := r.usage.FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&u)
+	// At the limit the filter matches no document, and the upsert collides with the existing one
+	if mongo.IsDuplicateKeyError(err) {
+		return limit, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return u.Requests, true, nil
+}
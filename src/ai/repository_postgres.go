@@ -0,0 +1,35 @@
+package ai
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository stores usage in the ai_usage table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Increment(ctx context.Context, userID, period string, limit int) (int, bool, error) {
+	var requests int
+	err := r.pool.QueryRow(ctx, `INSERT INTO ai_usage (user_id, period, requests, updated_at) VALUES ($1, $2, 1, now())
+		ON CONFLICT (user_id, period) DO UPDATE SET requests = ai_usage.requests + 1, updated_at = now()
+		WHERE ai_usage.requests < $3
+		RETURNING requests`, userID, period, limit).Scan(&requests)
+	// At the limit the conflicting row is left alone and nothing is returned
+	if errors.Is(err, pgx.ErrNoRows) {
+		return limit, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return requests, true, nil
+}
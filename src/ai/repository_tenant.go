@@ -0,0 +1,21 @@
+package ai
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[UsageRepository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[UsageRepository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Increment(ctx context.Context, userID, period string, limit int) (int, bool, error) {
+	return r.repos.For(ctx).Increment(ctx, userID, period, limit)
+}
@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/config"
+)
+
+// Usage counts the AI requests a user made in a period
+type Usage struct {
+	// ID is the user's ID and the period, see usageID
+	ID     string `bson:"_id"`
+	UserID string `bson:"user_id"`
+	// Period is the day, as 2006-01-02, or the month, as 2006-01, the requests were made in
+	Period    string    `bson:"period"`
+	Requests  int       `bson:"requests"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func usageID(userID, period string) string {
+	return userID + "/" + period
+}
+
+var usage UsageRepository
+var quota config.AIQuotaConfig
+
+// period returns the quota period the time falls in
+func period(t time.Time) string {
+	if quota.Period == "day" {
+		return t.UTC().Format(time.DateOnly)
+	}
+	return t.UTC().Format("2006-01")
+}
+
+// periodEnd returns when the quota period the time falls in ends
+func periodEnd(t time.Time) time.Time {
+	t = t.UTC()
+	if quota.Period == "day" {
+		return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Use counts a request by the user against their quota before it is sent to the provider, returning a 429
+// API error once they have used the quota up
+func Use(ctx context.Context, userID string) error {
+	limit := quota.Requests
+	if limit == 0 {
+		limit = math.MaxInt32
+	}
+	now := time.Now()
+	_, counted, err := usage.Increment(ctx, userID, period(now), limit)
+	if err != nil {
+		return err
+	}
+	if !counted {
+		reset := periodEnd(now)
+		return apierror.TooManyRequests("AI request quota used up until " + reset.Format(time.RFC3339)).
+			WithDetails(map[string]any{"limit": quota.Requests, "reset_at": reset})
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"profile-api/logging"
+	"profile-api/requestid"
+	"profile-api/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Machine-readable error codes returned in the code field of the error envelope
+const (
+	CodeBadRequest           = "bad_request"
+	CodeValidationFailed     = "validation_failed"
+	CodeUnauthorized         = "unauthorized"
+	CodePaymentRequired      = "payment_required"
+	CodeForbidden            = "forbidden"
+	CodeSudoRequired         = "sudo_required"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeConflict             = "conflict"
+	CodeUnprocessableEntity  = "unprocessable_entity"
+	CodeTooLarge             = "payload_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeQuotaExceeded        = "quota_exceeded"
+	CodePreconditionFailed   = "precondition_failed"
+	CodePreconditionRequired = "precondition_required"
+	CodeTooManyRequests      = "too_many_requests"
+	CodeInternal             = "internal_error"
+	CodeNotImplemented       = "not_implemented"
+	CodeServiceUnavailable   = "service_unavailable"
+	CodeTimeout              = "timeout"
+)
+
+// Response is the error envelope returned by every endpoint.
+//
+// swagger:model Response
+type Response struct {
+	// Machine-readable error code
+	// example: not_found
+	Code string `json:"code"`
+	// Human-readable error message
+	// example: Journal entry not found
+	Message string `json:"message"`
+	// Optional structured details, such as per-field validation errors
+	Details   any    `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// FieldError describes why a single request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error is an error that carries the HTTP status and envelope to respond with
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Details any
+	// Err is the underlying cause, logged but never returned to the client
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithDetails returns a copy of the error carrying the given details
+func (e *Error) WithDetails(details any) *Error {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// New creates an error with an explicit status and code
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// BadRequest creates a 400 error
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, CodeBadRequest, message)
+}
+
+// Unauthorized creates a 401 error
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+// PaymentRequired creates a 402 error, for what the user's plan does not include
+func PaymentRequired(message string) *Error {
+	return New(http.StatusPaymentRequired, CodePaymentRequired, message)
+}
+
+// Forbidden creates a 403 error
+func Forbidden(message string) *Error {
+	return New(http.StatusForbidden, CodeForbidden, message)
+}
+
+// NotFound creates a 404 error
+func NotFound(message string) *Error {
+	return New(http.StatusNotFound, CodeNotFound, message)
+}
+
+// MethodNotAllowed creates a 405 error
+func MethodNotAllowed(message string) *Error {
+	return New(http.StatusMethodNotAllowed, CodeMethodNotAllowed, message)
+}
+
+// Conflict creates a 409 error
+func Conflict(message string) *Error {
+	return New(http.StatusConflict, CodeConflict, message)
+}
+
+// TooLarge creates a 413 error
+func TooLarge(message string) *Error {
+	return New(http.StatusRequestEntityTooLarge, CodeTooLarge, message)
+}
+
+// UnsupportedMediaType creates a 415 error
+func UnsupportedMediaType(message string) *Error {
+	return New(http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, message)
+}
+
+// QuotaExceeded creates a 413 error for a write that would take the user past one of their quotas
+func QuotaExceeded(message string) *Error {
+	return New(http.StatusRequestEntityTooLarge, CodeQuotaExceeded, message)
+}
+
+// PreconditionFailed creates a 412 error, for a write whose If-Match header no longer matches the resource
+func PreconditionFailed(message string) *Error {
+	return New(http.StatusPreconditionFailed, CodePreconditionFailed, message)
+}
+
+// PreconditionRequired creates a 428 error, for a write missing a required If-Match header
+func PreconditionRequired(message string) *Error {
+	return New(http.StatusPreconditionRequired, CodePreconditionRequired, message)
+}
+
+// TooManyRequests creates a 429 error, for a user who has used up a quota
+func TooManyRequests(message string) *Error {
+	return New(http.StatusTooManyRequests, CodeTooManyRequests, message)
+}
+
+// Unprocessable creates a 422 error
+func Unprocessable(message string) *Error {
+	return New(http.StatusUnprocessableEntity, CodeUnprocessableEntity, message)
+}
+
+// Internal creates a 500 error
+func Internal(message string) *Error {
+	return New(http.StatusInternalServerError, CodeInternal, message)
+}
+
+// Wrap converts an error returned by a dependency into an API error with the given message.
+// Well known errors are mapped to a matching status, anything else is treated as an internal error.
+func Wrap(err error, message string) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	var validationErrs validator.ValidationErrors
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var sizeErr *http.MaxBytesError
+
+	e := Internal(message)
+	switch {
+	case errors.As(err, &sizeErr):
+		e = TooLarge("Request body is too large")
+	case errors.As(err, &validationErrs):
+		e = New(http.StatusBadRequest, CodeValidationFailed, "Request validation failed").WithDetails(fieldErrors(validationErrs))
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		e = BadRequest("Invalid request body")
+	case errors.Is(err, mongo.ErrNoDocuments), errors.Is(err, store.ErrNotFound):
+		e = NotFound(message)
+	case mongo.IsDuplicateKeyError(err), errors.Is(err, store.ErrConflict):
+		e = Conflict(message)
+	case errors.Is(err, context.DeadlineExceeded) || mongo.IsTimeout(err):
+		e = New(http.StatusGatewayTimeout, CodeTimeout, message)
+	case mongo.IsNetworkError(err):
+		e = New(http.StatusServiceUnavailable, CodeServiceUnavailable, message)
+	}
+	e.Err = err
+	return e
+}
+
+// Invalid converts an error from binding a request body into a 400 error, listing each rejected field
+// when the body failed validation, or a 413 error when the body was over its size limit.
+func Invalid(err error) *Error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return Wrap(err, "Request validation failed")
+	}
+	var sizeErr *http.MaxBytesError
+	if errors.As(err, &sizeErr) {
+		return Wrap(err, "Request body is too large")
+	}
+	e := BadRequest("Invalid request body")
+	e.Err = err
+	return e
+}
+
+// fieldErrors describes each failed validation rule
+func fieldErrors(errs validator.ValidationErrors) []FieldError {
+	fields := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		fields = append(fields, FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldMessage(fe),
+		})
+	}
+	return fields
+}
+
+// fieldPath returns the field's JSON path without the name of the top level struct
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
+
+// fieldMessage returns a human-readable description of a failed validation rule
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "notblank":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "max":
+		if fe.Kind() == reflect.String {
+			return "must be at most " + fe.Param() + " characters"
+		}
+		return "must have at most " + fe.Param() + " items"
+	case "min":
+		if fe.Kind() == reflect.String {
+			return "must be at least " + fe.Param() + " characters"
+		}
+		return "must be at least " + fe.Param()
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "date":
+		return "must be a date in YYYY-MM-DD, YYYY-MM or YYYY format"
+	case "weburl", "url":
+		return "must be an http or https URL"
+	case "hostname", "fqdn":
+		return "must be a valid domain name"
+	case "visibility":
+		return "must map fields of the document to public, members or private"
+	case "bcp47_language_tag":
+		return "must be a BCP 47 language tag, such as en or pt-BR"
+	}
+	return "failed the " + fe.Tag() + " rule"
+}
+
+// CodeForStatus returns the default error code for an HTTP status
+func CodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusPaymentRequired:
+		return CodePaymentRequired
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodeTooLarge
+	case http.StatusUnsupportedMediaType:
+		return CodeUnsupportedMediaType
+	case http.StatusUnprocessableEntity:
+		return CodeUnprocessableEntity
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return CodeTimeout
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// Abort records the error on the context and stops the handler chain.
+// The response is written by Middleware once the chain unwinds.
+func Abort(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// Middleware writes the error envelope for the last error recorded on the context
+// when a handler did not write a response body itself. Gin's Bind helpers write the
+// status line on failure, so only a written body means the response is complete.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Size() > 0 {
+			return
+		}
+		Write(c, c.Errors.Last().Err)
+	}
+}
+
+// Write responds with the envelope for the error immediately. Internal errors are logged with their cause.
+func Write(c *gin.Context, err error) {
+	e := Wrap(err, "Internal server error")
+	if e.Status >= http.StatusInternalServerError {
+		logging.Logger(c).Error(e.Message, "status", e.Status, "error", e.Err)
+	}
+	c.AbortWithStatusJSON(e.Status, Response{
+		Code:      e.Code,
+		Message:   e.Message,
+		Details:   e.Details,
+		RequestID: requestid.Get(c),
+	})
+}
@@ -0,0 +1,295 @@
+// Package apiusage counts the API calls each user makes with each of their session tokens, per day, as the
+// groundwork for enforcing fair use on the hosted instance. Users see their own counts and admins the
+// busiest users and the daily totals.
+//
+// Calls are counted in memory and added to the stored counters every flush interval, so counting adds no
+// write to the requests themselves. Counts not flushed yet are lost when a replica crashes, and counters
+// older than the retention are purged daily.
+package apiusage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultDays     = 30
+	defaultTopLimit = 20
+	maxTopLimit     = 500
+)
+
+var repo Repository
+var settings config.APIUsageConfig
+
+// pendingKey identifies the counter of a user's token on a day in a tenant
+type pendingKey struct {
+	tenant, userID, token, day string
+}
+
+// pending holds the calls counted since the last flush
+var (
+	mu      sync.Mutex
+	pending = map[pendingKey]*Counter{}
+)
+
+// Configure sets where the counters are stored, how often calls are flushed to them and how long they are
+// kept. Until it is called calls are not counted.
+func Configure(r Repository, cfg config.APIUsageConfig) {
+	repo = r
+	settings = cfg
+}
+
+// day returns the day the time falls on, in UTC
+func day(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+// tokenID identifies a session token without revealing it
+func tokenID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Middleware counts the calls of signed-in users once they are answered. It must run before the error
+// middleware, so it sees the status of failed calls, and reads the user the routes' auth middleware set.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		userID := c.GetString("userID")
+		if repo == nil || userID == "" {
+			return
+		}
+		token, _ := c.Cookie("token")
+		count(c.Request.Context(), userID, tokenID(token), c.Writer.Status() >= http.StatusBadRequest)
+	}
+}
+
+// count counts a call by the user with the token until the next flush
+func count(ctx context.Context, userID, token string, failed bool) {
+	now := time.Now()
+	key := pendingKey{tenant: tenant.ID(ctx), userID: userID, token: token, day: day(now)}
+
+	mu.Lock()
+	defer mu.Unlock()
+	counter, ok := pending[key]
+	if !ok {
+		counter = &Counter{UserID: userID, Token: token, Day: key.day}
+		pending[key] = counter
+	}
+	counter.Requests++
+	if failed {
+		counter.Errors++
+	}
+	counter.LastUsedAt = now
+}
+
+// Start adds the counted calls to the stored counters every flush interval until ctx is cancelled, then
+// flushes those counted last
+func Start(ctx context.Context) {
+	if repo == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(settings.FlushInterval.Std())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				flush(ctx)
+			case <-ctx.Done():
+				flush(context.WithoutCancel(ctx))
+				return
+			}
+		}
+	}()
+}
+
+// flush adds the calls counted since the last flush to each tenant's stored counters. Counts that could
+// not be stored are dropped rather than retried, so a failing store does not grow them without bound.
+func flush(ctx context.Context) {
+	mu.Lock()
+	byTenant := map[string][]Counter{}
+	for key, counter := range pending {
+		byTenant[key.tenant] = append(byTenant[key.tenant], *counter)
+	}
+	pending = map[pendingKey]*Counter{}
+	mu.Unlock()
+
+	for id, counters := range byTenant {
+		ctx, cancel := utils.WithOperationTimeout(tenant.WithID(ctx, id))
+		if err := repo.Add(ctx, counters); err != nil {
+			slog.ErrorContext(ctx, "Could not store API usage", "counters", len(counters), "error", err)
+		}
+		cancel()
+	}
+}
+
+// Purge removes the counters older than the retention
+func Purge(ctx context.Context) error {
+	removed, err := repo.Purge(ctx, day(time.Now().Add(-settings.Retention.Std())))
+	if err != nil {
+		return err
+	}
+	slog.Info("Purged API usage", "removed", removed)
+	return nil
+}
+
+// maxDays is the most days counts are kept for
+func maxDays() int {
+	return max(1, int(settings.Retention.Std()/(24*time.Hour)))
+}
+
+// parseDays reads the number of days to report from the query, or responds with an error
+func parseDays(c *gin.Context) (int, bool) {
+	days := min(defaultDays, maxDays())
+	if d := c.Query("days"); d != "" {
+		var err error
+		days, err = strconv.Atoi(d)
+		if err != nil || days < 1 || days > maxDays() {
+			apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("days must be between 1 and %d", maxDays())))
+			return 0, false
+		}
+	}
+	return days, true
+}
+
+// since returns the first of the last days, today being the last
+func since(days int) string {
+	return day(time.Now().AddDate(0, 0, 1-days))
+}
+
+// GetAPIUsage returns the API calls the user made lately
+//
+//	@Summary		Get your API usage
+//	@Description	Returns the API calls the user made in the last days, today included, per day and per session token, with how many were answered with an error. Calls are counted once they are answered and reported within the configured flush interval.
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			days	query		int	false	"Number of days to report (default 30, at most the retention)"
+//	@Success		200		{object}	Usage
+//	@Failure		400		{object}	apierror.Response	"Invalid number of days"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve API usage"
+//	@Router			/auth/usage/api [get]
+func GetAPIUsage(c *gin.Context) {
+	days, ok := parseDays(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	counters, err := repo.List(ctx, c.GetString("userID"), since(days))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve API usage"))
+		return
+	}
+
+	usage := Usage{Days: days, PerDay: []DayUsage{}, Tokens: []TokenUsage{}}
+	tokens := map[string]*TokenUsage{}
+	for _, counter := range counters {
+		usage.Requests += counter.Requests
+		usage.Errors += counter.Errors
+		if n := len(usage.PerDay); n == 0 || usage.PerDay[n-1].Day != counter.Day {
+			usage.PerDay = append(usage.PerDay, DayUsage{Day: counter.Day})
+		}
+		d := &usage.PerDay[len(usage.PerDay)-1]
+		d.Requests += counter.Requests
+		d.Errors += counter.Errors
+
+		t, ok := tokens[counter.Token]
+		if !ok {
+			t = &TokenUsage{Token: counter.Token}
+			tokens[counter.Token] = t
+		}
+		t.Requests += counter.Requests
+		t.Errors += counter.Errors
+		if counter.LastUsedAt.After(t.LastUsedAt) {
+			t.LastUsedAt = counter.LastUsedAt
+		}
+	}
+	for _, t := range tokens {
+		usage.Tokens = append(usage.Tokens, *t)
+	}
+	slices.SortFunc(usage.Tokens, func(a, b TokenUsage) int { return b.LastUsedAt.Compare(a.LastUsedAt) })
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// GetAggregate returns the API calls every user made lately
+//
+//	@Summary		Get API usage of all users
+//	@Description	Returns the API calls made in the last days, today included, per day with how many users made them, and the users who made the most. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			days	query		int	false	"Number of days to report (default 30, at most the retention)"
+//	@Param			limit	query		int	false	"Maximum number of users to return (default 20, max 500)"
+//	@Success		200		{object}	Aggregate
+//	@Failure		400		{object}	apierror.Response	"Invalid number of days"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve API usage"
+//	@Router			/admin/usage/api [get]
+func GetAggregate(c *gin.Context) {
+	days, ok := parseDays(c)
+	if !ok {
+		return
+	}
+	limit := defaultTopLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, maxTopLimit)
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	perDay, err := repo.PerDay(ctx, since(days))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve API usage"))
+		return
+	}
+	top, err := repo.Top(ctx, since(days), limit)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve API usage"))
+		return
+	}
+
+	aggregate := Aggregate{Days: days, PerDay: perDay, Top: top}
+	if aggregate.PerDay == nil {
+		aggregate.PerDay = []DayUsage{}
+	}
+	if aggregate.Top == nil {
+		aggregate.Top = []UserUsage{}
+	}
+	for _, d := range perDay {
+		aggregate.Requests += d.Requests
+		aggregate.Errors += d.Errors
+	}
+	c.JSON(http.StatusOK, aggregate)
+}
+
+// InitializeRoutes registers the API usage endpoint with the account routes
+func InitializeRoutes(router *gin.RouterGroup, users auth.Repository) {
+	router.GET("/usage/api", auth.AuthMiddleware(users, true), GetAPIUsage)
+}
+
+// InitializeAdminRoutes registers the aggregate API usage endpoint. The router must only admit admins.
+func InitializeAdminRoutes(router *gin.RouterGroup) {
+	router.GET("/api", GetAggregate)
+}
@@ -0,0 +1,72 @@
+package apiusage
+
+import "time"
+
+// Counter counts the API calls a user made with one session token on one day
+type Counter struct {
+	// ID is the user's ID, the token and the day, see counterID
+	ID     string `bson:"_id"`
+	UserID string `bson:"user_id"`
+	// Token identifies the session token the calls were made with, see tokenID
+	Token string `bson:"token"`
+	// Day is the day the calls were made on, as 2006-01-02 in UTC
+	Day      string `bson:"day"`
+	Requests int64  `bson:"requests"`
+	// Errors counts the calls answered with an error status
+	Errors     int64     `bson:"errors"`
+	LastUsedAt time.Time `bson:"last_used_at"`
+}
+
+func counterID(userID, token, day string) string {
+	return userID + "/" + token + "/" + day
+}
+
+// DayUsage counts the API calls made on a day
+type DayUsage struct {
+	Day      string `json:"day"`
+	Requests int64  `json:"requests"`
+	Errors   int64  `json:"errors"`
+	// Users is how many users made calls on the day, in the admin aggregates only
+	Users int `json:"users,omitempty"`
+}
+
+// TokenUsage counts the API calls made with a session token
+type TokenUsage struct {
+	// Token identifies the token without revealing it
+	Token      string    `json:"token"`
+	Requests   int64     `json:"requests"`
+	Errors     int64     `json:"errors"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+// Usage counts the API calls a user made in the last days
+type Usage struct {
+	Days     int   `json:"days"`
+	Requests int64 `json:"requests"`
+	Errors   int64 `json:"errors"`
+	// PerDay holds the days the user made calls on, oldest first
+	PerDay []DayUsage `json:"perDay"`
+	// Tokens holds the tokens the user made calls with, most recently used first
+	Tokens []TokenUsage `json:"tokens"`
+}
+
+// UserUsage counts the API calls a user made, for admins
+type UserUsage struct {
+	UserID   string `json:"userID" bson:"_id"`
+	Requests int64  `json:"requests" bson:"requests"`
+	Errors   int64  `json:"errors" bson:"errors"`
+	// Tokens is how many session tokens the user made calls with
+	Tokens     int       `json:"tokens" bson:"tokens"`
+	LastUsedAt time.Time `json:"lastUsedAt" bson:"last_used_at"`
+}
+
+// Aggregate counts the API calls every user made in the last days, for admins
+type Aggregate struct {
+	Days     int   `json:"days"`
+	Requests int64 `json:"requests"`
+	Errors   int64 `json:"errors"`
+	// PerDay holds the days calls were made on, oldest first
+	PerDay []DayUsage `json:"perDay"`
+	// Top holds the users who made the most calls, busiest first
+	Top []UserUsage `json:"top"`
+}
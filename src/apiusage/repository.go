@@ -0,0 +1,17 @@
+package apiusage
+
+import "context"
+
+// Repository stores the daily API call counters of each user and token
+type Repository interface {
+	// Add adds the counts of the counters to the stored ones, creating those not stored yet
+	Add(ctx context.Context, counters []Counter) error
+	// List returns the user's counters of the day since, as 2006-01-02, and the days after it
+	List(ctx context.Context, userID, since string) ([]Counter, error)
+	// PerDay returns the counts of every user per day from the day since on, oldest first
+	PerDay(ctx context.Context, since string) ([]DayUsage, error)
+	// Top returns the counts of each user from the day since on, busiest first, at most limit of them
+	Top(ctx context.Context, since string, limit int) ([]UserUsage, error)
+	// Purge removes the counters of the days before the given one, returning how many were removed
+	Purge(ctx context.Context, before string) (int64, error)
+}
@@ -0,0 +1,128 @@
+package apiusage
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+)
+
+// MemoryRepository keeps counters in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu       sync.Mutex
+	counters map[string]Counter
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{counters: map[string]Counter{}}
+}
+
+func (r *MemoryRepository) Add(ctx context.Context, counters []Counter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, add := range counters {
+		id := counterID(add.UserID, add.Token, add.Day)
+		stored, ok := r.counters[id]
+		if !ok {
+			stored = Counter{ID: id, UserID: add.UserID, Token: add.Token, Day: add.Day}
+		}
+		stored.Requests += add.Requests
+		stored.Errors += add.Errors
+		if add.LastUsedAt.After(stored.LastUsedAt) {
+			stored.LastUsedAt = add.LastUsedAt
+		}
+		r.counters[id] = stored
+	}
+	return nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID, since string) ([]Counter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []Counter
+	for _, counter := range r.counters {
+		if counter.UserID == userID && counter.Day >= since {
+			found = append(found, counter)
+		}
+	}
+	slices.SortFunc(found, func(a, b Counter) int { return cmp.Compare(a.Day, b.Day) })
+	return found, nil
+}
+
+func (r *MemoryRepository) PerDay(ctx context.Context, since string) ([]DayUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	days := map[string]*DayUsage{}
+	users := map[string]map[string]bool{}
+	for _, counter := range r.counters {
+		if counter.Day < since {
+			continue
+		}
+		day, ok := days[counter.Day]
+		if !ok {
+			day = &DayUsage{Day: counter.Day}
+			days[counter.Day] = day
+			users[counter.Day] = map[string]bool{}
+		}
+		day.Requests += counter.Requests
+		day.Errors += counter.Errors
+		users[counter.Day][counter.UserID] = true
+	}
+	found := make([]DayUsage, 0, len(days))
+	for _, day := range days {
+		day.Users = len(users[day.Day])
+		found = append(found, *day)
+	}
+	slices.SortFunc(found, func(a, b DayUsage) int { return cmp.Compare(a.Day, b.Day) })
+	return found, nil
+}
+
+func (r *MemoryRepository) Top(ctx context.Context, since string, limit int) ([]UserUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	byUser := map[string]*UserUsage{}
+	tokens := map[string]map[string]bool{}
+	for _, counter := range r.counters {
+		if counter.Day < since {
+			continue
+		}
+		u, ok := byUser[counter.UserID]
+		if !ok {
+			u = &UserUsage{UserID: counter.UserID}
+			byUser[counter.UserID] = u
+			tokens[counter.UserID] = map[string]bool{}
+		}
+		u.Requests += counter.Requests
+		u.Errors += counter.Errors
+		if counter.LastUsedAt.After(u.LastUsedAt) {
+			u.LastUsedAt = counter.LastUsedAt
+		}
+		tokens[counter.UserID][counter.Token] = true
+	}
+	found := make([]UserUsage, 0, len(byUser))
+	for _, u := range byUser {
+		u.Tokens = len(tokens[u.UserID])
+		found = append(found, *u)
+	}
+	slices.SortFunc(found, func(a, b UserUsage) int {
+		return cmp.Or(cmp.Compare(b.Requests, a.Requests), cmp.Compare(a.UserID, b.UserID))
+	})
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found, nil
+}
+
+func (r *MemoryRepository) Purge(ctx context.Context, before string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var removed int64
+	for id, counter := range r.counters {
+		if counter.Day < before {
+			delete(r.counters, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,105 @@
+package apiusage
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores counters in the api_usage collection
+type MongoRepository struct {
+	usage *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{usage: db.Collection("api_usage")}
+}
+
+func (r *MongoRepository) Add(ctx context.Context, counters []Counter) error {
+	for _, add := range counters {
+		update := bson.M{
+			"$inc":         bson.M{"requests": add.Requests, "errors": add.Errors},
+			"$max":         bson.M{"last_used_at": add.LastUsedAt},
+			"$setOnInsert": bson.M{"user_id": add.UserID, "token": add.Token, "day": add.Day},
+		}
+		_, err := r.usage.UpdateOne(ctx, bson.M{"_id": counterID(add.UserID, add.Token, add.Day)}, update, options.Update().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID, since string) ([]Counter, error) {
+	cursor, err := r.usage.Find(ctx, bson.M{"user_id": userID, "day": bson.M{"$gte": since}},
+		options.Find().SetSort(bson.D{{Key: "day", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var found []Counter
+	err = cursor.All(ctx, &found)
+	return found, err
+}
+
+func (r *MongoRepository) PerDay(ctx context.Context, since string) ([]DayUsage, error) {
+	cursor, err := r.usage.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"day": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$day",
+			"requests": bson.M{"$sum": "$requests"},
+			"errors":   bson.M{"$sum": "$errors"},
+			"users":    bson.M{"$addToSet": "$user_id"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var days []struct {
+		Day      string   `bson:"_id"`
+		Requests int64    `bson:"requests"`
+		Errors   int64    `bson:"errors"`
+		Users    []string `bson:"users"`
+	}
+	if err := cursor.All(ctx, &days); err != nil {
+		return nil, err
+	}
+	found := make([]DayUsage, 0, len(days))
+	for _, d := range days {
+		found = append(found, DayUsage{Day: d.Day, Requests: d.Requests, Errors: d.Errors, Users: len(d.Users)})
+	}
+	return found, nil
+}
+
+func (r *MongoRepository) Top(ctx context.Context, since string, limit int) ([]UserUsage, error) {
+	cursor, err := r.usage.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"day": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          "$user_id",
+			"requests":     bson.M{"$sum": "$requests"},
+			"errors":       bson.M{"$sum": "$errors"},
+			"tokens":       bson.M{"$addToSet": "$token"},
+			"last_used_at": bson.M{"$max": "$last_used_at"},
+		}}},
+		{{Key: "$set", Value: bson.M{"tokens": bson.M{"$size": "$tokens"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "requests", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var found []UserUsage
+	err = cursor.All(ctx, &found)
+	return found, err
+}
+
+func (r *MongoRepository) Purge(ctx context.Context, before string) (int64, error) {
+	result, err := r.usage.DeleteMany(ctx, bson.M{"day": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,82 @@
+package apiusage
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository stores counters in the api_usage table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Add(ctx context.Context, counters []Counter) error {
+	for _, add := range counters {
+		_, err := r.pool.Exec(ctx, `INSERT INTO api_usage (user_id, token, day, requests, errors, last_used_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (user_id, token, day) DO UPDATE SET
+				requests = api_usage.requests + excluded.requests,
+				errors = api_usage.errors + excluded.errors,
+				last_used_at = GREATEST(api_usage.last_used_at, excluded.last_used_at)`,
+			add.UserID, add.Token, add.Day, add.Requests, add.Errors, add.LastUsedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID, since string) ([]Counter, error) {
+	rows, err := r.pool.Query(ctx, `SELECT user_id, token, day, requests, errors, last_used_at FROM api_usage
+		WHERE user_id = $1 AND day >= $2 ORDER BY day`, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Counter, error) {
+		var c Counter
+		err := row.Scan(&c.UserID, &c.Token, &c.Day, &c.Requests, &c.Errors, &c.LastUsedAt)
+		c.ID = counterID(c.UserID, c.Token, c.Day)
+		return c, err
+	})
+}
+
+func (r *PostgresRepository) PerDay(ctx context.Context, since string) ([]DayUsage, error) {
+	rows, err := r.pool.Query(ctx, `SELECT day, SUM(requests)::BIGINT, SUM(errors)::BIGINT, COUNT(DISTINCT user_id) FROM api_usage
+		WHERE day >= $1 GROUP BY day ORDER BY day`, since)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (DayUsage, error) {
+		var d DayUsage
+		err := row.Scan(&d.Day, &d.Requests, &d.Errors, &d.Users)
+		return d, err
+	})
+}
+
+func (r *PostgresRepository) Top(ctx context.Context, since string, limit int) ([]UserUsage, error) {
+	rows, err := r.pool.Query(ctx, `SELECT user_id, SUM(requests)::BIGINT AS total, SUM(errors)::BIGINT, COUNT(DISTINCT token), MAX(last_used_at)
+		FROM api_usage WHERE day >= $1 GROUP BY user_id ORDER BY total DESC, user_id LIMIT $2`, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (UserUsage, error) {
+		var u UserUsage
+		err := row.Scan(&u.UserID, &u.Requests, &u.Errors, &u.Tokens, &u.LastUsedAt)
+		return u, err
+	})
+}
+
+func (r *PostgresRepository) Purge(ctx context.Context, before string) (int64, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM api_usage WHERE day < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,37 @@
+package apiusage
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Add(ctx context.Context, counters []Counter) error {
+	return r.repos.For(ctx).Add(ctx, counters)
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID, since string) ([]Counter, error) {
+	return r.repos.For(ctx).List(ctx, userID, since)
+}
+
+func (r *TenantRepository) PerDay(ctx context.Context, since string) ([]DayUsage, error) {
+	return r.repos.For(ctx).PerDay(ctx, since)
+}
+
+func (r *TenantRepository) Top(ctx context.Context, since string, limit int) ([]UserUsage, error) {
+	return r.repos.For(ctx).Top(ctx, since, limit)
+}
+
+func (r *TenantRepository) Purge(ctx context.Context, before string) (int64, error) {
+	return r.repos.For(ctx).Purge(ctx, before)
+}
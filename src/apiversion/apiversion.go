@@ -0,0 +1,189 @@
+// Package apiversion lets handlers serve several API versions from the same code.
+//
+// Versioning policy: /api/v1 is frozen. Its routes keep their current paths, status codes and bodies,
+// and only receive fixes that do not change either. Breaking improvements land in /api/v2, which wraps
+// every success body in an envelope, pages lists, answers creates with 201 and a Location header and
+// answers deletes with 204. Modules opt in by writing their responses through this package and being
+// mounted under both prefixes; the v1 routes of such modules then carry Deprecation and Link headers
+// pointing at their v2 successor. Error bodies are the same apierror envelope in both versions.
+package apiversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version identifies the API version a request was routed to
+type Version int
+
+const (
+	V1 Version = 1
+	V2 Version = 2
+)
+
+const (
+	contextKey = "apiVersion"
+
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// deprecatedAt is when v2 was introduced, sent in the Deprecation header of v1 responses that have a successor
+var deprecatedAt = time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)
+
+// Envelope wraps the body of v2 success responses
+type Envelope struct {
+	Data any   `json:"data"`
+	Meta *Meta `json:"meta,omitempty"`
+}
+
+// Meta describes the page of a v2 list response
+type Meta struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// Middleware marks the requests of a route group as served by version v
+func Middleware(v Version) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(contextKey, v)
+		c.Next()
+	}
+}
+
+// Deprecate adds the Deprecation header and a successor-version link to the same path under /api/v2.
+// It is used on v1 route groups whose module is also mounted under v2.
+func Deprecate() gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		successor := strings.Replace(c.Request.URL.Path, "/api/v1/", "/api/v2/", 1)
+		c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		c.Next()
+	}
+}
+
+// From returns the version of the request, V1 for routes outside a versioned group
+func From(c *gin.Context) Version {
+	if v, ok := c.Get(contextKey); ok {
+		return v.(Version)
+	}
+	return V1
+}
+
+// OK responds 200 with data, enveloped in v2
+func OK(c *gin.Context, data any) {
+	if From(c) == V1 {
+		c.JSON(http.StatusOK, data)
+		return
+	}
+	c.JSON(http.StatusOK, Envelope{Data: data})
+}
+
+// List responds 200 with the items. v1 returns every item; v2 returns the page selected by the limit
+// and offset query parameters along with the total.
+func List[T any](c *gin.Context, items []T) {
+	if From(c) == V1 {
+		c.JSON(http.StatusOK, items)
+		return
+	}
+
+	limit, err := queryInt(c, "limit", defaultLimit)
+	if err != nil || limit < 1 || limit > maxLimit {
+		apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("limit must be between 1 and %d", maxLimit)))
+		return
+	}
+	offset, err := queryInt(c, "offset", 0)
+	if err != nil || offset < 0 {
+		apierror.Abort(c, apierror.BadRequest("offset must not be negative"))
+		return
+	}
+
+	start := min(offset, len(items))
+	page := items[start : start+min(limit, len(items)-start)]
+	if page == nil {
+		page = []T{}
+	}
+	c.JSON(http.StatusOK, Envelope{
+		Data: page,
+		Meta: &Meta{Total: len(items), Limit: limit, Offset: offset},
+	})
+}
+
+// Created responds to a create. v1 keeps its original body; v2 responds 201 with the created resource
+// and a Location header for the new item under the request path.
+func Created(c *gin.Context, id string, resource any, v1Body any) {
+	if From(c) == V1 {
+		c.JSON(http.StatusOK, v1Body)
+		return
+	}
+	Location(c, id)
+	c.JSON(http.StatusCreated, Envelope{Data: resource})
+}
+
+// Location sets the Location header of a create to the new item under the request path. Adding it leaves
+// the status and body alone, so routes only served under v1 send it too.
+func Location(c *gin.Context, id string) {
+	c.Header("Location", path.Join(c.Request.URL.Path, id))
+}
+
+// Announcement is the v1 body of a create: the fields of the created resource, including its new ID,
+// alongside the message v1 has always sent, so clients reading the message keep working
+type Announcement struct {
+	Message  string
+	Resource any
+}
+
+// Announce returns the v1 body of a create of the resource
+func Announce(message string, resource any) Announcement {
+	return Announcement{Message: message, Resource: resource}
+}
+
+func (a Announcement) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(a.Resource)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	fields["message"] = a.Message
+	return json.Marshal(fields)
+}
+
+// Updated responds to an update. v1 keeps its original body; v2 responds with the updated resource.
+func Updated(c *gin.Context, resource any, v1Body any) {
+	if From(c) == V1 {
+		c.JSON(http.StatusOK, v1Body)
+		return
+	}
+	c.JSON(http.StatusOK, Envelope{Data: resource})
+}
+
+// NoContent responds to a delete or an upload. v1 keeps its original body; v2 responds 204.
+func NoContent(c *gin.Context, v1Body any) {
+	if From(c) == V1 {
+		c.JSON(http.StatusOK, v1Body)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func queryInt(c *gin.Context, name string, fallback int) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,188 @@
+// Package attachments removes the files stored for journal entries once no entry refers to them. Images a
+// journal import copies into the image store are tracked as they are stored. Editing an entry, dropping one
+// of its versions or deleting it leaves them behind, so a daily collection checks every tracked file against
+// the content and attachments of every version of its owner's entries. A file found unreferenced is only
+// removed once it has stayed so for the grace period, so an entry still being imported or edited can refer
+// to it again. Removing a file frees the storage it took from its owner's quota.
+package attachments
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/config"
+	"profile-api/journal"
+	"profile-api/profile"
+	"profile-api/quota"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	repo     Repository
+	journals journal.Repository
+	settings config.AttachmentsConfig
+)
+
+// Configure sets where tracked files and journal entries are stored and starts tracking the files stored
+// for journal entries
+func Configure(r Repository, j journal.Repository, cfg config.AttachmentsConfig) {
+	repo = r
+	journals = j
+	settings = cfg
+	journal.SetUploadTracker(track)
+}
+
+// track records a file stored for a journal entry
+func track(ctx context.Context, upload journal.Upload) {
+	file := File{
+		ID:        utils.GenerateID(),
+		UserID:    upload.UserID,
+		JournalID: upload.JournalID,
+		URL:       upload.URL,
+		Name:      upload.Name,
+		Bytes:     upload.Bytes,
+		CreatedAt: time.Now(),
+	}
+	if err := repo.Create(ctx, file); err != nil {
+		slog.ErrorContext(ctx, "Could not track journal file", "user_id", upload.UserID, "url", upload.URL, "error", err)
+	}
+}
+
+// references returns the content and attachments of every version of the user's journal entries
+func references(ctx context.Context, userID string) ([]string, error) {
+	entries, err := journals.List(ctx, journal.Filter{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	for _, e := range entries {
+		for _, version := range e.Entries {
+			refs = append(refs, version.Content)
+			refs = append(refs, version.Attachments...)
+		}
+	}
+	return refs, nil
+}
+
+// referenced reports whether any of the references names the file. Its URL is matched without the version
+// query, which changes with the image's content.
+func referenced(file File, refs []string) bool {
+	url, _, _ := strings.Cut(file.URL, "?")
+	return slices.ContainsFunc(refs, func(ref string) bool { return strings.Contains(ref, url) })
+}
+
+// collect checks every tracked file against its owner's journal entries, marking those found unreferenced
+// and removing those unreferenced for the grace period. A dry run reports what it would do without changing
+// anything.
+func collect(ctx context.Context, dryRun bool) (Report, error) {
+	report := Report{DryRun: dryRun, Orphaned: []File{}, Restored: []File{}, Deleted: []File{}}
+	files, err := repo.List(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Checked = len(files)
+
+	now := time.Now()
+	refs := map[string][]string{}
+	for _, file := range files {
+		userRefs, ok := refs[file.UserID]
+		if !ok {
+			if userRefs, err = references(ctx, file.UserID); err != nil {
+				return report, err
+			}
+			refs[file.UserID] = userRefs
+		}
+
+		switch {
+		case referenced(file, userRefs):
+			if file.OrphanedAt == nil {
+				continue
+			}
+			if !dryRun {
+				if err := repo.SetOrphaned(ctx, file.ID, nil); err != nil {
+					return report, err
+				}
+			}
+			report.Restored = append(report.Restored, file)
+		case file.OrphanedAt == nil:
+			if !dryRun {
+				if err := repo.SetOrphaned(ctx, file.ID, &now); err != nil {
+					return report, err
+				}
+			}
+			file.OrphanedAt = &now
+			report.Orphaned = append(report.Orphaned, file)
+		case now.Sub(*file.OrphanedAt) >= settings.OrphanGrace.Std():
+			if !dryRun {
+				if err := remove(ctx, file); err != nil {
+					return report, err
+				}
+			}
+			report.Deleted = append(report.Deleted, file)
+			report.FreedBytes += file.Bytes
+		}
+	}
+	return report, nil
+}
+
+// remove deletes the file from the image store, frees its storage and stops tracking it
+func remove(ctx context.Context, file File) error {
+	if images := profile.GetImageStore(ctx); images != nil {
+		if err := images.Delete(ctx, file.URL); err != nil && !errors.Is(err, store.ErrNotFound) {
+			return err
+		}
+	}
+	if err := quota.FreeUpload(ctx, "journal", file.JournalID, file.Name); err != nil {
+		return err
+	}
+	return repo.Delete(ctx, file.ID)
+}
+
+// Collect removes the files stored for journal entries that no entry has referred to for the grace period,
+// for the scheduler
+func Collect(ctx context.Context) error {
+	report, err := collect(ctx, false)
+	if err != nil {
+		return err
+	}
+	slog.Info("Collected journal files", "checked", report.Checked, "orphaned", len(report.Orphaned),
+		"restored", len(report.Restored), "deleted", len(report.Deleted), "freed_bytes", report.FreedBytes)
+	return nil
+}
+
+// GetCollectionReport reports what collecting unreferenced journal files would do
+//
+//	@Summary		Preview journal file collection
+//	@Description	Checks every file stored for journal entries against the content and attachments of every version of its owner's entries, without changing anything, and reports what the daily collection would do: the files it would find unreferenced for the first time and keep for the grace period, those referred to again, and those it would delete after staying unreferenced for the grace period, with the storage they would free. Only admins can preview collections.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	Report
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		403	{object}	apierror.Response	"Not an admin"
+//	@Failure		500	{object}	apierror.Response	"Could not check journal files"
+//	@Security		BearerAuth
+//	@Router			/admin/attachments/collection [get]
+func GetCollectionReport(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	report, err := collect(ctx, true)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not check journal files"))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// InitializeAdminRoutes registers the journal file collection routes. The router must only admit admins.
+func InitializeAdminRoutes(router *gin.RouterGroup) {
+	router.GET("/collection", GetCollectionReport)
+}
@@ -0,0 +1,33 @@
+package attachments
+
+import "time"
+
+// File is a file stored in the image store for a user's journal entry, tracked until no version of any of
+// their entries refers to it any more
+type File struct {
+	ID        string `bson:"_id" json:"id"`
+	UserID    string `bson:"user_id" json:"userID"`
+	JournalID string `bson:"journal_id" json:"journalID"`
+	URL       string `bson:"url" json:"url"`
+	// Name is the name the file is counted against the user's storage quota under
+	Name      string    `bson:"name" json:"name"`
+	Bytes     int64     `bson:"bytes" json:"bytes"`
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+	// OrphanedAt is when the file was first found unreferenced, nil while an entry refers to it
+	OrphanedAt *time.Time `bson:"orphaned_at,omitempty" json:"orphanedAt,omitempty"`
+}
+
+// Report is what a collection of unreferenced files did, or would do in a dry run
+type Report struct {
+	DryRun bool `json:"dryRun"`
+	// Checked is the number of files tracked
+	Checked int `json:"checked"`
+	// Orphaned are the files found unreferenced for the first time, kept for the grace period in case an
+	// entry refers to them again
+	Orphaned []File `json:"orphaned"`
+	// Restored are the files an entry refers to again since they were found unreferenced
+	Restored []File `json:"restored"`
+	// Deleted are the files removed after staying unreferenced for the grace period
+	Deleted    []File `json:"deleted"`
+	FreedBytes int64  `json:"freedBytes"`
+}
@@ -0,0 +1,20 @@
+package attachments
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores the files tracked for journal entries
+type Repository interface {
+	// Create starts tracking a file
+	Create(ctx context.Context, file File) error
+	// List returns every tracked file, oldest first
+	List(ctx context.Context) ([]File, error)
+	// ListByJournal returns the files tracked for the journal entry
+	ListByJournal(ctx context.Context, journalID string) ([]File, error)
+	// SetOrphaned records when the file was found unreferenced, or clears it when at is nil
+	SetOrphaned(ctx context.Context, id string, at *time.Time) error
+	// Delete stops tracking the file
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,67 @@
+package attachments
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps tracked files in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu    sync.Mutex
+	files []File
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, file File) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.ContainsFunc(r.files, func(f File) bool { return f.ID == file.ID }) {
+		return store.ErrConflict
+	}
+	r.files = append(r.files, file)
+	return nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context) ([]File, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.files), nil
+}
+
+func (r *MemoryRepository) ListByJournal(ctx context.Context, journalID string) ([]File, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var files []File
+	for _, f := range r.files {
+		if f.JournalID == journalID {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+func (r *MemoryRepository) SetOrphaned(ctx context.Context, id string, at *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.files, func(f File) bool { return f.ID == id })
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.files[i].OrphanedAt = at
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files = slices.DeleteFunc(r.files, func(f File) bool { return f.ID == id })
+	return nil
+}
@@ -0,0 +1,70 @@
+package attachments
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores tracked files in the journal_files collection
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{collection: db.Collection("journal_files")}
+}
+
+func (r *MongoRepository) Create(ctx context.Context, file File) error {
+	_, err := r.collection.InsertOne(ctx, file)
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	}
+	return err
+}
+
+func (r *MongoRepository) List(ctx context.Context) ([]File, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var files []File
+	err = cursor.All(ctx, &files)
+	return files, err
+}
+
+func (r *MongoRepository) ListByJournal(ctx context.Context, journalID string) ([]File, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"journal_id": journalID})
+	if err != nil {
+		return nil, err
+	}
+	var files []File
+	err = cursor.All(ctx, &files)
+	return files, err
+}
+
+func (r *MongoRepository) SetOrphaned(ctx context.Context, id string, at *time.Time) error {
+	update := bson.M{"$unset": bson.M{"orphaned_at": ""}}
+	if at != nil {
+		update = bson.M{"$set": bson.M{"orphaned_at": *at}}
+	}
+	result, err := r.collection.UpdateByID(ctx, id, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
@@ -0,0 +1,67 @@
+package attachments
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const fileColumns = "id, user_id, journal_id, url, name, bytes, created_at, orphaned_at"
+
+// PostgresRepository stores tracked files in the journal_files table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, f File) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO journal_files ("+fileColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		f.ID, f.UserID, f.JournalID, f.URL, f.Name, f.Bytes, f.CreatedAt, f.OrphanedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) List(ctx context.Context) ([]File, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+fileColumns+" FROM journal_files ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanFile)
+}
+
+func (r *PostgresRepository) ListByJournal(ctx context.Context, journalID string) ([]File, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+fileColumns+" FROM journal_files WHERE journal_id = $1", journalID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanFile)
+}
+
+func scanFile(row pgx.CollectableRow) (File, error) {
+	var f File
+	err := row.Scan(&f.ID, &f.UserID, &f.JournalID, &f.URL, &f.Name, &f.Bytes, &f.CreatedAt, &f.OrphanedAt)
+	return f, err
+}
+
+func (r *PostgresRepository) SetOrphaned(ctx context.Context, id string, at *time.Time) error {
+	tag, err := r.pool.Exec(ctx, "UPDATE journal_files SET orphaned_at = $2 WHERE id = $1", id, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM journal_files WHERE id = $1", id)
+	return err
+}
@@ -0,0 +1,38 @@
+package attachments
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, file File) error {
+	return r.repos.For(ctx).Create(ctx, file)
+}
+
+func (r *TenantRepository) List(ctx context.Context) ([]File, error) {
+	return r.repos.For(ctx).List(ctx)
+}
+
+func (r *TenantRepository) ListByJournal(ctx context.Context, journalID string) ([]File, error) {
+	return r.repos.For(ctx).ListByJournal(ctx, journalID)
+}
+
+func (r *TenantRepository) SetOrphaned(ctx context.Context, id string, at *time.Time) error {
+	return r.repos.For(ctx).SetOrphaned(ctx, id, at)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, id string) error {
+	return r.repos.For(ctx).Delete(ctx, id)
+}
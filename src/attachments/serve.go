@@ -0,0 +1,239 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/journal"
+	"profile-api/privacy"
+	"profile-api/profile"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var publicBaseURL = "http://localhost:8080"
+
+// SetBaseURL sets the public base URL of the links to attachments
+func SetBaseURL(url string) {
+	publicBaseURL = strings.TrimSuffix(url, "/")
+}
+
+// baseURL returns the public base URL of the links to attachments, which tenants may override
+func baseURL(ctx context.Context) string {
+	if t, ok := tenant.Current(ctx); ok && t.PublicBaseURL != "" {
+		return strings.TrimSuffix(t.PublicBaseURL, "/")
+	}
+	return publicBaseURL
+}
+
+// Link is a link to one of a journal entry's attachments, served through the entry's access checks
+type Link struct {
+	URL string `json:"url"`
+	// ExpiresAt is when a signed link to an attachment of an entry that isn't public stops working
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// signature signs the link to the attachment at the index of the journal entry until it expires
+func signature(ctx context.Context, journalID string, index int, expires int64) string {
+	return auth.Sign("journal-attachment", fmt.Sprintf("%s/%s/%d/%d", tenant.ID(ctx), journalID, index, expires))
+}
+
+// signed reports whether the request carries an unexpired signature for the attachment, and whether it
+// carries one that expired
+func signed(c *gin.Context, journalID string, index int) (valid, expired bool) {
+	given := c.Query("signature")
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if given == "" || err != nil {
+		return false, false
+	}
+	if !hmac.Equal([]byte(given), []byte(signature(c.Request.Context(), journalID, index, expires))) {
+		return false, false
+	}
+	if time.Now().Unix() > expires {
+		return false, true
+	}
+	return true, false
+}
+
+// current returns the attachments of the entry's current version
+func current(entry journal.JournalEntry) []string {
+	for _, e := range entry.Entries {
+		if e.Version == entry.Version {
+			return e.Attachments
+		}
+	}
+	if len(entry.Entries) == 0 {
+		return nil
+	}
+	return entry.Entries[len(entry.Entries)-1].Attachments
+}
+
+// ListAttachments links to the attachments of a journal entry
+//
+//	@Summary		List a journal entry's attachments
+//	@Description	Links to the attachments of the current version of a journal entry, in order, each served through the entry's access checks. Links to the attachments of entries that aren't public, or whose owner restricted their profile, are signed and stop working at their expiresAt, so they can be embedded where the requester's credentials aren't sent. Entries the requester may not read are reported as not found.
+//	@Tags			journal
+//	@Produce		json
+//	@Param			journalid	path		string	true	"Journal ID"
+//	@Success		200			{array}		Link
+//	@Failure		404			{object}	apierror.Response	"Journal entry not found"
+//	@Failure		500			{object}	apierror.Response	"Could not retrieve journal entry"
+//	@Router			/journal/{journalid}/attachments [get]
+func ListAttachments(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	entry, ok := getEntry(ctx, c)
+	if !ok {
+		return
+	}
+	if ok, err := journal.Readable(ctx, c, entry); err != nil || !ok {
+		apierror.Abort(c, notReadable(err))
+		return
+	}
+	restricted, err := privacy.Restricted(ctx, entry.UserID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve journal entry"))
+		return
+	}
+
+	links := []Link{}
+	expiresAt := time.Now().Add(settings.URLExpiry.Std()).Truncate(time.Second)
+	for i := range current(entry) {
+		link := Link{URL: fmt.Sprintf("%s/api/v1/journal/%s/attachments/%d", baseURL(ctx), entry.JournalID, i)}
+		if entry.Status != journal.StatusPublic || restricted {
+			link.URL += fmt.Sprintf("?expires=%d&signature=%s", expiresAt.Unix(), signature(ctx, entry.JournalID, i, expiresAt.Unix()))
+			link.ExpiresAt = &expiresAt
+		}
+		links = append(links, link)
+	}
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, links)
+}
+
+// getEntry returns the journal entry named by the path, responding when it can't
+func getEntry(ctx context.Context, c *gin.Context) (journal.JournalEntry, bool) {
+	entry, err := journals.Get(ctx, c.Param("journalid"))
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Journal entry not found"))
+		return entry, false
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve journal entry"))
+		return entry, false
+	}
+	return entry, true
+}
+
+// notReadable is the error for an entry the requester may not read, reported as missing so its existence
+// isn't revealed
+func notReadable(err error) error {
+	if err != nil {
+		return apierror.Wrap(err, "Could not retrieve journal entry")
+	}
+	return apierror.NotFound("Journal entry not found")
+}
+
+// GetAttachment serves one of a journal entry's attachments
+//
+//	@Summary		Retrieve a journal entry's attachment
+//	@Description	Serves the attachment at the index among those of the current version of a journal entry, once the requester is found to be allowed to read the entry or presents an unexpired signed link from the attachments list. Only files stored for the entry are served, streamed from the image store; attachments linking elsewhere are not found. Supports conditional and range requests.
+//	@Tags			journal
+//	@Param			journalid	path		string	true	"Journal ID"
+//	@Param			index		path		int		true	"Position of the attachment, from 0"
+//	@Param			expires		query		int		false	"Expiry of a signed link, in Unix seconds"
+//	@Param			signature	query		string	false	"Signature of a signed link"
+//	@Success		200			{file}		binary	"Attachment"
+//	@Success		304			"Not modified"
+//	@Failure		403			{object}	apierror.Response	"Signed link expired"
+//	@Failure		404			{object}	apierror.Response	"Journal entry or attachment not found"
+//	@Failure		500			{object}	apierror.Response	"Could not retrieve attachment"
+//	@Router			/journal/{journalid}/attachments/{index} [get]
+//	@Router			/journal/{journalid}/attachments/{index} [head]
+func GetAttachment(c *gin.Context) {
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 {
+		apierror.Abort(c, apierror.NotFound("Attachment not found"))
+		return
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+
+	entry, ok := getEntry(ctx, c)
+	if !ok {
+		return
+	}
+	valid, expired := signed(c, entry.JournalID, index)
+	if !valid {
+		ok, err := journal.Readable(ctx, c, entry)
+		switch {
+		case err == nil && !ok && expired:
+			apierror.Abort(c, apierror.Forbidden("The link to the attachment has expired"))
+			return
+		case err != nil || !ok:
+			apierror.Abort(c, notReadable(err))
+			return
+		}
+	}
+	attachments := current(entry)
+	if index >= len(attachments) {
+		apierror.Abort(c, apierror.NotFound("Attachment not found"))
+		return
+	}
+	url := attachments[index]
+
+	// Only files tracked for this entry are served, so an attachment can't name another user's file or send
+	// the client elsewhere
+	files, err := repo.ListByJournal(ctx, entry.JournalID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve attachment"))
+		return
+	}
+	i := slices.IndexFunc(files, func(f File) bool { return referenced(f, []string{url}) })
+	if i < 0 {
+		apierror.Abort(c, apierror.NotFound("Attachment not found"))
+		return
+	}
+	file := files[i]
+
+	data, err := profile.GetImageStore(ctx).Load(ctx, file.URL)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Attachment not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve attachment"))
+		return
+	}
+	if valid || entry.Status != journal.StatusPublic {
+		c.Header("Cache-Control", "private, max-age=300")
+	} else {
+		c.Header("Cache-Control", "public, max-age=300")
+	}
+	c.Writer.Header().Add("Vary", "Cookie")
+	c.Header("Content-Type", http.DetectContentType(data))
+	// The type is sniffed from the file, so browsers must not sniff another from it
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, path.Base(file.Name), file.CreatedAt, bytes.NewReader(data))
+}
+
+// InitializeRoutes registers the routes serving journal entries' attachments on the journal router
+func InitializeRoutes(router *gin.RouterGroup, users auth.Repository) {
+	authOptional := auth.AuthMiddleware(users, false)
+	router.GET("/:journalid/attachments", authOptional, ListAttachments)
+	router.GET("/:journalid/attachments/:index", authOptional, GetAttachment)
+	router.HEAD("/:journalid/attachments/:index", authOptional, GetAttachment)
+}
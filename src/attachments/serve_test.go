@@ -0,0 +1,61 @@
+package attachments
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSigned(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth.Configure(config.JWTConfig{Secret: "attachments-test"})
+	ctx := context.Background()
+	future := time.Now().Add(time.Hour).Unix()
+	past := time.Now().Add(-time.Hour).Unix()
+	link := func(journalID string, index int, expires int64) string {
+		return signature(ctx, journalID, index, expires)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		signature string
+		expires   string
+		// tenant is the tenant the link is followed on, empty for the default
+		tenant         string
+		valid, expired bool
+	}{
+		{"valid", link("entry", 0, future), strconv.FormatInt(future, 10), "", true, false},
+		{"expired", link("entry", 0, past), strconv.FormatInt(past, 10), "", false, true},
+		{"expiry extended", link("entry", 0, past), strconv.FormatInt(future, 10), "", false, false},
+		{"other attachment", link("entry", 1, future), strconv.FormatInt(future, 10), "", false, false},
+		{"other entry", link("other", 0, future), strconv.FormatInt(future, 10), "", false, false},
+		{"other tenant", link("entry", 0, future), strconv.FormatInt(future, 10), "acme", false, false},
+		{"no signature", "", strconv.FormatInt(future, 10), "", false, false},
+		{"no expiry", link("entry", 0, future), "", "", false, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			query := url.Values{"signature": {tc.signature}, "expires": {tc.expires}}
+			req := httptest.NewRequest(http.MethodGet, "/journal/entry/attachments/0?"+query.Encode(), nil)
+			if tc.tenant != "" {
+				req = req.WithContext(tenant.WithID(req.Context(), tc.tenant))
+			}
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = req
+
+			valid, expired := signed(c, "entry", 0)
+			if valid != tc.valid || expired != tc.expired {
+				t.Errorf("got valid %t and expired %t, want %t and %t", valid, expired, tc.valid, tc.expired)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+// Package audit records every change made to user data in an audit log: who made it, what changed and
+// the request that made it. Each module's repository is wrapped in a decorator calling Record after every
+// successful create, update and delete, so changes are recorded however they are made, including by
+// background jobs. Bookkeeping writes such as delivery records and digest timestamps are not recorded.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"profile-api/apierror"
+	"profile-api/clientip"
+	"profile-api/requestid"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+
+	// maxValueLength is the longest value kept in a change summary, longer values are truncated
+	maxValueLength = 200
+)
+
+var repo Repository
+
+// listeners are called after every change, see Subscribe
+var listeners []func(ctx context.Context, entry Entry)
+
+// Configure sets where changes are recorded. Until it is called nothing is recorded.
+func Configure(r Repository) {
+	repo = r
+}
+
+type actorKey struct{}
+
+// WithActor returns a context for changes made by the user
+func WithActor(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// Actor returns the user making changes with the context, or an empty string for the system
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// Subscribe registers fn to be called after every change, for keeping derived data such as the search index
+// up to date. Listeners are called synchronously by the writer, so they should hand slow work to a job.
+// Listeners must be subscribed before the server starts.
+func Subscribe(fn func(ctx context.Context, entry Entry)) {
+	listeners = append(listeners, fn)
+}
+
+// Record files a change to the user's resource in the audit log. Before is the resource before the
+// change, nil for a create, and after is the resource after it, nil for a delete; only the fields that
+// differ between them are kept. A change that cannot be recorded is logged rather than failing the write
+// that has already been made.
+func Record(ctx context.Context, action, resource, userID, resourceID string, before, after any) {
+	entry := Entry{
+		ID:         utils.GenerateID(),
+		UserID:     userID,
+		ActorID:    Actor(ctx),
+		Action:     action,
+		Resource:   resource,
+		ResourceID: resourceID,
+		RequestID:  requestid.FromContext(ctx),
+		ClientIP:   clientip.FromContext(ctx),
+		Time:       time.Now(),
+	}
+	changes, err := summarize(before, after)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not summarise change for audit log", "resource", resource, "error", err)
+	}
+	entry.Changes = changes
+
+	for _, fn := range listeners {
+		fn(ctx, entry)
+	}
+	if repo == nil {
+		return
+	}
+	ctx, cancel := utils.WithOperationTimeout(ctx)
+	defer cancel()
+	if err := repo.Record(ctx, entry); err != nil {
+		slog.ErrorContext(ctx, "Could not record change in audit log", "resource", resource, "resource_id", resourceID, "error", err)
+	}
+}
+
+// RecordSave files a save, which updates the resource when it existed before and creates it otherwise
+func RecordSave(ctx context.Context, resource, userID, resourceID string, before any, existed bool, after any) {
+	if !existed {
+		Record(ctx, ActionCreate, resource, userID, resourceID, nil, after)
+		return
+	}
+	Record(ctx, ActionUpdate, resource, userID, resourceID, before, after)
+}
+
+// summarize returns the fields that differ between the JSON forms of before and after, or nil when none do
+func summarize(before, after any) (json.RawMessage, error) {
+	b, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]Change{}
+	for name, value := range a {
+		// A missing field and a null one are the same
+		if !reflect.DeepEqual(b[name], value) {
+			changes[name] = Change{Before: truncate(b[name]), After: truncate(value)}
+		}
+	}
+	for name, value := range b {
+		if _, ok := a[name]; !ok && value != nil {
+			changes[name] = Change{Before: truncate(value)}
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(changes)
+}
+
+// fields returns the top-level fields of the JSON form of v
+func fields(v any) (map[string]any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
+
+// truncate shortens long values, turning long lists and objects into a truncated JSON string
+func truncate(v any) any {
+	switch value := v.(type) {
+	case nil, bool, float64:
+		return value
+	case string:
+		if len(value) > maxValueLength {
+			return cut(value)
+		}
+		return value
+	default:
+		data, _ := json.Marshal(value)
+		if len(data) > maxValueLength {
+			return cut(string(data))
+		}
+		return value
+	}
+}
+
+// cut shortens s to at most maxValueLength bytes without splitting a character
+func cut(s string) string {
+	end := maxValueLength
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end] + "…"
+}
+
+// List returns the entries matching the filter, newest first, for modules presenting changes in their own form
+func List(ctx context.Context, filter Filter) ([]Entry, error) {
+	if repo == nil {
+		return nil, nil
+	}
+	return repo.List(ctx, filter)
+}
+
+// queryFilter reads the resource and limit query parameters shared by both listings
+func queryFilter(c *gin.Context) Filter {
+	limit := defaultListLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, maxListLimit)
+	}
+	return Filter{Resource: c.Query("resource"), Limit: limit}
+}
+
+// listEntries responds with the entries matching the filter
+func listEntries(c *gin.Context, filter Filter) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	entries, err := repo.List(ctx, filter)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve audit log"))
+		return
+	}
+	if entries == nil {
+		entries = []Entry{}
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// ListOwn lists the changes made to the logged in user's data
+//
+//	@Summary		List changes to your data
+//	@Description	Lists the changes made to the logged in user's profile, CV sections, journal, webhooks and account, newest first, whoever made them
+//	@Tags			audit
+//	@Produce		json
+//	@Param			resource	query		string	false	"Only list changes to this kind of resource, such as skill or journal"
+//	@Param			limit		query		int		false	"Maximum number of entries to return (default 50, max 500)"
+//	@Success		200			{array}		Entry
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		500			{object}	apierror.Response	"Could not retrieve audit log"
+//	@Security		BearerAuth
+//	@Router			/audit [get]
+func ListOwn(c *gin.Context) {
+	filter := queryFilter(c)
+	filter.UserID = c.MustGet("userID").(string)
+	listEntries(c, filter)
+}
+
+// ListAll lists the changes made to any user's data
+//
+//	@Summary		List the audit log
+//	@Description	Lists changes to user data across every user, newest first, optionally only those to one user's data or made by one user. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			user		query		string	false	"Only list changes to this user's data"
+//	@Param			actor		query		string	false	"Only list changes made by this user"
+//	@Param			resource	query		string	false	"Only list changes to this kind of resource, such as skill or journal"
+//	@Param			limit		query		int		false	"Maximum number of entries to return (default 50, max 500)"
+//	@Success		200			{array}		Entry
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		403			{object}	apierror.Response	"Admin access required"
+//	@Failure		500			{object}	apierror.Response	"Could not retrieve audit log"
+//	@Router			/admin/audit [get]
+func ListAll(c *gin.Context) {
+	filter := queryFilter(c)
+	filter.UserID = c.Query("user")
+	filter.ActorID = c.Query("actor")
+	listEntries(c, filter)
+}
+
+// InitializeRoutes registers the audit log endpoint for owners. The router must require authentication.
+func InitializeRoutes(router *gin.RouterGroup) {
+	router.GET("", ListOwn)
+}
+
+// InitializeAdminRoutes registers the audit log endpoint for admins. The router must only admit admins.
+func InitializeAdminRoutes(router *gin.RouterGroup) {
+	router.GET("", ListAll)
+}
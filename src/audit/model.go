@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Entry records a single change to a user's data
+type Entry struct {
+	ID string `bson:"_id" json:"id"`
+	// UserID is the user owning the changed resource
+	UserID string `bson:"user_id" json:"userID"`
+	// ActorID is the user who made the change, empty for changes made by the system such as background jobs
+	ActorID    string `bson:"actor_id,omitempty" json:"actorID,omitempty"`
+	Action     string `bson:"action" json:"action"`
+	Resource   string `bson:"resource" json:"resource"`
+	ResourceID string `bson:"resource_id" json:"resourceID"`
+	// Changes maps each changed field to a summary of its values before and after the change
+	Changes   json.RawMessage `bson:"changes,omitempty" json:"changes,omitempty" swaggertype:"object"`
+	RequestID string          `bson:"request_id,omitempty" json:"requestID,omitempty"`
+	// ClientIP is the address of the client that made the change, behind any trusted proxies
+	ClientIP string    `bson:"client_ip,omitempty" json:"clientIP,omitempty"`
+	Time     time.Time `bson:"time" json:"time"`
+}
+
+// Change summarises the values of a field before and after a change
+type Change struct {
+	Before any `json:"before,omitempty"`
+	After  any `json:"after,omitempty"`
+}
+
+// Filter selects audit log entries, newest first
+type Filter struct {
+	UserID   string
+	ActorID  string
+	Resource string
+	Limit    int
+}
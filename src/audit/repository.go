@@ -0,0 +1,11 @@
+package audit
+
+import "context"
+
+// Repository stores the audit log
+type Repository interface {
+	// Record appends an entry to the log
+	Record(ctx context.Context, entry Entry) error
+	// List returns the entries matching the filter, newest first
+	List(ctx context.Context, filter Filter) ([]Entry, error)
+}
@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryRepository keeps the audit log in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) Record(ctx context.Context, entry Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, filter Filter) ([]Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var entries []Entry
+	for i := len(r.entries) - 1; i >= 0 && (filter.Limit <= 0 || len(entries) < filter.Limit); i-- {
+		entry := r.entries[i]
+		if (filter.UserID != "" && entry.UserID != filter.UserID) ||
+			(filter.ActorID != "" && entry.ActorID != filter.ActorID) ||
+			(filter.Resource != "" && entry.Resource != filter.Resource) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
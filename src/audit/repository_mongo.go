@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores the audit log in the audit_log collection
+type MongoRepository struct {
+	entries *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{entries: db.Collection("audit_log")}
+}
+
+func (r *MongoRepository) Record(ctx context.Context, entry Entry) error {
+	_, err := r.entries.InsertOne(ctx, entry)
+	return err
+}
+
+func (r *MongoRepository) List(ctx context.Context, filter Filter) ([]Entry, error) {
+	query := bson.M{}
+	if filter.UserID != "" {
+		query["user_id"] = filter.UserID
+	}
+	if filter.ActorID != "" {
+		query["actor_id"] = filter.ActorID
+	}
+	if filter.Resource != "" {
+		query["resource"] = filter.Resource
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+	cursor, err := r.entries.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []Entry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
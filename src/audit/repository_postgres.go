@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository stores the audit log in the audit_log table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Record(ctx context.Context, entry Entry) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO audit_log (id, user_id, actor_id, action, resource, resource_id, changes, request_id, client_ip, time)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		entry.ID, entry.UserID, entry.ActorID, entry.Action, entry.Resource, entry.ResourceID, entry.Changes, entry.RequestID, entry.ClientIP, entry.Time)
+	return err
+}
+
+func (r *PostgresRepository) List(ctx context.Context, filter Filter) ([]Entry, error) {
+	query := "SELECT id, user_id, actor_id, action, resource, resource_id, changes, request_id, client_ip, time FROM audit_log WHERE TRUE"
+	var args []any
+	conditions := []struct{ column, value string }{
+		{"user_id", filter.UserID},
+		{"actor_id", filter.ActorID},
+		{"resource", filter.Resource},
+	}
+	for _, cond := range conditions {
+		if cond.value != "" {
+			args = append(args, cond.value)
+			query += fmt.Sprintf(" AND %s = $%d", cond.column, len(args))
+		}
+	}
+	query += " ORDER BY time DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Entry, error) {
+		var entry Entry
+		err := row.Scan(&entry.ID, &entry.UserID, &entry.ActorID, &entry.Action, &entry.Resource,
+			&entry.ResourceID, &entry.Changes, &entry.RequestID, &entry.ClientIP, &entry.Time)
+		return entry, err
+	})
+}
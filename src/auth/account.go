@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"net/http"
+
+	"profile-api/apierror"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary		Delete account
+// @Description	Permanently delete the logged in user's account along with their profile, CV sections, journal and subscriptions. The user must have entered their password within the sudo window.
+// @Tags			Auth
+// @Produce		json
+// @Success		200	{string}	string	"Account deleted"
+// @Failure		401	{object}	apierror.Response
+// @Failure		403	{object}	apierror.Response "Password must be confirmed at /auth/sudo"
+// @Failure		500	{object}	apierror.Response
+// @Security		BearerAuth
+// @Router			/auth/account [delete]
+func DeleteAccount(c *gin.Context) {
+	userID := c.MustGet("userID").(string)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+
+	err := users.Delete(ctx, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete account"))
+		return
+	}
+
+	setTokenCookie(c, "", -1)
+	c.JSON(http.StatusOK, gin.H{"message": "Account deleted"})
+}
@@ -2,77 +2,110 @@ package auth
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"net/http"
 	"time"
 
+	"profile-api/apierror"
+	"profile-api/config"
+	"profile-api/events"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var usersCollection *mongo.Collection
+var users Repository
+
+var jwtSecret []byte
+var tokenExpiry = time.Hour
+var sudoWindow = 10 * time.Minute
 
-// ErrorResponse is a struct that represents an error response.
-//
-// swagger:model ErrorResponse
-type ErrorResponse struct {
-	// Error message
-	// example: Invalid request body
-	Error string `json:"error"`
+// Configure sets the secret and lifetime used for authentication tokens, and how recently users must have
+// entered their password for sensitive operations
+func Configure(cfg config.JWTConfig) {
+	jwtSecret = []byte(cfg.Secret)
+	tokenExpiry = cfg.Expiry.Std()
+	sudoWindow = cfg.SudoWindow.Std()
+}
+
+// Sign returns a MAC of the message keyed with the JWT secret, for tokens and links the server hands out
+// and later checks without storing them. The purpose keeps a signature made for one use from passing for
+// another.
+func Sign(purpose, message string) string {
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte(purpose + ":" + message))
+	return hex.EncodeToString(mac.Sum(nil))
 }
 
 // @Summary		Register
-// @Description	Register a new user
+// @Description	Register a new user. Registrations from disposable email domains are rejected, as are those sent too soon after fetching the form token when the server requires one. Bots filling in the hidden website field are answered as if they registered.
 // @Tags			Auth
 // @Accept			json
 // @Produce		json
 // @Param			register	body		RegisterRequest	true	"Registration request object"
 // @Success		201			{string}	string			"User created"
-// @Failure		400			{object}	ErrorResponse
-// @Failure		409			{object}	ErrorResponse
-// @Failure		500			{object}	ErrorResponse
+// @Failure		400			{object}	apierror.Response	"Invalid request body, disposable email address, or form submitted too quickly or expired"
+// @Failure		409			{object}	apierror.Response
+// @Failure		500			{object}	apierror.Response
 // @Router			/auth/register [post]
 func Register(c *gin.Context) {
 	var req RegisterRequest
-	if err := c.BindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	if reason := rejectRegistration(req, time.Now()); reason != "" {
+		countRejection(c.Request.Context(), reason, req.Email)
+		if err := rejectionError(reason); err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "User created"})
 		return
 	}
 
 	// Hash the password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not hash password"))
 		return
 	}
 
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+
 	// Check if the email is already registered
-	var existingUser User
-	err = usersCollection.FindOne(context.Background(), bson.M{"email": req.Email}).Decode(&existingUser)
-	if err != nil && err != mongo.ErrNoDocuments {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not check email existence"})
+	_, err = users.FindByEmail(ctx, req.Email)
+	if err == nil {
+		apierror.Abort(c, apierror.Conflict("Email already registered"))
 		return
 	}
-	if existingUser.Email != "" {
-		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Email already registered"})
+	if !errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.Wrap(err, "Could not check email existence"))
 		return
 	}
 
 	// Create the new user
 	newUser := User{
-		ID:       primitive.NewObjectID().Hex(),
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: string(hashedPassword),
+		ID:        primitive.NewObjectID().Hex(),
+		Name:      req.Name,
+		Email:     req.Email,
+		Password:  string(hashedPassword),
+		CreatedAt: time.Now(),
 	}
-	_, err = usersCollection.InsertOne(context.Background(), newUser)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not create user"})
+	if err := users.Create(ctx, newUser); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create user"))
 		return
 	}
+	events.Publish(ctx, newUser.ID, events.TypeUserRegistered, gin.H{"id": newUser.ID, "name": newUser.Name, "email": newUser.Email})
 
 	c.JSON(http.StatusCreated, gin.H{"message": "User created"})
 }
@@ -84,37 +117,56 @@ func Register(c *gin.Context) {
 // @Produce		json
 // @Param			login	body		LoginRequest	true	"Login request object"
 // @Success		200		{string}	string			"Token"
-// @Failure		400		{object}	ErrorResponse "Invalid request body"
-// @Failure		401		{object}	ErrorResponse "Invalid email or password"
+// @Failure		400		{object}	apierror.Response "Invalid request body"
+// @Failure		401		{object}	apierror.Response "Invalid email or password"
+// @Failure		403		{object}	apierror.Response "Account disabled or password reset required"
 // @Router			/auth/login [post]
 func Login(c *gin.Context) {
 	var req LoginRequest
-	if err := c.BindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+
 	// Find the user by email
-	var user User
-	err := usersCollection.FindOne(context.Background(), bson.M{"email": req.Email}).Decode(&user)
+	user, err := users.FindByEmail(ctx, req.Email)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		apierror.Abort(c, apierror.Unauthorized("Invalid email or password"))
 		return
 	}
 
 	// Check the password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		apierror.Abort(c, apierror.Unauthorized("Invalid email or password"))
+		return
+	}
+	if user.Disabled {
+		apierror.Abort(c, apierror.Forbidden("Account disabled"))
+		return
+	}
+	if user.ResetToken != "" {
+		apierror.Abort(c, apierror.Forbidden("Password reset required, use the token emailed to you"))
 		return
 	}
 
 	// Create a JWT token and return it to the client
-	token := createToken(user.ID)
-	c.SetCookie("token", token, 3600, "", "", false, true)
+	token := createToken(ctx, user.ID, time.Now())
+	setTokenCookie(c, token, int(tokenExpiry.Seconds()))
 	c.JSON(http.StatusOK, gin.H{"token": token})
 }
 
+// setTokenCookie sets the session cookie, deleting it when maxAge is negative. It is left off requests other
+// sites make, except for following a link, and is only sent back over HTTPS when it was set over HTTPS.
+func setTokenCookie(c *gin.Context, token string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie("token", token, maxAge, "", "", secure, true)
+}
+
 // @Summary		Logout
 // @Description	Logout the currently logged in user
 // @Tags			Auth
@@ -122,28 +174,76 @@ func Login(c *gin.Context) {
 // @Success		200	{string}	string	"Logged out"
 // @Router			/auth/logout [post]
 func Logout(c *gin.Context) {
-	c.SetCookie("token", "", -1, "", "", false, true)
+	setTokenCookie(c, "", -1)
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
 }
 
+// @Summary		Reset password
+// @Description	Sets a new password using the token emailed when an admin forces a password reset
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			reset	body		PasswordResetRequest	true	"Password reset request object"
+// @Success		200		{string}	string					"Password reset"
+// @Failure		400		{object}	apierror.Response
+// @Failure		404		{object}	apierror.Response "Invalid reset token"
+// @Failure		500		{object}	apierror.Response
+// @Router			/auth/password-reset [post]
+func ResetPassword(c *gin.Context) {
+	var req PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not hash password"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+
+	_, err = users.ResetPassword(ctx, req.Token, string(hashedPassword))
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Invalid reset token"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not reset password"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset"})
+}
+
 // InitializeRoutes initializes the authentication routes
-func InitializeRoutes(router *gin.RouterGroup, db *mongo.Client, db_name string) {
-	usersCollection = db.Database(db_name).Collection("users")
+func InitializeRoutes(router *gin.RouterGroup, repo Repository) {
+	users = repo
 	router.POST("/register", Register)
+	router.GET("/register/form", GetRegisterForm)
 	router.POST("/login", Login)
 	router.POST("/logout", Logout)
+	router.POST("/password-reset", ResetPassword)
+	router.POST("/sudo", AuthMiddleware(repo, true), Sudo)
+	router.PUT("/email", AuthMiddleware(repo, true), RequireSudo(), ChangeEmail)
+	router.DELETE("/account", AuthMiddleware(repo, true), RequireSudo(), DeleteAccount)
 }
 
-// createToken creates a new JWT token for the given user ID
-func createToken(userID string) string {
-	claims := jwt.StandardClaims{
-		Id:        userID,
-		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+// createToken creates a new JWT token for the given user ID, valid only on the tenant of the context,
+// recording when the user entered their password
+func createToken(ctx context.Context, userID string, authTime time.Time) string {
+	claims := Claims{AuthTime: authTime.Unix()}
+	claims.Id = userID
+	claims.ExpiresAt = time.Now().Add(tokenExpiry).Unix()
+	if t, ok := tenant.Current(ctx); ok {
+		claims.Audience = t.Audience()
 	}
 	token := jwt.NewWithClaims(
 		jwt.SigningMethodHS256,
 		claims,
 	)
-	signedToken, _ := token.SignedString([]byte("secret"))
+	signedToken, _ := token.SignedString(jwtSecret)
 	return signedToken
 }
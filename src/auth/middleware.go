@@ -2,53 +2,117 @@ package auth
 
 import (
 	"context"
-	"net/http"
+	"errors"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/audit"
+	"profile-api/tenant"
+	"profile-api/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func AuthMiddleware(db *mongo.Client, dbName string, required bool) gin.HandlerFunc {
+// AuthMiddleware loads the user identified by the token cookie into the context.
+// When required is set, requests without a valid token are rejected.
+func AuthMiddleware(users Repository, required bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token, err := c.Cookie("token")
 		if err != nil {
 			if required {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+				apierror.Abort(c, apierror.Unauthorized("Not authenticated"))
 				return
 			}
 			c.Next()
 			return
 		}
 
-		claims := &Claims{}
-		t, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte("secret"), nil
-		})
-		if err != nil || !t.Valid {
+		ctx, cancel := utils.DBContext(c)
+		user, claims, err := authenticate(ctx, users, token)
+		cancel()
+		if err != nil {
 			if required {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+				apierror.Abort(c, apierror.Unauthorized("Not authenticated"))
 				return
 			}
 			c.Next()
 			return
 		}
 
-		// Check if the user exists
-		usersCollection := db.Database(dbName).Collection("users")
-		var user User
-		err = usersCollection.FindOne(context.Background(), bson.M{"_id": claims.Id}).Decode(&user)
-		if err != nil {
-			if required {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
-				return
-			}
-			c.Next()
+		c.Set("user", user)
+		c.Set("userID", user.ID)
+		c.Set("authTime", time.Unix(claims.AuthTime, 0))
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), user.ID))
+		c.Next()
+	}
+}
+
+// Authenticate returns the user identified by a token issued at login, failing when the token is
+// invalid, expired or issued by another tenant, or the user no longer exists, is disabled or must reset their password
+func Authenticate(ctx context.Context, users Repository, token string) (User, error) {
+	user, _, err := authenticate(ctx, users, token)
+	return user, err
+}
+
+// authenticate is Authenticate also returning the claims of the token
+func authenticate(ctx context.Context, users Repository, token string) (User, *Claims, error) {
+	claims := &Claims{}
+	t, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	})
+	if err != nil {
+		return User{}, nil, err
+	}
+	if !t.Valid {
+		return User{}, nil, errors.New("invalid token")
+	}
+	if current, ok := tenant.Current(ctx); ok && !claims.VerifyAudience(current.Audience(), true) {
+		return User{}, nil, errors.New("token was issued for another tenant")
+	}
+	user, err := users.FindByID(ctx, claims.Id)
+	if err != nil {
+		return User{}, nil, err
+	}
+	if user.Disabled {
+		return User{}, nil, errors.New("account is disabled")
+	}
+	if user.ResetToken != "" {
+		return User{}, nil, errors.New("account awaits a password reset")
+	}
+	return user, claims, nil
+}
+
+// RequireAdmin rejects requests from users without the admin role. It must run after AuthMiddleware.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := c.Get("user")
+		if !ok {
+			apierror.Abort(c, apierror.Unauthorized("Not authenticated"))
+			return
+		}
+		if u, ok := user.(User); !ok || !u.Admin {
+			apierror.Abort(c, apierror.Forbidden("Admin access required"))
 			return
 		}
+		c.Next()
+	}
+}
 
-		c.Set("user", user)
+// RequireOwner rejects requests to change the documents of a user, named by the userid path parameter,
+// other than the authenticated one unless they are an admin. It must run after AuthMiddleware.
+func RequireOwner() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, _ := c.Get("user")
+		u, ok := user.(User)
+		if !ok {
+			apierror.Abort(c, apierror.Unauthorized("Not authenticated"))
+			return
+		}
+		if u.ID != c.Param("userid") && !u.Admin {
+			apierror.Abort(c, apierror.Forbidden("Only the owner can change these documents"))
+			return
+		}
 		c.Next()
 	}
 }
@@ -1,10 +1,16 @@
 package auth
 
-import "github.com/dgrijalva/jwt-go"
+import (
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
 
 // Claims represents the JWT claims for authentication
 type Claims struct {
 	jwt.StandardClaims
+	// AuthTime is when the user last proved who they are with their password, as a Unix time
+	AuthTime int64 `json:"auth_time,omitempty"`
 }
 
 // Token contains the JWT token for authentication
@@ -18,17 +24,69 @@ type User struct {
 	Name     string `bson:"name"`
 	Email    string `bson:"email"`
 	Password string `bson:"password"`
+	Admin    bool   `bson:"admin"`
+	// Disabled accounts can neither log in nor use tokens issued before they were disabled
+	Disabled bool `bson:"disabled"`
+	// ResetToken is set when an admin forces a password reset, until the user sets a new password with it
+	ResetToken string `bson:"reset_token,omitempty"`
+	// Plan is the billing plan the user subscribed to, or empty for the default plan
+	Plan      string    `bson:"plan,omitempty"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// UserFilter selects users to list, newest first
+type UserFilter struct {
+	// Query matches a substring of the name or email address, ignoring case
+	Query string
+	// Since only includes users who registered at or after the time
+	Since time.Time
+	// Admin only includes admins
+	Admin bool
+	Limit int
 }
 
 // RegisterRequest represents the request body for the /register endpoint
 type RegisterRequest struct {
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Name     string `json:"name" binding:"required,notblank,max=100"`
+	Email    string `json:"email" binding:"required,email,max=254"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+	// Website is hidden from people by the form, so a value is left by bots filling in every field
+	Website string `json:"website" binding:"max=2048"`
+	// FormToken is the token fetched with the registration form, recording when it was shown
+	FormToken string `json:"formToken" binding:"max=200"`
+}
+
+// RegisterForm holds the token to send with a registration
+type RegisterForm struct {
+	FormToken string `json:"formToken"`
+}
+
+// RegistrationRejections counts the registrations rejected as automated since the replica started
+type RegistrationRejections struct {
+	Since time.Time `json:"since"`
+	// Reasons counts the rejections by reason: honeypot, disposable_email, too_fast and invalid_form_token
+	Reasons map[string]int64 `json:"reasons"`
+	Total   int64            `json:"total"`
 }
 
 // LoginRequest represents the request body for the /login endpoint
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// SudoRequest represents the request body for the /sudo endpoint
+type SudoRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// EmailChangeRequest represents the request body for the /email endpoint
+type EmailChangeRequest struct {
+	Email string `json:"email" binding:"required,email,max=254"`
+}
+
+// PasswordResetRequest represents the request body for the /password-reset endpoint
+type PasswordResetRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
 }
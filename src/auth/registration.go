@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxFormAge is how long a registration form token may be used
+const maxFormAge = 24 * time.Hour
+
+// Reasons registrations are rejected
+const (
+	RejectHoneypot         = "honeypot"
+	RejectDisposableEmail  = "disposable_email"
+	RejectTooFast          = "too_fast"
+	RejectInvalidFormToken = "invalid_form_token"
+)
+
+var registration config.RegistrationConfig
+var disposableDomains = map[string]bool{}
+
+// rejections counts the registrations rejected for each reason since the replica started
+var (
+	rejectionsSince = time.Now()
+	rejections      = map[string]*atomic.Int64{
+		RejectHoneypot:         {},
+		RejectDisposableEmail:  {},
+		RejectTooFast:          {},
+		RejectInvalidFormToken: {},
+	}
+)
+
+// ConfigureRegistration sets the measures keeping bots from registering, reading the disposable domains file
+func ConfigureRegistration(cfg config.RegistrationConfig) error {
+	registration = cfg
+	disposableDomains = map[string]bool{}
+	for _, d := range cfg.DisposableDomains {
+		disposableDomains[strings.ToLower(strings.TrimSpace(d))] = true
+	}
+	if cfg.DisposableDomainsFile == "" {
+		return nil
+	}
+
+	f, err := os.Open(cfg.DisposableDomainsFile)
+	if err != nil {
+		return fmt.Errorf("error reading disposable domains: %w", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Blocklists comment lines out with #
+		if d := strings.ToLower(strings.TrimSpace(scanner.Text())); d != "" && !strings.HasPrefix(d, "#") {
+			disposableDomains[d] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading disposable domains: %w", err)
+	}
+	return nil
+}
+
+// isDisposable reports whether the email address belongs to a disposable domain or one of its subdomains
+func isDisposable(email string) bool {
+	_, domain, ok := strings.Cut(strings.ToLower(email), "@")
+	for ok {
+		if disposableDomains[domain] {
+			return true
+		}
+		_, domain, ok = strings.Cut(domain, ".")
+	}
+	return false
+}
+
+// formTokenMAC signs the time a registration form token was issued
+func formTokenMAC(issued string) string {
+	return Sign("registration-form", issued)
+}
+
+// newFormToken returns a token recording when the registration form was fetched
+func newFormToken(now time.Time) string {
+	issued := strconv.FormatInt(now.UnixMilli(), 10)
+	return issued + "." + formTokenMAC(issued)
+}
+
+// formAge returns how long ago the registration form token was issued, or false when it is not one
+func formAge(token string, now time.Time) (time.Duration, bool) {
+	issued, mac, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(mac), []byte(formTokenMAC(issued))) {
+		return 0, false
+	}
+	ms, err := strconv.ParseInt(issued, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return now.Sub(time.UnixMilli(ms)), true
+}
+
+// rejectRegistration returns why a registration looks automated, or "" when it does not
+func rejectRegistration(req RegisterRequest, now time.Time) string {
+	if registration.Honeypot && req.Website != "" {
+		return RejectHoneypot
+	}
+	if registration.MinSubmitTime > 0 {
+		age, ok := formAge(req.FormToken, now)
+		if !ok || age > maxFormAge {
+			return RejectInvalidFormToken
+		}
+		if age < registration.MinSubmitTime.Std() {
+			return RejectTooFast
+		}
+	}
+	if isDisposable(req.Email) {
+		return RejectDisposableEmail
+	}
+	return ""
+}
+
+// countRejection records a rejected registration
+func countRejection(ctx context.Context, reason, email string) {
+	rejections[reason].Add(1)
+	_, domain, _ := strings.Cut(email, "@")
+	slog.WarnContext(ctx, "Registration rejected", "reason", reason, "email_domain", domain)
+}
+
+// Rejections returns the registrations rejected for each reason since the replica started
+func Rejections() RegistrationRejections {
+	counts := RegistrationRejections{Since: rejectionsSince, Reasons: map[string]int64{}}
+	for reason, n := range rejections {
+		counts.Reasons[reason] = n.Load()
+		counts.Total += n.Load()
+	}
+	return counts
+}
+
+// @Summary		Get a registration form token
+// @Description	Returns a token to send with the registration, recording when the form was shown. When the server requires a minimum time to submit, registrations sent sooner after fetching the token, without one, or with one older than a day are rejected.
+// @Tags			Auth
+// @Produce		json
+// @Success		200	{object}	RegisterForm
+// @Router			/auth/register/form [get]
+func GetRegisterForm(c *gin.Context) {
+	c.JSON(http.StatusOK, RegisterForm{FormToken: newFormToken(time.Now())})
+}
+
+// rejectionError is the error a rejected registration is answered with. Bots filling in the honeypot are
+// told they registered, so they learn nothing.
+func rejectionError(reason string) error {
+	switch reason {
+	case RejectDisposableEmail:
+		return apierror.BadRequest("Disposable email addresses cannot be registered")
+	case RejectTooFast:
+		return apierror.BadRequest("The form was submitted too quickly, please try again")
+	case RejectInvalidFormToken:
+		return apierror.BadRequest("The form has expired, please reload it and try again")
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package auth
+
+import "context"
+
+// Repository stores registered users
+type Repository interface {
+	// FindByID returns the user with the given ID, or store.ErrNotFound
+	FindByID(ctx context.Context, userID string) (User, error)
+	// FindByEmail returns the user registered with the email address, or store.ErrNotFound
+	FindByEmail(ctx context.Context, email string) (User, error)
+	// Create stores a new user along with their empty profile
+	Create(ctx context.Context, user User) error
+	// Delete removes the user and every document they own
+	Delete(ctx context.Context, userID string) error
+	// List returns the users selected by the filter, most recently registered first
+	List(ctx context.Context, filter UserFilter) ([]User, error)
+	// SetDisabled disables or re-enables the user's account, or returns store.ErrNotFound
+	SetDisabled(ctx context.Context, userID string, disabled bool) error
+	// SetEmail changes the address the user signs in with, or returns store.ErrNotFound, or store.ErrConflict when
+	// another user is registered with it
+	SetEmail(ctx context.Context, userID string, email string) error
+	// SetPlan moves the user to the billing plan, or returns store.ErrNotFound
+	SetPlan(ctx context.Context, userID string, plan string) error
+	// RequirePasswordReset stores the token the user must present to set a new password, or returns store.ErrNotFound
+	RequirePasswordReset(ctx context.Context, userID string, token string) error
+	// ResetPassword replaces the password of the user holding the reset token and clears the token, returning
+	// the user's ID, or returns store.ErrNotFound when no user holds it
+	ResetPassword(ctx context.Context, token string, password string) (string, error)
+}
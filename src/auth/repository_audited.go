@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+
+	"profile-api/audit"
+)
+
+// AuditedRepository records every change to user accounts in the audit log
+type AuditedRepository struct {
+	Repository
+}
+
+// NewAuditedRepository wraps the repository to record its changes in the audit log
+func NewAuditedRepository(r Repository) *AuditedRepository {
+	return &AuditedRepository{Repository: r}
+}
+
+// auditedUser is the part of a user recorded in the audit log, leaving out their password and reset token
+type auditedUser struct {
+	Name                  string `json:"name"`
+	Email                 string `json:"email"`
+	Admin                 bool   `json:"admin"`
+	Disabled              bool   `json:"disabled"`
+	PasswordResetRequired bool   `json:"passwordResetRequired"`
+	Plan                  string `json:"plan,omitempty"`
+}
+
+func newAuditedUser(user User) auditedUser {
+	return auditedUser{
+		Name:                  user.Name,
+		Email:                 user.Email,
+		Admin:                 user.Admin,
+		Disabled:              user.Disabled,
+		PasswordResetRequired: user.ResetToken != "",
+		Plan:                  user.Plan,
+	}
+}
+
+// Create records the registration as made by the new user, unless an admin created the account
+func (r *AuditedRepository) Create(ctx context.Context, user User) error {
+	if err := r.Repository.Create(ctx, user); err != nil {
+		return err
+	}
+	if audit.Actor(ctx) == "" {
+		ctx = audit.WithActor(ctx, user.ID)
+	}
+	audit.Record(ctx, audit.ActionCreate, "user", user.ID, user.ID, nil, newAuditedUser(user))
+	return nil
+}
+
+// Delete records the deletion without the deleted user's details, which are removed along with their data
+func (r *AuditedRepository) Delete(ctx context.Context, userID string) error {
+	if err := r.Repository.Delete(ctx, userID); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionDelete, "user", userID, userID, nil, nil)
+	return nil
+}
+
+func (r *AuditedRepository) SetDisabled(ctx context.Context, userID string, disabled bool) error {
+	before, err := r.Repository.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.SetDisabled(ctx, userID, disabled); err != nil {
+		return err
+	}
+	after := before
+	after.Disabled = disabled
+	audit.Record(ctx, audit.ActionUpdate, "user", userID, userID, newAuditedUser(before), newAuditedUser(after))
+	return nil
+}
+
+func (r *AuditedRepository) SetEmail(ctx context.Context, userID string, email string) error {
+	before, err := r.Repository.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.SetEmail(ctx, userID, email); err != nil {
+		return err
+	}
+	after := before
+	after.Email = email
+	audit.Record(ctx, audit.ActionUpdate, "user", userID, userID, newAuditedUser(before), newAuditedUser(after))
+	return nil
+}
+
+func (r *AuditedRepository) SetPlan(ctx context.Context, userID string, plan string) error {
+	before, err := r.Repository.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.SetPlan(ctx, userID, plan); err != nil {
+		return err
+	}
+	after := before
+	after.Plan = plan
+	audit.Record(ctx, audit.ActionUpdate, "user", userID, userID, newAuditedUser(before), newAuditedUser(after))
+	return nil
+}
+
+func (r *AuditedRepository) RequirePasswordReset(ctx context.Context, userID string, token string) error {
+	before, err := r.Repository.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.RequirePasswordReset(ctx, userID, token); err != nil {
+		return err
+	}
+	after := before
+	after.ResetToken = token
+	audit.Record(ctx, audit.ActionUpdate, "user", userID, userID, newAuditedUser(before), newAuditedUser(after))
+	return nil
+}
+
+// ResetPassword records the reset as made by the user holding the token
+func (r *AuditedRepository) ResetPassword(ctx context.Context, token string, password string) (string, error) {
+	userID, err := r.Repository.ResetPassword(ctx, token, password)
+	if err != nil {
+		return userID, err
+	}
+	audit.Record(audit.WithActor(ctx, userID), audit.ActionUpdate, "user", userID, userID,
+		map[string]any{"passwordResetRequired": true},
+		map[string]any{"passwordResetRequired": false, "password": "changed"})
+	return userID, nil
+}
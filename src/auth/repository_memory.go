@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps users in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu    sync.RWMutex
+	users map[string]User
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{users: map[string]User{}}
+}
+
+func (r *MemoryRepository) FindByID(ctx context.Context, userID string) (User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	user, ok := r.users[userID]
+	if !ok {
+		return User{}, store.ErrNotFound
+	}
+	return user, nil
+}
+
+func (r *MemoryRepository) FindByEmail(ctx context.Context, email string) (User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return User{}, store.ErrNotFound
+}
+
+// Create stores the user. Profiles are kept by the profile repository, which creates one on first write.
+func (r *MemoryRepository) Create(ctx context.Context, user User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.users {
+		if existing.ID == user.ID || existing.Email == user.Email {
+			return store.ErrConflict
+		}
+	}
+	r.users[user.ID] = user
+	return nil
+}
+
+// Delete removes the user. Documents owned by the user in other in-memory repositories are left in place.
+func (r *MemoryRepository) Delete(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, userID)
+	return nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, filter UserFilter) ([]User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	query := strings.ToLower(filter.Query)
+	var users []User
+	for _, user := range r.users {
+		if query != "" && !strings.Contains(strings.ToLower(user.Name), query) && !strings.Contains(strings.ToLower(user.Email), query) {
+			continue
+		}
+		if user.CreatedAt.Before(filter.Since) || filter.Admin && !user.Admin {
+			continue
+		}
+		users = append(users, user)
+	}
+	slices.SortFunc(users, func(a, b User) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	if filter.Limit > 0 && len(users) > filter.Limit {
+		users = users[:filter.Limit]
+	}
+	return users, nil
+}
+
+func (r *MemoryRepository) SetDisabled(ctx context.Context, userID string, disabled bool) error {
+	return r.update(userID, func(user *User) { user.Disabled = disabled })
+}
+
+func (r *MemoryRepository) SetEmail(ctx context.Context, userID string, email string) error {
+	r.mu.Lock()
+	for id, existing := range r.users {
+		if id != userID && existing.Email == email {
+			r.mu.Unlock()
+			return store.ErrConflict
+		}
+	}
+	r.mu.Unlock()
+	return r.update(userID, func(user *User) { user.Email = email })
+}
+
+func (r *MemoryRepository) SetPlan(ctx context.Context, userID string, plan string) error {
+	return r.update(userID, func(user *User) { user.Plan = plan })
+}
+
+func (r *MemoryRepository) RequirePasswordReset(ctx context.Context, userID string, token string) error {
+	return r.update(userID, func(user *User) { user.ResetToken = token })
+}
+
+func (r *MemoryRepository) ResetPassword(ctx context.Context, token string, password string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, user := range r.users {
+		if user.ResetToken != "" && user.ResetToken == token {
+			user.Password = password
+			user.ResetToken = ""
+			r.users[id] = user
+			return id, nil
+		}
+	}
+	return "", store.ErrNotFound
+}
+
+// update applies the change to the stored user
+func (r *MemoryRepository) update(userID string, change func(user *User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[userID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	change(&user)
+	r.users[userID] = user
+	return nil
+}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"profile-api/store"
+	"profile-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// userDataCollections lists the collections holding documents owned by a user through their user_id
+var userDataCollections = []string{
+	"profiles",
+	"experience",
+	"qualifications",
+	"certificates",
+	"awards",
+	"languages",
+	"skills",
+	"journal",
+	"journal_settings",
+	"share_packages",
+	"share_views",
+	"subscriptions",
+	"email_log",
+	"webhooks",
+	"webhook_deliveries",
+	"audit_log",
+	"uploads",
+	"notifications",
+	"notification_preferences",
+	"activity",
+	"organization_members",
+	"recommendations",
+	"recommendation_invitations",
+	"contact_requests",
+	"blocks",
+	"privacy_settings",
+	"profile_visitors",
+}
+
+// MongoRepository stores users in the users collection
+type MongoRepository struct {
+	db    *mongo.Database
+	users *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{db: db, users: db.Collection("users")}
+}
+
+func (r *MongoRepository) FindByID(ctx context.Context, userID string) (User, error) {
+	var user User
+	err := r.users.FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
+	return user, store.MongoErr(err)
+}
+
+func (r *MongoRepository) FindByEmail(ctx context.Context, email string) (User, error) {
+	var user User
+	err := r.users.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	return user, store.MongoErr(err)
+}
+
+// Create inserts the user and their empty profile together so a failure cannot leave a user without a profile
+func (r *MongoRepository) Create(ctx context.Context, user User) error {
+	return utils.RunInTransaction(ctx, r.db.Client(), func(ctx context.Context) error {
+		if _, err := r.users.InsertOne(ctx, user); err != nil {
+			return err
+		}
+		_, err := r.db.Collection("profiles").InsertOne(ctx, bson.M{
+			"user_id":    user.ID,
+			"name":       user.Name,
+			"email":      user.Email,
+			"updated_at": time.Now(),
+		})
+		return err
+	})
+}
+
+// Delete removes the user and their documents from every collection in a single transaction
+func (r *MongoRepository) Delete(ctx context.Context, userID string) error {
+	return utils.RunInTransaction(ctx, r.db.Client(), func(ctx context.Context) error {
+		for _, name := range userDataCollections {
+			if _, err := r.db.Collection(name).DeleteMany(ctx, bson.M{"user_id": userID}); err != nil {
+				return err
+			}
+		}
+		_, err := r.users.DeleteOne(ctx, bson.M{"_id": userID})
+		return err
+	})
+}
+
+func (r *MongoRepository) List(ctx context.Context, filter UserFilter) ([]User, error) {
+	query := bson.M{}
+	if filter.Query != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(filter.Query), Options: "i"}
+		query["$or"] = bson.A{bson.M{"name": pattern}, bson.M{"email": pattern}}
+	}
+	if !filter.Since.IsZero() {
+		query["created_at"] = bson.M{"$gte": filter.Since}
+	}
+	if filter.Admin {
+		query["admin"] = true
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := r.users.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	var users []User
+	err = cursor.All(ctx, &users)
+	return users, err
+}
+
+func (r *MongoRepository) SetDisabled(ctx context.Context, userID string, disabled bool) error {
+	return r.update(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"disabled": disabled}})
+}
+
+// SetEmail relies on the unique index on email to reject addresses registered to another user
+func (r *MongoRepository) SetEmail(ctx context.Context, userID string, email string) error {
+	err := r.update(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"email": email}})
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	}
+	return err
+}
+
+// SetPlan removes the field for the default plan, as users who never subscribed have none
+func (r *MongoRepository) SetPlan(ctx context.Context, userID string, plan string) error {
+	if plan == "" {
+		return r.update(ctx, bson.M{"_id": userID}, bson.M{"$unset": bson.M{"plan": ""}})
+	}
+	return r.update(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"plan": plan}})
+}
+
+func (r *MongoRepository) RequirePasswordReset(ctx context.Context, userID string, token string) error {
+	return r.update(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"reset_token": token}})
+}
+
+func (r *MongoRepository) ResetPassword(ctx context.Context, token string, password string) (string, error) {
+	var user User
+	err := r.users.FindOneAndUpdate(ctx, bson.M{"reset_token": token}, bson.M{
+		"$set":   bson.M{"password": password},
+		"$unset": bson.M{"reset_token": ""},
+	}).Decode(&user)
+	return user.ID, store.MongoErr(err)
+}
+
+// update applies the update to the single user matched by the filter
+func (r *MongoRepository) update(ctx context.Context, filter bson.M, update bson.M) error {
+	result, err := r.users.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
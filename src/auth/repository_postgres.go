@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// userDataTables lists the tables holding rows owned by a user through their user_id
+var userDataTables = []string{
+	"profiles",
+	"experience",
+	"qualifications",
+	"certificates",
+	"awards",
+	"languages",
+	"skills",
+	"journal",
+	"journal_settings",
+	"share_packages",
+	"share_views",
+	"subscriptions",
+	"email_log",
+	"webhooks",
+	"webhook_deliveries",
+	"audit_log",
+	"uploads",
+	"notifications",
+	"notification_preferences",
+	"activity",
+	"organization_members",
+	"recommendations",
+	"recommendation_invitations",
+	"contact_requests",
+	"blocks",
+	"privacy_settings",
+	"profile_visitors",
+}
+
+// PostgresRepository stores users in the users table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+const userColumns = "id, name, email, password, admin, disabled, COALESCE(reset_token, ''), plan, created_at"
+
+func (r *PostgresRepository) FindByID(ctx context.Context, userID string) (User, error) {
+	return r.findOne(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1", userID)
+}
+
+func (r *PostgresRepository) FindByEmail(ctx context.Context, email string) (User, error) {
+	return r.findOne(ctx, "SELECT "+userColumns+" FROM users WHERE email = $1", email)
+}
+
+// Create inserts the user and their empty profile in a single transaction
+func (r *PostgresRepository) Create(ctx context.Context, user User) error {
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "INSERT INTO users (id, name, email, password, admin, disabled, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+			user.ID, user.Name, user.Email, user.Password, user.Admin, user.Disabled, user.CreatedAt)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, "INSERT INTO profiles (user_id, name, email, updated_at) VALUES ($1, $2, $3, $4)",
+			user.ID, user.Name, user.Email, time.Now())
+		return err
+	})
+	return store.PostgresErr(err)
+}
+
+// Delete removes the user and their rows from every table in a single transaction
+func (r *PostgresRepository) Delete(ctx context.Context, userID string) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		for _, table := range userDataTables {
+			if _, err := tx.Exec(ctx, "DELETE FROM "+table+" WHERE user_id = $1", userID); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec(ctx, "DELETE FROM users WHERE id = $1", userID)
+		return err
+	})
+}
+
+func (r *PostgresRepository) List(ctx context.Context, filter UserFilter) ([]User, error) {
+	query := "SELECT " + userColumns + " FROM users WHERE TRUE"
+	var args []any
+	if filter.Query != "" {
+		args = append(args, "%"+escapeLike(filter.Query)+"%")
+		query += fmt.Sprintf(" AND (name ILIKE $%d OR email ILIKE $%d)", len(args), len(args))
+	}
+	if !filter.Since.IsZero() {
+		args = append(args, filter.Since)
+		query += fmt.Sprintf(" AND created_at >= $%d", len(args))
+	}
+	if filter.Admin {
+		query += " AND admin"
+	}
+	query += " ORDER BY created_at DESC NULLS LAST, id DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var users []User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *PostgresRepository) SetDisabled(ctx context.Context, userID string, disabled bool) error {
+	return r.exec(ctx, "UPDATE users SET disabled = $2 WHERE id = $1", userID, disabled)
+}
+
+func (r *PostgresRepository) SetEmail(ctx context.Context, userID string, email string) error {
+	return r.exec(ctx, "UPDATE users SET email = $2 WHERE id = $1", userID, email)
+}
+
+func (r *PostgresRepository) SetPlan(ctx context.Context, userID string, plan string) error {
+	return r.exec(ctx, "UPDATE users SET plan = $2 WHERE id = $1", userID, plan)
+}
+
+func (r *PostgresRepository) RequirePasswordReset(ctx context.Context, userID string, token string) error {
+	return r.exec(ctx, "UPDATE users SET reset_token = $2 WHERE id = $1", userID, token)
+}
+
+func (r *PostgresRepository) ResetPassword(ctx context.Context, token string, password string) (string, error) {
+	var userID string
+	err := r.pool.QueryRow(ctx, "UPDATE users SET password = $2, reset_token = NULL WHERE reset_token = $1 RETURNING id", token, password).Scan(&userID)
+	return userID, store.PostgresErr(err)
+}
+
+// exec runs a statement updating a single user, returning store.ErrNotFound when it matches none
+func (r *PostgresRepository) exec(ctx context.Context, query string, args ...any) error {
+	tag, err := r.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return store.PostgresErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// findOne returns the single user selected by the query
+func (r *PostgresRepository) findOne(ctx context.Context, query string, arg string) (User, error) {
+	user, err := scanUser(r.pool.QueryRow(ctx, query, arg))
+	return user, store.PostgresErr(err)
+}
+
+// scanUser reads a row of userColumns. Users created before signup times were recorded have none.
+func scanUser(row pgx.Row) (User, error) {
+	var user User
+	var createdAt *time.Time
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Admin, &user.Disabled, &user.ResetToken, &user.Plan, &createdAt)
+	if createdAt != nil {
+		user.CreatedAt = *createdAt
+	}
+	return user, err
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) FindByID(ctx context.Context, userID string) (User, error) {
+	return r.repos.For(ctx).FindByID(ctx, userID)
+}
+
+func (r *TenantRepository) FindByEmail(ctx context.Context, email string) (User, error) {
+	return r.repos.For(ctx).FindByEmail(ctx, email)
+}
+
+func (r *TenantRepository) Create(ctx context.Context, user User) error {
+	return r.repos.For(ctx).Create(ctx, user)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, userID string) error {
+	return r.repos.For(ctx).Delete(ctx, userID)
+}
+
+func (r *TenantRepository) List(ctx context.Context, filter UserFilter) ([]User, error) {
+	return r.repos.For(ctx).List(ctx, filter)
+}
+
+func (r *TenantRepository) SetDisabled(ctx context.Context, userID string, disabled bool) error {
+	return r.repos.For(ctx).SetDisabled(ctx, userID, disabled)
+}
+
+func (r *TenantRepository) SetEmail(ctx context.Context, userID string, email string) error {
+	return r.repos.For(ctx).SetEmail(ctx, userID, email)
+}
+
+func (r *TenantRepository) SetPlan(ctx context.Context, userID string, plan string) error {
+	return r.repos.For(ctx).SetPlan(ctx, userID, plan)
+}
+
+func (r *TenantRepository) RequirePasswordReset(ctx context.Context, userID string, token string) error {
+	return r.repos.For(ctx).RequirePasswordReset(ctx, userID, token)
+}
+
+func (r *TenantRepository) ResetPassword(ctx context.Context, token string, password string) (string, error) {
+	return r.repos.For(ctx).ResetPassword(ctx, token, password)
+}
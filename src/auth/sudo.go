@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// RequireSudo rejects requests from users who have not entered their password within the sudo window, so a
+// stolen or forgotten session can't be used to take over or delete the account. Users confirm their password
+// at /auth/sudo and retry. It must run after AuthMiddleware.
+func RequireSudo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authTime, ok := c.Get("authTime")
+		if !ok {
+			apierror.Abort(c, apierror.Unauthorized("Not authenticated"))
+			return
+		}
+		if t, ok := authTime.(time.Time); !ok || time.Since(t) > sudoWindow {
+			apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.CodeSudoRequired, "Confirm your password to continue").
+				WithDetails(gin.H{"challenge": "/auth/sudo", "window": sudoWindow.String()}))
+			return
+		}
+		c.Next()
+	}
+}
+
+// @Summary		Confirm password
+// @Description	Confirms the logged in user's password, renewing their token so they may do sensitive operations, such as deleting their account or changing their email address, within the sudo window
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			sudo	body		SudoRequest	true	"Password confirmation request object"
+// @Success		200		{string}	string		"Token"
+// @Failure		400		{object}	apierror.Response
+// @Failure		401		{object}	apierror.Response "Not authenticated or wrong password"
+// @Security		BearerAuth
+// @Router			/auth/sudo [post]
+func Sudo(c *gin.Context) {
+	var req SudoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	user := c.MustGet("user").(User)
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		apierror.Abort(c, apierror.Unauthorized("Invalid password"))
+		return
+	}
+
+	token := createToken(c.Request.Context(), user.ID, time.Now())
+	setTokenCookie(c, token, int(tokenExpiry.Seconds()))
+	c.JSON(http.StatusOK, gin.H{"token": token})
+}
+
+// @Summary		Change email
+// @Description	Changes the address the logged in user signs in with. The user must have entered their password within the sudo window.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			email	body		EmailChangeRequest	true	"Email change request object"
+// @Success		200		{string}	string				"Email changed"
+// @Failure		400		{object}	apierror.Response
+// @Failure		401		{object}	apierror.Response
+// @Failure		403		{object}	apierror.Response "Password must be confirmed at /auth/sudo"
+// @Failure		409		{object}	apierror.Response "Email already registered"
+// @Failure		500		{object}	apierror.Response
+// @Security		BearerAuth
+// @Router			/auth/email [put]
+func ChangeEmail(c *gin.Context) {
+	var req EmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	userID := c.MustGet("userID").(string)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+
+	err := users.SetEmail(ctx, userID, req.Email)
+	if errors.Is(err, store.ErrConflict) {
+		apierror.Abort(c, apierror.Conflict("Email already registered"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not change email"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email changed"})
+}
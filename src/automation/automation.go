@@ -0,0 +1,427 @@
+// Package automation is a simplified API for no-code tools such as Zapier and n8n. Users create API keys
+// for the tools they connect, which then authenticate with the X-API-Key header rather than the session
+// cookie. The endpoints take and return flat JSON objects with fixed fields, so each maps directly onto a
+// tool's trigger or action: polling recent changes to the user's data, adding a journal entry and adding a
+// certificate.
+package automation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/certificates"
+	"profile-api/events"
+	"profile-api/journal"
+	"profile-api/quota"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// keyPrefix starts every API key, so leaked keys are easy to recognise
+const keyPrefix = "pak_"
+
+const (
+	// maxKeys bounds the keys a user may hold
+	maxKeys = 20
+	// touchInterval is how often the last use of a key is recorded
+	touchInterval = time.Minute
+
+	defaultEventLimit = 25
+	maxEventLimit     = 100
+)
+
+var (
+	repo     Repository
+	users    auth.Repository
+	journals journal.Repository
+	certs    certificates.Repository
+)
+
+// Configure sets where API keys and the documents created through them are stored, and starts removing
+// the keys of users who delete their account. j or c is nil when its module is disabled, leaving out the
+// endpoint creating its documents.
+func Configure(r Repository, u auth.Repository, j journal.Repository, c certificates.Repository) {
+	repo = r
+	users = u
+	journals = j
+	certs = c
+	audit.Subscribe(removeKeys)
+}
+
+// removeKeys removes the API keys of a deleted user
+func removeKeys(ctx context.Context, entry audit.Entry) {
+	if entry.Resource != "user" || entry.Action != audit.ActionDelete {
+		return
+	}
+	if err := repo.DeleteUser(ctx, entry.ResourceID); err != nil {
+		slog.ErrorContext(ctx, "Could not remove the API keys of a deleted user", "user_id", entry.ResourceID, "error", err)
+	}
+}
+
+// hashKey returns the hash a key is stored and looked up by
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// presentedKey returns the API key sent in the X-API-Key header, or as a bearer token
+func presentedKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && strings.HasPrefix(token, keyPrefix) {
+		return token
+	}
+	return ""
+}
+
+// Authenticate authenticates requests by API key, as the user who created it. Keys of users who were
+// disabled or must reset their password are refused.
+func Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := presentedKey(c)
+		if secret == "" {
+			apierror.Abort(c, apierror.Unauthorized("API key required"))
+			return
+		}
+
+		ctx, cancel := utils.DBContext(c)
+		defer cancel()
+		key, err := repo.GetByHash(ctx, hashKey(secret))
+		if err != nil {
+			apierror.Abort(c, apierror.Unauthorized("Invalid API key"))
+			return
+		}
+		user, err := users.FindByID(ctx, key.UserID)
+		if err != nil || user.Disabled || user.ResetToken != "" {
+			apierror.Abort(c, apierror.Unauthorized("Invalid API key"))
+			return
+		}
+		if now := time.Now(); key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= touchInterval {
+			if err := repo.Touch(ctx, key.ID, now); err != nil {
+				slog.WarnContext(ctx, "Could not record API key use", "key_id", key.ID, "error", err)
+			}
+		}
+
+		c.Set("user", user)
+		c.Set("userID", user.ID)
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), user.ID))
+		c.Next()
+	}
+}
+
+// CreateKey creates an API key for the current user
+//
+//	@Summary		Create an API key
+//	@Description	Creates an API key for connecting a no-code tool such as Zapier or n8n to the automation endpoints, which it sends in the X-API-Key header. The key acts as the user who created it, and is only returned here. A user may hold up to 20 keys. The user must have entered their password within the sudo window.
+//	@Tags			automation
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		KeyRequest	true	"Key name"
+//	@Success		201		{object}	CreatedKey
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Password must be confirmed at /auth/sudo"
+//	@Failure		409		{object}	apierror.Response	"Too many API keys"
+//	@Failure		500		{object}	apierror.Response	"Could not create API key"
+//	@Router			/automation/keys [post]
+func CreateKey(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req KeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	keys, err := repo.List(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create API key"))
+		return
+	}
+	if len(keys) >= maxKeys {
+		apierror.Abort(c, apierror.Conflict("Too many API keys, delete one first"))
+		return
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create API key"))
+		return
+	}
+	secret := keyPrefix + hex.EncodeToString(random)
+	key := Key{
+		ID:        utils.GenerateID(),
+		UserID:    user.ID,
+		Name:      req.Name,
+		Prefix:    secret[:len(keyPrefix)+8],
+		Hash:      hashKey(secret),
+		CreatedAt: time.Now(),
+	}
+	if err := repo.Create(ctx, key); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create API key"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreatedKey{Key: key, Secret: secret})
+}
+
+// ListKeys lists the current user's API keys
+//
+//	@Summary		List API keys
+//	@Description	Lists the current user's API keys, oldest first, with the start of each key and when it was last used
+//	@Tags			automation
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		Key
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve API keys"
+//	@Router			/automation/keys [get]
+func ListKeys(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	keys, err := repo.List(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve API keys"))
+		return
+	}
+	if keys == nil {
+		keys = []Key{}
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// DeleteKey revokes one of the current user's API keys
+//
+//	@Summary		Delete an API key
+//	@Description	Revokes an API key. Tools using it are refused from then on.
+//	@Tags			automation
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			keyid	path		string	true	"API key ID"
+//	@Success		200		{object}	map[string]string
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		404		{object}	apierror.Response	"API key not found"
+//	@Router			/automation/keys/{keyid} [delete]
+func DeleteKey(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Delete(ctx, user.ID, c.Param("keyid")); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "API key not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key deleted"})
+}
+
+// GetMe returns the user the API key authenticates as
+//
+//	@Summary		Test an API key
+//	@Description	Returns the user the API key authenticates as, for tools checking a connection
+//	@Tags			automation
+//	@Produce		json
+//	@Param			X-API-Key	header		string	true	"API key"
+//	@Success		200			{object}	Me
+//	@Failure		401			{object}	apierror.Response	"Invalid API key"
+//	@Router			/automation/me [get]
+func GetMe(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+	c.JSON(http.StatusOK, Me{ID: user.ID, Name: user.Name, Email: user.Email})
+}
+
+// ListEvents lists the latest changes to the user's data
+//
+//	@Summary		List recent changes
+//	@Description	Lists the latest changes to the user's profile, CV sections, journal and account, newest first, for polling triggers. Each change has an ID unique to it, so tools can tell new changes from those already seen. Its type is the resource and what was done to it, such as certificate.created or journal.updated.
+//	@Tags			automation
+//	@Produce		json
+//	@Param			X-API-Key	header		string	true	"API key"
+//	@Param			resource	query		string	false	"Only list changes to this kind of resource, such as certificate or journal"
+//	@Param			limit		query		int		false	"Maximum number of changes to return (default 25, max 100)"
+//	@Success		200			{array}		Event
+//	@Failure		401			{object}	apierror.Response	"Invalid API key"
+//	@Failure		500			{object}	apierror.Response	"Could not retrieve changes"
+//	@Router			/automation/events [get]
+func ListEvents(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+	limit := defaultEventLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, maxEventLimit)
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	entries, err := audit.List(ctx, audit.Filter{UserID: user.ID, Resource: c.Query("resource"), Limit: limit})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve changes"))
+		return
+	}
+	changes := make([]Event, 0, len(entries))
+	for _, e := range entries {
+		changes = append(changes, Event{
+			ID:         e.ID,
+			Type:       e.Resource + "." + e.Action + "d",
+			Resource:   e.Resource,
+			ResourceID: e.ResourceID,
+			Action:     e.Action,
+			Time:       e.Time,
+		})
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// checkDocumentsLeft returns the limit error when the user may keep no more documents of the collection
+func checkDocumentsLeft(ctx context.Context, user auth.User, collection string) *apierror.Error {
+	left, err := quota.DocumentsLeft(ctx, user, collection)
+	if err != nil {
+		return apierror.Wrap(err, "Could not check document quota")
+	}
+	if left == 0 {
+		return quota.DocumentLimitError(user, collection)
+	}
+	return nil
+}
+
+// CreateJournalEntry adds a journal entry for the user
+//
+//	@Summary		Add a journal entry
+//	@Description	Adds a journal entry with the title and content, with the status and taxonomy of the user's journal settings like one added through the journal endpoints
+//	@Tags			automation
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-API-Key	header		string				true	"API key"
+//	@Param			request		body		JournalEntryRequest	true	"Journal entry"
+//	@Success		201			{object}	JournalEntry
+//	@Header			201			{string}	Location	"URL of the created journal entry"
+//	@Failure		400			{object}	apierror.Response	"Invalid request body"
+//	@Failure		401			{object}	apierror.Response	"Invalid API key"
+//	@Failure		402			{object}	apierror.Response	"The user's plan allows no more journal entries"
+//	@Failure		500			{object}	apierror.Response	"Could not create journal entry"
+//	@Router			/automation/journal [post]
+func CreateJournalEntry(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req JournalEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := checkDocumentsLeft(ctx, user, "journal"); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	entry, err := journal.Create(ctx, user.ID, journal.Entry{Title: req.Title, Content: req.Content, Attachments: []string{}, UpdatedAt: time.Now()})
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.Header("Location", "/api/v1/journal/"+entry.JournalID)
+	c.JSON(http.StatusCreated, JournalEntry{
+		ID:        entry.JournalID,
+		Title:     req.Title,
+		Content:   req.Content,
+		Status:    entry.Status,
+		CreatedAt: entry.CreatedAt,
+	})
+}
+
+// CreateCertificate adds a certificate for the user
+//
+//	@Summary		Add a certificate
+//	@Description	Adds a certificate to the user's CV, like one added through the certificate endpoints
+//	@Tags			automation
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-API-Key	header		string				true	"API key"
+//	@Param			request		body		CertificateRequest	true	"Certificate"
+//	@Success		201			{object}	Certificate
+//	@Failure		400			{object}	apierror.Response	"Invalid request body"
+//	@Failure		401			{object}	apierror.Response	"Invalid API key"
+//	@Failure		402			{object}	apierror.Response	"The user's plan allows no more certificates"
+//	@Failure		500			{object}	apierror.Response	"Could not create certificate"
+//	@Router			/automation/certificates [post]
+func CreateCertificate(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req CertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := checkDocumentsLeft(ctx, user, "certificates"); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	cert := certificates.Certificate{
+		UserID:        user.ID,
+		CertificateID: primitive.NewObjectID().Hex(),
+		Title:         req.Title,
+		Institution:   req.Institution,
+		Start:         req.Start,
+		End:           req.End,
+		Description:   req.Description,
+	}
+	if err := certs.Create(ctx, cert); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create certificate"))
+		return
+	}
+	events.Publish(ctx, user.ID, events.TypeCertificateCreated, cert)
+
+	c.JSON(http.StatusCreated, Certificate{
+		ID:          cert.CertificateID,
+		Title:       cert.Title,
+		Institution: cert.Institution,
+		Start:       cert.Start,
+		End:         cert.End,
+		Description: cert.Description,
+	})
+}
+
+// InitializeRoutes registers the API key management routes, authenticated by session, and the automation
+// endpoints, authenticated by API key. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup, users auth.Repository) {
+	keys := router.Group("/keys")
+	keys.Use(auth.AuthMiddleware(users, true))
+	keys.POST("", auth.RequireSudo(), CreateKey)
+	keys.GET("", ListKeys)
+	keys.DELETE("/:keyid", DeleteKey)
+
+	automated := router.Group("/")
+	automated.Use(Authenticate())
+	automated.GET("/me", GetMe)
+	automated.GET("/events", ListEvents)
+	if journals != nil {
+		automated.POST("/journal", CreateJournalEntry)
+	}
+	if certs != nil {
+		automated.POST("/certificates", CreateCertificate)
+	}
+}
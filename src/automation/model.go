@@ -0,0 +1,80 @@
+package automation
+
+import "time"
+
+// Key is an API key authenticating a no-code tool, such as Zapier or n8n, as the user who created it
+type Key struct {
+	ID     string `bson:"_id" json:"id"`
+	UserID string `bson:"user_id" json:"userID"`
+	Name   string `bson:"name" json:"name"`
+	// Prefix is the start of the key, to tell keys apart
+	Prefix string `bson:"prefix" json:"prefix"`
+	// Hash is the SHA-256 of the key, which is itself only shown when it is created
+	Hash       string     `bson:"hash" json:"-"`
+	CreatedAt  time.Time  `bson:"created_at" json:"createdAt"`
+	LastUsedAt *time.Time `bson:"last_used_at,omitempty" json:"lastUsedAt,omitempty"`
+}
+
+// CreatedKey is returned when a key is created, the only time the key is shown
+type CreatedKey struct {
+	Key
+	Secret string `json:"key"`
+}
+
+// KeyRequest represents the request body for creating an API key
+type KeyRequest struct {
+	Name string `json:"name" binding:"required,notblank,max=100"`
+}
+
+// Me is the user an API key authenticates as
+type Me struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Event is a change to the user's data, flattened for polling triggers
+type Event struct {
+	// ID is unique to the change, for tools telling new events from those already seen
+	ID string `json:"id"`
+	// Type is the resource and what was done to it, such as certificate.created
+	Type       string    `json:"type"`
+	Resource   string    `json:"resource"`
+	ResourceID string    `json:"resourceID"`
+	Action     string    `json:"action"`
+	Time       time.Time `json:"time"`
+}
+
+// JournalEntryRequest represents the request body for creating a journal entry
+type JournalEntryRequest struct {
+	Title   string `json:"title" binding:"required,notblank,max=300"`
+	Content string `json:"content" binding:"required,max=200000"`
+}
+
+// JournalEntry is a created journal entry
+type JournalEntry struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CertificateRequest represents the request body for adding a certificate
+type CertificateRequest struct {
+	Title       string `json:"title" binding:"required,notblank,max=200"`
+	Institution string `json:"institution" binding:"required,notblank,max=200"`
+	Start       string `json:"start" binding:"omitempty,date"`
+	End         string `json:"end" binding:"omitempty,date"`
+	Description string `json:"description" binding:"max=5000"`
+}
+
+// Certificate is an added certificate
+type Certificate struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Institution string `json:"institution"`
+	Start       string `json:"start"`
+	End         string `json:"end"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,22 @@
+package automation
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores API keys
+type Repository interface {
+	// Create stores a new key
+	Create(ctx context.Context, key Key) error
+	// GetByHash returns the key with the given hash, or store.ErrNotFound
+	GetByHash(ctx context.Context, hash string) (Key, error)
+	// List returns the user's keys, oldest first
+	List(ctx context.Context, userID string) ([]Key, error)
+	// Delete removes one of the user's keys, or returns store.ErrNotFound
+	Delete(ctx context.Context, userID, keyID string) error
+	// Touch records when the key was last used
+	Touch(ctx context.Context, keyID string, at time.Time) error
+	// DeleteUser removes the user's keys
+	DeleteUser(ctx context.Context, userID string) error
+}
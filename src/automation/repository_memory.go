@@ -0,0 +1,82 @@
+package automation
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps API keys in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu   sync.RWMutex
+	keys []Key
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, key Key) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.ContainsFunc(r.keys, func(k Key) bool { return k.ID == key.ID || k.Hash == key.Hash }) {
+		return store.ErrConflict
+	}
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *MemoryRepository) GetByHash(ctx context.Context, hash string) (Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := slices.IndexFunc(r.keys, func(k Key) bool { return k.Hash == hash })
+	if i < 0 {
+		return Key{}, store.ErrNotFound
+	}
+	return r.keys[i], nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string) ([]Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var keys []Key
+	for _, k := range r.keys {
+		if k.UserID == userID {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, userID, keyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.keys, func(k Key) bool { return k.ID == keyID && k.UserID == userID })
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.keys = slices.Delete(r.keys, i, i+1)
+	return nil
+}
+
+func (r *MemoryRepository) Touch(ctx context.Context, keyID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.keys {
+		if r.keys[i].ID == keyID {
+			r.keys[i].LastUsedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) DeleteUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = slices.DeleteFunc(r.keys, func(k Key) bool { return k.UserID == userID })
+	return nil
+}
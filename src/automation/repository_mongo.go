@@ -0,0 +1,71 @@
+package automation
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores API keys in the api_keys collection
+type MongoRepository struct {
+	keys *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{keys: db.Collection("api_keys")}
+}
+
+func (r *MongoRepository) Create(ctx context.Context, key Key) error {
+	_, err := r.keys.InsertOne(ctx, key)
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	}
+	return err
+}
+
+func (r *MongoRepository) GetByHash(ctx context.Context, hash string) (Key, error) {
+	var key Key
+	err := r.keys.FindOne(ctx, bson.M{"hash": hash}).Decode(&key)
+	return key, store.MongoErr(err)
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string) ([]Key, error) {
+	cursor, err := r.keys.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var keys []Key
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, userID, keyID string) error {
+	res, err := r.keys.DeleteOne(ctx, bson.M{"_id": keyID, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) Touch(ctx context.Context, keyID string, at time.Time) error {
+	_, err := r.keys.UpdateOne(ctx, bson.M{"_id": keyID}, bson.M{"$set": bson.M{"last_used_at": at}})
+	return err
+}
+
+func (r *MongoRepository) DeleteUser(ctx context.Context, userID string) error {
+	_, err := r.keys.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
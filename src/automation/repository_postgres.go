@@ -0,0 +1,73 @@
+package automation
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const keyColumns = "id, user_id, name, prefix, hash, created_at, last_used_at"
+
+// PostgresRepository stores API keys in the api_keys table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, key Key) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO api_keys ("+keyColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		key.ID, key.UserID, key.Name, key.Prefix, key.Hash, key.CreatedAt, key.LastUsedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) GetByHash(ctx context.Context, hash string) (Key, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+keyColumns+" FROM api_keys WHERE hash = $1", hash)
+	if err != nil {
+		return Key{}, err
+	}
+	key, err := pgx.CollectExactlyOneRow(rows, scanKey)
+	return key, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string) ([]Key, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+keyColumns+" FROM api_keys WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanKey)
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, userID, keyID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM api_keys WHERE id = $1 AND user_id = $2", keyID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Touch(ctx context.Context, keyID string, at time.Time) error {
+	_, err := r.pool.Exec(ctx, "UPDATE api_keys SET last_used_at = $2 WHERE id = $1", keyID, at)
+	return err
+}
+
+func (r *PostgresRepository) DeleteUser(ctx context.Context, userID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM api_keys WHERE user_id = $1", userID)
+	return err
+}
+
+func scanKey(row pgx.CollectableRow) (Key, error) {
+	var key Key
+	err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.Hash, &key.CreatedAt, &key.LastUsedAt)
+	return key, err
+}
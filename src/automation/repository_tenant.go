@@ -0,0 +1,42 @@
+package automation
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, key Key) error {
+	return r.repos.For(ctx).Create(ctx, key)
+}
+
+func (r *TenantRepository) GetByHash(ctx context.Context, hash string) (Key, error) {
+	return r.repos.For(ctx).GetByHash(ctx, hash)
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID string) ([]Key, error) {
+	return r.repos.For(ctx).List(ctx, userID)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, userID, keyID string) error {
+	return r.repos.For(ctx).Delete(ctx, userID, keyID)
+}
+
+func (r *TenantRepository) Touch(ctx context.Context, keyID string, at time.Time) error {
+	return r.repos.For(ctx).Touch(ctx, keyID, at)
+}
+
+func (r *TenantRepository) DeleteUser(ctx context.Context, userID string) error {
+	return r.repos.For(ctx).DeleteUser(ctx, userID)
+}
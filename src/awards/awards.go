@@ -0,0 +1,372 @@
+package awards
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"profile-api/apierror"
+	"profile-api/apiversion"
+	"profile-api/auth"
+	"profile-api/bulk"
+	"profile-api/dryrun"
+	"profile-api/images"
+	"profile-api/logging"
+	"profile-api/quota"
+	"profile-api/scan"
+	"profile-api/store"
+	"profile-api/utils"
+	"profile-api/visibility"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var repo Repository
+var users auth.Repository
+
+// GetAwards retrieves all awards for a given user.
+//
+//	@Summary		Get all awards
+//	@Description	Retrieves all awards and honors of a given user, without the fields their visibility hides from the requester
+//	@Tags			Awards
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Success		200		{array}		Award
+//	@Failure		500		{object}	apierror.Response	"error":	"Could not retrieve awards"
+//	@Router			/awards/{userid} [get]
+func GetAwards(c *gin.Context) {
+	userID := c.Param("userid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	awards, err := repo.List(ctx, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve awards"))
+		return
+	}
+
+	visibility.List(c, awards)
+}
+
+// GetAward retrieves a specific award for a user.
+//
+//	@Summary		Get an award
+//	@Description	Retrieves a specific award of a user, without the fields its visibility hides from the requester
+//	@Tags			Awards
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Param			awardid	path		string	true	"Award ID"
+//	@Success		200		{object}	Award
+//	@Failure		404		{object}	apierror.Response	"error":	"Award not found"
+//	@Failure		500		{object}	apierror.Response	"error":	"Could not retrieve award"
+//	@Router			/awards/{userid}/{awardid} [get]
+func GetAward(c *gin.Context) {
+	userID := c.Param("userid")
+	awardID := c.Param("awardid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	award, err := repo.Get(ctx, userID, awardID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve award"))
+		return
+	}
+
+	visibility.OK(c, award)
+}
+
+// PostAward creates a new award for a user.
+//
+//	@Summary		Create an award
+//	@Description	Creates a new award or honor for a user
+//	@Tags			Awards
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Param			body	body		Award	true	"Award JSON object"
+//	@Success		200		{object}	Award	"The created award with its ID, and a message"
+//	@Failure		400		{object}	apierror.Response	"error":	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"error":	"Unauthorized"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the awards"
+//	@Failure		413		{object}	apierror.Response	"error":	"Award limit reached"
+//	@Failure		500		{object}	apierror.Response	"error":	"Could not create award"
+//	@Security		BearerAuth
+//	@Router			/awards/{userid} [post]
+func PostAward(c *gin.Context) {
+	userID := c.Param("userid")
+
+	var req Award
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	req.UserID = userID
+	req.AwardID = primitive.NewObjectID().Hex()
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Create(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create award"))
+		return
+	}
+
+	apiversion.Created(c, req.AwardID, req, apiversion.Announce("Award Added", req))
+}
+
+// PutAward updates or creates a specific award for a user.
+//
+//	@Summary		Update or create an award
+//	@Description	Updates or creates a specific award of a user
+//	@Tags			Awards
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Param			awardid	path		string	true	"Award ID"
+//	@Param			body	body		Award	true	"Award JSON object"
+//	@Success		200		{object}	map[string]string
+//	@Failure		400		{object}	apierror.Response	"error":	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"error":	"Unauthorized"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the awards"
+//	@Failure		500		{object}	apierror.Response	"error":	"Could not update award"
+//	@Security		BearerAuth
+//	@Router			/awards/{userid}/{awardid} [put]
+func PutAward(c *gin.Context) {
+	userID := c.Param("userid")
+	awardID := c.Param("awardid")
+
+	var req Award
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	req.UserID = userID
+	req.AwardID = awardID
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Save(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update award"))
+		return
+	}
+
+	apiversion.Updated(c, req, gin.H{"message": "Award updated"})
+}
+
+// DeleteAward deletes a specific award of a user.
+//
+//	@Summary		Delete an award
+//	@Description	Deletes a specific award of a user, with its image
+//	@Tags			Awards
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Param			awardid	path		string	true	"Award ID"
+//	@Success		200		{object}	map[string]string
+//	@Failure		401		{object}	apierror.Response	"error":	"Unauthorized"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the awards"
+//	@Failure		500		{object}	apierror.Response	"error":	"Could not delete award"
+//	@Failure		404		{object}	apierror.Response	"Award not found"
+//	@Security		BearerAuth
+//	@Router			/awards/{userid}/{awardid} [delete]
+func DeleteAward(c *gin.Context) {
+	userID := c.Param("userid")
+	awardID := c.Param("awardid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	err := repo.Delete(ctx, userID, awardID)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Award not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete award"))
+		return
+	}
+
+	apiversion.NoContent(c, gin.H{"message": "Award deleted"})
+}
+
+// BulkDeleteAwards deletes many of a user's awards at once.
+//
+//	@Summary		Delete awards in bulk
+//	@Description	Deletes the user's awards with the given IDs, or those whose fields match every value of the filter, such as {"issuer": "ACM"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.
+//	@Tags			Awards
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string				true	"User ID"
+//	@Param			body	body		bulk.DeleteRequest	true	"The awards to delete"
+//	@Success		200		{object}	bulk.DeleteResult
+//	@Failure		400		{object}	apierror.Response	"Invalid request, or a filter that is not confirmed"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the awards"
+//	@Failure		500		{object}	apierror.Response	"Could not delete awards"
+//	@Router			/awards/{userid}/bulk-delete [post]
+func BulkDeleteAwards(c *gin.Context) {
+	userID := c.Param("userid")
+
+	var req bulk.DeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	items, err := repo.List(ctx, userID)
+	cancel()
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve awards"))
+		return
+	}
+	result, err := bulk.Delete(c.Request.Context(), req, items, func(item Award) string { return item.AwardID }, func(ctx context.Context, id string) error {
+		return repo.Delete(ctx, userID, id)
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete awards"))
+		return
+	}
+
+	apiversion.OK(c, result)
+}
+
+// GetAwardImage serves the image of an award.
+//
+//	@Summary		Get an award's image
+//	@Description	Serves the image uploaded for an award, such as a photo of the medal or certificate
+//	@Tags			Awards
+//	@Produce		image/jpeg,image/png,image/gif,image/webp
+//	@Param			userid	path		string	true	"User ID"
+//	@Param			awardid	path		string	true	"Award ID"
+//	@Success		200		{file}		binary	"Image"
+//	@Failure		404		{object}	apierror.Response	"Image not found"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve image"
+//	@Router			/awards/{userid}/{awardid}/image [get]
+func GetAwardImage(c *gin.Context) {
+	userID := c.Param("userid")
+	awardID := c.Param("awardid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	image, err := repo.GetImage(ctx, userID, awardID)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Image not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve image"))
+		return
+	}
+
+	c.Data(http.StatusOK, http.DetectContentType(image), image)
+}
+
+// PutAwardImage uploads or replaces the image of an award.
+//
+//	@Summary		Upload or update an award's image
+//	@Description	Uploads or replaces the image of an award, such as a photo of the medal or certificate. The image is scanned for malware after it is stored.
+//	@Tags			Awards
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Param			awardid	path		string	true	"Award ID"
+//	@Param			file	formData	file	true	"Award image"
+//	@Success		200		{object}	map[string]string
+//	@Failure		400		{object}	apierror.Response	"Invalid image"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the awards"
+//	@Failure		402		{object}	apierror.Response	"The user's plan has no storage left for the image"
+//	@Failure		413		{object}	apierror.Response	"The user has no storage left for the image"
+//	@Security		BearerAuth
+//	@Router			/awards/{userid}/{awardid}/image [put]
+func PutAwardImage(c *gin.Context) {
+	userID := c.Param("userid")
+	awardID := c.Param("awardid")
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("invalid request body"))
+		return
+	}
+
+	fileReader, err := file.Open()
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("invalid request body"))
+		return
+	}
+	defer fileReader.Close()
+	image, err := io.ReadAll(fileReader)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("invalid request body"))
+		return
+	}
+	// Remove the metadata of photos, such as where they were taken
+	image, err = images.Process(image)
+	if errors.Is(err, images.ErrInvalid) {
+		apierror.Abort(c, apierror.BadRequest("invalid image"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "could not process image"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	// The image counts against the storage of the award's owner, who an admin may be uploading it for
+	owner := c.MustGet("user").(auth.User)
+	if owner.ID != userID {
+		if owner, err = users.FindByID(ctx, userID); err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "could not find award owner"))
+			return
+		}
+	}
+	upload := quota.NewUpload(userID, "award", awardID, "image", int64(len(image)))
+	if err := quota.CheckUpload(ctx, owner, upload); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "could not check storage quota"))
+		return
+	}
+	if err := repo.SetImage(ctx, userID, awardID, image); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "could not update award"))
+		return
+	}
+	if err := quota.RecordUpload(ctx, upload); err != nil {
+		logging.Logger(c).Error("Could not record upload", "error", err)
+	}
+	if err := scan.Enqueue(ctx, scan.Upload{Kind: ScanKind, UserID: userID, ItemID: awardID}); err != nil {
+		logging.Logger(c).Error("Could not queue image scan", "error", err)
+	}
+
+	apiversion.NoContent(c, gin.H{"message": "award image uploaded"})
+}
+
+// countAwards counts the user's awards against their document quota
+func countAwards(ctx context.Context, userID string) (int, error) {
+	items, err := repo.List(ctx, userID)
+	return len(items), err
+}
+
+// InitializeRoutes initializes the awards routes
+func InitializeRoutes(router *gin.RouterGroup, r Repository, u auth.Repository) {
+	repo = r
+	users = u
+
+	authOptional := auth.AuthMiddleware(u, false)
+	authRequired := auth.AuthMiddleware(u, true)
+
+	router.GET("/:userid", authOptional, GetAwards)
+	router.GET("/:userid/:awardid", authOptional, GetAward)
+	router.GET("/:userid/:awardid/image", GetAwardImage)
+
+	protected := router.Group("/")
+	protected.Use(authRequired)
+	protected.POST("/:userid", dryrun.Supported(), auth.RequireOwner(), quota.LimitDocuments("awards", countAwards), PostAward)
+	protected.PUT("/:userid/:awardid", dryrun.Supported(), auth.RequireOwner(), PutAward)
+	protected.DELETE("/:userid/:awardid", dryrun.Supported(), auth.RequireOwner(), DeleteAward)
+	protected.POST("/:userid/bulk-delete", dryrun.Supported(), auth.RequireOwner(), BulkDeleteAwards)
+	protected.PUT("/:userid/:awardid/image", auth.RequireOwner(), PutAwardImage)
+}
@@ -0,0 +1,27 @@
+package awards
+
+import "profile-api/visibility"
+
+// Award represents an award or honor a user received
+type Award struct {
+	UserID      string `bson:"user_id" json:"user_id"`
+	AwardID     string `bson:"award_id" json:"award_id"`
+	Title       string `bson:"title" json:"title" binding:"required,notblank,max=200"`
+	Issuer      string `bson:"issuer" json:"issuer" binding:"required,notblank,max=200"`
+	Date        string `bson:"date" json:"date" binding:"omitempty,date"`
+	Description string `bson:"description" json:"description" binding:"max=5000"`
+	// ImageQuarantined names the malware found in the last image uploaded, which was removed
+	ImageQuarantined string `bson:"image_quarantined,omitempty" json:"image_quarantined,omitempty" readonly:"true"`
+	// Visibility maps fields to who they are shown to: public, members or private
+	Visibility visibility.Rules `bson:"visibility" json:"visibility,omitempty" binding:"omitempty,visibility"`
+}
+
+// Owner returns the ID of the user the award belongs to
+func (a Award) Owner() string {
+	return a.UserID
+}
+
+// FieldVisibility returns the rules of the award's fields
+func (a Award) FieldVisibility() visibility.Rules {
+	return a.Visibility
+}
@@ -0,0 +1,25 @@
+package awards
+
+import "context"
+
+// Repository stores awards
+type Repository interface {
+	// List returns every award belonging to the user
+	List(ctx context.Context, userID string) ([]Award, error)
+	// ListByUsers returns every award belonging to any of the users
+	ListByUsers(ctx context.Context, userIDs []string) ([]Award, error)
+	// Get returns a single award, or store.ErrNotFound
+	Get(ctx context.Context, userID, awardID string) (Award, error)
+	// Create stores a new award
+	Create(ctx context.Context, item Award) error
+	// Save replaces an award, creating it if it does not exist
+	Save(ctx context.Context, item Award) error
+	// Delete removes an award, or returns store.ErrNotFound when the user has no such award
+	Delete(ctx context.Context, userID, awardID string) error
+	// SetImage stores the image of an award, creating the award if it does not exist
+	SetImage(ctx context.Context, userID, awardID string, image []byte) error
+	// GetImage returns the image of an award, or store.ErrNotFound
+	GetImage(ctx context.Context, userID, awardID string) ([]byte, error)
+	// QuarantineImage removes the image of an award and records the malware found in it
+	QuarantineImage(ctx context.Context, userID, awardID, threat string) error
+}
@@ -0,0 +1,78 @@
+package awards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"profile-api/audit"
+	"profile-api/store"
+)
+
+// AuditedRepository records every change to awards in the audit log
+type AuditedRepository struct {
+	Repository
+}
+
+// NewAuditedRepository wraps the repository to record its changes in the audit log
+func NewAuditedRepository(r Repository) *AuditedRepository {
+	return &AuditedRepository{Repository: r}
+}
+
+func (r *AuditedRepository) Create(ctx context.Context, item Award) error {
+	if err := r.Repository.Create(ctx, item); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionCreate, "award", item.UserID, item.AwardID, nil, item)
+	return nil
+}
+
+func (r *AuditedRepository) Save(ctx context.Context, item Award) error {
+	before, err := r.Repository.Get(ctx, item.UserID, item.AwardID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.Save(ctx, item); err != nil {
+		return err
+	}
+	audit.RecordSave(ctx, "award", item.UserID, item.AwardID, before, existed, item)
+	return nil
+}
+
+func (r *AuditedRepository) Delete(ctx context.Context, userID, awardID string) error {
+	before, err := r.Repository.Get(ctx, userID, awardID)
+	if errors.Is(err, store.ErrNotFound) {
+		return r.Repository.Delete(ctx, userID, awardID)
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.Delete(ctx, userID, awardID); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionDelete, "award", userID, awardID, before, nil)
+	return nil
+}
+
+func (r *AuditedRepository) SetImage(ctx context.Context, userID, awardID string, image []byte) error {
+	_, err := r.Repository.Get(ctx, userID, awardID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.SetImage(ctx, userID, awardID, image); err != nil {
+		return err
+	}
+	// Only the size of the image is recorded
+	audit.RecordSave(ctx, "award", userID, awardID, nil, existed, map[string]string{"image": fmt.Sprintf("%d bytes", len(image))})
+	return nil
+}
+
+func (r *AuditedRepository) QuarantineImage(ctx context.Context, userID, awardID, threat string) error {
+	if err := r.Repository.QuarantineImage(ctx, userID, awardID, threat); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionUpdate, "award", userID, awardID, nil, map[string]string{"image_quarantined": threat})
+	return nil
+}
@@ -0,0 +1,54 @@
+package awards
+
+import (
+	"context"
+
+	"profile-api/dryrun"
+)
+
+// DryRunRepository leaves awards unchanged in dry runs, reading them as usual. A deletion still
+// reports a missing document.
+type DryRunRepository struct {
+	Repository
+}
+
+// NewDryRunRepository wraps the repository to skip its writes in dry runs
+func NewDryRunRepository(r Repository) *DryRunRepository {
+	return &DryRunRepository{Repository: r}
+}
+
+func (r *DryRunRepository) Create(ctx context.Context, item Award) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.Create(ctx, item)
+}
+
+func (r *DryRunRepository) Save(ctx context.Context, item Award) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.Save(ctx, item)
+}
+
+func (r *DryRunRepository) Delete(ctx context.Context, userID, awardID string) error {
+	if dryrun.Active(ctx) {
+		_, err := r.Repository.Get(ctx, userID, awardID)
+		return err
+	}
+	return r.Repository.Delete(ctx, userID, awardID)
+}
+
+func (r *DryRunRepository) SetImage(ctx context.Context, userID, awardID string, image []byte) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.SetImage(ctx, userID, awardID, image)
+}
+
+func (r *DryRunRepository) QuarantineImage(ctx context.Context, userID, awardID, threat string) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.QuarantineImage(ctx, userID, awardID, threat)
+}
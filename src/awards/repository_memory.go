@@ -0,0 +1,134 @@
+package awards
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps awards in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu     sync.RWMutex
+	items  []Award
+	images map[string][]byte
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{images: map[string][]byte{}}
+}
+
+// index returns the position of an award in items, or -1. The caller must hold the lock.
+func (r *MemoryRepository) index(userID, awardID string) int {
+	for i, item := range r.items {
+		if item.UserID == userID && item.AwardID == awardID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string) ([]Award, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Award
+	for _, item := range r.items {
+		if item.UserID == userID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Award, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Award
+	for _, item := range r.items {
+		if slices.Contains(userIDs, item.UserID) {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, userID, awardID string) (Award, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.index(userID, awardID)
+	if i < 0 {
+		return Award{}, store.ErrNotFound
+	}
+	return r.items[i], nil
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, item Award) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.index(item.UserID, item.AwardID) >= 0 {
+		return store.ErrConflict
+	}
+	item.ImageQuarantined = ""
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, item Award) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(item.UserID, item.AwardID); i >= 0 {
+		item.ImageQuarantined = r.items[i].ImageQuarantined
+		r.items[i] = item
+		return nil
+	}
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, userID, awardID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, awardID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.items = append(r.items[:i], r.items[i+1:]...)
+	delete(r.images, userID+"/"+awardID)
+	return nil
+}
+
+func (r *MemoryRepository) SetImage(ctx context.Context, userID, awardID string, image []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(userID, awardID); i >= 0 {
+		r.items[i].ImageQuarantined = ""
+	} else {
+		r.items = append(r.items, Award{UserID: userID, AwardID: awardID})
+	}
+	r.images[userID+"/"+awardID] = image
+	return nil
+}
+
+func (r *MemoryRepository) GetImage(ctx context.Context, userID, awardID string) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	image, ok := r.images[userID+"/"+awardID]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return image, nil
+}
+
+func (r *MemoryRepository) QuarantineImage(ctx context.Context, userID, awardID, threat string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, awardID)
+	if i < 0 {
+		return nil
+	}
+	r.items[i].ImageQuarantined = threat
+	delete(r.images, userID+"/"+awardID)
+	return nil
+}
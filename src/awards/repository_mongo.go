@@ -0,0 +1,99 @@
+package awards
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores awards in the awards collection
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{collection: db.Collection("awards")}
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string) ([]Award, error) {
+	return r.find(ctx, bson.M{"user_id": userID})
+}
+
+func (r *MongoRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Award, error) {
+	return r.find(ctx, bson.M{"user_id": bson.M{"$in": userIDs}})
+}
+
+func (r *MongoRepository) find(ctx context.Context, filter bson.M) ([]Award, error) {
+	// The images are only read by GetImage
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"image": 0}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []Award
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *MongoRepository) Get(ctx context.Context, userID, awardID string) (Award, error) {
+	var item Award
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "award_id": awardID}, options.FindOne().SetProjection(bson.M{"image": 0})).Decode(&item)
+	return item, store.MongoErr(err)
+}
+
+func (r *MongoRepository) Create(ctx context.Context, item Award) error {
+	// The quarantine is only set by QuarantineImage
+	item.ImageQuarantined = ""
+	_, err := r.collection.InsertOne(ctx, item)
+	return err
+}
+
+func (r *MongoRepository) Save(ctx context.Context, item Award) error {
+	// The omitted quarantine is left as it is
+	item.ImageQuarantined = ""
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": item.UserID, "award_id": item.AwardID}, bson.M{"$set": item}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, userID, awardID string) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID, "award_id": awardID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) SetImage(ctx context.Context, userID, awardID string, image []byte) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": userID, "award_id": awardID}, bson.M{"$set": bson.M{"image": image}, "$unset": bson.M{"image_quarantined": ""}}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) GetImage(ctx context.Context, userID, awardID string) ([]byte, error) {
+	var doc struct {
+		Image []byte `bson:"image"`
+	}
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "award_id": awardID}, options.FindOne().SetProjection(bson.M{"image": 1})).Decode(&doc)
+	if err != nil {
+		return nil, store.MongoErr(err)
+	}
+	if doc.Image == nil {
+		return nil, store.ErrNotFound
+	}
+	return doc.Image, nil
+}
+
+func (r *MongoRepository) QuarantineImage(ctx context.Context, userID, awardID, threat string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": userID, "award_id": awardID}, bson.M{"$set": bson.M{"image_quarantined": threat}, "$unset": bson.M{"image": ""}})
+	return err
+}
@@ -0,0 +1,103 @@
+package awards
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const awardsColumns = "user_id, award_id, title, issuer, award_date, description, visibility"
+
+// PostgresRepository stores awards in the awards table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string) ([]Award, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+awardsColumns+", image_quarantined FROM awards WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanAward)
+}
+
+func (r *PostgresRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Award, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+awardsColumns+", image_quarantined FROM awards WHERE user_id = ANY($1) ORDER BY created_at", userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanAward)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, userID, awardID string) (Award, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+awardsColumns+", image_quarantined FROM awards WHERE user_id = $1 AND award_id = $2", userID, awardID)
+	if err != nil {
+		return Award{}, err
+	}
+	item, err := pgx.CollectExactlyOneRow(rows, scanAward)
+	return item, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, item Award) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO awards ("+awardsColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		item.UserID, item.AwardID, item.Title, item.Issuer, item.Date, item.Description, item.Visibility)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, item Award) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO awards ("+awardsColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7) "+
+		"ON CONFLICT (user_id, award_id) DO UPDATE SET title = EXCLUDED.title, issuer = EXCLUDED.issuer, award_date = EXCLUDED.award_date, description = EXCLUDED.description, visibility = EXCLUDED.visibility",
+		item.UserID, item.AwardID, item.Title, item.Issuer, item.Date, item.Description, item.Visibility)
+	return err
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, userID, awardID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM awards WHERE user_id = $1 AND award_id = $2", userID, awardID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) SetImage(ctx context.Context, userID, awardID string, image []byte) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO awards (user_id, award_id, image) VALUES ($1, $2, $3) "+
+		"ON CONFLICT (user_id, award_id) DO UPDATE SET image = EXCLUDED.image, image_quarantined = ''",
+		userID, awardID, image)
+	return err
+}
+
+func (r *PostgresRepository) GetImage(ctx context.Context, userID, awardID string) ([]byte, error) {
+	var image []byte
+	err := r.pool.QueryRow(ctx, "SELECT image FROM awards WHERE user_id = $1 AND award_id = $2", userID, awardID).Scan(&image)
+	if err != nil {
+		return nil, store.PostgresErr(err)
+	}
+	if image == nil {
+		return nil, store.ErrNotFound
+	}
+	return image, nil
+}
+
+func (r *PostgresRepository) QuarantineImage(ctx context.Context, userID, awardID, threat string) error {
+	_, err := r.pool.Exec(ctx, "UPDATE awards SET image = NULL, image_quarantined = $3 WHERE user_id = $1 AND award_id = $2",
+		userID, awardID, threat)
+	return err
+}
+
+// scanAward reads a row selected with awardsColumns and image_quarantined
+func scanAward(row pgx.CollectableRow) (Award, error) {
+	var item Award
+	err := row.Scan(&item.UserID, &item.AwardID, &item.Title, &item.Issuer, &item.Date, &item.Description, &item.Visibility, &item.ImageQuarantined)
+	return item, err
+}
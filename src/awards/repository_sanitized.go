@@ -0,0 +1,30 @@
+package awards
+
+import (
+	"context"
+
+	"profile-api/sanitize"
+)
+
+// SanitizedRepository cleans the user-supplied text of awards before storing them
+type SanitizedRepository struct {
+	Repository
+}
+
+// NewSanitizedRepository wraps the repository to clean awards with the configured sanitize policies
+func NewSanitizedRepository(r Repository) *SanitizedRepository {
+	return &SanitizedRepository{Repository: r}
+}
+
+func (r *SanitizedRepository) Create(ctx context.Context, item Award) error {
+	return r.Repository.Create(ctx, sanitized(item))
+}
+
+func (r *SanitizedRepository) Save(ctx context.Context, item Award) error {
+	return r.Repository.Save(ctx, sanitized(item))
+}
+
+func sanitized(item Award) Award {
+	item.Description = sanitize.Field(sanitize.AwardDescription, item.Description)
+	return item
+}
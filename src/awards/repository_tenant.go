@@ -0,0 +1,53 @@
+package awards
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID string) ([]Award, error) {
+	return r.repos.For(ctx).List(ctx, userID)
+}
+
+func (r *TenantRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Award, error) {
+	return r.repos.For(ctx).ListByUsers(ctx, userIDs)
+}
+
+func (r *TenantRepository) Get(ctx context.Context, userID, awardID string) (Award, error) {
+	return r.repos.For(ctx).Get(ctx, userID, awardID)
+}
+
+func (r *TenantRepository) Create(ctx context.Context, item Award) error {
+	return r.repos.For(ctx).Create(ctx, item)
+}
+
+func (r *TenantRepository) Save(ctx context.Context, item Award) error {
+	return r.repos.For(ctx).Save(ctx, item)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, userID, awardID string) error {
+	return r.repos.For(ctx).Delete(ctx, userID, awardID)
+}
+
+func (r *TenantRepository) SetImage(ctx context.Context, userID, awardID string, image []byte) error {
+	return r.repos.For(ctx).SetImage(ctx, userID, awardID, image)
+}
+
+func (r *TenantRepository) GetImage(ctx context.Context, userID, awardID string) ([]byte, error) {
+	return r.repos.For(ctx).GetImage(ctx, userID, awardID)
+}
+
+func (r *TenantRepository) QuarantineImage(ctx context.Context, userID, awardID, threat string) error {
+	return r.repos.For(ctx).QuarantineImage(ctx, userID, awardID, threat)
+}
@@ -0,0 +1,30 @@
+package awards
+
+import (
+	"context"
+
+	"profile-api/scan"
+)
+
+// ScanKind is the kind of upload award images are scanned as
+const ScanKind = "award-image"
+
+// scanTarget loads award images for scanning, and removes infected ones
+type scanTarget struct {
+	repo Repository
+}
+
+// NewScanTarget creates the target scanning the images of awards
+func NewScanTarget(r Repository) scan.Target {
+	return &scanTarget{repo: r}
+}
+
+func (t *scanTarget) Load(ctx context.Context, upload scan.Upload) ([]byte, error) {
+	return t.repo.GetImage(ctx, upload.UserID, upload.ItemID)
+}
+
+// Quarantine removes the image, as award images are kept in the database rather than a store to move them
+// aside in
+func (t *scanTarget) Quarantine(ctx context.Context, upload scan.Upload, threat string) error {
+	return t.repo.QuarantineImage(ctx, upload.UserID, upload.ItemID, threat)
+}
@@ -0,0 +1,219 @@
+// Package batch runs several API requests sent in one, so clients such as the profile editor can save many
+// resources in a single round trip.
+//
+// Each request in a batch is run in order through the full API, with the caller's credentials, tenant and
+// client address, and its response is returned in the batch's results whether it succeeded or not. The
+// batch does not stop at a failed request, nor roll back those that succeeded.
+package batch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"profile-api/apierror"
+	"profile-api/clientip"
+	"profile-api/config"
+	"profile-api/idempotency"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request is a request to run as part of a batch
+type Request struct {
+	Method string `json:"method" binding:"required,oneof=GET POST PUT PATCH DELETE"`
+	// Path is the path of the API route with any query, such as /api/v1/profile/{userid}
+	Path string `json:"path" binding:"required,startswith=/api/,max=2048"`
+	// Headers are sent along with the caller's, such as If-Match or Idempotency-Key. They cannot replace the
+	// caller's credentials.
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty" swaggertype:"object"`
+}
+
+// BatchRequest is the body of a batch
+type BatchRequest struct {
+	Requests []Request `json:"requests" binding:"required,min=1,dive"`
+}
+
+// Result is the response to one request of a batch
+type Result struct {
+	Status int `json:"status"`
+	// Headers holds the response headers clients act on, such as ETag and Location
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the JSON response, or a string holding any other response
+	Body json.RawMessage `json:"body,omitempty" swaggertype:"object"`
+}
+
+// BatchResponse holds the results of a batch's requests, in the order they were sent
+type BatchResponse struct {
+	Results []Result `json:"results"`
+}
+
+// callerHeaders are request headers only the caller sets, for the whole batch, along with the headers the
+// client address is read from
+var callerHeaders = []string{"Authorization", "Cookie", "Host", "X-Request-Id"}
+
+// batchHeaders are headers of the batch that do not apply to the requests in it
+var batchHeaders = []string{"Content-Length", "Content-Type", "Content-Encoding", "If-Match", "If-None-Match",
+	"If-Modified-Since", idempotency.Header}
+
+// resultHeaders are the response headers returned with each result
+var resultHeaders = []string{"ETag", "Last-Modified", "Location", "Retry-After", "Deprecation"}
+
+var handler http.Handler
+var settings = config.BatchConfig{MaxRequests: 20}
+
+// excluded holds the path prefixes of routes that cannot be batched
+var excluded []string
+
+// ServeBatch runs the requests of a batch
+//
+//	@Summary		Run several requests at once
+//	@Description	Runs each request of the batch in order, as if the caller had sent it with their credentials, and returns the response to each. A failed request does not stop the batch or undo the requests before it. Batches cannot be nested, nor hold streaming routes such as the event stream.
+//	@Tags			batch
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		BatchRequest		true	"Requests to run"
+//	@Success		200		{object}	BatchResponse		"Responses to the requests"
+//	@Failure		400		{object}	apierror.Response	"Invalid batch"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		415		{object}	apierror.Response	"The batch is not sent as application/json"
+//	@Router			/batch [post]
+func ServeBatch(c *gin.Context) {
+	// A JSON body can't be sent across origins without a preflight, so a page on another site can't have a
+	// signed in visitor's browser run a batch with their cookie
+	if c.ContentType() != gin.MIMEJSON {
+		apierror.Abort(c, apierror.UnsupportedMediaType("A batch must be sent as application/json"))
+		return
+	}
+	var batch BatchRequest
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	if len(batch.Requests) > settings.MaxRequests {
+		apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("A batch can hold at most %d requests", settings.MaxRequests)))
+		return
+	}
+
+	requests := make([]*http.Request, len(batch.Requests))
+	for i, item := range batch.Requests {
+		req, err := newRequest(c.Request, item)
+		if err != nil {
+			apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("Request %d: %s", i, err)))
+			return
+		}
+		requests[i] = req
+	}
+
+	results := make([]Result, len(requests))
+	for i, req := range requests {
+		w := newRecorder()
+		handler.ServeHTTP(w, req)
+		results[i] = w.result()
+	}
+	c.JSON(http.StatusOK, BatchResponse{Results: results})
+}
+
+// newRequest builds the request to run for an item of the caller's batch
+func newRequest(caller *http.Request, item Request) (*http.Request, error) {
+	target, err := url.Parse(item.Path)
+	if err != nil || target.Scheme != "" || target.Host != "" {
+		return nil, fmt.Errorf("invalid path %s", item.Path)
+	}
+	cleaned := path.Clean(target.Path)
+	for _, prefix := range excluded {
+		if cleaned == prefix || strings.HasPrefix(cleaned, prefix+"/") {
+			return nil, fmt.Errorf("%s cannot be batched", target.Path)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(caller.Context(), item.Method, target.RequestURI(), bytes.NewReader(item.Body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = caller.Header.Clone()
+	for _, name := range batchHeaders {
+		req.Header.Del(name)
+	}
+	for name, value := range item.Headers {
+		name = http.CanonicalHeaderKey(name)
+		if slices.Contains(callerHeaders, name) || slices.Contains(clientip.Headers(), name) {
+			return nil, fmt.Errorf("header %s cannot be set", name)
+		}
+		req.Header.Set(name, value)
+	}
+	if len(item.Body) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Host = caller.Host
+	req.RemoteAddr = caller.RemoteAddr
+	req.TLS = caller.TLS
+	return req, nil
+}
+
+// recorder keeps the response to a request of a batch
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newRecorder() *recorder {
+	return &recorder{header: http.Header{}}
+}
+
+func (w *recorder) Header() http.Header {
+	return w.header
+}
+
+func (w *recorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *recorder) Write(data []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(data)
+}
+
+// result returns the recorded response as a batch result
+func (w *recorder) result() Result {
+	result := Result{Status: w.status}
+	if result.Status == 0 {
+		result.Status = http.StatusOK
+	}
+	for _, name := range resultHeaders {
+		if value := w.header.Get(name); value != "" {
+			if result.Headers == nil {
+				result.Headers = map[string]string{}
+			}
+			result.Headers[name] = value
+		}
+	}
+	switch body := w.body.Bytes(); {
+	case len(body) == 0:
+	case json.Valid(body):
+		result.Body = body
+	default:
+		result.Body, _ = json.Marshal(string(body))
+	}
+	return result
+}
+
+// InitializeRoutes registers the batch endpoint, running requests through the handler serving the API.
+// The router must only admit authenticated users. Routes under the excluded paths, such as streams,
+// cannot be batched.
+func InitializeRoutes(router gin.IRoutes, h http.Handler, cfg config.BatchConfig, excludedPaths ...string) {
+	handler = h
+	settings = cfg
+	excluded = excludedPaths
+	router.POST("/batch", ServeBatch)
+}
@@ -0,0 +1,444 @@
+// Package billing sells premium plans through Stripe. Each user is on a plan bounding what they can do:
+// the default plan until they subscribe to another at a Stripe checkout. Stripe reports changes to
+// subscriptions to the webhook route, and the user's plan follows the subscription's state, fetched again
+// from Stripe as events can arrive out of order.
+//
+// Plans only apply while billing is enabled. Otherwise every user can use every feature without limits,
+// as on a self-hosted site.
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/jobs"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Features a plan can include
+const (
+	AI           = "ai"
+	CustomDomain = "custom-domain"
+)
+
+// Types of the background jobs keeping accounts in step with Stripe
+const (
+	// SyncJob moves the user to the plan their subscription currently pays for
+	SyncJob = "billing.sync"
+	// CloseJob deletes the Stripe customer of a deleted user, cancelling their subscription
+	CloseJob = "billing.close"
+)
+
+// entitledStatuses are the subscription statuses keeping the user on the plan, including past_due while
+// Stripe retries the payment
+var entitledStatuses = []string{"active", "trialing", "past_due"}
+
+// subscriptionEvents are the webhook events about a change to a subscription
+var subscriptionEvents = []string{
+	"customer.subscription.created",
+	"customer.subscription.updated",
+	"customer.subscription.deleted",
+	"customer.subscription.paused",
+	"customer.subscription.resumed",
+}
+
+var repo Repository
+var users auth.Repository
+var settings config.BillingConfig
+var stripe *stripeClient
+
+type syncPayload struct {
+	SubscriptionID string `json:"subscriptionID"`
+}
+
+type closePayload struct {
+	UserID string `json:"userID"`
+}
+
+// Configure sets where accounts are stored and the plans and Stripe account of enabled billing, registers
+// the jobs following subscriptions and starts closing the accounts of deleted users. It must be called
+// before the job workers start.
+func Configure(r Repository, u auth.Repository, cfg config.BillingConfig) {
+	repo = r
+	users = u
+	settings = cfg
+	stripe = newStripeClient(cfg.Stripe)
+
+	jobs.Register(SyncJob, func(ctx context.Context, job jobs.Job) error {
+		var payload syncPayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return err
+		}
+		return syncSubscription(ctx, payload.SubscriptionID)
+	})
+	jobs.Register(CloseJob, func(ctx context.Context, job jobs.Job) error {
+		var payload closePayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return err
+		}
+		return closeAccount(ctx, payload.UserID)
+	})
+	audit.Subscribe(closeDeleted)
+}
+
+// Enabled reports whether billing is enabled, so plans apply
+func Enabled() bool {
+	return settings.Enabled
+}
+
+// PlanOf returns the plan the user is on. While billing is disabled every user is on an unlimited plan.
+func PlanOf(user auth.User) Plan {
+	if !settings.Enabled {
+		return Plan{AI: true, CustomDomain: true}
+	}
+	name := user.Plan
+	if _, ok := settings.Plans[name]; !ok {
+		name = settings.DefaultPlan
+	}
+	return planView(name, settings.Plans[name])
+}
+
+func planView(name string, cfg config.PlanConfig) Plan {
+	return Plan{
+		Name:              name,
+		MaxJournalEntries: cfg.MaxJournalEntries,
+		StorageBytes:      cfg.StorageBytes,
+		AI:                cfg.AI,
+		CustomDomain:      cfg.CustomDomain,
+		Purchasable:       cfg.PriceID != "",
+	}
+}
+
+// Allow returns a 402 API error unless the user's plan includes the feature
+func Allow(user auth.User, feature string) error {
+	plan := PlanOf(user)
+	switch {
+	case feature == AI && !plan.AI:
+		return apierror.PaymentRequired("Your plan does not include AI features").WithDetails(map[string]any{"plan": plan.Name, "feature": feature})
+	case feature == CustomDomain && !plan.CustomDomain:
+		return apierror.PaymentRequired("Your plan does not include a custom domain").WithDetails(map[string]any{"plan": plan.Name, "feature": feature})
+	}
+	return nil
+}
+
+// Require responds 402 to users whose plan does not include the feature. Authentication must run first.
+func Require(feature string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, _ := c.Get("user")
+		u, _ := user.(auth.User)
+		if err := Allow(u, feature); err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// planForPrices returns the plan one of the prices pays for, or empty for the default plan
+func planForPrices(prices []string) string {
+	for name, plan := range settings.Plans {
+		if plan.PriceID != "" && slices.Contains(prices, plan.PriceID) {
+			return name
+		}
+	}
+	return ""
+}
+
+// syncSubscription moves the subscription's user to the plan it currently pays for, or back to the default
+// plan once it has ended
+func syncSubscription(ctx context.Context, subscriptionID string) error {
+	sub, err := stripe.subscription(ctx, subscriptionID)
+	if errors.Is(err, errStripeNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	account, err := repo.FindByCustomer(ctx, sub.Customer)
+	if errors.Is(err, store.ErrNotFound) {
+		slog.WarnContext(ctx, "Subscription of an unknown Stripe customer", "subscription_id", sub.ID, "customer_id", sub.Customer)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	entitled := slices.Contains(entitledStatuses, sub.Status)
+	// A replaced subscription ending must not take the user off the plan of the one replacing it
+	if !entitled && account.SubscriptionID != "" && account.SubscriptionID != sub.ID {
+		return nil
+	}
+	plan := ""
+	if entitled {
+		plan = planForPrices(sub.priceIDs())
+		if plan == "" {
+			slog.WarnContext(ctx, "Subscription to a price of no plan", "subscription_id", sub.ID, "prices", sub.priceIDs())
+		}
+	}
+
+	account.SubscriptionID = sub.ID
+	account.Status = sub.Status
+	account.CurrentPeriodEnd = nil
+	if sub.CurrentPeriodEnd > 0 {
+		end := time.Unix(sub.CurrentPeriodEnd, 0).UTC()
+		account.CurrentPeriodEnd = &end
+	}
+	account.CancelAtPeriodEnd = sub.CancelAtPeriodEnd
+	account.UpdatedAt = time.Now()
+	if err := repo.Save(ctx, account); err != nil {
+		return err
+	}
+
+	user, err := users.FindByID(ctx, account.UserID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Plan == plan {
+		return nil
+	}
+	if err := users.SetPlan(ctx, user.ID, plan); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "User plan changed", "user_id", user.ID, "from", user.Plan, "to", plan, "subscription_status", sub.Status)
+	return nil
+}
+
+// closeDeleted closes the billing account of a deleted user
+func closeDeleted(ctx context.Context, entry audit.Entry) {
+	if entry.Resource != "user" || entry.Action != audit.ActionDelete {
+		return
+	}
+	if err := jobs.Enqueue(ctx, CloseJob, closePayload{UserID: entry.ResourceID}); err != nil {
+		slog.ErrorContext(ctx, "Could not queue billing account closure", "user_id", entry.ResourceID, "error", err)
+	}
+}
+
+// closeAccount deletes the user's Stripe customer, so they are no longer charged, and then their account
+func closeAccount(ctx context.Context, userID string) error {
+	account, err := repo.Get(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := stripe.deleteCustomer(ctx, account.CustomerID); err != nil {
+		return err
+	}
+	return repo.Delete(ctx, userID)
+}
+
+// ListPlans lists the plans users can be on
+//
+//	@Summary		List plans
+//	@Description	Lists the plans users can be on with what each includes, by name. Limits of 0 are unlimited. Purchasable plans can be subscribed to at checkout. Only available when billing is enabled.
+//	@Tags			billing
+//	@Produce		json
+//	@Success		200	{array}	Plan
+//	@Router			/billing/plans [get]
+func ListPlans(c *gin.Context) {
+	names := make([]string, 0, len(settings.Plans))
+	for name := range settings.Plans {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	plans := make([]Plan, 0, len(names))
+	for _, name := range names {
+		plans = append(plans, planView(name, settings.Plans[name]))
+	}
+	c.JSON(http.StatusOK, plans)
+}
+
+// GetSubscription returns the user's plan and subscription
+//
+//	@Summary		Get your plan
+//	@Description	Returns the plan the user is on and the state of the subscription paying for it, if any. Only available when billing is enabled.
+//	@Tags			billing
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	Subscription
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve subscription"
+//	@Router			/billing/subscription [get]
+func GetSubscription(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	account, err := repo.Get(ctx, user.ID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve subscription"))
+		return
+	}
+	c.JSON(http.StatusOK, Subscription{
+		Plan:              PlanOf(user),
+		Status:            account.Status,
+		CurrentPeriodEnd:  account.CurrentPeriodEnd,
+		CancelAtPeriodEnd: account.CancelAtPeriodEnd,
+	})
+}
+
+// Checkout starts subscribing the user to a plan
+//
+//	@Summary		Subscribe to a plan
+//	@Description	Starts a Stripe checkout subscribing the user to the plan, returning the checkout page to send them to. The user moves to the plan once Stripe reports the subscription is paid. Users with a subscription change or cancel it in the customer portal instead. Only available when billing is enabled.
+//	@Tags			billing
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		CheckoutRequest		true	"Plan to subscribe to"
+//	@Success		200		{object}	Redirect			"Checkout page"
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		404		{object}	apierror.Response	"Unknown plan, or one that cannot be bought"
+//	@Failure		409		{object}	apierror.Response	"Already subscribed"
+//	@Failure		500		{object}	apierror.Response	"Could not start checkout"
+//	@Router			/billing/checkout [post]
+func Checkout(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+	var req CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	plan, ok := settings.Plans[req.Plan]
+	if !ok || plan.PriceID == "" {
+		apierror.Abort(c, apierror.NotFound("Unknown plan"))
+		return
+	}
+
+	account, err := customerAccount(c, user)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not start checkout"))
+		return
+	}
+	if account.SubscriptionID != "" && slices.Contains(entitledStatuses, account.Status) {
+		apierror.Abort(c, apierror.Conflict("Already subscribed, change the subscription in the customer portal"))
+		return
+	}
+	url, err := stripe.checkoutSession(c.Request.Context(), account.CustomerID, user.ID, plan.PriceID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not start checkout"))
+		return
+	}
+	c.JSON(http.StatusOK, Redirect{URL: url})
+}
+
+// customerAccount returns the user's account, creating their Stripe customer on their first checkout
+func customerAccount(c *gin.Context, user auth.User) (Account, error) {
+	ctx, cancel := utils.DBContext(c)
+	account, err := repo.Get(ctx, user.ID)
+	cancel()
+	if !errors.Is(err, store.ErrNotFound) {
+		return account, err
+	}
+
+	customerID, err := stripe.createCustomer(c.Request.Context(), user.ID, tenant.ID(c.Request.Context()), user.Name, user.Email)
+	if err != nil {
+		return Account{}, err
+	}
+	account = Account{UserID: user.ID, CustomerID: customerID, UpdatedAt: time.Now()}
+	ctx, cancel = utils.DBContext(c)
+	defer cancel()
+	return account, repo.Save(ctx, account)
+}
+
+// OpenPortal opens the Stripe customer portal for the user
+//
+//	@Summary		Manage your subscription
+//	@Description	Opens the Stripe customer portal, where the user changes their payment details or plan, or cancels their subscription, returning the page to send them to. Only available when billing is enabled.
+//	@Tags			billing
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	Redirect			"Customer portal page"
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		404	{object}	apierror.Response	"The user never subscribed"
+//	@Failure		500	{object}	apierror.Response	"Could not open the customer portal"
+//	@Router			/billing/portal [post]
+func OpenPortal(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	account, err := repo.Get(ctx, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "You have not subscribed"))
+		return
+	}
+	url, err := stripe.portalSession(c.Request.Context(), account.CustomerID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not open the customer portal"))
+		return
+	}
+	c.JSON(http.StatusOK, Redirect{URL: url})
+}
+
+// HandleWebhook receives events from Stripe
+//
+//	@Summary		Receive Stripe events
+//	@Description	Receives the events Stripe sends about subscriptions, signed with the webhook secret. Each site of a multi-tenant deployment has the endpoint registered at its own domain. Changes are applied in the background.
+//	@Tags			billing
+//	@Accept			json
+//	@Produce		json
+//	@Param			Stripe-Signature	header		string				true	"Signature of the event"
+//	@Param			request				body		object				true	"Stripe event"
+//	@Success		200					{object}	map[string]bool		"Event received"
+//	@Failure		400					{object}	apierror.Response	"Invalid signature or event"
+//	@Failure		500					{object}	apierror.Response	"Could not queue the event"
+//	@Router			/billing/webhook [post]
+func HandleWebhook(c *gin.Context) {
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Could not read event"))
+		return
+	}
+	if err := verifySignature(c.GetHeader("Stripe-Signature"), payload, settings.Stripe.WebhookSecret, time.Now()); err != nil {
+		apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("Invalid signature: %v", err)))
+		return
+	}
+	var event stripeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid event"))
+		return
+	}
+
+	if slices.Contains(subscriptionEvents, event.Type) && event.Data.Object.ID != "" {
+		if err := jobs.Enqueue(c.Request.Context(), SyncJob, syncPayload{SubscriptionID: event.Data.Object.ID}); err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not queue the event"))
+			return
+		}
+		slog.InfoContext(c.Request.Context(), "Stripe event received", "event_id", event.ID, "type", event.Type, "subscription_id", event.Data.Object.ID)
+	}
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
+
+// InitializeRoutes registers the billing endpoints
+func InitializeRoutes(router *gin.RouterGroup, u auth.Repository) {
+	router.GET("/plans", ListPlans)
+	router.POST("/webhook", HandleWebhook)
+
+	protected := router.Group("/")
+	protected.Use(auth.AuthMiddleware(u, true))
+	protected.GET("/subscription", GetSubscription)
+	protected.POST("/checkout", Checkout)
+	protected.POST("/portal", OpenPortal)
+}
@@ -0,0 +1,46 @@
+package billing
+
+import "time"
+
+// Account links a user to their Stripe customer and the subscription that set their plan
+type Account struct {
+	UserID     string `bson:"_id"`
+	CustomerID string `bson:"customer_id"`
+	// SubscriptionID is the user's latest subscription, empty until they first subscribe
+	SubscriptionID string `bson:"subscription_id"`
+	// Status is the Stripe status of the subscription, such as active, past_due or canceled
+	Status            string     `bson:"status"`
+	CurrentPeriodEnd  *time.Time `bson:"current_period_end"`
+	CancelAtPeriodEnd bool       `bson:"cancel_at_period_end"`
+	UpdatedAt         time.Time  `bson:"updated_at"`
+}
+
+// Plan is a plan users can be on, as shown to them. Limits of 0 are unlimited.
+type Plan struct {
+	Name              string `json:"name"`
+	MaxJournalEntries int    `json:"maxJournalEntries"`
+	StorageBytes      int64  `json:"storageBytes"`
+	AI                bool   `json:"ai"`
+	CustomDomain      bool   `json:"customDomain"`
+	// Purchasable plans can be subscribed to at checkout
+	Purchasable bool `json:"purchasable"`
+}
+
+// Subscription is the user's plan and the state of the subscription paying for it
+type Subscription struct {
+	Plan Plan `json:"plan"`
+	// Status is the Stripe status of the subscription, empty for users who never subscribed
+	Status            string     `json:"status,omitempty"`
+	CurrentPeriodEnd  *time.Time `json:"currentPeriodEnd,omitempty"`
+	CancelAtPeriodEnd bool       `json:"cancelAtPeriodEnd"`
+}
+
+// CheckoutRequest picks the plan to subscribe to
+type CheckoutRequest struct {
+	Plan string `json:"plan" binding:"required"`
+}
+
+// Redirect is a Stripe page to send the user to
+type Redirect struct {
+	URL string `json:"url"`
+}
@@ -0,0 +1,15 @@
+package billing
+
+import "context"
+
+// Repository stores the users' billing accounts
+type Repository interface {
+	// Get returns the user's account, or store.ErrNotFound
+	Get(ctx context.Context, userID string) (Account, error)
+	// FindByCustomer returns the account of the Stripe customer, or store.ErrNotFound
+	FindByCustomer(ctx context.Context, customerID string) (Account, error)
+	// Save creates or replaces the user's account
+	Save(ctx context.Context, account Account) error
+	// Delete removes the user's account
+	Delete(ctx context.Context, userID string) error
+}
@@ -0,0 +1,54 @@
+package billing
+
+import (
+	"context"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps accounts in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu       sync.Mutex
+	accounts map[string]Account
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{accounts: map[string]Account{}}
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, userID string) (Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	account, ok := r.accounts[userID]
+	if !ok {
+		return Account{}, store.ErrNotFound
+	}
+	return account, nil
+}
+
+func (r *MemoryRepository) FindByCustomer(ctx context.Context, customerID string) (Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, account := range r.accounts {
+		if account.CustomerID == customerID {
+			return account, nil
+		}
+	}
+	return Account{}, store.ErrNotFound
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, account Account) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.accounts[account.UserID] = account
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.accounts, userID)
+	return nil
+}
@@ -0,0 +1,43 @@
+package billing
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores accounts in the billing_accounts collection
+type MongoRepository struct {
+	accounts *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{accounts: db.Collection("billing_accounts")}
+}
+
+func (r *MongoRepository) Get(ctx context.Context, userID string) (Account, error) {
+	var account Account
+	err := r.accounts.FindOne(ctx, bson.M{"_id": userID}).Decode(&account)
+	return account, store.MongoErr(err)
+}
+
+func (r *MongoRepository) FindByCustomer(ctx context.Context, customerID string) (Account, error) {
+	var account Account
+	err := r.accounts.FindOne(ctx, bson.M{"customer_id": customerID}).Decode(&account)
+	return account, store.MongoErr(err)
+}
+
+func (r *MongoRepository) Save(ctx context.Context, account Account) error {
+	_, err := r.accounts.ReplaceOne(ctx, bson.M{"_id": account.UserID}, account, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, userID string) error {
+	_, err := r.accounts.DeleteOne(ctx, bson.M{"_id": userID})
+	return err
+}
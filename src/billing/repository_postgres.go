@@ -0,0 +1,53 @@
+package billing
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository stores accounts in the billing_accounts table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+const accountColumns = "user_id, customer_id, subscription_id, status, current_period_end, cancel_at_period_end, updated_at"
+
+func (r *PostgresRepository) Get(ctx context.Context, userID string) (Account, error) {
+	return r.findOne(ctx, "SELECT "+accountColumns+" FROM billing_accounts WHERE user_id = $1", userID)
+}
+
+func (r *PostgresRepository) FindByCustomer(ctx context.Context, customerID string) (Account, error) {
+	return r.findOne(ctx, "SELECT "+accountColumns+" FROM billing_accounts WHERE customer_id = $1", customerID)
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, account Account) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO billing_accounts (`+accountColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE SET customer_id = excluded.customer_id, subscription_id = excluded.subscription_id,
+			status = excluded.status, current_period_end = excluded.current_period_end,
+			cancel_at_period_end = excluded.cancel_at_period_end, updated_at = excluded.updated_at`,
+		account.UserID, account.CustomerID, account.SubscriptionID, account.Status, account.CurrentPeriodEnd,
+		account.CancelAtPeriodEnd, account.UpdatedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, userID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM billing_accounts WHERE user_id = $1", userID)
+	return err
+}
+
+// findOne returns the single account selected by the query
+func (r *PostgresRepository) findOne(ctx context.Context, query string, arg string) (Account, error) {
+	var a Account
+	err := r.pool.QueryRow(ctx, query, arg).
+		Scan(&a.UserID, &a.CustomerID, &a.SubscriptionID, &a.Status, &a.CurrentPeriodEnd, &a.CancelAtPeriodEnd, &a.UpdatedAt)
+	return a, store.PostgresErr(err)
+}
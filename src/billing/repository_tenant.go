@@ -0,0 +1,33 @@
+package billing
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Get(ctx context.Context, userID string) (Account, error) {
+	return r.repos.For(ctx).Get(ctx, userID)
+}
+
+func (r *TenantRepository) FindByCustomer(ctx context.Context, customerID string) (Account, error) {
+	return r.repos.For(ctx).FindByCustomer(ctx, customerID)
+}
+
+func (r *TenantRepository) Save(ctx context.Context, account Account) error {
+	return r.repos.For(ctx).Save(ctx, account)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, userID string) error {
+	return r.repos.For(ctx).Delete(ctx, userID)
+}
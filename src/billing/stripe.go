@@ -0,0 +1,209 @@
+package billing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"profile-api/config"
+)
+
+// signatureTolerance is how old a webhook event's signature may be, bounding the replay of captured events
+const signatureTolerance = 5 * time.Minute
+
+// errStripeNotFound is returned for Stripe objects that do not exist, such as deleted customers
+var errStripeNotFound = errors.New("stripe object not found")
+
+// stripeClient calls the Stripe API, which takes form-encoded requests and answers with JSON
+type stripeClient struct {
+	cfg  config.StripeConfig
+	http *http.Client
+}
+
+func newStripeClient(cfg config.StripeConfig) *stripeClient {
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	return &stripeClient{cfg: cfg, http: &http.Client{Timeout: cfg.Timeout.Std()}}
+}
+
+// stripeSubscription is the part of a Stripe subscription setting the user's plan
+type stripeSubscription struct {
+	ID                string `json:"id"`
+	Customer          string `json:"customer"`
+	Status            string `json:"status"`
+	CurrentPeriodEnd  int64  `json:"current_period_end"`
+	CancelAtPeriodEnd bool   `json:"cancel_at_period_end"`
+	Items             struct {
+		Data []struct {
+			Price struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// priceIDs returns the prices subscribed to
+func (s stripeSubscription) priceIDs() []string {
+	var ids []string
+	for _, item := range s.Items.Data {
+		ids = append(ids, item.Price.ID)
+	}
+	return ids
+}
+
+// stripeEvent is a webhook event. Only the ID of the object it is about is read, as the object is fetched
+// again so events arriving out of order cannot apply a stale state.
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object struct {
+			ID     string `json:"id"`
+			Object string `json:"object"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+// createCustomer creates the Stripe customer of the user, returning its ID
+func (s *stripeClient) createCustomer(ctx context.Context, userID, tenantID, name, email string) (string, error) {
+	form := url.Values{
+		"name":                {name},
+		"email":               {email},
+		"metadata[user_id]":   {userID},
+		"metadata[tenant_id]": {tenantID},
+	}
+	var customer struct {
+		ID string `json:"id"`
+	}
+	err := s.do(ctx, http.MethodPost, "/v1/customers", form, &customer)
+	return customer.ID, err
+}
+
+// deleteCustomer deletes the customer, which cancels their subscriptions at once
+func (s *stripeClient) deleteCustomer(ctx context.Context, customerID string) error {
+	err := s.do(ctx, http.MethodDelete, "/v1/customers/"+url.PathEscape(customerID), nil, nil)
+	if errors.Is(err, errStripeNotFound) {
+		return nil
+	}
+	return err
+}
+
+// checkoutSession starts a checkout subscribing the customer to the price, returning the page's URL
+func (s *stripeClient) checkoutSession(ctx context.Context, customerID, userID, priceID string) (string, error) {
+	form := url.Values{
+		"mode":                    {"subscription"},
+		"customer":                {customerID},
+		"client_reference_id":     {userID},
+		"line_items[0][price]":    {priceID},
+		"line_items[0][quantity]": {"1"},
+		"success_url":             {s.cfg.SuccessURL},
+		"cancel_url":              {s.cfg.CancelURL},
+	}
+	var session struct {
+		URL string `json:"url"`
+	}
+	err := s.do(ctx, http.MethodPost, "/v1/checkout/sessions", form, &session)
+	return session.URL, err
+}
+
+// portalSession opens the customer portal, where the customer changes their payment details or cancels,
+// returning the page's URL
+func (s *stripeClient) portalSession(ctx context.Context, customerID string) (string, error) {
+	form := url.Values{"customer": {customerID}, "return_url": {s.cfg.CancelURL}}
+	var session struct {
+		URL string `json:"url"`
+	}
+	err := s.do(ctx, http.MethodPost, "/v1/billing_portal/sessions", form, &session)
+	return session.URL, err
+}
+
+// subscription returns the current state of the subscription
+func (s *stripeClient) subscription(ctx context.Context, subscriptionID string) (stripeSubscription, error) {
+	var sub stripeSubscription
+	err := s.do(ctx, http.MethodGet, "/v1/subscriptions/"+url.PathEscape(subscriptionID), nil, &sub)
+	return sub, err
+}
+
+// do sends a request to the Stripe API and decodes the response into out, unless it is nil
+func (s *stripeClient) do(ctx context.Context, method, path string, form url.Values, out any) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.cfg.BaseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.cfg.SecretKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return errStripeNotFound
+	}
+	if resp.StatusCode >= 300 {
+		var answer struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&answer)
+		return fmt.Errorf("stripe returned %s: %s", resp.Status, answer.Error.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("could not decode stripe response: %w", err)
+	}
+	return nil
+}
+
+// verifySignature checks the Stripe-Signature header of a webhook event: an HMAC-SHA256 with the webhook
+// secret of the timestamp and payload, under any of the v1 schemes while secrets are rolled
+func verifySignature(header string, payload []byte, secret string, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return errors.New("malformed signature header")
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > signatureTolerance || age < -signatureTolerance {
+		return errors.New("signature timestamp is outside the tolerance")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, signature := range signatures {
+		if given, err := hex.DecodeString(signature); err == nil && hmac.Equal(given, expected) {
+			return nil
+		}
+	}
+	return errors.New("no signature matches")
+}
@@ -0,0 +1,59 @@
+package billing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// stripeSignature returns the v1 signature of the payload at the timestamp, as Stripe computes it
+func stripeSignature(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "whsec_test"
+	now := time.Unix(1700000000, 0)
+	payload := []byte(`{"id":"evt_1","type":"customer.subscription.updated"}`)
+	ts := now.Unix()
+	header := func(timestamp int64, signatures ...string) string {
+		h := "t=" + strconv.FormatInt(timestamp, 10)
+		for _, s := range signatures {
+			h += ",v1=" + s
+		}
+		return h
+	}
+
+	for _, tc := range []struct {
+		name    string
+		header  string
+		payload []byte
+		ok      bool
+	}{
+		{"valid", header(ts, stripeSignature(secret, ts, payload)), payload, true},
+		{"rolled secret", header(ts, stripeSignature("whsec_old", ts, payload), stripeSignature(secret, ts, payload)), payload, true},
+		{"spaces between parts", "t=" + strconv.FormatInt(ts, 10) + ", v1=" + stripeSignature(secret, ts, payload), payload, true},
+		{"replayed after the tolerance", header(ts-360, stripeSignature(secret, ts-360, payload)), payload, false},
+		{"dated in the future", header(ts+360, stripeSignature(secret, ts+360, payload)), payload, false},
+		{"timestamp changed", header(ts, stripeSignature(secret, ts-60, payload)), payload, false},
+		{"payload changed", header(ts, stripeSignature(secret, ts, payload)), []byte(`{"id":"evt_2"}`), false},
+		{"other secret", header(ts, stripeSignature("whsec_other", ts, payload)), payload, false},
+		{"no signature", header(ts), payload, false},
+		{"no timestamp", "v1=" + stripeSignature(secret, ts, payload), payload, false},
+		{"only other schemes", "t=" + strconv.FormatInt(ts, 10) + ",v0=" + stripeSignature(secret, ts, payload), payload, false},
+		{"empty", "", payload, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verifySignature(tc.header, tc.payload, secret, now)
+			if (err == nil) != tc.ok {
+				t.Errorf("got error %v, want valid %t", err, tc.ok)
+			}
+		})
+	}
+}
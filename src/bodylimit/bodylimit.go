@@ -0,0 +1,58 @@
+// Package bodylimit bounds the size of request bodies, so an oversized upload is refused instead of being
+// streamed into memory, temporary files or the image store.
+package bodylimit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"profile-api/apierror"
+	"profile-api/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reader stops reading the body at the limit, remembering that it was reached
+type reader struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	var sizeErr *http.MaxBytesError
+	if errors.As(err, &sizeErr) {
+		r.exceeded = true
+	}
+	return n, err
+}
+
+// Middleware limits each request body to its route's configured limit. Requests declaring a larger body
+// are rejected before it is read; bodies turning out larger fail when the handler reads past the limit,
+// and are answered with 413 whichever error the handler reported for the failed read.
+func Middleware(cfg config.BodyLimitsConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, ok := cfg.Routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			limit = cfg.Default
+		}
+		if c.Request.ContentLength > int64(limit) {
+			apierror.Abort(c, tooLarge(limit))
+			return
+		}
+
+		body := &reader{ReadCloser: http.MaxBytesReader(c.Writer, c.Request.Body, int64(limit))}
+		c.Request.Body = body
+		c.Next()
+
+		if body.exceeded && c.Writer.Size() <= 0 {
+			apierror.Abort(c, tooLarge(limit))
+		}
+	}
+}
+
+func tooLarge(limit int) *apierror.Error {
+	return apierror.TooLarge(fmt.Sprintf("Request body must be at most %d bytes", limit))
+}
@@ -0,0 +1,153 @@
+// Package bulk deletes many of a user's documents in one request, chosen by ID or by a filter on their
+// fields. A dry run returns what would be deleted without deleting it, and deleting by filter must be
+// confirmed, so a mistyped filter can't silently empty a collection.
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"profile-api/apierror"
+	"profile-api/dryrun"
+	"profile-api/store"
+	"profile-api/utils"
+)
+
+// DeleteRequest chooses the documents to delete, either by ID or by a filter matching the values of their
+// fields, named as in the JSON of the documents, with dots reaching into nested fields such as
+// taxonomy.tags
+type DeleteRequest struct {
+	IDs    []string          `json:"ids" binding:"max=500,dive,notblank,max=100"`
+	Filter map[string]string `json:"filter" binding:"max=20"`
+	// DryRun returns what would be deleted without deleting anything
+	DryRun bool `json:"dryRun"`
+	// Confirm must be set to delete by filter
+	Confirm bool `json:"confirm"`
+}
+
+// DeleteResult lists the documents deleted, or that would be deleted by a dry run
+type DeleteResult struct {
+	DryRun  bool     `json:"dryRun"`
+	Count   int      `json:"count"`
+	Deleted []string `json:"deleted"`
+	// NotFound lists the requested IDs that matched no document
+	NotFound []string `json:"notFound"`
+}
+
+// Delete deletes the items the request chooses among the user's items, using id to read each item's ID
+// and remove to delete one. Items are deleted one by one through the repository, each within the
+// operation timeout, so each deletion is audited like a single one; when one fails the error reports how
+// many were deleted before it. A request sent as a dry run with the X-Dry-Run header is one, whatever its
+// body says.
+func Delete[T any](ctx context.Context, req DeleteRequest, items []T, id func(T) string, remove func(ctx context.Context, id string) error) (DeleteResult, error) {
+	req.DryRun = req.DryRun || dryrun.Active(ctx)
+	result := DeleteResult{DryRun: req.DryRun, Deleted: []string{}, NotFound: []string{}}
+	switch {
+	case len(req.IDs) > 0 && len(req.Filter) > 0:
+		return result, apierror.BadRequest("Choose the documents to delete by ids or by filter, not both")
+	case len(req.IDs) == 0 && len(req.Filter) == 0:
+		return result, apierror.BadRequest("ids or filter is required")
+	case len(req.Filter) > 0 && !req.DryRun && !req.Confirm:
+		return result, apierror.BadRequest("Deleting by filter must be confirmed, check what it matches with a dry run first")
+	}
+
+	if len(req.IDs) > 0 {
+		byID := map[string]bool{}
+		for _, item := range items {
+			byID[id(item)] = true
+		}
+		for _, want := range req.IDs {
+			if slices.Contains(result.Deleted, want) {
+				continue
+			}
+			if byID[want] {
+				result.Deleted = append(result.Deleted, want)
+			} else {
+				result.NotFound = append(result.NotFound, want)
+			}
+		}
+	} else {
+		for _, item := range items {
+			ok, err := matches(item, req.Filter)
+			if err != nil {
+				return result, err
+			}
+			if ok {
+				result.Deleted = append(result.Deleted, id(item))
+			}
+		}
+	}
+
+	if !req.DryRun {
+		for i, itemID := range result.Deleted {
+			itemCtx, cancel := utils.WithOperationTimeout(ctx)
+			err := remove(itemCtx, itemID)
+			cancel()
+			// An item deleted since it was listed is gone either way
+			if err != nil && !errors.Is(err, store.ErrNotFound) {
+				return result, fmt.Errorf("deleted %d of %d documents: %w", i, len(result.Deleted), err)
+			}
+		}
+	}
+	result.Count = len(result.Deleted)
+	return result, nil
+}
+
+// matches reports whether every field of the filter holds its value in the item. Fields are compared as
+// text, case sensitively; a field holding a list matches when any of its values does, and a field the
+// item lacks matches nothing.
+func matches(item any, filter map[string]string) (bool, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return false, err
+	}
+	var fields any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false, err
+	}
+	for field, want := range filter {
+		value, ok := lookup(fields, field)
+		if !ok || !matchValue(value, want) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// lookup returns the value of the field at the dotted path in the decoded JSON
+func lookup(value any, path string) (any, bool) {
+	for _, name := range strings.Split(path, ".") {
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = fields[name]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func matchValue(value any, want string) bool {
+	switch v := value.(type) {
+	case nil:
+		return want == ""
+	case string:
+		return v == want
+	case []any:
+		for _, elem := range v {
+			if matchValue(elem, want) {
+				return true
+			}
+		}
+		return false
+	case map[string]any:
+		return false
+	default:
+		return fmt.Sprint(v) == want
+	}
+}
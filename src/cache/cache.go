@@ -0,0 +1,121 @@
+// Package cache keeps copies of hot public reads in Redis so traffic spikes do not all reach the database.
+// Cache failures are logged and treated as misses, they never fail a request.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"profile-api/config"
+	"profile-api/tenant"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache stores JSON encoded values in Redis under a common key prefix
+type Cache struct {
+	client *redis.Client
+	prefix string
+}
+
+// New connects to the Redis server in the cache config and checks that it is reachable
+func New(ctx context.Context, cfg config.CacheConfig) (*Cache, error) {
+	opts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error pinging Redis: %w", err)
+	}
+	slog.Info("Connected to Redis cache")
+	return &Cache{client: client, prefix: cfg.KeyPrefix}, nil
+}
+
+// Get decodes the value cached under key into dst, reporting whether it was found
+func (c *Cache) Get(ctx context.Context, key string, dst any) bool {
+	data, err := c.client.Get(ctx, c.key(ctx, key)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.Warn("Error reading from cache", "key", key, "error", err)
+		}
+		return false
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		slog.Warn("Error decoding cached value", "key", key, "error", err)
+		return false
+	}
+	return true
+}
+
+// Set caches the value under key until the TTL expires
+func (c *Cache) Set(ctx context.Context, key string, value any, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		slog.Warn("Error encoding value for cache", "key", key, "error", err)
+		return
+	}
+	if err := c.client.Set(ctx, c.key(ctx, key), data, ttl).Err(); err != nil {
+		slog.Warn("Error writing to cache", "key", key, "error", err)
+	}
+}
+
+// Delete removes the keys from the cache
+func (c *Cache) Delete(ctx context.Context, keys ...string) {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.key(ctx, key)
+	}
+	if err := c.client.Del(ctx, prefixed...).Err(); err != nil {
+		slog.Warn("Error deleting from cache", "keys", keys, "error", err)
+	}
+}
+
+// Generation returns the current value of a generation counter. Including the generation in cache keys
+// lets a whole group of keys be invalidated at once with Bump.
+func (c *Cache) Generation(ctx context.Context, key string) string {
+	gen, err := c.client.Get(ctx, c.key(ctx, key)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.Warn("Error reading cache generation", "key", key, "error", err)
+		}
+		return "0"
+	}
+	return gen
+}
+
+// Bump advances a generation counter, invalidating every key built from the previous generation
+func (c *Cache) Bump(ctx context.Context, key string) {
+	if err := c.client.Incr(ctx, c.key(ctx, key)).Err(); err != nil {
+		slog.Warn("Error bumping cache generation", "key", key, "error", err)
+	}
+}
+
+// Ping checks that Redis is reachable
+func (c *Cache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// Close closes the connection to Redis
+func (c *Cache) Close() error {
+	return c.client.Close()
+}
+
+// key returns the Redis key of the entry, namespaced by the prefix and the context's tenant
+func (c *Cache) key(ctx context.Context, key string) string {
+	if id := tenant.ID(ctx); id != tenant.Default {
+		return c.prefix + id + ":" + key
+	}
+	return c.prefix + key
+}
+
+// Key joins the parts of a cache key
+func Key(parts ...string) string {
+	return strings.Join(parts, ":")
+}
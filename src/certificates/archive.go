@@ -0,0 +1,86 @@
+package certificates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"profile-api/config"
+	"profile-api/events"
+	"profile-api/store"
+	"profile-api/utils"
+	"profile-api/validation"
+)
+
+// archival holds the rules ArchiveEnded archives certificates by
+var archival = config.ArchivalConfig{Rules: []config.ArchivalRule{{}}}
+
+// ConfigureArchival sets the rules ArchiveEnded archives certificates by
+func ConfigureArchival(cfg config.ArchivalConfig) {
+	archival = cfg
+}
+
+// endedAt returns when a certificate ending on the date has ended: the start of the day after it, or of the
+// month or year after one ending on a month or year
+func endedAt(date string) (time.Time, bool) {
+	t, ok := validation.ParseDate(date)
+	if !ok {
+		return time.Time{}, false
+	}
+	switch len(date) {
+	case len("2006"):
+		return t.AddDate(1, 0, 0), true
+	case len("2006-01"):
+		return t.AddDate(0, 1, 0), true
+	}
+	return t.AddDate(0, 0, 1), true
+}
+
+// archivalRule returns the first rule matching the certificate
+func archivalRule(item Certificate) (config.ArchivalRule, bool) {
+	for _, rule := range archival.Rules {
+		if rule.Institution == "" || strings.EqualFold(rule.Institution, item.Institution) {
+			return rule, true
+		}
+	}
+	return config.ArchivalRule{}, false
+}
+
+// ArchiveEnded archives the certificates whose archival rule's grace has passed since they ended, hiding
+// them as the rule says, and tells their owners through the certificate.archived event. Certificates
+// matching no rule are left alone.
+func ArchiveEnded(ctx context.Context) error {
+	now := time.Now().UTC()
+	items, err := repo.ListUnarchived(ctx, now.Format("2006-01-02"))
+	if err != nil {
+		return err
+	}
+	archived := 0
+	var errs []error
+	for _, item := range items {
+		rule, ok := archivalRule(item)
+		if !ok {
+			continue
+		}
+		end, ok := endedAt(item.End)
+		if !ok || now.Before(end.Add(rule.Grace.Std())) {
+			continue
+		}
+		item.ArchivedAt, item.Restricted = &now, rule.Hide
+		itemCtx, cancel := utils.WithOperationTimeout(ctx)
+		err := repo.SetArchived(itemCtx, item.UserID, item.CertificateID, item.ArchivedAt, item.Restricted)
+		if err == nil {
+			events.Publish(itemCtx, item.UserID, events.TypeCertificateArchived, item)
+			archived++
+		}
+		cancel()
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			errs = append(errs, fmt.Errorf("certificate %s: %w", item.CertificateID, err))
+		}
+	}
+	slog.Info("Archived ended certificates", "archived", archived, "failed", len(errs))
+	return errors.Join(errs...)
+}
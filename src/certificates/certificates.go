@@ -2,93 +2,89 @@ package certificates
 
 import (
 	"context"
-	"net/http"
+	"errors"
+	"io"
 
+	"profile-api/apierror"
+	"profile-api/apiversion"
 	"profile-api/auth"
+	"profile-api/bulk"
+	"profile-api/dryrun"
+	"profile-api/events"
+	"profile-api/images"
+	"profile-api/logging"
+	"profile-api/quota"
+	"profile-api/scan"
+	"profile-api/store"
+	"profile-api/utils"
+	"profile-api/visibility"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var certificateCollection *mongo.Collection
-
-// Certificate represents a user's certificate
-type JSONResponse struct {
-	Message string `json:"message"`
-	Error   string `json:"error"`
-}
+var repo Repository
 
 // GetCertificates retrieves all certificates for a given user.
 //
 //	@Summary		Get all certificates
-//	@Description	Retrieves all certificates for a given user
+//	@Description	Retrieves all certificates for a given user, without the fields their visibility hides from the requester. Archived certificates hidden from the requester are left out.
 //	@Tags			Certificates
 //	@Accept			json
 //	@Produce		json
 //	@Param			userid	path		string	true	"User ID"
 //	@Success		200		{array}		Certificate
-//	@Failure		500		{object}	JSONResponse	"error":	"Could not retrieve certificates"
+//	@Failure		500		{object}	apierror.Response	"error":	"Could not retrieve certificates"
 //	@Router			/certificates/{userid} [get]
 func GetCertificates(c *gin.Context) {
 	userID := c.Param("userid")
 
-	var certificates []Certificate
-	cursor, err := certificateCollection.Find(context.Background(), bson.M{"user_id": userID})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	certificates, err := repo.List(ctx, userID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve certificates"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve certificates"))
 		return
 	}
-	defer cursor.Close(context.Background())
-	for cursor.Next(context.Background()) {
-		var certificate Certificate
-		err := cursor.Decode(&certificate)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve certificate"})
-			return
-		}
-		certificates = append(certificates, certificate)
-	}
 
-	c.JSON(http.StatusOK, certificates)
+	visibility.List(c, certificates)
 }
 
 // GetCertificateEntry retrieves a specific certificate entry for a user.
 //
 //	@Summary		Get a certificate entry
-//	@Description	Retrieves a specific certificate entry for a user
+//	@Description	Retrieves a specific certificate entry for a user, without the fields its visibility hides from the requester. An archived certificate hidden from the requester is not found.
 //	@Tags			Certificates
 //	@Accept			json
 //	@Produce		json
 //	@Param			userid			path		string	true	"User ID"
 //	@Param			certificateid	path		string	true	"Certificate ID"
 //	@Success		200				{object}	Certificate
-//	@Failure		404				{object}	JSONResponse	"error":	"Certificate not found"
-//	@Failure		401				{object}	JSONResponse	"error":	"Unauthorized"
-//	@Failure		403				{object}	JSONResponse	"error":	"Forbidden"
-//	@Failure		400				{object}	JSONResponse	"error":	"Invalid request body"
-//	@Failure		500				{object}	JSONResponse	"error":	"Could not retrieve certificate"
+//	@Failure		404				{object}	apierror.Response	"error":	"Certificate not found"
+//	@Failure		401				{object}	apierror.Response	"error":	"Unauthorized"
+//	@Failure		403				{object}	apierror.Response	"error":	"Forbidden"
+//	@Failure		400				{object}	apierror.Response	"error":	"Invalid request body"
+//	@Failure		500				{object}	apierror.Response	"error":	"Could not retrieve certificate"
 //	@Router			/certificates/{userid}/{certificateid} [get]
 func GetCertificateEntry(c *gin.Context) {
 	userID := c.Param("userid")
 	certificateID := c.Param("certificateid")
 
-	var certificate Certificate
-	err := certificateCollection.FindOne(context.Background(), bson.M{"user_id": userID, "certificate_id": certificateID}).Decode(&certificate)
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	certificate, err := repo.Get(ctx, userID, certificateID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve certificate"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve certificate"))
 		return
 	}
 
-	c.JSON(http.StatusOK, certificate)
+	visibility.OK(c, certificate)
 }
 
 // PutCertificateEntry updates or creates a specific certificate entry for a user.
 //
 //	@Summary		Update or create a certificate entry
-//	@Description	Updates or creates a specific certificate entry for a user
+//	@Description	Updates or creates a specific certificate entry for a user. Changing the end date of an archived certificate unarchives it.
 //	@Tags			Certificates
 //	@Accept			json
 //	@Produce		json
@@ -96,10 +92,10 @@ func GetCertificateEntry(c *gin.Context) {
 //	@Param			certificateid	path		string		true	"Certificate ID"
 //	@Param			body			body		Certificate	true	"Certificate JSON object"
 //	@Success		200				{object}	map[string]string
-//	@Failure		400				{object}	JSONResponse	"error":	"Invalid request body"
-//	@Failure		500				{object}	JSONResponse	"error":	"Could not update certificate"
-//	@Failure		401				{object}	JSONResponse	"error":	"Unauthorized"
-//	@Failure		403				{object}	JSONResponse	"error":	"Forbidden"
+//	@Failure		400				{object}	apierror.Response	"error":	"Invalid request body"
+//	@Failure		500				{object}	apierror.Response	"error":	"Could not update certificate"
+//	@Failure		401				{object}	apierror.Response	"error":	"Unauthorized"
+//	@Failure		403				{object}	apierror.Response	"error":	"Forbidden"
 //	@Security		BearerAuth
 //	@Router			/certificates/{userid}/{certificateid} [put]
 func PutCertificateEntry(c *gin.Context) {
@@ -107,20 +103,33 @@ func PutCertificateEntry(c *gin.Context) {
 	certificateID := c.Param("certificateid")
 
 	var req Certificate
-	if err := c.BindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 	req.UserID = userID
 	req.CertificateID = certificateID
 
-	_, err := certificateCollection.UpdateOne(context.Background(), bson.M{"user_id": userID, "certificate_id": certificateID}, bson.M{"$set": req}, options.Update().SetUpsert(true))
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not update certificate"})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	existing, err := repo.Get(ctx, userID, certificateID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update certificate"))
 		return
 	}
+	if err := repo.Save(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update certificate"))
+		return
+	}
+	// A renewed certificate is archived again once its new end date passes
+	if existing.ArchivedAt != nil && existing.End != req.End {
+		if err := repo.SetArchived(ctx, userID, certificateID, nil, ""); err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not update certificate"))
+			return
+		}
+	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Certificate updated"})
+	apiversion.Updated(c, req, gin.H{"message": "Certificate updated"})
 }
 
 // DeleteCertificateEntry deletes a specific certificate entry for a user.
@@ -133,18 +142,69 @@ func PutCertificateEntry(c *gin.Context) {
 //	@Param			userid			path		string	true	"User ID"
 //	@Param			certificateid	path		string	true	"Certificate ID"
 //	@Success		200				{object}	map[string]string
+//	@Failure		404				{object}	apierror.Response	"Certificate not found"
+//	@Failure		403				{object}	apierror.Response	"Not the owner of the certificates"
 //	@Router			/certificates/{userid}/{certificateid} [delete]
 func DeleteCertificateEntry(c *gin.Context) {
 	userID := c.Param("userid")
 	certificateID := c.Param("certificateid")
 
-	_, err := certificateCollection.DeleteOne(context.Background(), bson.M{"user_id": userID, "certificate_id": certificateID})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	err := repo.Delete(ctx, userID, certificateID)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Certificate not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete certificate"))
+		return
+	}
+
+	apiversion.NoContent(c, gin.H{"message": "Certificate deleted"})
+}
+
+// BulkDeleteCertificates deletes many of a user's certificates at once.
+//
+//	@Summary		Delete certificates in bulk
+//	@Description	Deletes the user's certificates with the given IDs, or those whose fields match every value of the filter, such as {"institution": "AWS"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.
+//	@Tags			Certificates
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string				true	"User ID"
+//	@Param			body	body		bulk.DeleteRequest	true	"The certificates to delete"
+//	@Success		200		{object}	bulk.DeleteResult
+//	@Failure		400		{object}	apierror.Response	"Invalid request, or a filter that is not confirmed"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the certificates"
+//	@Failure		500		{object}	apierror.Response	"Could not delete certificates"
+//	@Router			/certificates/{userid}/bulk-delete [post]
+func BulkDeleteCertificates(c *gin.Context) {
+	userID := c.Param("userid")
+
+	var req bulk.DeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	items, err := repo.List(ctx, userID)
+	cancel()
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve certificates"))
+		return
+	}
+	result, err := bulk.Delete(c.Request.Context(), req, items, func(item Certificate) string { return item.CertificateID }, func(ctx context.Context, id string) error {
+		return repo.Delete(ctx, userID, id)
+	})
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not delete certificate"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete certificates"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Certificate deleted"})
+	apiversion.OK(c, result)
 }
 
 // PutCertificateImage uploads or updates the certificate image for a specific certificate entry.
@@ -158,6 +218,9 @@ func DeleteCertificateEntry(c *gin.Context) {
 //	@Param			certificateid	path		string	true	"Certificate ID"
 //	@Param			file			formData	file	true	"Certificate Image"
 //	@Success		200				{object}	map[string]string
+//	@Failure		402				{object}	apierror.Response	"The user's plan has no storage left for the image"
+//	@Failure		403				{object}	apierror.Response	"Not the owner of the certificates"
+//	@Failure		413				{object}	apierror.Response	"The user has no storage left for the image"
 //	@Router			/certificates/{userid}/{certificateid}/cert_image [put]
 func PutCertificateImage(c *gin.Context) {
 	userID := c.Param("userid")
@@ -165,24 +228,51 @@ func PutCertificateImage(c *gin.Context) {
 
 	file, err := c.FormFile("file")
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		apierror.Abort(c, apierror.BadRequest("invalid request body"))
 		return
 	}
 
-	FileBytes, err := file.Open()
+	fileReader, err := file.Open()
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		apierror.Abort(c, apierror.BadRequest("invalid request body"))
+		return
+	}
+	defer fileReader.Close()
+	image, err := io.ReadAll(fileReader)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("invalid request body"))
+		return
+	}
+	// Remove the metadata of photos, such as where they were taken
+	image, err = images.Process(image)
+	if errors.Is(err, images.ErrInvalid) {
+		apierror.Abort(c, apierror.BadRequest("invalid image"))
 		return
 	}
-	defer FileBytes.Close()
-
-	_, err = certificateCollection.UpdateOne(context.Background(), bson.M{"user_id": userID, "certificate_id": certificateID}, bson.M{"$set": bson.M{"cert_image": FileBytes}}, options.Update().SetUpsert(true))
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "could not update certification"})
+		apierror.Abort(c, apierror.Wrap(err, "could not process image"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	upload := quota.NewUpload(userID, "certificate", certificateID, "cert_image", int64(len(image)))
+	if err := quota.CheckUpload(ctx, c.MustGet("user").(auth.User), upload); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "could not check storage quota"))
+		return
+	}
+	if err := repo.SetCertImage(ctx, userID, certificateID, image); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "could not update certification"))
 		return
 	}
+	if err := quota.RecordUpload(ctx, upload); err != nil {
+		logging.Logger(c).Error("Could not record upload", "error", err)
+	}
+	if err := scan.Enqueue(ctx, scan.Upload{Kind: ScanKind, UserID: userID, ItemID: certificateID}); err != nil {
+		logging.Logger(c).Error("Could not queue image scan", "error", err)
+	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "cert image uploaded"})
+	apiversion.NoContent(c, gin.H{"message": "cert image uploaded"})
 }
 
 // PostCertificate creates a new certificate entry for a user.
@@ -194,49 +284,59 @@ func PutCertificateImage(c *gin.Context) {
 //	@Produce		json
 //	@Param			userid	path		string		true	"User ID"
 //	@Param			body	body		Certificate	true	"Certificate JSON object"
-//	@Success		200		{object}	map[string]string
-//	@Failure		400		{object}	JSONResponse	"error":	"Invalid request body"
-//	@Failure		401		{object}	JSONResponse	"error":	"Unauthorized"
-//	@Failure		403		{object}	JSONResponse	"error":	"Forbidden"
-//	@Failure		404		{object}	JSONResponse	"error":	"User not found"
-//	@Failure		409		{object}	JSONResponse	"error":	"Certificate already exists"
-//	@Failure		500		{object}	JSONResponse	"error":	"Could not create certificate"
+//	@Success		200		{object}	Certificate	"The created certificate with its ID, and a message"
+//	@Failure		400		{object}	apierror.Response	"error":	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"error":	"Unauthorized"
+//	@Failure		403		{object}	apierror.Response	"error":	"Forbidden"
+//	@Failure		404		{object}	apierror.Response	"error":	"User not found"
+//	@Failure		409		{object}	apierror.Response	"error":	"Certificate already exists"
+//	@Failure		413		{object}	apierror.Response	"error":	"Certificate limit reached"
+//	@Failure		500		{object}	apierror.Response	"error":	"Could not create certificate"
 //	@Security		BearerAuth
 //	@Router			/certificates/{userid} [post]
 func PostCertificate(c *gin.Context) {
 	userID := c.Param("userid")
 
 	var req Certificate
-	if err := c.BindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 	req.UserID = userID
 	req.CertificateID = primitive.NewObjectID().Hex()
 
-	_, err := certificateCollection.InsertOne(context.Background(), req)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not create certificate"})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Create(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create certificate"))
 		return
 	}
+	events.Publish(ctx, userID, events.TypeCertificateCreated, req)
+
+	apiversion.Created(c, req.CertificateID, req, apiversion.Announce("Certificate Added", req))
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Certificate Added"})
+// countCertificates counts the user's certificates against their document quota
+func countCertificates(ctx context.Context, userID string) (int, error) {
+	items, err := repo.List(ctx, userID)
+	return len(items), err
 }
 
 // InitializeRoutes initializes the certificates routes
-func InitializeRoutes(router *gin.RouterGroup, db *mongo.Client, db_name string) {
-	certificateCollection = db.Database(db_name).Collection("certificates")
+func InitializeRoutes(router *gin.RouterGroup, r Repository, users auth.Repository) {
+	repo = r
 
-	authOptional := auth.AuthMiddleware(db, db_name, false)
-	authRequired := auth.AuthMiddleware(db, db_name, true)
+	authOptional := auth.AuthMiddleware(users, false)
+	authRequired := auth.AuthMiddleware(users, true)
 
 	router.GET("/:userid", authOptional, GetCertificates)
 	router.GET("/:userid/:certificateid", authOptional, GetCertificateEntry)
 
 	protected := router.Group("/")
 	protected.Use(authRequired)
-	protected.POST("/:userid", PostCertificate)
-	protected.PUT("/:userid/:certificateid", PutCertificateEntry)
-	protected.DELETE("/:userid/:certificateid", DeleteCertificateEntry)
-	protected.PUT("/:userid/:certificateid/cert_image", PutCertificateImage)
+	protected.POST("/:userid", dryrun.Supported(), auth.RequireOwner(), quota.LimitDocuments("certificates", countCertificates), PostCertificate)
+	protected.PUT("/:userid/:certificateid", dryrun.Supported(), auth.RequireOwner(), PutCertificateEntry)
+	protected.DELETE("/:userid/:certificateid", dryrun.Supported(), auth.RequireOwner(), DeleteCertificateEntry)
+	protected.POST("/:userid/bulk-delete", dryrun.Supported(), auth.RequireOwner(), BulkDeleteCertificates)
+	protected.PUT("/:userid/:certificateid/cert_image", auth.RequireOwner(), PutCertificateImage)
 }
@@ -1,12 +1,42 @@
 package certificates
 
+import (
+	"time"
+
+	"profile-api/visibility"
+)
+
 // Certificate represents a user's certification
 type Certificate struct {
 	UserID        string `bson:"user_id" json:"user_id"`
 	CertificateID string `bson:"certificate_id" json:"certificate_id"`
-	Title         string `bson:"title" json:"title"`
-	Institution   string `bson:"institution" json:"institution"`
-	Start         string `bson:"start" json:"start"`
-	End           string `bson:"end" json:"end"`
-	Description   string `bson:"description" json:"description"`
+	Title         string `bson:"title" json:"title" binding:"required,notblank,max=200"`
+	Institution   string `bson:"institution" json:"institution" binding:"required,notblank,max=200"`
+	Start         string `bson:"start" json:"start" binding:"omitempty,date"`
+	End           string `bson:"end" json:"end" binding:"omitempty,date"`
+	Description   string `bson:"description" json:"description" binding:"max=5000"`
+	// CertImageQuarantined names the malware found in the last certificate image uploaded, which was removed
+	CertImageQuarantined string `bson:"cert_image_quarantined,omitempty" json:"cert_image_quarantined,omitempty" readonly:"true"`
+	// Visibility maps fields to who they are shown to: public, members or private
+	Visibility visibility.Rules `bson:"visibility" json:"visibility,omitempty" binding:"omitempty,visibility"`
+	// ArchivedAt is when the certificate was archived as expired, some time after it ended
+	ArchivedAt *time.Time `bson:"archived_at,omitempty" json:"archived_at,omitempty" readonly:"true"`
+	// Restricted is who an archived certificate is still shown to at all, members or private, when archiving
+	// hid it from the public
+	Restricted string `bson:"restricted,omitempty" json:"restricted,omitempty" readonly:"true"`
+}
+
+// Owner returns the ID of the user the certificate belongs to
+func (c Certificate) Owner() string {
+	return c.UserID
+}
+
+// FieldVisibility returns the rules of the certificate's fields
+func (c Certificate) FieldVisibility() visibility.Rules {
+	return c.Visibility
+}
+
+// DocumentVisibility returns who an archived certificate is still shown to, or "" for everyone
+func (c Certificate) DocumentVisibility() string {
+	return c.Restricted
 }
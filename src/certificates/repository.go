@@ -0,0 +1,38 @@
+package certificates
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores certificates
+type Repository interface {
+	// List returns every certificate belonging to the user
+	List(ctx context.Context, userID string) ([]Certificate, error)
+	// ListByUsers returns every certificate belonging to any of the users
+	ListByUsers(ctx context.Context, userIDs []string) ([]Certificate, error)
+	// ListEnding returns the certificates of every user ending from the first date up to but excluding the
+	// second. Dates are compared as text, so a certificate ending on a month or year is listed just before
+	// its first day.
+	ListEnding(ctx context.Context, from, to string) ([]Certificate, error)
+	// ListUnarchived returns the certificates of every user ending before the date, compared as text, that are
+	// not archived
+	ListUnarchived(ctx context.Context, before string) ([]Certificate, error)
+	// Get returns a single certificate, or store.ErrNotFound
+	Get(ctx context.Context, userID, certificateID string) (Certificate, error)
+	// Create stores a new certificate
+	Create(ctx context.Context, item Certificate) error
+	// Save replaces a certificate, creating it if it does not exist. Whether it is archived is left as it is.
+	Save(ctx context.Context, item Certificate) error
+	// SetArchived archives a certificate at the time, restricting who it is shown to when restricted is not
+	// empty, or unarchives it when archivedAt is nil
+	SetArchived(ctx context.Context, userID, certificateID string, archivedAt *time.Time, restricted string) error
+	// Delete removes a certificate, or returns store.ErrNotFound when the user has no such certificate
+	Delete(ctx context.Context, userID, certificateID string) error
+	// SetCertImage stores the certificate image of a certificate, creating the certificate if it does not exist
+	SetCertImage(ctx context.Context, userID, certificateID string, image []byte) error
+	// GetCertImage returns the certificate image of a certificate, or store.ErrNotFound
+	GetCertImage(ctx context.Context, userID, certificateID string) ([]byte, error)
+	// QuarantineCertImage removes the certificate image of a certificate and records the malware found in it
+	QuarantineCertImage(ctx context.Context, userID, certificateID, threat string) error
+}
@@ -0,0 +1,87 @@
+package certificates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"profile-api/audit"
+	"profile-api/store"
+)
+
+// AuditedRepository records every change to certificates in the audit log
+type AuditedRepository struct {
+	Repository
+}
+
+// NewAuditedRepository wraps the repository to record its changes in the audit log
+func NewAuditedRepository(r Repository) *AuditedRepository {
+	return &AuditedRepository{Repository: r}
+}
+
+func (r *AuditedRepository) Create(ctx context.Context, item Certificate) error {
+	if err := r.Repository.Create(ctx, item); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionCreate, "certificate", item.UserID, item.CertificateID, nil, item)
+	return nil
+}
+
+func (r *AuditedRepository) Save(ctx context.Context, item Certificate) error {
+	before, err := r.Repository.Get(ctx, item.UserID, item.CertificateID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.Save(ctx, item); err != nil {
+		return err
+	}
+	audit.RecordSave(ctx, "certificate", item.UserID, item.CertificateID, before, existed, item)
+	return nil
+}
+
+func (r *AuditedRepository) Delete(ctx context.Context, userID, certificateID string) error {
+	before, err := r.Repository.Get(ctx, userID, certificateID)
+	if errors.Is(err, store.ErrNotFound) {
+		return r.Repository.Delete(ctx, userID, certificateID)
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.Delete(ctx, userID, certificateID); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionDelete, "certificate", userID, certificateID, before, nil)
+	return nil
+}
+
+func (r *AuditedRepository) SetArchived(ctx context.Context, userID, certificateID string, archivedAt *time.Time, restricted string) error {
+	if err := r.Repository.SetArchived(ctx, userID, certificateID, archivedAt, restricted); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionUpdate, "certificate", userID, certificateID, nil, map[string]any{"archived_at": archivedAt, "restricted": restricted})
+	return nil
+}
+
+func (r *AuditedRepository) SetCertImage(ctx context.Context, userID, certificateID string, image []byte) error {
+	_, err := r.Repository.Get(ctx, userID, certificateID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.SetCertImage(ctx, userID, certificateID, image); err != nil {
+		return err
+	}
+	// Only the size of the image is recorded
+	audit.RecordSave(ctx, "certificate", userID, certificateID, nil, existed, map[string]string{"cert_image": fmt.Sprintf("%d bytes", len(image))})
+	return nil
+}
+
+func (r *AuditedRepository) QuarantineCertImage(ctx context.Context, userID, certificateID, threat string) error {
+	if err := r.Repository.QuarantineCertImage(ctx, userID, certificateID, threat); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionUpdate, "certificate", userID, certificateID, nil, map[string]string{"cert_image_quarantined": threat})
+	return nil
+}
@@ -0,0 +1,62 @@
+package certificates
+
+import (
+	"context"
+	"time"
+
+	"profile-api/dryrun"
+)
+
+// DryRunRepository leaves certificates unchanged in dry runs, reading them as usual. A deletion still
+// reports a missing document.
+type DryRunRepository struct {
+	Repository
+}
+
+// NewDryRunRepository wraps the repository to skip its writes in dry runs
+func NewDryRunRepository(r Repository) *DryRunRepository {
+	return &DryRunRepository{Repository: r}
+}
+
+func (r *DryRunRepository) Create(ctx context.Context, item Certificate) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.Create(ctx, item)
+}
+
+func (r *DryRunRepository) Save(ctx context.Context, item Certificate) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.Save(ctx, item)
+}
+
+func (r *DryRunRepository) SetArchived(ctx context.Context, userID, certificateID string, archivedAt *time.Time, restricted string) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.SetArchived(ctx, userID, certificateID, archivedAt, restricted)
+}
+
+func (r *DryRunRepository) Delete(ctx context.Context, userID, certificateID string) error {
+	if dryrun.Active(ctx) {
+		_, err := r.Repository.Get(ctx, userID, certificateID)
+		return err
+	}
+	return r.Repository.Delete(ctx, userID, certificateID)
+}
+
+func (r *DryRunRepository) SetCertImage(ctx context.Context, userID, certificateID string, image []byte) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.SetCertImage(ctx, userID, certificateID, image)
+}
+
+func (r *DryRunRepository) QuarantineCertImage(ctx context.Context, userID, certificateID, threat string) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.QuarantineCertImage(ctx, userID, certificateID, threat)
+}
@@ -0,0 +1,176 @@
+package certificates
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps certificates in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu     sync.RWMutex
+	items  []Certificate
+	images map[string][]byte
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{images: map[string][]byte{}}
+}
+
+// index returns the position of a certificate in items, or -1. The caller must hold the lock.
+func (r *MemoryRepository) index(userID, certificateID string) int {
+	for i, item := range r.items {
+		if item.UserID == userID && item.CertificateID == certificateID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string) ([]Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Certificate
+	for _, item := range r.items {
+		if item.UserID == userID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Certificate
+	for _, item := range r.items {
+		if slices.Contains(userIDs, item.UserID) {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) ListEnding(ctx context.Context, from, to string) ([]Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Certificate
+	for _, item := range r.items {
+		if item.End >= from && item.End < to {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) ListUnarchived(ctx context.Context, before string) ([]Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Certificate
+	for _, item := range r.items {
+		if item.End != "" && item.End < before && item.ArchivedAt == nil {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, userID, certificateID string) (Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.index(userID, certificateID)
+	if i < 0 {
+		return Certificate{}, store.ErrNotFound
+	}
+	return r.items[i], nil
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, item Certificate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.index(item.UserID, item.CertificateID) >= 0 {
+		return store.ErrConflict
+	}
+	item.CertImageQuarantined = ""
+	item.ArchivedAt, item.Restricted = nil, ""
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, item Certificate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(item.UserID, item.CertificateID); i >= 0 {
+		item.CertImageQuarantined = r.items[i].CertImageQuarantined
+		item.ArchivedAt, item.Restricted = r.items[i].ArchivedAt, r.items[i].Restricted
+		r.items[i] = item
+		return nil
+	}
+	item.ArchivedAt, item.Restricted = nil, ""
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *MemoryRepository) SetArchived(ctx context.Context, userID, certificateID string, archivedAt *time.Time, restricted string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, certificateID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	if archivedAt == nil {
+		restricted = ""
+	}
+	r.items[i].ArchivedAt, r.items[i].Restricted = archivedAt, restricted
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, userID, certificateID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, certificateID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.items = append(r.items[:i], r.items[i+1:]...)
+	delete(r.images, userID+"/"+certificateID)
+	return nil
+}
+
+func (r *MemoryRepository) SetCertImage(ctx context.Context, userID, certificateID string, image []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(userID, certificateID); i >= 0 {
+		r.items[i].CertImageQuarantined = ""
+	} else {
+		r.items = append(r.items, Certificate{UserID: userID, CertificateID: certificateID})
+	}
+	r.images[userID+"/"+certificateID] = image
+	return nil
+}
+
+func (r *MemoryRepository) GetCertImage(ctx context.Context, userID, certificateID string) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	image, ok := r.images[userID+"/"+certificateID]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return image, nil
+}
+
+func (r *MemoryRepository) QuarantineCertImage(ctx context.Context, userID, certificateID, threat string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, certificateID)
+	if i < 0 {
+		return nil
+	}
+	r.items[i].CertImageQuarantined = threat
+	delete(r.images, userID+"/"+certificateID)
+	return nil
+}
@@ -0,0 +1,154 @@
+package certificates
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores certificates in the certificates collection
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{collection: db.Collection("certificates")}
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string) ([]Certificate, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []Certificate
+	for cursor.Next(ctx) {
+		var item Certificate
+		if err := cursor.Decode(&item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, cursor.Err()
+}
+
+func (r *MongoRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Certificate, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []Certificate
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *MongoRepository) ListEnding(ctx context.Context, from, to string) ([]Certificate, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"end": bson.M{"$gte": from, "$lt": to}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []Certificate
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *MongoRepository) ListUnarchived(ctx context.Context, before string) ([]Certificate, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"end": bson.M{"$gt": "", "$lt": before}, "archived_at": nil})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []Certificate
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *MongoRepository) Get(ctx context.Context, userID, certificateID string) (Certificate, error) {
+	var item Certificate
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "certificate_id": certificateID}).Decode(&item)
+	return item, store.MongoErr(err)
+}
+
+func (r *MongoRepository) Create(ctx context.Context, item Certificate) error {
+	// The quarantine is only set by QuarantineCertImage, and archiving by SetArchived
+	item.CertImageQuarantined = ""
+	item.ArchivedAt, item.Restricted = nil, ""
+	_, err := r.collection.InsertOne(ctx, item)
+	return err
+}
+
+func (r *MongoRepository) Save(ctx context.Context, item Certificate) error {
+	// The omitted quarantine and archiving are left as they are
+	item.CertImageQuarantined = ""
+	item.ArchivedAt, item.Restricted = nil, ""
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": item.UserID, "certificate_id": item.CertificateID}, bson.M{"$set": item}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) SetArchived(ctx context.Context, userID, certificateID string, archivedAt *time.Time, restricted string) error {
+	update := bson.M{"$unset": bson.M{"archived_at": "", "restricted": ""}}
+	if archivedAt != nil {
+		update = bson.M{"$set": bson.M{"archived_at": archivedAt, "restricted": restricted}}
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"user_id": userID, "certificate_id": certificateID}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, userID, certificateID string) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID, "certificate_id": certificateID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) SetCertImage(ctx context.Context, userID, certificateID string, image []byte) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": userID, "certificate_id": certificateID}, bson.M{"$set": bson.M{"cert_image": image}, "$unset": bson.M{"cert_image_quarantined": ""}}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) GetCertImage(ctx context.Context, userID, certificateID string) ([]byte, error) {
+	var doc struct {
+		CertImage []byte `bson:"cert_image"`
+	}
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "certificate_id": certificateID}, options.FindOne().SetProjection(bson.M{"cert_image": 1})).Decode(&doc)
+	if err != nil {
+		return nil, store.MongoErr(err)
+	}
+	if doc.CertImage == nil {
+		return nil, store.ErrNotFound
+	}
+	return doc.CertImage, nil
+}
+
+func (r *MongoRepository) QuarantineCertImage(ctx context.Context, userID, certificateID, threat string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": userID, "certificate_id": certificateID}, bson.M{"$set": bson.M{"cert_image_quarantined": threat}, "$unset": bson.M{"cert_image": ""}})
+	return err
+}
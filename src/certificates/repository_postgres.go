@@ -0,0 +1,139 @@
+package certificates
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const certificatesColumns = "user_id, certificate_id, title, institution, start_date, end_date, description, visibility"
+
+// PostgresRepository stores certificates in the certificates table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string) ([]Certificate, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+certificatesColumns+", cert_image_quarantined, archived_at, restricted FROM certificates WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanCertificate)
+}
+
+func (r *PostgresRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Certificate, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+certificatesColumns+", cert_image_quarantined, archived_at, restricted FROM certificates WHERE user_id = ANY($1) ORDER BY created_at", userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanCertificate)
+}
+
+func (r *PostgresRepository) ListEnding(ctx context.Context, from, to string) ([]Certificate, error) {
+	// Compared byte by byte so partial dates sort as they do in the other stores, whatever the collation
+	rows, err := r.pool.Query(ctx, "SELECT "+certificatesColumns+`, cert_image_quarantined, archived_at, restricted FROM certificates
+		WHERE end_date COLLATE "C" >= $1 AND end_date COLLATE "C" < $2 ORDER BY created_at`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanCertificate)
+}
+
+func (r *PostgresRepository) ListUnarchived(ctx context.Context, before string) ([]Certificate, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+certificatesColumns+`, cert_image_quarantined, archived_at, restricted FROM certificates
+		WHERE end_date <> '' AND end_date COLLATE "C" < $1 AND archived_at IS NULL ORDER BY created_at`, before)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanCertificate)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, userID, certificateID string) (Certificate, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+certificatesColumns+", cert_image_quarantined, archived_at, restricted FROM certificates WHERE user_id = $1 AND certificate_id = $2", userID, certificateID)
+	if err != nil {
+		return Certificate{}, err
+	}
+	item, err := pgx.CollectExactlyOneRow(rows, scanCertificate)
+	return item, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, item Certificate) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO certificates ("+certificatesColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		item.UserID, item.CertificateID, item.Title, item.Institution, item.Start, item.End, item.Description, item.Visibility)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, item Certificate) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO certificates ("+certificatesColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8) "+
+		"ON CONFLICT (user_id, certificate_id) DO UPDATE SET title = EXCLUDED.title, institution = EXCLUDED.institution, start_date = EXCLUDED.start_date, end_date = EXCLUDED.end_date, description = EXCLUDED.description, visibility = EXCLUDED.visibility",
+		item.UserID, item.CertificateID, item.Title, item.Institution, item.Start, item.End, item.Description, item.Visibility)
+	return err
+}
+
+func (r *PostgresRepository) SetArchived(ctx context.Context, userID, certificateID string, archivedAt *time.Time, restricted string) error {
+	if archivedAt == nil {
+		restricted = ""
+	}
+	tag, err := r.pool.Exec(ctx, "UPDATE certificates SET archived_at = $3, restricted = $4 WHERE user_id = $1 AND certificate_id = $2",
+		userID, certificateID, archivedAt, restricted)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, userID, certificateID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM certificates WHERE user_id = $1 AND certificate_id = $2", userID, certificateID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) SetCertImage(ctx context.Context, userID, certificateID string, image []byte) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO certificates (user_id, certificate_id, cert_image) VALUES ($1, $2, $3) "+
+		"ON CONFLICT (user_id, certificate_id) DO UPDATE SET cert_image = EXCLUDED.cert_image, cert_image_quarantined = ''",
+		userID, certificateID, image)
+	return err
+}
+
+func (r *PostgresRepository) GetCertImage(ctx context.Context, userID, certificateID string) ([]byte, error) {
+	var image []byte
+	err := r.pool.QueryRow(ctx, "SELECT cert_image FROM certificates WHERE user_id = $1 AND certificate_id = $2", userID, certificateID).Scan(&image)
+	if err != nil {
+		return nil, store.PostgresErr(err)
+	}
+	if image == nil {
+		return nil, store.ErrNotFound
+	}
+	return image, nil
+}
+
+func (r *PostgresRepository) QuarantineCertImage(ctx context.Context, userID, certificateID, threat string) error {
+	_, err := r.pool.Exec(ctx, "UPDATE certificates SET cert_image = NULL, cert_image_quarantined = $3 WHERE user_id = $1 AND certificate_id = $2",
+		userID, certificateID, threat)
+	return err
+}
+
+// scanCertificate reads a row selected with certificatesColumns, cert_image_quarantined, archived_at and
+// restricted
+func scanCertificate(row pgx.CollectableRow) (Certificate, error) {
+	var item Certificate
+	err := row.Scan(&item.UserID, &item.CertificateID, &item.Title, &item.Institution, &item.Start, &item.End, &item.Description, &item.Visibility, &item.CertImageQuarantined, &item.ArchivedAt, &item.Restricted)
+	return item, err
+}
@@ -0,0 +1,30 @@
+package certificates
+
+import (
+	"context"
+
+	"profile-api/sanitize"
+)
+
+// SanitizedRepository cleans the user-supplied text of certificates before storing them
+type SanitizedRepository struct {
+	Repository
+}
+
+// NewSanitizedRepository wraps the repository to clean certificates with the configured sanitize policies
+func NewSanitizedRepository(r Repository) *SanitizedRepository {
+	return &SanitizedRepository{Repository: r}
+}
+
+func (r *SanitizedRepository) Create(ctx context.Context, item Certificate) error {
+	return r.Repository.Create(ctx, sanitized(item))
+}
+
+func (r *SanitizedRepository) Save(ctx context.Context, item Certificate) error {
+	return r.Repository.Save(ctx, sanitized(item))
+}
+
+func sanitized(item Certificate) Certificate {
+	item.Description = sanitize.Field(sanitize.CertificateDescription, item.Description)
+	return item
+}
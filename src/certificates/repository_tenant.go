@@ -0,0 +1,66 @@
+package certificates
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID string) ([]Certificate, error) {
+	return r.repos.For(ctx).List(ctx, userID)
+}
+
+func (r *TenantRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Certificate, error) {
+	return r.repos.For(ctx).ListByUsers(ctx, userIDs)
+}
+
+func (r *TenantRepository) ListEnding(ctx context.Context, from, to string) ([]Certificate, error) {
+	return r.repos.For(ctx).ListEnding(ctx, from, to)
+}
+
+func (r *TenantRepository) ListUnarchived(ctx context.Context, before string) ([]Certificate, error) {
+	return r.repos.For(ctx).ListUnarchived(ctx, before)
+}
+
+func (r *TenantRepository) Get(ctx context.Context, userID, certificateID string) (Certificate, error) {
+	return r.repos.For(ctx).Get(ctx, userID, certificateID)
+}
+
+func (r *TenantRepository) Create(ctx context.Context, item Certificate) error {
+	return r.repos.For(ctx).Create(ctx, item)
+}
+
+func (r *TenantRepository) Save(ctx context.Context, item Certificate) error {
+	return r.repos.For(ctx).Save(ctx, item)
+}
+
+func (r *TenantRepository) SetArchived(ctx context.Context, userID, certificateID string, archivedAt *time.Time, restricted string) error {
+	return r.repos.For(ctx).SetArchived(ctx, userID, certificateID, archivedAt, restricted)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, userID, certificateID string) error {
+	return r.repos.For(ctx).Delete(ctx, userID, certificateID)
+}
+
+func (r *TenantRepository) SetCertImage(ctx context.Context, userID, certificateID string, image []byte) error {
+	return r.repos.For(ctx).SetCertImage(ctx, userID, certificateID, image)
+}
+
+func (r *TenantRepository) GetCertImage(ctx context.Context, userID, certificateID string) ([]byte, error) {
+	return r.repos.For(ctx).GetCertImage(ctx, userID, certificateID)
+}
+
+func (r *TenantRepository) QuarantineCertImage(ctx context.Context, userID, certificateID, threat string) error {
+	return r.repos.For(ctx).QuarantineCertImage(ctx, userID, certificateID, threat)
+}
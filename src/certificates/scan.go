@@ -0,0 +1,30 @@
+package certificates
+
+import (
+	"context"
+
+	"profile-api/scan"
+)
+
+// ScanKind is the kind of upload certificate images of certificates are scanned as
+const ScanKind = "certificate-image"
+
+// scanTarget loads certificate images for scanning, and removes infected ones
+type scanTarget struct {
+	repo Repository
+}
+
+// NewScanTarget creates the target scanning the certificate images of certificates
+func NewScanTarget(r Repository) scan.Target {
+	return &scanTarget{repo: r}
+}
+
+func (t *scanTarget) Load(ctx context.Context, upload scan.Upload) ([]byte, error) {
+	return t.repo.GetCertImage(ctx, upload.UserID, upload.ItemID)
+}
+
+// Quarantine removes the image, as certificate images are kept in the database rather than a store to move
+// them aside in
+func (t *scanTarget) Quarantine(ctx context.Context, upload scan.Upload, threat string) error {
+	return t.repo.QuarantineCertImage(ctx, upload.UserID, upload.ItemID, threat)
+}
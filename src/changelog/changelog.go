@@ -0,0 +1,158 @@
+// Package changelog keeps an append-only log of the changes made to each user's profile data, so external
+// systems, such as an HR sync, can consume the changes since they last looked instead of polling every
+// resource. Events are appended from the audit log, so they are recorded however a change is made, and are
+// numbered per user in the order the changes were made. A consumer remembers the number of the last event it
+// saw and asks for those after it.
+//
+// Only the profile, its images and the CV sections and journal are logged, not the account or its settings.
+// A user's log is removed along with their account.
+package changelog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"profile-api/apierror"
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+
+	// maxAttempts is how many times appending an event is tried when concurrent changes race for its number
+	maxAttempts = 5
+)
+
+// resources are the audit log resources making up a user's profile data
+var resources = []string{
+	"profile", "image", "experience", "qualification", "cert_image", "certificate", "award", "skill", "language", "journal",
+}
+
+var repo Repository
+
+// Configure sets where the change log is stored and starts appending the changes recorded in the audit log
+func Configure(r Repository) {
+	repo = r
+	audit.Subscribe(record)
+}
+
+// record appends a change to the profile data to its owner's log, and removes the log of deleted users
+func record(ctx context.Context, entry audit.Entry) {
+	// The change has been made, so it is logged even when the client has gone away
+	ctx, cancel := utils.WithOperationTimeout(context.WithoutCancel(ctx))
+	defer cancel()
+
+	if entry.Resource == "user" && entry.Action == audit.ActionDelete {
+		if err := repo.DeleteUser(ctx, entry.UserID); err != nil {
+			slog.ErrorContext(ctx, "Could not remove the change log of a deleted user", "user_id", entry.UserID, "error", err)
+		}
+		return
+	}
+	if !slices.Contains(resources, entry.Resource) {
+		return
+	}
+	if err := appendEvent(ctx, entry); err != nil {
+		slog.ErrorContext(ctx, "Could not append change to change log", "resource", entry.Resource, "resource_id", entry.ResourceID, "error", err)
+	}
+}
+
+// appendEvent appends the change as the user's next event, trying again with the following number when a
+// concurrent change took it
+func appendEvent(ctx context.Context, entry audit.Entry) error {
+	event := Event{
+		ID:         utils.GenerateID(),
+		UserID:     entry.UserID,
+		Action:     entry.Action,
+		Resource:   entry.Resource,
+		ResourceID: entry.ResourceID,
+		Changes:    entry.Changes,
+		Time:       entry.Time,
+	}
+	for range maxAttempts {
+		last, err := repo.Last(ctx, entry.UserID)
+		if err != nil {
+			return err
+		}
+		event.Seq = last + 1
+		err = repo.Append(ctx, event)
+		if !errors.Is(err, store.ErrConflict) {
+			return err
+		}
+	}
+	return fmt.Errorf("gave up after %d concurrent changes", maxAttempts)
+}
+
+// GetChanges returns a page of the changes made to a user's profile data
+//
+//	@Summary		List changes to a user's profile data
+//	@Description	Returns the changes made to the user's profile, CV sections and journal after the event numbered after, oldest first. Each user's events are numbered from 1 in the order the changes were made. Pass the next cursor of a page as after to get the following events; while more is set there are further events to fetch. Long values in the changes are truncated as in the audit log. Only the user and admins may read their changes.
+//	@Tags			changes
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Param			after	query		int		false	"Number of the last event already seen (default 0, the start of the log)"
+//	@Param			limit	query		int		false	"Maximum number of events to return (default 100, max 1000)"
+//	@Success		200		{object}	Page
+//	@Failure		400		{object}	apierror.Response	"Invalid cursor"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the user or an admin"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve changes"
+//	@Security		BearerAuth
+//	@Router			/changes/{userid} [get]
+func GetChanges(c *gin.Context) {
+	userID := c.Param("userid")
+	user := c.MustGet("user").(auth.User)
+	if user.ID != userID && !user.Admin {
+		apierror.Abort(c, apierror.Forbidden("Only the user and admins can read their changes"))
+		return
+	}
+	var after int64
+	if a := c.Query("after"); a != "" {
+		var err error
+		after, err = strconv.ParseInt(a, 10, 64)
+		if err != nil || after < 0 {
+			apierror.Abort(c, apierror.BadRequest("after must be the number of an event, or 0"))
+			return
+		}
+	}
+	limit := defaultPageSize
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, maxPageSize)
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	// One more event than asked for tells whether there are more
+	events, err := repo.List(ctx, userID, after, limit+1)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve changes"))
+		return
+	}
+
+	page := Page{Events: events, Next: after}
+	if len(events) > limit {
+		page.Events, page.More = events[:limit], true
+	}
+	if page.Events == nil {
+		page.Events = []Event{}
+	}
+	if n := len(page.Events); n > 0 {
+		page.Next = page.Events[n-1].Seq
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// InitializeRoutes registers the change log endpoint. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup, users auth.Repository) {
+	router.GET("/:userid", auth.AuthMiddleware(users, true), GetChanges)
+}
@@ -0,0 +1,32 @@
+package changelog
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event records a change to a user's profile data. A user's events are numbered in the order the changes
+// were made, without gaps, so consumers can ask for those after the last one they saw.
+type Event struct {
+	ID     string `bson:"_id" json:"id"`
+	UserID string `bson:"user_id" json:"userID"`
+	// Seq is the number of the event among the user's events, starting at 1
+	Seq int64 `bson:"seq" json:"seq"`
+	// Action is create, update or delete
+	Action     string `bson:"action" json:"action"`
+	Resource   string `bson:"resource" json:"resource"`
+	ResourceID string `bson:"resource_id" json:"resourceID"`
+	// Changes maps each changed field to its values before and after the change, as in the audit log
+	Changes json.RawMessage `bson:"changes,omitempty" json:"changes,omitempty" swaggertype:"object"`
+	Time    time.Time       `bson:"time" json:"time"`
+}
+
+// Page is a page of a user's events, oldest first
+type Page struct {
+	Events []Event `json:"events"`
+	// Next is the cursor to ask for the following events with, the number of the last event of the page or
+	// the cursor asked with when there are no new events
+	Next int64 `json:"next"`
+	// More is set when there are events after the page
+	More bool `json:"more"`
+}
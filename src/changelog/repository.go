@@ -0,0 +1,15 @@
+package changelog
+
+import "context"
+
+// Repository stores the change log of each user
+type Repository interface {
+	// Append stores an event, or returns store.ErrConflict when the user already has an event numbered Seq
+	Append(ctx context.Context, event Event) error
+	// Last returns the number of the user's last event, 0 when they have none
+	Last(ctx context.Context, userID string) (int64, error)
+	// List returns the user's events numbered after the given one, oldest first, at most limit of them
+	List(ctx context.Context, userID string, after int64, limit int) ([]Event, error)
+	// DeleteUser removes the user's events
+	DeleteUser(ctx context.Context, userID string) error
+}
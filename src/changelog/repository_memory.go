@@ -0,0 +1,57 @@
+package changelog
+
+import (
+	"context"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps events in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu     sync.Mutex
+	events map[string][]Event
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{events: map[string][]Event{}}
+}
+
+func (r *MemoryRepository) Append(ctx context.Context, event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := r.events[event.UserID]
+	if event.Seq != int64(len(events))+1 {
+		return store.ErrConflict
+	}
+	r.events[event.UserID] = append(events, event)
+	return nil
+}
+
+func (r *MemoryRepository) Last(ctx context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return int64(len(r.events[userID])), nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string, after int64, limit int) ([]Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := r.events[userID]
+	if after >= int64(len(events)) {
+		return nil, nil
+	}
+	events = events[max(after, 0):]
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return append([]Event(nil), events...), nil
+}
+
+func (r *MemoryRepository) DeleteUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.events, userID)
+	return nil
+}
@@ -0,0 +1,57 @@
+package changelog
+
+import (
+	"context"
+	"errors"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores events in the changelog collection, whose unique index on the user and number
+// turns a lost race for a number into a duplicate key error
+type MongoRepository struct {
+	events *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{events: db.Collection("changelog")}
+}
+
+func (r *MongoRepository) Append(ctx context.Context, event Event) error {
+	_, err := r.events.InsertOne(ctx, event)
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	}
+	return err
+}
+
+func (r *MongoRepository) Last(ctx context.Context, userID string) (int64, error) {
+	var last Event
+	err := r.events.FindOne(ctx, bson.M{"user_id": userID},
+		options.FindOne().SetSort(bson.D{{Key: "seq", Value: -1}}).SetProjection(bson.M{"seq": 1})).Decode(&last)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	return last.Seq, err
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string, after int64, limit int) ([]Event, error) {
+	cursor, err := r.events.Find(ctx, bson.M{"user_id": userID, "seq": bson.M{"$gt": after}},
+		options.Find().SetSort(bson.D{{Key: "seq", Value: 1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	var events []Event
+	err = cursor.All(ctx, &events)
+	return events, err
+}
+
+func (r *MongoRepository) DeleteUser(ctx context.Context, userID string) error {
+	_, err := r.events.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
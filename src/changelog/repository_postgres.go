@@ -0,0 +1,52 @@
+package changelog
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository stores events in the changelog table, whose unique constraint on the user and number
+// turns a lost race for a number into a unique violation
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Append(ctx context.Context, event Event) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO changelog (id, user_id, seq, action, resource, resource_id, changes, time)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		event.ID, event.UserID, event.Seq, event.Action, event.Resource, event.ResourceID, event.Changes, event.Time)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Last(ctx context.Context, userID string) (int64, error) {
+	var last int64
+	err := r.pool.QueryRow(ctx, "SELECT COALESCE(MAX(seq), 0) FROM changelog WHERE user_id = $1", userID).Scan(&last)
+	return last, err
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string, after int64, limit int) ([]Event, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id, user_id, seq, action, resource, resource_id, changes, time FROM changelog
+		WHERE user_id = $1 AND seq > $2 ORDER BY seq LIMIT $3`, userID, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Event, error) {
+		var e Event
+		err := row.Scan(&e.ID, &e.UserID, &e.Seq, &e.Action, &e.Resource, &e.ResourceID, &e.Changes, &e.Time)
+		return e, err
+	})
+}
+
+func (r *PostgresRepository) DeleteUser(ctx context.Context, userID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM changelog WHERE user_id = $1", userID)
+	return err
+}
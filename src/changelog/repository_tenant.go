@@ -0,0 +1,33 @@
+package changelog
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Append(ctx context.Context, event Event) error {
+	return r.repos.For(ctx).Append(ctx, event)
+}
+
+func (r *TenantRepository) Last(ctx context.Context, userID string) (int64, error) {
+	return r.repos.For(ctx).Last(ctx, userID)
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID string, after int64, limit int) ([]Event, error) {
+	return r.repos.For(ctx).List(ctx, userID, after, limit)
+}
+
+func (r *TenantRepository) DeleteUser(ctx context.Context, userID string) error {
+	return r.repos.For(ctx).DeleteUser(ctx, userID)
+}
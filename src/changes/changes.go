@@ -0,0 +1,236 @@
+// Package changes follows the writes to users' documents through Mongo change streams, so every replica
+// learns of every change whichever replica, or tool, made it. Each replica passes the changes to its
+// handlers, such as cache invalidation, and notifies its own connected clients, while the first replica to
+// claim a change forwards it to the event listeners, such as webhooks, so they see it once.
+//
+// Changes made while no replica is watching are not replayed.
+package changes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"profile-api/events"
+	"profile-api/tenant"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Operations of a change
+const (
+	OperationCreated = "created"
+	OperationUpdated = "updated"
+	OperationDeleted = "deleted"
+)
+
+// retryDelay is how long to wait before reopening a change stream that failed
+const retryDelay = 5 * time.Second
+
+// claimsCollection records which replica forwards each change to the event listeners
+const claimsCollection = "change_claims"
+
+// Change is a write to one of a user's documents
+type Change struct {
+	// Resource is the kind of document, named as in the audit log
+	Resource  string    `json:"resource"`
+	Operation string    `json:"operation"`
+	ID        string    `json:"id"`
+	Time      time.Time `json:"time"`
+	// UserID is empty for deletions when Mongo keeps no copy of deleted documents
+	UserID string `json:"-"`
+	Tenant string `json:"-"`
+}
+
+// Handler is called with every change seen by this replica
+type Handler func(ctx context.Context, change Change)
+
+var handlers []Handler
+
+// Subscribe registers a handler for every change seen by this replica. Handlers are called synchronously,
+// with a context carrying the tenant of the change, and must be registered before watching starts.
+func Subscribe(h Handler) {
+	handlers = append(handlers, h)
+}
+
+// collection is a watched collection, holding documents of one resource
+type collection struct {
+	resource string
+	// idField is the field holding the document's ID
+	idField string
+}
+
+// watched are the collections whose changes are followed
+var watched = map[string]collection{
+	"profiles":        {resource: "profile", idField: "user_id"},
+	"experience":      {resource: "experience", idField: "experience_id"},
+	"qualifications":  {resource: "qualification", idField: "qualification_id"},
+	"certificates":    {resource: "certificate", idField: "certificate_id"},
+	"awards":          {resource: "award", idField: "award_id"},
+	"skills":          {resource: "skill", idField: "skill_id"},
+	"languages":       {resource: "language", idField: "language_id"},
+	"journal":         {resource: "journal", idField: "journal_id"},
+	"recommendations": {resource: "recommendation", idField: "_id"},
+}
+
+// streamEvent is the part of a change stream event that is read
+type streamEvent struct {
+	OperationType string `bson:"operationType"`
+	Namespace     struct {
+		Collection string `bson:"coll"`
+	} `bson:"ns"`
+	ClusterTime              primitive.Timestamp `bson:"clusterTime"`
+	FullDocument             bson.M              `bson:"fullDocument"`
+	FullDocumentBeforeChange bson.M              `bson:"fullDocumentBeforeChange"`
+}
+
+// Watch follows the changes to the tenant's database until the context is cancelled, reopening the
+// stream where it left off after failures. Change streams need a replica set.
+func Watch(ctx context.Context, db *mongo.Database, tenantID string) {
+	ctx = tenant.WithID(ctx, tenantID)
+	preImages := enablePreImages(ctx, db)
+	slog.InfoContext(ctx, "Watching database changes", "database", db.Name(), "deletions_attributed", preImages)
+
+	var resumeToken bson.Raw
+	for {
+		err := watch(ctx, db, preImages, &resumeToken)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.ErrorContext(ctx, "Change stream failed, reopening", "database", db.Name(), "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// watch handles the changes of one stream until it fails, keeping the token to resume after the last
+// change handled
+func watch(ctx context.Context, db *mongo.Database, preImages bool, resumeToken *bson.Raw) error {
+	names := make(bson.A, 0, len(watched))
+	for name := range watched {
+		names = append(names, name)
+	}
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{
+		"ns.coll":       bson.M{"$in": names},
+		"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}},
+	}}}}
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if preImages {
+		opts.SetFullDocumentBeforeChange(options.WhenAvailable)
+	}
+	if *resumeToken != nil {
+		opts.SetResumeAfter(*resumeToken)
+	}
+
+	stream, err := db.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.WithoutCancel(ctx))
+	for stream.Next(ctx) {
+		var event streamEvent
+		if err := stream.Decode(&event); err != nil {
+			slog.ErrorContext(ctx, "Could not decode change", "database", db.Name(), "error", err)
+		} else {
+			handle(ctx, db, event, stream.ResumeToken())
+		}
+		*resumeToken = stream.ResumeToken()
+	}
+	return stream.Err()
+}
+
+// handle passes a change to the handlers and this replica's clients, and forwards it to the event
+// listeners if this replica claims it first
+func handle(ctx context.Context, db *mongo.Database, event streamEvent, token bson.Raw) {
+	coll := watched[event.Namespace.Collection]
+	doc := event.FullDocument
+	if doc == nil {
+		doc = event.FullDocumentBeforeChange
+	}
+	change := Change{
+		Resource:  coll.resource,
+		Operation: OperationUpdated,
+		ID:        fieldString(doc, coll.idField),
+		Time:      time.Unix(int64(event.ClusterTime.T), 0),
+		UserID:    fieldString(doc, "user_id"),
+		Tenant:    tenant.ID(ctx),
+	}
+	switch event.OperationType {
+	case "insert":
+		change.Operation = OperationCreated
+	case "delete":
+		change.Operation = OperationDeleted
+	}
+
+	for _, h := range handlers {
+		h(ctx, change)
+	}
+	// Events go to the user the document belongs to, so changes that can't be attributed are not sent
+	if change.UserID == "" {
+		return
+	}
+	events.Notify(ctx, change.UserID, events.TypeDocumentChanged, change)
+	claimed, err := claim(ctx, db, token)
+	if err != nil {
+		slog.ErrorContext(ctx, "Could not claim change", "resource", change.Resource, "id", change.ID, "error", err)
+		return
+	}
+	if claimed {
+		events.Dispatch(ctx, change.UserID, events.TypeDocumentChanged, change)
+	}
+}
+
+// claim records that this replica forwards the change with the resume token, reporting false when
+// another replica already does
+func claim(ctx context.Context, db *mongo.Database, token bson.Raw) (bool, error) {
+	id := sha256.Sum256(token)
+	_, err := db.Collection(claimsCollection).InsertOne(ctx, bson.M{"_id": hex.EncodeToString(id[:]), "claimed_at": time.Now()})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// enablePreImages asks Mongo to keep a copy of the watched documents as they were before each change, so
+// deletions can be attributed to their user. It reports whether every watched collection keeps them,
+// which needs Mongo 6 or later.
+func enablePreImages(ctx context.Context, db *mongo.Database) bool {
+	for name := range watched {
+		err := db.CreateCollection(ctx, name)
+		var cmdErr mongo.CommandError
+		if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Name == "NamespaceExists") {
+			slog.WarnContext(ctx, "Could not create watched collection", "collection", name, "error", err)
+			return false
+		}
+		cmd := bson.D{{Key: "collMod", Value: name}, {Key: "changeStreamPreAndPostImages", Value: bson.M{"enabled": true}}}
+		if err := db.RunCommand(ctx, cmd).Err(); err != nil {
+			slog.WarnContext(ctx, "Could not keep documents from before changes, deletions will not be attributed to their user", "collection", name, "error", err)
+			return false
+		}
+	}
+	return true
+}
+
+// fieldString returns a field of the document as a string
+func fieldString(doc bson.M, field string) string {
+	switch v := doc[field].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case primitive.ObjectID:
+		return v.Hex()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,174 @@
+// Package cli implements the admin commands of the profile-api binary, run as `profile-api admin <command>`
+// against the storage in the server's configuration, for operations with no API such as creating the first
+// admin or moving a user between deployments.
+//
+// Commands change data through the same repositories as the API, so their changes are recorded in the
+// audit log, as made by the system, and reach the search index through the job queue once a server picks
+// the jobs up. In multi-tenant deployments commands working on users need the tenant they belong to.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+
+	"profile-api/config"
+	"profile-api/server"
+	"profile-api/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrUsage is returned when the command line is invalid, after the usage has been printed
+var ErrUsage = errors.New("invalid usage")
+
+// errHelp stops a command whose help was asked for
+var errHelp = errors.New("help requested")
+
+// Output written by commands, replaced by callers wanting to capture it
+var (
+	Stdin  io.Reader = os.Stdin
+	Stdout io.Writer = os.Stdout
+	Stderr io.Writer = os.Stderr
+)
+
+// command is an admin command, which parses its own flags
+type command struct {
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
+
+var commands map[string]command
+
+// Commands are registered in init as they look up their own summaries
+func init() {
+	commands = map[string]command{
+		"create-admin":   {"Create a user with the admin role", createAdmin},
+		"reset-password": {"Set a new password for a user", resetPassword},
+		"reindex":        {"Rebuild the search index", reindex},
+		"migrate":        {"Apply, roll back or list database migrations", migrate},
+		"export-user":    {"Write a user and everything they own as JSON", exportUser},
+		"import-user":    {"Create a user from the JSON written by export-user", importUser},
+		"check-config":   {"Validate the configuration and connect to its storage", checkConfig},
+		"demo-reset":     {"Delete the demo users and seed them again", demoReset},
+	}
+}
+
+// Run runs the admin command named by the first argument
+func Run(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage()
+		if len(args) == 0 {
+			return ErrUsage
+		}
+		return nil
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(Stderr, "Unknown command %q\n\n", args[0])
+		usage()
+		return ErrUsage
+	}
+
+	// Keep stdout for the command's output, such as an exported user
+	slog.SetDefault(slog.New(slog.NewTextHandler(Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	gin.SetMode(gin.ReleaseMode)
+	if err := cmd.run(ctx, args[1:]); !errors.Is(err, errHelp) {
+		return err
+	}
+	return nil
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(Stderr, "Usage: profile-api admin <command> [flags]")
+	fmt.Fprintln(Stderr, "\nCommands:")
+	for _, name := range names {
+		fmt.Fprintf(Stderr, "  %-16s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(Stderr, "\nRun profile-api admin <command> -h for the command's flags. The configuration is read as by the server.")
+}
+
+// newFlagSet returns the flag set of the command
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("admin "+name, flag.ContinueOnError)
+	fs.SetOutput(Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(Stderr, "Usage: profile-api admin %s [flags]\n\n%s\n\nFlags:\n", name, commands[name].summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the command's flags, reporting invalid ones as ErrUsage
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return errHelp
+		}
+		return ErrUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(Stderr, "Unexpected argument %q\n", fs.Arg(0))
+		fs.Usage()
+		return ErrUsage
+	}
+	return nil
+}
+
+// session is the configured storage commands run against
+type session struct {
+	cfg   *config.Config
+	deps  *server.Deps
+	repos server.Repositories
+}
+
+// open loads the configuration, connects to its storage and configures every module as the server does
+func open(ctx context.Context) (*session, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	deps, err := server.Open(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := server.New(cfg, deps); err != nil {
+		deps.Close(ctx)
+		return nil, err
+	}
+	return &session{cfg: cfg, deps: deps, repos: deps.Repos.Audited().Sanitized()}, nil
+}
+
+func (s *session) close(ctx context.Context) {
+	s.deps.Close(context.WithoutCancel(ctx))
+}
+
+// forTenant returns a context for the tenant with the ID, which is required in multi-tenant deployments
+func forTenant(ctx context.Context, id string) (context.Context, error) {
+	if !tenant.Enabled() {
+		if id != "" {
+			return nil, errors.New("-tenant is only used in multi-tenant deployments")
+		}
+		return ctx, nil
+	}
+	if id == "" {
+		return nil, errors.New("-tenant is required in multi-tenant deployments")
+	}
+	for _, t := range tenant.All() {
+		if t.ID == id {
+			return tenant.WithID(ctx, id), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown tenant %q", id)
+}
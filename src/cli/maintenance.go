@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"profile-api/config"
+	"profile-api/demo"
+	"profile-api/search"
+	"profile-api/server"
+	"profile-api/store"
+	"profile-api/tenant"
+)
+
+// reindex rebuilds the search index of one tenant, or of every tenant
+func reindex(ctx context.Context, args []string) error {
+	fs := newFlagSet("reindex")
+	tenantID := fs.String("tenant", "", "tenant to reindex, every tenant when omitted")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	s, err := open(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.close(ctx)
+
+	task := tenant.Each(search.Reindex)
+	if *tenantID != "" {
+		ctx, err = forTenant(ctx, *tenantID)
+		if err != nil {
+			return err
+		}
+		task = search.Reindex
+	}
+	if err := task(ctx); err != nil {
+		return fmt.Errorf("could not rebuild search index: %w", err)
+	}
+	fmt.Fprintln(Stdout, "Rebuilt search index")
+	return nil
+}
+
+// migrationVersion matches a migration version or just its number
+var migrationVersion = regexp.MustCompile(`^[0-9]{4}(_[a-z0-9_]+)?$`)
+
+// migrate applies the pending migrations of the main database and every tenant's database, rolls them
+// back or lists them
+func migrate(ctx context.Context, args []string) error {
+	fs := newFlagSet("migrate")
+	status := fs.Bool("status", false, "list the migrations of each database instead of applying them")
+	down := fs.String("down", "", "roll back the migrations numbered after this `version`, 0000 for all of them")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if *down != "" && !migrationVersion.MatchString(*down) {
+		return fmt.Errorf("-down must be a migration version, such as 0012 or 0012_feature_flags")
+	}
+	if cfg.Storage == store.Memory {
+		fmt.Fprintln(Stdout, "memory storage has no schema migrations")
+		return nil
+	}
+	// Open the storage as it is, to migrate it here
+	cfg.Migrations.OnStart = "skip"
+	deps, err := server.Open(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer deps.Close(context.WithoutCancel(ctx))
+
+	switch {
+	case *status:
+	case *down != "":
+		if err := deps.Rollback(ctx, *down); err != nil {
+			return err
+		}
+	default:
+		if err := deps.Migrate(ctx); err != nil {
+			return err
+		}
+	}
+	return printMigrations(ctx, deps)
+}
+
+// printMigrations lists the migrations of each database
+func printMigrations(ctx context.Context, deps *server.Deps) error {
+	status, err := deps.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+	for _, db := range status {
+		fmt.Fprintf(Stdout, "%s database:\n", db.Database)
+		for _, m := range db.Migrations {
+			applied := "pending"
+			if m.AppliedAt != nil {
+				applied = "applied " + m.AppliedAt.Local().Format(time.DateTime)
+			}
+			reversible := ""
+			if !m.Reversible {
+				reversible = " (irreversible)"
+			}
+			fmt.Fprintf(Stdout, "  %-32s %s%s\n", m.Version, applied, reversible)
+		}
+	}
+	return nil
+}
+
+// checkConfig validates the configuration the server would start with and connects to its storage
+func checkConfig(ctx context.Context, args []string) error {
+	fs := newFlagSet("check-config")
+	offline := fs.Bool("offline", false, "only validate the configuration, without connecting to its storage")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	fmt.Fprintln(Stdout, "Configuration is valid")
+	if *offline {
+		return nil
+	}
+
+	// Report pending migrations rather than applying them
+	cfg.Migrations.OnStart = "skip"
+	deps, err := server.Open(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer deps.Close(context.WithoutCancel(ctx))
+	if _, err := server.New(cfg, deps); err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, "Connected to %s storage\n", cfg.Storage)
+
+	status, err := deps.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+	for _, db := range status {
+		if pending := store.Pending(db.Migrations); len(pending) > 0 {
+			fmt.Fprintf(Stdout, "%s database has %d pending migrations, run profile-api admin migrate\n", db.Database, len(pending))
+		}
+	}
+	return nil
+}
+
+// demoReset restores the demo users, discarding any changes made to their data
+func demoReset(ctx context.Context, args []string) error {
+	fs := newFlagSet("demo-reset")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	s, err := open(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.close(ctx)
+	if !demo.Enabled() {
+		return errors.New("demo data is not enabled, see demo.enabled")
+	}
+	if err := demo.Reset(ctx); err != nil {
+		return fmt.Errorf("could not reset demo data: %w", err)
+	}
+	fmt.Fprintln(Stdout, "Reset demo data")
+	return nil
+}
@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"profile-api/auth"
+	"profile-api/awards"
+	"profile-api/certificates"
+	"profile-api/experience"
+	"profile-api/journal"
+	"profile-api/languages"
+	"profile-api/profile"
+	"profile-api/qualifications"
+	"profile-api/skills"
+	"profile-api/store"
+)
+
+// exportVersion is the version of the export format written, bumped when it changes incompatibly
+const exportVersion = 1
+
+// Export is a user and everything they own. Certificate, qualification and award images are not included, nor
+// are the documents of a disabled module.
+type Export struct {
+	Version        int                            `json:"version"`
+	ExportedAt     time.Time                      `json:"exportedAt"`
+	User           ExportedUser                   `json:"user"`
+	Profile        *profile.Profile               `json:"profile,omitempty"`
+	Skills         []skills.Skill                 `json:"skills"`
+	Experience     []experience.Experience        `json:"experience"`
+	Qualifications []qualifications.Qualification `json:"qualifications"`
+	Certificates   []certificates.Certificate     `json:"certificates,omitempty"`
+	Awards         []awards.Award                 `json:"awards"`
+	Languages      []languages.Language           `json:"languages"`
+	Journals       []journal.JournalEntry         `json:"journals,omitempty"`
+}
+
+// ExportedUser is the account of an exported user. It holds their password hash, so they can log in after
+// an import, and must be kept as safe as the database.
+type ExportedUser struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Password  string    `json:"password"`
+	Admin     bool      `json:"admin"`
+	Disabled  bool      `json:"disabled"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// exportUser writes a user and everything they own as JSON, for import-user to restore
+func exportUser(ctx context.Context, args []string) error {
+	fs := newFlagSet("export-user")
+	email := fs.String("email", "", "email address of the user (required)")
+	output := fs.String("o", "", "file to write, standard output when omitted")
+	tenantID := fs.String("tenant", "", "tenant the user belongs to")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *email == "" {
+		fs.Usage()
+		return ErrUsage
+	}
+
+	s, err := open(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.close(ctx)
+	ctx, err = forTenant(ctx, *tenantID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.repos.Users.FindByEmail(ctx, *email)
+	if errors.Is(err, store.ErrNotFound) {
+		return fmt.Errorf("%s is not registered", *email)
+	}
+	if err != nil {
+		return fmt.Errorf("could not find user: %w", err)
+	}
+	export, err := s.export(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	w := Stdout
+	if *output != "" {
+		f, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(export); err != nil {
+		return fmt.Errorf("could not write export: %w", err)
+	}
+	if *output != "" {
+		fmt.Fprintf(Stdout, "Exported %s to %s\n", user.Email, *output)
+	}
+	return nil
+}
+
+// export reads everything the user owns
+func (s *session) export(ctx context.Context, user auth.User) (Export, error) {
+	export := Export{
+		Version:    exportVersion,
+		ExportedAt: time.Now().UTC(),
+		User: ExportedUser{
+			ID:        user.ID,
+			Name:      user.Name,
+			Email:     user.Email,
+			Password:  user.Password,
+			Admin:     user.Admin,
+			Disabled:  user.Disabled,
+			CreatedAt: user.CreatedAt,
+		},
+	}
+
+	p, err := s.repos.Profiles.Get(ctx, user.ID)
+	switch {
+	case err == nil:
+		export.Profile = &p
+	case !errors.Is(err, store.ErrNotFound):
+		return export, fmt.Errorf("could not read profile: %w", err)
+	}
+	if s.cfg.Modules.Skills {
+		if export.Skills, err = s.repos.Skills.List(ctx, user.ID); err != nil {
+			return export, fmt.Errorf("could not read skills: %w", err)
+		}
+	}
+	if s.cfg.Modules.Experience {
+		if export.Experience, err = s.repos.Experience.List(ctx, user.ID); err != nil {
+			return export, fmt.Errorf("could not read experience: %w", err)
+		}
+	}
+	if s.cfg.Modules.Qualifications {
+		if export.Qualifications, err = s.repos.Qualifications.List(ctx, user.ID); err != nil {
+			return export, fmt.Errorf("could not read qualifications: %w", err)
+		}
+	}
+	if s.cfg.Modules.Certificates {
+		if export.Certificates, err = s.repos.Certificates.List(ctx, user.ID); err != nil {
+			return export, fmt.Errorf("could not read certificates: %w", err)
+		}
+	}
+	if s.cfg.Modules.Awards {
+		if export.Awards, err = s.repos.Awards.List(ctx, user.ID); err != nil {
+			return export, fmt.Errorf("could not read awards: %w", err)
+		}
+	}
+	if s.cfg.Modules.Languages {
+		if export.Languages, err = s.repos.Languages.List(ctx, user.ID); err != nil {
+			return export, fmt.Errorf("could not read languages: %w", err)
+		}
+	}
+	if s.cfg.Modules.Journal {
+		if export.Journals, err = s.repos.Journals.List(ctx, journal.Filter{UserID: user.ID}); err != nil {
+			return export, fmt.Errorf("could not read journals: %w", err)
+		}
+	}
+	return export, nil
+}
+
+// importUser creates a user, with their ID, and everything they own from the JSON written by export-user
+func importUser(ctx context.Context, args []string) error {
+	fs := newFlagSet("import-user")
+	input := fs.String("i", "", "file to read, standard input when omitted")
+	tenantID := fs.String("tenant", "", "tenant to import the user into")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	r := Stdin
+	if *input != "" {
+		f, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	export, err := readExport(r)
+	if err != nil {
+		return err
+	}
+
+	s, err := open(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.close(ctx)
+	ctx, err = forTenant(ctx, *tenantID)
+	if err != nil {
+		return err
+	}
+	if err := s.importExport(ctx, export); err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, "Imported %s with ID %s\n", export.User.Email, export.User.ID)
+	return nil
+}
+
+// readExport decodes and checks an export
+func readExport(r io.Reader) (Export, error) {
+	var export Export
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return export, fmt.Errorf("could not read export: %w", err)
+	}
+	if export.Version != exportVersion {
+		return export, fmt.Errorf("unsupported export version %d", export.Version)
+	}
+	if export.User.ID == "" || export.User.Email == "" || export.User.Password == "" {
+		return export, errors.New("export has no user")
+	}
+	return export, nil
+}
+
+// importExport creates the exported user, failing when their ID or email address is already registered
+func (s *session) importExport(ctx context.Context, export Export) error {
+	u := export.User
+	if _, err := s.repos.Users.FindByID(ctx, u.ID); !errors.Is(err, store.ErrNotFound) {
+		if err == nil {
+			return fmt.Errorf("a user with ID %s already exists", u.ID)
+		}
+		return fmt.Errorf("could not check user existence: %w", err)
+	}
+	if _, err := s.repos.Users.FindByEmail(ctx, u.Email); !errors.Is(err, store.ErrNotFound) {
+		if err == nil {
+			return fmt.Errorf("%s is already registered", u.Email)
+		}
+		return fmt.Errorf("could not check email existence: %w", err)
+	}
+
+	err := s.repos.Users.Create(ctx, auth.User{
+		ID:        u.ID,
+		Name:      u.Name,
+		Email:     u.Email,
+		Password:  u.Password,
+		Admin:     u.Admin,
+		Disabled:  u.Disabled,
+		CreatedAt: u.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create user: %w", err)
+	}
+
+	// Everything imported belongs to the user, whatever the export says
+	if p := export.Profile; p != nil {
+		p.UserID = u.ID
+		if err := s.repos.Profiles.Save(ctx, *p); err != nil {
+			return fmt.Errorf("could not save profile: %w", err)
+		}
+	}
+	for _, item := range export.Skills {
+		item.UserID = u.ID
+		if err := s.repos.Skills.Create(ctx, item); err != nil {
+			return fmt.Errorf("could not create skill %s: %w", item.SkillID, err)
+		}
+	}
+	for _, item := range export.Experience {
+		item.UserID = u.ID
+		if err := s.repos.Experience.Create(ctx, item); err != nil {
+			return fmt.Errorf("could not create experience %s: %w", item.ExperienceID, err)
+		}
+	}
+	for _, item := range export.Qualifications {
+		item.UserID = u.ID
+		if err := s.repos.Qualifications.Create(ctx, item); err != nil {
+			return fmt.Errorf("could not create qualification %s: %w", item.QualificationID, err)
+		}
+	}
+	for _, item := range export.Certificates {
+		item.UserID = u.ID
+		if err := s.repos.Certificates.Create(ctx, item); err != nil {
+			return fmt.Errorf("could not create certificate %s: %w", item.CertificateID, err)
+		}
+	}
+	for _, item := range export.Awards {
+		item.UserID = u.ID
+		if err := s.repos.Awards.Create(ctx, item); err != nil {
+			return fmt.Errorf("could not create award %s: %w", item.AwardID, err)
+		}
+	}
+	for _, item := range export.Languages {
+		item.UserID = u.ID
+		if err := s.repos.Languages.Create(ctx, item); err != nil {
+			return fmt.Errorf("could not create language %s: %w", item.LanguageID, err)
+		}
+	}
+	for _, entry := range export.Journals {
+		entry.UserID = u.ID
+		if err := s.repos.Journals.Create(ctx, entry); err != nil {
+			return fmt.Errorf("could not create journal %s: %w", entry.JournalID, err)
+		}
+	}
+	return nil
+}
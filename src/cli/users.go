@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"profile-api/auth"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// createAdmin creates a user with the admin role, such as the first admin of a new deployment
+func createAdmin(ctx context.Context, args []string) error {
+	fs := newFlagSet("create-admin")
+	email := fs.String("email", "", "email address the admin logs in with (required)")
+	name := fs.String("name", "", "name of the admin (required)")
+	tenantID := fs.String("tenant", "", "tenant the admin belongs to")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *email == "" || *name == "" {
+		fs.Usage()
+		return ErrUsage
+	}
+
+	s, err := open(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.close(ctx)
+	ctx, err = forTenant(ctx, *tenantID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repos.Users.FindByEmail(ctx, *email)
+	if err == nil {
+		return fmt.Errorf("%s is already registered", *email)
+	}
+	if !errors.Is(err, store.ErrNotFound) {
+		return fmt.Errorf("could not check email existence: %w", err)
+	}
+	hashedPassword, err := readPassword()
+	if err != nil {
+		return err
+	}
+
+	user := auth.User{
+		ID:        primitive.NewObjectID().Hex(),
+		Name:      *name,
+		Email:     *email,
+		Password:  hashedPassword,
+		Admin:     true,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repos.Users.Create(ctx, user); err != nil {
+		return fmt.Errorf("could not create user: %w", err)
+	}
+	fmt.Fprintf(Stdout, "Created admin %s with ID %s\n", user.Email, user.ID)
+	return nil
+}
+
+// resetPassword sets a new password for a user, who can log in with it straight away
+func resetPassword(ctx context.Context, args []string) error {
+	fs := newFlagSet("reset-password")
+	email := fs.String("email", "", "email address of the user (required)")
+	tenantID := fs.String("tenant", "", "tenant the user belongs to")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *email == "" {
+		fs.Usage()
+		return ErrUsage
+	}
+
+	s, err := open(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.close(ctx)
+	ctx, err = forTenant(ctx, *tenantID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.repos.Users.FindByEmail(ctx, *email)
+	if errors.Is(err, store.ErrNotFound) {
+		return fmt.Errorf("%s is not registered", *email)
+	}
+	if err != nil {
+		return fmt.Errorf("could not find user: %w", err)
+	}
+	hashedPassword, err := readPassword()
+	if err != nil {
+		return err
+	}
+
+	// Replace the password the way a user does with a reset token, which also clears any reset an admin required
+	token := utils.GenerateID()
+	if err := s.repos.Users.RequirePasswordReset(ctx, user.ID, token); err != nil {
+		return fmt.Errorf("could not reset password: %w", err)
+	}
+	if _, err := s.repos.Users.ResetPassword(ctx, token, hashedPassword); err != nil {
+		return fmt.Errorf("could not reset password: %w", err)
+	}
+	fmt.Fprintf(Stdout, "Reset the password of %s\n", user.Email)
+	return nil
+}
+
+// readPassword reads the new password from the first line of standard input, so it is not left in the
+// shell history or the process list, and returns its hash
+func readPassword() (string, error) {
+	fmt.Fprint(Stderr, "New password: ")
+	line, err := bufio.NewReader(Stdin).ReadString('\n')
+	fmt.Fprintln(Stderr)
+	if err != nil && line == "" {
+		return "", fmt.Errorf("could not read password: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if len(password) < 8 || len(password) > 72 {
+		return "", errors.New("password must be between 8 and 72 characters")
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("could not hash password: %w", err)
+	}
+	return string(hashed), nil
+}
@@ -0,0 +1,77 @@
+// Package clientip derives the address of the client behind the proxies in front of the server, such as
+// nginx or Cloudflare, so logs, the audit log and per-client limits see the real client rather than the
+// proxy.
+//
+// Forwarding headers such as X-Forwarded-For are only believed when the request comes from one of the
+// trusted proxies; otherwise the address of the direct peer is used, as any client can set the headers. The
+// header of a trusted platform such as Cloudflare is no different, so the platform's edge addresses, or the
+// proxy in front of the server that passes the header on, must be among the trusted proxies.
+// The address is derived once per request by Gin's ClientIP, which the Middleware also stores in the
+// request's context for code without the Gin context.
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"profile-api/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Platforms whose edge sets a header holding the client address, selectable in the config
+var platforms = map[string]string{
+	"cloudflare":        gin.PlatformCloudflare,
+	"google-app-engine": gin.PlatformGoogleAppEngine,
+}
+
+type contextKey struct{}
+
+// headers holds the headers the client address is read from, canonicalized
+var headers []string
+
+// Configure sets which proxies the router believes, and the headers it reads the client address from
+func Configure(router *gin.Engine, cfg *config.Config) error {
+	// No trusted proxies means trusting none, rather than Gin's default of trusting every peer
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	// The platform's header is read first among the forwarding headers, rather than set as Gin's
+	// TrustedPlatform, which believes it from any peer
+	router.RemoteIPHeaders = cfg.ClientIPHeaders
+	if platform := platforms[cfg.TrustedPlatform]; platform != "" {
+		router.RemoteIPHeaders = append([]string{platform}, cfg.ClientIPHeaders...)
+	}
+	router.TrustedPlatform = ""
+
+	headers = nil
+	for _, name := range cfg.ClientIPHeaders {
+		headers = append(headers, http.CanonicalHeaderKey(name))
+	}
+	if platform := platforms[cfg.TrustedPlatform]; platform != "" {
+		headers = append(headers, http.CanonicalHeaderKey(platform))
+	}
+	return nil
+}
+
+// Headers returns the headers the client address is read from, including the trusted platform's, for code
+// passing requests on that must not let clients set them
+func Headers() []string {
+	return headers
+}
+
+// Middleware stores the address of the client in the request's context
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), contextKey{}, c.ClientIP()))
+		c.Next()
+	}
+}
+
+// FromContext returns the address of the client making the request the context was derived from, or an
+// empty string outside a request
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(contextKey{}).(string)
+	return ip
+}
@@ -0,0 +1,1873 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPath is the config file read when CONFIG_FILE is not set
+const DefaultPath = "config-selfhosted.json"
+
+// Config holds the complete server configuration
+type Config struct {
+	ListenPort      int                          `json:"listen-port"`
+	ShutdownTimeout Duration                     `json:"shutdown-timeout"`
+	PublicBaseURL   string                       `json:"public-base-url"`
+	TrustedProxies  []string                     `json:"trusted-proxies"`
+	ClientIPHeaders []string                     `json:"client-ip-headers"`
+	TrustedPlatform string                       `json:"trusted-platform"`
+	Storage         string                       `json:"storage"`
+	Modules         ModulesConfig                `json:"modules"`
+	Mongo           MongoConfig                  `json:"mongodb"`
+	Postgres        PostgresConfig               `json:"postgres"`
+	Migrations      MigrationsConfig             `json:"migrations"`
+	ReadOnly        ReadOnlyConfig               `json:"read-only"`
+	Cache           CacheConfig                  `json:"cache"`
+	Jobs            JobsConfig                   `json:"jobs"`
+	Scheduler       SchedulerConfig              `json:"scheduler"`
+	Webhooks        WebhooksConfig               `json:"webhooks"`
+	Notifications   NotificationsConfig          `json:"notifications"`
+	Archival        ArchivalConfig               `json:"archival"`
+	Recovery        RecoveryConfig               `json:"recovery"`
+	Recommendations RecommendationsConfig        `json:"recommendations"`
+	Inbox           InboxConfig                  `json:"inbox"`
+	Privacy         PrivacyConfig                `json:"privacy"`
+	Shares          SharesConfig                 `json:"shares"`
+	Embed           EmbedConfig                  `json:"embed"`
+	Domains         DomainsConfig                `json:"domains"`
+	Moderation      ModerationConfig             `json:"moderation"`
+	Attachments     AttachmentsConfig            `json:"attachments"`
+	ActivityPub     ActivityPubConfig            `json:"activitypub"`
+	Billing         BillingConfig                `json:"billing"`
+	Quotas          QuotasConfig                 `json:"quotas"`
+	Search          SearchConfig                 `json:"search"`
+	Idempotency     IdempotencyConfig            `json:"idempotency"`
+	APIUsage        APIUsageConfig               `json:"api-usage"`
+	Scraping        ScrapingConfig               `json:"scraping"`
+	RequireIfMatch  bool                         `json:"require-if-match"`
+	Batch           BatchConfig                  `json:"batch"`
+	BodyLimits      BodyLimitsConfig             `json:"body-limits"`
+	Timeouts        TimeoutsConfig               `json:"timeouts"`
+	OpenAPI         OpenAPIConfig                `json:"openapi"`
+	Sanitize        map[string]string            `json:"sanitize"`
+	Features        map[string]FeatureFlagConfig `json:"features"`
+	Demo            DemoConfig                   `json:"demo"`
+	GRPC            GRPCConfig                   `json:"grpc"`
+	Branding        BrandingConfig               `json:"branding"`
+	Tenants         []TenantConfig               `json:"tenants"`
+	JWT             JWTConfig                    `json:"jwt"`
+	Registration    RegistrationConfig           `json:"registration"`
+	ImageStore      ImageStoreConfig             `json:"image-store"`
+	CORS            CORSConfig                   `json:"cors"`
+	Email           EmailConfig                  `json:"email"`
+	AI              AIConfig                     `json:"ai"`
+	Resume          ResumeConfig                 `json:"resume"`
+	Scan            ScanConfig                   `json:"scan"`
+	TLS             TLSConfig                    `json:"tls"`
+	Log             LogConfig                    `json:"log"`
+	Tracing         TracingConfig                `json:"tracing"`
+}
+
+// ModulesConfig switches off the modules a deployment does not use. A disabled module's routes are not
+// registered, its periodic tasks do not run, and its documents are left out of full profiles, exports and the
+// GraphQL and gRPC profiles. Its stored data is kept, so enabling it again brings it back.
+type ModulesConfig struct {
+	// Journal is the journal, its attachments and the features built on its entries, such as search,
+	// widgets, the activity feed and automation. The features reading them leave them out when disabled.
+	Journal bool `json:"journal"`
+	// Certificates is the certificates and the integrations awarding them. The features reading them, such
+	// as search, shares, calendars and expiry notifications, leave them out when disabled.
+	Certificates bool `json:"certificates"`
+	// Experience is the work history. Skill suggestions are read from it, so they are unavailable when it is
+	// disabled.
+	Experience bool `json:"experience"`
+	// Qualifications is the education and training history
+	Qualifications bool `json:"qualifications"`
+	// Skills is the skills listed on profiles
+	Skills bool `json:"skills"`
+	// Awards is the awards and honours listed on profiles
+	Awards bool `json:"awards"`
+	// Languages is the spoken languages listed on profiles
+	Languages bool `json:"languages"`
+	// Recommendations is the recommendations users request from colleagues and show on their profile
+	Recommendations bool `json:"recommendations"`
+	// Resume is the import of a CV into experience, qualifications and skills, so it requires those three
+	Resume bool `json:"resume"`
+	// Subscriptions is the email digests of new journal entries, so it requires the journal
+	Subscriptions bool `json:"subscriptions"`
+}
+
+// MongoConfig holds the MongoDB connection settings
+type MongoConfig struct {
+	URI      string `json:"uri"`
+	Database string `json:"database"`
+	// OperationTimeout bounds each database operation made while serving a request
+	OperationTimeout Duration `json:"operation-timeout"`
+	// PublicReadPreference is where heavy public reads, such as listings and searches, are served from:
+	// primary, primaryPreferred, secondary, secondaryPreferred or nearest. Writes and every other read
+	// stay on the primary.
+	PublicReadPreference string `json:"public-read-preference"`
+	// MaxStaleness bounds how far behind the primary a secondary serving public reads may be, 0 for no
+	// bound. Mongo requires at least 90 seconds.
+	MaxStaleness Duration `json:"max-staleness"`
+	// MaxPoolSize and MinPoolSize bound the connections kept to each server, 0 for no maximum
+	MaxPoolSize int `json:"max-pool-size"`
+	MinPoolSize int `json:"min-pool-size"`
+	// MaxConnIdleTime is how long an idle connection is kept open, 0 to keep it until the server closes it
+	MaxConnIdleTime Duration `json:"max-conn-idle-time"`
+	// ConnectTimeout bounds opening a connection, and ServerSelectionTimeout finding a server to run an
+	// operation on
+	ConnectTimeout         Duration `json:"connect-timeout"`
+	ServerSelectionTimeout Duration `json:"server-selection-timeout"`
+	// ConnectRetries is how many more times connecting at startup is tried when Mongo can't be reached,
+	// waiting ConnectRetryBackoff before the first retry and twice as long before each following one
+	ConnectRetries      int      `json:"connect-retries"`
+	ConnectRetryBackoff Duration `json:"connect-retry-backoff"`
+	// HealthCheckInterval is how often Mongo is pinged while serving. While it can't be reached, requests
+	// needing storage are answered 503 at once rather than each waiting for the operation timeout. 0 turns
+	// the checks off.
+	HealthCheckInterval Duration `json:"health-check-interval"`
+	// ChangeStreams follows the writes to users' documents through change streams, so every replica drops
+	// its stale cached copies and notifies its clients of changes made by the others. It needs a replica
+	// set, and Mongo 6 or later to attribute deletions to their user.
+	ChangeStreams bool `json:"change-streams"`
+}
+
+// mongoReadPreferences are the read preferences public reads may be served with
+var mongoReadPreferences = []string{"primary", "primaryPreferred", "secondary", "secondaryPreferred", "nearest"}
+
+// PostgresConfig holds the PostgreSQL connection settings, used when storage is postgres
+type PostgresConfig struct {
+	URL string `json:"url"`
+}
+
+// MigrationsConfig holds how the schema of the configured storage is kept up to date. OnStart is apply to
+// migrate each database when the server starts, verify to refuse to start while migrations are pending,
+// for deployments migrating with `profile-api admin migrate` before rolling out, or skip to do neither.
+type MigrationsConfig struct {
+	OnStart string `json:"on-start"`
+}
+
+// ReadOnlyConfig holds the settings of serving the API as a read-only replica, such as at the edge, while
+// writes go to the main instance. A replica serves every request as anonymous, refuses all but GET, HEAD
+// and OPTIONS requests and runs no background jobs or periodic tasks.
+type ReadOnlyConfig struct {
+	Enabled bool `json:"enabled"`
+	// CacheMaxAge is how long shared caches may keep the successful responses of a replica
+	CacheMaxAge Duration `json:"cache-max-age"`
+	// WriteURL is the base URL of the main instance, which clients are pointed to when they try to write
+	WriteURL string `json:"write-url"`
+}
+
+// CacheConfig holds the Redis cache settings for public reads. Caching is disabled when RedisURL is empty.
+type CacheConfig struct {
+	RedisURL       string   `json:"redis-url"`
+	KeyPrefix      string   `json:"key-prefix"`
+	ProfileTTL     Duration `json:"profile-ttl"`
+	SkillsTTL      Duration `json:"skills-ttl"`
+	JournalListTTL Duration `json:"journal-list-ttl"`
+	// AggregateTTL is how long a composed full profile is kept, in Redis when it is configured and in each
+	// replica's memory otherwise. Zero disables caching full profiles.
+	AggregateTTL Duration `json:"aggregate-ttl"`
+	// AggregateSize is how many full profiles each replica keeps in memory when Redis is not configured
+	AggregateSize int `json:"aggregate-size"`
+}
+
+// JobsConfig holds the background job queue settings. The storage backend keeps the queue in the
+// configured database, the redis backend in the Redis server at RedisURL.
+type JobsConfig struct {
+	Backend      string   `json:"backend"`
+	RedisURL     string   `json:"redis-url"`
+	Workers      int      `json:"workers"`
+	MaxAttempts  int      `json:"max-attempts"`
+	PollInterval Duration `json:"poll-interval"`
+	Lease        Duration `json:"lease"`
+	RetryBackoff Duration `json:"retry-backoff"`
+	// Retention is how long finished and dead jobs are kept before the purge task removes them
+	Retention Duration `json:"retention"`
+}
+
+// SchedulerConfig holds the periodic task settings. Only the replica holding the leader lease runs tasks.
+// Tasks missing from Tasks run on their default schedule.
+type SchedulerConfig struct {
+	LeaderLease Duration              `json:"leader-lease"`
+	Tasks       map[string]TaskConfig `json:"tasks"`
+}
+
+// TaskConfig enables, disables or reschedules a periodic task. Schedule is a cron expression or
+// a descriptor such as @hourly, and defaults to the task's own schedule when empty.
+type TaskConfig struct {
+	Enabled  *bool  `json:"enabled"`
+	Schedule string `json:"schedule"`
+}
+
+// WebhooksConfig holds the outbound webhook delivery settings. Deliveries are retried by the job queue.
+type WebhooksConfig struct {
+	// Timeout bounds each delivery request
+	Timeout Duration `json:"timeout"`
+	// AllowPrivateNetworks permits delivering to loopback and private addresses, for local development
+	AllowPrivateNetworks bool `json:"allow-private-networks"`
+	// Retention is how long delivery history is kept
+	Retention Duration `json:"retention"`
+}
+
+// NotificationsConfig holds the settings of the notifications users receive about their content
+type NotificationsConfig struct {
+	// CertificateExpiryNotice is how long before a certificate ends its owner is notified
+	CertificateExpiryNotice Duration `json:"certificate-expiry-notice"`
+	// Retention is how long notifications are kept, read or not
+	Retention Duration `json:"retention"`
+}
+
+// ArchivalConfig holds the rules of the archive-certificates task, which archives certificates some time
+// after they end. Each certificate is archived by the first rule matching it, and left alone when none does.
+type ArchivalConfig struct {
+	Rules []ArchivalRule `json:"rules"`
+}
+
+// ArchivalRule archives the certificates it matches once they have ended
+type ArchivalRule struct {
+	// Institution matches the certificates from the institution, ignoring case, or every certificate when
+	// empty
+	Institution string `json:"institution"`
+	// Grace is how long after a certificate ends it is archived
+	Grace Duration `json:"grace"`
+	// Hide is who an archived certificate is still shown to, members or private, or empty to keep showing it
+	// to everyone
+	Hide string `json:"hide"`
+}
+
+// RecoveryConfig holds the settings of recovering from panics in request handlers, which are answered with
+// a 500 response and reported to admins through their notifications
+type RecoveryConfig struct {
+	// AlertInterval is how often admins are alerted of panics on the same route at most, or 0 to not alert
+	// them
+	AlertInterval Duration `json:"alert-interval"`
+}
+
+// RecommendationsConfig holds the settings of the recommendations users ask external referees for
+type RecommendationsConfig struct {
+	// InvitationTTL is how long the link emailed to an external referee can be used
+	InvitationTTL Duration `json:"invitation-ttl"`
+	// MaxPendingInvitations is how many unused invitations a user may have waiting at once, so the
+	// instance can't be used to email strangers in bulk
+	MaxPendingInvitations int `json:"max-pending-invitations"`
+}
+
+// InboxConfig holds the settings of the contact requests visitors send users from their profile
+type InboxConfig struct {
+	// SpamThreshold is the spam score, from 0 to 1, at which a contact request is filed as spam without
+	// notifying the user
+	SpamThreshold float64 `json:"spam-threshold"`
+	// SpamWords are words making a contact request more likely to be spam, matched ignoring case
+	SpamWords []string `json:"spam-words"`
+	// MaxPerSender is how many contact requests one address may send a user a day before the rest are
+	// filed as spam
+	MaxPerSender int `json:"max-per-sender"`
+	// SpamRetention is how long contact requests filed as spam are kept
+	SpamRetention Duration `json:"spam-retention"`
+}
+
+// PrivacyConfig holds the settings of the allowlists users restrict their profile to
+type PrivacyConfig struct {
+	// AccessTTL is how long a visitor on an allowlist may see the profile after following their link,
+	// before they must follow it again
+	AccessTTL Duration `json:"access-ttl"`
+	// MaxVisitors is how many visitors a user's allowlist may hold, so the instance can't be used to
+	// email strangers in bulk
+	MaxVisitors int `json:"max-visitors"`
+}
+
+// SharesConfig holds the settings of the packages users share with recruiters through read-only links
+type SharesConfig struct {
+	// DefaultTTL is how long a package's link works when its owner does not say
+	DefaultTTL Duration `json:"default-ttl"`
+	// MaxTTL is how long a package's link may work at most
+	MaxTTL Duration `json:"max-ttl"`
+	// MaxActive is how many packages a user may have whose link still works
+	MaxActive int `json:"max-active"`
+}
+
+// EmbedConfig holds the settings of the widgets users embed on other sites
+type EmbedConfig struct {
+	// MaxAge is how long browsers and shared caches may keep a widget before fetching it again
+	MaxAge Duration `json:"max-age"`
+	// JournalEntries is how many of the latest journal entries the journal widget shows
+	JournalEntries int `json:"journal-entries"`
+}
+
+// RegistrationConfig holds the measures keeping bots from registering
+type RegistrationConfig struct {
+	// Honeypot rejects registrations filling in the website field, which registration forms hide from people
+	Honeypot bool `json:"honeypot"`
+	// MinSubmitTime is how long must pass between fetching a registration form token and registering with
+	// it, quicker than people fill in the form. 0 requires no token.
+	MinSubmitTime Duration `json:"min-submit-time"`
+	// DisposableDomains are the domains, and their subdomains, of throwaway email services that may not register
+	DisposableDomains []string `json:"disposable-domains"`
+	// DisposableDomainsFile names a file of further disposable domains, one per line, such as a published blocklist
+	DisposableDomainsFile string `json:"disposable-domains-file"`
+}
+
+// DomainsConfig holds the settings of the domains users attach to their profile
+type DomainsConfig struct {
+	// MaxPerUser is how many domains a user may attach, 0 turning custom domains off
+	MaxPerUser int `json:"max-per-user"`
+	// PendingTTL is how long a domain is checked for its verification record before it is removed
+	PendingTTL Duration `json:"pending-ttl"`
+	// CacheTTL is how long each replica remembers which profile a host serves, or that it serves none
+	CacheTTL Duration `json:"cache-ttl"`
+}
+
+// ModerationConfig holds the settings of reporting abuse
+type ModerationConfig struct {
+	// AutoHideReports is how many open reports, each from a different reporter, hide content until an admin
+	// reviews it, 0 never hiding content automatically
+	AutoHideReports int `json:"auto-hide-reports"`
+	// AnonymousReports lets visitors who are not signed in report content, each address counting as a reporter
+	AnonymousReports bool `json:"anonymous-reports"`
+}
+
+// AttachmentsConfig holds the settings of removing the files stored for journal entries once no entry refers
+// to them
+type AttachmentsConfig struct {
+	// OrphanGrace is how long a file stays unreferenced before it is removed, in case an entry being edited
+	// or imported refers to it again
+	OrphanGrace Duration `json:"orphan-grace"`
+	// URLExpiry is how long the signed links to the attachments of entries that aren't public last
+	URLExpiry Duration `json:"url-expiry"`
+}
+
+// IdempotencyConfig holds the settings for replaying retried POST requests sent with an Idempotency-Key
+type IdempotencyConfig struct {
+	// Retention is how long a key and the response to its request are kept
+	Retention Duration `json:"retention"`
+}
+
+// APIUsageConfig holds the settings for counting the API calls each user makes
+type APIUsageConfig struct {
+	// FlushInterval is how often the calls counted in memory are added to the stored counters
+	FlushInterval Duration `json:"flush-interval"`
+	// Retention is how long the daily counters are kept
+	Retention Duration `json:"retention"`
+}
+
+// ScrapingConfig holds the settings of the detection of clients scraping the public journal, by bursts of
+// requests from one address or by looking entries up in the order of their IDs
+type ScrapingConfig struct {
+	Enabled bool `json:"enabled"`
+	// BurstRequests is how many requests to the public journal one address may make within BurstWindow
+	BurstRequests int      `json:"burst-requests"`
+	BurstWindow   Duration `json:"burst-window"`
+	// WalkLength is how many journal entries one address may look up in a row in the order of their IDs
+	// within WalkWindow
+	WalkLength int      `json:"walk-length"`
+	WalkWindow Duration `json:"walk-window"`
+	// Action is what is done with the requests of an address detected scraping until Penalty has passed:
+	// report only records the detection, slow delays each request by Delay and block refuses them with 429
+	Action  string   `json:"action"`
+	Delay   Duration `json:"delay"`
+	Penalty Duration `json:"penalty"`
+	// Retention is how long detections are kept for the admin report
+	Retention Duration `json:"retention"`
+}
+
+// BatchConfig holds the settings of the batch endpoint, which runs several API requests sent in one
+type BatchConfig struct {
+	// MaxRequests is the most requests a batch may hold
+	MaxRequests int `json:"max-requests"`
+}
+
+// BodyLimitsConfig bounds the size of request bodies, in bytes. Larger requests are rejected with 413.
+type BodyLimitsConfig struct {
+	// Default applies to every route missing from Routes
+	Default int `json:"default"`
+	// Routes holds the limits of routes needing a different one, such as uploads, keyed by method and path
+	// pattern, for example "PUT /api/v1/profile/:userid/image"
+	Routes map[string]int `json:"routes"`
+	// MultipartMemory is how much of a multipart upload is held in memory, the rest is buffered in temporary files
+	MultipartMemory int `json:"multipart-memory"`
+}
+
+// TimeoutsConfig bounds how long the server waits on clients. Routes are grouped in classes with their own
+// read and write timeouts, so uploads and exports are given longer without every route waiting as long.
+type TimeoutsConfig struct {
+	// ReadHeader bounds reading a request's headers, so a client trickling them in can't hold a connection
+	ReadHeader Duration `json:"read-header"`
+	// Idle is how long a keep-alive connection is held open waiting for the next request
+	Idle Duration `json:"idle"`
+	// MaxHeaderBytes bounds the size of a request's headers
+	MaxHeaderBytes int `json:"max-header-bytes"`
+	// Default applies to every route missing from Routes
+	Default RouteTimeouts `json:"default"`
+	Uploads RouteTimeouts `json:"uploads"`
+	// Exports bound writing each part of the response rather than all of it, so a large response is only
+	// cut off when it stops flowing
+	Exports RouteTimeouts `json:"exports"`
+	// Routes puts routes in the uploads or exports class, keyed by method and path pattern, for example
+	// "PUT /api/v1/profile/:userid/image"
+	Routes map[string]string `json:"routes"`
+}
+
+// RouteTimeouts bound reading the whole request, body included, and writing the response. Zero leaves
+// either unbounded.
+type RouteTimeouts struct {
+	Read  Duration `json:"read"`
+	Write Duration `json:"write"`
+}
+
+// OpenAPIConfig sets how requests are checked against the API's OpenAPI document. Validation is enforce to
+// reject requests whose parameters, body or content type do not match the documented operation with 400,
+// report to only log the mismatches, for finding where the documentation has drifted from the handlers, or
+// off.
+type OpenAPIConfig struct {
+	Validation string `json:"validation"`
+}
+
+// FeatureFlagConfig sets who a feature is enabled for until an admin overrides it at runtime. An enabled
+// feature is on for the listed users and for the given percentage of the others, chosen by their user ID.
+type FeatureFlagConfig struct {
+	Enabled    bool     `json:"enabled"`
+	Percentage int      `json:"percentage"`
+	Users      []string `json:"users"`
+}
+
+// DemoConfig holds the settings for seeding example users, so evaluators and frontend developers have data
+// to work with. The demo users are created on startup when missing, on the site of the tenant with the
+// given ID in multi-tenant deployments, and can log in with Password.
+type DemoConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Tenant   string `json:"tenant"`
+	Password string `json:"password"`
+}
+
+// ActivityPubConfig holds the settings for federating public journals with ActivityPub servers such as
+// Mastodon
+type ActivityPubConfig struct {
+	Enabled bool `json:"enabled"`
+	// Timeout bounds each request to another server
+	Timeout Duration `json:"timeout"`
+	// AllowPrivateNetworks permits requests to loopback and private addresses, for local development
+	AllowPrivateNetworks bool `json:"allow-private-networks"`
+}
+
+// BillingConfig holds the settings for selling premium plans through Stripe. Each user is on a plan
+// bounding what they can do, DefaultPlan until they subscribe to another. Plans only apply while billing
+// is enabled, so self-hosted sites are not limited.
+type BillingConfig struct {
+	Enabled     bool                  `json:"enabled"`
+	DefaultPlan string                `json:"default-plan"`
+	Plans       map[string]PlanConfig `json:"plans"`
+	Stripe      StripeConfig          `json:"stripe"`
+}
+
+// PlanConfig sets what the users on a plan can do. Limits of 0 are unlimited.
+type PlanConfig struct {
+	// PriceID is the Stripe price of the plan's subscription, empty for plans that cannot be bought
+	PriceID           string `json:"price-id"`
+	MaxJournalEntries int    `json:"max-journal-entries"`
+	// StorageBytes bounds the size of the user's uploads
+	StorageBytes int64 `json:"storage-bytes"`
+	// AI permits the features using the AI provider
+	AI bool `json:"ai"`
+	// CustomDomain permits setting the domain a profile is served on
+	CustomDomain bool `json:"custom-domain"`
+}
+
+// StripeConfig holds the Stripe account plans are sold with. SuccessURL and CancelURL are where checkout
+// returns the user to.
+type StripeConfig struct {
+	SecretKey     string   `json:"secret-key"`
+	WebhookSecret string   `json:"webhook-secret"`
+	BaseURL       string   `json:"base-url"`
+	SuccessURL    string   `json:"success-url"`
+	CancelURL     string   `json:"cancel-url"`
+	Timeout       Duration `json:"timeout"`
+}
+
+// QuotaCollections are the collections whose documents each user can keep a bounded number of
+var QuotaCollections = []string{"experience", "qualifications", "certificates", "awards", "skills", "languages", "journal"}
+
+// QuotasConfig bounds what each user can store, on top of the limits of their billing plan. Limits of 0
+// are unlimited.
+type QuotasConfig struct {
+	// StorageBytes bounds the size of the user's uploads: their profile image, certificate and award images
+	// and the images of imported journal entries
+	StorageBytes int64 `json:"storage-bytes"`
+	// Documents bounds the documents of each of QuotaCollections
+	Documents map[string]int `json:"documents"`
+}
+
+// SearchConfig selects where the search index is kept
+type SearchConfig struct {
+	// Backend is "storage" to index in the storage backend's database, or "elasticsearch"
+	Backend       string              `json:"backend"`
+	Elasticsearch ElasticsearchConfig `json:"elasticsearch"`
+	// Vectors selects where the vectors of semantic search are kept
+	Vectors VectorsConfig `json:"vectors"`
+}
+
+// VectorsConfig selects where the vectors of semantic search are kept
+type VectorsConfig struct {
+	// Backend is "storage" to keep them in the storage backend's database, or "qdrant"
+	Backend string       `json:"backend"`
+	Qdrant  QdrantConfig `json:"qdrant"`
+}
+
+// QdrantConfig holds the Qdrant vector database the vectors of semantic search are kept in
+type QdrantConfig struct {
+	URL    string `json:"url"`
+	APIKey string `json:"api-key"`
+	// CollectionPrefix is prepended to the name of each tenant's collection
+	CollectionPrefix string `json:"collection-prefix"`
+}
+
+// ElasticsearchConfig holds the Elasticsearch cluster the search index is kept in
+type ElasticsearchConfig struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// IndexPrefix is prepended to the name of each tenant's index
+	IndexPrefix string `json:"index-prefix"`
+}
+
+// GRPCConfig holds the settings for the gRPC API served to internal services
+type GRPCConfig struct {
+	// ListenPort is the port the gRPC server listens on. The server is disabled when it is 0.
+	ListenPort int `json:"listen-port"`
+}
+
+// BrandingConfig holds how a site presents itself to its frontend
+type BrandingConfig struct {
+	Name         string `json:"name"`
+	LogoURL      string `json:"logo-url"`
+	PrimaryColor string `json:"primary-color"`
+}
+
+// TenantConfig describes one of several independent sites served by the deployment. Each tenant keeps its
+// data in its own database; the job queue and scheduler locks stay in the main database.
+type TenantConfig struct {
+	// ID identifies the tenant. Requests whose subdomain equals the ID select the tenant when no domain matches.
+	ID string `json:"id"`
+	// Domains are the hosts the tenant is served on
+	Domains []string `json:"domains"`
+	// Database is the MongoDB database holding the tenant's data when storage is mongo
+	Database string `json:"database"`
+	// PostgresURL is the PostgreSQL database holding the tenant's data when storage is postgres
+	PostgresURL string `json:"postgres-url"`
+	// PublicBaseURL is used for links in the tenant's emails, defaulting to public-base-url
+	PublicBaseURL string         `json:"public-base-url"`
+	Branding      BrandingConfig `json:"branding"`
+	// S3Bucket overrides the image store bucket. Local image stores keep each tenant's images in a subdirectory.
+	S3Bucket string `json:"s3-bucket"`
+	// CDNBaseURL overrides the CDN images are served through, which tenants with a bucket or local images
+	// of their own do not share
+	CDNBaseURL string `json:"cdn-base-url"`
+	// JWTAudience is set in the tenant's tokens and required when validating them, defaulting to the ID
+	JWTAudience string `json:"jwt-audience"`
+}
+
+// Audience returns the audience of the tenant's authentication tokens
+func (t TenantConfig) Audience() string {
+	if t.JWTAudience != "" {
+		return t.JWTAudience
+	}
+	return t.ID
+}
+
+// JWTConfig holds the settings used to sign authentication tokens
+type JWTConfig struct {
+	Secret string   `json:"secret"`
+	Expiry Duration `json:"expiry"`
+	// SudoWindow is how long after signing in, or confirming their password again, users may delete their
+	// account, change their email address and do other sensitive operations
+	SudoWindow Duration `json:"sudo-window"`
+}
+
+// ImageStoreConfig selects and configures where uploaded images are stored
+type ImageStoreConfig struct {
+	Type      string   `json:"type"`
+	LocalPath string   `json:"local-path"`
+	S3        S3Config `json:"s3"`
+	// StripMetadata removes metadata such as the GPS location from uploaded images before they are stored
+	StripMetadata bool `json:"strip-metadata"`
+	// ApplyOrientation rotates images to match their EXIF orientation before it is removed with the metadata
+	ApplyOrientation bool                `json:"apply-orientation"`
+	Variants         ImageVariantsConfig `json:"variants"`
+	CDN              CDNConfig           `json:"cdn"`
+}
+
+// CDNConfig holds the CDN, such as CloudFront or imgproxy, images are served through. BaseURL maps to the
+// bucket of the s3 image store, or to the /images route of the local one, and starts the URLs of new images
+// in place of the store's own.
+type CDNConfig struct {
+	BaseURL string `json:"base-url"`
+	// Private uploads images without public access, handing out CloudFront URLs signed with the key pair
+	// that expire after URLExpiry
+	Private        bool     `json:"private"`
+	KeyPairID      string   `json:"key-pair-id"`
+	PrivateKeyFile string   `json:"private-key-file"`
+	URLExpiry      Duration `json:"url-expiry"`
+}
+
+// ImageVariantsConfig holds which smaller formats uploaded images are converted to, stored alongside the
+// original. The commands are run with {in} and {out} replaced by the paths of the image and the variant.
+type ImageVariantsConfig struct {
+	// Formats are the formats to convert to, webp and avif
+	Formats     []string `json:"formats"`
+	WebPCommand string   `json:"webp-command"`
+	AVIFCommand string   `json:"avif-command"`
+	// Timeout bounds each conversion
+	Timeout Duration `json:"timeout"`
+}
+
+// S3Config holds the settings for the S3 image store
+type S3Config struct {
+	Bucket          string `json:"bucket"`
+	Region          string `json:"region"`
+	AccessKeyID     string `json:"access-key-id"`
+	SecretAccessKey string `json:"secret-access-key"`
+	Endpoint        string `json:"endpoint"`
+}
+
+// CORSConfig holds the cross-origin settings for browser clients
+type CORSConfig struct {
+	AllowedOrigins   []string `json:"allowed-origins"`
+	AllowCredentials bool     `json:"allow-credentials"`
+}
+
+// EmailConfig holds the outbound email settings. Provider is one of smtp, ses, sendgrid or log;
+// when empty, smtp is used if an SMTP host is set and messages are only logged otherwise.
+type EmailConfig struct {
+	Provider     string         `json:"provider"`
+	SMTPHost     string         `json:"smtp-host"`
+	SMTPPort     int            `json:"smtp-port"`
+	SMTPUsername string         `json:"smtp-username"`
+	SMTPPassword string         `json:"smtp-password"`
+	From         string         `json:"from"`
+	SES          SESConfig      `json:"ses"`
+	SendGrid     SendGridConfig `json:"sendgrid"`
+}
+
+// SESConfig holds the settings for delivering email through Amazon SES.
+// The default AWS credential chain is used when no access key is set.
+type SESConfig struct {
+	Region          string `json:"region"`
+	AccessKeyID     string `json:"access-key-id"`
+	SecretAccessKey string `json:"secret-access-key"`
+	Endpoint        string `json:"endpoint"`
+}
+
+// SendGridConfig holds the settings for delivering email through SendGrid
+type SendGridConfig struct {
+	APIKey string `json:"api-key"`
+}
+
+// ActiveProvider returns the configured email provider, deriving it from the SMTP host when unset
+func (c EmailConfig) ActiveProvider() string {
+	if c.Provider != "" {
+		return c.Provider
+	}
+	if c.SMTPHost != "" {
+		return "smtp"
+	}
+	return "log"
+}
+
+// AIConfig holds the settings for the AI provider used to process content
+type AIConfig struct {
+	Provider string `json:"provider"`
+	APIKey   string `json:"api-key"`
+	Model    string `json:"model"`
+	BaseURL  string `json:"base-url"`
+	// Quota bounds the AI requests each user can make
+	Quota AIQuotaConfig `json:"quota"`
+	// Embeddings is the provider turning text into vectors for semantic search
+	Embeddings EmbeddingsConfig `json:"embeddings"`
+}
+
+// EmbeddingsConfig holds the settings for the embedding provider: openai, which also covers compatible
+// services through base-url, or voyage. Semantic search is disabled when no provider is set.
+type EmbeddingsConfig struct {
+	Provider string `json:"provider"`
+	APIKey   string `json:"api-key"`
+	Model    string `json:"model"`
+	BaseURL  string `json:"base-url"`
+}
+
+// AIQuotaConfig holds how many AI requests each user can make per period, day or month. A zero limit leaves
+// requests unlimited, though they are still counted.
+type AIQuotaConfig struct {
+	Requests int    `json:"requests"`
+	Period   string `json:"period"`
+}
+
+// ResumeConfig holds the settings for importing CVs. Parser is heuristic, to read them locally, or ai to
+// have the AI provider read them.
+type ResumeConfig struct {
+	Parser string `json:"parser"`
+}
+
+// ScanConfig holds the malware scanner uploads are checked with in the background. Scanner is clamav,
+// virustotal or empty to not scan uploads.
+type ScanConfig struct {
+	Scanner    string           `json:"scanner"`
+	ClamAV     ClamAVConfig     `json:"clamav"`
+	VirusTotal VirusTotalConfig `json:"virustotal"`
+	// Timeout bounds each scan
+	Timeout Duration `json:"timeout"`
+}
+
+// ClamAVConfig holds the ClamAV daemon uploads are streamed to
+type ClamAVConfig struct {
+	// Address is the host:port of clamd's TCP socket, or the path of its Unix socket
+	Address string `json:"address"`
+}
+
+// VirusTotalConfig holds the VirusTotal API settings. Uploads are looked up by their hash, and only sent
+// to VirusTotal, where they are shared with its partners, when Upload is on.
+type VirusTotalConfig struct {
+	APIKey  string `json:"api-key"`
+	BaseURL string `json:"base-url"`
+	Upload  bool   `json:"upload"`
+}
+
+// TLSConfig holds the HTTPS settings
+type TLSConfig struct {
+	CertFile     string         `json:"cert-file"`
+	KeyFile      string         `json:"key-file"`
+	RedirectHTTP bool           `json:"redirect-http"`
+	HTTPPort     int            `json:"http-port"`
+	Autocert     AutocertConfig `json:"autocert"`
+}
+
+// AutocertConfig holds the Let's Encrypt settings
+type AutocertConfig struct {
+	Enabled bool     `json:"enabled"`
+	Domains []string `json:"domains"`
+	// CustomDomains also obtains certificates for the custom domains users have verified
+	CustomDomains bool `json:"custom-domains"`
+	// Cache is where the account key and certificates are kept: "dir" for the cache directory, which
+	// may be a mounted volume, or "mongo" for the Mongo database, shared by every replica
+	Cache    string `json:"cache"`
+	CacheDir string `json:"cache-dir"`
+	Email    string `json:"email"`
+}
+
+// LogConfig holds the logging settings
+type LogConfig struct {
+	Level  string `json:"level"`
+	Format string `json:"format"`
+}
+
+// TracingConfig holds the OpenTelemetry tracing settings
+type TracingConfig struct {
+	Enabled     bool    `json:"enabled"`
+	Endpoint    string  `json:"endpoint"`
+	Insecure    bool    `json:"insecure"`
+	ServiceName string  `json:"service-name"`
+	SampleRatio float64 `json:"sample-ratio"`
+}
+
+// Duration is a time.Duration that can be configured as a number of seconds or a duration string such as "30s"
+type Duration time.Duration
+
+// UnmarshalJSON parses a number of seconds or a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err == nil {
+		*d = Duration(seconds * float64(time.Second))
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	parsed, err := parseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Std returns the value as a time.Duration
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// parseDuration accepts either a duration string or a plain number of seconds
+func parseDuration(s string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// Default returns the configuration used before the config file and environment are applied
+func Default() *Config {
+	return &Config{
+		ListenPort:      8080,
+		ShutdownTimeout: Duration(15 * time.Second),
+		PublicBaseURL:   "http://localhost:8080",
+		ClientIPHeaders: []string{"X-Forwarded-For", "X-Real-IP"},
+		Storage:         "mongo",
+		Modules: ModulesConfig{
+			Journal:         true,
+			Certificates:    true,
+			Experience:      true,
+			Qualifications:  true,
+			Skills:          true,
+			Awards:          true,
+			Languages:       true,
+			Recommendations: true,
+			Resume:          true,
+			Subscriptions:   true,
+		},
+		Mongo: MongoConfig{
+			URI:                    "mongodb://localhost:27017",
+			Database:               "profile",
+			OperationTimeout:       Duration(5 * time.Second),
+			PublicReadPreference:   "secondaryPreferred",
+			MaxPoolSize:            100,
+			MaxConnIdleTime:        Duration(5 * time.Minute),
+			ConnectTimeout:         Duration(10 * time.Second),
+			ServerSelectionTimeout: Duration(30 * time.Second),
+			ConnectRetries:         5,
+			ConnectRetryBackoff:    Duration(time.Second),
+			HealthCheckInterval:    Duration(10 * time.Second),
+		},
+		Migrations: MigrationsConfig{OnStart: "apply"},
+		Cache: CacheConfig{
+			KeyPrefix:      "profile-api:",
+			ProfileTTL:     Duration(5 * time.Minute),
+			SkillsTTL:      Duration(5 * time.Minute),
+			JournalListTTL: Duration(time.Minute),
+			AggregateTTL:   Duration(5 * time.Minute),
+			AggregateSize:  1000,
+		},
+		Jobs: JobsConfig{
+			Backend:      "storage",
+			Workers:      4,
+			MaxAttempts:  5,
+			PollInterval: Duration(time.Second),
+			Lease:        Duration(5 * time.Minute),
+			RetryBackoff: Duration(10 * time.Second),
+			Retention:    Duration(7 * 24 * time.Hour),
+		},
+		Scheduler: SchedulerConfig{
+			LeaderLease: Duration(30 * time.Second),
+		},
+		Webhooks: WebhooksConfig{
+			Timeout:   Duration(10 * time.Second),
+			Retention: Duration(30 * 24 * time.Hour),
+		},
+		Notifications: NotificationsConfig{
+			CertificateExpiryNotice: Duration(30 * 24 * time.Hour),
+			Retention:               Duration(90 * 24 * time.Hour),
+		},
+		Archival: ArchivalConfig{
+			Rules: []ArchivalRule{{}},
+		},
+		Recovery: RecoveryConfig{
+			AlertInterval: Duration(10 * time.Minute),
+		},
+		Recommendations: RecommendationsConfig{
+			InvitationTTL:         Duration(30 * 24 * time.Hour),
+			MaxPendingInvitations: 20,
+		},
+		Inbox: InboxConfig{
+			SpamThreshold: 0.7,
+			SpamWords:     []string{"backlinks", "casino", "crypto", "forex", "loan", "seo"},
+			MaxPerSender:  3,
+			SpamRetention: Duration(30 * 24 * time.Hour),
+		},
+		Privacy: PrivacyConfig{
+			AccessTTL:   Duration(30 * 24 * time.Hour),
+			MaxVisitors: 50,
+		},
+		Shares: SharesConfig{
+			DefaultTTL: Duration(14 * 24 * time.Hour),
+			MaxTTL:     Duration(90 * 24 * time.Hour),
+			MaxActive:  20,
+		},
+		Embed: EmbedConfig{
+			MaxAge:         Duration(5 * time.Minute),
+			JournalEntries: 5,
+		},
+		Registration: RegistrationConfig{
+			Honeypot: true,
+			DisposableDomains: []string{
+				"10minutemail.com", "guerrillamail.com", "mailinator.com", "maildrop.cc", "sharklasers.com",
+				"temp-mail.org", "tempmail.com", "throwawaymail.com", "trashmail.com", "yopmail.com",
+			},
+		},
+		Domains: DomainsConfig{
+			MaxPerUser: 3,
+			PendingTTL: Duration(7 * 24 * time.Hour),
+			CacheTTL:   Duration(time.Minute),
+		},
+		Moderation: ModerationConfig{
+			AutoHideReports:  3,
+			AnonymousReports: true,
+		},
+		Attachments: AttachmentsConfig{
+			OrphanGrace: Duration(7 * 24 * time.Hour),
+			URLExpiry:   Duration(15 * time.Minute),
+		},
+		ReadOnly: ReadOnlyConfig{
+			CacheMaxAge: Duration(time.Minute),
+		},
+		ActivityPub: ActivityPubConfig{
+			Timeout: Duration(10 * time.Second),
+		},
+		Billing: BillingConfig{
+			DefaultPlan: "free",
+			Plans: map[string]PlanConfig{
+				"free":    {MaxJournalEntries: 50, StorageBytes: 100 << 20},
+				"premium": {StorageBytes: 10 << 30, AI: true, CustomDomain: true},
+			},
+			Stripe: StripeConfig{
+				BaseURL: "https://api.stripe.com",
+				Timeout: Duration(10 * time.Second),
+			},
+		},
+		Quotas: QuotasConfig{
+			StorageBytes: 1 << 30,
+			Documents: map[string]int{
+				"experience":     200,
+				"qualifications": 100,
+				"certificates":   200,
+				"awards":         200,
+				"skills":         500,
+				"languages":      50,
+				"journal":        5000,
+			},
+		},
+		Idempotency: IdempotencyConfig{
+			Retention: Duration(24 * time.Hour),
+		},
+		APIUsage: APIUsageConfig{
+			FlushInterval: Duration(30 * time.Second),
+			Retention:     Duration(90 * 24 * time.Hour),
+		},
+		Scraping: ScrapingConfig{
+			Enabled:       true,
+			BurstRequests: 120,
+			BurstWindow:   Duration(time.Minute),
+			WalkLength:    30,
+			WalkWindow:    Duration(5 * time.Minute),
+			Action:        "slow",
+			Delay:         Duration(2 * time.Second),
+			Penalty:       Duration(15 * time.Minute),
+			Retention:     Duration(30 * 24 * time.Hour),
+		},
+		RequireIfMatch: true,
+		Batch:          BatchConfig{MaxRequests: 20},
+		BodyLimits: BodyLimitsConfig{
+			Default: 2 << 20,
+			Routes: map[string]int{
+				"PUT /api/v1/profile/:userid/image":                              10 << 20,
+				"PUT /api/v1/certificates/:userid/:certificateid/cert_image":     10 << 20,
+				"PUT /api/v2/certificates/:userid/:certificateid/cert_image":     10 << 20,
+				"PUT /api/v1/qualifications/:userid/:qualificationid/cert_image": 10 << 20,
+				"PUT /api/v2/qualifications/:userid/:qualificationid/cert_image": 10 << 20,
+				"PUT /api/v1/awards/:userid/:awardid/image":                      10 << 20,
+				"PUT /api/v2/awards/:userid/:awardid/image":                      10 << 20,
+				"POST /api/v1/journal/import":                                    50 << 20,
+			},
+			MultipartMemory: 8 << 20,
+		},
+		Timeouts: TimeoutsConfig{
+			ReadHeader:     Duration(5 * time.Second),
+			Idle:           Duration(2 * time.Minute),
+			MaxHeaderBytes: 1 << 20,
+			Default:        RouteTimeouts{Read: Duration(10 * time.Second), Write: Duration(10 * time.Second)},
+			Uploads:        RouteTimeouts{Read: Duration(5 * time.Minute), Write: Duration(5 * time.Minute)},
+			Exports:        RouteTimeouts{Read: Duration(10 * time.Second), Write: Duration(30 * time.Second)},
+			Routes: map[string]string{
+				"PUT /api/v1/profile/:userid/image":                              "uploads",
+				"PUT /api/v1/certificates/:userid/:certificateid/cert_image":     "uploads",
+				"PUT /api/v2/certificates/:userid/:certificateid/cert_image":     "uploads",
+				"PUT /api/v1/qualifications/:userid/:qualificationid/cert_image": "uploads",
+				"PUT /api/v2/qualifications/:userid/:qualificationid/cert_image": "uploads",
+				"PUT /api/v1/awards/:userid/:awardid/image":                      "uploads",
+				"PUT /api/v2/awards/:userid/:awardid/image":                      "uploads",
+				"POST /api/v1/journal/import":                                    "uploads",
+				"POST /api/v1/profile/:userid/import/resume":                     "uploads",
+				"GET /api/v1/profile/:userid/full":                               "exports",
+				"GET /api/v1/profile/:userid/calendar.ics":                       "exports",
+				"GET /api/v1/awards/:userid/:awardid/image":                      "exports",
+				"GET /api/v2/awards/:userid/:awardid/image":                      "exports",
+				"GET /images/*name":                                              "exports",
+				"GET /api/v1/journal/:journalid/attachments/:index":              "exports",
+			},
+		},
+		OpenAPI: OpenAPIConfig{Validation: "enforce"},
+		Sanitize: map[string]string{
+			"profile.bio":                "rich-text",
+			"profile.interests":          "text",
+			"experience.description":     "rich-text",
+			"experience.notes":           "text",
+			"qualifications.description": "rich-text",
+			"certificates.description":   "rich-text",
+			"skills.description":         "rich-text",
+			"journal.title":              "text",
+			"journal.content":            "rich-text",
+			"journal.summary":            "text",
+			"organizations.description":  "rich-text",
+			"recommendations.content":    "rich-text",
+			"awards.description":         "rich-text",
+			"inbox.message":              "text",
+		},
+		Features: map[string]FeatureFlagConfig{
+			"search":        {Enabled: true, Percentage: 100},
+			"ai-processing": {},
+			"comments":      {},
+		},
+		Demo: DemoConfig{Password: "demo-password"},
+		Search: SearchConfig{
+			Backend: "storage",
+			Elasticsearch: ElasticsearchConfig{
+				IndexPrefix: "profile-api-",
+			},
+			Vectors: VectorsConfig{
+				Backend: "storage",
+				Qdrant:  QdrantConfig{CollectionPrefix: "profile-api-"},
+			},
+		},
+		JWT: JWTConfig{
+			Expiry:     Duration(time.Hour),
+			SudoWindow: Duration(10 * time.Minute),
+		},
+		ImageStore: ImageStoreConfig{
+			Type:             "local",
+			StripMetadata:    true,
+			ApplyOrientation: true,
+			Variants: ImageVariantsConfig{
+				Formats:     []string{"webp"},
+				WebPCommand: "cwebp -quiet -q 80 {in} -o {out}",
+				AVIFCommand: "avifenc --speed 6 {in} {out}",
+				Timeout:     Duration(30 * time.Second),
+			},
+			CDN: CDNConfig{URLExpiry: Duration(time.Hour)},
+		},
+		Email: EmailConfig{
+			SMTPPort: 587,
+		},
+		AI: AIConfig{
+			Quota: AIQuotaConfig{Requests: 100, Period: "month"},
+		},
+		Resume: ResumeConfig{Parser: "heuristic"},
+		Scan: ScanConfig{
+			ClamAV:     ClamAVConfig{Address: "localhost:3310"},
+			VirusTotal: VirusTotalConfig{BaseURL: "https://www.virustotal.com/api/v3"},
+			Timeout:    Duration(time.Minute),
+		},
+		TLS: TLSConfig{
+			HTTPPort: 80,
+			Autocert: AutocertConfig{
+				Cache:    "dir",
+				CacheDir: "certs",
+			},
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Tracing: TracingConfig{
+			Endpoint:    "http://localhost:4318",
+			ServiceName: "profile-api",
+			SampleRatio: 1,
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, the config file if it exists, and environment variables,
+// then validates the result. The config file is read from CONFIG_FILE or DefaultPath.
+func Load() (*Config, error) {
+	cfg := Default()
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		path = DefaultPath
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && os.Getenv("CONFIG_FILE") == "":
+		// Running from environment variables alone
+	default:
+		return nil, fmt.Errorf("error reading config file %s: %w", path, err)
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides config values with any environment variables that are set
+func (c *Config) applyEnv() error {
+	var errs []error
+
+	envString("STORAGE", &c.Storage)
+	errs = append(errs, envBool("MODULES_JOURNAL", &c.Modules.Journal))
+	errs = append(errs, envBool("MODULES_CERTIFICATES", &c.Modules.Certificates))
+	errs = append(errs, envBool("MODULES_EXPERIENCE", &c.Modules.Experience))
+	errs = append(errs, envBool("MODULES_QUALIFICATIONS", &c.Modules.Qualifications))
+	errs = append(errs, envBool("MODULES_SKILLS", &c.Modules.Skills))
+	errs = append(errs, envBool("MODULES_AWARDS", &c.Modules.Awards))
+	errs = append(errs, envBool("MODULES_LANGUAGES", &c.Modules.Languages))
+	errs = append(errs, envBool("MODULES_RECOMMENDATIONS", &c.Modules.Recommendations))
+	errs = append(errs, envBool("MODULES_RESUME", &c.Modules.Resume))
+	errs = append(errs, envBool("MODULES_SUBSCRIPTIONS", &c.Modules.Subscriptions))
+	envString("MONGO_URI", &c.Mongo.URI)
+	envString("MONGO_DB_NAME", &c.Mongo.Database)
+	errs = append(errs, envDuration("MONGO_OPERATION_TIMEOUT", &c.Mongo.OperationTimeout))
+	envString("MONGO_PUBLIC_READ_PREFERENCE", &c.Mongo.PublicReadPreference)
+	errs = append(errs, envInt("MONGO_MAX_POOL_SIZE", &c.Mongo.MaxPoolSize))
+	errs = append(errs, envInt("MONGO_MIN_POOL_SIZE", &c.Mongo.MinPoolSize))
+	errs = append(errs, envBool("MONGO_CHANGE_STREAMS", &c.Mongo.ChangeStreams))
+	errs = append(errs, envInt("MONGO_CONNECT_RETRIES", &c.Mongo.ConnectRetries))
+	errs = append(errs, envDuration("MONGO_CONNECT_RETRY_BACKOFF", &c.Mongo.ConnectRetryBackoff))
+	errs = append(errs, envDuration("MONGO_HEALTH_CHECK_INTERVAL", &c.Mongo.HealthCheckInterval))
+	envString("POSTGRES_URL", &c.Postgres.URL)
+	envString("MIGRATIONS_ON_START", &c.Migrations.OnStart)
+	errs = append(errs, envBool("READ_ONLY", &c.ReadOnly.Enabled))
+	errs = append(errs, envDuration("READ_ONLY_CACHE_MAX_AGE", &c.ReadOnly.CacheMaxAge))
+	envString("READ_ONLY_WRITE_URL", &c.ReadOnly.WriteURL)
+	envString("REDIS_URL", &c.Cache.RedisURL)
+	envString("JOBS_BACKEND", &c.Jobs.Backend)
+	envString("JOBS_REDIS_URL", &c.Jobs.RedisURL)
+	errs = append(errs, envInt("JOBS_WORKERS", &c.Jobs.Workers))
+	errs = append(errs, envBool("WEBHOOKS_ALLOW_PRIVATE_NETWORKS", &c.Webhooks.AllowPrivateNetworks))
+	errs = append(errs, envDuration("RECOVERY_ALERT_INTERVAL", &c.Recovery.AlertInterval))
+	errs = append(errs, envBool("ACTIVITYPUB_ENABLED", &c.ActivityPub.Enabled))
+	errs = append(errs, envBool("ACTIVITYPUB_ALLOW_PRIVATE_NETWORKS", &c.ActivityPub.AllowPrivateNetworks))
+	errs = append(errs, envBool("BILLING_ENABLED", &c.Billing.Enabled))
+	envString("STRIPE_SECRET_KEY", &c.Billing.Stripe.SecretKey)
+	envString("STRIPE_WEBHOOK_SECRET", &c.Billing.Stripe.WebhookSecret)
+	errs = append(errs, envInt("BODY_LIMIT_DEFAULT", &c.BodyLimits.Default))
+	errs = append(errs, envDuration("HTTP_READ_HEADER_TIMEOUT", &c.Timeouts.ReadHeader))
+	errs = append(errs, envDuration("HTTP_IDLE_TIMEOUT", &c.Timeouts.Idle))
+	errs = append(errs, envDuration("HTTP_READ_TIMEOUT", &c.Timeouts.Default.Read))
+	errs = append(errs, envDuration("HTTP_WRITE_TIMEOUT", &c.Timeouts.Default.Write))
+	errs = append(errs, envDuration("HTTP_UPLOAD_READ_TIMEOUT", &c.Timeouts.Uploads.Read))
+	errs = append(errs, envDuration("HTTP_UPLOAD_WRITE_TIMEOUT", &c.Timeouts.Uploads.Write))
+	errs = append(errs, envDuration("HTTP_EXPORT_WRITE_TIMEOUT", &c.Timeouts.Exports.Write))
+	envString("OPENAPI_VALIDATION", &c.OpenAPI.Validation)
+	errs = append(errs, envDuration("IDEMPOTENCY_RETENTION", &c.Idempotency.Retention))
+	errs = append(errs, envBool("SCRAPING_ENABLED", &c.Scraping.Enabled))
+	envString("SCRAPING_ACTION", &c.Scraping.Action)
+	errs = append(errs, envBool("REQUIRE_IF_MATCH", &c.RequireIfMatch))
+	errs = append(errs, envInt("BATCH_MAX_REQUESTS", &c.Batch.MaxRequests))
+	errs = append(errs, envBool("DEMO_ENABLED", &c.Demo.Enabled))
+	envString("DEMO_TENANT", &c.Demo.Tenant)
+	envString("DEMO_PASSWORD", &c.Demo.Password)
+	envString("SEARCH_BACKEND", &c.Search.Backend)
+	envString("ELASTICSEARCH_URL", &c.Search.Elasticsearch.URL)
+	envString("ELASTICSEARCH_USERNAME", &c.Search.Elasticsearch.Username)
+	envString("ELASTICSEARCH_PASSWORD", &c.Search.Elasticsearch.Password)
+	envString("VECTORS_BACKEND", &c.Search.Vectors.Backend)
+	envString("QDRANT_URL", &c.Search.Vectors.Qdrant.URL)
+	envString("QDRANT_API_KEY", &c.Search.Vectors.Qdrant.APIKey)
+	errs = append(errs, envInt("GRPC_LISTEN_PORT", &c.GRPC.ListenPort))
+	envString("PUBLIC_BASE_URL", &c.PublicBaseURL)
+	envList("TRUSTED_PROXIES", &c.TrustedProxies)
+	envList("CLIENT_IP_HEADERS", &c.ClientIPHeaders)
+	envString("TRUSTED_PLATFORM", &c.TrustedPlatform)
+	errs = append(errs, envInt("LISTEN_PORT", &c.ListenPort))
+	errs = append(errs, envDuration("SHUTDOWN_TIMEOUT", &c.ShutdownTimeout))
+
+	envString("JWT_SECRET", &c.JWT.Secret)
+	errs = append(errs, envDuration("JWT_EXPIRY", &c.JWT.Expiry))
+	errs = append(errs, envDuration("JWT_SUDO_WINDOW", &c.JWT.SudoWindow))
+
+	envString("IMAGE_STORE", &c.ImageStore.Type)
+	envString("LOCAL_PATH", &c.ImageStore.LocalPath)
+	envString("S3_BUCKET", &c.ImageStore.S3.Bucket)
+	envString("AWS_REGION", &c.ImageStore.S3.Region)
+	envString("AWS_ACCESS_KEY_ID", &c.ImageStore.S3.AccessKeyID)
+	envString("AWS_SECRET_ACCESS_KEY", &c.ImageStore.S3.SecretAccessKey)
+	envString("AWS_S3_ENDPOINT", &c.ImageStore.S3.Endpoint)
+	errs = append(errs, envBool("IMAGE_STRIP_METADATA", &c.ImageStore.StripMetadata))
+	errs = append(errs, envBool("IMAGE_APPLY_ORIENTATION", &c.ImageStore.ApplyOrientation))
+	envList("IMAGE_VARIANTS", &c.ImageStore.Variants.Formats)
+	envString("IMAGE_CDN_BASE_URL", &c.ImageStore.CDN.BaseURL)
+	errs = append(errs, envBool("IMAGE_CDN_PRIVATE", &c.ImageStore.CDN.Private))
+	envString("IMAGE_CDN_KEY_PAIR_ID", &c.ImageStore.CDN.KeyPairID)
+	envString("IMAGE_CDN_PRIVATE_KEY_FILE", &c.ImageStore.CDN.PrivateKeyFile)
+
+	envList("CORS_ALLOWED_ORIGINS", &c.CORS.AllowedOrigins)
+	errs = append(errs, envBool("CORS_ALLOW_CREDENTIALS", &c.CORS.AllowCredentials))
+
+	envString("EMAIL_PROVIDER", &c.Email.Provider)
+	envString("SMTP_HOST", &c.Email.SMTPHost)
+	errs = append(errs, envInt("SMTP_PORT", &c.Email.SMTPPort))
+	envString("SMTP_USERNAME", &c.Email.SMTPUsername)
+	envString("SMTP_PASSWORD", &c.Email.SMTPPassword)
+	envString("SMTP_FROM", &c.Email.From)
+	envString("SES_REGION", &c.Email.SES.Region)
+	envString("SES_ACCESS_KEY_ID", &c.Email.SES.AccessKeyID)
+	envString("SES_SECRET_ACCESS_KEY", &c.Email.SES.SecretAccessKey)
+	envString("SENDGRID_API_KEY", &c.Email.SendGrid.APIKey)
+
+	envString("AI_PROVIDER", &c.AI.Provider)
+	envString("AI_API_KEY", &c.AI.APIKey)
+	envString("AI_MODEL", &c.AI.Model)
+	envString("AI_BASE_URL", &c.AI.BaseURL)
+	errs = append(errs, envInt("AI_QUOTA_REQUESTS", &c.AI.Quota.Requests))
+	envString("AI_QUOTA_PERIOD", &c.AI.Quota.Period)
+	envString("EMBEDDINGS_PROVIDER", &c.AI.Embeddings.Provider)
+	envString("EMBEDDINGS_API_KEY", &c.AI.Embeddings.APIKey)
+	envString("EMBEDDINGS_MODEL", &c.AI.Embeddings.Model)
+	envString("EMBEDDINGS_BASE_URL", &c.AI.Embeddings.BaseURL)
+
+	envString("RESUME_PARSER", &c.Resume.Parser)
+
+	envString("SCANNER", &c.Scan.Scanner)
+	envString("CLAMAV_ADDRESS", &c.Scan.ClamAV.Address)
+	envString("VIRUSTOTAL_API_KEY", &c.Scan.VirusTotal.APIKey)
+
+	envString("TLS_CERT_FILE", &c.TLS.CertFile)
+	envString("TLS_KEY_FILE", &c.TLS.KeyFile)
+	errs = append(errs, envBool("TLS_REDIRECT_HTTP", &c.TLS.RedirectHTTP))
+	errs = append(errs, envInt("TLS_HTTP_PORT", &c.TLS.HTTPPort))
+	if os.Getenv("TLS_AUTOCERT_DOMAINS") != "" {
+		c.TLS.Autocert.Enabled = true
+		envList("TLS_AUTOCERT_DOMAINS", &c.TLS.Autocert.Domains)
+	}
+	envString("TLS_AUTOCERT_EMAIL", &c.TLS.Autocert.Email)
+	envString("TLS_AUTOCERT_CACHE", &c.TLS.Autocert.Cache)
+	envString("TLS_AUTOCERT_CACHE_DIR", &c.TLS.Autocert.CacheDir)
+	errs = append(errs, envBool("TLS_AUTOCERT_CUSTOM_DOMAINS", &c.TLS.Autocert.CustomDomains))
+
+	envString("LOG_LEVEL", &c.Log.Level)
+	envString("LOG_FORMAT", &c.Log.Format)
+
+	errs = append(errs, envBool("TRACING_ENABLED", &c.Tracing.Enabled))
+	envString("OTEL_EXPORTER_OTLP_ENDPOINT", &c.Tracing.Endpoint)
+	errs = append(errs, envBool("OTEL_EXPORTER_OTLP_INSECURE", &c.Tracing.Insecure))
+	envString("OTEL_SERVICE_NAME", &c.Tracing.ServiceName)
+	errs = append(errs, envFloat("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio))
+
+	return errors.Join(errs...)
+}
+
+// Validate checks that the configuration is complete and consistent
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.ListenPort < 1 || c.ListenPort > 65535 {
+		errs = append(errs, fmt.Errorf("listen-port must be between 1 and 65535"))
+	}
+	if c.ShutdownTimeout < 0 {
+		errs = append(errs, fmt.Errorf("shutdown-timeout must not be negative"))
+	}
+	for i, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Errorf("trusted-proxies[%d] must be an IP address or CIDR range", i))
+		}
+	}
+	if c.TrustedPlatform != "" && c.TrustedPlatform != "cloudflare" && c.TrustedPlatform != "google-app-engine" {
+		errs = append(errs, fmt.Errorf("trusted-platform must be cloudflare, google-app-engine or empty"))
+	}
+	if c.TrustedPlatform != "" && len(c.TrustedProxies) == 0 {
+		errs = append(errs, fmt.Errorf("trusted-platform is only believed from trusted-proxies, which must list the platform's edge addresses or the proxy in front of the server"))
+	}
+	if c.Storage != "mongo" && c.Storage != "postgres" && c.Storage != "memory" {
+		errs = append(errs, fmt.Errorf("storage must be mongo, postgres or memory"))
+	}
+	if c.Mongo.ChangeStreams && c.Storage != "mongo" {
+		errs = append(errs, fmt.Errorf("mongodb.change-streams requires mongo storage"))
+	}
+	if c.Storage == "postgres" && c.Postgres.URL == "" {
+		errs = append(errs, fmt.Errorf("postgres.url is required when storage is postgres"))
+	}
+	if c.Migrations.OnStart != "apply" && c.Migrations.OnStart != "verify" && c.Migrations.OnStart != "skip" {
+		errs = append(errs, fmt.Errorf("migrations.on-start must be apply, verify or skip"))
+	}
+	if c.Mongo.URI == "" {
+		errs = append(errs, fmt.Errorf("mongodb.uri is required"))
+	}
+	if c.Mongo.Database == "" {
+		errs = append(errs, fmt.Errorf("mongodb.database is required"))
+	}
+	if c.Mongo.OperationTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("mongodb.operation-timeout must be positive"))
+	}
+	errs = append(errs, c.Mongo.validate()...)
+	if c.Cache.RedisURL != "" && (c.Cache.ProfileTTL <= 0 || c.Cache.SkillsTTL <= 0 || c.Cache.JournalListTTL <= 0) {
+		errs = append(errs, fmt.Errorf("cache TTLs must be positive"))
+	}
+	if c.Cache.AggregateTTL < 0 {
+		errs = append(errs, fmt.Errorf("cache.aggregate-ttl must not be negative"))
+	}
+	if c.Cache.AggregateTTL > 0 && c.Cache.RedisURL == "" && c.Cache.AggregateSize <= 0 {
+		errs = append(errs, fmt.Errorf("cache.aggregate-size must be positive when full profiles are cached in memory"))
+	}
+	if c.Jobs.Backend != "storage" && c.Jobs.Backend != "redis" {
+		errs = append(errs, fmt.Errorf("jobs.backend must be storage or redis"))
+	}
+	if c.Jobs.Backend == "redis" && c.Jobs.RedisURL == "" {
+		errs = append(errs, fmt.Errorf("jobs.redis-url is required when jobs.backend is redis"))
+	}
+	if c.Jobs.Workers < 1 {
+		errs = append(errs, fmt.Errorf("jobs.workers must be at least 1"))
+	}
+	if c.Jobs.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("jobs.max-attempts must be at least 1"))
+	}
+	if c.Jobs.PollInterval <= 0 || c.Jobs.Lease <= 0 || c.Jobs.RetryBackoff <= 0 {
+		errs = append(errs, fmt.Errorf("jobs.poll-interval, jobs.lease and jobs.retry-backoff must be positive"))
+	}
+	if c.Jobs.Retention <= 0 {
+		errs = append(errs, fmt.Errorf("jobs.retention must be positive"))
+	}
+	if c.Scheduler.LeaderLease <= 0 {
+		errs = append(errs, fmt.Errorf("scheduler.leader-lease must be positive"))
+	}
+	if c.Webhooks.Timeout <= 0 || c.Webhooks.Retention <= 0 {
+		errs = append(errs, fmt.Errorf("webhooks.timeout and webhooks.retention must be positive"))
+	}
+	if c.Notifications.CertificateExpiryNotice <= 0 || c.Notifications.Retention <= 0 {
+		errs = append(errs, fmt.Errorf("notifications.certificate-expiry-notice and notifications.retention must be positive"))
+	}
+	for i, rule := range c.Archival.Rules {
+		if rule.Grace < 0 {
+			errs = append(errs, fmt.Errorf("archival.rules[%d].grace must not be negative", i))
+		}
+		if rule.Hide != "" && rule.Hide != "members" && rule.Hide != "private" {
+			errs = append(errs, fmt.Errorf("archival.rules[%d].hide must be members or private", i))
+		}
+	}
+	if c.Recovery.AlertInterval < 0 {
+		errs = append(errs, fmt.Errorf("recovery.alert-interval must not be negative"))
+	}
+	if c.Recommendations.InvitationTTL <= 0 || c.Recommendations.MaxPendingInvitations <= 0 {
+		errs = append(errs, fmt.Errorf("recommendations.invitation-ttl and recommendations.max-pending-invitations must be positive"))
+	}
+	if c.Inbox.SpamThreshold <= 0 || c.Inbox.SpamThreshold > 1 {
+		errs = append(errs, fmt.Errorf("inbox.spam-threshold must be above 0 and at most 1"))
+	}
+	if c.Inbox.MaxPerSender <= 0 || c.Inbox.SpamRetention <= 0 {
+		errs = append(errs, fmt.Errorf("inbox.max-per-sender and inbox.spam-retention must be positive"))
+	}
+	if c.Privacy.AccessTTL <= 0 || c.Privacy.MaxVisitors <= 0 {
+		errs = append(errs, fmt.Errorf("privacy.access-ttl and privacy.max-visitors must be positive"))
+	}
+	if c.Shares.DefaultTTL <= 0 || c.Shares.MaxTTL <= 0 || c.Shares.MaxActive <= 0 {
+		errs = append(errs, fmt.Errorf("shares.default-ttl, shares.max-ttl and shares.max-active must be positive"))
+	} else if c.Shares.DefaultTTL > c.Shares.MaxTTL {
+		errs = append(errs, fmt.Errorf("shares.default-ttl must not exceed shares.max-ttl"))
+	}
+	if c.Embed.MaxAge < 0 || c.Embed.JournalEntries <= 0 {
+		errs = append(errs, fmt.Errorf("embed.max-age must not be negative and embed.journal-entries must be positive"))
+	}
+	if c.Registration.MinSubmitTime < 0 {
+		errs = append(errs, fmt.Errorf("registration.min-submit-time must not be negative"))
+	}
+	if c.Attachments.OrphanGrace <= 0 || c.Attachments.URLExpiry <= 0 {
+		errs = append(errs, fmt.Errorf("attachments.orphan-grace and attachments.url-expiry must be positive"))
+	}
+	if c.ReadOnly.CacheMaxAge < 0 {
+		errs = append(errs, fmt.Errorf("read-only.cache-max-age must not be negative"))
+	}
+	if c.ReadOnly.Enabled {
+		// A replica writes nothing, so it must leave migrations and the demo data to the main instance
+		if c.Migrations.OnStart == "apply" {
+			errs = append(errs, fmt.Errorf("migrations.on-start must be verify or skip when read-only is enabled"))
+		}
+		if c.Demo.Enabled {
+			errs = append(errs, fmt.Errorf("demo.enabled cannot be combined with read-only"))
+		}
+		if u, err := url.Parse(c.ReadOnly.WriteURL); c.ReadOnly.WriteURL != "" && (err != nil || (u.Scheme != "http" && u.Scheme != "https")) {
+			errs = append(errs, fmt.Errorf("read-only.write-url must be an http or https URL"))
+		}
+	}
+	if c.Moderation.AutoHideReports < 0 {
+		errs = append(errs, fmt.Errorf("moderation.auto-hide-reports must not be negative"))
+	}
+	if c.Domains.MaxPerUser < 0 || c.Domains.PendingTTL <= 0 || c.Domains.CacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("domains.max-per-user and domains.cache-ttl must not be negative and domains.pending-ttl must be positive"))
+	}
+	if c.Modules.Resume && !(c.Modules.Experience && c.Modules.Qualifications && c.Modules.Skills) {
+		errs = append(errs, fmt.Errorf("modules.resume imports into experience, qualifications and skills, so it requires modules.experience, modules.qualifications and modules.skills"))
+	}
+	if c.Modules.Subscriptions && !c.Modules.Journal {
+		errs = append(errs, fmt.Errorf("modules.subscriptions sends digests of journal entries, so it requires modules.journal"))
+	}
+	if c.ActivityPub.Enabled && !c.Modules.Journal {
+		errs = append(errs, fmt.Errorf("activitypub federates journal entries, so it requires modules.journal"))
+	}
+	if c.ActivityPub.Enabled && c.ActivityPub.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("activitypub.timeout must be positive"))
+	}
+	if c.ActivityPub.Enabled && !strings.HasPrefix(c.PublicBaseURL, "https://") && !c.ActivityPub.AllowPrivateNetworks {
+		errs = append(errs, fmt.Errorf("activitypub requires an https public-base-url, as other servers refuse plain http"))
+	}
+	if c.Billing.Enabled {
+		errs = append(errs, c.Billing.validate()...)
+	}
+	if c.Quotas.StorageBytes < 0 {
+		errs = append(errs, fmt.Errorf("quotas.storage-bytes must not be negative"))
+	}
+	for collection, limit := range c.Quotas.Documents {
+		if !slices.Contains(QuotaCollections, collection) {
+			errs = append(errs, fmt.Errorf("quotas.documents[%q] must be one of %s", collection, strings.Join(QuotaCollections, ", ")))
+		}
+		if limit < 0 {
+			errs = append(errs, fmt.Errorf("quotas.documents[%q] must not be negative", collection))
+		}
+	}
+	if c.Idempotency.Retention <= 0 {
+		errs = append(errs, fmt.Errorf("idempotency.retention must be positive"))
+	}
+	if c.APIUsage.FlushInterval <= 0 || c.APIUsage.Retention <= 0 {
+		errs = append(errs, fmt.Errorf("api-usage.flush-interval and api-usage.retention must be positive"))
+	}
+	if c.Scraping.Enabled {
+		if c.Scraping.BurstRequests <= 0 || c.Scraping.BurstWindow <= 0 || c.Scraping.WalkLength <= 1 || c.Scraping.WalkWindow <= 0 {
+			errs = append(errs, fmt.Errorf("scraping.burst-requests, scraping.burst-window and scraping.walk-window must be positive and scraping.walk-length above 1"))
+		}
+		if c.Scraping.Penalty <= 0 || c.Scraping.Retention <= 0 {
+			errs = append(errs, fmt.Errorf("scraping.penalty and scraping.retention must be positive"))
+		}
+		switch c.Scraping.Action {
+		case "report", "block":
+		case "slow":
+			if c.Scraping.Delay <= 0 {
+				errs = append(errs, fmt.Errorf("scraping.delay must be positive to slow scrapers"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("scraping.action must be one of report, slow, block"))
+		}
+	}
+	if c.Batch.MaxRequests <= 0 {
+		errs = append(errs, fmt.Errorf("batch.max-requests must be positive"))
+	}
+	if c.BodyLimits.Default <= 0 || c.BodyLimits.MultipartMemory <= 0 {
+		errs = append(errs, fmt.Errorf("body-limits.default and body-limits.multipart-memory must be positive"))
+	}
+	for route, limit := range c.BodyLimits.Routes {
+		if limit <= 0 {
+			errs = append(errs, fmt.Errorf("body-limits.routes[%q] must be positive", route))
+		}
+	}
+	if c.Timeouts.ReadHeader <= 0 || c.Timeouts.MaxHeaderBytes <= 0 {
+		errs = append(errs, fmt.Errorf("timeouts.read-header and timeouts.max-header-bytes must be positive"))
+	}
+	for name, t := range map[string]RouteTimeouts{"default": c.Timeouts.Default, "uploads": c.Timeouts.Uploads, "exports": c.Timeouts.Exports} {
+		if t.Read < 0 || t.Write < 0 {
+			errs = append(errs, fmt.Errorf("timeouts.%s must not be negative", name))
+		}
+	}
+	if c.Timeouts.Idle < 0 {
+		errs = append(errs, fmt.Errorf("timeouts.idle must not be negative"))
+	}
+	for route, class := range c.Timeouts.Routes {
+		if class != "uploads" && class != "exports" {
+			errs = append(errs, fmt.Errorf("timeouts.routes[%q] must be uploads or exports", route))
+		}
+	}
+	switch c.OpenAPI.Validation {
+	case "enforce", "report", "off":
+	default:
+		errs = append(errs, fmt.Errorf("openapi.validation must be one of enforce, report, off"))
+	}
+	sanitized := Default().Sanitize
+	for field, policy := range c.Sanitize {
+		if _, ok := sanitized[field]; !ok {
+			errs = append(errs, fmt.Errorf("sanitize[%q] is not a sanitized field", field))
+		}
+		if policy != "none" && policy != "text" && policy != "rich-text" {
+			errs = append(errs, fmt.Errorf("sanitize[%q] must be none, text or rich-text", field))
+		}
+	}
+	for name, flag := range c.Features {
+		if flag.Percentage < 0 || flag.Percentage > 100 {
+			errs = append(errs, fmt.Errorf("features[%q].percentage must be between 0 and 100", name))
+		}
+	}
+	if c.Search.Backend != "storage" && c.Search.Backend != "elasticsearch" {
+		errs = append(errs, fmt.Errorf("search.backend must be storage or elasticsearch"))
+	}
+	if c.Search.Backend == "elasticsearch" && c.Search.Elasticsearch.URL == "" {
+		errs = append(errs, fmt.Errorf("search.elasticsearch.url is required when search.backend is elasticsearch"))
+	}
+	if c.Search.Vectors.Backend != "storage" && c.Search.Vectors.Backend != "qdrant" {
+		errs = append(errs, fmt.Errorf("search.vectors.backend must be storage or qdrant"))
+	}
+	if c.Search.Vectors.Backend == "qdrant" && !isWebURL(c.Search.Vectors.Qdrant.URL) {
+		errs = append(errs, fmt.Errorf("search.vectors.qdrant.url must be an http or https URL when search.vectors.backend is qdrant"))
+	}
+	if c.GRPC.ListenPort < 0 || c.GRPC.ListenPort > 65535 {
+		errs = append(errs, fmt.Errorf("grpc.listen-port must be between 0 and 65535"))
+	}
+	if c.GRPC.ListenPort != 0 && c.GRPC.ListenPort == c.ListenPort {
+		errs = append(errs, fmt.Errorf("grpc.listen-port must differ from listen-port"))
+	}
+	errs = append(errs, c.validateTenants()...)
+	if c.Demo.Enabled {
+		if len(c.Demo.Password) < 8 || len(c.Demo.Password) > 72 {
+			errs = append(errs, fmt.Errorf("demo.password must be between 8 and 72 characters"))
+		}
+		if len(c.Tenants) > 0 && !slices.ContainsFunc(c.Tenants, func(t TenantConfig) bool { return t.ID == c.Demo.Tenant }) {
+			errs = append(errs, fmt.Errorf("demo.tenant must be the ID of a tenant"))
+		}
+		if len(c.Tenants) == 0 && c.Demo.Tenant != "" {
+			errs = append(errs, fmt.Errorf("demo.tenant is only used with tenants"))
+		}
+	}
+	if c.JWT.Secret == "" {
+		errs = append(errs, fmt.Errorf("jwt.secret is required"))
+	}
+	if c.JWT.Expiry <= 0 {
+		errs = append(errs, fmt.Errorf("jwt.expiry must be positive"))
+	}
+	if c.JWT.SudoWindow <= 0 {
+		errs = append(errs, fmt.Errorf("jwt.sudo-window must be positive"))
+	}
+
+	switch c.ImageStore.Type {
+	case "local":
+	case "s3":
+		if c.ImageStore.S3.Bucket == "" {
+			errs = append(errs, fmt.Errorf("image-store.s3.bucket is required for the s3 image store"))
+		}
+		if c.ImageStore.S3.Region == "" {
+			errs = append(errs, fmt.Errorf("image-store.s3.region is required for the s3 image store"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("image-store.type must be local or s3"))
+	}
+	for i, format := range c.ImageStore.Variants.Formats {
+		if format != "webp" && format != "avif" {
+			errs = append(errs, fmt.Errorf("image-store.variants.formats[%d] must be webp or avif", i))
+		}
+	}
+	if c.ImageStore.Variants.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("image-store.variants.timeout must be positive"))
+	}
+	if cdn := c.ImageStore.CDN; cdn.BaseURL != "" && !isWebURL(cdn.BaseURL) {
+		errs = append(errs, fmt.Errorf("image-store.cdn.base-url must be an http or https URL"))
+	}
+	if cdn := c.ImageStore.CDN; cdn.Private {
+		if c.ImageStore.Type != "s3" {
+			errs = append(errs, fmt.Errorf("image-store.cdn.private requires the s3 image store"))
+		}
+		if cdn.BaseURL == "" || cdn.KeyPairID == "" || cdn.PrivateKeyFile == "" {
+			errs = append(errs, fmt.Errorf("image-store.cdn.base-url, key-pair-id and private-key-file are required when private"))
+		}
+		if cdn.URLExpiry <= 0 {
+			errs = append(errs, fmt.Errorf("image-store.cdn.url-expiry must be positive"))
+		}
+	}
+
+	switch c.Email.ActiveProvider() {
+	case "log":
+	case "smtp":
+		if c.Email.SMTPHost == "" {
+			errs = append(errs, fmt.Errorf("email.smtp-host is required for the smtp email provider"))
+		}
+		if c.Email.SMTPPort < 1 || c.Email.SMTPPort > 65535 {
+			errs = append(errs, fmt.Errorf("email.smtp-port must be between 1 and 65535"))
+		}
+	case "ses":
+		if c.Email.SES.Region == "" {
+			errs = append(errs, fmt.Errorf("email.ses.region is required for the ses email provider"))
+		}
+	case "sendgrid":
+		if c.Email.SendGrid.APIKey == "" {
+			errs = append(errs, fmt.Errorf("email.sendgrid.api-key is required for the sendgrid email provider"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("email.provider must be smtp, ses, sendgrid or log"))
+	}
+	if c.Email.ActiveProvider() != "log" && c.Email.From == "" {
+		errs = append(errs, fmt.Errorf("email.from is required when an email provider is configured"))
+	}
+	switch c.AI.Provider {
+	case "", "openai", "anthropic":
+	default:
+		errs = append(errs, fmt.Errorf("ai.provider must be openai, anthropic or empty"))
+	}
+	if c.AI.Provider != "" && c.AI.APIKey == "" {
+		errs = append(errs, fmt.Errorf("ai.api-key is required when ai.provider is set"))
+	}
+	if c.AI.Provider != "" && c.AI.Model == "" {
+		errs = append(errs, fmt.Errorf("ai.model is required when ai.provider is set"))
+	}
+	if c.AI.BaseURL != "" && !isWebURL(c.AI.BaseURL) {
+		errs = append(errs, fmt.Errorf("ai.base-url must be an http or https URL"))
+	}
+	if c.AI.Quota.Requests < 0 {
+		errs = append(errs, fmt.Errorf("ai.quota.requests must not be negative"))
+	}
+	if c.AI.Quota.Period != "day" && c.AI.Quota.Period != "month" {
+		errs = append(errs, fmt.Errorf("ai.quota.period must be day or month"))
+	}
+	switch c.AI.Embeddings.Provider {
+	case "", "openai", "voyage":
+	default:
+		errs = append(errs, fmt.Errorf("ai.embeddings.provider must be openai, voyage or empty"))
+	}
+	if c.AI.Embeddings.Provider != "" && c.AI.Embeddings.APIKey == "" {
+		errs = append(errs, fmt.Errorf("ai.embeddings.api-key is required when ai.embeddings.provider is set"))
+	}
+	if c.AI.Embeddings.Provider != "" && c.AI.Embeddings.Model == "" {
+		errs = append(errs, fmt.Errorf("ai.embeddings.model is required when ai.embeddings.provider is set"))
+	}
+	if c.AI.Embeddings.BaseURL != "" && !isWebURL(c.AI.Embeddings.BaseURL) {
+		errs = append(errs, fmt.Errorf("ai.embeddings.base-url must be an http or https URL"))
+	}
+	switch c.Resume.Parser {
+	case "heuristic":
+	case "ai":
+		if c.AI.Provider == "" {
+			errs = append(errs, fmt.Errorf("resume.parser ai requires ai.provider"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("resume.parser must be heuristic or ai"))
+	}
+	switch c.Scan.Scanner {
+	case "":
+	case "clamav":
+		if c.Scan.ClamAV.Address == "" {
+			errs = append(errs, fmt.Errorf("scan.clamav.address is required for the clamav scanner"))
+		}
+	case "virustotal":
+		if c.Scan.VirusTotal.APIKey == "" {
+			errs = append(errs, fmt.Errorf("scan.virustotal.api-key is required for the virustotal scanner"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("scan.scanner must be clamav, virustotal or empty"))
+	}
+	if c.Scan.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("scan.timeout must be positive"))
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		errs = append(errs, fmt.Errorf("tls.cert-file and tls.key-file must be set together"))
+	}
+	if c.TLS.Autocert.Enabled && len(c.TLS.Autocert.Domains) == 0 && !c.TLS.Autocert.CustomDomains {
+		errs = append(errs, fmt.Errorf("tls.autocert.domains is required when autocert is enabled, unless it only serves custom domains"))
+	}
+	switch c.TLS.Autocert.Cache {
+	case "dir":
+	case "mongo":
+		if c.Storage != "mongo" {
+			errs = append(errs, fmt.Errorf("tls.autocert.cache can only be mongo with the mongo storage"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tls.autocert.cache must be dir or mongo"))
+	}
+	if c.TLS.HTTPPort < 1 || c.TLS.HTTPPort > 65535 {
+		errs = append(errs, fmt.Errorf("tls.http-port must be between 1 and 65535"))
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log.level must be debug, info, warn or error"))
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "text", "json":
+	default:
+		errs = append(errs, fmt.Errorf("log.format must be text or json"))
+	}
+
+	if c.Tracing.Enabled && c.Tracing.Endpoint == "" {
+		errs = append(errs, fmt.Errorf("tracing.endpoint is required when tracing is enabled"))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample-ratio must be between 0 and 1"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// tenantIDPattern keeps tenant IDs usable as a subdomain, cache key segment and directory name
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// validateTenants checks that every tenant is identifiable and has storage of its own
+func (c *Config) validateTenants() []error {
+	var errs []error
+	ids := map[string]bool{}
+	domains := map[string]bool{}
+	databases := map[string]bool{}
+	for i, t := range c.Tenants {
+		if !tenantIDPattern.MatchString(t.ID) {
+			errs = append(errs, fmt.Errorf("tenants[%d].id must be lowercase letters, digits and dashes", i))
+		}
+		if ids[t.ID] {
+			errs = append(errs, fmt.Errorf("tenants[%d].id %q is used by another tenant", i, t.ID))
+		}
+		ids[t.ID] = true
+		if t.CDNBaseURL != "" && !isWebURL(t.CDNBaseURL) {
+			errs = append(errs, fmt.Errorf("tenants[%d].cdn-base-url must be an http or https URL", i))
+		}
+		for _, domain := range t.Domains {
+			domain = strings.ToLower(domain)
+			if domains[domain] {
+				errs = append(errs, fmt.Errorf("tenants[%d] domain %q is used by another tenant", i, domain))
+			}
+			domains[domain] = true
+		}
+		switch c.Storage {
+		case "mongo":
+			if t.Database == "" {
+				errs = append(errs, fmt.Errorf("tenants[%d].database is required when storage is mongo", i))
+			}
+			if databases[t.Database] || t.Database == c.Mongo.Database {
+				errs = append(errs, fmt.Errorf("tenants[%d].database must not be shared with another tenant or mongodb.database", i))
+			}
+			databases[t.Database] = true
+		case "postgres":
+			if t.PostgresURL == "" {
+				errs = append(errs, fmt.Errorf("tenants[%d].postgres-url is required when storage is postgres", i))
+			}
+			if databases[t.PostgresURL] || t.PostgresURL == c.Postgres.URL {
+				errs = append(errs, fmt.Errorf("tenants[%d].postgres-url must not be shared with another tenant or postgres.url", i))
+			}
+			databases[t.PostgresURL] = true
+		}
+	}
+	return errs
+}
+
+// validate checks the read preference and connection pool settings
+func (m MongoConfig) validate() []error {
+	var errs []error
+	if !slices.Contains(mongoReadPreferences, m.PublicReadPreference) {
+		errs = append(errs, fmt.Errorf("mongodb.public-read-preference must be one of %s", strings.Join(mongoReadPreferences, ", ")))
+	}
+	if m.MaxStaleness < 0 || (m.MaxStaleness > 0 && m.MaxStaleness < Duration(90*time.Second)) {
+		errs = append(errs, fmt.Errorf("mongodb.max-staleness must be 0 or at least 90s"))
+	}
+	if m.MaxStaleness > 0 && m.PublicReadPreference == "primary" {
+		errs = append(errs, fmt.Errorf("mongodb.max-staleness cannot be set when public reads use the primary"))
+	}
+	if m.MaxPoolSize < 0 || m.MinPoolSize < 0 || (m.MaxPoolSize > 0 && m.MinPoolSize > m.MaxPoolSize) {
+		errs = append(errs, fmt.Errorf("mongodb.min-pool-size and max-pool-size must not be negative, and the minimum must not exceed the maximum"))
+	}
+	if m.MaxConnIdleTime < 0 || m.ConnectTimeout <= 0 || m.ServerSelectionTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("mongodb.connect-timeout and server-selection-timeout must be positive, and max-conn-idle-time must not be negative"))
+	}
+	if m.ConnectRetries < 0 || m.ConnectRetryBackoff <= 0 || m.HealthCheckInterval < 0 {
+		errs = append(errs, fmt.Errorf("mongodb.connect-retries and health-check-interval must not be negative, and connect-retry-backoff must be positive"))
+	}
+	return errs
+}
+
+// validate checks the plans and the Stripe account of enabled billing
+func (b BillingConfig) validate() []error {
+	var errs []error
+	if _, ok := b.Plans[b.DefaultPlan]; !ok {
+		errs = append(errs, fmt.Errorf("billing.default-plan must name one of billing.plans"))
+	}
+	prices := map[string]bool{}
+	for name, plan := range b.Plans {
+		if plan.MaxJournalEntries < 0 || plan.StorageBytes < 0 {
+			errs = append(errs, fmt.Errorf("billing.plans[%q] limits must not be negative", name))
+		}
+		if plan.PriceID != "" && prices[plan.PriceID] {
+			errs = append(errs, fmt.Errorf("billing.plans[%q].price-id must not be shared with another plan", name))
+		}
+		prices[plan.PriceID] = true
+	}
+	if b.Stripe.SecretKey == "" || b.Stripe.WebhookSecret == "" {
+		errs = append(errs, fmt.Errorf("billing.stripe.secret-key and billing.stripe.webhook-secret are required"))
+	}
+	if !isWebURL(b.Stripe.BaseURL) || !isWebURL(b.Stripe.SuccessURL) || !isWebURL(b.Stripe.CancelURL) {
+		errs = append(errs, fmt.Errorf("billing.stripe.base-url, success-url and cancel-url must be absolute http or https URLs"))
+	}
+	if b.Stripe.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("billing.stripe.timeout must be positive"))
+	}
+	return errs
+}
+
+// isWebURL reports whether the value is an absolute http or https URL
+func isWebURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func envString(key string, dst *string) {
+	if v := os.Getenv(key); v != "" {
+		*dst = v
+	}
+}
+
+func envList(key string, dst *[]string) {
+	if v := os.Getenv(key); v != "" {
+		var list []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*dst = list
+	}
+}
+
+func envInt(key string, dst *int) error {
+	if v := os.Getenv(key); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("error parsing %s environment variable: %w", key, err)
+		}
+		*dst = n
+	}
+	return nil
+}
+
+func envFloat(key string, dst *float64) error {
+	if v := os.Getenv(key); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("error parsing %s environment variable: %w", key, err)
+		}
+		*dst = f
+	}
+	return nil
+}
+
+func envBool(key string, dst *bool) error {
+	if v := os.Getenv(key); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("error parsing %s environment variable: %w", key, err)
+		}
+		*dst = b
+	}
+	return nil
+}
+
+func envDuration(key string, dst *Duration) error {
+	if v := os.Getenv(key); v != "" {
+		d, err := parseDuration(v)
+		if err != nil {
+			return fmt.Errorf("error parsing %s environment variable: %w", key, err)
+		}
+		*dst = Duration(d)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package demo
+
+import (
+	"profile-api/certificates"
+	"profile-api/experience"
+	"profile-api/journal"
+	"profile-api/qualifications"
+	"profile-api/skills"
+)
+
+// user is a demo user and everything they own. IDs are fixed, so links into the demo data keep working
+// after a reset.
+type user struct {
+	id             string
+	name           string
+	email          string
+	bio            string
+	interests      string
+	skills         []skills.Skill
+	experience     []experience.Experience
+	qualifications []qualifications.Qualification
+	certificates   []certificates.Certificate
+	posts          []post
+}
+
+// post is a public journal post of a demo user
+type post struct {
+	id       string
+	title    string
+	content  string
+	summary  string
+	taxonomy journal.Taxonomy
+	// daysAgo is how long before seeding the post was written
+	daysAgo int
+}
+
+var users = []user{
+	{
+		id:        "demo-alex-morgan",
+		name:      "Alex Morgan",
+		email:     "alex.morgan@demo.example",
+		bio:       "Backend engineer building APIs and the platforms behind them. Ten years of Go, Postgres and too many YAML files.",
+		interests: "Distributed systems, developer tooling, cycling",
+		skills: []skills.Skill{
+			{SkillID: "demo-alex-go", Name: "Go", ProficiencyLevel: "Expert", StartedAt: "2015-03-01", LastUsed: "2026-09-30", Description: "Services, CLIs and the odd code generator."},
+			{SkillID: "demo-alex-postgres", Name: "PostgreSQL", ProficiencyLevel: "Advanced", StartedAt: "2014-06-01", LastUsed: "2026-09-30", Description: "Schema design, query tuning and zero-downtime migrations."},
+			{SkillID: "demo-alex-kubernetes", Name: "Kubernetes", ProficiencyLevel: "Intermediate", StartedAt: "2019-01-01", LastUsed: "2026-08-15"},
+		},
+		experience: []experience.Experience{
+			{ExperienceID: "demo-alex-northwind", Company: "Northwind Payments", Position: "Staff Engineer", Start: "2021-04-01", Description: "Leads the ledger team, which moved settlement onto an event-sourced core."},
+			{ExperienceID: "demo-alex-contoso", Company: "Contoso Cloud", Position: "Software Engineer", Start: "2016-02-01", End: "2021-03-31", Description: "Built the provisioning API behind the managed database product."},
+		},
+		qualifications: []qualifications.Qualification{
+			{QualificationID: "demo-alex-bsc", Title: "BSc Computer Science", Institution: "University of Leeds", Start: "2011-09-01", End: "2014-06-30"},
+		},
+		certificates: []certificates.Certificate{
+			{CertificateID: "demo-alex-cka", Title: "Certified Kubernetes Administrator", Institution: "The Linux Foundation", Start: "2022-05-10", End: "2025-05-10"},
+		},
+		posts: []post{
+			{
+				id:       "demo-alex-post-migrations",
+				title:    "Migrations you can roll back",
+				content:  "Every schema change we ship now comes with its down script, reviewed alongside the up. It costs a few minutes per change and has saved two releases this year.\n\nThe rule of thumb: if you cannot write the rollback, split the change until you can.",
+				summary:  "Why every schema change should ship with a reviewed rollback script.",
+				taxonomy: journal.Taxonomy{Categories: []string{"Engineering"}, Subcategories: []string{"Databases"}, Topics: []string{"Migrations"}, Tags: []string{"postgres", "deployment"}},
+				daysAgo:  12,
+			},
+			{
+				id:       "demo-alex-post-oncall",
+				title:    "What a quiet on-call week taught me",
+				content:  "No pages for seven days, which felt like luck until we looked at why: the alerts we deleted last quarter were the noisy ones, and the ones left all pointed at user-facing symptoms.",
+				summary:  "Alerting on symptoms rather than causes made on-call quieter.",
+				taxonomy: journal.Taxonomy{Categories: []string{"Engineering"}, Subcategories: []string{"Operations"}, Topics: []string{"On-call"}, Tags: []string{"sre", "alerting"}},
+				daysAgo:  3,
+			},
+		},
+	},
+	{
+		id:        "demo-priya-shah",
+		name:      "Priya Shah",
+		email:     "priya.shah@demo.example",
+		bio:       "Data scientist turning messy event data into product decisions. Happiest with a notebook and a hypothesis.",
+		interests: "Experiment design, causal inference, bouldering",
+		skills: []skills.Skill{
+			{SkillID: "demo-priya-python", Name: "Python", ProficiencyLevel: "Expert", StartedAt: "2013-09-01", LastUsed: "2026-10-01"},
+			{SkillID: "demo-priya-sql", Name: "SQL", ProficiencyLevel: "Advanced", StartedAt: "2014-01-01", LastUsed: "2026-10-01"},
+			{SkillID: "demo-priya-stats", Name: "Statistics", ProficiencyLevel: "Advanced", StartedAt: "2012-09-01", LastUsed: "2026-09-20", Description: "A/B testing, Bayesian models and explaining p-values kindly."},
+		},
+		experience: []experience.Experience{
+			{ExperienceID: "demo-priya-fabrikam", Company: "Fabrikam Retail", Position: "Senior Data Scientist", Start: "2020-07-01", Description: "Runs the experimentation platform used by every product team."},
+			{ExperienceID: "demo-priya-adatum", Company: "Adatum Health", Position: "Data Analyst", Start: "2017-01-01", End: "2020-06-30"},
+		},
+		qualifications: []qualifications.Qualification{
+			{QualificationID: "demo-priya-msc", Title: "MSc Statistics", Institution: "University of Edinburgh", Start: "2015-09-01", End: "2016-09-30"},
+			{QualificationID: "demo-priya-bsc", Title: "BSc Mathematics", Institution: "University of Warwick", Start: "2012-09-01", End: "2015-06-30"},
+		},
+		posts: []post{
+			{
+				id:       "demo-priya-post-peeking",
+				title:    "Stop peeking at your experiments",
+				content:  "Checking an A/B test every morning and stopping when it looks significant inflates false positives dramatically. Decide the sample size up front, or use a sequential test designed for peeking.",
+				summary:  "Repeatedly checking experiments inflates false positives; plan sample sizes or use sequential tests.",
+				taxonomy: journal.Taxonomy{Categories: []string{"Data"}, Subcategories: []string{"Experimentation"}, Topics: []string{"A/B testing"}, Tags: []string{"statistics"}},
+				daysAgo:  20,
+			},
+		},
+	},
+	{
+		id:        "demo-sam-okafor",
+		name:      "Sam Okafor",
+		email:     "sam.okafor@demo.example",
+		bio:       "Product designer who prototypes in code. Currently obsessed with accessible forms.",
+		interests: "Accessibility, typography, film photography",
+		skills: []skills.Skill{
+			{SkillID: "demo-sam-figma", Name: "Figma", ProficiencyLevel: "Expert", StartedAt: "2017-05-01", LastUsed: "2026-10-02"},
+			{SkillID: "demo-sam-a11y", Name: "Accessibility", ProficiencyLevel: "Advanced", StartedAt: "2019-02-01", LastUsed: "2026-10-02", Description: "WCAG audits and inclusive design reviews."},
+			{SkillID: "demo-sam-typescript", Name: "TypeScript", ProficiencyLevel: "Intermediate", StartedAt: "2020-01-01", LastUsed: "2026-09-12"},
+		},
+		experience: []experience.Experience{
+			{ExperienceID: "demo-sam-litware", Company: "Litware Studio", Position: "Lead Product Designer", Start: "2022-01-10", Description: "Leads design for the self-service onboarding flows."},
+		},
+		certificates: []certificates.Certificate{
+			{CertificateID: "demo-sam-cpacc", Title: "Certified Professional in Accessibility Core Competencies", Institution: "IAAP", Start: "2023-03-01"},
+		},
+		posts: []post{
+			{
+				id:       "demo-sam-post-labels",
+				title:    "Placeholders are not labels",
+				content:  "A placeholder disappears the moment someone starts typing, taking the only hint about the field with it. Keep a visible label above every input; use the placeholder for an example value, if at all.",
+				summary:  "Use visible labels on form fields rather than relying on placeholders.",
+				taxonomy: journal.Taxonomy{Categories: []string{"Design"}, Subcategories: []string{"Accessibility"}, Topics: []string{"Forms"}, Tags: []string{"a11y", "ux"}},
+				daysAgo:  7,
+			},
+		},
+	},
+}
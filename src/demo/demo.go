@@ -0,0 +1,300 @@
+// Package demo seeds example users with profiles, CV sections and public journal posts, so evaluators and
+// frontend developers get a working dataset on a fresh deployment.
+//
+// The demo users are created on startup when missing, on the demo tenant's site in multi-tenant
+// deployments, and all log in with the configured password. Admins reset them, discarding any changes made
+// to their data, through the admin endpoint or `profile-api admin demo-reset`.
+package demo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/awards"
+	"profile-api/certificates"
+	"profile-api/config"
+	"profile-api/experience"
+	"profile-api/journal"
+	"profile-api/languages"
+	"profile-api/profile"
+	"profile-api/qualifications"
+	"profile-api/skills"
+	"profile-api/store"
+	"profile-api/tenant"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Repositories holds the storage the demo users are written to. Certificates and Journals are nil when their
+// module is disabled, leaving them out of the demo data.
+type Repositories struct {
+	Users          auth.Repository
+	Profiles       profile.Repository
+	Skills         skills.Repository
+	Experience     experience.Repository
+	Qualifications qualifications.Repository
+	Certificates   certificates.Repository
+	Awards         awards.Repository
+	Languages      languages.Repository
+	Journals       journal.Repository
+}
+
+var repos Repositories
+var settings config.DemoConfig
+
+// Configure sets where the demo users are written and whether they are seeded
+func Configure(r Repositories, cfg config.DemoConfig) {
+	repos = r
+	settings = cfg
+}
+
+// Enabled reports whether demo data is seeded
+func Enabled() bool {
+	return settings.Enabled
+}
+
+// Seed creates the demo users that are missing, with everything they own. Users that exist are left as
+// they are.
+func Seed(ctx context.Context) error {
+	if !settings.Enabled {
+		return nil
+	}
+	ctx = withDemoTenant(ctx)
+	var password string
+	created := 0
+	for _, u := range users {
+		_, err := repos.Users.FindByID(ctx, u.id)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, store.ErrNotFound) {
+			return fmt.Errorf("could not check demo user %s: %w", u.id, err)
+		}
+		if password == "" {
+			hashed, err := bcrypt.GenerateFromPassword([]byte(settings.Password), bcrypt.DefaultCost)
+			if err != nil {
+				return fmt.Errorf("could not hash demo password: %w", err)
+			}
+			password = string(hashed)
+		}
+		if err := create(ctx, u, password); err != nil {
+			return fmt.Errorf("could not create demo user %s: %w", u.id, err)
+		}
+		created++
+	}
+	if created > 0 {
+		slog.InfoContext(ctx, "Seeded demo users", "created", created, "tenant", settings.Tenant)
+	}
+	return nil
+}
+
+// Reset deletes the demo users, along with any changes made to their data, and seeds them again
+func Reset(ctx context.Context) error {
+	if !settings.Enabled {
+		return nil
+	}
+	for _, u := range users {
+		if err := remove(withDemoTenant(ctx), u.id); err != nil {
+			return fmt.Errorf("could not delete demo user %s: %w", u.id, err)
+		}
+	}
+	return Seed(ctx)
+}
+
+// remove deletes the user and everything they own. The items are deleted one by one, including those the
+// user added, as not every backend deletes them along with the user; the profile is replaced when seeding.
+func remove(ctx context.Context, userID string) error {
+	skillList, err := repos.Skills.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, item := range skillList {
+		if err := ignoreNotFound(repos.Skills.Delete(ctx, userID, item.SkillID)); err != nil {
+			return err
+		}
+	}
+	experienceList, err := repos.Experience.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, item := range experienceList {
+		if err := ignoreNotFound(repos.Experience.Delete(ctx, userID, item.ExperienceID)); err != nil {
+			return err
+		}
+	}
+	qualificationList, err := repos.Qualifications.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, item := range qualificationList {
+		if err := ignoreNotFound(repos.Qualifications.Delete(ctx, userID, item.QualificationID)); err != nil {
+			return err
+		}
+	}
+	if repos.Certificates != nil {
+		certificateList, err := repos.Certificates.List(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, item := range certificateList {
+			if err := ignoreNotFound(repos.Certificates.Delete(ctx, userID, item.CertificateID)); err != nil {
+				return err
+			}
+		}
+	}
+	awardList, err := repos.Awards.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, item := range awardList {
+		if err := ignoreNotFound(repos.Awards.Delete(ctx, userID, item.AwardID)); err != nil {
+			return err
+		}
+	}
+	languageList, err := repos.Languages.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, item := range languageList {
+		if err := ignoreNotFound(repos.Languages.Delete(ctx, userID, item.LanguageID)); err != nil {
+			return err
+		}
+	}
+	if repos.Journals != nil {
+		journals, err := repos.Journals.List(ctx, journal.Filter{UserID: userID})
+		if err != nil {
+			return err
+		}
+		for _, entry := range journals {
+			if err := ignoreNotFound(repos.Journals.Delete(ctx, entry.JournalID, userID)); err != nil {
+				return err
+			}
+		}
+	}
+	return ignoreNotFound(repos.Users.Delete(ctx, userID))
+}
+
+func ignoreNotFound(err error) error {
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// withDemoTenant returns a context for the demo tenant's data
+func withDemoTenant(ctx context.Context) context.Context {
+	if settings.Tenant == "" {
+		return ctx
+	}
+	return tenant.WithID(ctx, settings.Tenant)
+}
+
+// create writes the demo user and everything they own
+func create(ctx context.Context, u user, password string) error {
+	now := time.Now()
+	err := repos.Users.Create(ctx, auth.User{
+		ID:        u.id,
+		Name:      u.name,
+		Email:     u.email,
+		Password:  password,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+	err = repos.Profiles.Save(ctx, profile.Profile{
+		UserID:    u.id,
+		Name:      &u.name,
+		Email:     &u.email,
+		Bio:       &u.bio,
+		Interests: &u.interests,
+		UpdatedAt: &now,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, item := range u.skills {
+		item.UserID = u.id
+		if err := repos.Skills.Create(ctx, item); err != nil {
+			return err
+		}
+	}
+	for _, item := range u.experience {
+		item.UserID = u.id
+		if err := repos.Experience.Create(ctx, item); err != nil {
+			return err
+		}
+	}
+	for _, item := range u.qualifications {
+		item.UserID = u.id
+		if err := repos.Qualifications.Create(ctx, item); err != nil {
+			return err
+		}
+	}
+	if repos.Certificates != nil {
+		for _, item := range u.certificates {
+			item.UserID = u.id
+			if err := repos.Certificates.Create(ctx, item); err != nil {
+				return err
+			}
+		}
+	}
+	if repos.Journals != nil {
+		for _, p := range u.posts {
+			written := now.AddDate(0, 0, -p.daysAgo)
+			err := repos.Journals.Create(ctx, journal.JournalEntry{
+				JournalID: p.id,
+				UserID:    u.id,
+				Version:   1,
+				Entries:   []journal.Entry{{Version: 1, Title: p.title, Content: p.content, Attachments: []string{}, UpdatedAt: written}},
+				Status:    journal.StatusPublic,
+				Taxonomy:  p.taxonomy,
+				Summary:   p.summary,
+				CreatedAt: written,
+				UpdatedAt: written,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ResetDemo restores the demo users
+//
+//	@Summary		Reset the demo data
+//	@Description	Deletes the demo users, discarding any changes made to their profiles, CV sections and journal posts, and creates them again. Only available on the demo site when demo data is enabled. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	map[string]string
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		403	{object}	apierror.Response	"Admin access required"
+//	@Failure		404	{object}	apierror.Response	"Demo data is not enabled on this site"
+//	@Failure		500	{object}	apierror.Response	"Could not reset demo data"
+//	@Router			/admin/demo/reset [post]
+func ResetDemo(c *gin.Context) {
+	if tenant.ID(c.Request.Context()) != settings.Tenant {
+		apierror.Abort(c, apierror.NotFound("Demo data is not enabled on this site"))
+		return
+	}
+	if err := Reset(c.Request.Context()); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not reset demo data"))
+		return
+	}
+	slog.InfoContext(c.Request.Context(), "Demo data reset", "admin", c.GetString("userID"))
+	c.JSON(http.StatusOK, gin.H{"message": "Demo data reset"})
+}
+
+// InitializeAdminRoutes registers the demo reset endpoint. The router must only admit admins.
+func InitializeAdminRoutes(router *gin.RouterGroup) {
+	router.POST("/reset", ResetDemo)
+}
@@ -18,144 +18,189 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Login a user",
-                "consumes": [
-                    "application/json"
-                ],
+        "/.well-known/webfinger": {
+            "get": {
+                "description": "Resolves acct:{userid}@{host} or the actor's URL to the user's ActivityPub actor, for Mastodon and other servers to follow them. Only available when ActivityPub federation is enabled.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "activitypub"
                 ],
-                "summary": "Login",
+                "summary": "Find a user's actor",
                 "parameters": [
                     {
-                        "description": "Login request object",
-                        "name": "login",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/auth.LoginRequest"
-                        }
+                        "type": "string",
+                        "description": "The account, as acct:{userid}@{host}, or the actor's URL",
+                        "name": "resource",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Token",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/activitypub.WebFinger"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Missing or invalid resource",
                         "schema": {
-                            "$ref": "#/definitions/auth.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "401": {
-                        "description": "Invalid email or password",
+                    "404": {
+                        "description": "No such user on this site",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not find user",
                         "schema": {
-                            "$ref": "#/definitions/auth.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/auth/logout": {
-            "post": {
-                "description": "Logout the currently logged in user",
+        "/activity/{userid}": {
+            "get": {
+                "description": "Returns the user's recent public activity, newest first: journal entries they published, certificates they added and roles they added to their experience. Each item carries its document without the fields the requester may not see; documents since deleted or unpublished are left out, so a page may hold fewer items than the limit. Pass the returned next value as before to get the following page.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "activity"
+                ],
+                "summary": "Get a user's activity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return activity from before this RFC 3339 time, as returned in next",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of activities to read (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Logout",
                 "responses": {
                     "200": {
-                        "description": "Logged out",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/activity.Feed"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid before or limit",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve activity",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/auth/register": {
-            "post": {
-                "description": "Register a new user",
-                "consumes": [
-                    "application/json"
+        "/admin/attachments/collection": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Checks every file stored for journal entries against the content and attachments of every version of its owner's entries, without changing anything, and reports what the daily collection would do: the files it would find unreferenced for the first time and keep for the grace period, those referred to again, and those it would delete after staying unreferenced for the grace period, with the storage they would free. Only admins can preview collections.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
-                ],
-                "summary": "Register",
-                "parameters": [
-                    {
-                        "description": "Registration request object",
-                        "name": "register",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/auth.RegisterRequest"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "Preview journal file collection",
                 "responses": {
-                    "201": {
-                        "description": "User created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/attachments.Report"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/auth.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "403": {
+                        "description": "Not an admin",
                         "schema": {
-                            "$ref": "#/definitions/auth.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Could not check journal files",
                         "schema": {
-                            "$ref": "#/definitions/auth.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/certificates/{userid}": {
+        "/admin/audit": {
             "get": {
-                "description": "Retrieves all certificates for a given user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Lists changes to user data across every user, newest first, optionally only those to one user's data or made by one user. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Certificates"
+                    "admin"
                 ],
-                "summary": "Get all certificates",
+                "summary": "List the audit log",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
+                        "description": "Only list changes to this user's data",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list changes made by this user",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list changes to this kind of resource, such as skill or journal",
+                        "name": "resource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries to return (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -164,53 +209,73 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/certificates.Certificate"
+                                "$ref": "#/definitions/audit.Entry"
                             }
                         }
                     },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
                     "500": {
-                        "description": "error\":\t\"Could not retrieve certificates",
+                        "description": "Could not retrieve audit log",
                         "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Creates a new certificate entry for a user",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/admin/cache/profiles": {
+            "get": {
+                "description": "Returns the hits, misses and invalidations of the full profile cache on this replica since it started, and how many full profiles it keeps when they are kept in its memory rather than in Redis. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Certificates"
+                    "admin"
                 ],
-                "summary": "Create a new certificate entry",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
+                "summary": "Get full profile cache statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/profile.CacheStats"
+                        }
                     },
-                    {
-                        "description": "Certificate JSON object",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/certificates.Certificate"
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
+                }
+            }
+        },
+        "/admin/demo/reset": {
+            "post": {
+                "description": "Deletes the demo users, discarding any changes made to their profiles, CV sections and journal posts, and creates them again. Only available on the demo site when demo data is enabled. Requires the admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
                 ],
+                "summary": "Reset the demo data",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -221,58 +286,43 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "error\":\t\"Invalid request body",
-                        "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
-                        }
-                    },
                     "401": {
-                        "description": "error\":\t\"Unauthorized",
+                        "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "403": {
-                        "description": "error\":\t\"Forbidden",
+                        "description": "Admin access required",
                         "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "404": {
-                        "description": "error\":\t\"User not found",
+                        "description": "Demo data is not enabled on this site",
                         "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "error\":\t\"Certificate already exists",
-                        "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "error\":\t\"Could not create certificate",
+                        "description": "Could not reset demo data",
                         "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/certificates/{userid}/{certificateid}": {
+        "/admin/email-log/{userid}": {
             "get": {
-                "description": "Retrieves a specific certificate entry for a user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Lists delivery attempts of emails sent on behalf of a user, newest first, including the provider used and any delivery error. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Certificates"
+                    "admin"
                 ],
-                "summary": "Get a certificate entry",
+                "summary": "List a user's email send log",
                 "parameters": [
                     {
                         "type": "string",
@@ -282,132 +332,81 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Certificate ID",
-                        "name": "certificateid",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Maximum number of entries to return (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/certificates.Certificate"
-                        }
-                    },
-                    "400": {
-                        "description": "error\":\t\"Invalid request body",
-                        "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/email.LogEntry"
+                            }
                         }
                     },
                     "401": {
-                        "description": "error\":\t\"Unauthorized",
+                        "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "403": {
-                        "description": "error\":\t\"Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "error\":\t\"Certificate not found",
+                        "description": "Admin access required",
                         "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "error\":\t\"Could not retrieve certificate",
+                        "description": "Could not retrieve send log",
                         "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Updates or creates a specific certificate entry for a user",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/admin/features": {
+            "get": {
+                "description": "Lists every configured feature flag with who it is enabled for on this site, showing whether it is as configured or overridden at runtime. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Certificates"
-                ],
-                "summary": "Update or create a certificate entry",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Certificate ID",
-                        "name": "certificateid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Certificate JSON object",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/certificates.Certificate"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "List feature flags",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/features.Flag"
                             }
                         }
                     },
-                    "400": {
-                        "description": "error\":\t\"Invalid request body",
-                        "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
-                        }
-                    },
                     "401": {
-                        "description": "error\":\t\"Unauthorized",
+                        "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "403": {
-                        "description": "error\":\t\"Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "error\":\t\"Could not update certificate",
+                        "description": "Admin access required",
                         "schema": {
-                            "$ref": "#/definitions/certificates.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a specific certificate entry for a user",
+            }
+        },
+        "/admin/features/{name}": {
+            "put": {
+                "description": "Sets who a feature is enabled for on this site, replacing its configured rollout until the override is removed. Other replicas apply the change within 30 seconds. Requires the admin role.",
                 "consumes": [
                     "application/json"
                 ],
@@ -415,129 +414,170 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Certificates"
+                    "admin"
                 ],
-                "summary": "Delete a certificate entry",
+                "summary": "Override a feature flag",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
+                        "description": "Feature flag name",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Certificate ID",
-                        "name": "certificateid",
-                        "in": "path",
-                        "required": true
+                        "description": "Rollout of the feature",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/features.FlagRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/features.Flag"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown feature flag",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not save feature flag",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            }
-        },
-        "/certificates/{userid}/{certificateid}/cert_image": {
-            "put": {
-                "description": "Uploads or updates the certificate image for a specific certificate entry",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+            },
+            "delete": {
+                "description": "Returns a feature flag to its configured rollout on this site. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Certificates"
+                    "admin"
                 ],
-                "summary": "Upload or update certificate image",
+                "summary": "Remove a feature flag override",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Certificate ID",
-                        "name": "certificateid",
+                        "description": "Feature flag name",
+                        "name": "name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Certificate Image",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/features.Flag"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Feature flag is not overridden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not remove feature flag override",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/experience/{userid}": {
+        "/admin/integrations": {
             "get": {
-                "description": "Retrieves all work experience records for the specified user",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Lists the integrations allowed to push events, oldest first, without their secrets. Only admins can manage integrations.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "experience"
-                ],
-                "summary": "Get all user experiences",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "List integrations",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/experience.Experience"
+                                "$ref": "#/definitions/integrations.Integration"
                             }
                         }
                     },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
                     "500": {
-                        "description": "error\":\t\"Could not retrieve experience",
+                        "description": "Could not retrieve integrations",
                         "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Creates a new work experience record for the specified user",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates an integration an external system pushes events through, to POST /integrations/{integrationid}/events. The secret it signs its events with is only returned here and when it is rotated. With matchEmail, users of the integration not mapped to an account are mapped to the account registered with the email address it reports, so only enable it for systems that verify their users' addresses. Only admins can manage integrations.",
                 "consumes": [
                     "application/json"
                 ],
@@ -545,104 +585,74 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "experience"
+                    "admin"
                 ],
-                "summary": "Create a new experience item",
+                "summary": "Create an integration",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Experience Object",
-                        "name": "Experience",
+                        "description": "Integration",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/experience.Experience"
+                            "$ref": "#/definitions/integrations.IntegrationRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/experience.Experience"
+                            "$ref": "#/definitions/integrations.CreatedIntegration"
                         }
                     },
                     "400": {
-                        "description": "error\":\t\"Invalid request body",
+                        "description": "Invalid request body",
                         "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "401": {
-                        "description": "error\":\t\"Unauthorized",
+                        "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "403": {
-                        "description": "error\":\t\"Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "error\":\t\"User not found",
-                        "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "error\":\t\"Experience already exists",
-                        "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "error\":\t\"Invalid experience type",
+                        "description": "Not an admin",
                         "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "error\":\t\"Could not insert experience",
+                        "description": "Could not create integration",
                         "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/experience/{userid}/{experienceid}": {
-            "get": {
-                "description": "Retrieves a specific work experience record for the specified user and experience ID",
-                "consumes": [
-                    "application/json"
+        "/admin/integrations/{integrationid}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Removes an integration with the mappings of its users and the events it pushed. Events it sends afterwards are rejected. The certificates it created are kept. Only admins can manage integrations.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "experience"
+                    "admin"
                 ],
-                "summary": "Get specific experience item",
+                "summary": "Delete an integration",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Experience ID",
-                        "name": "experienceid",
+                        "description": "Integration ID",
+                        "name": "integrationid",
                         "in": "path",
                         "required": true
                     }
@@ -651,188 +661,207 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/experience.Experience"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "error\":\t\"Could not retrieve experience",
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Integration not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/integrations/{integrationid}/events": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates a specific work experience record for the specified user and experience ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Lists the events an integration pushed, newest first, with what was done with each and its payload. Only admins can manage integrations.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "experience"
+                    "admin"
                 ],
-                "summary": "Update specific experience item",
+                "summary": "List an integration's events",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
+                        "description": "Integration ID",
+                        "name": "integrationid",
                         "in": "path",
                         "required": true
                     },
                     {
+                        "enum": [
+                            "pending",
+                            "processed",
+                            "unmatched",
+                            "ignored",
+                            "rejected",
+                            "failed"
+                        ],
                         "type": "string",
-                        "description": "Experience ID",
-                        "name": "experienceid",
-                        "in": "path",
-                        "required": true
+                        "description": "Only list events with the status",
+                        "name": "status",
+                        "in": "query"
                     },
                     {
-                        "description": "Experience Object",
-                        "name": "Experience",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/experience.Experience"
-                        }
+                        "type": "integer",
+                        "description": "Maximum number of events to return (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "message\":\t\"Experience updated",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/integrations.Receipt"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "error\":\t\"Invalid request body",
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "401": {
-                        "description": "error\":\t\"Unauthorized",
+                    "403": {
+                        "description": "Not an admin",
                         "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "403": {
-                        "description": "error\":\t\"Forbidden",
+                    "404": {
+                        "description": "Integration not found",
                         "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "error\":\t\"Could not update experience",
+                        "description": "Could not retrieve events",
                         "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a specific work experience record for the specified user and experience ID",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/admin/integrations/{integrationid}/events/{eventid}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Processes again an event kept because its user was mapped to no account, such as after mapping them, or one whose processing failed. Only admins can manage integrations.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "experience"
+                    "admin"
                 ],
-                "summary": "Delete specific experience item",
+                "summary": "Replay an integration event",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
+                        "description": "Integration ID",
+                        "name": "integrationid",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Experience ID",
-                        "name": "experienceid",
+                        "description": "ID of the event in the integration",
+                        "name": "eventid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "message\":\t\"Experience deleted",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
+                            "$ref": "#/definitions/integrations.Receipt"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Integration or event not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Event already processed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "error\":\t\"Could not delete experience",
+                        "description": "Could not process event",
                         "schema": {
-                            "$ref": "#/definitions/experience.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/journal": {
+        "/admin/integrations/{integrationid}/mappings": {
             "get": {
-                "description": "Get all public journal entries, supports filtering by date range, taxonomy, and users",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the users of an integration mapped to accounts, whether by an admin or by their email address. Only admins can manage integrations.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "admin"
                 ],
-                "summary": "Get public journal entries",
+                "summary": "List an integration's user mappings",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Start date",
-                        "name": "start",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "End date",
-                        "name": "end",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Category",
-                        "name": "category",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Subcategory",
-                        "name": "subcategory",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Topic",
-                        "name": "topic",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Tag",
-                        "name": "tag",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user",
-                        "in": "query"
+                        "description": "Integration ID",
+                        "name": "integrationid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -841,20 +870,45 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/journal.JournalEntry"
+                                "$ref": "#/definitions/integrations.Mapping"
                             }
                         }
                     },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Integration not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
                     "500": {
-                        "description": "Error message",
+                        "description": "Could not retrieve mappings",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new journal entry",
+            }
+        },
+        "/admin/integrations/{integrationid}/mappings/{externalid}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Maps the user the integration identifies by the external ID to an account, replacing an earlier mapping. Events already received about them are not processed until they are replayed. Only admins can manage integrations.",
                 "consumes": [
                     "application/json"
                 ],
@@ -862,57 +916,99 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "admin"
                 ],
-                "summary": "Create a new journal entry",
+                "summary": "Map an integration's user to an account",
                 "parameters": [
                     {
-                        "description": "Journal Entry",
-                        "name": "entry",
+                        "type": "string",
+                        "description": "Integration ID",
+                        "name": "integrationid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user in the integration",
+                        "name": "externalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Account to map the user to",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/journal.Entry"
+                            "$ref": "#/definitions/integrations.MappingRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/journal.JournalEntry"
+                            "$ref": "#/definitions/integrations.Mapping"
                         }
                     },
                     "400": {
-                        "description": "Error message",
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Integration or user not found",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Error message",
+                        "description": "Could not save mapping",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            }
-        },
-        "/journal/u/{userid}": {
-            "get": {
-                "description": "Get all journal entries for a specific user by ID",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the mapping of the user the integration identifies by the external ID. Certificates already created for them are kept. Only admins can manage integrations.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "admin"
                 ],
-                "summary": "Get user-specific journal entries",
+                "summary": "Unmap an integration's user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
+                        "description": "Integration ID",
+                        "name": "integrationid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user in the integration",
+                        "name": "externalid",
                         "in": "path",
                         "required": true
                     }
@@ -921,36 +1017,53 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/journal.JournalEntry"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Error message",
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Mapping not found",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/journal/{journalid}": {
-            "get": {
-                "description": "Get a single journal entry by ID, returns metadata if the user is authenticated",
+        "/admin/integrations/{integrationid}/secret": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the secret an integration signs its events with, returning the new one. Events signed with the old secret are rejected from then on. Only admins can manage integrations.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "admin"
                 ],
-                "summary": "Get a single journal entry",
+                "summary": "Rotate an integration's secret",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Journal ID",
-                        "name": "journalid",
+                        "description": "Integration ID",
+                        "name": "integrationid",
                         "in": "path",
                         "required": true
                     }
@@ -959,310 +1072,413 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/journal.JournalEntry"
+                            "$ref": "#/definitions/integrations.CreatedIntegration"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "404": {
-                        "description": "Error message",
+                        "description": "Integration not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not rotate secret",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update a journal entry by ID, increments the version",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/admin/jobs": {
+            "get": {
+                "description": "Lists background jobs, newest first, optionally filtered by status. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "admin"
                 ],
-                "summary": "Update a journal entry",
+                "summary": "List background jobs",
                 "parameters": [
                     {
+                        "enum": [
+                            "pending",
+                            "running",
+                            "succeeded",
+                            "dead"
+                        ],
                         "type": "string",
-                        "description": "Journal ID",
-                        "name": "journalid",
-                        "in": "path",
-                        "required": true
+                        "description": "Job status",
+                        "name": "status",
+                        "in": "query"
                     },
                     {
-                        "description": "Updated Entry",
-                        "name": "entry",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/journal.Entry"
-                        }
+                        "type": "integer",
+                        "description": "Maximum number of jobs to return (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/journal.JournalEntry"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/jobs.Job"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Error message",
+                        "description": "Invalid status",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "404": {
-                        "description": "Error message",
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Error message",
+                        "description": "Could not retrieve jobs",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a journal entry by ID",
+            }
+        },
+        "/admin/jobs/{jobid}": {
+            "get": {
+                "description": "Retrieves a background job, including the error from its last failed attempt. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "admin"
                 ],
-                "summary": "Delete a journal entry",
+                "summary": "Get a background job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Journal ID",
-                        "name": "journalid",
+                        "description": "Job ID",
+                        "name": "jobid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Journal entry deleted",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/journal.DeleteResponse"
+                            "$ref": "#/definitions/jobs.Job"
                         }
                     },
-                    "500": {
-                        "description": "Error message",
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/journal/{journalid}/meta": {
-            "get": {
-                "description": "Get metadata for a journal entry by ID",
+        "/admin/jobs/{jobid}/retry": {
+            "post": {
+                "description": "Returns a job from the dead-letter state to the queue with its attempts reset. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "admin"
                 ],
-                "summary": "Get journal metadata",
+                "summary": "Retry a dead job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Journal ID",
-                        "name": "journalid",
+                        "description": "Job ID",
+                        "name": "jobid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "createdAt\", \"updatedAt\", \"version\", \"status\", \"userID",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/journal.SuccessResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "404": {
-                        "description": "Error message",
+                        "description": "Dead job not found",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/journal/{journalid}/process": {
-            "put": {
-                "description": "Trigger processing for a journal entry by ID",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/moderation/hidden": {
+            "get": {
+                "description": "Lists the profiles and journal entries hidden by admins or for reaching the report threshold, most recently hidden first. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
-                ],
-                "summary": "Process a journal entry",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Journal ID",
-                        "name": "journalid",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "List hidden content",
                 "responses": {
                     "200": {
-                        "description": "Journal entry is being processed",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/journal.ProcessingResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/moderation.Hidden"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Error message",
+                        "description": "Could not retrieve hidden content",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/journal/{journalid}/status": {
-            "put": {
-                "description": "Set the status of a journal entry by ID",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/moderation/hidden/{type}/{targetid}": {
+            "delete": {
+                "description": "Shows hidden content again. A journal entry made private by the hide is made public again, unless its owner changed its status since. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "admin"
                 ],
-                "summary": "Set the status of a journal entry",
+                "summary": "Lift a hide",
                 "parameters": [
                     {
+                        "enum": [
+                            "profile",
+                            "journal"
+                        ],
                         "type": "string",
-                        "description": "Journal ID",
-                        "name": "journalid",
+                        "description": "Type of content",
+                        "name": "type",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Status",
-                        "name": "status",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "string"
-                        }
+                        "type": "string",
+                        "description": "User ID of a profile or journal ID of a journal entry",
+                        "name": "targetid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Journal status updated",
+                        "description": "Content shown again",
                         "schema": {
-                            "$ref": "#/definitions/journal.ProcessingResponse"
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Error message",
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Content not hidden",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Error message",
+                        "description": "Could not lift the hide",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/journal/{journalid}/version": {
-            "put": {
-                "description": "Set the current version of a journal entry by ID",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/moderation/queue": {
+            "get": {
+                "description": "Returns the content with open reports, the most reported first and then the longest waiting, with the number of reports for each reason and whether the content is hidden. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "admin"
                 ],
-                "summary": "Set the current version of a journal entry",
+                "summary": "Get the moderation queue",
                 "parameters": [
                     {
+                        "enum": [
+                            "profile",
+                            "journal"
+                        ],
                         "type": "string",
-                        "description": "Journal ID",
-                        "name": "journalid",
-                        "in": "path",
-                        "required": true
+                        "description": "Type of content",
+                        "name": "type",
+                        "in": "query"
                     },
                     {
-                        "description": "Version",
-                        "name": "version",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "integer"
-                        }
+                        "type": "integer",
+                        "description": "Maximum number of items to return (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/journal.JournalEntry"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/moderation.Case"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Error message",
+                        "description": "Invalid type",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "404": {
-                        "description": "Error message",
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Error message",
+                        "description": "Could not retrieve the moderation queue",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/journal/{journalid}/versions": {
+        "/admin/moderation/reports": {
             "get": {
-                "description": "Get all versions of a journal entry by ID",
+                "description": "Lists reports, oldest first, optionally only those with a status or about a piece of content. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "admin"
                 ],
-                "summary": "Get journal versions",
+                "summary": "List reports",
                 "parameters": [
                     {
+                        "enum": [
+                            "open",
+                            "resolved",
+                            "dismissed"
+                        ],
                         "type": "string",
-                        "description": "Journal ID",
-                        "name": "journalid",
-                        "in": "path",
-                        "required": true
+                        "description": "Status of the reports",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "profile",
+                            "journal"
+                        ],
+                        "type": "string",
+                        "description": "Type of content",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID of a profile or journal ID of a journal entry",
+                        "name": "targetid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of reports to return (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1271,483 +1487,534 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/journal.Entry"
+                                "$ref": "#/definitions/moderation.Report"
                             }
                         }
                     },
-                    "404": {
-                        "description": "Error message",
+                    "400": {
+                        "description": "Invalid status or type",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve reports",
                         "schema": {
-                            "$ref": "#/definitions/journal.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/profile/{userid}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/admin/moderation/targets/{type}/{targetid}/actions": {
+            "post": {
+                "description": "Hides the content, emails its owner a warning with the message, suspends the owner's account, or dismisses the reports, lifting a hide made for reaching the report threshold. The content's open reports are closed, dismissed for dismiss and resolved otherwise. Content may be acted on without being reported. Requires the admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Retrieves the profile of the user with the specified user ID.",
                 "tags": [
-                    "profile"
+                    "admin"
                 ],
-                "summary": "Retrieve a user's profile.",
-                "operationId": "get-profile",
+                "summary": "Act on reported content",
                 "parameters": [
                     {
+                        "enum": [
+                            "profile",
+                            "journal"
+                        ],
                         "type": "string",
-                        "description": "The ID of the user whose profile to get",
-                        "name": "userid",
+                        "description": "Type of content",
+                        "name": "type",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID of a profile or journal ID of a journal entry",
+                        "name": "targetid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Action to take",
+                        "name": "action",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/moderation.ActionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profile retrieved successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/profile.Profile"
+                            "$ref": "#/definitions/moderation.ActionResponse"
                         }
                     },
-                    "401": {
-                        "description": "Not authenticated",
+                    "400": {
+                        "description": "Invalid request body, or suspending the admin's own account",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Content not found",
                         "schema": {
-                            "$ref": "#/definitions/profile.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Could not retrieve profile",
+                        "description": "Could not act on the content",
                         "schema": {
-                            "$ref": "#/definitions/profile.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+            }
+        },
+        "/admin/scraping": {
+            "get": {
+                "description": "Returns the addresses detected scraping the public journal in the last days, by a burst of requests or by looking entries up in the order of their IDs, most recently detected first, with the latest detections. Addresses are identified by a hash. Requires the admin role.",
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Updates the profile of the user with the specified user ID using the provided profile data.",
                 "tags": [
-                    "profile"
+                    "admin"
                 ],
-                "summary": "Update a user's profile.",
-                "operationId": "update-profile",
+                "summary": "Get the journal scraping report",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "The ID of the user whose profile to update",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Number of days to report (default 7, at most the retention)",
+                        "name": "days",
+                        "in": "query"
                     },
                     {
-                        "description": "Profile object that needs to be updated",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/profile.Profile"
-                        }
+                        "type": "integer",
+                        "description": "Maximum number of detections to return (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profile updated",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/scraping.Report"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid number of days",
                         "schema": {
-                            "$ref": "#/definitions/profile.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "401": {
                         "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/profile.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Could not update profile",
+                        "description": "Could not retrieve scraping report",
                         "schema": {
-                            "$ref": "#/definitions/profile.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/search/reindex": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+                "description": "Queues a background job rebuilding every user's search documents, and their vectors when an embedding provider is configured, from their current data, for use after the index is lost or the search backend changes. Requires the admin role.",
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Creates a new profile for the user with the specified user ID using the provided profile data.",
                 "tags": [
-                    "profile"
-                ],
-                "summary": "Create a new user profile.",
-                "operationId": "create-profile",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "The ID of the user for whom the profile is to be created",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Profile object that needs to be created",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/profile.Profile"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "Rebuild the search index",
                 "responses": {
-                    "201": {
-                        "description": "Profile created",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid request body",
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/profile.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "401": {
-                        "description": "Not authenticated",
+                    "403": {
+                        "description": "Admin access required",
                         "schema": {
-                            "$ref": "#/definitions/profile.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Could not create profile",
+                        "description": "Could not queue reindex",
                         "schema": {
-                            "$ref": "#/definitions/profile.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/profile/{userid}/image": {
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/admin/signups": {
+            "get": {
+                "description": "Lists the users who registered within the last days, most recent first. Users registered before signup times were recorded are never included. Requires the admin role.",
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Updates the profile image of the user with the specified user ID.",
                 "tags": [
-                    "profile"
+                    "admin"
                 ],
-                "summary": "Update a user's profile image.",
-                "operationId": "update-profile-image",
+                "summary": "List recent signups",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "The ID of the user whose profile image to update",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Number of days to look back (default 7, max 365)",
+                        "name": "days",
+                        "in": "query"
                     },
                     {
-                        "type": "file",
-                        "description": "Profile image to upload",
-                        "name": "profileImage",
-                        "in": "formData",
-                        "required": true
+                        "type": "integer",
+                        "description": "Maximum number of users to return (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profile image updated",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/admin.Signups"
                         }
                     },
                     "400": {
-                        "description": "Profile image not found",
+                        "description": "Invalid number of days",
                         "schema": {
-                            "$ref": "#/definitions/profile.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "401": {
                         "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/profile.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Could not upload image",
+                        "description": "Could not retrieve signups",
                         "schema": {
-                            "$ref": "#/definitions/profile.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/qualifications/{userid}": {
+        "/admin/signups/rejected": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+                "description": "Returns how many registrations this replica rejected as automated since it started, by reason: the hidden honeypot field filled in, a disposable email domain, the form submitted too quickly, or a missing or expired form token. Requires the admin role.",
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Retrieves all qualifications associated with the specified user ID.",
                 "tags": [
-                    "Qualifications"
+                    "admin"
                 ],
-                "summary": "Get all qualifications for a user.",
-                "operationId": "get-qualifications",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "The ID of the user whose qualifications are to be retrieved",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
+                "summary": "Get rejected signups",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.RegistrationRejections"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
                     }
+                }
+            }
+        },
+        "/admin/stats": {
+            "get": {
+                "description": "Returns the number of documents and storage used by each collection, and the storage used altogether. In-memory storage reports no collections. Requires the admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
                 ],
+                "summary": "Get platform statistics",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/qualifications.Qualification"
-                            }
+                            "$ref": "#/definitions/admin.Stats"
                         }
                     },
                     "401": {
                         "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Could not retrieve qualifications",
+                        "description": "Could not retrieve statistics",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+            }
+        },
+        "/admin/usage/api": {
+            "get": {
+                "description": "Returns the API calls made in the last days, today included, per day with how many users made them, and the users who made the most. Requires the admin role.",
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Creates a new qualification entry associated with the specified user ID using the provided qualification data.",
                 "tags": [
-                    "Qualifications"
+                    "admin"
                 ],
-                "summary": "Create a new qualification for a user.",
-                "operationId": "post-qualification",
+                "summary": "Get API usage of all users",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "The ID of the user for whom the qualification is to be created",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Number of days to report (default 30, at most the retention)",
+                        "name": "days",
+                        "in": "query"
                     },
                     {
-                        "description": "Qualification object to be created",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/qualifications.Qualification"
-                        }
+                        "type": "integer",
+                        "description": "Maximum number of users to return (default 20, max 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Qualification Created",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apiusage.Aggregate"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid number of days",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "401": {
                         "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Could not update qualification",
+                        "description": "Could not retrieve API usage",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/qualifications/{userid}/{qualificationid}": {
+        "/admin/users": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+                "description": "Lists registered users, most recently registered first, optionally searching their names and email addresses. Requires the admin role.",
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Retrieves the qualification entry associated with the specified user ID and qualification ID.",
                 "tags": [
-                    "Qualifications"
+                    "admin"
                 ],
-                "summary": "Get a specific qualification for a user.",
-                "operationId": "get-qualification-entry",
+                "summary": "List users",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The ID of the user whose qualification is to be retrieved",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
+                        "description": "Text to find in the name or email address, ignoring case",
+                        "name": "q",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "The ID of the qualification to be retrieved",
-                        "name": "qualificationid",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Maximum number of users to return (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.Qualification"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/admin.User"
+                            }
                         }
                     },
                     "401": {
                         "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Could not retrieve qualification",
+                        "description": "Could not retrieve users",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Updates the qualification entry associated with the specified user ID and qualification ID using the provided qualification data.",
-                "tags": [
-                    "Qualifications"
+            }
+        },
+        "/admin/users/{userid}/disable": {
+            "post": {
+                "description": "Disables a user's account. The user can no longer log in and their existing sessions stop working. Requires the admin role.",
+                "produces": [
+                    "application/json"
                 ],
-                "summary": "Update a specific qualification for a user.",
-                "operationId": "put-qualification-entry",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Disable a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The ID of the user whose qualification is to be updated",
+                        "description": "User ID",
                         "name": "userid",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "The ID of the qualification to be updated",
-                        "name": "qualificationid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Qualification object that needs to be updated",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/qualifications.Qualification"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Qualification updated",
+                        "description": "User disabled",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Admins cannot disable their own account",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "401": {
                         "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "500": {
-                        "description": "Could not update qualification",
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+            }
+        },
+        "/admin/users/{userid}/enable": {
+            "post": {
+                "description": "Re-enables a disabled user's account so they can log in again. Requires the admin role.",
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Deletes the qualification entry associated with the specified user ID and qualification ID.",
                 "tags": [
-                    "Qualifications"
+                    "admin"
                 ],
-                "summary": "Delete a specific qualification for a user.",
-                "operationId": "delete-qualification-entry",
+                "summary": "Enable a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The ID of the user whose qualification is to be deleted",
+                        "description": "User ID",
                         "name": "userid",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "The ID of the qualification to be deleted",
-                        "name": "qualificationid",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Qualification deleted",
+                        "description": "User enabled",
                         "schema": {
                             "type": "string"
                         }
@@ -1755,99 +2022,91 @@ const docTemplate = `{
                     "401": {
                         "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "500": {
-                        "description": "Could not delete qualification",
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/qualifications/{userid}/{qualificationid}/cert_image": {
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Updates the certificate image for the qualification associated with the specified user ID and qualification ID using the provided image file.",
-                "consumes": [
-                    "multipart/form-data"
+        "/admin/users/{userid}/password-reset": {
+            "post": {
+                "description": "Requires a user to choose a new password. Their existing sessions stop working and they cannot log in until they set a new password with the token emailed to them. Requires the admin role.",
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "Qualifications"
+                    "admin"
                 ],
-                "summary": "Upload a certificate image for a qualification.",
-                "operationId": "put-qualification-image",
+                "summary": "Force a password reset",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The ID of the user whose qualification certificate image is to be updated",
+                        "description": "User ID",
                         "name": "userid",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "The ID of the qualification whose certificate image is to be updated",
-                        "name": "qualificationid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Certificate image file to upload",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "cert image uploaded",
+                        "description": "Password reset required",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "invalid request body",
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "401": {
-                        "description": "Not authenticated",
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "could not update qualification",
+                        "description": "Could not send password reset email",
                         "schema": {
-                            "$ref": "#/definitions/qualifications.ErrorResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/skills/{userid}": {
+        "/ap/users/{userid}": {
             "get": {
-                "description": "Retrieve all skills for a specific user",
+                "description": "Returns the ActivityPub Person representing the user, with their profile's name, bio and image and the key their deliveries are signed with. Only available when ActivityPub federation is enabled.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Skills"
+                    "activitypub"
                 ],
-                "summary": "Retrieve all skills for a specific user",
+                "summary": "Get a user's actor",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "The ID of the user",
                         "name": "userid",
                         "in": "path",
                         "required": true
@@ -1855,537 +2114,14656 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Skills retrieved",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/skills.Skill"
-                            }
-                        }
-                    },
-                    "401": {
-                        "description": "error\":\t\"Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "error\":\t\"Forbidden",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/activitypub.Actor"
                         }
                     },
                     "404": {
-                        "description": "error\":\t\"Skill not found",
+                        "description": "User not found",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "error\":\t\"Could not retrieve skills",
+                        "description": "Could not retrieve actor",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new skill for a specific user",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/ap/users/{userid}/followers": {
+            "get": {
+                "description": "Returns the collection of the user's followers, with their number only so followers are not disclosed. Only available when ActivityPub federation is enabled.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Skills"
+                    "activitypub"
                 ],
-                "summary": "Create a new skill for a specific user",
+                "summary": "Get a user's followers",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "The ID of the user",
                         "name": "userid",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Skill details",
-                        "name": "req",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/skills.Skill"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Skill created",
-                        "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request body",
-                        "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/activitypub.OrderedCollection"
                         }
                     },
                     "404": {
-                        "description": "Skill not found",
-                        "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Skill already exists",
+                        "description": "User not found",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Could not create skill",
+                        "description": "Could not retrieve followers",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/skills/{userid}/{skillId}": {
-            "put": {
-                "description": "Update a specific skill for a specific user",
+        "/ap/users/{userid}/inbox": {
+            "post": {
+                "description": "Receives an ActivityPub activity signed by its actor with HTTP Signatures. Follow activities add the actor to the user's followers, who are accepted straight away and receive the user's journal entries as they are made public; Undo of a Follow and Delete of the actor remove them. Other activities are ignored. Only available when ActivityPub federation is enabled.",
                 "consumes": [
-                    "application/json"
-                ],
-                "produces": [
+                    "application/activity+json",
+                    "application/ld+json",
                     "application/json"
                 ],
                 "tags": [
-                    "Skills"
+                    "activitypub"
                 ],
-                "summary": "Update a specific skill for a specific user",
+                "summary": "Deliver an activity to a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "The ID of the user",
                         "name": "userid",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Skill Name",
-                        "name": "skillname",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Skill details",
-                        "name": "req",
+                        "description": "The activity, as JSON-LD",
+                        "name": "activity",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/skills.Skill"
+                            "type": "object"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Skill updated",
-                        "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
-                        }
+                    "202": {
+                        "description": "Accepted"
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid activity",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
+                        "description": "Missing or invalid signature",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "404": {
-                        "description": "Skill not found",
+                        "description": "User not found",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Could not update skill",
+                        "description": "Could not process activity",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/skills/{userid}/{skillid}": {
+        "/ap/users/{userid}/notes/{journalid}": {
             "get": {
-                "description": "Retrieve a specific skill for a specific user",
+                "description": "Returns the public journal entry as an ActivityPub note quoting its title and summary and linking to the full entry. Only available when ActivityPub federation is enabled.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Skills"
+                    "activitypub"
                 ],
-                "summary": "Retrieve a specific skill for a specific user",
+                "summary": "Get the note of a journal entry",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "The ID of the user",
                         "name": "userid",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Skill ID",
-                        "name": "skillid",
+                        "description": "The ID of the journal entry",
+                        "name": "journalid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Skill retrieved",
-                        "schema": {
-                            "$ref": "#/definitions/skills.Skill"
-                        }
-                    },
-                    "401": {
-                        "description": "error\":\t\"Unauthorized",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/activitypub.Note"
                         }
                     },
                     "404": {
-                        "description": "error\":\t\"Skill not found",
+                        "description": "Journal entry not found or not public",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "error\":\t\"Could not retrieve skill",
+                        "description": "Could not retrieve note",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a specific skill for a specific user",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/ap/users/{userid}/outbox": {
+            "get": {
+                "description": "Returns the collection of the user's public journal entries as Create activities of notes. Without page it returns the collection, pointing at its first page; each page lists 20 activities, newest first. Only available when ActivityPub federation is enabled.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Skills"
+                    "activitypub"
                 ],
-                "summary": "Delete a specific skill for a specific user",
+                "summary": "Get a user's outbox",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "The ID of the user",
                         "name": "userid",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "The page to return, from 1",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of activities, or the OrderedCollection without page",
+                        "schema": {
+                            "$ref": "#/definitions/activitypub.OrderedCollectionPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid page",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve outbox",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the changes made to the logged in user's profile, CV sections, journal, webhooks and account, newest first, whoever made them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "List changes to your data",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Skill ID",
-                        "name": "skillid",
-                        "in": "path",
-                        "required": true
+                        "description": "Only list changes to this kind of resource, such as skill or journal",
+                        "name": "resource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries to return (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Skill deleted",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/audit.Entry"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve audit log",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/account": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permanently delete the logged in user's account along with their profile, CV sections, journal and subscriptions. The user must have entered their password within the sudo window.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Delete account",
+                "responses": {
+                    "200": {
+                        "description": "Account deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Password must be confirmed at /auth/sudo",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/email": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the address the logged in user signs in with. The user must have entered their password within the sudo window.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Change email",
+                "parameters": [
+                    {
+                        "description": "Email change request object",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.EmailChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email changed",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "403": {
-                        "description": "Forbidden",
+                        "description": "Password must be confirmed at /auth/sudo",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "404": {
-                        "description": "Skill not found",
+                    "409": {
+                        "description": "Email already registered",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "422": {
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Login a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "Login request object",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
                         "description": "Invalid request body",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "429": {
-                        "description": "Too many requests",
+                    "401": {
+                        "description": "Invalid email or password",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "500": {
-                        "description": "Could not delete skill",
+                    "403": {
+                        "description": "Account disabled or password reset required",
                         "schema": {
-                            "$ref": "#/definitions/skills.JSONResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "auth.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "description": "Error message\nexample: Invalid request body",
-                    "type": "string"
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Logout the currently logged in user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Logout",
+                "responses": {
+                    "200": {
+                        "description": "Logged out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
                 }
             }
         },
-        "auth.LoginRequest": {
-            "type": "object",
-            "properties": {
-                "email": {
+        "/auth/password-reset": {
+            "post": {
+                "description": "Sets a new password using the token emailed when an admin forces a password reset",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Password reset request object",
+                        "name": "reset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.PasswordResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password reset",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid reset token",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Register a new user. Registrations from disposable email domains are rejected, as are those sent too soon after fetching the form token when the server requires one. Bots filling in the hidden website field are answered as if they registered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Register",
+                "parameters": [
+                    {
+                        "description": "Registration request object",
+                        "name": "register",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, disposable email address, or form submitted too quickly or expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register/form": {
+            "get": {
+                "description": "Returns a token to send with the registration, recording when the form was shown. When the server requires a minimum time to submit, registrations sent sooner after fetching the token, without one, or with one older than a day are rejected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get a registration form token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.RegisterForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sudo": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confirms the logged in user's password, renewing their token so they may do sensitive operations, such as deleting their account or changing their email address, within the sudo window",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Confirm password",
+                "parameters": [
+                    {
+                        "description": "Password confirmation request object",
+                        "name": "sudo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.SudoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated or wrong password",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/usage": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the storage the user's uploads take and how many documents they keep in each collection, with the limits of the site and their plan. Limits of 0 are unlimited. Writes past a limit of the user's plan are refused with 402, and past one of the site with 413.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get your usage",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/quota.Usage"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve usage",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/usage/api": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the API calls the user made in the last days, today included, per day and per session token, with how many were answered with an error. Calls are counted once they are answered and reported within the configured flush interval.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get your API usage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of days to report (default 30, at most the retention)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/apiusage.Usage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid number of days",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve API usage",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/automation/certificates": {
+            "post": {
+                "description": "Adds a certificate to the user's CV, like one added through the certificate endpoints",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "automation"
+                ],
+                "summary": "Add a certificate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Certificate",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/automation.CertificateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/automation.Certificate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "The user's plan allows no more certificates",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create certificate",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/automation/events": {
+            "get": {
+                "description": "Lists the latest changes to the user's profile, CV sections, journal and account, newest first, for polling triggers. Each change has an ID unique to it, so tools can tell new changes from those already seen. Its type is the resource and what was done to it, such as certificate.created or journal.updated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "automation"
+                ],
+                "summary": "List recent changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list changes to this kind of resource, such as certificate or journal",
+                        "name": "resource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of changes to return (default 25, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/automation.Event"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve changes",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/automation/journal": {
+            "post": {
+                "description": "Adds a journal entry with the title and content, with the status and taxonomy of the user's journal settings like one added through the journal endpoints",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "automation"
+                ],
+                "summary": "Add a journal entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Journal entry",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/automation.JournalEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/automation.JournalEntry"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created journal entry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "The user's plan allows no more journal entries",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create journal entry",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/automation/keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the current user's API keys, oldest first, with the start of each key and when it was last used",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "automation"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/automation.Key"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve API keys",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates an API key for connecting a no-code tool such as Zapier or n8n to the automation endpoints, which it sends in the X-API-Key header. The key acts as the user who created it, and is only returned here. A user may hold up to 20 keys. The user must have entered their password within the sudo window.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "automation"
+                ],
+                "summary": "Create an API key",
+                "parameters": [
+                    {
+                        "description": "Key name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/automation.KeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/automation.CreatedKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Password must be confirmed at /auth/sudo",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Too many API keys",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create API key",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/automation/keys/{keyid}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes an API key. Tools using it are refused from then on.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "automation"
+                ],
+                "summary": "Delete an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "keyid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/automation/me": {
+            "get": {
+                "description": "Returns the user the API key authenticates as, for tools checking a connection",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "automation"
+                ],
+                "summary": "Test an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/automation.Me"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/awards/{userid}": {
+            "get": {
+                "description": "Retrieves all awards and honors of a given user, without the fields their visibility hides from the requester",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Awards"
+                ],
+                "summary": "Get all awards",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/awards.Award"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not retrieve awards",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new award or honor for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Awards"
+                ],
+                "summary": "Create an award",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Award JSON object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/awards.Award"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created award with its ID, and a message",
+                        "schema": {
+                            "$ref": "#/definitions/awards.Award"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\t\"Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "error\":\t\"Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the awards",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "error\":\t\"Award limit reached",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not create award",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/awards/{userid}/bulk-delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the user's awards with the given IDs, or those whose fields match every value of the filter, such as {\"issuer\": \"ACM\"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Awards"
+                ],
+                "summary": "Delete awards in bulk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The awards to delete",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, or a filter that is not confirmed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the awards",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete awards",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/awards/{userid}/{awardid}": {
+            "get": {
+                "description": "Retrieves a specific award of a user, without the fields its visibility hides from the requester",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Awards"
+                ],
+                "summary": "Get an award",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Award ID",
+                        "name": "awardid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/awards.Award"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\t\"Award not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not retrieve award",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates or creates a specific award of a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Awards"
+                ],
+                "summary": "Update or create an award",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Award ID",
+                        "name": "awardid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Award JSON object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/awards.Award"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\t\"Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "error\":\t\"Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the awards",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not update award",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a specific award of a user, with its image",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Awards"
+                ],
+                "summary": "Delete an award",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Award ID",
+                        "name": "awardid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "error\":\t\"Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the awards",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Award not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not delete award",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/awards/{userid}/{awardid}/image": {
+            "get": {
+                "description": "Serves the image uploaded for an award, such as a photo of the medal or certificate",
+                "produces": [
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif",
+                    "image/webp"
+                ],
+                "tags": [
+                    "Awards"
+                ],
+                "summary": "Get an award's image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Award ID",
+                        "name": "awardid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Image not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve image",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uploads or replaces the image of an award, such as a photo of the medal or certificate. The image is scanned for malware after it is stored.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Awards"
+                ],
+                "summary": "Upload or update an award's image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Award ID",
+                        "name": "awardid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Award image",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid image",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "The user's plan has no storage left for the image",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the awards",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "The user has no storage left for the image",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Runs each request of the batch in order, as if the caller had sent it with their credentials, and returns the response to each. A failed request does not stop the batch or undo the requests before it. Batches cannot be nested, nor hold streaming routes such as the event stream.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "batch"
+                ],
+                "summary": "Run several requests at once",
+                "parameters": [
+                    {
+                        "description": "Requests to run",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/batch.BatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Responses to the requests",
+                        "schema": {
+                            "$ref": "#/definitions/batch.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid batch",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "The batch is not sent as application/json",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/billing/checkout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts a Stripe checkout subscribing the user to the plan, returning the checkout page to send them to. The user moves to the plan once Stripe reports the subscription is paid. Users with a subscription change or cancel it in the customer portal instead. Only available when billing is enabled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "billing"
+                ],
+                "summary": "Subscribe to a plan",
+                "parameters": [
+                    {
+                        "description": "Plan to subscribe to",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/billing.CheckoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Checkout page",
+                        "schema": {
+                            "$ref": "#/definitions/billing.Redirect"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown plan, or one that cannot be bought",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Already subscribed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not start checkout",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/billing/plans": {
+            "get": {
+                "description": "Lists the plans users can be on with what each includes, by name. Limits of 0 are unlimited. Purchasable plans can be subscribed to at checkout. Only available when billing is enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "billing"
+                ],
+                "summary": "List plans",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/billing.Plan"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/billing/portal": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Opens the Stripe customer portal, where the user changes their payment details or plan, or cancels their subscription, returning the page to send them to. Only available when billing is enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "billing"
+                ],
+                "summary": "Manage your subscription",
+                "responses": {
+                    "200": {
+                        "description": "Customer portal page",
+                        "schema": {
+                            "$ref": "#/definitions/billing.Redirect"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "The user never subscribed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not open the customer portal",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/billing/subscription": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the plan the user is on and the state of the subscription paying for it, if any. Only available when billing is enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "billing"
+                ],
+                "summary": "Get your plan",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/billing.Subscription"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve subscription",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/billing/webhook": {
+            "post": {
+                "description": "Receives the events Stripe sends about subscriptions, signed with the webhook secret. Each site of a multi-tenant deployment has the endpoint registered at its own domain. Changes are applied in the background.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "billing"
+                ],
+                "summary": "Receive Stripe events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signature of the event",
+                        "name": "Stripe-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Stripe event",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event received",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "boolean"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid signature or event",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not queue the event",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/certificates/{userid}": {
+            "get": {
+                "description": "Retrieves all certificates for a given user, without the fields their visibility hides from the requester. Archived certificates hidden from the requester are left out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Certificates"
+                ],
+                "summary": "Get all certificates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/certificates.Certificate"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not retrieve certificates",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new certificate entry for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Certificates"
+                ],
+                "summary": "Create a new certificate entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Certificate JSON object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/certificates.Certificate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created certificate with its ID, and a message",
+                        "schema": {
+                            "$ref": "#/definitions/certificates.Certificate"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\t\"Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "error\":\t\"Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "error\":\t\"Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\t\"User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "error\":\t\"Certificate already exists",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "error\":\t\"Certificate limit reached",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not create certificate",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/certificates/{userid}/bulk-delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the user's certificates with the given IDs, or those whose fields match every value of the filter, such as {\"institution\": \"AWS\"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Certificates"
+                ],
+                "summary": "Delete certificates in bulk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The certificates to delete",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, or a filter that is not confirmed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the certificates",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete certificates",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/certificates/{userid}/{certificateid}": {
+            "get": {
+                "description": "Retrieves a specific certificate entry for a user, without the fields its visibility hides from the requester. An archived certificate hidden from the requester is not found.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Certificates"
+                ],
+                "summary": "Get a certificate entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Certificate ID",
+                        "name": "certificateid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/certificates.Certificate"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\t\"Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "error\":\t\"Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "error\":\t\"Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\t\"Certificate not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not retrieve certificate",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates or creates a specific certificate entry for a user. Changing the end date of an archived certificate unarchives it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Certificates"
+                ],
+                "summary": "Update or create a certificate entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Certificate ID",
+                        "name": "certificateid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Certificate JSON object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/certificates.Certificate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\t\"Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "error\":\t\"Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "error\":\t\"Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not update certificate",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a specific certificate entry for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Certificates"
+                ],
+                "summary": "Delete a certificate entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Certificate ID",
+                        "name": "certificateid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the certificates",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Certificate not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/certificates/{userid}/{certificateid}/cert_image": {
+            "put": {
+                "description": "Uploads or updates the certificate image for a specific certificate entry",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Certificates"
+                ],
+                "summary": "Upload or update certificate image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Certificate ID",
+                        "name": "certificateid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Certificate Image",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "402": {
+                        "description": "The user's plan has no storage left for the image",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the certificates",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "The user has no storage left for the image",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/changes/{userid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the changes made to the user's profile, CV sections and journal after the event numbered after, oldest first. Each user's events are numbered from 1 in the order the changes were made. Pass the next cursor of a page as after to get the following events; while more is set there are further events to fetch. Long values in the changes are truncated as in the audit log. Only the user and admins may read their changes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changes"
+                ],
+                "summary": "List changes to a user's profile data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of the last event already seen (default 0, the start of the log)",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of events to return (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/changelog.Page"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid cursor",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the user or an admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve changes",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/domains": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the domains attached to the current user's profile, oldest first, with their status and the TXT record verifying them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "domains"
+                ],
+                "summary": "List domains",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domains.DomainStatus"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve domains",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Attaches a domain to the current user's profile, pending verification. To verify it, publish the returned TXT record and call the verify endpoint; pending domains are also checked daily, and removed if still unverified after the configured pending TTL. The domain must also point at this site, with a CNAME or A record, to serve the profile, and is then served over HTTPS when the site obtains certificates for custom domains.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "domains"
+                ],
+                "summary": "Attach a domain",
+                "parameters": [
+                    {
+                        "description": "Domain name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domains.DomainRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domains.DomainStatus"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created domain"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid domain name",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Custom domains are turned off",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Domain already attached",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Too many domains",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not attach domain",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/domains/site": {
+            "get": {
+                "description": "Returns the user whose profile is served on the Host of the request, when it is a verified custom domain, for frontends deciding what to show",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "domains"
+                ],
+                "summary": "Get the site of the host",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domains.Site"
+                        }
+                    },
+                    "404": {
+                        "description": "The host is not a custom domain",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/domains/{domainid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a domain attached to the current user's profile, with its status, when it was last checked and why it was not verified, and the TXT record verifying it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "domains"
+                ],
+                "summary": "Get a domain",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domain ID",
+                        "name": "domainid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domains.DomainStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Domain not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Detaches a domain from the current user's profile. Replicas may keep serving the profile on it for the configured cache TTL.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "domains"
+                ],
+                "summary": "Delete a domain",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domain ID",
+                        "name": "domainid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Domain not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/domains/{domainid}/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Looks up the domain's TXT record and verifies the domain when it holds the domain's token, after which the domain serves the profile. Otherwise the domain stays pending and lastError tells what was found. DNS changes may take a while to be seen. Checking a verified domain again has no effect.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "domains"
+                ],
+                "summary": "Verify a domain",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domain ID",
+                        "name": "domainid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domains.DomainStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Domain not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not verify domain",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/embed/oembed": {
+            "get": {
+                "description": "Returns a widget as HTML to show inline, for sites and editors supporting oEmbed. The url parameter is the URL of a skills or journal widget. Only the JSON format is supported.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "embed"
+                ],
+                "summary": "Get the oEmbed response for a widget",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "URL of the widget",
+                        "name": "url",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum width of the HTML, in pixels",
+                        "name": "maxwidth",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum height of the HTML, in pixels",
+                        "name": "maxheight",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/widgets.OEmbed"
+                        }
+                    },
+                    "404": {
+                        "description": "Not a widget URL, or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve widget",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "501": {
+                        "description": "Format not supported",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/embed/{userid}/journal": {
+            "get": {
+                "description": "Returns the user's latest public journal entries, newest first, in compact JSON for embedding on other sites. Any origin may fetch it, and caches may keep it for the configured time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "embed"
+                ],
+                "summary": "Get a user's journal widget",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/widgets.JournalWidget"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve journal entries",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/embed/{userid}/skills": {
+            "get": {
+                "description": "Returns the user's skills with their proficiency, in compact JSON for embedding on other sites. Only what everyone may see is included. Any origin may fetch it, and caches may keep it for the configured time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "embed"
+                ],
+                "summary": "Get a user's skills widget",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/widgets.SkillsWidget"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve skills",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Streams the authenticated user's events as text/event-stream. Each event carries its ID, so a client reconnecting with the Last-Event-ID header, or the last_event_id query parameter, receives the recent events it missed.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Stream notifications as Server-Sent Events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last event received, for clients that cannot set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of events",
+                        "schema": {
+                            "$ref": "#/definitions/events.Event"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/experience/{userid}": {
+            "get": {
+                "description": "Retrieves all work experience records for the specified user, without the fields their visibility hides from the requester",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "experience"
+                ],
+                "summary": "Get all user experiences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/experience.Experience"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not retrieve experience",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new work experience record for the specified user. It may link to a verified organization with organization_id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "experience"
+                ],
+                "summary": "Create a new experience item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Experience Object",
+                        "name": "Experience",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/experience.Experience"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/experience.Experience"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\t\"Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "error\":\t\"Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "error\":\t\"Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\t\"User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "error\":\t\"Experience already exists",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "error\":\t\"Experience limit reached",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "error\":\t\"Invalid experience type, or organization is not verified",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not insert experience",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/experience/{userid}/bulk-delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the user's experience with the given IDs, or those whose fields match every value of the filter, such as {\"company\": \"Acme\"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "experience"
+                ],
+                "summary": "Delete experience in bulk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The experience to delete",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, or a filter that is not confirmed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the experience",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete experience",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/experience/{userid}/{experienceid}": {
+            "get": {
+                "description": "Retrieves a specific work experience record for the specified user and experience ID, without the fields its visibility hides from the requester",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "experience"
+                ],
+                "summary": "Get specific experience item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Experience ID",
+                        "name": "experienceid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/experience.Experience"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not retrieve experience",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates a specific work experience record for the specified user and experience ID. It may link to a verified organization with organization_id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "experience"
+                ],
+                "summary": "Update specific experience item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Experience ID",
+                        "name": "experienceid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Experience Object",
+                        "name": "Experience",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/experience.Experience"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "message\":\t\"Experience updated",
+                        "schema": {
+                            "$ref": "#/definitions/experience.JSONResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\t\"Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "error\":\t\"Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "error\":\t\"Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "error\":\t\"Organization is not verified",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not update experience",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a specific work experience record for the specified user and experience ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "experience"
+                ],
+                "summary": "Delete specific experience item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Experience ID",
+                        "name": "experienceid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "message\":\t\"Experience deleted",
+                        "schema": {
+                            "$ref": "#/definitions/experience.JSONResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the experience",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Experience not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not delete experience",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "post": {
+                "description": "Executes a GraphQL query against the read-only schema served at /graphql/schema. Authentication is optional; fields are shown according to their visibility, as in the REST API, and the authenticated user also sees their private journal entries and the me field.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "graphql"
+                ],
+                "summary": "Execute a GraphQL query",
+                "parameters": [
+                    {
+                        "description": "GraphQL request with query, operationName and variables",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GraphQL response with data and errors",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql/schema": {
+            "get": {
+                "description": "Returns the GraphQL schema definition served at /graphql",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "graphql"
+                ],
+                "summary": "Get the GraphQL schema",
+                "responses": {
+                    "200": {
+                        "description": "Schema definition",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Reports that the process is up, for liveness probes. Dependencies are not checked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Check the server is up",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/images/{name}": {
+            "get": {
+                "description": "Serves an image saved by the local image store, as its AVIF or WebP variant when the Accept header allows one and the image has it. Images are named by the user's directory and a random name, such as 6650a1b2c3d4e5f6a7b8c9d0/0f1e2d3c4b5a69788796a5b4c3d2e1f0.jpg. Supports conditional requests using ETag and Last-Modified.",
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Retrieve an uploaded image.",
+                "operationId": "get-image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Image name, including the user's directory",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "404": {
+                        "description": "Image not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Serves an image saved by the local image store, as its AVIF or WebP variant when the Accept header allows one and the image has it. Images are named by the user's directory and a random name, such as 6650a1b2c3d4e5f6a7b8c9d0/0f1e2d3c4b5a69788796a5b4c3d2e1f0.jpg. Supports conditional requests using ETag and Last-Modified.",
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Retrieve an uploaded image.",
+                "operationId": "get-image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Image name, including the user's directory",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "404": {
+                        "description": "Image not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/inbox": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the contact requests sent to the current user, newest first. Without a status, new and read requests are listed, leaving out those archived or filed as spam.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inbox"
+                ],
+                "summary": "List contact requests",
+                "parameters": [
+                    {
+                        "enum": [
+                            "new",
+                            "read",
+                            "archived",
+                            "spam"
+                        ],
+                        "type": "string",
+                        "description": "Only list contact requests with the status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/inbox.ContactRequest"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve contact requests",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/inbox/u/{userid}": {
+            "post": {
+                "description": "Sends a message to the user's inbox from the contact form of their profile. The user is notified unless the message is scored as spam. Bots filling in the hidden website field are filed as spam, and the response is the same either way. Senders the user blocked, by their signed in account or the address they give, are rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inbox"
+                ],
+                "summary": "Send a contact request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the user to contact",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The message",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/inbox.ContactForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Blocked by the user",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not send message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/inbox/{requestid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns one of the current user's contact requests, marking it read if it was new",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inbox"
+                ],
+                "summary": "Get a contact request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact request ID",
+                        "name": "requestid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/inbox.ContactRequest"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Contact request not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve contact request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes one of the current user's contact requests",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inbox"
+                ],
+                "summary": "Delete a contact request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact request ID",
+                        "name": "requestid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Contact request not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete contact request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/inbox/{requestid}/reply": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Emails the reply to the address the contact request was sent from, quoting it. Replies to the email go to the address the current user signs in with, so the conversation carries on by email.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inbox"
+                ],
+                "summary": "Reply to a contact request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact request ID",
+                        "name": "requestid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The reply",
+                        "name": "reply",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/inbox.ReplyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, or replying to spam",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Contact request not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not send reply",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/inbox/{requestid}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks one of the current user's contact requests new or read, archives it, or files it as spam. Requests filed as spam are removed after the configured retention.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inbox"
+                ],
+                "summary": "Change a contact request's status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact request ID",
+                        "name": "requestid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The new status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/inbox.StatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Contact request not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update contact request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/integrations/{integrationid}/events": {
+            "post": {
+                "description": "Receives an event pushed by an external system. Events are signed with the integration's secret: the X-Webhook-Signature header is \"sha256=\" followed by the hex HMAC-SHA256 of the X-Webhook-Timestamp header, a dot and the body, as for outbound webhooks, and the timestamp must be within five minutes. The event's user is mapped to an account by an admin, or by email address when the integration matches them. A course.completed event creates a certificate for the course. Each event ID is processed once: an event sent again is answered with the outcome of the first delivery and marked as a duplicate, unless processing it failed. Events about users mapped to no account are answered with 202 and kept, so an admin can replay them once the user is mapped. Events of other types are recorded and ignored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "integrations"
+                ],
+                "summary": "Receive an integration event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Integration ID",
+                        "name": "integrationid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unix time the event was signed at",
+                        "name": "X-Webhook-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature of the event",
+                        "name": "X-Webhook-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Event",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/integrations.Event"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event processed, ignored or rejected",
+                        "schema": {
+                            "$ref": "#/definitions/integrations.EventResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Event kept until its user is mapped to an account",
+                        "schema": {
+                            "$ref": "#/definitions/integrations.EventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid event",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid signature",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not process event",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal": {
+            "get": {
+                "description": "Get all public journal entries, supports filtering by date range, taxonomy, and users. Entries of users who restricted their profile are left out unless the requester may see it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get public journal entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Earliest creation date, YYYY-MM-DD or RFC 3339",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest creation date, YYYY-MM-DD or RFC 3339",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subcategory",
+                        "name": "subcategory",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Topic",
+                        "name": "topic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return of each entry, such as journalID,summary,taxonomy",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/journal.JournalEntry"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests from the address, which was detected scraping",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new journal entry with the status and taxonomy of the user's journal settings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Create a new journal entry",
+                "parameters": [
+                    {
+                        "description": "Journal Entry",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/journal.Entry"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/journal.JournalEntry"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created journal entry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "The user's plan allows no more journal entries",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "The site allows no more journal entries",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Get all public journal entries, supports filtering by date range, taxonomy, and users. Entries of users who restricted their profile are left out unless the requester may see it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get public journal entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Earliest creation date, YYYY-MM-DD or RFC 3339",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest creation date, YYYY-MM-DD or RFC 3339",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subcategory",
+                        "name": "subcategory",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Topic",
+                        "name": "topic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return of each entry, such as journalID,summary,taxonomy",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/journal.JournalEntry"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests from the address, which was detected scraping",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/bulk-delete": {
+            "post": {
+                "description": "Deletes the user's journal entries with the given IDs, or those whose fields match every value of the filter, such as {\"status\": \"private\", \"taxonomy.tags\": \"draft\"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Delete journal entries in bulk",
+                "parameters": [
+                    {
+                        "description": "The journal entries to delete",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, or a filter that is not confirmed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/import": {
+            "post": {
+                "description": "Import posts from a Medium ZIP export or a WordPress WXR export. Referenced images are downloaded into the image store. Published posts are imported as private entries and drafts as pending entries. Posts beyond the number of journal entries the user's plan allows are reported as failed. A Medium export may hold at most 10000 files, with posts of up to 5 MB and 200 MB in all.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Import journal entries",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Medium ZIP or WordPress WXR export",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Export format (medium or wordpress), detected from the file when omitted",
+                        "name": "format",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/journal.ImportResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/settings": {
+            "get": {
+                "description": "Returns the defaults the current user's new journal entries are created with: their status, their taxonomy, whether entries created public are sent to processing first, and who may comment on them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get journal settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/journal.Settings"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the defaults the current user's new journal entries are created with. Entries already created keep their status and taxonomy.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Update journal settings",
+                "parameters": [
+                    {
+                        "description": "The journal settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/journal.SettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/journal.Settings"
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/taxonomy": {
+            "get": {
+                "description": "Lists the terms of each taxonomy field across the user's journal entries, with the number of entries holding each, so misspelt or duplicate terms can be found and renamed, merged or deleted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "List the user's taxonomy terms",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/journal.TermCount"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/taxonomy/{field}/delete": {
+            "post": {
+                "description": "Removes terms from a taxonomy field of the user's journal entries. A dry run, sent with the X-Dry-Run header, returns the entries that would be updated without updating them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Delete taxonomy terms",
+                "parameters": [
+                    {
+                        "enum": [
+                            "categories",
+                            "subcategories",
+                            "topics",
+                            "tags"
+                        ],
+                        "type": "string",
+                        "description": "Taxonomy field",
+                        "name": "field",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The terms to remove",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/journal.DeleteTermsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/journal.TermsResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "No journal entry holds the terms",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/taxonomy/{field}/merge": {
+            "post": {
+                "description": "Replaces several terms of a taxonomy field with one across the user's journal entries, each entry keeping a single copy of it. The term merged into may be one of those merged. A dry run, sent with the X-Dry-Run header, returns the entries that would be updated without updating them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Merge taxonomy terms",
+                "parameters": [
+                    {
+                        "enum": [
+                            "categories",
+                            "subcategories",
+                            "topics",
+                            "tags"
+                        ],
+                        "type": "string",
+                        "description": "Taxonomy field",
+                        "name": "field",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The terms and the one they are merged into",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/journal.MergeTermsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/journal.TermsResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "No journal entry holds the terms",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/taxonomy/{field}/rename": {
+            "post": {
+                "description": "Renames a term of a taxonomy field across the user's journal entries. Renaming a term to one an entry already holds merges them. A dry run, sent with the X-Dry-Run header, returns the entries that would be updated without updating them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Rename a taxonomy term",
+                "parameters": [
+                    {
+                        "enum": [
+                            "categories",
+                            "subcategories",
+                            "topics",
+                            "tags"
+                        ],
+                        "type": "string",
+                        "description": "Taxonomy field",
+                        "name": "field",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The term and its new name",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/journal.RenameTermRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/journal.TermsResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "No journal entry holds the term",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/u/{userid}": {
+            "get": {
+                "description": "Get all journal entries for a specific user by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get user-specific journal entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return of each entry, such as journalID,summary,status",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/journal.JournalEntry"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Get all journal entries for a specific user by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get user-specific journal entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return of each entry, such as journalID,summary,status",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/journal.JournalEntry"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{journalid}": {
+            "get": {
+                "description": "Get a single journal entry by ID, returns metadata if the user is authenticated",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get a single journal entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Journal ID",
+                        "name": "journalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as journalID,summary,taxonomy",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/journal.JournalEntry"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the journal entry for authenticated users, for If-Match when updating it"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests from the address, which was detected scraping",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a journal entry by ID, increments the version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Update a journal entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Journal ID",
+                        "name": "journalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Entry",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/journal.Entry"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the journal entry being updated, required unless require-if-match is off",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/journal.JournalEntry"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the updated journal entry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "428": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a journal entry by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Delete a journal entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Journal ID",
+                        "name": "journalid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Journal entry deleted",
+                        "schema": {
+                            "$ref": "#/definitions/journal.DeleteResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Journal entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Get a single journal entry by ID, returns metadata if the user is authenticated",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get a single journal entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Journal ID",
+                        "name": "journalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as journalID,summary,taxonomy",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/journal.JournalEntry"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the journal entry for authenticated users, for If-Match when updating it"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests from the address, which was detected scraping",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{journalid}/attachments": {
+            "get": {
+                "description": "Links to the attachments of the current version of a journal entry, in order, each served through the entry's access checks. Links to the attachments of entries that aren't public, or whose owner restricted their profile, are signed and stop working at their expiresAt, so they can be embedded where the requester's credentials aren't sent. Entries the requester may not read are reported as not found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "List a journal entry's attachments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Journal ID",
+                        "name": "journalid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/attachments.Link"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Journal entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve journal entry",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{journalid}/attachments/{index}": {
+            "get": {
+                "description": "Serves the attachment at the index among those of the current version of a journal entry, once the requester is found to be allowed to read the entry or presents an unexpired signed link from the attachments list. Only files stored for the entry are served, streamed from the image store; attachments linking elsewhere are not found. Supports conditional and range requests.",
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Retrieve a journal entry's attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Journal ID",
+                        "name": "journalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Position of the attachment, from 0",
+                        "name": "index",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expiry of a signed link, in Unix seconds",
+                        "name": "expires",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature of a signed link",
+                        "name": "signature",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attachment",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "403": {
+                        "description": "Signed link expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Journal entry or attachment not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve attachment",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Serves the attachment at the index among those of the current version of a journal entry, once the requester is found to be allowed to read the entry or presents an unexpired signed link from the attachments list. Only files stored for the entry are served, streamed from the image store; attachments linking elsewhere are not found. Supports conditional and range requests.",
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Retrieve a journal entry's attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Journal ID",
+                        "name": "journalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Position of the attachment, from 0",
+                        "name": "index",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expiry of a signed link, in Unix seconds",
+                        "name": "expires",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature of a signed link",
+                        "name": "signature",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attachment",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "403": {
+                        "description": "Signed link expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Journal entry or attachment not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve attachment",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{journalid}/meta": {
+            "get": {
+                "description": "Get metadata for a journal entry by ID, found only when it is public or owned by the requester",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get journal metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Journal ID",
+                        "name": "journalid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "createdAt\", \"updatedAt\", \"version\", \"status\", \"userID\", \"revision",
+                        "schema": {
+                            "$ref": "#/definitions/journal.SuccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the journal entry, for If-Match when updating it"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "404": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{journalid}/process": {
+            "put": {
+                "description": "Trigger processing for a journal entry by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Process a journal entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Journal ID",
+                        "name": "journalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the journal entry being processed, required unless require-if-match is off",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Journal entry is being processed",
+                        "schema": {
+                            "$ref": "#/definitions/journal.ProcessingResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "428": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{journalid}/related": {
+            "get": {
+                "description": "Get other public journal entries ranked by the number of taxonomy terms they share with the given entry",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get related journal entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Journal ID",
+                        "name": "journalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries to return (default 5, max 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/journal.RelatedEntry"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{journalid}/status": {
+            "put": {
+                "description": "Set the status of a journal entry by ID. Valid statuses are pending, processing, private, public and archived, and only allowed transitions between them are accepted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Set the status of a journal entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Journal ID",
+                        "name": "journalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/journal.StatusRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the journal entry being updated, required unless require-if-match is off",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Journal status updated",
+                        "schema": {
+                            "$ref": "#/definitions/journal.ProcessingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "The entry was hidden by a moderator and cannot be made public",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "428": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{journalid}/version": {
+            "put": {
+                "description": "Set the current version of a journal entry by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Set the current version of a journal entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Journal ID",
+                        "name": "journalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Version",
+                        "name": "version",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/journal.VersionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the journal entry being updated, required unless require-if-match is off",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/journal.JournalEntry"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the updated journal entry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "428": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{journalid}/versions": {
+            "get": {
+                "description": "Get all versions of a journal entry owned by the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get journal versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Journal ID",
+                        "name": "journalid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/journal.Entry"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Error message",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/languages/{userid}": {
+            "get": {
+                "description": "Retrieves every language a user speaks with their CEFR proficiency level, without the fields their visibility hides from the requester",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Get all languages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/languages.Language"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve languages",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a language the user speaks, with its BCP 47 tag and a proficiency level of A1, A2, B1, B2, C1, C2 or native",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Add a language",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Language JSON object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/languages.Language"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created language with its ID, and a message",
+                        "schema": {
+                            "$ref": "#/definitions/languages.Language"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the languages",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Language limit reached",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create language",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/languages/{userid}/bulk-delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the user's languages with the given IDs, or those whose fields match every value of the filter, such as {\"proficiency\": \"A1\"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Delete languages in bulk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The languages to delete",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, or a filter that is not confirmed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the languages",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete languages",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/languages/{userid}/{languageid}": {
+            "get": {
+                "description": "Retrieves a specific language of a user, without the fields its visibility hides from the requester",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Get a language",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language ID",
+                        "name": "languageid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/languages.Language"
+                        }
+                    },
+                    "404": {
+                        "description": "Language not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve language",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates or creates a specific language of a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Update or create a language",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language ID",
+                        "name": "languageid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Language JSON object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/languages.Language"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the languages",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update language",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a specific language of a user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Delete a language",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language ID",
+                        "name": "languageid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the languages",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Language not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete language",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the current user's notifications, newest first. A notification is unread while its readAt is null.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List notifications",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only list unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of notifications to return (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/notifications.Notification"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve notifications",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the channels each kind of notification is sent over: email, in-app and webhook. Kinds the user has not chosen channels for show their defaults.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Get notification preferences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/notifications.Preferences"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve notification preferences",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the channels of the kinds of notification in the request: comment, endorsement, certificate_expiring, certificate_archived, processing_finished, recommendation or contact_request. Other kinds keep their channels. Webhook notifications are delivered to the user's webhooks subscribed to notification.created.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Update notification preferences",
+                "parameters": [
+                    {
+                        "description": "Channels per kind of notification",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/notifications.PreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/notifications.Preferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update notification preferences",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/read": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks every unread notification of the current user read, returning how many were marked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark all notifications read",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not mark notifications read",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/unread-count": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Counts the current user's unread notifications, for showing a badge",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Count unread notifications",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not count notifications",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/{notificationid}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a notification from the notification center",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Delete a notification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "notificationid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Notification not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/{notificationid}/read": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks a notification read. Marking a notification read again keeps the time it was first read.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark a notification read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "notificationid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Notification not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/openapi.json": {
+            "get": {
+                "description": "Returns the OpenAPI 3 document of every route the server serves, described by the operations documented on their handlers. Requests are validated against it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "openapi"
+                ],
+                "summary": "Get the OpenAPI document",
+                "responses": {
+                    "200": {
+                        "description": "OpenAPI 3 document",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations": {
+            "get": {
+                "description": "Lists the organizations on the instance, sorted by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List organizations",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only list verified organizations",
+                        "name": "verified",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/organizations.Organization"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve organizations",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates an organization with the current user as its owner. It may be linked from experience once an admin verifies it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Create an organization",
+                "parameters": [
+                    {
+                        "description": "The organization's profile",
+                        "name": "organization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organizations.OrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.Organization"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created organization"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create organization",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/u/{userid}": {
+            "get": {
+                "description": "Lists the memberships of a user, for showing their organizations on their profile",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List a user's organizations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/organizations.Member"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve memberships",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{organizationid}": {
+            "get": {
+                "description": "Returns an organization's profile and its members, in the order they joined",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Get an organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organizationid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.Page"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve organization",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces an organization's profile. Only its owners and admins may update it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Update an organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organizationid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The organization's profile",
+                        "name": "organization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organizations.OrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin of the organization",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update organization",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes an organization and its memberships. Only its owners may delete it, after confirming their password. Experience linked to it keeps the link, which no longer resolves.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Delete an organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organizationid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an owner of the organization, or the password must be confirmed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete organization",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{organizationid}/members/{userid}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a user to an organization with a role, or changes the role of a member. Owners and admins manage members, but only owners may make or unmake owners, and the last owner can't step down.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Add or update a member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organizationid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The member's role",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organizations.MemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.Member"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not allowed to give or take the role",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The organization would have no owner",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not save member",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a member from an organization. Members may leave, and owners and admins remove others, but only owners may remove owners, and the last owner can't leave.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Remove a member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organizationid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not allowed to remove the member",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Member not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The organization would have no owner",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not remove member",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{organizationid}/verification": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Verifies an organization, so experience may link to it, or withdraws its verification. Links made while it was verified are kept. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Verify an organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organizationid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Whether the organization is verified",
+                        "name": "verification",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organizations.VerificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not verify organization",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/privacy/access/{token}": {
+            "get": {
+                "description": "Lets in the visitor the link was emailed to, setting a cookie with which they may see the user's restricted profile until the access expires. Following the link again renews the access.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Follow an access link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Access token from the emailed link",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/privacy.Access"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not follow link",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/privacy/allowlist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the visitors who may see the current user's profile while it is restricted, in the order they were added, with when each last followed their link",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "List the allowlist",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/privacy.Visitor"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve allowlist",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds an email address to the current user's allowlist and emails it a link to their profile. Following the link lets the visitor see the profile while it is restricted, for a while each time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Add a visitor to the allowlist",
+                "parameters": [
+                    {
+                        "description": "The visitor's email address",
+                        "name": "visitor",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/privacy.VisitorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/privacy.Visitor"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The address is already on the allowlist",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "The allowlist is full",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not add visitor",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/privacy/allowlist/{visitorid}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a visitor from the current user's allowlist. Their link stops working straight away.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Remove a visitor from the allowlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Visitor ID",
+                        "name": "visitorid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not remove visitor",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/privacy/blocks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the users the current user blocked, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "List blocked users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/privacy.Block"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve blocked users",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/privacy/blocks/{userid}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Blocks a user, whose recommendations and contact requests for the current user are then rejected. Blocking a user again keeps the first block.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Block a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the user to block",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Users can't block themselves",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not block user",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the current user's block of a user, who may reach them again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Unblock a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the user to unblock",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not unblock user",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/privacy/settings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns whether the current user's profile is restricted to their allowlist",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Get privacy settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/privacy.Settings"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve privacy settings",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restricts the current user's profile to their allowlist, or opens it again. While restricted, the profile and the documents shown on it are only shown to the user, admins and the visitors on the allowlist, and the user is left out of search.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Update privacy settings",
+                "parameters": [
+                    {
+                        "description": "The privacy settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/privacy.SettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/privacy.Settings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update privacy settings",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/{userid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the profile of the user with the specified user ID. Fields are shown according to the profile's visibility rules: private fields, including the email address unless the owner shares it, are only returned to the owner and admins, and members fields to signed in users.",
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Retrieve a user's profile.",
+                "operationId": "get-profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose profile to get",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as name,bio,profile_img",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/profile.Profile"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the profile, for If-Match when updating it"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Unknown field",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve profile",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the profile of the user with the specified user ID using the provided profile data.",
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Update a user's profile.",
+                "operationId": "update-profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose profile to update",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Profile object that needs to be updated",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/profile.Profile"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the profile being updated, required unless require-if-match is off",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile updated",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the updated profile, when If-Match named one"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "The user's plan does not include a custom domain",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the profile",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Profile has been changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match is required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update profile",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new profile for the user with the specified user ID using the provided profile data.",
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Create a new user profile.",
+                "operationId": "create-profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user for whom the profile is to be created",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Profile object that needs to be created",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/profile.Profile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The created profile, and a message",
+                        "schema": {
+                            "$ref": "#/definitions/profile.Profile"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "The user's plan does not include a custom domain",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the profile",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create profile",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the profile of the user with the specified user ID. Fields are shown according to the profile's visibility rules: private fields, including the email address unless the owner shares it, are only returned to the owner and admins, and members fields to signed in users.",
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Retrieve a user's profile.",
+                "operationId": "get-profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose profile to get",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as name,bio,profile_img",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/profile.Profile"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the profile, for If-Match when updating it"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Unknown field",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve profile",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/{userid}/availability": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves whether the user is open to work, when they can start and the roles, locations and remote work they are after. The availability field of the profile's visibility rules decides who may see it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Retrieve a user's availability.",
+                "operationId": "get-profile-availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose availability to get",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Availability retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/profile.Availability"
+                        }
+                    },
+                    "404": {
+                        "description": "Availability not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve availability",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets whether the user is open to work (open, passive or closed), the date they can start, and the roles, locations and remote work (onsite, hybrid or remote) they are after. Open and passive users whose availability is public are found by the talent search filters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Update a user's availability.",
+                "operationId": "update-profile-availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose availability to update",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Availability of the user",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/profile.Availability"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Availability updated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the profile",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update availability",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the user's availability from their profile and from the talent search",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Clear a user's availability.",
+                "operationId": "delete-profile-availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose availability to clear",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Availability cleared",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the profile",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not clear availability",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/{userid}/calendar.ics": {
+            "get": {
+                "description": "Returns an iCalendar feed of all-day events for the start and end of each role, the completion of each qualification, each award received and the expiry of each certificate that has not yet expired, with a reminder 30 days before. Calendar apps can subscribe to its URL to overlay the timeline on the user's calendar. Partial dates fall on the first day of their month or year.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Get a user's career timeline as a calendar.",
+                "operationId": "get-calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not build calendar",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/{userid}/full": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the profile of the user with the specified user ID together with their experience, qualifications, certificates, awards, languages, skills and approved recommendations, for rendering a profile page in one request. The sections of disabled modules are left out. Fields are shown according to the visibility rules of each document, as by the endpoints of each section. Full profiles are cached for a few minutes and dropped whenever any of their parts is written. The ETag is computed over the response, so a client sending it back in If-None-Match gets 304 until something it can see changes.",
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Retrieve a user's full profile.",
+                "operationId": "get-full-profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose profile to get",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Full profile retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/profile.Aggregate"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the response, for If-None-Match"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "404": {
+                        "description": "Profile not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve profile",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the profile of the user with the specified user ID together with their experience, qualifications, certificates, awards, languages, skills and approved recommendations, for rendering a profile page in one request. The sections of disabled modules are left out. Fields are shown according to the visibility rules of each document, as by the endpoints of each section. Full profiles are cached for a few minutes and dropped whenever any of their parts is written. The ETag is computed over the response, so a client sending it back in If-None-Match gets 304 until something it can see changes.",
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Retrieve a user's full profile.",
+                "operationId": "get-full-profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose profile to get",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Full profile retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/profile.Aggregate"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the response, for If-None-Match"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "404": {
+                        "description": "Profile not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve profile",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/{userid}/generate-summary": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Has the AI provider write a headline and bio from the user's experience, qualifications and skills, in the tone and length asked for. Nothing is stored: the user saves the bio to their profile once they have edited it. Each summary counts against the user's AI request quota. Only available when an AI provider is configured, the ai-processing feature is enabled for the user and their plan includes AI features.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Generate a profile summary.",
+                "operationId": "generate-summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user to write the summary for",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tone and length of the summary",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/profile.SummaryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Generated summary",
+                        "schema": {
+                            "$ref": "#/definitions/profile.Summary"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid options",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "The user's plan does not include AI features",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the profile",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found, when the feature is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "The user has no records to write a summary from",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "AI request quota used up",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not generate summary",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "No AI provider is configured",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/{userid}/image": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the profile image of the user with the specified user ID.",
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Update a user's profile image.",
+                "operationId": "update-profile-image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose profile image to update",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Profile image to upload",
+                        "name": "profileImage",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the profile being changed, required unless require-if-match is off",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile image updated",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Profile image not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "The user's plan has no storage left for the image",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the profile",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Profile has been changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "The user has no storage left for the image",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match is required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not upload image",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/{userid}/import/resume": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reads the experience, qualifications and skills of a PDF or DOCX CV, with the configured parser or the one named. Nothing is stored: the proposed records are returned for the user to correct and send to the confirm route. The ai parser is only available when an AI provider is configured, the ai-processing feature is enabled for the user and their plan includes AI features, and each CV it reads counts against the user's AI request quota. Users whose plan does not include AI features get the heuristic parser unless they name one. A dry run with the ai parser checks the CV without sending it to the AI provider and proposes no records.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Read a CV into proposed records",
+                "operationId": "import-resume",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose CV it is",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CV as a PDF or DOCX file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parser to read the CV with, heuristic or ai, defaulting to the configured one",
+                        "name": "parser",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/resume.Proposal"
+                        }
+                    },
+                    "400": {
+                        "description": "CV not found, too large or not a PDF or DOCX file",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "The ai parser was named but the user's plan does not include AI features",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "No text could be read from the CV",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "AI request quota used up",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not read CV",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/{userid}/import/resume/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores the experience, qualifications and skills proposed by the import route that the user kept, as corrected by them. Every record is validated before any is stored, and none are when the user may not keep that many more of any kind.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Store the records of a CV",
+                "operationId": "confirm-resume",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose CV it is",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Records to store",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/resume.Proposal"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The stored records, with their IDs",
+                        "schema": {
+                            "$ref": "#/definitions/resume.Proposal"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid records",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "Document limit of the user's plan reached",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Document limit of the site reached",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not store records",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/qualifications/{userid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all qualifications associated with the specified user ID, without the fields their visibility hides from the requester.",
+                "tags": [
+                    "Qualifications"
+                ],
+                "summary": "Get all qualifications for a user.",
+                "operationId": "get-qualifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose qualifications are to be retrieved",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/qualifications.Qualification"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve qualifications",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new qualification entry associated with the specified user ID using the provided qualification data.",
+                "tags": [
+                    "Qualifications"
+                ],
+                "summary": "Create a new qualification for a user.",
+                "operationId": "post-qualification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user for whom the qualification is to be created",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Qualification object to be created",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/qualifications.Qualification"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created qualification with its ID, and a message",
+                        "schema": {
+                            "$ref": "#/definitions/qualifications.Qualification"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the qualifications",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Qualification limit reached",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update qualification",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/qualifications/{userid}/bulk-delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the user's qualifications with the given IDs, or those whose fields match every value of the filter, such as {\"institution\": \"Open University\"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Qualifications"
+                ],
+                "summary": "Delete qualifications in bulk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The qualifications to delete",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, or a filter that is not confirmed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the qualifications",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete qualifications",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/qualifications/{userid}/{qualificationid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the qualification entry associated with the specified user ID and qualification ID, without the fields its visibility hides from the requester.",
+                "tags": [
+                    "Qualifications"
+                ],
+                "summary": "Get a specific qualification for a user.",
+                "operationId": "get-qualification-entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose qualification is to be retrieved",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The ID of the qualification to be retrieved",
+                        "name": "qualificationid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/qualifications.Qualification"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve qualification",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the qualification entry associated with the specified user ID and qualification ID using the provided qualification data.",
+                "tags": [
+                    "Qualifications"
+                ],
+                "summary": "Update a specific qualification for a user.",
+                "operationId": "put-qualification-entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose qualification is to be updated",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The ID of the qualification to be updated",
+                        "name": "qualificationid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Qualification object that needs to be updated",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/qualifications.Qualification"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Qualification updated",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the qualifications",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update qualification",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the qualification entry associated with the specified user ID and qualification ID.",
+                "tags": [
+                    "Qualifications"
+                ],
+                "summary": "Delete a specific qualification for a user.",
+                "operationId": "delete-qualification-entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose qualification is to be deleted",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The ID of the qualification to be deleted",
+                        "name": "qualificationid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Qualification deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the qualifications",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Qualification not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete qualification",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/qualifications/{userid}/{qualificationid}/cert_image": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the certificate image for the qualification associated with the specified user ID and qualification ID using the provided image file.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "Qualifications"
+                ],
+                "summary": "Upload a certificate image for a qualification.",
+                "operationId": "put-qualification-image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The ID of the user whose qualification certificate image is to be updated",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The ID of the qualification whose certificate image is to be updated",
+                        "name": "qualificationid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Certificate image file to upload",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "cert image uploaded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "The user's plan has no storage left for the image",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the qualifications",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "The user has no storage left for the image",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "could not update qualification",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Reports whether the configured storage, cache and image store are reachable, for readiness probes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Check the server is ready",
+                "responses": {
+                    "200": {
+                        "description": "Every dependency is reachable",
+                        "schema": {
+                            "$ref": "#/definitions/health.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "A dependency is unreachable",
+                        "schema": {
+                            "$ref": "#/definitions/health.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists every recommendation written for the current user, newest first, including those awaiting their approval and those they hid",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "List received recommendations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only list recommendations with the status: pending, approved or hidden",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/recommendations.Recommendation"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve recommendations",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/recommendations/invitations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the referees the current user invited, newest first. An invitation is pending until it is used or expires.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "List invitations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/recommendations.Invitation"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve invitations",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Emails a link to write a recommendation for the current user, for referees without an account. The link can be used once, until the invitation expires.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Invite a referee",
+                "parameters": [
+                    {
+                        "description": "The referee to invite",
+                        "name": "invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/recommendations.InvitationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/recommendations.Invitation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Too many pending invitations",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not send invitation",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/recommendations/invitations/{invitationid}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes one of the current user's invitations, so its link can no longer be used",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Withdraw an invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invitation ID",
+                        "name": "invitationid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete invitation",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/recommendations/referee/{token}": {
+            "get": {
+                "description": "Returns who the invitation with the token from the emailed link asks the referee to recommend, for the form they write their recommendation in",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Get a referee's invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invitation token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/recommendations.RefereeInvitation"
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation not found, used or expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve invitation",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Writes a recommendation through the link emailed to an invited referee, signed with the name they were invited by. Each link can be used once. The recommendation is shown on the user's profile once they approve it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Write a recommendation as a referee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invitation token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The recommendation",
+                        "name": "recommendation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/recommendations.RecommendationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation not found, used or expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not save recommendation",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/recommendations/u/{userid}": {
+            "get": {
+                "description": "Lists the recommendations the user approved, newest first, for their public profile",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "List a user's recommendations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/recommendations.Recommendation"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve recommendations",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Writes a recommendation for another user, signed with the current user's name. It is shown on their profile once they approve it. Users can't recommend those who blocked them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Write a recommendation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the user to recommend",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The recommendation",
+                        "name": "recommendation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/recommendations.RecommendationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/recommendations.Recommendation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, or recommending oneself",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Blocked by the user",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not save recommendation",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/recommendations/{recommendationid}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a recommendation. The recommended user, its author and admins may delete it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Delete a recommendation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recommendation ID",
+                        "name": "recommendationid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Recommendation not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete recommendation",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/recommendations/{recommendationid}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Approves one of the current user's recommendations, showing it on their public profile, or hides it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Approve or hide a recommendation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recommendation ID",
+                        "name": "recommendationid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The new status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/recommendations.StatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Recommendation not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update recommendation",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/report": {
+            "post": {
+                "description": "Flags a public profile, by its user ID, or a public journal entry to the moderators. Each signed in user, or each address for visitors when anonymous reports are allowed, may have one open report on a piece of content. Content reaching the configured number of open reports is hidden until an admin reviews it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "Report content",
+                "parameters": [
+                    {
+                        "description": "What is reported and why",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/moderation.ReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/moderation.ReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, or the user's own content",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated, when anonymous reports are not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "No such public content",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Already reported",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not store report",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Searches the titles and text of public profiles, skills, experience, qualifications, certificates and public journal entries, best matches first, or most recently updated first without a query. Facets count every match by kind, taxonomy term, skill and institution. The availability, role, location, remote and available_by filters find profiles of users open to work who show their availability publicly. Results may trail changes by a few seconds.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search profiles and journals",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Words to find in titles and text",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "profile",
+                            "skill",
+                            "experience",
+                            "qualification",
+                            "certificate",
+                            "journal"
+                        ],
+                        "type": "string",
+                        "description": "Only match this kind of document",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only match journal entries with this taxonomy term",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only match skills and profiles with this skill",
+                        "name": "skill",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only match qualifications, certificates and profiles with this institution",
+                        "name": "institution",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "open",
+                            "passive"
+                        ],
+                        "type": "string",
+                        "description": "Only match profiles whose owner is open to work with this status",
+                        "name": "availability",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only match profiles whose owner is open to this role",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only match profiles whose owner is open to work in this location",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "onsite",
+                            "hybrid",
+                            "remote"
+                        ],
+                        "type": "string",
+                        "description": "Only match profiles whose owner prefers this way of working",
+                        "name": "remote",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only match profiles whose owner is open to work and can start on or before this date (YYYY-MM-DD)",
+                        "name": "available_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of hits to return (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of hits to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/search.Results"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kind, availability, remote, available_by, limit or offset",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not search",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/search/semantic": {
+            "get": {
+                "description": "Finds the profile summaries and public journal entries closest in meaning to the query, even when they share none of its words, closest first. The score is the cosine similarity of the query and the document. Only available when an embedding provider is configured. Results may trail changes while they are embedded in the background.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search profiles and journals by meaning",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "What to find",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "profile",
+                            "journal"
+                        ],
+                        "type": "string",
+                        "description": "Only match this kind of document",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of hits to return (default 20, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/search.SemanticResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing query, or invalid kind or limit",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not search",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "No embedding provider is configured",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/search/suggest": {
+            "get": {
+                "description": "Returns the names of public profiles, skills, journal taxonomy terms and institutions that start with the prefix, or have a word starting with it, ignoring case, for completing the global search box. Each type has its own limit. Values starting with the prefix come first, then names of the most recently updated profiles and the skills, terms and institutions found in the most documents. Suggestions may trail changes by a few seconds.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Suggest search terms",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Prefix typed so far (at most 100 characters)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "names",
+                            "skills",
+                            "tags",
+                            "institutions"
+                        ],
+                        "type": "string",
+                        "description": "Comma-separated types to suggest (default all)",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of suggestions of each type (default 5, max 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/search.Suggestions"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or too long prefix, or invalid types or limit",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not suggest",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/shares": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the current user's packages, newest first, with how often and when each was last viewed. Expired and revoked packages are listed too.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "List share packages",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/shares.Package"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve packages",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Bundles the current user's profile with the experience, qualifications and certificates named, and optionally a PDF of them, behind a read-only link for a recruiter. The link is only returned now. It works for the days given, or the configured default, until it expires or is revoked. The recipient sees the documents as they are when they follow the link, as a signed in member would, even while the profile is restricted to its allowlist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "Create a share package",
+                "parameters": [
+                    {
+                        "description": "The recipient and the documents to share",
+                        "name": "package",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/shares.PackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/shares.CreatedPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, a document the user does not have, or a link lasting too long",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "The user has too many packages whose link works",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create package",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/shares/view/{token}": {
+            "get": {
+                "description": "Returns the package the link was made for: the profile and the documents chosen, without the fields their visibility keeps from signed in members, and where to download its PDF when one is offered. Documents deleted since the package was made are left out. Each view is recorded for the package's owner.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "View a share package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token from the package's link",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/shares.Bundle"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found, expired or revoked",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not open package",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/shares/view/{token}/pdf": {
+            "get": {
+                "description": "Returns the package the link was made for as a printable PDF, when its owner offered one, without the fields their visibility keeps from signed in members. Each download is recorded for the package's owner.",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "Download a share package as a PDF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token from the package's link",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found, expired or revoked, or no PDF offered",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not open package",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/shares/{shareid}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stops the link of one of the current user's packages from working straight away. The package stays listed with its views.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "Revoke a share package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "shareid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Package not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not revoke package",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/shares/{shareid}/views": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists each time the recipient opened one of the current user's packages or downloaded its PDF, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "List the views of a share package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "shareid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/shares.View"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Package not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve views",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/site": {
+            "get": {
+                "description": "Returns the name, logo and colour of the site serving the request, chosen by its host in multi-tenant deployments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "site"
+                ],
+                "summary": "Get site branding",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tenant.Branding"
+                        }
+                    }
+                }
+            }
+        },
+        "/skills/{userid}": {
+            "get": {
+                "description": "Retrieve all skills for a specific user, without the fields their visibility hides from the requester",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skills"
+                ],
+                "summary": "Retrieve all skills for a specific user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Skills retrieved",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/skills.Skill"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "error\":\t\"Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "error\":\t\"Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\t\"Skill not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not retrieve skills",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new skill for a specific user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skills"
+                ],
+                "summary": "Create a new skill for a specific user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Skill details",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/skills.Skill"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created skill with its ID, and a message",
+                        "schema": {
+                            "$ref": "#/definitions/skills.Skill"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Skill not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Skill already exists",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Skill limit reached",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create skill",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/skills/{userid}/bulk-delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the user's skills with the given IDs, or those whose fields match every value of the filter, such as {\"proficiency_level\": \"Beginner\"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skills"
+                ],
+                "summary": "Delete skills in bulk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The skills to delete",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/bulk.DeleteResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, or a filter that is not confirmed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the skills",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete skills",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/skills/{userid}/suggestions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Has the AI provider read the user's experience and suggest the skills it shows that are not yet in their skills, with an estimated proficiency and the dates they were used. Nothing is stored: a suggestion is accepted by creating its skill. Each request counts against the user's AI request quota. Only available when an AI provider is configured, the ai-processing feature is enabled for the user and their plan includes AI features.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skills"
+                ],
+                "summary": "Suggest skills from experience",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Suggested skills",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/skills.Suggestion"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "error\":\t\"Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "error\":\t\"The user's plan does not include AI features",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "error\":\t\"Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\t\"Not found, when the feature or the experience module is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "error\":\t\"AI request quota used up",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not suggest skills",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "error\":\t\"No AI provider is configured",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/skills/{userid}/{skillId}": {
+            "put": {
+                "description": "Update a specific skill for a specific user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skills"
+                ],
+                "summary": "Update a specific skill for a specific user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Skill Name",
+                        "name": "skillname",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Skill details",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/skills.Skill"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Skill updated",
+                        "schema": {
+                            "$ref": "#/definitions/skills.JSONResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Skill not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update skill",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/skills/{userid}/{skillid}": {
+            "get": {
+                "description": "Retrieve a specific skill for a specific user, without the fields its visibility hides from the requester",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skills"
+                ],
+                "summary": "Retrieve a specific skill for a specific user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Skill ID",
+                        "name": "skillid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Skill retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/skills.Skill"
+                        }
+                    },
+                    "401": {
+                        "description": "error\":\t\"Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\t\"Skill not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\t\"Could not retrieve skill",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a specific skill for a specific user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skills"
+                ],
+                "summary": "Delete a specific skill for a specific user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Skill ID",
+                        "name": "skillid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Skill deleted",
+                        "schema": {
+                            "$ref": "#/definitions/skills.JSONResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Skill not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete skill",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/confirm/{token}": {
+            "get": {
+                "description": "Serves the page the confirmation email links to, with a button posting the token back to confirm the subscription. Opening the page changes nothing.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Show the confirmation page of a journal digest subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Confirmation token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Confirmation page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Activates a pending subscription using the token sent in the confirmation email. Browsers submitting the confirmation page are answered with a page, other clients with JSON.",
+                "produces": [
+                    "application/json",
+                    "text/html"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Confirm a journal digest subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Confirmation token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription confirmed",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions.JSONResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not confirm subscription",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/unsubscribe/{token}": {
+            "get": {
+                "description": "Serves the page the unsubscribe link of every digest email opens, with a button posting the token back to unsubscribe. Opening the page changes nothing.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Show the unsubscribe page of a journal digest subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unsubscribe token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unsubscribe page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Removes a subscription using the unsubscribe token included in every digest email. Browsers submitting the unsubscribe page are answered with a page, other clients with JSON.",
+                "produces": [
+                    "application/json",
+                    "text/html"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Unsubscribe from a journal digest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unsubscribe token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unsubscribed",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions.JSONResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not unsubscribe",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{userid}": {
+            "post": {
+                "description": "Registers an email for a daily or weekly digest of a user's new public journal entries. A confirmation email is sent and the subscription is only active once confirmed. Hidden and restricted profiles can't be subscribed to. An email already confirmed keeps its subscription and is sent nothing. Requests are limited per address and per email.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Subscribe to a journal digest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription details",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions.SubscribeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Already subscribed",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions.JSONResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Confirmation email sent",
+                        "schema": {
+                            "$ref": "#/definitions/subscriptions.JSONResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found, or their profile is hidden or restricted",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many subscription requests",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create subscription",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the webhooks created by the current user. Secrets are not included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/webhooks.Webhook"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribes a URL to events. Each delivery is a JSON POST signed with the returned secret: the X-Webhook-Signature header is \"sha256=\" followed by the hex HMAC-SHA256 of the X-Webhook-Timestamp header, a dot and the body. Failed deliveries are retried with backoff. Admins may create global webhooks receiving the events of every user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create a webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook URL and events",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.CreatedWebhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required for global webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create webhook",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{webhookid}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a webhook. Deliveries still queued for it are abandoned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{webhookid}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the deliveries made to a webhook, newest first, with their status, attempts and the last response status or error",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of deliveries to return (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/webhooks.Delivery"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve deliveries",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws": {
+            "get": {
+                "description": "Upgrades to a WebSocket that receives the authenticated user's events as JSON messages. Messages sent by the client are ignored.",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Stream notifications over a WebSocket",
+                "responses": {
+                    "101": {
+                        "description": "Switching protocols, events follow as messages",
+                        "schema": {
+                            "$ref": "#/definitions/events.Event"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "activity.Feed": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/activity.Item"
+                    }
+                },
+                "next": {
+                    "description": "Next is the before parameter returning the next page, empty on the last page",
+                    "type": "string"
+                }
+            }
+        },
+        "activity.Item": {
+            "type": "object",
+            "properties": {
+                "document": {
+                    "description": "Document is the post, certificate or role, without the fields the requester may not see",
+                    "type": "object"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "resourceID": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "activitypub.Activity": {
+            "type": "object",
+            "properties": {
+                "@context": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "cc": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "object": {
+                    "description": "Object is the object acted on, either embedded or as its ID",
+                    "type": "object"
+                },
+                "published": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "activitypub.Actor": {
+            "type": "object",
+            "properties": {
+                "@context": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "discoverable": {
+                    "description": "Discoverable lets Mastodon list the actor in its directory and suggest it to follow",
+                    "type": "boolean"
+                },
+                "followers": {
+                    "type": "string"
+                },
+                "icon": {
+                    "$ref": "#/definitions/activitypub.Image"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inbox": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "outbox": {
+                    "type": "string"
+                },
+                "preferredUsername": {
+                    "type": "string"
+                },
+                "publicKey": {
+                    "$ref": "#/definitions/activitypub.PublicKey"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "activitypub.Image": {
+            "type": "object",
+            "properties": {
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "activitypub.Note": {
+            "type": "object",
+            "properties": {
+                "@context": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "attributedTo": {
+                    "type": "string"
+                },
+                "cc": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "content": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "published": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/activitypub.Tag"
+                    }
+                },
+                "to": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "activitypub.OrderedCollection": {
+            "type": "object",
+            "properties": {
+                "@context": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "first": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "totalItems": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "activitypub.OrderedCollectionPage": {
+            "type": "object",
+            "properties": {
+                "@context": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "next": {
+                    "type": "string"
+                },
+                "orderedItems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/activitypub.Activity"
+                    }
+                },
+                "partOf": {
+                    "type": "string"
+                },
+                "totalItems": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "activitypub.PublicKey": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "publicKeyPem": {
+                    "type": "string"
+                }
+            }
+        },
+        "activitypub.Tag": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "activitypub.WebFinger": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/activitypub.WebFingerLink"
+                    }
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "activitypub.WebFingerLink": {
+            "type": "object",
+            "properties": {
+                "href": {
+                    "type": "string"
+                },
+                "rel": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.CollectionStats": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "description": "Bytes is the storage used by the collection, including its indexes",
+                    "type": "integer"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.Signups": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.User"
+                    }
+                }
+            }
+        },
+        "admin.Stats": {
+            "type": "object",
+            "properties": {
+                "collections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.CollectionStats"
+                    }
+                },
+                "storageBytes": {
+                    "description": "StorageBytes is the storage used by every collection together",
+                    "type": "integer"
+                }
+            }
+        },
+        "admin.User": {
+            "type": "object",
+            "properties": {
+                "admin": {
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "type": "boolean"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "passwordResetRequired": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "apierror.Response": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Machine-readable error code\nexample: not_found",
+                    "type": "string"
+                },
+                "details": {
+                    "description": "Optional structured details, such as per-field validation errors"
+                },
+                "message": {
+                    "description": "Human-readable error message\nexample: Journal entry not found",
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiusage.Aggregate": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "perDay": {
+                    "description": "PerDay holds the days calls were made on, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiusage.DayUsage"
+                    }
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "top": {
+                    "description": "Top holds the users who made the most calls, busiest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiusage.UserUsage"
+                    }
+                }
+            }
+        },
+        "apiusage.DayUsage": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "users": {
+                    "description": "Users is how many users made calls on the day, in the admin aggregates only",
+                    "type": "integer"
+                }
+            }
+        },
+        "apiusage.TokenUsage": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "integer"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "token": {
+                    "description": "Token identifies the token without revealing it",
+                    "type": "string"
+                }
+            }
+        },
+        "apiusage.Usage": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "perDay": {
+                    "description": "PerDay holds the days the user made calls on, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiusage.DayUsage"
+                    }
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "tokens": {
+                    "description": "Tokens holds the tokens the user made calls with, most recently used first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiusage.TokenUsage"
+                    }
+                }
+            }
+        },
+        "apiusage.UserUsage": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "integer"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "tokens": {
+                    "description": "Tokens is how many session tokens the user made calls with",
+                    "type": "integer"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "attachments.File": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "journalID": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the name the file is counted against the user's storage quota under",
+                    "type": "string"
+                },
+                "orphanedAt": {
+                    "description": "OrphanedAt is when the file was first found unreferenced, nil while an entry refers to it",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "attachments.Link": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "description": "ExpiresAt is when a signed link to an attachment of an entry that isn't public stops working",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "attachments.Report": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "description": "Checked is the number of files tracked",
+                    "type": "integer"
+                },
+                "deleted": {
+                    "description": "Deleted are the files removed after staying unreferenced for the grace period",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/attachments.File"
+                    }
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "freedBytes": {
+                    "type": "integer"
+                },
+                "orphaned": {
+                    "description": "Orphaned are the files found unreferenced for the first time, kept for the grace period in case an\nentry refers to them again",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/attachments.File"
+                    }
+                },
+                "restored": {
+                    "description": "Restored are the files an entry refers to again since they were found unreferenced",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/attachments.File"
+                    }
+                }
+            }
+        },
+        "audit.Entry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actorID": {
+                    "description": "ActorID is the user who made the change, empty for changes made by the system such as background jobs",
+                    "type": "string"
+                },
+                "changes": {
+                    "description": "Changes maps each changed field to a summary of its values before and after the change",
+                    "type": "object"
+                },
+                "clientIP": {
+                    "description": "ClientIP is the address of the client that made the change, behind any trusted proxies",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "requestID": {
+                    "type": "string"
+                },
+                "resource": {
+                    "type": "string"
+                },
+                "resourceID": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "userID": {
+                    "description": "UserID is the user owning the changed resource",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.EmailChangeRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 254
+                }
+            }
+        },
+        "auth.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.PasswordResetRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RegisterForm": {
+            "type": "object",
+            "properties": {
+                "formToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 254
+                },
+                "formToken": {
+                    "description": "FormToken is the token fetched with the registration form, recording when it was shown",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8
+                },
+                "website": {
+                    "description": "Website is hidden from people by the form, so a value is left by bots filling in every field",
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "auth.RegistrationRejections": {
+            "type": "object",
+            "properties": {
+                "reasons": {
+                    "description": "Reasons counts the rejections by reason: honeypot, disposable_email, too_fast and invalid_form_token",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "since": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "auth.SudoRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "automation.Certificate": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "institution": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "automation.CertificateRequest": {
+            "type": "object",
+            "required": [
+                "institution",
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "end": {
+                    "type": "string"
+                },
+                "institution": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "start": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "automation.CreatedKey": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Prefix is the start of the key, to tell keys apart",
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "automation.Event": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is unique to the change, for tools telling new events from those already seen",
+                    "type": "string"
+                },
+                "resource": {
+                    "type": "string"
+                },
+                "resourceID": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type is the resource and what was done to it, such as certificate.created",
+                    "type": "string"
+                }
+            }
+        },
+        "automation.JournalEntry": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "automation.JournalEntryRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "title"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "maxLength": 200000
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 300
+                }
+            }
+        },
+        "automation.Key": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Prefix is the start of the key, to tell keys apart",
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "automation.KeyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "automation.Me": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "awards.Award": {
+            "type": "object",
+            "required": [
+                "issuer",
+                "title"
+            ],
+            "properties": {
+                "award_id": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "image_quarantined": {
+                    "description": "ImageQuarantined names the malware found in the last image uploaded, which was removed",
+                    "type": "string",
+                    "readOnly": true
+                },
+                "issuer": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "Visibility maps fields to who they are shown to: public, members or private",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/visibility.Rules"
+                        }
+                    ]
+                }
+            }
+        },
+        "batch.BatchRequest": {
+            "type": "object",
+            "required": [
+                "requests"
+            ],
+            "properties": {
+                "requests": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/batch.Request"
+                    }
+                }
+            }
+        },
+        "batch.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/batch.Result"
+                    }
+                }
+            }
+        },
+        "batch.Request": {
+            "type": "object",
+            "required": [
+                "method",
+                "path"
+            ],
+            "properties": {
+                "body": {
+                    "type": "object"
+                },
+                "headers": {
+                    "description": "Headers are sent along with the caller's, such as If-Match or Idempotency-Key. They cannot replace the\ncaller's credentials.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "method": {
+                    "type": "string",
+                    "enum": [
+                        "GET",
+                        "POST",
+                        "PUT",
+                        "PATCH",
+                        "DELETE"
+                    ]
+                },
+                "path": {
+                    "description": "Path is the path of the API route with any query, such as /api/v1/profile/{userid}",
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "batch.Result": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "description": "Body is the JSON response, or a string holding any other response",
+                    "type": "object"
+                },
+                "headers": {
+                    "description": "Headers holds the response headers clients act on, such as ETag and Location",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "billing.CheckoutRequest": {
+            "type": "object",
+            "required": [
+                "plan"
+            ],
+            "properties": {
+                "plan": {
+                    "type": "string"
+                }
+            }
+        },
+        "billing.Plan": {
+            "type": "object",
+            "properties": {
+                "ai": {
+                    "type": "boolean"
+                },
+                "customDomain": {
+                    "type": "boolean"
+                },
+                "maxJournalEntries": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "purchasable": {
+                    "description": "Purchasable plans can be subscribed to at checkout",
+                    "type": "boolean"
+                },
+                "storageBytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "billing.Redirect": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "billing.Subscription": {
+            "type": "object",
+            "properties": {
+                "cancelAtPeriodEnd": {
+                    "type": "boolean"
+                },
+                "currentPeriodEnd": {
+                    "type": "string"
+                },
+                "plan": {
+                    "$ref": "#/definitions/billing.Plan"
+                },
+                "status": {
+                    "description": "Status is the Stripe status of the subscription, empty for users who never subscribed",
+                    "type": "string"
+                }
+            }
+        },
+        "bulk.DeleteRequest": {
+            "type": "object",
+            "properties": {
+                "confirm": {
+                    "description": "Confirm must be set to delete by filter",
+                    "type": "boolean"
+                },
+                "dryRun": {
+                    "description": "DryRun returns what would be deleted without deleting anything",
+                    "type": "boolean"
+                },
+                "filter": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "bulk.DeleteResult": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "deleted": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "notFound": {
+                    "description": "NotFound lists the requested IDs that matched no document",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "certificates.Certificate": {
+            "type": "object",
+            "required": [
+                "institution",
+                "title"
+            ],
+            "properties": {
+                "archived_at": {
+                    "description": "ArchivedAt is when the certificate was archived as expired, some time after it ended",
+                    "type": "string",
+                    "readOnly": true
+                },
+                "cert_image_quarantined": {
+                    "description": "CertImageQuarantined names the malware found in the last certificate image uploaded, which was removed",
+                    "type": "string",
+                    "readOnly": true
+                },
+                "certificate_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "end": {
+                    "type": "string"
+                },
+                "institution": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "restricted": {
+                    "description": "Restricted is who an archived certificate is still shown to at all, members or private, when archiving\nhid it from the public",
+                    "type": "string",
+                    "readOnly": true
+                },
+                "start": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "Visibility maps fields to who they are shown to: public, members or private",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/visibility.Rules"
+                        }
+                    ]
+                }
+            }
+        },
+        "changelog.Event": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is create, update or delete",
+                    "type": "string"
+                },
+                "changes": {
+                    "description": "Changes maps each changed field to its values before and after the change, as in the audit log",
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "resource": {
+                    "type": "string"
+                },
+                "resourceID": {
+                    "type": "string"
+                },
+                "seq": {
+                    "description": "Seq is the number of the event among the user's events, starting at 1",
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "changelog.Page": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/changelog.Event"
+                    }
+                },
+                "more": {
+                    "description": "More is set when there are events after the page",
+                    "type": "boolean"
+                },
+                "next": {
+                    "description": "Next is the cursor to ask for the following events with, the number of the last event of the page or\nthe cursor asked with when there are no new events",
+                    "type": "integer"
+                }
+            }
+        },
+        "domains.DomainRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 253
+                }
+            }
+        },
+        "domains.DomainStatus": {
+            "type": "object",
+            "properties": {
+                "checkedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "description": "LastError tells why the last check did not verify the domain",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the host name, in lower case",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                },
+                "verification": {
+                    "$ref": "#/definitions/domains.Verification"
+                },
+                "verifiedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "domains.Site": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "domains.Verification": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "email.LogEntry": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "sentAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "template": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "events.Event": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "id": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "experience.Experience": {
+            "type": "object",
+            "required": [
+                "company",
+                "position",
+                "start"
+            ],
+            "properties": {
+                "company": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "end": {
+                    "type": "string"
+                },
+                "experience_id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "organization_id": {
+                    "description": "OrganizationID links the experience to the page of a verified organization",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "position": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "start": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "Visibility maps fields to who they are shown to: public, members or private",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/visibility.Rules"
+                        }
+                    ]
+                }
+            }
+        },
+        "experience.JSONResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "features.Flag": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percentage": {
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "Source is \"config\" for a flag as configured, or \"override\" for one changed at runtime",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "description": "UpdatedBy and UpdatedAt record the admin who last overrode the flag",
+                    "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "features.FlagRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "percentage": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "users": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "health.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "inbox.ContactForm": {
+            "type": "object",
+            "required": [
+                "email",
+                "message",
+                "name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 254
+                },
+                "message": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "subject": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "website": {
+                    "description": "Website is hidden from people by the form, so a value is left by bots filling in every field",
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "inbox.ContactRequest": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "description": "Email is the address replies are sent to",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "repliedAt": {
+                    "description": "RepliedAt is nil until the user replies to the request",
+                    "type": "string"
+                },
+                "spamScore": {
+                    "description": "SpamScore is how likely the request is to be spam, from 0 to 1",
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "inbox.ReplyRequest": {
+            "type": "object",
+            "required": [
+                "message"
+            ],
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "maxLength": 10000
+                }
+            }
+        },
+        "inbox.StatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "new",
+                        "read",
+                        "archived",
+                        "spam"
+                    ]
+                }
+            }
+        },
+        "integrations.Course": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "completedAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "expiresAt": {
+                    "description": "ExpiresAt is when the certification earned lapses, if it does",
+                    "type": "string"
+                },
+                "institution": {
+                    "description": "Institution defaults to that of the integration",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "integrations.CreatedIntegration": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "institution": {
+                    "description": "Institution is recorded on the certificates created from the integration's events naming no institution",
+                    "type": "string"
+                },
+                "matchEmail": {
+                    "description": "MatchEmail maps the integration's users without a mapping to the account with the same email address",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "integrations.Event": {
+            "type": "object",
+            "required": [
+                "id",
+                "type"
+            ],
+            "properties": {
+                "course": {
+                    "description": "Course is the course completed, for course.completed events",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/integrations.Course"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "ID identifies the event within the integration, so an event sent again is only processed once",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "type": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "user": {
+                    "$ref": "#/definitions/integrations.EventUser"
+                }
+            }
+        },
+        "integrations.EventResponse": {
+            "type": "object",
+            "properties": {
+                "certificateID": {
+                    "type": "string"
+                },
+                "duplicate": {
+                    "description": "Duplicate is set when the event had already been received, and was not processed again",
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "integrations.EventUser": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 254
+                },
+                "id": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "integrations.Integration": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "institution": {
+                    "description": "Institution is recorded on the certificates created from the integration's events naming no institution",
+                    "type": "string"
+                },
+                "matchEmail": {
+                    "description": "MatchEmail maps the integration's users without a mapping to the account with the same email address",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "integrations.IntegrationRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "institution": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "matchEmail": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "integrations.Mapping": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "externalID": {
+                    "type": "string"
+                },
+                "integrationID": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "integrations.MappingRequest": {
+            "type": "object",
+            "required": [
+                "userID"
+            ],
+            "properties": {
+                "userID": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "integrations.Receipt": {
+            "type": "object",
+            "properties": {
+                "certificateID": {
+                    "description": "CertificateID is the certificate the event created",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "eventID": {
+                    "type": "string"
+                },
+                "externalUserID": {
+                    "type": "string"
+                },
+                "integrationID": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "processedAt": {
+                    "type": "string"
+                },
+                "receivedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "userID": {
+                    "description": "UserID is the account the event was mapped to",
+                    "type": "string"
+                }
+            }
+        },
+        "jobs.Job": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lockedUntil": {
+                    "description": "LockedUntil is when a running job's lease expires and another worker may take it over",
+                    "type": "string"
+                },
+                "maxAttempts": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "runAt": {
+                    "description": "RunAt is the earliest time the next attempt may start",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "description": "Tenant is the tenant the job was queued for, whose storage the handler works with",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "journal.DeleteResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "journal.DeleteTermsRequest": {
+            "type": "object",
+            "required": [
+                "terms"
+            ],
+            "properties": {
+                "terms": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "journal.Entry": {
+            "type": "object",
+            "required": [
+                "content",
+                "title"
+            ],
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "content": {
+                    "type": "string",
+                    "maxLength": 200000
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 300
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "journal.ImportResult": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "images": {
+                    "type": "integer"
+                },
+                "journalID": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "journal.JournalEntry": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/journal.Entry"
+                    }
+                },
+                "journalID": {
+                    "type": "string"
+                },
+                "revision": {
+                    "description": "Revision counts the changes to the entry, unlike Version which picks one of its entries",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "statusChangedAt": {
+                    "type": "string"
+                },
+                "statusChangedBy": {
+                    "type": "string"
+                },
+                "statusHistory": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/journal.StatusChange"
+                    }
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "taxonomy": {
+                    "$ref": "#/definitions/journal.Taxonomy"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "journal.MergeTermsRequest": {
+            "type": "object",
+            "required": [
+                "from",
+                "into"
+            ],
+            "properties": {
+                "from": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "into": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "journal.ProcessingResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "journal.RelatedEntry": {
+            "type": "object",
+            "properties": {
+                "journalID": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "taxonomy": {
+                    "$ref": "#/definitions/journal.Taxonomy"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "journal.RenameTermRequest": {
+            "type": "object",
+            "required": [
+                "from",
+                "to"
+            ],
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "to": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "journal.Settings": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "description": "Comments is who may comment on the user's entries when comments are enabled: everyone, members or none",
+                    "type": "string"
+                },
+                "processOnPublish": {
+                    "description": "ProcessOnPublish sends entries that would be created public to processing instead, which makes them\npublic once it finishes",
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "Status is the status new entries start in: pending, private or public",
+                    "type": "string"
+                },
+                "taxonomy": {
+                    "description": "Taxonomy is given to new entries",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/journal.Taxonomy"
+                        }
+                    ]
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "journal.SettingsRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "comments": {
+                    "description": "Comments defaults to everyone",
+                    "type": "string",
+                    "enum": [
+                        "everyone",
+                        "members",
+                        "none"
+                    ]
+                },
+                "processOnPublish": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "private",
+                        "public"
+                    ]
+                },
+                "taxonomy": {
+                    "$ref": "#/definitions/journal.Taxonomy"
+                }
+            }
+        },
+        "journal.StatusChange": {
+            "type": "object",
+            "properties": {
+                "changedAt": {
+                    "type": "string"
+                },
+                "changedBy": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "journal.StatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "description": "Status is pending, processing, private, public or archived",
+                    "type": "string"
+                }
+            }
+        },
+        "journal.SuccessResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "journal.Taxonomy": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subcategories": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "topics": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "journal.TermCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "term": {
+                    "type": "string"
+                }
+            }
+        },
+        "journal.TermsResult": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "journal.VersionRequest": {
+            "type": "object",
+            "required": [
+                "version"
+            ],
+            "properties": {
+                "version": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "languages.Language": {
+            "type": "object",
+            "required": [
+                "name",
+                "proficiency"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Code is the BCP 47 tag of the language, such as en or pt-BR",
+                    "type": "string"
+                },
+                "language_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "proficiency": {
+                    "description": "Proficiency is the CEFR level the user speaks the language at, A1 to C2, or native",
+                    "type": "string",
+                    "enum": [
+                        "A1",
+                        "A2",
+                        "B1",
+                        "B2",
+                        "C1",
+                        "C2",
+                        "native"
+                    ]
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "Visibility maps fields to who they are shown to: public, members or private",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/visibility.Rules"
+                        }
+                    ]
+                }
+            }
+        },
+        "moderation.ActionRequest": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "description": "Action is hide, warn, suspend or dismiss",
+                    "type": "string",
+                    "enum": [
+                        "hide",
+                        "warn",
+                        "suspend",
+                        "dismiss"
+                    ]
+                },
+                "message": {
+                    "description": "Message is emailed to the owner with a warning, and kept as the note of a hide",
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "moderation.ActionResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "resolved": {
+                    "description": "Resolved counts the open reports the action closed",
+                    "type": "integer"
+                }
+            }
+        },
+        "moderation.Case": {
+            "type": "object",
+            "properties": {
+                "firstReportedAt": {
+                    "type": "string"
+                },
+                "hidden": {
+                    "type": "boolean"
+                },
+                "lastReportedAt": {
+                    "type": "string"
+                },
+                "ownerID": {
+                    "type": "string"
+                },
+                "reasons": {
+                    "description": "Reasons counts the open reports by reason",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "reports": {
+                    "description": "Reports counts the open reports, each from a different reporter",
+                    "type": "integer"
+                },
+                "targetID": {
+                    "type": "string"
+                },
+                "targetType": {
+                    "type": "string"
+                }
+            }
+        },
+        "moderation.Hidden": {
+            "type": "object",
+            "properties": {
+                "automatic": {
+                    "description": "Automatic is set when the content was hidden for reaching the report threshold, awaiting review",
+                    "type": "boolean"
+                },
+                "hiddenAt": {
+                    "type": "string"
+                },
+                "hiddenBy": {
+                    "description": "HiddenBy is the admin who hid the content, empty when it was hidden automatically",
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "ownerID": {
+                    "type": "string"
+                },
+                "targetID": {
+                    "type": "string"
+                },
+                "targetType": {
+                    "type": "string"
+                },
+                "unpublished": {
+                    "description": "Unpublished is set when hiding moved a public journal entry to private, so it is made public again\nwhen the hide is lifted",
+                    "type": "boolean"
+                }
+            }
+        },
+        "moderation.Report": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is what the admin resolving the report did",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ownerID": {
+                    "description": "OwnerID is the user the reported content belongs to",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason is spam, harassment, hate, sexual, violence, impersonation or other",
+                    "type": "string"
+                },
+                "reporterID": {
+                    "description": "ReporterID is the user who reported, empty for reports by visitors who were not signed in",
+                    "type": "string"
+                },
+                "resolvedAt": {
+                    "type": "string"
+                },
+                "resolvedBy": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "targetID": {
+                    "type": "string"
+                },
+                "targetType": {
+                    "type": "string"
+                }
+            }
+        },
+        "moderation.ReportRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "reason",
+                "type"
+            ],
+            "properties": {
+                "details": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "id": {
+                    "description": "ID is the user ID of a profile or the journal ID of a journal entry",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "spam",
+                        "harassment",
+                        "hate",
+                        "sexual",
+                        "violence",
+                        "impersonation",
+                        "other"
+                    ]
+                },
+                "type": {
+                    "description": "Type is profile or journal",
+                    "type": "string",
+                    "enum": [
+                        "profile",
+                        "journal"
+                    ]
+                }
+            }
+        },
+        "moderation.ReportResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "notifications.Channels": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "Email sends the notification to the address the user signs in with",
+                    "type": "boolean"
+                },
+                "inApp": {
+                    "description": "InApp keeps the notification in the notification center and sends it to the user's connected clients",
+                    "type": "boolean"
+                },
+                "webhook": {
+                    "description": "Webhook delivers the notification to the user's webhooks subscribed to notification.created",
+                    "type": "boolean"
+                }
+            }
+        },
+        "notifications.Notification": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "readAt": {
+                    "description": "ReadAt is nil until the user marks the notification read",
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "notifications.Preferences": {
+            "type": "object",
+            "properties": {
+                "kinds": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/notifications.Channels"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "notifications.PreferencesRequest": {
+            "type": "object",
+            "required": [
+                "kinds"
+            ],
+            "properties": {
+                "kinds": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/notifications.Channels"
+                    }
+                }
+            }
+        },
+        "organizations.Member": {
+            "type": "object",
+            "properties": {
+                "joinedAt": {
+                    "type": "string"
+                },
+                "organizationID": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "organizations.MemberRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "admin",
+                        "member"
+                    ]
+                }
+            }
+        },
+        "organizations.Organization": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "description": "CreatedBy is the user who created the organization",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
+                },
+                "website": {
+                    "type": "string"
+                }
+            }
+        },
+        "organizations.OrganizationRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "location": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "website": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "organizations.Page": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "description": "CreatedBy is the user who created the organization",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/organizations.Member"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
+                },
+                "website": {
+                    "type": "string"
+                }
+            }
+        },
+        "organizations.VerificationRequest": {
+            "type": "object",
+            "properties": {
+                "verified": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "privacy.Access": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "description": "UserID is the user whose profile the visitor may now see",
+                    "type": "string"
+                }
+            }
+        },
+        "privacy.Block": {
+            "type": "object",
+            "properties": {
+                "blockedID": {
+                    "description": "BlockedID is the user they blocked",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "description": "UserID is the user who blocked",
+                    "type": "string"
+                }
+            }
+        },
+        "privacy.Settings": {
+            "type": "object",
+            "properties": {
+                "restricted": {
+                    "description": "Restricted profiles are only shown to their owner, admins and the visitors on the allowlist",
+                    "type": "boolean"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "privacy.SettingsRequest": {
+            "type": "object",
+            "required": [
+                "restricted"
+            ],
+            "properties": {
+                "restricted": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "privacy.Visitor": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastVisitAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "privacy.VisitorRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 254
+                }
+            }
+        },
+        "profile.Aggregate": {
+            "type": "object",
+            "properties": {
+                "awards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/awards.Award"
+                    }
+                },
+                "certificates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/certificates.Certificate"
+                    }
+                },
+                "experience": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/experience.Experience"
+                    }
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/languages.Language"
+                    }
+                },
+                "profile": {
+                    "$ref": "#/definitions/profile.Profile"
+                },
+                "qualifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/qualifications.Qualification"
+                    }
+                },
+                "recommendations": {
+                    "description": "Recommendations are those the user approved, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/recommendations.Recommendation"
+                    }
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/skills.Skill"
+                    }
+                }
+            }
+        },
+        "profile.Availability": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "available_from": {
+                    "description": "AvailableFrom is the date the user can start, empty when they can start straight away",
+                    "type": "string"
+                },
+                "locations": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "remote": {
+                    "type": "string",
+                    "enum": [
+                        "onsite",
+                        "hybrid",
+                        "remote"
+                    ]
+                },
+                "roles": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "open",
+                        "passive",
+                        "closed"
+                    ]
+                }
+            }
+        },
+        "profile.CacheStats": {
+            "type": "object",
+            "properties": {
+                "backend": {
+                    "description": "Backend is memory, redis or disabled",
+                    "type": "string"
+                },
+                "entries": {
+                    "description": "Entries is the number of full profiles kept in memory, missing when they are kept in Redis",
+                    "type": "integer"
+                },
+                "hit_ratio": {
+                    "description": "HitRatio is the share of lookups served from the cache",
+                    "type": "number"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "invalidations": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "since": {
+                    "type": "string"
+                }
+            }
+        },
+        "profile.Profile": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "description": "Availability is whether the user is open to work, which is set through its own endpoint",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/profile.Availability"
+                        }
+                    ],
+                    "readOnly": true
+                },
+                "bio": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "domain": {
+                    "type": "string",
+                    "maxLength": 253
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 254
+                },
+                "image_quarantined": {
+                    "description": "ImageQuarantined names the malware found in the last image uploaded, which was removed from the profile",
+                    "type": "string",
+                    "readOnly": true
+                },
+                "interests": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "number": {
+                    "type": "string",
+                    "maxLength": 30
+                },
+                "profile_img": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "revision": {
+                    "description": "Revision counts the changes to the profile, which the repository sets on every write",
+                    "type": "integer",
+                    "readOnly": true
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "userid": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "Visibility maps fields to who they are shown to: public, members or private",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/visibility.Rules"
+                        }
+                    ]
+                }
+            }
+        },
+        "profile.Summary": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "headline": {
+                    "type": "string"
+                }
+            }
+        },
+        "profile.SummaryRequest": {
+            "type": "object",
+            "properties": {
+                "length": {
+                    "description": "Length is short, medium or long, defaulting to medium",
+                    "type": "string",
+                    "enum": [
+                        "short",
+                        "medium",
+                        "long"
+                    ]
+                },
+                "tone": {
+                    "description": "Tone is professional, friendly, formal or enthusiastic, defaulting to professional",
+                    "type": "string",
+                    "enum": [
+                        "professional",
+                        "friendly",
+                        "formal",
+                        "enthusiastic"
+                    ]
+                }
+            }
+        },
+        "qualifications.Qualification": {
+            "type": "object",
+            "required": [
+                "institution",
+                "title"
+            ],
+            "properties": {
+                "cert_image_quarantined": {
+                    "description": "CertImageQuarantined names the malware found in the last certificate image uploaded, which was removed",
+                    "type": "string",
+                    "readOnly": true
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "end": {
+                    "type": "string"
+                },
+                "institution": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "qualification_id": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "Visibility maps fields to who they are shown to: public, members or private",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/visibility.Rules"
+                        }
+                    ]
+                }
+            }
+        },
+        "quota.Allowance": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "used": {
+                    "type": "integer"
+                }
+            }
+        },
+        "quota.Usage": {
+            "type": "object",
+            "properties": {
+                "documents": {
+                    "description": "Documents holds the number of documents of each collection",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/quota.Allowance"
+                    }
+                },
+                "plan": {
+                    "description": "Plan is the user's billing plan, when billing is enabled",
+                    "type": "string"
+                },
+                "storage": {
+                    "$ref": "#/definitions/quota.Allowance"
+                }
+            }
+        },
+        "recommendations.Invitation": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "usedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "recommendations.InvitationRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 254
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "recommendations.Recommendation": {
+            "type": "object",
+            "properties": {
+                "authorEmail": {
+                    "description": "AuthorEmail is the address an external referee was invited at, only shown to the recommended user",
+                    "type": "string"
+                },
+                "authorID": {
+                    "description": "AuthorID is the user who wrote the recommendation, empty for external referees",
+                    "type": "string"
+                },
+                "authorName": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "relationship": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "description": "UserID is the user the recommendation is written for",
+                    "type": "string"
+                }
+            }
+        },
+        "recommendations.RecommendationRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "relationship"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "relationship": {
+                    "description": "Relationship describes how the author knows the user, such as \"Managed them at Acme\"",
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "recommendations.RefereeInvitation": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "userName": {
+                    "description": "UserName is the name of the user the recommendation is for",
+                    "type": "string"
+                }
+            }
+        },
+        "recommendations.StatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "approved",
+                        "hidden"
+                    ]
+                }
+            }
+        },
+        "resume.Proposal": {
+            "type": "object",
+            "properties": {
+                "experience": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/experience.Experience"
+                    }
+                },
+                "qualifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/qualifications.Qualification"
+                    }
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/skills.Skill"
+                    }
+                }
+            }
+        },
+        "scraping.ClientReport": {
+            "type": "object",
+            "properties": {
+                "client": {
+                    "type": "string"
+                },
+                "detections": {
+                    "type": "integer"
+                },
+                "lastDetectedAt": {
+                    "type": "string"
+                },
+                "lastUserAgent": {
+                    "type": "string"
+                },
+                "patterns": {
+                    "description": "Patterns are the patterns the address was detected by",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "throttledUntil": {
+                    "type": "string"
+                }
+            }
+        },
+        "scraping.Detection": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is what was done with the address's requests until ThrottledUntil",
+                    "type": "string"
+                },
+                "client": {
+                    "description": "Client identifies the address without revealing it, see clientKey",
+                    "type": "string"
+                },
+                "detectedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "requests": {
+                    "description": "Requests is how many requests made up the burst or the walk",
+                    "type": "integer"
+                },
+                "route": {
+                    "description": "Route is the route of the request that tripped the detection",
+                    "type": "string"
+                },
+                "throttledUntil": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string"
+                }
+            }
+        },
+        "scraping.Report": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is what is currently done with the requests of addresses detected scraping",
+                    "type": "string"
+                },
+                "clients": {
+                    "description": "Clients holds the addresses detected, most recently detected first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scraping.ClientReport"
+                    }
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "detections": {
+                    "description": "Detections holds the latest detections, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scraping.Detection"
+                    }
+                }
+            }
+        },
+        "search.FacetValue": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "search.Facets": {
+            "type": "object",
+            "properties": {
+                "institutions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/search.FacetValue"
+                    }
+                },
+                "kinds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/search.FacetValue"
+                    }
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/search.FacetValue"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/search.FacetValue"
+                    }
+                }
+            }
+        },
+        "search.Hit": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "description": "Availability is the status of a profile whose owner is open to work",
+                    "type": "string"
+                },
+                "institutions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "resourceID": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "snippet": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "search.Results": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "$ref": "#/definitions/search.Facets"
+                },
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/search.Hit"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "search.SemanticResults": {
+            "type": "object",
+            "properties": {
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/search.Hit"
+                    }
+                }
+            }
+        },
+        "search.Suggestion": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Count is how many documents have the skill, tag or institution",
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "userID": {
+                    "description": "UserID is the user a name belongs to",
+                    "type": "string"
+                }
+            }
+        },
+        "search.Suggestions": {
+            "type": "object",
+            "properties": {
+                "institutions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/search.Suggestion"
+                    }
+                },
+                "names": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/search.Suggestion"
+                    }
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/search.Suggestion"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/search.Suggestion"
+                    }
+                }
+            }
+        },
+        "shares.Bundle": {
+            "type": "object",
+            "properties": {
+                "certificates": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": {}
+                    }
+                },
+                "experience": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": {}
+                    }
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "pdfURL": {
+                    "description": "PDFURL is where the package may be downloaded as a PDF, when it is offered",
+                    "type": "string"
+                },
+                "profile": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "qualifications": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": {}
+                    }
+                },
+                "recipient": {
+                    "type": "string"
+                }
+            }
+        },
+        "shares.CreatedPackage": {
+            "type": "object",
+            "properties": {
+                "certificates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "experience": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastViewedAt": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Message is shown to the recipient with the package",
+                    "type": "string"
+                },
+                "pdf": {
+                    "description": "PDF offers the recipient the package as a PDF too",
+                    "type": "boolean"
+                },
+                "qualifications": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recipient": {
+                    "description": "Recipient names the recruiter the package was made for",
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "shares.Package": {
+            "type": "object",
+            "properties": {
+                "certificates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "experience": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastViewedAt": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Message is shown to the recipient with the package",
+                    "type": "string"
+                },
+                "pdf": {
+                    "description": "PDF offers the recipient the package as a PDF too",
+                    "type": "boolean"
+                },
+                "qualifications": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recipient": {
+                    "description": "Recipient names the recruiter the package was made for",
                     "type": "string"
                 },
-                "password": {
+                "revokedAt": {
+                    "type": "string"
+                },
+                "userID": {
                     "type": "string"
+                },
+                "views": {
+                    "type": "integer"
                 }
             }
         },
-        "auth.RegisterRequest": {
+        "shares.PackageRequest": {
             "type": "object",
+            "required": [
+                "recipient"
+            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "certificates": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "name": {
-                    "type": "string"
+                "experience": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "password": {
-                    "type": "string"
+                "expiresInDays": {
+                    "description": "ExpiresInDays is how long the link works, or the configured default when 0",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "message": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "pdf": {
+                    "type": "boolean"
+                },
+                "qualifications": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recipient": {
+                    "type": "string",
+                    "maxLength": 200
                 }
             }
         },
-        "certificates.Certificate": {
+        "shares.View": {
             "type": "object",
             "properties": {
-                "certificate_id": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "end": {
+                "id": {
                     "type": "string"
                 },
-                "institution": {
+                "kind": {
+                    "description": "Kind is what was viewed: the bundle or the PDF",
                     "type": "string"
                 },
-                "start": {
+                "packageID": {
                     "type": "string"
                 },
-                "title": {
+                "userAgent": {
                     "type": "string"
                 },
-                "user_id": {
+                "viewedAt": {
                     "type": "string"
                 }
             }
         },
-        "certificates.JSONResponse": {
+        "skills.JSONResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string"
-                },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "experience.Experience": {
+        "skills.Skill": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "company": {
-                    "type": "string"
-                },
                 "description": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 5000
                 },
-                "end": {
+                "last_used": {
                     "type": "string"
                 },
-                "experience_id": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
                 },
-                "notes": {
-                    "type": "string"
+                "proficiency_level": {
+                    "type": "string",
+                    "maxLength": 50
                 },
-                "position": {
+                "skill_id": {
                     "type": "string"
                 },
-                "start": {
+                "started_at": {
                     "type": "string"
                 },
                 "user_id": {
                     "type": "string"
+                },
+                "visibility": {
+                    "description": "Visibility maps fields to who they are shown to: public, members or private",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/visibility.Rules"
+                        }
+                    ]
                 }
             }
         },
-        "experience.JSONResponse": {
+        "skills.Suggestion": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string"
+                "experience_ids": {
+                    "description": "ExperienceIDs are the experience records the skill was found in",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "message": {
+                "reason": {
+                    "description": "Reason explains what in the user's experience shows the skill",
                     "type": "string"
+                },
+                "skill": {
+                    "$ref": "#/definitions/skills.Skill"
                 }
             }
         },
-        "journal.DeleteResponse": {
+        "subscriptions.JSONResponse": {
             "type": "object",
             "properties": {
-                "body": {
-                    "type": "string"
-                },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "journal.Entry": {
+        "subscriptions.SubscribeRequest": {
             "type": "object",
+            "required": [
+                "email"
+            ],
             "properties": {
-                "attachments": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "email": {
+                    "type": "string",
+                    "maxLength": 254
                 },
-                "content": {
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly"
+                    ]
+                }
+            }
+        },
+        "tenant.Branding": {
+            "type": "object",
+            "properties": {
+                "logoURL": {
                     "type": "string"
                 },
-                "title": {
+                "name": {
                     "type": "string"
                 },
-                "updatedAt": {
+                "primaryColor": {
                     "type": "string"
                 },
-                "version": {
-                    "type": "integer"
+                "tenant": {
+                    "type": "string"
                 }
             }
         },
-        "journal.ErrorResponse": {
+        "visibility.Rules": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "webhooks.CreateWebhookRequest": {
             "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
             "properties": {
-                "error": {
-                    "type": "string"
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "message": {
-                    "type": "string"
+                "global": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
                 }
             }
         },
-        "journal.JournalEntry": {
+        "webhooks.CreatedWebhook": {
             "type": "object",
             "properties": {
                 "createdAt": {
                     "type": "string"
                 },
-                "entries": {
+                "events": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/journal.Entry"
+                        "type": "string"
                     }
                 },
-                "journalID": {
-                    "type": "string"
+                "global": {
+                    "type": "boolean"
                 },
-                "status": {
+                "id": {
                     "type": "string"
                 },
-                "summary": {
+                "secret": {
                     "type": "string"
                 },
-                "taxonomy": {
-                    "$ref": "#/definitions/journal.Taxonomy"
-                },
-                "updatedAt": {
+                "url": {
                     "type": "string"
                 },
                 "userID": {
                     "type": "string"
-                },
-                "version": {
-                    "type": "integer"
                 }
             }
         },
-        "journal.ProcessingResponse": {
+        "webhooks.Delivery": {
             "type": "object",
             "properties": {
-                "body": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
                     "type": "string"
                 },
-                "message": {
+                "event": {
                     "type": "string"
-                }
-            }
-        },
-        "journal.SuccessResponse": {
-            "type": "object",
-            "properties": {
-                "createdAt": {
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastError": {
                     "type": "string"
                 },
+                "payload": {
+                    "type": "object"
+                },
+                "responseStatus": {
+                    "type": "integer"
+                },
                 "status": {
                     "type": "string"
                 },
@@ -2393,151 +16771,146 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "userID": {
+                    "description": "UserID is the user the event is about",
                     "type": "string"
                 },
-                "version": {
-                    "type": "integer"
+                "webhookID": {
+                    "type": "string"
                 }
             }
         },
-        "journal.Taxonomy": {
+        "webhooks.Webhook": {
             "type": "object",
             "properties": {
-                "categories": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "createdAt": {
+                    "type": "string"
                 },
-                "subcategories": {
+                "events": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "global": {
+                    "type": "boolean"
                 },
-                "topics": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
                 }
             }
         },
-        "profile.ErrorResponse": {
+        "widgets.JournalWidget": {
             "type": "object",
             "properties": {
-                "error": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/widgets.WidgetEntry"
+                    }
+                },
+                "name": {
+                    "description": "Name is the user's name when their profile shows it to everyone",
                     "type": "string"
                 },
-                "message": {
+                "userID": {
                     "type": "string"
                 }
             }
         },
-        "profile.Profile": {
+        "widgets.OEmbed": {
             "type": "object",
             "properties": {
-                "bio": {
+                "author_name": {
                     "type": "string"
                 },
-                "domain": {
-                    "type": "string"
+                "cache_age": {
+                    "description": "CacheAge is how long, in seconds, the response may be cached",
+                    "type": "integer"
                 },
-                "email": {
+                "height": {
+                    "type": "integer"
+                },
+                "html": {
                     "type": "string"
                 },
-                "interests": {
+                "provider_name": {
                     "type": "string"
                 },
-                "name": {
+                "provider_url": {
                     "type": "string"
                 },
-                "number": {
+                "title": {
                     "type": "string"
                 },
-                "profile_img": {
+                "type": {
                     "type": "string"
                 },
-                "userID": {
+                "version": {
                     "type": "string"
+                },
+                "width": {
+                    "type": "integer"
                 }
             }
         },
-        "qualifications.ErrorResponse": {
+        "widgets.SkillsWidget": {
             "type": "object",
             "properties": {
-                "error": {
-                    "description": "Error message\nexample: Invalid request body",
+                "name": {
+                    "description": "Name is the user's name when their profile shows it to everyone",
+                    "type": "string"
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/widgets.WidgetSkill"
+                    }
+                },
+                "userID": {
                     "type": "string"
                 }
             }
         },
-        "qualifications.Qualification": {
+        "widgets.WidgetEntry": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "end": {
+                "id": {
                     "type": "string"
                 },
-                "institution": {
-                    "type": "string"
-                },
-                "qualification_id": {
+                "summary": {
                     "type": "string"
                 },
-                "start": {
-                    "type": "string"
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "title": {
                     "type": "string"
                 },
-                "user_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "skills.JSONResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
+                "updatedAt": {
                     "type": "string"
                 },
-                "message": {
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "skills.Skill": {
+        "widgets.WidgetSkill": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "last_used": {
+                "level": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
-                },
-                "proficiency_level": {
-                    "type": "string"
-                },
-                "skill_id": {
-                    "type": "string"
-                },
-                "started_at": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
                 }
             }
         }
@@ -0,0 +1,50 @@
+package domains
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// MongoCertCache keeps the ACME account key and the certificates obtained for each host in the
+// tls_certificates collection, so every replica serves the certificates any of them obtained
+type MongoCertCache struct {
+	certs *mongo.Collection
+}
+
+// certEntry is a cached key or certificate
+type certEntry struct {
+	Key       string    `bson:"_id"`
+	Data      []byte    `bson:"data"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// NewMongoCertCache creates a certificate cache backed by the given database
+func NewMongoCertCache(db *mongo.Database) *MongoCertCache {
+	return &MongoCertCache{certs: db.Collection("tls_certificates")}
+}
+
+func (c *MongoCertCache) Get(ctx context.Context, key string) ([]byte, error) {
+	var entry certEntry
+	err := c.certs.FindOne(ctx, bson.M{"_id": key}).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, autocert.ErrCacheMiss
+	}
+	return entry.Data, err
+}
+
+func (c *MongoCertCache) Put(ctx context.Context, key string, data []byte) error {
+	_, err := c.certs.ReplaceOne(ctx, bson.M{"_id": key}, certEntry{Key: key, Data: data, UpdatedAt: time.Now()},
+		options.Replace().SetUpsert(true))
+	return err
+}
+
+func (c *MongoCertCache) Delete(ctx context.Context, key string) error {
+	_, err := c.certs.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
@@ -0,0 +1,486 @@
+// Package domains lets users serve their profile on a domain of their own, beyond the subdomains of the
+// site. A user attaches a domain and proves they control it by publishing a TXT record holding the token
+// they are given, which is checked when they ask and daily until the domain is verified or the pending TTL
+// passes. Verified domains are then routed to their owner: requests whose Host header names one are
+// assigned their owner as the identifier, in the owner's tenant, and the site endpoint tells frontends whose
+// profile to show. When automatic TLS covers custom domains, a certificate is obtained for each verified
+// domain on its first HTTPS request, kept in a directory or the Mongo database, and no longer served once the
+// domain is detached.
+//
+// Each replica remembers which profile a host serves for the cache TTL, so a domain removed or verified on
+// another replica may take that long to stop or start being routed there.
+package domains
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/apiversion"
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// recordPrefix is prepended to a domain to name its verification record
+	recordPrefix = "_profile-verification."
+	// valuePrefix starts the value of a verification record, followed by the domain's token
+	valuePrefix = "profile-verification="
+
+	// maxCached is the most hosts remembered, beyond which the cache starts over
+	maxCached = 10000
+)
+
+// label is one part of a domain name, in lower case ASCII
+var label = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// lookupTXT finds the TXT records of a name
+var lookupTXT = net.DefaultResolver.LookupTXT
+
+var repo Repository
+var settings config.DomainsConfig
+
+// siteHost is the host name of the public base URL
+var siteHost = "localhost"
+
+// Configure sets where domains are stored and how many a user may attach, and starts removing the domains
+// of users who delete their account
+func Configure(r Repository, cfg config.DomainsConfig) {
+	repo = r
+	settings = cfg
+	audit.Subscribe(removeDomains)
+}
+
+// SetBaseURL sets the public base URL, whose host and its subdomains users may not attach
+func SetBaseURL(baseURL string) {
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+		siteHost = strings.ToLower(u.Hostname())
+	}
+}
+
+// removeDomains removes the domains of a deleted user
+func removeDomains(ctx context.Context, entry audit.Entry) {
+	if entry.Resource != "user" || entry.Action != audit.ActionDelete {
+		return
+	}
+	domains, err := repo.List(ctx, entry.ResourceID)
+	if err == nil {
+		err = repo.DeleteUser(ctx, entry.ResourceID)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Could not remove the domains of a deleted user", "user_id", entry.ResourceID, "error", err)
+		return
+	}
+	for _, d := range domains {
+		forget(d.Name)
+	}
+}
+
+// hostname returns the host name of a Host header, without its port, in lower case
+func hostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// normalize returns the domain name a user entered in lower case, or an error telling what is wrong with it
+func normalize(name string) (string, error) {
+	name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 || net.ParseIP(name) != nil || slices.ContainsFunc(labels, func(l string) bool { return !label.MatchString(l) }) {
+		return "", errors.New("name must be a domain name such as cv.example.com, with international names in punycode")
+	}
+	if isSiteHost(name) {
+		return "", errors.New("name must not be a domain of this site")
+	}
+	return name, nil
+}
+
+// isSiteHost reports whether the host name is the site's own or a tenant's, or a subdomain of one
+func isSiteHost(name string) bool {
+	hosts := []string{siteHost}
+	for _, t := range tenant.All() {
+		hosts = append(hosts, t.Domains...)
+		if u, err := url.Parse(t.PublicBaseURL); err == nil && u.Hostname() != "" {
+			hosts = append(hosts, u.Hostname())
+		}
+	}
+	return slices.ContainsFunc(hosts, func(host string) bool {
+		host = strings.ToLower(host)
+		return name == host || strings.HasSuffix(name, "."+host)
+	})
+}
+
+// newStatus returns the domain with the record verifying it
+func newStatus(d Domain) DomainStatus {
+	return DomainStatus{Domain: d, Verification: Verification{Type: "TXT", Name: recordPrefix + d.Name, Value: valuePrefix + d.Token}}
+}
+
+// check looks up the domain's verification record, verifying the domain when it holds the token, and saves
+// the outcome
+func check(ctx context.Context, d Domain) (Domain, error) {
+	now := time.Now()
+	d.CheckedAt = &now
+	d.LastError = ""
+
+	records, err := lookupTXT(ctx, recordPrefix+d.Name)
+	var dnsErr *net.DNSError
+	switch {
+	case err != nil && (!errors.As(err, &dnsErr) || !dnsErr.IsNotFound):
+		d.LastError = fmt.Sprintf("Could not look up the TXT records of %s: %v", recordPrefix+d.Name, err)
+	case !slices.Contains(records, valuePrefix+d.Token):
+		d.LastError = fmt.Sprintf("No TXT record of %s holds %s", recordPrefix+d.Name, valuePrefix+d.Token)
+	default:
+		d.Status = StatusVerified
+		d.VerifiedAt = &now
+	}
+
+	err = repo.Update(ctx, d)
+	if errors.Is(err, store.ErrConflict) {
+		d.Status, d.VerifiedAt = StatusPending, nil
+		d.LastError = "Another user has already verified this domain"
+		err = repo.Update(ctx, d)
+	}
+	if err != nil {
+		return Domain{}, err
+	}
+	if d.Status == StatusVerified {
+		forget(d.Name)
+	}
+	return d, nil
+}
+
+// Check removes the domains left unverified for longer than the pending TTL and checks the records of the
+// others, for the scheduler
+func Check(ctx context.Context) error {
+	removed, err := repo.PurgePending(ctx, time.Now().Add(-settings.PendingTTL.Std()))
+	if err != nil {
+		return err
+	}
+	pending, err := repo.ListPending(ctx)
+	if err != nil {
+		return err
+	}
+	verified := 0
+	for _, d := range pending {
+		d, err := check(ctx, d)
+		if err != nil {
+			return err
+		}
+		if d.Status == StatusVerified {
+			verified++
+		}
+	}
+	slog.Info("Checked pending domains", "removed", removed, "checked", len(pending), "verified", verified)
+	return nil
+}
+
+// resolved is what a replica remembers about a host
+type resolved struct {
+	site    Site
+	ok      bool
+	expires time.Time
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = map[string]resolved{}
+)
+
+// forget drops what this replica remembers about a host
+func forget(name string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	delete(cache, name)
+}
+
+// Resolve returns the site served on a host when it is a verified domain, looking it up in every tenant's
+// domains when the deployment serves several. Hosts of the site itself are never custom domains.
+func Resolve(ctx context.Context, host string) (Site, bool) {
+	name := hostname(host)
+	if repo == nil || isSiteHost(name) || !strings.Contains(name, ".") {
+		return Site{}, false
+	}
+
+	cacheMu.Lock()
+	r, hit := cache[name]
+	cacheMu.Unlock()
+	if hit && time.Now().Before(r.expires) {
+		return r.site, r.ok
+	}
+
+	ctx, cancel := utils.WithOperationTimeout(ctx)
+	defer cancel()
+	ids := []string{tenant.Default}
+	if tenant.Enabled() {
+		ids = nil
+		for _, t := range tenant.All() {
+			ids = append(ids, t.ID)
+		}
+	}
+	r = resolved{expires: time.Now().Add(settings.CacheTTL.Std())}
+	for _, id := range ids {
+		d, err := repo.GetVerified(tenant.WithID(ctx, id), name)
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			// Not remembered, so the host is looked up again once the storage recovers
+			slog.ErrorContext(ctx, "Could not resolve custom domain", "host", name, "error", err)
+			return Site{}, false
+		}
+		r.site, r.ok = Site{Domain: d.Name, UserID: d.UserID, Tenant: id}, true
+		break
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if len(cache) >= maxCached {
+		cache = map[string]resolved{}
+	}
+	cache[name] = r
+	return r.site, r.ok
+}
+
+// CreateDomain attaches a domain to the current user's profile
+//
+//	@Summary		Attach a domain
+//	@Description	Attaches a domain to the current user's profile, pending verification. To verify it, publish the returned TXT record and call the verify endpoint; pending domains are also checked daily, and removed if still unverified after the configured pending TTL. The domain must also point at this site, with a CNAME or A record, to serve the profile, and is then served over HTTPS when the site obtains certificates for custom domains.
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		DomainRequest	true	"Domain name"
+//	@Success		201		{object}	DomainStatus
+//	@Header			201		{string}	Location	"URL of the created domain"
+//	@Failure		400		{object}	apierror.Response	"Invalid domain name"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Custom domains are turned off"
+//	@Failure		409		{object}	apierror.Response	"Domain already attached"
+//	@Failure		413		{object}	apierror.Response	"Too many domains"
+//	@Failure		500		{object}	apierror.Response	"Could not attach domain"
+//	@Router			/domains [post]
+func CreateDomain(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req DomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	name, err := normalize(req.Name)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest(err.Error()))
+		return
+	}
+	if settings.MaxPerUser == 0 {
+		apierror.Abort(c, apierror.Forbidden("Custom domains are turned off"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	domains, err := repo.List(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not attach domain"))
+		return
+	}
+	if len(domains) >= settings.MaxPerUser {
+		apierror.Abort(c, apierror.QuotaExceeded(fmt.Sprintf("You may attach at most %d domains", settings.MaxPerUser)))
+		return
+	}
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not attach domain"))
+		return
+	}
+	d := Domain{
+		ID:        utils.GenerateID(),
+		UserID:    user.ID,
+		Name:      name,
+		Status:    StatusPending,
+		Token:     hex.EncodeToString(token),
+		CreatedAt: time.Now(),
+	}
+	if err := repo.Create(ctx, d); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			apierror.Abort(c, apierror.Conflict("You have already attached this domain"))
+			return
+		}
+		apierror.Abort(c, apierror.Wrap(err, "Could not attach domain"))
+		return
+	}
+
+	apiversion.Location(c, d.ID)
+	c.JSON(http.StatusCreated, newStatus(d))
+}
+
+// ListDomains lists the current user's domains
+//
+//	@Summary		List domains
+//	@Description	Lists the domains attached to the current user's profile, oldest first, with their status and the TXT record verifying them
+//	@Tags			domains
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		DomainStatus
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve domains"
+//	@Router			/domains [get]
+func ListDomains(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	domains, err := repo.List(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve domains"))
+		return
+	}
+
+	statuses := []DomainStatus{}
+	for _, d := range domains {
+		statuses = append(statuses, newStatus(d))
+	}
+	c.JSON(http.StatusOK, statuses)
+}
+
+// GetDomain returns one of the current user's domains
+//
+//	@Summary		Get a domain
+//	@Description	Returns a domain attached to the current user's profile, with its status, when it was last checked and why it was not verified, and the TXT record verifying it
+//	@Tags			domains
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			domainid	path		string	true	"Domain ID"
+//	@Success		200			{object}	DomainStatus
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		404			{object}	apierror.Response	"Domain not found"
+//	@Router			/domains/{domainid} [get]
+func GetDomain(c *gin.Context) {
+	d, ok := ownDomain(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, newStatus(d))
+}
+
+// VerifyDomain checks the verification record of one of the current user's domains
+//
+//	@Summary		Verify a domain
+//	@Description	Looks up the domain's TXT record and verifies the domain when it holds the domain's token, after which the domain serves the profile. Otherwise the domain stays pending and lastError tells what was found. DNS changes may take a while to be seen. Checking a verified domain again has no effect.
+//	@Tags			domains
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			domainid	path		string	true	"Domain ID"
+//	@Success		200			{object}	DomainStatus
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		404			{object}	apierror.Response	"Domain not found"
+//	@Failure		500			{object}	apierror.Response	"Could not verify domain"
+//	@Router			/domains/{domainid}/verify [post]
+func VerifyDomain(c *gin.Context) {
+	d, ok := ownDomain(c)
+	if !ok {
+		return
+	}
+	if d.Status != StatusVerified {
+		ctx, cancel := utils.DBContext(c)
+		defer cancel()
+		var err error
+		if d, err = check(ctx, d); err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not verify domain"))
+			return
+		}
+	}
+	c.JSON(http.StatusOK, newStatus(d))
+}
+
+// DeleteDomain detaches one of the current user's domains
+//
+//	@Summary		Delete a domain
+//	@Description	Detaches a domain from the current user's profile. Replicas may keep serving the profile on it for the configured cache TTL.
+//	@Tags			domains
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			domainid	path		string	true	"Domain ID"
+//	@Success		200			{object}	map[string]string
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		404			{object}	apierror.Response	"Domain not found"
+//	@Router			/domains/{domainid} [delete]
+func DeleteDomain(c *gin.Context) {
+	d, ok := ownDomain(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Delete(ctx, d.ID); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Domain not found"))
+		return
+	}
+	forget(d.Name)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Domain deleted"})
+}
+
+// GetSite tells whose profile the requested host serves
+//
+//	@Summary		Get the site of the host
+//	@Description	Returns the user whose profile is served on the Host of the request, when it is a verified custom domain, for frontends deciding what to show
+//	@Tags			domains
+//	@Produce		json
+//	@Success		200	{object}	Site
+//	@Failure		404	{object}	apierror.Response	"The host is not a custom domain"
+//	@Router			/domains/site [get]
+func GetSite(c *gin.Context) {
+	site, ok := Resolve(c.Request.Context(), c.Request.Host)
+	if !ok {
+		apierror.Abort(c, apierror.NotFound("The host is not a custom domain"))
+		return
+	}
+	c.JSON(http.StatusOK, site)
+}
+
+// ownDomain loads the domain in the path, aborting with 404 unless it belongs to the current user
+func ownDomain(c *gin.Context) (Domain, bool) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	d, err := repo.Get(ctx, c.Param("domainid"))
+	if err != nil || d.UserID != user.ID {
+		apierror.Abort(c, apierror.NotFound("Domain not found"))
+		return Domain{}, false
+	}
+	return d, true
+}
+
+// InitializeRoutes registers the domain routes. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup, users auth.Repository) {
+	router.GET("/site", GetSite)
+
+	owned := router.Group("", auth.AuthMiddleware(users, true))
+	owned.POST("", CreateDomain)
+	owned.GET("", ListDomains)
+	owned.GET("/:domainid", GetDomain)
+	owned.POST("/:domainid/verify", VerifyDomain)
+	owned.DELETE("/:domainid", DeleteDomain)
+}
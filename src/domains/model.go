@@ -0,0 +1,54 @@
+package domains
+
+import "time"
+
+// Domain statuses
+const (
+	// StatusPending domains wait for their verification record to be found
+	StatusPending = "pending"
+	// StatusVerified domains serve their owner's profile
+	StatusVerified = "verified"
+)
+
+// Domain is a domain a user attaches to their profile. It serves the profile once the user proved they
+// control it by publishing the verification record.
+type Domain struct {
+	ID     string `bson:"_id" json:"id"`
+	UserID string `bson:"user_id" json:"userID"`
+	// Name is the host name, in lower case
+	Name   string `bson:"name" json:"name"`
+	Status string `bson:"status" json:"status"`
+	// Token is the value the verification record must hold
+	Token string `bson:"token" json:"-"`
+	// LastError tells why the last check did not verify the domain
+	LastError  string     `bson:"last_error,omitempty" json:"lastError,omitempty"`
+	CheckedAt  *time.Time `bson:"checked_at,omitempty" json:"checkedAt,omitempty"`
+	VerifiedAt *time.Time `bson:"verified_at,omitempty" json:"verifiedAt,omitempty"`
+	CreatedAt  time.Time  `bson:"created_at" json:"createdAt"`
+}
+
+// Verification is the DNS record proving control of a domain
+type Verification struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// DomainStatus is a domain with the record to publish to verify it
+type DomainStatus struct {
+	Domain
+	Verification Verification `json:"verification"`
+}
+
+// DomainRequest represents the request body for attaching a domain
+type DomainRequest struct {
+	Name string `json:"name" binding:"required,max=253"`
+}
+
+// Site tells which user's profile a host serves
+type Site struct {
+	Domain string `json:"domain"`
+	UserID string `json:"userID"`
+	// Tenant is the tenant the user belongs to
+	Tenant string `json:"-"`
+}
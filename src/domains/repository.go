@@ -0,0 +1,30 @@
+package domains
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores the domains users attach to their profile
+type Repository interface {
+	// Create stores a new domain, or returns store.ErrConflict when the user already attached its name
+	Create(ctx context.Context, domain Domain) error
+	// Get returns the domain with the given ID, or store.ErrNotFound
+	Get(ctx context.Context, domainID string) (Domain, error)
+	// GetVerified returns the verified domain with the name, or store.ErrNotFound
+	GetVerified(ctx context.Context, name string) (Domain, error)
+	// List returns the user's domains, oldest first
+	List(ctx context.Context, userID string) ([]Domain, error)
+	// ListPending returns the domains not verified yet, oldest first
+	ListPending(ctx context.Context) ([]Domain, error)
+	// Update saves the status of a domain, returning store.ErrConflict when another user verified the name
+	// first, or store.ErrNotFound
+	Update(ctx context.Context, domain Domain) error
+	// Delete removes the domain, or returns store.ErrNotFound
+	Delete(ctx context.Context, domainID string) error
+	// DeleteUser removes the user's domains
+	DeleteUser(ctx context.Context, userID string) error
+	// PurgePending removes the domains created before the given time and not verified yet, returning how
+	// many were removed
+	PurgePending(ctx context.Context, before time.Time) (int64, error)
+}
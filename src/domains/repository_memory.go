@@ -0,0 +1,116 @@
+package domains
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps domains in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu      sync.Mutex
+	domains []Domain
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, domain Domain) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.ContainsFunc(r.domains, func(d Domain) bool { return d.UserID == domain.UserID && d.Name == domain.Name }) {
+		return store.ErrConflict
+	}
+	r.domains = append(r.domains, domain)
+	return nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, domainID string) (Domain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.domains, func(d Domain) bool { return d.ID == domainID })
+	if i < 0 {
+		return Domain{}, store.ErrNotFound
+	}
+	return r.domains[i], nil
+}
+
+func (r *MemoryRepository) GetVerified(ctx context.Context, name string) (Domain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.domains, func(d Domain) bool { return d.Name == name && d.Status == StatusVerified })
+	if i < 0 {
+		return Domain{}, store.ErrNotFound
+	}
+	return r.domains[i], nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string) ([]Domain, error) {
+	return r.filter(func(d Domain) bool { return d.UserID == userID }), nil
+}
+
+func (r *MemoryRepository) ListPending(ctx context.Context) ([]Domain, error) {
+	return r.filter(func(d Domain) bool { return d.Status == StatusPending }), nil
+}
+
+// filter returns the domains matching, oldest first
+func (r *MemoryRepository) filter(match func(Domain) bool) []Domain {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var domains []Domain
+	for _, d := range r.domains {
+		if match(d) {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+func (r *MemoryRepository) Update(ctx context.Context, domain Domain) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.domains, func(d Domain) bool { return d.ID == domain.ID })
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	if domain.Status == StatusVerified && slices.ContainsFunc(r.domains, func(d Domain) bool {
+		return d.ID != domain.ID && d.Name == domain.Name && d.Status == StatusVerified
+	}) {
+		return store.ErrConflict
+	}
+	r.domains[i] = domain
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, domainID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(r.domains)
+	r.domains = slices.DeleteFunc(r.domains, func(d Domain) bool { return d.ID == domainID })
+	if len(r.domains) == n {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MemoryRepository) DeleteUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.domains = slices.DeleteFunc(r.domains, func(d Domain) bool { return d.UserID == userID })
+	return nil
+}
+
+func (r *MemoryRepository) PurgePending(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(r.domains)
+	r.domains = slices.DeleteFunc(r.domains, func(d Domain) bool {
+		return d.Status == StatusPending && d.CreatedAt.Before(before)
+	})
+	return int64(n - len(r.domains)), nil
+}
@@ -0,0 +1,105 @@
+package domains
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores domains in the domains collection, whose partial unique index on the names of
+// verified domains keeps two users from verifying the same one
+type MongoRepository struct {
+	domains *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{domains: db.Collection("domains")}
+}
+
+func (r *MongoRepository) Create(ctx context.Context, domain Domain) error {
+	_, err := r.domains.InsertOne(ctx, domain)
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	}
+	return err
+}
+
+func (r *MongoRepository) Get(ctx context.Context, domainID string) (Domain, error) {
+	return r.findOne(ctx, bson.M{"_id": domainID})
+}
+
+func (r *MongoRepository) GetVerified(ctx context.Context, name string) (Domain, error) {
+	return r.findOne(ctx, bson.M{"name": name, "status": StatusVerified})
+}
+
+func (r *MongoRepository) findOne(ctx context.Context, filter bson.M) (Domain, error) {
+	var domain Domain
+	err := r.domains.FindOne(ctx, filter).Decode(&domain)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Domain{}, store.ErrNotFound
+	}
+	return domain, err
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string) ([]Domain, error) {
+	return r.find(ctx, bson.M{"user_id": userID})
+}
+
+func (r *MongoRepository) ListPending(ctx context.Context) ([]Domain, error) {
+	return r.find(ctx, bson.M{"status": StatusPending})
+}
+
+func (r *MongoRepository) find(ctx context.Context, filter bson.M) ([]Domain, error) {
+	cursor, err := r.domains.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var domains []Domain
+	err = cursor.All(ctx, &domains)
+	return domains, err
+}
+
+func (r *MongoRepository) Update(ctx context.Context, domain Domain) error {
+	result, err := r.domains.ReplaceOne(ctx, bson.M{"_id": domain.ID}, domain)
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, domainID string) error {
+	result, err := r.domains.DeleteOne(ctx, bson.M{"_id": domainID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) DeleteUser(ctx context.Context, userID string) error {
+	_, err := r.domains.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
+
+func (r *MongoRepository) PurgePending(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.domains.DeleteMany(ctx, bson.M{"status": StatusPending, "created_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
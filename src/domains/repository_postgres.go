@@ -0,0 +1,106 @@
+package domains
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const domainColumns = "id, user_id, name, status, token, last_error, checked_at, verified_at, created_at"
+
+// PostgresRepository stores domains in the domains table, whose partial unique index on the names of
+// verified domains keeps two users from verifying the same one
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, d Domain) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO domains ("+domainColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		d.ID, d.UserID, d.Name, d.Status, d.Token, d.LastError, d.CheckedAt, d.VerifiedAt, d.CreatedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, domainID string) (Domain, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+domainColumns+" FROM domains WHERE id = $1", domainID)
+	if err != nil {
+		return Domain{}, err
+	}
+	domain, err := pgx.CollectExactlyOneRow(rows, scanDomain)
+	return domain, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) GetVerified(ctx context.Context, name string) (Domain, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+domainColumns+" FROM domains WHERE name = $1 AND status = $2", name, StatusVerified)
+	if err != nil {
+		return Domain{}, err
+	}
+	domain, err := pgx.CollectExactlyOneRow(rows, scanDomain)
+	return domain, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string) ([]Domain, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+domainColumns+" FROM domains WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanDomain)
+}
+
+func (r *PostgresRepository) ListPending(ctx context.Context) ([]Domain, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+domainColumns+" FROM domains WHERE status = $1 ORDER BY created_at", StatusPending)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanDomain)
+}
+
+func (r *PostgresRepository) Update(ctx context.Context, d Domain) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE domains SET status = $2, last_error = $3, checked_at = $4, verified_at = $5
+		WHERE id = $1`, d.ID, d.Status, d.LastError, d.CheckedAt, d.VerifiedAt)
+	if err != nil {
+		return store.PostgresErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, domainID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM domains WHERE id = $1", domainID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) DeleteUser(ctx context.Context, userID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM domains WHERE user_id = $1", userID)
+	return err
+}
+
+func (r *PostgresRepository) PurgePending(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM domains WHERE status = $1 AND created_at < $2", StatusPending, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanDomain(row pgx.CollectableRow) (Domain, error) {
+	var d Domain
+	err := row.Scan(&d.ID, &d.UserID, &d.Name, &d.Status, &d.Token, &d.LastError, &d.CheckedAt, &d.VerifiedAt, &d.CreatedAt)
+	return d, err
+}
@@ -0,0 +1,54 @@
+package domains
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, domain Domain) error {
+	return r.repos.For(ctx).Create(ctx, domain)
+}
+
+func (r *TenantRepository) Get(ctx context.Context, domainID string) (Domain, error) {
+	return r.repos.For(ctx).Get(ctx, domainID)
+}
+
+func (r *TenantRepository) GetVerified(ctx context.Context, name string) (Domain, error) {
+	return r.repos.For(ctx).GetVerified(ctx, name)
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID string) ([]Domain, error) {
+	return r.repos.For(ctx).List(ctx, userID)
+}
+
+func (r *TenantRepository) ListPending(ctx context.Context) ([]Domain, error) {
+	return r.repos.For(ctx).ListPending(ctx)
+}
+
+func (r *TenantRepository) Update(ctx context.Context, domain Domain) error {
+	return r.repos.For(ctx).Update(ctx, domain)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, domainID string) error {
+	return r.repos.For(ctx).Delete(ctx, domainID)
+}
+
+func (r *TenantRepository) DeleteUser(ctx context.Context, userID string) error {
+	return r.repos.For(ctx).DeleteUser(ctx, userID)
+}
+
+func (r *TenantRepository) PurgePending(ctx context.Context, before time.Time) (int64, error) {
+	return r.repos.For(ctx).PurgePending(ctx, before)
+}
@@ -0,0 +1,90 @@
+// Package dryrun lets clients check a write without making it. A POST, PUT or DELETE sent with the
+// X-Dry-Run: true header runs the route's ownership checks and the handler's validation and responds with the
+// result it would have had, while the repositories of the routes that support dry runs leave storage unchanged
+// and no events are published. Routes opt in with Supported, so a dry run never reaches a handler whose side
+// effects are not covered, such as uploads or AI processing; those reject it instead.
+package dryrun
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
+
+	"profile-api/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Header is the request header asking for a dry run, echoed in the response of one
+const Header = "X-Dry-Run"
+
+// contextKey marks the request's context as a dry run
+type contextKey struct{}
+
+// With returns a context marked as a dry run
+func With(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, true)
+}
+
+// Active reports whether the context belongs to a dry run, so writes must not be made
+func Active(ctx context.Context) bool {
+	active, _ := ctx.Value(contextKey{}).(bool)
+	return active
+}
+
+// allow marks the routes supporting dry runs. It does nothing when called.
+func allow(c *gin.Context) {
+	c.Next()
+}
+
+// allowName is how allow appears among a route's handler names
+var allowName = runtime.FuncForPC(reflect.ValueOf(allow).Pointer()).Name()
+
+// Supported marks a route as supporting dry runs. Its handler's writes must all go through repositories
+// wrapped to skip them in a dry run.
+func Supported() gin.HandlerFunc {
+	return allow
+}
+
+// requested reports whether the request asks for a dry run
+func requested(c *gin.Context) (bool, error) {
+	value := c.GetHeader(Header)
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// Middleware marks the context of POST, PUT and DELETE requests asking for a dry run, and rejects those sent
+// to routes that do not support one. It must run before any middleware that stores the response, so a dry
+// run is never replayed as the real write.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+		dryRun, err := requested(c)
+		if err != nil {
+			apierror.Abort(c, apierror.BadRequest(Header+" must be true or false"))
+			return
+		}
+		// Unmatched routes are left to respond with 404
+		if !dryRun || c.FullPath() == "" {
+			c.Next()
+			return
+		}
+		if !slices.Contains(c.HandlerNames(), allowName) {
+			apierror.Abort(c, apierror.BadRequest("Dry runs are not supported by this endpoint"))
+			return
+		}
+		c.Request = c.Request.WithContext(With(c.Request.Context()))
+		c.Header(Header, "true")
+		c.Next()
+	}
+}
@@ -0,0 +1,148 @@
+// Package email delivers outbound email through SMTP, Amazon SES or SendGrid, rendering messages from
+// embedded templates and recording every delivery attempt in a per-user send log.
+package email
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"profile-api/config"
+	"profile-api/utils"
+)
+
+// Message represents an outbound email
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	// ReplyTo is the address replies go to, when it is not the sender's
+	ReplyTo string
+	// UserID is the user the message is sent on behalf of, used to file it in their send log
+	UserID string
+	// Template is the name of the template the message was rendered from, if any
+	Template string
+}
+
+// Sender delivers email messages
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+var sender Sender = &LogSender{}
+var provider = "log"
+
+// SMTPSender delivers messages through an SMTP relay
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Send delivers the message through the configured SMTP server
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	addr := fmt.Sprintf("%s:%s", s.Host, s.Port)
+	return smtp.SendMail(addr, auth, s.From, []string{msg.To}, buildMIME(s.From, msg))
+}
+
+// LogSender writes messages to the log instead of delivering them, used when no provider is configured
+type LogSender struct{}
+
+// Send logs the message
+func (l *LogSender) Send(ctx context.Context, msg Message) error {
+	slog.Info("Email not sent, no provider configured", "to", msg.To, "subject", msg.Subject, "body", msg.Text)
+	return nil
+}
+
+// buildMIME renders a multipart/alternative message with text and optional html parts
+func buildMIME(from string, msg Message) []byte {
+	const boundary = "profile-api-boundary"
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	if msg.ReplyTo != "" {
+		fmt.Fprintf(&b, "Reply-To: %s\r\n", msg.ReplyTo)
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	if msg.HTML == "" {
+		b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+		b.WriteString(msg.Text)
+		return []byte(b.String())
+	}
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+	fmt.Fprintf(&b, "--%s\r\nContent-Type: text/plain; charset=\"utf-8\"\r\n\r\n%s\r\n", boundary, msg.Text)
+	fmt.Fprintf(&b, "--%s\r\nContent-Type: text/html; charset=\"utf-8\"\r\n\r\n%s\r\n", boundary, msg.HTML)
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return []byte(b.String())
+}
+
+// InitSender configures the email sender for the configured provider, falling back to logging messages
+func InitSender(ctx context.Context, cfg config.EmailConfig) error {
+	provider = cfg.ActiveProvider()
+	switch provider {
+	case "smtp":
+		sender = &SMTPSender{
+			Host:     cfg.SMTPHost,
+			Port:     strconv.Itoa(cfg.SMTPPort),
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.From,
+		}
+	case "ses":
+		s, err := NewSESSender(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		sender = s
+	case "sendgrid":
+		sender = NewSendGridSender(cfg)
+	default:
+		sender = &LogSender{}
+	}
+	return nil
+}
+
+// Send delivers a message using the configured sender and records the attempt in the sender's log
+func Send(ctx context.Context, msg Message) error {
+	err := sender.Send(ctx, msg)
+	record(ctx, msg, err)
+	return err
+}
+
+// record files the delivery attempt in the send log of the user the message was sent for
+func record(ctx context.Context, msg Message, sendErr error) {
+	if repo == nil || msg.UserID == "" {
+		return
+	}
+	entry := LogEntry{
+		ID:       utils.GenerateID(),
+		UserID:   msg.UserID,
+		To:       msg.To,
+		Subject:  msg.Subject,
+		Template: msg.Template,
+		Provider: provider,
+		Status:   StatusSent,
+		SentAt:   time.Now(),
+	}
+	if sendErr != nil {
+		entry.Status = StatusFailed
+		entry.Error = sendErr.Error()
+	}
+	ctx, cancel := utils.WithOperationTimeout(ctx)
+	defer cancel()
+	if err := repo.Record(ctx, entry); err != nil {
+		slog.Warn("Could not record email in send log", "user_id", msg.UserID, "error", err)
+	}
+}
@@ -0,0 +1,58 @@
+package email
+
+import (
+	"net/http"
+	"strconv"
+
+	"profile-api/apierror"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var repo Repository
+
+const (
+	defaultLogLimit = 50
+	maxLogLimit     = 500
+)
+
+// ListSendLog lists the emails sent on behalf of a user
+//
+//	@Summary		List a user's email send log
+//	@Description	Lists delivery attempts of emails sent on behalf of a user, newest first, including the provider used and any delivery error. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Param			limit	query		int		false	"Maximum number of entries to return (default 50, max 500)"
+//	@Success		200		{array}		LogEntry
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve send log"
+//	@Router			/admin/email-log/{userid} [get]
+func ListSendLog(c *gin.Context) {
+	limit := defaultLogLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, maxLogLimit)
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	entries, err := repo.List(ctx, c.Param("userid"), limit)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve send log"))
+		return
+	}
+	if entries == nil {
+		entries = []LogEntry{}
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// InitializeRoutes registers the send log endpoints and starts recording sent email. The router must only admit admins.
+func InitializeRoutes(router *gin.RouterGroup, r Repository) {
+	repo = r
+
+	router.GET("/:userid", ListSendLog)
+}
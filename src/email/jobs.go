@@ -0,0 +1,27 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+
+	"profile-api/jobs"
+)
+
+// SendJob is the type of the background job delivering a queued message
+const SendJob = "email.send"
+
+// RegisterJobs registers the handler delivering queued messages
+func RegisterJobs() {
+	jobs.Register(SendJob, func(ctx context.Context, job jobs.Job) error {
+		var msg Message
+		if err := json.Unmarshal(job.Payload, &msg); err != nil {
+			return err
+		}
+		return Send(ctx, msg)
+	})
+}
+
+// Enqueue queues the message for delivery by a background worker, which retries it if the provider fails
+func Enqueue(ctx context.Context, msg Message) error {
+	return jobs.Enqueue(ctx, SendJob, msg)
+}
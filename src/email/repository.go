@@ -0,0 +1,33 @@
+package email
+
+import (
+	"context"
+	"time"
+)
+
+// Send log statuses
+const (
+	StatusSent   = "sent"
+	StatusFailed = "failed"
+)
+
+// LogEntry records a single delivery attempt of a message sent on behalf of a user
+type LogEntry struct {
+	ID       string    `bson:"_id" json:"id"`
+	UserID   string    `bson:"user_id" json:"userID"`
+	To       string    `bson:"to" json:"to"`
+	Subject  string    `bson:"subject" json:"subject"`
+	Template string    `bson:"template,omitempty" json:"template,omitempty"`
+	Provider string    `bson:"provider" json:"provider"`
+	Status   string    `bson:"status" json:"status"`
+	Error    string    `bson:"error,omitempty" json:"error,omitempty"`
+	SentAt   time.Time `bson:"sent_at" json:"sentAt"`
+}
+
+// Repository stores the per-user email send log
+type Repository interface {
+	// Record appends a delivery attempt to the log
+	Record(ctx context.Context, entry LogEntry) error
+	// List returns up to limit attempts made on behalf of the user, newest first
+	List(ctx context.Context, userID string, limit int) ([]LogEntry, error)
+}
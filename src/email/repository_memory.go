@@ -0,0 +1,36 @@
+package email
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryRepository keeps the send log in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	entries []LogEntry
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) Record(ctx context.Context, entry LogEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string, limit int) ([]LogEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var entries []LogEntry
+	for i := len(r.entries) - 1; i >= 0 && len(entries) < limit; i-- {
+		if r.entries[i].UserID == userID {
+			entries = append(entries, r.entries[i])
+		}
+	}
+	return entries, nil
+}
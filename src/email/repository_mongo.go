@@ -0,0 +1,39 @@
+package email
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores the send log in the email_log collection
+type MongoRepository struct {
+	entries *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{entries: db.Collection("email_log")}
+}
+
+func (r *MongoRepository) Record(ctx context.Context, entry LogEntry) error {
+	_, err := r.entries.InsertOne(ctx, entry)
+	return err
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string, limit int) ([]LogEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "sent_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.entries.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []LogEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,39 @@
+package email
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository stores the send log in the email_log table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Record(ctx context.Context, entry LogEntry) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO email_log (id, user_id, recipient, subject, template, provider, status, error, sent_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		entry.ID, entry.UserID, entry.To, entry.Subject, entry.Template, entry.Provider, entry.Status, entry.Error, entry.SentAt)
+	return err
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string, limit int) ([]LogEntry, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id, user_id, recipient, subject, template, provider, status, error, sent_at
+		FROM email_log WHERE user_id = $1 ORDER BY sent_at DESC LIMIT $2`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (LogEntry, error) {
+		var entry LogEntry
+		err := row.Scan(&entry.ID, &entry.UserID, &entry.To, &entry.Subject, &entry.Template,
+			&entry.Provider, &entry.Status, &entry.Error, &entry.SentAt)
+		return entry, err
+	})
+}
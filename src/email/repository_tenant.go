@@ -0,0 +1,25 @@
+package email
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Record(ctx context.Context, entry LogEntry) error {
+	return r.repos.For(ctx).Record(ctx, entry)
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID string, limit int) ([]LogEntry, error) {
+	return r.repos.For(ctx).List(ctx, userID, limit)
+}
@@ -0,0 +1,68 @@
+package email
+
+import (
+	"context"
+	"fmt"
+
+	"profile-api/config"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// SESSender delivers messages through the Amazon SES v2 API
+type SESSender struct {
+	client *sesv2.Client
+	from   string
+}
+
+// NewSESSender creates a sender for the configured SES region, using static credentials when
+// an access key is set and the default AWS credential chain otherwise
+func NewSESSender(ctx context.Context, cfg config.EmailConfig) (*SESSender, error) {
+	opts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(cfg.SES.Region)}
+	if cfg.SES.AccessKeyID != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.SES.AccessKeyID, cfg.SES.SecretAccessKey, ""),
+		))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load AWS config: %w", err)
+	}
+
+	client := sesv2.NewFromConfig(awsCfg, func(o *sesv2.Options) {
+		// For LocalStack, e.g. http://localstack:4566
+		if cfg.SES.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.SES.Endpoint)
+		}
+	})
+	return &SESSender{client: client, from: cfg.From}, nil
+}
+
+// Send delivers the message as a simple SES email with text and optional html bodies
+func (s *SESSender) Send(ctx context.Context, msg Message) error {
+	body := &types.Body{Text: sesContent(msg.Text)}
+	if msg.HTML != "" {
+		body.Html = sesContent(msg.HTML)
+	}
+	input := &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(s.from),
+		Destination:      &types.Destination{ToAddresses: []string{msg.To}},
+		Content: &types.EmailContent{
+			Simple: &types.Message{Subject: sesContent(msg.Subject), Body: body},
+		},
+	}
+	if msg.ReplyTo != "" {
+		input.ReplyToAddresses = []string{msg.ReplyTo}
+	}
+	_, err := s.client.SendEmail(ctx, input)
+	return err
+}
+
+// sesContent wraps a utf-8 string for the SES API
+func sesContent(data string) *types.Content {
+	return &types.Content{Data: aws.String(data), Charset: aws.String("UTF-8")}
+}
@@ -0,0 +1,71 @@
+package email
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Each template is a text file, which also defines the "subject" template, and an optional html file
+//
+//go:embed templates
+var templateFS embed.FS
+
+type messageTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var templates = loadTemplates()
+
+// loadTemplates parses every embedded template, panicking on a malformed one as it is a build error
+func loadTemplates() map[string]messageTemplate {
+	files, err := fs.Glob(templateFS, "templates/*.txt")
+	if err != nil {
+		panic(err)
+	}
+	loaded := map[string]messageTemplate{}
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), ".txt")
+		t := messageTemplate{text: texttemplate.Must(texttemplate.ParseFS(templateFS, file))}
+		htmlFile := strings.TrimSuffix(file, ".txt") + ".html"
+		if _, err := fs.Stat(templateFS, htmlFile); err == nil {
+			t.html = htmltemplate.Must(htmltemplate.ParseFS(templateFS, htmlFile))
+		}
+		loaded[name] = t
+	}
+	return loaded
+}
+
+// Render builds a message from the named template. The caller sets the recipient.
+func Render(name string, data any) (Message, error) {
+	t, ok := templates[name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, text strings.Builder
+	if err := t.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("could not render %s subject: %w", name, err)
+	}
+	if err := t.text.Execute(&text, data); err != nil {
+		return Message{}, fmt.Errorf("could not render %s text: %w", name, err)
+	}
+	msg := Message{
+		Subject:  strings.TrimSpace(subject.String()),
+		Text:     strings.TrimSpace(text.String()) + "\n",
+		Template: name,
+	}
+	if t.html != nil {
+		var html strings.Builder
+		if err := t.html.Execute(&html, data); err != nil {
+			return Message{}, fmt.Errorf("could not render %s html: %w", name, err)
+		}
+		msg.HTML = html.String()
+	}
+	return msg, nil
+}
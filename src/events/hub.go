@@ -0,0 +1,233 @@
+// Package events delivers real-time notifications about a user's content to their connected clients.
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"profile-api/dryrun"
+	"profile-api/tenant"
+)
+
+// Event types
+const (
+	// TypeJournalStatusChanged is sent whenever one of the user's journal entries changes status
+	TypeJournalStatusChanged = "journal.status_changed"
+	// TypeJournalProcessed is sent when processing of one of the user's journal entries finishes
+	TypeJournalProcessed = "journal.processed"
+	// TypeProfileUpdated is sent when the user's profile is created or updated
+	TypeProfileUpdated = "profile.updated"
+	// TypeCertificateCreated is sent when the user adds a certificate
+	TypeCertificateCreated = "certificate.created"
+	// TypeCertificateArchived is sent when one of the user's certificates is archived some time after it ended
+	TypeCertificateArchived = "certificate.archived"
+	// TypeUserRegistered is sent when the user registers
+	TypeUserRegistered = "user.registered"
+	// TypeDocumentChanged is sent when one of the user's documents is written, by any replica or tool, as
+	// seen through Mongo change streams
+	TypeDocumentChanged = "document.changed"
+	// TypeNotification is sent when the user is notified, to clients and webhooks when the user's preferences
+	// send that kind of notification to them
+	TypeNotification = "notification.created"
+)
+
+const (
+	// subscriberBuffer is how many events may queue for a client before it is considered too slow and dropped
+	subscriberBuffer = 32
+	// historySize and historyTTL bound the recent events kept per user for clients resuming a stream
+	historySize = 100
+	historyTTL  = 15 * time.Minute
+)
+
+// Event is a notification sent to the clients of a single user
+type Event struct {
+	ID     uint64    `json:"id"`
+	Type   string    `json:"type"`
+	Data   any       `json:"data"`
+	Time   time.Time `json:"time"`
+	UserID string    `json:"-"`
+	// Tenant is the tenant the user belongs to, for listeners working with its storage
+	Tenant string `json:"-"`
+}
+
+// Subscription receives the events published to one user until it is closed
+type Subscription struct {
+	// Events is closed when the subscription is removed from the hub
+	Events <-chan Event
+	events chan Event
+	userID string
+}
+
+// Hub routes published events to the subscriptions of the event's user
+type Hub interface {
+	// Publish sends the event to every current subscription of its user
+	Publish(event Event)
+	// Subscribe starts receiving the user's events
+	Subscribe(userID string) *Subscription
+	// Resume starts receiving the user's events, first returning those published after lastEventID
+	// that are still held in the history
+	Resume(userID string, lastEventID uint64) (*Subscription, []Event)
+	// Unsubscribe stops the subscription and closes its channel
+	Unsubscribe(sub *Subscription)
+}
+
+// LocalHub delivers events to clients connected to this replica
+type LocalHub struct {
+	mu        sync.Mutex
+	lastID    uint64
+	subs      map[string]map[*Subscription]bool
+	history   map[string][]Event
+	lastSweep time.Time
+}
+
+// NewLocalHub creates a hub with no subscriptions
+func NewLocalHub() *LocalHub {
+	return &LocalHub{
+		subs:      map[string]map[*Subscription]bool{},
+		history:   map[string][]Event{},
+		lastSweep: time.Now(),
+	}
+}
+
+func (h *LocalHub) Publish(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// IDs follow the clock so they keep increasing across restarts and Last-Event-ID stays meaningful
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	h.lastID = max(h.lastID+1, uint64(event.Time.UnixMicro()))
+	event.ID = h.lastID
+
+	h.record(event)
+	for sub := range h.subs[event.UserID] {
+		select {
+		case sub.events <- event:
+		default:
+			// Dropping a slow client makes it reconnect rather than silently miss events
+			slog.Warn("Dropping slow event subscriber", "user_id", event.UserID)
+			h.remove(sub)
+		}
+	}
+}
+
+func (h *LocalHub) Subscribe(userID string) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.subscribe(userID)
+}
+
+func (h *LocalHub) Resume(userID string, lastEventID uint64) (*Subscription, []Event) {
+	// Reading the history under the same lock as subscribing means no event is missed or sent twice
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var missed []Event
+	for _, event := range h.history[userID] {
+		if event.ID > lastEventID {
+			missed = append(missed, event)
+		}
+	}
+	return h.subscribe(userID), missed
+}
+
+func (h *LocalHub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(sub)
+}
+
+// subscribe adds a subscription for the user. The caller must hold the lock.
+func (h *LocalHub) subscribe(userID string) *Subscription {
+	events := make(chan Event, subscriberBuffer)
+	sub := &Subscription{Events: events, events: events, userID: userID}
+	if h.subs[userID] == nil {
+		h.subs[userID] = map[*Subscription]bool{}
+	}
+	h.subs[userID][sub] = true
+	return sub
+}
+
+// remove deletes the subscription and closes its channel. The caller must hold the lock.
+func (h *LocalHub) remove(sub *Subscription) {
+	subs := h.subs[sub.userID]
+	if !subs[sub] {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subs, sub.userID)
+	}
+	close(sub.events)
+}
+
+// record adds the event to its user's history, dropping events past the history bounds. The caller must hold the lock.
+func (h *LocalHub) record(event Event) {
+	events := append(h.history[event.UserID], event)
+	if len(events) > historySize {
+		events = events[len(events)-historySize:]
+	}
+	h.history[event.UserID] = events
+
+	if time.Since(h.lastSweep) < historyTTL {
+		return
+	}
+	cutoff := time.Now().Add(-historyTTL)
+	for userID, events := range h.history {
+		i := 0
+		for i < len(events) && events[i].Time.Before(cutoff) {
+			i++
+		}
+		if i == len(events) {
+			delete(h.history, userID)
+		} else {
+			h.history[userID] = events[i:]
+		}
+	}
+	h.lastSweep = time.Now()
+}
+
+var hub Hub = NewLocalHub()
+
+// Listener is called for every published event, after it has been sent to the hub
+type Listener func(event Event)
+
+var listeners []Listener
+
+// SetHub replaces the hub events are published through
+func SetHub(h Hub) {
+	hub = h
+}
+
+// Listen registers a listener for every published event, such as outbound webhooks. Listeners are
+// called synchronously by Publish and must be registered before the server starts.
+func Listen(l Listener) {
+	listeners = append(listeners, l)
+}
+
+// Publish notifies the user's connected clients and the registered listeners of an event. Nothing is
+// published for a dry run, which changed nothing.
+func Publish(ctx context.Context, userID, eventType string, data any) {
+	if dryrun.Active(ctx) {
+		return
+	}
+	Notify(ctx, userID, eventType, data)
+	Dispatch(ctx, userID, eventType, data)
+}
+
+// Notify sends an event to the user's clients connected to this replica only. It is for events every
+// replica learns of by itself, such as changes seen through change streams, which each replica sends to
+// its own clients while one of them calls the listeners with Dispatch.
+func Notify(ctx context.Context, userID, eventType string, data any) {
+	hub.Publish(Event{Type: eventType, Data: data, UserID: userID, Tenant: tenant.ID(ctx), Time: time.Now()})
+}
+
+// Dispatch calls the registered listeners with an event, without sending it to any client
+func Dispatch(ctx context.Context, userID, eventType string, data any) {
+	event := Event{Type: eventType, Data: data, UserID: userID, Tenant: tenant.ID(ctx), Time: time.Now()}
+	for _, l := range listeners {
+		l(event)
+	}
+}
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"profile-api/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// heartbeatPeriod is how often a comment is sent to keep idle connections open through proxies
+const heartbeatPeriod = 30 * time.Second
+
+// ServeEvents streams the authenticated user's events as Server-Sent Events
+//
+//	@Summary		Stream notifications as Server-Sent Events
+//	@Description	Streams the authenticated user's events as text/event-stream. Each event carries its ID, so a client reconnecting with the Last-Event-ID header, or the last_event_id query parameter, receives the recent events it missed.
+//	@Tags			events
+//	@Produce		text/event-stream
+//	@Param			Last-Event-ID	header		int		false	"ID of the last event received"
+//	@Param			last_event_id	query		int		false	"ID of the last event received, for clients that cannot set headers"
+//	@Success		200				{object}	Event	"Stream of events"
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Router			/events [get]
+func ServeEvents(c *gin.Context) {
+	userID := c.MustGet("userID").(string)
+
+	var sub *Subscription
+	var missed []Event
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if id, err := strconv.ParseUint(lastEventID, 10, 64); err == nil {
+		sub, missed = hub.Resume(userID, id)
+	} else {
+		sub = hub.Subscribe(userID)
+	}
+	defer hub.Unsubscribe(sub)
+
+	// The server's write timeout would otherwise end the stream
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logging.Logger(c).Warn("Could not clear write deadline for event stream", "error", err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprint(c.Writer, "retry: 3000\n\n")
+	for _, event := range missed {
+		writeEvent(c, event)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatPeriod)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Dropped as too slow, the client reconnects and resumes from its last event
+				return
+			}
+			writeEvent(c, event)
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent writes the event in the event stream format
+func writeEvent(c *gin.Context, event Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		logging.Logger(c).Error("Error encoding event", "type", event.Type, "error", err)
+		return
+	}
+	fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
@@ -0,0 +1,110 @@
+package events
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"profile-api/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = pongWait * 9 / 10
+)
+
+var allowedOrigins = map[string]bool{}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkOrigin,
+}
+
+// checkOrigin accepts same-origin requests and those from the CORS allowed origins. The connection is
+// authenticated by cookie, so accepting any origin would let other sites read the user's events.
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || allowedOrigins[origin] || allowedOrigins["*"] {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// ServeWebSocket streams the authenticated user's events over a WebSocket
+//
+//	@Summary		Stream notifications over a WebSocket
+//	@Description	Upgrades to a WebSocket that receives the authenticated user's events as JSON messages. Messages sent by the client are ignored.
+//	@Tags			events
+//	@Success		101		{object}	Event	"Switching protocols, events follow as messages"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Router			/ws [get]
+func ServeWebSocket(c *gin.Context) {
+	userID := c.MustGet("userID").(string)
+
+	// Upgrade writes the error response itself when the handshake is invalid
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logging.Logger(c).Debug("WebSocket upgrade failed", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	sub := hub.Subscribe(userID)
+	defer hub.Unsubscribe(sub)
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(pongWait))
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(pingPeriod)
+	defer ping.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow"))
+				return
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// InitializeRoutes registers the event streaming endpoints. The router must authenticate the user.
+func InitializeRoutes(router gin.IRoutes, origins []string) {
+	for _, origin := range origins {
+		allowedOrigins[origin] = true
+	}
+	router.GET("/ws", ServeWebSocket)
+	router.GET("/events", ServeEvents)
+}
@@ -2,85 +2,83 @@ package experience
 
 import (
 	"context"
-	"net/http"
+	"errors"
+
+	"profile-api/apierror"
+	"profile-api/apiversion"
 	"profile-api/auth"
+	"profile-api/bulk"
+	"profile-api/dryrun"
+	"profile-api/organizations"
+	"profile-api/quota"
+	"profile-api/store"
+	"profile-api/utils"
+	"profile-api/visibility"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var experienceCollection *mongo.Collection
+var repo Repository
 
 type JSONResponse struct {
 	Message string `json:"message"`
-	Error   string `json:"error"`
 }
 
 // GetExperience retrieves all work experience records for the specified user.
 //
 //	@Summary		Get all user experiences
-//	@Description	Retrieves all work experience records for the specified user
+//	@Description	Retrieves all work experience records for the specified user, without the fields their visibility hides from the requester
 //	@Tags			experience
 //	@Accept			json
 //	@Produce		json
 //	@Param			userid	path		string	true	"User ID"
 //	@Success		200		{array}		Experience
-//	@Failure		500		{object}	JSONResponse	"error":	"Could not retrieve experience"
+//	@Failure		500		{object}	apierror.Response	"error":	"Could not retrieve experience"
 //	@Router			/experience/{userid} [get]
 func GetExperience(c *gin.Context) {
 	userID := c.Param("userid")
-	var experience []Experience
-	cursor, err := experienceCollection.Find(context.Background(), bson.M{"user_id": userID})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	experience, err := repo.List(ctx, userID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve experience"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve experience"))
 		return
 	}
-	defer cursor.Close(context.Background())
-	for cursor.Next(context.Background()) {
-		var exp Experience
-		err := cursor.Decode(&exp)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve experience"})
-			return
-		}
-		experience = append(experience, exp)
-	}
 
-	c.JSON(http.StatusOK, experience)
+	visibility.List(c, experience)
 }
 
 // GetExperienceItem retrieves a specific work experience record for the specified user and experience ID.
 //
 //	@Summary		Get specific experience item
-//	@Description	Retrieves a specific work experience record for the specified user and experience ID
+//	@Description	Retrieves a specific work experience record for the specified user and experience ID, without the fields its visibility hides from the requester
 //	@Tags			experience
 //	@Accept			json
 //	@Produce		json
 //	@Param			userid			path		string	true	"User ID"
 //	@Param			experienceid	path		string	true	"Experience ID"
 //	@Success		200				{object}	Experience
-//	@Failure		500				{object}	JSONResponse	"error":	"Could not retrieve experience"
+//	@Failure		500				{object}	apierror.Response	"error":	"Could not retrieve experience"
 //	@Router			/experience/{userid}/{experienceid} [get]
 func GetExperienceItem(c *gin.Context) {
 	userID := c.Param("userid")
 	experienceID := c.Param("experienceid")
-	var exp Experience
-	err := experienceCollection.FindOne(context.Background(), bson.M{"user_id": userID, "experience_id": experienceID}).Decode(&exp)
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	exp, err := repo.Get(ctx, userID, experienceID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve experience"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve experience"))
 		return
 	}
 
-	c.JSON(http.StatusOK, exp)
+	visibility.OK(c, exp)
 }
 
 // PutExperienceItem updates a specific work experience record for the specified user and experience ID.
 //
 //	@Summary		Update specific experience item
-//	@Description	Updates a specific work experience record for the specified user and experience ID
+//	@Description	Updates a specific work experience record for the specified user and experience ID. It may link to a verified organization with organization_id.
 //	@Tags			experience
 //	@Accept			json
 //	@Produce		json
@@ -88,10 +86,11 @@ func GetExperienceItem(c *gin.Context) {
 //	@Param			experienceid	path		string			true		"Experience ID"
 //	@Param			Experience		body		Experience		true		"Experience Object"
 //	@Success		200				{object}	JSONResponse	"message":	"Experience updated"
-//	@Failure		400				{object}	JSONResponse	"error":	"Invalid request body"
-//	@Failure		401				{object}	JSONResponse	"error":	"Unauthorized"
-//	@Failure		403				{object}	JSONResponse	"error":	"Forbidden"
-//	@Failure		500				{object}	JSONResponse	"error":	"Could not update experience"
+//	@Failure		400				{object}	apierror.Response	"error":	"Invalid request body"
+//	@Failure		401				{object}	apierror.Response	"error":	"Unauthorized"
+//	@Failure		403				{object}	apierror.Response	"error":	"Forbidden"
+//	@Failure		422				{object}	apierror.Response	"error":	"Organization is not verified"
+//	@Failure		500				{object}	apierror.Response	"error":	"Could not update experience"
 //	@Security		BearerAuth
 //	@Router			/experience/{userid}/{experienceid} [put]
 func PutExperienceItem(c *gin.Context) {
@@ -99,58 +98,81 @@ func PutExperienceItem(c *gin.Context) {
 	experienceID := c.Param("experienceid")
 
 	var req Experience
-	if err := c.BindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 	req.UserID = userID
 	req.ExperienceID = experienceID
 
-	_, err := experienceCollection.UpdateOne(context.Background(), bson.M{"user_id": userID, "experience_id": experienceID}, bson.M{"$set": req}, options.Update().SetUpsert(true))
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not update experience"})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if req.OrganizationID != "" {
+		// Links to organizations since unverified are kept
+		current, err := repo.Get(ctx, userID, experienceID)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			apierror.Abort(c, apierror.Wrap(err, "Could not update experience"))
+			return
+		}
+		if current.OrganizationID != req.OrganizationID {
+			if _, err := organizations.Verified(c, req.OrganizationID); err != nil {
+				apierror.Abort(c, err)
+				return
+			}
+		}
+	}
+	if err := repo.Save(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update experience"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Experience updated"})
+	apiversion.Updated(c, req, gin.H{"message": "Experience updated"})
 }
 
 // PostExperience creates a new work experience record for the specified user.
 //
 //	@Summary		Create a new experience item
-//	@Description	Creates a new work experience record for the specified user
+//	@Description	Creates a new work experience record for the specified user. It may link to a verified organization with organization_id.
 //	@Tags			experience
 //	@Accept			json
 //	@Produce		json
 //	@Param			userid		path		string		true	"User ID"
 //	@Param			Experience	body		Experience	true	"Experience Object"
 //	@Success		200			{object}	Experience
-//	@Failure		400			{object}	JSONResponse	"error":	"Invalid request body"
-//	@Failure		401			{object}	JSONResponse	"error":	"Unauthorized"
-//	@Failure		403			{object}	JSONResponse	"error":	"Forbidden"
-//	@Failure		404			{object}	JSONResponse	"error":	"User not found"
-//	@Failure		409			{object}	JSONResponse	"error":	"Experience already exists"
-//	@Failure		422			{object}	JSONResponse	"error":	"Invalid experience type"
-//	@Failure		500			{object}	JSONResponse	"error":	"Could not insert experience"
+//	@Failure		400			{object}	apierror.Response	"error":	"Invalid request body"
+//	@Failure		401			{object}	apierror.Response	"error":	"Unauthorized"
+//	@Failure		403			{object}	apierror.Response	"error":	"Forbidden"
+//	@Failure		404			{object}	apierror.Response	"error":	"User not found"
+//	@Failure		409			{object}	apierror.Response	"error":	"Experience already exists"
+//	@Failure		413			{object}	apierror.Response	"error":	"Experience limit reached"
+//	@Failure		422			{object}	apierror.Response	"error":	"Invalid experience type, or organization is not verified"
+//	@Failure		500			{object}	apierror.Response	"error":	"Could not insert experience"
 //	@Router			/experience/{userid} [post]
 func PostExperience(c *gin.Context) {
 	userID := c.Param("userid")
 
 	var req Experience
-	if err := c.BindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 	req.UserID = userID
 	req.ExperienceID = primitive.NewObjectID().Hex()
+	if req.OrganizationID != "" {
+		if _, err := organizations.Verified(c, req.OrganizationID); err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+	}
 
-	_, err := experienceCollection.InsertOne(context.Background(), req)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not insert experience"})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Create(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not insert experience"))
 		return
 	}
 
-	c.JSON(http.StatusOK, req)
+	apiversion.Created(c, req.ExperienceID, req, req)
 }
 
 // DeleteExperienceItem deletes a specific work experience record for the specified user and experience ID.
@@ -163,32 +185,92 @@ func PostExperience(c *gin.Context) {
 //	@Param			userid			path		string			true		"User ID"
 //	@Param			experienceid	path		string			true		"Experience ID"
 //	@Success		200				{object}	JSONResponse	"message":	"Experience deleted"
-//	@Failure		500				{object}	JSONResponse	"error":	"Could not delete experience"
+//	@Failure		500				{object}	apierror.Response	"error":	"Could not delete experience"
+//	@Failure		404				{object}	apierror.Response	"Experience not found"
+//	@Failure		403				{object}	apierror.Response	"Not the owner of the experience"
 //	@Router			/experience/{userid}/{experienceid} [delete]
 func DeleteExperienceItem(c *gin.Context) {
 	userID := c.Param("userid")
 	experienceID := c.Param("experienceid")
 
-	_, err := experienceCollection.DeleteOne(context.Background(), bson.M{"user_id": userID, "experience_id": experienceID})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	err := repo.Delete(ctx, userID, experienceID)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Experience not found"))
+		return
+	}
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not delete experience"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete experience"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Experience deleted"})
+	apiversion.NoContent(c, gin.H{"message": "Experience deleted"})
+}
+
+// BulkDeleteExperience deletes many of a user's experience at once.
+//
+//	@Summary		Delete experience in bulk
+//	@Description	Deletes the user's experience with the given IDs, or those whose fields match every value of the filter, such as {"company": "Acme"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.
+//	@Tags			experience
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string				true	"User ID"
+//	@Param			body	body		bulk.DeleteRequest	true	"The experience to delete"
+//	@Success		200		{object}	bulk.DeleteResult
+//	@Failure		400		{object}	apierror.Response	"Invalid request, or a filter that is not confirmed"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the experience"
+//	@Failure		500		{object}	apierror.Response	"Could not delete experience"
+//	@Router			/experience/{userid}/bulk-delete [post]
+func BulkDeleteExperience(c *gin.Context) {
+	userID := c.Param("userid")
+
+	var req bulk.DeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	items, err := repo.List(ctx, userID)
+	cancel()
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve experience"))
+		return
+	}
+	result, err := bulk.Delete(c.Request.Context(), req, items, func(item Experience) string { return item.ExperienceID }, func(ctx context.Context, id string) error {
+		return repo.Delete(ctx, userID, id)
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete experience"))
+		return
+	}
+
+	apiversion.OK(c, result)
+}
+
+// countExperience counts the user's experience against their document quota
+func countExperience(ctx context.Context, userID string) (int, error) {
+	items, err := repo.List(ctx, userID)
+	return len(items), err
 }
 
 // InitializeRoutes initializes the experience routes
-func InitializeRoutes(router *gin.RouterGroup, db *mongo.Client, db_name string) {
-	experienceCollection = db.Database(db_name).Collection("experience")
+func InitializeRoutes(router *gin.RouterGroup, r Repository, users auth.Repository) {
+	repo = r
+
+	authOptional := auth.AuthMiddleware(users, false)
+	authRequired := auth.AuthMiddleware(users, true)
 
-	router.GET("/:userid", GetExperience)
-	router.GET("/:userid/:experienceid", GetExperienceItem)
+	router.GET("/:userid", authOptional, GetExperience)
+	router.GET("/:userid/:experienceid", authOptional, GetExperienceItem)
 
-	authRequired := auth.AuthMiddleware(db, db_name, true)
 	protected := router.Group("/")
 	protected.Use(authRequired)
-	protected.POST("/:userid", PostExperience)
-	protected.PUT("/:userid/:experienceid", PutExperienceItem)
-	protected.DELETE("/:userid/:experienceid", DeleteExperienceItem)
+	protected.POST("/:userid", dryrun.Supported(), auth.RequireOwner(), quota.LimitDocuments("experience", countExperience), PostExperience)
+	protected.PUT("/:userid/:experienceid", dryrun.Supported(), auth.RequireOwner(), PutExperienceItem)
+	protected.DELETE("/:userid/:experienceid", dryrun.Supported(), auth.RequireOwner(), DeleteExperienceItem)
+	protected.POST("/:userid/bulk-delete", dryrun.Supported(), auth.RequireOwner(), BulkDeleteExperience)
 }
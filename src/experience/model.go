@@ -1,13 +1,29 @@
 package experience
 
+import "profile-api/visibility"
+
 // Experience represents a user's work experience
 type Experience struct {
 	UserID       string `bson:"user_id" json:"user_id"`
 	ExperienceID string `bson:"experience_id" json:"experience_id"`
-	Company      string `bson:"company" json:"company"`
-	Position     string `bson:"position" json:"position"`
-	Start        string `bson:"start" json:"start"`
-	End          string `bson:"end" json:"end"`
-	Description  string `bson:"description" json:"description"`
-	Notes        string `bson:"notes" json:"notes"`
+	Company      string `bson:"company" json:"company" binding:"required,notblank,max=200"`
+	Position     string `bson:"position" json:"position" binding:"required,notblank,max=200"`
+	Start        string `bson:"start" json:"start" binding:"required,date"`
+	End          string `bson:"end" json:"end" binding:"omitempty,date"`
+	Description  string `bson:"description" json:"description" binding:"max=5000"`
+	Notes        string `bson:"notes" json:"notes" binding:"max=5000"`
+	// OrganizationID links the experience to the page of a verified organization
+	OrganizationID string `bson:"organization_id,omitempty" json:"organization_id,omitempty" binding:"max=100"`
+	// Visibility maps fields to who they are shown to: public, members or private
+	Visibility visibility.Rules `bson:"visibility" json:"visibility,omitempty" binding:"omitempty,visibility"`
+}
+
+// Owner returns the ID of the user the experience belongs to
+func (e Experience) Owner() string {
+	return e.UserID
+}
+
+// FieldVisibility returns the rules of the experience's fields
+func (e Experience) FieldVisibility() visibility.Rules {
+	return e.Visibility
 }
@@ -0,0 +1,19 @@
+package experience
+
+import "context"
+
+// Repository stores work experience records
+type Repository interface {
+	// List returns every experience record belonging to the user
+	List(ctx context.Context, userID string) ([]Experience, error)
+	// ListByUsers returns every experience record belonging to any of the users
+	ListByUsers(ctx context.Context, userIDs []string) ([]Experience, error)
+	// Get returns a single experience record, or store.ErrNotFound
+	Get(ctx context.Context, userID, experienceID string) (Experience, error)
+	// Create stores a new experience record
+	Create(ctx context.Context, item Experience) error
+	// Save replaces a experience record, creating it if it does not exist
+	Save(ctx context.Context, item Experience) error
+	// Delete removes a experience record, or returns store.ErrNotFound when the user has no such experience record
+	Delete(ctx context.Context, userID, experienceID string) error
+}
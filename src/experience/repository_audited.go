@@ -0,0 +1,55 @@
+package experience
+
+import (
+	"context"
+	"errors"
+
+	"profile-api/audit"
+	"profile-api/store"
+)
+
+// AuditedRepository records every change to experience records in the audit log
+type AuditedRepository struct {
+	Repository
+}
+
+// NewAuditedRepository wraps the repository to record its changes in the audit log
+func NewAuditedRepository(r Repository) *AuditedRepository {
+	return &AuditedRepository{Repository: r}
+}
+
+func (r *AuditedRepository) Create(ctx context.Context, item Experience) error {
+	if err := r.Repository.Create(ctx, item); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionCreate, "experience", item.UserID, item.ExperienceID, nil, item)
+	return nil
+}
+
+func (r *AuditedRepository) Save(ctx context.Context, item Experience) error {
+	before, err := r.Repository.Get(ctx, item.UserID, item.ExperienceID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.Save(ctx, item); err != nil {
+		return err
+	}
+	audit.RecordSave(ctx, "experience", item.UserID, item.ExperienceID, before, existed, item)
+	return nil
+}
+
+func (r *AuditedRepository) Delete(ctx context.Context, userID, experienceID string) error {
+	before, err := r.Repository.Get(ctx, userID, experienceID)
+	if errors.Is(err, store.ErrNotFound) {
+		return r.Repository.Delete(ctx, userID, experienceID)
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.Delete(ctx, userID, experienceID); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionDelete, "experience", userID, experienceID, before, nil)
+	return nil
+}
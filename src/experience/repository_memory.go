@@ -0,0 +1,96 @@
+package experience
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps work experience records in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu    sync.RWMutex
+	items []Experience
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+// index returns the position of a experience record in items, or -1. The caller must hold the lock.
+func (r *MemoryRepository) index(userID, experienceID string) int {
+	for i, item := range r.items {
+		if item.UserID == userID && item.ExperienceID == experienceID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string) ([]Experience, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Experience
+	for _, item := range r.items {
+		if item.UserID == userID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Experience, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Experience
+	for _, item := range r.items {
+		if slices.Contains(userIDs, item.UserID) {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, userID, experienceID string) (Experience, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.index(userID, experienceID)
+	if i < 0 {
+		return Experience{}, store.ErrNotFound
+	}
+	return r.items[i], nil
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, item Experience) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.index(item.UserID, item.ExperienceID) >= 0 {
+		return store.ErrConflict
+	}
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, item Experience) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(item.UserID, item.ExperienceID); i >= 0 {
+		r.items[i] = item
+		return nil
+	}
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, userID, experienceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, experienceID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.items = append(r.items[:i], r.items[i+1:]...)
+	return nil
+}
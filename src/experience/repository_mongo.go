@@ -0,0 +1,80 @@
+package experience
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores work experience records in the experience collection
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{collection: db.Collection("experience")}
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string) ([]Experience, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []Experience
+	for cursor.Next(ctx) {
+		var item Experience
+		if err := cursor.Decode(&item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, cursor.Err()
+}
+
+func (r *MongoRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Experience, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []Experience
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *MongoRepository) Get(ctx context.Context, userID, experienceID string) (Experience, error) {
+	var item Experience
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "experience_id": experienceID}).Decode(&item)
+	return item, store.MongoErr(err)
+}
+
+func (r *MongoRepository) Create(ctx context.Context, item Experience) error {
+	_, err := r.collection.InsertOne(ctx, item)
+	return err
+}
+
+func (r *MongoRepository) Save(ctx context.Context, item Experience) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": item.UserID, "experience_id": item.ExperienceID}, bson.M{"$set": item}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, userID, experienceID string) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID, "experience_id": experienceID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
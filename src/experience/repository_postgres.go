@@ -0,0 +1,78 @@
+package experience
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const experienceColumns = "user_id, experience_id, company, position, start_date, end_date, description, notes, visibility, organization_id"
+
+// PostgresRepository stores experience records in the experience table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string) ([]Experience, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+experienceColumns+" FROM experience WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanExperience)
+}
+
+func (r *PostgresRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Experience, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+experienceColumns+" FROM experience WHERE user_id = ANY($1) ORDER BY created_at", userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanExperience)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, userID, experienceID string) (Experience, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+experienceColumns+" FROM experience WHERE user_id = $1 AND experience_id = $2", userID, experienceID)
+	if err != nil {
+		return Experience{}, err
+	}
+	item, err := pgx.CollectExactlyOneRow(rows, scanExperience)
+	return item, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, item Experience) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO experience ("+experienceColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		item.UserID, item.ExperienceID, item.Company, item.Position, item.Start, item.End, item.Description, item.Notes, item.Visibility, item.OrganizationID)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, item Experience) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO experience ("+experienceColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) "+
+		"ON CONFLICT (user_id, experience_id) DO UPDATE SET company = EXCLUDED.company, position = EXCLUDED.position, start_date = EXCLUDED.start_date, end_date = EXCLUDED.end_date, description = EXCLUDED.description, notes = EXCLUDED.notes, visibility = EXCLUDED.visibility, organization_id = EXCLUDED.organization_id",
+		item.UserID, item.ExperienceID, item.Company, item.Position, item.Start, item.End, item.Description, item.Notes, item.Visibility, item.OrganizationID)
+	return err
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, userID, experienceID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM experience WHERE user_id = $1 AND experience_id = $2", userID, experienceID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// scanExperience reads a row selected with experienceColumns
+func scanExperience(row pgx.CollectableRow) (Experience, error) {
+	var item Experience
+	err := row.Scan(&item.UserID, &item.ExperienceID, &item.Company, &item.Position, &item.Start, &item.End, &item.Description, &item.Notes, &item.Visibility, &item.OrganizationID)
+	return item, err
+}
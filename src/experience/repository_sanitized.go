@@ -0,0 +1,31 @@
+package experience
+
+import (
+	"context"
+
+	"profile-api/sanitize"
+)
+
+// SanitizedRepository cleans the user-supplied text of experience entries before storing them
+type SanitizedRepository struct {
+	Repository
+}
+
+// NewSanitizedRepository wraps the repository to clean experience entries with the configured sanitize policies
+func NewSanitizedRepository(r Repository) *SanitizedRepository {
+	return &SanitizedRepository{Repository: r}
+}
+
+func (r *SanitizedRepository) Create(ctx context.Context, item Experience) error {
+	return r.Repository.Create(ctx, sanitized(item))
+}
+
+func (r *SanitizedRepository) Save(ctx context.Context, item Experience) error {
+	return r.Repository.Save(ctx, sanitized(item))
+}
+
+func sanitized(item Experience) Experience {
+	item.Description = sanitize.Field(sanitize.ExperienceDescription, item.Description)
+	item.Notes = sanitize.Field(sanitize.ExperienceNotes, item.Notes)
+	return item
+}
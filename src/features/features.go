@@ -0,0 +1,259 @@
+// Package features switches subsystems on and off without a deploy. Each flag is defined in the config
+// and can be overridden at runtime by an admin, per tenant, to roll a feature out to chosen users or a
+// percentage of users, or to switch it off in an emergency.
+//
+// Overrides are read from storage at most every cacheTTL, so a change made on one replica reaches the
+// others within that time. A user's percentage bucket is derived from the flag name and their user ID,
+// so the same users stay in a rollout as it grows. Anonymous requests only see features rolled out to
+// every user.
+package features
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/config"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Flags gating subsystems
+const (
+	Search       = "search"
+	AIProcessing = "ai-processing"
+	Comments     = "comments"
+)
+
+// cacheTTL is how long overrides are used before they are read from storage again
+const cacheTTL = 30 * time.Second
+
+var repo Repository
+var defaults map[string]config.FeatureFlagConfig
+
+// overrides caches each tenant's overrides
+var overrides = struct {
+	sync.Mutex
+	byTenant map[string]cachedOverrides
+}{byTenant: map[string]cachedOverrides{}}
+
+type cachedOverrides struct {
+	flags    map[string]Flag
+	loadedAt time.Time
+}
+
+// Configure sets the configured flags and where runtime overrides are stored
+func Configure(r Repository, cfg map[string]config.FeatureFlagConfig) {
+	repo = r
+	defaults = cfg
+}
+
+// Enabled reports whether the feature is enabled for the user, who is empty for anonymous requests.
+// Unknown features are disabled.
+func Enabled(ctx context.Context, name, userID string) bool {
+	flag, ok := lookup(ctx, name)
+	if !ok || !flag.Enabled {
+		return false
+	}
+	if flag.Percentage >= 100 || slices.Contains(flag.Users, userID) {
+		return true
+	}
+	if userID == "" {
+		return false
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + userID))
+	return int(h.Sum32()%100) < flag.Percentage
+}
+
+// Require responds 404 to requests from users the feature is not enabled for, as if the route did not
+// exist. Authentication must run first for the user's rollout to apply.
+func Require(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !Enabled(c.Request.Context(), name, c.GetString("userID")) {
+			apierror.Abort(c, apierror.NotFound("Route not found"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// lookup returns the flag as overridden for the context's tenant, or as configured
+func lookup(ctx context.Context, name string) (Flag, bool) {
+	if flag, ok := loadOverrides(ctx)[name]; ok {
+		return flag, true
+	}
+	cfg, ok := defaults[name]
+	return configuredFlag(name, cfg), ok
+}
+
+// loadOverrides returns the tenant's cached overrides, reading them again once they are older than cacheTTL.
+// When they cannot be read the previous ones are kept, so a storage outage does not flip features.
+func loadOverrides(ctx context.Context) map[string]Flag {
+	id := tenant.ID(ctx)
+	overrides.Lock()
+	defer overrides.Unlock()
+	cached, ok := overrides.byTenant[id]
+	if ok && time.Since(cached.loadedAt) < cacheTTL {
+		return cached.flags
+	}
+
+	ctx, cancel := utils.WithOperationTimeout(ctx)
+	defer cancel()
+	flags, err := repo.List(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Could not load feature flag overrides", "error", err)
+		// Retry after another cacheTTL rather than on every request
+		cached.loadedAt = time.Now()
+		overrides.byTenant[id] = cached
+		return cached.flags
+	}
+	cached = cachedOverrides{flags: map[string]Flag{}, loadedAt: time.Now()}
+	for _, flag := range flags {
+		flag.Source = "override"
+		cached.flags[flag.Name] = flag
+	}
+	overrides.byTenant[id] = cached
+	return cached.flags
+}
+
+// invalidate makes the next lookup for the context's tenant read the overrides again
+func invalidate(ctx context.Context) {
+	overrides.Lock()
+	defer overrides.Unlock()
+	delete(overrides.byTenant, tenant.ID(ctx))
+}
+
+func configuredFlag(name string, cfg config.FeatureFlagConfig) Flag {
+	users := cfg.Users
+	if users == nil {
+		users = []string{}
+	}
+	return Flag{Name: name, Enabled: cfg.Enabled, Percentage: cfg.Percentage, Users: users, Source: "config"}
+}
+
+// ListFlags lists every feature flag as it currently applies
+//
+//	@Summary		List feature flags
+//	@Description	Lists every configured feature flag with who it is enabled for on this site, showing whether it is as configured or overridden at runtime. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{array}		Flag
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		403	{object}	apierror.Response	"Admin access required"
+//	@Router			/admin/features [get]
+func ListFlags(c *gin.Context) {
+	invalidate(c.Request.Context())
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	flags := make([]Flag, 0, len(names))
+	for _, name := range names {
+		flag, _ := lookup(c.Request.Context(), name)
+		flags = append(flags, flag)
+	}
+	c.JSON(http.StatusOK, flags)
+}
+
+// SetFlag overrides a feature flag
+//
+//	@Summary		Override a feature flag
+//	@Description	Sets who a feature is enabled for on this site, replacing its configured rollout until the override is removed. Other replicas apply the change within 30 seconds. Requires the admin role.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string		true	"Feature flag name"
+//	@Param			request	body		FlagRequest	true	"Rollout of the feature"
+//	@Success		200		{object}	Flag
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		404		{object}	apierror.Response	"Unknown feature flag"
+//	@Failure		500		{object}	apierror.Response	"Could not save feature flag"
+//	@Router			/admin/features/{name} [put]
+func SetFlag(c *gin.Context) {
+	name := c.Param("name")
+	if _, ok := defaults[name]; !ok {
+		apierror.Abort(c, apierror.NotFound("Unknown feature flag"))
+		return
+	}
+	var req FlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	if req.Users == nil {
+		req.Users = []string{}
+	}
+
+	now := time.Now()
+	flag := Flag{
+		Name:       name,
+		Enabled:    req.Enabled,
+		Percentage: req.Percentage,
+		Users:      req.Users,
+		Source:     "override",
+		UpdatedBy:  c.GetString("userID"),
+		UpdatedAt:  &now,
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Save(ctx, flag); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not save feature flag"))
+		return
+	}
+	invalidate(c.Request.Context())
+	slog.InfoContext(ctx, "Feature flag overridden", "flag", name, "enabled", flag.Enabled, "percentage", flag.Percentage, "admin", flag.UpdatedBy)
+
+	c.JSON(http.StatusOK, flag)
+}
+
+// ResetFlag removes a feature flag's override
+//
+//	@Summary		Remove a feature flag override
+//	@Description	Returns a feature flag to its configured rollout on this site. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			name	path		string	true	"Feature flag name"
+//	@Success		200		{object}	Flag
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		404		{object}	apierror.Response	"Feature flag is not overridden"
+//	@Failure		500		{object}	apierror.Response	"Could not remove feature flag override"
+//	@Router			/admin/features/{name} [delete]
+func ResetFlag(c *gin.Context) {
+	name := c.Param("name")
+	cfg, ok := defaults[name]
+	if !ok {
+		apierror.Abort(c, apierror.NotFound("Unknown feature flag"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Delete(ctx, name); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Feature flag is not overridden"))
+		return
+	}
+	invalidate(c.Request.Context())
+	slog.InfoContext(ctx, "Feature flag override removed", "flag", name, "admin", c.GetString("userID"))
+
+	c.JSON(http.StatusOK, configuredFlag(name, cfg))
+}
+
+// InitializeAdminRoutes registers the feature flag endpoints. The router must only admit admins.
+func InitializeAdminRoutes(router *gin.RouterGroup) {
+	router.GET("", ListFlags)
+	router.PUT("/:name", SetFlag)
+	router.DELETE("/:name", ResetFlag)
+}
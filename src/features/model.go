@@ -0,0 +1,24 @@
+package features
+
+import "time"
+
+// Flag sets who a feature is enabled for. An enabled feature is on for the listed users and for the given
+// percentage of the others.
+type Flag struct {
+	Name       string   `bson:"_id" json:"name"`
+	Enabled    bool     `bson:"enabled" json:"enabled"`
+	Percentage int      `bson:"percentage" json:"percentage"`
+	Users      []string `bson:"users" json:"users"`
+	// Source is "config" for a flag as configured, or "override" for one changed at runtime
+	Source string `bson:"-" json:"source"`
+	// UpdatedBy and UpdatedAt record the admin who last overrode the flag
+	UpdatedBy string     `bson:"updated_by" json:"updatedBy,omitempty"`
+	UpdatedAt *time.Time `bson:"updated_at" json:"updatedAt,omitempty"`
+}
+
+// FlagRequest overrides a flag
+type FlagRequest struct {
+	Enabled    bool     `json:"enabled"`
+	Percentage int      `json:"percentage" binding:"min=0,max=100"`
+	Users      []string `json:"users" binding:"max=1000"`
+}
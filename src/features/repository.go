@@ -0,0 +1,13 @@
+package features
+
+import "context"
+
+// Repository stores the flags overridden at runtime
+type Repository interface {
+	// List returns every overridden flag
+	List(ctx context.Context) ([]Flag, error)
+	// Save stores the flag's override, replacing any earlier one
+	Save(ctx context.Context, flag Flag) error
+	// Delete removes the flag's override, or returns store.ErrNotFound
+	Delete(ctx context.Context, name string) error
+}
@@ -0,0 +1,46 @@
+package features
+
+import (
+	"context"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps overrides in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu    sync.RWMutex
+	flags map[string]Flag
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{flags: map[string]Flag{}}
+}
+
+func (r *MemoryRepository) List(ctx context.Context) ([]Flag, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	flags := make([]Flag, 0, len(r.flags))
+	for _, flag := range r.flags {
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, flag Flag) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flags[flag.Name] = flag
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.flags[name]; !ok {
+		return store.ErrNotFound
+	}
+	delete(r.flags, name)
+	return nil
+}
@@ -0,0 +1,47 @@
+package features
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores overrides in the feature_flags collection
+type MongoRepository struct {
+	flags *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{flags: db.Collection("feature_flags")}
+}
+
+func (r *MongoRepository) List(ctx context.Context) ([]Flag, error) {
+	cursor, err := r.flags.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var flags []Flag
+	err = cursor.All(ctx, &flags)
+	return flags, err
+}
+
+func (r *MongoRepository) Save(ctx context.Context, flag Flag) error {
+	_, err := r.flags.ReplaceOne(ctx, bson.M{"_id": flag.Name}, flag, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, name string) error {
+	res, err := r.flags.DeleteOne(ctx, bson.M{"_id": name})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
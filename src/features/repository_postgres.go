@@ -0,0 +1,56 @@
+package features
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const flagColumns = "name, enabled, percentage, users, updated_by, updated_at"
+
+// PostgresRepository stores overrides in the feature_flags table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) List(ctx context.Context) ([]Flag, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+flagColumns+" FROM feature_flags")
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Flag, error) {
+		var flag Flag
+		err := row.Scan(&flag.Name, &flag.Enabled, &flag.Percentage, &flag.Users, &flag.UpdatedBy, &flag.UpdatedAt)
+		return flag, err
+	})
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, flag Flag) error {
+	users := flag.Users
+	if users == nil {
+		users = []string{}
+	}
+	_, err := r.pool.Exec(ctx, "INSERT INTO feature_flags ("+flagColumns+`) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (name) DO UPDATE SET enabled = $2, percentage = $3, users = $4, updated_by = $5, updated_at = $6`,
+		flag.Name, flag.Enabled, flag.Percentage, users, flag.UpdatedBy, flag.UpdatedAt)
+	return err
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, name string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM feature_flags WHERE name = $1", name)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package features
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) List(ctx context.Context) ([]Flag, error) {
+	return r.repos.For(ctx).List(ctx)
+}
+
+func (r *TenantRepository) Save(ctx context.Context, flag Flag) error {
+	return r.repos.For(ctx).Save(ctx, flag)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, name string) error {
+	return r.repos.For(ctx).Delete(ctx, name)
+}
@@ -0,0 +1,97 @@
+// Package gql serves a read-only GraphQL API over the profile modules, so clients can fetch a profile
+// with its skills, experience, qualifications, certificates, awards, languages, journal and recommendations in a
+// single request.
+package gql
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+
+	"profile-api/auth"
+	"profile-api/awards"
+	"profile-api/certificates"
+	"profile-api/experience"
+	"profile-api/journal"
+	"profile-api/languages"
+	"profile-api/profile"
+	"profile-api/qualifications"
+	"profile-api/recommendations"
+	"profile-api/skills"
+	"profile-api/utils"
+	"profile-api/visibility"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Repositories holds the storage the GraphQL resolvers read from. A repository other than Profiles is nil when
+// its module is disabled, and its fields then resolve to nothing.
+type Repositories struct {
+	Profiles        profile.Repository
+	Skills          skills.Repository
+	Experience      experience.Repository
+	Qualifications  qualifications.Repository
+	Certificates    certificates.Repository
+	Awards          awards.Repository
+	Languages       languages.Repository
+	Journals        journal.Repository
+	Recommendations recommendations.Repository
+}
+
+var repos Repositories
+
+// schema is parsed when the routes are initialized, failing startup on a schema that does not match the resolvers
+var schema *graphql.Schema
+
+// ServeGraphQL executes a GraphQL query.
+//
+//	@Summary		Execute a GraphQL query
+//	@Description	Executes a GraphQL query against the read-only schema served at /graphql/schema. Authentication is optional; fields are shown according to their visibility, as in the REST API, and the authenticated user also sees their private journal entries and the me field.
+//	@Tags			graphql
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		object	true	"GraphQL request with query, operationName and variables"
+//	@Success		200		{object}	object	"GraphQL response with data and errors"
+//	@Router			/graphql [post]
+func ServeGraphQL(c *gin.Context) {
+	viewer := visibility.ViewerOf(c)
+	ctx := context.WithValue(c.Request.Context(), loadersKey, newLoaders(viewer))
+	ctx = context.WithValue(ctx, viewerKey, viewer)
+	ctx, cancel := utils.WithOperationTimeout(ctx)
+	defer cancel()
+
+	handler := &relay.Handler{Schema: schema}
+	handler.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+}
+
+// GetSchema returns the GraphQL schema in SDL.
+//
+//	@Summary		Get the GraphQL schema
+//	@Description	Returns the GraphQL schema definition served at /graphql
+//	@Tags			graphql
+//	@Produce		plain
+//	@Success		200	{string}	string	"Schema definition"
+//	@Router			/graphql/schema [get]
+func GetSchema(c *gin.Context) {
+	c.String(http.StatusOK, schemaSDL)
+}
+
+// InitializeRoutes parses the schema and registers the GraphQL routes
+func InitializeRoutes(router gin.IRoutes, r Repositories, users auth.Repository) error {
+	repos = r
+
+	s, err := graphql.ParseSchema(schemaSDL, &resolver{}, graphql.UseFieldResolvers(), graphql.MaxDepth(8))
+	if err != nil {
+		return err
+	}
+	schema = s
+
+	router.POST("/graphql", auth.AuthMiddleware(users, false), ServeGraphQL)
+	router.GET("/graphql/schema", GetSchema)
+	return nil
+}
@@ -0,0 +1,93 @@
+package gql
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// batchWait is how long a loader collects keys from concurrently resolved fields before fetching them
+const batchWait = 2 * time.Millisecond
+
+// Loader batches the keys requested while resolving a query into a single fetch and caches the
+// results for the rest of the request, so lists of items do not query the database once per item
+type Loader[V any] struct {
+	fetch   func(ctx context.Context, keys []string) (map[string]V, error)
+	mu      sync.Mutex
+	results map[string]*loaderResult[V]
+	pending map[string]*loaderResult[V]
+	armed   bool
+}
+
+type loaderResult[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// NewLoader creates a loader fetching batches of keys with fetch. Keys missing from the returned map
+// load as the zero value.
+func NewLoader[V any](fetch func(ctx context.Context, keys []string) (map[string]V, error)) *Loader[V] {
+	return &Loader[V]{
+		fetch:   fetch,
+		results: map[string]*loaderResult[V]{},
+		pending: map[string]*loaderResult[V]{},
+	}
+}
+
+// Queue adds keys to the next batch without waiting for them. List resolvers queue the keys of all
+// their items, as the executor only resolves a few items at a time.
+func (l *Loader[V]) Queue(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		l.enqueue(key)
+	}
+}
+
+// Load returns the value for the key, fetching it with the other keys requested around the same time
+func (l *Loader[V]) Load(ctx context.Context, key string) (V, error) {
+	l.mu.Lock()
+	r := l.enqueue(key)
+	if len(l.pending) > 0 && !l.armed {
+		l.armed = true
+		time.AfterFunc(batchWait, func() { l.dispatch(ctx) })
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// enqueue returns the result for the key, adding it to the pending batch if it was never requested
+func (l *Loader[V]) enqueue(key string) *loaderResult[V] {
+	if r, ok := l.results[key]; ok {
+		return r
+	}
+	r := &loaderResult[V]{done: make(chan struct{})}
+	l.results[key] = r
+	l.pending[key] = r
+	return r
+}
+
+// dispatch fetches the pending batch and completes its results
+func (l *Loader[V]) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = map[string]*loaderResult[V]{}
+	l.armed = false
+	l.mu.Unlock()
+
+	values, err := l.fetch(ctx, slices.Sorted(maps.Keys(pending)))
+	for key, r := range pending {
+		r.value, r.err = values[key], err
+		close(r.done)
+	}
+}
@@ -0,0 +1,433 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"profile-api/awards"
+	"profile-api/certificates"
+	"profile-api/experience"
+	"profile-api/journal"
+	"profile-api/languages"
+	"profile-api/moderation"
+	"profile-api/privacy"
+	"profile-api/profile"
+	"profile-api/qualifications"
+	"profile-api/recommendations"
+	"profile-api/skills"
+	"profile-api/store"
+	"profile-api/visibility"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+const maxJournals = 100
+
+// loaders holds the per-request loaders, so each is only fetched once per batch of users
+type loaders struct {
+	profiles       *Loader[*profile.Profile]
+	skills         *Loader[[]skills.Skill]
+	experience     *Loader[[]experience.Experience]
+	qualifications *Loader[[]qualifications.Qualification]
+	certificates   *Loader[[]certificates.Certificate]
+	awards         *Loader[[]awards.Award]
+	languages      *Loader[[]languages.Language]
+}
+
+type contextKey int
+
+const (
+	loadersKey contextKey = iota
+	viewerKey
+)
+
+// newLoaders creates the loaders for a single request, loading documents stripped of the fields the viewer
+// may not see
+func newLoaders(viewer visibility.Viewer) *loaders {
+	l := &loaders{
+		profiles: NewLoader(func(ctx context.Context, userIDs []string) (map[string]*profile.Profile, error) {
+			items, err := repos.Profiles.GetMany(ctx, userIDs)
+			if err != nil {
+				return nil, err
+			}
+			if items, err = visibility.StripAll(viewer, items); err != nil {
+				return nil, err
+			}
+			byUser := make(map[string]*profile.Profile, len(items))
+			for i := range items {
+				ok, err := shown(ctx, viewer, items[i].UserID)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					byUser[items[i].UserID] = &items[i]
+				}
+			}
+			return byUser, nil
+		}),
+	}
+	if repos.Skills != nil {
+		l.skills = NewLoader(byUser(viewer, repos.Skills.ListByUsers))
+	}
+	if repos.Experience != nil {
+		l.experience = NewLoader(byUser(viewer, repos.Experience.ListByUsers))
+	}
+	if repos.Qualifications != nil {
+		l.qualifications = NewLoader(byUser(viewer, repos.Qualifications.ListByUsers))
+	}
+	if repos.Certificates != nil {
+		l.certificates = NewLoader(byUser(viewer, repos.Certificates.ListByUsers))
+	}
+	if repos.Awards != nil {
+		l.awards = NewLoader(byUser(viewer, repos.Awards.ListByUsers))
+	}
+	if repos.Languages != nil {
+		l.languages = NewLoader(byUser(viewer, repos.Languages.ListByUsers))
+	}
+	return l
+}
+
+// byUser adapts a repository method listing the items of several users into a loader fetch grouping them by
+// user, stripped of the fields the viewer may not see
+func byUser[T visibility.Document](viewer visibility.Viewer, list func(context.Context, []string) ([]T, error)) func(context.Context, []string) (map[string][]T, error) {
+	return func(ctx context.Context, userIDs []string) (map[string][]T, error) {
+		items, err := list(ctx, userIDs)
+		if err != nil {
+			return nil, err
+		}
+		if items, err = visibility.StripAll(viewer, items); err != nil {
+			return nil, err
+		}
+		grouped := map[string][]T{}
+		for _, item := range items {
+			grouped[item.Owner()] = append(grouped[item.Owner()], item)
+		}
+		return grouped, nil
+	}
+}
+
+// shown reports whether the user's profile data is served to the viewer. Profiles hidden by moderation and
+// restricted profiles are left out, as visitors on an allowlist are only let in over REST, unless the viewer
+// is the user or an admin.
+func shown(ctx context.Context, viewer visibility.Viewer, userID string) (bool, error) {
+	if viewer.Sees(userID, visibility.Private) {
+		return true, nil
+	}
+	hidden, err := moderation.ProfileHidden(ctx, userID)
+	if err != nil || hidden {
+		return false, err
+	}
+	restricted, err := privacy.Restricted(ctx, userID)
+	return !restricted, err
+}
+
+// shownEntries leaves out the journal entries of users whose profile data is not served to the viewer
+func shownEntries(ctx context.Context, entries []journal.JournalEntry) ([]journal.JournalEntry, error) {
+	viewer, _ := ctx.Value(viewerKey).(visibility.Viewer)
+	byUser := map[string]bool{}
+	visible := entries[:0]
+	for _, entry := range entries {
+		ok, seen := byUser[entry.UserID]
+		if !seen {
+			var err error
+			if ok, err = shown(ctx, viewer, entry.UserID); err != nil {
+				return nil, err
+			}
+			byUser[entry.UserID] = ok
+		}
+		if ok {
+			visible = append(visible, entry)
+		}
+	}
+	return visible, nil
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey).(*loaders)
+}
+
+// viewerFrom returns the ID of the authenticated user, or an empty string for anonymous requests
+func viewerFrom(ctx context.Context) string {
+	viewer, _ := ctx.Value(viewerKey).(visibility.Viewer)
+	return viewer.UserID
+}
+
+// resolver is the root query resolver
+type resolver struct{}
+
+func (r *resolver) Profile(ctx context.Context, args struct{ UserID graphql.ID }) (*profileResolver, error) {
+	return loadProfile(ctx, string(args.UserID))
+}
+
+func (r *resolver) Profiles(ctx context.Context, args struct{ UserIDs []graphql.ID }) ([]*profileResolver, error) {
+	l := loadersFrom(ctx)
+	for _, id := range args.UserIDs {
+		l.profiles.Queue(string(id))
+	}
+	profiles := []*profileResolver{}
+	for _, id := range args.UserIDs {
+		p, err := loadProfile(ctx, string(id))
+		if err != nil {
+			return nil, err
+		}
+		if p != nil {
+			profiles = append(profiles, p)
+		}
+	}
+	return profiles, nil
+}
+
+func (r *resolver) Me(ctx context.Context) (*profileResolver, error) {
+	viewer := viewerFrom(ctx)
+	if viewer == "" {
+		return nil, errors.New("not authenticated")
+	}
+	return loadProfile(ctx, viewer)
+}
+
+func (r *resolver) Journal(ctx context.Context, args struct{ JournalID graphql.ID }) (*journalResolver, error) {
+	if repos.Journals == nil {
+		return nil, nil
+	}
+	entry, err := repos.Journals.Get(ctx, string(args.JournalID))
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != journal.StatusPublic && entry.UserID != viewerFrom(ctx) {
+		return nil, nil
+	}
+	visible, err := shownEntries(ctx, []journal.JournalEntry{entry})
+	if err != nil || len(visible) == 0 {
+		return nil, err
+	}
+	return &journalResolver{entry}, nil
+}
+
+type journalFilterInput struct {
+	UserID      *graphql.ID
+	Category    *string
+	Subcategory *string
+	Topic       *string
+	Tag         *string
+}
+
+func (r *resolver) Journals(ctx context.Context, args struct {
+	Filter *journalFilterInput
+	First  int32
+}) ([]*journalResolver, error) {
+	if repos.Journals == nil {
+		return []*journalResolver{}, nil
+	}
+	filter := journal.Filter{Status: journal.StatusPublic}
+	if f := args.Filter; f != nil {
+		filter.UserID = deref((*string)(f.UserID))
+		filter.Category = deref(f.Category)
+		filter.Subcategory = deref(f.Subcategory)
+		filter.Topic = deref(f.Topic)
+		filter.Tag = deref(f.Tag)
+	}
+	entries, err := repos.Journals.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if entries, err = shownEntries(ctx, entries); err != nil {
+		return nil, err
+	}
+	sortNewestFirst(entries)
+	limit := min(max(int(args.First), 0), maxJournals)
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return journalResolvers(ctx, entries), nil
+}
+
+// loadProfile returns the user's profile through the request's loader, or nil if they have none
+func loadProfile(ctx context.Context, userID string) (*profileResolver, error) {
+	p, err := loadersFrom(ctx).profiles.Load(ctx, userID)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	return &profileResolver{*p}, nil
+}
+
+type profileResolver struct {
+	p profile.Profile
+}
+
+func (r *profileResolver) UserID() graphql.ID  { return graphql.ID(r.p.UserID) }
+func (r *profileResolver) Name() *string       { return r.p.Name }
+func (r *profileResolver) Number() *string     { return r.p.Number }
+func (r *profileResolver) Bio() *string        { return r.p.Bio }
+func (r *profileResolver) ProfileImg() *string { return r.p.ProfileImg }
+func (r *profileResolver) Interests() *string  { return r.p.Interests }
+func (r *profileResolver) Domain() *string     { return r.p.Domain }
+func (r *profileResolver) Email() *string      { return r.p.Email }
+func (r *profileResolver) UpdatedAt() *graphql.Time {
+	if r.p.UpdatedAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.p.UpdatedAt}
+}
+
+func (r *profileResolver) Skills(ctx context.Context) ([]skills.Skill, error) {
+	if loadersFrom(ctx).skills == nil {
+		return []skills.Skill{}, nil
+	}
+	return loadersFrom(ctx).skills.Load(ctx, r.p.UserID)
+}
+
+func (r *profileResolver) Experience(ctx context.Context) ([]experience.Experience, error) {
+	if loadersFrom(ctx).experience == nil {
+		return []experience.Experience{}, nil
+	}
+	return loadersFrom(ctx).experience.Load(ctx, r.p.UserID)
+}
+
+func (r *profileResolver) Qualifications(ctx context.Context) ([]qualifications.Qualification, error) {
+	if loadersFrom(ctx).qualifications == nil {
+		return []qualifications.Qualification{}, nil
+	}
+	return loadersFrom(ctx).qualifications.Load(ctx, r.p.UserID)
+}
+
+func (r *profileResolver) Certificates(ctx context.Context) ([]certificates.Certificate, error) {
+	if loadersFrom(ctx).certificates == nil {
+		return []certificates.Certificate{}, nil
+	}
+	return loadersFrom(ctx).certificates.Load(ctx, r.p.UserID)
+}
+
+func (r *profileResolver) Awards(ctx context.Context) ([]awards.Award, error) {
+	if loadersFrom(ctx).awards == nil {
+		return []awards.Award{}, nil
+	}
+	return loadersFrom(ctx).awards.Load(ctx, r.p.UserID)
+}
+
+func (r *profileResolver) Languages(ctx context.Context) ([]languages.Language, error) {
+	if loadersFrom(ctx).languages == nil {
+		return []languages.Language{}, nil
+	}
+	return loadersFrom(ctx).languages.Load(ctx, r.p.UserID)
+}
+
+func (r *profileResolver) Journal(ctx context.Context) ([]*journalResolver, error) {
+	if repos.Journals == nil {
+		return []*journalResolver{}, nil
+	}
+	filter := journal.Filter{UserID: r.p.UserID}
+	if viewerFrom(ctx) != r.p.UserID {
+		filter.Status = journal.StatusPublic
+	}
+	entries, err := repos.Journals.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	sortNewestFirst(entries)
+	return journalResolvers(ctx, entries), nil
+}
+
+func (r *profileResolver) Recommendations(ctx context.Context) ([]*recommendationResolver, error) {
+	if repos.Recommendations == nil {
+		return []*recommendationResolver{}, nil
+	}
+	list, err := repos.Recommendations.List(ctx, r.p.UserID, recommendations.StatusApproved)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*recommendationResolver, len(list))
+	for i := range list {
+		resolvers[i] = &recommendationResolver{list[i]}
+	}
+	return resolvers, nil
+}
+
+type recommendationResolver struct {
+	r recommendations.Recommendation
+}
+
+func (r *recommendationResolver) RecommendationID() graphql.ID { return graphql.ID(r.r.ID) }
+func (r *recommendationResolver) AuthorName() string           { return r.r.AuthorName }
+func (r *recommendationResolver) Relationship() string         { return r.r.Relationship }
+func (r *recommendationResolver) Content() string              { return r.r.Content }
+func (r *recommendationResolver) CreatedAt() graphql.Time      { return graphql.Time{Time: r.r.CreatedAt} }
+func (r *recommendationResolver) AuthorID() *graphql.ID {
+	if r.r.AuthorID == "" {
+		return nil
+	}
+	id := graphql.ID(r.r.AuthorID)
+	return &id
+}
+
+type journalResolver struct {
+	j journal.JournalEntry
+}
+
+// journalResolvers wraps the entries, queueing their authors so they are loaded in one batch
+func journalResolvers(ctx context.Context, entries []journal.JournalEntry) []*journalResolver {
+	l := loadersFrom(ctx)
+	resolvers := make([]*journalResolver, len(entries))
+	for i, entry := range entries {
+		l.profiles.Queue(entry.UserID)
+		resolvers[i] = &journalResolver{entry}
+	}
+	return resolvers
+}
+
+func (r *journalResolver) JournalID() graphql.ID   { return graphql.ID(r.j.JournalID) }
+func (r *journalResolver) UserID() graphql.ID      { return graphql.ID(r.j.UserID) }
+func (r *journalResolver) Version() int32          { return int32(r.j.Version) }
+func (r *journalResolver) Status() string          { return r.j.Status }
+func (r *journalResolver) Summary() string         { return r.j.Summary }
+func (r *journalResolver) Taxonomy() taxonomy      { return taxonomy{r.j.Taxonomy} }
+func (r *journalResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.j.CreatedAt} }
+func (r *journalResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.j.UpdatedAt} }
+func (r *journalResolver) Title() string           { return r.latest().Title }
+func (r *journalResolver) Content() string         { return r.latest().Content }
+
+func (r *journalResolver) Author(ctx context.Context) (*profileResolver, error) {
+	return loadProfile(ctx, r.j.UserID)
+}
+
+// latest returns the latest version of the entry, as the REST API shows to anonymous readers
+func (r *journalResolver) latest() journal.Entry {
+	if len(r.j.Entries) == 0 {
+		return journal.Entry{}
+	}
+	return r.j.Entries[len(r.j.Entries)-1]
+}
+
+// taxonomy resolves the lists of a journal entry's taxonomy, which are nil when unset
+type taxonomy struct {
+	t journal.Taxonomy
+}
+
+func (r taxonomy) Categories() []string    { return nonNil(r.t.Categories) }
+func (r taxonomy) Subcategories() []string { return nonNil(r.t.Subcategories) }
+func (r taxonomy) Topics() []string        { return nonNil(r.t.Topics) }
+func (r taxonomy) Tags() []string          { return nonNil(r.t.Tags) }
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// sortNewestFirst orders journal entries by when they were last updated, most recent first
+func sortNewestFirst(entries []journal.JournalEntry) {
+	slices.SortStableFunc(entries, func(a, b journal.JournalEntry) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+}
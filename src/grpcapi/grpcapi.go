@@ -0,0 +1,474 @@
+// Package grpcapi serves the core read APIs over gRPC for internal services, using the protobuf
+// definitions in profilev1. Callers authenticate with the same tokens as the REST API, sent in the
+// authorization metadata.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"profile-api/auth"
+	"profile-api/awards"
+	"profile-api/certificates"
+	"profile-api/experience"
+	"profile-api/grpcapi/profilev1"
+	"profile-api/journal"
+	"profile-api/languages"
+	"profile-api/moderation"
+	"profile-api/privacy"
+	"profile-api/profile"
+	"profile-api/qualifications"
+	"profile-api/recommendations"
+	"profile-api/skills"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+	"profile-api/visibility"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// Repositories holds the storage the gRPC service reads from. A repository other than Profiles is nil when its
+// module is disabled: aggregates leave its section out and the journal methods are unimplemented.
+type Repositories struct {
+	Profiles        profile.Repository
+	Skills          skills.Repository
+	Experience      experience.Repository
+	Qualifications  qualifications.Repository
+	Certificates    certificates.Repository
+	Awards          awards.Repository
+	Languages       languages.Repository
+	Recommendations recommendations.Repository
+	Journals        journal.Repository
+}
+
+var repos Repositories
+var users auth.Repository
+
+// errJournalDisabled is returned by the journal methods when the journal module is disabled
+var errJournalDisabled = status.Error(codes.Unimplemented, "the journal module is disabled")
+
+type contextKey int
+
+const viewerKey contextKey = iota
+
+// NewServer returns a gRPC server with the profile service registered
+func NewServer(r Repositories, u auth.Repository) *grpc.Server {
+	repos = r
+	users = u
+
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(logCalls, resolveTenant, authenticate))
+	profilev1.RegisterProfileServiceServer(s, &service{})
+	return s
+}
+
+// resolveTenant assigns calls to the tenant served on the requested authority in multi-tenant deployments.
+// Callers connecting through an internal address set the authority to the tenant's domain.
+func resolveTenant(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !tenant.Enabled() {
+		return handler(ctx, req)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	authority := md.Get(":authority")
+	if len(authority) == 0 {
+		return nil, status.Error(codes.NotFound, "unknown site")
+	}
+	t, ok := tenant.Resolve(authority[0])
+	if !ok {
+		return nil, status.Error(codes.NotFound, "unknown site")
+	}
+	return handler(tenant.WithID(ctx, t.ID), req)
+}
+
+// authenticate loads the user identified by the authorization metadata into the context. Calls without
+// a token are served as anonymous, but an invalid token is rejected so misconfigured callers notice.
+func authenticate(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return handler(ctx, req)
+	}
+
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+	authCtx, cancel := utils.WithOperationTimeout(ctx)
+	user, err := auth.Authenticate(authCtx, users, token)
+	cancel()
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "not authenticated")
+	}
+	return handler(context.WithValue(ctx, viewerKey, visibility.Viewer{UserID: user.ID, Admin: user.Admin}), req)
+}
+
+// logCalls logs each call with its outcome, as the access log does for HTTP requests
+func logCalls(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	code := status.Code(err)
+	level := slog.LevelInfo
+	if code == codes.Internal || code == codes.Unknown {
+		level = slog.LevelError
+	}
+	slog.Log(ctx, level, "gRPC call", "method", info.FullMethod, "code", code.String(), "duration", time.Since(start))
+	return resp, err
+}
+
+// viewerFrom returns the ID of the authenticated user, or an empty string for anonymous calls
+func viewerFrom(ctx context.Context) string {
+	return viewerOf(ctx).UserID
+}
+
+// viewerOf returns who the caller's documents are shown to, anonymous for unauthenticated calls
+func viewerOf(ctx context.Context) visibility.Viewer {
+	viewer, _ := ctx.Value(viewerKey).(visibility.Viewer)
+	return viewer
+}
+
+// toStatus converts a storage error into a gRPC status, hiding the details of unexpected errors
+func toStatus(err error, msg string) error {
+	if errors.Is(err, store.ErrNotFound) {
+		return status.Error(codes.NotFound, msg+": not found")
+	}
+	slog.Error(msg, "error", err)
+	return status.Error(codes.Internal, msg)
+}
+
+type service struct {
+	profilev1.UnimplementedProfileServiceServer
+}
+
+func (s *service) GetProfile(ctx context.Context, req *profilev1.GetProfileRequest) (*profilev1.ProfileAggregate, error) {
+	userID := req.GetUserId()
+	if userID == "" {
+		userID = viewerFrom(ctx)
+	}
+	if userID == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required for anonymous calls")
+	}
+
+	ctx, cancel := utils.WithOperationTimeout(ctx)
+	defer cancel()
+
+	// Hidden and restricted profiles are reported as not found, as visitors on an allowlist are only let in over REST
+	hidden, err := withheld(ctx, viewerOf(ctx), userID)
+	if err != nil {
+		return nil, toStatus(err, "could not retrieve privacy settings")
+	}
+	if hidden {
+		return nil, status.Error(codes.NotFound, "could not retrieve profile: not found")
+	}
+	p, err := repos.Profiles.Get(ctx, userID)
+	if err != nil {
+		return nil, toStatus(err, "could not retrieve profile")
+	}
+	var userSkills []skills.Skill
+	if repos.Skills != nil {
+		if userSkills, err = repos.Skills.List(ctx, userID); err != nil {
+			return nil, toStatus(err, "could not retrieve skills")
+		}
+	}
+	var userExperience []experience.Experience
+	if repos.Experience != nil {
+		if userExperience, err = repos.Experience.List(ctx, userID); err != nil {
+			return nil, toStatus(err, "could not retrieve experience")
+		}
+	}
+	var userQualifications []qualifications.Qualification
+	if repos.Qualifications != nil {
+		if userQualifications, err = repos.Qualifications.List(ctx, userID); err != nil {
+			return nil, toStatus(err, "could not retrieve qualifications")
+		}
+	}
+	var userCertificates []certificates.Certificate
+	if repos.Certificates != nil {
+		if userCertificates, err = repos.Certificates.List(ctx, userID); err != nil {
+			return nil, toStatus(err, "could not retrieve certificates")
+		}
+	}
+	var userAwards []awards.Award
+	if repos.Awards != nil {
+		if userAwards, err = repos.Awards.List(ctx, userID); err != nil {
+			return nil, toStatus(err, "could not retrieve awards")
+		}
+	}
+	var userLanguages []languages.Language
+	if repos.Languages != nil {
+		if userLanguages, err = repos.Languages.List(ctx, userID); err != nil {
+			return nil, toStatus(err, "could not retrieve languages")
+		}
+	}
+	var userRecommendations []recommendations.Recommendation
+	if repos.Recommendations != nil {
+		if userRecommendations, err = repos.Recommendations.List(ctx, userID, recommendations.StatusApproved); err != nil {
+			return nil, toStatus(err, "could not retrieve recommendations")
+		}
+	}
+
+	// Fields are left out according to their visibility, as in the REST API
+	viewer := viewerOf(ctx)
+	p, err = visibility.Strip(viewer, p)
+	if err == nil {
+		userSkills, err = visibility.StripAll(viewer, userSkills)
+	}
+	if err == nil {
+		userExperience, err = visibility.StripAll(viewer, userExperience)
+	}
+	if err == nil {
+		userQualifications, err = visibility.StripAll(viewer, userQualifications)
+	}
+	if err == nil {
+		userCertificates, err = visibility.StripAll(viewer, userCertificates)
+	}
+	if err == nil {
+		userAwards, err = visibility.StripAll(viewer, userAwards)
+	}
+	if err == nil {
+		userLanguages, err = visibility.StripAll(viewer, userLanguages)
+	}
+	if err != nil {
+		return nil, toStatus(err, "could not apply visibility")
+	}
+
+	aggregate := &profilev1.ProfileAggregate{Profile: profileMessage(p)}
+	for _, item := range userSkills {
+		aggregate.Skills = append(aggregate.Skills, &profilev1.Skill{
+			SkillId:          item.SkillID,
+			Name:             item.Name,
+			ProficiencyLevel: item.ProficiencyLevel,
+			StartedAt:        item.StartedAt,
+			LastUsed:         item.LastUsed,
+			Description:      item.Description,
+		})
+	}
+	for _, item := range userExperience {
+		aggregate.Experience = append(aggregate.Experience, &profilev1.Experience{
+			ExperienceId: item.ExperienceID,
+			Company:      item.Company,
+			Position:     item.Position,
+			Start:        item.Start,
+			End:          item.End,
+			Description:  item.Description,
+			Notes:        item.Notes,
+		})
+	}
+	for _, item := range userQualifications {
+		aggregate.Qualifications = append(aggregate.Qualifications, &profilev1.Qualification{
+			QualificationId: item.QualificationID,
+			Title:           item.Title,
+			Institution:     item.Institution,
+			Start:           item.Start,
+			End:             item.End,
+			Description:     item.Description,
+		})
+	}
+	for _, item := range userCertificates {
+		aggregate.Certificates = append(aggregate.Certificates, &profilev1.Certificate{
+			CertificateId: item.CertificateID,
+			Title:         item.Title,
+			Institution:   item.Institution,
+			Start:         item.Start,
+			End:           item.End,
+			Description:   item.Description,
+		})
+	}
+	for _, item := range userAwards {
+		aggregate.Awards = append(aggregate.Awards, &profilev1.Award{
+			AwardId:     item.AwardID,
+			Title:       item.Title,
+			Issuer:      item.Issuer,
+			Date:        item.Date,
+			Description: item.Description,
+		})
+	}
+	for _, item := range userLanguages {
+		aggregate.Languages = append(aggregate.Languages, &profilev1.Language{
+			LanguageId:  item.LanguageID,
+			Name:        item.Name,
+			Code:        item.Code,
+			Proficiency: item.Proficiency,
+		})
+	}
+	// The email of external referees is only shown to the recommended user, through the REST API
+	for _, item := range userRecommendations {
+		aggregate.Recommendations = append(aggregate.Recommendations, &profilev1.Recommendation{
+			RecommendationId: item.ID,
+			AuthorId:         item.AuthorID,
+			AuthorName:       item.AuthorName,
+			Relationship:     item.Relationship,
+			Content:          item.Content,
+			CreatedAt:        timestamppb.New(item.CreatedAt),
+		})
+	}
+	return aggregate, nil
+}
+
+func (s *service) ListJournal(ctx context.Context, req *profilev1.ListJournalRequest) (*profilev1.ListJournalResponse, error) {
+	if repos.Journals == nil {
+		return nil, errJournalDisabled
+	}
+	filter := journal.Filter{
+		UserID:      req.GetUserId(),
+		Category:    req.GetCategory(),
+		Subcategory: req.GetSubcategory(),
+		Topic:       req.GetTopic(),
+		Tag:         req.GetTag(),
+	}
+	// Owners see their private entries, everyone else only public ones
+	if filter.UserID == "" || filter.UserID != viewerFrom(ctx) {
+		filter.Status = journal.StatusPublic
+	}
+
+	ctx, cancel := utils.WithOperationTimeout(ctx)
+	defer cancel()
+	entries, err := repos.Journals.List(ctx, filter)
+	if err != nil {
+		return nil, toStatus(err, "could not retrieve journal entries")
+	}
+	if entries, err = withoutWithheld(ctx, entries); err != nil {
+		return nil, toStatus(err, "could not retrieve privacy settings")
+	}
+	return journalResponse(entries, req.GetLimit()), nil
+}
+
+func (s *service) SearchJournal(ctx context.Context, req *profilev1.SearchJournalRequest) (*profilev1.ListJournalResponse, error) {
+	if repos.Journals == nil {
+		return nil, errJournalDisabled
+	}
+	query := strings.ToLower(strings.TrimSpace(req.GetQuery()))
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+
+	ctx, cancel := utils.WithOperationTimeout(ctx)
+	defer cancel()
+	entries, err := repos.Journals.List(ctx, journal.Filter{Status: journal.StatusPublic})
+	if err != nil {
+		return nil, toStatus(err, "could not retrieve journal entries")
+	}
+	if entries, err = withoutWithheld(ctx, entries); err != nil {
+		return nil, toStatus(err, "could not retrieve privacy settings")
+	}
+
+	entries = slices.DeleteFunc(entries, func(entry journal.JournalEntry) bool {
+		latest := latestEntry(entry)
+		return !strings.Contains(strings.ToLower(latest.Title), query) &&
+			!strings.Contains(strings.ToLower(latest.Content), query) &&
+			!strings.Contains(strings.ToLower(entry.Summary), query)
+	})
+	return journalResponse(entries, req.GetLimit()), nil
+}
+
+// withheld reports whether a moderator hid the user's profile or the user restricted it to their allowlist,
+// and the viewer is neither them nor an admin
+func withheld(ctx context.Context, viewer visibility.Viewer, userID string) (bool, error) {
+	if viewer.Sees(userID, visibility.Private) {
+		return false, nil
+	}
+	hidden, err := moderation.ProfileHidden(ctx, userID)
+	if err != nil || hidden {
+		return hidden, err
+	}
+	return privacy.Restricted(ctx, userID)
+}
+
+// withoutWithheld leaves out the entries of users whose profile is withheld from the caller
+func withoutWithheld(ctx context.Context, entries []journal.JournalEntry) ([]journal.JournalEntry, error) {
+	viewer := viewerOf(ctx)
+	hidden := map[string]bool{}
+	var err error
+	entries = slices.DeleteFunc(entries, func(entry journal.JournalEntry) bool {
+		h, seen := hidden[entry.UserID]
+		if !seen && err == nil {
+			h, err = withheld(ctx, viewer, entry.UserID)
+			hidden[entry.UserID] = h
+		}
+		return h
+	})
+	return entries, err
+}
+
+// profileMessage converts a profile stripped of the fields the caller may not see
+func profileMessage(p profile.Profile) *profilev1.Profile {
+	msg := &profilev1.Profile{
+		UserId:     p.UserID,
+		Name:       deref(p.Name),
+		Number:     deref(p.Number),
+		Bio:        deref(p.Bio),
+		ProfileImg: deref(p.ProfileImg),
+		Interests:  deref(p.Interests),
+		Domain:     deref(p.Domain),
+		Email:      deref(p.Email),
+	}
+	if p.UpdatedAt != nil {
+		msg.UpdatedAt = timestamppb.New(*p.UpdatedAt)
+	}
+	return msg
+}
+
+// journalResponse converts the entries most recently updated first, keeping at most limit of them
+func journalResponse(entries []journal.JournalEntry, limit int32) *profilev1.ListJournalResponse {
+	slices.SortStableFunc(entries, func(a, b journal.JournalEntry) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+	n := int(limit)
+	if n <= 0 {
+		n = defaultLimit
+	}
+	n = min(n, maxLimit)
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+
+	resp := &profilev1.ListJournalResponse{}
+	for _, entry := range entries {
+		latest := latestEntry(entry)
+		resp.Entries = append(resp.Entries, &profilev1.JournalEntry{
+			JournalId: entry.JournalID,
+			UserId:    entry.UserID,
+			Version:   int32(entry.Version),
+			Status:    entry.Status,
+			Title:     latest.Title,
+			Content:   latest.Content,
+			Summary:   entry.Summary,
+			Taxonomy: &profilev1.Taxonomy{
+				Categories:    entry.Taxonomy.Categories,
+				Subcategories: entry.Taxonomy.Subcategories,
+				Topics:        entry.Taxonomy.Topics,
+				Tags:          entry.Taxonomy.Tags,
+			},
+			CreatedAt: timestamppb.New(entry.CreatedAt),
+			UpdatedAt: timestamppb.New(entry.UpdatedAt),
+		})
+	}
+	return resp
+}
+
+// latestEntry returns the latest version of the journal entry, as the REST API shows to anonymous readers
+func latestEntry(entry journal.JournalEntry) journal.Entry {
+	if len(entry.Entries) == 0 {
+		return journal.Entry{}
+	}
+	return entry.Entries[len(entry.Entries)-1]
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,1431 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: profilev1/profile.proto
+
+// Read-only access to profiles and journals for internal services.
+// Regenerate the Go code with `task proto` after editing this file.
+
+package profilev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetProfileRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The user whose profile to return. Defaults to the authenticated user.
+	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProfileRequest) Reset() {
+	*x = GetProfileRequest{}
+	mi := &file_profilev1_profile_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProfileRequest) ProtoMessage() {}
+
+func (x *GetProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetProfileRequest) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetProfileRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ProfileAggregate struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Profile        *Profile               `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	Skills         []*Skill               `protobuf:"bytes,2,rep,name=skills,proto3" json:"skills,omitempty"`
+	Experience     []*Experience          `protobuf:"bytes,3,rep,name=experience,proto3" json:"experience,omitempty"`
+	Qualifications []*Qualification       `protobuf:"bytes,4,rep,name=qualifications,proto3" json:"qualifications,omitempty"`
+	Certificates   []*Certificate         `protobuf:"bytes,5,rep,name=certificates,proto3" json:"certificates,omitempty"`
+	Awards         []*Award               `protobuf:"bytes,6,rep,name=awards,proto3" json:"awards,omitempty"`
+	Languages      []*Language            `protobuf:"bytes,7,rep,name=languages,proto3" json:"languages,omitempty"`
+	// Only the recommendations the user approved, newest first
+	Recommendations []*Recommendation `protobuf:"bytes,8,rep,name=recommendations,proto3" json:"recommendations,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ProfileAggregate) Reset() {
+	*x = ProfileAggregate{}
+	mi := &file_profilev1_profile_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileAggregate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileAggregate) ProtoMessage() {}
+
+func (x *ProfileAggregate) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileAggregate.ProtoReflect.Descriptor instead.
+func (*ProfileAggregate) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProfileAggregate) GetProfile() *Profile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *ProfileAggregate) GetSkills() []*Skill {
+	if x != nil {
+		return x.Skills
+	}
+	return nil
+}
+
+func (x *ProfileAggregate) GetExperience() []*Experience {
+	if x != nil {
+		return x.Experience
+	}
+	return nil
+}
+
+func (x *ProfileAggregate) GetQualifications() []*Qualification {
+	if x != nil {
+		return x.Qualifications
+	}
+	return nil
+}
+
+func (x *ProfileAggregate) GetCertificates() []*Certificate {
+	if x != nil {
+		return x.Certificates
+	}
+	return nil
+}
+
+func (x *ProfileAggregate) GetAwards() []*Award {
+	if x != nil {
+		return x.Awards
+	}
+	return nil
+}
+
+func (x *ProfileAggregate) GetLanguages() []*Language {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *ProfileAggregate) GetRecommendations() []*Recommendation {
+	if x != nil {
+		return x.Recommendations
+	}
+	return nil
+}
+
+type Profile struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name   string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Only returned to the profile's owner
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Number        string                 `protobuf:"bytes,4,opt,name=number,proto3" json:"number,omitempty"`
+	Bio           string                 `protobuf:"bytes,5,opt,name=bio,proto3" json:"bio,omitempty"`
+	ProfileImg    string                 `protobuf:"bytes,6,opt,name=profile_img,json=profileImg,proto3" json:"profile_img,omitempty"`
+	Interests     string                 `protobuf:"bytes,7,opt,name=interests,proto3" json:"interests,omitempty"`
+	Domain        string                 `protobuf:"bytes,8,opt,name=domain,proto3" json:"domain,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Profile) Reset() {
+	*x = Profile{}
+	mi := &file_profilev1_profile_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Profile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Profile) ProtoMessage() {}
+
+func (x *Profile) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Profile.ProtoReflect.Descriptor instead.
+func (*Profile) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Profile) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Profile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Profile) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Profile) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *Profile) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
+}
+
+func (x *Profile) GetProfileImg() string {
+	if x != nil {
+		return x.ProfileImg
+	}
+	return ""
+}
+
+func (x *Profile) GetInterests() string {
+	if x != nil {
+		return x.Interests
+	}
+	return ""
+}
+
+func (x *Profile) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Profile) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Skill struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SkillId          string                 `protobuf:"bytes,1,opt,name=skill_id,json=skillId,proto3" json:"skill_id,omitempty"`
+	Name             string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ProficiencyLevel string                 `protobuf:"bytes,3,opt,name=proficiency_level,json=proficiencyLevel,proto3" json:"proficiency_level,omitempty"`
+	StartedAt        string                 `protobuf:"bytes,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	LastUsed         string                 `protobuf:"bytes,5,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`
+	Description      string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Skill) Reset() {
+	*x = Skill{}
+	mi := &file_profilev1_profile_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Skill) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Skill) ProtoMessage() {}
+
+func (x *Skill) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Skill.ProtoReflect.Descriptor instead.
+func (*Skill) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Skill) GetSkillId() string {
+	if x != nil {
+		return x.SkillId
+	}
+	return ""
+}
+
+func (x *Skill) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Skill) GetProficiencyLevel() string {
+	if x != nil {
+		return x.ProficiencyLevel
+	}
+	return ""
+}
+
+func (x *Skill) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *Skill) GetLastUsed() string {
+	if x != nil {
+		return x.LastUsed
+	}
+	return ""
+}
+
+func (x *Skill) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type Experience struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExperienceId  string                 `protobuf:"bytes,1,opt,name=experience_id,json=experienceId,proto3" json:"experience_id,omitempty"`
+	Company       string                 `protobuf:"bytes,2,opt,name=company,proto3" json:"company,omitempty"`
+	Position      string                 `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	Start         string                 `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`
+	End           string                 `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	Notes         string                 `protobuf:"bytes,7,opt,name=notes,proto3" json:"notes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Experience) Reset() {
+	*x = Experience{}
+	mi := &file_profilev1_profile_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Experience) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Experience) ProtoMessage() {}
+
+func (x *Experience) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Experience.ProtoReflect.Descriptor instead.
+func (*Experience) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Experience) GetExperienceId() string {
+	if x != nil {
+		return x.ExperienceId
+	}
+	return ""
+}
+
+func (x *Experience) GetCompany() string {
+	if x != nil {
+		return x.Company
+	}
+	return ""
+}
+
+func (x *Experience) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *Experience) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *Experience) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *Experience) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Experience) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+type Qualification struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	QualificationId string                 `protobuf:"bytes,1,opt,name=qualification_id,json=qualificationId,proto3" json:"qualification_id,omitempty"`
+	Title           string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Institution     string                 `protobuf:"bytes,3,opt,name=institution,proto3" json:"institution,omitempty"`
+	Start           string                 `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`
+	End             string                 `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	Description     string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Qualification) Reset() {
+	*x = Qualification{}
+	mi := &file_profilev1_profile_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Qualification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Qualification) ProtoMessage() {}
+
+func (x *Qualification) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Qualification.ProtoReflect.Descriptor instead.
+func (*Qualification) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Qualification) GetQualificationId() string {
+	if x != nil {
+		return x.QualificationId
+	}
+	return ""
+}
+
+func (x *Qualification) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Qualification) GetInstitution() string {
+	if x != nil {
+		return x.Institution
+	}
+	return ""
+}
+
+func (x *Qualification) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *Qualification) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *Qualification) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type Certificate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CertificateId string                 `protobuf:"bytes,1,opt,name=certificate_id,json=certificateId,proto3" json:"certificate_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Institution   string                 `protobuf:"bytes,3,opt,name=institution,proto3" json:"institution,omitempty"`
+	Start         string                 `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`
+	End           string                 `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Certificate) Reset() {
+	*x = Certificate{}
+	mi := &file_profilev1_profile_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Certificate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Certificate) ProtoMessage() {}
+
+func (x *Certificate) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Certificate.ProtoReflect.Descriptor instead.
+func (*Certificate) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Certificate) GetCertificateId() string {
+	if x != nil {
+		return x.CertificateId
+	}
+	return ""
+}
+
+func (x *Certificate) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Certificate) GetInstitution() string {
+	if x != nil {
+		return x.Institution
+	}
+	return ""
+}
+
+func (x *Certificate) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *Certificate) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *Certificate) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type Award struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AwardId       string                 `protobuf:"bytes,1,opt,name=award_id,json=awardId,proto3" json:"award_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Issuer        string                 `protobuf:"bytes,3,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	Date          string                 `protobuf:"bytes,4,opt,name=date,proto3" json:"date,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Award) Reset() {
+	*x = Award{}
+	mi := &file_profilev1_profile_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Award) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Award) ProtoMessage() {}
+
+func (x *Award) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Award.ProtoReflect.Descriptor instead.
+func (*Award) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Award) GetAwardId() string {
+	if x != nil {
+		return x.AwardId
+	}
+	return ""
+}
+
+func (x *Award) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Award) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *Award) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *Award) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type Language struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	LanguageId string                 `protobuf:"bytes,1,opt,name=language_id,json=languageId,proto3" json:"language_id,omitempty"`
+	Name       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// BCP 47 tag of the language, such as en or pt-BR
+	Code string `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	// CEFR level, A1 to C2, or native
+	Proficiency   string `protobuf:"bytes,4,opt,name=proficiency,proto3" json:"proficiency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Language) Reset() {
+	*x = Language{}
+	mi := &file_profilev1_profile_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Language) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Language) ProtoMessage() {}
+
+func (x *Language) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Language.ProtoReflect.Descriptor instead.
+func (*Language) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Language) GetLanguageId() string {
+	if x != nil {
+		return x.LanguageId
+	}
+	return ""
+}
+
+func (x *Language) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Language) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Language) GetProficiency() string {
+	if x != nil {
+		return x.Proficiency
+	}
+	return ""
+}
+
+type Recommendation struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RecommendationId string                 `protobuf:"bytes,1,opt,name=recommendation_id,json=recommendationId,proto3" json:"recommendation_id,omitempty"`
+	// The user who wrote the recommendation, empty for external referees
+	AuthorId      string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	AuthorName    string                 `protobuf:"bytes,3,opt,name=author_name,json=authorName,proto3" json:"author_name,omitempty"`
+	Relationship  string                 `protobuf:"bytes,4,opt,name=relationship,proto3" json:"relationship,omitempty"`
+	Content       string                 `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Recommendation) Reset() {
+	*x = Recommendation{}
+	mi := &file_profilev1_profile_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Recommendation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Recommendation) ProtoMessage() {}
+
+func (x *Recommendation) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Recommendation.ProtoReflect.Descriptor instead.
+func (*Recommendation) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Recommendation) GetRecommendationId() string {
+	if x != nil {
+		return x.RecommendationId
+	}
+	return ""
+}
+
+func (x *Recommendation) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *Recommendation) GetAuthorName() string {
+	if x != nil {
+		return x.AuthorName
+	}
+	return ""
+}
+
+func (x *Recommendation) GetRelationship() string {
+	if x != nil {
+		return x.Relationship
+	}
+	return ""
+}
+
+func (x *Recommendation) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Recommendation) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListJournalRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Restricts the feed to one user's entries. The owner also sees their private entries.
+	UserId      string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Category    string `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	Subcategory string `protobuf:"bytes,3,opt,name=subcategory,proto3" json:"subcategory,omitempty"`
+	Topic       string `protobuf:"bytes,4,opt,name=topic,proto3" json:"topic,omitempty"`
+	Tag         string `protobuf:"bytes,5,opt,name=tag,proto3" json:"tag,omitempty"`
+	// Maximum number of entries to return, 20 when unset and at most 100
+	Limit         int32 `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJournalRequest) Reset() {
+	*x = ListJournalRequest{}
+	mi := &file_profilev1_profile_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJournalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJournalRequest) ProtoMessage() {}
+
+func (x *ListJournalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJournalRequest.ProtoReflect.Descriptor instead.
+func (*ListJournalRequest) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListJournalRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListJournalRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *ListJournalRequest) GetSubcategory() string {
+	if x != nil {
+		return x.Subcategory
+	}
+	return ""
+}
+
+func (x *ListJournalRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *ListJournalRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ListJournalRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchJournalRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Maximum number of entries to return, 20 when unset and at most 100
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchJournalRequest) Reset() {
+	*x = SearchJournalRequest{}
+	mi := &file_profilev1_profile_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchJournalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchJournalRequest) ProtoMessage() {}
+
+func (x *SearchJournalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchJournalRequest.ProtoReflect.Descriptor instead.
+func (*SearchJournalRequest) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SearchJournalRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchJournalRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListJournalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*JournalEntry        `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJournalResponse) Reset() {
+	*x = ListJournalResponse{}
+	mi := &file_profilev1_profile_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJournalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJournalResponse) ProtoMessage() {}
+
+func (x *ListJournalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJournalResponse.ProtoReflect.Descriptor instead.
+func (*ListJournalResponse) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListJournalResponse) GetEntries() []*JournalEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type JournalEntry struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	JournalId string                 `protobuf:"bytes,1,opt,name=journal_id,json=journalId,proto3" json:"journal_id,omitempty"`
+	UserId    string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Version   int32                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Status    string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	// Title of the latest version
+	Title string `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	// Content of the latest version
+	Content       string                 `protobuf:"bytes,6,opt,name=content,proto3" json:"content,omitempty"`
+	Summary       string                 `protobuf:"bytes,7,opt,name=summary,proto3" json:"summary,omitempty"`
+	Taxonomy      *Taxonomy              `protobuf:"bytes,8,opt,name=taxonomy,proto3" json:"taxonomy,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JournalEntry) Reset() {
+	*x = JournalEntry{}
+	mi := &file_profilev1_profile_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JournalEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JournalEntry) ProtoMessage() {}
+
+func (x *JournalEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JournalEntry.ProtoReflect.Descriptor instead.
+func (*JournalEntry) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *JournalEntry) GetJournalId() string {
+	if x != nil {
+		return x.JournalId
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *JournalEntry) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetTaxonomy() *Taxonomy {
+	if x != nil {
+		return x.Taxonomy
+	}
+	return nil
+}
+
+func (x *JournalEntry) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *JournalEntry) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Taxonomy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []string               `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	Subcategories []string               `protobuf:"bytes,2,rep,name=subcategories,proto3" json:"subcategories,omitempty"`
+	Topics        []string               `protobuf:"bytes,3,rep,name=topics,proto3" json:"topics,omitempty"`
+	Tags          []string               `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Taxonomy) Reset() {
+	*x = Taxonomy{}
+	mi := &file_profilev1_profile_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Taxonomy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Taxonomy) ProtoMessage() {}
+
+func (x *Taxonomy) ProtoReflect() protoreflect.Message {
+	mi := &file_profilev1_profile_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Taxonomy.ProtoReflect.Descriptor instead.
+func (*Taxonomy) Descriptor() ([]byte, []int) {
+	return file_profilev1_profile_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Taxonomy) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *Taxonomy) GetSubcategories() []string {
+	if x != nil {
+		return x.Subcategories
+	}
+	return nil
+}
+
+func (x *Taxonomy) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+func (x *Taxonomy) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+var File_profilev1_profile_proto protoreflect.FileDescriptor
+
+const file_profilev1_profile_proto_rawDesc = "" +
+	"\n" +
+	"\x17profilev1/profile.proto\x12\n" +
+	"profile.v1\x1a\x1fgoogle/protobuf/timestamp.proto\",\n" +
+	"\x11GetProfileRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xc9\x03\n" +
+	"\x10ProfileAggregate\x12-\n" +
+	"\aprofile\x18\x01 \x01(\v2\x13.profile.v1.ProfileR\aprofile\x12)\n" +
+	"\x06skills\x18\x02 \x03(\v2\x11.profile.v1.SkillR\x06skills\x126\n" +
+	"\n" +
+	"experience\x18\x03 \x03(\v2\x16.profile.v1.ExperienceR\n" +
+	"experience\x12A\n" +
+	"\x0equalifications\x18\x04 \x03(\v2\x19.profile.v1.QualificationR\x0equalifications\x12;\n" +
+	"\fcertificates\x18\x05 \x03(\v2\x17.profile.v1.CertificateR\fcertificates\x12)\n" +
+	"\x06awards\x18\x06 \x03(\v2\x11.profile.v1.AwardR\x06awards\x122\n" +
+	"\tlanguages\x18\a \x03(\v2\x14.profile.v1.LanguageR\tlanguages\x12D\n" +
+	"\x0frecommendations\x18\b \x03(\v2\x1a.profile.v1.RecommendationR\x0frecommendations\"\x88\x02\n" +
+	"\aProfile\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x16\n" +
+	"\x06number\x18\x04 \x01(\tR\x06number\x12\x10\n" +
+	"\x03bio\x18\x05 \x01(\tR\x03bio\x12\x1f\n" +
+	"\vprofile_img\x18\x06 \x01(\tR\n" +
+	"profileImg\x12\x1c\n" +
+	"\tinterests\x18\a \x01(\tR\tinterests\x12\x16\n" +
+	"\x06domain\x18\b \x01(\tR\x06domain\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xc1\x01\n" +
+	"\x05Skill\x12\x19\n" +
+	"\bskill_id\x18\x01 \x01(\tR\askillId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12+\n" +
+	"\x11proficiency_level\x18\x03 \x01(\tR\x10proficiencyLevel\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x04 \x01(\tR\tstartedAt\x12\x1b\n" +
+	"\tlast_used\x18\x05 \x01(\tR\blastUsed\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\"\xc7\x01\n" +
+	"\n" +
+	"Experience\x12#\n" +
+	"\rexperience_id\x18\x01 \x01(\tR\fexperienceId\x12\x18\n" +
+	"\acompany\x18\x02 \x01(\tR\acompany\x12\x1a\n" +
+	"\bposition\x18\x03 \x01(\tR\bposition\x12\x14\n" +
+	"\x05start\x18\x04 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x05 \x01(\tR\x03end\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05notes\x18\a \x01(\tR\x05notes\"\xbc\x01\n" +
+	"\rQualification\x12)\n" +
+	"\x10qualification_id\x18\x01 \x01(\tR\x0fqualificationId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vinstitution\x18\x03 \x01(\tR\vinstitution\x12\x14\n" +
+	"\x05start\x18\x04 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x05 \x01(\tR\x03end\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\"\xb6\x01\n" +
+	"\vCertificate\x12%\n" +
+	"\x0ecertificate_id\x18\x01 \x01(\tR\rcertificateId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vinstitution\x18\x03 \x01(\tR\vinstitution\x12\x14\n" +
+	"\x05start\x18\x04 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x05 \x01(\tR\x03end\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\"\x86\x01\n" +
+	"\x05Award\x12\x19\n" +
+	"\baward_id\x18\x01 \x01(\tR\aawardId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
+	"\x06issuer\x18\x03 \x01(\tR\x06issuer\x12\x12\n" +
+	"\x04date\x18\x04 \x01(\tR\x04date\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\"u\n" +
+	"\bLanguage\x12\x1f\n" +
+	"\vlanguage_id\x18\x01 \x01(\tR\n" +
+	"languageId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12 \n" +
+	"\vproficiency\x18\x04 \x01(\tR\vproficiency\"\xf4\x01\n" +
+	"\x0eRecommendation\x12+\n" +
+	"\x11recommendation_id\x18\x01 \x01(\tR\x10recommendationId\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\x12\x1f\n" +
+	"\vauthor_name\x18\x03 \x01(\tR\n" +
+	"authorName\x12\"\n" +
+	"\frelationship\x18\x04 \x01(\tR\frelationship\x12\x18\n" +
+	"\acontent\x18\x05 \x01(\tR\acontent\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xa9\x01\n" +
+	"\x12ListJournalRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x12 \n" +
+	"\vsubcategory\x18\x03 \x01(\tR\vsubcategory\x12\x14\n" +
+	"\x05topic\x18\x04 \x01(\tR\x05topic\x12\x10\n" +
+	"\x03tag\x18\x05 \x01(\tR\x03tag\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\"B\n" +
+	"\x14SearchJournalRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"I\n" +
+	"\x13ListJournalResponse\x122\n" +
+	"\aentries\x18\x01 \x03(\v2\x18.profile.v1.JournalEntryR\aentries\"\xea\x02\n" +
+	"\fJournalEntry\x12\x1d\n" +
+	"\n" +
+	"journal_id\x18\x01 \x01(\tR\tjournalId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x06 \x01(\tR\acontent\x12\x18\n" +
+	"\asummary\x18\a \x01(\tR\asummary\x120\n" +
+	"\btaxonomy\x18\b \x01(\v2\x14.profile.v1.TaxonomyR\btaxonomy\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"|\n" +
+	"\bTaxonomy\x12\x1e\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\tR\n" +
+	"categories\x12$\n" +
+	"\rsubcategories\x18\x02 \x03(\tR\rsubcategories\x12\x16\n" +
+	"\x06topics\x18\x03 \x03(\tR\x06topics\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags2\xff\x01\n" +
+	"\x0eProfileService\x12I\n" +
+	"\n" +
+	"GetProfile\x12\x1d.profile.v1.GetProfileRequest\x1a\x1c.profile.v1.ProfileAggregate\x12N\n" +
+	"\vListJournal\x12\x1e.profile.v1.ListJournalRequest\x1a\x1f.profile.v1.ListJournalResponse\x12R\n" +
+	"\rSearchJournal\x12 .profile.v1.SearchJournalRequest\x1a\x1f.profile.v1.ListJournalResponseB\x1fZ\x1dprofile-api/grpcapi/profilev1b\x06proto3"
+
+var (
+	file_profilev1_profile_proto_rawDescOnce sync.Once
+	file_profilev1_profile_proto_rawDescData []byte
+)
+
+func file_profilev1_profile_proto_rawDescGZIP() []byte {
+	file_profilev1_profile_proto_rawDescOnce.Do(func() {
+		file_profilev1_profile_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_profilev1_profile_proto_rawDesc), len(file_profilev1_profile_proto_rawDesc)))
+	})
+	return file_profilev1_profile_proto_rawDescData
+}
+
+var file_profilev1_profile_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_profilev1_profile_proto_goTypes = []any{
+	(*GetProfileRequest)(nil),     // 0: profile.v1.GetProfileRequest
+	(*ProfileAggregate)(nil),      // 1: profile.v1.ProfileAggregate
+	(*Profile)(nil),               // 2: profile.v1.Profile
+	(*Skill)(nil),                 // 3: profile.v1.Skill
+	(*Experience)(nil),            // 4: profile.v1.Experience
+	(*Qualification)(nil),         // 5: profile.v1.Qualification
+	(*Certificate)(nil),           // 6: profile.v1.Certificate
+	(*Award)(nil),                 // 7: profile.v1.Award
+	(*Language)(nil),              // 8: profile.v1.Language
+	(*Recommendation)(nil),        // 9: profile.v1.Recommendation
+	(*ListJournalRequest)(nil),    // 10: profile.v1.ListJournalRequest
+	(*SearchJournalRequest)(nil),  // 11: profile.v1.SearchJournalRequest
+	(*ListJournalResponse)(nil),   // 12: profile.v1.ListJournalResponse
+	(*JournalEntry)(nil),          // 13: profile.v1.JournalEntry
+	(*Taxonomy)(nil),              // 14: profile.v1.Taxonomy
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+}
+var file_profilev1_profile_proto_depIdxs = []int32{
+	2,  // 0: profile.v1.ProfileAggregate.profile:type_name -> profile.v1.Profile
+	3,  // 1: profile.v1.ProfileAggregate.skills:type_name -> profile.v1.Skill
+	4,  // 2: profile.v1.ProfileAggregate.experience:type_name -> profile.v1.Experience
+	5,  // 3: profile.v1.ProfileAggregate.qualifications:type_name -> profile.v1.Qualification
+	6,  // 4: profile.v1.ProfileAggregate.certificates:type_name -> profile.v1.Certificate
+	7,  // 5: profile.v1.ProfileAggregate.awards:type_name -> profile.v1.Award
+	8,  // 6: profile.v1.ProfileAggregate.languages:type_name -> profile.v1.Language
+	9,  // 7: profile.v1.ProfileAggregate.recommendations:type_name -> profile.v1.Recommendation
+	15, // 8: profile.v1.Profile.updated_at:type_name -> google.protobuf.Timestamp
+	15, // 9: profile.v1.Recommendation.created_at:type_name -> google.protobuf.Timestamp
+	13, // 10: profile.v1.ListJournalResponse.entries:type_name -> profile.v1.JournalEntry
+	14, // 11: profile.v1.JournalEntry.taxonomy:type_name -> profile.v1.Taxonomy
+	15, // 12: profile.v1.JournalEntry.created_at:type_name -> google.protobuf.Timestamp
+	15, // 13: profile.v1.JournalEntry.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 14: profile.v1.ProfileService.GetProfile:input_type -> profile.v1.GetProfileRequest
+	10, // 15: profile.v1.ProfileService.ListJournal:input_type -> profile.v1.ListJournalRequest
+	11, // 16: profile.v1.ProfileService.SearchJournal:input_type -> profile.v1.SearchJournalRequest
+	1,  // 17: profile.v1.ProfileService.GetProfile:output_type -> profile.v1.ProfileAggregate
+	12, // 18: profile.v1.ProfileService.ListJournal:output_type -> profile.v1.ListJournalResponse
+	12, // 19: profile.v1.ProfileService.SearchJournal:output_type -> profile.v1.ListJournalResponse
+	17, // [17:20] is the sub-list for method output_type
+	14, // [14:17] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_profilev1_profile_proto_init() }
+func file_profilev1_profile_proto_init() {
+	if File_profilev1_profile_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_profilev1_profile_proto_rawDesc), len(file_profilev1_profile_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_profilev1_profile_proto_goTypes,
+		DependencyIndexes: file_profilev1_profile_proto_depIdxs,
+		MessageInfos:      file_profilev1_profile_proto_msgTypes,
+	}.Build()
+	File_profilev1_profile_proto = out.File
+	file_profilev1_profile_proto_goTypes = nil
+	file_profilev1_profile_proto_depIdxs = nil
+}
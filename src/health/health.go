@@ -0,0 +1,99 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"profile-api/cache"
+	"profile-api/profile"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Paths of the health endpoints, excluded from access logging
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+const checkTimeout = 2 * time.Second
+
+var (
+	client *mongo.Client
+	pool   *pgxpool.Pool
+	redis  *cache.Cache
+)
+
+// HealthResponse reports the overall status and the result of each dependency check
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Liveness reports that the process is up.
+//
+//	@Summary		Check the server is up
+//	@Description	Reports that the process is up, for liveness probes. Dependencies are not checked.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	HealthResponse
+//	@Router			/healthz [get]
+func Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+// Readiness reports whether the server's dependencies are reachable.
+//
+//	@Summary		Check the server is ready
+//	@Description	Reports whether the configured storage, cache and image store are reachable, for readiness probes.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	HealthResponse	"Every dependency is reachable"
+//	@Failure		503	{object}	HealthResponse	"A dependency is unreachable"
+//	@Router			/readyz [get]
+func Readiness(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkTimeout)
+	defer cancel()
+
+	resp := HealthResponse{Status: "ok", Checks: map[string]string{}}
+	check := func(name string, err error) {
+		if err != nil {
+			resp.Status = "unavailable"
+			resp.Checks[name] = err.Error()
+			return
+		}
+		resp.Checks[name] = "ok"
+	}
+
+	if client != nil {
+		check("mongo", client.Ping(ctx, nil))
+	}
+	if pool != nil {
+		check("postgres", pool.Ping(ctx))
+	}
+	if redis != nil {
+		check("redis", redis.Ping(ctx))
+	}
+	if store := profile.GetImageStore(ctx); store != nil {
+		check("image_store", store.Ping(ctx))
+	}
+
+	status := http.StatusOK
+	if resp.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, resp)
+}
+
+// InitializeRoutes registers the health endpoints on the root router, outside any authenticated group.
+// Each dependency check is skipped when its client is nil, so only the configured storage and cache are checked.
+func InitializeRoutes(router gin.IRoutes, db *mongo.Client, pg *pgxpool.Pool, rc *cache.Cache) {
+	client = db
+	pool = pg
+	redis = rc
+	router.GET(LivenessPath, Liveness)
+	router.GET(ReadinessPath, Readiness)
+}
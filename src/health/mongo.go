@@ -0,0 +1,63 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"profile-api/apierror"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongoDown is set while the background check can't reach MongoDB
+var mongoDown atomic.Bool
+
+// retryAfter is how long clients are told to wait while MongoDB is down, the interval between checks
+var retryAfter = 10 * time.Second
+
+// MonitorMongo pings MongoDB every interval until ctx is cancelled, logging when it goes away and comes
+// back. The driver reconnects by itself once the server is reachable again, so the check only tracks
+// whether it is.
+func MonitorMongo(ctx context.Context, db *mongo.Client, interval time.Duration) {
+	retryAfter = interval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		pingCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		err := db.Ping(pingCtx, nil)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		switch {
+		case err != nil && !mongoDown.Swap(true):
+			slog.Error("MongoDB is unreachable, answering requests with 503 until it is back", "error", err)
+		case err == nil && mongoDown.Swap(false):
+			slog.Info("MongoDB is reachable again")
+		}
+	}
+}
+
+// RequireMongo answers requests with 503 Service Unavailable while MongoDB is down, instead of each
+// waiting for the driver to give up on the server. The health endpoints are registered before it, so the
+// probes still report the outage.
+func RequireMongo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if mongoDown.Load() {
+			c.Header("Retry-After", strconv.Itoa(int(max(retryAfter, time.Second).Seconds())))
+			apierror.Abort(c, apierror.New(http.StatusServiceUnavailable, apierror.CodeServiceUnavailable, "The database is unavailable, try again later"))
+			return
+		}
+		c.Next()
+	}
+}
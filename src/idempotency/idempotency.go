@@ -0,0 +1,190 @@
+// Package idempotency makes POST requests safe to retry. A client sending the same Idempotency-Key header
+// with a retried request, such as after a double click or a dropped connection, gets the stored response
+// to the first request instead of creating the resource twice.
+//
+// Keys belong to the caller, identified by their session token or, for anonymous requests, their address,
+// and are kept for the configured retention. Reusing a key for a different request is rejected, as is a
+// retry arriving while the first request is still being processed. Responses to requests that failed
+// with a server error are not stored, so those can be retried, and neither are responses setting cookies,
+// such as logins, as they carry credentials.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/config"
+	"profile-api/dryrun"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headers of requests made with a key and of replayed responses
+const (
+	Header         = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// maxKeyLength is the longest key accepted
+const maxKeyLength = 255
+
+var repo Repository
+var settings = config.IdempotencyConfig{Retention: config.Duration(24 * time.Hour)}
+
+// Configure sets where keys are stored and how long they are kept. Until it is called keys are ignored.
+func Configure(r Repository, cfg config.IdempotencyConfig) {
+	repo = r
+	settings = cfg
+}
+
+// recorder keeps a copy of the response body as it is written
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Middleware replays the stored response to POST requests repeating an earlier request's Idempotency-Key.
+// It must run after the request is assigned to its tenant. Dry runs are not recorded, so the key is still
+// free for the write they checked.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(Header)
+		if repo == nil || c.Request.Method != http.MethodPost || key == "" || dryrun.Active(c.Request.Context()) {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			apierror.Abort(c, apierror.BadRequest("Idempotency-Key must be at most 255 characters"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apierror.Abort(c, apierror.BadRequest("Could not read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		record := Record{
+			ID:          recordID(c, key),
+			Fingerprint: fingerprint(c.Request, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(settings.Retention.Std()),
+		}
+		ctx, cancel := utils.DBContext(c)
+		stored, claimed, err := repo.Claim(ctx, record)
+		cancel()
+		if err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not check Idempotency-Key"))
+			return
+		}
+		if !claimed {
+			replay(c, stored, record.Fingerprint)
+			return
+		}
+
+		w := &recorder{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		// Write a recorded error now rather than in the error middleware, so it is stored with the response
+		if len(c.Errors) > 0 && w.Size() <= 0 {
+			apierror.Write(c, c.Errors.Last().Err)
+		}
+		finish(c.Request.Context(), record.ID, w)
+	}
+}
+
+// replay responds with the stored response to the request that first used the key
+func replay(c *gin.Context, stored Record, fingerprint string) {
+	switch {
+	case stored.Fingerprint != fingerprint:
+		apierror.Abort(c, apierror.Unprocessable("Idempotency-Key was already used for a different request"))
+	case stored.Response == nil:
+		apierror.Abort(c, apierror.Conflict("A request with this Idempotency-Key is still being processed"))
+	default:
+		resp := stored.Response
+		if resp.Location != "" {
+			c.Header("Location", resp.Location)
+		}
+		c.Header(ReplayedHeader, "true")
+		c.Data(resp.Status, resp.ContentType, resp.Body)
+		c.Abort()
+	}
+}
+
+// finish stores the response for replaying, or releases the key when the response must not be replayed
+func finish(ctx context.Context, id string, w *recorder) {
+	// The response is stored even when the client has gone away, as it may well retry
+	ctx, cancel := utils.WithOperationTimeout(context.WithoutCancel(ctx))
+	defer cancel()
+
+	status := w.Status()
+	if status >= http.StatusInternalServerError || w.Header().Get("Set-Cookie") != "" {
+		if err := repo.Release(ctx, id); err != nil {
+			slog.ErrorContext(ctx, "Could not release Idempotency-Key", "error", err)
+		}
+		return
+	}
+	err := repo.Complete(ctx, id, Response{
+		Status:      status,
+		ContentType: w.Header().Get("Content-Type"),
+		Location:    w.Header().Get("Location"),
+		Body:        w.body.Bytes(),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Could not store response for Idempotency-Key", "error", err)
+	}
+}
+
+// recordID combines the key with the caller's identity, so callers cannot replay each other's responses. Callers
+// sending an API key are told apart by it, as no-code tools share the addresses they send requests from.
+func recordID(c *gin.Context, key string) string {
+	caller := "address:" + c.ClientIP()
+	if token, err := c.Cookie("token"); err == nil {
+		caller = "token:" + token
+	} else if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		caller = "api-key:" + apiKey
+	} else if authorization := c.GetHeader("Authorization"); authorization != "" {
+		caller = "authorization:" + authorization
+	}
+	sum := sha256.Sum256([]byte(caller + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// fingerprint identifies the request by its method, path, query and body
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\x00")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Purge removes expired keys and their responses
+func Purge(ctx context.Context) error {
+	removed, err := repo.Purge(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	slog.Info("Purged idempotency keys", "removed", removed)
+	return nil
+}
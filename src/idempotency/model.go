@@ -0,0 +1,23 @@
+package idempotency
+
+import "time"
+
+// Record is a request made with an Idempotency-Key and, once it has finished, the response to replay
+type Record struct {
+	// ID identifies the key among the keys of the same caller, see recordID
+	ID string `bson:"_id"`
+	// Fingerprint identifies the request, so the key cannot be reused for a different one
+	Fingerprint string `bson:"fingerprint"`
+	// Response is nil while the request is still being processed
+	Response  *Response `bson:"response,omitempty"`
+	CreatedAt time.Time `bson:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// Response is a stored response
+type Response struct {
+	Status      int    `bson:"status"`
+	ContentType string `bson:"content_type"`
+	Location    string `bson:"location,omitempty"`
+	Body        []byte `bson:"body"`
+}
@@ -0,0 +1,19 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores the requests made with an Idempotency-Key
+type Repository interface {
+	// Claim stores the record unless an unexpired record with the same ID exists, returning the stored
+	// record and whether it is the given one
+	Claim(ctx context.Context, record Record) (Record, bool, error)
+	// Complete stores the response to the claimed record's request
+	Complete(ctx context.Context, id string, resp Response) error
+	// Release removes a record whose request failed, so it can be retried
+	Release(ctx context.Context, id string) error
+	// Purge removes records that expired before the given time, returning how many were removed
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,58 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRepository keeps records in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{records: map[string]Record{}}
+}
+
+func (r *MemoryRepository) Claim(ctx context.Context, record Record) (Record, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.records[record.ID]; ok && existing.ExpiresAt.After(time.Now()) {
+		return existing, false, nil
+	}
+	r.records[record.ID] = record
+	return record, true, nil
+}
+
+func (r *MemoryRepository) Complete(ctx context.Context, id string, resp Response) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if record, ok := r.records[id]; ok {
+		record.Response = &resp
+		r.records[id] = record
+	}
+	return nil
+}
+
+func (r *MemoryRepository) Release(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.records, id)
+	return nil
+}
+
+func (r *MemoryRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var removed int64
+	for id, record := range r.records {
+		if record.ExpiresAt.Before(before) {
+			delete(r.records, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,58 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoRepository stores records in the idempotency_keys collection
+type MongoRepository struct {
+	records *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{records: db.Collection("idempotency_keys")}
+}
+
+func (r *MongoRepository) Claim(ctx context.Context, record Record) (Record, bool, error) {
+	// An expired record waiting to be purged no longer holds the key
+	_, err := r.records.DeleteOne(ctx, bson.M{"_id": record.ID, "expires_at": bson.M{"$lte": time.Now()}})
+	if err != nil {
+		return Record{}, false, err
+	}
+	_, err = r.records.InsertOne(ctx, record)
+	if err == nil {
+		return record, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return Record{}, false, err
+	}
+
+	var existing Record
+	err = r.records.FindOne(ctx, bson.M{"_id": record.ID}).Decode(&existing)
+	return existing, false, store.MongoErr(err)
+}
+
+func (r *MongoRepository) Complete(ctx context.Context, id string, resp Response) error {
+	_, err := r.records.UpdateByID(ctx, id, bson.M{"$set": bson.M{"response": resp}})
+	return err
+}
+
+func (r *MongoRepository) Release(ctx context.Context, id string) error {
+	_, err := r.records.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *MongoRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.records.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
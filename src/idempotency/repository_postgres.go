@@ -0,0 +1,79 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const recordColumns = "id, fingerprint, status, content_type, location, body, created_at, expires_at"
+
+// PostgresRepository stores records in the idempotency_keys table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Claim(ctx context.Context, record Record) (Record, bool, error) {
+	// An expired record waiting to be purged no longer holds the key
+	_, err := r.pool.Exec(ctx, "DELETE FROM idempotency_keys WHERE id = $1 AND expires_at <= now()", record.ID)
+	if err != nil {
+		return Record{}, false, err
+	}
+	tag, err := r.pool.Exec(ctx, `INSERT INTO idempotency_keys (id, fingerprint, created_at, expires_at)
+		VALUES ($1, $2, $3, $4) ON CONFLICT (id) DO NOTHING`,
+		record.ID, record.Fingerprint, record.CreatedAt, record.ExpiresAt)
+	if err != nil {
+		return Record{}, false, err
+	}
+	if tag.RowsAffected() == 1 {
+		return record, true, nil
+	}
+
+	rows, err := r.pool.Query(ctx, "SELECT "+recordColumns+" FROM idempotency_keys WHERE id = $1", record.ID)
+	if err != nil {
+		return Record{}, false, err
+	}
+	existing, err := pgx.CollectExactlyOneRow(rows, scanRecord)
+	return existing, false, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Complete(ctx context.Context, id string, resp Response) error {
+	_, err := r.pool.Exec(ctx, "UPDATE idempotency_keys SET status = $2, content_type = $3, location = $4, body = $5 WHERE id = $1",
+		id, resp.Status, resp.ContentType, resp.Location, resp.Body)
+	return err
+}
+
+func (r *PostgresRepository) Release(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM idempotency_keys WHERE id = $1", id)
+	return err
+}
+
+func (r *PostgresRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM idempotency_keys WHERE expires_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanRecord(row pgx.CollectableRow) (Record, error) {
+	var record Record
+	var status *int
+	var resp Response
+	err := row.Scan(&record.ID, &record.Fingerprint, &status, &resp.ContentType, &resp.Location, &resp.Body,
+		&record.CreatedAt, &record.ExpiresAt)
+	if status != nil {
+		resp.Status = *status
+		record.Response = &resp
+	}
+	return record, err
+}
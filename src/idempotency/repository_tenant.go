@@ -0,0 +1,34 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Claim(ctx context.Context, record Record) (Record, bool, error) {
+	return r.repos.For(ctx).Claim(ctx, record)
+}
+
+func (r *TenantRepository) Complete(ctx context.Context, id string, resp Response) error {
+	return r.repos.For(ctx).Complete(ctx, id, resp)
+}
+
+func (r *TenantRepository) Release(ctx context.Context, id string) error {
+	return r.repos.For(ctx).Release(ctx, id)
+}
+
+func (r *TenantRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return r.repos.For(ctx).Purge(ctx, before)
+}
@@ -0,0 +1,58 @@
+// Package images processes uploaded images before they are stored, removing the metadata cameras and
+// phones embed in them, such as the location a photo was taken at and the device that took it.
+//
+// Metadata is removed from JPEG, PNG and WebP images without re-encoding them. Photos taken sideways are
+// only upright through their EXIF orientation, which is lost along with the rest of the metadata, so
+// JPEG and PNG images with one are rotated to match before it is removed when apply-orientation is on.
+// Other formats are stored as they are.
+//
+// JPEG and PNG images are also converted to WebP, and optionally AVIF, by the encoders configured, which
+// are installed alongside the server (cwebp and avifenc by default). The variants are stored next to the
+// original, which is kept, and the image-serving endpoint returns AVIF or WebP to clients accepting them.
+package images
+
+import (
+	"errors"
+	"net/http"
+
+	"profile-api/config"
+)
+
+// ErrInvalid is returned for images that cannot be read as the format they claim to be
+var ErrInvalid = errors.New("invalid image")
+
+var settings = config.ImageStoreConfig{StripMetadata: true, ApplyOrientation: true}
+
+// Configure sets how uploaded images are processed and which variants are made of them
+func Configure(cfg config.ImageStoreConfig) {
+	settings = cfg
+	configureVariants(cfg.Variants)
+}
+
+// Process returns the image as it should be stored, without its metadata unless strip-metadata is off
+func Process(data []byte) ([]byte, error) {
+	if !settings.StripMetadata {
+		return data, nil
+	}
+	return StripMetadata(data, settings.ApplyOrientation)
+}
+
+// Extension returns the file extension of the image's format, detected from its content rather than the name
+// it was uploaded with, or ErrInvalid when the data is not an image in a format browsers show
+func Extension(data []byte) (string, error) {
+	// Sniffing does not know AVIF, whose ISO media file brand follows the length of its first box
+	if len(data) >= 12 && string(data[4:8]) == "ftyp" && (string(data[8:12]) == "avif" || string(data[8:12]) == "avis") {
+		return ".avif", nil
+	}
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return ".jpg", nil
+	case "image/png":
+		return ".png", nil
+	case "image/gif":
+		return ".gif", nil
+	case "image/webp":
+		return ".webp", nil
+	}
+	return "", ErrInvalid
+}
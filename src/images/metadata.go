@@ -0,0 +1,166 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+var (
+	jpegStart    = []byte{0xFF, 0xD8}
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+	iccProfile   = []byte("ICC_PROFILE\x00")
+	exifHeader   = []byte("Exif\x00\x00")
+)
+
+// JPEG markers of the segments kept when removing metadata. APP2 segments are only kept when they hold
+// the ICC colour profile.
+const (
+	markerAPP0  = 0xE0
+	markerAPP1  = 0xE1
+	markerAPP2  = 0xE2
+	markerAPP14 = 0xEE
+	markerSOS   = 0xDA
+	markerEOI   = 0xD9
+)
+
+// pngMetadata are the PNG chunks holding metadata rather than pixels or colour information
+var pngMetadata = map[string]bool{"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true}
+
+// StripMetadata removes the metadata of a JPEG, PNG or WebP image, returning other images unchanged. With
+// applyOrientation, images whose EXIF orientation is not upright are first rotated to match.
+func StripMetadata(data []byte, applyOrientation bool) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, jpegStart):
+		return stripJPEG(data, applyOrientation)
+	case bytes.HasPrefix(data, pngSignature):
+		return stripPNG(data, applyOrientation)
+	case len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return stripWebP(data)
+	default:
+		return data, nil
+	}
+}
+
+// stripJPEG keeps the JFIF header, colour profile and the image data, dropping the EXIF, XMP and IPTC
+// segments, comments, and other application segments such as maker notes
+func stripJPEG(data []byte, applyOrientation bool) ([]byte, error) {
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(jpegStart)
+	orientation := 1
+	pos := len(jpegStart)
+	for {
+		if pos+2 > len(data) || data[pos] != 0xFF {
+			return nil, fmt.Errorf("%w: malformed JPEG", ErrInvalid)
+		}
+		marker := data[pos+1]
+		if marker == 0xFF {
+			// Fill byte before a marker
+			pos++
+			continue
+		}
+		if marker == markerSOS || marker == markerEOI {
+			// The image data follows, to the end of the file
+			out.Write(data[pos:])
+			break
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			// Markers without a segment
+			out.Write(data[pos : pos+2])
+			pos += 2
+			continue
+		}
+		if pos+4 > len(data) {
+			return nil, fmt.Errorf("%w: malformed JPEG", ErrInvalid)
+		}
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+		if end > len(data) || end < pos+4 {
+			return nil, fmt.Errorf("%w: malformed JPEG", ErrInvalid)
+		}
+		payload := data[pos+4 : end]
+		switch {
+		case marker == markerAPP1 && bytes.HasPrefix(payload, exifHeader):
+			orientation = exifOrientation(payload[len(exifHeader):])
+		case marker == markerAPP0 || marker == markerAPP14:
+			out.Write(data[pos:end])
+		case marker == markerAPP2 && bytes.HasPrefix(payload, iccProfile):
+			out.Write(data[pos:end])
+		case marker > markerAPP0 && marker <= 0xEF, marker == 0xFE:
+			// Other application segments and comments
+		default:
+			out.Write(data[pos:end])
+		}
+		pos = end
+	}
+
+	if applyOrientation && orientation != 1 {
+		return orientJPEG(out.Bytes(), orientation)
+	}
+	return out.Bytes(), nil
+}
+
+// stripPNG drops the EXIF and text chunks and the modification time
+func stripPNG(data []byte, applyOrientation bool) ([]byte, error) {
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(pngSignature)
+	orientation := 1
+	for pos := len(pngSignature); pos < len(data); {
+		if pos+12 > len(data) {
+			return nil, fmt.Errorf("%w: malformed PNG", ErrInvalid)
+		}
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		end := pos + 12 + length
+		if length < 0 || end > len(data) || end < pos {
+			return nil, fmt.Errorf("%w: malformed PNG", ErrInvalid)
+		}
+		chunk := string(data[pos+4 : pos+8])
+		if chunk == "eXIf" {
+			orientation = exifOrientation(data[pos+8 : pos+8+length])
+		}
+		if !pngMetadata[chunk] {
+			out.Write(data[pos:end])
+		}
+		pos = end
+		if chunk == "IEND" {
+			break
+		}
+	}
+
+	if applyOrientation && orientation != 1 {
+		return orientPNG(out.Bytes(), orientation)
+	}
+	return out.Bytes(), nil
+}
+
+// stripWebP drops the EXIF and XMP chunks, clearing the flags announcing them. WebP images are not
+// rotated, as there is no encoder to write them again.
+func stripWebP(data []byte) ([]byte, error) {
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:12])
+	for pos := 12; pos < len(data); {
+		if pos+8 > len(data) {
+			return nil, fmt.Errorf("%w: malformed WebP", ErrInvalid)
+		}
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		end := pos + 8 + size + size%2
+		if size < 0 || end > len(data) || end < pos {
+			return nil, fmt.Errorf("%w: malformed WebP", ErrInvalid)
+		}
+		switch chunk := string(data[pos : pos+4]); chunk {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			start := out.Len()
+			out.Write(data[pos:end])
+			if size > 0 {
+				// Clear the EXIF and XMP flags
+				out.Bytes()[start+8] &^= 0x08 | 0x04
+			}
+		default:
+			out.Write(data[pos:end])
+		}
+		pos = end
+	}
+	stripped := out.Bytes()
+	binary.LittleEndian.PutUint32(stripped[4:], uint32(len(stripped)-8))
+	return stripped, nil
+}
@@ -0,0 +1,138 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+// jpegQuality is the quality JPEG images are encoded at again after being rotated
+const jpegQuality = 90
+
+// maxPixels bounds the size of the images rotated, which are decoded in memory at four bytes a pixel
+const maxPixels = 50_000_000
+
+// orientationTag is the EXIF tag holding the orientation
+const orientationTag = 0x0112
+
+// exifOrientation returns the orientation held by the EXIF data, a TIFF structure, or 1 for upright when
+// it holds none
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == orientationTag {
+			if orientation := int(order.Uint16(tiff[entry+8:])); orientation >= 1 && orientation <= 8 {
+				return orientation
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// orientJPEG rotates the JPEG image to match its EXIF orientation
+func orientJPEG(data []byte, orientation int) ([]byte, error) {
+	if err := checkSize(jpeg.DecodeConfig(bytes.NewReader(data))); err != nil {
+		return nil, err
+	}
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, orient(img, orientation), &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// orientPNG rotates the PNG image to match its EXIF orientation
+func orientPNG(data []byte, orientation int) ([]byte, error) {
+	if err := checkSize(png.DecodeConfig(bytes.NewReader(data))); err != nil {
+		return nil, err
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	var out bytes.Buffer
+	if err := png.Encode(&out, orient(img, orientation)); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// checkSize returns an error for images too large to rotate
+func checkSize(cfg image.Config, err error) error {
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return fmt.Errorf("%w: images over %d pixels cannot be rotated", ErrInvalid, maxPixels)
+	}
+	return nil
+}
+
+// orient returns the image transformed as the EXIF orientation says it is to be displayed
+func orient(img image.Image, orientation int) image.Image {
+	b := img.Bounds()
+	src := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		// Orientations 5 to 8 turn the image a quarter, swapping its width and height
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			default:
+				dx, dy = x, y
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], src.Pix[src.PixOffset(x, y):][:4])
+		}
+	}
+	return dst
+}
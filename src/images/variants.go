@@ -0,0 +1,159 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"profile-api/config"
+)
+
+// Formats variants are made in, in the order they are preferred when a client accepts several
+const (
+	FormatAVIF = "avif"
+	FormatWebP = "webp"
+)
+
+// Formats holds every format variants are made in, most preferred first
+var Formats = []string{FormatAVIF, FormatWebP}
+
+// Variant is an uploaded image converted to a smaller format, stored alongside the original under the
+// original's name followed by the format's extension
+type Variant struct {
+	Format string
+	Data   []byte
+}
+
+// Extension returns the extension appended to the original's name to name the variant
+func (v Variant) Extension() string {
+	return "." + v.Format
+}
+
+// ContentType returns the media type of the variant's format
+func ContentType(format string) string {
+	return mime.TypeByExtension("." + format)
+}
+
+// encoders holds the command converting images to each enabled format
+var encoders = map[string][]string{}
+
+// encodeTimeout bounds each conversion
+var encodeTimeout time.Duration
+
+// configureVariants enables the formats whose encoder is installed. Formats are left out with a warning
+// rather than failing startup, as the originals are served without them.
+func configureVariants(cfg config.ImageVariantsConfig) {
+	encoders = map[string][]string{}
+	commands := map[string]string{FormatWebP: cfg.WebPCommand, FormatAVIF: cfg.AVIFCommand}
+	for _, format := range cfg.Formats {
+		command := strings.Fields(commands[format])
+		if len(command) == 0 {
+			continue
+		}
+		if _, err := exec.LookPath(command[0]); err != nil {
+			slog.Warn("Image variants disabled, the encoder is not installed", "format", format, "command", command[0])
+			continue
+		}
+		encoders[format] = command
+	}
+	encodeTimeout = cfg.Timeout.Std()
+}
+
+// Convert returns the variants of the image in every enabled format. Only JPEG and PNG images are
+// converted. Images that fail to convert are logged and left without the variant, as the original is
+// still served.
+func Convert(ctx context.Context, data []byte) []Variant {
+	if len(encoders) == 0 || !(bytes.HasPrefix(data, jpegStart) || bytes.HasPrefix(data, pngSignature)) {
+		return nil
+	}
+	var variants []Variant
+	for _, format := range Formats {
+		command, ok := encoders[format]
+		if !ok {
+			continue
+		}
+		encoded, err := encode(ctx, command, data, format)
+		if err != nil {
+			slog.WarnContext(ctx, "Could not convert image", "format", format, "error", err)
+			continue
+		}
+		// A variant no smaller than the original saves nothing
+		if len(encoded) < len(data) {
+			variants = append(variants, Variant{Format: format, Data: encoded})
+		}
+	}
+	return variants
+}
+
+// encode runs the encoder command, replacing {in} and {out} in its arguments with the paths of the image
+// and of the file to write the variant to
+func encode(ctx context.Context, command []string, data []byte, format string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "image-variant-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	in := filepath.Join(dir, "in")
+	if bytes.HasPrefix(data, jpegStart) {
+		in += ".jpg"
+	} else {
+		in += ".png"
+	}
+	out := filepath.Join(dir, "out."+format)
+	if err := os.WriteFile(in, data, 0o600); err != nil {
+		return nil, err
+	}
+
+	args := make([]string, len(command)-1)
+	for i, arg := range command[1:] {
+		args[i] = strings.NewReplacer("{in}", in, "{out}", out).Replace(arg)
+	}
+	ctx, cancel := context.WithTimeout(ctx, encodeTimeout)
+	defer cancel()
+	if output, err := exec.CommandContext(ctx, command[0], args...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", command[0], err, strings.TrimSpace(string(output)))
+	}
+	return os.ReadFile(out)
+}
+
+// Accepted returns the enabled variant formats the Accept header allows, most preferred first
+func Accepted(accept string) []string {
+	var formats []string
+	for _, format := range Formats {
+		if _, ok := encoders[format]; ok && accepts(accept, ContentType(format)) {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
+
+// VariantsEnabled reports whether variants are made in any format, so responses vary by Accept
+func VariantsEnabled() bool {
+	return len(encoders) > 0
+}
+
+// accepts reports whether the Accept header names the media type without a zero quality. Wildcards are
+// not taken as accepting a variant, as browsers send */* for images they may not be able to show.
+func accepts(accept, mediaType string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(name), mediaType) {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if key == "q" && strings.Trim(value, "0.") == "" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,395 @@
+// Package inbox keeps the messages visitors send users through the contact form of their profile as contact
+// requests, which users read, archive and reply to by email from their inbox. Each request is scored for
+// spam when it arrives: likely spam is filed away without notifying the user, and removed after a while.
+package inbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/email"
+	"profile-api/notifications"
+	"profile-api/privacy"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const replyEmailTemplate = "contact_reply"
+
+var repo Repository
+var users auth.Repository
+var settings config.InboxConfig
+
+// statuses lists every status a contact request may have
+var statuses = []string{StatusNew, StatusRead, StatusArchived, StatusSpam}
+
+// Configure sets where contact requests are stored and how they are scored for spam
+func Configure(r Repository, u auth.Repository, cfg config.InboxConfig) {
+	repo = r
+	users = u
+	settings = cfg
+}
+
+// SendContactRequest sends a user a contact request
+//
+//	@Summary		Send a contact request
+//	@Description	Sends a message to the user's inbox from the contact form of their profile. The user is notified unless the message is scored as spam. Bots filling in the hidden website field are filed as spam, and the response is the same either way. Senders the user blocked, by their signed in account or the address they give, are rejected.
+//	@Tags			inbox
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string		true	"ID of the user to contact"
+//	@Param			message	body		ContactForm	true	"The message"
+//	@Success		201		{object}	map[string]string
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		403		{object}	apierror.Response	"Blocked by the user"
+//	@Failure		404		{object}	apierror.Response	"User not found"
+//	@Failure		500		{object}	apierror.Response	"Could not send message"
+//	@Router			/inbox/u/{userid} [post]
+func SendContactRequest(c *gin.Context) {
+	userID := c.Param("userid")
+
+	var form ContactForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	user, err := users.FindByID(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && user.Disabled) {
+		apierror.Abort(c, apierror.NotFound("User not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not send message"))
+		return
+	}
+	// Senders are recognised by the account they are signed in to, or the one with the address they gave
+	if err := privacy.CheckContact(ctx, userID, c.GetString("userID"), form.Email); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	now := time.Now()
+	recent, err := repo.CountFrom(ctx, userID, form.Email, now.Add(-24*time.Hour))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not send message"))
+		return
+	}
+	req := ContactRequest{
+		ID:     utils.GenerateID(),
+		UserID: userID,
+		// The name and subject end up in the headers of the emails about the request, so they are kept to
+		// one line
+		Name:      singleLine(form.Name),
+		Email:     form.Email,
+		Subject:   singleLine(form.Subject),
+		Message:   form.Message,
+		Status:    StatusNew,
+		SpamScore: spamScore(form, recent),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if req.SpamScore >= settings.SpamThreshold {
+		req.Status = StatusSpam
+	}
+	if err := repo.Create(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not send message"))
+		return
+	}
+	if req.Status != StatusSpam {
+		notify(ctx, req)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Message sent"})
+}
+
+// ListInbox lists the current user's contact requests
+//
+//	@Summary		List contact requests
+//	@Description	Lists the contact requests sent to the current user, newest first. Without a status, new and read requests are listed, leaving out those archived or filed as spam.
+//	@Tags			inbox
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			status	query		string	false	"Only list contact requests with the status"	Enums(new, read, archived, spam)
+//	@Success		200		{array}		ContactRequest
+//	@Failure		400		{object}	apierror.Response	"Invalid status"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve contact requests"
+//	@Router			/inbox [get]
+func ListInbox(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	listed := []string{StatusNew, StatusRead}
+	if status := c.Query("status"); status != "" {
+		if !slices.Contains(statuses, status) {
+			apierror.Abort(c, apierror.BadRequest("status must be one of "+strings.Join(statuses, ", ")))
+			return
+		}
+		listed = []string{status}
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	list, err := repo.List(ctx, user.ID, listed)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve contact requests"))
+		return
+	}
+	if list == nil {
+		list = []ContactRequest{}
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetContactRequest returns one of the current user's contact requests
+//
+//	@Summary		Get a contact request
+//	@Description	Returns one of the current user's contact requests, marking it read if it was new
+//	@Tags			inbox
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			requestid	path		string	true	"Contact request ID"
+//	@Success		200			{object}	ContactRequest
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		404			{object}	apierror.Response	"Contact request not found"
+//	@Failure		500			{object}	apierror.Response	"Could not retrieve contact request"
+//	@Router			/inbox/{requestid} [get]
+func GetContactRequest(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	req, err := repo.Get(ctx, user.ID, c.Param("requestid"))
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Contact request not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve contact request"))
+		return
+	}
+	if req.Status == StatusNew {
+		now := time.Now()
+		if err := repo.SetStatus(ctx, user.ID, req.ID, StatusRead, now); err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not retrieve contact request"))
+			return
+		}
+		req.Status = StatusRead
+		req.UpdatedAt = now
+	}
+
+	c.JSON(http.StatusOK, req)
+}
+
+// SetStatus marks a contact request new or read, archives it or files it as spam
+//
+//	@Summary		Change a contact request's status
+//	@Description	Marks one of the current user's contact requests new or read, archives it, or files it as spam. Requests filed as spam are removed after the configured retention.
+//	@Tags			inbox
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			requestid	path		string			true	"Contact request ID"
+//	@Param			status		body		StatusRequest	true	"The new status"
+//	@Success		200			{object}	map[string]string
+//	@Failure		400			{object}	apierror.Response	"Invalid request body"
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		404			{object}	apierror.Response	"Contact request not found"
+//	@Failure		500			{object}	apierror.Response	"Could not update contact request"
+//	@Router			/inbox/{requestid}/status [put]
+func SetStatus(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req StatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	err := repo.SetStatus(ctx, user.ID, c.Param("requestid"), req.Status, time.Now())
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Contact request not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update contact request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contact request marked " + req.Status})
+}
+
+// Reply emails a reply to a contact request
+//
+//	@Summary		Reply to a contact request
+//	@Description	Emails the reply to the address the contact request was sent from, quoting it. Replies to the email go to the address the current user signs in with, so the conversation carries on by email.
+//	@Tags			inbox
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			requestid	path		string			true	"Contact request ID"
+//	@Param			reply		body		ReplyRequest	true	"The reply"
+//	@Success		200			{object}	map[string]string
+//	@Failure		400			{object}	apierror.Response	"Invalid request body, or replying to spam"
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		404			{object}	apierror.Response	"Contact request not found"
+//	@Failure		500			{object}	apierror.Response	"Could not send reply"
+//	@Router			/inbox/{requestid}/reply [post]
+func Reply(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var body ReplyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	req, err := repo.Get(ctx, user.ID, c.Param("requestid"))
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Contact request not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not send reply"))
+		return
+	}
+	// Replying would confirm the address to whoever sent the spam
+	if req.Status == StatusSpam {
+		apierror.Abort(c, apierror.BadRequest("Move the contact request out of spam to reply to it"))
+		return
+	}
+
+	subject := req.Subject
+	if subject == "" {
+		subject = "Your message to " + user.Name
+	}
+	msg, err := email.Render(replyEmailTemplate, replyEmail{
+		Name:     req.Name,
+		UserName: user.Name,
+		Subject:  subject,
+		Message:  body.Message,
+		Original: quote(req.Message),
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not render reply email"))
+		return
+	}
+	msg.To = req.Email
+	msg.ReplyTo = user.Email
+	msg.UserID = user.ID
+	if err := email.Enqueue(ctx, msg); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not queue reply email"))
+		return
+	}
+	now := time.Now()
+	if err := repo.SetReplied(ctx, user.ID, req.ID, now); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not record reply"))
+		return
+	}
+	if req.Status == StatusNew {
+		if err := repo.SetStatus(ctx, user.ID, req.ID, StatusRead, now); err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not record reply"))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reply sent"})
+}
+
+// DeleteContactRequest deletes one of the current user's contact requests
+//
+//	@Summary		Delete a contact request
+//	@Description	Deletes one of the current user's contact requests
+//	@Tags			inbox
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			requestid	path		string	true	"Contact request ID"
+//	@Success		200			{object}	map[string]string
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		500			{object}	apierror.Response	"Could not delete contact request"
+//	@Failure		404			{object}	apierror.Response	"Contact request not found"
+//	@Router			/inbox/{requestid} [delete]
+func DeleteContactRequest(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	err := repo.Delete(ctx, user.ID, c.Param("requestid"))
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Contact request not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete contact request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contact request deleted"})
+}
+
+// PurgeSpam removes the contact requests filed as spam longer ago than the configured retention
+func PurgeSpam(ctx context.Context) error {
+	removed, err := repo.PurgeSpam(ctx, time.Now().Add(-settings.SpamRetention.Std()))
+	if err != nil {
+		return err
+	}
+	slog.Info("Purged spam contact requests", "removed", removed)
+	return nil
+}
+
+// notify tells the user a contact request arrived. Failures are logged, as the request is saved either way.
+func notify(ctx context.Context, req ContactRequest) {
+	message := fmt.Sprintf("%s sent you a message", req.Name)
+	if req.Subject != "" {
+		message += ": " + req.Subject
+	}
+	if err := notifications.Send(ctx, req.UserID, notifications.KindContactRequest, message, gin.H{"contactRequestID": req.ID}); err != nil {
+		slog.ErrorContext(ctx, "Could not notify user", "kind", notifications.KindContactRequest, "user_id", req.UserID, "error", err)
+	}
+}
+
+// singleLine joins the lines of the text, collapsing runs of spaces
+func singleLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// quote marks every line of the text as quoted, as replies to emails do
+func quote(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = "> " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// InitializeRoutes initializes the inbox routes. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup) {
+	authRequired := auth.AuthMiddleware(users, true)
+
+	router.POST("/u/:userid", auth.AuthMiddleware(users, false), SendContactRequest)
+
+	protected := router.Group("")
+	protected.Use(authRequired)
+	protected.GET("", ListInbox)
+	protected.GET("/:requestid", GetContactRequest)
+	protected.PUT("/:requestid/status", SetStatus)
+	protected.POST("/:requestid/reply", Reply)
+	protected.DELETE("/:requestid", DeleteContactRequest)
+}
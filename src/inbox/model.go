@@ -0,0 +1,63 @@
+package inbox
+
+import "time"
+
+// Statuses of a contact request
+const (
+	// StatusNew requests have not been read yet
+	StatusNew = "new"
+	// StatusRead requests have been opened by the user
+	StatusRead = "read"
+	// StatusArchived requests are kept out of the inbox
+	StatusArchived = "archived"
+	// StatusSpam requests were filed as spam, by their score or by the user, and are removed after a while
+	StatusSpam = "spam"
+)
+
+// ContactRequest is a message a visitor sent a user through the contact form of their profile
+type ContactRequest struct {
+	ID     string `bson:"_id" json:"id"`
+	UserID string `bson:"user_id" json:"userID"`
+	Name   string `bson:"name" json:"name"`
+	// Email is the address replies are sent to
+	Email   string `bson:"email" json:"email"`
+	Subject string `bson:"subject" json:"subject"`
+	Message string `bson:"message" json:"message"`
+	Status  string `bson:"status" json:"status"`
+	// SpamScore is how likely the request is to be spam, from 0 to 1
+	SpamScore float64 `bson:"spam_score" json:"spamScore"`
+	// RepliedAt is nil until the user replies to the request
+	RepliedAt *time.Time `bson:"replied_at,omitempty" json:"repliedAt"`
+	CreatedAt time.Time  `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time  `bson:"updated_at" json:"updatedAt"`
+}
+
+// ContactForm represents the request body a visitor sends a user a contact request with
+type ContactForm struct {
+	Name    string `json:"name" binding:"required,notblank,max=200"`
+	Email   string `json:"email" binding:"required,email,max=254"`
+	Subject string `json:"subject" binding:"max=200"`
+	Message string `json:"message" binding:"required,notblank,max=5000"`
+	// Website is hidden from people by the form, so a value is left by bots filling in every field
+	Website string `json:"website" binding:"max=2048"`
+}
+
+// StatusRequest represents the request body for marking a contact request read, archiving it or filing it
+// as spam
+type StatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=new read archived spam"`
+}
+
+// ReplyRequest represents the request body for replying to a contact request by email
+type ReplyRequest struct {
+	Message string `json:"message" binding:"required,notblank,max=10000"`
+}
+
+// replyEmail is the data for the contact_reply email template
+type replyEmail struct {
+	Name     string
+	UserName string
+	Subject  string
+	Message  string
+	Original string
+}
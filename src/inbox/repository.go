@@ -0,0 +1,26 @@
+package inbox
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores the contact requests sent to users
+type Repository interface {
+	// Create stores a new contact request
+	Create(ctx context.Context, req ContactRequest) error
+	// Get returns one of the user's contact requests, or store.ErrNotFound
+	Get(ctx context.Context, userID, requestID string) (ContactRequest, error)
+	// List returns the user's contact requests with any of the statuses, newest first
+	List(ctx context.Context, userID string, statuses []string) ([]ContactRequest, error)
+	// CountFrom counts the contact requests the address sent the user since the given time
+	CountFrom(ctx context.Context, userID, email string, since time.Time) (int, error)
+	// SetStatus changes the status of one of the user's contact requests, or returns store.ErrNotFound
+	SetStatus(ctx context.Context, userID, requestID, status string, at time.Time) error
+	// SetReplied records when the user replied to one of their contact requests, or returns store.ErrNotFound
+	SetReplied(ctx context.Context, userID, requestID string, at time.Time) error
+	// Delete removes one of the user's contact requests, or returns store.ErrNotFound when they have no such request
+	Delete(ctx context.Context, userID, requestID string) error
+	// PurgeSpam removes the contact requests filed as spam before the given time, returning how many were removed
+	PurgeSpam(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,115 @@
+package inbox
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps contact requests in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	requests []ContactRequest
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, req ContactRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	return nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, userID, requestID string) (ContactRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.index(userID, requestID)
+	if i < 0 {
+		return ContactRequest{}, store.ErrNotFound
+	}
+	return r.requests[i], nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string, statuses []string) ([]ContactRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []ContactRequest
+	// Newest first
+	for i := len(r.requests) - 1; i >= 0; i-- {
+		req := r.requests[i]
+		if req.UserID == userID && slices.Contains(statuses, req.Status) {
+			list = append(list, req)
+		}
+	}
+	return list, nil
+}
+
+func (r *MemoryRepository) CountFrom(ctx context.Context, userID, email string, since time.Time) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n := 0
+	for _, req := range r.requests {
+		if req.UserID == userID && req.Email == email && !req.CreatedAt.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *MemoryRepository) SetStatus(ctx context.Context, userID, requestID, status string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, requestID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.requests[i].Status = status
+	r.requests[i].UpdatedAt = at
+	return nil
+}
+
+func (r *MemoryRepository) SetReplied(ctx context.Context, userID, requestID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, requestID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.requests[i].RepliedAt = &at
+	r.requests[i].UpdatedAt = at
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, userID, requestID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, requestID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.requests = slices.Delete(r.requests, i, i+1)
+	return nil
+}
+
+func (r *MemoryRepository) PurgeSpam(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(r.requests)
+	r.requests = slices.DeleteFunc(r.requests, func(req ContactRequest) bool {
+		return req.Status == StatusSpam && req.UpdatedAt.Before(before)
+	})
+	return int64(n - len(r.requests)), nil
+}
+
+// index returns the position of one of the user's contact requests in requests, or -1. The caller must
+// hold the lock.
+func (r *MemoryRepository) index(userID, requestID string) int {
+	return slices.IndexFunc(r.requests, func(req ContactRequest) bool { return req.UserID == userID && req.ID == requestID })
+}
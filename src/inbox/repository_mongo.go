@@ -0,0 +1,90 @@
+package inbox
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores contact requests in the contact_requests collection
+type MongoRepository struct {
+	requests *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{requests: db.Collection("contact_requests")}
+}
+
+func (r *MongoRepository) Create(ctx context.Context, req ContactRequest) error {
+	_, err := r.requests.InsertOne(ctx, req)
+	return err
+}
+
+func (r *MongoRepository) Get(ctx context.Context, userID, requestID string) (ContactRequest, error) {
+	var req ContactRequest
+	err := r.requests.FindOne(ctx, bson.M{"_id": requestID, "user_id": userID}).Decode(&req)
+	return req, store.MongoErr(err)
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string, statuses []string) ([]ContactRequest, error) {
+	filter := bson.M{"user_id": userID, "status": bson.M{"$in": statuses}}
+	cursor, err := r.requests.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	var list []ContactRequest
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *MongoRepository) CountFrom(ctx context.Context, userID, email string, since time.Time) (int, error) {
+	n, err := r.requests.CountDocuments(ctx, bson.M{"user_id": userID, "email": email, "created_at": bson.M{"$gte": since}})
+	return int(n), err
+}
+
+func (r *MongoRepository) SetStatus(ctx context.Context, userID, requestID, status string, at time.Time) error {
+	return r.update(ctx, userID, requestID, bson.M{"status": status, "updated_at": at})
+}
+
+func (r *MongoRepository) SetReplied(ctx context.Context, userID, requestID string, at time.Time) error {
+	return r.update(ctx, userID, requestID, bson.M{"replied_at": at, "updated_at": at})
+}
+
+// update sets the fields of one of the user's contact requests, or returns store.ErrNotFound
+func (r *MongoRepository) update(ctx context.Context, userID, requestID string, fields bson.M) error {
+	result, err := r.requests.UpdateOne(ctx, bson.M{"_id": requestID, "user_id": userID}, bson.M{"$set": fields})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, userID, requestID string) error {
+	res, err := r.requests.DeleteOne(ctx, bson.M{"_id": requestID, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) PurgeSpam(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.requests.DeleteMany(ctx, bson.M{"status": StatusSpam, "updated_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,101 @@
+package inbox
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const contactRequestColumns = "id, user_id, name, email, subject, message, status, spam_score, replied_at, created_at, updated_at"
+
+// PostgresRepository stores contact requests in the contact_requests table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, req ContactRequest) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO contact_requests ("+contactRequestColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)",
+		req.ID, req.UserID, req.Name, req.Email, req.Subject, req.Message, req.Status, req.SpamScore, req.RepliedAt, req.CreatedAt, req.UpdatedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, userID, requestID string) (ContactRequest, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+contactRequestColumns+" FROM contact_requests WHERE id = $1 AND user_id = $2", requestID, userID)
+	if err != nil {
+		return ContactRequest{}, err
+	}
+	req, err := pgx.CollectExactlyOneRow(rows, scanContactRequest)
+	return req, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string, statuses []string) ([]ContactRequest, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+contactRequestColumns+" FROM contact_requests WHERE user_id = $1 AND status = ANY($2) ORDER BY created_at DESC", userID, statuses)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanContactRequest)
+}
+
+func (r *PostgresRepository) CountFrom(ctx context.Context, userID, email string, since time.Time) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx, "SELECT count(*) FROM contact_requests WHERE user_id = $1 AND email = $2 AND created_at >= $3", userID, email, since).Scan(&n)
+	return n, err
+}
+
+func (r *PostgresRepository) SetStatus(ctx context.Context, userID, requestID, status string, at time.Time) error {
+	tag, err := r.pool.Exec(ctx, "UPDATE contact_requests SET status = $3, updated_at = $4 WHERE id = $1 AND user_id = $2", requestID, userID, status, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) SetReplied(ctx context.Context, userID, requestID string, at time.Time) error {
+	tag, err := r.pool.Exec(ctx, "UPDATE contact_requests SET replied_at = $3, updated_at = $3 WHERE id = $1 AND user_id = $2", requestID, userID, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, userID, requestID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM contact_requests WHERE id = $1 AND user_id = $2", requestID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) PurgeSpam(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM contact_requests WHERE status = $1 AND updated_at < $2", StatusSpam, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// scanContactRequest reads a row selected with contactRequestColumns
+func scanContactRequest(row pgx.CollectableRow) (ContactRequest, error) {
+	var req ContactRequest
+	err := row.Scan(&req.ID, &req.UserID, &req.Name, &req.Email, &req.Subject, &req.Message, &req.Status, &req.SpamScore,
+		&req.RepliedAt, &req.CreatedAt, &req.UpdatedAt)
+	return req, err
+}
@@ -0,0 +1,22 @@
+package inbox
+
+import (
+	"context"
+
+	"profile-api/sanitize"
+)
+
+// SanitizedRepository cleans the text visitors send in contact requests before storing them
+type SanitizedRepository struct {
+	Repository
+}
+
+// NewSanitizedRepository wraps the repository to clean contact requests with the configured sanitize policies
+func NewSanitizedRepository(r Repository) *SanitizedRepository {
+	return &SanitizedRepository{Repository: r}
+}
+
+func (r *SanitizedRepository) Create(ctx context.Context, req ContactRequest) error {
+	req.Message = sanitize.Field(sanitize.ContactMessage, req.Message)
+	return r.Repository.Create(ctx, req)
+}
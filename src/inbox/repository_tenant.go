@@ -0,0 +1,50 @@
+package inbox
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, req ContactRequest) error {
+	return r.repos.For(ctx).Create(ctx, req)
+}
+
+func (r *TenantRepository) Get(ctx context.Context, userID, requestID string) (ContactRequest, error) {
+	return r.repos.For(ctx).Get(ctx, userID, requestID)
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID string, statuses []string) ([]ContactRequest, error) {
+	return r.repos.For(ctx).List(ctx, userID, statuses)
+}
+
+func (r *TenantRepository) CountFrom(ctx context.Context, userID, email string, since time.Time) (int, error) {
+	return r.repos.For(ctx).CountFrom(ctx, userID, email, since)
+}
+
+func (r *TenantRepository) SetStatus(ctx context.Context, userID, requestID, status string, at time.Time) error {
+	return r.repos.For(ctx).SetStatus(ctx, userID, requestID, status, at)
+}
+
+func (r *TenantRepository) SetReplied(ctx context.Context, userID, requestID string, at time.Time) error {
+	return r.repos.For(ctx).SetReplied(ctx, userID, requestID, at)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, userID, requestID string) error {
+	return r.repos.For(ctx).Delete(ctx, userID, requestID)
+}
+
+func (r *TenantRepository) PurgeSpam(ctx context.Context, before time.Time) (int64, error) {
+	return r.repos.For(ctx).PurgeSpam(ctx, before)
+}
@@ -0,0 +1,47 @@
+package inbox
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// linkPattern finds web links, which most spam carries
+var linkPattern = regexp.MustCompile(`(?i)https?://|www\.`)
+
+// spamScore rates how likely a contact request is to be spam, from 0 to 1. Recent counts the contact
+// requests the sender's address sent the user in the last day.
+func spamScore(form ContactForm, recent int) float64 {
+	// People never see the honeypot field, and an address sending many requests a day is flooding the inbox
+	if form.Website != "" || recent >= settings.MaxPerSender {
+		return 1
+	}
+	text := form.Subject + "\n" + form.Message
+
+	score := min(0.2*float64(len(linkPattern.FindAllStringIndex(text, -1))), 0.6)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	for _, word := range settings.SpamWords {
+		if slices.Contains(words, strings.ToLower(word)) {
+			score += 0.25
+		}
+	}
+	if shouting(form.Message) {
+		score += 0.3
+	}
+	return min(score, 1)
+}
+
+// shouting reports whether the text is long enough to tell and mostly written in capitals
+func shouting(text string) bool {
+	letters, upper := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	return letters >= 20 && float64(upper) > 0.7*float64(letters)
+}
@@ -0,0 +1,650 @@
+// Package integrations receives events pushed by external systems, such as a learning platform reporting
+// the courses its users completed. Each integration is created by an admin and signs its events with its own
+// secret. Its users are mapped to accounts by an admin, or by their email address when the integration is
+// trusted to report it. Every event is recorded once, so an integration retrying a delivery does not create
+// a second certificate, and events about users not yet mapped can be replayed once they are.
+package integrations
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/certificates"
+	"profile-api/events"
+	"profile-api/quota"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// signatureTolerance is how old an event's signature may be, bounding the replay of captured events
+const signatureTolerance = 5 * time.Minute
+
+const (
+	defaultReceiptLimit = 50
+	maxReceiptLimit     = 500
+)
+
+var (
+	repo  Repository
+	users auth.Repository
+	certs certificates.Repository
+)
+
+// Configure sets where integrations, accounts and certificates are stored, and starts removing the mappings
+// of users who delete their account
+func Configure(r Repository, u auth.Repository, c certificates.Repository) {
+	repo = r
+	users = u
+	certs = c
+	audit.Subscribe(removeUser)
+}
+
+// removeUser removes the mappings to a deleted user's account and the events received about them
+func removeUser(ctx context.Context, entry audit.Entry) {
+	if entry.Resource != "user" || entry.Action != audit.ActionDelete {
+		return
+	}
+	if err := repo.DeleteUser(ctx, entry.ResourceID); err != nil {
+		slog.ErrorContext(ctx, "Could not remove the integration mappings of a deleted user", "user_id", entry.ResourceID, "error", err)
+	}
+}
+
+// newSecret returns a random secret for signing an integration's events
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// verifySignature checks that the payload was signed with the secret at the timestamp, as outbound webhooks
+// are: the signature is "sha256=" followed by the hex HMAC-SHA256 of the timestamp, a dot and the payload
+func verifySignature(signature, timestamp string, payload []byte, secret string, now time.Time) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("malformed timestamp")
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > signatureTolerance || age < -signatureTolerance {
+		return errors.New("timestamp is outside the tolerance")
+	}
+	given, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || !strings.HasPrefix(signature, "sha256=") {
+		return errors.New("malformed signature")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	if !hmac.Equal(given, mac.Sum(nil)) {
+		return errors.New("signature does not match")
+	}
+	return nil
+}
+
+// mappedUser returns the account the event's user is mapped to, mapping them by email address when the
+// integration allows it, or store.ErrNotFound when they are mapped to none
+func mappedUser(ctx context.Context, integration Integration, event Event) (auth.User, error) {
+	mapping, err := repo.GetMapping(ctx, integration.ID, event.User.ID)
+	if err == nil {
+		return users.FindByID(ctx, mapping.UserID)
+	}
+	if !errors.Is(err, store.ErrNotFound) || !integration.MatchEmail || event.User.Email == "" {
+		return auth.User{}, err
+	}
+
+	user, err := users.FindByEmail(ctx, event.User.Email)
+	if err != nil {
+		return auth.User{}, err
+	}
+	mapping = Mapping{IntegrationID: integration.ID, ExternalID: event.User.ID, UserID: user.ID, CreatedAt: time.Now()}
+	if err := repo.SaveMapping(ctx, mapping); err != nil {
+		return auth.User{}, err
+	}
+	return user, nil
+}
+
+// process makes the event's change and records the outcome on its receipt. It returns an error only when the
+// event could not be processed, and may be retried.
+func process(ctx context.Context, integration Integration, event Event, receipt Receipt) (Receipt, error) {
+	receipt.Error = ""
+	user, err := mappedUser(ctx, integration, event)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		receipt.Status = StatusUnmatched
+		return receipt, nil
+	case err != nil:
+		return receipt, err
+	}
+	receipt.UserID = user.ID
+
+	switch event.Type {
+	case EventCourseCompleted:
+		return completeCourse(ctx, integration, event, user, receipt)
+	default:
+		receipt.Status = StatusIgnored
+		return receipt, nil
+	}
+}
+
+// completeCourse creates a certificate for the course the user completed
+func completeCourse(ctx context.Context, integration Integration, event Event, user auth.User, receipt Receipt) (Receipt, error) {
+	if event.Course == nil {
+		receipt.Status = StatusRejected
+		receipt.Error = "course.completed events must name the course"
+		return receipt, nil
+	}
+	left, err := quota.DocumentsLeft(ctx, user, "certificates")
+	if err != nil {
+		return receipt, err
+	}
+	if left == 0 {
+		receipt.Status = StatusRejected
+		receipt.Error = "the user has reached their certificate limit"
+		return receipt, nil
+	}
+
+	institution := event.Course.Institution
+	if institution == "" {
+		institution = integration.Institution
+	}
+	if institution == "" {
+		institution = integration.Name
+	}
+	cert := certificates.Certificate{
+		UserID:        user.ID,
+		CertificateID: primitive.NewObjectID().Hex(),
+		Title:         event.Course.Title,
+		Institution:   institution,
+		Start:         event.Course.CompletedAt,
+		End:           event.Course.ExpiresAt,
+		Description:   event.Course.Description,
+	}
+	if err := certs.Create(ctx, cert); err != nil {
+		return receipt, err
+	}
+	events.Publish(ctx, user.ID, events.TypeCertificateCreated, cert)
+
+	receipt.Status = StatusProcessed
+	receipt.CertificateID = cert.CertificateID
+	return receipt, nil
+}
+
+// record processes the event and stores the outcome on its receipt
+func record(ctx context.Context, integration Integration, event Event, receipt Receipt) (Receipt, error) {
+	receipt, err := process(ctx, integration, event, receipt)
+	if err != nil {
+		receipt.Status = StatusFailed
+		receipt.Error = err.Error()
+	}
+	receipt.ProcessedAt = time.Now()
+	if updateErr := repo.UpdateReceipt(ctx, receipt); updateErr != nil && err == nil {
+		err = updateErr
+	}
+	return receipt, err
+}
+
+// claim records a newly received event, returning whether it is to be processed: it was not received
+// before, or processing it failed
+func claim(ctx context.Context, receipt Receipt) (Receipt, bool, error) {
+	err := repo.CreateReceipt(ctx, receipt)
+	if !errors.Is(err, store.ErrConflict) {
+		return receipt, err == nil, err
+	}
+	existing, err := repo.GetReceipt(ctx, receipt.ID)
+	if err != nil {
+		return receipt, false, err
+	}
+	return existing, existing.Status == StatusFailed, nil
+}
+
+// ReceiveEvent processes an event pushed by an integration
+//
+//	@Summary		Receive an integration event
+//	@Description	Receives an event pushed by an external system. Events are signed with the integration's secret: the X-Webhook-Signature header is "sha256=" followed by the hex HMAC-SHA256 of the X-Webhook-Timestamp header, a dot and the body, as for outbound webhooks, and the timestamp must be within five minutes. The event's user is mapped to an account by an admin, or by email address when the integration matches them. A course.completed event creates a certificate for the course. Each event ID is processed once: an event sent again is answered with the outcome of the first delivery and marked as a duplicate, unless processing it failed. Events about users mapped to no account are answered with 202 and kept, so an admin can replay them once the user is mapped. Events of other types are recorded and ignored.
+//	@Tags			integrations
+//	@Accept			json
+//	@Produce		json
+//	@Param			integrationid		path		string				true	"Integration ID"
+//	@Param			X-Webhook-Timestamp	header		string				true	"Unix time the event was signed at"
+//	@Param			X-Webhook-Signature	header		string				true	"Signature of the event"
+//	@Param			request				body		Event				true	"Event"
+//	@Success		200					{object}	EventResponse		"Event processed, ignored or rejected"
+//	@Success		202					{object}	EventResponse		"Event kept until its user is mapped to an account"
+//	@Failure		400					{object}	apierror.Response	"Invalid event"
+//	@Failure		401					{object}	apierror.Response	"Invalid signature"
+//	@Failure		500					{object}	apierror.Response	"Could not process event"
+//	@Router			/integrations/{integrationid}/events [post]
+func ReceiveEvent(c *gin.Context) {
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Could not read event"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	integration, err := repo.Get(ctx, c.Param("integrationid"))
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.Wrap(err, "Could not process event"))
+		return
+	}
+	// An unknown integration is answered as a bad signature, so integration IDs cannot be probed
+	if err != nil {
+		apierror.Abort(c, apierror.Unauthorized("Invalid signature"))
+		return
+	}
+	if err := verifySignature(c.GetHeader("X-Webhook-Signature"), c.GetHeader("X-Webhook-Timestamp"), payload, integration.Secret, time.Now()); err != nil {
+		apierror.Abort(c, apierror.Unauthorized(fmt.Sprintf("Invalid signature: %v", err)))
+		return
+	}
+	var event Event
+	if err := binding.JSON.BindBody(payload, &event); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	now := time.Now()
+	receipt, fresh, err := claim(ctx, Receipt{
+		ID:             receiptID(integration.ID, event.ID),
+		IntegrationID:  integration.ID,
+		EventID:        event.ID,
+		Type:           event.Type,
+		ExternalUserID: event.User.ID,
+		Status:         StatusPending,
+		Payload:        payload,
+		ReceivedAt:     now,
+		ProcessedAt:    now,
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not process event"))
+		return
+	}
+	if !fresh {
+		c.JSON(http.StatusOK, EventResponse{Status: receipt.Status, CertificateID: receipt.CertificateID, Error: receipt.Error, Duplicate: true})
+		return
+	}
+
+	receipt, err = record(ctx, integration, event, receipt)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not process event"))
+		return
+	}
+	slog.InfoContext(ctx, "Integration event received", "integration_id", integration.ID, "event_id", event.ID, "type", event.Type, "status", receipt.Status)
+
+	status := http.StatusOK
+	if receipt.Status == StatusUnmatched {
+		status = http.StatusAccepted
+	}
+	c.JSON(status, EventResponse{Status: receipt.Status, CertificateID: receipt.CertificateID, Error: receipt.Error})
+}
+
+// CreateIntegration creates an integration allowed to push events
+//
+//	@Summary		Create an integration
+//	@Description	Creates an integration an external system pushes events through, to POST /integrations/{integrationid}/events. The secret it signs its events with is only returned here and when it is rotated. With matchEmail, users of the integration not mapped to an account are mapped to the account registered with the email address it reports, so only enable it for systems that verify their users' addresses. Only admins can manage integrations.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		IntegrationRequest	true	"Integration"
+//	@Success		201		{object}	CreatedIntegration
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not an admin"
+//	@Failure		500		{object}	apierror.Response	"Could not create integration"
+//	@Router			/admin/integrations [post]
+func CreateIntegration(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req IntegrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	secret, err := newSecret()
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create integration"))
+		return
+	}
+	integration := Integration{
+		ID:          utils.GenerateID(),
+		Name:        req.Name,
+		Institution: req.Institution,
+		MatchEmail:  req.MatchEmail,
+		Secret:      secret,
+		CreatedBy:   user.ID,
+		CreatedAt:   time.Now(),
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Create(ctx, integration); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create integration"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreatedIntegration{Integration: integration, Secret: secret})
+}
+
+// ListIntegrations lists the integrations
+//
+//	@Summary		List integrations
+//	@Description	Lists the integrations allowed to push events, oldest first, without their secrets. Only admins can manage integrations.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		Integration
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		403	{object}	apierror.Response	"Not an admin"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve integrations"
+//	@Router			/admin/integrations [get]
+func ListIntegrations(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	integrations, err := repo.List(ctx)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve integrations"))
+		return
+	}
+	if integrations == nil {
+		integrations = []Integration{}
+	}
+
+	c.JSON(http.StatusOK, integrations)
+}
+
+// DeleteIntegration removes an integration
+//
+//	@Summary		Delete an integration
+//	@Description	Removes an integration with the mappings of its users and the events it pushed. Events it sends afterwards are rejected. The certificates it created are kept. Only admins can manage integrations.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			integrationid	path		string	true	"Integration ID"
+//	@Success		200				{object}	map[string]string
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not an admin"
+//	@Failure		404				{object}	apierror.Response	"Integration not found"
+//	@Router			/admin/integrations/{integrationid} [delete]
+func DeleteIntegration(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Delete(ctx, c.Param("integrationid")); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Integration not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Integration deleted"})
+}
+
+// RotateSecret replaces an integration's secret
+//
+//	@Summary		Rotate an integration's secret
+//	@Description	Replaces the secret an integration signs its events with, returning the new one. Events signed with the old secret are rejected from then on. Only admins can manage integrations.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			integrationid	path		string	true	"Integration ID"
+//	@Success		200				{object}	CreatedIntegration
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not an admin"
+//	@Failure		404				{object}	apierror.Response	"Integration not found"
+//	@Failure		500				{object}	apierror.Response	"Could not rotate secret"
+//	@Router			/admin/integrations/{integrationid}/secret [post]
+func RotateSecret(c *gin.Context) {
+	integration, ok := loadIntegration(c)
+	if !ok {
+		return
+	}
+	secret, err := newSecret()
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not rotate secret"))
+		return
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.SetSecret(ctx, integration.ID, secret); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not rotate secret"))
+		return
+	}
+
+	c.JSON(http.StatusOK, CreatedIntegration{Integration: integration, Secret: secret})
+}
+
+// ListMappings lists the accounts an integration's users are mapped to
+//
+//	@Summary		List an integration's user mappings
+//	@Description	Lists the users of an integration mapped to accounts, whether by an admin or by their email address. Only admins can manage integrations.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			integrationid	path		string	true	"Integration ID"
+//	@Success		200				{array}		Mapping
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not an admin"
+//	@Failure		404				{object}	apierror.Response	"Integration not found"
+//	@Failure		500				{object}	apierror.Response	"Could not retrieve mappings"
+//	@Router			/admin/integrations/{integrationid}/mappings [get]
+func ListMappings(c *gin.Context) {
+	integration, ok := loadIntegration(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	mappings, err := repo.ListMappings(ctx, integration.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve mappings"))
+		return
+	}
+	if mappings == nil {
+		mappings = []Mapping{}
+	}
+
+	c.JSON(http.StatusOK, mappings)
+}
+
+// PutMapping maps a user of an integration to an account
+//
+//	@Summary		Map an integration's user to an account
+//	@Description	Maps the user the integration identifies by the external ID to an account, replacing an earlier mapping. Events already received about them are not processed until they are replayed. Only admins can manage integrations.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			integrationid	path		string			true	"Integration ID"
+//	@Param			externalid		path		string			true	"ID of the user in the integration"
+//	@Param			request			body		MappingRequest	true	"Account to map the user to"
+//	@Success		200				{object}	Mapping
+//	@Failure		400				{object}	apierror.Response	"Invalid request body"
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not an admin"
+//	@Failure		404				{object}	apierror.Response	"Integration or user not found"
+//	@Failure		500				{object}	apierror.Response	"Could not save mapping"
+//	@Router			/admin/integrations/{integrationid}/mappings/{externalid} [put]
+func PutMapping(c *gin.Context) {
+	var req MappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	integration, ok := loadIntegration(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if _, err := users.FindByID(ctx, req.UserID); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "User not found"))
+		return
+	}
+	mapping := Mapping{IntegrationID: integration.ID, ExternalID: c.Param("externalid"), UserID: req.UserID, CreatedAt: time.Now()}
+	if err := repo.SaveMapping(ctx, mapping); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not save mapping"))
+		return
+	}
+
+	c.JSON(http.StatusOK, mapping)
+}
+
+// DeleteMapping removes the mapping of an integration's user
+//
+//	@Summary		Unmap an integration's user
+//	@Description	Removes the mapping of the user the integration identifies by the external ID. Certificates already created for them are kept. Only admins can manage integrations.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			integrationid	path		string	true	"Integration ID"
+//	@Param			externalid		path		string	true	"ID of the user in the integration"
+//	@Success		200				{object}	map[string]string
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not an admin"
+//	@Failure		404				{object}	apierror.Response	"Mapping not found"
+//	@Router			/admin/integrations/{integrationid}/mappings/{externalid} [delete]
+func DeleteMapping(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.DeleteMapping(ctx, c.Param("integrationid"), c.Param("externalid")); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Mapping not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Mapping deleted"})
+}
+
+// ListEvents lists the events an integration pushed
+//
+//	@Summary		List an integration's events
+//	@Description	Lists the events an integration pushed, newest first, with what was done with each and its payload. Only admins can manage integrations.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			integrationid	path		string	true	"Integration ID"
+//	@Param			status			query		string	false	"Only list events with the status"	Enums(pending, processed, unmatched, ignored, rejected, failed)
+//	@Param			limit			query		int		false	"Maximum number of events to return (default 50, max 500)"
+//	@Success		200				{array}		Receipt
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not an admin"
+//	@Failure		404				{object}	apierror.Response	"Integration not found"
+//	@Failure		500				{object}	apierror.Response	"Could not retrieve events"
+//	@Router			/admin/integrations/{integrationid}/events [get]
+func ListEvents(c *gin.Context) {
+	limit := defaultReceiptLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, maxReceiptLimit)
+	}
+
+	integration, ok := loadIntegration(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	receipts, err := repo.ListReceipts(ctx, integration.ID, c.Query("status"), limit)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve events"))
+		return
+	}
+	if receipts == nil {
+		receipts = []Receipt{}
+	}
+
+	c.JSON(http.StatusOK, receipts)
+}
+
+// ReplayEvent processes an event kept because its user was not mapped, or whose processing failed
+//
+//	@Summary		Replay an integration event
+//	@Description	Processes again an event kept because its user was mapped to no account, such as after mapping them, or one whose processing failed. Only admins can manage integrations.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			integrationid	path		string	true	"Integration ID"
+//	@Param			eventid			path		string	true	"ID of the event in the integration"
+//	@Success		200				{object}	Receipt
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not an admin"
+//	@Failure		404				{object}	apierror.Response	"Integration or event not found"
+//	@Failure		409				{object}	apierror.Response	"Event already processed"
+//	@Failure		500				{object}	apierror.Response	"Could not process event"
+//	@Router			/admin/integrations/{integrationid}/events/{eventid}/replay [post]
+func ReplayEvent(c *gin.Context) {
+	integration, ok := loadIntegration(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	receipt, err := repo.GetReceipt(ctx, receiptID(integration.ID, c.Param("eventid")))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Event not found"))
+		return
+	}
+	if receipt.Status != StatusUnmatched && receipt.Status != StatusFailed {
+		apierror.Abort(c, apierror.Conflict("Only unmatched or failed events can be replayed"))
+		return
+	}
+	var event Event
+	if err := json.Unmarshal(receipt.Payload, &event); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not process event"))
+		return
+	}
+
+	receipt, err = record(ctx, integration, event, receipt)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not process event"))
+		return
+	}
+
+	c.JSON(http.StatusOK, receipt)
+}
+
+// loadIntegration loads the integration in the path, aborting with 404 when there is none
+func loadIntegration(c *gin.Context) (Integration, bool) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	integration, err := repo.Get(ctx, c.Param("integrationid"))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Integration not found"))
+		return Integration{}, false
+	}
+	return integration, true
+}
+
+// InitializeRoutes registers the endpoint integrations push events to. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup) {
+	router.POST("/:integrationid/events", ReceiveEvent)
+}
+
+// InitializeAdminRoutes registers the integration management routes. The router must only admit admins.
+func InitializeAdminRoutes(router *gin.RouterGroup) {
+	router.POST("", CreateIntegration)
+	router.GET("", ListIntegrations)
+	router.DELETE("/:integrationid", DeleteIntegration)
+	router.POST("/:integrationid/secret", RotateSecret)
+	router.GET("/:integrationid/mappings", ListMappings)
+	router.PUT("/:integrationid/mappings/:externalid", PutMapping)
+	router.DELETE("/:integrationid/mappings/:externalid", DeleteMapping)
+	router.GET("/:integrationid/events", ListEvents)
+	router.POST("/:integrationid/events/:eventid/replay", ReplayEvent)
+}
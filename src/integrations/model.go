@@ -0,0 +1,127 @@
+package integrations
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event types integrations may push
+const (
+	// EventCourseCompleted records that a user completed a course, creating a certificate for them
+	EventCourseCompleted = "course.completed"
+)
+
+// Receipt statuses
+const (
+	// StatusPending events are being processed
+	StatusPending = "pending"
+	// StatusProcessed events made their change
+	StatusProcessed = "processed"
+	// StatusUnmatched events are about a user mapped to no account, and can be replayed once they are mapped
+	StatusUnmatched = "unmatched"
+	// StatusIgnored events are of a type nothing is done with
+	StatusIgnored = "ignored"
+	// StatusRejected events could not make their change, such as for a user at their document limit
+	StatusRejected = "rejected"
+	// StatusFailed events hit an error, and are processed again when the integration retries them
+	StatusFailed = "failed"
+)
+
+// Integration is an external system, such as a learning platform, allowed to push events about its users
+type Integration struct {
+	ID   string `bson:"_id" json:"id"`
+	Name string `bson:"name" json:"name"`
+	// Institution is recorded on the certificates created from the integration's events naming no institution
+	Institution string `bson:"institution" json:"institution"`
+	// MatchEmail maps the integration's users without a mapping to the account with the same email address
+	MatchEmail bool      `bson:"match_email" json:"matchEmail"`
+	Secret     string    `bson:"secret" json:"-"`
+	CreatedBy  string    `bson:"created_by" json:"createdBy"`
+	CreatedAt  time.Time `bson:"created_at" json:"createdAt"`
+}
+
+// CreatedIntegration is returned when an integration is created or its secret rotated, the only times the
+// secret is shown
+type CreatedIntegration struct {
+	Integration
+	Secret string `json:"secret"`
+}
+
+// IntegrationRequest represents the request body for creating an integration
+type IntegrationRequest struct {
+	Name        string `json:"name" binding:"required,notblank,max=100"`
+	Institution string `json:"institution" binding:"max=200"`
+	MatchEmail  bool   `json:"matchEmail"`
+}
+
+// Mapping ties a user of an integration to an account
+type Mapping struct {
+	IntegrationID string    `bson:"integration_id" json:"integrationID"`
+	ExternalID    string    `bson:"external_id" json:"externalID"`
+	UserID        string    `bson:"user_id" json:"userID"`
+	CreatedAt     time.Time `bson:"created_at" json:"createdAt"`
+}
+
+// MappingRequest represents the request body for mapping a user of an integration to an account
+type MappingRequest struct {
+	UserID string `json:"userID" binding:"required,max=100"`
+}
+
+// Event is an event pushed by an integration
+type Event struct {
+	// ID identifies the event within the integration, so an event sent again is only processed once
+	ID   string    `json:"id" binding:"required,max=200"`
+	Type string    `json:"type" binding:"required,max=100"`
+	User EventUser `json:"user"`
+	// Course is the course completed, for course.completed events
+	Course *Course `json:"course" binding:"omitempty"`
+}
+
+// EventUser is the user of the integration an event is about
+type EventUser struct {
+	ID    string `json:"id" binding:"required,max=200"`
+	Email string `json:"email" binding:"omitempty,email,max=254"`
+}
+
+// Course is a course a user completed
+type Course struct {
+	Title string `json:"title" binding:"required,notblank,max=200"`
+	// Institution defaults to that of the integration
+	Institution string `json:"institution" binding:"max=200"`
+	CompletedAt string `json:"completedAt" binding:"omitempty,date"`
+	// ExpiresAt is when the certification earned lapses, if it does
+	ExpiresAt   string `json:"expiresAt" binding:"omitempty,date"`
+	Description string `json:"description" binding:"max=5000"`
+}
+
+// Receipt records an event received from an integration and what was done with it
+type Receipt struct {
+	// ID is the integration's ID and the event's
+	ID             string `bson:"_id" json:"-"`
+	IntegrationID  string `bson:"integration_id" json:"integrationID"`
+	EventID        string `bson:"event_id" json:"eventID"`
+	Type           string `bson:"type" json:"type"`
+	ExternalUserID string `bson:"external_user_id" json:"externalUserID"`
+	Status         string `bson:"status" json:"status"`
+	// UserID is the account the event was mapped to
+	UserID string `bson:"user_id,omitempty" json:"userID,omitempty"`
+	// CertificateID is the certificate the event created
+	CertificateID string          `bson:"certificate_id,omitempty" json:"certificateID,omitempty"`
+	Error         string          `bson:"error,omitempty" json:"error,omitempty"`
+	Payload       json.RawMessage `bson:"payload" json:"payload" swaggertype:"object"`
+	ReceivedAt    time.Time       `bson:"received_at" json:"receivedAt"`
+	ProcessedAt   time.Time       `bson:"processed_at" json:"processedAt"`
+}
+
+func receiptID(integrationID, eventID string) string {
+	return integrationID + "/" + eventID
+}
+
+// EventResponse tells an integration what was done with its event
+type EventResponse struct {
+	Status        string `json:"status"`
+	CertificateID string `json:"certificateID,omitempty"`
+	Error         string `json:"error,omitempty"`
+	// Duplicate is set when the event had already been received, and was not processed again
+	Duplicate bool `json:"duplicate,omitempty"`
+}
@@ -0,0 +1,35 @@
+package integrations
+
+import "context"
+
+// Repository stores integrations, the mappings of their users to accounts and the events they pushed
+type Repository interface {
+	// Create stores a new integration
+	Create(ctx context.Context, integration Integration) error
+	// Get returns the integration with the given ID, or store.ErrNotFound
+	Get(ctx context.Context, id string) (Integration, error)
+	// List returns every integration, oldest first
+	List(ctx context.Context) ([]Integration, error)
+	// SetSecret replaces the integration's secret, or returns store.ErrNotFound
+	SetSecret(ctx context.Context, id, secret string) error
+	// Delete removes the integration with its mappings and receipts, or returns store.ErrNotFound
+	Delete(ctx context.Context, id string) error
+	// SaveMapping maps a user of the integration to an account, replacing an earlier mapping
+	SaveMapping(ctx context.Context, mapping Mapping) error
+	// GetMapping returns the mapping of the user of the integration, or store.ErrNotFound
+	GetMapping(ctx context.Context, integrationID, externalID string) (Mapping, error)
+	// ListMappings returns the mappings of the integration's users
+	ListMappings(ctx context.Context, integrationID string) ([]Mapping, error)
+	// DeleteMapping removes the mapping of the user of the integration, or returns store.ErrNotFound
+	DeleteMapping(ctx context.Context, integrationID, externalID string) error
+	// CreateReceipt records a received event, or returns store.ErrConflict when it was already received
+	CreateReceipt(ctx context.Context, receipt Receipt) error
+	// GetReceipt returns the receipt with the given ID, or store.ErrNotFound
+	GetReceipt(ctx context.Context, id string) (Receipt, error)
+	// UpdateReceipt records what was done with a received event
+	UpdateReceipt(ctx context.Context, receipt Receipt) error
+	// ListReceipts returns the integration's latest receipts, optionally only those with the status
+	ListReceipts(ctx context.Context, integrationID, status string, limit int) ([]Receipt, error)
+	// DeleteUser removes the mappings to the user's account and the receipts of events about them
+	DeleteUser(ctx context.Context, userID string) error
+}
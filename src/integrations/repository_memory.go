@@ -0,0 +1,163 @@
+package integrations
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps integrations in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu           sync.Mutex
+	integrations []Integration
+	mappings     []Mapping
+	receipts     []Receipt
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, integration Integration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.integrations = append(r.integrations, integration)
+	return nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, id string) (Integration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.integrations, func(in Integration) bool { return in.ID == id })
+	if i < 0 {
+		return Integration{}, store.ErrNotFound
+	}
+	return r.integrations[i], nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context) ([]Integration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.integrations), nil
+}
+
+func (r *MemoryRepository) SetSecret(ctx context.Context, id, secret string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.integrations, func(in Integration) bool { return in.ID == id })
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.integrations[i].Secret = secret
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(r.integrations)
+	r.integrations = slices.DeleteFunc(r.integrations, func(in Integration) bool { return in.ID == id })
+	if len(r.integrations) == n {
+		return store.ErrNotFound
+	}
+	r.mappings = slices.DeleteFunc(r.mappings, func(m Mapping) bool { return m.IntegrationID == id })
+	r.receipts = slices.DeleteFunc(r.receipts, func(rc Receipt) bool { return rc.IntegrationID == id })
+	return nil
+}
+
+func (r *MemoryRepository) SaveMapping(ctx context.Context, mapping Mapping) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mappings = slices.DeleteFunc(r.mappings, func(m Mapping) bool {
+		return m.IntegrationID == mapping.IntegrationID && m.ExternalID == mapping.ExternalID
+	})
+	r.mappings = append(r.mappings, mapping)
+	return nil
+}
+
+func (r *MemoryRepository) GetMapping(ctx context.Context, integrationID, externalID string) (Mapping, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.mappings, func(m Mapping) bool { return m.IntegrationID == integrationID && m.ExternalID == externalID })
+	if i < 0 {
+		return Mapping{}, store.ErrNotFound
+	}
+	return r.mappings[i], nil
+}
+
+func (r *MemoryRepository) ListMappings(ctx context.Context, integrationID string) ([]Mapping, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var mappings []Mapping
+	for _, m := range r.mappings {
+		if m.IntegrationID == integrationID {
+			mappings = append(mappings, m)
+		}
+	}
+	return mappings, nil
+}
+
+func (r *MemoryRepository) DeleteMapping(ctx context.Context, integrationID, externalID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(r.mappings)
+	r.mappings = slices.DeleteFunc(r.mappings, func(m Mapping) bool { return m.IntegrationID == integrationID && m.ExternalID == externalID })
+	if len(r.mappings) == n {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MemoryRepository) CreateReceipt(ctx context.Context, receipt Receipt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.ContainsFunc(r.receipts, func(rc Receipt) bool { return rc.ID == receipt.ID }) {
+		return store.ErrConflict
+	}
+	r.receipts = append(r.receipts, receipt)
+	return nil
+}
+
+func (r *MemoryRepository) GetReceipt(ctx context.Context, id string) (Receipt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.receipts, func(rc Receipt) bool { return rc.ID == id })
+	if i < 0 {
+		return Receipt{}, store.ErrNotFound
+	}
+	return r.receipts[i], nil
+}
+
+func (r *MemoryRepository) UpdateReceipt(ctx context.Context, receipt Receipt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.receipts, func(rc Receipt) bool { return rc.ID == receipt.ID })
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.receipts[i] = receipt
+	return nil
+}
+
+func (r *MemoryRepository) ListReceipts(ctx context.Context, integrationID, status string, limit int) ([]Receipt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var receipts []Receipt
+	for i := len(r.receipts) - 1; i >= 0 && len(receipts) < limit; i-- {
+		if rc := r.receipts[i]; rc.IntegrationID == integrationID && (status == "" || rc.Status == status) {
+			receipts = append(receipts, rc)
+		}
+	}
+	return receipts, nil
+}
+
+func (r *MemoryRepository) DeleteUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mappings = slices.DeleteFunc(r.mappings, func(m Mapping) bool { return m.UserID == userID })
+	r.receipts = slices.DeleteFunc(r.receipts, func(rc Receipt) bool { return rc.UserID == userID })
+	return nil
+}
@@ -0,0 +1,156 @@
+package integrations
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores integrations in the integrations collection, the mappings of their users in
+// integration_mappings and the events they pushed in integration_receipts
+type MongoRepository struct {
+	integrations *mongo.Collection
+	mappings     *mongo.Collection
+	receipts     *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		integrations: db.Collection("integrations"),
+		mappings:     db.Collection("integration_mappings"),
+		receipts:     db.Collection("integration_receipts"),
+	}
+}
+
+func (r *MongoRepository) Create(ctx context.Context, integration Integration) error {
+	_, err := r.integrations.InsertOne(ctx, integration)
+	return err
+}
+
+func (r *MongoRepository) Get(ctx context.Context, id string) (Integration, error) {
+	var integration Integration
+	err := r.integrations.FindOne(ctx, bson.M{"_id": id}).Decode(&integration)
+	return integration, store.MongoErr(err)
+}
+
+func (r *MongoRepository) List(ctx context.Context) ([]Integration, error) {
+	cursor, err := r.integrations.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var integrations []Integration
+	err = cursor.All(ctx, &integrations)
+	return integrations, err
+}
+
+func (r *MongoRepository) SetSecret(ctx context.Context, id, secret string) error {
+	result, err := r.integrations.UpdateByID(ctx, id, bson.M{"$set": bson.M{"secret": secret}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.integrations.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	if _, err := r.mappings.DeleteMany(ctx, bson.M{"integration_id": id}); err != nil {
+		return err
+	}
+	_, err = r.receipts.DeleteMany(ctx, bson.M{"integration_id": id})
+	return err
+}
+
+func (r *MongoRepository) SaveMapping(ctx context.Context, mapping Mapping) error {
+	_, err := r.mappings.ReplaceOne(ctx, bson.M{"integration_id": mapping.IntegrationID, "external_id": mapping.ExternalID},
+		mapping, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) GetMapping(ctx context.Context, integrationID, externalID string) (Mapping, error) {
+	var mapping Mapping
+	err := r.mappings.FindOne(ctx, bson.M{"integration_id": integrationID, "external_id": externalID}).Decode(&mapping)
+	return mapping, store.MongoErr(err)
+}
+
+func (r *MongoRepository) ListMappings(ctx context.Context, integrationID string) ([]Mapping, error) {
+	cursor, err := r.mappings.Find(ctx, bson.M{"integration_id": integrationID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var mappings []Mapping
+	err = cursor.All(ctx, &mappings)
+	return mappings, err
+}
+
+func (r *MongoRepository) DeleteMapping(ctx context.Context, integrationID, externalID string) error {
+	result, err := r.mappings.DeleteOne(ctx, bson.M{"integration_id": integrationID, "external_id": externalID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) CreateReceipt(ctx context.Context, receipt Receipt) error {
+	_, err := r.receipts.InsertOne(ctx, receipt)
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	}
+	return err
+}
+
+func (r *MongoRepository) GetReceipt(ctx context.Context, id string) (Receipt, error) {
+	var receipt Receipt
+	err := r.receipts.FindOne(ctx, bson.M{"_id": id}).Decode(&receipt)
+	return receipt, store.MongoErr(err)
+}
+
+func (r *MongoRepository) UpdateReceipt(ctx context.Context, receipt Receipt) error {
+	result, err := r.receipts.ReplaceOne(ctx, bson.M{"_id": receipt.ID}, receipt)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) ListReceipts(ctx context.Context, integrationID, status string, limit int) ([]Receipt, error) {
+	query := bson.M{"integration_id": integrationID}
+	if status != "" {
+		query["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "received_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.receipts.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	var receipts []Receipt
+	err = cursor.All(ctx, &receipts)
+	return receipts, err
+}
+
+func (r *MongoRepository) DeleteUser(ctx context.Context, userID string) error {
+	if _, err := r.mappings.DeleteMany(ctx, bson.M{"user_id": userID}); err != nil {
+		return err
+	}
+	_, err := r.receipts.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
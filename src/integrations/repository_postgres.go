@@ -0,0 +1,176 @@
+package integrations
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	integrationColumns = "id, name, institution, match_email, secret, created_by, created_at"
+	mappingColumns     = "integration_id, external_id, user_id, created_at"
+	receiptColumns     = `id, integration_id, event_id, type, external_user_id, status, user_id, certificate_id, error, payload,
+	received_at, processed_at`
+)
+
+// PostgresRepository stores integrations in the integrations table, the mappings of their users in
+// integration_mappings and the events they pushed in integration_receipts
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, in Integration) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO integrations ("+integrationColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		in.ID, in.Name, in.Institution, in.MatchEmail, in.Secret, in.CreatedBy, in.CreatedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, id string) (Integration, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+integrationColumns+" FROM integrations WHERE id = $1", id)
+	if err != nil {
+		return Integration{}, err
+	}
+	integration, err := pgx.CollectExactlyOneRow(rows, scanIntegration)
+	return integration, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) List(ctx context.Context) ([]Integration, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+integrationColumns+" FROM integrations ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanIntegration)
+}
+
+func (r *PostgresRepository) SetSecret(ctx context.Context, id, secret string) error {
+	tag, err := r.pool.Exec(ctx, "UPDATE integrations SET secret = $2 WHERE id = $1", id, secret)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
+	// The mappings and receipts are removed with the integration by their foreign keys
+	tag, err := r.pool.Exec(ctx, "DELETE FROM integrations WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) SaveMapping(ctx context.Context, m Mapping) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO integration_mappings ("+mappingColumns+`) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (integration_id, external_id) DO UPDATE SET user_id = EXCLUDED.user_id, created_at = EXCLUDED.created_at`,
+		m.IntegrationID, m.ExternalID, m.UserID, m.CreatedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) GetMapping(ctx context.Context, integrationID, externalID string) (Mapping, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+mappingColumns+" FROM integration_mappings WHERE integration_id = $1 AND external_id = $2",
+		integrationID, externalID)
+	if err != nil {
+		return Mapping{}, err
+	}
+	mapping, err := pgx.CollectExactlyOneRow(rows, scanMapping)
+	return mapping, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) ListMappings(ctx context.Context, integrationID string) ([]Mapping, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+mappingColumns+" FROM integration_mappings WHERE integration_id = $1 ORDER BY created_at",
+		integrationID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanMapping)
+}
+
+func (r *PostgresRepository) DeleteMapping(ctx context.Context, integrationID, externalID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM integration_mappings WHERE integration_id = $1 AND external_id = $2", integrationID, externalID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) CreateReceipt(ctx context.Context, rc Receipt) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO integration_receipts ("+receiptColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		rc.ID, rc.IntegrationID, rc.EventID, rc.Type, rc.ExternalUserID, rc.Status, rc.UserID, rc.CertificateID, rc.Error,
+		rc.Payload, rc.ReceivedAt, rc.ProcessedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) GetReceipt(ctx context.Context, id string) (Receipt, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+receiptColumns+" FROM integration_receipts WHERE id = $1", id)
+	if err != nil {
+		return Receipt{}, err
+	}
+	receipt, err := pgx.CollectExactlyOneRow(rows, scanReceipt)
+	return receipt, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) UpdateReceipt(ctx context.Context, rc Receipt) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE integration_receipts SET status = $2, user_id = $3, certificate_id = $4, error = $5,
+		processed_at = $6 WHERE id = $1`, rc.ID, rc.Status, rc.UserID, rc.CertificateID, rc.Error, rc.ProcessedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListReceipts(ctx context.Context, integrationID, status string, limit int) ([]Receipt, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+receiptColumns+` FROM integration_receipts
+		WHERE integration_id = $1 AND ($2 = '' OR status = $2) ORDER BY received_at DESC LIMIT $3`, integrationID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanReceipt)
+}
+
+func (r *PostgresRepository) DeleteUser(ctx context.Context, userID string) error {
+	if _, err := r.pool.Exec(ctx, "DELETE FROM integration_mappings WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	_, err := r.pool.Exec(ctx, "DELETE FROM integration_receipts WHERE user_id = $1", userID)
+	return err
+}
+
+func scanIntegration(row pgx.CollectableRow) (Integration, error) {
+	var in Integration
+	err := row.Scan(&in.ID, &in.Name, &in.Institution, &in.MatchEmail, &in.Secret, &in.CreatedBy, &in.CreatedAt)
+	return in, err
+}
+
+func scanMapping(row pgx.CollectableRow) (Mapping, error) {
+	var m Mapping
+	err := row.Scan(&m.IntegrationID, &m.ExternalID, &m.UserID, &m.CreatedAt)
+	return m, err
+}
+
+func scanReceipt(row pgx.CollectableRow) (Receipt, error) {
+	var rc Receipt
+	err := row.Scan(&rc.ID, &rc.IntegrationID, &rc.EventID, &rc.Type, &rc.ExternalUserID, &rc.Status, &rc.UserID,
+		&rc.CertificateID, &rc.Error, &rc.Payload, &rc.ReceivedAt, &rc.ProcessedAt)
+	return rc, err
+}
@@ -0,0 +1,73 @@
+package integrations
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, integration Integration) error {
+	return r.repos.For(ctx).Create(ctx, integration)
+}
+
+func (r *TenantRepository) Get(ctx context.Context, id string) (Integration, error) {
+	return r.repos.For(ctx).Get(ctx, id)
+}
+
+func (r *TenantRepository) List(ctx context.Context) ([]Integration, error) {
+	return r.repos.For(ctx).List(ctx)
+}
+
+func (r *TenantRepository) SetSecret(ctx context.Context, id, secret string) error {
+	return r.repos.For(ctx).SetSecret(ctx, id, secret)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, id string) error {
+	return r.repos.For(ctx).Delete(ctx, id)
+}
+
+func (r *TenantRepository) SaveMapping(ctx context.Context, mapping Mapping) error {
+	return r.repos.For(ctx).SaveMapping(ctx, mapping)
+}
+
+func (r *TenantRepository) GetMapping(ctx context.Context, integrationID, externalID string) (Mapping, error) {
+	return r.repos.For(ctx).GetMapping(ctx, integrationID, externalID)
+}
+
+func (r *TenantRepository) ListMappings(ctx context.Context, integrationID string) ([]Mapping, error) {
+	return r.repos.For(ctx).ListMappings(ctx, integrationID)
+}
+
+func (r *TenantRepository) DeleteMapping(ctx context.Context, integrationID, externalID string) error {
+	return r.repos.For(ctx).DeleteMapping(ctx, integrationID, externalID)
+}
+
+func (r *TenantRepository) CreateReceipt(ctx context.Context, receipt Receipt) error {
+	return r.repos.For(ctx).CreateReceipt(ctx, receipt)
+}
+
+func (r *TenantRepository) GetReceipt(ctx context.Context, id string) (Receipt, error) {
+	return r.repos.For(ctx).GetReceipt(ctx, id)
+}
+
+func (r *TenantRepository) UpdateReceipt(ctx context.Context, receipt Receipt) error {
+	return r.repos.For(ctx).UpdateReceipt(ctx, receipt)
+}
+
+func (r *TenantRepository) ListReceipts(ctx context.Context, integrationID, status string, limit int) ([]Receipt, error) {
+	return r.repos.For(ctx).ListReceipts(ctx, integrationID, status, limit)
+}
+
+func (r *TenantRepository) DeleteUser(ctx context.Context, userID string) error {
+	return r.repos.For(ctx).DeleteUser(ctx, userID)
+}
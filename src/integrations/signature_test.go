@@ -0,0 +1,52 @@
+package integrations
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// sign returns the signature of the payload at the timestamp, as a sender of events computes it
+func sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "integration-secret"
+	now := time.Unix(1700000000, 0)
+	payload := []byte(`{"id":"evt_1","type":"course.completed"}`)
+	at := func(d time.Duration) string { return strconv.FormatInt(now.Add(d).Unix(), 10) }
+
+	for _, tc := range []struct {
+		name      string
+		signature string
+		timestamp string
+		payload   []byte
+		ok        bool
+	}{
+		{"valid", sign(secret, at(0), payload), at(0), payload, true},
+		{"within the tolerance", sign(secret, at(-4*time.Minute), payload), at(-4 * time.Minute), payload, true},
+		{"replayed after the tolerance", sign(secret, at(-6*time.Minute), payload), at(-6 * time.Minute), payload, false},
+		{"dated in the future", sign(secret, at(6*time.Minute), payload), at(6 * time.Minute), payload, false},
+		{"timestamp changed", sign(secret, at(-time.Minute), payload), at(0), payload, false},
+		{"payload changed", sign(secret, at(0), payload), at(0), []byte(`{"id":"evt_2"}`), false},
+		{"other secret", sign("other-secret", at(0), payload), at(0), payload, false},
+		{"no scheme", sign(secret, at(0), payload)[len("sha256="):], at(0), payload, false},
+		{"not hex", "sha256=zz", at(0), payload, false},
+		{"empty", "", at(0), payload, false},
+		{"malformed timestamp", sign(secret, "soon", payload), "soon", payload, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verifySignature(tc.signature, tc.timestamp, tc.payload, secret, now)
+			if (err == nil) != tc.ok {
+				t.Errorf("got error %v, want valid %t", err, tc.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+// ListJobs lists queued, running and finished jobs
+//
+//	@Summary		List background jobs
+//	@Description	Lists background jobs, newest first, optionally filtered by status. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			status	query		string	false	"Job status"	Enums(pending, running, succeeded, dead)
+//	@Param			limit	query		int		false	"Maximum number of jobs to return (default 50, max 500)"
+//	@Success		200		{array}		Job
+//	@Failure		400		{object}	apierror.Response	"Invalid status"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve jobs"
+//	@Router			/admin/jobs [get]
+func ListJobs(c *gin.Context) {
+	status := c.Query("status")
+	if status != "" && !isValidStatus(status) {
+		apierror.Abort(c, apierror.BadRequest("Invalid status"))
+		return
+	}
+	limit := defaultListLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, maxListLimit)
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	jobs, err := queue.List(ctx, status, limit)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve jobs"))
+		return
+	}
+	if jobs == nil {
+		jobs = []Job{}
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// GetJob retrieves a single job
+//
+//	@Summary		Get a background job
+//	@Description	Retrieves a background job, including the error from its last failed attempt. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			jobid	path		string	true	"Job ID"
+//	@Success		200		{object}	Job
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		404		{object}	apierror.Response	"Job not found"
+//	@Router			/admin/jobs/{jobid} [get]
+func GetJob(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	job, err := queue.Get(ctx, c.Param("jobid"))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Job not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// RetryJob returns a dead job to the queue
+//
+//	@Summary		Retry a dead job
+//	@Description	Returns a job from the dead-letter state to the queue with its attempts reset. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			jobid	path		string	true	"Job ID"
+//	@Success		200		{object}	map[string]string
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		404		{object}	apierror.Response	"Dead job not found"
+//	@Router			/admin/jobs/{jobid}/retry [post]
+func RetryJob(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := queue.Requeue(ctx, c.Param("jobid"), time.Now()); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Dead job not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Job requeued"})
+}
+
+// InitializeRoutes registers the job inspection endpoints. The router must only admit admins.
+func InitializeRoutes(router *gin.RouterGroup) {
+	router.GET("", ListJobs)
+	router.GET("/:jobid", GetJob)
+	router.POST("/:jobid/retry", RetryJob)
+}
@@ -0,0 +1,77 @@
+// Package jobs runs background work, such as sending email, on a pool of workers fed by a persistent queue.
+// Failed jobs are retried with exponential backoff and moved to the dead-letter state once they run out of attempts.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Job statuses
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusDead      = "dead"
+)
+
+// Job is a unit of background work, run by the handler registered for its type
+type Job struct {
+	ID   string `bson:"_id" json:"id"`
+	Type string `bson:"type" json:"type"`
+	// Tenant is the tenant the job was queued for, whose storage the handler works with
+	Tenant      string          `bson:"tenant,omitempty" json:"tenant,omitempty"`
+	Payload     json.RawMessage `bson:"payload" json:"payload" swaggertype:"object"`
+	Status      string          `bson:"status" json:"status"`
+	Attempts    int             `bson:"attempts" json:"attempts"`
+	MaxAttempts int             `bson:"max_attempts" json:"maxAttempts"`
+	// RunAt is the earliest time the next attempt may start
+	RunAt time.Time `bson:"run_at" json:"runAt"`
+	// LockedUntil is when a running job's lease expires and another worker may take it over
+	LockedUntil *time.Time `bson:"locked_until,omitempty" json:"lockedUntil,omitempty"`
+	LastError   string     `bson:"last_error,omitempty" json:"lastError,omitempty"`
+	CreatedAt   time.Time  `bson:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time  `bson:"updated_at" json:"updatedAt"`
+	FinishedAt  *time.Time `bson:"finished_at,omitempty" json:"finishedAt,omitempty"`
+}
+
+// Queue stores jobs and hands them out to workers
+type Queue interface {
+	// Enqueue stores a new pending job
+	Enqueue(ctx context.Context, job Job) error
+	// Claim leases the next due job to the caller until now+lease, counting the attempt. Running jobs whose
+	// lease expired are claimed again. It returns nil when no job is due.
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (*Job, error)
+	// Complete marks the job as succeeded
+	Complete(ctx context.Context, jobID string, at time.Time) error
+	// Retry releases the job to run again at runAt, recording why the attempt failed
+	Retry(ctx context.Context, jobID string, runAt time.Time, lastError string) error
+	// Bury moves the job to the dead-letter state, recording why the last attempt failed
+	Bury(ctx context.Context, jobID string, at time.Time, lastError string) error
+	// Requeue returns a dead job to the queue with its attempts reset, or returns store.ErrNotFound
+	Requeue(ctx context.Context, jobID string, at time.Time) error
+	// Get returns the job with the given ID, or store.ErrNotFound
+	Get(ctx context.Context, jobID string) (Job, error)
+	// List returns up to limit jobs, newest first, optionally only those with the given status
+	List(ctx context.Context, status string, limit int) ([]Job, error)
+	// Purge removes succeeded and dead jobs that finished before the given time, returning how many were removed
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// isValidStatus reports whether status is one of the job statuses
+func isValidStatus(status string) bool {
+	switch status {
+	case StatusPending, StatusRunning, StatusSucceeded, StatusDead:
+		return true
+	}
+	return false
+}
+
+// sortNewestFirst orders jobs by creation time, newest first
+func sortNewestFirst(jobs []Job) {
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+}
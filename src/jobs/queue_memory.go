@@ -0,0 +1,147 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryQueue keeps jobs in memory, used with in-memory storage. Queued jobs are lost when the server stops.
+type MemoryQueue struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// NewMemoryQueue creates an empty in-memory queue
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{jobs: map[string]*Job{}}
+}
+
+func (q *MemoryQueue) Enqueue(ctx context.Context, job Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs[job.ID] = &job
+	return nil
+}
+
+func (q *MemoryQueue) Claim(ctx context.Context, now time.Time, lease time.Duration) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var next *Job
+	for _, job := range q.jobs {
+		due := (job.Status == StatusPending && !job.RunAt.After(now)) ||
+			(job.Status == StatusRunning && job.LockedUntil != nil && !job.LockedUntil.After(now))
+		if due && (next == nil || job.RunAt.Before(next.RunAt)) {
+			next = job
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	lockedUntil := now.Add(lease)
+	next.Status = StatusRunning
+	next.Attempts++
+	next.LockedUntil = &lockedUntil
+	next.UpdatedAt = now
+	claimed := *next
+	return &claimed, nil
+}
+
+func (q *MemoryQueue) Complete(ctx context.Context, jobID string, at time.Time) error {
+	return q.update(jobID, func(job *Job) {
+		job.Status = StatusSucceeded
+		job.LockedUntil = nil
+		job.UpdatedAt = at
+		job.FinishedAt = &at
+	})
+}
+
+func (q *MemoryQueue) Retry(ctx context.Context, jobID string, runAt time.Time, lastError string) error {
+	return q.update(jobID, func(job *Job) {
+		job.Status = StatusPending
+		job.RunAt = runAt
+		job.LockedUntil = nil
+		job.LastError = lastError
+		job.UpdatedAt = time.Now()
+	})
+}
+
+func (q *MemoryQueue) Bury(ctx context.Context, jobID string, at time.Time, lastError string) error {
+	return q.update(jobID, func(job *Job) {
+		job.Status = StatusDead
+		job.LockedUntil = nil
+		job.LastError = lastError
+		job.UpdatedAt = at
+		job.FinishedAt = &at
+	})
+}
+
+func (q *MemoryQueue) Requeue(ctx context.Context, jobID string, at time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[jobID]
+	if !ok || job.Status != StatusDead {
+		return store.ErrNotFound
+	}
+	job.Status = StatusPending
+	job.Attempts = 0
+	job.RunAt = at
+	job.UpdatedAt = at
+	job.FinishedAt = nil
+	return nil
+}
+
+func (q *MemoryQueue) Get(ctx context.Context, jobID string) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[jobID]
+	if !ok {
+		return Job{}, store.ErrNotFound
+	}
+	return *job, nil
+}
+
+func (q *MemoryQueue) List(ctx context.Context, status string, limit int) ([]Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var jobs []Job
+	for _, job := range q.jobs {
+		if status == "" || job.Status == status {
+			jobs = append(jobs, *job)
+		}
+	}
+	sortNewestFirst(jobs)
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+func (q *MemoryQueue) Purge(ctx context.Context, before time.Time) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var removed int64
+	for id, job := range q.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(before) {
+			delete(q.jobs, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// update applies the change to the stored job
+func (q *MemoryQueue) update(jobID string, change func(job *Job)) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[jobID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	change(job)
+	return nil
+}
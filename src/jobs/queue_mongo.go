@@ -0,0 +1,126 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoQueue stores jobs in the jobs collection
+type MongoQueue struct {
+	jobs *mongo.Collection
+}
+
+// NewMongoQueue creates a queue backed by the given database
+func NewMongoQueue(db *mongo.Database) *MongoQueue {
+	return &MongoQueue{jobs: db.Collection("jobs")}
+}
+
+func (q *MongoQueue) Enqueue(ctx context.Context, job Job) error {
+	_, err := q.jobs.InsertOne(ctx, job)
+	return err
+}
+
+func (q *MongoQueue) Claim(ctx context.Context, now time.Time, lease time.Duration) (*Job, error) {
+	var job Job
+	err := q.jobs.FindOneAndUpdate(
+		ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"status": StatusPending, "run_at": bson.M{"$lte": now}},
+			bson.M{"status": StatusRunning, "locked_until": bson.M{"$lte": now}},
+		}},
+		bson.M{
+			"$set": bson.M{"status": StatusRunning, "locked_until": now.Add(lease), "updated_at": now},
+			"$inc": bson.M{"attempts": 1},
+		},
+		options.FindOneAndUpdate().SetSort(bson.M{"run_at": 1}).SetReturnDocument(options.After),
+	).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (q *MongoQueue) Complete(ctx context.Context, jobID string, at time.Time) error {
+	return q.update(ctx, bson.M{"_id": jobID}, bson.M{
+		"$set":   bson.M{"status": StatusSucceeded, "updated_at": at, "finished_at": at},
+		"$unset": bson.M{"locked_until": ""},
+	})
+}
+
+func (q *MongoQueue) Retry(ctx context.Context, jobID string, runAt time.Time, lastError string) error {
+	return q.update(ctx, bson.M{"_id": jobID}, bson.M{
+		"$set":   bson.M{"status": StatusPending, "run_at": runAt, "last_error": lastError, "updated_at": time.Now()},
+		"$unset": bson.M{"locked_until": ""},
+	})
+}
+
+func (q *MongoQueue) Bury(ctx context.Context, jobID string, at time.Time, lastError string) error {
+	return q.update(ctx, bson.M{"_id": jobID}, bson.M{
+		"$set":   bson.M{"status": StatusDead, "last_error": lastError, "updated_at": at, "finished_at": at},
+		"$unset": bson.M{"locked_until": ""},
+	})
+}
+
+func (q *MongoQueue) Requeue(ctx context.Context, jobID string, at time.Time) error {
+	return q.update(ctx, bson.M{"_id": jobID, "status": StatusDead}, bson.M{
+		"$set":   bson.M{"status": StatusPending, "attempts": 0, "run_at": at, "updated_at": at},
+		"$unset": bson.M{"finished_at": ""},
+	})
+}
+
+func (q *MongoQueue) Get(ctx context.Context, jobID string) (Job, error) {
+	var job Job
+	err := q.jobs.FindOne(ctx, bson.M{"_id": jobID}).Decode(&job)
+	return job, store.MongoErr(err)
+}
+
+func (q *MongoQueue) List(ctx context.Context, status string, limit int) ([]Job, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	cursor, err := q.jobs.Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []Job
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (q *MongoQueue) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := q.jobs.DeleteMany(ctx, bson.M{
+		"status":      bson.M{"$in": bson.A{StatusSucceeded, StatusDead}},
+		"finished_at": bson.M{"$lt": before},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// update applies the update to the job matching the filter, returning store.ErrNotFound when none matches
+func (q *MongoQueue) update(ctx context.Context, filter, update bson.M) error {
+	res, err := q.jobs.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const jobColumns = `id, type, tenant, payload, status, attempts, max_attempts, run_at, locked_until, last_error,
+	created_at, updated_at, finished_at`
+
+// PostgresQueue stores jobs in the jobs table
+type PostgresQueue struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresQueue creates a queue backed by the given connection pool
+func NewPostgresQueue(pool *pgxpool.Pool) *PostgresQueue {
+	return &PostgresQueue{pool: pool}
+}
+
+func (q *PostgresQueue) Enqueue(ctx context.Context, job Job) error {
+	_, err := q.pool.Exec(ctx, `INSERT INTO jobs (id, type, tenant, payload, status, attempts, max_attempts, run_at,
+		last_error, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		job.ID, job.Type, job.Tenant, job.Payload, job.Status, job.Attempts, job.MaxAttempts, job.RunAt,
+		job.LastError, job.CreatedAt, job.UpdatedAt)
+	return store.PostgresErr(err)
+}
+
+func (q *PostgresQueue) Claim(ctx context.Context, now time.Time, lease time.Duration) (*Job, error) {
+	// SKIP LOCKED lets concurrent workers claim different jobs without waiting on each other
+	rows, err := q.pool.Query(ctx, `UPDATE jobs SET status = $2, locked_until = $3, attempts = attempts + 1, updated_at = $1
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE (status = $4 AND run_at <= $1) OR (status = $2 AND locked_until <= $1)
+			ORDER BY run_at LIMIT 1 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns,
+		now, StatusRunning, now.Add(lease), StatusPending)
+	if err != nil {
+		return nil, err
+	}
+	job, err := pgx.CollectExactlyOneRow(rows, scanJob)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (q *PostgresQueue) Complete(ctx context.Context, jobID string, at time.Time) error {
+	return q.update(ctx, "UPDATE jobs SET status = $2, locked_until = NULL, updated_at = $3, finished_at = $3 WHERE id = $1",
+		jobID, StatusSucceeded, at)
+}
+
+func (q *PostgresQueue) Retry(ctx context.Context, jobID string, runAt time.Time, lastError string) error {
+	return q.update(ctx, "UPDATE jobs SET status = $2, run_at = $3, last_error = $4, locked_until = NULL, updated_at = now() WHERE id = $1",
+		jobID, StatusPending, runAt, lastError)
+}
+
+func (q *PostgresQueue) Bury(ctx context.Context, jobID string, at time.Time, lastError string) error {
+	return q.update(ctx, "UPDATE jobs SET status = $2, last_error = $4, locked_until = NULL, updated_at = $3, finished_at = $3 WHERE id = $1",
+		jobID, StatusDead, at, lastError)
+}
+
+func (q *PostgresQueue) Requeue(ctx context.Context, jobID string, at time.Time) error {
+	return q.update(ctx, "UPDATE jobs SET status = $3, attempts = 0, run_at = $4, updated_at = $4, finished_at = NULL WHERE id = $1 AND status = $2",
+		jobID, StatusDead, StatusPending, at)
+}
+
+func (q *PostgresQueue) Get(ctx context.Context, jobID string) (Job, error) {
+	rows, err := q.pool.Query(ctx, "SELECT "+jobColumns+" FROM jobs WHERE id = $1", jobID)
+	if err != nil {
+		return Job{}, err
+	}
+	job, err := pgx.CollectExactlyOneRow(rows, scanJob)
+	return job, store.PostgresErr(err)
+}
+
+func (q *PostgresQueue) List(ctx context.Context, status string, limit int) ([]Job, error) {
+	rows, err := q.pool.Query(ctx, "SELECT "+jobColumns+" FROM jobs WHERE $1 = '' OR status = $1 ORDER BY created_at DESC LIMIT $2",
+		status, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanJob)
+}
+
+func (q *PostgresQueue) Purge(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := q.pool.Exec(ctx, "DELETE FROM jobs WHERE status IN ($1, $2) AND finished_at < $3",
+		StatusSucceeded, StatusDead, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// update runs the statement, returning store.ErrNotFound when it matched no job
+func (q *PostgresQueue) update(ctx context.Context, query string, args ...any) error {
+	tag, err := q.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// scanJob reads a row selected with jobColumns
+func scanJob(row pgx.CollectableRow) (Job, error) {
+	var job Job
+	err := row.Scan(&job.ID, &job.Type, &job.Tenant, &job.Payload, &job.Status, &job.Attempts, &job.MaxAttempts, &job.RunAt,
+		&job.LockedUntil, &job.LastError, &job.CreatedAt, &job.UpdatedAt, &job.FinishedAt)
+	return job, err
+}
@@ -0,0 +1,253 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keys used by the queue. Each job is stored as JSON under jobKeyPrefix+ID, and indexed in a
+// sorted set per status scored by creation time for listing.
+const (
+	jobKeyPrefix    = "jobs:job:"
+	statusKeyPrefix = "jobs:status:"
+	// dueKey holds pending job IDs scored by the time they may run
+	dueKey = "jobs:due"
+	// leasedKey holds running job IDs scored by the time their lease expires
+	leasedKey = "jobs:leased"
+)
+
+// claimScript atomically takes the first job whose lease expired, or else the first due job, and leases it
+var claimScript = redis.NewScript(`
+local id = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1], 'LIMIT', 0, 1)[1]
+if not id then
+	id = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, 1)[1]
+end
+if not id then
+	return false
+end
+redis.call('ZREM', KEYS[1], id)
+redis.call('ZADD', KEYS[2], ARGV[2], id)
+return id
+`)
+
+// RedisQueue stores jobs in Redis
+type RedisQueue struct {
+	client *redis.Client
+}
+
+// NewRedisQueue connects to the Redis server at url and checks that it is reachable
+func NewRedisQueue(ctx context.Context, url string) (*RedisQueue, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error pinging Redis: %w", err)
+	}
+	return &RedisQueue{client: client}, nil
+}
+
+// Close closes the connection to Redis
+func (q *RedisQueue) Close() error {
+	return q.client.Close()
+}
+
+func (q *RedisQueue) Enqueue(ctx context.Context, job Job) error {
+	return q.save(ctx, job, "")
+}
+
+func (q *RedisQueue) Claim(ctx context.Context, now time.Time, lease time.Duration) (*Job, error) {
+	id, err := claimScript.Run(ctx, q.client, []string{dueKey, leasedKey}, now.UnixMilli(), now.Add(lease).UnixMilli()).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := q.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previous := job.Status
+	lockedUntil := now.Add(lease)
+	job.Status = StatusRunning
+	job.Attempts++
+	job.LockedUntil = &lockedUntil
+	job.UpdatedAt = now
+	if err := q.save(ctx, job, previous); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (q *RedisQueue) Complete(ctx context.Context, jobID string, at time.Time) error {
+	return q.update(ctx, jobID, func(job *Job) {
+		job.Status = StatusSucceeded
+		job.LockedUntil = nil
+		job.UpdatedAt = at
+		job.FinishedAt = &at
+	})
+}
+
+func (q *RedisQueue) Retry(ctx context.Context, jobID string, runAt time.Time, lastError string) error {
+	return q.update(ctx, jobID, func(job *Job) {
+		job.Status = StatusPending
+		job.RunAt = runAt
+		job.LockedUntil = nil
+		job.LastError = lastError
+		job.UpdatedAt = time.Now()
+	})
+}
+
+func (q *RedisQueue) Bury(ctx context.Context, jobID string, at time.Time, lastError string) error {
+	return q.update(ctx, jobID, func(job *Job) {
+		job.Status = StatusDead
+		job.LockedUntil = nil
+		job.LastError = lastError
+		job.UpdatedAt = at
+		job.FinishedAt = &at
+	})
+}
+
+func (q *RedisQueue) Requeue(ctx context.Context, jobID string, at time.Time) error {
+	job, err := q.Get(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status != StatusDead {
+		return store.ErrNotFound
+	}
+	job.Status = StatusPending
+	job.Attempts = 0
+	job.RunAt = at
+	job.UpdatedAt = at
+	job.FinishedAt = nil
+	return q.save(ctx, job, StatusDead)
+}
+
+func (q *RedisQueue) Get(ctx context.Context, jobID string) (Job, error) {
+	var job Job
+	data, err := q.client.Get(ctx, jobKeyPrefix+jobID).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return job, store.ErrNotFound
+	}
+	if err != nil {
+		return job, err
+	}
+	err = json.Unmarshal(data, &job)
+	return job, err
+}
+
+func (q *RedisQueue) List(ctx context.Context, status string, limit int) ([]Job, error) {
+	statuses := []string{StatusPending, StatusRunning, StatusSucceeded, StatusDead}
+	if status != "" {
+		statuses = []string{status}
+	}
+
+	var jobs []Job
+	for _, s := range statuses {
+		ids, err := q.client.ZRevRange(ctx, statusKeyPrefix+s, 0, int64(limit)-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			job, err := q.Get(ctx, id)
+			if errors.Is(err, store.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			jobs = append(jobs, job)
+		}
+	}
+
+	sortNewestFirst(jobs)
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+// Purge checks the finished jobs created before the given time, as the status indexes are scored by creation time
+func (q *RedisQueue) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var removed int64
+	for _, status := range []string{StatusSucceeded, StatusDead} {
+		ids, err := q.client.ZRangeByScore(ctx, statusKeyPrefix+status, &redis.ZRangeBy{
+			Min: "-inf",
+			Max: strconv.FormatInt(before.UnixMilli(), 10),
+		}).Result()
+		if err != nil {
+			return removed, err
+		}
+		for _, id := range ids {
+			job, err := q.Get(ctx, id)
+			if err != nil && !errors.Is(err, store.ErrNotFound) {
+				return removed, err
+			}
+			if err == nil && (job.FinishedAt == nil || !job.FinishedAt.Before(before)) {
+				continue
+			}
+			_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Del(ctx, jobKeyPrefix+id)
+				pipe.ZRem(ctx, statusKeyPrefix+status, id)
+				return nil
+			})
+			if err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// update applies the change to the stored job
+func (q *RedisQueue) update(ctx context.Context, jobID string, change func(job *Job)) error {
+	job, err := q.Get(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	previous := job.Status
+	change(&job)
+	return q.save(ctx, job, previous)
+}
+
+// save stores the job and moves it from the previous status's indexes to those of its current status
+func (q *RedisQueue) save(ctx context.Context, job Job, previous string) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, jobKeyPrefix+job.ID, data, 0)
+		if previous != "" && previous != job.Status {
+			pipe.ZRem(ctx, statusKeyPrefix+previous, job.ID)
+		}
+		pipe.ZAdd(ctx, statusKeyPrefix+job.Status, redis.Z{Score: float64(job.CreatedAt.UnixMilli()), Member: job.ID})
+		switch job.Status {
+		case StatusPending:
+			pipe.ZRem(ctx, leasedKey, job.ID)
+			pipe.ZAdd(ctx, dueKey, redis.Z{Score: float64(job.RunAt.UnixMilli()), Member: job.ID})
+		case StatusRunning:
+			pipe.ZRem(ctx, dueKey, job.ID)
+			pipe.ZAdd(ctx, leasedKey, redis.Z{Score: float64(job.LockedUntil.UnixMilli()), Member: job.ID})
+		default:
+			pipe.ZRem(ctx, dueKey, job.ID)
+			pipe.ZRem(ctx, leasedKey, job.ID)
+		}
+		return nil
+	})
+	return err
+}
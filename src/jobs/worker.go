@@ -0,0 +1,169 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"profile-api/config"
+	"profile-api/tenant"
+	"profile-api/utils"
+)
+
+// maxBackoff caps the delay between attempts of a failing job
+const maxBackoff = time.Hour
+
+// Handler runs a job. Returning an error schedules a retry until the job runs out of attempts.
+type Handler func(ctx context.Context, job Job) error
+
+var (
+	queue    Queue
+	settings config.JobsConfig
+	handlers = map[string]Handler{}
+	workers  sync.WaitGroup
+)
+
+// Configure sets the queue jobs are stored in and the worker settings
+func Configure(q Queue, cfg config.JobsConfig) {
+	queue = q
+	settings = cfg
+}
+
+// Register sets the handler run for jobs of the given type. Handlers must be registered before Start.
+func Register(jobType string, h Handler) {
+	handlers[jobType] = h
+}
+
+// Enqueue stores a job of the given type to run as soon as a worker is free. The payload is encoded as JSON.
+func Enqueue(ctx context.Context, jobType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding %s job payload: %w", jobType, err)
+	}
+	now := time.Now()
+	return queue.Enqueue(ctx, Job{
+		ID:          utils.GenerateID(),
+		Type:        jobType,
+		Tenant:      tenant.ID(ctx),
+		Payload:     data,
+		Status:      StatusPending,
+		MaxAttempts: settings.MaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+}
+
+// Start runs the configured number of workers until the context is cancelled
+func Start(ctx context.Context) {
+	slog.Info("Starting job workers", "workers", settings.Workers)
+	for i := 0; i < settings.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			work(ctx)
+		}()
+	}
+}
+
+// Wait blocks until every worker has finished its current job after the Start context was cancelled, or
+// until ctx is done. Jobs cut short are claimed again once their lease expires.
+func Wait(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		slog.Warn("Stopped waiting for running jobs", "error", ctx.Err())
+	}
+}
+
+// Purge removes finished and dead jobs older than the configured retention
+func Purge(ctx context.Context) error {
+	removed, err := queue.Purge(ctx, time.Now().Add(-settings.Retention.Std()))
+	if err != nil {
+		return err
+	}
+	slog.Info("Purged finished jobs", "removed", removed)
+	return nil
+}
+
+// work claims and runs due jobs, polling the queue while it is empty
+func work(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimCtx, cancel := utils.WithOperationTimeout(ctx)
+		job, err := queue.Claim(claimCtx, time.Now(), settings.Lease.Std())
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			slog.Error("Error claiming job", "error", err)
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(settings.PollInterval.Std()):
+			}
+			continue
+		}
+		// A claimed job runs to completion even when shutting down, Wait lets it finish
+		run(context.WithoutCancel(ctx), *job)
+	}
+}
+
+// run executes the job's handler and records the outcome
+func run(ctx context.Context, job Job) {
+	log := slog.With("job_id", job.ID, "job_type", job.Type, "attempt", job.Attempts)
+
+	err := execute(ctx, job)
+	opCtx, cancel := utils.WithOperationTimeout(ctx)
+	defer cancel()
+	now := time.Now()
+	switch {
+	case err == nil:
+		log.Debug("Job succeeded")
+		err = queue.Complete(opCtx, job.ID, now)
+	case job.Attempts >= job.MaxAttempts:
+		log.Error("Job failed, moving to dead letters", "error", err)
+		err = queue.Bury(opCtx, job.ID, now, err.Error())
+	default:
+		delay := backoff(job.Attempts)
+		log.Warn("Job failed, retrying", "error", err, "delay", delay)
+		err = queue.Retry(opCtx, job.ID, now.Add(delay), err.Error())
+	}
+	if err != nil {
+		log.Error("Error recording job outcome", "error", err)
+	}
+}
+
+// execute runs the job's handler within its lease, converting a panic into an error
+func execute(ctx context.Context, job Job) (err error) {
+	handler, ok := handlers[job.Type]
+	if !ok {
+		return fmt.Errorf("no handler registered for job type %q", job.Type)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(tenant.WithID(ctx, job.Tenant), settings.Lease.Std())
+	defer cancel()
+	return handler(ctx, job)
+}
+
+// backoff returns the delay before the next attempt, doubling with each failed attempt plus up to 20% jitter
+func backoff(attempts int) time.Duration {
+	delay := settings.RetryBackoff.Std()
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxBackoff)
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
@@ -0,0 +1,469 @@
+package journal
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/netutil"
+	"profile-api/profile"
+	"profile-api/quota"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/html"
+)
+
+// Supported import formats
+const (
+	ImportFormatMedium    = "medium"
+	ImportFormatWordPress = "wordpress"
+)
+
+const (
+	maxImportSize      = 50 << 20
+	maxImportImageSize = 10 << 20
+	// An archive is bounded by what it unpacks to as well as its size, as a small one may unpack to far more
+	maxImportFiles        = 10000
+	maxImportPostSize     = 5 << 20
+	maxImportUnpackedSize = 200 << 20
+	// maxImportRedirects bounds the redirects followed fetching an image
+	maxImportRedirects = 5
+)
+
+// ImportResult reports the outcome of importing a single post
+type ImportResult struct {
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	JournalID string   `json:"journalID,omitempty"`
+	Images    int      `json:"images"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// importedPost is a post parsed from an export before it is stored
+type importedPost struct {
+	Title     string
+	Content   string
+	Published bool
+	CreatedAt time.Time
+	Taxonomy  Taxonomy
+}
+
+var imageSrcPattern = regexp.MustCompile(`<img[^>]+src="([^"]+)"`)
+
+var importHTTPClient = newImportClient()
+
+// newImportClient creates the client images referenced by imported posts are fetched with. As the posts come
+// from users, it refuses to connect to private addresses.
+func newImportClient() *http.Client {
+	client := netutil.SafeClient(30*time.Second, false)
+	// Image hosts commonly redirect to a CDN. Every hop is dialed through the client's checks, so a redirect
+	// can't reach an internal host either.
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxImportRedirects {
+			return errors.New("too many redirects")
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+		}
+		return nil
+	}
+	return client
+}
+
+// @Summary Import journal entries
+// @Description Import posts from a Medium ZIP export or a WordPress WXR export. Referenced images are downloaded into the image store. Published posts are imported as private entries and drafts as pending entries. Posts beyond the number of journal entries the user's plan allows are reported as failed. A Medium export may hold at most 10000 files, with posts of up to 5 MB and 200 MB in all.
+// @Tags journal
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "Medium ZIP or WordPress WXR export"
+// @Param format formData string false "Export format (medium or wordpress), detected from the file when omitted"
+// @Success 200 {array} ImportResult
+// @Failure 400 {object} apierror.Response "Error message"
+// @Failure 401 {object} apierror.Response "Error message"
+// @Failure 500 {object} apierror.Response "Error message"
+// @Router /journal/import [post]
+func ImportJournalEntries(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		apierror.Abort(c, apierror.Unauthorized("User not authenticated"))
+		return
+	}
+	userStruct, ok := user.(auth.User)
+	if !ok {
+		apierror.Abort(c, apierror.Internal("Failed to parse user information"))
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Export file not found"))
+		return
+	}
+	if fileHeader.Size > maxImportSize {
+		apierror.Abort(c, apierror.BadRequest("Export file is too large"))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Could not open export file"))
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Could not read export file"))
+		return
+	}
+
+	format := c.PostForm("format")
+	if format == "" {
+		format = detectImportFormat(fileHeader.Filename, data)
+	}
+
+	var posts []importedPost
+	switch format {
+	case ImportFormatMedium:
+		posts, err = parseMediumExport(data)
+	case ImportFormatWordPress:
+		posts, err = parseWordPressExport(data)
+	default:
+		apierror.Abort(c, apierror.BadRequest("Unsupported export format"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest(err.Error()))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	left, err := quota.DocumentsLeft(ctx, userStruct, "journal")
+	cancel()
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not count journal entries"))
+		return
+	}
+
+	results := make([]ImportResult, 0, len(posts))
+	for _, post := range posts {
+		// Posts past the quota are reported as failed, so the user sees which were left out
+		if left == 0 {
+			results = append(results, ImportResult{Title: post.Title, Status: "failed", Errors: []string{quota.DocumentLimitError(userStruct, "journal").Message}})
+			continue
+		}
+		result := importPost(c.Request.Context(), userStruct, post)
+		if result.Status == "imported" && left > 0 {
+			left--
+		}
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// detectImportFormat guesses the export format from the file name and contents
+func detectImportFormat(filename string, data []byte) string {
+	if strings.HasSuffix(strings.ToLower(filename), ".zip") || bytes.HasPrefix(data, []byte("PK")) {
+		return ImportFormatMedium
+	}
+	if bytes.Contains(data[:min(len(data), 4096)], []byte("wordpress.org/export")) {
+		return ImportFormatWordPress
+	}
+	return ""
+}
+
+// importPost downloads a post's images and stores it as a new journal entry
+func importPost(ctx context.Context, user auth.User, post importedPost) ImportResult {
+	result := ImportResult{Title: post.Title}
+
+	journalID := utils.GenerateID()
+	content, images, errs := importImages(ctx, user, journalID, post.Content)
+	result.Images = images
+	result.Errors = errs
+
+	// Published posts are kept private until the owner chooses to publish them on this site
+	status := StatusPending
+	if post.Published {
+		status = StatusPrivate
+	}
+
+	createdAt := post.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	journalEntry := JournalEntry{
+		JournalID: journalID,
+		UserID:    user.ID,
+		Version:   1,
+		Entries: []Entry{{
+			Version:   1,
+			Title:     post.Title,
+			Content:   content,
+			UpdatedAt: createdAt,
+		}},
+		Status:    status,
+		Taxonomy:  post.Taxonomy,
+		CreatedAt: createdAt,
+		UpdatedAt: time.Now(),
+	}
+
+	// Each post gets its own timeout as downloading the images may take a while
+	ctx, cancel := utils.WithOperationTimeout(ctx)
+	defer cancel()
+	if err := repo.Create(ctx, journalEntry); err != nil {
+		result.Status = "failed"
+		result.Errors = append(result.Errors, "Error creating journal entry")
+		return result
+	}
+
+	result.Status = "imported"
+	result.JournalID = journalEntry.JournalID
+	return result
+}
+
+// importImages copies every image referenced by the content into the image store and rewrites the references
+func importImages(ctx context.Context, user auth.User, journalID, content string) (string, int, []string) {
+	store := profile.GetImageStore(ctx)
+	if store == nil {
+		return content, 0, nil
+	}
+
+	var errs []string
+	imported := 0
+	seen := map[string]string{}
+	for _, match := range imageSrcPattern.FindAllStringSubmatch(content, -1) {
+		src := html.UnescapeString(match[1])
+		if _, done := seen[src]; done || !strings.HasPrefix(src, "http") {
+			continue
+		}
+		url, err := downloadImage(ctx, store, user, journalID, src)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Could not import image %s: %v", src, err))
+			seen[src] = ""
+			continue
+		}
+		seen[src] = url
+		imported++
+	}
+
+	for src, url := range seen {
+		if url != "" {
+			content = strings.ReplaceAll(content, src, url)
+			content = strings.ReplaceAll(content, html.EscapeString(src), url)
+		}
+	}
+	return content, imported, errs
+}
+
+// downloadImage fetches a remote image and saves it in the image store, counting it against the user's
+// storage
+func downloadImage(ctx context.Context, store profile.ImageStore, user auth.User, journalID, src string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := importHTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "image/") {
+		return "", fmt.Errorf("not an image")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImportImageSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxImportImageSize {
+		return "", fmt.Errorf("image is too large")
+	}
+
+	name := utils.GenerateID() + "-" + path.Base(strings.SplitN(src, "?", 2)[0])
+	upload := quota.NewUpload(user.ID, "journal", journalID, name, int64(len(data)))
+	if err := quota.CheckUpload(ctx, user, upload); err != nil {
+		return "", err
+	}
+	url, err := store.SaveImage(user.ID, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	trackUpload(ctx, Upload{UserID: user.ID, JournalID: journalID, URL: url, Name: name, Bytes: int64(len(data))})
+	return url, quota.RecordUpload(ctx, upload)
+}
+
+// parseMediumExport reads the posts from a Medium export archive
+func parseMediumExport(data []byte) ([]importedPost, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Medium export: %w", err)
+	}
+
+	if len(archive.File) > maxImportFiles {
+		return nil, fmt.Errorf("invalid Medium export: more than %d files", maxImportFiles)
+	}
+
+	var posts []importedPost
+	var unpacked int64
+	for _, f := range archive.File {
+		if !strings.HasPrefix(f.Name, "posts/") || !strings.HasSuffix(f.Name, ".html") {
+			continue
+		}
+		content, err := readArchived(f)
+		if err != nil {
+			return nil, err
+		}
+		if unpacked += int64(len(content)); unpacked > maxImportUnpackedSize {
+			return nil, fmt.Errorf("invalid Medium export: posts larger than %d MB", maxImportUnpackedSize>>20)
+		}
+		doc, err := html.Parse(bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("invalid Medium post %s: %w", f.Name, err)
+		}
+
+		post := importedPost{Published: !strings.HasPrefix(path.Base(f.Name), "draft_")}
+		walkHTML(doc, func(n *html.Node) bool {
+			switch {
+			case n.Data == "title" && post.Title == "":
+				post.Title = strings.TrimSpace(textContent(n))
+			case n.Data == "time" && hasClass(n, "dt-published"):
+				if t, err := time.Parse(time.RFC3339, attr(n, "datetime")); err == nil {
+					post.CreatedAt = t
+				}
+			case n.Data == "section" && attr(n, "data-field") == "body":
+				post.Content = renderChildren(n)
+				return false
+			}
+			return true
+		})
+		posts = append(posts, post)
+	}
+	return posts, nil
+}
+
+// readArchived reads a post from an archive, failing when it unpacks to more than a post may be. The size
+// the archive declares is not trusted, so reading stops once the limit is passed.
+func readArchived(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("invalid Medium export: %w", err)
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(io.LimitReader(rc, maxImportPostSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Medium post %s: %w", f.Name, err)
+	}
+	if len(content) > maxImportPostSize {
+		return nil, fmt.Errorf("invalid Medium post %s: larger than %d MB", f.Name, maxImportPostSize>>20)
+	}
+	return content, nil
+}
+
+// wxrExport is the subset of a WordPress WXR export used for importing
+type wxrExport struct {
+	Items []struct {
+		Title      string `xml:"title"`
+		Content    string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+		PostDate   string `xml:"http://wordpress.org/export/1.2/ post_date_gmt"`
+		Status     string `xml:"http://wordpress.org/export/1.2/ status"`
+		PostType   string `xml:"http://wordpress.org/export/1.2/ post_type"`
+		Categories []struct {
+			Domain string `xml:"domain,attr"`
+			Name   string `xml:",chardata"`
+		} `xml:"category"`
+	} `xml:"channel>item"`
+}
+
+// parseWordPressExport reads the posts from a WordPress WXR export
+func parseWordPressExport(data []byte) ([]importedPost, error) {
+	var export wxrExport
+	if err := xml.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid WordPress export: %w", err)
+	}
+
+	var posts []importedPost
+	for _, item := range export.Items {
+		if item.PostType != "post" {
+			continue
+		}
+		post := importedPost{
+			Title:     item.Title,
+			Content:   item.Content,
+			Published: item.Status == "publish",
+		}
+		if t, err := time.Parse("2006-01-02 15:04:05", item.PostDate); err == nil {
+			post.CreatedAt = t
+		}
+		for _, category := range item.Categories {
+			switch category.Domain {
+			case "category":
+				post.Taxonomy.Categories = append(post.Taxonomy.Categories, category.Name)
+			case "post_tag":
+				post.Taxonomy.Tags = append(post.Taxonomy.Tags, category.Name)
+			}
+		}
+		posts = append(posts, post)
+	}
+	return posts, nil
+}
+
+// walkHTML visits nodes depth first, skipping the children of nodes for which visit returns false
+func walkHTML(n *html.Node, visit func(*html.Node) bool) {
+	if n.Type == html.ElementNode && !visit(n) {
+		return
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		walkHTML(child, visit)
+	}
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(attr(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(textContent(child))
+	}
+	return b.String()
+}
+
+func renderChildren(n *html.Node) string {
+	var b bytes.Buffer
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		html.Render(&b, child)
+	}
+	return b.String()
+}
@@ -2,22 +2,25 @@ package journal
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"profile-api/apierror"
+	"profile-api/apiversion"
 	"profile-api/auth"
+	"profile-api/bulk"
+	"profile-api/dryrun"
+	"profile-api/events"
+	"profile-api/privacy"
+	"profile-api/quota"
+	"profile-api/scraping"
+	"profile-api/store"
 	"profile-api/utils"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-var journalCollection *mongo.Collection
-
-type ErrorResponse struct {
-	Message string `json:"message"`
-	Error   string `json:"error"`
-}
+var repo Repository
 
 type ProcessingResponse struct {
 	Message string `json:"message"`
@@ -35,55 +38,88 @@ type SuccessResponse struct {
 	Version   int    `json:"version"`
 	Status    string `json:"status"`
 	UserID    string `json:"userID"`
+	Revision  int    `json:"revision"`
 }
 
 // @Summary Create a new journal entry
-// @Description Create a new journal entry
+// @Description Create a new journal entry with the status and taxonomy of the user's journal settings
 // @Tags journal
 // @Accept json
 // @Produce json
 // @Param entry body Entry true "Journal Entry"
 // @Success 201 {object} JournalEntry
-// @Failure 400 {object} ErrorResponse "Error message"
-// @Failure 500 {object} ErrorResponse "Error message"
+// @Header 201 {string} Location "URL of the created journal entry"
+// @Failure 400 {object} apierror.Response "Error message"
+// @Failure 402 {object} apierror.Response "The user's plan allows no more journal entries"
+// @Failure 413 {object} apierror.Response "The site allows no more journal entries"
+// @Failure 500 {object} apierror.Response "Error message"
 // @Router /journal [post]
 func CreateJournalEntry(c *gin.Context) {
 	user, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		apierror.Abort(c, apierror.Unauthorized("User not authenticated"))
 		return
 	}
 
 	// Type assert the user to the correct type
 	userStruct, ok := user.(auth.User)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse user information"})
+		apierror.Abort(c, apierror.Internal("Failed to parse user information"))
 		return
 	}
 
 	var newEntry Entry
 	if err := c.ShouldBindJSON(&newEntry); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	journalEntry, err := Create(ctx, userStruct.ID, newEntry)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	apiversion.Location(c, journalEntry.JournalID)
+	c.JSON(http.StatusCreated, journalEntry)
+}
+
+// Create adds a journal entry for the user with the status and taxonomy of their journal settings, for the
+// journal endpoints and the other modules creating entries on a user's behalf
+func Create(ctx context.Context, userID string, entry Entry) (JournalEntry, error) {
+	settings, err := userSettings(ctx, userID)
+	if err != nil {
+		return JournalEntry{}, apierror.Wrap(err, "Error retrieving journal settings")
+	}
+
 	journalEntry := JournalEntry{
 		JournalID: utils.GenerateID(),
-		UserID:    userStruct.ID,
+		UserID:    userID,
 		Version:   1,
-		Entries:   []Entry{newEntry},
-		Status:    "pending",
+		Entries:   []Entry{entry},
+		Taxonomy:  settings.Taxonomy,
+		Status:    settings.initialStatus(),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	_, err := journalCollection.InsertOne(context.Background(), journalEntry)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating journal entry"})
-		return
+	if err := repo.Create(ctx, journalEntry); err != nil {
+		return JournalEntry{}, apierror.Wrap(err, "Error creating journal entry")
+	}
+	if journalEntry.Status != StatusPending {
+		// Entries are otherwise created pending, so subscribers learn of one created visible as if it changed
+		events.Publish(ctx, userID, events.TypeJournalStatusChanged,
+			gin.H{"journalID": journalEntry.JournalID, "from": StatusPending, "to": journalEntry.Status})
 	}
+	return journalEntry, nil
+}
 
-	c.JSON(http.StatusCreated, journalEntry)
+// countEntries counts the user's journal entries against their document quota
+func countEntries(ctx context.Context, userID string) (int, error) {
+	entries, err := repo.List(ctx, Filter{UserID: userID})
+	return len(entries), err
 }
 
 // @Summary Update a journal entry
@@ -93,25 +129,40 @@ func CreateJournalEntry(c *gin.Context) {
 // @Produce json
 // @Param journalid path string true "Journal ID"
 // @Param entry body Entry true "Updated Entry"
+// @Param If-Match header string false "ETag of the journal entry being updated, required unless require-if-match is off"
 // @Success 200 {object} JournalEntry
-// @Failure 400 {object} ErrorResponse "Error message"
-// @Failure 404 {object} ErrorResponse "Error message"
-// @Failure 500 {object} ErrorResponse "Error message"
+// @Header 200 {string} ETag "Revision of the updated journal entry"
+// @Failure 400 {object} apierror.Response "Error message"
+// @Failure 404 {object} apierror.Response "Error message"
+// @Failure 409 {object} apierror.Response "Error message"
+// @Failure 412 {object} apierror.Response "Error message"
+// @Failure 428 {object} apierror.Response "Error message"
+// @Failure 500 {object} apierror.Response "Error message"
 // @Router /journal/{journalid} [put]
 func UpdateJournalEntry(c *gin.Context) {
 	journalID := c.Param("journalid")
 	userID := c.MustGet("userID").(string)
+	precondition, apiErr := utils.IfMatch(c)
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return
+	}
 
 	var updatedEntry Entry
 	if err := c.ShouldBindJSON(&updatedEntry); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 
-	var journal JournalEntry
-	err := journalCollection.FindOne(context.Background(), bson.M{"journal_id": journalID, "user_id": userID}).Decode(&journal)
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	journal, err := repo.GetOwned(ctx, journalID, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Journal entry not found"})
+		apierror.Abort(c, apierror.NotFound("Journal entry not found"))
+		return
+	}
+	if !precondition.Allows(journal.Revision) {
+		apierror.Abort(c, errChanged)
 		return
 	}
 
@@ -121,34 +172,43 @@ func UpdateJournalEntry(c *gin.Context) {
 	journal.Version = updatedEntry.Version
 	journal.UpdatedAt = time.Now()
 
-	_, err = journalCollection.UpdateOne(
-		context.Background(),
-		bson.M{"journal_id": journalID, "user_id": userID},
-		bson.M{"$set": bson.M{"entries": journal.Entries, "version": journal.Version, "updated_at": journal.UpdatedAt}},
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating journal entry"})
+	if err := repo.SaveEntries(ctx, journal); err != nil {
+		apierror.Abort(c, writeError(err, precondition, "Error updating journal entry"))
 		return
 	}
 
+	journal.Revision++
+	c.Header("ETag", utils.RevisionETag(journal.Revision))
 	c.JSON(http.StatusOK, journal)
 }
 
 // @Summary Get journal metadata
-// @Description Get metadata for a journal entry by ID
+// @Description Get metadata for a journal entry by ID, found only when it is public or owned by the requester
 // @Tags journal
 // @Produce json
 // @Param journalid path string true "Journal ID"
-// @Success 200 {object} SuccessResponse "createdAt", "updatedAt", "version", "status", "userID"
-// @Failure 404 {object} ErrorResponse "Error message"
+// @Success 200 {object} SuccessResponse "createdAt", "updatedAt", "version", "status", "userID", "revision"
+// @Header 200 {string} ETag "Revision of the journal entry, for If-Match when updating it"
+// @Success 304 "Not modified"
+// @Failure 404 {object} apierror.Response "Error message"
 // @Router /journal/{journalid}/meta [get]
 func GetJournalMeta(c *gin.Context) {
 	journalID := c.Param("journalid")
 
-	var journal JournalEntry
-	err := journalCollection.FindOne(context.Background(), bson.M{"journal_id": journalID}).Decode(&journal)
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	journal, err := repo.Get(ctx, journalID)
+	if err != nil {
+		apierror.Abort(c, apierror.NotFound("Journal entry not found"))
+		return
+	}
+	readable, err := Readable(ctx, c, journal)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Journal entry not found"})
+		apierror.Abort(c, apierror.Wrap(err, "Error retrieving journal entry"))
+		return
+	}
+	if !readable {
+		apierror.Abort(c, apierror.NotFound("Journal entry not found"))
 		return
 	}
 
@@ -158,9 +218,10 @@ func GetJournalMeta(c *gin.Context) {
 		"version":   journal.Version,
 		"status":    journal.Status,
 		"userID":    journal.UserID,
+		"revision":  journal.Revision,
 	}
 
-	c.JSON(http.StatusOK, meta)
+	utils.RevisionJSON(c, meta, journal.Revision, journal.UpdatedAt)
 }
 
 // @Summary Process a journal entry
@@ -169,20 +230,28 @@ func GetJournalMeta(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param journalid path string true "Journal ID"
+// @Param If-Match header string false "ETag of the journal entry being processed, required unless require-if-match is off"
 // @Success 200 {object} ProcessingResponse "Journal entry is being processed"
-// @Failure 500 {object} ErrorResponse "Error message"
+// @Failure 404 {object} apierror.Response "Error message"
+// @Failure 409 {object} apierror.Response "Error message"
+// @Failure 412 {object} apierror.Response "Error message"
+// @Failure 422 {object} apierror.Response "Error message"
+// @Failure 428 {object} apierror.Response "Error message"
+// @Failure 500 {object} apierror.Response "Error message"
 // @Router /journal/{journalid}/process [put]
 func ProcessJournalEntry(c *gin.Context) {
 	journalID := c.Param("journalid")
 	userID := c.MustGet("userID").(string)
+	precondition, apiErr := utils.IfMatch(c)
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return
+	}
 
-	_, err := journalCollection.UpdateOne(
-		context.Background(),
-		bson.M{"journal_id": journalID, "user_id": userID},
-		bson.M{"$set": bson.M{"status": "processing"}},
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing journal entry"})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := changeStatus(ctx, journalID, userID, userID, StatusProcessing, precondition); err != nil {
+		apierror.Abort(c, err)
 		return
 	}
 
@@ -191,20 +260,22 @@ func ProcessJournalEntry(c *gin.Context) {
 }
 
 // @Summary Get journal versions
-// @Description Get all versions of a journal entry by ID
+// @Description Get all versions of a journal entry owned by the authenticated user
 // @Tags journal
 // @Produce json
 // @Param journalid path string true "Journal ID"
 // @Success 200 {array} Entry
-// @Failure 404 {object} ErrorResponse "Error message"
+// @Failure 404 {object} apierror.Response "Error message"
 // @Router /journal/{journalid}/versions [get]
 func GetJournalVersions(c *gin.Context) {
 	journalID := c.Param("journalid")
+	userID := c.MustGet("userID").(string)
 
-	var journal JournalEntry
-	err := journalCollection.FindOne(context.Background(), bson.M{"journal_id": journalID}).Decode(&journal)
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	journal, err := repo.GetOwned(ctx, journalID, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Journal entry not found"})
+		apierror.Abort(c, apierror.NotFound("Journal entry not found"))
 		return
 	}
 
@@ -217,28 +288,41 @@ func GetJournalVersions(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param journalid path string true "Journal ID"
-// @Param version body int true "Version"
+// @Param version body VersionRequest true "Version"
+// @Param If-Match header string false "ETag of the journal entry being updated, required unless require-if-match is off"
 // @Success 200 {object} JournalEntry
-// @Failure 400 {object} ErrorResponse "Error message"
-// @Failure 404 {object} ErrorResponse "Error message"
-// @Failure 500 {object} ErrorResponse "Error message"
+// @Header 200 {string} ETag "Revision of the updated journal entry"
+// @Failure 400 {object} apierror.Response "Error message"
+// @Failure 404 {object} apierror.Response "Error message"
+// @Failure 409 {object} apierror.Response "Error message"
+// @Failure 412 {object} apierror.Response "Error message"
+// @Failure 428 {object} apierror.Response "Error message"
+// @Failure 500 {object} apierror.Response "Error message"
 // @Router /journal/{journalid}/version [put]
 func SetJournalVersion(c *gin.Context) {
 	journalID := c.Param("journalid")
 	userID := c.MustGet("userID").(string)
-
-	var versionRequest struct {
-		Version int `json:"version"`
+	precondition, apiErr := utils.IfMatch(c)
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return
 	}
+
+	var versionRequest VersionRequest
 	if err := c.ShouldBindJSON(&versionRequest); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 
-	var journal JournalEntry
-	err := journalCollection.FindOne(context.Background(), bson.M{"journal_id": journalID, "user_id": userID}).Decode(&journal)
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	journal, err := repo.GetOwned(ctx, journalID, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Journal entry not found"})
+		apierror.Abort(c, apierror.NotFound("Journal entry not found"))
+		return
+	}
+	if !precondition.Allows(journal.Revision) {
+		apierror.Abort(c, errChanged)
 		return
 	}
 
@@ -247,81 +331,242 @@ func SetJournalVersion(c *gin.Context) {
 			journal.Version = versionRequest.Version
 			journal.UpdatedAt = time.Now()
 
-			_, err = journalCollection.UpdateOne(
-				context.Background(),
-				bson.M{"journal_id": journalID, "user_id": userID},
-				bson.M{"$set": bson.M{"version": journal.Version, "updated_at": journal.UpdatedAt}},
-			)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error setting journal version"})
+			if err := repo.SetVersion(ctx, journalID, userID, journal.Version, journal.Revision, journal.UpdatedAt); err != nil {
+				apierror.Abort(c, writeError(err, precondition, "Error setting journal version"))
 				return
 			}
 
+			journal.Revision++
+			c.Header("ETag", utils.RevisionETag(journal.Revision))
 			c.JSON(http.StatusOK, journal)
 			return
 		}
 	}
 
-	c.JSON(http.StatusBadRequest, gin.H{"error": "Version not found"})
+	apierror.Abort(c, apierror.BadRequest("Version not found"))
 }
 
 // @Summary Set the status of a journal entry
-// @Description Set the status of a journal entry by ID
+// @Description Set the status of a journal entry by ID. Valid statuses are pending, processing, private, public and archived, and only allowed transitions between them are accepted
 // @Tags journal
 // @Accept json
 // @Produce json
 // @Param journalid path string true "Journal ID"
-// @Param status body string true "Status"
+// @Param status body StatusRequest true "Status"
+// @Param If-Match header string false "ETag of the journal entry being updated, required unless require-if-match is off"
 // @Success 200 {object} ProcessingResponse "Journal status updated"
-// @Failure 400 {object} ErrorResponse "Error message"
-// @Failure 500 {object} ErrorResponse "Error message"
+// @Failure 400 {object} apierror.Response "Error message"
+// @Failure 403 {object} apierror.Response "The entry was hidden by a moderator and cannot be made public"
+// @Failure 404 {object} apierror.Response "Error message"
+// @Failure 409 {object} apierror.Response "Error message"
+// @Failure 412 {object} apierror.Response "Error message"
+// @Failure 422 {object} apierror.Response "Error message"
+// @Failure 428 {object} apierror.Response "Error message"
+// @Failure 500 {object} apierror.Response "Error message"
 // @Router /journal/{journalid}/status [put]
 func SetJournalStatus(c *gin.Context) {
 	journalID := c.Param("journalid")
 	userID := c.MustGet("userID").(string)
-
-	var statusRequest struct {
-		Status string `json:"status"`
+	precondition, apiErr := utils.IfMatch(c)
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return
 	}
+
+	var statusRequest StatusRequest
 	if err := c.ShouldBindJSON(&statusRequest); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 
-	_, err := journalCollection.UpdateOne(
-		context.Background(),
-		bson.M{"journal_id": journalID, "user_id": userID},
-		bson.M{"$set": bson.M{"status": statusRequest.Status, "updated_at": time.Now()}},
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error setting journal status"})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := changeStatus(ctx, journalID, userID, userID, statusRequest.Status, precondition); err != nil {
+		apierror.Abort(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Journal status updated"})
 }
 
+// HiddenCheck reports whether moderation hid a journal entry, which is then only shown to its owner and
+// admins and cannot be made public
+type HiddenCheck func(ctx context.Context, journalID string) (bool, error)
+
+var hiddenCheck HiddenCheck
+
+// SetHiddenCheck sets how journal entries hidden by moderation are told apart
+func SetHiddenCheck(check HiddenCheck) {
+	hiddenCheck = check
+}
+
+// Hidden reports whether moderation hid the journal entry
+func Hidden(ctx context.Context, journalID string) (bool, error) {
+	if hiddenCheck == nil {
+		return false, nil
+	}
+	return hiddenCheck(ctx, journalID)
+}
+
+var profileHiddenCheck HiddenCheck
+
+// SetProfileHiddenCheck sets how the users whose profile moderation hid are told apart, whose entries are
+// then left out of every listing and only shown to them and admins
+func SetProfileHiddenCheck(check HiddenCheck) {
+	profileHiddenCheck = check
+}
+
+// ProfileHidden reports whether moderation hid the user's profile
+func ProfileHidden(ctx context.Context, userID string) (bool, error) {
+	if profileHiddenCheck == nil {
+		return false, nil
+	}
+	return profileHiddenCheck(ctx, userID)
+}
+
+// Upload is a file stored in the image store for a journal entry
+type Upload struct {
+	UserID    string
+	JournalID string
+	URL       string
+	// Name is the name the file is counted against the user's storage quota under
+	Name  string
+	Bytes int64
+}
+
+// UploadTracker records the files stored for journal entries, so those no entry refers to any more can be
+// removed
+type UploadTracker func(ctx context.Context, upload Upload)
+
+var uploadTracker UploadTracker
+
+// SetUploadTracker sets what records the files stored for journal entries
+func SetUploadTracker(tracker UploadTracker) {
+	uploadTracker = tracker
+}
+
+// trackUpload records a file stored for a journal entry
+func trackUpload(ctx context.Context, upload Upload) {
+	if uploadTracker != nil {
+		uploadTracker(ctx, upload)
+	}
+}
+
+// SetStatus moves the user's journal entry to another status on behalf of someone else, such as a
+// moderator, with the checks applied when the user changes it
+func SetStatus(ctx context.Context, journalID, userID, changedBy, to string) error {
+	if err := changeStatus(ctx, journalID, userID, changedBy, to, utils.Precondition{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// changeStatus validates and applies a status transition of the user's journal entry, recording who made
+// the change and when. It returns the error to respond with when the change was not applied.
+func changeStatus(ctx context.Context, journalID, userID, changedBy, to string, precondition utils.Precondition) *apierror.Error {
+	if !isValidStatus(to) {
+		return apierror.Unprocessable("Invalid status")
+	}
+
+	journal, err := repo.GetOwned(ctx, journalID, userID)
+	if err != nil {
+		return apierror.Wrap(err, "Journal entry not found")
+	}
+	if !precondition.Allows(journal.Revision) {
+		return errChanged
+	}
+	if journal.Status == to {
+		return nil
+	}
+	if !canTransition(journal.Status, to) {
+		return apierror.Unprocessable("Cannot change status from " + journal.Status + " to " + to)
+	}
+	if to == StatusPublic {
+		hidden, err := Hidden(ctx, journalID)
+		if err != nil {
+			return apierror.Wrap(err, "Error setting journal status")
+		}
+		if hidden {
+			return apierror.Forbidden("This journal entry was hidden by a moderator and cannot be made public")
+		}
+	}
+
+	now := time.Now()
+	change := StatusChange{From: journal.Status, To: to, ChangedBy: changedBy, ChangedAt: now}
+	err = repo.ChangeStatus(ctx, journalID, userID, change)
+	if err != nil {
+		return writeError(err, precondition, "Error setting journal status")
+	}
+
+	payload := gin.H{"journalID": journalID, "from": change.From, "to": change.To}
+	events.Publish(ctx, userID, events.TypeJournalStatusChanged, payload)
+	if change.From == StatusProcessing {
+		events.Publish(ctx, userID, events.TypeJournalProcessed, payload)
+	}
+	return nil
+}
+
+// errChanged is the error for a write whose If-Match header names an older revision of the journal entry
+var errChanged = apierror.PreconditionFailed("Journal entry has been changed since it was read")
+
+// writeError returns the error to respond with when a write failed. A write that lost a race with another
+// fails its If-Match precondition, or conflicts when it had none.
+func writeError(err error, precondition utils.Precondition, message string) *apierror.Error {
+	if errors.Is(err, store.ErrConflict) {
+		if precondition.Conditional {
+			return errChanged
+		}
+		return apierror.Conflict("Journal entry was changed concurrently")
+	}
+	return apierror.Wrap(err, message)
+}
+
 // @Summary Get a single journal entry
 // @Description Get a single journal entry by ID, returns metadata if the user is authenticated
 // @Tags journal
 // @Produce json
 // @Param journalid path string true "Journal ID"
+// @Param fields query string false "Comma-separated fields to return, such as journalID,summary,taxonomy"
 // @Success 200 {object} JournalEntry
-// @Failure 404 {object} ErrorResponse "Error message"
+// @Header 200 {string} ETag "Revision of the journal entry for authenticated users, for If-Match when updating it"
+// @Success 304 "Not modified"
+// @Failure 400 {object} apierror.Response "Error message"
+// @Failure 404 {object} apierror.Response "Error message"
+// @Failure 429 {object} apierror.Response "Too many requests from the address, which was detected scraping"
 // @Router /journal/{journalid} [get]
+// @Router /journal/{journalid} [head]
 func GetJournalEntry(c *gin.Context) {
 	journalID := c.Param("journalid")
+	fields, apiErr := utils.Fields(c, JournalEntry{})
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return
+	}
 
-	var journal JournalEntry
-	err := journalCollection.FindOne(context.Background(), bson.M{"journal_id": journalID}).Decode(&journal)
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	journal, err := repo.Get(store.WithFields(ctx, fields, "updatedAt", "revision", "userID", "status", "journalID"), journalID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Journal entry not found"})
+		apierror.Abort(c, apierror.NotFound("Journal entry not found"))
 		return
 	}
 
+	// Authenticated users receive a different representation
+	c.Writer.Header().Add("Vary", "Cookie")
 	user, exists := c.Get("user")
-	if exists && user != nil {
-		meta := gin.H{
+	authenticated := exists && user != nil
+	readable, err := Readable(ctx, c, journal)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Error retrieving journal entry"))
+		return
+	}
+	if !readable {
+		apierror.Abort(c, apierror.NotFound("Journal entry not found"))
+		return
+	}
+	var body any
+	if authenticated {
+		body = gin.H{
 			"createdAt": journal.CreatedAt,
 			"updatedAt": journal.UpdatedAt,
 			"version":   journal.Version,
@@ -330,8 +575,8 @@ func GetJournalEntry(c *gin.Context) {
 			"entries":   journal.Entries,
 			"taxonomy":  journal.Taxonomy,
 			"summary":   journal.Summary,
+			"revision":  journal.Revision,
 		}
-		c.JSON(http.StatusOK, meta)
 	} else {
 		// Unauthenticated users get the latest entry as part of an array
 		latestEntry := []Entry{}
@@ -339,7 +584,7 @@ func GetJournalEntry(c *gin.Context) {
 			latestEntry = append(latestEntry, journal.Entries[len(journal.Entries)-1])
 		}
 
-		c.JSON(http.StatusOK, gin.H{
+		body = gin.H{
 			"journalID": journal.JournalID,
 			"userID":    journal.UserID,
 			"version":   journal.Version,
@@ -347,76 +592,159 @@ func GetJournalEntry(c *gin.Context) {
 			"taxonomy":  journal.Taxonomy,
 			"summary":   journal.Summary,
 			"entries":   latestEntry, // Return only the latest version
-		})
+		}
+	}
+
+	body, err = utils.Project(body, fields)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Error encoding response"))
+		return
+	}
+	if authenticated {
+		utils.RevisionJSON(c, body, journal.Revision, journal.UpdatedAt)
+	} else {
+		utils.ConditionalJSON(c, body, journal.UpdatedAt)
 	}
 }
 
 // @Summary Get public journal entries
-// @Description Get all public journal entries, supports filtering by date range, taxonomy, and users
+// @Description Get all public journal entries, supports filtering by date range, taxonomy, and users. Entries of users who restricted their profile are left out unless the requester may see it.
 // @Tags journal
 // @Produce json
-// @Param start query string false "Start date"
-// @Param end query string false "End date"
+// @Param start query string false "Earliest creation date, YYYY-MM-DD or RFC 3339"
+// @Param end query string false "Latest creation date, YYYY-MM-DD or RFC 3339"
 // @Param category query string false "Category"
 // @Param subcategory query string false "Subcategory"
 // @Param topic query string false "Topic"
 // @Param tag query string false "Tag"
 // @Param user query string false "User ID"
+// @Param fields query string false "Comma-separated fields to return of each entry, such as journalID,summary,taxonomy"
 // @Success 200 {array} JournalEntry
-// @Failure 500 {object} ErrorResponse "Error message"
+// @Success 304 "Not modified"
+// @Failure 400 {object} apierror.Response "Error message"
+// @Failure 429 {object} apierror.Response "Too many requests from the address, which was detected scraping"
+// @Failure 500 {object} apierror.Response "Error message"
 // @Router /journal [get]
+// @Router /journal [head]
 func GetPublicJournals(c *gin.Context) {
-	filter := bson.M{"status": "public"}
-
-	startDate := c.Query("start")
-	endDate := c.Query("end")
-	category := c.Query("category")
-	subcategory := c.Query("subcategory")
-	topic := c.Query("topic")
-	tag := c.Query("tag")
-	user := c.Query("user")
-
-	if startDate != "" && endDate != "" {
-		filter["created_at"] = bson.M{
-			"$gte": startDate,
-			"$lte": endDate,
-		}
+	filter := Filter{
+		Status:      StatusPublic,
+		Category:    c.Query("category"),
+		Subcategory: c.Query("subcategory"),
+		Topic:       c.Query("topic"),
+		Tag:         c.Query("tag"),
+		UserID:      c.Query("user"),
+	}
+	fields, apiErr := utils.Fields(c, JournalEntry{})
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return
 	}
 
-	if category != "" {
-		filter["taxonomy.categories"] = category
+	var err error
+	if start := c.Query("start"); start != "" {
+		if filter.CreatedFrom, err = parseDate(start, false); err != nil {
+			apierror.Abort(c, apierror.BadRequest("Invalid start date"))
+			return
+		}
+	}
+	if end := c.Query("end"); end != "" {
+		if filter.CreatedTo, err = parseDate(end, true); err != nil {
+			apierror.Abort(c, apierror.BadRequest("Invalid end date"))
+			return
+		}
 	}
 
-	if subcategory != "" {
-		filter["taxonomy.subcategories"] = subcategory
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	journals, err := repo.List(store.WithFields(store.PublicRead(ctx), fields, "updatedAt", "userID"), filter)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Error retrieving journal entries"))
+		return
+	}
+	if journals, err = withoutRestricted(ctx, c, journals); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Error retrieving journal entries"))
+		return
 	}
 
-	if topic != "" {
-		filter["taxonomy.topics"] = topic
+	body, err := utils.Project(journals, fields)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Error encoding response"))
+		return
 	}
+	utils.ConditionalJSON(c, body, lastUpdated(journals))
+}
 
-	if tag != "" {
-		filter["taxonomy.tags"] = tag
+// withoutRestricted leaves out the entries of users whose profile moderation hid, or who restricted it to
+// visitors other than the requester
+func withoutRestricted(ctx context.Context, c *gin.Context, journals []JournalEntry) ([]JournalEntry, error) {
+	allowed := map[string]bool{}
+	visible := journals[:0]
+	for _, journal := range journals {
+		ok, err := authorShown(ctx, c, journal.UserID, allowed)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			visible = append(visible, journal)
+		}
 	}
+	return visible, nil
+}
 
-	if user != "" {
-		filter["user_id"] = user
+// Readable reports whether the requester may read the journal entry: its owner and admins always may,
+// others only while it is public, not hidden by moderation and its author's profile is shown to them
+func Readable(ctx context.Context, c *gin.Context, entry JournalEntry) (bool, error) {
+	if u, ok := c.Get("user"); ok {
+		if user, _ := u.(auth.User); user.ID == entry.UserID || user.Admin {
+			return true, nil
+		}
+	}
+	if entry.Status != StatusPublic {
+		return false, nil
 	}
+	hidden, err := Hidden(ctx, entry.JournalID)
+	if err != nil || hidden {
+		return false, err
+	}
+	return authorShown(ctx, c, entry.UserID, map[string]bool{})
+}
 
-	cursor, err := journalCollection.Find(context.Background(), filter)
+// authorShown reports whether the entries of the user are shown to the requester: not when moderation hid
+// their profile, or they restricted it to other visitors, unless the requester is the user or an admin. The
+// answers are kept in allowed by user.
+func authorShown(ctx context.Context, c *gin.Context, userID string, allowed map[string]bool) (bool, error) {
+	if ok, seen := allowed[userID]; seen {
+		return ok, nil
+	}
+	if u, _ := c.Get("user"); u != nil {
+		if user := u.(auth.User); user.ID == userID || user.Admin {
+			return true, nil
+		}
+	}
+	hidden, err := ProfileHidden(ctx, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving journal entries"})
-		return
+		return false, err
 	}
-	defer cursor.Close(context.Background())
-
-	var journals []JournalEntry
-	if err := cursor.All(context.Background(), &journals); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error parsing journal entries"})
-		return
+	ok := !hidden
+	if ok {
+		if ok, err = privacy.Allowed(ctx, c, userID); err != nil {
+			return false, err
+		}
 	}
+	allowed[userID] = ok
+	return ok, nil
+}
 
-	c.JSON(http.StatusOK, journals)
+// lastUpdated returns when the most recently updated of the entries was last updated
+func lastUpdated(journals []JournalEntry) time.Time {
+	var last time.Time
+	for _, journal := range journals {
+		if journal.UpdatedAt.After(last) {
+			last = journal.UpdatedAt
+		}
+	}
+	return last
 }
 
 // @Summary Get user-specific journal entries
@@ -424,28 +752,35 @@ func GetPublicJournals(c *gin.Context) {
 // @Tags journal
 // @Produce json
 // @Param userid path string true "User ID"
+// @Param fields query string false "Comma-separated fields to return of each entry, such as journalID,summary,status"
 // @Success 200 {array} JournalEntry
-// @Failure 500 {object} ErrorResponse "Error message"
+// @Success 304 "Not modified"
+// @Failure 400 {object} apierror.Response "Error message"
+// @Failure 500 {object} apierror.Response "Error message"
 // @Router /journal/u/{userid} [get]
+// @Router /journal/u/{userid} [head]
 func GetUserJournals(c *gin.Context) {
 	userID := c.Param("userid")
+	fields, apiErr := utils.Fields(c, JournalEntry{})
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return
+	}
 
-	filter := bson.M{"user_id": userID}
-
-	cursor, err := journalCollection.Find(context.Background(), filter)
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	journals, err := repo.List(store.WithFields(store.PublicRead(ctx), fields, "updatedAt"), Filter{UserID: userID})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving journal entries"})
+		apierror.Abort(c, apierror.Wrap(err, "Error retrieving journal entries"))
 		return
 	}
-	defer cursor.Close(context.Background())
 
-	var journals []JournalEntry
-	if err := cursor.All(context.Background(), &journals); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error parsing journal entries"})
+	body, err := utils.Project(journals, fields)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Error encoding response"))
 		return
 	}
-
-	c.JSON(http.StatusOK, journals)
+	utils.ConditionalJSON(c, body, lastUpdated(journals))
 }
 
 // @Summary Delete a journal entry
@@ -454,37 +789,113 @@ func GetUserJournals(c *gin.Context) {
 // @Produce json
 // @Param journalid path string true "Journal ID"
 // @Success 200 {object} DeleteResponse "Journal entry deleted"
-// @Failure 500 {object} ErrorResponse "Error message"
+// @Failure 500 {object} apierror.Response "Error message"
+// @Failure 404 {object} apierror.Response "Journal entry not found"
 // @Router /journal/{journalid} [delete]
 func DeleteJournalEntry(c *gin.Context) {
 	journalID := c.Param("journalid")
 	userID := c.MustGet("userID").(string)
 
-	_, err := journalCollection.DeleteOne(context.Background(), bson.M{"journal_id": journalID, "user_id": userID})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	err := repo.Delete(ctx, journalID, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Journal entry not found"))
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting journal entry"})
+		apierror.Abort(c, apierror.Wrap(err, "Error deleting journal entry"))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Journal entry deleted"})
 }
 
-func InitializeRoutes(router *gin.RouterGroup, db *mongo.Client, db_name string) {
-	journalCollection = db.Database(db_name).Collection("journal")
+// @Summary Delete journal entries in bulk
+// @Description Deletes the user's journal entries with the given IDs, or those whose fields match every value of the filter, such as {"status": "private", "taxonomy.tags": "draft"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.
+// @Tags journal
+// @Accept json
+// @Produce json
+// @Param body body bulk.DeleteRequest true "The journal entries to delete"
+// @Success 200 {object} bulk.DeleteResult
+// @Failure 400 {object} apierror.Response "Invalid request, or a filter that is not confirmed"
+// @Failure 500 {object} apierror.Response "Error message"
+// @Router /journal/bulk-delete [post]
+func BulkDeleteJournalEntries(c *gin.Context) {
+	userID := c.MustGet("userID").(string)
 
-	router.GET("/", GetPublicJournals)
-	router.GET("/u/:userid", GetUserJournals)
-	router.GET("/:journalid", GetJournalEntry)
-	router.GET("/:journalid/meta", GetJournalMeta)
+	var req bulk.DeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	journals, err := repo.List(ctx, Filter{UserID: userID})
+	cancel()
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Error retrieving journal entries"))
+		return
+	}
+	result, err := bulk.Delete(c.Request.Context(), req, journals, func(j JournalEntry) string { return j.JournalID }, func(ctx context.Context, id string) error {
+		return repo.Delete(ctx, id, userID)
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Error deleting journal entries"))
+		return
+	}
 
-	authRequired := auth.AuthMiddleware(db, db_name, true)
+	c.JSON(http.StatusOK, result)
+}
+
+// parseDate parses a date query parameter given as YYYY-MM-DD or RFC 3339. A plain date used as
+// the end of a range covers the whole day.
+func parseDate(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+// InitializeRoutes initializes the journal routes
+func InitializeRoutes(router *gin.RouterGroup, r Repository, users auth.Repository) {
+	repo = r
+
+	// Scrapers of the public feed and its entries are slowed down or refused apart from other clients
+	router.GET("/", scraping.Watch(""), GetPublicJournals)
+	router.HEAD("/", scraping.Watch(""), GetPublicJournals)
+	router.GET("/u/:userid", GetUserJournals)
+	router.HEAD("/u/:userid", GetUserJournals)
+	// Entries are shown to their owner and admins apart from other requesters, who are identified if signed in
+	authOptional := auth.AuthMiddleware(users, false)
+	router.GET("/:journalid", scraping.Watch("journalid"), authOptional, GetJournalEntry)
+	router.HEAD("/:journalid", scraping.Watch("journalid"), authOptional, GetJournalEntry)
+	router.GET("/:journalid/meta", authOptional, GetJournalMeta)
+	router.GET("/:journalid/related", authOptional, GetRelatedJournals)
+
+	authRequired := auth.AuthMiddleware(users, true)
 	protected := router.Group("/")
 	protected.Use(authRequired)
-	protected.POST("/", CreateJournalEntry)
-	protected.PUT("/:journalid", UpdateJournalEntry)
+	protected.POST("/", dryrun.Supported(), quota.LimitDocuments("journal", countEntries), CreateJournalEntry)
+	protected.POST("/import", ImportJournalEntries)
+	protected.PUT("/:journalid", dryrun.Supported(), UpdateJournalEntry)
 	protected.PUT("/:journalid/process", ProcessJournalEntry)
 	protected.GET("/:journalid/versions", GetJournalVersions)
-	protected.PUT("/:journalid/version", SetJournalVersion)
-	protected.PUT("/:journalid/status", SetJournalStatus)
-	protected.DELETE("/:journalid", DeleteJournalEntry)
+	protected.PUT("/:journalid/version", dryrun.Supported(), SetJournalVersion)
+	protected.PUT("/:journalid/status", dryrun.Supported(), SetJournalStatus)
+	protected.DELETE("/:journalid", dryrun.Supported(), DeleteJournalEntry)
+	protected.POST("/bulk-delete", dryrun.Supported(), BulkDeleteJournalEntries)
+	protected.GET("/settings", GetJournalSettings)
+	protected.PUT("/settings", UpdateJournalSettings)
+	protected.GET("/taxonomy", GetTaxonomyTerms)
+	protected.POST("/taxonomy/:field/rename", dryrun.Supported(), RenameTaxonomyTerm)
+	protected.POST("/taxonomy/:field/merge", dryrun.Supported(), MergeTaxonomyTerms)
+	protected.POST("/taxonomy/:field/delete", dryrun.Supported(), DeleteTaxonomyTerms)
 }
@@ -0,0 +1,199 @@
+package journal
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultRelatedLimit = 5
+	maxRelatedLimit     = 20
+	relatedCacheTTL     = 5 * time.Minute
+)
+
+// RelatedEntry is a compact summary of a public journal entry suggested as further reading
+type RelatedEntry struct {
+	JournalID string    `json:"journalID"`
+	UserID    string    `json:"userID"`
+	Title     string    `json:"title"`
+	Summary   string    `json:"summary"`
+	Taxonomy  Taxonomy  `json:"taxonomy"`
+	Score     int       `json:"score"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type relatedCacheItem struct {
+	candidates []JournalEntry
+	expires    time.Time
+}
+
+// relatedCache holds the entries recently found sharing terms with an entry, keyed by tenant and journal
+// ID. Which of them the requester may see is checked on every request.
+var relatedCache = struct {
+	sync.Mutex
+	items map[string]relatedCacheItem
+}{items: map[string]relatedCacheItem{}}
+
+// @Summary Get related journal entries
+// @Description Get other public journal entries ranked by the number of taxonomy terms they share with the given entry
+// @Tags journal
+// @Produce json
+// @Param journalid path string true "Journal ID"
+// @Param limit query int false "Maximum number of entries to return (default 5, max 20)"
+// @Success 200 {array} RelatedEntry
+// @Failure 404 {object} apierror.Response "Error message"
+// @Failure 500 {object} apierror.Response "Error message"
+// @Router /journal/{journalid}/related [get]
+func GetRelatedJournals(c *gin.Context) {
+	journalID := c.Param("journalid")
+
+	limit := defaultRelatedLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, maxRelatedLimit)
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	journal, err := repo.Get(ctx, journalID)
+	if err != nil || journal.Status != StatusPublic {
+		apierror.Abort(c, apierror.NotFound("Journal entry not found"))
+		return
+	}
+	visible, err := relatedVisible(ctx, c, journal, map[string]bool{})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Error retrieving related entries"))
+		return
+	}
+	if !visible {
+		apierror.Abort(c, apierror.NotFound("Journal entry not found"))
+		return
+	}
+
+	terms := taxonomyTerms(journal.Taxonomy)
+	related := []RelatedEntry{}
+	if len(terms) > 0 {
+		related, err = findRelated(ctx, c, journal, terms, limit)
+		if err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Error retrieving related entries"))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, related)
+}
+
+// relatedCandidates returns the public entries sharing any of the terms with the journal, from the cache
+// while it holds them
+func relatedCandidates(ctx context.Context, journal JournalEntry, terms map[string]bool) ([]JournalEntry, error) {
+	cacheKey := tenant.ID(ctx) + "/" + journal.JournalID
+	now := time.Now()
+	relatedCache.Lock()
+	item, ok := relatedCache.items[cacheKey]
+	relatedCache.Unlock()
+	if ok && now.Before(item.expires) {
+		return item.candidates, nil
+	}
+
+	values := make([]string, 0, len(terms))
+	for term := range terms {
+		values = append(values, term)
+	}
+	candidates, err := repo.ListRelated(store.PublicRead(ctx), journal.JournalID, values)
+	if err != nil {
+		return nil, err
+	}
+
+	relatedCache.Lock()
+	for key, cached := range relatedCache.items {
+		if now.After(cached.expires) {
+			delete(relatedCache.items, key)
+		}
+	}
+	relatedCache.items[cacheKey] = relatedCacheItem{candidates: candidates, expires: now.Add(relatedCacheTTL)}
+	relatedCache.Unlock()
+	return candidates, nil
+}
+
+// relatedVisible reports whether the requester may see the entry, which is neither hidden by moderation
+// nor written by a user whose profile is hidden or restricted to others. Allowed holds the users already
+// checked.
+func relatedVisible(ctx context.Context, c *gin.Context, journal JournalEntry, allowed map[string]bool) (bool, error) {
+	hidden, err := Hidden(ctx, journal.JournalID)
+	if err != nil || hidden {
+		return false, err
+	}
+	return authorShown(ctx, c, journal.UserID, allowed)
+}
+
+// findRelated returns the public entries sharing taxonomy terms with the journal that the requester may
+// see, best matches first
+func findRelated(ctx context.Context, c *gin.Context, journal JournalEntry, terms map[string]bool, limit int) ([]RelatedEntry, error) {
+	candidates, err := relatedCandidates(ctx, journal, terms)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := map[string]bool{}
+	related := make([]RelatedEntry, 0, len(candidates))
+	for _, candidate := range candidates {
+		visible, err := relatedVisible(ctx, c, candidate, allowed)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			continue
+		}
+		score := 0
+		for term := range taxonomyTerms(candidate.Taxonomy) {
+			if terms[term] {
+				score++
+			}
+		}
+		title := ""
+		if len(candidate.Entries) > 0 {
+			title = candidate.Entries[len(candidate.Entries)-1].Title
+		}
+		related = append(related, RelatedEntry{
+			JournalID: candidate.JournalID,
+			UserID:    candidate.UserID,
+			Title:     title,
+			Summary:   candidate.Summary,
+			Taxonomy:  candidate.Taxonomy,
+			Score:     score,
+			UpdatedAt: candidate.UpdatedAt,
+		})
+	}
+
+	sort.SliceStable(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return related[i].UpdatedAt.After(related[j].UpdatedAt)
+	})
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	return related, nil
+}
+
+// taxonomyTerms returns the set of all terms used in a taxonomy
+func taxonomyTerms(t Taxonomy) map[string]bool {
+	terms := map[string]bool{}
+	for _, list := range [][]string{t.Categories, t.Subcategories, t.Topics, t.Tags} {
+		for _, term := range list {
+			terms[term] = true
+		}
+	}
+	return terms
+}
@@ -0,0 +1,58 @@
+package journal
+
+import (
+	"context"
+	"time"
+)
+
+// Filter selects journal entries. Empty fields match every entry.
+type Filter struct {
+	UserID string
+	Status string
+	// CreatedFrom and CreatedTo bound the creation time, inclusive
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// StatusChangedAfter is exclusive and StatusChangedUntil inclusive, so consecutive windows do not overlap.
+	// Entries whose status never changed are matched by their creation time.
+	StatusChangedAfter time.Time
+	StatusChangedUntil time.Time
+	Category           string
+	Subcategory        string
+	Topic              string
+	Tag                string
+}
+
+// Repository stores journal entries
+type Repository interface {
+	// Create stores a new journal entry
+	Create(ctx context.Context, journal JournalEntry) error
+	// Get returns the journal entry with the given ID, or store.ErrNotFound
+	Get(ctx context.Context, journalID string) (JournalEntry, error)
+	// GetOwned returns the journal entry only if it belongs to the user, or store.ErrNotFound
+	GetOwned(ctx context.Context, journalID, userID string) (JournalEntry, error)
+	// SaveEntries stores the entries, current version and update time of the journal entry. It only matches
+	// while the entry is still at the journal's Revision, returning store.ErrConflict when it has been changed
+	// since it was read.
+	SaveEntries(ctx context.Context, journal JournalEntry) error
+	// SetVersion sets which of the stored entries is the current version, while the entry is at the revision
+	// as for SaveEntries
+	SetVersion(ctx context.Context, journalID, userID string, version, revision int, updatedAt time.Time) error
+	// SetTaxonomy stores the taxonomy of the journal entry, while the entry is at the revision as for
+	// SaveEntries
+	SetTaxonomy(ctx context.Context, journalID, userID string, taxonomy Taxonomy, revision int, updatedAt time.Time) error
+	// ChangeStatus applies the status change and records it in the history. It only matches while the entry
+	// still has the change's From status, returning store.ErrConflict when another change got there first.
+	// Every change moves the entry to its next revision.
+	ChangeStatus(ctx context.Context, journalID, userID string, change StatusChange) error
+	// List returns the journal entries matching the filter
+	List(ctx context.Context, filter Filter) ([]JournalEntry, error)
+	// ListRelated returns the public entries, other than journalID, tagged with any of the taxonomy terms
+	ListRelated(ctx context.Context, journalID string, terms []string) ([]JournalEntry, error)
+	// Delete removes the user's journal entry, or returns store.ErrNotFound when it does not exist
+	Delete(ctx context.Context, journalID, userID string) error
+
+	// GetSettings returns the user's journal settings, or store.ErrNotFound if they never changed them
+	GetSettings(ctx context.Context, userID string) (Settings, error)
+	// SaveSettings creates or replaces the user's journal settings
+	SaveSettings(ctx context.Context, settings Settings) error
+}
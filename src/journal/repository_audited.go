@@ -0,0 +1,98 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"profile-api/audit"
+	"profile-api/store"
+)
+
+// AuditedRepository records every change to journal entries in the audit log
+type AuditedRepository struct {
+	Repository
+}
+
+// NewAuditedRepository wraps the repository to record its changes in the audit log
+func NewAuditedRepository(r Repository) *AuditedRepository {
+	return &AuditedRepository{Repository: r}
+}
+
+func (r *AuditedRepository) Create(ctx context.Context, journal JournalEntry) error {
+	if err := r.Repository.Create(ctx, journal); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionCreate, "journal", journal.UserID, journal.JournalID, nil, journal)
+	return nil
+}
+
+func (r *AuditedRepository) SaveEntries(ctx context.Context, journal JournalEntry) error {
+	return r.update(ctx, journal.JournalID, journal.UserID, func() error {
+		return r.Repository.SaveEntries(ctx, journal)
+	})
+}
+
+func (r *AuditedRepository) SetVersion(ctx context.Context, journalID, userID string, version, revision int, updatedAt time.Time) error {
+	return r.update(ctx, journalID, userID, func() error {
+		return r.Repository.SetVersion(ctx, journalID, userID, version, revision, updatedAt)
+	})
+}
+
+func (r *AuditedRepository) SetTaxonomy(ctx context.Context, journalID, userID string, taxonomy Taxonomy, revision int, updatedAt time.Time) error {
+	return r.update(ctx, journalID, userID, func() error {
+		return r.Repository.SetTaxonomy(ctx, journalID, userID, taxonomy, revision, updatedAt)
+	})
+}
+
+func (r *AuditedRepository) ChangeStatus(ctx context.Context, journalID, userID string, change StatusChange) error {
+	return r.update(ctx, journalID, userID, func() error {
+		return r.Repository.ChangeStatus(ctx, journalID, userID, change)
+	})
+}
+
+func (r *AuditedRepository) Delete(ctx context.Context, journalID, userID string) error {
+	before, err := r.Repository.GetOwned(ctx, journalID, userID)
+	if err != nil {
+		// Nothing to delete, leave reporting that to the repository
+		return r.Repository.Delete(ctx, journalID, userID)
+	}
+	if err := r.Repository.Delete(ctx, journalID, userID); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionDelete, "journal", userID, journalID, before, nil)
+	return nil
+}
+
+// update makes a change to the user's journal entry with write, reading the entry before and after to
+// record what changed
+func (r *AuditedRepository) SaveSettings(ctx context.Context, settings Settings) error {
+	before, err := r.Repository.GetSettings(ctx, settings.UserID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.SaveSettings(ctx, settings); err != nil {
+		return err
+	}
+	audit.RecordSave(ctx, "journal_settings", settings.UserID, settings.UserID, before, existed, settings)
+	return nil
+}
+
+func (r *AuditedRepository) update(ctx context.Context, journalID, userID string, write func() error) error {
+	before, err := r.Repository.GetOwned(ctx, journalID, userID)
+	if err != nil {
+		return write()
+	}
+	if err := write(); err != nil {
+		return err
+	}
+	after, err := r.Repository.GetOwned(ctx, journalID, userID)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not read changed journal entry for audit log", "journal_id", journalID, "error", err)
+		return nil
+	}
+	audit.Record(ctx, audit.ActionUpdate, "journal", userID, journalID, before, after)
+	return nil
+}
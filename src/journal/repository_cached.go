@@ -0,0 +1,100 @@
+package journal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"profile-api/cache"
+	"profile-api/store"
+)
+
+// listGenerationKey holds the generation of the cached journal lists, bumped by every journal write
+const listGenerationKey = "journal:lists:generation"
+
+// CachedRepository serves public journal lists from the cache. A list can include any user's entries,
+// so every write invalidates all cached lists at once by bumping their generation.
+type CachedRepository struct {
+	Repository
+	cache *cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedRepository wraps the repository with a cache keeping public journal lists for ttl
+func NewCachedRepository(r Repository, c *cache.Cache, ttl time.Duration) *CachedRepository {
+	return &CachedRepository{Repository: r, cache: c, ttl: ttl}
+}
+
+// List caches whole public lists that are not bounded by status change time. Lists of drafts, the digest's
+// publication windows and lists of only some fields are always read from the repository.
+func (r *CachedRepository) List(ctx context.Context, filter Filter) ([]JournalEntry, error) {
+	if filter.Status != StatusPublic || !filter.StatusChangedAfter.IsZero() || !filter.StatusChangedUntil.IsZero() || store.Fields(ctx) != nil {
+		return r.Repository.List(ctx, filter)
+	}
+
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return r.Repository.List(ctx, filter)
+	}
+	hash := sha256.Sum256(data)
+	key := cache.Key("journal", "list", r.cache.Generation(ctx, listGenerationKey), hex.EncodeToString(hash[:]))
+
+	var journals []JournalEntry
+	if r.cache.Get(ctx, key, &journals) {
+		return journals, nil
+	}
+	journals, err = r.Repository.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.Set(ctx, key, journals, r.ttl)
+	return journals, nil
+}
+
+func (r *CachedRepository) Create(ctx context.Context, journal JournalEntry) error {
+	err := r.Repository.Create(ctx, journal)
+	r.invalidate(ctx)
+	return err
+}
+
+func (r *CachedRepository) SaveEntries(ctx context.Context, journal JournalEntry) error {
+	err := r.Repository.SaveEntries(ctx, journal)
+	r.invalidate(ctx)
+	return err
+}
+
+func (r *CachedRepository) SetVersion(ctx context.Context, journalID, userID string, version, revision int, updatedAt time.Time) error {
+	err := r.Repository.SetVersion(ctx, journalID, userID, version, revision, updatedAt)
+	r.invalidate(ctx)
+	return err
+}
+
+func (r *CachedRepository) SetTaxonomy(ctx context.Context, journalID, userID string, taxonomy Taxonomy, revision int, updatedAt time.Time) error {
+	err := r.Repository.SetTaxonomy(ctx, journalID, userID, taxonomy, revision, updatedAt)
+	r.invalidate(ctx)
+	return err
+}
+
+func (r *CachedRepository) ChangeStatus(ctx context.Context, journalID, userID string, change StatusChange) error {
+	err := r.Repository.ChangeStatus(ctx, journalID, userID, change)
+	r.invalidate(ctx)
+	return err
+}
+
+func (r *CachedRepository) Delete(ctx context.Context, journalID, userID string) error {
+	err := r.Repository.Delete(ctx, journalID, userID)
+	r.invalidate(ctx)
+	return err
+}
+
+// invalidate drops every cached journal list
+func (r *CachedRepository) invalidate(ctx context.Context) {
+	InvalidateCache(ctx, r.cache)
+}
+
+// InvalidateCache drops every cached journal list, such as after a write seen through change streams
+func InvalidateCache(ctx context.Context, c *cache.Cache) {
+	c.Bump(ctx, listGenerationKey)
+}
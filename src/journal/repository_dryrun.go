@@ -0,0 +1,91 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"profile-api/dryrun"
+	"profile-api/store"
+)
+
+// DryRunRepository leaves journal entries unchanged in dry runs, reading them as usual. Writes guarded by the
+// entry's revision or status are still checked against it, so a dry run reports the conflict the write
+// would have met, and a deletion still reports a missing entry.
+type DryRunRepository struct {
+	Repository
+}
+
+// NewDryRunRepository wraps the repository to skip its writes in dry runs
+func NewDryRunRepository(r Repository) *DryRunRepository {
+	return &DryRunRepository{Repository: r}
+}
+
+func (r *DryRunRepository) Create(ctx context.Context, journal JournalEntry) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.Create(ctx, journal)
+}
+
+func (r *DryRunRepository) SaveEntries(ctx context.Context, journal JournalEntry) error {
+	if dryrun.Active(ctx) {
+		return r.checkRevision(ctx, journal.JournalID, journal.UserID, journal.Revision)
+	}
+	return r.Repository.SaveEntries(ctx, journal)
+}
+
+func (r *DryRunRepository) SetVersion(ctx context.Context, journalID, userID string, version, revision int, updatedAt time.Time) error {
+	if dryrun.Active(ctx) {
+		return r.checkRevision(ctx, journalID, userID, revision)
+	}
+	return r.Repository.SetVersion(ctx, journalID, userID, version, revision, updatedAt)
+}
+
+func (r *DryRunRepository) SetTaxonomy(ctx context.Context, journalID, userID string, taxonomy Taxonomy, revision int, updatedAt time.Time) error {
+	if dryrun.Active(ctx) {
+		return r.checkRevision(ctx, journalID, userID, revision)
+	}
+	return r.Repository.SetTaxonomy(ctx, journalID, userID, taxonomy, revision, updatedAt)
+}
+
+func (r *DryRunRepository) ChangeStatus(ctx context.Context, journalID, userID string, change StatusChange) error {
+	if !dryrun.Active(ctx) {
+		return r.Repository.ChangeStatus(ctx, journalID, userID, change)
+	}
+	current, err := r.Repository.GetOwned(ctx, journalID, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return store.ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+	if current.Status != change.From {
+		return store.ErrConflict
+	}
+	return nil
+}
+
+func (r *DryRunRepository) Delete(ctx context.Context, journalID, userID string) error {
+	if dryrun.Active(ctx) {
+		_, err := r.Repository.GetOwned(ctx, journalID, userID)
+		return err
+	}
+	return r.Repository.Delete(ctx, journalID, userID)
+}
+
+// checkRevision returns store.ErrConflict when the user's entry is no longer at the revision. A missing
+// entry matches nothing, as for the write.
+func (r *DryRunRepository) checkRevision(ctx context.Context, journalID, userID string, revision int) error {
+	current, err := r.Repository.GetOwned(ctx, journalID, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if current.Revision != revision {
+		return store.ErrConflict
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package journal
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps journal entries in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	journals []JournalEntry
+	settings map[string]Settings
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{settings: map[string]Settings{}}
+}
+
+// index returns the position of a journal entry, or -1. An empty userID matches any owner.
+// The caller must hold the lock.
+func (r *MemoryRepository) index(journalID, userID string) int {
+	for i, journal := range r.journals {
+		if journal.JournalID == journalID && (userID == "" || journal.UserID == userID) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, journal JournalEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.index(journal.JournalID, "") >= 0 {
+		return store.ErrConflict
+	}
+	r.journals = append(r.journals, cloneJournal(journal))
+	return nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, journalID string) (JournalEntry, error) {
+	return r.GetOwned(ctx, journalID, "")
+}
+
+func (r *MemoryRepository) GetOwned(ctx context.Context, journalID, userID string) (JournalEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.index(journalID, userID)
+	if i < 0 {
+		return JournalEntry{}, store.ErrNotFound
+	}
+	return cloneJournal(r.journals[i]), nil
+}
+
+func (r *MemoryRepository) SaveEntries(ctx context.Context, journal JournalEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(journal.JournalID, journal.UserID)
+	if i < 0 {
+		return nil
+	}
+	if r.journals[i].Revision != journal.Revision {
+		return store.ErrConflict
+	}
+	r.journals[i].Entries = slices.Clone(journal.Entries)
+	r.journals[i].Version = journal.Version
+	r.journals[i].UpdatedAt = journal.UpdatedAt
+	r.journals[i].Revision++
+	return nil
+}
+
+func (r *MemoryRepository) SetVersion(ctx context.Context, journalID, userID string, version, revision int, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(journalID, userID)
+	if i < 0 {
+		return nil
+	}
+	if r.journals[i].Revision != revision {
+		return store.ErrConflict
+	}
+	r.journals[i].Version = version
+	r.journals[i].UpdatedAt = updatedAt
+	r.journals[i].Revision++
+	return nil
+}
+
+func (r *MemoryRepository) SetTaxonomy(ctx context.Context, journalID, userID string, taxonomy Taxonomy, revision int, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(journalID, userID)
+	if i < 0 {
+		return nil
+	}
+	if r.journals[i].Revision != revision {
+		return store.ErrConflict
+	}
+	r.journals[i].Taxonomy = cloneJournal(JournalEntry{Taxonomy: taxonomy}).Taxonomy
+	r.journals[i].UpdatedAt = updatedAt
+	r.journals[i].Revision++
+	return nil
+}
+
+func (r *MemoryRepository) ChangeStatus(ctx context.Context, journalID, userID string, change StatusChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(journalID, userID)
+	if i < 0 || r.journals[i].Status != change.From {
+		return store.ErrConflict
+	}
+	journal := &r.journals[i]
+	changedAt := change.ChangedAt
+	journal.Status = change.To
+	journal.StatusChangedBy = change.ChangedBy
+	journal.StatusChangedAt = &changedAt
+	journal.UpdatedAt = change.ChangedAt
+	journal.StatusHistory = append(slices.Clone(journal.StatusHistory), change)
+	journal.Revision++
+	return nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, filter Filter) ([]JournalEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var journals []JournalEntry
+	for _, journal := range r.journals {
+		if filter.matches(journal) {
+			journals = append(journals, cloneJournal(journal))
+		}
+	}
+	return journals, nil
+}
+
+func (r *MemoryRepository) ListRelated(ctx context.Context, journalID string, terms []string) ([]JournalEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var journals []JournalEntry
+	for _, journal := range r.journals {
+		if journal.Status != StatusPublic || journal.JournalID == journalID {
+			continue
+		}
+		shared := taxonomyTerms(journal.Taxonomy)
+		if slices.ContainsFunc(terms, func(term string) bool { return shared[term] }) {
+			journals = append(journals, cloneJournal(journal))
+		}
+	}
+	return journals, nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, journalID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(journalID, userID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.journals = slices.Delete(r.journals, i, i+1)
+	return nil
+}
+
+// statusChangedAt returns when the entry moved to its status, which is when it was created if it never moved
+func (j JournalEntry) statusChangedAt() time.Time {
+	if j.StatusChangedAt != nil {
+		return *j.StatusChangedAt
+	}
+	return j.CreatedAt
+}
+
+// matches reports whether the journal entry is selected by the filter
+func (f Filter) matches(journal JournalEntry) bool {
+	switch {
+	case f.UserID != "" && journal.UserID != f.UserID,
+		f.Status != "" && journal.Status != f.Status,
+		!f.CreatedFrom.IsZero() && journal.CreatedAt.Before(f.CreatedFrom),
+		!f.CreatedTo.IsZero() && journal.CreatedAt.After(f.CreatedTo),
+		!f.StatusChangedAfter.IsZero() && !journal.statusChangedAt().After(f.StatusChangedAfter),
+		!f.StatusChangedUntil.IsZero() && journal.statusChangedAt().After(f.StatusChangedUntil),
+		f.Category != "" && !slices.Contains(journal.Taxonomy.Categories, f.Category),
+		f.Subcategory != "" && !slices.Contains(journal.Taxonomy.Subcategories, f.Subcategory),
+		f.Topic != "" && !slices.Contains(journal.Taxonomy.Topics, f.Topic),
+		f.Tag != "" && !slices.Contains(journal.Taxonomy.Tags, f.Tag):
+		return false
+	}
+	return true
+}
+
+func (r *MemoryRepository) GetSettings(ctx context.Context, userID string) (Settings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.settings[userID]
+	if !ok {
+		return Settings{}, store.ErrNotFound
+	}
+	s.Taxonomy = cloneJournal(JournalEntry{Taxonomy: s.Taxonomy}).Taxonomy
+	return s, nil
+}
+
+func (r *MemoryRepository) SaveSettings(ctx context.Context, settings Settings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	settings.Taxonomy = cloneJournal(JournalEntry{Taxonomy: settings.Taxonomy}).Taxonomy
+	r.settings[settings.UserID] = settings
+	return nil
+}
+
+// cloneJournal copies a journal entry so callers cannot modify the stored copy through its slices
+func cloneJournal(journal JournalEntry) JournalEntry {
+	journal.Entries = slices.Clone(journal.Entries)
+	journal.StatusHistory = slices.Clone(journal.StatusHistory)
+	journal.Taxonomy = Taxonomy{
+		Categories:    slices.Clone(journal.Taxonomy.Categories),
+		Subcategories: slices.Clone(journal.Taxonomy.Subcategories),
+		Topics:        slices.Clone(journal.Taxonomy.Topics),
+		Tags:          slices.Clone(journal.Taxonomy.Tags),
+	}
+	return journal
+}
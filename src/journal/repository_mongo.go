@@ -0,0 +1,205 @@
+package journal
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores journal entries in the journal collection and settings in journal_settings
+type MongoRepository struct {
+	journals *mongo.Collection
+	settings *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{journals: db.Collection("journal"), settings: db.Collection("journal_settings")}
+}
+
+func (r *MongoRepository) Create(ctx context.Context, journal JournalEntry) error {
+	_, err := r.journals.InsertOne(ctx, journal)
+	return err
+}
+
+func (r *MongoRepository) Get(ctx context.Context, journalID string) (JournalEntry, error) {
+	var journal JournalEntry
+	err := r.journals.FindOne(ctx, bson.M{"journal_id": journalID}, store.MongoFindOne(ctx, journal)).Decode(&journal)
+	return journal, store.MongoErr(err)
+}
+
+func (r *MongoRepository) GetOwned(ctx context.Context, journalID, userID string) (JournalEntry, error) {
+	var journal JournalEntry
+	err := r.journals.FindOne(ctx, bson.M{"journal_id": journalID, "user_id": userID}).Decode(&journal)
+	return journal, store.MongoErr(err)
+}
+
+func (r *MongoRepository) SaveEntries(ctx context.Context, journal JournalEntry) error {
+	return r.update(ctx, journal.JournalID, journal.UserID, journal.Revision,
+		bson.M{"entries": journal.Entries, "version": journal.Version, "updated_at": journal.UpdatedAt})
+}
+
+func (r *MongoRepository) SetVersion(ctx context.Context, journalID, userID string, version, revision int, updatedAt time.Time) error {
+	return r.update(ctx, journalID, userID, revision, bson.M{"version": version, "updated_at": updatedAt})
+}
+
+func (r *MongoRepository) SetTaxonomy(ctx context.Context, journalID, userID string, taxonomy Taxonomy, revision int, updatedAt time.Time) error {
+	return r.update(ctx, journalID, userID, revision, bson.M{"taxonomy": taxonomy, "updated_at": updatedAt})
+}
+
+// update sets the fields of the user's journal entry while it is at the revision, moving it to the next
+func (r *MongoRepository) update(ctx context.Context, journalID, userID string, revision int, fields bson.M) error {
+	res, err := r.journals.UpdateOne(
+		ctx,
+		bson.M{"journal_id": journalID, "user_id": userID, "revision": store.MongoRevision(revision)},
+		bson.M{"$set": fields, "$inc": bson.M{"revision": 1}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		// Tell a changed entry from a missing one, which is left to the caller as before
+		n, err := r.journals.CountDocuments(ctx, bson.M{"journal_id": journalID, "user_id": userID})
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return store.ErrConflict
+		}
+	}
+	return nil
+}
+
+func (r *MongoRepository) ChangeStatus(ctx context.Context, journalID, userID string, change StatusChange) error {
+	res, err := r.journals.UpdateOne(
+		ctx,
+		// Match on the current status so concurrent transitions cannot skip validation
+		bson.M{"journal_id": journalID, "user_id": userID, "status": change.From},
+		bson.M{
+			"$set": bson.M{
+				"status":            change.To,
+				"status_changed_by": change.ChangedBy,
+				"status_changed_at": change.ChangedAt,
+				"updated_at":        change.ChangedAt,
+			},
+			"$push": bson.M{"status_history": change},
+			"$inc":  bson.M{"revision": 1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrConflict
+	}
+	return nil
+}
+
+func (r *MongoRepository) List(ctx context.Context, filter Filter) ([]JournalEntry, error) {
+	return r.find(ctx, mongoFilter(filter))
+}
+
+func (r *MongoRepository) ListRelated(ctx context.Context, journalID string, terms []string) ([]JournalEntry, error) {
+	return r.find(ctx, bson.M{
+		"status":     StatusPublic,
+		"journal_id": bson.M{"$ne": journalID},
+		"$or": bson.A{
+			bson.M{"taxonomy.categories": bson.M{"$in": terms}},
+			bson.M{"taxonomy.subcategories": bson.M{"$in": terms}},
+			bson.M{"taxonomy.topics": bson.M{"$in": terms}},
+			bson.M{"taxonomy.tags": bson.M{"$in": terms}},
+		},
+	})
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, journalID, userID string) error {
+	res, err := r.journals.DeleteOne(ctx, bson.M{"journal_id": journalID, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// find returns every journal entry matching the query
+func (r *MongoRepository) find(ctx context.Context, query bson.M) ([]JournalEntry, error) {
+	cursor, err := store.MongoReader(ctx, r.journals).Find(ctx, query, store.MongoFind(ctx, JournalEntry{}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var journals []JournalEntry
+	if err := cursor.All(ctx, &journals); err != nil {
+		return nil, err
+	}
+	return journals, nil
+}
+
+// mongoFilter converts a filter into a query on the journal collection
+func mongoFilter(filter Filter) bson.M {
+	query := bson.M{}
+	if filter.UserID != "" {
+		query["user_id"] = filter.UserID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+
+	created := bson.M{}
+	if !filter.CreatedFrom.IsZero() {
+		created["$gte"] = filter.CreatedFrom
+	}
+	if !filter.CreatedTo.IsZero() {
+		created["$lte"] = filter.CreatedTo
+	}
+	if len(created) > 0 {
+		query["created_at"] = created
+	}
+
+	changed := bson.M{}
+	if !filter.StatusChangedAfter.IsZero() {
+		changed["$gt"] = filter.StatusChangedAfter
+	}
+	if !filter.StatusChangedUntil.IsZero() {
+		changed["$lte"] = filter.StatusChangedUntil
+	}
+	if len(changed) > 0 {
+		query["$or"] = bson.A{
+			bson.M{"status_changed_at": changed},
+			bson.M{"status_changed_at": nil, "created_at": changed},
+		}
+	}
+
+	if filter.Category != "" {
+		query["taxonomy.categories"] = filter.Category
+	}
+	if filter.Subcategory != "" {
+		query["taxonomy.subcategories"] = filter.Subcategory
+	}
+	if filter.Topic != "" {
+		query["taxonomy.topics"] = filter.Topic
+	}
+	if filter.Tag != "" {
+		query["taxonomy.tags"] = filter.Tag
+	}
+	return query
+}
+
+func (r *MongoRepository) GetSettings(ctx context.Context, userID string) (Settings, error) {
+	var settings Settings
+	err := r.settings.FindOne(ctx, bson.M{"user_id": userID}).Decode(&settings)
+	return settings, store.MongoErr(err)
+}
+
+func (r *MongoRepository) SaveSettings(ctx context.Context, settings Settings) error {
+	_, err := r.settings.ReplaceOne(ctx, bson.M{"user_id": settings.UserID}, settings, options.Replace().SetUpsert(true))
+	return err
+}
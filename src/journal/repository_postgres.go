@@ -0,0 +1,213 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const journalColumns = `journal_id, user_id, version, entries, status, taxonomy, summary, created_at, updated_at,
+	COALESCE(status_changed_by, ''), status_changed_at, status_history, revision`
+
+// PostgresRepository stores journal entries in the journal table and settings in journal_settings.
+// Entries, taxonomy and status history are kept as JSONB documents.
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, journal JournalEntry) error {
+	history := journal.StatusHistory
+	if history == nil {
+		history = []StatusChange{}
+	}
+	_, err := r.pool.Exec(ctx, `INSERT INTO journal (journal_id, user_id, version, entries, status, taxonomy, summary,
+		created_at, updated_at, status_changed_by, status_changed_at, status_history, revision)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11, $12, $13)`,
+		journal.JournalID, journal.UserID, journal.Version, journal.Entries, journal.Status, journal.Taxonomy,
+		journal.Summary, journal.CreatedAt, journal.UpdatedAt, journal.StatusChangedBy, journal.StatusChangedAt, history,
+		journal.Revision)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, journalID string) (JournalEntry, error) {
+	return r.findOne(ctx, "SELECT "+journalColumns+" FROM journal WHERE journal_id = $1", journalID)
+}
+
+func (r *PostgresRepository) GetOwned(ctx context.Context, journalID, userID string) (JournalEntry, error) {
+	return r.findOne(ctx, "SELECT "+journalColumns+" FROM journal WHERE journal_id = $1 AND user_id = $2", journalID, userID)
+}
+
+func (r *PostgresRepository) SaveEntries(ctx context.Context, journal JournalEntry) error {
+	return r.update(ctx, journal.JournalID, journal.UserID, journal.Revision, "entries = $4, version = $5, updated_at = $6",
+		journal.Entries, journal.Version, journal.UpdatedAt)
+}
+
+func (r *PostgresRepository) SetVersion(ctx context.Context, journalID, userID string, version, revision int, updatedAt time.Time) error {
+	return r.update(ctx, journalID, userID, revision, "version = $4, updated_at = $5", version, updatedAt)
+}
+
+func (r *PostgresRepository) SetTaxonomy(ctx context.Context, journalID, userID string, taxonomy Taxonomy, revision int, updatedAt time.Time) error {
+	return r.update(ctx, journalID, userID, revision, "taxonomy = $4, updated_at = $5", taxonomy, updatedAt)
+}
+
+// update sets the columns of the user's journal entry while it is at the revision, moving it to the next.
+// The assignments take their arguments from $4.
+func (r *PostgresRepository) update(ctx context.Context, journalID, userID string, revision int, set string, args ...any) error {
+	tag, err := r.pool.Exec(ctx, "UPDATE journal SET "+set+", revision = revision + 1 WHERE journal_id = $1 AND user_id = $2 AND revision = $3",
+		append([]any{journalID, userID, revision}, args...)...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		// Tell a changed entry from a missing one, which is left to the caller as before
+		var exists bool
+		err := r.pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM journal WHERE journal_id = $1 AND user_id = $2)", journalID, userID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return store.ErrConflict
+		}
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ChangeStatus(ctx context.Context, journalID, userID string, change StatusChange) error {
+	// Match on the current status so concurrent transitions cannot skip validation
+	tag, err := r.pool.Exec(ctx, `UPDATE journal SET status = $4, status_changed_by = $5, status_changed_at = $6,
+		updated_at = $6, status_history = status_history || $7::jsonb, revision = revision + 1
+		WHERE journal_id = $1 AND user_id = $2 AND status = $3`,
+		journalID, userID, change.From, change.To, change.ChangedBy, change.ChangedAt, []StatusChange{change})
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrConflict
+	}
+	return nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context, filter Filter) ([]JournalEntry, error) {
+	where, args := postgresFilter(filter)
+	return r.find(ctx, "SELECT "+journalColumns+" FROM journal"+where+" ORDER BY created_at", args...)
+}
+
+func (r *PostgresRepository) ListRelated(ctx context.Context, journalID string, terms []string) ([]JournalEntry, error) {
+	return r.find(ctx, `SELECT `+journalColumns+` FROM journal
+		WHERE status = $1 AND journal_id <> $2 AND (
+			taxonomy->'categories' ?| $3 OR taxonomy->'subcategories' ?| $3 OR
+			taxonomy->'topics' ?| $3 OR taxonomy->'tags' ?| $3)`,
+		StatusPublic, journalID, terms)
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, journalID, userID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM journal WHERE journal_id = $1 AND user_id = $2", journalID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// findOne returns the single journal entry selected by the query
+func (r *PostgresRepository) findOne(ctx context.Context, query string, args ...any) (JournalEntry, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return JournalEntry{}, err
+	}
+	journal, err := pgx.CollectExactlyOneRow(rows, scanJournal)
+	return journal, store.PostgresErr(err)
+}
+
+// find returns every journal entry selected by the query
+func (r *PostgresRepository) find(ctx context.Context, query string, args ...any) ([]JournalEntry, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanJournal)
+}
+
+// scanJournal reads a row selected with journalColumns
+func scanJournal(row pgx.CollectableRow) (JournalEntry, error) {
+	var j JournalEntry
+	err := row.Scan(&j.JournalID, &j.UserID, &j.Version, &j.Entries, &j.Status, &j.Taxonomy, &j.Summary,
+		&j.CreatedAt, &j.UpdatedAt, &j.StatusChangedBy, &j.StatusChangedAt, &j.StatusHistory, &j.Revision)
+	return j, err
+}
+
+// postgresFilter converts a filter into a WHERE clause on the journal table and its arguments
+func postgresFilter(filter Filter) (string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.UserID != "" {
+		add("user_id = $%d", filter.UserID)
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		add("created_at >= $%d", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		add("created_at <= $%d", filter.CreatedTo)
+	}
+	if !filter.StatusChangedAfter.IsZero() {
+		add("COALESCE(status_changed_at, created_at) > $%d", filter.StatusChangedAfter)
+	}
+	if !filter.StatusChangedUntil.IsZero() {
+		add("COALESCE(status_changed_at, created_at) <= $%d", filter.StatusChangedUntil)
+	}
+
+	// Containment queries can use the GIN index on taxonomy
+	for key, term := range map[string]string{
+		"categories":    filter.Category,
+		"subcategories": filter.Subcategory,
+		"topics":        filter.Topic,
+		"tags":          filter.Tag,
+	} {
+		if term != "" {
+			add("taxonomy @> jsonb_build_object('"+key+"', jsonb_build_array($%d::text))", term)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *PostgresRepository) GetSettings(ctx context.Context, userID string) (Settings, error) {
+	var s Settings
+	err := r.pool.QueryRow(ctx, `SELECT user_id, status, taxonomy, process_on_publish, comments, updated_at
+		FROM journal_settings WHERE user_id = $1`, userID).
+		Scan(&s.UserID, &s.Status, &s.Taxonomy, &s.ProcessOnPublish, &s.Comments, &s.UpdatedAt)
+	return s, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) SaveSettings(ctx context.Context, s Settings) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO journal_settings (user_id, status, taxonomy, process_on_publish, comments, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE SET status = EXCLUDED.status, taxonomy = EXCLUDED.taxonomy,
+		process_on_publish = EXCLUDED.process_on_publish, comments = EXCLUDED.comments, updated_at = EXCLUDED.updated_at`,
+		s.UserID, s.Status, s.Taxonomy, s.ProcessOnPublish, s.Comments, s.UpdatedAt)
+	return err
+}
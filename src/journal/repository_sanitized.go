@@ -0,0 +1,38 @@
+package journal
+
+import (
+	"context"
+	"slices"
+
+	"profile-api/sanitize"
+)
+
+// SanitizedRepository cleans the user-supplied text of journal entries before storing them
+type SanitizedRepository struct {
+	Repository
+}
+
+// NewSanitizedRepository wraps the repository to clean journal entries with the configured sanitize policies
+func NewSanitizedRepository(r Repository) *SanitizedRepository {
+	return &SanitizedRepository{Repository: r}
+}
+
+func (r *SanitizedRepository) Create(ctx context.Context, journal JournalEntry) error {
+	return r.Repository.Create(ctx, sanitized(journal))
+}
+
+func (r *SanitizedRepository) SaveEntries(ctx context.Context, journal JournalEntry) error {
+	return r.Repository.SaveEntries(ctx, sanitized(journal))
+}
+
+// sanitized cleans every version of the entry, which leaves those cleaned before as they are
+func sanitized(journal JournalEntry) JournalEntry {
+	journal.Summary = sanitize.Field(sanitize.JournalSummary, journal.Summary)
+	// Copy the entries rather than cleaning the caller's
+	journal.Entries = slices.Clone(journal.Entries)
+	for i, entry := range journal.Entries {
+		journal.Entries[i].Title = sanitize.Field(sanitize.JournalTitle, entry.Title)
+		journal.Entries[i].Content = sanitize.Field(sanitize.JournalContent, entry.Content)
+	}
+	return journal
+}
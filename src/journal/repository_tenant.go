@@ -0,0 +1,66 @@
+package journal
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, journal JournalEntry) error {
+	return r.repos.For(ctx).Create(ctx, journal)
+}
+
+func (r *TenantRepository) Get(ctx context.Context, journalID string) (JournalEntry, error) {
+	return r.repos.For(ctx).Get(ctx, journalID)
+}
+
+func (r *TenantRepository) GetOwned(ctx context.Context, journalID, userID string) (JournalEntry, error) {
+	return r.repos.For(ctx).GetOwned(ctx, journalID, userID)
+}
+
+func (r *TenantRepository) SaveEntries(ctx context.Context, journal JournalEntry) error {
+	return r.repos.For(ctx).SaveEntries(ctx, journal)
+}
+
+func (r *TenantRepository) SetVersion(ctx context.Context, journalID, userID string, version, revision int, updatedAt time.Time) error {
+	return r.repos.For(ctx).SetVersion(ctx, journalID, userID, version, revision, updatedAt)
+}
+
+func (r *TenantRepository) SetTaxonomy(ctx context.Context, journalID, userID string, taxonomy Taxonomy, revision int, updatedAt time.Time) error {
+	return r.repos.For(ctx).SetTaxonomy(ctx, journalID, userID, taxonomy, revision, updatedAt)
+}
+
+func (r *TenantRepository) ChangeStatus(ctx context.Context, journalID, userID string, change StatusChange) error {
+	return r.repos.For(ctx).ChangeStatus(ctx, journalID, userID, change)
+}
+
+func (r *TenantRepository) List(ctx context.Context, filter Filter) ([]JournalEntry, error) {
+	return r.repos.For(ctx).List(ctx, filter)
+}
+
+func (r *TenantRepository) ListRelated(ctx context.Context, journalID string, terms []string) ([]JournalEntry, error) {
+	return r.repos.For(ctx).ListRelated(ctx, journalID, terms)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, journalID, userID string) error {
+	return r.repos.For(ctx).Delete(ctx, journalID, userID)
+}
+
+func (r *TenantRepository) GetSettings(ctx context.Context, userID string) (Settings, error) {
+	return r.repos.For(ctx).GetSettings(ctx, userID)
+}
+
+func (r *TenantRepository) SaveSettings(ctx context.Context, settings Settings) error {
+	return r.repos.For(ctx).SaveSettings(ctx, settings)
+}
@@ -0,0 +1,92 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userSettings returns the user's journal settings, or the defaults when they never changed them
+func userSettings(ctx context.Context, userID string) (Settings, error) {
+	s, err := repo.GetSettings(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return Settings{UserID: userID, Status: StatusPending, Comments: CommentsEveryone}, nil
+	}
+	return s, err
+}
+
+// initialStatus returns the status the user's settings give a new journal entry
+func (s Settings) initialStatus() string {
+	if s.Status == StatusPublic && s.ProcessOnPublish {
+		return StatusProcessing
+	}
+	return s.Status
+}
+
+// @Summary Get journal settings
+// @Description Returns the defaults the current user's new journal entries are created with: their status, their taxonomy, whether entries created public are sent to processing first, and who may comment on them
+// @Tags journal
+// @Produce json
+// @Success 200 {object} Settings
+// @Failure 500 {object} apierror.Response "Error message"
+// @Router /journal/settings [get]
+func GetJournalSettings(c *gin.Context) {
+	userID := c.MustGet("userID").(string)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	s, err := userSettings(ctx, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Error retrieving journal settings"))
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
+
+// @Summary Update journal settings
+// @Description Replaces the defaults the current user's new journal entries are created with. Entries already created keep their status and taxonomy.
+// @Tags journal
+// @Accept json
+// @Produce json
+// @Param settings body SettingsRequest true "The journal settings"
+// @Success 200 {object} Settings
+// @Failure 400 {object} apierror.Response "Error message"
+// @Failure 500 {object} apierror.Response "Error message"
+// @Router /journal/settings [put]
+func UpdateJournalSettings(c *gin.Context) {
+	userID := c.MustGet("userID").(string)
+
+	var req SettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	s := Settings{
+		UserID:           userID,
+		Status:           req.Status,
+		Taxonomy:         req.Taxonomy,
+		ProcessOnPublish: req.ProcessOnPublish,
+		Comments:         req.Comments,
+		UpdatedAt:        time.Now(),
+	}
+	if s.Comments == "" {
+		s.Comments = CommentsEveryone
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.SaveSettings(ctx, s); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Error updating journal settings"))
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
@@ -0,0 +1,39 @@
+package journal
+
+// Journal entry statuses
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusPrivate    = "private"
+	StatusPublic     = "public"
+	StatusArchived   = "archived"
+)
+
+// statusTransitions lists the statuses each status may move to
+var statusTransitions = map[string][]string{
+	StatusPending:    {StatusProcessing, StatusPrivate, StatusPublic, StatusArchived},
+	StatusProcessing: {StatusPending, StatusPrivate, StatusPublic},
+	StatusPrivate:    {StatusProcessing, StatusPublic, StatusArchived},
+	StatusPublic:     {StatusProcessing, StatusPrivate, StatusArchived},
+	StatusArchived:   {StatusPrivate, StatusPublic},
+}
+
+// isValidStatus reports whether the status is a known journal status
+func isValidStatus(status string) bool {
+	_, ok := statusTransitions[status]
+	return ok
+}
+
+// canTransition reports whether a journal entry may move from one status to another
+func canTransition(from, to string) bool {
+	// Entries created before statuses were validated may hold unknown values, allow them to be corrected
+	if !isValidStatus(from) {
+		return isValidStatus(to)
+	}
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package journal
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	for _, tc := range []struct {
+		from, to string
+		ok       bool
+	}{
+		{StatusPending, StatusProcessing, true},
+		{StatusPending, StatusPublic, true},
+		{StatusPending, StatusPending, false},
+		{StatusProcessing, StatusPending, true},
+		{StatusProcessing, StatusPublic, true},
+		{StatusProcessing, StatusArchived, false},
+		{StatusPrivate, StatusPublic, true},
+		{StatusPrivate, StatusPending, false},
+		{StatusPublic, StatusPrivate, true},
+		{StatusPublic, StatusArchived, true},
+		{StatusPublic, StatusPending, false},
+		{StatusArchived, StatusPublic, true},
+		{StatusArchived, StatusProcessing, false},
+		{StatusPublic, "published", false},
+		// Entries stored with an unknown status may move to any known one, but not to another unknown one
+		{"draft", StatusPrivate, true},
+		{"draft", "published", false},
+	} {
+		if got := canTransition(tc.from, tc.to); got != tc.ok {
+			t.Errorf("canTransition(%q, %q) = %t, want %t", tc.from, tc.to, got, tc.ok)
+		}
+	}
+}
@@ -13,21 +13,77 @@ type JournalEntry struct {
 	Summary   string    `bson:"summary" json:"summary"`
 	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+	// Revision counts the changes to the entry, unlike Version which picks one of its entries
+	Revision int `bson:"revision" json:"revision"`
+
+	StatusChangedBy string         `bson:"status_changed_by,omitempty" json:"statusChangedBy,omitempty"`
+	StatusChangedAt *time.Time     `bson:"status_changed_at,omitempty" json:"statusChangedAt,omitempty"`
+	StatusHistory   []StatusChange `bson:"status_history,omitempty" json:"statusHistory,omitempty"`
+}
+
+// VersionRequest picks which of a journal entry's versions is current
+type VersionRequest struct {
+	Version int `json:"version" binding:"required,min=1"`
+}
+
+// StatusRequest moves a journal entry to another status
+type StatusRequest struct {
+	// Status is pending, processing, private, public or archived
+	Status string `json:"status" binding:"required"`
+}
+
+// StatusChange records a single status transition of a journal entry
+type StatusChange struct {
+	From      string    `bson:"from" json:"from"`
+	To        string    `bson:"to" json:"to"`
+	ChangedBy string    `bson:"changed_by" json:"changedBy"`
+	ChangedAt time.Time `bson:"changed_at" json:"changedAt"`
 }
 
 // Entry represents a versioned entry in the journal
 type Entry struct {
 	Version     int       `bson:"version" json:"version"`
-	Title       string    `bson:"title" json:"title"`
-	Content     string    `bson:"content" json:"content"`
-	Attachments []string  `bson:"attachments" json:"attachments"`
+	Title       string    `bson:"title" json:"title" binding:"required,notblank,max=300"`
+	Content     string    `bson:"content" json:"content" binding:"required,max=200000"`
+	Attachments []string  `bson:"attachments" json:"attachments" binding:"max=50,dive,weburl,max=2048"`
 	UpdatedAt   time.Time `bson:"updated_at" json:"updatedAt"`
 }
 
+// Comment policies of a user's journal entries
+const (
+	CommentsEveryone = "everyone"
+	CommentsMembers  = "members"
+	CommentsNone     = "none"
+)
+
+// Settings are the defaults a user's new journal entries are created with
+type Settings struct {
+	UserID string `bson:"user_id" json:"userID"`
+	// Status is the status new entries start in: pending, private or public
+	Status string `bson:"status" json:"status"`
+	// Taxonomy is given to new entries
+	Taxonomy Taxonomy `bson:"taxonomy" json:"taxonomy"`
+	// ProcessOnPublish sends entries that would be created public to processing instead, which makes them
+	// public once it finishes
+	ProcessOnPublish bool `bson:"process_on_publish" json:"processOnPublish"`
+	// Comments is who may comment on the user's entries when comments are enabled: everyone, members or none
+	Comments  string    `bson:"comments" json:"comments"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+}
+
+// SettingsRequest replaces a user's journal settings
+type SettingsRequest struct {
+	Status           string   `json:"status" binding:"required,oneof=pending private public"`
+	Taxonomy         Taxonomy `json:"taxonomy"`
+	ProcessOnPublish bool     `json:"processOnPublish"`
+	// Comments defaults to everyone
+	Comments string `json:"comments" binding:"omitempty,oneof=everyone members none"`
+}
+
 // Taxonomy represents categories, subcategories, topics, and tags for the journal entry
 type Taxonomy struct {
-	Categories    []string `bson:"categories" json:"categories"`
-	Subcategories []string `bson:"subcategories" json:"subcategories"`
-	Topics        []string `bson:"topics" json:"topics"`
-	Tags          []string `bson:"tags" json:"tags"`
+	Categories    []string `bson:"categories" json:"categories" binding:"max=20,dive,notblank,max=100"`
+	Subcategories []string `bson:"subcategories" json:"subcategories" binding:"max=20,dive,notblank,max=100"`
+	Topics        []string `bson:"topics" json:"topics" binding:"max=20,dive,notblank,max=100"`
+	Tags          []string `bson:"tags" json:"tags" binding:"max=50,dive,notblank,max=100"`
 }
@@ -0,0 +1,274 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/dryrun"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// taxonomyAttempts is how many times a term change is applied to an entry changed by another request meanwhile
+const taxonomyAttempts = 3
+
+// TaxonomyFields are the fields of the taxonomy whose terms can be managed, named as in its JSON
+var TaxonomyFields = []string{"categories", "subcategories", "topics", "tags"}
+
+// terms returns the taxonomy's list of terms named by the field, or nil when there is none
+func (t *Taxonomy) terms(field string) *[]string {
+	switch field {
+	case "categories":
+		return &t.Categories
+	case "subcategories":
+		return &t.Subcategories
+	case "topics":
+		return &t.Topics
+	case "tags":
+		return &t.Tags
+	}
+	return nil
+}
+
+// TermCount is a taxonomy term and the number of the user's journal entries holding it
+type TermCount struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+// RenameTermRequest renames a term of a taxonomy field
+type RenameTermRequest struct {
+	From string `json:"from" binding:"required,notblank,max=100"`
+	To   string `json:"to" binding:"required,notblank,max=100"`
+}
+
+// MergeTermsRequest replaces several terms of a taxonomy field with one, which may be one of them
+type MergeTermsRequest struct {
+	From []string `json:"from" binding:"required,min=1,max=50,dive,notblank,max=100"`
+	Into string   `json:"into" binding:"required,notblank,max=100"`
+}
+
+// DeleteTermsRequest removes terms from a taxonomy field
+type DeleteTermsRequest struct {
+	Terms []string `json:"terms" binding:"required,min=1,max=50,dive,notblank,max=100"`
+}
+
+// TermsResult lists the journal entries a change to the terms of a taxonomy field updated, or would update
+// in a dry run
+type TermsResult struct {
+	DryRun  bool     `json:"dryRun"`
+	Field   string   `json:"field"`
+	Count   int      `json:"count"`
+	Updated []string `json:"updated"`
+}
+
+// replaceTerms returns the taxonomy with the terms of the field replaced by into, or removed when into is
+// empty, and whether any was. A term ending up twice in the field is kept once.
+func replaceTerms(t Taxonomy, field string, from []string, into string) (Taxonomy, bool) {
+	terms := t.terms(field)
+	kept := []string{}
+	changed := false
+	for _, term := range *terms {
+		if slices.Contains(from, term) {
+			term = into
+			changed = true
+		}
+		if term != "" && !slices.Contains(kept, term) {
+			kept = append(kept, term)
+		}
+	}
+	*terms = kept
+	return t, changed
+}
+
+// changeTerms replaces the terms of the field with into, or removes them when into is empty, in each of the
+// user's journal entries holding any of them. Entries are updated one by one through the repository, so each
+// change is audited; an entry changed meanwhile by another request has the change applied to its latest
+// taxonomy.
+func changeTerms(ctx context.Context, userID, field string, from []string, into string) (TermsResult, error) {
+	result := TermsResult{DryRun: dryrun.Active(ctx), Field: field, Updated: []string{}}
+	listCtx, cancel := utils.WithOperationTimeout(ctx)
+	entries, err := repo.List(listCtx, Filter{UserID: userID})
+	cancel()
+	if err != nil {
+		return result, err
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		taxonomy, changed := replaceTerms(entry.Taxonomy, field, from, into)
+		if !changed {
+			continue
+		}
+		entryCtx, cancel := utils.WithOperationTimeout(ctx)
+		for attempt := 1; ; attempt++ {
+			err = repo.SetTaxonomy(entryCtx, entry.JournalID, userID, taxonomy, entry.Revision, now)
+			if !errors.Is(err, store.ErrConflict) || attempt == taxonomyAttempts {
+				break
+			}
+			if entry, err = repo.GetOwned(entryCtx, entry.JournalID, userID); err != nil {
+				break
+			}
+			if taxonomy, changed = replaceTerms(entry.Taxonomy, field, from, into); !changed {
+				break
+			}
+		}
+		cancel()
+		if errors.Is(err, store.ErrNotFound) {
+			// Deleted since it was listed, it holds no terms any more
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("updated %d journal entries before failing: %w", len(result.Updated), err)
+		}
+		if changed {
+			result.Updated = append(result.Updated, entry.JournalID)
+		}
+	}
+	result.Count = len(result.Updated)
+	if result.Count == 0 {
+		return result, apierror.NotFound("No journal entry holds the terms")
+	}
+	return result, nil
+}
+
+// taxonomyField returns the taxonomy field named by the path, responding 400 when there is no such field
+func taxonomyField(c *gin.Context) (string, bool) {
+	field := c.Param("field")
+	if !slices.Contains(TaxonomyFields, field) {
+		apierror.Abort(c, apierror.BadRequest("field must be one of categories, subcategories, topics, tags"))
+		return "", false
+	}
+	return field, true
+}
+
+// @Summary List the user's taxonomy terms
+// @Description Lists the terms of each taxonomy field across the user's journal entries, with the number of entries holding each, so misspelt or duplicate terms can be found and renamed, merged or deleted
+// @Tags journal
+// @Produce json
+// @Success 200 {object} map[string][]TermCount
+// @Failure 500 {object} apierror.Response "Error message"
+// @Router /journal/taxonomy [get]
+func GetTaxonomyTerms(c *gin.Context) {
+	userID := c.MustGet("userID").(string)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	entries, err := repo.List(ctx, Filter{UserID: userID})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Error retrieving journal entries"))
+		return
+	}
+
+	result := map[string][]TermCount{}
+	for _, field := range TaxonomyFields {
+		counts := map[string]int{}
+		for _, entry := range entries {
+			for _, term := range *entry.Taxonomy.terms(field) {
+				counts[term]++
+			}
+		}
+		terms := []TermCount{}
+		for term, count := range counts {
+			terms = append(terms, TermCount{Term: term, Count: count})
+		}
+		slices.SortFunc(terms, func(a, b TermCount) int { return strings.Compare(a.Term, b.Term) })
+		result[field] = terms
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Rename a taxonomy term
+// @Description Renames a term of a taxonomy field across the user's journal entries. Renaming a term to one an entry already holds merges them. A dry run, sent with the X-Dry-Run header, returns the entries that would be updated without updating them.
+// @Tags journal
+// @Accept json
+// @Produce json
+// @Param field path string true "Taxonomy field" Enums(categories, subcategories, topics, tags)
+// @Param body body RenameTermRequest true "The term and its new name"
+// @Success 200 {object} TermsResult
+// @Failure 400 {object} apierror.Response "Error message"
+// @Failure 404 {object} apierror.Response "No journal entry holds the term"
+// @Failure 500 {object} apierror.Response "Error message"
+// @Router /journal/taxonomy/{field}/rename [post]
+func RenameTaxonomyTerm(c *gin.Context) {
+	field, ok := taxonomyField(c)
+	if !ok {
+		return
+	}
+	var req RenameTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	writeTermsResult(c, field, []string{req.From}, req.To)
+}
+
+// @Summary Merge taxonomy terms
+// @Description Replaces several terms of a taxonomy field with one across the user's journal entries, each entry keeping a single copy of it. The term merged into may be one of those merged. A dry run, sent with the X-Dry-Run header, returns the entries that would be updated without updating them.
+// @Tags journal
+// @Accept json
+// @Produce json
+// @Param field path string true "Taxonomy field" Enums(categories, subcategories, topics, tags)
+// @Param body body MergeTermsRequest true "The terms and the one they are merged into"
+// @Success 200 {object} TermsResult
+// @Failure 400 {object} apierror.Response "Error message"
+// @Failure 404 {object} apierror.Response "No journal entry holds the terms"
+// @Failure 500 {object} apierror.Response "Error message"
+// @Router /journal/taxonomy/{field}/merge [post]
+func MergeTaxonomyTerms(c *gin.Context) {
+	field, ok := taxonomyField(c)
+	if !ok {
+		return
+	}
+	var req MergeTermsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	writeTermsResult(c, field, req.From, req.Into)
+}
+
+// @Summary Delete taxonomy terms
+// @Description Removes terms from a taxonomy field of the user's journal entries. A dry run, sent with the X-Dry-Run header, returns the entries that would be updated without updating them.
+// @Tags journal
+// @Accept json
+// @Produce json
+// @Param field path string true "Taxonomy field" Enums(categories, subcategories, topics, tags)
+// @Param body body DeleteTermsRequest true "The terms to remove"
+// @Success 200 {object} TermsResult
+// @Failure 400 {object} apierror.Response "Error message"
+// @Failure 404 {object} apierror.Response "No journal entry holds the terms"
+// @Failure 500 {object} apierror.Response "Error message"
+// @Router /journal/taxonomy/{field}/delete [post]
+func DeleteTaxonomyTerms(c *gin.Context) {
+	field, ok := taxonomyField(c)
+	if !ok {
+		return
+	}
+	var req DeleteTermsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	writeTermsResult(c, field, req.Terms, "")
+}
+
+// writeTermsResult changes the terms of the field across the user's journal entries and responds with the
+// entries updated
+func writeTermsResult(c *gin.Context, field string, from []string, into string) {
+	userID := c.MustGet("userID").(string)
+	result, err := changeTerms(c.Request.Context(), userID, field, from, into)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Error updating journal entries"))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
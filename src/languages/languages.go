@@ -0,0 +1,249 @@
+package languages
+
+import (
+	"context"
+	"errors"
+
+	"profile-api/apierror"
+	"profile-api/apiversion"
+	"profile-api/auth"
+	"profile-api/bulk"
+	"profile-api/dryrun"
+	"profile-api/quota"
+	"profile-api/store"
+	"profile-api/utils"
+	"profile-api/visibility"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var repo Repository
+
+// GetLanguages retrieves all languages a user speaks.
+//
+//	@Summary		Get all languages
+//	@Description	Retrieves every language a user speaks with their CEFR proficiency level, without the fields their visibility hides from the requester
+//	@Tags			Languages
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Success		200		{array}		Language
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve languages"
+//	@Router			/languages/{userid} [get]
+func GetLanguages(c *gin.Context) {
+	userID := c.Param("userid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	languages, err := repo.List(ctx, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve languages"))
+		return
+	}
+
+	visibility.List(c, languages)
+}
+
+// GetLanguage retrieves a specific language of a user.
+//
+//	@Summary		Get a language
+//	@Description	Retrieves a specific language of a user, without the fields its visibility hides from the requester
+//	@Tags			Languages
+//	@Produce		json
+//	@Param			userid		path		string	true	"User ID"
+//	@Param			languageid	path		string	true	"Language ID"
+//	@Success		200			{object}	Language
+//	@Failure		404			{object}	apierror.Response	"Language not found"
+//	@Failure		500			{object}	apierror.Response	"Could not retrieve language"
+//	@Router			/languages/{userid}/{languageid} [get]
+func GetLanguage(c *gin.Context) {
+	userID := c.Param("userid")
+	languageID := c.Param("languageid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	language, err := repo.Get(ctx, userID, languageID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve language"))
+		return
+	}
+
+	visibility.OK(c, language)
+}
+
+// PostLanguage adds a language a user speaks.
+//
+//	@Summary		Add a language
+//	@Description	Adds a language the user speaks, with its BCP 47 tag and a proficiency level of A1, A2, B1, B2, C1, C2 or native
+//	@Tags			Languages
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string		true	"User ID"
+//	@Param			body	body		Language	true	"Language JSON object"
+//	@Success		200		{object}	Language	"The created language with its ID, and a message"
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the languages"
+//	@Failure		413		{object}	apierror.Response	"Language limit reached"
+//	@Failure		500		{object}	apierror.Response	"Could not create language"
+//	@Security		BearerAuth
+//	@Router			/languages/{userid} [post]
+func PostLanguage(c *gin.Context) {
+	userID := c.Param("userid")
+
+	var req Language
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	req.UserID = userID
+	req.LanguageID = primitive.NewObjectID().Hex()
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Create(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create language"))
+		return
+	}
+
+	apiversion.Created(c, req.LanguageID, req, apiversion.Announce("Language added", req))
+}
+
+// PutLanguage updates or creates a specific language of a user.
+//
+//	@Summary		Update or create a language
+//	@Description	Updates or creates a specific language of a user
+//	@Tags			Languages
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid		path		string		true	"User ID"
+//	@Param			languageid	path		string		true	"Language ID"
+//	@Param			body		body		Language	true	"Language JSON object"
+//	@Success		200			{object}	map[string]string
+//	@Failure		400			{object}	apierror.Response	"Invalid request body"
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		403			{object}	apierror.Response	"Not the owner of the languages"
+//	@Failure		500			{object}	apierror.Response	"Could not update language"
+//	@Security		BearerAuth
+//	@Router			/languages/{userid}/{languageid} [put]
+func PutLanguage(c *gin.Context) {
+	userID := c.Param("userid")
+	languageID := c.Param("languageid")
+
+	var req Language
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	req.UserID = userID
+	req.LanguageID = languageID
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Save(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update language"))
+		return
+	}
+
+	apiversion.Updated(c, req, gin.H{"message": "Language updated"})
+}
+
+// DeleteLanguage deletes a specific language of a user.
+//
+//	@Summary		Delete a language
+//	@Description	Deletes a specific language of a user
+//	@Tags			Languages
+//	@Produce		json
+//	@Param			userid		path		string	true	"User ID"
+//	@Param			languageid	path		string	true	"Language ID"
+//	@Success		200			{object}	map[string]string
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		403			{object}	apierror.Response	"Not the owner of the languages"
+//	@Failure		500			{object}	apierror.Response	"Could not delete language"
+//	@Failure		404			{object}	apierror.Response	"Language not found"
+//	@Security		BearerAuth
+//	@Router			/languages/{userid}/{languageid} [delete]
+func DeleteLanguage(c *gin.Context) {
+	userID := c.Param("userid")
+	languageID := c.Param("languageid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	err := repo.Delete(ctx, userID, languageID)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Language not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete language"))
+		return
+	}
+
+	apiversion.NoContent(c, gin.H{"message": "Language deleted"})
+}
+
+// BulkDeleteLanguages deletes many of a user's languages at once.
+//
+//	@Summary		Delete languages in bulk
+//	@Description	Deletes the user's languages with the given IDs, or those whose fields match every value of the filter, such as {"proficiency": "A1"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.
+//	@Tags			Languages
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string				true	"User ID"
+//	@Param			body	body		bulk.DeleteRequest	true	"The languages to delete"
+//	@Success		200		{object}	bulk.DeleteResult
+//	@Failure		400		{object}	apierror.Response	"Invalid request, or a filter that is not confirmed"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the languages"
+//	@Failure		500		{object}	apierror.Response	"Could not delete languages"
+//	@Router			/languages/{userid}/bulk-delete [post]
+func BulkDeleteLanguages(c *gin.Context) {
+	userID := c.Param("userid")
+
+	var req bulk.DeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	items, err := repo.List(ctx, userID)
+	cancel()
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve languages"))
+		return
+	}
+	result, err := bulk.Delete(c.Request.Context(), req, items, func(item Language) string { return item.LanguageID }, func(ctx context.Context, id string) error {
+		return repo.Delete(ctx, userID, id)
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete languages"))
+		return
+	}
+
+	apiversion.OK(c, result)
+}
+
+// countLanguages counts the user's languages against their document quota
+func countLanguages(ctx context.Context, userID string) (int, error) {
+	items, err := repo.List(ctx, userID)
+	return len(items), err
+}
+
+// InitializeRoutes initializes the languages routes
+func InitializeRoutes(router *gin.RouterGroup, r Repository, users auth.Repository) {
+	repo = r
+
+	authOptional := auth.AuthMiddleware(users, false)
+
+	router.GET("/:userid", authOptional, GetLanguages)
+	router.GET("/:userid/:languageid", authOptional, GetLanguage)
+
+	protected := router.Group("/")
+	protected.Use(auth.AuthMiddleware(users, true))
+	protected.POST("/:userid", dryrun.Supported(), auth.RequireOwner(), quota.LimitDocuments("languages", countLanguages), PostLanguage)
+	protected.PUT("/:userid/:languageid", dryrun.Supported(), auth.RequireOwner(), PutLanguage)
+	protected.DELETE("/:userid/:languageid", dryrun.Supported(), auth.RequireOwner(), DeleteLanguage)
+	protected.POST("/:userid/bulk-delete", dryrun.Supported(), auth.RequireOwner(), BulkDeleteLanguages)
+}
@@ -0,0 +1,41 @@
+package languages
+
+import "profile-api/visibility"
+
+// Proficiency levels of the Common European Framework of Reference for Languages, from the lowest, and of
+// native speakers
+const (
+	LevelA1     = "A1"
+	LevelA2     = "A2"
+	LevelB1     = "B1"
+	LevelB2     = "B2"
+	LevelC1     = "C1"
+	LevelC2     = "C2"
+	LevelNative = "native"
+)
+
+// Levels lists the proficiency levels a language may have, from the lowest
+var Levels = []string{LevelA1, LevelA2, LevelB1, LevelB2, LevelC1, LevelC2, LevelNative}
+
+// Language represents a language a user speaks
+type Language struct {
+	UserID     string `bson:"user_id" json:"user_id"`
+	LanguageID string `bson:"language_id" json:"language_id"`
+	Name       string `bson:"name" json:"name" binding:"required,notblank,max=100"`
+	// Code is the BCP 47 tag of the language, such as en or pt-BR
+	Code string `bson:"code" json:"code" binding:"omitempty,bcp47_language_tag"`
+	// Proficiency is the CEFR level the user speaks the language at, A1 to C2, or native
+	Proficiency string `bson:"proficiency" json:"proficiency" binding:"required,oneof=A1 A2 B1 B2 C1 C2 native"`
+	// Visibility maps fields to who they are shown to: public, members or private
+	Visibility visibility.Rules `bson:"visibility" json:"visibility,omitempty" binding:"omitempty,visibility"`
+}
+
+// Owner returns the ID of the user the language belongs to
+func (l Language) Owner() string {
+	return l.UserID
+}
+
+// FieldVisibility returns the rules of the language's fields
+func (l Language) FieldVisibility() visibility.Rules {
+	return l.Visibility
+}
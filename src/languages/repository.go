@@ -0,0 +1,19 @@
+package languages
+
+import "context"
+
+// Repository stores the languages users speak
+type Repository interface {
+	// List returns every language of the user
+	List(ctx context.Context, userID string) ([]Language, error)
+	// ListByUsers returns every language of any of the users
+	ListByUsers(ctx context.Context, userIDs []string) ([]Language, error)
+	// Get returns a single language, or store.ErrNotFound
+	Get(ctx context.Context, userID, languageID string) (Language, error)
+	// Create stores a new language
+	Create(ctx context.Context, item Language) error
+	// Save replaces a language, creating it if it does not exist
+	Save(ctx context.Context, item Language) error
+	// Delete removes a language, or returns store.ErrNotFound when the user has no such language
+	Delete(ctx context.Context, userID, languageID string) error
+}
@@ -0,0 +1,55 @@
+package languages
+
+import (
+	"context"
+	"errors"
+
+	"profile-api/audit"
+	"profile-api/store"
+)
+
+// AuditedRepository records every change to languages in the audit log
+type AuditedRepository struct {
+	Repository
+}
+
+// NewAuditedRepository wraps the repository to record its changes in the audit log
+func NewAuditedRepository(r Repository) *AuditedRepository {
+	return &AuditedRepository{Repository: r}
+}
+
+func (r *AuditedRepository) Create(ctx context.Context, item Language) error {
+	if err := r.Repository.Create(ctx, item); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionCreate, "language", item.UserID, item.LanguageID, nil, item)
+	return nil
+}
+
+func (r *AuditedRepository) Save(ctx context.Context, item Language) error {
+	before, err := r.Repository.Get(ctx, item.UserID, item.LanguageID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.Save(ctx, item); err != nil {
+		return err
+	}
+	audit.RecordSave(ctx, "language", item.UserID, item.LanguageID, before, existed, item)
+	return nil
+}
+
+func (r *AuditedRepository) Delete(ctx context.Context, userID, languageID string) error {
+	before, err := r.Repository.Get(ctx, userID, languageID)
+	if errors.Is(err, store.ErrNotFound) {
+		return r.Repository.Delete(ctx, userID, languageID)
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.Delete(ctx, userID, languageID); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionDelete, "language", userID, languageID, before, nil)
+	return nil
+}
@@ -0,0 +1,40 @@
+package languages
+
+import (
+	"context"
+
+	"profile-api/dryrun"
+)
+
+// DryRunRepository leaves languages unchanged in dry runs, reading them as usual. A deletion still
+// reports a missing document.
+type DryRunRepository struct {
+	Repository
+}
+
+// NewDryRunRepository wraps the repository to skip its writes in dry runs
+func NewDryRunRepository(r Repository) *DryRunRepository {
+	return &DryRunRepository{Repository: r}
+}
+
+func (r *DryRunRepository) Create(ctx context.Context, item Language) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.Create(ctx, item)
+}
+
+func (r *DryRunRepository) Save(ctx context.Context, item Language) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.Save(ctx, item)
+}
+
+func (r *DryRunRepository) Delete(ctx context.Context, userID, languageID string) error {
+	if dryrun.Active(ctx) {
+		_, err := r.Repository.Get(ctx, userID, languageID)
+		return err
+	}
+	return r.Repository.Delete(ctx, userID, languageID)
+}
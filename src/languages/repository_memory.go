@@ -0,0 +1,96 @@
+package languages
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps languages in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu    sync.RWMutex
+	items []Language
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+// index returns the position of a language in items, or -1. The caller must hold the lock.
+func (r *MemoryRepository) index(userID, languageID string) int {
+	for i, item := range r.items {
+		if item.UserID == userID && item.LanguageID == languageID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string) ([]Language, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Language
+	for _, item := range r.items {
+		if item.UserID == userID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Language, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Language
+	for _, item := range r.items {
+		if slices.Contains(userIDs, item.UserID) {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, userID, languageID string) (Language, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.index(userID, languageID)
+	if i < 0 {
+		return Language{}, store.ErrNotFound
+	}
+	return r.items[i], nil
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, item Language) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.index(item.UserID, item.LanguageID) >= 0 {
+		return store.ErrConflict
+	}
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, item Language) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(item.UserID, item.LanguageID); i >= 0 {
+		r.items[i] = item
+		return nil
+	}
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, userID, languageID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, languageID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.items = append(r.items[:i], r.items[i+1:]...)
+	return nil
+}
@@ -0,0 +1,70 @@
+package languages
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores languages in the languages collection
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{collection: db.Collection("languages")}
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string) ([]Language, error) {
+	return r.find(ctx, bson.M{"user_id": userID})
+}
+
+func (r *MongoRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Language, error) {
+	return r.find(ctx, bson.M{"user_id": bson.M{"$in": userIDs}})
+}
+
+func (r *MongoRepository) find(ctx context.Context, filter bson.M) ([]Language, error) {
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []Language
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *MongoRepository) Get(ctx context.Context, userID, languageID string) (Language, error) {
+	var item Language
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "language_id": languageID}).Decode(&item)
+	return item, store.MongoErr(err)
+}
+
+func (r *MongoRepository) Create(ctx context.Context, item Language) error {
+	_, err := r.collection.InsertOne(ctx, item)
+	return err
+}
+
+func (r *MongoRepository) Save(ctx context.Context, item Language) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": item.UserID, "language_id": item.LanguageID}, bson.M{"$set": item}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, userID, languageID string) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID, "language_id": languageID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package languages
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const languagesColumns = "user_id, language_id, name, code, proficiency, visibility"
+
+// PostgresRepository stores languages in the languages table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string) ([]Language, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+languagesColumns+" FROM languages WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanLanguage)
+}
+
+func (r *PostgresRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Language, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+languagesColumns+" FROM languages WHERE user_id = ANY($1) ORDER BY created_at", userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanLanguage)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, userID, languageID string) (Language, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+languagesColumns+" FROM languages WHERE user_id = $1 AND language_id = $2", userID, languageID)
+	if err != nil {
+		return Language{}, err
+	}
+	item, err := pgx.CollectExactlyOneRow(rows, scanLanguage)
+	return item, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, item Language) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO languages ("+languagesColumns+") VALUES ($1, $2, $3, $4, $5, $6)",
+		item.UserID, item.LanguageID, item.Name, item.Code, item.Proficiency, item.Visibility)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, item Language) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO languages ("+languagesColumns+") VALUES ($1, $2, $3, $4, $5, $6) "+
+		"ON CONFLICT (user_id, language_id) DO UPDATE SET name = EXCLUDED.name, code = EXCLUDED.code, proficiency = EXCLUDED.proficiency, visibility = EXCLUDED.visibility",
+		item.UserID, item.LanguageID, item.Name, item.Code, item.Proficiency, item.Visibility)
+	return err
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, userID, languageID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM languages WHERE user_id = $1 AND language_id = $2", userID, languageID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// scanLanguage reads a row selected with languagesColumns
+func scanLanguage(row pgx.CollectableRow) (Language, error) {
+	var item Language
+	err := row.Scan(&item.UserID, &item.LanguageID, &item.Name, &item.Code, &item.Proficiency, &item.Visibility)
+	return item, err
+}
@@ -0,0 +1,41 @@
+package languages
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID string) ([]Language, error) {
+	return r.repos.For(ctx).List(ctx, userID)
+}
+
+func (r *TenantRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Language, error) {
+	return r.repos.For(ctx).ListByUsers(ctx, userIDs)
+}
+
+func (r *TenantRepository) Get(ctx context.Context, userID, languageID string) (Language, error) {
+	return r.repos.For(ctx).Get(ctx, userID, languageID)
+}
+
+func (r *TenantRepository) Create(ctx context.Context, item Language) error {
+	return r.repos.For(ctx).Create(ctx, item)
+}
+
+func (r *TenantRepository) Save(ctx context.Context, item Language) error {
+	return r.repos.For(ctx).Save(ctx, item)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, userID, languageID string) error {
+	return r.repos.For(ctx).Delete(ctx, userID, languageID)
+}
@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"profile-api/config"
+	"profile-api/requestid"
+	"profile-api/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Setup configures the default slog logger from the log config. Output from the standard
+// log package is routed through the same handler.
+func Setup(cfg config.LogConfig) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return fmt.Errorf("invalid log level %q", cfg.Level)
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	case "text", "":
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	default:
+		return fmt.Errorf("invalid log format %q", cfg.Format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// Logger returns the default logger annotated with the current request's ID and trace ID
+func Logger(c *gin.Context) *slog.Logger {
+	if traceID := tracing.TraceID(c); traceID != "" {
+		return slog.With("request_id", requestid.Get(c), "trace_id", traceID)
+	}
+	return slog.With("request_id", requestid.Get(c))
+}
+
+// AccessLog logs every request with its status, latency and authenticated user.
+// Requests to any of the skipped paths are not logged.
+func AccessLog(skipPaths ...string) gin.HandlerFunc {
+	skip := map[string]bool{}
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		query := c.Request.URL.RawQuery
+
+		c.Next()
+
+		if skip[path] {
+			return
+		}
+
+		status := c.Writer.Status()
+		attrs := []any{
+			"request_id", requestid.Get(c),
+			"method", c.Request.Method,
+			"path", path,
+			"status", status,
+			"latency", time.Since(start),
+			"client_ip", c.ClientIP(),
+			"bytes", c.Writer.Size(),
+		}
+		if query != "" {
+			attrs = append(attrs, "query", query)
+		}
+		if traceID := tracing.TraceID(c); traceID != "" {
+			attrs = append(attrs, "trace_id", traceID)
+		}
+		if route := c.FullPath(); route != "" {
+			attrs = append(attrs, "route", route)
+		}
+		if userID, ok := c.Get("userID"); ok {
+			attrs = append(attrs, "user_id", userID)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+
+		switch {
+		case status >= 500:
+			slog.Error("request", attrs...)
+		case status >= 400:
+			slog.Warn("request", attrs...)
+		default:
+			slog.Info("request", attrs...)
+		}
+	}
+}
@@ -1,70 +1,62 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
-	"strconv"
-	"strings"
-	"text/template"
-	"time"
-
-	"profile-api/auth"
-	"profile-api/certificates"
-	"profile-api/experience"
-	"profile-api/journal"
-	"profile-api/profile"
-	"profile-api/qualifications"
-	"profile-api/skills"
-	"profile-api/utils"
+	"os/signal"
+	"syscall"
+
+	"profile-api/cli"
+	"profile-api/config"
+	"profile-api/jobs"
+	"profile-api/logging"
+	"profile-api/server"
+	"profile-api/tracing"
 
 	_ "profile-api/docs"
 
-	"github.com/gin-gonic/gin"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
+	"google.golang.org/grpc"
 )
 
-var templates *template.Template
-
-// extractIdentifierMiddleware is a middleware that extracts the subdomain or email from the request and stores it in the Gin context.
-func extractIdentifierMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		host := c.Request.Host
-		subdomain := extractSubdomain(host)
-		email := c.Param("email")
-
-		if subdomain != "" {
-			c.Set("identifier", subdomain)
-		} else if email != "" {
-			c.Set("identifier", email)
-		}
-		c.Next()
-	}
+// fatal logs the error and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
 
-// extractSubdomain takes the host and returns the subdomain part.
-// It will only return a subdomain if the host contains a valid subdomain.
-func extractSubdomain(host string) string {
-	// Remove the port if present
-	hostname, _, err := net.SplitHostPort(host)
-	if err != nil {
-		hostname = host // If no port, use the original host
+// stopGRPC waits for in-flight gRPC calls to finish, cancelling those still running when ctx expires
+func stopGRPC(ctx context.Context, s *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.Stop()
 	}
+}
 
-	// Split the hostname into parts
-	parts := strings.Split(hostname, ".")
-
-	// Ensure the hostname has at least three parts for a subdomain (e.g., sub.domain.com)
-	if len(parts) > 2 {
-		return parts[0] // Return the first part as the subdomain
+// runAdmin runs an admin command, returning the exit code
+func runAdmin(args []string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	err := cli.Run(ctx, args)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, cli.ErrUsage):
+		return 2
+	default:
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
 	}
-
-	// Return empty string if no subdomain is found
-	return ""
 }
 
 // @title			Go Profile API
@@ -78,109 +70,123 @@ func extractSubdomain(host string) string {
 // @license		MIT
 func main() {
 
-	db_name, ok := os.LookupEnv("MONGO_HOST")
-	if ok {
-		fmt.Printf("MONGO_HOST env is %s\n", db_name)
+	// profile-api admin <command> runs an admin command instead of the server
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		os.Exit(runAdmin(os.Args[2:]))
 	}
 
-	db_uri, ok := os.LookupEnv("MONGO_URI")
-	if ok {
-		fmt.Printf("MONGO_HOST env is %s\n", db_uri)
+	// Load config from the config file and environment
+	cfg, err := config.Load()
+	if err != nil {
+		fatal("Invalid configuration", err)
 	}
-
-	// Load config
-	_, err := os.Stat("config-selfhosted.json")
-	if os.IsNotExist(err) {
-		log.Fatalf("Config file not found")
+	if err := logging.Setup(cfg.Log); err != nil {
+		fatal("Invalid log configuration", err)
 	}
-	configData, err := os.ReadFile("config-selfhosted.json")
+
+	// Cancelled on SIGINT/SIGTERM to start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
 	if err != nil {
-		log.Fatalf("Error reading config file: %v", err)
+		fatal("Failed to initialize tracing", err)
 	}
 
-	var config map[string]interface{}
-	err = json.Unmarshal(configData, &config)
+	deps, err := server.Open(ctx, cfg)
 	if err != nil {
-		log.Fatalf("Error parsing config file: %v", err)
+		fatal("Failed to open storage", err)
 	}
-
-	// Load from config and allow environment variables to override
-	db_name = config["mongodb"].(map[string]interface{})["database"].(string)
-	if os.Getenv("MONGO_DB_NAME") != "" {
-		db_name = os.Getenv("MONGO_DB_NAME")
+	router, err := server.New(cfg, deps)
+	if err != nil {
+		fatal("Failed to initialize server", err)
 	}
-	db_uri = config["mongodb"].(map[string]interface{})["uri"].(string)
-	if os.Getenv("MONGO_URI") != "" {
-		db_uri = os.Getenv("MONGO_URI")
+	if err := server.StartBackground(ctx, cfg, deps); err != nil {
+		fatal("Failed to start background work", err)
 	}
 
-	listen_port := config["listen-port"].(float64)
-	if os.Getenv("LISTEN_PORT") != "" {
-		var err error
-		listen_port, err = strconv.ParseFloat(os.Getenv("LISTEN_PORT"), 64)
+	s := &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.ListenPort),
+		Handler: router,
+		// Routes of the uploads and exports classes extend these for themselves
+		ReadTimeout:       cfg.Timeouts.Default.Read.Std(),
+		WriteTimeout:      cfg.Timeouts.Default.Write.Std(),
+		ReadHeaderTimeout: cfg.Timeouts.ReadHeader.Std(),
+		IdleTimeout:       cfg.Timeouts.Idle.Std(),
+		MaxHeaderBytes:    cfg.Timeouts.MaxHeaderBytes,
+	}
+
+	serverErr := make(chan error, 3)
+
+	// Serve the gRPC API for internal services on its own port when enabled
+	var grpcServer *grpc.Server
+	if cfg.GRPC.ListenPort != 0 {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.ListenPort))
 		if err != nil {
-			log.Fatalf("Error parsing LISTEN_PORT environment variable: %v", err)
+			fatal("Failed to listen for gRPC", err)
 		}
+		grpcServer = server.NewGRPC(cfg, deps)
+		slog.Info("Starting gRPC server", "port", cfg.GRPC.ListenPort)
+		go func() {
+			serverErr <- grpcServer.Serve(lis)
+		}()
 	}
+	var httpServer *http.Server
+	if tlsEnabled(cfg.TLS) {
+		if handler := configureTLS(s, cfg.TLS, cfg.ListenPort, autocertCache(cfg, deps)); handler != nil {
+			httpServer = &http.Server{
+				Addr:              fmt.Sprintf(":%d", cfg.TLS.HTTPPort),
+				Handler:           handler,
+				ReadHeaderTimeout: cfg.Timeouts.ReadHeader.Std(),
+			}
+			slog.Info("Starting HTTP listener", "port", cfg.TLS.HTTPPort)
+			go func() {
+				serverErr <- httpServer.ListenAndServe()
+			}()
+		}
 
-	// Connect to the database
-	db, err := utils.ConnectDB(db_uri)
-	if err != nil {
-		log.Fatalf("Error connecting to MongoDB: %v", err)
+		slog.Info("Starting TLS server", "port", cfg.ListenPort)
+		go func() {
+			// Certificates come from the autocert manager when the files are empty
+			serverErr <- s.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		}()
+	} else {
+		slog.Info("Starting server", "port", cfg.ListenPort)
+		go func() {
+			serverErr <- s.ListenAndServe()
+		}()
 	}
 
-	router := gin.Default()
-	router.Use(extractIdentifierMiddleware())
-
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	// Initialize authentication routes
-	authRouter := router.Group("/api/v1/auth")
-	auth.InitializeRoutes(authRouter, db, db_name)
-
-	// Initialize profile routes
-	profileRouter := router.Group("/api/v1/profile")
-	profile.InitializeRoutes(profileRouter, db, db_name)
-
-	// Initialize experience routes
-	experienceRouter := router.Group("/api/v1/experience")
-	experience.InitializeRoutes(experienceRouter, db, db_name)
-
-	// Initialize qualifications routes
-	qualificationsRouter := router.Group("/api/v1/qualifications")
-	qualifications.InitializeRoutes(qualificationsRouter, db, db_name)
-
-	// Initialize qualifications routes
-	certificatesRouter := router.Group("/api/v1/certificates")
-	certificates.InitializeRoutes(certificatesRouter, db, db_name)
-
-	// Initialize skills routes
-	skillsRouter := router.Group("/api/v1/skills")
-	skills.InitializeRoutes(skillsRouter, db, db_name)
-
-	// Initialize journal routes
-	journalRouter := router.Group("/api/v1/journal")
-	journal.InitializeRoutes(journalRouter, db, db_name)
-
-	router.NoRoute(func(c *gin.Context) {
-		// Debugging the incoming path
-		path := c.Request.URL.Path
-		log.Printf("404: Incoming request path: %s", path)
-		c.JSON(http.StatusNotFound, gin.H{"error": "NotFound"})
-		return
-	})
-
-	s := &http.Server{
-		Addr:           fmt.Sprintf(":%d", int(listen_port)),
-		Handler:        router,
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20,
+	select {
+	case err := <-serverErr:
+		if err != nil && err != http.ErrServerClosed {
+			fatal("Server error", err)
+		}
+	case <-ctx.Done():
+		stop()
+		slog.Info("Shutting down, draining requests", "timeout", cfg.ShutdownTimeout.Std())
 	}
 
-	log.Printf("Starting server on port %d", int(listen_port))
+	// Stop accepting new connections and wait for in-flight requests to finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout.Std())
+	defer cancel()
+	if err := s.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Error shutting down server", "error", err)
+	}
+	if httpServer != nil {
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			slog.Error("Error shutting down HTTP listener", "error", err)
+		}
+	}
+	if grpcServer != nil {
+		stopGRPC(shutdownCtx, grpcServer)
+	}
 
-	//log.Fatal(s.ListenAndServeTLS(certPath, keyPath))
-	// Start the server
-	log.Fatal(s.ListenAndServe())
+	// Let workers finish the jobs they are running before closing the connections they use
+	jobs.Wait(shutdownCtx)
+	deps.Close(shutdownCtx)
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("Error flushing traces", "error", err)
+	}
+	slog.Info("Server stopped")
 }
@@ -0,0 +1,132 @@
+package moderation
+
+import "time"
+
+// Types of reported content
+const (
+	// TargetProfile is a user's profile, identified by the user's ID
+	TargetProfile = "profile"
+	// TargetJournal is a journal entry, identified by its journal ID
+	TargetJournal = "journal"
+)
+
+// Report statuses
+const (
+	// StatusOpen reports wait in the moderation queue
+	StatusOpen = "open"
+	// StatusResolved reports were acted on by an admin
+	StatusResolved = "resolved"
+	// StatusDismissed reports were found to need no action
+	StatusDismissed = "dismissed"
+)
+
+// Actions admins take on reported content
+const (
+	// ActionHide hides the content from everyone but its owner and admins
+	ActionHide = "hide"
+	// ActionWarn emails the owner a warning
+	ActionWarn = "warn"
+	// ActionSuspend disables the owner's account
+	ActionSuspend = "suspend"
+	// ActionDismiss closes the reports without acting, lifting an automatic hide
+	ActionDismiss = "dismiss"
+)
+
+// Report flags a public profile or journal entry to the moderators
+type Report struct {
+	ID         string `bson:"_id" json:"id"`
+	TargetType string `bson:"target_type" json:"targetType"`
+	TargetID   string `bson:"target_id" json:"targetID"`
+	// OwnerID is the user the reported content belongs to
+	OwnerID string `bson:"owner_id" json:"ownerID"`
+	// ReporterID is the user who reported, empty for reports by visitors who were not signed in
+	ReporterID string `bson:"reporter_id,omitempty" json:"reporterID,omitempty"`
+	// Reporter identifies who reported, the user or a hash of the visitor's address, so each counts once
+	Reporter string `bson:"reporter" json:"-"`
+	// Reason is spam, harassment, hate, sexual, violence, impersonation or other
+	Reason  string `bson:"reason" json:"reason"`
+	Details string `bson:"details,omitempty" json:"details,omitempty"`
+	Status  string `bson:"status" json:"status"`
+	// Action is what the admin resolving the report did
+	Action     string     `bson:"action,omitempty" json:"action,omitempty"`
+	ResolvedBy string     `bson:"resolved_by,omitempty" json:"resolvedBy,omitempty"`
+	ResolvedAt *time.Time `bson:"resolved_at,omitempty" json:"resolvedAt,omitempty"`
+	CreatedAt  time.Time  `bson:"created_at" json:"createdAt"`
+}
+
+// ReportRequest represents the request body for reporting content
+type ReportRequest struct {
+	// Type is profile or journal
+	Type string `json:"type" binding:"required,oneof=profile journal"`
+	// ID is the user ID of a profile or the journal ID of a journal entry
+	ID      string `json:"id" binding:"required,max=100"`
+	Reason  string `json:"reason" binding:"required,oneof=spam harassment hate sexual violence impersonation other"`
+	Details string `json:"details" binding:"max=2000"`
+}
+
+// ReportResponse acknowledges a report
+type ReportResponse struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// ReportFilter selects reports. Empty fields match every report.
+type ReportFilter struct {
+	Status     string
+	TargetType string
+	TargetID   string
+	Limit      int
+}
+
+// Resolution closes the open reports of a piece of content
+type Resolution struct {
+	Status     string
+	Action     string
+	ResolvedBy string
+	ResolvedAt time.Time
+}
+
+// Hidden is a profile or journal entry hidden from everyone but its owner and admins
+type Hidden struct {
+	TargetType string `bson:"target_type" json:"targetType"`
+	TargetID   string `bson:"target_id" json:"targetID"`
+	OwnerID    string `bson:"owner_id" json:"ownerID"`
+	// Automatic is set when the content was hidden for reaching the report threshold, awaiting review
+	Automatic bool `bson:"automatic" json:"automatic"`
+	// Unpublished is set when hiding moved a public journal entry to private, so it is made public again
+	// when the hide is lifted
+	Unpublished bool `bson:"unpublished,omitempty" json:"unpublished,omitempty"`
+	// HiddenBy is the admin who hid the content, empty when it was hidden automatically
+	HiddenBy string    `bson:"hidden_by,omitempty" json:"hiddenBy,omitempty"`
+	Note     string    `bson:"note,omitempty" json:"note,omitempty"`
+	HiddenAt time.Time `bson:"hidden_at" json:"hiddenAt"`
+}
+
+// Case is a piece of content in the moderation queue, with its open reports
+type Case struct {
+	TargetType string `json:"targetType"`
+	TargetID   string `json:"targetID"`
+	OwnerID    string `json:"ownerID"`
+	// Reports counts the open reports, each from a different reporter
+	Reports int `json:"reports"`
+	// Reasons counts the open reports by reason
+	Reasons         map[string]int `json:"reasons"`
+	Hidden          bool           `json:"hidden"`
+	FirstReportedAt time.Time      `json:"firstReportedAt"`
+	LastReportedAt  time.Time      `json:"lastReportedAt"`
+}
+
+// ActionRequest represents the request body for acting on reported content
+type ActionRequest struct {
+	// Action is hide, warn, suspend or dismiss
+	Action string `json:"action" binding:"required,oneof=hide warn suspend dismiss"`
+	// Message is emailed to the owner with a warning, and kept as the note of a hide
+	Message string `json:"message" binding:"max=2000"`
+}
+
+// ActionResponse tells what an action did
+type ActionResponse struct {
+	Action string `json:"action"`
+	// Resolved counts the open reports the action closed
+	Resolved int64 `json:"resolved"`
+}
@@ -0,0 +1,615 @@
+// Package moderation lets anyone flag public profiles and journal entries that break the site's rules, and
+// admins act on them. Reports wait in a queue grouping them by the content they are about, where an admin
+// hides the content, warns its owner by email, suspends the owner's account or dismisses the reports.
+//
+// Hidden content is only shown to its owner and admins. A hidden profile hides the documents read by the
+// owner's user ID, such as the profile, CV sections and journal list, while a hidden journal entry is made
+// private and cannot be made public again until the hide is lifted. Content reaching the configured number
+// of open reports, each from a different reporter, is hidden automatically until an admin reviews it;
+// dismissing its reports lifts the hide. Reports by and about a user are removed with their account.
+package moderation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/email"
+	"profile-api/journal"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+
+	// maxQueueReports is the most open reports the queue is built from
+	maxQueueReports = 10000
+
+	warningEmail = "moderation_warning"
+	// automaticActor changes the status of journal entries hidden for reaching the report threshold
+	automaticActor = "moderation"
+)
+
+// targetTypes are the types of content that can be reported
+var targetTypes = []string{TargetProfile, TargetJournal}
+
+// statuses are the statuses of reports
+var statuses = []string{StatusOpen, StatusResolved, StatusDismissed}
+
+var repo Repository
+var users auth.Repository
+var journals journal.Repository
+var settings config.ModerationConfig
+
+// Configure sets where reports are stored and where reported content is looked up, hides the journal
+// entries and profiles hidden by moderation, and starts removing the reports of users who delete their account
+func Configure(r Repository, u auth.Repository, j journal.Repository, cfg config.ModerationConfig) {
+	repo = r
+	users = u
+	journals = j
+	settings = cfg
+	journal.SetHiddenCheck(journalHidden)
+	journal.SetProfileHiddenCheck(ProfileHidden)
+	audit.Subscribe(removeReports)
+}
+
+// removeReports removes the reports by and about a deleted user and the hides of their content
+func removeReports(ctx context.Context, entry audit.Entry) {
+	if entry.Resource != "user" || entry.Action != audit.ActionDelete {
+		return
+	}
+	if err := repo.DeleteUser(ctx, entry.ResourceID); err != nil {
+		slog.ErrorContext(ctx, "Could not remove the reports of a deleted user", "user_id", entry.ResourceID, "error", err)
+	}
+}
+
+// ProfileHidden reports whether a moderator hid the user's profile. It is the one check of every surface
+// serving the user's profile data: Restrict for the routes naming the user in their path, and the public
+// feed, search, related entries, widgets, GraphQL, gRPC, ActivityPub and journal digests for the rest.
+func ProfileHidden(ctx context.Context, userID string) (bool, error) {
+	_, err := repo.GetHidden(ctx, TargetProfile, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// journalHidden reports whether the journal entry is hidden
+func journalHidden(ctx context.Context, journalID string) (bool, error) {
+	_, err := repo.GetHidden(ctx, TargetJournal, journalID)
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Restrict responds 404 to reads of the documents of a user, named by the userid path parameter, whose
+// profile was hidden, unless the requester is the user or an admin. It authenticates the requester itself,
+// so it may run before the routes' own authentication.
+func Restrict() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("userid")
+		if userID == "" || (c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead) {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := utils.DBContext(c)
+		defer cancel()
+		hidden, err := ProfileHidden(ctx, userID)
+		if err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not check moderation"))
+			return
+		}
+		if !hidden {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Cookie")
+		if token, err := c.Cookie("token"); err == nil {
+			if user, err := auth.Authenticate(ctx, users, token); err == nil && (user.ID == userID || user.Admin) {
+				c.Next()
+				return
+			}
+		}
+		apierror.Abort(c, apierror.NotFound("User not found"))
+	}
+}
+
+// reporterKey identifies who is reporting: the signed in user, or a hash of the visitor's address
+func reporterKey(c *gin.Context) string {
+	if userID := c.GetString("userID"); userID != "" {
+		return "user:" + userID
+	}
+	sum := sha256.Sum256([]byte(c.ClientIP()))
+	return "ip:" + hex.EncodeToString(sum[:8])
+}
+
+// reportedOwner returns the owner of public content, or an error to respond with when there is no such
+// content anyone could have seen
+func reportedOwner(ctx context.Context, targetType, targetID string) (string, error) {
+	switch targetType {
+	case TargetProfile:
+		user, err := users.FindByID(ctx, targetID)
+		if err != nil {
+			return "", apierror.Wrap(err, "Content not found")
+		}
+		if user.Disabled {
+			return "", apierror.NotFound("Content not found")
+		}
+		return user.ID, nil
+	case TargetJournal:
+		entry, err := journals.Get(ctx, targetID)
+		if err != nil {
+			return "", apierror.Wrap(err, "Content not found")
+		}
+		if entry.Status != journal.StatusPublic {
+			return "", apierror.NotFound("Content not found")
+		}
+		return entry.UserID, nil
+	}
+	return "", apierror.BadRequest("type must be profile or journal")
+}
+
+// owner returns the owner of content, public or not
+func owner(ctx context.Context, targetType, targetID string) (string, error) {
+	switch targetType {
+	case TargetProfile:
+		user, err := users.FindByID(ctx, targetID)
+		if err != nil {
+			return "", apierror.Wrap(err, "Content not found")
+		}
+		return user.ID, nil
+	case TargetJournal:
+		entry, err := journals.Get(ctx, targetID)
+		if err != nil {
+			return "", apierror.Wrap(err, "Content not found")
+		}
+		return entry.UserID, nil
+	}
+	return "", apierror.NotFound("Content not found")
+}
+
+// hide hides the content, making a public journal entry private. Content hidden by no one was hidden for
+// reaching the report threshold.
+func hide(ctx context.Context, targetType, targetID, ownerID, hiddenBy, note string) error {
+	hidden := Hidden{
+		TargetType: targetType,
+		TargetID:   targetID,
+		OwnerID:    ownerID,
+		Automatic:  hiddenBy == "",
+		HiddenBy:   hiddenBy,
+		Note:       note,
+		HiddenAt:   time.Now(),
+	}
+	existing, err := repo.GetHidden(ctx, targetType, targetID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	hidden.Unpublished = existing.Unpublished
+
+	var entry journal.JournalEntry
+	if targetType == TargetJournal {
+		if entry, err = journals.Get(ctx, targetID); err != nil {
+			return err
+		}
+		hidden.Unpublished = hidden.Unpublished || entry.Status == journal.StatusPublic
+	}
+	// The hide is recorded first, so the owner cannot make the entry public again in between
+	if err := repo.Hide(ctx, hidden); err != nil {
+		return err
+	}
+	// Recorded for the modules serving the owner's data from copies, such as search, to drop it
+	audit.Record(ctx, audit.ActionCreate, "hide", ownerID, targetID, nil, hidden)
+	if entry.Status == journal.StatusPublic {
+		changedBy := hiddenBy
+		if changedBy == "" {
+			changedBy = automaticActor
+		}
+		return journal.SetStatus(ctx, targetID, ownerID, changedBy, journal.StatusPrivate)
+	}
+	return nil
+}
+
+// lift lifts the hide of content, making a journal entry made private by the hide public again
+func lift(ctx context.Context, hidden Hidden, by string) error {
+	if err := repo.Unhide(ctx, hidden.TargetType, hidden.TargetID); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionDelete, "hide", hidden.OwnerID, hidden.TargetID, hidden, nil)
+	if hidden.TargetType != TargetJournal || !hidden.Unpublished {
+		return nil
+	}
+	entry, err := journals.Get(ctx, hidden.TargetID)
+	if err != nil {
+		return err
+	}
+	// Entries their owner changed since they were hidden are left as they are
+	if entry.Status != journal.StatusPrivate {
+		return nil
+	}
+	return journal.SetStatus(ctx, hidden.TargetID, hidden.OwnerID, by, journal.StatusPublic)
+}
+
+// autoHide hides the reported content once it reaches the report threshold
+func autoHide(ctx context.Context, report Report) {
+	if settings.AutoHideReports <= 0 {
+		return
+	}
+	n, err := repo.CountOpen(ctx, report.TargetType, report.TargetID)
+	if err == nil && n >= settings.AutoHideReports {
+		if _, err = repo.GetHidden(ctx, report.TargetType, report.TargetID); errors.Is(err, store.ErrNotFound) {
+			err = hide(ctx, report.TargetType, report.TargetID, report.OwnerID, "", fmt.Sprintf("Reported %d times", n))
+			if err == nil {
+				slog.WarnContext(ctx, "Reported content hidden", "type", report.TargetType, "id", report.TargetID, "reports", n)
+			}
+		}
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Could not hide reported content", "type", report.TargetType, "id", report.TargetID, "error", err)
+	}
+}
+
+// warningData is rendered into the warning email
+type warningData struct {
+	Name    string
+	Content string
+	Message string
+}
+
+// warn emails the owner of the content a warning
+func warn(ctx context.Context, targetType, targetID, ownerID, message string) error {
+	user, err := users.FindByID(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+	content := "profile"
+	if targetType == TargetJournal {
+		content = "journal entry"
+		if entry, err := journals.Get(ctx, targetID); err == nil && len(entry.Entries) > 0 {
+			content = fmt.Sprintf("journal entry %q", entry.Entries[len(entry.Entries)-1].Title)
+		}
+	}
+	msg, err := email.Render(warningEmail, warningData{Name: user.Name, Content: content, Message: message})
+	if err != nil {
+		return err
+	}
+	msg.To = user.Email
+	msg.UserID = user.ID
+	return email.Enqueue(ctx, msg)
+}
+
+// CreateReport flags content to the moderators
+//
+//	@Summary		Report content
+//	@Description	Flags a public profile, by its user ID, or a public journal entry to the moderators. Each signed in user, or each address for visitors when anonymous reports are allowed, may have one open report on a piece of content. Content reaching the configured number of open reports is hidden until an admin reviews it.
+//	@Tags			moderation
+//	@Accept			json
+//	@Produce		json
+//	@Param			report	body		ReportRequest	true	"What is reported and why"
+//	@Success		201		{object}	ReportResponse
+//	@Failure		400		{object}	apierror.Response	"Invalid request body, or the user's own content"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated, when anonymous reports are not allowed"
+//	@Failure		404		{object}	apierror.Response	"No such public content"
+//	@Failure		409		{object}	apierror.Response	"Already reported"
+//	@Failure		500		{object}	apierror.Response	"Could not store report"
+//	@Router			/report [post]
+func CreateReport(c *gin.Context) {
+	var req ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	reporterID := c.GetString("userID")
+	if reporterID == "" && !settings.AnonymousReports {
+		apierror.Abort(c, apierror.Unauthorized("Not authenticated"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	ownerID, err := reportedOwner(ctx, req.Type, req.ID)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	if ownerID == reporterID {
+		apierror.Abort(c, apierror.BadRequest("You cannot report your own content"))
+		return
+	}
+
+	report := Report{
+		ID:         utils.GenerateID(),
+		TargetType: req.Type,
+		TargetID:   req.ID,
+		OwnerID:    ownerID,
+		ReporterID: reporterID,
+		Reporter:   reporterKey(c),
+		Reason:     req.Reason,
+		Details:    req.Details,
+		Status:     StatusOpen,
+		CreatedAt:  time.Now(),
+	}
+	if err := repo.CreateReport(ctx, report); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			apierror.Abort(c, apierror.Conflict("You already reported this content"))
+			return
+		}
+		apierror.Abort(c, apierror.Wrap(err, "Could not store report"))
+		return
+	}
+	slog.InfoContext(ctx, "Content reported", "type", report.TargetType, "id", report.TargetID, "reason", report.Reason)
+	autoHide(ctx, report)
+
+	c.JSON(http.StatusCreated, ReportResponse{ID: report.ID, Message: "Report received"})
+}
+
+// GetQueue returns the content with open reports
+//
+//	@Summary		Get the moderation queue
+//	@Description	Returns the content with open reports, the most reported first and then the longest waiting, with the number of reports for each reason and whether the content is hidden. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			type	query		string	false	"Type of content"	Enums(profile, journal)
+//	@Param			limit	query		int		false	"Maximum number of items to return (default 100, max 1000)"
+//	@Success		200		{array}		Case
+//	@Failure		400		{object}	apierror.Response	"Invalid type"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve the moderation queue"
+//	@Router			/admin/moderation/queue [get]
+func GetQueue(c *gin.Context) {
+	targetType := c.Query("type")
+	if targetType != "" && !slices.Contains(targetTypes, targetType) {
+		apierror.Abort(c, apierror.BadRequest("type must be profile or journal"))
+		return
+	}
+	limit := listLimit(c)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	reports, err := repo.ListReports(ctx, ReportFilter{Status: StatusOpen, TargetType: targetType, Limit: maxQueueReports})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve the moderation queue"))
+		return
+	}
+	hidden, err := repo.ListHidden(ctx)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve the moderation queue"))
+		return
+	}
+
+	queue := []Case{}
+	index := map[[2]string]int{}
+	for _, r := range reports {
+		key := [2]string{r.TargetType, r.TargetID}
+		i, ok := index[key]
+		if !ok {
+			i = len(queue)
+			index[key] = i
+			queue = append(queue, Case{
+				TargetType:      r.TargetType,
+				TargetID:        r.TargetID,
+				OwnerID:         r.OwnerID,
+				Reasons:         map[string]int{},
+				Hidden:          slices.ContainsFunc(hidden, func(h Hidden) bool { return h.TargetType == r.TargetType && h.TargetID == r.TargetID }),
+				FirstReportedAt: r.CreatedAt,
+			})
+		}
+		queue[i].Reports++
+		queue[i].Reasons[r.Reason]++
+		queue[i].LastReportedAt = r.CreatedAt
+	}
+	slices.SortStableFunc(queue, func(a, b Case) int {
+		if a.Reports != b.Reports {
+			return b.Reports - a.Reports
+		}
+		return a.FirstReportedAt.Compare(b.FirstReportedAt)
+	})
+
+	c.JSON(http.StatusOK, queue[:min(limit, len(queue))])
+}
+
+// ListReports lists reports
+//
+//	@Summary		List reports
+//	@Description	Lists reports, oldest first, optionally only those with a status or about a piece of content. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			status		query		string	false	"Status of the reports"	Enums(open, resolved, dismissed)
+//	@Param			type		query		string	false	"Type of content"		Enums(profile, journal)
+//	@Param			targetid	query		string	false	"User ID of a profile or journal ID of a journal entry"
+//	@Param			limit		query		int		false	"Maximum number of reports to return (default 100, max 1000)"
+//	@Success		200			{array}		Report
+//	@Failure		400			{object}	apierror.Response	"Invalid status or type"
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		403			{object}	apierror.Response	"Admin access required"
+//	@Failure		500			{object}	apierror.Response	"Could not retrieve reports"
+//	@Router			/admin/moderation/reports [get]
+func ListReports(c *gin.Context) {
+	filter := ReportFilter{Status: c.Query("status"), TargetType: c.Query("type"), TargetID: c.Query("targetid"), Limit: listLimit(c)}
+	if filter.Status != "" && !slices.Contains(statuses, filter.Status) {
+		apierror.Abort(c, apierror.BadRequest("status must be open, resolved or dismissed"))
+		return
+	}
+	if filter.TargetType != "" && !slices.Contains(targetTypes, filter.TargetType) {
+		apierror.Abort(c, apierror.BadRequest("type must be profile or journal"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	reports, err := repo.ListReports(ctx, filter)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve reports"))
+		return
+	}
+	if reports == nil {
+		reports = []Report{}
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// TakeAction acts on reported content and closes its open reports
+//
+//	@Summary		Act on reported content
+//	@Description	Hides the content, emails its owner a warning with the message, suspends the owner's account, or dismisses the reports, lifting a hide made for reaching the report threshold. The content's open reports are closed, dismissed for dismiss and resolved otherwise. Content may be acted on without being reported. Requires the admin role.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			type		path		string			true	"Type of content"	Enums(profile, journal)
+//	@Param			targetid	path		string			true	"User ID of a profile or journal ID of a journal entry"
+//	@Param			action		body		ActionRequest	true	"Action to take"
+//	@Success		200			{object}	ActionResponse
+//	@Failure		400			{object}	apierror.Response	"Invalid request body, or suspending the admin's own account"
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		403			{object}	apierror.Response	"Admin access required"
+//	@Failure		404			{object}	apierror.Response	"Content not found"
+//	@Failure		500			{object}	apierror.Response	"Could not act on the content"
+//	@Router			/admin/moderation/targets/{type}/{targetid}/actions [post]
+func TakeAction(c *gin.Context) {
+	targetType, targetID := c.Param("type"), c.Param("targetid")
+	adminID := c.GetString("userID")
+	var req ActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	ownerID, err := owner(ctx, targetType, targetID)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	status := StatusResolved
+	switch req.Action {
+	case ActionHide:
+		err = hide(ctx, targetType, targetID, ownerID, adminID, req.Message)
+	case ActionWarn:
+		err = warn(ctx, targetType, targetID, ownerID, req.Message)
+	case ActionSuspend:
+		if ownerID == adminID {
+			apierror.Abort(c, apierror.BadRequest("Admins cannot suspend their own account"))
+			return
+		}
+		err = users.SetDisabled(ctx, ownerID, true)
+	case ActionDismiss:
+		status = StatusDismissed
+		var hidden Hidden
+		hidden, err = repo.GetHidden(ctx, targetType, targetID)
+		if err == nil && hidden.Automatic {
+			err = lift(ctx, hidden, adminID)
+		} else if errors.Is(err, store.ErrNotFound) {
+			err = nil
+		}
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not act on the content"))
+		return
+	}
+
+	resolved, err := repo.Resolve(ctx, targetType, targetID, Resolution{Status: status, Action: req.Action, ResolvedBy: adminID, ResolvedAt: time.Now()})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not close the reports"))
+		return
+	}
+	slog.InfoContext(ctx, "Moderation action taken", "action", req.Action, "type", targetType, "id", targetID, "owner_id", ownerID, "admin_id", adminID)
+
+	c.JSON(http.StatusOK, ActionResponse{Action: req.Action, Resolved: resolved})
+}
+
+// ListHidden lists the hidden content
+//
+//	@Summary		List hidden content
+//	@Description	Lists the profiles and journal entries hidden by admins or for reaching the report threshold, most recently hidden first. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{array}		Hidden
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		403	{object}	apierror.Response	"Admin access required"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve hidden content"
+//	@Router			/admin/moderation/hidden [get]
+func ListHidden(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	hidden, err := repo.ListHidden(ctx)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve hidden content"))
+		return
+	}
+	if hidden == nil {
+		hidden = []Hidden{}
+	}
+	c.JSON(http.StatusOK, hidden)
+}
+
+// Unhide lifts the hide of content
+//
+//	@Summary		Lift a hide
+//	@Description	Shows hidden content again. A journal entry made private by the hide is made public again, unless its owner changed its status since. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			type		path		string	true	"Type of content"	Enums(profile, journal)
+//	@Param			targetid	path		string	true	"User ID of a profile or journal ID of a journal entry"
+//	@Success		200			{string}	string				"Content shown again"
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		403			{object}	apierror.Response	"Admin access required"
+//	@Failure		404			{object}	apierror.Response	"Content not hidden"
+//	@Failure		500			{object}	apierror.Response	"Could not lift the hide"
+//	@Router			/admin/moderation/hidden/{type}/{targetid} [delete]
+func Unhide(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	hidden, err := repo.GetHidden(ctx, c.Param("type"), c.Param("targetid"))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Content not hidden"))
+		return
+	}
+	if err := lift(ctx, hidden, c.GetString("userID")); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not lift the hide"))
+		return
+	}
+	slog.InfoContext(ctx, "Hide lifted", "type", hidden.TargetType, "id", hidden.TargetID, "admin_id", c.GetString("userID"))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Content shown again"})
+}
+
+// listLimit reads the number of items to list from the query
+func listLimit(c *gin.Context) int {
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		return min(l, maxListLimit)
+	}
+	return defaultListLimit
+}
+
+// InitializeRoutes registers the report route, open to visitors. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup, users auth.Repository) {
+	router.POST("", auth.AuthMiddleware(users, false), CreateReport)
+}
+
+// InitializeAdminRoutes registers the moderation queue routes. The router must only admit admins.
+func InitializeAdminRoutes(router *gin.RouterGroup) {
+	router.GET("/queue", GetQueue)
+	router.GET("/reports", ListReports)
+	router.POST("/targets/:type/:targetid/actions", TakeAction)
+	router.GET("/hidden", ListHidden)
+	router.DELETE("/hidden/:type/:targetid", Unhide)
+}
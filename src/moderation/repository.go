@@ -0,0 +1,26 @@
+package moderation
+
+import "context"
+
+// Repository stores reports and the content hidden by moderation
+type Repository interface {
+	// CreateReport stores a new report, or returns store.ErrConflict when the reporter already has an open
+	// report on the content
+	CreateReport(ctx context.Context, report Report) error
+	// ListReports returns the reports matching the filter, oldest first
+	ListReports(ctx context.Context, filter ReportFilter) ([]Report, error)
+	// CountOpen returns how many open reports the content has
+	CountOpen(ctx context.Context, targetType, targetID string) (int, error)
+	// Resolve closes the content's open reports, returning how many it closed
+	Resolve(ctx context.Context, targetType, targetID string, resolution Resolution) (int64, error)
+	// Hide records the content as hidden, replacing an earlier hide
+	Hide(ctx context.Context, hidden Hidden) error
+	// GetHidden returns the hide of the content, or store.ErrNotFound when it is not hidden
+	GetHidden(ctx context.Context, targetType, targetID string) (Hidden, error)
+	// ListHidden returns the hidden content, most recently hidden first
+	ListHidden(ctx context.Context) ([]Hidden, error)
+	// Unhide lifts the hide of the content, or returns store.ErrNotFound
+	Unhide(ctx context.Context, targetType, targetID string) error
+	// DeleteUser removes the reports by and about the user and the hides of their content
+	DeleteUser(ctx context.Context, userID string) error
+}
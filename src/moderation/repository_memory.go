@@ -0,0 +1,121 @@
+package moderation
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps reports and hides in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu      sync.Mutex
+	reports []Report
+	hidden  []Hidden
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) CreateReport(ctx context.Context, report Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.ContainsFunc(r.reports, func(o Report) bool {
+		return o.Status == StatusOpen && o.TargetType == report.TargetType && o.TargetID == report.TargetID && o.Reporter == report.Reporter
+	}) {
+		return store.ErrConflict
+	}
+	r.reports = append(r.reports, report)
+	return nil
+}
+
+func (r *MemoryRepository) ListReports(ctx context.Context, filter ReportFilter) ([]Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var reports []Report
+	for _, report := range r.reports {
+		if (filter.Status == "" || report.Status == filter.Status) &&
+			(filter.TargetType == "" || report.TargetType == filter.TargetType) &&
+			(filter.TargetID == "" || report.TargetID == filter.TargetID) {
+			reports = append(reports, report)
+		}
+		if filter.Limit > 0 && len(reports) == filter.Limit {
+			break
+		}
+	}
+	return reports, nil
+}
+
+func (r *MemoryRepository) CountOpen(ctx context.Context, targetType, targetID string) (int, error) {
+	reports, err := r.ListReports(ctx, ReportFilter{Status: StatusOpen, TargetType: targetType, TargetID: targetID})
+	return len(reports), err
+}
+
+func (r *MemoryRepository) Resolve(ctx context.Context, targetType, targetID string, resolution Resolution) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for i, report := range r.reports {
+		if report.Status == StatusOpen && report.TargetType == targetType && report.TargetID == targetID {
+			resolvedAt := resolution.ResolvedAt
+			r.reports[i].Status = resolution.Status
+			r.reports[i].Action = resolution.Action
+			r.reports[i].ResolvedBy = resolution.ResolvedBy
+			r.reports[i].ResolvedAt = &resolvedAt
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *MemoryRepository) Hide(ctx context.Context, hidden Hidden) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hidden = slices.DeleteFunc(r.hidden, func(h Hidden) bool {
+		return h.TargetType == hidden.TargetType && h.TargetID == hidden.TargetID
+	})
+	r.hidden = append(r.hidden, hidden)
+	return nil
+}
+
+func (r *MemoryRepository) GetHidden(ctx context.Context, targetType, targetID string) (Hidden, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.hidden, func(h Hidden) bool { return h.TargetType == targetType && h.TargetID == targetID })
+	if i < 0 {
+		return Hidden{}, store.ErrNotFound
+	}
+	return r.hidden[i], nil
+}
+
+func (r *MemoryRepository) ListHidden(ctx context.Context) ([]Hidden, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hidden := slices.Clone(r.hidden)
+	slices.SortStableFunc(hidden, func(a, b Hidden) int { return b.HiddenAt.Compare(a.HiddenAt) })
+	return hidden, nil
+}
+
+func (r *MemoryRepository) Unhide(ctx context.Context, targetType, targetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(r.hidden)
+	r.hidden = slices.DeleteFunc(r.hidden, func(h Hidden) bool { return h.TargetType == targetType && h.TargetID == targetID })
+	if len(r.hidden) == n {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MemoryRepository) DeleteUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = slices.DeleteFunc(r.reports, func(report Report) bool {
+		return report.OwnerID == userID || report.ReporterID == userID
+	})
+	r.hidden = slices.DeleteFunc(r.hidden, func(h Hidden) bool { return h.OwnerID == userID })
+	return nil
+}
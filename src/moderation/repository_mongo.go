@@ -0,0 +1,120 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores reports in the reports collection, whose partial unique index keeps a reporter to
+// one open report on each piece of content, and hides in the hidden_content collection
+type MongoRepository struct {
+	reports *mongo.Collection
+	hidden  *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{reports: db.Collection("reports"), hidden: db.Collection("hidden_content")}
+}
+
+func (r *MongoRepository) CreateReport(ctx context.Context, report Report) error {
+	_, err := r.reports.InsertOne(ctx, report)
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	}
+	return err
+}
+
+func (r *MongoRepository) ListReports(ctx context.Context, filter ReportFilter) ([]Report, error) {
+	query := bson.M{}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.TargetType != "" {
+		query["target_type"] = filter.TargetType
+	}
+	if filter.TargetID != "" {
+		query["target_id"] = filter.TargetID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+	cursor, err := r.reports.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	var reports []Report
+	err = cursor.All(ctx, &reports)
+	return reports, err
+}
+
+func (r *MongoRepository) CountOpen(ctx context.Context, targetType, targetID string) (int, error) {
+	n, err := r.reports.CountDocuments(ctx, bson.M{"status": StatusOpen, "target_type": targetType, "target_id": targetID})
+	return int(n), err
+}
+
+func (r *MongoRepository) Resolve(ctx context.Context, targetType, targetID string, resolution Resolution) (int64, error) {
+	result, err := r.reports.UpdateMany(ctx,
+		bson.M{"status": StatusOpen, "target_type": targetType, "target_id": targetID},
+		bson.M{"$set": bson.M{
+			"status":      resolution.Status,
+			"action":      resolution.Action,
+			"resolved_by": resolution.ResolvedBy,
+			"resolved_at": resolution.ResolvedAt,
+		}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *MongoRepository) Hide(ctx context.Context, hidden Hidden) error {
+	_, err := r.hidden.ReplaceOne(ctx, bson.M{"target_type": hidden.TargetType, "target_id": hidden.TargetID}, hidden,
+		options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) GetHidden(ctx context.Context, targetType, targetID string) (Hidden, error) {
+	var hidden Hidden
+	err := r.hidden.FindOne(ctx, bson.M{"target_type": targetType, "target_id": targetID}).Decode(&hidden)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Hidden{}, store.ErrNotFound
+	}
+	return hidden, err
+}
+
+func (r *MongoRepository) ListHidden(ctx context.Context) ([]Hidden, error) {
+	cursor, err := r.hidden.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "hidden_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	var hidden []Hidden
+	err = cursor.All(ctx, &hidden)
+	return hidden, err
+}
+
+func (r *MongoRepository) Unhide(ctx context.Context, targetType, targetID string) error {
+	result, err := r.hidden.DeleteOne(ctx, bson.M{"target_type": targetType, "target_id": targetID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) DeleteUser(ctx context.Context, userID string) error {
+	if _, err := r.reports.DeleteMany(ctx, bson.M{"$or": bson.A{bson.M{"owner_id": userID}, bson.M{"reporter_id": userID}}}); err != nil {
+		return err
+	}
+	_, err := r.hidden.DeleteMany(ctx, bson.M{"owner_id": userID})
+	return err
+}
@@ -0,0 +1,134 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	reportColumns = "id, target_type, target_id, owner_id, reporter_id, reporter, reason, details, status, action, resolved_by, resolved_at, created_at"
+	hiddenColumns = "target_type, target_id, owner_id, automatic, unpublished, hidden_by, note, hidden_at"
+)
+
+// PostgresRepository stores reports in the reports table, whose partial unique index keeps a reporter to
+// one open report on each piece of content, and hides in the hidden_content table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) CreateReport(ctx context.Context, rp Report) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO reports ("+reportColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)",
+		rp.ID, rp.TargetType, rp.TargetID, rp.OwnerID, rp.ReporterID, rp.Reporter, rp.Reason, rp.Details, rp.Status,
+		rp.Action, rp.ResolvedBy, rp.ResolvedAt, rp.CreatedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) ListReports(ctx context.Context, filter ReportFilter) ([]Report, error) {
+	var conds []string
+	var args []any
+	for column, value := range map[string]string{"status": filter.Status, "target_type": filter.TargetType, "target_id": filter.TargetID} {
+		if value != "" {
+			args = append(args, value)
+			conds = append(conds, fmt.Sprintf("%s = $%d", column, len(args)))
+		}
+	}
+	query := "SELECT " + reportColumns + " FROM reports"
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY created_at"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanReport)
+}
+
+func (r *PostgresRepository) CountOpen(ctx context.Context, targetType, targetID string) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx, "SELECT count(*) FROM reports WHERE status = $1 AND target_type = $2 AND target_id = $3",
+		StatusOpen, targetType, targetID).Scan(&n)
+	return n, err
+}
+
+func (r *PostgresRepository) Resolve(ctx context.Context, targetType, targetID string, res Resolution) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `UPDATE reports SET status = $4, action = $5, resolved_by = $6, resolved_at = $7
+		WHERE status = $1 AND target_type = $2 AND target_id = $3`,
+		StatusOpen, targetType, targetID, res.Status, res.Action, res.ResolvedBy, res.ResolvedAt)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *PostgresRepository) Hide(ctx context.Context, h Hidden) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO hidden_content ("+hiddenColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (target_type, target_id) DO UPDATE SET owner_id = $3, automatic = $4, unpublished = $5,
+		hidden_by = $6, note = $7, hidden_at = $8`,
+		h.TargetType, h.TargetID, h.OwnerID, h.Automatic, h.Unpublished, h.HiddenBy, h.Note, h.HiddenAt)
+	return err
+}
+
+func (r *PostgresRepository) GetHidden(ctx context.Context, targetType, targetID string) (Hidden, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+hiddenColumns+" FROM hidden_content WHERE target_type = $1 AND target_id = $2", targetType, targetID)
+	if err != nil {
+		return Hidden{}, err
+	}
+	hidden, err := pgx.CollectExactlyOneRow(rows, scanHidden)
+	return hidden, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) ListHidden(ctx context.Context) ([]Hidden, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+hiddenColumns+" FROM hidden_content ORDER BY hidden_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanHidden)
+}
+
+func (r *PostgresRepository) Unhide(ctx context.Context, targetType, targetID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM hidden_content WHERE target_type = $1 AND target_id = $2", targetType, targetID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) DeleteUser(ctx context.Context, userID string) error {
+	if _, err := r.pool.Exec(ctx, "DELETE FROM reports WHERE owner_id = $1 OR reporter_id = $1", userID); err != nil {
+		return err
+	}
+	_, err := r.pool.Exec(ctx, "DELETE FROM hidden_content WHERE owner_id = $1", userID)
+	return err
+}
+
+func scanReport(row pgx.CollectableRow) (Report, error) {
+	var rp Report
+	err := row.Scan(&rp.ID, &rp.TargetType, &rp.TargetID, &rp.OwnerID, &rp.ReporterID, &rp.Reporter, &rp.Reason, &rp.Details,
+		&rp.Status, &rp.Action, &rp.ResolvedBy, &rp.ResolvedAt, &rp.CreatedAt)
+	return rp, err
+}
+
+func scanHidden(row pgx.CollectableRow) (Hidden, error) {
+	var h Hidden
+	err := row.Scan(&h.TargetType, &h.TargetID, &h.OwnerID, &h.Automatic, &h.Unpublished, &h.HiddenBy, &h.Note, &h.HiddenAt)
+	return h, err
+}
@@ -0,0 +1,53 @@
+package moderation
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) CreateReport(ctx context.Context, report Report) error {
+	return r.repos.For(ctx).CreateReport(ctx, report)
+}
+
+func (r *TenantRepository) ListReports(ctx context.Context, filter ReportFilter) ([]Report, error) {
+	return r.repos.For(ctx).ListReports(ctx, filter)
+}
+
+func (r *TenantRepository) CountOpen(ctx context.Context, targetType, targetID string) (int, error) {
+	return r.repos.For(ctx).CountOpen(ctx, targetType, targetID)
+}
+
+func (r *TenantRepository) Resolve(ctx context.Context, targetType, targetID string, resolution Resolution) (int64, error) {
+	return r.repos.For(ctx).Resolve(ctx, targetType, targetID, resolution)
+}
+
+func (r *TenantRepository) Hide(ctx context.Context, hidden Hidden) error {
+	return r.repos.For(ctx).Hide(ctx, hidden)
+}
+
+func (r *TenantRepository) GetHidden(ctx context.Context, targetType, targetID string) (Hidden, error) {
+	return r.repos.For(ctx).GetHidden(ctx, targetType, targetID)
+}
+
+func (r *TenantRepository) ListHidden(ctx context.Context) ([]Hidden, error) {
+	return r.repos.For(ctx).ListHidden(ctx)
+}
+
+func (r *TenantRepository) Unhide(ctx context.Context, targetType, targetID string) error {
+	return r.repos.For(ctx).Unhide(ctx, targetType, targetID)
+}
+
+func (r *TenantRepository) DeleteUser(ctx context.Context, userID string) error {
+	return r.repos.For(ctx).DeleteUser(ctx, userID)
+}
@@ -0,0 +1,318 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// migrations in version order. Released migrations must not be changed, only followed by new ones.
+var migrations = []migration{
+	{version: "0001_indexes", up: createIndexes(initialIndexes), down: dropIndexes(initialIndexes)},
+	{version: "0002_billing", up: createIndexes(billingIndexes), down: dropIndexes(billingIndexes)},
+	{version: "0003_uploads", up: createIndexes(uploadIndexes), down: dropIndexes(uploadIndexes)},
+	{version: "0004_change_claims", up: createIndexes(changeClaimIndexes), down: dropIndexes(changeClaimIndexes)},
+	{version: "0005_notifications", up: createIndexes(notificationIndexes), down: dropIndexes(notificationIndexes)},
+	{version: "0006_activity", up: createIndexes(activityIndexes), down: dropIndexes(activityIndexes)},
+	{version: "0007_organizations", up: createIndexes(organizationIndexes), down: dropIndexes(organizationIndexes)},
+	{version: "0008_recommendations", up: createIndexes(recommendationIndexes), down: dropIndexes(recommendationIndexes)},
+	{version: "0009_awards", up: createIndexes(awardIndexes), down: dropIndexes(awardIndexes)},
+	{version: "0010_languages", up: createIndexes(languageIndexes), down: dropIndexes(languageIndexes)},
+	{version: "0011_contact_requests", up: createIndexes(contactRequestIndexes), down: dropIndexes(contactRequestIndexes)},
+	{version: "0012_privacy", up: createIndexes(privacyIndexes), down: dropIndexes(privacyIndexes)},
+	{version: "0013_api_usage", up: createIndexes(apiUsageIndexes), down: dropIndexes(apiUsageIndexes)},
+	{version: "0014_changelog", up: createIndexes(changelogIndexes), down: dropIndexes(changelogIndexes)},
+	{version: "0015_domains", up: createIndexes(domainIndexes), down: dropIndexes(domainIndexes)},
+	{version: "0016_moderation", up: createIndexes(moderationIndexes), down: dropIndexes(moderationIndexes)},
+	{version: "0017_journal_files", up: createIndexes(journalFileIndexes), down: dropIndexes(journalFileIndexes)},
+	{version: "0018_integrations", up: createIndexes(integrationIndexes), down: dropIndexes(integrationIndexes)},
+	{version: "0019_api_keys", up: createIndexes(apiKeyIndexes), down: dropIndexes(apiKeyIndexes)},
+	{version: "0020_journal_files_journal_id", up: createIndexes(journalFileEntryIndexes), down: dropIndexes(journalFileEntryIndexes)},
+	{version: "0021_journal_settings", up: createIndexes(journalSettingsIndexes), down: dropIndexes(journalSettingsIndexes)},
+	{version: "0022_share_packages", up: createIndexes(shareIndexes), down: dropIndexes(shareIndexes)},
+	{version: "0023_scraping_detections", up: createIndexes(scrapingIndexes), down: dropIndexes(scrapingIndexes)},
+}
+
+// billingIndexes find the account of the Stripe customer a billing event is about
+var billingIndexes = map[string][]mongo.IndexModel{
+	"billing_accounts": {
+		{Keys: bson.D{{Key: "customer_id", Value: 1}}, Options: options.Index().SetName("billing_accounts_customer").SetUnique(true)},
+	},
+}
+
+// initialIndexes serve the lookups of every module, matching the Postgres schema's keys and indexes
+var initialIndexes = map[string][]mongo.IndexModel{
+	"users": {
+		{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetName("users_email").SetUnique(true)},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}, Options: options.Index().SetName("users_created_at")},
+	},
+	"profiles": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("profiles_user_id").SetUnique(true)},
+	},
+	"experience":     {userIndex("experience", "experience_id")},
+	"qualifications": {userIndex("qualifications", "qualification_id")},
+	"certificates":   {userIndex("certificates", "certificate_id")},
+	"skills":         {userIndex("skills", "skill_id")},
+	"journal": {
+		{Keys: bson.D{{Key: "journal_id", Value: 1}}, Options: options.Index().SetName("journal_journal_id").SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("journal_user_id")},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "updated_at", Value: 1}}, Options: options.Index().SetName("journal_status_updated_at")},
+	},
+	"subscriptions": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "email", Value: 1}}, Options: options.Index().SetName("subscriptions_user_email")},
+	},
+	"email_log": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "sent_at", Value: -1}}, Options: options.Index().SetName("email_log_user_sent")},
+	},
+	"webhooks": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("webhooks_user")},
+	},
+	"audit_log": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "time", Value: -1}}, Options: options.Index().SetName("audit_log_user_time")},
+		{Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "time", Value: -1}}, Options: options.Index().SetName("audit_log_actor_time")},
+	},
+}
+
+// uploadIndexes sum the sizes of a user's uploads and find those of a deleted resource
+var uploadIndexes = map[string][]mongo.IndexModel{
+	"uploads": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("uploads_user_id")},
+		{Keys: bson.D{{Key: "resource", Value: 1}, {Key: "resource_id", Value: 1}}, Options: options.Index().SetName("uploads_resource")},
+	},
+}
+
+// changeClaimIndexes expire the claims replicas make on the changes they forward to event listeners, once
+// every replica is long past the change
+var changeClaimIndexes = map[string][]mongo.IndexModel{
+	"change_claims": {
+		{Keys: bson.D{{Key: "claimed_at", Value: 1}}, Options: options.Index().SetName("change_claims_expiry").SetExpireAfterSeconds(24 * 60 * 60)},
+	},
+}
+
+// notificationIndexes list a user's notifications, find the certificates ending on a day and hold one set
+// of preferences per user
+var notificationIndexes = map[string][]mongo.IndexModel{
+	"notifications": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("notifications_user_created")},
+		{Keys: bson.D{{Key: "created_at", Value: 1}}, Options: options.Index().SetName("notifications_created")},
+	},
+	"notification_preferences": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("notification_preferences_user").SetUnique(true)},
+	},
+	"certificates": {
+		{Keys: bson.D{{Key: "end", Value: 1}}, Options: options.Index().SetName("certificates_end")},
+	},
+}
+
+// activityIndexes page through a user's feed and hold one activity per document
+var activityIndexes = map[string][]mongo.IndexModel{
+	"activity": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "kind", Value: 1}, {Key: "resource_id", Value: 1}}, Options: options.Index().SetName("activity_user_resource").SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "time", Value: -1}}, Options: options.Index().SetName("activity_user_time")},
+	},
+}
+
+// organizationIndexes list organizations by name and hold one membership per user and organization
+var organizationIndexes = map[string][]mongo.IndexModel{
+	"organizations": {
+		{Keys: bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("organizations_name")},
+	},
+	"organization_members": {
+		{Keys: bson.D{{Key: "organization_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetName("organization_members_organization_user").SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("organization_members_user")},
+	},
+}
+
+// recommendationIndexes list a user's recommendations and invitations and find invitations by their token
+var recommendationIndexes = map[string][]mongo.IndexModel{
+	"recommendations": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("recommendations_user_created")},
+		{Keys: bson.D{{Key: "author_id", Value: 1}}, Options: options.Index().SetName("recommendations_author").SetSparse(true)},
+	},
+	"recommendation_invitations": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("recommendation_invitations_user_created")},
+		{Keys: bson.D{{Key: "token", Value: 1}}, Options: options.Index().SetName("recommendation_invitations_token").SetUnique(true)},
+	},
+}
+
+// awardIndexes find a user's awards
+var awardIndexes = map[string][]mongo.IndexModel{
+	"awards": {userIndex("awards", "award_id")},
+}
+
+// languageIndexes find the languages a user speaks
+var languageIndexes = map[string][]mongo.IndexModel{
+	"languages": {userIndex("languages", "language_id")},
+}
+
+// contactRequestIndexes list a user's inbox, count the requests a sender sent them lately and find the spam
+// to purge
+var contactRequestIndexes = map[string][]mongo.IndexModel{
+	"contact_requests": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("contact_requests_user_created")},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "email", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("contact_requests_user_sender")},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "updated_at", Value: 1}}, Options: options.Index().SetName("contact_requests_status_updated")},
+	},
+}
+
+// privacyIndexes keep one block per pair of users and one settings document per user, find the blocks of a
+// deleted user, and keep each address once on an allowlist, found by the token of its link
+var privacyIndexes = map[string][]mongo.IndexModel{
+	"blocks": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "blocked_id", Value: 1}}, Options: options.Index().SetName("blocks_user_blocked").SetUnique(true)},
+		{Keys: bson.D{{Key: "blocked_id", Value: 1}}, Options: options.Index().SetName("blocks_blocked")},
+	},
+	"privacy_settings": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("privacy_settings_user").SetUnique(true)},
+	},
+	"profile_visitors": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "email", Value: 1}}, Options: options.Index().SetName("profile_visitors_user_email").SetUnique(true)},
+		{Keys: bson.D{{Key: "token", Value: 1}}, Options: options.Index().SetName("profile_visitors_token").SetUnique(true)},
+	},
+}
+
+// apiUsageIndexes list a user's daily counters, and aggregate and purge those of every user by day
+var apiUsageIndexes = map[string][]mongo.IndexModel{
+	"api_usage": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "day", Value: 1}}, Options: options.Index().SetName("api_usage_user_day")},
+		{Keys: bson.D{{Key: "day", Value: 1}}, Options: options.Index().SetName("api_usage_day")},
+	},
+}
+
+// changelogIndexes number each user's events uniquely, and list them in order
+var changelogIndexes = map[string][]mongo.IndexModel{
+	"changelog": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "seq", Value: 1}}, Options: options.Index().SetName("changelog_user_seq").SetUnique(true)},
+	},
+}
+
+// domainIndexes keep users from attaching a domain twice and two users from verifying the same one, and
+// find the verified domain serving a host
+var domainIndexes = map[string][]mongo.IndexModel{
+	"domains": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetName("domains_user_name").SetUnique(true)},
+		{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetName("domains_verified_name").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": "verified"})},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}, Options: options.Index().SetName("domains_status_created")},
+	},
+}
+
+// moderationIndexes keep a reporter to one open report on each piece of content, list the queue and find
+// the hides of content
+var moderationIndexes = map[string][]mongo.IndexModel{
+	"reports": {
+		{Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "reporter", Value: 1}},
+			Options: options.Index().SetName("reports_open_reporter").SetUnique(true).SetPartialFilterExpression(bson.M{"status": "open"})},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}, Options: options.Index().SetName("reports_status_created")},
+		{Keys: bson.D{{Key: "owner_id", Value: 1}}, Options: options.Index().SetName("reports_owner_id")},
+	},
+	"hidden_content": {
+		{Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}}, Options: options.Index().SetName("hidden_content_target").SetUnique(true)},
+		{Keys: bson.D{{Key: "owner_id", Value: 1}}, Options: options.Index().SetName("hidden_content_owner_id")},
+	},
+}
+
+// journalFileIndexes list the files tracked for journal entries in the order they were stored
+var journalFileIndexes = map[string][]mongo.IndexModel{
+	"journal_files": {
+		{Keys: bson.D{{Key: "created_at", Value: 1}}, Options: options.Index().SetName("journal_files_created_at")},
+	},
+}
+
+// integrationIndexes keep one mapping for each user of an integration and list the events it pushed, latest
+// first
+var integrationIndexes = map[string][]mongo.IndexModel{
+	"integration_mappings": {
+		{Keys: bson.D{{Key: "integration_id", Value: 1}, {Key: "external_id", Value: 1}}, Options: options.Index().SetName("integration_mappings_external").SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("integration_mappings_user_id")},
+	},
+	"integration_receipts": {
+		{Keys: bson.D{{Key: "integration_id", Value: 1}, {Key: "received_at", Value: -1}}, Options: options.Index().SetName("integration_receipts_integration_received")},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("integration_receipts_user_id")},
+	},
+}
+
+// apiKeyIndexes find the key presented with a request by its hash and list the keys of a user
+var apiKeyIndexes = map[string][]mongo.IndexModel{
+	"api_keys": {
+		{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetName("api_keys_hash").SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("api_keys_user_id")},
+	},
+}
+
+// journalFileEntryIndexes find the files tracked for a journal entry when serving its attachments
+var journalFileEntryIndexes = map[string][]mongo.IndexModel{
+	"journal_files": {
+		{Keys: bson.D{{Key: "journal_id", Value: 1}}, Options: options.Index().SetName("journal_files_journal_id")},
+	},
+}
+
+// journalSettingsIndexes keep one journal settings document per user
+var journalSettingsIndexes = map[string][]mongo.IndexModel{
+	"journal_settings": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("journal_settings_user").SetUnique(true)},
+	},
+}
+
+// shareIndexes find a share package by its link's token and list a user's packages and their views
+var shareIndexes = map[string][]mongo.IndexModel{
+	"share_packages": {
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetName("share_packages_token").SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("share_packages_user_created")},
+	},
+	"share_views": {
+		{Keys: bson.D{{Key: "package_id", Value: 1}, {Key: "viewed_at", Value: -1}}, Options: options.Index().SetName("share_views_package_viewed")},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("share_views_user")},
+	},
+}
+
+// scrapingIndexes list the latest scraping detections and purge the old ones
+var scrapingIndexes = map[string][]mongo.IndexModel{
+	"scraping_detections": {
+		{Keys: bson.D{{Key: "detected_at", Value: -1}}, Options: options.Index().SetName("scraping_detections_detected_at")},
+	},
+}
+
+// userIndex is the unique index of a CV section's items, which belong to a user
+func userIndex(collection, idField string) mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: idField, Value: 1}},
+		Options: options.Index().SetName(collection + "_user_item").SetUnique(true),
+	}
+}
+
+// createIndexes returns a migration creating the indexes, which does nothing for indexes that already exist
+func createIndexes(indexes map[string][]mongo.IndexModel) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		for collection, models := range indexes {
+			if _, err := db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// dropIndexes returns a migration dropping the indexes created by createIndexes
+func dropIndexes(indexes map[string][]mongo.IndexModel) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		for collection, models := range indexes {
+			for _, model := range models {
+				_, err := db.Collection(collection).Indexes().DropOne(ctx, *model.Options.Name)
+				if err != nil && !isIndexNotFound(err) {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// isIndexNotFound reports whether the error is for dropping an index that does not exist
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && (cmdErr.Code == 27 || cmdErr.Name == "IndexNotFound")
+}
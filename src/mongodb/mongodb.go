@@ -0,0 +1,156 @@
+// Package mongodb keeps the indexes and documents of the Mongo storage backend up to date as releases
+// change them, as the postgres package does for its schema.
+//
+// Migrations are Go functions applied in version order and recorded in the schema_migrations collection.
+// Mongo cannot run them in a transaction, so a migration interrupted part way is run again in full: each
+// must be safe to repeat, such as by creating indexes or rewriting only documents not yet rewritten.
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"profile-api/scheduler"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// migration changes the database. Down undoes Up and is nil for migrations that cannot be rolled back.
+type migration struct {
+	version string
+	up      func(ctx context.Context, db *mongo.Database) error
+	down    func(ctx context.Context, db *mongo.Database) error
+}
+
+// applied records a migration applied to the database
+type applied struct {
+	Version   string    `bson:"_id"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// lockName is the lease serialising instances migrating the database at the same time
+const lockName = "schema-migrations"
+
+// lockTTL is how long an instance may hold the lease, after which it is assumed to have crashed
+const lockTTL = 10 * time.Minute
+
+// Migrate applies every migration that has not been applied yet, in version order
+func Migrate(ctx context.Context, db *mongo.Database) error {
+	return withLock(ctx, db, func() error {
+		done, err := appliedVersions(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if _, ok := done[m.version]; ok {
+				continue
+			}
+			if err := m.up(ctx, db); err != nil {
+				return fmt.Errorf("error applying migration %s: %w", m.version, err)
+			}
+			_, err := db.Collection("schema_migrations").InsertOne(ctx, applied{Version: m.version, AppliedAt: time.Now()})
+			if err != nil {
+				return fmt.Errorf("error recording migration %s: %w", m.version, err)
+			}
+			slog.Info("Applied Mongo migration", "version", m.version)
+		}
+		return nil
+	})
+}
+
+// Rollback rolls back every applied migration numbered after the target, newest first. It stops at the
+// first migration that cannot be rolled back.
+func Rollback(ctx context.Context, db *mongo.Database, target string) error {
+	return withLock(ctx, db, func() error {
+		done, err := appliedVersions(ctx, db)
+		if err != nil {
+			return err
+		}
+		for i := len(migrations) - 1; i >= 0; i-- {
+			m := migrations[i]
+			if !(store.Migration{Version: m.version}).After(target) {
+				break
+			}
+			if _, ok := done[m.version]; !ok {
+				continue
+			}
+			if m.down == nil {
+				return fmt.Errorf("error rolling back migration %s: migration cannot be rolled back", m.version)
+			}
+			if err := m.down(ctx, db); err != nil {
+				return fmt.Errorf("error rolling back migration %s: %w", m.version, err)
+			}
+			if _, err := db.Collection("schema_migrations").DeleteOne(ctx, bson.M{"_id": m.version}); err != nil {
+				return fmt.Errorf("error recording rollback of migration %s: %w", m.version, err)
+			}
+			slog.Info("Rolled back Mongo migration", "version", m.version)
+		}
+		return nil
+	})
+}
+
+// Status lists every migration with when it was applied to the database
+func Status(ctx context.Context, db *mongo.Database) ([]store.Migration, error) {
+	done, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	status := make([]store.Migration, 0, len(migrations))
+	for _, m := range migrations {
+		s := store.Migration{Version: m.version, Reversible: m.down != nil}
+		if t, ok := done[m.version]; ok {
+			s.AppliedAt = &t
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
+
+// appliedVersions returns when each applied migration was applied
+func appliedVersions(ctx context.Context, db *mongo.Database) (map[string]time.Time, error) {
+	cursor, err := db.Collection("schema_migrations").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var records []applied
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	done := make(map[string]time.Time, len(records))
+	for _, r := range records {
+		done[r.Version] = r.AppliedAt
+	}
+	return done, nil
+}
+
+// withLock runs fn holding the migration lease, waiting for another instance migrating the database to finish
+func withLock(ctx context.Context, db *mongo.Database, fn func() error) error {
+	locker := scheduler.NewMongoLocker(db)
+	owner := utils.GenerateID()
+	for {
+		acquired, err := locker.Acquire(ctx, lockName, owner, lockTTL)
+		if err != nil {
+			return fmt.Errorf("error locking schema_migrations: %w", err)
+		}
+		if acquired {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return errors.New("timed out waiting for another instance to finish migrating")
+		case <-time.After(time.Second):
+		}
+	}
+	defer func() {
+		if err := locker.Release(context.WithoutCancel(ctx), lockName, owner); err != nil {
+			slog.Error("Could not release migration lock", "error", err)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,50 @@
+// Package netutil creates the HTTP clients requests to addresses chosen by users are made with, such as
+// webhook deliveries, ActivityPub servers and the images of imported posts, so they can't be used to reach
+// hosts on the server's own network.
+//
+// Addresses are checked once resolved, as they are dialed, so DNS names pointing at internal hosts and
+// redirects to them are refused too. Proxies set in the environment are not used, as the proxy rather than
+// the server would then resolve and dial the address.
+package netutil
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress is returned for requests to addresses that are not publicly routable
+var ErrPrivateAddress = errors.New("address is not publicly routable")
+
+// SafeClient creates a client refusing to connect to private, loopback and other addresses that are not
+// publicly routable unless allowPrivate is set, for development and tests. Requests time out after the
+// timeout. Redirects are not followed, so a request can't be bounced to another host; clients that follow
+// them replace CheckRedirect.
+func SafeClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = refusePrivate
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// refusePrivate fails dialing a resolved address that is not publicly routable
+func refusePrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return ErrPrivateAddress
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package notifications
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Kinds of notification
+const (
+	// KindComment is sent when someone comments on one of the user's documents
+	KindComment = "comment"
+	// KindEndorsement is sent when someone endorses one of the user's skills
+	KindEndorsement = "endorsement"
+	// KindCertificateExpiring is sent ahead of the end date of one of the user's certificates
+	KindCertificateExpiring = "certificate_expiring"
+	// KindCertificateArchived is sent when one of the user's certificates is archived after it ended
+	KindCertificateArchived = "certificate_archived"
+	// KindProcessingFinished is sent when processing of one of the user's journal entries finishes
+	KindProcessingFinished = "processing_finished"
+	// KindRecommendation is sent when someone writes a recommendation for the user, awaiting their approval
+	KindRecommendation = "recommendation"
+	// KindContactRequest is sent when a visitor sends the user a contact request that is not spam
+	KindContactRequest = "contact_request"
+	// KindServerError is sent to admins when a request fails on a panic in its handler
+	KindServerError = "server_error"
+)
+
+// Kinds lists every kind of notification
+var Kinds = []string{KindComment, KindEndorsement, KindCertificateExpiring, KindCertificateArchived, KindProcessingFinished, KindRecommendation, KindContactRequest, KindServerError}
+
+// Notification is a message to a user, kept for them to read in the notification center
+type Notification struct {
+	ID      string          `bson:"_id" json:"id"`
+	UserID  string          `bson:"user_id" json:"userID"`
+	Kind    string          `bson:"kind" json:"kind"`
+	Message string          `bson:"message" json:"message"`
+	Data    json.RawMessage `bson:"data,omitempty" json:"data,omitempty" swaggertype:"object"`
+	// ReadAt is nil until the user marks the notification read
+	ReadAt    *time.Time `bson:"read_at,omitempty" json:"readAt"`
+	CreatedAt time.Time  `bson:"created_at" json:"createdAt"`
+}
+
+// Filter selects notifications to list, newest first
+type Filter struct {
+	// Unread leaves out the notifications already read
+	Unread bool
+	Limit  int
+}
+
+// Channels chooses how a kind of notification reaches the user
+type Channels struct {
+	// Email sends the notification to the address the user signs in with
+	Email bool `bson:"email" json:"email"`
+	// InApp keeps the notification in the notification center and sends it to the user's connected clients
+	InApp bool `bson:"in_app" json:"inApp"`
+	// Webhook delivers the notification to the user's webhooks subscribed to notification.created
+	Webhook bool `bson:"webhook" json:"webhook"`
+}
+
+// Preferences are the channels the user chose for each kind of notification
+type Preferences struct {
+	UserID    string              `bson:"user_id" json:"-"`
+	Kinds     map[string]Channels `bson:"kinds" json:"kinds"`
+	UpdatedAt time.Time           `bson:"updated_at" json:"updatedAt"`
+}
+
+// PreferencesRequest represents the request body for changing notification preferences. Kinds left out
+// keep their channels.
+type PreferencesRequest struct {
+	Kinds map[string]Channels `json:"kinds" binding:"required,dive,keys,oneof=comment endorsement certificate_expiring certificate_archived processing_finished recommendation contact_request server_error,endkeys"`
+}
+
+// defaultChannels are the channels of the kinds of notification a user has not chosen channels for
+var defaultChannels = map[string]Channels{
+	KindComment:             {Email: true, InApp: true, Webhook: true},
+	KindEndorsement:         {Email: true, InApp: true, Webhook: true},
+	KindCertificateExpiring: {Email: true, InApp: true, Webhook: true},
+	KindCertificateArchived: {Email: true, InApp: true, Webhook: true},
+	KindProcessingFinished:  {InApp: true, Webhook: true},
+	KindRecommendation:      {Email: true, InApp: true, Webhook: true},
+	KindContactRequest:      {Email: true, InApp: true, Webhook: true},
+	KindServerError:         {Email: true, InApp: true},
+}
+
+// effective returns the channels of every kind of notification, the defaults overridden by the user's choices
+func (p Preferences) effective() Preferences {
+	kinds := make(map[string]Channels, len(defaultChannels))
+	for kind, channels := range defaultChannels {
+		kinds[kind] = channels
+	}
+	for kind, channels := range p.Kinds {
+		if _, ok := kinds[kind]; ok {
+			kinds[kind] = channels
+		}
+	}
+	p.Kinds = kinds
+	return p
+}
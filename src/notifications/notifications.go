@@ -0,0 +1,236 @@
+// Package notifications tells users about what happens to their content, such as comments, endorsements,
+// certificates about to expire and journal entries finishing processing. Each notification goes out over
+// the channels the user chose for its kind: kept in their notification center, where it stays unread until
+// they mark it read, emailed to them, or delivered to their webhooks.
+package notifications
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+// ListNotifications lists the current user's notifications
+//
+//	@Summary		List notifications
+//	@Description	Lists the current user's notifications, newest first. A notification is unread while its readAt is null.
+//	@Tags			notifications
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			unread	query		bool	false	"Only list unread notifications"
+//	@Param			limit	query		int		false	"Maximum number of notifications to return (default 50, max 500)"
+//	@Success		200		{array}		Notification
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve notifications"
+//	@Router			/notifications [get]
+func ListNotifications(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+	filter := Filter{Limit: defaultListLimit}
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		filter.Limit = min(l, maxListLimit)
+	}
+	filter.Unread, _ = strconv.ParseBool(c.Query("unread"))
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	list, err := repo.List(ctx, user.ID, filter)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve notifications"))
+		return
+	}
+	if list == nil {
+		list = []Notification{}
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// CountUnread counts the current user's unread notifications
+//
+//	@Summary		Count unread notifications
+//	@Description	Counts the current user's unread notifications, for showing a badge
+//	@Tags			notifications
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	map[string]int64
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		500	{object}	apierror.Response	"Could not count notifications"
+//	@Router			/notifications/unread-count [get]
+func CountUnread(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	count, err := repo.CountUnread(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not count notifications"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+// MarkRead marks one of the current user's notifications read
+//
+//	@Summary		Mark a notification read
+//	@Description	Marks a notification read. Marking a notification read again keeps the time it was first read.
+//	@Tags			notifications
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			notificationid	path		string	true	"Notification ID"
+//	@Success		200				{object}	map[string]string
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		404				{object}	apierror.Response	"Notification not found"
+//	@Router			/notifications/{notificationid}/read [post]
+func MarkRead(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.MarkRead(ctx, user.ID, c.Param("notificationid"), time.Now()); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Notification not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked read"})
+}
+
+// MarkAllRead marks every unread notification of the current user read
+//
+//	@Summary		Mark all notifications read
+//	@Description	Marks every unread notification of the current user read, returning how many were marked
+//	@Tags			notifications
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	map[string]int64
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		500	{object}	apierror.Response	"Could not mark notifications read"
+//	@Router			/notifications/read [post]
+func MarkAllRead(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	marked, err := repo.MarkAllRead(ctx, user.ID, time.Now())
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not mark notifications read"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+// DeleteNotification removes one of the current user's notifications
+//
+//	@Summary		Delete a notification
+//	@Description	Removes a notification from the notification center
+//	@Tags			notifications
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			notificationid	path		string	true	"Notification ID"
+//	@Success		200				{object}	map[string]string
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		404				{object}	apierror.Response	"Notification not found"
+//	@Router			/notifications/{notificationid} [delete]
+func DeleteNotification(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Delete(ctx, user.ID, c.Param("notificationid")); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Notification not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification deleted"})
+}
+
+// GetPreferences returns the current user's notification preferences
+//
+//	@Summary		Get notification preferences
+//	@Description	Returns the channels each kind of notification is sent over: email, in-app and webhook. Kinds the user has not chosen channels for show their defaults.
+//	@Tags			notifications
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	Preferences
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve notification preferences"
+//	@Router			/notifications/preferences [get]
+func GetPreferences(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	prefs, err := preferences(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve notification preferences"))
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences changes the channels of some kinds of notification for the current user
+//
+//	@Summary		Update notification preferences
+//	@Description	Sets the channels of the kinds of notification in the request: comment, endorsement, certificate_expiring, certificate_archived, processing_finished, recommendation or contact_request. Other kinds keep their channels. Webhook notifications are delivered to the user's webhooks subscribed to notification.created.
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		PreferencesRequest	true	"Channels per kind of notification"
+//	@Success		200		{object}	Preferences
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		500		{object}	apierror.Response	"Could not update notification preferences"
+//	@Router			/notifications/preferences [put]
+func UpdatePreferences(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req PreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	prefs, err := preferences(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update notification preferences"))
+		return
+	}
+	for kind, channels := range req.Kinds {
+		prefs.Kinds[kind] = channels
+	}
+	prefs.UpdatedAt = time.Now()
+	if err := repo.SavePreferences(ctx, prefs); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update notification preferences"))
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// InitializeRoutes initializes the notification routes. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup, users auth.Repository) {
+	router.Use(auth.AuthMiddleware(users, true))
+	router.GET("", ListNotifications)
+	router.GET("/unread-count", CountUnread)
+	router.POST("/read", MarkAllRead)
+	router.POST("/:notificationid/read", MarkRead)
+	router.DELETE("/:notificationid", DeleteNotification)
+	router.GET("/preferences", GetPreferences)
+	router.PUT("/preferences", UpdatePreferences)
+}
@@ -0,0 +1,28 @@
+package notifications
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores users' notifications and their notification preferences
+type Repository interface {
+	// Create stores a new notification
+	Create(ctx context.Context, n Notification) error
+	// List returns the user's notifications selected by the filter, newest first
+	List(ctx context.Context, userID string, filter Filter) ([]Notification, error)
+	// CountUnread returns how many of the user's notifications are unread
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	// MarkRead marks one of the user's notifications read, or returns store.ErrNotFound
+	MarkRead(ctx context.Context, userID, notificationID string, at time.Time) error
+	// MarkAllRead marks every unread notification of the user read, returning how many were marked
+	MarkAllRead(ctx context.Context, userID string, at time.Time) (int64, error)
+	// Delete removes one of the user's notifications, or returns store.ErrNotFound
+	Delete(ctx context.Context, userID, notificationID string) error
+	// Purge removes notifications created before the given time, returning how many were removed
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	// GetPreferences returns the channels the user chose, or store.ErrNotFound when they never chose any
+	GetPreferences(ctx context.Context, userID string) (Preferences, error)
+	// SavePreferences replaces the user's preferences
+	SavePreferences(ctx context.Context, prefs Preferences) error
+}
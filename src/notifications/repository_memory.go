@@ -0,0 +1,123 @@
+package notifications
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps notifications and preferences in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu            sync.RWMutex
+	notifications []Notification
+	preferences   map[string]Preferences
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{preferences: map[string]Preferences{}}
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, n Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifications = append(r.notifications, n)
+	return nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string, filter Filter) ([]Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []Notification
+	for i := len(r.notifications) - 1; i >= 0 && (filter.Limit <= 0 || len(list) < filter.Limit); i-- {
+		n := r.notifications[i]
+		if n.UserID == userID && (!filter.Unread || n.ReadAt == nil) {
+			list = append(list, n)
+		}
+	}
+	return list, nil
+}
+
+func (r *MemoryRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var count int64
+	for _, n := range r.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryRepository) MarkRead(ctx context.Context, userID, notificationID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, notificationID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	if r.notifications[i].ReadAt == nil {
+		r.notifications[i].ReadAt = &at
+	}
+	return nil
+}
+
+func (r *MemoryRepository) MarkAllRead(ctx context.Context, userID string, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var marked int64
+	for i, n := range r.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			r.notifications[i].ReadAt = &at
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, userID, notificationID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, notificationID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.notifications = slices.Delete(r.notifications, i, i+1)
+	return nil
+}
+
+func (r *MemoryRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(r.notifications)
+	r.notifications = slices.DeleteFunc(r.notifications, func(item Notification) bool { return item.CreatedAt.Before(before) })
+	return int64(n - len(r.notifications)), nil
+}
+
+func (r *MemoryRepository) GetPreferences(ctx context.Context, userID string) (Preferences, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	prefs, ok := r.preferences[userID]
+	if !ok {
+		return Preferences{}, store.ErrNotFound
+	}
+	return prefs, nil
+}
+
+func (r *MemoryRepository) SavePreferences(ctx context.Context, prefs Preferences) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.preferences[prefs.UserID] = prefs
+	return nil
+}
+
+// index returns the position of the user's notification, or -1. The caller must hold the lock.
+func (r *MemoryRepository) index(userID, notificationID string) int {
+	return slices.IndexFunc(r.notifications, func(n Notification) bool {
+		return n.UserID == userID && n.ID == notificationID
+	})
+}
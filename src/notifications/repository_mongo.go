@@ -0,0 +1,110 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores notifications in the notifications collection and preferences in
+// notification_preferences
+type MongoRepository struct {
+	notifications *mongo.Collection
+	preferences   *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		notifications: db.Collection("notifications"),
+		preferences:   db.Collection("notification_preferences"),
+	}
+}
+
+func (r *MongoRepository) Create(ctx context.Context, n Notification) error {
+	_, err := r.notifications.InsertOne(ctx, n)
+	return err
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string, filter Filter) ([]Notification, error) {
+	query := bson.M{"user_id": userID}
+	if filter.Unread {
+		query["read_at"] = bson.M{"$exists": false}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+	cursor, err := r.notifications.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var list []Notification
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *MongoRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return r.notifications.CountDocuments(ctx, bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}})
+}
+
+func (r *MongoRepository) MarkRead(ctx context.Context, userID, notificationID string, at time.Time) error {
+	result, err := r.notifications.UpdateOne(ctx, bson.M{"_id": notificationID, "user_id": userID},
+		// Keeps the time the notification was first read
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"read_at": bson.M{"$ifNull": bson.A{"$read_at", at}}}}}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) MarkAllRead(ctx context.Context, userID string, at time.Time) (int64, error) {
+	result, err := r.notifications.UpdateMany(ctx, bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": at}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, userID, notificationID string) error {
+	result, err := r.notifications.DeleteOne(ctx, bson.M{"_id": notificationID, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.notifications.DeleteMany(ctx, bson.M{"created_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (r *MongoRepository) GetPreferences(ctx context.Context, userID string) (Preferences, error) {
+	var prefs Preferences
+	err := r.preferences.FindOne(ctx, bson.M{"user_id": userID}).Decode(&prefs)
+	return prefs, store.MongoErr(err)
+}
+
+func (r *MongoRepository) SavePreferences(ctx context.Context, prefs Preferences) error {
+	_, err := r.preferences.ReplaceOne(ctx, bson.M{"user_id": prefs.UserID}, prefs, options.Replace().SetUpsert(true))
+	return err
+}
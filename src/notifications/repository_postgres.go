@@ -0,0 +1,115 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const notificationColumns = "id, user_id, kind, message, data, read_at, created_at"
+
+// PostgresRepository stores notifications in the notifications table and preferences in
+// notification_preferences
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, n Notification) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO notifications ("+notificationColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		n.ID, n.UserID, n.Kind, n.Message, n.Data, n.ReadAt, n.CreatedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string, filter Filter) ([]Notification, error) {
+	query := "SELECT " + notificationColumns + " FROM notifications WHERE user_id = $1"
+	if filter.Unread {
+		query += " AND read_at IS NULL"
+	}
+	query += " ORDER BY created_at DESC"
+	args := []any{userID}
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanNotification)
+}
+
+func (r *PostgresRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.pool.QueryRow(ctx, "SELECT count(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL", userID).Scan(&count)
+	return count, err
+}
+
+func (r *PostgresRepository) MarkRead(ctx context.Context, userID, notificationID string, at time.Time) error {
+	// Keeps the time the notification was first read
+	tag, err := r.pool.Exec(ctx, "UPDATE notifications SET read_at = COALESCE(read_at, $3) WHERE user_id = $1 AND id = $2",
+		userID, notificationID, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) MarkAllRead(ctx context.Context, userID string, at time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, "UPDATE notifications SET read_at = $2 WHERE user_id = $1 AND read_at IS NULL", userID, at)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, userID, notificationID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM notifications WHERE user_id = $1 AND id = $2", userID, notificationID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM notifications WHERE created_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *PostgresRepository) GetPreferences(ctx context.Context, userID string) (Preferences, error) {
+	prefs := Preferences{UserID: userID}
+	err := r.pool.QueryRow(ctx, "SELECT kinds, updated_at FROM notification_preferences WHERE user_id = $1", userID).
+		Scan(&prefs.Kinds, &prefs.UpdatedAt)
+	return prefs, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) SavePreferences(ctx context.Context, prefs Preferences) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO notification_preferences (user_id, kinds, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET kinds = EXCLUDED.kinds, updated_at = EXCLUDED.updated_at`,
+		prefs.UserID, prefs.Kinds, prefs.UpdatedAt)
+	return err
+}
+
+func scanNotification(row pgx.CollectableRow) (Notification, error) {
+	var n Notification
+	err := row.Scan(&n.ID, &n.UserID, &n.Kind, &n.Message, &n.Data, &n.ReadAt, &n.CreatedAt)
+	return n, err
+}
@@ -0,0 +1,54 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, n Notification) error {
+	return r.repos.For(ctx).Create(ctx, n)
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID string, filter Filter) ([]Notification, error) {
+	return r.repos.For(ctx).List(ctx, userID, filter)
+}
+
+func (r *TenantRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return r.repos.For(ctx).CountUnread(ctx, userID)
+}
+
+func (r *TenantRepository) MarkRead(ctx context.Context, userID, notificationID string, at time.Time) error {
+	return r.repos.For(ctx).MarkRead(ctx, userID, notificationID, at)
+}
+
+func (r *TenantRepository) MarkAllRead(ctx context.Context, userID string, at time.Time) (int64, error) {
+	return r.repos.For(ctx).MarkAllRead(ctx, userID, at)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, userID, notificationID string) error {
+	return r.repos.For(ctx).Delete(ctx, userID, notificationID)
+}
+
+func (r *TenantRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return r.repos.For(ctx).Purge(ctx, before)
+}
+
+func (r *TenantRepository) GetPreferences(ctx context.Context, userID string) (Preferences, error) {
+	return r.repos.For(ctx).GetPreferences(ctx, userID)
+}
+
+func (r *TenantRepository) SavePreferences(ctx context.Context, prefs Preferences) error {
+	return r.repos.For(ctx).SavePreferences(ctx, prefs)
+}
@@ -0,0 +1,187 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"profile-api/auth"
+	"profile-api/certificates"
+	"profile-api/config"
+	"profile-api/email"
+	"profile-api/events"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+)
+
+const notificationEmail = "notification"
+
+var (
+	repo     Repository
+	users    auth.Repository
+	certs    certificates.Repository
+	settings = config.NotificationsConfig{
+		CertificateExpiryNotice: config.Duration(30 * 24 * time.Hour),
+		Retention:               config.Duration(90 * 24 * time.Hour),
+	}
+)
+
+type emailData struct {
+	Name    string
+	Message string
+}
+
+// Configure sets where notifications are stored, where their recipients and certificates are looked up,
+// and starts notifying users of the published events they are notified of
+func Configure(r Repository, u auth.Repository, c certificates.Repository, cfg config.NotificationsConfig) {
+	repo = r
+	users = u
+	certs = c
+	settings = cfg
+	events.Listen(notifyEvent)
+}
+
+// Send notifies the user over the channels they chose for the kind of notification: keeping it in their
+// notification center and sending it to their connected clients, emailing them, and delivering it to their
+// webhooks. The data, such as the ID of the document the notification is about, is included as is.
+func Send(ctx context.Context, userID, kind, message string, data any) error {
+	prefs, err := preferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+	channels := prefs.Kinds[kind]
+
+	n := Notification{
+		ID:        utils.GenerateID(),
+		UserID:    userID,
+		Kind:      kind,
+		Message:   message,
+		CreatedAt: time.Now(),
+	}
+	if data != nil {
+		if n.Data, err = json.Marshal(data); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	if channels.InApp {
+		if err := repo.Create(ctx, n); err != nil {
+			errs = append(errs, fmt.Errorf("storing notification: %w", err))
+		} else {
+			events.Notify(ctx, userID, events.TypeNotification, n)
+		}
+	}
+	if channels.Webhook {
+		events.Dispatch(ctx, userID, events.TypeNotification, n)
+	}
+	if channels.Email {
+		if err := sendEmail(ctx, userID, message); err != nil {
+			errs = append(errs, fmt.Errorf("emailing notification: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotifyAdmins sends the notification to every admin whose account is enabled
+func NotifyAdmins(ctx context.Context, kind, message string, data any) error {
+	admins, err := users.List(ctx, auth.UserFilter{Admin: true})
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, admin := range admins {
+		if admin.Disabled {
+			continue
+		}
+		if err := Send(ctx, admin.ID, kind, message, data); err != nil {
+			errs = append(errs, fmt.Errorf("notifying admin %s: %w", admin.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendEmail queues the notification message for delivery to the address the user signs in with
+func sendEmail(ctx context.Context, userID, message string) error {
+	user, err := users.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	msg, err := email.Render(notificationEmail, emailData{Name: user.Name, Message: message})
+	if err != nil {
+		return err
+	}
+	msg.To = user.Email
+	msg.UserID = user.ID
+	return email.Enqueue(ctx, msg)
+}
+
+// preferences returns the channels of every kind of notification for the user
+func preferences(ctx context.Context, userID string) (Preferences, error) {
+	prefs, err := repo.GetPreferences(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		prefs, err = Preferences{UserID: userID}, nil
+	}
+	if err != nil {
+		return Preferences{}, err
+	}
+	return prefs.effective(), nil
+}
+
+// notifyEvent notifies users of the published events that concern them
+func notifyEvent(event events.Event) {
+	var kind, message string
+	switch event.Type {
+	case events.TypeJournalProcessed:
+		kind, message = KindProcessingFinished, "Processing of your journal entry finished"
+	case events.TypeCertificateArchived:
+		item, _ := event.Data.(certificates.Certificate)
+		kind, message = KindCertificateArchived, fmt.Sprintf("Your certificate %s from %s ended on %s and was archived", item.Title, item.Institution, item.End)
+		if item.Restricted != "" {
+			message += ", so it is no longer shown on your public profile"
+		}
+	default:
+		return
+	}
+	ctx, cancel := utils.WithOperationTimeout(tenant.WithID(context.Background(), event.Tenant))
+	defer cancel()
+	if err := Send(ctx, event.UserID, kind, message, event.Data); err != nil {
+		slog.Error("Could not notify user", "kind", kind, "user_id", event.UserID, "error", err)
+	}
+}
+
+// NotifyExpiringCertificates notifies the owners of the certificates ending on the day the configured notice
+// ahead of today. A certificate ending on a month or year, rather than a day, is notified ahead of the month
+// or year starting. Certificates ending on a day the task did not run for are not notified.
+func NotifyExpiringCertificates(ctx context.Context) error {
+	day := time.Now().UTC().Add(settings.CertificateExpiryNotice.Std())
+	const layout = "2006-01-02"
+	items, err := certs.ListEnding(ctx, day.Format(layout), day.AddDate(0, 0, 1).Format(layout))
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, item := range items {
+		message := fmt.Sprintf("Your certificate %s from %s ends on %s", item.Title, item.Institution, item.End)
+		data := map[string]string{"certificateID": item.CertificateID, "title": item.Title, "end": item.End}
+		if err := Send(ctx, item.UserID, KindCertificateExpiring, message, data); err != nil {
+			errs = append(errs, fmt.Errorf("certificate %s: %w", item.CertificateID, err))
+		}
+	}
+	slog.Info("Notified owners of expiring certificates", "certificates", len(items), "failed", len(errs))
+	return errors.Join(errs...)
+}
+
+// Purge removes notifications older than the configured retention
+func Purge(ctx context.Context) error {
+	removed, err := repo.Purge(ctx, time.Now().Add(-settings.Retention.Std()))
+	if err != nil {
+		return err
+	}
+	slog.Info("Purged notifications", "removed", removed)
+	return nil
+}
@@ -0,0 +1,202 @@
+package openapi
+
+import "strings"
+
+// parameterSchemaKeys are the fields of a Swagger 2.0 parameter that make up its schema in OpenAPI 3
+var parameterSchemaKeys = []string{"type", "format", "items", "enum", "default", "minimum", "maximum", "maxLength", "minLength", "maxItems", "minItems", "pattern"}
+
+// parameterSchema extracts the schema of a Swagger 2.0 parameter other than a body
+func parameterSchema(param map[string]any) map[string]any {
+	schema := map[string]any{}
+	for _, key := range parameterSchemaKeys {
+		if v, ok := param[key]; ok {
+			schema[key] = v
+		}
+	}
+	return schema
+}
+
+// paramName returns the name a parameter is served under, renaming the path parameters the documentation
+// names differently from the route
+func paramName(param map[string]any, renamed map[string]string) string {
+	name, _ := param["name"].(string)
+	if param["in"] == "path" {
+		if served, ok := renamed[name]; ok {
+			return served
+		}
+	}
+	return name
+}
+
+// consumes returns the media types the operation's body may have
+func consumes(op swaggerOperation, spec swagger) []string {
+	if len(op.Consumes) > 0 {
+		return op.Consumes
+	}
+	if len(spec.Consumes) > 0 {
+		return spec.Consumes
+	}
+	for _, param := range op.Parameters {
+		switch param["in"] {
+		case "body":
+			return []string{"application/json"}
+		case "formData":
+			return []string{"multipart/form-data"}
+		}
+	}
+	return nil
+}
+
+// convertOperation converts a Swagger 2.0 operation into an OpenAPI 3 one served at a route with the given
+// path parameters
+func convertOperation(op swaggerOperation, renamed map[string]string, routeParams []string, spec swagger) map[string]any {
+	result := map[string]any{}
+	if op.OperationID != "" {
+		result["operationId"] = op.OperationID
+	}
+	if op.Summary != "" {
+		result["summary"] = op.Summary
+	}
+	if op.Description != "" {
+		result["description"] = op.Description
+	}
+	if len(op.Tags) > 0 {
+		result["tags"] = op.Tags
+	}
+	if len(op.Security) > 0 {
+		result["security"] = op.Security
+	}
+	if op.Deprecated {
+		result["deprecated"] = true
+	}
+
+	params := []any{}
+	documented := map[string]bool{}
+	formProperties := map[string]any{}
+	var formRequired []string
+	for _, param := range op.Parameters {
+		name := paramName(param, renamed)
+		switch param["in"] {
+		case "body":
+			content := map[string]any{}
+			for _, mediaType := range consumes(op, spec) {
+				content[mediaType] = map[string]any{"schema": rewriteRefs(param["schema"])}
+			}
+			result["requestBody"] = map[string]any{"required": param["required"] == true, "content": content}
+		case "formData":
+			formProperties[name] = parameterSchema(param)
+			if param["required"] == true {
+				formRequired = append(formRequired, name)
+			}
+		default:
+			converted := map[string]any{
+				"name":     name,
+				"in":       param["in"],
+				"required": param["required"] == true || param["in"] == "path",
+				"schema":   rewriteRefs(parameterSchema(param)),
+			}
+			if description, ok := param["description"]; ok {
+				converted["description"] = description
+			}
+			if param["in"] == "path" {
+				documented[name] = true
+			}
+			params = append(params, converted)
+		}
+	}
+	// Every path parameter of the route is listed, documented or not
+	for _, name := range routeParams {
+		if !documented[name] {
+			params = append(params, map[string]any{"name": name, "in": "path", "required": true, "schema": map[string]any{"type": "string"}})
+		}
+	}
+	if len(params) > 0 {
+		result["parameters"] = params
+	}
+	if len(formProperties) > 0 {
+		schema := map[string]any{"type": "object", "properties": formProperties}
+		if len(formRequired) > 0 {
+			schema["required"] = formRequired
+		}
+		content := map[string]any{}
+		for _, mediaType := range consumes(op, spec) {
+			content[mediaType] = map[string]any{"schema": schema}
+		}
+		result["requestBody"] = map[string]any{"required": len(formRequired) > 0, "content": content}
+	}
+
+	produces := op.Produces
+	if len(produces) == 0 {
+		produces = spec.Produces
+	}
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+	responses := map[string]any{}
+	for status, response := range op.Responses {
+		converted := map[string]any{"description": response["description"]}
+		if converted["description"] == nil {
+			converted["description"] = ""
+		}
+		if schema, ok := response["schema"]; ok {
+			content := map[string]any{}
+			for _, mediaType := range produces {
+				content[mediaType] = map[string]any{"schema": rewriteRefs(schema)}
+			}
+			converted["content"] = content
+		}
+		responses[status] = converted
+	}
+	result["responses"] = responses
+	return result
+}
+
+// validatedOperation extracts what requests to the operation are validated against
+func validatedOperation(op swaggerOperation, renamed map[string]string, spec swagger) *operation {
+	result := &operation{Consumes: consumes(op, spec)}
+	for _, param := range op.Parameters {
+		switch param["in"] {
+		case "body":
+			result.Body, _ = param["schema"].(map[string]any)
+			result.BodyRequired = param["required"] == true
+		case "path", "query", "header":
+			result.Params = append(result.Params, parameter{
+				Name:     paramName(param, renamed),
+				In:       param["in"].(string),
+				Required: param["required"] == true,
+				Schema:   parameterSchema(param),
+			})
+		}
+	}
+	return result
+}
+
+// rewriteRefs copies the schema, pointing its references at the OpenAPI 3 components and turning files into
+// binary strings
+func rewriteRefs(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for key, value := range v {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				copied[key] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+				continue
+			}
+			copied[key] = rewriteRefs(value)
+		}
+		// Files are binary strings in OpenAPI 3
+		if copied["type"] == "file" {
+			copied["type"] = "string"
+			copied["format"] = "binary"
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, value := range v {
+			copied[i] = rewriteRefs(value)
+		}
+		return copied
+	default:
+		return v
+	}
+}
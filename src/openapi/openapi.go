@@ -0,0 +1,218 @@
+// Package openapi builds the API's OpenAPI 3 document from the routes the router serves, described by the
+// operations documented on their handlers, and validates incoming requests against it. Routes and
+// documented operations that do not match each other are logged when the document is built, so drift
+// between the documentation and the handlers shows up at start-up rather than in clients.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"profile-api/config"
+	"profile-api/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Path is where the OpenAPI document is served
+const Path = "/openapi.json"
+
+// versionPrefix matches the prefix of the versioned API routes, which the documented paths are relative to
+var versionPrefix = regexp.MustCompile(`^/api/v[0-9]+`)
+
+// pathParam matches a parameter of a documented path, {name}, or of a route, :name or *name
+var pathParam = regexp.MustCompile(`\{[^}]+\}|[:*][^/]+`)
+
+// excludedRoutes are served without being part of the API
+var excludedRoutes = []string{"/swagger/*any", Path}
+
+var mode = "enforce"
+
+// document is the OpenAPI document, encoded once built
+var document []byte
+
+// operations holds what requests are validated against, keyed by method and route
+var operations = map[string]*operation{}
+
+// definitions are the schemas the operations' body schemas refer to
+var definitions map[string]any
+
+// parameter is a documented parameter of an operation
+type parameter struct {
+	Name     string
+	In       string
+	Required bool
+	Schema   map[string]any
+}
+
+// operation is what a route accepts, as documented
+type operation struct {
+	Params []parameter
+	// Body is the schema of the JSON body, or nil when the operation takes none
+	Body         map[string]any
+	BodyRequired bool
+	// Consumes lists the media types the body may have
+	Consumes []string
+}
+
+// swagger is the Swagger 2.0 document generated from the handlers' annotations
+type swagger struct {
+	Info        map[string]any                         `json:"info"`
+	Consumes    []string                               `json:"consumes"`
+	Produces    []string                               `json:"produces"`
+	Paths       map[string]map[string]swaggerOperation `json:"paths"`
+	Definitions map[string]any                         `json:"definitions"`
+}
+
+type swaggerOperation struct {
+	OperationID string                    `json:"operationId"`
+	Summary     string                    `json:"summary"`
+	Description string                    `json:"description"`
+	Tags        []string                  `json:"tags"`
+	Consumes    []string                  `json:"consumes"`
+	Produces    []string                  `json:"produces"`
+	Parameters  []map[string]any          `json:"parameters"`
+	Responses   map[string]map[string]any `json:"responses"`
+	Security    []map[string][]string     `json:"security"`
+	Deprecated  bool                      `json:"deprecated"`
+}
+
+// documented is a documented operation and the path it is documented under
+type documented struct {
+	Method    string
+	Path      string
+	Operation swaggerOperation
+	matched   bool
+}
+
+// Configure sets how requests are validated
+func Configure(cfg config.OpenAPIConfig) {
+	mode = cfg.Validation
+}
+
+// shape replaces the parameters of a path with {} and drops any trailing slash, so documented paths and
+// routes can be compared
+func shape(path string) string {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return pathParam.ReplaceAllString(path, "{}")
+}
+
+// paramNames lists the names of the parameters of a documented path or a route, in order
+func paramNames(path string) []string {
+	var names []string
+	for _, p := range pathParam.FindAllString(path, -1) {
+		names = append(names, strings.Trim(p, "{}:*"))
+	}
+	return names
+}
+
+// Build builds the OpenAPI document and the operations requests are validated against from the routes, and
+// logs the routes that are not documented. It must be
+// called once every route is registered.
+func Build(routes gin.RoutesInfo) error {
+	var spec swagger
+	if err := json.Unmarshal([]byte(docs.SwaggerInfo.ReadDoc()), &spec); err != nil {
+		return fmt.Errorf("could not parse the API documentation: %w", err)
+	}
+	definitions = spec.Definitions
+
+	byShape := map[string]*documented{}
+	for path, item := range spec.Paths {
+		for method, op := range item {
+			byShape[strings.ToUpper(method)+" "+shape(path)] = &documented{Method: strings.ToUpper(method), Path: path, Operation: op}
+		}
+	}
+
+	paths := map[string]map[string]any{}
+	built := map[string]*operation{}
+	for _, route := range routes {
+		if slices.Contains(excludedRoutes, route.Path) {
+			continue
+		}
+		relative := versionPrefix.ReplaceAllString(route.Path, "")
+		doc, ok := byShape[route.Method+" "+shape(relative)]
+		if !ok {
+			slog.Warn("Route is not documented", "method", route.Method, "path", route.Path)
+			doc = &documented{Method: route.Method, Path: relative, Operation: undocumented()}
+		}
+		doc.matched = true
+
+		// The documentation may name the path parameters differently, the route's names are the ones served
+		renamed := map[string]string{}
+		routeNames := paramNames(route.Path)
+		for i, name := range paramNames(doc.Path) {
+			if i < len(routeNames) {
+				renamed[name] = routeNames[i]
+			}
+		}
+		path := pathParam.ReplaceAllStringFunc(route.Path, func(p string) string { return "{" + strings.Trim(p, ":*") + "}" })
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		op := doc.Operation
+		// HEAD is documented on the GET handlers, so it shares their operation ID, which must be unique
+		if route.Method == http.MethodHead && op.OperationID != "" {
+			op.OperationID += "-head"
+		}
+		paths[path][strings.ToLower(route.Method)] = convertOperation(op, renamed, routeNames, spec)
+		if ok {
+			built[route.Method+" "+route.Path] = validatedOperation(doc.Operation, renamed, spec)
+		}
+	}
+	for _, doc := range byShape {
+		if !doc.matched {
+			// Routes of optional modules are only served when they are enabled
+			slog.Debug("Documented operation is not served", "method", doc.Method, "path", doc.Path)
+		}
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"openapi": "3.0.3",
+		"info":    spec.Info,
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": rewriteRefs(spec.Definitions),
+			"securitySchemes": map[string]any{
+				"BearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	document = data
+	operations = built
+	return nil
+}
+
+// undocumented is the operation of a route missing from the documentation
+func undocumented() swaggerOperation {
+	return swaggerOperation{
+		Summary:   "Undocumented",
+		Responses: map[string]map[string]any{"default": {"description": "Undocumented"}},
+	}
+}
+
+// GetDocument returns the OpenAPI document
+//
+//	@Summary		Get the OpenAPI document
+//	@Description	Returns the OpenAPI 3 document of every route the server serves, described by the operations documented on their handlers. Requests are validated against it.
+//	@Tags			openapi
+//	@Produce		json
+//	@Success		200	{object}	object	"OpenAPI 3 document"
+//	@Router			/openapi.json [get]
+func GetDocument(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", document)
+}
+
+// InitializeRoutes registers the route serving the OpenAPI document
+func InitializeRoutes(router gin.IRoutes) {
+	router.GET(Path, GetDocument)
+}
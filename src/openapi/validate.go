@@ -0,0 +1,292 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"profile-api/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSchemaDepth bounds how deeply bodies are validated, as recursive schemas could otherwise loop
+const maxSchemaDepth = 32
+
+// Middleware validates the path, query and header parameters, content type and JSON body of requests to
+// documented routes against their operations. Invalid requests are rejected with 400 and the fields at
+// fault, or only logged when validation is report.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if mode == "off" {
+			c.Next()
+			return
+		}
+		op, ok := operations[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+		problems, err := validateRequest(c, op)
+		if err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not read request"))
+			return
+		}
+		if len(problems) > 0 {
+			if mode == "enforce" {
+				apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Request does not match the API specification").WithDetails(problems))
+				return
+			}
+			slog.WarnContext(c.Request.Context(), "Request does not match the API specification", "method", c.Request.Method, "route", c.FullPath(), "errors", problems)
+		}
+		c.Next()
+	}
+}
+
+// validateRequest returns what in the request does not match the operation. The body is read and put back
+// for the handler.
+func validateRequest(c *gin.Context, op *operation) ([]apierror.FieldError, error) {
+	var problems []apierror.FieldError
+	query := c.Request.URL.Query()
+	for _, param := range op.Params {
+		var values []string
+		switch param.In {
+		case "path":
+			values = []string{c.Param(param.Name)}
+		case "query":
+			values = query[param.Name]
+		case "header":
+			values = c.Request.Header.Values(param.Name)
+		}
+		if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+			if param.Required {
+				problems = append(problems, apierror.FieldError{Field: param.Name, Rule: "required", Message: "is required"})
+			}
+			continue
+		}
+		if param.Schema["type"] == "array" {
+			items, _ := param.Schema["items"].(map[string]any)
+			var all []string
+			for _, v := range values {
+				all = append(all, strings.Split(v, ",")...)
+			}
+			for _, v := range all {
+				problems = append(problems, validateParam(param.Name, v, items)...)
+			}
+			continue
+		}
+		problems = append(problems, validateParam(param.Name, values[0], param.Schema)...)
+	}
+
+	if len(op.Consumes) == 0 || c.Request.Body == nil || c.Request.Body == http.NoBody || c.Request.ContentLength == 0 {
+		if op.Body != nil && op.BodyRequired {
+			problems = append(problems, apierror.FieldError{Field: "body", Rule: "required", Message: "is required"})
+		}
+		return problems, nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+	if !slices.Contains(op.Consumes, mediaType) {
+		return append(problems, apierror.FieldError{
+			Field:   "Content-Type",
+			Rule:    "content_type",
+			Param:   strings.Join(op.Consumes, " "),
+			Message: "must be one of: " + strings.Join(op.Consumes, ", "),
+		}), nil
+	}
+	if op.Body == nil || !strings.HasSuffix(mediaType, "json") {
+		return problems, nil
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+	if len(bytes.TrimSpace(data)) == 0 {
+		if op.BodyRequired {
+			problems = append(problems, apierror.FieldError{Field: "body", Rule: "required", Message: "is required"})
+		}
+		return problems, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var body any
+	if err := decoder.Decode(&body); err != nil {
+		return append(problems, apierror.FieldError{Field: "body", Rule: "json", Message: "must be valid JSON"}), nil
+	}
+	return append(problems, validateValue("", body, op.Body, 0)...), nil
+}
+
+// validateParam checks a parameter's value, which arrives as text, against its schema
+func validateParam(name, value string, schema map[string]any) []apierror.FieldError {
+	var parsed any = value
+	switch schema["type"] {
+	case "integer", "number":
+		parsed = json.Number(value)
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return []apierror.FieldError{typeError(name, "boolean")}
+		}
+		parsed = b
+	}
+	return validateValue(name, parsed, schema, 0)
+}
+
+// validateValue checks a decoded JSON value against the schema, resolving references to definitions
+func validateValue(path string, value any, schema map[string]any, depth int) []apierror.FieldError {
+	if depth > maxSchemaDepth || schema == nil {
+		return nil
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, _ := definitions[strings.TrimPrefix(ref, "#/definitions/")].(map[string]any)
+		return validateValue(path, value, resolved, depth+1)
+	}
+	var problems []apierror.FieldError
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, s := range all {
+			sub, _ := s.(map[string]any)
+			problems = append(problems, validateValue(path, value, sub, depth+1)...)
+		}
+	}
+	// A null leaves the field at its zero value, which the handler validates like a missing field
+	if value == nil {
+		return problems
+	}
+
+	field := path
+	if field == "" {
+		field = "body"
+	}
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return append(problems, typeError(field, "object"))
+		}
+		for _, name := range stringList(schema["required"]) {
+			if object[name] == nil {
+				problems = append(problems, apierror.FieldError{Field: join(path, name), Rule: "required", Message: "is required"})
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		additional, _ := schema["additionalProperties"].(map[string]any)
+		for name, v := range object {
+			property, ok := properties[name].(map[string]any)
+			if !ok {
+				property = additional
+			}
+			problems = append(problems, validateValue(join(path, name), v, property, depth+1)...)
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return append(problems, typeError(field, "array"))
+		}
+		if limit, ok := number(schema["maxItems"]); ok && float64(len(items)) > limit {
+			problems = append(problems, limitError(field, "max", limit, "must have at most %s items"))
+		}
+		if limit, ok := number(schema["minItems"]); ok && float64(len(items)) < limit {
+			problems = append(problems, limitError(field, "min", limit, "must have at least %s items"))
+		}
+		itemSchema, _ := schema["items"].(map[string]any)
+		for i, item := range items {
+			problems = append(problems, validateValue(fmt.Sprintf("%s[%d]", path, i), item, itemSchema, depth+1)...)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return append(problems, typeError(field, "string"))
+		}
+		length := float64(utf8.RuneCountInString(s))
+		if limit, ok := number(schema["maxLength"]); ok && length > limit {
+			problems = append(problems, limitError(field, "max", limit, "must be at most %s characters"))
+		}
+		if limit, ok := number(schema["minLength"]); ok && length < limit {
+			problems = append(problems, limitError(field, "min", limit, "must be at least %s characters"))
+		}
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			return append(problems, typeError(field, schema["type"].(string)))
+		}
+		f, err := n.Float64()
+		if err != nil || (schema["type"] == "integer" && f != math.Trunc(f)) {
+			return append(problems, typeError(field, schema["type"].(string)))
+		}
+		if limit, ok := number(schema["maximum"]); ok && f > limit {
+			problems = append(problems, limitError(field, "max", limit, "must be at most %s"))
+		}
+		if limit, ok := number(schema["minimum"]); ok && f < limit {
+			problems = append(problems, limitError(field, "min", limit, "must be at least %s"))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return append(problems, typeError(field, "boolean"))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		allowed := make([]string, len(enum))
+		for i, e := range enum {
+			allowed[i] = fmt.Sprint(e)
+		}
+		if !slices.Contains(allowed, fmt.Sprint(value)) {
+			problems = append(problems, apierror.FieldError{
+				Field:   field,
+				Rule:    "oneof",
+				Param:   strings.Join(allowed, " "),
+				Message: "must be one of: " + strings.Join(allowed, ", "),
+			})
+		}
+	}
+	return problems
+}
+
+func typeError(field, typ string) apierror.FieldError {
+	article := "a"
+	if strings.ContainsRune("aeiou", rune(typ[0])) {
+		article = "an"
+	}
+	return apierror.FieldError{Field: field, Rule: "type", Param: typ, Message: "must be " + article + " " + typ}
+}
+
+func limitError(field, rule string, limit float64, message string) apierror.FieldError {
+	param := strconv.FormatFloat(limit, 'f', -1, 64)
+	return apierror.FieldError{Field: field, Rule: rule, Param: param, Message: fmt.Sprintf(message, param)}
+}
+
+// join appends a property to a JSON path
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// number reads a numeric keyword of a schema
+func number(v any) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+// stringList reads a list of strings from a schema
+func stringList(v any) []string {
+	list, _ := v.([]any)
+	var result []string
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
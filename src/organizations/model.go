@@ -0,0 +1,70 @@
+package organizations
+
+import "time"
+
+// Roles of an organization's members, from the most to the least privileged
+const (
+	// RoleOwner members manage the organization, its members and may delete it
+	RoleOwner = "owner"
+	// RoleAdmin members edit the organization's profile and manage its members, except its owners
+	RoleAdmin = "admin"
+	// RoleMember members are listed on the organization's page
+	RoleMember = "member"
+)
+
+// rank orders the roles, lower ranks holding more privileges
+var rank = map[string]int{RoleOwner: 0, RoleAdmin: 1, RoleMember: 2}
+
+// atLeast reports whether the role holds the privileges of the other
+func atLeast(role, other string) bool {
+	r, ok := rank[role]
+	return ok && r <= rank[other]
+}
+
+// Organization is a company or other organization with its own profile page. Verified organizations,
+// checked by an admin, may be linked from their members' experience.
+type Organization struct {
+	ID          string `bson:"_id" json:"id"`
+	Name        string `bson:"name" json:"name"`
+	Description string `bson:"description" json:"description"`
+	Website     string `bson:"website" json:"website"`
+	Location    string `bson:"location" json:"location"`
+	Verified    bool   `bson:"verified" json:"verified"`
+	// CreatedBy is the user who created the organization
+	CreatedBy string    `bson:"created_by" json:"createdBy"`
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+}
+
+// OrganizationRequest represents the request body for creating or updating an organization's profile
+type OrganizationRequest struct {
+	Name        string `json:"name" binding:"required,notblank,max=200"`
+	Description string `json:"description" binding:"max=5000"`
+	Website     string `json:"website" binding:"omitempty,weburl,max=2048"`
+	Location    string `json:"location" binding:"max=200"`
+}
+
+// VerificationRequest represents the request body for verifying an organization or withdrawing its
+// verification
+type VerificationRequest struct {
+	Verified bool `json:"verified"`
+}
+
+// Member is a user belonging to an organization
+type Member struct {
+	OrganizationID string    `bson:"organization_id" json:"organizationID"`
+	UserID         string    `bson:"user_id" json:"userID"`
+	Role           string    `bson:"role" json:"role"`
+	JoinedAt       time.Time `bson:"joined_at" json:"joinedAt"`
+}
+
+// MemberRequest represents the request body for adding a member or changing their role
+type MemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner admin member"`
+}
+
+// Page is an organization's public page: its profile and its team
+type Page struct {
+	Organization
+	Members []Member `json:"members"`
+}
@@ -0,0 +1,474 @@
+// Package organizations gives companies and other organizations their own profile page on the instance,
+// listing their team. Any user may create an organization, becoming its owner; owners and admins edit its
+// profile and add members with roles, and members may leave. Admins of the instance verify organizations,
+// after which users may link their experience to them.
+package organizations
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/apiversion"
+	"profile-api/auth"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var repo Repository
+var users auth.Repository
+
+// Configure sets where organizations are stored and where their members' accounts are read from
+func Configure(r Repository, u auth.Repository) {
+	repo = r
+	users = u
+}
+
+// Verified returns the organization, or an error to respond with when it does not exist or is not verified,
+// for modules linking to organizations
+func Verified(c *gin.Context, organizationID string) (Organization, error) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	org, err := repo.Get(ctx, organizationID)
+	if errors.Is(err, store.ErrNotFound) {
+		return org, apierror.Unprocessable("Organization not found")
+	}
+	if err != nil {
+		return org, apierror.Wrap(err, "Could not retrieve organization")
+	}
+	if !org.Verified {
+		return org, apierror.Unprocessable("Organization is not verified")
+	}
+	return org, nil
+}
+
+// ListOrganizations lists the organizations
+//
+//	@Summary		List organizations
+//	@Description	Lists the organizations on the instance, sorted by name
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			verified	query		bool	false	"Only list verified organizations"
+//	@Success		200			{array}		Organization
+//	@Failure		500			{object}	apierror.Response	"Could not retrieve organizations"
+//	@Router			/organizations [get]
+func ListOrganizations(c *gin.Context) {
+	verifiedOnly, _ := strconv.ParseBool(c.Query("verified"))
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	list, err := repo.List(ctx, verifiedOnly)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve organizations"))
+		return
+	}
+	if list == nil {
+		list = []Organization{}
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// CreateOrganization creates an organization owned by the current user
+//
+//	@Summary		Create an organization
+//	@Description	Creates an organization with the current user as its owner. It may be linked from experience once an admin verifies it.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			organization	body		OrganizationRequest	true	"The organization's profile"
+//	@Success		201				{object}	Organization
+//	@Header			201				{string}	Location	"URL of the created organization"
+//	@Failure		400				{object}	apierror.Response	"Invalid request body"
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		500				{object}	apierror.Response	"Could not create organization"
+//	@Router			/organizations [post]
+func CreateOrganization(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req OrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	now := time.Now()
+	org := Organization{
+		ID:          utils.GenerateID(),
+		Name:        req.Name,
+		Description: req.Description,
+		Website:     req.Website,
+		Location:    req.Location,
+		CreatedBy:   user.ID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Create(ctx, org); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create organization"))
+		return
+	}
+	if err := repo.SaveMember(ctx, Member{OrganizationID: org.ID, UserID: user.ID, Role: RoleOwner, JoinedAt: now}); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create organization"))
+		return
+	}
+
+	apiversion.Location(c, org.ID)
+	c.JSON(http.StatusCreated, org)
+}
+
+// GetOrganization returns an organization's page
+//
+//	@Summary		Get an organization
+//	@Description	Returns an organization's profile and its members, in the order they joined
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			organizationid	path		string	true	"Organization ID"
+//	@Success		200				{object}	Page
+//	@Failure		404				{object}	apierror.Response	"Organization not found"
+//	@Failure		500				{object}	apierror.Response	"Could not retrieve organization"
+//	@Router			/organizations/{organizationid} [get]
+func GetOrganization(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	org, err := repo.Get(ctx, c.Param("organizationid"))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve organization"))
+		return
+	}
+	members, err := repo.Members(ctx, org.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve organization"))
+		return
+	}
+	if members == nil {
+		members = []Member{}
+	}
+
+	c.JSON(http.StatusOK, Page{Organization: org, Members: members})
+}
+
+// UpdateOrganization replaces an organization's profile
+//
+//	@Summary		Update an organization
+//	@Description	Replaces an organization's profile. Only its owners and admins may update it.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			organizationid	path		string				true	"Organization ID"
+//	@Param			organization	body		OrganizationRequest	true	"The organization's profile"
+//	@Success		200				{object}	Organization
+//	@Failure		400				{object}	apierror.Response	"Invalid request body"
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not an admin of the organization"
+//	@Failure		404				{object}	apierror.Response	"Organization not found"
+//	@Failure		500				{object}	apierror.Response	"Could not update organization"
+//	@Router			/organizations/{organizationid} [put]
+func UpdateOrganization(c *gin.Context) {
+	var req OrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	org, err := repo.Get(ctx, c.Param("organizationid"))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update organization"))
+		return
+	}
+	org.Name = req.Name
+	org.Description = req.Description
+	org.Website = req.Website
+	org.Location = req.Location
+	org.UpdatedAt = time.Now()
+	if err := repo.Save(ctx, org); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update organization"))
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// DeleteOrganization deletes an organization
+//
+//	@Summary		Delete an organization
+//	@Description	Deletes an organization and its memberships. Only its owners may delete it, after confirming their password. Experience linked to it keeps the link, which no longer resolves.
+//	@Tags			organizations
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			organizationid	path		string	true	"Organization ID"
+//	@Success		200				{object}	map[string]string
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not an owner of the organization, or the password must be confirmed"
+//	@Failure		500				{object}	apierror.Response	"Could not delete organization"
+//	@Router			/organizations/{organizationid} [delete]
+func DeleteOrganization(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Delete(ctx, c.Param("organizationid")); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete organization"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Organization deleted"})
+}
+
+// VerifyOrganization verifies an organization or withdraws its verification
+//
+//	@Summary		Verify an organization
+//	@Description	Verifies an organization, so experience may link to it, or withdraws its verification. Links made while it was verified are kept. Admin only.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			organizationid	path		string				true	"Organization ID"
+//	@Param			verification	body		VerificationRequest	true	"Whether the organization is verified"
+//	@Success		200				{object}	Organization
+//	@Failure		400				{object}	apierror.Response	"Invalid request body"
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Admin access required"
+//	@Failure		404				{object}	apierror.Response	"Organization not found"
+//	@Failure		500				{object}	apierror.Response	"Could not verify organization"
+//	@Router			/organizations/{organizationid}/verification [put]
+func VerifyOrganization(c *gin.Context) {
+	var req VerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	org, err := repo.Get(ctx, c.Param("organizationid"))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not verify organization"))
+		return
+	}
+	org.Verified = req.Verified
+	org.UpdatedAt = time.Now()
+	if err := repo.Save(ctx, org); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not verify organization"))
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// SaveMember adds a user to an organization or changes their role
+//
+//	@Summary		Add or update a member
+//	@Description	Adds a user to an organization with a role, or changes the role of a member. Owners and admins manage members, but only owners may make or unmake owners, and the last owner can't step down.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			organizationid	path		string			true	"Organization ID"
+//	@Param			userid			path		string			true	"User ID"
+//	@Param			member			body		MemberRequest	true	"The member's role"
+//	@Success		200				{object}	Member
+//	@Failure		400				{object}	apierror.Response	"Invalid request body"
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not allowed to give or take the role"
+//	@Failure		404				{object}	apierror.Response	"Organization or user not found"
+//	@Failure		409				{object}	apierror.Response	"The organization would have no owner"
+//	@Failure		500				{object}	apierror.Response	"Could not save member"
+//	@Router			/organizations/{organizationid}/members/{userid} [put]
+func SaveMember(c *gin.Context) {
+	var req MemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	organizationID, userID := c.Param("organizationid"), c.Param("userid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if _, err := users.FindByID(ctx, userID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			apierror.Abort(c, apierror.NotFound("User not found"))
+		} else {
+			apierror.Abort(c, apierror.Wrap(err, "Could not save member"))
+		}
+		return
+	}
+	member, err := repo.Member(ctx, organizationID, userID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.Wrap(err, "Could not save member"))
+		return
+	}
+	if (req.Role == RoleOwner || member.Role == RoleOwner) && !atLeast(role(c), RoleOwner) {
+		apierror.Abort(c, apierror.Forbidden("Only owners may make or unmake owners"))
+		return
+	}
+	if member.Role == RoleOwner && req.Role != RoleOwner {
+		if err := keepOwner(c, organizationID); err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+	}
+	if member.UserID == "" {
+		member = Member{OrganizationID: organizationID, UserID: userID, JoinedAt: time.Now()}
+	}
+	member.Role = req.Role
+	if err := repo.SaveMember(ctx, member); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not save member"))
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember removes a user from an organization
+//
+//	@Summary		Remove a member
+//	@Description	Removes a member from an organization. Members may leave, and owners and admins remove others, but only owners may remove owners, and the last owner can't leave.
+//	@Tags			organizations
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			organizationid	path		string	true	"Organization ID"
+//	@Param			userid			path		string	true	"User ID"
+//	@Success		200				{object}	map[string]string
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not allowed to remove the member"
+//	@Failure		404				{object}	apierror.Response	"Member not found"
+//	@Failure		409				{object}	apierror.Response	"The organization would have no owner"
+//	@Failure		500				{object}	apierror.Response	"Could not remove member"
+//	@Router			/organizations/{organizationid}/members/{userid} [delete]
+func RemoveMember(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+	organizationID, userID := c.Param("organizationid"), c.Param("userid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	member, err := repo.Member(ctx, organizationID, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not remove member"))
+		return
+	}
+	switch {
+	case userID == user.ID:
+	case member.Role == RoleOwner && !atLeast(role(c), RoleOwner):
+		apierror.Abort(c, apierror.Forbidden("Only owners may remove owners"))
+		return
+	case !atLeast(role(c), RoleAdmin):
+		apierror.Abort(c, apierror.Forbidden("Only owners and admins may remove members"))
+		return
+	}
+	if member.Role == RoleOwner {
+		if err := keepOwner(c, organizationID); err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+	}
+	if err := repo.RemoveMember(ctx, organizationID, userID); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not remove member"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+}
+
+// ListMemberships lists the organizations a user belongs to
+//
+//	@Summary		List a user's organizations
+//	@Description	Lists the memberships of a user, for showing their organizations on their profile
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Success		200		{array}		Member
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve memberships"
+//	@Router			/organizations/u/{userid} [get]
+func ListMemberships(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	list, err := repo.Memberships(ctx, c.Param("userid"))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve memberships"))
+		return
+	}
+	if list == nil {
+		list = []Member{}
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// requireRole rejects requests about the organization named by the organizationid path parameter from users
+// who are not its members with at least the role, saving the requester's role for the handler. Admins of the
+// instance act as owners of every organization. It must run after AuthMiddleware.
+func requireRole(minimum string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(auth.User)
+		if user.Admin {
+			c.Set("organizationRole", RoleOwner)
+			c.Next()
+			return
+		}
+
+		ctx, cancel := utils.DBContext(c)
+		defer cancel()
+		member, err := repo.Member(ctx, c.Param("organizationid"), user.ID)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			apierror.Abort(c, apierror.Wrap(err, "Could not retrieve membership"))
+			return
+		}
+		c.Set("organizationRole", member.Role)
+		// Members may leave whatever their role, which the handler checks
+		if !atLeast(member.Role, minimum) {
+			apierror.Abort(c, apierror.Forbidden("Not allowed to manage this organization"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// role returns the requester's role in the organization, saved by requireRole
+func role(c *gin.Context) string {
+	return c.GetString("organizationRole")
+}
+
+// keepOwner returns an error to respond with unless the organization has an owner other than the one about
+// to step down or leave
+func keepOwner(c *gin.Context, organizationID string) error {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	members, err := repo.Members(ctx, organizationID)
+	if err != nil {
+		return apierror.Wrap(err, "Could not retrieve members")
+	}
+	owners := 0
+	for _, m := range members {
+		if m.Role == RoleOwner {
+			owners++
+		}
+	}
+	if owners < 2 {
+		return apierror.Conflict("The organization must keep an owner")
+	}
+	return nil
+}
+
+// InitializeRoutes initializes the organization routes. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup) {
+	authRequired := auth.AuthMiddleware(users, true)
+
+	router.GET("", ListOrganizations)
+	router.GET("/:organizationid", GetOrganization)
+	router.GET("/u/:userid", ListMemberships)
+
+	router.POST("", authRequired, CreateOrganization)
+	router.PUT("/:organizationid", authRequired, requireRole(RoleAdmin), UpdateOrganization)
+	router.DELETE("/:organizationid", authRequired, requireRole(RoleOwner), auth.RequireSudo(), DeleteOrganization)
+	router.PUT("/:organizationid/verification", authRequired, auth.RequireAdmin(), VerifyOrganization)
+	router.PUT("/:organizationid/members/:userid", authRequired, requireRole(RoleAdmin), SaveMember)
+	router.DELETE("/:organizationid/members/:userid", authRequired, requireRole(RoleMember), RemoveMember)
+}
@@ -0,0 +1,27 @@
+package organizations
+
+import "context"
+
+// Repository stores organizations and their members
+type Repository interface {
+	// Create stores a new organization
+	Create(ctx context.Context, org Organization) error
+	// Get returns an organization, or store.ErrNotFound
+	Get(ctx context.Context, organizationID string) (Organization, error)
+	// List returns every organization, or only the verified ones, sorted by name
+	List(ctx context.Context, verifiedOnly bool) ([]Organization, error)
+	// Save replaces an organization's profile, or returns store.ErrNotFound
+	Save(ctx context.Context, org Organization) error
+	// Delete removes an organization along with its members
+	Delete(ctx context.Context, organizationID string) error
+	// Members returns the members of an organization, in the order they joined
+	Members(ctx context.Context, organizationID string) ([]Member, error)
+	// Memberships returns the organizations the user is a member of, in the order they joined
+	Memberships(ctx context.Context, userID string) ([]Member, error)
+	// Member returns the user's membership of an organization, or store.ErrNotFound
+	Member(ctx context.Context, organizationID, userID string) (Member, error)
+	// SaveMember adds a member to an organization, or changes the role of an existing member
+	SaveMember(ctx context.Context, m Member) error
+	// RemoveMember removes a member from an organization
+	RemoveMember(ctx context.Context, organizationID, userID string) error
+}
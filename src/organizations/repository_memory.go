@@ -0,0 +1,133 @@
+package organizations
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps organizations and their members in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu            sync.RWMutex
+	organizations map[string]Organization
+	members       []Member
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{organizations: map[string]Organization{}}
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, org Organization) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.organizations[org.ID]; ok {
+		return store.ErrConflict
+	}
+	r.organizations[org.ID] = org
+	return nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, organizationID string) (Organization, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	org, ok := r.organizations[organizationID]
+	if !ok {
+		return Organization{}, store.ErrNotFound
+	}
+	return org, nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, verifiedOnly bool) ([]Organization, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []Organization
+	for _, org := range r.organizations {
+		if org.Verified || !verifiedOnly {
+			list = append(list, org)
+		}
+	}
+	slices.SortFunc(list, func(a, b Organization) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+	return list, nil
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, org Organization) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.organizations[org.ID]; !ok {
+		return store.ErrNotFound
+	}
+	r.organizations[org.ID] = org
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, organizationID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.organizations, organizationID)
+	r.members = slices.DeleteFunc(r.members, func(m Member) bool { return m.OrganizationID == organizationID })
+	return nil
+}
+
+func (r *MemoryRepository) Members(ctx context.Context, organizationID string) ([]Member, error) {
+	return r.filterMembers(func(m Member) bool { return m.OrganizationID == organizationID }), nil
+}
+
+func (r *MemoryRepository) Memberships(ctx context.Context, userID string) ([]Member, error) {
+	return r.filterMembers(func(m Member) bool { return m.UserID == userID }), nil
+}
+
+func (r *MemoryRepository) Member(ctx context.Context, organizationID, userID string) (Member, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.index(organizationID, userID)
+	if i < 0 {
+		return Member{}, store.ErrNotFound
+	}
+	return r.members[i], nil
+}
+
+func (r *MemoryRepository) SaveMember(ctx context.Context, m Member) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(m.OrganizationID, m.UserID); i >= 0 {
+		r.members[i].Role = m.Role
+		return nil
+	}
+	r.members = append(r.members, m)
+	return nil
+}
+
+func (r *MemoryRepository) RemoveMember(ctx context.Context, organizationID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(organizationID, userID); i >= 0 {
+		r.members = slices.Delete(r.members, i, i+1)
+	}
+	return nil
+}
+
+// filterMembers returns the members kept by the function, in the order they joined
+func (r *MemoryRepository) filterMembers(keep func(Member) bool) []Member {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []Member
+	for _, m := range r.members {
+		if keep(m) {
+			list = append(list, m)
+		}
+	}
+	return list
+}
+
+// index returns the position of a membership in members, or -1. The caller must hold the lock.
+func (r *MemoryRepository) index(organizationID, userID string) int {
+	return slices.IndexFunc(r.members, func(m Member) bool {
+		return m.OrganizationID == organizationID && m.UserID == userID
+	})
+}
@@ -0,0 +1,114 @@
+package organizations
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores organizations in the organizations collection and their members in
+// organization_members
+type MongoRepository struct {
+	organizations *mongo.Collection
+	members       *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		organizations: db.Collection("organizations"),
+		members:       db.Collection("organization_members"),
+	}
+}
+
+func (r *MongoRepository) Create(ctx context.Context, org Organization) error {
+	_, err := r.organizations.InsertOne(ctx, org)
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	}
+	return err
+}
+
+func (r *MongoRepository) Get(ctx context.Context, organizationID string) (Organization, error) {
+	var org Organization
+	err := r.organizations.FindOne(ctx, bson.M{"_id": organizationID}).Decode(&org)
+	return org, store.MongoErr(err)
+}
+
+func (r *MongoRepository) List(ctx context.Context, verifiedOnly bool) ([]Organization, error) {
+	filter := bson.M{}
+	if verifiedOnly {
+		filter["verified"] = true
+	}
+	cursor, err := r.organizations.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var list []Organization
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *MongoRepository) Save(ctx context.Context, org Organization) error {
+	result, err := r.organizations.ReplaceOne(ctx, bson.M{"_id": org.ID}, org)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, organizationID string) error {
+	if _, err := r.organizations.DeleteOne(ctx, bson.M{"_id": organizationID}); err != nil {
+		return err
+	}
+	_, err := r.members.DeleteMany(ctx, bson.M{"organization_id": organizationID})
+	return err
+}
+
+func (r *MongoRepository) Members(ctx context.Context, organizationID string) ([]Member, error) {
+	return r.findMembers(ctx, bson.M{"organization_id": organizationID})
+}
+
+func (r *MongoRepository) Memberships(ctx context.Context, userID string) ([]Member, error) {
+	return r.findMembers(ctx, bson.M{"user_id": userID})
+}
+
+func (r *MongoRepository) Member(ctx context.Context, organizationID, userID string) (Member, error) {
+	var m Member
+	err := r.members.FindOne(ctx, bson.M{"organization_id": organizationID, "user_id": userID}).Decode(&m)
+	return m, store.MongoErr(err)
+}
+
+func (r *MongoRepository) SaveMember(ctx context.Context, m Member) error {
+	_, err := r.members.UpdateOne(ctx,
+		bson.M{"organization_id": m.OrganizationID, "user_id": m.UserID},
+		bson.M{"$set": bson.M{"role": m.Role}, "$setOnInsert": bson.M{"joined_at": m.JoinedAt}},
+		options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) RemoveMember(ctx context.Context, organizationID, userID string) error {
+	_, err := r.members.DeleteOne(ctx, bson.M{"organization_id": organizationID, "user_id": userID})
+	return err
+}
+
+func (r *MongoRepository) findMembers(ctx context.Context, filter bson.M) ([]Member, error) {
+	cursor, err := r.members.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "joined_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var list []Member
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,118 @@
+package organizations
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	organizationColumns = "id, name, description, website, location, verified, created_by, created_at, updated_at"
+	memberColumns       = "organization_id, user_id, role, joined_at"
+)
+
+// PostgresRepository stores organizations in the organizations table and their members in
+// organization_members
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, org Organization) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO organizations ("+organizationColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		org.ID, org.Name, org.Description, org.Website, org.Location, org.Verified, org.CreatedBy, org.CreatedAt, org.UpdatedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, organizationID string) (Organization, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+organizationColumns+" FROM organizations WHERE id = $1", organizationID)
+	if err != nil {
+		return Organization{}, err
+	}
+	org, err := pgx.CollectExactlyOneRow(rows, scanOrganization)
+	return org, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) List(ctx context.Context, verifiedOnly bool) ([]Organization, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+organizationColumns+" FROM organizations WHERE verified OR NOT $1 ORDER BY name, id", verifiedOnly)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanOrganization)
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, org Organization) error {
+	tag, err := r.pool.Exec(ctx, "UPDATE organizations SET name = $2, description = $3, website = $4, location = $5, verified = $6, updated_at = $7 WHERE id = $1",
+		org.ID, org.Name, org.Description, org.Website, org.Location, org.Verified, org.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, organizationID string) error {
+	// Members are removed along with the organization by their foreign key
+	_, err := r.pool.Exec(ctx, "DELETE FROM organizations WHERE id = $1", organizationID)
+	return err
+}
+
+func (r *PostgresRepository) Members(ctx context.Context, organizationID string) ([]Member, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+memberColumns+" FROM organization_members WHERE organization_id = $1 ORDER BY joined_at", organizationID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanMember)
+}
+
+func (r *PostgresRepository) Memberships(ctx context.Context, userID string) ([]Member, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+memberColumns+" FROM organization_members WHERE user_id = $1 ORDER BY joined_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanMember)
+}
+
+func (r *PostgresRepository) Member(ctx context.Context, organizationID, userID string) (Member, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+memberColumns+" FROM organization_members WHERE organization_id = $1 AND user_id = $2", organizationID, userID)
+	if err != nil {
+		return Member{}, err
+	}
+	m, err := pgx.CollectExactlyOneRow(rows, scanMember)
+	return m, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) SaveMember(ctx context.Context, m Member) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO organization_members ("+memberColumns+") VALUES ($1, $2, $3, $4) "+
+		"ON CONFLICT (organization_id, user_id) DO UPDATE SET role = EXCLUDED.role",
+		m.OrganizationID, m.UserID, m.Role, m.JoinedAt)
+	return err
+}
+
+func (r *PostgresRepository) RemoveMember(ctx context.Context, organizationID, userID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2", organizationID, userID)
+	return err
+}
+
+// scanOrganization reads a row selected with organizationColumns
+func scanOrganization(row pgx.CollectableRow) (Organization, error) {
+	var org Organization
+	err := row.Scan(&org.ID, &org.Name, &org.Description, &org.Website, &org.Location, &org.Verified, &org.CreatedBy, &org.CreatedAt, &org.UpdatedAt)
+	return org, err
+}
+
+// scanMember reads a row selected with memberColumns
+func scanMember(row pgx.CollectableRow) (Member, error) {
+	var m Member
+	err := row.Scan(&m.OrganizationID, &m.UserID, &m.Role, &m.JoinedAt)
+	return m, err
+}
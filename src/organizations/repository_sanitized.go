@@ -0,0 +1,30 @@
+package organizations
+
+import (
+	"context"
+
+	"profile-api/sanitize"
+)
+
+// SanitizedRepository cleans the user-supplied text of organizations before storing them
+type SanitizedRepository struct {
+	Repository
+}
+
+// NewSanitizedRepository wraps the repository to clean organizations with the configured sanitize policies
+func NewSanitizedRepository(r Repository) *SanitizedRepository {
+	return &SanitizedRepository{Repository: r}
+}
+
+func (r *SanitizedRepository) Create(ctx context.Context, org Organization) error {
+	return r.Repository.Create(ctx, sanitized(org))
+}
+
+func (r *SanitizedRepository) Save(ctx context.Context, org Organization) error {
+	return r.Repository.Save(ctx, sanitized(org))
+}
+
+func sanitized(org Organization) Organization {
+	org.Description = sanitize.Field(sanitize.OrganizationDescription, org.Description)
+	return org
+}
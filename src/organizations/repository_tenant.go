@@ -0,0 +1,57 @@
+package organizations
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, org Organization) error {
+	return r.repos.For(ctx).Create(ctx, org)
+}
+
+func (r *TenantRepository) Get(ctx context.Context, organizationID string) (Organization, error) {
+	return r.repos.For(ctx).Get(ctx, organizationID)
+}
+
+func (r *TenantRepository) List(ctx context.Context, verifiedOnly bool) ([]Organization, error) {
+	return r.repos.For(ctx).List(ctx, verifiedOnly)
+}
+
+func (r *TenantRepository) Save(ctx context.Context, org Organization) error {
+	return r.repos.For(ctx).Save(ctx, org)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, organizationID string) error {
+	return r.repos.For(ctx).Delete(ctx, organizationID)
+}
+
+func (r *TenantRepository) Members(ctx context.Context, organizationID string) ([]Member, error) {
+	return r.repos.For(ctx).Members(ctx, organizationID)
+}
+
+func (r *TenantRepository) Memberships(ctx context.Context, userID string) ([]Member, error) {
+	return r.repos.For(ctx).Memberships(ctx, userID)
+}
+
+func (r *TenantRepository) Member(ctx context.Context, organizationID, userID string) (Member, error) {
+	return r.repos.For(ctx).Member(ctx, organizationID, userID)
+}
+
+func (r *TenantRepository) SaveMember(ctx context.Context, m Member) error {
+	return r.repos.For(ctx).SaveMember(ctx, m)
+}
+
+func (r *TenantRepository) RemoveMember(ctx context.Context, organizationID, userID string) error {
+	return r.repos.For(ctx).RemoveMember(ctx, organizationID, userID)
+}
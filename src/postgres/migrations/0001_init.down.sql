@@ -0,0 +1,8 @@
+DROP TABLE subscriptions;
+DROP TABLE journal;
+DROP TABLE skills;
+DROP TABLE certificates;
+DROP TABLE qualifications;
+DROP TABLE experience;
+DROP TABLE profiles;
+DROP TABLE users;
@@ -0,0 +1,103 @@
+CREATE TABLE users (
+    id       TEXT PRIMARY KEY,
+    name     TEXT NOT NULL,
+    email    TEXT NOT NULL UNIQUE,
+    password TEXT NOT NULL
+);
+
+CREATE TABLE profiles (
+    user_id     TEXT PRIMARY KEY,
+    name        TEXT,
+    email       TEXT,
+    number      TEXT,
+    bio         TEXT,
+    profile_img TEXT,
+    interests   TEXT,
+    domain      TEXT,
+    updated_at  TIMESTAMPTZ
+);
+
+CREATE TABLE experience (
+    user_id       TEXT NOT NULL,
+    experience_id TEXT NOT NULL,
+    company       TEXT NOT NULL DEFAULT '',
+    position      TEXT NOT NULL DEFAULT '',
+    start_date    TEXT NOT NULL DEFAULT '',
+    end_date      TEXT NOT NULL DEFAULT '',
+    description   TEXT NOT NULL DEFAULT '',
+    notes         TEXT NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, experience_id)
+);
+
+CREATE TABLE qualifications (
+    user_id          TEXT NOT NULL,
+    qualification_id TEXT NOT NULL,
+    title            TEXT NOT NULL DEFAULT '',
+    institution      TEXT NOT NULL DEFAULT '',
+    start_date       TEXT NOT NULL DEFAULT '',
+    end_date         TEXT NOT NULL DEFAULT '',
+    description      TEXT NOT NULL DEFAULT '',
+    cert_image       BYTEA,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, qualification_id)
+);
+
+CREATE TABLE certificates (
+    user_id        TEXT NOT NULL,
+    certificate_id TEXT NOT NULL,
+    title          TEXT NOT NULL DEFAULT '',
+    institution    TEXT NOT NULL DEFAULT '',
+    start_date     TEXT NOT NULL DEFAULT '',
+    end_date       TEXT NOT NULL DEFAULT '',
+    description    TEXT NOT NULL DEFAULT '',
+    cert_image     BYTEA,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, certificate_id)
+);
+
+CREATE TABLE skills (
+    user_id           TEXT NOT NULL,
+    skill_id          TEXT NOT NULL,
+    name              TEXT NOT NULL DEFAULT '',
+    proficiency_level TEXT NOT NULL DEFAULT '',
+    started_at        TEXT NOT NULL DEFAULT '',
+    last_used         TEXT NOT NULL DEFAULT '',
+    description       TEXT NOT NULL DEFAULT '',
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, skill_id)
+);
+
+CREATE TABLE journal (
+    journal_id        TEXT PRIMARY KEY,
+    user_id           TEXT NOT NULL,
+    version           INTEGER NOT NULL,
+    entries           JSONB NOT NULL DEFAULT '[]',
+    status            TEXT NOT NULL,
+    taxonomy          JSONB NOT NULL DEFAULT '{}',
+    summary           TEXT NOT NULL DEFAULT '',
+    created_at        TIMESTAMPTZ NOT NULL,
+    updated_at        TIMESTAMPTZ NOT NULL,
+    status_changed_by TEXT,
+    status_changed_at TIMESTAMPTZ,
+    status_history    JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX journal_user_id ON journal (user_id);
+CREATE INDEX journal_status_updated_at ON journal (status, updated_at);
+CREATE INDEX journal_taxonomy ON journal USING GIN (taxonomy jsonb_path_ops);
+
+CREATE TABLE subscriptions (
+    subscription_id   TEXT PRIMARY KEY,
+    user_id           TEXT NOT NULL,
+    email             TEXT NOT NULL,
+    frequency         TEXT NOT NULL,
+    confirmed         BOOLEAN NOT NULL DEFAULT FALSE,
+    confirm_token     TEXT NOT NULL UNIQUE,
+    unsubscribe_token TEXT NOT NULL UNIQUE,
+    created_at        TIMESTAMPTZ NOT NULL,
+    confirmed_at      TIMESTAMPTZ,
+    last_sent_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX subscriptions_user_email ON subscriptions (user_id, email);
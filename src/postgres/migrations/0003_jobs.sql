@@ -0,0 +1,17 @@
+CREATE TABLE jobs (
+    id           TEXT PRIMARY KEY,
+    type         TEXT NOT NULL,
+    payload      JSONB NOT NULL,
+    status       TEXT NOT NULL,
+    attempts     INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    run_at       TIMESTAMPTZ NOT NULL,
+    locked_until TIMESTAMPTZ,
+    last_error   TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL,
+    finished_at  TIMESTAMPTZ
+);
+
+CREATE INDEX jobs_status_run_at ON jobs (status, run_at);
+CREATE INDEX jobs_created_at ON jobs (created_at);
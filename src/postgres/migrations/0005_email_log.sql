@@ -0,0 +1,13 @@
+CREATE TABLE email_log (
+    id        TEXT PRIMARY KEY,
+    user_id   TEXT NOT NULL,
+    recipient TEXT NOT NULL,
+    subject   TEXT NOT NULL,
+    template  TEXT NOT NULL DEFAULT '',
+    provider  TEXT NOT NULL,
+    status    TEXT NOT NULL,
+    error     TEXT NOT NULL DEFAULT '',
+    sent_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX email_log_user_sent ON email_log (user_id, sent_at DESC);
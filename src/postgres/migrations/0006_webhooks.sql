@@ -0,0 +1,28 @@
+CREATE TABLE webhooks (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    url        TEXT NOT NULL,
+    events     TEXT[] NOT NULL,
+    global     BOOLEAN NOT NULL DEFAULT FALSE,
+    secret     TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX webhooks_user ON webhooks (user_id);
+
+CREATE TABLE webhook_deliveries (
+    id              TEXT PRIMARY KEY,
+    webhook_id      TEXT NOT NULL,
+    user_id         TEXT NOT NULL,
+    event           TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    status          TEXT NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    last_error      TEXT NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL,
+    updated_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX webhook_deliveries_webhook_created ON webhook_deliveries (webhook_id, created_at DESC);
+CREATE INDEX webhook_deliveries_created ON webhook_deliveries (created_at);
@@ -0,0 +1 @@
+ALTER TABLE jobs ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+DROP INDEX users_created_at;
+ALTER TABLE users DROP COLUMN reset_token;
+ALTER TABLE users DROP COLUMN disabled;
+ALTER TABLE users DROP COLUMN created_at;
@@ -0,0 +1,6 @@
+-- Users created before this migration have no recorded signup time
+ALTER TABLE users ADD COLUMN created_at TIMESTAMPTZ;
+ALTER TABLE users ALTER COLUMN created_at SET DEFAULT now();
+ALTER TABLE users ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN reset_token TEXT UNIQUE;
+CREATE INDEX users_created_at ON users (created_at DESC);
@@ -0,0 +1,14 @@
+CREATE TABLE audit_log (
+    id          TEXT PRIMARY KEY,
+    user_id     TEXT NOT NULL,
+    actor_id    TEXT NOT NULL DEFAULT '',
+    action      TEXT NOT NULL,
+    resource    TEXT NOT NULL,
+    resource_id TEXT NOT NULL,
+    changes     JSONB,
+    request_id  TEXT NOT NULL DEFAULT '',
+    time        TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX audit_log_user_time ON audit_log (user_id, time DESC);
+CREATE INDEX audit_log_actor_time ON audit_log (actor_id, time DESC);
@@ -0,0 +1,18 @@
+CREATE TABLE search_index (
+    id           TEXT PRIMARY KEY,
+    kind         TEXT NOT NULL,
+    user_id      TEXT NOT NULL,
+    resource_id  TEXT NOT NULL,
+    title        TEXT NOT NULL,
+    body         TEXT NOT NULL,
+    tags         TEXT[] NOT NULL DEFAULT '{}',
+    skills       TEXT[] NOT NULL DEFAULT '{}',
+    institutions TEXT[] NOT NULL DEFAULT '{}',
+    updated_at   TIMESTAMPTZ NOT NULL,
+    document     TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', title), 'A') || setweight(to_tsvector('english', body), 'B')
+    ) STORED
+);
+
+CREATE INDEX search_index_user ON search_index (user_id);
+CREATE INDEX search_index_document ON search_index USING GIN (document);
@@ -0,0 +1,8 @@
+CREATE TABLE feature_flags (
+    name       TEXT PRIMARY KEY,
+    enabled    BOOLEAN NOT NULL,
+    percentage INTEGER NOT NULL,
+    users      TEXT[] NOT NULL DEFAULT '{}',
+    updated_by TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ
+);
@@ -0,0 +1,2 @@
+ALTER TABLE journal DROP COLUMN revision;
+ALTER TABLE profiles DROP COLUMN revision;
@@ -0,0 +1,2 @@
+ALTER TABLE profiles ADD COLUMN revision INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE journal ADD COLUMN revision INTEGER NOT NULL DEFAULT 0;
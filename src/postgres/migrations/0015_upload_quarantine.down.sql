@@ -0,0 +1,3 @@
+ALTER TABLE certificates DROP COLUMN cert_image_quarantined;
+ALTER TABLE qualifications DROP COLUMN cert_image_quarantined;
+ALTER TABLE profiles DROP COLUMN image_quarantined;
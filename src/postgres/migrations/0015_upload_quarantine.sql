@@ -0,0 +1,3 @@
+ALTER TABLE profiles ADD COLUMN image_quarantined TEXT NOT NULL DEFAULT '';
+ALTER TABLE qualifications ADD COLUMN cert_image_quarantined TEXT NOT NULL DEFAULT '';
+ALTER TABLE certificates ADD COLUMN cert_image_quarantined TEXT NOT NULL DEFAULT '';
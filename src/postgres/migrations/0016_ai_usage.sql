@@ -0,0 +1,7 @@
+CREATE TABLE ai_usage (
+    user_id    TEXT NOT NULL,
+    period     TEXT NOT NULL,
+    requests   INTEGER NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, period)
+);
@@ -0,0 +1,14 @@
+CREATE TABLE search_vectors (
+    id          TEXT PRIMARY KEY,
+    kind        TEXT NOT NULL,
+    user_id     TEXT NOT NULL,
+    resource_id TEXT NOT NULL,
+    title       TEXT NOT NULL,
+    snippet     TEXT NOT NULL,
+    hash        TEXT NOT NULL,
+    vals        REAL[] NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX search_vectors_user ON search_vectors (user_id);
+CREATE INDEX search_vectors_kind ON search_vectors (kind);
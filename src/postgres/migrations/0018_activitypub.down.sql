@@ -0,0 +1,2 @@
+DROP TABLE activitypub_followers;
+DROP TABLE activitypub_keys;
@@ -0,0 +1,15 @@
+CREATE TABLE activitypub_keys (
+    user_id     TEXT PRIMARY KEY,
+    public_key  TEXT NOT NULL,
+    private_key TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE activitypub_followers (
+    user_id      TEXT NOT NULL,
+    actor_id     TEXT NOT NULL,
+    inbox        TEXT NOT NULL,
+    shared_inbox TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, actor_id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE billing_accounts;
+ALTER TABLE users DROP COLUMN plan;
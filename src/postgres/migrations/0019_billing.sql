@@ -0,0 +1,12 @@
+-- An empty plan is the configured default plan
+ALTER TABLE users ADD COLUMN plan TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE billing_accounts (
+    user_id              TEXT PRIMARY KEY,
+    customer_id          TEXT NOT NULL UNIQUE,
+    subscription_id      TEXT NOT NULL DEFAULT '',
+    status               TEXT NOT NULL DEFAULT '',
+    current_period_end   TIMESTAMPTZ,
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at           TIMESTAMPTZ NOT NULL
+);
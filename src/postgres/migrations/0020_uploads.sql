@@ -0,0 +1,11 @@
+CREATE TABLE uploads (
+    id          TEXT PRIMARY KEY,
+    user_id     TEXT NOT NULL,
+    resource    TEXT NOT NULL,
+    resource_id TEXT NOT NULL,
+    bytes       BIGINT NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX uploads_user_id ON uploads (user_id);
+CREATE INDEX uploads_resource ON uploads (resource, resource_id);
@@ -0,0 +1,5 @@
+ALTER TABLE skills DROP COLUMN visibility;
+ALTER TABLE certificates DROP COLUMN visibility;
+ALTER TABLE qualifications DROP COLUMN visibility;
+ALTER TABLE experience DROP COLUMN visibility;
+ALTER TABLE profiles DROP COLUMN visibility;
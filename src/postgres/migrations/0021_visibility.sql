@@ -0,0 +1,6 @@
+-- Maps the JSON names of fields to who they are shown to, NULL when every field is shown by default
+ALTER TABLE profiles ADD COLUMN visibility JSONB;
+ALTER TABLE experience ADD COLUMN visibility JSONB;
+ALTER TABLE qualifications ADD COLUMN visibility JSONB;
+ALTER TABLE certificates ADD COLUMN visibility JSONB;
+ALTER TABLE skills ADD COLUMN visibility JSONB;
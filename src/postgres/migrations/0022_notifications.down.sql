@@ -0,0 +1,3 @@
+DROP INDEX certificates_end_date;
+DROP TABLE notification_preferences;
+DROP TABLE notifications;
@@ -0,0 +1,20 @@
+CREATE TABLE notifications (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    kind       TEXT NOT NULL,
+    message    TEXT NOT NULL,
+    data       JSONB,
+    read_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX notifications_user_created ON notifications (user_id, created_at DESC);
+CREATE INDEX notifications_created ON notifications (created_at);
+
+CREATE TABLE notification_preferences (
+    user_id    TEXT PRIMARY KEY,
+    kinds      JSONB NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX certificates_end_date ON certificates (end_date COLLATE "C");
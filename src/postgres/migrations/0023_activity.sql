@@ -0,0 +1,9 @@
+CREATE TABLE activity (
+    user_id     TEXT NOT NULL,
+    kind        TEXT NOT NULL,
+    resource_id TEXT NOT NULL,
+    time        TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, kind, resource_id)
+);
+
+CREATE INDEX activity_user_time ON activity (user_id, time DESC);
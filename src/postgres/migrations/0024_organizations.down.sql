@@ -0,0 +1,3 @@
+ALTER TABLE experience DROP COLUMN organization_id;
+DROP TABLE organization_members;
+DROP TABLE organizations;
@@ -0,0 +1,2 @@
+DROP TABLE recommendation_invitations;
+DROP TABLE recommendations;
@@ -0,0 +1,28 @@
+CREATE TABLE recommendations (
+    id           TEXT PRIMARY KEY,
+    user_id      TEXT NOT NULL,
+    author_id    TEXT NOT NULL DEFAULT '',
+    author_name  TEXT NOT NULL,
+    author_email TEXT NOT NULL DEFAULT '',
+    relationship TEXT NOT NULL,
+    content      TEXT NOT NULL,
+    status       TEXT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX recommendations_user_created ON recommendations (user_id, created_at DESC);
+CREATE INDEX recommendations_author ON recommendations (author_id);
+
+CREATE TABLE recommendation_invitations (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    name       TEXT NOT NULL,
+    email      TEXT NOT NULL,
+    token      TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ
+);
+
+CREATE INDEX recommendation_invitations_user_created ON recommendation_invitations (user_id, created_at DESC);
@@ -0,0 +1,13 @@
+CREATE TABLE awards (
+    user_id           TEXT NOT NULL,
+    award_id          TEXT NOT NULL,
+    title             TEXT NOT NULL DEFAULT '',
+    issuer            TEXT NOT NULL DEFAULT '',
+    award_date        TEXT NOT NULL DEFAULT '',
+    description       TEXT NOT NULL DEFAULT '',
+    image             BYTEA,
+    image_quarantined TEXT NOT NULL DEFAULT '',
+    visibility        JSONB,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, award_id)
+);
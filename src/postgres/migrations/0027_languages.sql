@@ -0,0 +1,10 @@
+CREATE TABLE languages (
+    user_id     TEXT NOT NULL,
+    language_id TEXT NOT NULL,
+    name        TEXT NOT NULL DEFAULT '',
+    code        TEXT NOT NULL DEFAULT '',
+    proficiency TEXT NOT NULL DEFAULT '',
+    visibility  JSONB,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, language_id)
+);
@@ -0,0 +1,6 @@
+ALTER TABLE search_index DROP COLUMN remote;
+ALTER TABLE search_index DROP COLUMN locations;
+ALTER TABLE search_index DROP COLUMN roles;
+ALTER TABLE search_index DROP COLUMN available_from;
+ALTER TABLE search_index DROP COLUMN availability;
+ALTER TABLE profiles DROP COLUMN availability;
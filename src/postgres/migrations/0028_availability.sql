@@ -0,0 +1,7 @@
+ALTER TABLE profiles ADD COLUMN availability JSONB;
+-- Documents indexed before this migration are rebuilt with their availability by the next reindex
+ALTER TABLE search_index ADD COLUMN availability TEXT NOT NULL DEFAULT '';
+ALTER TABLE search_index ADD COLUMN available_from TEXT NOT NULL DEFAULT '';
+ALTER TABLE search_index ADD COLUMN roles TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE search_index ADD COLUMN locations TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE search_index ADD COLUMN remote TEXT NOT NULL DEFAULT '';
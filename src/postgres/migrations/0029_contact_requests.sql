@@ -0,0 +1,17 @@
+CREATE TABLE contact_requests (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    name       TEXT NOT NULL,
+    email      TEXT NOT NULL,
+    subject    TEXT NOT NULL DEFAULT '',
+    message    TEXT NOT NULL,
+    status     TEXT NOT NULL,
+    spam_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    replied_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX contact_requests_user_created ON contact_requests (user_id, created_at DESC);
+CREATE INDEX contact_requests_user_sender ON contact_requests (user_id, email, created_at DESC);
+CREATE INDEX contact_requests_status_updated ON contact_requests (status, updated_at);
@@ -0,0 +1,3 @@
+DROP TABLE profile_visitors;
+DROP TABLE privacy_settings;
+DROP TABLE blocks;
@@ -0,0 +1,24 @@
+CREATE TABLE blocks (
+    user_id    TEXT NOT NULL,
+    blocked_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, blocked_id)
+);
+
+CREATE INDEX blocks_blocked ON blocks (blocked_id);
+
+CREATE TABLE privacy_settings (
+    user_id    TEXT PRIMARY KEY,
+    restricted BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE profile_visitors (
+    id            TEXT PRIMARY KEY,
+    user_id       TEXT NOT NULL,
+    email         TEXT NOT NULL,
+    token         TEXT NOT NULL UNIQUE,
+    created_at    TIMESTAMPTZ NOT NULL,
+    last_visit_at TIMESTAMPTZ,
+    UNIQUE (user_id, email)
+);
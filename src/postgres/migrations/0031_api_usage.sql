@@ -0,0 +1,11 @@
+CREATE TABLE api_usage (
+    user_id      TEXT NOT NULL,
+    token        TEXT NOT NULL,
+    day          TEXT NOT NULL,
+    requests     BIGINT NOT NULL,
+    errors       BIGINT NOT NULL,
+    last_used_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, token, day)
+);
+
+CREATE INDEX api_usage_day ON api_usage (day);
@@ -0,0 +1,11 @@
+CREATE TABLE changelog (
+    id          TEXT PRIMARY KEY,
+    user_id     TEXT NOT NULL,
+    seq         BIGINT NOT NULL,
+    action      TEXT NOT NULL,
+    resource    TEXT NOT NULL,
+    resource_id TEXT NOT NULL,
+    changes     JSONB,
+    time        TIMESTAMPTZ NOT NULL,
+    UNIQUE (user_id, seq)
+);
@@ -0,0 +1,15 @@
+CREATE TABLE domains (
+    id          TEXT PRIMARY KEY,
+    user_id     TEXT NOT NULL,
+    name        TEXT NOT NULL,
+    status      TEXT NOT NULL,
+    token       TEXT NOT NULL,
+    last_error  TEXT NOT NULL DEFAULT '',
+    checked_at  TIMESTAMPTZ,
+    verified_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL,
+    UNIQUE (user_id, name)
+);
+
+CREATE UNIQUE INDEX domains_verified_name ON domains (name) WHERE status = 'verified';
+CREATE INDEX domains_status_created ON domains (status, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE hidden_content;
+DROP TABLE reports;
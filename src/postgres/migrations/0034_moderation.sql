@@ -0,0 +1,33 @@
+CREATE TABLE reports (
+    id          TEXT PRIMARY KEY,
+    target_type TEXT NOT NULL,
+    target_id   TEXT NOT NULL,
+    owner_id    TEXT NOT NULL,
+    reporter_id TEXT NOT NULL DEFAULT '',
+    reporter    TEXT NOT NULL,
+    reason      TEXT NOT NULL,
+    details     TEXT NOT NULL DEFAULT '',
+    status      TEXT NOT NULL,
+    action      TEXT NOT NULL DEFAULT '',
+    resolved_by TEXT NOT NULL DEFAULT '',
+    resolved_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX reports_open_reporter ON reports (target_type, target_id, reporter) WHERE status = 'open';
+CREATE INDEX reports_status_created ON reports (status, created_at);
+CREATE INDEX reports_owner_id ON reports (owner_id);
+
+CREATE TABLE hidden_content (
+    target_type TEXT NOT NULL,
+    target_id   TEXT NOT NULL,
+    owner_id    TEXT NOT NULL,
+    automatic   BOOLEAN NOT NULL DEFAULT FALSE,
+    unpublished BOOLEAN NOT NULL DEFAULT FALSE,
+    hidden_by   TEXT NOT NULL DEFAULT '',
+    note        TEXT NOT NULL DEFAULT '',
+    hidden_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (target_type, target_id)
+);
+
+CREATE INDEX hidden_content_owner_id ON hidden_content (owner_id);
@@ -0,0 +1 @@
+DROP TABLE journal_files;
@@ -0,0 +1,12 @@
+CREATE TABLE journal_files (
+    id          TEXT PRIMARY KEY,
+    user_id     TEXT NOT NULL,
+    journal_id  TEXT NOT NULL,
+    url         TEXT NOT NULL,
+    name        TEXT NOT NULL,
+    bytes       BIGINT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL,
+    orphaned_at TIMESTAMPTZ
+);
+
+CREATE INDEX journal_files_created_at ON journal_files (created_at);
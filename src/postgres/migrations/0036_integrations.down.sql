@@ -0,0 +1,3 @@
+DROP TABLE integration_receipts;
+DROP TABLE integration_mappings;
+DROP TABLE integrations;
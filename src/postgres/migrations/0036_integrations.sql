@@ -0,0 +1,37 @@
+CREATE TABLE integrations (
+    id          TEXT PRIMARY KEY,
+    name        TEXT NOT NULL,
+    institution TEXT NOT NULL DEFAULT '',
+    match_email BOOLEAN NOT NULL DEFAULT FALSE,
+    secret      TEXT NOT NULL,
+    created_by  TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE integration_mappings (
+    integration_id TEXT NOT NULL REFERENCES integrations (id) ON DELETE CASCADE,
+    external_id    TEXT NOT NULL,
+    user_id        TEXT NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (integration_id, external_id)
+);
+
+CREATE INDEX integration_mappings_user_id ON integration_mappings (user_id);
+
+CREATE TABLE integration_receipts (
+    id               TEXT PRIMARY KEY,
+    integration_id   TEXT NOT NULL REFERENCES integrations (id) ON DELETE CASCADE,
+    event_id         TEXT NOT NULL,
+    type             TEXT NOT NULL,
+    external_user_id TEXT NOT NULL,
+    status           TEXT NOT NULL,
+    user_id          TEXT NOT NULL DEFAULT '',
+    certificate_id   TEXT NOT NULL DEFAULT '',
+    error            TEXT NOT NULL DEFAULT '',
+    payload          JSONB NOT NULL,
+    received_at      TIMESTAMPTZ NOT NULL,
+    processed_at     TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX integration_receipts_integration_received ON integration_receipts (integration_id, received_at DESC);
+CREATE INDEX integration_receipts_user_id ON integration_receipts (user_id);
@@ -0,0 +1 @@
+DROP INDEX journal_files_journal_id;
@@ -0,0 +1 @@
+CREATE INDEX journal_files_journal_id ON journal_files (journal_id);
@@ -0,0 +1,8 @@
+CREATE TABLE journal_settings (
+    user_id            TEXT PRIMARY KEY,
+    status             TEXT NOT NULL,
+    taxonomy           JSONB NOT NULL,
+    process_on_publish BOOLEAN NOT NULL DEFAULT FALSE,
+    comments           TEXT NOT NULL,
+    updated_at         TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,2 @@
+ALTER TABLE certificates DROP COLUMN restricted;
+ALTER TABLE certificates DROP COLUMN archived_at;
@@ -0,0 +1,2 @@
+ALTER TABLE certificates ADD COLUMN archived_at TIMESTAMPTZ;
+ALTER TABLE certificates ADD COLUMN restricted TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,30 @@
+CREATE TABLE share_packages (
+    id             TEXT PRIMARY KEY,
+    user_id        TEXT NOT NULL,
+    recipient      TEXT NOT NULL,
+    message        TEXT NOT NULL DEFAULT '',
+    experience     TEXT[] NOT NULL DEFAULT '{}',
+    qualifications TEXT[] NOT NULL DEFAULT '{}',
+    certificates   TEXT[] NOT NULL DEFAULT '{}',
+    pdf            BOOLEAN NOT NULL DEFAULT FALSE,
+    token_hash     TEXT NOT NULL UNIQUE,
+    created_at     TIMESTAMPTZ NOT NULL,
+    expires_at     TIMESTAMPTZ NOT NULL,
+    revoked_at     TIMESTAMPTZ,
+    views          INTEGER NOT NULL DEFAULT 0,
+    last_viewed_at TIMESTAMPTZ
+);
+
+CREATE INDEX share_packages_user_created ON share_packages (user_id, created_at DESC);
+
+CREATE TABLE share_views (
+    id         TEXT PRIMARY KEY,
+    package_id TEXT NOT NULL REFERENCES share_packages (id) ON DELETE CASCADE,
+    user_id    TEXT NOT NULL,
+    kind       TEXT NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    viewed_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX share_views_package_viewed ON share_views (package_id, viewed_at DESC);
+CREATE INDEX share_views_user_id ON share_views (user_id);
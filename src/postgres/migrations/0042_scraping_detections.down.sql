@@ -0,0 +1 @@
+DROP TABLE scraping_detections;
@@ -0,0 +1,13 @@
+CREATE TABLE scraping_detections (
+    id              TEXT PRIMARY KEY,
+    client          TEXT NOT NULL,
+    pattern         TEXT NOT NULL,
+    route           TEXT NOT NULL,
+    requests        INTEGER NOT NULL,
+    user_agent      TEXT NOT NULL DEFAULT '',
+    action          TEXT NOT NULL,
+    detected_at     TIMESTAMPTZ NOT NULL,
+    throttled_until TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX scraping_detections_detected_at ON scraping_detections (detected_at DESC);
@@ -0,0 +1,210 @@
+// Package postgres connects to PostgreSQL and keeps its schema up to date for the Postgres storage backend.
+package postgres
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Connect opens a connection pool to the database and checks that it is reachable
+func Connect(ctx context.Context, url string) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Postgres pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("error pinging Postgres: %w", err)
+	}
+	slog.Info("Connected to Postgres")
+	return pool, nil
+}
+
+// migration is a migration script and the script rolling it back, which is empty for irreversible migrations
+type migration struct {
+	version string
+	up      string
+	down    string
+}
+
+// loadMigrations reads the embedded migrations in version order. A migration NNNN_name.sql is rolled back
+// by NNNN_name.down.sql when there is one.
+func loadMigrations() ([]migration, error) {
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	var ms []migration
+	for _, name := range names {
+		if strings.HasSuffix(name, ".down.sql") {
+			continue
+		}
+		up, err := migrations.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		down, err := migrations.ReadFile(strings.TrimSuffix(name, ".sql") + ".down.sql")
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		version := strings.TrimSuffix(strings.TrimPrefix(name, "migrations/"), ".sql")
+		ms = append(ms, migration{version: version, up: string(up), down: string(down)})
+	}
+	return ms, nil
+}
+
+// createMigrationsTable creates the table recording which migrations have been applied
+func createMigrationsTable(ctx context.Context, pool *pgxpool.Pool) error {
+	_, err := pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// lockMigrations serialises instances migrating the database at the same time, until tx ends
+func lockMigrations(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, "LOCK TABLE schema_migrations IN EXCLUSIVE MODE")
+	return err
+}
+
+// isApplied reports whether the migration has been applied
+func isApplied(ctx context.Context, tx pgx.Tx, version string) (bool, error) {
+	var applied bool
+	err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", version).Scan(&applied)
+	return applied, err
+}
+
+// Migrate applies every migration that has not been applied yet, in version order.
+// Each migration runs in its own transaction and is recorded in the schema_migrations table.
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	if err := createMigrationsTable(ctx, pool); err != nil {
+		return err
+	}
+	ms, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range ms {
+		err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+			if err := lockMigrations(ctx, tx); err != nil {
+				return err
+			}
+			applied, err := isApplied(ctx, tx, m.version)
+			if err != nil || applied {
+				return err
+			}
+			if _, err := tx.Exec(ctx, m.up); err != nil {
+				return err
+			}
+			_, err = tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", m.version)
+			if err == nil {
+				slog.Info("Applied Postgres migration", "version", m.version)
+			}
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("error applying migration %s: %w", m.version, err)
+		}
+	}
+	return nil
+}
+
+// Rollback rolls back every applied migration numbered after the target, newest first, each in its own
+// transaction. It stops at the first migration that cannot be rolled back.
+func Rollback(ctx context.Context, pool *pgxpool.Pool, target string) error {
+	if err := createMigrationsTable(ctx, pool); err != nil {
+		return err
+	}
+	ms, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	for i := len(ms) - 1; i >= 0; i-- {
+		m := ms[i]
+		if !(store.Migration{Version: m.version}).After(target) {
+			break
+		}
+		err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+			if err := lockMigrations(ctx, tx); err != nil {
+				return err
+			}
+			applied, err := isApplied(ctx, tx, m.version)
+			if err != nil || !applied {
+				return err
+			}
+			if m.down == "" {
+				return errors.New("migration cannot be rolled back")
+			}
+			if _, err := tx.Exec(ctx, m.down); err != nil {
+				return err
+			}
+			_, err = tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", m.version)
+			if err == nil {
+				slog.Info("Rolled back Postgres migration", "version", m.version)
+			}
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("error rolling back migration %s: %w", m.version, err)
+		}
+	}
+	return nil
+}
+
+// Status lists every migration with when it was applied to the database
+func Status(ctx context.Context, pool *pgxpool.Pool) ([]store.Migration, error) {
+	if err := createMigrationsTable(ctx, pool); err != nil {
+		return nil, err
+	}
+	ms, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := pool.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	applied := map[string]time.Time{}
+	var version string
+	var appliedAt time.Time
+	_, err = pgx.ForEachRow(rows, []any{&version, &appliedAt}, func() error {
+		applied[version] = appliedAt
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]store.Migration, 0, len(ms))
+	for _, m := range ms {
+		s := store.Migration{Version: m.version, Reversible: m.down != ""}
+		if t, ok := applied[m.version]; ok {
+			s.AppliedAt = &t
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
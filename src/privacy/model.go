@@ -0,0 +1,54 @@
+package privacy
+
+import "time"
+
+// Block keeps a user from reaching the user who blocked them
+type Block struct {
+	// UserID is the user who blocked
+	UserID string `bson:"user_id" json:"userID"`
+	// BlockedID is the user they blocked
+	BlockedID string    `bson:"blocked_id" json:"blockedID"`
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+}
+
+// Settings are the privacy settings of a user
+type Settings struct {
+	UserID string `bson:"user_id" json:"userID"`
+	// Restricted profiles are only shown to their owner, admins and the visitors on the allowlist
+	Restricted bool      `bson:"restricted" json:"restricted"`
+	UpdatedAt  time.Time `bson:"updated_at" json:"updatedAt"`
+}
+
+// SettingsRequest represents the request body for updating the privacy settings
+type SettingsRequest struct {
+	Restricted *bool `json:"restricted" binding:"required"`
+}
+
+// Visitor is someone without an account allowed to see a restricted profile, through the link emailed to them
+type Visitor struct {
+	ID     string `bson:"_id" json:"id"`
+	UserID string `bson:"user_id" json:"userID"`
+	Email  string `bson:"email" json:"email"`
+	// Token is sent in the link emailed to the visitor, and is never shown again
+	Token       string     `bson:"token" json:"-"`
+	CreatedAt   time.Time  `bson:"created_at" json:"createdAt"`
+	LastVisitAt *time.Time `bson:"last_visit_at,omitempty" json:"lastVisitAt"`
+}
+
+// VisitorRequest represents the request body for adding a visitor to the allowlist
+type VisitorRequest struct {
+	Email string `json:"email" binding:"required,email,max=254"`
+}
+
+// Access is returned to a visitor who followed their link
+type Access struct {
+	// UserID is the user whose profile the visitor may now see
+	UserID    string    `json:"userID"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// accessEmail is the data for the profile_access email template
+type accessEmail struct {
+	UserName string
+	URL      string
+}
@@ -0,0 +1,493 @@
+// Package privacy lets users keep others away. Users block other users, whose attempts to reach them, such
+// as recommendations and contact requests, are then rejected. Users may also restrict their profile to an
+// allowlist: only they, admins and the visitors they add by email may then see it, each visitor through a
+// link emailed to them which lets them in for a while every time it is followed.
+package privacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/email"
+	"profile-api/readonly"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// accessCookiePrefix starts the name of the cookie holding a visitor's link token, which is followed by the
+// ID of the user whose profile it lets them see
+const accessCookiePrefix = "access_"
+
+var repo Repository
+var users auth.Repository
+var settings config.PrivacyConfig
+
+var publicBaseURL = "http://localhost:8080"
+
+// Configure sets where blocks and allowlists are stored and how long visitors' access lasts, and starts
+// removing the blocks of users who delete their account
+func Configure(r Repository, u auth.Repository, cfg config.PrivacyConfig) {
+	repo = r
+	users = u
+	settings = cfg
+	audit.Subscribe(removeBlocks)
+}
+
+// SetBaseURL sets the public base URL of the links emailed to visitors
+func SetBaseURL(url string) {
+	publicBaseURL = strings.TrimSuffix(url, "/")
+}
+
+// baseURL returns the public base URL used for links in emails, which tenants may override
+func baseURL(ctx context.Context) string {
+	if t, ok := tenant.Current(ctx); ok && t.PublicBaseURL != "" {
+		return strings.TrimSuffix(t.PublicBaseURL, "/")
+	}
+	return publicBaseURL
+}
+
+// removeBlocks removes the blocks of a deleted user, whose own are removed with the rest of their data
+func removeBlocks(ctx context.Context, entry audit.Entry) {
+	if entry.Resource != "user" || entry.Action != audit.ActionDelete {
+		return
+	}
+	if err := repo.DeleteBlocksOf(ctx, entry.ResourceID); err != nil {
+		slog.ErrorContext(ctx, "Could not remove the blocks of a deleted user", "user_id", entry.ResourceID, "error", err)
+	}
+}
+
+// CheckContact returns an error to respond with when the user blocked the sender, for modules letting users
+// reach one another. The sender is the signed in user with the ID, or the user whose account has the email
+// address; either may be empty.
+func CheckContact(ctx context.Context, userID, senderID, senderEmail string) error {
+	if senderID == "" && senderEmail != "" {
+		sender, err := users.FindByEmail(ctx, senderEmail)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return apierror.Wrap(err, "Could not check blocked users")
+		}
+		senderID = sender.ID
+	}
+	if senderID == "" {
+		return nil
+	}
+	blocked, err := repo.Blocked(ctx, userID, senderID)
+	if err != nil {
+		return apierror.Wrap(err, "Could not check blocked users")
+	}
+	if blocked {
+		return apierror.Forbidden("This user is not accepting contact from you")
+	}
+	return nil
+}
+
+// Restricted reports whether the user restricted their profile to their allowlist
+func Restricted(ctx context.Context, userID string) (bool, error) {
+	s, err := repo.GetSettings(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	}
+	return s.Restricted, err
+}
+
+// Restrict responds 404 to reads of the documents of a user, named by the userid path parameter, who
+// restricted their profile, unless the requester is the user, an admin or a visitor on their allowlist.
+// It authenticates the requester itself, so it may run before the routes' own authentication.
+func Restrict() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("userid")
+		if userID == "" || (c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead) {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := utils.DBContext(c)
+		defer cancel()
+		restricted, err := Restricted(ctx, userID)
+		if err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not retrieve privacy settings"))
+			return
+		}
+		if !restricted {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Cookie")
+		if !allowed(ctx, c, userID) {
+			apierror.Abort(c, apierror.NotFound("User not found"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// Allowed reports whether the requester may see the documents of the user, for reads not naming the user
+// in their path, which Restrict can't check
+func Allowed(ctx context.Context, c *gin.Context, userID string) (bool, error) {
+	restricted, err := Restricted(ctx, userID)
+	if err != nil || !restricted {
+		return !restricted, err
+	}
+	c.Writer.Header().Add("Vary", "Cookie")
+	return allowed(ctx, c, userID), nil
+}
+
+// allowed reports whether the requester may see the restricted profile of the user
+func allowed(ctx context.Context, c *gin.Context, userID string) bool {
+	if token, err := c.Cookie("token"); err == nil {
+		if user, err := auth.Authenticate(ctx, users, token); err == nil && (user.ID == userID || user.Admin) {
+			return true
+		}
+	}
+	token, err := c.Cookie(accessCookiePrefix + userID)
+	if err != nil {
+		return false
+	}
+	visitor, err := repo.GetVisitor(ctx, token)
+	if err != nil {
+		return false
+	}
+	return visitor.UserID == userID && visitor.LastVisitAt != nil && time.Since(*visitor.LastVisitAt) < settings.AccessTTL.Std()
+}
+
+// ListBlocks lists the users the current user blocked
+//
+//	@Summary		List blocked users
+//	@Description	Lists the users the current user blocked, newest first
+//	@Tags			privacy
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		Block
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve blocked users"
+//	@Router			/privacy/blocks [get]
+func ListBlocks(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	list, err := repo.ListBlocks(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve blocked users"))
+		return
+	}
+	if list == nil {
+		list = []Block{}
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// BlockUser blocks a user
+//
+//	@Summary		Block a user
+//	@Description	Blocks a user, whose recommendations and contact requests for the current user are then rejected. Blocking a user again keeps the first block.
+//	@Tags			privacy
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			userid	path		string	true	"ID of the user to block"
+//	@Success		200		{object}	map[string]string
+//	@Failure		400		{object}	apierror.Response	"Users can't block themselves"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		404		{object}	apierror.Response	"User not found"
+//	@Failure		500		{object}	apierror.Response	"Could not block user"
+//	@Router			/privacy/blocks/{userid} [put]
+func BlockUser(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+	blockedID := c.Param("userid")
+	if blockedID == user.ID {
+		apierror.Abort(c, apierror.BadRequest("You can't block yourself"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if _, err := users.FindByID(ctx, blockedID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			apierror.Abort(c, apierror.NotFound("User not found"))
+		} else {
+			apierror.Abort(c, apierror.Wrap(err, "Could not block user"))
+		}
+		return
+	}
+	if err := repo.Block(ctx, Block{UserID: user.ID, BlockedID: blockedID, CreatedAt: time.Now()}); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not block user"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User blocked"})
+}
+
+// UnblockUser unblocks a user
+//
+//	@Summary		Unblock a user
+//	@Description	Removes the current user's block of a user, who may reach them again
+//	@Tags			privacy
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			userid	path		string	true	"ID of the user to unblock"
+//	@Success		200		{object}	map[string]string
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		500		{object}	apierror.Response	"Could not unblock user"
+//	@Router			/privacy/blocks/{userid} [delete]
+func UnblockUser(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Unblock(ctx, user.ID, c.Param("userid")); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not unblock user"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unblocked"})
+}
+
+// GetSettings returns the current user's privacy settings
+//
+//	@Summary		Get privacy settings
+//	@Description	Returns whether the current user's profile is restricted to their allowlist
+//	@Tags			privacy
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	Settings
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve privacy settings"
+//	@Router			/privacy/settings [get]
+func GetSettings(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	s, err := repo.GetSettings(ctx, user.ID)
+	if errors.Is(err, store.ErrNotFound) {
+		s, err = Settings{UserID: user.ID}, nil
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve privacy settings"))
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
+
+// UpdateSettings changes the current user's privacy settings
+//
+//	@Summary		Update privacy settings
+//	@Description	Restricts the current user's profile to their allowlist, or opens it again. While restricted, the profile and the documents shown on it are only shown to the user, admins and the visitors on the allowlist, and the user is left out of search.
+//	@Tags			privacy
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			settings	body		SettingsRequest	true	"The privacy settings"
+//	@Success		200			{object}	Settings
+//	@Failure		400			{object}	apierror.Response	"Invalid request body"
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		500			{object}	apierror.Response	"Could not update privacy settings"
+//	@Router			/privacy/settings [put]
+func UpdateSettings(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req SettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	s := Settings{UserID: user.ID, Restricted: *req.Restricted, UpdatedAt: time.Now()}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.SaveSettings(ctx, s); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update privacy settings"))
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
+
+// ListVisitors lists the current user's allowlist
+//
+//	@Summary		List the allowlist
+//	@Description	Lists the visitors who may see the current user's profile while it is restricted, in the order they were added, with when each last followed their link
+//	@Tags			privacy
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		Visitor
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve allowlist"
+//	@Router			/privacy/allowlist [get]
+func ListVisitors(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	list, err := repo.ListVisitors(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve allowlist"))
+		return
+	}
+	if list == nil {
+		list = []Visitor{}
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// AddVisitor adds a visitor to the current user's allowlist
+//
+//	@Summary		Add a visitor to the allowlist
+//	@Description	Adds an email address to the current user's allowlist and emails it a link to their profile. Following the link lets the visitor see the profile while it is restricted, for a while each time.
+//	@Tags			privacy
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			visitor	body		VisitorRequest	true	"The visitor's email address"
+//	@Success		201		{object}	Visitor
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		409		{object}	apierror.Response	"The address is already on the allowlist"
+//	@Failure		413		{object}	apierror.Response	"The allowlist is full"
+//	@Failure		500		{object}	apierror.Response	"Could not add visitor"
+//	@Router			/privacy/allowlist [post]
+func AddVisitor(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req VisitorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	visitors, err := repo.ListVisitors(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not add visitor"))
+		return
+	}
+	if len(visitors) >= settings.MaxVisitors {
+		apierror.Abort(c, apierror.QuotaExceeded(fmt.Sprintf("The allowlist is full, it may hold at most %d visitors", settings.MaxVisitors)))
+		return
+	}
+
+	visitor := Visitor{
+		ID:     utils.GenerateID(),
+		UserID: user.ID,
+		// Addresses are compared ignoring case, so one can't be added twice
+		Email:     strings.ToLower(req.Email),
+		Token:     utils.GenerateID(),
+		CreatedAt: time.Now(),
+	}
+	if err := repo.AddVisitor(ctx, visitor); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			apierror.Abort(c, apierror.Conflict("The address is already on the allowlist"))
+		} else {
+			apierror.Abort(c, apierror.Wrap(err, "Could not add visitor"))
+		}
+		return
+	}
+	msg, err := email.Render("profile_access", accessEmail{
+		UserName: user.Name,
+		URL:      fmt.Sprintf("%s/api/v1/privacy/access/%s", baseURL(ctx), visitor.Token),
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not render access email"))
+		return
+	}
+	msg.To = visitor.Email
+	msg.UserID = user.ID
+	if err := email.Enqueue(ctx, msg); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not queue access email"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, visitor)
+}
+
+// DeleteVisitor removes a visitor from the current user's allowlist
+//
+//	@Summary		Remove a visitor from the allowlist
+//	@Description	Removes a visitor from the current user's allowlist. Their link stops working straight away.
+//	@Tags			privacy
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			visitorid	path		string	true	"Visitor ID"
+//	@Success		200			{object}	map[string]string
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		500			{object}	apierror.Response	"Could not remove visitor"
+//	@Router			/privacy/allowlist/{visitorid} [delete]
+func DeleteVisitor(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.DeleteVisitor(ctx, user.ID, c.Param("visitorid")); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not remove visitor"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Visitor removed"})
+}
+
+// FollowAccessLink lets a visitor on an allowlist in
+//
+//	@Summary		Follow an access link
+//	@Description	Lets in the visitor the link was emailed to, setting a cookie with which they may see the user's restricted profile until the access expires. Following the link again renews the access.
+//	@Tags			privacy
+//	@Produce		json
+//	@Param			token	path		string	true	"Access token from the emailed link"
+//	@Success		200		{object}	Access
+//	@Failure		404		{object}	apierror.Response	"Link not found"
+//	@Failure		500		{object}	apierror.Response	"Could not follow link"
+//	@Router			/privacy/access/{token} [get]
+func FollowAccessLink(c *gin.Context) {
+	token := c.Param("token")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	visitor, err := repo.GetVisitor(ctx, token)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Link not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not follow link"))
+		return
+	}
+	now := time.Now()
+	if err := repo.SetVisited(ctx, token, now); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not follow link"))
+		return
+	}
+
+	ttl := settings.AccessTTL.Std()
+	c.SetCookie(accessCookiePrefix+visitor.UserID, token, int(ttl.Seconds()), "", "", false, true)
+	c.JSON(http.StatusOK, Access{UserID: visitor.UserID, ExpiresAt: now.Add(ttl)})
+}
+
+// InitializeRoutes initializes the privacy routes. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup) {
+	router.GET("/access/:token", readonly.Writes(), FollowAccessLink)
+
+	protected := router.Group("")
+	protected.Use(auth.AuthMiddleware(users, true))
+	protected.GET("/blocks", ListBlocks)
+	protected.PUT("/blocks/:userid", BlockUser)
+	protected.DELETE("/blocks/:userid", UnblockUser)
+	protected.GET("/settings", GetSettings)
+	protected.PUT("/settings", UpdateSettings)
+	protected.GET("/allowlist", ListVisitors)
+	protected.POST("/allowlist", AddVisitor)
+	protected.DELETE("/allowlist/:visitorid", DeleteVisitor)
+}
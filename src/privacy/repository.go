@@ -0,0 +1,36 @@
+package privacy
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores users' blocks, privacy settings and allowlists
+type Repository interface {
+	// Block records that the user blocked another, doing nothing if they already had
+	Block(ctx context.Context, block Block) error
+	// Unblock removes the user's block of another
+	Unblock(ctx context.Context, userID, blockedID string) error
+	// Blocked reports whether the user blocked the other
+	Blocked(ctx context.Context, userID, blockedID string) (bool, error)
+	// ListBlocks returns the users the user blocked, newest first
+	ListBlocks(ctx context.Context, userID string) ([]Block, error)
+	// DeleteBlocksOf removes every block of the user, for when their account is deleted
+	DeleteBlocksOf(ctx context.Context, blockedID string) error
+
+	// GetSettings returns the user's privacy settings, or store.ErrNotFound if they never changed them
+	GetSettings(ctx context.Context, userID string) (Settings, error)
+	// SaveSettings creates or replaces the user's privacy settings
+	SaveSettings(ctx context.Context, settings Settings) error
+
+	// AddVisitor adds a visitor to the user's allowlist, or returns store.ErrConflict if their email is on it
+	AddVisitor(ctx context.Context, visitor Visitor) error
+	// ListVisitors returns the user's allowlist, in the order the visitors were added
+	ListVisitors(ctx context.Context, userID string) ([]Visitor, error)
+	// GetVisitor returns the visitor with the token, or store.ErrNotFound
+	GetVisitor(ctx context.Context, token string) (Visitor, error)
+	// SetVisited records when the visitor with the token last followed their link
+	SetVisited(ctx context.Context, token string, at time.Time) error
+	// DeleteVisitor removes a visitor from the user's allowlist
+	DeleteVisitor(ctx context.Context, userID, visitorID string) error
+}
@@ -0,0 +1,32 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+
+	"profile-api/audit"
+	"profile-api/store"
+)
+
+// AuditedRepository records every change to privacy settings in the audit log
+type AuditedRepository struct {
+	Repository
+}
+
+// NewAuditedRepository wraps the repository to record its changes in the audit log
+func NewAuditedRepository(r Repository) *AuditedRepository {
+	return &AuditedRepository{Repository: r}
+}
+
+func (r *AuditedRepository) SaveSettings(ctx context.Context, settings Settings) error {
+	before, err := r.Repository.GetSettings(ctx, settings.UserID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.SaveSettings(ctx, settings); err != nil {
+		return err
+	}
+	audit.RecordSave(ctx, "privacy", settings.UserID, settings.UserID, before, existed, settings)
+	return nil
+}
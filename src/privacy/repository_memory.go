@@ -0,0 +1,142 @@
+package privacy
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps blocks, settings and allowlists in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	blocks   []Block
+	settings map[string]Settings
+	visitors []Visitor
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{settings: map[string]Settings{}}
+}
+
+func (r *MemoryRepository) Block(ctx context.Context, block Block) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.blockIndex(block.UserID, block.BlockedID) < 0 {
+		r.blocks = append(r.blocks, block)
+	}
+	return nil
+}
+
+func (r *MemoryRepository) Unblock(ctx context.Context, userID, blockedID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.blockIndex(userID, blockedID); i >= 0 {
+		r.blocks = slices.Delete(r.blocks, i, i+1)
+	}
+	return nil
+}
+
+func (r *MemoryRepository) Blocked(ctx context.Context, userID, blockedID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.blockIndex(userID, blockedID) >= 0, nil
+}
+
+func (r *MemoryRepository) ListBlocks(ctx context.Context, userID string) ([]Block, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []Block
+	// Newest first
+	for i := len(r.blocks) - 1; i >= 0; i-- {
+		if r.blocks[i].UserID == userID {
+			list = append(list, r.blocks[i])
+		}
+	}
+	return list, nil
+}
+
+func (r *MemoryRepository) DeleteBlocksOf(ctx context.Context, blockedID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.blocks = slices.DeleteFunc(r.blocks, func(b Block) bool { return b.BlockedID == blockedID })
+	return nil
+}
+
+// blockIndex returns the position of the user's block of the other, or -1. The caller must hold the lock.
+func (r *MemoryRepository) blockIndex(userID, blockedID string) int {
+	return slices.IndexFunc(r.blocks, func(b Block) bool { return b.UserID == userID && b.BlockedID == blockedID })
+}
+
+func (r *MemoryRepository) GetSettings(ctx context.Context, userID string) (Settings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	settings, ok := r.settings[userID]
+	if !ok {
+		return Settings{}, store.ErrNotFound
+	}
+	return settings, nil
+}
+
+func (r *MemoryRepository) SaveSettings(ctx context.Context, settings Settings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.settings[settings.UserID] = settings
+	return nil
+}
+
+func (r *MemoryRepository) AddVisitor(ctx context.Context, visitor Visitor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.ContainsFunc(r.visitors, func(v Visitor) bool {
+		return v.UserID == visitor.UserID && strings.EqualFold(v.Email, visitor.Email)
+	}) {
+		return store.ErrConflict
+	}
+	r.visitors = append(r.visitors, visitor)
+	return nil
+}
+
+func (r *MemoryRepository) ListVisitors(ctx context.Context, userID string) ([]Visitor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []Visitor
+	for _, v := range r.visitors {
+		if v.UserID == userID {
+			list = append(list, v)
+		}
+	}
+	return list, nil
+}
+
+func (r *MemoryRepository) GetVisitor(ctx context.Context, token string) (Visitor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := slices.IndexFunc(r.visitors, func(v Visitor) bool { return v.Token == token })
+	if i < 0 {
+		return Visitor{}, store.ErrNotFound
+	}
+	return r.visitors[i], nil
+}
+
+func (r *MemoryRepository) SetVisited(ctx context.Context, token string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.visitors, func(v Visitor) bool { return v.Token == token })
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.visitors[i].LastVisitAt = &at
+	return nil
+}
+
+func (r *MemoryRepository) DeleteVisitor(ctx context.Context, userID, visitorID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.visitors = slices.DeleteFunc(r.visitors, func(v Visitor) bool { return v.UserID == userID && v.ID == visitorID })
+	return nil
+}
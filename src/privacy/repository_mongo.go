@@ -0,0 +1,115 @@
+package privacy
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores blocks in the blocks collection, settings in privacy_settings and allowlists in
+// profile_visitors
+type MongoRepository struct {
+	blocks   *mongo.Collection
+	settings *mongo.Collection
+	visitors *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		blocks:   db.Collection("blocks"),
+		settings: db.Collection("privacy_settings"),
+		visitors: db.Collection("profile_visitors"),
+	}
+}
+
+func (r *MongoRepository) Block(ctx context.Context, block Block) error {
+	filter := bson.M{"user_id": block.UserID, "blocked_id": block.BlockedID}
+	_, err := r.blocks.UpdateOne(ctx, filter, bson.M{"$setOnInsert": block}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) Unblock(ctx context.Context, userID, blockedID string) error {
+	_, err := r.blocks.DeleteOne(ctx, bson.M{"user_id": userID, "blocked_id": blockedID})
+	return err
+}
+
+func (r *MongoRepository) Blocked(ctx context.Context, userID, blockedID string) (bool, error) {
+	n, err := r.blocks.CountDocuments(ctx, bson.M{"user_id": userID, "blocked_id": blockedID}, options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+func (r *MongoRepository) ListBlocks(ctx context.Context, userID string) ([]Block, error) {
+	cursor, err := r.blocks.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	var list []Block
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *MongoRepository) DeleteBlocksOf(ctx context.Context, blockedID string) error {
+	_, err := r.blocks.DeleteMany(ctx, bson.M{"blocked_id": blockedID})
+	return err
+}
+
+func (r *MongoRepository) GetSettings(ctx context.Context, userID string) (Settings, error) {
+	var settings Settings
+	err := r.settings.FindOne(ctx, bson.M{"user_id": userID}).Decode(&settings)
+	return settings, store.MongoErr(err)
+}
+
+func (r *MongoRepository) SaveSettings(ctx context.Context, settings Settings) error {
+	_, err := r.settings.ReplaceOne(ctx, bson.M{"user_id": settings.UserID}, settings, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) AddVisitor(ctx context.Context, visitor Visitor) error {
+	_, err := r.visitors.InsertOne(ctx, visitor)
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	}
+	return err
+}
+
+func (r *MongoRepository) ListVisitors(ctx context.Context, userID string) ([]Visitor, error) {
+	cursor, err := r.visitors.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var list []Visitor
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *MongoRepository) GetVisitor(ctx context.Context, token string) (Visitor, error) {
+	var visitor Visitor
+	err := r.visitors.FindOne(ctx, bson.M{"token": token}).Decode(&visitor)
+	return visitor, store.MongoErr(err)
+}
+
+func (r *MongoRepository) SetVisited(ctx context.Context, token string, at time.Time) error {
+	result, err := r.visitors.UpdateOne(ctx, bson.M{"token": token}, bson.M{"$set": bson.M{"last_visit_at": at}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) DeleteVisitor(ctx context.Context, userID, visitorID string) error {
+	_, err := r.visitors.DeleteOne(ctx, bson.M{"_id": visitorID, "user_id": userID})
+	return err
+}
@@ -0,0 +1,117 @@
+package privacy
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const visitorColumns = "id, user_id, email, token, created_at, last_visit_at"
+
+// PostgresRepository stores blocks in the blocks table, settings in privacy_settings and allowlists in
+// profile_visitors
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Block(ctx context.Context, block Block) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO blocks (user_id, blocked_id, created_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING",
+		block.UserID, block.BlockedID, block.CreatedAt)
+	return err
+}
+
+func (r *PostgresRepository) Unblock(ctx context.Context, userID, blockedID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM blocks WHERE user_id = $1 AND blocked_id = $2", userID, blockedID)
+	return err
+}
+
+func (r *PostgresRepository) Blocked(ctx context.Context, userID, blockedID string) (bool, error) {
+	var blocked bool
+	err := r.pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM blocks WHERE user_id = $1 AND blocked_id = $2)", userID, blockedID).Scan(&blocked)
+	return blocked, err
+}
+
+func (r *PostgresRepository) ListBlocks(ctx context.Context, userID string) ([]Block, error) {
+	rows, err := r.pool.Query(ctx, "SELECT user_id, blocked_id, created_at FROM blocks WHERE user_id = $1 ORDER BY created_at DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Block, error) {
+		var b Block
+		err := row.Scan(&b.UserID, &b.BlockedID, &b.CreatedAt)
+		return b, err
+	})
+}
+
+func (r *PostgresRepository) DeleteBlocksOf(ctx context.Context, blockedID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM blocks WHERE blocked_id = $1", blockedID)
+	return err
+}
+
+func (r *PostgresRepository) GetSettings(ctx context.Context, userID string) (Settings, error) {
+	var s Settings
+	err := r.pool.QueryRow(ctx, "SELECT user_id, restricted, updated_at FROM privacy_settings WHERE user_id = $1", userID).
+		Scan(&s.UserID, &s.Restricted, &s.UpdatedAt)
+	return s, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) SaveSettings(ctx context.Context, settings Settings) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO privacy_settings (user_id, restricted, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET restricted = EXCLUDED.restricted, updated_at = EXCLUDED.updated_at`,
+		settings.UserID, settings.Restricted, settings.UpdatedAt)
+	return err
+}
+
+func (r *PostgresRepository) AddVisitor(ctx context.Context, visitor Visitor) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO profile_visitors ("+visitorColumns+") VALUES ($1, $2, $3, $4, $5, $6)",
+		visitor.ID, visitor.UserID, visitor.Email, visitor.Token, visitor.CreatedAt, visitor.LastVisitAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) ListVisitors(ctx context.Context, userID string) ([]Visitor, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+visitorColumns+" FROM profile_visitors WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanVisitor)
+}
+
+func (r *PostgresRepository) GetVisitor(ctx context.Context, token string) (Visitor, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+visitorColumns+" FROM profile_visitors WHERE token = $1", token)
+	if err != nil {
+		return Visitor{}, err
+	}
+	visitor, err := pgx.CollectExactlyOneRow(rows, scanVisitor)
+	return visitor, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) SetVisited(ctx context.Context, token string, at time.Time) error {
+	tag, err := r.pool.Exec(ctx, "UPDATE profile_visitors SET last_visit_at = $2 WHERE token = $1", token, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) DeleteVisitor(ctx context.Context, userID, visitorID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM profile_visitors WHERE id = $1 AND user_id = $2", visitorID, userID)
+	return err
+}
+
+func scanVisitor(row pgx.CollectableRow) (Visitor, error) {
+	var v Visitor
+	err := row.Scan(&v.ID, &v.UserID, &v.Email, &v.Token, &v.CreatedAt, &v.LastVisitAt)
+	return v, err
+}
@@ -0,0 +1,66 @@
+package privacy
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Block(ctx context.Context, block Block) error {
+	return r.repos.For(ctx).Block(ctx, block)
+}
+
+func (r *TenantRepository) Unblock(ctx context.Context, userID, blockedID string) error {
+	return r.repos.For(ctx).Unblock(ctx, userID, blockedID)
+}
+
+func (r *TenantRepository) Blocked(ctx context.Context, userID, blockedID string) (bool, error) {
+	return r.repos.For(ctx).Blocked(ctx, userID, blockedID)
+}
+
+func (r *TenantRepository) ListBlocks(ctx context.Context, userID string) ([]Block, error) {
+	return r.repos.For(ctx).ListBlocks(ctx, userID)
+}
+
+func (r *TenantRepository) DeleteBlocksOf(ctx context.Context, blockedID string) error {
+	return r.repos.For(ctx).DeleteBlocksOf(ctx, blockedID)
+}
+
+func (r *TenantRepository) GetSettings(ctx context.Context, userID string) (Settings, error) {
+	return r.repos.For(ctx).GetSettings(ctx, userID)
+}
+
+func (r *TenantRepository) SaveSettings(ctx context.Context, settings Settings) error {
+	return r.repos.For(ctx).SaveSettings(ctx, settings)
+}
+
+func (r *TenantRepository) AddVisitor(ctx context.Context, visitor Visitor) error {
+	return r.repos.For(ctx).AddVisitor(ctx, visitor)
+}
+
+func (r *TenantRepository) ListVisitors(ctx context.Context, userID string) ([]Visitor, error) {
+	return r.repos.For(ctx).ListVisitors(ctx, userID)
+}
+
+func (r *TenantRepository) GetVisitor(ctx context.Context, token string) (Visitor, error) {
+	return r.repos.For(ctx).GetVisitor(ctx, token)
+}
+
+func (r *TenantRepository) SetVisited(ctx context.Context, token string, at time.Time) error {
+	return r.repos.For(ctx).SetVisited(ctx, token, at)
+}
+
+func (r *TenantRepository) DeleteVisitor(ctx context.Context, userID, visitorID string) error {
+	return r.repos.For(ctx).DeleteVisitor(ctx, userID, visitorID)
+}
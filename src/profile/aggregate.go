@@ -0,0 +1,205 @@
+package profile
+
+import (
+	"context"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/audit"
+	"profile-api/awards"
+	"profile-api/certificates"
+	"profile-api/experience"
+	"profile-api/languages"
+	"profile-api/qualifications"
+	"profile-api/recommendations"
+	"profile-api/skills"
+	"profile-api/utils"
+	"profile-api/visibility"
+
+	"github.com/gin-gonic/gin"
+)
+
+// aggregateResources are the audit log resources a full profile is composed from
+var aggregateResources = map[string]bool{
+	"profile": true, "image": true, "experience": true, "qualification": true, "cert_image": true,
+	"certificate": true, "award": true, "skill": true, "language": true, "recommendation": true,
+}
+
+// AggregateSources are where the CV sections of a full profile are read. A source is nil when its module is
+// disabled, leaving its section out of full profiles.
+type AggregateSources struct {
+	Experience      experience.Repository
+	Qualifications  qualifications.Repository
+	Certificates    certificates.Repository
+	Awards          awards.Repository
+	Languages       languages.Repository
+	Skills          skills.Repository
+	Recommendations recommendations.Repository
+}
+
+var aggregateSources AggregateSources
+
+// SetAggregateSources sets where the CV sections of full profiles are read, and starts dropping cached full
+// profiles when any of them is written
+func SetAggregateSources(s AggregateSources) {
+	aggregateSources = s
+	audit.Subscribe(invalidateAggregate)
+}
+
+// invalidateAggregate drops the cached full profile of the owner of changed profile data, or of a deleted user
+func invalidateAggregate(ctx context.Context, entry audit.Entry) {
+	if aggregateResources[entry.Resource] || entry.Resource == "user" && entry.Action == audit.ActionDelete {
+		InvalidateAggregate(ctx, entry.UserID)
+	}
+}
+
+// composeAggregate reads the user's profile and each of their CV sections
+func composeAggregate(ctx context.Context, userID string) (Aggregate, error) {
+	var a Aggregate
+	var err error
+	if a.Profile, err = profiles.Get(ctx, userID); err != nil {
+		return a, err
+	}
+	if aggregateSources.Experience != nil {
+		if a.Experience, err = aggregateSources.Experience.List(ctx, userID); err != nil {
+			return a, err
+		}
+	}
+	if aggregateSources.Qualifications != nil {
+		if a.Qualifications, err = aggregateSources.Qualifications.List(ctx, userID); err != nil {
+			return a, err
+		}
+	}
+	if aggregateSources.Certificates != nil {
+		if a.Certificates, err = aggregateSources.Certificates.List(ctx, userID); err != nil {
+			return a, err
+		}
+	}
+	if aggregateSources.Awards != nil {
+		if a.Awards, err = aggregateSources.Awards.List(ctx, userID); err != nil {
+			return a, err
+		}
+	}
+	if aggregateSources.Languages != nil {
+		if a.Languages, err = aggregateSources.Languages.List(ctx, userID); err != nil {
+			return a, err
+		}
+	}
+	if aggregateSources.Skills != nil {
+		if a.Skills, err = aggregateSources.Skills.List(ctx, userID); err != nil {
+			return a, err
+		}
+	}
+	if aggregateSources.Recommendations != nil {
+		if a.Recommendations, err = aggregateSources.Recommendations.List(ctx, userID, recommendations.StatusApproved); err != nil {
+			return a, err
+		}
+	}
+	// The email of external referees is only shown to the recommended user, through the recommendations
+	// endpoints, so it is not kept with the full profile
+	for i := range a.Recommendations {
+		a.Recommendations[i].AuthorEmail = ""
+	}
+	return a, nil
+}
+
+// loadAggregate returns the user's full profile from the cache, composing and caching it on a miss
+func loadAggregate(ctx context.Context, userID string) (Aggregate, error) {
+	if aggregates == nil {
+		return composeAggregate(ctx, userID)
+	}
+	if a, ok := aggregates.get(ctx, userID); ok {
+		aggregateHits.Add(1)
+		return a, nil
+	}
+	aggregateMisses.Add(1)
+	a, err := composeAggregate(ctx, userID)
+	if err != nil {
+		return a, err
+	}
+	aggregates.set(ctx, userID, a)
+	return a, nil
+}
+
+// redactAggregate returns the full profile with the fields the viewer may not see removed
+func redactAggregate(viewer visibility.Viewer, a Aggregate) (map[string]any, error) {
+	full := map[string]any{}
+	var err error
+	if full["profile"], err = visibility.Redact(viewer, a.Profile); err != nil {
+		return nil, err
+	}
+	if aggregateSources.Experience != nil {
+		if full["experience"], err = visibility.RedactAll(viewer, a.Experience); err != nil {
+			return nil, err
+		}
+	}
+	if aggregateSources.Qualifications != nil {
+		if full["qualifications"], err = visibility.RedactAll(viewer, a.Qualifications); err != nil {
+			return nil, err
+		}
+	}
+	if aggregateSources.Certificates != nil {
+		if full["certificates"], err = visibility.RedactAll(viewer, a.Certificates); err != nil {
+			return nil, err
+		}
+	}
+	if aggregateSources.Awards != nil {
+		if full["awards"], err = visibility.RedactAll(viewer, a.Awards); err != nil {
+			return nil, err
+		}
+	}
+	if aggregateSources.Languages != nil {
+		if full["languages"], err = visibility.RedactAll(viewer, a.Languages); err != nil {
+			return nil, err
+		}
+	}
+	if aggregateSources.Skills != nil {
+		if full["skills"], err = visibility.RedactAll(viewer, a.Skills); err != nil {
+			return nil, err
+		}
+	}
+	if aggregateSources.Recommendations != nil {
+		full["recommendations"] = a.Recommendations
+		if a.Recommendations == nil {
+			full["recommendations"] = []recommendations.Recommendation{}
+		}
+	}
+	return full, nil
+}
+
+// GetFullProfile retrieves a user's profile together with their CV sections.
+//
+//	@Summary		Retrieve a user's full profile.
+//	@Description	Retrieves the profile of the user with the specified user ID together with their experience, qualifications, certificates, awards, languages, skills and approved recommendations, for rendering a profile page in one request. The sections of disabled modules are left out. Fields are shown according to the visibility rules of each document, as by the endpoints of each section. Full profiles are cached for a few minutes and dropped whenever any of their parts is written. The ETag is computed over the response, so a client sending it back in If-None-Match gets 304 until something it can see changes.
+//	@Tags			profile
+//	@Security		BearerAuth
+//	@ID				get-full-profile
+//	@Param			userid	path		string				true	"The ID of the user whose profile to get"
+//	@Success		200		{object}	Aggregate			"Full profile retrieved successfully"
+//	@Header			200		{string}	ETag				"Version of the response, for If-None-Match"
+//	@Success		304		"Not modified"
+//	@Failure		404		{object}	apierror.Response	"Profile not found"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve profile"
+//	@Router			/profile/{userid}/full [get]
+//	@Router			/profile/{userid}/full [head]
+func GetFullProfile(c *gin.Context) {
+	userID := c.Param("userid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	a, err := loadAggregate(ctx, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve profile"))
+		return
+	}
+
+	// Fields are shown according to their visibility, so the response depends on who asks
+	c.Writer.Header().Add("Vary", "Cookie")
+	full, err := redactAggregate(visibility.ViewerOf(c), a)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not encode response"))
+		return
+	}
+	// The sections have no common modification time, so only the ETag tells whether the response changed
+	utils.ConditionalJSON(c, full, time.Time{})
+}
@@ -0,0 +1,176 @@
+package profile
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"profile-api/cache"
+	"profile-api/tenant"
+)
+
+// aggregateCache keeps composed full profiles, so a page view does not read every CV section
+type aggregateCache interface {
+	get(ctx context.Context, userID string) (Aggregate, bool)
+	set(ctx context.Context, userID string, a Aggregate)
+	remove(ctx context.Context, userID string)
+	// len returns the number of full profiles kept, or -1 when it is not known
+	len() int
+	backend() string
+}
+
+// redisAggregateCache keeps full profiles in Redis, shared by every replica
+type redisAggregateCache struct {
+	cache *cache.Cache
+	ttl   time.Duration
+}
+
+func (r *redisAggregateCache) get(ctx context.Context, userID string) (Aggregate, bool) {
+	var a Aggregate
+	ok := r.cache.Get(ctx, cache.Key("aggregate", userID), &a)
+	return a, ok
+}
+
+func (r *redisAggregateCache) set(ctx context.Context, userID string, a Aggregate) {
+	r.cache.Set(ctx, cache.Key("aggregate", userID), a, r.ttl)
+}
+
+func (r *redisAggregateCache) remove(ctx context.Context, userID string) {
+	r.cache.Delete(ctx, cache.Key("aggregate", userID))
+}
+
+func (r *redisAggregateCache) len() int { return -1 }
+
+func (r *redisAggregateCache) backend() string { return "redis" }
+
+// memoryAggregateCache keeps the most recently viewed full profiles in the replica's memory. Writes made
+// through other replicas only reach it through change streams, so without them entries may be stale until
+// they expire.
+type memoryAggregateCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryAggregateEntry struct {
+	key       string
+	aggregate Aggregate
+	expires   time.Time
+}
+
+func newMemoryAggregateCache(size int, ttl time.Duration) *memoryAggregateCache {
+	return &memoryAggregateCache{ttl: ttl, size: size, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+// memoryKey namespaces the user's entry by the context's tenant
+func memoryKey(ctx context.Context, userID string) string {
+	return tenant.ID(ctx) + ":" + userID
+}
+
+func (m *memoryAggregateCache) get(ctx context.Context, userID string) (Aggregate, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[memoryKey(ctx, userID)]
+	if !ok {
+		return Aggregate{}, false
+	}
+	entry := e.Value.(*memoryAggregateEntry)
+	if time.Now().After(entry.expires) {
+		m.order.Remove(e)
+		delete(m.entries, entry.key)
+		return Aggregate{}, false
+	}
+	m.order.MoveToFront(e)
+	return entry.aggregate, true
+}
+
+func (m *memoryAggregateCache) set(ctx context.Context, userID string, a Aggregate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := memoryKey(ctx, userID)
+	if e, ok := m.entries[key]; ok {
+		m.order.Remove(e)
+	}
+	m.entries[key] = m.order.PushFront(&memoryAggregateEntry{key: key, aggregate: a, expires: time.Now().Add(m.ttl)})
+	for m.order.Len() > m.size {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryAggregateEntry).key)
+	}
+}
+
+func (m *memoryAggregateCache) remove(ctx context.Context, userID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := memoryKey(ctx, userID)
+	if e, ok := m.entries[key]; ok {
+		m.order.Remove(e)
+		delete(m.entries, key)
+	}
+}
+
+func (m *memoryAggregateCache) len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+func (m *memoryAggregateCache) backend() string { return "memory" }
+
+// aggregates is nil when full profiles are not cached
+var aggregates aggregateCache
+
+// aggregateStats counts the lookups of full profiles in the cache since the replica started
+var (
+	aggregateStatsSince  = time.Now()
+	aggregateHits        atomic.Int64
+	aggregateMisses      atomic.Int64
+	aggregateInvalidated atomic.Int64
+)
+
+// ConfigureAggregateCache sets how composed full profiles are cached: in Redis when c is not nil, otherwise
+// in memory. A TTL of zero disables the cache.
+func ConfigureAggregateCache(c *cache.Cache, ttl time.Duration, size int) {
+	switch {
+	case ttl <= 0:
+		aggregates = nil
+	case c != nil:
+		aggregates = &redisAggregateCache{cache: c, ttl: ttl}
+	default:
+		aggregates = newMemoryAggregateCache(size, ttl)
+	}
+}
+
+// InvalidateAggregate drops the user's cached full profile, such as after a write seen through change streams
+func InvalidateAggregate(ctx context.Context, userID string) {
+	if aggregates == nil {
+		return
+	}
+	aggregates.remove(ctx, userID)
+	aggregateInvalidated.Add(1)
+}
+
+// AggregateCacheStats returns how well the full profile cache has served since the replica started
+func AggregateCacheStats() CacheStats {
+	stats := CacheStats{
+		Backend:       "disabled",
+		Since:         aggregateStatsSince,
+		Hits:          aggregateHits.Load(),
+		Misses:        aggregateMisses.Load(),
+		Invalidations: aggregateInvalidated.Load(),
+	}
+	if aggregates != nil {
+		stats.Backend = aggregates.backend()
+		if n := aggregates.len(); n >= 0 {
+			stats.Entries = &n
+		}
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
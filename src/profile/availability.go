@@ -0,0 +1,111 @@
+package profile
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/store"
+	"profile-api/utils"
+	"profile-api/visibility"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAvailability retrieves whether a user is open to work.
+//
+//	@Summary		Retrieve a user's availability.
+//	@Description	Retrieves whether the user is open to work, when they can start and the roles, locations and remote work they are after. The availability field of the profile's visibility rules decides who may see it.
+//	@Tags			profile
+//	@Security		BearerAuth
+//	@ID				get-profile-availability
+//	@Produce		json
+//	@Param			userid	path		string			true	"The ID of the user whose availability to get"
+//	@Success		200		{object}	Availability	"Availability retrieved successfully"
+//	@Failure		404		{object}	apierror.Response	"Availability not found"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve availability"
+//	@Router			/profile/{userid}/availability [get]
+func GetAvailability(c *gin.Context) {
+	userID := c.Param("userid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	profile, err := profiles.Get(ctx, userID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve availability"))
+		return
+	}
+
+	// Hidden availability is reported as missing, so requesters cannot tell it is set
+	c.Writer.Header().Add("Vary", "Cookie")
+	level := profile.FieldVisibility()["availability"]
+	if profile.Availability == nil || (level != "" && !visibility.ViewerOf(c).Sees(userID, level)) {
+		apierror.Abort(c, apierror.NotFound("Availability not found"))
+		return
+	}
+	c.JSON(http.StatusOK, profile.Availability)
+}
+
+// PutAvailability sets whether a user is open to work.
+//
+//	@Summary		Update a user's availability.
+//	@Description	Sets whether the user is open to work (open, passive or closed), the date they can start, and the roles, locations and remote work (onsite, hybrid or remote) they are after. Open and passive users whose availability is public are found by the talent search filters.
+//	@Tags			profile
+//	@Security		BearerAuth
+//	@ID				update-profile-availability
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string			true	"The ID of the user whose availability to update"
+//	@Param			request	body		Availability	true	"Availability of the user"
+//	@Success		200		{object}	map[string]string	"Availability updated"
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the profile"
+//	@Failure		500		{object}	apierror.Response	"Could not update availability"
+//	@Router			/profile/{userid}/availability [put]
+func PutAvailability(c *gin.Context) {
+	userID := c.Param("userid")
+
+	var req Availability
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := profiles.SetAvailability(ctx, userID, &req, time.Now()); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update availability"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Availability updated"})
+}
+
+// DeleteAvailability clears whether a user is open to work.
+//
+//	@Summary		Clear a user's availability.
+//	@Description	Removes the user's availability from their profile and from the talent search
+//	@Tags			profile
+//	@Security		BearerAuth
+//	@ID				delete-profile-availability
+//	@Produce		json
+//	@Param			userid	path		string			true	"The ID of the user whose availability to clear"
+//	@Success		200		{object}	map[string]string	"Availability cleared"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the profile"
+//	@Failure		500		{object}	apierror.Response	"Could not clear availability"
+//	@Router			/profile/{userid}/availability [delete]
+func DeleteAvailability(c *gin.Context) {
+	userID := c.Param("userid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := profiles.SetAvailability(ctx, userID, nil, time.Now()); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not clear availability"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Availability cleared"})
+}
@@ -0,0 +1,217 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/awards"
+	"profile-api/certificates"
+	"profile-api/experience"
+	"profile-api/qualifications"
+	"profile-api/store"
+	"profile-api/utils"
+	"profile-api/validation"
+	"profile-api/visibility"
+
+	"github.com/gin-gonic/gin"
+)
+
+// certificateReminder is how long before a certificate expires calendars are asked to remind the user
+const certificateReminder = "-P30D"
+
+// CalendarSources are where the records a calendar is built from are read
+type CalendarSources struct {
+	Experience     experience.Repository
+	Qualifications qualifications.Repository
+	// Certificates is nil when the certificates module is disabled
+	Certificates certificates.Repository
+	Awards       awards.Repository
+}
+
+var calendarSources CalendarSources
+
+// SetCalendarSources sets where the records calendars are built from are read
+func SetCalendarSources(s CalendarSources) {
+	calendarSources = s
+}
+
+// calendarEvent is an all-day event of a calendar
+type calendarEvent struct {
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+	// Reminder is when, relative to the event, calendars should remind the user, as an iCalendar duration
+	Reminder string
+}
+
+// GetCalendar returns the user's career timeline as an iCalendar feed.
+//
+//	@Summary		Get a user's career timeline as a calendar.
+//	@Description	Returns an iCalendar feed of all-day events for the start and end of each role, the completion of each qualification, each award received and the expiry of each certificate that has not yet expired, with a reminder 30 days before. Calendar apps can subscribe to its URL to overlay the timeline on the user's calendar. Partial dates fall on the first day of their month or year.
+//	@Tags			profile
+//	@ID				get-calendar
+//	@Produce		text/calendar
+//	@Param			userid	path		string				true	"The ID of the user"
+//	@Success		200		{string}	string				"iCalendar feed"
+//	@Failure		500		{object}	apierror.Response	"Could not build calendar"
+//	@Router			/profile/{userid}/calendar.ics [get]
+func GetCalendar(c *gin.Context) {
+	userID := c.Param("userid")
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+
+	name := "Career timeline"
+	profile, err := profiles.Get(ctx, userID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.Wrap(err, "Could not build calendar"))
+		return
+	}
+	// Calendar apps fetch the feed without signing in, so it only shows public fields
+	if profile, err = visibility.Strip(visibility.Viewer{}, profile); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not build calendar"))
+		return
+	}
+	if profile.Name != nil && *profile.Name != "" {
+		name = *profile.Name + " - " + name
+	}
+	events, err := calendarEvents(ctx, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not build calendar"))
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(renderCalendar(name, events, time.Now())))
+}
+
+// calendarEvents lists the events of the user's roles, qualifications, unexpired certificates and awards
+func calendarEvents(ctx context.Context, userID string) ([]calendarEvent, error) {
+	roles, err := calendarSources.Experience.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	quals, err := calendarSources.Qualifications.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var certs []certificates.Certificate
+	if calendarSources.Certificates != nil {
+		if certs, err = calendarSources.Certificates.List(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	honors, err := calendarSources.Awards.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	anonymous := visibility.Viewer{}
+	if roles, err = visibility.StripAll(anonymous, roles); err != nil {
+		return nil, err
+	}
+	if quals, err = visibility.StripAll(anonymous, quals); err != nil {
+		return nil, err
+	}
+	if certs, err = visibility.StripAll(anonymous, certs); err != nil {
+		return nil, err
+	}
+	if honors, err = visibility.StripAll(anonymous, honors); err != nil {
+		return nil, err
+	}
+
+	var events []calendarEvent
+	add := func(uid, date, summary, description, reminder string) {
+		if day, ok := validation.ParseDate(date); ok {
+			events = append(events, calendarEvent{UID: uid, Date: day, Summary: summary, Description: description, Reminder: reminder})
+		}
+	}
+	for _, role := range roles {
+		add("experience-"+role.ExperienceID+"-start", role.Start, fmt.Sprintf("Started as %s at %s", role.Position, role.Company), role.Description, "")
+		add("experience-"+role.ExperienceID+"-end", role.End, fmt.Sprintf("Left %s at %s", role.Position, role.Company), "", "")
+	}
+	for _, q := range quals {
+		add("qualification-"+q.QualificationID+"-end", q.End, fmt.Sprintf("Completed %s at %s", q.Title, q.Institution), q.Description, "")
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, cert := range certs {
+		if expiry, ok := validation.ParseDate(cert.End); ok && !expiry.Before(today) {
+			add("certificate-"+cert.CertificateID+"-expiry", cert.End, fmt.Sprintf("%s (%s) expires", cert.Title, cert.Institution), cert.Description, certificateReminder)
+		}
+	}
+	for _, award := range honors {
+		add("award-"+award.AwardID, award.Date, fmt.Sprintf("Received %s from %s", award.Title, award.Issuer), award.Description, "")
+	}
+	return events, nil
+}
+
+// renderCalendar writes the events as an iCalendar document as defined by RFC 5545
+func renderCalendar(name string, events []calendarEvent, now time.Time) string {
+	var b strings.Builder
+	line := func(content string) {
+		b.WriteString(foldLine(content))
+		b.WriteString("\r\n")
+	}
+	stamp := now.UTC().Format("20060102T150405Z")
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//profile-api//Career timeline//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeText(name))
+	for _, event := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + event.UID + "@profile-api")
+		line("DTSTAMP:" + stamp)
+		line("DTSTART;VALUE=DATE:" + event.Date.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + event.Date.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + escapeText(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION:" + escapeText(event.Description))
+		}
+		line("TRANSP:TRANSPARENT")
+		if event.Reminder != "" {
+			line("BEGIN:VALARM")
+			line("ACTION:DISPLAY")
+			line("DESCRIPTION:" + escapeText(event.Summary))
+			line("TRIGGER:" + event.Reminder)
+			line("END:VALARM")
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// escapeText escapes a TEXT value, whose backslashes, separators and line breaks must be escaped
+func escapeText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", `\n`).Replace(text)
+}
+
+// foldLine splits a content line longer than 75 bytes into continuation lines, without splitting a
+// character
+func foldLine(content string) string {
+	const limit = 75
+	if len(content) <= limit {
+		return content
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range content {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			// The leading space of a continuation line counts towards its length
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
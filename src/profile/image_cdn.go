@@ -0,0 +1,113 @@
+package profile
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"profile-api/config"
+)
+
+// signatureParams are the query parameters of a CloudFront signed URL
+var signatureParams = []string{"Expires", "Signature", "Key-Pair-Id"}
+
+// URLSigner signs the URLs of images served by a private CloudFront distribution with a canned policy,
+// letting whoever holds one fetch the image until it expires
+type URLSigner struct {
+	key       *rsa.PrivateKey
+	keyPairID string
+	expiry    time.Duration
+	// baseURLs are the CDNs whose URLs are signed, leaving URLs of other sites as they are
+	baseURLs []string
+}
+
+// NewURLSigner loads the CDN's private key to sign the URLs of images served by the CDN of the image
+// store or of any tenant
+func NewURLSigner(cfg config.ImageStoreConfig, tenants []config.TenantConfig) (*URLSigner, error) {
+	data, err := os.ReadFile(cfg.CDN.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CDN private key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("CDN private key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, pkcs8Err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if pkcs8Err != nil || !ok {
+			return nil, fmt.Errorf("CDN private key must be an RSA key: %w", err)
+		}
+		key = rsaKey
+	}
+
+	signer := &URLSigner{key: key, keyPairID: cfg.CDN.KeyPairID, expiry: cfg.CDN.URLExpiry.Std()}
+	signer.baseURLs = append(signer.baseURLs, strings.TrimSuffix(cfg.CDN.BaseURL, "/")+"/")
+	for _, t := range tenants {
+		if t.CDNBaseURL != "" {
+			signer.baseURLs = append(signer.baseURLs, strings.TrimSuffix(t.CDNBaseURL, "/")+"/")
+		}
+	}
+	return signer, nil
+}
+
+// Sign returns the URL signed to expire between half the expiry and the expiry from now. Expiry times
+// are rounded so an image keeps the same URL for a while, for browsers to cache it. URLs not served by
+// the CDN are returned unchanged.
+func (s *URLSigner) Sign(rawURL string) string {
+	if !s.signs(rawURL) {
+		return rawURL
+	}
+	expires := time.Now().Truncate(s.expiry / 2).Add(s.expiry).Unix()
+	policy := fmt.Sprintf(`{"Statement":[{"Resource":"%s","Condition":{"DateLessThan":{"AWS:EpochTime":%d}}}]}`, rawURL, expires)
+	digest := sha1.Sum([]byte(policy))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA1, digest[:])
+	if err != nil {
+		return rawURL
+	}
+	// CloudFront's URL-safe base64 alphabet
+	encoded := strings.NewReplacer("+", "-", "=", "_", "/", "~").Replace(base64.StdEncoding.EncodeToString(signature))
+
+	separator := "?"
+	if strings.Contains(rawURL, "?") {
+		separator = "&"
+	}
+	return rawURL + separator + "Expires=" + strconv.FormatInt(expires, 10) + "&Signature=" + encoded + "&Key-Pair-Id=" + url.QueryEscape(s.keyPairID)
+}
+
+// Unsign returns the URL without its signature, as it is stored
+func (s *URLSigner) Unsign(rawURL string) string {
+	if !s.signs(rawURL) {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := u.Query()
+	for _, param := range signatureParams {
+		query.Del(param)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func (s *URLSigner) signs(rawURL string) bool {
+	for _, base := range s.baseURLs {
+		if strings.HasPrefix(rawURL, base) {
+			return true
+		}
+	}
+	return false
+}
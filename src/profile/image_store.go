@@ -1,9 +1,109 @@
 package profile
 
 import (
-	"mime/multipart"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"profile-api/images"
+	"profile-api/store"
 )
 
+// quarantineDir is where quarantined images are moved to, out of reach of the image URLs
+const quarantineDir = "quarantine"
+
+// ImageStore stores uploaded images. Images are stored under a random name in a directory of the user who
+// uploaded them, with the extension of the format detected from their content, so the name a client uploads
+// an image with never reaches the path. Images uploaded before were named after the user and the file, and
+// are still served and loaded under those names.
 type ImageStore interface {
-    SaveImage(userID, filename string, file multipart.File) (string, error)
-}
\ No newline at end of file
+	// SaveImage stores the image, returning its URL, or images.ErrInvalid when it is not an image
+	SaveImage(userID string, file io.Reader) (string, error)
+	// Load returns the image at the URL returned by SaveImage, or store.ErrNotFound
+	Load(ctx context.Context, imageURL string) ([]byte, error)
+	// Delete removes the image at the URL and its variants
+	Delete(ctx context.Context, imageURL string) error
+	// Quarantine moves the image at the URL, and its variants, where they are no longer served
+	Quarantine(ctx context.Context, imageURL string) error
+	// Ping checks that the store is reachable
+	Ping(ctx context.Context) error
+}
+
+// imageURL returns the URL of the image under the base URL, with a version changing with its content so
+// caches such as the CDN fetch an image replaced under the same name again
+func imageURL(baseURL, name string, data []byte) string {
+	sum := sha256.Sum256(data)
+	return strings.TrimSuffix(baseURL, "/") + "/" + name + "?v=" + hex.EncodeToString(sum[:6])
+}
+
+// objectName matches the last segment of the names SaveImage gives images
+var objectName = regexp.MustCompile(`^[0-9a-f]{32}\.[a-z0-9]+$`)
+
+// validSegment reports whether the part of an image's name can be used as a file name without leaving the
+// store's directory or hiding the file
+func validSegment(segment string) bool {
+	return segment != "" && !strings.HasPrefix(segment, ".") && !strings.ContainsAny(segment, "/\\\x00")
+}
+
+// validImageName reports whether the name is that of an image in a user's directory, or of one stored before
+// images had directories. Quarantined images are never named.
+func validImageName(name string) bool {
+	dir, file, nested := strings.Cut(name, "/")
+	if !nested {
+		return validSegment(name)
+	}
+	return validSegment(dir) && dir != quarantineDir && validSegment(file)
+}
+
+// newImageName returns a random name for the user's image in the format of the data
+func newImageName(userID string, data []byte) (string, error) {
+	if !validSegment(userID) || userID == quarantineDir {
+		return "", fmt.Errorf("invalid user ID %q for an image name", userID)
+	}
+	ext, err := images.Extension(data)
+	if err != nil {
+		return "", err
+	}
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return userID + "/" + hex.EncodeToString(random) + ext, nil
+}
+
+// imageName returns the name of the stored image at the URL: the user's directory and the image's name, or
+// only its name for images stored before images had directories
+func imageName(imageURL string) (string, error) {
+	u, err := url.Parse(imageURL)
+	if err != nil {
+		return "", err
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	name := segments[len(segments)-1]
+	if n := len(segments); n >= 2 && objectName.MatchString(name) {
+		name = segments[n-2] + "/" + name
+	}
+	if !validImageName(name) {
+		return "", store.ErrNotFound
+	}
+	return name, nil
+}
+
+// imageOwnedBy reports whether the image at the URL was uploaded by the user
+func imageOwnedBy(imageURL, userID string) bool {
+	name, err := imageName(imageURL)
+	if err != nil {
+		return false
+	}
+	dir, _, nested := strings.Cut(name, "/")
+	if nested {
+		return dir == userID
+	}
+	return strings.HasPrefix(name, userID+"-")
+}
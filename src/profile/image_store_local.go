@@ -1,28 +1,137 @@
 package profile
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"io/fs"
 	"os"
 	"path/filepath"
+
+	"profile-api/images"
+	"profile-api/store"
 )
 
 type LocalImageStore struct {
-    BasePath string
-}
-
-func (l *LocalImageStore) SaveImage(userID, filename string, file multipart.File) (string, error) {
-    imageName := fmt.Sprintf("%s-%s", userID, filename)
-    imagePath := filepath.Join(l.BasePath, imageName)
-    out, err := os.Create(imagePath)
-    if err != nil {
-        return "", err
-    }
-    defer out.Close()
-    _, err = io.Copy(out, file)
-    if err != nil {
-        return "", err
-    }
-    return "/images/" + imageName, nil
-}
\ No newline at end of file
+	BasePath string
+	// PublicBaseURL is the URL images are served from in place of the /images route, such as a CDN in front of it
+	PublicBaseURL string
+}
+
+func (l *LocalImageStore) Ping(ctx context.Context) error {
+	info, err := os.Stat(l.basePath())
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", l.basePath())
+	}
+	return nil
+}
+
+// basePath returns the directory images are written to, defaulting to the working directory
+func (l *LocalImageStore) basePath() string {
+	if l.BasePath == "" {
+		return "."
+	}
+	return l.BasePath
+}
+
+// Open opens a stored image by the name returned in its URL
+func (l *LocalImageStore) Open(name string) (*os.File, error) {
+	if !validImageName(name) {
+		return nil, os.ErrNotExist
+	}
+	return os.Open(filepath.Join(l.basePath(), filepath.FromSlash(name)))
+}
+
+func (l *LocalImageStore) SaveImage(userID string, file io.Reader) (string, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	if data, err = images.Process(data); err != nil {
+		return "", err
+	}
+	imageName, err := newImageName(userID, data)
+	if err != nil {
+		return "", err
+	}
+	imagePath := filepath.Join(l.basePath(), filepath.FromSlash(imageName))
+	if err := os.MkdirAll(filepath.Dir(imagePath), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(imagePath, data, 0o644); err != nil {
+		return "", err
+	}
+	for _, variant := range images.Convert(context.TODO(), data) {
+		if err := os.WriteFile(imagePath+variant.Extension(), variant.Data, 0o644); err != nil {
+			return "", err
+		}
+	}
+	if l.PublicBaseURL != "" {
+		return imageURL(l.PublicBaseURL, imageName, data), nil
+	}
+	return imageURL("/images", imageName, data), nil
+}
+
+func (l *LocalImageStore) Load(ctx context.Context, imageURL string) ([]byte, error) {
+	name, err := imageName(imageURL)
+	if err != nil {
+		return nil, err
+	}
+	file, err := l.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+func (l *LocalImageStore) Delete(ctx context.Context, imageURL string) error {
+	name, err := imageName(imageURL)
+	if err != nil {
+		return err
+	}
+	imagePath := filepath.Join(l.basePath(), filepath.FromSlash(name))
+	for _, path := range append([]string{imagePath}, variantPaths(imagePath)...) {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *LocalImageStore) Quarantine(ctx context.Context, imageURL string) error {
+	name, err := imageName(imageURL)
+	if err != nil {
+		return err
+	}
+	quarantinePath := filepath.Join(l.basePath(), quarantineDir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(quarantinePath), 0o700); err != nil {
+		return err
+	}
+	imagePath := filepath.Join(l.basePath(), filepath.FromSlash(name))
+	if err := os.Rename(imagePath, quarantinePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	for _, path := range variantPaths(imagePath) {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// variantPaths returns the paths the variants of the image are stored at
+func variantPaths(imagePath string) []string {
+	paths := make([]string, len(images.Formats))
+	for i, format := range images.Formats {
+		paths[i] = imagePath + "." + format
+	}
+	return paths
+}
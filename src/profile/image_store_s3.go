@@ -1,12 +1,19 @@
 package profile
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"mime/multipart"
-	"os"
+	"io"
+	"mime"
+	"net/url"
+	"path"
 	"strings"
 
+	"profile-api/images"
+	"profile-api/store"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
@@ -15,6 +22,12 @@ import (
 type S3ImageStore struct {
 	Client     *s3.Client
 	BucketName string
+	Region     string
+	Endpoint   string
+	// PublicBaseURL is the URL of the CDN serving the bucket, used for image URLs in place of the bucket's own
+	PublicBaseURL string
+	// Private uploads images without public access, for a CDN with access to the bucket to serve
+	Private bool
 }
 
 func (s *S3ImageStore) InitBucketAndCORS(ctx context.Context) error {
@@ -23,8 +36,7 @@ func (s *S3ImageStore) InitBucketAndCORS(ctx context.Context) error {
 		Bucket: aws.String(s.BucketName),
 	})
 	if err != nil {
-		endpoint := os.Getenv("AWS_S3_ENDPOINT")
-		if endpoint != "" {
+		if s.Endpoint != "" {
 			// LocalStack: do NOT set CreateBucketConfiguration
 			_, createErr := s.Client.CreateBucket(ctx, &s3.CreateBucketInput{
 				Bucket: aws.String(s.BucketName),
@@ -33,7 +45,7 @@ func (s *S3ImageStore) InitBucketAndCORS(ctx context.Context) error {
 				return fmt.Errorf("unable to create S3 bucket: %w", createErr)
 			}
 		} else {
-			region := os.Getenv("AWS_REGION")
+			region := s.Region
 			input := &s3.CreateBucketInput{
 				Bucket: aws.String(s.BucketName),
 			}
@@ -70,36 +82,162 @@ func (s *S3ImageStore) InitBucketAndCORS(ctx context.Context) error {
 	return nil
 }
 
-func (s *S3ImageStore) SaveImage(userID, filename string, file multipart.File) (string, error) {
-	imageName := fmt.Sprintf("%s-%s", userID, filename)
+func (s *S3ImageStore) Ping(ctx context.Context) error {
+	_, err := s.Client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.BucketName),
+	})
+	return err
+}
+
+func (s *S3ImageStore) SaveImage(userID string, file io.Reader) (string, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	if data, err = images.Process(data); err != nil {
+		return "", err
+	}
+	imageName, err := newImageName(userID, data)
+	if err != nil {
+		return "", err
+	}
 
 	// Upload the file to S3
-	_, err := s.Client.PutObject(context.TODO(), &s3.PutObjectInput{
-		Bucket: aws.String(s.BucketName),
-		Key:    aws.String(imageName),
-		Body:   file,
-		ACL:    "public-read", // For LocalStack and public S3 access
+	_, err = s.Client.PutObject(context.TODO(), &s3.PutObjectInput{
+		Bucket:      aws.String(s.BucketName),
+		Key:         aws.String(imageName),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(mime.TypeByExtension(path.Ext(imageName))),
+		ACL:         s.acl(),
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to upload image to S3: %w", err)
 	}
+	// Upload the smaller variants next to the original, for a CDN to pick by the Accept header
+	for _, variant := range images.Convert(context.TODO(), data) {
+		_, err = s.Client.PutObject(context.TODO(), &s3.PutObjectInput{
+			Bucket:      aws.String(s.BucketName),
+			Key:         aws.String(imageName + variant.Extension()),
+			Body:        bytes.NewReader(variant.Data),
+			ContentType: aws.String(images.ContentType(variant.Format)),
+			ACL:         s.acl(),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to upload image variant to S3: %w", err)
+		}
+	}
 
+	return imageURL(s.baseURL(), imageName, data), nil
+}
+
+func (s *S3ImageStore) Load(ctx context.Context, imageURL string) ([]byte, error) {
+	name, err := imageName(imageURL)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.BucketName),
+		Key:    aws.String(name),
+	})
+	var noSuchKey *s3types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image from S3: %w", err)
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+func (s *S3ImageStore) Delete(ctx context.Context, imageURL string) error {
+	name, err := imageName(imageURL)
+	if err != nil {
+		return err
+	}
+	for _, key := range append([]string{name}, variantKeys(name)...) {
+		_, err = s.Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(s.BucketName),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove image from S3: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *S3ImageStore) Quarantine(ctx context.Context, imageURL string) error {
+	name, err := imageName(imageURL)
+	if err != nil {
+		return err
+	}
+	// Copied without public access, for an administrator to inspect
+	_, err = s.Client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(s.BucketName),
+		Key:        aws.String(quarantineDir + "/" + name),
+		CopySource: aws.String(url.PathEscape(s.BucketName) + "/" + escapeKey(name)),
+		ACL:        s3types.ObjectCannedACLPrivate,
+	})
+	var noSuchKey *s3types.NoSuchKey
+	if err != nil && !errors.As(err, &noSuchKey) {
+		return fmt.Errorf("failed to quarantine image in S3: %w", err)
+	}
+	for _, key := range append([]string{name}, variantKeys(name)...) {
+		_, err = s.Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(s.BucketName),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove quarantined image from S3: %w", err)
+		}
+	}
+	return nil
+}
+
+// escapeKey escapes each segment of the object key for a copy source
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// variantKeys returns the keys the variants of the image are stored under
+func variantKeys(name string) []string {
+	keys := make([]string, len(images.Formats))
+	for i, format := range images.Formats {
+		keys[i] = name + "." + format
+	}
+	return keys
+}
+
+// acl returns the access images are uploaded with, public unless they are served by a private CDN
+func (s *S3ImageStore) acl() s3types.ObjectCannedACL {
+	if s.Private {
+		return ""
+	}
+	// For LocalStack and public S3 access
+	return s3types.ObjectCannedACLPublicRead
+}
+
+// baseURL returns the URL the bucket's objects are served from, the CDN's when there is one
+func (s *S3ImageStore) baseURL() string {
+	if s.PublicBaseURL != "" {
+		return s.PublicBaseURL
+	}
 	// Construct the public URL
 	// For AWS S3: https://{bucket}.s3.{region}.amazonaws.com/{key}
 	// For LocalStack: http://localhost:4566/{bucket}/{key}
-	endpoint := os.Getenv("AWS_S3_ENDPOINT")
-	var imageURL string
+	endpoint := s.Endpoint
 	if endpoint != "" {
 		// Replace "localstack" with "localhost" for URLs returned to the frontend
 		publicEndpoint := endpoint
 		if strings.Contains(endpoint, "localstack") {
 			publicEndpoint = strings.Replace(endpoint, "localstack", "localhost", 1)
 		}
-		imageURL = fmt.Sprintf("%s/%s/%s", publicEndpoint, s.BucketName, imageName)
-	} else {
-		region := os.Getenv("AWS_REGION")
-		imageURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.BucketName, region, imageName)
+		return fmt.Sprintf("%s/%s", publicEndpoint, s.BucketName)
 	}
-
-	return imageURL, nil
-}
\ No newline at end of file
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", s.BucketName, s.Region)
+}
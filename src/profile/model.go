@@ -1,13 +1,108 @@
 package profile
 
+import (
+	"time"
+
+	"profile-api/awards"
+	"profile-api/certificates"
+	"profile-api/experience"
+	"profile-api/languages"
+	"profile-api/qualifications"
+	"profile-api/recommendations"
+	"profile-api/skills"
+	"profile-api/visibility"
+)
+
 // Profile represents a user's profile information
 type Profile struct {
-	UserID     string  `bson:"user_id"	json:"userid"`
-	Name       *string `bson:"name" json:"name"`
-	Email      *string `bson:"email" json:"email"`
-	Number     *string `bson:"number" json:"number"`
-	Bio        *string `bson:"bio" json:"bio"`
-	ProfileImg *string `bson:"profile_img" json:"profile_img"`
-	Interests  *string `bson:"interests" json:"interests"`
-	Domain     *string `bson:"domain" json:"domain"`
+	UserID     string  `bson:"user_id" json:"userid"`
+	Name       *string `bson:"name" json:"name" binding:"omitempty,max=100"`
+	Email      *string `bson:"email" json:"email" binding:"omitempty,email,max=254"`
+	Number     *string `bson:"number" json:"number" binding:"omitempty,max=30"`
+	Bio        *string `bson:"bio" json:"bio" binding:"omitempty,max=5000"`
+	ProfileImg *string `bson:"profile_img" json:"profile_img" binding:"omitempty,weburl,max=2048"`
+	Interests  *string `bson:"interests" json:"interests" binding:"omitempty,max=2000"`
+	Domain     *string `bson:"domain" json:"domain" binding:"omitempty,fqdn,max=253"`
+	// ImageQuarantined names the malware found in the last image uploaded, which was removed from the profile
+	ImageQuarantined string `bson:"image_quarantined,omitempty" json:"image_quarantined,omitempty" readonly:"true"`
+	// Availability is whether the user is open to work, which is set through its own endpoint
+	Availability *Availability `bson:"availability,omitempty" json:"availability,omitempty" readonly:"true"`
+
+	UpdatedAt *time.Time `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+	// Revision counts the changes to the profile, which the repository sets on every write
+	Revision int `bson:"revision,omitempty" json:"revision" readonly:"true"`
+	// Visibility maps fields to who they are shown to: public, members or private
+	Visibility visibility.Rules `bson:"visibility" json:"visibility,omitempty" binding:"omitempty,visibility"`
+}
+
+// Availability statuses, from the most eager
+const (
+	// AvailabilityOpen users are looking for work
+	AvailabilityOpen = "open"
+	// AvailabilityPassive users are not looking but will hear offers
+	AvailabilityPassive = "passive"
+	// AvailabilityClosed users are not interested in offers
+	AvailabilityClosed = "closed"
+)
+
+// Remote work preferences
+const (
+	RemoteOnsite = "onsite"
+	RemoteHybrid = "hybrid"
+	RemoteOnly   = "remote"
+)
+
+// Availability is whether a user is open to work, and the work they are after
+type Availability struct {
+	Status string `bson:"status" json:"status" binding:"required,oneof=open passive closed"`
+	// AvailableFrom is the date the user can start, empty when they can start straight away
+	AvailableFrom string   `bson:"available_from,omitempty" json:"available_from,omitempty" binding:"omitempty,date"`
+	Roles         []string `bson:"roles,omitempty" json:"roles,omitempty" binding:"max=20,dive,notblank,max=100"`
+	Locations     []string `bson:"locations,omitempty" json:"locations,omitempty" binding:"max=20,dive,notblank,max=100"`
+	Remote        string   `bson:"remote,omitempty" json:"remote,omitempty" binding:"omitempty,oneof=onsite hybrid remote"`
+}
+
+// Searchable reports whether the user wants to be found by recruiters
+func (a *Availability) Searchable() bool {
+	return a != nil && (a.Status == AvailabilityOpen || a.Status == AvailabilityPassive)
+}
+
+// defaultVisibility keeps the email address to the owner until they choose to share it
+var defaultVisibility = visibility.Rules{"email": visibility.Private}
+
+// Owner returns the ID of the user the profile belongs to
+func (p Profile) Owner() string {
+	return p.UserID
+}
+
+// FieldVisibility returns the rules of the profile's fields over the defaults
+func (p Profile) FieldVisibility() visibility.Rules {
+	return defaultVisibility.With(p.Visibility)
+}
+
+// Aggregate is a user's profile together with their CV sections
+type Aggregate struct {
+	Profile        Profile                        `json:"profile"`
+	Experience     []experience.Experience        `json:"experience"`
+	Qualifications []qualifications.Qualification `json:"qualifications"`
+	Certificates   []certificates.Certificate     `json:"certificates"`
+	Awards         []awards.Award                 `json:"awards"`
+	Languages      []languages.Language           `json:"languages"`
+	Skills         []skills.Skill                 `json:"skills"`
+	// Recommendations are those the user approved, newest first
+	Recommendations []recommendations.Recommendation `json:"recommendations"`
+}
+
+// CacheStats counts the lookups of full profiles in the cache since the replica started
+type CacheStats struct {
+	// Backend is memory, redis or disabled
+	Backend       string    `json:"backend"`
+	Since         time.Time `json:"since"`
+	Hits          int64     `json:"hits"`
+	Misses        int64     `json:"misses"`
+	Invalidations int64     `json:"invalidations"`
+	// HitRatio is the share of lookups served from the cache
+	HitRatio float64 `json:"hit_ratio"`
+	// Entries is the number of full profiles kept in memory, missing when they are kept in Redis
+	Entries *int `json:"entries,omitempty"`
 }
@@ -2,114 +2,230 @@ package profile
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"profile-api/apierror"
+	"profile-api/apiversion"
 	"profile-api/auth"
-
-	"github.com/aws/aws-sdk-go-v2/config"
+	"profile-api/billing"
+	"profile-api/config"
+	"profile-api/dryrun"
+	"profile-api/events"
+	"profile-api/features"
+	"profile-api/images"
+	"profile-api/logging"
+	"profile-api/quota"
+	"profile-api/scan"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+	"profile-api/visibility"
+	"strconv"
+	"strings"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var profilesCollection *mongo.Collection
+var profiles Repository
+
+var imageStore ImageStore
+
+// tenantImageStores holds the image store of each tenant in multi-tenant deployments
+var tenantImageStores = map[string]ImageStore{}
 
-type ErrorResponse struct {
-	Message string `json:"message"`
-	Error   string `json:"error"`
+// GetImageStore returns the image store of the context's tenant for use by other modules
+func GetImageStore(ctx context.Context) ImageStore {
+	if s, ok := tenantImageStores[tenant.ID(ctx)]; ok {
+		return s
+	}
+	return imageStore
 }
 
-var imageStore ImageStore
+// InitImageStore configures where uploaded images are stored. Tenants use their own S3 bucket when
+// they set one, and a subdirectory of the local path. They share the CDN only when they share the bucket.
+func InitImageStore(cfg config.ImageStoreConfig, tenants []config.TenantConfig) error {
+	s, err := newImageStore(cfg, cfg.S3.Bucket, cfg.LocalPath, cfg.CDN.BaseURL)
+	if err != nil {
+		return err
+	}
+	imageStore = s
 
-func InitImageStore() error {
-	storeType := os.Getenv("IMAGE_STORE")
-	if storeType == "s3" {
-		bucket := os.Getenv("S3_BUCKET")
-		region := os.Getenv("AWS_REGION")
-		accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
-		secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
-		endpoint := os.Getenv("AWS_S3_ENDPOINT") // For LocalStack, e.g. http://localstack:4566
+	for _, t := range tenants {
+		bucket := cfg.S3.Bucket
+		if t.S3Bucket != "" {
+			bucket = t.S3Bucket
+		}
+		cdnBaseURL := t.CDNBaseURL
+		if cdnBaseURL == "" && cfg.Type == "s3" && bucket == cfg.S3.Bucket {
+			cdnBaseURL = cfg.CDN.BaseURL
+		}
+		s, err := newImageStore(cfg, bucket, filepath.Join(cfg.LocalPath, t.ID), cdnBaseURL)
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", t.ID, err)
+		}
+		tenantImageStores[t.ID] = s
+	}
+	return nil
+}
+
+// newImageStore creates the configured type of image store, writing to the given bucket or local path and
+// handing out URLs under the CDN's base URL when there is one
+func newImageStore(cfg config.ImageStoreConfig, bucket, localPath, cdnBaseURL string) (ImageStore, error) {
+	if cfg.Type == "s3" {
+		endpoint := cfg.S3.Endpoint // For LocalStack, e.g. http://localstack:4566
 
 		// Custom AWS config for LocalStack or real AWS
-		cfg, err := config.LoadDefaultConfig(context.TODO(),
-			config.WithRegion(region),
-			config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.TODO(),
+			awsconfig.WithRegion(cfg.S3.Region),
+			awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(cfg.S3.AccessKeyID, cfg.S3.SecretAccessKey, "")),
 		)
 		if err != nil {
-			return fmt.Errorf("unable to load AWS config: %w", err)
+			return nil, fmt.Errorf("unable to load AWS config: %w", err)
 		}
 
 		// If using LocalStack, override the endpoint
 		var client *s3.Client
 		if endpoint != "" {
-			client = s3.NewFromConfig(cfg, func(o *s3.Options) {
+			client = s3.NewFromConfig(awsCfg, func(o *s3.Options) {
 				o.EndpointResolver = s3.EndpointResolverFromURL(endpoint)
 				o.UsePathStyle = true // Required for LocalStack
 			})
 		} else {
-			client = s3.NewFromConfig(cfg)
-		}
-
-		// Check if the bucket exists, create if not
-		_, err = client.HeadBucket(context.TODO(), &s3.HeadBucketInput{
-			Bucket: &bucket,
-		})
-		if err != nil {
-			_, createErr := client.CreateBucket(context.TODO(), &s3.CreateBucketInput{
-				Bucket: &bucket,
-			})
-			if createErr != nil {
-				return fmt.Errorf("unable to create S3 bucket: %w", createErr)
-			}
+			client = s3.NewFromConfig(awsCfg)
 		}
 
 		s3Store := &S3ImageStore{
-			Client:     client,
-			BucketName: bucket,
+			Client:        client,
+			BucketName:    bucket,
+			Region:        cfg.S3.Region,
+			Endpoint:      endpoint,
+			PublicBaseURL: cdnBaseURL,
+			Private:       cfg.CDN.Private,
 		}
-		// Call the method on the concrete type
+		// Create the bucket if it does not exist and apply the CORS policy
 		if err := s3Store.InitBucketAndCORS(context.TODO()); err != nil {
-			return err
+			return nil, err
 		}
-		// Now assign to the interface
-		imageStore = s3Store
-	} else {
-		basePath := os.Getenv("LOCAL_PATH")
-		imageStore = &LocalImageStore{BasePath: basePath}
+		return s3Store, nil
 	}
-	return nil
+
+	if localPath != cfg.LocalPath {
+		if err := os.MkdirAll(localPath, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &LocalImageStore{BasePath: localPath, PublicBaseURL: cdnBaseURL}, nil
 }
 
 // GetProfile retrieves the profile of the given user.
 //
 //	@Summary		Retrieve a user's profile.
-//	@Description	Retrieves the profile of the user with the specified user ID.
+//	@Description	Retrieves the profile of the user with the specified user ID. Fields are shown according to the profile's visibility rules: private fields, including the email address unless the owner shares it, are only returned to the owner and admins, and members fields to signed in users.
 //	@Tags			profile
 //	@Security		BearerAuth
 //	@ID				get-profile
 //	@Param			userid	path		string			true	"The ID of the user whose profile to get"
+//	@Param			fields	query		string			false	"Comma-separated fields to return, such as name,bio,profile_img"
 //	@Success		200		{object}	Profile			"Profile retrieved successfully"
-//	@Failure		401		{object}	ErrorResponse	"Not authenticated"
-//	@Failure		500		{object}	ErrorResponse	"Could not retrieve profile"
+//	@Header			200		{string}	ETag			"Revision of the profile, for If-Match when updating it"
+//	@Success		304		"Not modified"
+//	@Failure		400		{object}	apierror.Response	"Unknown field"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve profile"
 //	@Router			/profile/{userid} [get]
+//	@Router			/profile/{userid} [head]
 func GetProfile(c *gin.Context) {
 	userID := c.Param("userid")
+	fields, apiErr := utils.Fields(c, Profile{})
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return
+	}
 
-	var profile Profile
-	err := profilesCollection.FindOne(context.Background(), bson.M{"user_id": userID}).Decode(&profile)
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	profile, err := profiles.Get(store.WithFields(ctx, fields, "updated_at", "revision", "userid", "visibility"), userID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve profile"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve profile"))
 		return
 	}
 
-	// If the user is not the owner of the profile, do not return the email address
-	c.JSON(http.StatusOK, profile)
+	var lastModified time.Time
+	if profile.UpdatedAt != nil {
+		lastModified = *profile.UpdatedAt
+	}
+
+	// Fields are shown according to their visibility, so the response depends on who asks
+	c.Writer.Header().Add("Vary", "Cookie")
+	visible, err := visibility.Redact(visibility.ViewerOf(c), profile)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not encode response"))
+		return
+	}
+	body, err := utils.Project(visible, fields)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not encode response"))
+		return
+	}
+	utils.RevisionJSON(c, body, profile.Revision, lastModified)
+}
+
+// GetImage serves an image uploaded to the local image store.
+//
+//	@Summary		Retrieve an uploaded image.
+//	@Description	Serves an image saved by the local image store, as its AVIF or WebP variant when the Accept header allows one and the image has it. Images are named by the user's directory and a random name, such as 6650a1b2c3d4e5f6a7b8c9d0/0f1e2d3c4b5a69788796a5b4c3d2e1f0.jpg. Supports conditional requests using ETag and Last-Modified.
+//	@Tags			profile
+//	@ID				get-image
+//	@Param			name	path		string			true	"Image name, including the user's directory"
+//	@Success		200		{file}		binary			"Image"
+//	@Success		304		"Not modified"
+//	@Failure		404		{object}	apierror.Response	"Image not found"
+//	@Router			/images/{name} [get]
+//	@Router			/images/{name} [head]
+func GetImage(c *gin.Context) {
+	local, ok := GetImageStore(c.Request.Context()).(*LocalImageStore)
+	if !ok {
+		apierror.Abort(c, apierror.NotFound("Image not found"))
+		return
+	}
+
+	// Serve the smaller variant of the image in a format the client accepts, when there is one
+	if images.VariantsEnabled() {
+		c.Writer.Header().Add("Vary", "Accept")
+	}
+	name := strings.TrimPrefix(c.Param("name"), "/")
+	var file *os.File
+	var err error
+	for _, format := range images.Accepted(c.GetHeader("Accept")) {
+		if file, err = local.Open(name + "." + format); err == nil {
+			c.Header("Content-Type", images.ContentType(format))
+			break
+		}
+	}
+	if file == nil {
+		if file, err = local.Open(name); err != nil {
+			apierror.Abort(c, apierror.NotFound("Image not found"))
+			return
+		}
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		apierror.Abort(c, apierror.NotFound("Image not found"))
+		return
+	}
+
+	// ServeContent answers If-None-Match and If-Modified-Since with 304s
+	c.Header("ETag", `"`+strconv.FormatInt(info.ModTime().UnixNano(), 36)+"-"+strconv.FormatInt(info.Size(), 36)+`"`)
+	c.Header("Cache-Control", "public, max-age=86400")
+	http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), file)
 }
 
 // PutImage updates the profile image of the given user.
@@ -121,51 +237,90 @@ func GetProfile(c *gin.Context) {
 //	@ID				update-profile-image
 //	@Param			userid			path		string			true	"The ID of the user whose profile image to update"
 //	@Param			profileImage	formData	file			true	"Profile image to upload"
+//	@Param			If-Match		header		string			false	"ETag of the profile being changed, required unless require-if-match is off"
 //	@Success		200				{string}	string			"Profile image updated"
-//	@Failure		400				{object}	ErrorResponse	"Profile image not found"
-//	@Failure		401				{object}	ErrorResponse	"Not authenticated"
-//	@Failure		500				{object}	ErrorResponse	"Could not upload image"
+//	@Failure		400				{object}	apierror.Response	"Profile image not found"
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not the owner of the profile"
+//	@Failure		402				{object}	apierror.Response	"The user's plan has no storage left for the image"
+//	@Failure		412				{object}	apierror.Response	"Profile has been changed since it was read"
+//	@Failure		413				{object}	apierror.Response	"The user has no storage left for the image"
+//	@Failure		428				{object}	apierror.Response	"If-Match is required"
+//	@Failure		500				{object}	apierror.Response	"Could not upload image"
 //	@Router			/profile/{userid}/image [put]
 func PutImage(c *gin.Context) {
 	userID := c.Param("userid")
+	precondition, apiErr := utils.IfMatch(c)
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return
+	}
 
 	fileHeader, err := c.FormFile("profileImage")
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Profile image not found"})
+		apierror.Abort(c, apierror.BadRequest("Profile image not found"))
 		return
 	}
 	file, err := fileHeader.Open()
 	if err != nil {
-		log.Printf("Error opening file: %v", err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not open image"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not open image"))
 		return
 	}
 	defer file.Close()
 
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	// Check the revision before uploading, so a stale client does not leave an unused image behind
+	current, err := profiles.Get(ctx, userID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve profile"))
+		return
+	}
+	if !precondition.Allows(current.Revision) {
+		apierror.Abort(c, apierror.PreconditionFailed("Profile has been changed since it was read"))
+		return
+	}
+
+	imageStore := GetImageStore(c.Request.Context())
 	if imageStore == nil {
-		log.Println("Image store not initialized")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Image store not initialized"})
+		logging.Logger(c).Error("Image store not initialized")
+		apierror.Abort(c, apierror.Internal("Image store not initialized"))
 		return
 	}
 
-	imageURL, err := imageStore.SaveImage(userID, fileHeader.Filename, file)
+	upload := quota.NewUpload(userID, "profile", userID, "image", fileHeader.Size)
+	if err := quota.CheckUpload(ctx, c.MustGet("user").(auth.User), upload); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not check storage quota"))
+		return
+	}
+	imageURL, err := imageStore.SaveImage(userID, file)
+	if errors.Is(err, images.ErrInvalid) {
+		apierror.Abort(c, apierror.BadRequest("Invalid image"))
+		return
+	}
 	if err != nil {
-		log.Printf("Error saving image: %v", err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not upload image"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not upload image"))
 		return
 	}
+	if err := quota.RecordUpload(ctx, upload); err != nil {
+		logging.Logger(c).Error("Could not record upload", "error", err)
+	}
 
-	_, err = profilesCollection.UpdateOne(
-		context.Background(),
-		bson.M{"user_id": userID},
-		bson.M{"$set": bson.M{"profile_img": imageURL}},
-		options.Update().SetUpsert(true),
-	)
-	if err != nil {
-		log.Printf("Error updating profile image in database: %v", err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not update profile image"})
+	if err := profiles.SetImage(ctx, userID, imageURL, time.Now()); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update profile image"))
 		return
 	}
+	// Images are stored under new names, so the one replaced would otherwise stay behind. Only images the
+	// user uploaded are removed, not those their profile merely links to.
+	if previous := current.ProfileImg; previous != nil && *previous != imageURL && imageOwnedBy(*previous, userID) {
+		if err := imageStore.Delete(ctx, *previous); err != nil {
+			logging.Logger(c).Error("Could not remove replaced image", "error", err)
+		}
+	}
+	// The image is served until the scan finds it infected
+	if err := scan.Enqueue(ctx, scan.Upload{Kind: ScanKind, UserID: userID, URL: imageURL}); err != nil {
+		logging.Logger(c).Error("Could not queue image scan", "error", err)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"profileImage": imageURL})
 }
@@ -179,39 +334,62 @@ func PutImage(c *gin.Context) {
 //	@ID				update-profile
 //	@Param			userid	path		string			true	"The ID of the user whose profile to update"
 //	@Param			request	body		Profile			true	"Profile object that needs to be updated"
+//	@Param			If-Match	header	string			false	"ETag of the profile being updated, required unless require-if-match is off"
 //	@Success		200		{string}	string			"Profile updated"
-//	@Failure		400		{object}	ErrorResponse	"Invalid request body"
-//	@Failure		401		{object}	ErrorResponse	"Not authenticated"
-//	@Failure		500		{object}	ErrorResponse	"Could not update profile"
+//	@Header			200		{string}	ETag			"Revision of the updated profile, when If-Match named one"
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the profile"
+//	@Failure		402		{object}	apierror.Response	"The user's plan does not include a custom domain"
+//	@Failure		412		{object}	apierror.Response	"Profile has been changed since it was read"
+//	@Failure		428		{object}	apierror.Response	"If-Match is required"
+//	@Failure		500		{object}	apierror.Response	"Could not update profile"
 //	@Router			/profile/{userid} [put]
 func PutProfile(c *gin.Context) {
 	userID := c.Param("userid")
-
-	fmt.Println("Put Profile")
-	fmt.Println(userID)
+	precondition, apiErr := utils.IfMatch(c)
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return
+	}
 
 	var profile Profile
-	if err := c.BindJSON(&profile); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&profile); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 
 	profile.UserID = userID
+	now := time.Now()
+	profile.UpdatedAt = &now
 
-	// Print out the profile json encoded
-	profileJSON, err2 := json.Marshal(profile)
-	if err2 != nil {
-		log.Panicln("Error marshalling profile: ", err2)
-	}
-	fmt.Println(string(profileJSON))
+	logging.Logger(c).Debug("Put profile", "user_id", userID, "profile", profile)
 
 	// Update the profile in the database
-	_, err := profilesCollection.UpdateOne(context.Background(), bson.M{"user_id": userID}, bson.M{"$set": profile}, options.Update().SetUpsert(true))
-	if err != nil {
-		log.Panicln("Database Error: ", err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not update profile"})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := checkDomain(c, ctx, userID, profile.Domain); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update profile"))
 		return
 	}
+	if precondition.Conditional {
+		err := profiles.Replace(ctx, profile, precondition.Revision)
+		if errors.Is(err, store.ErrConflict) {
+			apierror.Abort(c, apierror.PreconditionFailed("Profile has been changed since it was read"))
+			return
+		}
+		if err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not update profile"))
+			return
+		}
+		// Another write may follow at any time, so the new revision is only known for a conditional write
+		profile.Revision = precondition.Revision + 1
+		c.Header("ETag", utils.RevisionETag(profile.Revision))
+	} else if err := profiles.Save(ctx, profile); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update profile"))
+		return
+	}
+	events.Publish(ctx, userID, events.TypeProfileUpdated, profile)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Profile updated"})
 }
@@ -225,45 +403,86 @@ func PutProfile(c *gin.Context) {
 //	@ID				create-profile
 //	@Param			userid	path		string			true	"The ID of the user for whom the profile is to be created"
 //	@Param			request	body		Profile			true	"Profile object that needs to be created"
-//	@Success		201		{string}	string			"Profile created"
-//	@Failure		400		{object}	ErrorResponse	"Invalid request body"
-//	@Failure		401		{object}	ErrorResponse	"Not authenticated"
-//	@Failure		500		{object}	ErrorResponse	"Could not create profile"
+//	@Success		201		{object}	Profile	"The created profile, and a message"
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the profile"
+//	@Failure		402		{object}	apierror.Response	"The user's plan does not include a custom domain"
+//	@Failure		500		{object}	apierror.Response	"Could not create profile"
 //	@Router			/profile/{userid} [post]
 func PostProfile(c *gin.Context) {
 	userID := c.Param("userid")
-	log.Printf("%v", userID)
 	var req Profile
-	if err := c.BindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 	req.UserID = userID
+	now := time.Now()
+	req.UpdatedAt = &now
 
-	_, err := profilesCollection.InsertOne(context.Background(), req)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not create profile"})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := checkDomain(c, ctx, userID, req.Domain); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create profile"))
+		return
+	}
+	// Registration creates an empty profile, so creating one fills it in rather than adding a second
+	if err := profiles.Save(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create profile"))
 		return
 	}
+	events.Publish(ctx, userID, events.TypeProfileUpdated, req)
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Profile created"})
+	c.JSON(http.StatusCreated, apiversion.Announce("Profile created", req))
+}
+
+// checkDomain refuses a custom domain the user's plan does not include, while letting users keep the domain
+// they set before changing plans
+func checkDomain(c *gin.Context, ctx context.Context, userID string, domain *string) error {
+	if domain == nil || *domain == "" {
+		return nil
+	}
+	user, _ := c.Get("user")
+	u, _ := user.(auth.User)
+	err := billing.Allow(u, billing.CustomDomain)
+	if err == nil {
+		return nil
+	}
+	current, getErr := profiles.Get(ctx, userID)
+	if getErr != nil && !errors.Is(getErr, store.ErrNotFound) {
+		return getErr
+	}
+	if current.Domain != nil && *current.Domain == *domain {
+		return nil
+	}
+	return err
+}
+
+// InitializeImageRoutes registers the route serving images from the local image store
+func InitializeImageRoutes(router gin.IRoutes) {
+	router.GET("/images/*name", GetImage)
+	router.HEAD("/images/*name", GetImage)
 }
 
 // InitializeRoutes initializes the profile routes.
-func InitializeRoutes(router *gin.RouterGroup, db *mongo.Client, db_name string) {
-	profilesCollection = db.Database(db_name).Collection("profiles")
+func InitializeRoutes(router *gin.RouterGroup, repo Repository, users auth.Repository) {
+	profiles = repo
 
-	router.GET("/:userid", GetProfile)
+	authOptional := auth.AuthMiddleware(users, false)
+	router.GET("/:userid", authOptional, GetProfile)
+	router.HEAD("/:userid", authOptional, GetProfile)
+	router.GET("/:userid/full", authOptional, GetFullProfile)
+	router.HEAD("/:userid/full", authOptional, GetFullProfile)
+	router.GET("/:userid/calendar.ics", GetCalendar)
+	router.GET("/:userid/availability", authOptional, GetAvailability)
 
 	protected := router.Group("/")
-	protected.Use(auth.AuthMiddleware(db, db_name, true))
-	protected.PUT("/:userid", PutProfile)
-	protected.PUT("/:userid/image", PutImage)
-	protected.POST("/:userid", PostProfile)
-}
-
-func init() {
-	if err := InitImageStore(); err != nil {
-		log.Fatalf("Failed to initialize image store: %v", err)
-	}
+	protected.Use(auth.AuthMiddleware(users, true))
+	protected.PUT("/:userid", dryrun.Supported(), auth.RequireOwner(), PutProfile)
+	protected.PUT("/:userid/image", auth.RequireOwner(), PutImage)
+	protected.PUT("/:userid/availability", dryrun.Supported(), auth.RequireOwner(), PutAvailability)
+	protected.DELETE("/:userid/availability", dryrun.Supported(), auth.RequireOwner(), DeleteAvailability)
+	protected.POST("/:userid", dryrun.Supported(), auth.RequireOwner(), PostProfile)
+	protected.POST("/:userid/generate-summary", auth.RequireOwner(), features.Require(features.AIProcessing), billing.Require(billing.AI), GenerateSummary)
 }
@@ -0,0 +1,27 @@
+package profile
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores user profiles
+type Repository interface {
+	// Get returns the user's profile, or store.ErrNotFound
+	Get(ctx context.Context, userID string) (Profile, error)
+	// GetMany returns the profiles of those users who have one
+	GetMany(ctx context.Context, userIDs []string) ([]Profile, error)
+	// Save replaces the user's profile, creating it if they do not have one yet
+	Save(ctx context.Context, profile Profile) error
+	// Replace replaces the user's profile only while it is at the revision, creating it at revision 0 if
+	// they do not have one yet. It returns store.ErrConflict when the profile has been changed since.
+	Replace(ctx context.Context, profile Profile, revision int) error
+	// SetImage sets the URL of the user's profile image, creating the profile if they do not have one yet
+	SetImage(ctx context.Context, userID, imageURL string, updatedAt time.Time) error
+	// SetAvailability sets whether the user is open to work, or clears it when nil, creating the profile if
+	// they do not have one yet
+	SetAvailability(ctx context.Context, userID string, availability *Availability, updatedAt time.Time) error
+	// QuarantineImage removes the image from the user's profile, unless it has since been replaced, and
+	// records the malware found in it
+	QuarantineImage(ctx context.Context, userID, imageURL, threat string) error
+}
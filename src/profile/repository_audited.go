@@ -0,0 +1,108 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"profile-api/audit"
+	"profile-api/store"
+)
+
+// AuditedRepository records every change to profiles in the audit log
+type AuditedRepository struct {
+	Repository
+}
+
+// NewAuditedRepository wraps the repository to record its changes in the audit log
+func NewAuditedRepository(r Repository) *AuditedRepository {
+	return &AuditedRepository{Repository: r}
+}
+
+func (r *AuditedRepository) Save(ctx context.Context, profile Profile) error {
+	before, err := r.Repository.Get(ctx, profile.UserID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.Save(ctx, profile); err != nil {
+		return err
+	}
+	profile.Revision = before.Revision + 1
+	profile.Availability = before.Availability
+	audit.RecordSave(ctx, "profile", profile.UserID, profile.UserID, before, existed, profile)
+	return nil
+}
+
+func (r *AuditedRepository) Replace(ctx context.Context, profile Profile, revision int) error {
+	before, err := r.Repository.Get(ctx, profile.UserID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.Replace(ctx, profile, revision); err != nil {
+		return err
+	}
+	profile.Revision = revision + 1
+	profile.Availability = before.Availability
+	audit.RecordSave(ctx, "profile", profile.UserID, profile.UserID, before, existed, profile)
+	return nil
+}
+
+func (r *AuditedRepository) SetImage(ctx context.Context, userID, imageURL string, updatedAt time.Time) error {
+	before, err := r.Repository.Get(ctx, userID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.SetImage(ctx, userID, imageURL, updatedAt); err != nil {
+		return err
+	}
+	after := before
+	after.UserID = userID
+	after.ProfileImg = &imageURL
+	after.UpdatedAt = &updatedAt
+	after.Revision++
+	audit.RecordSave(ctx, "profile", userID, userID, before, existed, after)
+	return nil
+}
+
+func (r *AuditedRepository) SetAvailability(ctx context.Context, userID string, availability *Availability, updatedAt time.Time) error {
+	before, err := r.Repository.Get(ctx, userID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.SetAvailability(ctx, userID, availability, updatedAt); err != nil {
+		return err
+	}
+	after := before
+	after.UserID = userID
+	after.Availability = availability
+	after.UpdatedAt = &updatedAt
+	after.Revision++
+	audit.RecordSave(ctx, "profile", userID, userID, before, existed, after)
+	return nil
+}
+
+func (r *AuditedRepository) QuarantineImage(ctx context.Context, userID, imageURL, threat string) error {
+	before, err := r.Repository.Get(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.QuarantineImage(ctx, userID, imageURL, threat); err != nil {
+		return err
+	}
+	if before.ProfileImg == nil || *before.ProfileImg != imageURL {
+		return nil
+	}
+	after := before
+	after.ProfileImg = nil
+	after.ImageQuarantined = threat
+	after.Revision++
+	audit.Record(ctx, audit.ActionUpdate, "profile", userID, userID, before, after)
+	return nil
+}
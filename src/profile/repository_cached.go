@@ -0,0 +1,74 @@
+package profile
+
+import (
+	"context"
+	"time"
+
+	"profile-api/cache"
+	"profile-api/store"
+)
+
+// CachedRepository serves profiles from the cache, dropping a user's cached profile whenever it is written
+type CachedRepository struct {
+	Repository
+	cache *cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedRepository wraps the repository with a cache keeping profiles for ttl
+func NewCachedRepository(r Repository, c *cache.Cache, ttl time.Duration) *CachedRepository {
+	return &CachedRepository{Repository: r, cache: c, ttl: ttl}
+}
+
+func (r *CachedRepository) Get(ctx context.Context, userID string) (Profile, error) {
+	// Only whole profiles are cached
+	if store.Fields(ctx) != nil {
+		return r.Repository.Get(ctx, userID)
+	}
+	key := cache.Key("profile", userID)
+	var p Profile
+	if r.cache.Get(ctx, key, &p) {
+		return p, nil
+	}
+	p, err := r.Repository.Get(ctx, userID)
+	if err != nil {
+		return p, err
+	}
+	r.cache.Set(ctx, key, p, r.ttl)
+	return p, nil
+}
+
+func (r *CachedRepository) Save(ctx context.Context, p Profile) error {
+	err := r.Repository.Save(ctx, p)
+	InvalidateCache(ctx, r.cache, p.UserID)
+	return err
+}
+
+func (r *CachedRepository) Replace(ctx context.Context, p Profile, revision int) error {
+	err := r.Repository.Replace(ctx, p, revision)
+	InvalidateCache(ctx, r.cache, p.UserID)
+	return err
+}
+
+func (r *CachedRepository) SetImage(ctx context.Context, userID, imageURL string, updatedAt time.Time) error {
+	err := r.Repository.SetImage(ctx, userID, imageURL, updatedAt)
+	InvalidateCache(ctx, r.cache, userID)
+	return err
+}
+
+func (r *CachedRepository) SetAvailability(ctx context.Context, userID string, availability *Availability, updatedAt time.Time) error {
+	err := r.Repository.SetAvailability(ctx, userID, availability, updatedAt)
+	InvalidateCache(ctx, r.cache, userID)
+	return err
+}
+
+func (r *CachedRepository) QuarantineImage(ctx context.Context, userID, imageURL, threat string) error {
+	err := r.Repository.QuarantineImage(ctx, userID, imageURL, threat)
+	InvalidateCache(ctx, r.cache, userID)
+	return err
+}
+
+// InvalidateCache drops the user's cached profile, such as after a write seen through change streams
+func InvalidateCache(ctx context.Context, c *cache.Cache, userID string) {
+	c.Delete(ctx, cache.Key("profile", userID))
+}
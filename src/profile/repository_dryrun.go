@@ -0,0 +1,64 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"profile-api/dryrun"
+	"profile-api/store"
+)
+
+// DryRunRepository leaves profiles unchanged in dry runs, reading them as usual. A replacement is still
+// checked against the profile's revision, so a dry run reports the conflict the write would have met.
+type DryRunRepository struct {
+	Repository
+}
+
+// NewDryRunRepository wraps the repository to skip its writes in dry runs
+func NewDryRunRepository(r Repository) *DryRunRepository {
+	return &DryRunRepository{Repository: r}
+}
+
+func (r *DryRunRepository) Save(ctx context.Context, profile Profile) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.Save(ctx, profile)
+}
+
+func (r *DryRunRepository) Replace(ctx context.Context, profile Profile, revision int) error {
+	if !dryrun.Active(ctx) {
+		return r.Repository.Replace(ctx, profile, revision)
+	}
+	// A missing profile is at revision 0
+	current, err := r.Repository.Get(ctx, profile.UserID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	if current.Revision != revision {
+		return store.ErrConflict
+	}
+	return nil
+}
+
+func (r *DryRunRepository) SetImage(ctx context.Context, userID, imageURL string, updatedAt time.Time) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.SetImage(ctx, userID, imageURL, updatedAt)
+}
+
+func (r *DryRunRepository) SetAvailability(ctx context.Context, userID string, availability *Availability, updatedAt time.Time) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.SetAvailability(ctx, userID, availability, updatedAt)
+}
+
+func (r *DryRunRepository) QuarantineImage(ctx context.Context, userID, imageURL, threat string) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.QuarantineImage(ctx, userID, imageURL, threat)
+}
@@ -0,0 +1,104 @@
+package profile
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps profiles in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	profiles map[string]Profile
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{profiles: map[string]Profile{}}
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, userID string) (Profile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	profile, ok := r.profiles[userID]
+	if !ok {
+		return Profile{}, store.ErrNotFound
+	}
+	return profile, nil
+}
+
+func (r *MemoryRepository) GetMany(ctx context.Context, userIDs []string) ([]Profile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var profiles []Profile
+	for _, userID := range userIDs {
+		if profile, ok := r.profiles[userID]; ok {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, nil
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, profile Profile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	profile.Revision = r.profiles[profile.UserID].Revision + 1
+	profile.ImageQuarantined = r.profiles[profile.UserID].ImageQuarantined
+	profile.Availability = r.profiles[profile.UserID].Availability
+	r.profiles[profile.UserID] = profile
+	return nil
+}
+
+func (r *MemoryRepository) Replace(ctx context.Context, profile Profile, revision int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.profiles[profile.UserID].Revision != revision {
+		return store.ErrConflict
+	}
+	profile.Revision = revision + 1
+	profile.ImageQuarantined = r.profiles[profile.UserID].ImageQuarantined
+	profile.Availability = r.profiles[profile.UserID].Availability
+	r.profiles[profile.UserID] = profile
+	return nil
+}
+
+func (r *MemoryRepository) SetImage(ctx context.Context, userID, imageURL string, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	profile := r.profiles[userID]
+	profile.UserID = userID
+	profile.ProfileImg = &imageURL
+	profile.ImageQuarantined = ""
+	profile.UpdatedAt = &updatedAt
+	profile.Revision++
+	r.profiles[userID] = profile
+	return nil
+}
+
+func (r *MemoryRepository) SetAvailability(ctx context.Context, userID string, availability *Availability, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	profile := r.profiles[userID]
+	profile.UserID = userID
+	profile.Availability = availability
+	profile.UpdatedAt = &updatedAt
+	profile.Revision++
+	r.profiles[userID] = profile
+	return nil
+}
+
+func (r *MemoryRepository) QuarantineImage(ctx context.Context, userID, imageURL, threat string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	profile, ok := r.profiles[userID]
+	if !ok || profile.ProfileImg == nil || *profile.ProfileImg != imageURL {
+		return nil
+	}
+	profile.ProfileImg = nil
+	profile.ImageQuarantined = threat
+	profile.Revision++
+	r.profiles[userID] = profile
+	return nil
+}
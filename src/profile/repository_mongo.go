@@ -0,0 +1,107 @@
+package profile
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores profiles in the profiles collection
+type MongoRepository struct {
+	profiles *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{profiles: db.Collection("profiles")}
+}
+
+func (r *MongoRepository) Get(ctx context.Context, userID string) (Profile, error) {
+	var profile Profile
+	err := r.profiles.FindOne(ctx, bson.M{"user_id": userID}, store.MongoFindOne(ctx, profile)).Decode(&profile)
+	return profile, store.MongoErr(err)
+}
+
+func (r *MongoRepository) GetMany(ctx context.Context, userIDs []string) ([]Profile, error) {
+	cursor, err := r.profiles.Find(ctx, bson.M{"user_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var profiles []Profile
+	if err := cursor.All(ctx, &profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func (r *MongoRepository) Save(ctx context.Context, profile Profile) error {
+	_, err := r.profiles.UpdateOne(ctx, bson.M{"user_id": profile.UserID}, saveUpdate(profile), options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) Replace(ctx context.Context, profile Profile, revision int) error {
+	filter := bson.M{"user_id": profile.UserID, "revision": store.MongoRevision(revision)}
+	// Only a missing profile is created, one at another revision fails the unique index on user_id
+	res, err := r.profiles.UpdateOne(ctx, filter, saveUpdate(profile), options.Update().SetUpsert(revision == 0))
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 && res.UpsertedCount == 0 {
+		return store.ErrConflict
+	}
+	return nil
+}
+
+// saveUpdate replaces the fields of the profile and moves it to the next revision
+func saveUpdate(profile Profile) bson.M {
+	// The omitted revision is left to $inc, the quarantine is only set by QuarantineImage and the
+	// availability by SetAvailability
+	profile.Revision = 0
+	profile.ImageQuarantined = ""
+	profile.Availability = nil
+	return bson.M{"$set": profile, "$inc": bson.M{"revision": 1}}
+}
+
+func (r *MongoRepository) SetImage(ctx context.Context, userID, imageURL string, updatedAt time.Time) error {
+	_, err := r.profiles.UpdateOne(
+		ctx,
+		bson.M{"user_id": userID},
+		bson.M{
+			"$set":   bson.M{"profile_img": imageURL, "updated_at": updatedAt},
+			"$unset": bson.M{"image_quarantined": ""},
+			"$inc":   bson.M{"revision": 1},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *MongoRepository) SetAvailability(ctx context.Context, userID string, availability *Availability, updatedAt time.Time) error {
+	update := bson.M{"$set": bson.M{"updated_at": updatedAt}, "$inc": bson.M{"revision": 1}}
+	if availability == nil {
+		update["$unset"] = bson.M{"availability": ""}
+	} else {
+		update["$set"] = bson.M{"availability": availability, "updated_at": updatedAt}
+	}
+	_, err := r.profiles.UpdateOne(ctx, bson.M{"user_id": userID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) QuarantineImage(ctx context.Context, userID, imageURL, threat string) error {
+	_, err := r.profiles.UpdateOne(
+		ctx,
+		bson.M{"user_id": userID, "profile_img": imageURL},
+		bson.M{"$set": bson.M{"profile_img": nil, "image_quarantined": threat}, "$inc": bson.M{"revision": 1}},
+	)
+	return err
+}
@@ -0,0 +1,101 @@
+package profile
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository stores profiles in the profiles table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, userID string) (Profile, error) {
+	var p Profile
+	err := r.pool.QueryRow(ctx, `SELECT user_id, name, email, number, bio, profile_img, interests, domain, image_quarantined, availability, updated_at, revision, visibility
+		FROM profiles WHERE user_id = $1`, userID).
+		Scan(&p.UserID, &p.Name, &p.Email, &p.Number, &p.Bio, &p.ProfileImg, &p.Interests, &p.Domain, &p.ImageQuarantined, &p.Availability, &p.UpdatedAt, &p.Revision, &p.Visibility)
+	return p, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) GetMany(ctx context.Context, userIDs []string) ([]Profile, error) {
+	rows, err := r.pool.Query(ctx, `SELECT user_id, name, email, number, bio, profile_img, interests, domain, image_quarantined, availability, updated_at, revision, visibility
+		FROM profiles WHERE user_id = ANY($1)`, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Profile, error) {
+		var p Profile
+		err := row.Scan(&p.UserID, &p.Name, &p.Email, &p.Number, &p.Bio, &p.ProfileImg, &p.Interests, &p.Domain, &p.ImageQuarantined, &p.Availability, &p.UpdatedAt, &p.Revision, &p.Visibility)
+		return p, err
+	})
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, p Profile) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO profiles (user_id, name, email, number, bio, profile_img, interests, domain, updated_at, visibility)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (user_id) DO UPDATE SET
+			name = EXCLUDED.name, email = EXCLUDED.email, number = EXCLUDED.number, bio = EXCLUDED.bio,
+			profile_img = EXCLUDED.profile_img, interests = EXCLUDED.interests, domain = EXCLUDED.domain,
+			updated_at = EXCLUDED.updated_at, visibility = EXCLUDED.visibility, revision = profiles.revision + 1`,
+		p.UserID, p.Name, p.Email, p.Number, p.Bio, p.ProfileImg, p.Interests, p.Domain, p.UpdatedAt, p.Visibility)
+	return err
+}
+
+func (r *PostgresRepository) Replace(ctx context.Context, p Profile, revision int) error {
+	query := `UPDATE profiles SET name = $2, email = $3, number = $4, bio = $5, profile_img = $6, interests = $7,
+		domain = $8, updated_at = $9, visibility = $11, revision = revision + 1
+		WHERE user_id = $1 AND revision = $10`
+	if revision == 0 {
+		// Only a missing profile is created, the update does not match one at another revision
+		query = `INSERT INTO profiles (user_id, name, email, number, bio, profile_img, interests, domain, updated_at, visibility, revision)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $11, 1)
+		ON CONFLICT (user_id) DO UPDATE SET
+			name = EXCLUDED.name, email = EXCLUDED.email, number = EXCLUDED.number, bio = EXCLUDED.bio,
+			profile_img = EXCLUDED.profile_img, interests = EXCLUDED.interests, domain = EXCLUDED.domain,
+			updated_at = EXCLUDED.updated_at, visibility = EXCLUDED.visibility, revision = profiles.revision + 1
+		WHERE profiles.revision = $10`
+	}
+	tag, err := r.pool.Exec(ctx, query,
+		p.UserID, p.Name, p.Email, p.Number, p.Bio, p.ProfileImg, p.Interests, p.Domain, p.UpdatedAt, revision, p.Visibility)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrConflict
+	}
+	return nil
+}
+
+func (r *PostgresRepository) SetImage(ctx context.Context, userID, imageURL string, updatedAt time.Time) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO profiles (user_id, profile_img, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET profile_img = EXCLUDED.profile_img, image_quarantined = '',
+			updated_at = EXCLUDED.updated_at, revision = profiles.revision + 1`,
+		userID, imageURL, updatedAt)
+	return err
+}
+
+func (r *PostgresRepository) SetAvailability(ctx context.Context, userID string, availability *Availability, updatedAt time.Time) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO profiles (user_id, availability, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET availability = EXCLUDED.availability,
+			updated_at = EXCLUDED.updated_at, revision = profiles.revision + 1`,
+		userID, availability, updatedAt)
+	return err
+}
+
+func (r *PostgresRepository) QuarantineImage(ctx context.Context, userID, imageURL, threat string) error {
+	_, err := r.pool.Exec(ctx, `UPDATE profiles SET profile_img = NULL, image_quarantined = $3, revision = revision + 1
+		WHERE user_id = $1 AND profile_img = $2`,
+		userID, imageURL, threat)
+	return err
+}
@@ -0,0 +1,37 @@
+package profile
+
+import (
+	"context"
+
+	"profile-api/sanitize"
+)
+
+// SanitizedRepository cleans the user-supplied text of profiles before storing them
+type SanitizedRepository struct {
+	Repository
+}
+
+// NewSanitizedRepository wraps the repository to clean profiles with the configured sanitize policies
+func NewSanitizedRepository(r Repository) *SanitizedRepository {
+	return &SanitizedRepository{Repository: r}
+}
+
+func (r *SanitizedRepository) Save(ctx context.Context, profile Profile) error {
+	return r.Repository.Save(ctx, sanitized(profile))
+}
+
+func (r *SanitizedRepository) Replace(ctx context.Context, profile Profile, revision int) error {
+	return r.Repository.Replace(ctx, sanitized(profile), revision)
+}
+
+func sanitized(profile Profile) Profile {
+	if profile.Bio != nil {
+		bio := sanitize.Field(sanitize.ProfileBio, *profile.Bio)
+		profile.Bio = &bio
+	}
+	if profile.Interests != nil {
+		interests := sanitize.Field(sanitize.ProfileInterests, *profile.Interests)
+		profile.Interests = &interests
+	}
+	return profile
+}
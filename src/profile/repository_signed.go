@@ -0,0 +1,58 @@
+package profile
+
+import "context"
+
+// SignedRepository hands out profile images served by a private CDN with signed URLs, and stores them
+// without the signature when a profile read is saved back
+type SignedRepository struct {
+	Repository
+	signer *URLSigner
+}
+
+// NewSignedRepository wraps the repository to sign the URLs of profile images with the signer
+func NewSignedRepository(r Repository, signer *URLSigner) *SignedRepository {
+	return &SignedRepository{Repository: r, signer: signer}
+}
+
+func (r *SignedRepository) Get(ctx context.Context, userID string) (Profile, error) {
+	profile, err := r.Repository.Get(ctx, userID)
+	if err != nil {
+		return profile, err
+	}
+	return r.sign(profile), nil
+}
+
+func (r *SignedRepository) GetMany(ctx context.Context, userIDs []string) ([]Profile, error) {
+	profiles, err := r.Repository.GetMany(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i, profile := range profiles {
+		profiles[i] = r.sign(profile)
+	}
+	return profiles, nil
+}
+
+func (r *SignedRepository) Save(ctx context.Context, profile Profile) error {
+	return r.Repository.Save(ctx, r.unsign(profile))
+}
+
+func (r *SignedRepository) Replace(ctx context.Context, profile Profile, revision int) error {
+	return r.Repository.Replace(ctx, r.unsign(profile), revision)
+}
+
+func (r *SignedRepository) sign(profile Profile) Profile {
+	if profile.ProfileImg != nil {
+		signed := r.signer.Sign(*profile.ProfileImg)
+		profile.ProfileImg = &signed
+	}
+	return profile
+}
+
+func (r *SignedRepository) unsign(profile Profile) Profile {
+	if profile.ProfileImg != nil {
+		unsigned := r.signer.Unsign(*profile.ProfileImg)
+		profile.ProfileImg = &unsigned
+	}
+	return profile
+}
@@ -0,0 +1,46 @@
+package profile
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Get(ctx context.Context, userID string) (Profile, error) {
+	return r.repos.For(ctx).Get(ctx, userID)
+}
+
+func (r *TenantRepository) GetMany(ctx context.Context, userIDs []string) ([]Profile, error) {
+	return r.repos.For(ctx).GetMany(ctx, userIDs)
+}
+
+func (r *TenantRepository) Save(ctx context.Context, profile Profile) error {
+	return r.repos.For(ctx).Save(ctx, profile)
+}
+
+func (r *TenantRepository) Replace(ctx context.Context, profile Profile, revision int) error {
+	return r.repos.For(ctx).Replace(ctx, profile, revision)
+}
+
+func (r *TenantRepository) SetImage(ctx context.Context, userID, imageURL string, updatedAt time.Time) error {
+	return r.repos.For(ctx).SetImage(ctx, userID, imageURL, updatedAt)
+}
+
+func (r *TenantRepository) SetAvailability(ctx context.Context, userID string, availability *Availability, updatedAt time.Time) error {
+	return r.repos.For(ctx).SetAvailability(ctx, userID, availability, updatedAt)
+}
+
+func (r *TenantRepository) QuarantineImage(ctx context.Context, userID, imageURL, threat string) error {
+	return r.repos.For(ctx).QuarantineImage(ctx, userID, imageURL, threat)
+}
@@ -0,0 +1,41 @@
+package profile
+
+import (
+	"context"
+
+	"profile-api/scan"
+	"profile-api/store"
+)
+
+// ScanKind is the kind of upload profile images are scanned as
+const ScanKind = "profile-image"
+
+// scanTarget loads profile images from the image store for scanning, and quarantines infected ones
+type scanTarget struct {
+	profiles Repository
+}
+
+// NewScanTarget creates the target scanning profile images. The repository must hand out image URLs as
+// stored, without signatures.
+func NewScanTarget(r Repository) scan.Target {
+	return &scanTarget{profiles: r}
+}
+
+func (t *scanTarget) Load(ctx context.Context, upload scan.Upload) ([]byte, error) {
+	profile, err := t.profiles.Get(ctx, upload.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// A replaced image is no longer referenced, its replacement is scanned in turn
+	if profile.ProfileImg == nil || *profile.ProfileImg != upload.URL {
+		return nil, store.ErrNotFound
+	}
+	return GetImageStore(ctx).Load(ctx, upload.URL)
+}
+
+func (t *scanTarget) Quarantine(ctx context.Context, upload scan.Upload, threat string) error {
+	if err := GetImageStore(ctx).Quarantine(ctx, upload.URL); err != nil {
+		return err
+	}
+	return t.profiles.QuarantineImage(ctx, upload.UserID, upload.URL, threat)
+}
@@ -0,0 +1,210 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"profile-api/ai"
+	"profile-api/apierror"
+	"profile-api/experience"
+	"profile-api/qualifications"
+	"profile-api/skills"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// summaryTones describes each tone a summary can be written in
+var summaryTones = map[string]string{
+	"professional": "professional and confident",
+	"friendly":     "warm and approachable",
+	"formal":       "formal and understated",
+	"enthusiastic": "energetic and enthusiastic",
+}
+
+// summaryLengths gives the rough number of words of each length of summary
+var summaryLengths = map[string]int{
+	"short":  50,
+	"medium": 120,
+	"long":   250,
+}
+
+const summaryInstructions = `You write the headline and bio of a person's profile from their experience, qualifications and skills. Answer with a JSON object with:
+"headline", a single line of at most 120 characters naming their role and what they are known for, such as "Backend engineer building payment systems in Go";
+"bio", paragraphs in the first person separated by blank lines, as plain text.
+Only state what the records show, without inventing employers, achievements or numbers.`
+
+// SummarySources are where the records a summary is written from are read
+type SummarySources struct {
+	Experience     experience.Repository
+	Qualifications qualifications.Repository
+	Skills         skills.Repository
+}
+
+var summarySources SummarySources
+
+// SetSummarySources sets where the records summaries are written from are read
+func SetSummarySources(s SummarySources) {
+	summarySources = s
+}
+
+// SummaryRequest holds the options of a generated summary
+type SummaryRequest struct {
+	// Tone is professional, friendly, formal or enthusiastic, defaulting to professional
+	Tone string `json:"tone" binding:"omitempty,oneof=professional friendly formal enthusiastic"`
+	// Length is short, medium or long, defaulting to medium
+	Length string `json:"length" binding:"omitempty,oneof=short medium long"`
+}
+
+// Summary is a generated headline and bio, for the user to edit and save to their profile
+type Summary struct {
+	Headline string `json:"headline"`
+	Bio      string `json:"bio"`
+}
+
+// GenerateSummary writes a headline and bio for the user from their experience, qualifications and skills.
+//
+//	@Summary		Generate a profile summary.
+//	@Description	Has the AI provider write a headline and bio from the user's experience, qualifications and skills, in the tone and length asked for. Nothing is stored: the user saves the bio to their profile once they have edited it. Each summary counts against the user's AI request quota. Only available when an AI provider is configured, the ai-processing feature is enabled for the user and their plan includes AI features.
+//	@Tags			profile
+//	@Security		BearerAuth
+//	@ID				generate-summary
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string				true	"The ID of the user to write the summary for"
+//	@Param			request	body		SummaryRequest		false	"Tone and length of the summary"
+//	@Success		200		{object}	Summary				"Generated summary"
+//	@Failure		400		{object}	apierror.Response	"Invalid options"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the profile"
+//	@Failure		402		{object}	apierror.Response	"The user's plan does not include AI features"
+//	@Failure		404		{object}	apierror.Response	"Not found, when the feature is disabled"
+//	@Failure		422		{object}	apierror.Response	"The user has no records to write a summary from"
+//	@Failure		429		{object}	apierror.Response	"AI request quota used up"
+//	@Failure		500		{object}	apierror.Response	"Could not generate summary"
+//	@Failure		503		{object}	apierror.Response	"No AI provider is configured"
+//	@Router			/profile/{userid}/generate-summary [post]
+func GenerateSummary(c *gin.Context) {
+	userID := c.Param("userid")
+	if !ai.Enabled() {
+		apierror.Abort(c, apierror.New(http.StatusServiceUnavailable, apierror.CodeServiceUnavailable, "No AI provider is configured"))
+		return
+	}
+
+	// The options are optional, so an empty body asks for the defaults
+	req := SummaryRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	if req.Tone == "" {
+		req.Tone = "professional"
+	}
+	if req.Length == "" {
+		req.Length = "medium"
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	prompt, err := summaryPrompt(ctx, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve profile records"))
+		return
+	}
+	if prompt == "" {
+		apierror.Abort(c, apierror.Unprocessable("Add experience, qualifications or skills to write a summary from"))
+		return
+	}
+	if err := ai.Use(ctx, userID); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not check AI request quota"))
+		return
+	}
+	cancel()
+
+	prompt += fmt.Sprintf("\nWrite the bio in about %d words, in a %s tone.\n", summaryLengths[req.Length], summaryTones[req.Tone])
+	var summary Summary
+	if err := ai.CompleteJSON(c.Request.Context(), ai.Request{System: summaryInstructions, Prompt: prompt}, &summary); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not generate summary"))
+		return
+	}
+	summary.Headline = truncate(strings.TrimSpace(summary.Headline), 150)
+	summary.Bio = truncate(strings.TrimSpace(summary.Bio), 5000)
+	c.JSON(http.StatusOK, summary)
+}
+
+// summaryPrompt describes the user's records, or returns empty when they have none
+func summaryPrompt(ctx context.Context, userID string) (string, error) {
+	roles, err := summarySources.Experience.List(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	quals, err := summarySources.Qualifications.List(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	userSkills, err := summarySources.Skills.List(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if len(roles) == 0 && len(quals) == 0 && len(userSkills) == 0 {
+		return "", nil
+	}
+
+	var prompt strings.Builder
+	profile, err := profiles.Get(ctx, userID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return "", err
+	}
+	if profile.Name != nil && *profile.Name != "" {
+		fmt.Fprintf(&prompt, "Name: %s\n", *profile.Name)
+	}
+	if profile.Interests != nil && *profile.Interests != "" {
+		fmt.Fprintf(&prompt, "Interests: %s\n", *profile.Interests)
+	}
+	if len(roles) > 0 {
+		prompt.WriteString("\nExperience:\n")
+		for _, role := range roles {
+			fmt.Fprintf(&prompt, "- %s at %s, from %s to %s\n", role.Position, role.Company, role.Start, orPresent(role.End))
+			if role.Description != "" {
+				fmt.Fprintf(&prompt, "  %s\n", role.Description)
+			}
+		}
+	}
+	if len(quals) > 0 {
+		prompt.WriteString("\nQualifications:\n")
+		for _, q := range quals {
+			fmt.Fprintf(&prompt, "- %s, %s, from %s to %s\n", q.Title, q.Institution, q.Start, orPresent(q.End))
+		}
+	}
+	if len(userSkills) > 0 {
+		prompt.WriteString("\nSkills:\n")
+		for _, s := range userSkills {
+			if s.ProficiencyLevel != "" {
+				fmt.Fprintf(&prompt, "- %s (%s)\n", s.Name, s.ProficiencyLevel)
+			} else {
+				fmt.Fprintf(&prompt, "- %s\n", s.Name)
+			}
+		}
+	}
+	return prompt.String(), nil
+}
+
+func orPresent(end string) string {
+	if end == "" {
+		return "present"
+	}
+	return end
+}
+
+// truncate cuts the text to at most max bytes without splitting a character
+func truncate(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	return strings.ToValidUTF8(text[:max], "")
+}
@@ -1,12 +1,28 @@
 package qualifications
 
+import "profile-api/visibility"
+
 // Qualification represents a user's qualification
 type Qualification struct {
 	UserID          string `bson:"user_id" json:"user_id"`
 	QualificationID string `bson:"qualification_id" json:"qualification_id"`
-	Title           string `bson:"title" json:"title"`
-	Institution     string `bson:"institution" json:"institution"`
-	Start           string `bson:"start" json:"start"`
-	End             string `bson:"end" json:"end"`
-	Description     string `bson:"description" json:"description"`
+	Title           string `bson:"title" json:"title" binding:"required,notblank,max=200"`
+	Institution     string `bson:"institution" json:"institution" binding:"required,notblank,max=200"`
+	Start           string `bson:"start" json:"start" binding:"omitempty,date"`
+	End             string `bson:"end" json:"end" binding:"omitempty,date"`
+	Description     string `bson:"description" json:"description" binding:"max=5000"`
+	// CertImageQuarantined names the malware found in the last certificate image uploaded, which was removed
+	CertImageQuarantined string `bson:"cert_image_quarantined,omitempty" json:"cert_image_quarantined,omitempty" readonly:"true"`
+	// Visibility maps fields to who they are shown to: public, members or private
+	Visibility visibility.Rules `bson:"visibility" json:"visibility,omitempty" binding:"omitempty,visibility"`
+}
+
+// Owner returns the ID of the user the qualification belongs to
+func (q Qualification) Owner() string {
+	return q.UserID
+}
+
+// FieldVisibility returns the rules of the qualification's fields
+func (q Qualification) FieldVisibility() visibility.Rules {
+	return q.Visibility
 }
@@ -2,89 +2,80 @@ package qualifications
 
 import (
 	"context"
-	"log"
-	"net/http"
+	"errors"
+	"io"
 
+	"profile-api/apierror"
+	"profile-api/apiversion"
 	"profile-api/auth"
+	"profile-api/bulk"
+	"profile-api/dryrun"
+	"profile-api/images"
+	"profile-api/logging"
+	"profile-api/quota"
+	"profile-api/scan"
+	"profile-api/store"
+	"profile-api/utils"
+	"profile-api/visibility"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var qualificationsCollection *mongo.Collection
-
-// ErrorResponse is a struct that represents an error response.
-//
-// swagger:model ErrorResponse
-type ErrorResponse struct {
-	// Error message
-	// example: Invalid request body
-	Error string `json:"error"`
-}
+var repo Repository
 
 // GetQualifications retrieves all qualifications for a specific user.
 //
 //	@Summary		Get all qualifications for a user.
-//	@Description	Retrieves all qualifications associated with the specified user ID.
+//	@Description	Retrieves all qualifications associated with the specified user ID, without the fields their visibility hides from the requester.
 //	@tags			Qualifications
 //	@Security		BearerAuth
 //	@ID				get-qualifications
 //	@Param			userid	path		string	true	"The ID of the user whose qualifications are to be retrieved"
 //	@Success		200		{array}		Qualification
-//	@Failure		401		{object}	ErrorResponse	"Not authenticated"
-//	@Failure		500		{object}	ErrorResponse	"Could not retrieve qualifications"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve qualifications"
 //	@Router			/qualifications/{userid} [get]
 func GetQualifications(c *gin.Context) {
 	userID := c.Param("userid")
 
-	var qualifications []Qualification
-	cursor, err := qualificationsCollection.Find(context.Background(), bson.M{"user_id": userID})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	qualifications, err := repo.List(ctx, userID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve qualifications"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve qualifications"))
 		return
 	}
-	defer cursor.Close(context.Background())
-	for cursor.Next(context.Background()) {
-		var qualification Qualification
-		err := cursor.Decode(&qualification)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve qualifications"})
-			return
-		}
-		qualifications = append(qualifications, qualification)
-	}
 
-	c.JSON(http.StatusOK, qualifications)
+	visibility.List(c, qualifications)
 }
 
 // GetQualificationEntry retrieves a specific qualification for a user.
 //
 //	@Summary		Get a specific qualification for a user.
-//	@Description	Retrieves the qualification entry associated with the specified user ID and qualification ID.
+//	@Description	Retrieves the qualification entry associated with the specified user ID and qualification ID, without the fields its visibility hides from the requester.
 //	@tags			Qualifications
 //	@Security		BearerAuth
 //	@ID				get-qualification-entry
 //	@Param			userid			path		string	true	"The ID of the user whose qualification is to be retrieved"
 //	@Param			qualificationid	path		string	true	"The ID of the qualification to be retrieved"
 //	@Success		200				{object}	Qualification
-//	@Failure		401				{object}	ErrorResponse	"Not authenticated"
-//	@Failure		500				{object}	ErrorResponse	"Could not retrieve qualification"
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		500				{object}	apierror.Response	"Could not retrieve qualification"
 //	@Router			/qualifications/{userid}/{qualificationid} [get]
 func GetQualificationEntry(c *gin.Context) {
 	userID := c.Param("userid")
 	qualificationID := c.Param("qualificationid")
 
-	var qualification Qualification
-	err := qualificationsCollection.FindOne(context.Background(), bson.M{"user_id": userID, "qualification_id": qualificationID}).Decode(&qualification)
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	qualification, err := repo.Get(ctx, userID, qualificationID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve qualification"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve qualification"))
 		return
 	}
 
-	c.JSON(http.StatusOK, qualification)
+	visibility.OK(c, qualification)
 }
 
 // PutQualificationEntry updates a specific qualification for a user.
@@ -98,29 +89,31 @@ func GetQualificationEntry(c *gin.Context) {
 //	@Param			qualificationid	path		string			true	"The ID of the qualification to be updated"
 //	@Param			request			body		Qualification	true	"Qualification object that needs to be updated"
 //	@Success		200				{string}	string			"Qualification updated"
-//	@Failure		400				{object}	ErrorResponse	"Invalid request body"
-//	@Failure		401				{object}	ErrorResponse	"Not authenticated"
-//	@Failure		500				{object}	ErrorResponse	"Could not update qualification"
+//	@Failure		400				{object}	apierror.Response	"Invalid request body"
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not the owner of the qualifications"
+//	@Failure		500				{object}	apierror.Response	"Could not update qualification"
 //	@Router			/qualifications/{userid}/{qualificationid} [put]
 func PutQualificationEntry(c *gin.Context) {
 	userID := c.Param("userid")
 	qualificationID := c.Param("qualificationid")
 
 	var req Qualification
-	if err := c.BindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 	req.UserID = userID
 	req.QualificationID = qualificationID
 
-	_, err := qualificationsCollection.UpdateOne(context.Background(), bson.M{"user_id": userID, "qualification_id": qualificationID}, bson.M{"$set": req}, options.Update().SetUpsert(true))
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not update qualification"})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Save(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update qualification"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Qualification updated"})
+	apiversion.Updated(c, req, gin.H{"message": "Qualification updated"})
 }
 
 // DeleteQualificationEntry deletes a specific qualification for a user.
@@ -133,20 +126,71 @@ func PutQualificationEntry(c *gin.Context) {
 //	@Param			userid			path		string			true	"The ID of the user whose qualification is to be deleted"
 //	@Param			qualificationid	path		string			true	"The ID of the qualification to be deleted"
 //	@Success		200				{string}	string			"Qualification deleted"
-//	@Failure		401				{object}	ErrorResponse	"Not authenticated"
-//	@Failure		500				{object}	ErrorResponse	"Could not delete qualification"
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not the owner of the qualifications"
+//	@Failure		500				{object}	apierror.Response	"Could not delete qualification"
+//	@Failure		404				{object}	apierror.Response	"Qualification not found"
 //	@Router			/qualifications/{userid}/{qualificationid} [delete]
 func DeleteQualificationEntry(c *gin.Context) {
 	userID := c.Param("userid")
 	qualificationID := c.Param("qualificationid")
 
-	_, err := qualificationsCollection.DeleteOne(context.Background(), bson.M{"user_id": userID, "qualification_id": qualificationID})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	err := repo.Delete(ctx, userID, qualificationID)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Qualification not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete qualification"))
+		return
+	}
+
+	apiversion.NoContent(c, gin.H{"message": "Qualification deleted"})
+}
+
+// BulkDeleteQualifications deletes many of a user's qualifications at once.
+//
+//	@Summary		Delete qualifications in bulk
+//	@Description	Deletes the user's qualifications with the given IDs, or those whose fields match every value of the filter, such as {"institution": "Open University"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.
+//	@tags			Qualifications
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string				true	"User ID"
+//	@Param			body	body		bulk.DeleteRequest	true	"The qualifications to delete"
+//	@Success		200		{object}	bulk.DeleteResult
+//	@Failure		400		{object}	apierror.Response	"Invalid request, or a filter that is not confirmed"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the qualifications"
+//	@Failure		500		{object}	apierror.Response	"Could not delete qualifications"
+//	@Router			/qualifications/{userid}/bulk-delete [post]
+func BulkDeleteQualifications(c *gin.Context) {
+	userID := c.Param("userid")
+
+	var req bulk.DeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	items, err := repo.List(ctx, userID)
+	cancel()
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not delete qualification"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve qualifications"))
+		return
+	}
+	result, err := bulk.Delete(c.Request.Context(), req, items, func(item Qualification) string { return item.QualificationID }, func(ctx context.Context, id string) error {
+		return repo.Delete(ctx, userID, id)
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete qualifications"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Qualification deleted"})
+	apiversion.OK(c, result)
 }
 
 // PutQualificationImage uploads a certificate image for a specific qualification.
@@ -161,9 +205,12 @@ func DeleteQualificationEntry(c *gin.Context) {
 //	@Param			qualificationid	path		string			true	"The ID of the qualification whose certificate image is to be updated"
 //	@Param			file			formData	file			true	"Certificate image file to upload"
 //	@Success		200				{string}	string			"cert image uploaded"
-//	@Failure		400				{object}	ErrorResponse	"invalid request body"
-//	@Failure		401				{object}	ErrorResponse	"Not authenticated"
-//	@Failure		500				{object}	ErrorResponse	"could not update qualification"
+//	@Failure		400				{object}	apierror.Response	"invalid request body"
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Not the owner of the qualifications"
+//	@Failure		402				{object}	apierror.Response	"The user's plan has no storage left for the image"
+//	@Failure		413				{object}	apierror.Response	"The user has no storage left for the image"
+//	@Failure		500				{object}	apierror.Response	"could not update qualification"
 //	@Router			/qualifications/{userid}/{qualificationid}/cert_image [put]
 func PutQualificationImage(c *gin.Context) {
 	userID := c.Param("userid")
@@ -171,24 +218,51 @@ func PutQualificationImage(c *gin.Context) {
 
 	file, err := c.FormFile("file")
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		apierror.Abort(c, apierror.BadRequest("invalid request body"))
 		return
 	}
 
-	FileBytes, err := file.Open()
+	fileReader, err := file.Open()
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		apierror.Abort(c, apierror.BadRequest("invalid request body"))
+		return
+	}
+	defer fileReader.Close()
+	image, err := io.ReadAll(fileReader)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("invalid request body"))
+		return
+	}
+	// Remove the metadata of photos, such as where they were taken
+	image, err = images.Process(image)
+	if errors.Is(err, images.ErrInvalid) {
+		apierror.Abort(c, apierror.BadRequest("invalid image"))
 		return
 	}
-	defer FileBytes.Close()
-
-	_, err = qualificationsCollection.UpdateOne(context.Background(), bson.M{"user_id": userID, "qualification_id": qualificationID}, bson.M{"$set": bson.M{"cert_image": FileBytes}}, options.Update().SetUpsert(true))
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "could not update qualification"})
+		apierror.Abort(c, apierror.Wrap(err, "could not process image"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "cert image uploaded"})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	upload := quota.NewUpload(userID, "qualification", qualificationID, "cert_image", int64(len(image)))
+	if err := quota.CheckUpload(ctx, c.MustGet("user").(auth.User), upload); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "could not check storage quota"))
+		return
+	}
+	if err := repo.SetCertImage(ctx, userID, qualificationID, image); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "could not update qualification"))
+		return
+	}
+	if err := quota.RecordUpload(ctx, upload); err != nil {
+		logging.Logger(c).Error("Could not record upload", "error", err)
+	}
+	if err := scan.Enqueue(ctx, scan.Upload{Kind: ScanKind, UserID: userID, ItemID: qualificationID}); err != nil {
+		logging.Logger(c).Error("Could not queue image scan", "error", err)
+	}
+
+	apiversion.NoContent(c, gin.H{"message": "cert image uploaded"})
 }
 
 // PostQualification creates a new qualification for a user.
@@ -200,43 +274,54 @@ func PutQualificationImage(c *gin.Context) {
 //	@ID				post-qualification
 //	@Param			userid	path		string			true	"The ID of the user for whom the qualification is to be created"
 //	@Param			request	body		Qualification	true	"Qualification object to be created"
-//	@Success		200		{string}	string			"Qualification Created"
-//	@Failure		400		{object}	ErrorResponse	"Invalid request body"
-//	@Failure		401		{object}	ErrorResponse	"Not authenticated"
-//	@Failure		500		{object}	ErrorResponse	"Could not update qualification"
+//	@Success		200		{object}	Qualification	"The created qualification with its ID, and a message"
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the qualifications"
+//	@Failure		413		{object}	apierror.Response	"Qualification limit reached"
+//	@Failure		500		{object}	apierror.Response	"Could not update qualification"
 //	@Router			/qualifications/{userid} [post]
 func PostQualification(c *gin.Context) {
 	userID := c.Param("userid")
 
 	var req Qualification
-	if err := c.BindJSON(&req); err != nil {
-		log.Println(err)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logging.Logger(c).Debug("Invalid qualification request body", "error", err)
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 	req.UserID = userID
 	req.QualificationID = primitive.NewObjectID().Hex()
 
-	_, err := qualificationsCollection.InsertOne(context.Background(), req)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not update qualification"})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Create(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update qualification"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Qualification Created"})
+	apiversion.Created(c, req.QualificationID, req, apiversion.Announce("Qualification Created", req))
+}
+
+// countQualifications counts the user's qualifications against their document quota
+func countQualifications(ctx context.Context, userID string) (int, error) {
+	items, err := repo.List(ctx, userID)
+	return len(items), err
 }
 
 // InitializeRoutes initializes the qualifications routes
-func InitializeRoutes(router *gin.RouterGroup, db *mongo.Client, db_name string) {
-	qualificationsCollection = db.Database(db_name).Collection("qualifications")
+func InitializeRoutes(router *gin.RouterGroup, r Repository, users auth.Repository) {
+	repo = r
 
-	router.GET("/:userid", GetQualifications)
-	router.GET("/:userid/:qualificationid", GetQualificationEntry)
+	authOptional := auth.AuthMiddleware(users, false)
+	router.GET("/:userid", authOptional, GetQualifications)
+	router.GET("/:userid/:qualificationid", authOptional, GetQualificationEntry)
 
 	protected := router.Group("/")
-	protected.Use(auth.AuthMiddleware(db, db_name, true))
-	protected.POST("/:userid", PostQualification)
-	protected.PUT("/:userid/:qualificationid", PutQualificationEntry)
-	protected.DELETE("/:userid/:qualificationid", DeleteQualificationEntry)
-	protected.PUT("/:userid/:qualificationid/cert_image", PutQualificationImage)
+	protected.Use(auth.AuthMiddleware(users, true))
+	protected.POST("/:userid", dryrun.Supported(), auth.RequireOwner(), quota.LimitDocuments("qualifications", countQualifications), PostQualification)
+	protected.PUT("/:userid/:qualificationid", dryrun.Supported(), auth.RequireOwner(), PutQualificationEntry)
+	protected.DELETE("/:userid/:qualificationid", dryrun.Supported(), auth.RequireOwner(), DeleteQualificationEntry)
+	protected.POST("/:userid/bulk-delete", dryrun.Supported(), auth.RequireOwner(), BulkDeleteQualifications)
+	protected.PUT("/:userid/:qualificationid/cert_image", auth.RequireOwner(), PutQualificationImage)
 }
@@ -0,0 +1,25 @@
+package qualifications
+
+import "context"
+
+// Repository stores qualifications
+type Repository interface {
+	// List returns every qualification belonging to the user
+	List(ctx context.Context, userID string) ([]Qualification, error)
+	// ListByUsers returns every qualification belonging to any of the users
+	ListByUsers(ctx context.Context, userIDs []string) ([]Qualification, error)
+	// Get returns a single qualification, or store.ErrNotFound
+	Get(ctx context.Context, userID, qualificationID string) (Qualification, error)
+	// Create stores a new qualification
+	Create(ctx context.Context, item Qualification) error
+	// Save replaces a qualification, creating it if it does not exist
+	Save(ctx context.Context, item Qualification) error
+	// Delete removes a qualification, or returns store.ErrNotFound when the user has no such qualification
+	Delete(ctx context.Context, userID, qualificationID string) error
+	// SetCertImage stores the certificate image of a qualification, creating the qualification if it does not exist
+	SetCertImage(ctx context.Context, userID, qualificationID string, image []byte) error
+	// GetCertImage returns the certificate image of a qualification, or store.ErrNotFound
+	GetCertImage(ctx context.Context, userID, qualificationID string) ([]byte, error)
+	// QuarantineCertImage removes the certificate image of a qualification and records the malware found in it
+	QuarantineCertImage(ctx context.Context, userID, qualificationID, threat string) error
+}
@@ -0,0 +1,78 @@
+package qualifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"profile-api/audit"
+	"profile-api/store"
+)
+
+// AuditedRepository records every change to qualifications in the audit log
+type AuditedRepository struct {
+	Repository
+}
+
+// NewAuditedRepository wraps the repository to record its changes in the audit log
+func NewAuditedRepository(r Repository) *AuditedRepository {
+	return &AuditedRepository{Repository: r}
+}
+
+func (r *AuditedRepository) Create(ctx context.Context, item Qualification) error {
+	if err := r.Repository.Create(ctx, item); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionCreate, "qualification", item.UserID, item.QualificationID, nil, item)
+	return nil
+}
+
+func (r *AuditedRepository) Save(ctx context.Context, item Qualification) error {
+	before, err := r.Repository.Get(ctx, item.UserID, item.QualificationID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.Save(ctx, item); err != nil {
+		return err
+	}
+	audit.RecordSave(ctx, "qualification", item.UserID, item.QualificationID, before, existed, item)
+	return nil
+}
+
+func (r *AuditedRepository) Delete(ctx context.Context, userID, qualificationID string) error {
+	before, err := r.Repository.Get(ctx, userID, qualificationID)
+	if errors.Is(err, store.ErrNotFound) {
+		return r.Repository.Delete(ctx, userID, qualificationID)
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.Delete(ctx, userID, qualificationID); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionDelete, "qualification", userID, qualificationID, before, nil)
+	return nil
+}
+
+func (r *AuditedRepository) SetCertImage(ctx context.Context, userID, qualificationID string, image []byte) error {
+	_, err := r.Repository.Get(ctx, userID, qualificationID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	existed := err == nil
+	if err := r.Repository.SetCertImage(ctx, userID, qualificationID, image); err != nil {
+		return err
+	}
+	// Only the size of the image is recorded
+	audit.RecordSave(ctx, "qualification", userID, qualificationID, nil, existed, map[string]string{"cert_image": fmt.Sprintf("%d bytes", len(image))})
+	return nil
+}
+
+func (r *AuditedRepository) QuarantineCertImage(ctx context.Context, userID, qualificationID, threat string) error {
+	if err := r.Repository.QuarantineCertImage(ctx, userID, qualificationID, threat); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionUpdate, "qualification", userID, qualificationID, nil, map[string]string{"cert_image_quarantined": threat})
+	return nil
+}
@@ -0,0 +1,54 @@
+package qualifications
+
+import (
+	"context"
+
+	"profile-api/dryrun"
+)
+
+// DryRunRepository leaves qualifications unchanged in dry runs, reading them as usual. A deletion still
+// reports a missing document.
+type DryRunRepository struct {
+	Repository
+}
+
+// NewDryRunRepository wraps the repository to skip its writes in dry runs
+func NewDryRunRepository(r Repository) *DryRunRepository {
+	return &DryRunRepository{Repository: r}
+}
+
+func (r *DryRunRepository) Create(ctx context.Context, item Qualification) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.Create(ctx, item)
+}
+
+func (r *DryRunRepository) Save(ctx context.Context, item Qualification) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.Save(ctx, item)
+}
+
+func (r *DryRunRepository) Delete(ctx context.Context, userID, qualificationID string) error {
+	if dryrun.Active(ctx) {
+		_, err := r.Repository.Get(ctx, userID, qualificationID)
+		return err
+	}
+	return r.Repository.Delete(ctx, userID, qualificationID)
+}
+
+func (r *DryRunRepository) SetCertImage(ctx context.Context, userID, qualificationID string, image []byte) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.SetCertImage(ctx, userID, qualificationID, image)
+}
+
+func (r *DryRunRepository) QuarantineCertImage(ctx context.Context, userID, qualificationID, threat string) error {
+	if dryrun.Active(ctx) {
+		return nil
+	}
+	return r.Repository.QuarantineCertImage(ctx, userID, qualificationID, threat)
+}
@@ -0,0 +1,134 @@
+package qualifications
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps qualifications in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu     sync.RWMutex
+	items  []Qualification
+	images map[string][]byte
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{images: map[string][]byte{}}
+}
+
+// index returns the position of a qualification in items, or -1. The caller must hold the lock.
+func (r *MemoryRepository) index(userID, qualificationID string) int {
+	for i, item := range r.items {
+		if item.UserID == userID && item.QualificationID == qualificationID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string) ([]Qualification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Qualification
+	for _, item := range r.items {
+		if item.UserID == userID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Qualification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Qualification
+	for _, item := range r.items {
+		if slices.Contains(userIDs, item.UserID) {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, userID, qualificationID string) (Qualification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.index(userID, qualificationID)
+	if i < 0 {
+		return Qualification{}, store.ErrNotFound
+	}
+	return r.items[i], nil
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, item Qualification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.index(item.UserID, item.QualificationID) >= 0 {
+		return store.ErrConflict
+	}
+	item.CertImageQuarantined = ""
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, item Qualification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(item.UserID, item.QualificationID); i >= 0 {
+		item.CertImageQuarantined = r.items[i].CertImageQuarantined
+		r.items[i] = item
+		return nil
+	}
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, userID, qualificationID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, qualificationID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.items = append(r.items[:i], r.items[i+1:]...)
+	delete(r.images, userID+"/"+qualificationID)
+	return nil
+}
+
+func (r *MemoryRepository) SetCertImage(ctx context.Context, userID, qualificationID string, image []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(userID, qualificationID); i >= 0 {
+		r.items[i].CertImageQuarantined = ""
+	} else {
+		r.items = append(r.items, Qualification{UserID: userID, QualificationID: qualificationID})
+	}
+	r.images[userID+"/"+qualificationID] = image
+	return nil
+}
+
+func (r *MemoryRepository) GetCertImage(ctx context.Context, userID, qualificationID string) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	image, ok := r.images[userID+"/"+qualificationID]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return image, nil
+}
+
+func (r *MemoryRepository) QuarantineCertImage(ctx context.Context, userID, qualificationID, threat string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, qualificationID)
+	if i < 0 {
+		return nil
+	}
+	r.items[i].CertImageQuarantined = threat
+	delete(r.images, userID+"/"+qualificationID)
+	return nil
+}
@@ -0,0 +1,108 @@
+package qualifications
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores qualifications in the qualifications collection
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{collection: db.Collection("qualifications")}
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string) ([]Qualification, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []Qualification
+	for cursor.Next(ctx) {
+		var item Qualification
+		if err := cursor.Decode(&item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, cursor.Err()
+}
+
+func (r *MongoRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Qualification, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []Qualification
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *MongoRepository) Get(ctx context.Context, userID, qualificationID string) (Qualification, error) {
+	var item Qualification
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "qualification_id": qualificationID}).Decode(&item)
+	return item, store.MongoErr(err)
+}
+
+func (r *MongoRepository) Create(ctx context.Context, item Qualification) error {
+	// The quarantine is only set by QuarantineCertImage
+	item.CertImageQuarantined = ""
+	_, err := r.collection.InsertOne(ctx, item)
+	return err
+}
+
+func (r *MongoRepository) Save(ctx context.Context, item Qualification) error {
+	// The omitted quarantine is left as it is
+	item.CertImageQuarantined = ""
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": item.UserID, "qualification_id": item.QualificationID}, bson.M{"$set": item}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, userID, qualificationID string) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID, "qualification_id": qualificationID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) SetCertImage(ctx context.Context, userID, qualificationID string, image []byte) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": userID, "qualification_id": qualificationID}, bson.M{"$set": bson.M{"cert_image": image}, "$unset": bson.M{"cert_image_quarantined": ""}}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) GetCertImage(ctx context.Context, userID, qualificationID string) ([]byte, error) {
+	var doc struct {
+		CertImage []byte `bson:"cert_image"`
+	}
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "qualification_id": qualificationID}, options.FindOne().SetProjection(bson.M{"cert_image": 1})).Decode(&doc)
+	if err != nil {
+		return nil, store.MongoErr(err)
+	}
+	if doc.CertImage == nil {
+		return nil, store.ErrNotFound
+	}
+	return doc.CertImage, nil
+}
+
+func (r *MongoRepository) QuarantineCertImage(ctx context.Context, userID, qualificationID, threat string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": userID, "qualification_id": qualificationID}, bson.M{"$set": bson.M{"cert_image_quarantined": threat}, "$unset": bson.M{"cert_image": ""}})
+	return err
+}
@@ -0,0 +1,103 @@
+package qualifications
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const qualificationsColumns = "user_id, qualification_id, title, institution, start_date, end_date, description, visibility"
+
+// PostgresRepository stores qualifications in the qualifications table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string) ([]Qualification, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+qualificationsColumns+", cert_image_quarantined FROM qualifications WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanQualification)
+}
+
+func (r *PostgresRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Qualification, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+qualificationsColumns+", cert_image_quarantined FROM qualifications WHERE user_id = ANY($1) ORDER BY created_at", userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanQualification)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, userID, qualificationID string) (Qualification, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+qualificationsColumns+", cert_image_quarantined FROM qualifications WHERE user_id = $1 AND qualification_id = $2", userID, qualificationID)
+	if err != nil {
+		return Qualification{}, err
+	}
+	item, err := pgx.CollectExactlyOneRow(rows, scanQualification)
+	return item, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, item Qualification) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO qualifications ("+qualificationsColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		item.UserID, item.QualificationID, item.Title, item.Institution, item.Start, item.End, item.Description, item.Visibility)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, item Qualification) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO qualifications ("+qualificationsColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8) "+
+		"ON CONFLICT (user_id, qualification_id) DO UPDATE SET title = EXCLUDED.title, institution = EXCLUDED.institution, start_date = EXCLUDED.start_date, end_date = EXCLUDED.end_date, description = EXCLUDED.description, visibility = EXCLUDED.visibility",
+		item.UserID, item.QualificationID, item.Title, item.Institution, item.Start, item.End, item.Description, item.Visibility)
+	return err
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, userID, qualificationID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM qualifications WHERE user_id = $1 AND qualification_id = $2", userID, qualificationID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) SetCertImage(ctx context.Context, userID, qualificationID string, image []byte) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO qualifications (user_id, qualification_id, cert_image) VALUES ($1, $2, $3) "+
+		"ON CONFLICT (user_id, qualification_id) DO UPDATE SET cert_image = EXCLUDED.cert_image, cert_image_quarantined = ''",
+		userID, qualificationID, image)
+	return err
+}
+
+func (r *PostgresRepository) GetCertImage(ctx context.Context, userID, qualificationID string) ([]byte, error) {
+	var image []byte
+	err := r.pool.QueryRow(ctx, "SELECT cert_image FROM qualifications WHERE user_id = $1 AND qualification_id = $2", userID, qualificationID).Scan(&image)
+	if err != nil {
+		return nil, store.PostgresErr(err)
+	}
+	if image == nil {
+		return nil, store.ErrNotFound
+	}
+	return image, nil
+}
+
+func (r *PostgresRepository) QuarantineCertImage(ctx context.Context, userID, qualificationID, threat string) error {
+	_, err := r.pool.Exec(ctx, "UPDATE qualifications SET cert_image = NULL, cert_image_quarantined = $3 WHERE user_id = $1 AND qualification_id = $2",
+		userID, qualificationID, threat)
+	return err
+}
+
+// scanQualification reads a row selected with qualificationsColumns and cert_image_quarantined
+func scanQualification(row pgx.CollectableRow) (Qualification, error) {
+	var item Qualification
+	err := row.Scan(&item.UserID, &item.QualificationID, &item.Title, &item.Institution, &item.Start, &item.End, &item.Description, &item.Visibility, &item.CertImageQuarantined)
+	return item, err
+}
@@ -0,0 +1,30 @@
+package qualifications
+
+import (
+	"context"
+
+	"profile-api/sanitize"
+)
+
+// SanitizedRepository cleans the user-supplied text of qualifications before storing them
+type SanitizedRepository struct {
+	Repository
+}
+
+// NewSanitizedRepository wraps the repository to clean qualifications with the configured sanitize policies
+func NewSanitizedRepository(r Repository) *SanitizedRepository {
+	return &SanitizedRepository{Repository: r}
+}
+
+func (r *SanitizedRepository) Create(ctx context.Context, item Qualification) error {
+	return r.Repository.Create(ctx, sanitized(item))
+}
+
+func (r *SanitizedRepository) Save(ctx context.Context, item Qualification) error {
+	return r.Repository.Save(ctx, sanitized(item))
+}
+
+func sanitized(item Qualification) Qualification {
+	item.Description = sanitize.Field(sanitize.QualificationDescription, item.Description)
+	return item
+}
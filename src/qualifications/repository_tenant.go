@@ -0,0 +1,53 @@
+package qualifications
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID string) ([]Qualification, error) {
+	return r.repos.For(ctx).List(ctx, userID)
+}
+
+func (r *TenantRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Qualification, error) {
+	return r.repos.For(ctx).ListByUsers(ctx, userIDs)
+}
+
+func (r *TenantRepository) Get(ctx context.Context, userID, qualificationID string) (Qualification, error) {
+	return r.repos.For(ctx).Get(ctx, userID, qualificationID)
+}
+
+func (r *TenantRepository) Create(ctx context.Context, item Qualification) error {
+	return r.repos.For(ctx).Create(ctx, item)
+}
+
+func (r *TenantRepository) Save(ctx context.Context, item Qualification) error {
+	return r.repos.For(ctx).Save(ctx, item)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, userID, qualificationID string) error {
+	return r.repos.For(ctx).Delete(ctx, userID, qualificationID)
+}
+
+func (r *TenantRepository) SetCertImage(ctx context.Context, userID, qualificationID string, image []byte) error {
+	return r.repos.For(ctx).SetCertImage(ctx, userID, qualificationID, image)
+}
+
+func (r *TenantRepository) GetCertImage(ctx context.Context, userID, qualificationID string) ([]byte, error) {
+	return r.repos.For(ctx).GetCertImage(ctx, userID, qualificationID)
+}
+
+func (r *TenantRepository) QuarantineCertImage(ctx context.Context, userID, qualificationID, threat string) error {
+	return r.repos.For(ctx).QuarantineCertImage(ctx, userID, qualificationID, threat)
+}
@@ -0,0 +1,30 @@
+package qualifications
+
+import (
+	"context"
+
+	"profile-api/scan"
+)
+
+// ScanKind is the kind of upload certificate images of qualifications are scanned as
+const ScanKind = "qualification-image"
+
+// scanTarget loads certificate images for scanning, and removes infected ones
+type scanTarget struct {
+	repo Repository
+}
+
+// NewScanTarget creates the target scanning the certificate images of qualifications
+func NewScanTarget(r Repository) scan.Target {
+	return &scanTarget{repo: r}
+}
+
+func (t *scanTarget) Load(ctx context.Context, upload scan.Upload) ([]byte, error) {
+	return t.repo.GetCertImage(ctx, upload.UserID, upload.ItemID)
+}
+
+// Quarantine removes the image, as certificate images are kept in the database rather than a store to move
+// them aside in
+func (t *scanTarget) Quarantine(ctx context.Context, upload scan.Upload, threat string) error {
+	return t.repo.QuarantineCertImage(ctx, upload.UserID, upload.ItemID, threat)
+}
@@ -0,0 +1,34 @@
+package quota
+
+import "time"
+
+// Upload records the size of a file a user uploaded, counted against their storage quota
+type Upload struct {
+	// ID is the resource, its ID and the file's name, see uploadID
+	ID     string `bson:"_id"`
+	UserID string `bson:"user_id"`
+	// Resource and ResourceID are what the file belongs to, whose deletion frees its storage
+	Resource   string    `bson:"resource"`
+	ResourceID string    `bson:"resource_id"`
+	Bytes      int64     `bson:"bytes"`
+	UpdatedAt  time.Time `bson:"updated_at"`
+}
+
+func uploadID(resource, resourceID, name string) string {
+	return resource + "/" + resourceID + "/" + name
+}
+
+// Allowance is how much of a quota the user has used. A limit of 0 is unlimited.
+type Allowance struct {
+	Used  int64 `json:"used"`
+	Limit int64 `json:"limit"`
+}
+
+// Usage is what the user stores against their quotas
+type Usage struct {
+	// Plan is the user's billing plan, when billing is enabled
+	Plan    string    `json:"plan,omitempty"`
+	Storage Allowance `json:"storage"`
+	// Documents holds the number of documents of each collection
+	Documents map[string]Allowance `json:"documents"`
+}
@@ -0,0 +1,236 @@
+// Package quota bounds what each user can store: the total size of their uploads and the number of
+// documents they keep in each collection. The site's limits are narrowed by those of the user's billing
+// plan. A write that would take the user past their plan's limit is refused with 402, so they can move to
+// a bigger plan, and one past the site's limit with 413.
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/billing"
+	"profile-api/config"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Counter returns how many documents of a collection the user has
+type Counter func(ctx context.Context, userID string) (int, error)
+
+// uploadResources are the audit log resources whose deletion removes the files uploaded with them
+var uploadResources = []string{"certificate", "qualification", "award", "journal"}
+
+var repo Repository
+var settings config.QuotasConfig
+
+// counters holds the counter of each collection with a document quota, registered by LimitDocuments
+var counters = map[string]Counter{}
+
+// Configure sets where the sizes of uploads are recorded and the site's limits, and starts freeing the
+// storage of the files of deleted resources
+func Configure(r Repository, cfg config.QuotasConfig) {
+	repo = r
+	settings = cfg
+	audit.Subscribe(freeDeleted)
+}
+
+// NewUpload describes a file the user uploads, named uniquely among the files of the resource it belongs to
+func NewUpload(userID, resource, resourceID, name string, bytes int64) Upload {
+	return Upload{
+		ID:         uploadID(resource, resourceID, name),
+		UserID:     userID,
+		Resource:   resource,
+		ResourceID: resourceID,
+		Bytes:      bytes,
+	}
+}
+
+// storageLimit returns the most bytes the user's uploads may take, 0 for no limit, and whether it is the
+// limit of their plan
+func storageLimit(user auth.User) (int64, bool) {
+	limit := settings.StorageBytes
+	if planLimit := billing.PlanOf(user).StorageBytes; planLimit > 0 && (limit == 0 || planLimit < limit) {
+		return planLimit, true
+	}
+	return limit, false
+}
+
+// documentLimit returns the most documents of the collection the user may keep, 0 for no limit, and
+// whether it is the limit of their plan
+func documentLimit(user auth.User, collection string) (int, bool) {
+	limit := settings.Documents[collection]
+	if collection != "journal" {
+		return limit, false
+	}
+	if planLimit := billing.PlanOf(user).MaxJournalEntries; planLimit > 0 && (limit == 0 || planLimit < limit) {
+		return planLimit, true
+	}
+	return limit, false
+}
+
+// exceeded returns the error refusing a write past a limit, 402 for the limit of the user's plan
+func exceeded(user auth.User, fromPlan bool, message string, details map[string]any) *apierror.Error {
+	if fromPlan {
+		details["plan"] = billing.PlanOf(user).Name
+		return apierror.PaymentRequired("Your plan's " + message).WithDetails(details)
+	}
+	return apierror.QuotaExceeded("The " + message).WithDetails(details)
+}
+
+// CheckUpload returns an API error when storing the upload would take its user past their storage limit.
+// The upload's size replaces that of the file it overwrites.
+func CheckUpload(ctx context.Context, user auth.User, upload Upload) error {
+	limit, fromPlan := storageLimit(user)
+	if limit == 0 {
+		return nil
+	}
+	used, err := repo.StorageUsed(ctx, upload.UserID)
+	if err != nil {
+		return err
+	}
+	previous, err := repo.Get(ctx, upload.ID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	used -= previous.Bytes
+	if used+upload.Bytes <= limit {
+		return nil
+	}
+	return exceeded(user, fromPlan, fmt.Sprintf("storage limit of %d bytes leaves no room for the upload", limit), map[string]any{
+		"used":  used,
+		"limit": limit,
+		"size":  upload.Bytes,
+	})
+}
+
+// RecordUpload counts a stored upload against its user's storage
+func RecordUpload(ctx context.Context, upload Upload) error {
+	upload.UpdatedAt = time.Now()
+	return repo.Save(ctx, upload)
+}
+
+// FreeUpload stops counting a file removed on its own, rather than with the resource it belongs to, against
+// its user's storage
+func FreeUpload(ctx context.Context, resource, resourceID, name string) error {
+	return repo.Delete(ctx, uploadID(resource, resourceID, name))
+}
+
+// DocumentsLeft returns how many more documents of the collection the user may create, or -1 when there
+// is no limit
+func DocumentsLeft(ctx context.Context, user auth.User, collection string) (int, error) {
+	return documentsLeft(ctx, user, user.ID, collection)
+}
+
+func documentsLeft(ctx context.Context, user auth.User, ownerID, collection string) (int, error) {
+	limit, _ := documentLimit(user, collection)
+	count, ok := counters[collection]
+	if limit == 0 || !ok {
+		return -1, nil
+	}
+	n, err := count(ctx, ownerID)
+	if err != nil {
+		return 0, err
+	}
+	return max(limit-n, 0), nil
+}
+
+// DocumentLimitError is returned once the user has as many documents of the collection as they may keep
+func DocumentLimitError(user auth.User, collection string) *apierror.Error {
+	limit, fromPlan := documentLimit(user, collection)
+	return exceeded(user, fromPlan, fmt.Sprintf("limit of %d %s documents is reached", limit, collection), map[string]any{
+		"collection": collection,
+		"limit":      limit,
+	})
+}
+
+// LimitDocuments refuses to create documents of the collection once the user whose ID is in the path, or
+// the authenticated user, has as many as they may keep, counting them with count. The count is also shown
+// in the user's usage. Authentication must run first.
+func LimitDocuments(collection string, count Counter) gin.HandlerFunc {
+	counters[collection] = count
+	return func(c *gin.Context) {
+		user, _ := c.Get("user")
+		u, _ := user.(auth.User)
+		ownerID := c.Param("userid")
+		if ownerID == "" {
+			ownerID = u.ID
+		}
+
+		ctx, cancel := utils.DBContext(c)
+		left, err := documentsLeft(ctx, u, ownerID, collection)
+		cancel()
+		if err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not check document quota"))
+			return
+		}
+		if left == 0 {
+			apierror.Abort(c, DocumentLimitError(u, collection))
+			return
+		}
+		c.Next()
+	}
+}
+
+// freeDeleted frees the storage of the files uploaded with a deleted resource
+func freeDeleted(ctx context.Context, entry audit.Entry) {
+	if entry.Action != audit.ActionDelete || !slices.Contains(uploadResources, entry.Resource) {
+		return
+	}
+	if err := repo.DeleteResource(ctx, entry.Resource, entry.ResourceID); err != nil {
+		slog.ErrorContext(ctx, "Could not free the storage of deleted uploads", "resource", entry.Resource, "resource_id", entry.ResourceID, "error", err)
+	}
+}
+
+// GetUsage returns what the user stores against their quotas
+//
+//	@Summary		Get your usage
+//	@Description	Returns the storage the user's uploads take and how many documents they keep in each collection, with the limits of the site and their plan. Limits of 0 are unlimited. Writes past a limit of the user's plan are refused with 402, and past one of the site with 413.
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	Usage
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve usage"
+//	@Router			/auth/usage [get]
+func GetUsage(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	used, err := repo.StorageUsed(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve usage"))
+		return
+	}
+	storageLimit, _ := storageLimit(user)
+	usage := Usage{
+		Plan:      billing.PlanOf(user).Name,
+		Storage:   Allowance{Used: used, Limit: storageLimit},
+		Documents: map[string]Allowance{},
+	}
+	for collection, count := range counters {
+		n, err := count(ctx, user.ID)
+		if err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not retrieve usage"))
+			return
+		}
+		limit, _ := documentLimit(user, collection)
+		usage.Documents[collection] = Allowance{Used: int64(n), Limit: int64(limit)}
+	}
+	c.JSON(http.StatusOK, usage)
+}
+
+// InitializeRoutes registers the usage endpoint with the account routes
+func InitializeRoutes(router *gin.RouterGroup, users auth.Repository) {
+	router.GET("/usage", auth.AuthMiddleware(users, true), GetUsage)
+}
@@ -0,0 +1,17 @@
+package quota
+
+import "context"
+
+// Repository stores the sizes of the users' uploads
+type Repository interface {
+	// Save creates or replaces the record of an upload
+	Save(ctx context.Context, upload Upload) error
+	// Get returns the record of the upload with the given ID, or store.ErrNotFound
+	Get(ctx context.Context, id string) (Upload, error)
+	// Delete removes the record of the upload with the given ID, if there is one
+	Delete(ctx context.Context, id string) error
+	// DeleteResource removes the records of the uploads belonging to the resource
+	DeleteResource(ctx context.Context, resource, resourceID string) error
+	// StorageUsed returns the total size of the user's uploads
+	StorageUsed(ctx context.Context, userID string) (int64, error)
+}
@@ -0,0 +1,66 @@
+package quota
+
+import (
+	"context"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps upload records in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu      sync.Mutex
+	uploads map[string]Upload
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{uploads: map[string]Upload{}}
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, upload Upload) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.uploads[upload.ID] = upload
+	return nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, id string) (Upload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	upload, ok := r.uploads[id]
+	if !ok {
+		return Upload{}, store.ErrNotFound
+	}
+	return upload, nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.uploads, id)
+	return nil
+}
+
+func (r *MemoryRepository) DeleteResource(ctx context.Context, resource, resourceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, upload := range r.uploads {
+		if upload.Resource == resource && upload.ResourceID == resourceID {
+			delete(r.uploads, id)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) StorageUsed(ctx context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var used int64
+	for _, upload := range r.uploads {
+		if upload.UserID == userID {
+			used += upload.Bytes
+		}
+	}
+	return used, nil
+}
@@ -0,0 +1,59 @@
+package quota
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores upload records in the uploads collection
+type MongoRepository struct {
+	uploads *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{uploads: db.Collection("uploads")}
+}
+
+func (r *MongoRepository) Save(ctx context.Context, upload Upload) error {
+	_, err := r.uploads.ReplaceOne(ctx, bson.M{"_id": upload.ID}, upload, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) Get(ctx context.Context, id string) (Upload, error) {
+	var upload Upload
+	err := r.uploads.FindOne(ctx, bson.M{"_id": id}).Decode(&upload)
+	return upload, store.MongoErr(err)
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.uploads.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *MongoRepository) DeleteResource(ctx context.Context, resource, resourceID string) error {
+	_, err := r.uploads.DeleteMany(ctx, bson.M{"resource": resource, "resource_id": resourceID})
+	return err
+}
+
+func (r *MongoRepository) StorageUsed(ctx context.Context, userID string) (int64, error) {
+	cursor, err := r.uploads.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "bytes": bson.M{"$sum": "$bytes"}}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var totals []struct {
+		Bytes int64 `bson:"bytes"`
+	}
+	if err := cursor.All(ctx, &totals); err != nil || len(totals) == 0 {
+		return 0, err
+	}
+	return totals[0].Bytes, nil
+}
@@ -0,0 +1,50 @@
+package quota
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository stores upload records in the uploads table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, upload Upload) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO uploads (id, user_id, resource, resource_id, bytes, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET bytes = excluded.bytes, updated_at = excluded.updated_at`,
+		upload.ID, upload.UserID, upload.Resource, upload.ResourceID, upload.Bytes, upload.UpdatedAt)
+	return err
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, id string) (Upload, error) {
+	var u Upload
+	err := r.pool.QueryRow(ctx, "SELECT id, user_id, resource, resource_id, bytes, updated_at FROM uploads WHERE id = $1", id).
+		Scan(&u.ID, &u.UserID, &u.Resource, &u.ResourceID, &u.Bytes, &u.UpdatedAt)
+	return u, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM uploads WHERE id = $1", id)
+	return err
+}
+
+func (r *PostgresRepository) DeleteResource(ctx context.Context, resource, resourceID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM uploads WHERE resource = $1 AND resource_id = $2", resource, resourceID)
+	return err
+}
+
+func (r *PostgresRepository) StorageUsed(ctx context.Context, userID string) (int64, error) {
+	var used int64
+	err := r.pool.QueryRow(ctx, "SELECT COALESCE(SUM(bytes), 0) FROM uploads WHERE user_id = $1", userID).Scan(&used)
+	return used, err
+}
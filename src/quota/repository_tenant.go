@@ -0,0 +1,37 @@
+package quota
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Save(ctx context.Context, upload Upload) error {
+	return r.repos.For(ctx).Save(ctx, upload)
+}
+
+func (r *TenantRepository) Get(ctx context.Context, id string) (Upload, error) {
+	return r.repos.For(ctx).Get(ctx, id)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, id string) error {
+	return r.repos.For(ctx).Delete(ctx, id)
+}
+
+func (r *TenantRepository) DeleteResource(ctx context.Context, resource, resourceID string) error {
+	return r.repos.For(ctx).DeleteResource(ctx, resource, resourceID)
+}
+
+func (r *TenantRepository) StorageUsed(ctx context.Context, userID string) (int64, error) {
+	return r.repos.For(ctx).StorageUsed(ctx, userID)
+}
@@ -0,0 +1,112 @@
+// Package readonly serves the API as a read-only replica, such as a cheap instance at the edge in front of
+// a cache, while writes go to the main instance. Every request is served as anonymous, so only public data is
+// shown and responses are the same for every client, and successful reads may be kept by shared caches.
+//
+// Some GET routes write, such as links followed from emails that confirm a subscription or record a visit.
+// They opt in with Writes and are refused like the other writes.
+package readonly
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"slices"
+
+	"profile-api/apierror"
+	"profile-api/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// allowed are the methods a replica serves
+const allowed = "GET, HEAD, OPTIONS"
+
+// credentialHeaders are removed from every request, so none is authenticated
+var credentialHeaders = []string{"Cookie", "Authorization", "X-API-Key"}
+
+// write marks the GET routes that write. It does nothing when called.
+func write(c *gin.Context) {
+	c.Next()
+}
+
+// writeName is how write appears among a route's handler names
+var writeName = runtime.FuncForPC(reflect.ValueOf(write).Pointer()).Name()
+
+// Writes marks a GET route as changing state, such as recording a visit or setting a cookie, so replicas
+// refuse it
+func Writes() gin.HandlerFunc {
+	return write
+}
+
+// Middleware refuses writes and serves reads as anonymous, marking successful reads as cacheable for the
+// configured time. Reads answered for a token in the path are never marked, as the link is private to whoever
+// it was sent to.
+func Middleware(cfg config.ReadOnlyConfig) gin.HandlerFunc {
+	cacheControl := fmt.Sprintf("public, max-age=%d", int(cfg.CacheMaxAge.Std().Seconds()))
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		if method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions {
+			c.Header("Allow", allowed)
+			refuse(c, cfg)
+			return
+		}
+		if slices.Contains(c.HandlerNames(), writeName) {
+			refuse(c, cfg)
+			return
+		}
+
+		for _, h := range credentialHeaders {
+			c.Request.Header.Del(h)
+		}
+		if cfg.CacheMaxAge > 0 && method != http.MethodOptions && c.Param("token") == "" {
+			c.Writer = &cachingWriter{ResponseWriter: c.Writer, cacheControl: cacheControl}
+		}
+		c.Next()
+	}
+}
+
+// refuse responds that the request must be sent to the main instance
+func refuse(c *gin.Context, cfg config.ReadOnlyConfig) {
+	message := "This server is read-only"
+	if cfg.WriteURL != "" {
+		c.Header("Link", fmt.Sprintf(`<%s>; rel="alternate"`, cfg.WriteURL))
+		message += ", send writes to " + cfg.WriteURL
+	}
+	apierror.Abort(c, apierror.MethodNotAllowed(message))
+}
+
+// cachingWriter lets shared caches keep successful responses whose handler set no caching policy itself and
+// that set no cookie
+type cachingWriter struct {
+	gin.ResponseWriter
+	cacheControl string
+}
+
+// setCacheControl adds the caching policy before the headers are written
+func (w *cachingWriter) setCacheControl() {
+	if w.Written() {
+		return
+	}
+	if status := w.Status(); status != http.StatusOK && status != http.StatusNotModified {
+		return
+	}
+	if w.Header().Get("Cache-Control") == "" && w.Header().Get("Set-Cookie") == "" {
+		w.Header().Set("Cache-Control", w.cacheControl)
+	}
+}
+
+func (w *cachingWriter) WriteHeaderNow() {
+	w.setCacheControl()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cachingWriter) Write(data []byte) (int, error) {
+	w.setCacheControl()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cachingWriter) WriteString(s string) (int, error) {
+	w.setCacheControl()
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,82 @@
+package recommendations
+
+import "time"
+
+// Statuses of a recommendation
+const (
+	// StatusPending recommendations await the recommended user's approval and are only shown to them
+	StatusPending = "pending"
+	// StatusApproved recommendations are shown on the recommended user's public profile
+	StatusApproved = "approved"
+	// StatusHidden recommendations were hidden by the recommended user and are only shown to them
+	StatusHidden = "hidden"
+)
+
+// Recommendation is a reference written for a user by another user or by an external referee they invited
+type Recommendation struct {
+	ID string `bson:"_id" json:"id"`
+	// UserID is the user the recommendation is written for
+	UserID string `bson:"user_id" json:"userID"`
+	// AuthorID is the user who wrote the recommendation, empty for external referees
+	AuthorID   string `bson:"author_id,omitempty" json:"authorID,omitempty"`
+	AuthorName string `bson:"author_name" json:"authorName"`
+	// AuthorEmail is the address an external referee was invited at, only shown to the recommended user
+	AuthorEmail  string    `bson:"author_email,omitempty" json:"authorEmail,omitempty"`
+	Relationship string    `bson:"relationship" json:"relationship"`
+	Content      string    `bson:"content" json:"content"`
+	Status       string    `bson:"status" json:"status"`
+	CreatedAt    time.Time `bson:"created_at" json:"createdAt"`
+	UpdatedAt    time.Time `bson:"updated_at" json:"updatedAt"`
+}
+
+// RecommendationRequest represents the request body for writing a recommendation
+type RecommendationRequest struct {
+	// Relationship describes how the author knows the user, such as "Managed them at Acme"
+	Relationship string `json:"relationship" binding:"required,notblank,max=200"`
+	Content      string `json:"content" binding:"required,notblank,max=5000"`
+}
+
+// StatusRequest represents the request body for approving or hiding a recommendation
+type StatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved hidden"`
+}
+
+// Invitation asks someone without an account, by email, to write a recommendation for a user
+type Invitation struct {
+	ID     string `bson:"_id" json:"id"`
+	UserID string `bson:"user_id" json:"userID"`
+	Name   string `bson:"name" json:"name"`
+	Email  string `bson:"email" json:"email"`
+	// Token is sent in the link emailed to the referee, and is never shown again
+	Token     string     `bson:"token" json:"-"`
+	CreatedAt time.Time  `bson:"created_at" json:"createdAt"`
+	ExpiresAt time.Time  `bson:"expires_at" json:"expiresAt"`
+	UsedAt    *time.Time `bson:"used_at,omitempty" json:"usedAt"`
+}
+
+// pending reports whether the invitation may still be used
+func (i Invitation) pending(now time.Time) bool {
+	return i.UsedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// InvitationRequest represents the request body for inviting an external referee
+type InvitationRequest struct {
+	Name  string `json:"name" binding:"required,notblank,max=200"`
+	Email string `json:"email" binding:"required,email,max=254"`
+}
+
+// RefereeInvitation is shown to an invited referee before they write their recommendation
+type RefereeInvitation struct {
+	Name string `json:"name"`
+	// UserName is the name of the user the recommendation is for
+	UserName  string    `json:"userName"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// invitationEmail is the data for the recommendation_invitation email template
+type invitationEmail struct {
+	Name      string
+	UserName  string
+	URL       string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,540 @@
+// Package recommendations lets others vouch for a user. Signed in users write recommendations for one
+// another, and users invite referees without an account by email, who write theirs through the emailed
+// link. Every recommendation waits for the recommended user to approve it before it is shown on their
+// public profile, and they may hide it again at any time.
+package recommendations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/email"
+	"profile-api/notifications"
+	"profile-api/privacy"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var repo Repository
+var users auth.Repository
+var settings config.RecommendationsConfig
+
+var publicBaseURL = "http://localhost:8080"
+
+// Configure sets where recommendations are stored and how long invitations last, and starts removing the
+// recommendations of users who delete their account
+func Configure(r Repository, u auth.Repository, cfg config.RecommendationsConfig) {
+	repo = r
+	users = u
+	settings = cfg
+	audit.Subscribe(removeAuthored)
+}
+
+// SetBaseURL sets the public base URL of the links emailed to referees
+func SetBaseURL(url string) {
+	publicBaseURL = strings.TrimSuffix(url, "/")
+}
+
+// baseURL returns the public base URL used for links in emails, which tenants may override
+func baseURL(ctx context.Context) string {
+	if t, ok := tenant.Current(ctx); ok && t.PublicBaseURL != "" {
+		return strings.TrimSuffix(t.PublicBaseURL, "/")
+	}
+	return publicBaseURL
+}
+
+// removeAuthored removes the recommendations written by a deleted user, whose own are removed with the
+// rest of their data
+func removeAuthored(ctx context.Context, entry audit.Entry) {
+	if entry.Resource != "user" || entry.Action != audit.ActionDelete {
+		return
+	}
+	if err := repo.DeleteByAuthor(ctx, entry.ResourceID); err != nil {
+		slog.ErrorContext(ctx, "Could not remove the recommendations of a deleted user", "user_id", entry.ResourceID, "error", err)
+	}
+}
+
+// ListRecommendations lists the approved recommendations of a user
+//
+//	@Summary		List a user's recommendations
+//	@Description	Lists the recommendations the user approved, newest first, for their public profile
+//	@Tags			recommendations
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Success		200		{array}		Recommendation
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve recommendations"
+//	@Router			/recommendations/u/{userid} [get]
+func ListRecommendations(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	list, err := repo.List(ctx, c.Param("userid"), StatusApproved)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve recommendations"))
+		return
+	}
+	for i := range list {
+		list[i].AuthorEmail = ""
+	}
+	if list == nil {
+		list = []Recommendation{}
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// ListReceived lists the recommendations written for the current user
+//
+//	@Summary		List received recommendations
+//	@Description	Lists every recommendation written for the current user, newest first, including those awaiting their approval and those they hid
+//	@Tags			recommendations
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			status	query		string	false	"Only list recommendations with the status: pending, approved or hidden"
+//	@Success		200		{array}		Recommendation
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve recommendations"
+//	@Router			/recommendations [get]
+func ListReceived(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	list, err := repo.List(ctx, user.ID, c.Query("status"))
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve recommendations"))
+		return
+	}
+	if list == nil {
+		list = []Recommendation{}
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// WriteRecommendation writes a recommendation for a user
+//
+//	@Summary		Write a recommendation
+//	@Description	Writes a recommendation for another user, signed with the current user's name. It is shown on their profile once they approve it. Users can't recommend those who blocked them.
+//	@Tags			recommendations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			userid			path		string					true	"ID of the user to recommend"
+//	@Param			recommendation	body		RecommendationRequest	true	"The recommendation"
+//	@Success		201				{object}	Recommendation
+//	@Failure		400				{object}	apierror.Response	"Invalid request body, or recommending oneself"
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		403				{object}	apierror.Response	"Blocked by the user"
+//	@Failure		404				{object}	apierror.Response	"User not found"
+//	@Failure		500				{object}	apierror.Response	"Could not save recommendation"
+//	@Router			/recommendations/u/{userid} [post]
+func WriteRecommendation(c *gin.Context) {
+	author := c.MustGet("user").(auth.User)
+	userID := c.Param("userid")
+
+	var req RecommendationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	if userID == author.ID {
+		apierror.Abort(c, apierror.BadRequest("You can't recommend yourself"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	user, err := users.FindByID(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && user.Disabled) {
+		apierror.Abort(c, apierror.NotFound("User not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not save recommendation"))
+		return
+	}
+	if err := privacy.CheckContact(ctx, userID, author.ID, ""); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	now := time.Now()
+	rec := Recommendation{
+		ID:           utils.GenerateID(),
+		UserID:       userID,
+		AuthorID:     author.ID,
+		AuthorName:   author.Name,
+		Relationship: req.Relationship,
+		Content:      req.Content,
+		Status:       StatusPending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := repo.Create(ctx, rec); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not save recommendation"))
+		return
+	}
+	notify(ctx, rec)
+
+	c.JSON(http.StatusCreated, rec)
+}
+
+// SetStatus approves or hides a recommendation
+//
+//	@Summary		Approve or hide a recommendation
+//	@Description	Approves one of the current user's recommendations, showing it on their public profile, or hides it
+//	@Tags			recommendations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			recommendationid	path		string			true	"Recommendation ID"
+//	@Param			status				body		StatusRequest	true	"The new status"
+//	@Success		200					{object}	map[string]string
+//	@Failure		400					{object}	apierror.Response	"Invalid request body"
+//	@Failure		401					{object}	apierror.Response	"Not authenticated"
+//	@Failure		404					{object}	apierror.Response	"Recommendation not found"
+//	@Failure		500					{object}	apierror.Response	"Could not update recommendation"
+//	@Router			/recommendations/{recommendationid}/status [put]
+func SetStatus(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req StatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	rec, err := repo.Get(ctx, c.Param("recommendationid"))
+	if err == nil && rec.UserID != user.ID {
+		err = store.ErrNotFound
+	}
+	if err == nil {
+		err = repo.SetStatus(ctx, rec.ID, req.Status, time.Now())
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Recommendation not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update recommendation"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Recommendation " + req.Status})
+}
+
+// DeleteRecommendation deletes a recommendation
+//
+//	@Summary		Delete a recommendation
+//	@Description	Deletes a recommendation. The recommended user, its author and admins may delete it.
+//	@Tags			recommendations
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			recommendationid	path		string	true	"Recommendation ID"
+//	@Success		200					{object}	map[string]string
+//	@Failure		401					{object}	apierror.Response	"Not authenticated"
+//	@Failure		404					{object}	apierror.Response	"Recommendation not found"
+//	@Failure		500					{object}	apierror.Response	"Could not delete recommendation"
+//	@Router			/recommendations/{recommendationid} [delete]
+func DeleteRecommendation(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	rec, err := repo.Get(ctx, c.Param("recommendationid"))
+	if err == nil && rec.UserID != user.ID && rec.AuthorID != user.ID && !user.Admin {
+		err = store.ErrNotFound
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Recommendation not found"))
+		return
+	}
+	if err == nil {
+		err = repo.Delete(ctx, rec.ID)
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete recommendation"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Recommendation deleted"})
+}
+
+// InviteReferee emails someone without an account a link to write a recommendation for the current user
+//
+//	@Summary		Invite a referee
+//	@Description	Emails a link to write a recommendation for the current user, for referees without an account. The link can be used once, until the invitation expires.
+//	@Tags			recommendations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			invitation	body		InvitationRequest	true	"The referee to invite"
+//	@Success		201			{object}	Invitation
+//	@Failure		400			{object}	apierror.Response	"Invalid request body"
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		413			{object}	apierror.Response	"Too many pending invitations"
+//	@Failure		500			{object}	apierror.Response	"Could not send invitation"
+//	@Router			/recommendations/invitations [post]
+func InviteReferee(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req InvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	if strings.EqualFold(req.Email, user.Email) {
+		apierror.Abort(c, apierror.BadRequest("You can't recommend yourself"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	invitations, err := repo.ListInvitations(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not send invitation"))
+		return
+	}
+	now := time.Now()
+	pending := 0
+	for _, inv := range invitations {
+		if inv.pending(now) {
+			pending++
+		}
+	}
+	if pending >= settings.MaxPendingInvitations {
+		apierror.Abort(c, apierror.QuotaExceeded(fmt.Sprintf("Too many pending invitations, at most %d may wait at once", settings.MaxPendingInvitations)))
+		return
+	}
+
+	inv := Invitation{
+		ID:        utils.GenerateID(),
+		UserID:    user.ID,
+		Name:      req.Name,
+		Email:     req.Email,
+		Token:     utils.GenerateID(),
+		CreatedAt: now,
+		ExpiresAt: now.Add(settings.InvitationTTL.Std()),
+	}
+	if err := repo.CreateInvitation(ctx, inv); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not send invitation"))
+		return
+	}
+	msg, err := email.Render("recommendation_invitation", invitationEmail{
+		Name:      inv.Name,
+		UserName:  user.Name,
+		URL:       fmt.Sprintf("%s/api/v1/recommendations/referee/%s", baseURL(ctx), inv.Token),
+		ExpiresAt: inv.ExpiresAt,
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not render invitation email"))
+		return
+	}
+	msg.To = inv.Email
+	msg.UserID = user.ID
+	if err := email.Enqueue(ctx, msg); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not queue invitation email"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, inv)
+}
+
+// ListInvitations lists the current user's invitations
+//
+//	@Summary		List invitations
+//	@Description	Lists the referees the current user invited, newest first. An invitation is pending until it is used or expires.
+//	@Tags			recommendations
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		Invitation
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve invitations"
+//	@Router			/recommendations/invitations [get]
+func ListInvitations(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	list, err := repo.ListInvitations(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve invitations"))
+		return
+	}
+	if list == nil {
+		list = []Invitation{}
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// DeleteInvitation withdraws one of the current user's invitations
+//
+//	@Summary		Withdraw an invitation
+//	@Description	Deletes one of the current user's invitations, so its link can no longer be used
+//	@Tags			recommendations
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			invitationid	path		string	true	"Invitation ID"
+//	@Success		200				{object}	map[string]string
+//	@Failure		401				{object}	apierror.Response	"Not authenticated"
+//	@Failure		500				{object}	apierror.Response	"Could not delete invitation"
+//	@Router			/recommendations/invitations/{invitationid} [delete]
+func DeleteInvitation(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.DeleteInvitation(ctx, user.ID, c.Param("invitationid")); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete invitation"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation deleted"})
+}
+
+// GetRefereeInvitation shows a referee the invitation they were emailed
+//
+//	@Summary		Get a referee's invitation
+//	@Description	Returns who the invitation with the token from the emailed link asks the referee to recommend, for the form they write their recommendation in
+//	@Tags			recommendations
+//	@Produce		json
+//	@Param			token	path		string	true	"Invitation token"
+//	@Success		200		{object}	RefereeInvitation
+//	@Failure		404		{object}	apierror.Response	"Invitation not found, used or expired"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve invitation"
+//	@Router			/recommendations/referee/{token} [get]
+func GetRefereeInvitation(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	inv, user, err := pendingInvitation(ctx, c.Param("token"))
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RefereeInvitation{Name: inv.Name, UserName: user.Name, ExpiresAt: inv.ExpiresAt})
+}
+
+// WriteAsReferee writes the recommendation an invited referee was asked for
+//
+//	@Summary		Write a recommendation as a referee
+//	@Description	Writes a recommendation through the link emailed to an invited referee, signed with the name they were invited by. Each link can be used once. The recommendation is shown on the user's profile once they approve it.
+//	@Tags			recommendations
+//	@Accept			json
+//	@Produce		json
+//	@Param			token			path		string					true	"Invitation token"
+//	@Param			recommendation	body		RecommendationRequest	true	"The recommendation"
+//	@Success		201				{object}	map[string]string
+//	@Failure		400				{object}	apierror.Response	"Invalid request body"
+//	@Failure		404				{object}	apierror.Response	"Invitation not found, used or expired"
+//	@Failure		500				{object}	apierror.Response	"Could not save recommendation"
+//	@Router			/recommendations/referee/{token} [post]
+func WriteAsReferee(c *gin.Context) {
+	var req RecommendationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	token := c.Param("token")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if _, _, err := pendingInvitation(ctx, token); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+	now := time.Now()
+	// Using the invitation first means two submissions racing each other can't both be saved
+	inv, err := repo.UseInvitation(ctx, token, now)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Invitation not found, used or expired"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not save recommendation"))
+		return
+	}
+	rec := Recommendation{
+		ID:           utils.GenerateID(),
+		UserID:       inv.UserID,
+		AuthorName:   inv.Name,
+		AuthorEmail:  inv.Email,
+		Relationship: req.Relationship,
+		Content:      req.Content,
+		Status:       StatusPending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := repo.Create(ctx, rec); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not save recommendation"))
+		return
+	}
+	notify(ctx, rec)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Recommendation sent"})
+}
+
+// pendingInvitation returns the invitation with the token and the user it is for, or an error to respond
+// with when it was used, has expired or the user can no longer be recommended
+func pendingInvitation(ctx context.Context, token string) (Invitation, auth.User, error) {
+	inv, err := repo.GetInvitation(ctx, token)
+	if err == nil && !inv.pending(time.Now()) {
+		err = store.ErrNotFound
+	}
+	var user auth.User
+	if err == nil {
+		user, err = users.FindByID(ctx, inv.UserID)
+	}
+	if err == nil && user.Disabled {
+		err = store.ErrNotFound
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		return inv, user, apierror.NotFound("Invitation not found, used or expired")
+	}
+	if err != nil {
+		return inv, user, apierror.Wrap(err, "Could not retrieve invitation")
+	}
+	return inv, user, nil
+}
+
+// notify tells the recommended user a recommendation awaits their approval. Failures are logged, as the
+// recommendation is saved either way.
+func notify(ctx context.Context, rec Recommendation) {
+	message := fmt.Sprintf("%s wrote a recommendation for you, approve it to show it on your profile", rec.AuthorName)
+	if err := notifications.Send(ctx, rec.UserID, notifications.KindRecommendation, message, gin.H{"recommendationID": rec.ID}); err != nil {
+		slog.ErrorContext(ctx, "Could not notify user", "kind", notifications.KindRecommendation, "user_id", rec.UserID, "error", err)
+	}
+}
+
+// InitializeRoutes initializes the recommendation routes. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup) {
+	authRequired := auth.AuthMiddleware(users, true)
+
+	router.GET("/u/:userid", ListRecommendations)
+	router.GET("/referee/:token", GetRefereeInvitation)
+	router.POST("/referee/:token", WriteAsReferee)
+
+	protected := router.Group("")
+	protected.Use(authRequired)
+	protected.GET("", ListReceived)
+	protected.POST("/u/:userid", WriteRecommendation)
+	protected.PUT("/:recommendationid/status", SetStatus)
+	protected.DELETE("/:recommendationid", DeleteRecommendation)
+	protected.POST("/invitations", InviteReferee)
+	protected.GET("/invitations", ListInvitations)
+	protected.DELETE("/invitations/:invitationid", DeleteInvitation)
+}
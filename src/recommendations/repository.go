@@ -0,0 +1,37 @@
+package recommendations
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores recommendations and the invitations of external referees
+type Repository interface {
+	// Create stores a new recommendation
+	Create(ctx context.Context, rec Recommendation) error
+	// Get returns a recommendation, or store.ErrNotFound
+	Get(ctx context.Context, recommendationID string) (Recommendation, error)
+	// List returns the recommendations written for the user, newest first, only those with the status
+	// unless it is empty
+	List(ctx context.Context, userID, status string) ([]Recommendation, error)
+	// SetStatus approves or hides a recommendation, or returns store.ErrNotFound
+	SetStatus(ctx context.Context, recommendationID, status string, at time.Time) error
+	// Delete removes a recommendation
+	Delete(ctx context.Context, recommendationID string) error
+	// ListByAuthor returns the recommendations written by the user
+	ListByAuthor(ctx context.Context, authorID string) ([]Recommendation, error)
+	// DeleteByAuthor removes every recommendation written by the user
+	DeleteByAuthor(ctx context.Context, authorID string) error
+
+	// CreateInvitation stores a new invitation
+	CreateInvitation(ctx context.Context, inv Invitation) error
+	// ListInvitations returns the user's invitations, newest first
+	ListInvitations(ctx context.Context, userID string) ([]Invitation, error)
+	// GetInvitation returns the invitation with the token, or store.ErrNotFound
+	GetInvitation(ctx context.Context, token string) (Invitation, error)
+	// UseInvitation marks the invitation with the token used, returning it, or returns store.ErrNotFound
+	// when there is none or it was already used, so each invitation is used once
+	UseInvitation(ctx context.Context, token string, at time.Time) (Invitation, error)
+	// DeleteInvitation removes one of the user's invitations
+	DeleteInvitation(ctx context.Context, userID, invitationID string) error
+}
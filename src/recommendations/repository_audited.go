@@ -0,0 +1,72 @@
+package recommendations
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"profile-api/audit"
+	"profile-api/store"
+)
+
+// AuditedRepository records every change to recommendations in the audit log of the recommended user
+type AuditedRepository struct {
+	Repository
+}
+
+// NewAuditedRepository wraps the repository to record its changes in the audit log
+func NewAuditedRepository(r Repository) *AuditedRepository {
+	return &AuditedRepository{Repository: r}
+}
+
+func (r *AuditedRepository) Create(ctx context.Context, rec Recommendation) error {
+	if err := r.Repository.Create(ctx, rec); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionCreate, "recommendation", rec.UserID, rec.ID, nil, rec)
+	return nil
+}
+
+func (r *AuditedRepository) SetStatus(ctx context.Context, recommendationID, status string, at time.Time) error {
+	before, err := r.Repository.Get(ctx, recommendationID)
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.SetStatus(ctx, recommendationID, status, at); err != nil {
+		return err
+	}
+	after := before
+	after.Status = status
+	after.UpdatedAt = at
+	audit.Record(ctx, audit.ActionUpdate, "recommendation", before.UserID, recommendationID, before, after)
+	return nil
+}
+
+func (r *AuditedRepository) Delete(ctx context.Context, recommendationID string) error {
+	before, err := r.Repository.Get(ctx, recommendationID)
+	if errors.Is(err, store.ErrNotFound) {
+		return r.Repository.Delete(ctx, recommendationID)
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.Delete(ctx, recommendationID); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionDelete, "recommendation", before.UserID, recommendationID, before, nil)
+	return nil
+}
+
+func (r *AuditedRepository) DeleteByAuthor(ctx context.Context, authorID string) error {
+	written, err := r.Repository.ListByAuthor(ctx, authorID)
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.DeleteByAuthor(ctx, authorID); err != nil {
+		return err
+	}
+	for _, rec := range written {
+		audit.Record(ctx, audit.ActionDelete, "recommendation", rec.UserID, rec.ID, rec, nil)
+	}
+	return nil
+}
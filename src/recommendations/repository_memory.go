@@ -0,0 +1,152 @@
+package recommendations
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps recommendations and invitations in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu              sync.RWMutex
+	recommendations []Recommendation
+	invitations     []Invitation
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, rec Recommendation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recommendations = append(r.recommendations, rec)
+	return nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, recommendationID string) (Recommendation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.index(recommendationID)
+	if i < 0 {
+		return Recommendation{}, store.ErrNotFound
+	}
+	return r.recommendations[i], nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID, status string) ([]Recommendation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []Recommendation
+	// Newest first
+	for i := len(r.recommendations) - 1; i >= 0; i-- {
+		rec := r.recommendations[i]
+		if rec.UserID == userID && (status == "" || rec.Status == status) {
+			list = append(list, rec)
+		}
+	}
+	return list, nil
+}
+
+func (r *MemoryRepository) SetStatus(ctx context.Context, recommendationID, status string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(recommendationID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.recommendations[i].Status = status
+	r.recommendations[i].UpdatedAt = at
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, recommendationID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(recommendationID); i >= 0 {
+		r.recommendations = slices.Delete(r.recommendations, i, i+1)
+	}
+	return nil
+}
+
+func (r *MemoryRepository) ListByAuthor(ctx context.Context, authorID string) ([]Recommendation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []Recommendation
+	for _, rec := range r.recommendations {
+		if rec.AuthorID == authorID {
+			list = append(list, rec)
+		}
+	}
+	return list, nil
+}
+
+func (r *MemoryRepository) DeleteByAuthor(ctx context.Context, authorID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recommendations = slices.DeleteFunc(r.recommendations, func(rec Recommendation) bool { return rec.AuthorID == authorID })
+	return nil
+}
+
+func (r *MemoryRepository) CreateInvitation(ctx context.Context, inv Invitation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.invitations = append(r.invitations, inv)
+	return nil
+}
+
+func (r *MemoryRepository) ListInvitations(ctx context.Context, userID string) ([]Invitation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []Invitation
+	for i := len(r.invitations) - 1; i >= 0; i-- {
+		if r.invitations[i].UserID == userID {
+			list = append(list, r.invitations[i])
+		}
+	}
+	return list, nil
+}
+
+func (r *MemoryRepository) GetInvitation(ctx context.Context, token string) (Invitation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.invitationIndex(token)
+	if i < 0 {
+		return Invitation{}, store.ErrNotFound
+	}
+	return r.invitations[i], nil
+}
+
+func (r *MemoryRepository) UseInvitation(ctx context.Context, token string, at time.Time) (Invitation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.invitationIndex(token)
+	if i < 0 || r.invitations[i].UsedAt != nil {
+		return Invitation{}, store.ErrNotFound
+	}
+	r.invitations[i].UsedAt = &at
+	return r.invitations[i], nil
+}
+
+func (r *MemoryRepository) DeleteInvitation(ctx context.Context, userID, invitationID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.invitations = slices.DeleteFunc(r.invitations, func(inv Invitation) bool {
+		return inv.UserID == userID && inv.ID == invitationID
+	})
+	return nil
+}
+
+// index returns the position of a recommendation in recommendations, or -1. The caller must hold the lock.
+func (r *MemoryRepository) index(recommendationID string) int {
+	return slices.IndexFunc(r.recommendations, func(rec Recommendation) bool { return rec.ID == recommendationID })
+}
+
+// invitationIndex returns the position of the invitation with the token, or -1. The caller must hold the lock.
+func (r *MemoryRepository) invitationIndex(token string) int {
+	return slices.IndexFunc(r.invitations, func(inv Invitation) bool { return inv.Token == token })
+}
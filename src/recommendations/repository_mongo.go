@@ -0,0 +1,124 @@
+package recommendations
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores recommendations in the recommendations collection and invitations in
+// recommendation_invitations
+type MongoRepository struct {
+	recommendations *mongo.Collection
+	invitations     *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		recommendations: db.Collection("recommendations"),
+		invitations:     db.Collection("recommendation_invitations"),
+	}
+}
+
+func (r *MongoRepository) Create(ctx context.Context, rec Recommendation) error {
+	_, err := r.recommendations.InsertOne(ctx, rec)
+	return err
+}
+
+func (r *MongoRepository) Get(ctx context.Context, recommendationID string) (Recommendation, error) {
+	var rec Recommendation
+	err := r.recommendations.FindOne(ctx, bson.M{"_id": recommendationID}).Decode(&rec)
+	return rec, store.MongoErr(err)
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID, status string) ([]Recommendation, error) {
+	filter := bson.M{"user_id": userID}
+	if status != "" {
+		filter["status"] = status
+	}
+	cursor, err := r.recommendations.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	var list []Recommendation
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *MongoRepository) SetStatus(ctx context.Context, recommendationID, status string, at time.Time) error {
+	result, err := r.recommendations.UpdateOne(ctx, bson.M{"_id": recommendationID}, bson.M{"$set": bson.M{"status": status, "updated_at": at}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, recommendationID string) error {
+	_, err := r.recommendations.DeleteOne(ctx, bson.M{"_id": recommendationID})
+	return err
+}
+
+func (r *MongoRepository) ListByAuthor(ctx context.Context, authorID string) ([]Recommendation, error) {
+	cursor, err := r.recommendations.Find(ctx, bson.M{"author_id": authorID})
+	if err != nil {
+		return nil, err
+	}
+	var list []Recommendation
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *MongoRepository) DeleteByAuthor(ctx context.Context, authorID string) error {
+	_, err := r.recommendations.DeleteMany(ctx, bson.M{"author_id": authorID})
+	return err
+}
+
+func (r *MongoRepository) CreateInvitation(ctx context.Context, inv Invitation) error {
+	_, err := r.invitations.InsertOne(ctx, inv)
+	return err
+}
+
+func (r *MongoRepository) ListInvitations(ctx context.Context, userID string) ([]Invitation, error) {
+	cursor, err := r.invitations.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	var list []Invitation
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *MongoRepository) GetInvitation(ctx context.Context, token string) (Invitation, error) {
+	var inv Invitation
+	err := r.invitations.FindOne(ctx, bson.M{"token": token}).Decode(&inv)
+	return inv, store.MongoErr(err)
+}
+
+func (r *MongoRepository) UseInvitation(ctx context.Context, token string, at time.Time) (Invitation, error) {
+	var inv Invitation
+	err := r.invitations.FindOneAndUpdate(ctx,
+		bson.M{"token": token, "used_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"used_at": at}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&inv)
+	return inv, store.MongoErr(err)
+}
+
+func (r *MongoRepository) DeleteInvitation(ctx context.Context, userID, invitationID string) error {
+	_, err := r.invitations.DeleteOne(ctx, bson.M{"_id": invitationID, "user_id": userID})
+	return err
+}
@@ -0,0 +1,130 @@
+package recommendations
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	recommendationColumns = "id, user_id, author_id, author_name, author_email, relationship, content, status, created_at, updated_at"
+	invitationColumns     = "id, user_id, name, email, token, created_at, expires_at, used_at"
+)
+
+// PostgresRepository stores recommendations in the recommendations table and invitations in
+// recommendation_invitations
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, rec Recommendation) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO recommendations ("+recommendationColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		rec.ID, rec.UserID, rec.AuthorID, rec.AuthorName, rec.AuthorEmail, rec.Relationship, rec.Content, rec.Status, rec.CreatedAt, rec.UpdatedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, recommendationID string) (Recommendation, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+recommendationColumns+" FROM recommendations WHERE id = $1", recommendationID)
+	if err != nil {
+		return Recommendation{}, err
+	}
+	rec, err := pgx.CollectExactlyOneRow(rows, scanRecommendation)
+	return rec, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID, status string) ([]Recommendation, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+recommendationColumns+" FROM recommendations WHERE user_id = $1 AND ($2 = '' OR status = $2) ORDER BY created_at DESC", userID, status)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanRecommendation)
+}
+
+func (r *PostgresRepository) SetStatus(ctx context.Context, recommendationID, status string, at time.Time) error {
+	tag, err := r.pool.Exec(ctx, "UPDATE recommendations SET status = $2, updated_at = $3 WHERE id = $1", recommendationID, status, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, recommendationID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM recommendations WHERE id = $1", recommendationID)
+	return err
+}
+
+func (r *PostgresRepository) ListByAuthor(ctx context.Context, authorID string) ([]Recommendation, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+recommendationColumns+" FROM recommendations WHERE author_id = $1", authorID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanRecommendation)
+}
+
+func (r *PostgresRepository) DeleteByAuthor(ctx context.Context, authorID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM recommendations WHERE author_id = $1", authorID)
+	return err
+}
+
+func (r *PostgresRepository) CreateInvitation(ctx context.Context, inv Invitation) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO recommendation_invitations ("+invitationColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		inv.ID, inv.UserID, inv.Name, inv.Email, inv.Token, inv.CreatedAt, inv.ExpiresAt, inv.UsedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) ListInvitations(ctx context.Context, userID string) ([]Invitation, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+invitationColumns+" FROM recommendation_invitations WHERE user_id = $1 ORDER BY created_at DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanInvitation)
+}
+
+func (r *PostgresRepository) GetInvitation(ctx context.Context, token string) (Invitation, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+invitationColumns+" FROM recommendation_invitations WHERE token = $1", token)
+	if err != nil {
+		return Invitation{}, err
+	}
+	inv, err := pgx.CollectExactlyOneRow(rows, scanInvitation)
+	return inv, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) UseInvitation(ctx context.Context, token string, at time.Time) (Invitation, error) {
+	rows, err := r.pool.Query(ctx, "UPDATE recommendation_invitations SET used_at = $2 WHERE token = $1 AND used_at IS NULL RETURNING "+invitationColumns, token, at)
+	if err != nil {
+		return Invitation{}, err
+	}
+	inv, err := pgx.CollectExactlyOneRow(rows, scanInvitation)
+	return inv, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) DeleteInvitation(ctx context.Context, userID, invitationID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM recommendation_invitations WHERE id = $1 AND user_id = $2", invitationID, userID)
+	return err
+}
+
+// scanRecommendation reads a row selected with recommendationColumns
+func scanRecommendation(row pgx.CollectableRow) (Recommendation, error) {
+	var rec Recommendation
+	err := row.Scan(&rec.ID, &rec.UserID, &rec.AuthorID, &rec.AuthorName, &rec.AuthorEmail, &rec.Relationship, &rec.Content, &rec.Status, &rec.CreatedAt, &rec.UpdatedAt)
+	return rec, err
+}
+
+// scanInvitation reads a row selected with invitationColumns
+func scanInvitation(row pgx.CollectableRow) (Invitation, error) {
+	var inv Invitation
+	err := row.Scan(&inv.ID, &inv.UserID, &inv.Name, &inv.Email, &inv.Token, &inv.CreatedAt, &inv.ExpiresAt, &inv.UsedAt)
+	return inv, err
+}
@@ -0,0 +1,22 @@
+package recommendations
+
+import (
+	"context"
+
+	"profile-api/sanitize"
+)
+
+// SanitizedRepository cleans the user-supplied text of recommendations before storing them
+type SanitizedRepository struct {
+	Repository
+}
+
+// NewSanitizedRepository wraps the repository to clean recommendations with the configured sanitize policies
+func NewSanitizedRepository(r Repository) *SanitizedRepository {
+	return &SanitizedRepository{Repository: r}
+}
+
+func (r *SanitizedRepository) Create(ctx context.Context, rec Recommendation) error {
+	rec.Content = sanitize.Field(sanitize.RecommendationContent, rec.Content)
+	return r.Repository.Create(ctx, rec)
+}
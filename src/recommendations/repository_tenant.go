@@ -0,0 +1,66 @@
+package recommendations
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, rec Recommendation) error {
+	return r.repos.For(ctx).Create(ctx, rec)
+}
+
+func (r *TenantRepository) Get(ctx context.Context, recommendationID string) (Recommendation, error) {
+	return r.repos.For(ctx).Get(ctx, recommendationID)
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID, status string) ([]Recommendation, error) {
+	return r.repos.For(ctx).List(ctx, userID, status)
+}
+
+func (r *TenantRepository) SetStatus(ctx context.Context, recommendationID, status string, at time.Time) error {
+	return r.repos.For(ctx).SetStatus(ctx, recommendationID, status, at)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, recommendationID string) error {
+	return r.repos.For(ctx).Delete(ctx, recommendationID)
+}
+
+func (r *TenantRepository) ListByAuthor(ctx context.Context, authorID string) ([]Recommendation, error) {
+	return r.repos.For(ctx).ListByAuthor(ctx, authorID)
+}
+
+func (r *TenantRepository) DeleteByAuthor(ctx context.Context, authorID string) error {
+	return r.repos.For(ctx).DeleteByAuthor(ctx, authorID)
+}
+
+func (r *TenantRepository) CreateInvitation(ctx context.Context, inv Invitation) error {
+	return r.repos.For(ctx).CreateInvitation(ctx, inv)
+}
+
+func (r *TenantRepository) ListInvitations(ctx context.Context, userID string) ([]Invitation, error) {
+	return r.repos.For(ctx).ListInvitations(ctx, userID)
+}
+
+func (r *TenantRepository) GetInvitation(ctx context.Context, token string) (Invitation, error) {
+	return r.repos.For(ctx).GetInvitation(ctx, token)
+}
+
+func (r *TenantRepository) UseInvitation(ctx context.Context, token string, at time.Time) (Invitation, error) {
+	return r.repos.For(ctx).UseInvitation(ctx, token, at)
+}
+
+func (r *TenantRepository) DeleteInvitation(ctx context.Context, userID, invitationID string) error {
+	return r.repos.For(ctx).DeleteInvitation(ctx, userID, invitationID)
+}
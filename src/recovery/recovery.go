@@ -0,0 +1,140 @@
+// Package recovery turns panics in request handlers into the same 500 response as any other internal error,
+// carrying the request ID, so a handler bug neither drops the connection nor shows its stack to the client.
+// The stack is logged instead, and admins are alerted through their notifications, at most once per alert
+// interval for each route so a panic on a busy route does not flood them.
+package recovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"sync"
+	"syscall"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/config"
+	"profile-api/logging"
+	"profile-api/requestid"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Panic describes a request that failed on a panic, as alerted to admins
+type Panic struct {
+	Method    string    `json:"method"`
+	Route     string    `json:"route"`
+	Path      string    `json:"path"`
+	RequestID string    `json:"requestID"`
+	Value     string    `json:"value"`
+	Time      time.Time `json:"time"`
+}
+
+// Alert reports a panic to admins
+type Alert func(ctx context.Context, p Panic) error
+
+var (
+	settings config.RecoveryConfig
+	alert    Alert
+
+	mu sync.Mutex
+	// alerted holds when admins were last alerted of a panic on each route
+	alerted = map[string]time.Time{}
+)
+
+// Configure sets how often admins are alerted of panics and how
+func Configure(cfg config.RecoveryConfig, a Alert) {
+	settings = cfg
+	alert = a
+}
+
+// Middleware recovers from panics in the handlers after it, responding 500 with the error envelope unless the
+// response was already started. It must run after the request ID is assigned.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			// The handler gave up on the response on purpose, which net/http handles itself
+			if value == http.ErrAbortHandler {
+				panic(value)
+			}
+			if brokenPipe(value) {
+				logging.Logger(c).Warn("Client went away", "error", value)
+				c.Abort()
+				return
+			}
+
+			p := Panic{
+				Method:    c.Request.Method,
+				Route:     c.FullPath(),
+				Path:      c.Request.URL.Path,
+				RequestID: requestid.Get(c),
+				Value:     fmt.Sprint(value),
+				Time:      time.Now(),
+			}
+			logging.Logger(c).Error("Recovered from panic", "panic", p.Value, "route", p.Route, "stack", string(debug.Stack()))
+			notify(c.Request.Context(), p)
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			// Written directly, as the panic is already logged
+			c.AbortWithStatusJSON(http.StatusInternalServerError, apierror.Response{
+				Code:      apierror.CodeInternal,
+				Message:   "Internal server error",
+				RequestID: p.RequestID,
+			})
+		}()
+		c.Next()
+	}
+}
+
+// brokenPipe reports whether the panic is from writing to a client that closed the connection
+func brokenPipe(value any) bool {
+	err, ok := value.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var syscallErr *os.SyscallError
+	return errors.As(opErr, &syscallErr) && (errors.Is(syscallErr, syscall.EPIPE) || errors.Is(syscallErr, syscall.ECONNRESET))
+}
+
+// notify alerts admins of the panic in the background, unless they were alerted of one on the same route
+// within the alert interval
+func notify(ctx context.Context, p Panic) {
+	if alert == nil || settings.AlertInterval <= 0 {
+		return
+	}
+	mu.Lock()
+	if last, ok := alerted[p.Route]; ok && p.Time.Sub(last) < settings.AlertInterval.Std() {
+		mu.Unlock()
+		return
+	}
+	alerted[p.Route] = p.Time
+	mu.Unlock()
+
+	// The request is over by the time the alert is sent, so it only keeps the request's tenant
+	tenantID := tenant.ID(ctx)
+	go func() {
+		ctx, cancel := utils.WithOperationTimeout(tenant.WithID(context.Background(), tenantID))
+		defer cancel()
+		if err := alert(ctx, p); err != nil {
+			slog.Error("Could not alert admins of panic", "route", p.Route, "request_id", p.RequestID, "error", err)
+		}
+	}()
+}
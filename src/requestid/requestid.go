@@ -0,0 +1,89 @@
+package requestid
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header carrying the request ID
+const Header = "X-Request-ID"
+
+// contextKey is the Gin context key the request ID is stored under
+const contextKey = "requestID"
+
+// requestContextKey is the key the request ID is stored under in the request's context, for code without the Gin context
+type requestContextKey struct{}
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Middleware assigns every request an ID, returned in the X-Request-ID response header.
+// An incoming X-Request-ID is reused only when the request comes directly from one of the
+// trusted proxies, given as IP addresses or CIDR ranges.
+func Middleware(trustedProxies []string) gin.HandlerFunc {
+	trusted := parseNetworks(trustedProxies)
+
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if id == "" || !validID.MatchString(id) || !isTrusted(c.Request, trusted) {
+			id = uuid.New().String()
+		}
+
+		c.Set(contextKey, id)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestContextKey{}, id))
+		c.Header(Header, id)
+		c.Next()
+	}
+}
+
+// Get returns the ID of the current request
+func Get(c *gin.Context) string {
+	return c.GetString(contextKey)
+}
+
+// FromContext returns the ID of the request the context was derived from, or an empty string outside a request
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestContextKey{}).(string)
+	return id
+}
+
+// parseNetworks converts IP addresses and CIDR ranges into networks, ignoring invalid entries
+func parseNetworks(entries []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, entry := range entries {
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			networks = append(networks, network)
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	return networks
+}
+
+// isTrusted reports whether the request's direct peer is one of the trusted networks
+func isTrusted(r *http.Request, trusted []*net.IPNet) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
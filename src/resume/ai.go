@@ -0,0 +1,28 @@
+package resume
+
+import (
+	"context"
+
+	"profile-api/ai"
+)
+
+// maxPromptText bounds the text of a CV sent to the AI provider, which is far longer than any CV
+const maxPromptText = 50000
+
+const aiInstructions = `You read CVs into structured records. Answer with a JSON object holding three arrays:
+"experience" of objects with "company", "position", "start", "end" and "description";
+"qualifications" of objects with "title", "institution", "start", "end" and "description", for degrees, courses and other education;
+"skills" of objects with "name" and "proficiency_level", which is beginner, intermediate, advanced or expert when the CV suggests one and empty otherwise.
+Dates are YYYY-MM or YYYY, and "end" is empty for ongoing roles and studies. Only include what the CV states, leaving unknown fields empty.`
+
+// AIParser has the AI provider read CVs, for layouts the heuristics cannot follow
+type AIParser struct{}
+
+func (AIParser) Parse(ctx context.Context, text string) (Proposal, error) {
+	if len(text) > maxPromptText {
+		text = text[:maxPromptText]
+	}
+	var proposal Proposal
+	err := ai.CompleteJSON(ctx, ai.Request{System: aiInstructions, Prompt: text}, &proposal)
+	return proposal, err
+}
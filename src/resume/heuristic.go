@@ -0,0 +1,248 @@
+package resume
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"profile-api/experience"
+	"profile-api/qualifications"
+	"profile-api/skills"
+	"profile-api/validation"
+)
+
+// HeuristicParser reads CVs locally, finding their sections by their headings and the entries in them by
+// their date ranges. It suits the common layouts of one entry after another under headings such as
+// Experience, Education and Skills.
+type HeuristicParser struct{}
+
+// CV sections
+const (
+	sectionNone = iota
+	sectionExperience
+	sectionEducation
+	sectionSkills
+	sectionOther
+)
+
+// sectionHeadings maps the headings of CV sections, lowercased, to the section
+var sectionHeadings = map[string]int{
+	"experience":                   sectionExperience,
+	"work experience":              sectionExperience,
+	"professional experience":      sectionExperience,
+	"relevant experience":          sectionExperience,
+	"employment":                   sectionExperience,
+	"employment history":           sectionExperience,
+	"work history":                 sectionExperience,
+	"career history":               sectionExperience,
+	"education":                    sectionEducation,
+	"qualifications":               sectionEducation,
+	"education and training":       sectionEducation,
+	"academic background":          sectionEducation,
+	"education & qualifications":   sectionEducation,
+	"education and qualifications": sectionEducation,
+	"skills":                       sectionSkills,
+	"technical skills":             sectionSkills,
+	"key skills":                   sectionSkills,
+	"core skills":                  sectionSkills,
+	"core competencies":            sectionSkills,
+	"skills & expertise":           sectionSkills,
+	"skills and expertise":         sectionSkills,
+	"summary":                      sectionOther,
+	"profile":                      sectionOther,
+	"personal statement":           sectionOther,
+	"about me":                     sectionOther,
+	"projects":                     sectionOther,
+	"certifications":               sectionOther,
+	"awards":                       sectionOther,
+	"languages":                    sectionOther,
+	"interests":                    sectionOther,
+	"hobbies":                      sectionOther,
+	"references":                   sectionOther,
+	"publications":                 sectionOther,
+	"volunteering":                 sectionOther,
+}
+
+const (
+	monthPattern = `(?:jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?`
+	datePattern  = `(?:` + monthPattern + `\s+\d{4}|\d{1,2}/\d{4}|\d{4}-\d{2}|\d{4})`
+)
+
+var (
+	// dateRange matches ranges such as "Jan 2019 - Present", "03/2017 – 06/2019" or "2015 to 2018"
+	dateRange = regexp.MustCompile(`(?i)(` + datePattern + `)\s*(?:-|–|—|to|until)\s*(` + datePattern + `|present|current|now|today|ongoing)\b`)
+	// singleYear matches a lone year, such as the year of a degree
+	singleYear   = regexp.MustCompile(`\b(19|20)\d{2}\b`)
+	monthYear    = regexp.MustCompile(`(?i)^(` + monthPattern + `)\s+(\d{4})$`)
+	numericMonth = regexp.MustCompile(`^(\d{1,2})/(\d{4})$`)
+	// headerSeparator splits a heading line such as "Engineer at Acme" or "Acme | Engineer"
+	headerSeparator = regexp.MustCompile(`\s+(?:at|@|\||—|–|-)\s+|,\s+`)
+	bullet          = regexp.MustCompile(`^[•·▪◦‣\-*–]\s*`)
+	skillSeparator  = regexp.MustCompile(`[,;•·▪|]|\s{2,}|\t`)
+)
+
+// maxHeaderLines is how many lines before a date range are taken as the entry's heading
+const maxHeaderLines = 2
+
+func (HeuristicParser) Parse(ctx context.Context, text string) (Proposal, error) {
+	sections := map[int][]string{}
+	section := sectionNone
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.ReplaceAll(line, "\t", "  "))
+		if line == "" {
+			continue
+		}
+		if s, ok := heading(line); ok {
+			section = s
+			continue
+		}
+		sections[section] = append(sections[section], line)
+	}
+
+	var proposal Proposal
+	for _, entry := range entries(sections[sectionExperience]) {
+		position, company := splitHeader(entry.header)
+		proposal.Experience = append(proposal.Experience, experience.Experience{
+			Company:     company,
+			Position:    position,
+			Start:       entry.start,
+			End:         entry.end,
+			Description: entry.description,
+		})
+	}
+	for _, entry := range entries(sections[sectionEducation]) {
+		title, institution := splitHeader(entry.header)
+		proposal.Qualifications = append(proposal.Qualifications, qualifications.Qualification{
+			Title:       title,
+			Institution: institution,
+			Start:       entry.start,
+			End:         entry.end,
+			Description: entry.description,
+		})
+	}
+	seen := map[string]bool{}
+	for _, line := range sections[sectionSkills] {
+		// Drop a leading label such as "Languages: Go, Python"
+		if label, rest, ok := strings.Cut(line, ":"); ok && len(label) < 30 {
+			line = rest
+		}
+		for _, name := range skillSeparator.Split(bullet.ReplaceAllString(line, ""), -1) {
+			name = strings.TrimSpace(bullet.ReplaceAllString(strings.TrimSpace(name), ""))
+			key := strings.ToLower(name)
+			if name == "" || len(name) > 60 || seen[key] {
+				continue
+			}
+			seen[key] = true
+			proposal.Skills = append(proposal.Skills, skills.Skill{Name: name})
+		}
+	}
+	return proposal, nil
+}
+
+// heading returns the section a line is the heading of
+func heading(line string) (int, bool) {
+	if len(line) > 40 {
+		return 0, false
+	}
+	s, ok := sectionHeadings[strings.ToLower(strings.TrimRight(line, ": "))]
+	return s, ok
+}
+
+// entry is a dated entry of a section, such as a role or a degree
+type entry struct {
+	header      string
+	start, end  string
+	description string
+}
+
+// entries splits the lines of a section into entries at each line holding a date range, or a lone year
+// where the section has no ranges. The lines just before a date are the entry's heading, along with the
+// rest of the date's line, and the lines after it up to the next heading are its description.
+func entries(lines []string) []entry {
+	var dated []int
+	for i, line := range lines {
+		if dateRange.MatchString(line) {
+			dated = append(dated, i)
+		}
+	}
+	ranges := len(dated) > 0
+	if !ranges {
+		for i, line := range lines {
+			if singleYear.MatchString(line) && !bullet.MatchString(line) {
+				dated = append(dated, i)
+			}
+		}
+	}
+
+	var result []entry
+	// previousEnd is where the lines of the previous entry's description start
+	previousEnd := 0
+	for n, i := range dated {
+		headerStart := i
+		for headerStart > previousEnd && i-headerStart < maxHeaderLines && !bullet.MatchString(lines[headerStart-1]) && len(lines[headerStart-1]) <= 100 {
+			headerStart--
+		}
+		if n > 0 {
+			result[n-1].description = strings.Join(lines[previousEnd:headerStart], "\n")
+		}
+
+		var e entry
+		rest := lines[i]
+		if ranges {
+			m := dateRange.FindStringSubmatchIndex(rest)
+			e.start = normalizeDate(rest[m[2]:m[3]])
+			e.end = normalizeDate(rest[m[4]:m[5]])
+			rest = rest[:m[0]] + rest[m[1]:]
+		} else {
+			loc := singleYear.FindStringIndex(rest)
+			e.end = rest[loc[0]:loc[1]]
+			rest = rest[:loc[0]] + rest[loc[1]:]
+		}
+		header := slices.Clone(lines[headerStart:i])
+		if rest = strings.Trim(rest, " ,|()–—-"); rest != "" {
+			header = append(header, rest)
+		}
+		e.header = strings.Join(header, ", ")
+		result = append(result, e)
+		previousEnd = i + 1
+	}
+	if len(result) > 0 {
+		result[len(result)-1].description = strings.Join(lines[previousEnd:], "\n")
+	}
+	return result
+}
+
+// splitHeader splits an entry's heading into its title, such as the position, and the organisation
+func splitHeader(header string) (title, organisation string) {
+	parts := headerSeparator.Split(header, 2)
+	title = strings.TrimSpace(parts[0])
+	if len(parts) > 1 {
+		organisation = strings.TrimSpace(parts[1])
+	}
+	return title, organisation
+}
+
+// normalizeDate converts a date of a CV to the partial ISO 8601 dates records hold, or empty for dates
+// such as "Present"
+func normalizeDate(value string) string {
+	value = strings.TrimSpace(value)
+	if m := monthYear.FindStringSubmatch(value); m != nil {
+		month, err := time.Parse("Jan", strings.ToUpper(m[1][:1])+strings.ToLower(m[1][1:3]))
+		if err != nil {
+			return m[2]
+		}
+		return m[2] + "-" + month.Format("01")
+	}
+	if m := numericMonth.FindStringSubmatch(value); m != nil {
+		if len(m[1]) == 1 {
+			m[1] = "0" + m[1]
+		}
+		return m[2] + "-" + m[1]
+	}
+	if validation.IsDate(value) {
+		return value
+	}
+	return ""
+}
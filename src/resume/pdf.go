@@ -0,0 +1,443 @@
+package resume
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// maxCMapEntries bounds the character maps read from a PDF
+const maxCMapEntries = 1 << 17
+
+var (
+	pdfStreamStart = regexp.MustCompile(`stream\r?\n`)
+	// pdfSkippedStreams matches the dictionaries of streams holding no text: images, embedded fonts,
+	// cross-reference tables and compressed objects
+	pdfSkippedStreams = regexp.MustCompile(`/Subtype\s*/Image|/Length[123]\b|/Type\s*/(XRef|ObjStm|Metadata)`)
+	pdfFilter         = regexp.MustCompile(`/Filter\s*\[?\s*/(\w+)`)
+	pdfCMapSection    = regexp.MustCompile(`(?s)begin(bfchar|bfrange)(.*?)endbf(?:char|range)`)
+	pdfCMapToken      = regexp.MustCompile(`<[0-9A-Fa-f\s]*>|\[|\]`)
+)
+
+// pdfText returns the text a PDF draws, in the order its content streams draw it. Text is decoded with
+// the fonts' ToUnicode maps where there are any, which are merged rather than matched to their fonts, so
+// a CV whose fonts disagree may come out garbled. Text drawn as images cannot be read.
+func pdfText(data []byte) (string, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return "", fmt.Errorf("not a PDF file")
+	}
+	var contents [][]byte
+	cmap := map[string]string{}
+	for _, stream := range pdfStreams(data) {
+		if bytes.Contains(stream, []byte("begincmap")) {
+			parseCMap(stream, cmap)
+			continue
+		}
+		contents = append(contents, stream)
+	}
+
+	var text strings.Builder
+	for _, content := range contents {
+		(&pdfContent{lexer: pdfLexer{data: content}, cmap: cmap, text: &text}).read()
+		if text.Len() > maxTextSize {
+			break
+		}
+	}
+	return text.String(), nil
+}
+
+// pdfStreams returns the decoded data of the streams that may hold text. Streams compressed with filters
+// other than Flate are skipped.
+func pdfStreams(data []byte) [][]byte {
+	var streams [][]byte
+	for _, loc := range pdfStreamStart.FindAllIndex(data, -1) {
+		// The dictionary is between the object header and the stream keyword
+		dictStart := bytes.LastIndex(data[:loc[0]], []byte("obj"))
+		if dictStart < 0 || !bytes.HasSuffix(bytes.TrimSpace(data[dictStart:loc[0]]), []byte(">>")) {
+			continue
+		}
+		dict := data[dictStart:loc[0]]
+		end := bytes.Index(data[loc[1]:], []byte("endstream"))
+		if end < 0 || pdfSkippedStreams.Match(dict) {
+			continue
+		}
+		raw := data[loc[1] : loc[1]+end]
+
+		filter := pdfFilter.FindSubmatch(dict)
+		switch {
+		case filter == nil:
+			streams = append(streams, raw)
+		case string(filter[1]) == "FlateDecode":
+			r, err := zlib.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				continue
+			}
+			// Streams cut short by a trailing end of line still yield what was decoded
+			decoded, _ := io.ReadAll(io.LimitReader(r, maxTextSize*4))
+			if len(decoded) > 0 {
+				streams = append(streams, decoded)
+			}
+		}
+	}
+	return streams
+}
+
+// parseCMap adds the character codes mapped by a ToUnicode CMap to cmap, keyed by the code's bytes
+func parseCMap(data []byte, cmap map[string]string) {
+	for _, section := range pdfCMapSection.FindAllSubmatch(data, -1) {
+		tokens := pdfCMapToken.FindAll(section[2], -1)
+		if string(section[1]) == "bfchar" {
+			for i := 0; i+1 < len(tokens) && len(cmap) < maxCMapEntries; i += 2 {
+				cmap[string(hexBytes(tokens[i]))] = utf16String(hexBytes(tokens[i+1]))
+			}
+			continue
+		}
+		for i := 0; i+2 < len(tokens) && len(cmap) < maxCMapEntries; {
+			lo, hi := hexBytes(tokens[i]), hexBytes(tokens[i+1])
+			i += 2
+			var dsts [][]byte
+			if string(tokens[i]) == "[" {
+				for i++; i < len(tokens) && string(tokens[i]) != "]"; i++ {
+					dsts = append(dsts, hexBytes(tokens[i]))
+				}
+			} else {
+				dsts = append(dsts, hexBytes(tokens[i]))
+			}
+			i++
+			if len(lo) != len(hi) || len(lo) == 0 || len(lo) > 2 || len(dsts) == 0 {
+				continue
+			}
+			from, to := codeValue(lo), codeValue(hi)
+			for code := from; code <= to && len(cmap) < maxCMapEntries; code++ {
+				var dst []byte
+				if len(dsts) > 1 {
+					if code-from >= len(dsts) {
+						break
+					}
+					dst = dsts[code-from]
+				} else {
+					// A single destination is incremented along the range
+					dst = bytes.Clone(dsts[0])
+					if len(dst) > 0 {
+						dst[len(dst)-1] += byte(code - from)
+					}
+				}
+				key := []byte{byte(code)}
+				if len(lo) == 2 {
+					key = []byte{byte(code >> 8), byte(code)}
+				}
+				cmap[string(key)] = utf16String(dst)
+			}
+		}
+	}
+}
+
+func codeValue(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+// hexBytes decodes a hex string such as <00A0>, ignoring whitespace and padding an odd last digit
+func hexBytes(token []byte) []byte {
+	digits := bytes.Map(func(r rune) rune {
+		if strings.ContainsRune("<> \t\r\n\f", r) {
+			return -1
+		}
+		return r
+	}, token)
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	b, _ := hex.DecodeString(string(digits))
+	return b
+}
+
+func utf16String(b []byte) string {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
+
+// pdfToken is a token of a content stream
+type pdfToken struct {
+	kind  byte // 'n' number, 's' string, 'o' operator, 'a' array, '/' name
+	num   float64
+	str   []byte
+	op    string
+	array []pdfToken
+}
+
+// pdfLexer splits a content stream into tokens
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func (l *pdfLexer) next() (pdfToken, bool) {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isPDFSpace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		case c == '(':
+			return pdfToken{kind: 's', str: l.literal()}, true
+		case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<', c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+			// Dictionaries only hold marked-content properties, which are not text
+			l.pos += 2
+		case c == '<':
+			end := bytes.IndexByte(l.data[l.pos:], '>')
+			if end < 0 {
+				end = len(l.data) - l.pos - 1
+			}
+			token := pdfToken{kind: 's', str: hexBytes(l.data[l.pos : l.pos+end+1])}
+			l.pos += end + 1
+			return token, true
+		case c == '[':
+			l.pos++
+			var array []pdfToken
+			for {
+				token, ok := l.next()
+				if !ok || (token.kind == 'o' && token.op == "]") {
+					break
+				}
+				array = append(array, token)
+			}
+			return pdfToken{kind: 'a', array: array}, true
+		case c == ']':
+			l.pos++
+			return pdfToken{kind: 'o', op: "]"}, true
+		case c == '/':
+			start := l.pos
+			l.pos++
+			l.regular()
+			return pdfToken{kind: '/', op: string(l.data[start:l.pos])}, true
+		default:
+			start := l.pos
+			l.regular()
+			if l.pos == start {
+				// A stray delimiter
+				l.pos++
+				continue
+			}
+			word := string(l.data[start:l.pos])
+			if n, err := strconv.ParseFloat(word, 64); err == nil {
+				return pdfToken{kind: 'n', num: n}, true
+			}
+			return pdfToken{kind: 'o', op: word}, true
+		}
+	}
+	return pdfToken{}, false
+}
+
+// regular advances past a run of regular characters
+func (l *pdfLexer) regular() {
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !strings.ContainsRune("()<>[]{}/%", rune(l.data[l.pos])) {
+		l.pos++
+	}
+}
+
+// literal reads a string in parentheses, which may nest balanced parentheses
+func (l *pdfLexer) literal() []byte {
+	var s []byte
+	depth := 0
+	for l.pos++; l.pos < len(l.data); l.pos++ {
+		c := l.data[l.pos]
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				l.pos++
+				return s
+			}
+			depth--
+		case '\\':
+			l.pos++
+			if l.pos >= len(l.data) {
+				return s
+			}
+			e := l.data[l.pos]
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				// A line continuation
+				if e == '\r' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '\n' {
+					l.pos++
+				}
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := 0
+					for i := 0; i < 3 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					l.pos--
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		s = append(s, c)
+	}
+	return s
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+// pdfContent reads the text drawn by a content stream
+type pdfContent struct {
+	lexer    pdfLexer
+	cmap     map[string]string
+	text     *strings.Builder
+	operands []pdfToken
+	// lineY is the vertical position set by the last text matrix, to break lines when it changes
+	lineY float64
+}
+
+func (p *pdfContent) read() {
+	for {
+		token, ok := p.lexer.next()
+		if !ok {
+			return
+		}
+		if token.kind != 'o' {
+			p.operands = append(p.operands, token)
+			continue
+		}
+		p.operator(token.op)
+		p.operands = p.operands[:0]
+	}
+}
+
+func (p *pdfContent) operator(op string) {
+	switch op {
+	case "Tj":
+		p.show(p.operand(0))
+	case "'", "\"":
+		p.newline()
+		p.show(p.operand(0))
+	case "TJ":
+		for _, item := range p.operand(0).array {
+			switch item.kind {
+			case 's':
+				p.show(item)
+			case 'n':
+				// Large negative adjustments move the next glyph right, as between words
+				if item.num < -250 {
+					p.space()
+				}
+			}
+		}
+	case "T*":
+		p.newline()
+	case "Td", "TD":
+		if p.operand(0).num != 0 {
+			p.newline()
+		} else {
+			p.space()
+		}
+	case "Tm":
+		if y := p.operand(0).num; y != p.lineY {
+			p.lineY = y
+			p.newline()
+		} else {
+			p.space()
+		}
+	case "ET":
+		p.space()
+	case "ID":
+		// Skip the data of an inline image
+		end := bytes.Index(p.lexer.data[p.lexer.pos:], []byte("EI"))
+		if end < 0 {
+			p.lexer.pos = len(p.lexer.data)
+		} else {
+			p.lexer.pos += end + 2
+		}
+	}
+}
+
+// operand returns the operand back from the last, or a zero token
+func (p *pdfContent) operand(back int) pdfToken {
+	if back >= len(p.operands) {
+		return pdfToken{}
+	}
+	return p.operands[len(p.operands)-1-back]
+}
+
+func (p *pdfContent) show(token pdfToken) {
+	if token.kind == 's' {
+		p.text.WriteString(decodePDFString(token.str, p.cmap))
+	}
+}
+
+func (p *pdfContent) space() {
+	if s := p.text.String(); s != "" && !strings.HasSuffix(s, " ") && !strings.HasSuffix(s, "\n") {
+		p.text.WriteByte(' ')
+	}
+}
+
+func (p *pdfContent) newline() {
+	if s := p.text.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		p.text.WriteByte('\n')
+	}
+}
+
+// decodePDFString decodes the text of a string shown by a content stream. Two-byte codes are read with
+// the ToUnicode maps when they map every code. Single bytes are read as WinAnsi, the encoding of the
+// standard fonts, except control characters a map names, as subset fonts number their glyphs from one.
+func decodePDFString(s []byte, cmap map[string]string) string {
+	if bytes.HasPrefix(s, []byte{0xFE, 0xFF}) {
+		return utf16String(s[2:])
+	}
+	if len(s)%2 == 0 && len(s) > 0 {
+		var text strings.Builder
+		mapped := true
+		for i := 0; i < len(s) && mapped; i += 2 {
+			var r string
+			r, mapped = cmap[string(s[i:i+2])]
+			text.WriteString(r)
+		}
+		if mapped {
+			return text.String()
+		}
+	}
+	var text strings.Builder
+	for _, b := range s {
+		if r, ok := cmap[string([]byte{b})]; ok && b < 0x20 {
+			text.WriteString(r)
+		} else if b >= 0x20 {
+			text.WriteRune(charmap.Windows1252.DecodeByte(b))
+		}
+	}
+	return text.String()
+}
@@ -0,0 +1,319 @@
+// Package resume imports CVs into a profile. An uploaded PDF or DOCX CV is read by the configured parser,
+// locally by its headings and dates or by the AI provider, into proposed experience, qualification and
+// skill records. Nothing is stored until the user confirms the records they want, after correcting any
+// the parser got wrong.
+package resume
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"profile-api/ai"
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/billing"
+	"profile-api/config"
+	"profile-api/dryrun"
+	"profile-api/experience"
+	"profile-api/features"
+	"profile-api/qualifications"
+	"profile-api/quota"
+	"profile-api/skills"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Parsers CVs are read with
+const (
+	ParserHeuristic = "heuristic"
+	ParserAI        = "ai"
+)
+
+const (
+	maxResumeSize = 10 << 20
+	// maxTextSize bounds the text read from a CV
+	maxTextSize = 1 << 20
+)
+
+// Parser reads the records of a CV from its text
+type Parser interface {
+	Parse(ctx context.Context, text string) (Proposal, error)
+}
+
+// Proposal holds the records read from a CV, for the user to confirm
+type Proposal struct {
+	Experience     []experience.Experience        `json:"experience" binding:"dive"`
+	Qualifications []qualifications.Qualification `json:"qualifications" binding:"dive"`
+	Skills         []skills.Skill                 `json:"skills" binding:"dive"`
+}
+
+// Repositories are where confirmed records are stored
+type Repositories struct {
+	Experience     experience.Repository
+	Qualifications qualifications.Repository
+	Skills         skills.Repository
+}
+
+var repos Repositories
+var settings = config.ResumeConfig{Parser: ParserHeuristic}
+
+// Configure sets the parser CVs are read with unless a request picks one
+func Configure(cfg config.ResumeConfig) {
+	settings = cfg
+}
+
+// ImportResume reads an uploaded CV and proposes the records in it
+//
+//	@Summary		Read a CV into proposed records
+//	@Description	Reads the experience, qualifications and skills of a PDF or DOCX CV, with the configured parser or the one named. Nothing is stored: the proposed records are returned for the user to correct and send to the confirm route. The ai parser is only available when an AI provider is configured, the ai-processing feature is enabled for the user and their plan includes AI features, and each CV it reads counts against the user's AI request quota. Users whose plan does not include AI features get the heuristic parser unless they name one. A dry run with the ai parser checks the CV without sending it to the AI provider and proposes no records.
+//	@Tags			profile
+//	@Security		BearerAuth
+//	@ID				import-resume
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			userid	path		string	true	"The ID of the user whose CV it is"
+//	@Param			file	formData	file	true	"CV as a PDF or DOCX file"
+//	@Param			parser	formData	string	false	"Parser to read the CV with, heuristic or ai, defaulting to the configured one"
+//	@Success		200		{object}	Proposal
+//	@Failure		400		{object}	apierror.Response	"CV not found, too large or not a PDF or DOCX file"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		402		{object}	apierror.Response	"The ai parser was named but the user's plan does not include AI features"
+//	@Failure		403		{object}	apierror.Response	"Not the owner"
+//	@Failure		422		{object}	apierror.Response	"No text could be read from the CV"
+//	@Failure		429		{object}	apierror.Response	"AI request quota used up"
+//	@Failure		500		{object}	apierror.Response	"Could not read CV"
+//	@Router			/profile/{userid}/import/resume [post]
+func ImportResume(c *gin.Context) {
+	userID := c.Param("userid")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("CV file not found"))
+		return
+	}
+	if fileHeader.Size > maxResumeSize {
+		apierror.Abort(c, apierror.TooLarge("CV file is too large"))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Could not open CV file"))
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Could not read CV file"))
+		return
+	}
+
+	user := c.MustGet("user").(auth.User)
+	name := c.PostForm("parser")
+	if name == "" {
+		name = settings.Parser
+		// The configured parser is only a default, so users whose plan has no AI fall back to reading locally
+		if name == ParserAI && billing.Allow(user, billing.AI) != nil {
+			name = ParserHeuristic
+		}
+	}
+	parser, err := parserFor(c.Request.Context(), name, userID, user)
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	text, err := extractText(fileHeader.Filename, data)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest(err.Error()))
+		return
+	}
+	if strings.TrimSpace(text) == "" {
+		apierror.Abort(c, apierror.Unprocessable("No text could be read from the CV, it may be a scanned image"))
+		return
+	}
+
+	if _, ok := parser.(AIParser); ok {
+		// A dry run must not send the CV to the provider or count against the quota
+		if dryrun.Active(c.Request.Context()) {
+			c.JSON(http.StatusOK, Proposal{}.normalized())
+			return
+		}
+		if err := ai.Use(c.Request.Context(), userID); err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not check AI request quota"))
+			return
+		}
+	}
+
+	proposal, err := parser.Parse(c.Request.Context(), text)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not read CV"))
+		return
+	}
+	c.JSON(http.StatusOK, proposal.normalized())
+}
+
+// ConfirmResume stores the records of a CV the user confirmed
+//
+//	@Summary		Store the records of a CV
+//	@Description	Stores the experience, qualifications and skills proposed by the import route that the user kept, as corrected by them. Every record is validated before any is stored, and none are when the user may not keep that many more of any kind.
+//	@Tags			profile
+//	@Security		BearerAuth
+//	@ID				confirm-resume
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string		true	"The ID of the user whose CV it is"
+//	@Param			request	body		Proposal	true	"Records to store"
+//	@Success		201		{object}	Proposal	"The stored records, with their IDs"
+//	@Failure		400		{object}	apierror.Response	"Invalid records"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		402		{object}	apierror.Response	"Document limit of the user's plan reached"
+//	@Failure		403		{object}	apierror.Response	"Not the owner"
+//	@Failure		413		{object}	apierror.Response	"Document limit of the site reached"
+//	@Failure		500		{object}	apierror.Response	"Could not store records"
+//	@Router			/profile/{userid}/import/resume/confirm [post]
+func ConfirmResume(c *gin.Context) {
+	userID := c.Param("userid")
+
+	var proposal Proposal
+	if err := c.ShouldBindJSON(&proposal); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	user := c.MustGet("user").(auth.User)
+	for _, records := range []struct {
+		collection string
+		n          int
+	}{
+		{"experience", len(proposal.Experience)},
+		{"qualifications", len(proposal.Qualifications)},
+		{"skills", len(proposal.Skills)},
+	} {
+		if records.n == 0 {
+			continue
+		}
+		left, err := quota.DocumentsLeft(ctx, user, records.collection)
+		if err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not check document quota"))
+			return
+		}
+		if left >= 0 && left < records.n {
+			apierror.Abort(c, quota.DocumentLimitError(user, records.collection))
+			return
+		}
+	}
+	for i := range proposal.Experience {
+		item := &proposal.Experience[i]
+		item.UserID = userID
+		item.ExperienceID = primitive.NewObjectID().Hex()
+		if err := repos.Experience.Create(ctx, *item); err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not insert experience"))
+			return
+		}
+	}
+	for i := range proposal.Qualifications {
+		item := &proposal.Qualifications[i]
+		item.UserID = userID
+		item.QualificationID = primitive.NewObjectID().Hex()
+		if err := repos.Qualifications.Create(ctx, *item); err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not insert qualification"))
+			return
+		}
+	}
+	for i := range proposal.Skills {
+		item := &proposal.Skills[i]
+		item.UserID = userID
+		item.SkillID = primitive.NewObjectID().Hex()
+		if err := repos.Skills.Create(ctx, *item); err != nil {
+			apierror.Abort(c, apierror.Wrap(err, "Could not insert skill"))
+			return
+		}
+	}
+	c.JSON(http.StatusCreated, proposal)
+}
+
+// parserFor returns the named parser, if it is available to the user
+func parserFor(ctx context.Context, name, userID string, user auth.User) (Parser, error) {
+	switch name {
+	case ParserHeuristic:
+		return HeuristicParser{}, nil
+	case ParserAI:
+		if !ai.Enabled() || !features.Enabled(ctx, features.AIProcessing, userID) {
+			return nil, apierror.BadRequest("The ai parser is not available")
+		}
+		if err := billing.Allow(user, billing.AI); err != nil {
+			return nil, err
+		}
+		return AIParser{}, nil
+	default:
+		return nil, apierror.BadRequest("Parser must be heuristic or ai")
+	}
+}
+
+// extractText returns the text of a PDF or DOCX file, told apart by their contents
+func extractText(filename string, data []byte) (string, error) {
+	var text string
+	var err error
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		text, err = pdfText(data)
+	case bytes.HasPrefix(data, []byte("PK")):
+		text, err = docxText(data)
+	default:
+		return "", errors.New("CV must be a PDF or DOCX file, not " + strings.TrimPrefix(filepath.Ext(filename), "."))
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(text) > maxTextSize {
+		text = text[:maxTextSize]
+	}
+	return strings.ToValidUTF8(text, string(utf8.RuneError)), nil
+}
+
+// normalized returns the proposal with empty lists rather than null, and its dates in the formats records
+// accept
+func (p Proposal) normalized() Proposal {
+	if p.Experience == nil {
+		p.Experience = []experience.Experience{}
+	}
+	if p.Qualifications == nil {
+		p.Qualifications = []qualifications.Qualification{}
+	}
+	if p.Skills == nil {
+		p.Skills = []skills.Skill{}
+	}
+	for i := range p.Experience {
+		p.Experience[i].Start = normalizeDate(p.Experience[i].Start)
+		p.Experience[i].End = normalizeDate(p.Experience[i].End)
+	}
+	for i := range p.Qualifications {
+		p.Qualifications[i].Start = normalizeDate(p.Qualifications[i].Start)
+		p.Qualifications[i].End = normalizeDate(p.Qualifications[i].End)
+	}
+	for i := range p.Skills {
+		p.Skills[i].StartedAt = normalizeDate(p.Skills[i].StartedAt)
+		p.Skills[i].LastUsed = normalizeDate(p.Skills[i].LastUsed)
+	}
+	return p
+}
+
+// InitializeRoutes registers the CV import routes on the profile group
+func InitializeRoutes(router *gin.RouterGroup, r Repositories, users auth.Repository) {
+	repos = r
+
+	protected := router.Group("/")
+	protected.Use(auth.AuthMiddleware(users, true))
+	protected.POST("/:userid/import/resume", dryrun.Supported(), auth.RequireOwner(), ImportResume)
+	protected.POST("/:userid/import/resume/confirm", dryrun.Supported(), auth.RequireOwner(), ConfirmResume)
+}
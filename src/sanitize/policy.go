@@ -0,0 +1,32 @@
+package sanitize
+
+import "golang.org/x/net/html/atom"
+
+// elements are those kept by the rich text policy, with the attributes allowed on each
+var elements = map[atom.Atom]map[string]bool{
+	atom.P: {}, atom.Br: {}, atom.Hr: {}, atom.Div: {}, atom.Span: {},
+	atom.B: {}, atom.Strong: {}, atom.I: {}, atom.Em: {}, atom.U: {}, atom.S: {}, atom.Del: {}, atom.Ins: {},
+	atom.Sub: {}, atom.Sup: {}, atom.Small: {}, atom.Mark: {}, atom.Abbr: {},
+	atom.Code: {}, atom.Pre: {}, atom.Kbd: {}, atom.Samp: {},
+	atom.H1: {}, atom.H2: {}, atom.H3: {}, atom.H4: {}, atom.H5: {}, atom.H6: {},
+	atom.Ul: {}, atom.Ol: {"start": true, "reversed": true}, atom.Li: {}, atom.Dl: {}, atom.Dt: {}, atom.Dd: {},
+	atom.Blockquote: {"cite": true}, atom.Q: {"cite": true}, atom.Cite: {},
+	atom.A: {"href": true}, atom.Img: {"src": true, "alt": true, "width": true, "height": true},
+	atom.Figure: {}, atom.Figcaption: {},
+	atom.Table: {}, atom.Caption: {}, atom.Thead: {}, atom.Tbody: {}, atom.Tfoot: {}, atom.Tr: {},
+	atom.Th: {"colspan": true, "rowspan": true, "scope": true}, atom.Td: {"colspan": true, "rowspan": true},
+}
+
+// globalAttributes are allowed on every kept element
+var globalAttributes = map[string]bool{"title": true, "lang": true, "dir": true}
+
+// urlAttributes hold URLs, which are dropped unless safe
+var urlAttributes = map[string]bool{"href": true, "src": true, "cite": true}
+
+// dropped are the elements removed along with their content, which is code or markup rather than text
+var dropped = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Frame: true, atom.Frameset: true,
+	atom.Object: true, atom.Embed: true, atom.Applet: true, atom.Noscript: true, atom.Template: true,
+	atom.Textarea: true, atom.Select: true, atom.Svg: true, atom.Math: true, atom.Head: true,
+	atom.Title: true, atom.Meta: true, atom.Link: true, atom.Base: true,
+}
@@ -0,0 +1,185 @@
+// Package sanitize cleans user-supplied text before it is stored, so a frontend inserting bios,
+// descriptions or journal content as HTML cannot be made to run a script.
+//
+// Each field is cleaned with the policy configured for it: rich-text keeps formatting markup such as
+// paragraphs, lists and links while removing scripts, event handlers and unsafe URLs; text removes all
+// markup, keeping only the text; none stores the field as written. Both policies are idempotent, so
+// saving a field again leaves it as it is.
+package sanitize
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Policies a field can be cleaned with
+const (
+	None     = "none"
+	Text     = "text"
+	RichText = "rich-text"
+)
+
+// Fields holding user-supplied text, named by module and JSON field
+const (
+	ProfileBio               = "profile.bio"
+	ProfileInterests         = "profile.interests"
+	ExperienceDescription    = "experience.description"
+	ExperienceNotes          = "experience.notes"
+	QualificationDescription = "qualifications.description"
+	CertificateDescription   = "certificates.description"
+	SkillDescription         = "skills.description"
+	JournalTitle             = "journal.title"
+	JournalContent           = "journal.content"
+	JournalSummary           = "journal.summary"
+	OrganizationDescription  = "organizations.description"
+	RecommendationContent    = "recommendations.content"
+	AwardDescription         = "awards.description"
+	ContactMessage           = "inbox.message"
+)
+
+var policies = map[string]string{}
+
+// Configure sets the policy of each field. Fields missing from the config are cleaned as rich text.
+func Configure(cfg map[string]string) {
+	policies = cfg
+}
+
+// Field cleans the value of the field with the field's policy
+func Field(field, value string) string {
+	policy, ok := policies[field]
+	if !ok {
+		policy = RichText
+	}
+	switch policy {
+	case None:
+		return value
+	case Text:
+		return StripTags(value)
+	default:
+		return HTML(value)
+	}
+}
+
+// HTML removes everything from the markup but the elements and attributes of the rich text policy. Elements
+// that are not allowed are replaced by their content, except those such as scripts whose content is not
+// text, which are removed along with it.
+func HTML(value string) string {
+	if !strings.ContainsAny(value, "<&") {
+		return value
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(value), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return StripTags(value)
+	}
+	var b strings.Builder
+	for _, n := range nodes {
+		for _, clean := range cleanNode(n) {
+			if err := html.Render(&b, clean); err != nil {
+				return StripTags(value)
+			}
+		}
+	}
+	return b.String()
+}
+
+// StripTags removes all markup, keeping the text. Only < is escaped, so the text is safe to insert as
+// HTML while otherwise reading as it was written.
+func StripTags(value string) string {
+	if !strings.ContainsAny(value, "<&") {
+		return value
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(value), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return strings.ReplaceAll(value, "<", "&lt;")
+	}
+	var b strings.Builder
+	for _, n := range nodes {
+		writeText(&b, n)
+	}
+	return strings.ReplaceAll(b.String(), "<", "&lt;")
+}
+
+// writeText writes the text of the node and its descendants, leaving out the content of dropped elements
+func writeText(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(n.Data)
+		return
+	case html.ElementNode:
+		if dropped[n.DataAtom] {
+			return
+		}
+		if n.DataAtom == atom.Br {
+			b.WriteString("\n")
+		}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		writeText(b, child)
+	}
+}
+
+// cleanNode returns the nodes to keep in place of the node, detached from its tree
+func cleanNode(n *html.Node) []*html.Node {
+	switch n.Type {
+	case html.TextNode:
+		return []*html.Node{{Type: html.TextNode, Data: n.Data}}
+	case html.ElementNode:
+	default:
+		return nil
+	}
+	if dropped[n.DataAtom] {
+		return nil
+	}
+
+	var children []*html.Node
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		children = append(children, cleanNode(child)...)
+	}
+	attrs, ok := elements[n.DataAtom]
+	if !ok || n.Namespace != "" {
+		return children
+	}
+
+	clean := &html.Node{Type: html.ElementNode, Data: n.Data, DataAtom: n.DataAtom}
+	for _, a := range n.Attr {
+		if a.Namespace != "" || !(attrs[a.Key] || globalAttributes[a.Key]) {
+			continue
+		}
+		if urlAttributes[a.Key] && !safeURL(a.Val) {
+			continue
+		}
+		clean.Attr = append(clean.Attr, html.Attribute{Key: a.Key, Val: a.Val})
+	}
+	if n.DataAtom == atom.A {
+		// Links to other sites must not pass on the page's ranking, nor get a handle on the page
+		clean.Attr = append(clean.Attr, html.Attribute{Key: "rel", Val: "nofollow noopener noreferrer"})
+	}
+	if n.DataAtom == atom.Img && !hasAttr(clean, "src") {
+		return nil
+	}
+	for _, child := range children {
+		clean.AppendChild(child)
+	}
+	return []*html.Node{clean}
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// safeURL accepts http, https and mailto URLs, and relative ones
+func safeURL(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	scheme, _, found := strings.Cut(value, ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+	return scheme == "http" || scheme == "https" || scheme == "mailto"
+}
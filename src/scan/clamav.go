@@ -0,0 +1,67 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamAVChunkSize is the size of the chunks files are streamed to clamd in
+const clamAVChunkSize = 64 << 10
+
+// ClamAVScanner streams files to a ClamAV daemon with its INSTREAM command
+type ClamAVScanner struct {
+	// Address is the host:port of clamd's TCP socket, or the path of its Unix socket
+	Address string
+	Timeout time.Duration
+}
+
+func (s *ClamAVScanner) Scan(ctx context.Context, data []byte) (Result, error) {
+	network := "tcp"
+	if strings.HasPrefix(s.Address, "/") {
+		network = "unix"
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+	conn, err := (&net.Dialer{}).DialContext(ctx, network, s.Address)
+	if err != nil {
+		return Result{}, fmt.Errorf("could not connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	w := bufio.NewWriter(conn)
+	w.WriteString("zINSTREAM\x00")
+	for len(data) > 0 {
+		n := min(len(data), clamAVChunkSize)
+		binary.Write(w, binary.BigEndian, uint32(n))
+		w.Write(data[:n])
+		data = data[n:]
+	}
+	// A zero-length chunk ends the stream
+	binary.Write(w, binary.BigEndian, uint32(0))
+	if err := w.Flush(); err != nil {
+		return Result{}, fmt.Errorf("could not send file to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return Result{}, fmt.Errorf("could not read clamd reply: %w", err)
+	}
+	// Replies are "stream: OK", "stream: <threat> FOUND" or "<message> ERROR"
+	reply = strings.TrimPrefix(strings.TrimSuffix(reply, "\x00"), "stream: ")
+	switch {
+	case reply == "OK":
+		return Result{}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return Result{Infected: true, Threat: strings.TrimSuffix(reply, " FOUND")}, nil
+	default:
+		return Result{}, fmt.Errorf("clamd: %s", reply)
+	}
+}
@@ -0,0 +1,163 @@
+// Package scan checks uploaded files for malware in the background, with a ClamAV daemon or VirusTotal.
+//
+// Modules queue a scan of each upload once it is stored, and register a Target loading and quarantining
+// their kind of upload. Infected files are moved out of reach, the record referencing them is flagged and
+// the owner is notified by email. Scans that fail, such as while the scanner is unreachable, are retried
+// by the job queue.
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/email"
+	"profile-api/jobs"
+	"profile-api/store"
+)
+
+// Job is the type of the background job scanning an upload
+const Job = "scan.upload"
+
+const quarantinedEmail = "upload_quarantined"
+
+// ErrPending is returned by scanners that have not finished analysing a file, which is scanned again later
+var ErrPending = errors.New("scan pending")
+
+// Result is the verdict of a scan
+type Result struct {
+	Infected bool
+	// Threat names the malware found in an infected file
+	Threat string
+}
+
+// Scanner checks a file for malware
+type Scanner interface {
+	Scan(ctx context.Context, data []byte) (Result, error)
+}
+
+// Upload identifies an uploaded file to scan
+type Upload struct {
+	// Kind selects the target loading the file, such as profile-image
+	Kind   string `json:"kind"`
+	UserID string `json:"userID"`
+	// ItemID identifies the record holding the file, for kinds that are not the user's own
+	ItemID string `json:"itemID,omitempty"`
+	// URL locates files kept in the image store
+	URL string `json:"url,omitempty"`
+}
+
+// Target loads and quarantines one kind of upload
+type Target interface {
+	// Load returns the uploaded file, or store.ErrNotFound when it has since been replaced or removed
+	Load(ctx context.Context, upload Upload) ([]byte, error)
+	// Quarantine moves the infected file out of reach and flags the record referencing it
+	Quarantine(ctx context.Context, upload Upload, threat string) error
+}
+
+var (
+	scanner Scanner
+	users   auth.Repository
+	targets = map[string]Target{}
+)
+
+// Configure sets the scanner uploads are checked with, leaving them unscanned when none is configured, and
+// where the owners of infected uploads are looked up to notify them
+func Configure(cfg config.ScanConfig, u auth.Repository) {
+	users = u
+	switch cfg.Scanner {
+	case "clamav":
+		scanner = &ClamAVScanner{Address: cfg.ClamAV.Address, Timeout: cfg.Timeout.Std()}
+	case "virustotal":
+		scanner = NewVirusTotalScanner(cfg.VirusTotal, cfg.Timeout.Std())
+	default:
+		scanner = nil
+	}
+}
+
+// RegisterTarget sets the target loading and quarantining uploads of the kind
+func RegisterTarget(kind string, t Target) {
+	targets[kind] = t
+}
+
+// RegisterJobs registers the handler scanning queued uploads
+func RegisterJobs() {
+	jobs.Register(Job, func(ctx context.Context, job jobs.Job) error {
+		var upload Upload
+		if err := json.Unmarshal(job.Payload, &upload); err != nil {
+			return err
+		}
+		return scan(ctx, upload)
+	})
+}
+
+// Enqueue queues a scan of the stored upload. Nothing is queued when no scanner is configured.
+func Enqueue(ctx context.Context, upload Upload) error {
+	if scanner == nil {
+		return nil
+	}
+	return jobs.Enqueue(ctx, Job, upload)
+}
+
+// scan checks the upload, quarantining it and notifying its owner when it is infected
+func scan(ctx context.Context, upload Upload) error {
+	target, ok := targets[upload.Kind]
+	if !ok {
+		return fmt.Errorf("unknown upload kind %q", upload.Kind)
+	}
+	if scanner == nil {
+		return nil
+	}
+	data, err := target.Load(ctx, upload)
+	if errors.Is(err, store.ErrNotFound) {
+		// Replaced or removed before it was scanned, any new upload is scanned in turn
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	result, err := scanner.Scan(ctx, data)
+	if err != nil {
+		return err
+	}
+	if !result.Infected {
+		return nil
+	}
+
+	slog.WarnContext(ctx, "Quarantining infected upload", "kind", upload.Kind, "user_id", upload.UserID, "item_id", upload.ItemID, "threat", result.Threat)
+	if err := target.Quarantine(ctx, upload, result.Threat); err != nil {
+		return fmt.Errorf("could not quarantine upload: %w", err)
+	}
+	return notify(ctx, upload, result.Threat)
+}
+
+type quarantinedData struct {
+	Name   string
+	Kind   string
+	Threat string
+}
+
+// notify emails the owner of the infected upload
+func notify(ctx context.Context, upload Upload, threat string) error {
+	user, err := users.FindByID(ctx, upload.UserID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Kinds read as what was uploaded, such as "profile image"
+	kind := strings.ReplaceAll(upload.Kind, "-", " ")
+	msg, err := email.Render(quarantinedEmail, quarantinedData{Name: user.Name, Kind: kind, Threat: threat})
+	if err != nil {
+		return err
+	}
+	msg.To = user.Email
+	msg.UserID = user.ID
+	return email.Enqueue(ctx, msg)
+}
@@ -0,0 +1,150 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"profile-api/config"
+)
+
+// VirusTotalScanner looks files up on VirusTotal by their hash, uploading files it has not seen when
+// allowed to. Files still being analysed are reported with ErrPending.
+type VirusTotalScanner struct {
+	apiKey  string
+	baseURL string
+	upload  bool
+	client  *http.Client
+}
+
+// NewVirusTotalScanner creates a scanner using the configured VirusTotal API key
+func NewVirusTotalScanner(cfg config.VirusTotalConfig, timeout time.Duration) *VirusTotalScanner {
+	return &VirusTotalScanner{
+		apiKey:  cfg.APIKey,
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		upload:  cfg.Upload,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type virusTotalReport struct {
+	Data struct {
+		Attributes struct {
+			LastAnalysisResults map[string]struct {
+				Category string `json:"category"`
+				Result   string `json:"result"`
+			} `json:"last_analysis_results"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+func (s *VirusTotalScanner) Scan(ctx context.Context, data []byte) (Result, error) {
+	sum := sha256.Sum256(data)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/files/"+hex.EncodeToString(sum[:]), nil)
+	if err != nil {
+		return Result{}, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound && s.upload:
+		if err := s.submit(ctx, data); err != nil {
+			return Result{}, err
+		}
+		return Result{}, ErrPending
+	case resp.StatusCode == http.StatusNotFound:
+		// Files VirusTotal has never seen are not known to be malicious
+		return Result{}, nil
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return Result{}, fmt.Errorf("virustotal returned %s: %s", resp.Status, body)
+	}
+
+	var report virusTotalReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return Result{}, fmt.Errorf("could not decode virustotal report: %w", err)
+	}
+	results := report.Data.Attributes.LastAnalysisResults
+	if len(results) == 0 {
+		return Result{}, ErrPending
+	}
+	var threats []string
+	for _, r := range results {
+		if r.Category == "malicious" && r.Result != "" {
+			threats = append(threats, r.Result)
+		}
+	}
+	if len(threats) == 0 {
+		return Result{}, nil
+	}
+	// Engines name the same malware differently, the most common name is reported
+	return Result{Infected: true, Threat: mostCommon(threats)}, nil
+}
+
+// submit uploads the file for VirusTotal to analyse
+func (s *VirusTotalScanner) submit(ctx context.Context, data []byte) error {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", "upload")
+	if err != nil {
+		return err
+	}
+	part.Write(data)
+	if err := w.Close(); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/files", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("virustotal returned %s: %s", resp.Status, body)
+	}
+	return nil
+}
+
+func (s *VirusTotalScanner) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("x-apikey", s.apiKey)
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("virustotal request failed: %w", err)
+	}
+	return resp, nil
+}
+
+func mostCommon(names []string) string {
+	sort.Strings(names)
+	best, bestCount, count := names[0], 0, 0
+	for i, name := range names {
+		if i > 0 && name == names[i-1] {
+			count++
+		} else {
+			count = 1
+		}
+		if count > bestCount {
+			best, bestCount = name, count
+		}
+	}
+	return best
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Locker grants named leases, each held by a single owner at a time
+type Locker interface {
+	// Acquire takes or renews the lease on name for ttl, reporting whether owner now holds it.
+	// It fails to acquire a lease held by another owner that has not expired.
+	Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	// Release gives up the lease if owner holds it
+	Release(ctx context.Context, name, owner string) error
+}
+
+type lease struct {
+	owner   string
+	expires time.Time
+}
+
+// MemoryLocker keeps leases in memory, for a single replica using in-memory storage
+type MemoryLocker struct {
+	mu     sync.Mutex
+	leases map[string]lease
+}
+
+// NewMemoryLocker creates a locker with no leases held
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{leases: map[string]lease{}}
+}
+
+func (l *MemoryLocker) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if current, ok := l.leases[name]; ok && current.owner != owner && current.expires.After(now) {
+		return false, nil
+	}
+	l.leases[name] = lease{owner: owner, expires: now.Add(ttl)}
+	return true, nil
+}
+
+func (l *MemoryLocker) Release(ctx context.Context, name, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if current, ok := l.leases[name]; ok && current.owner == owner {
+		delete(l.leases, name)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoLocker keeps leases in the locks collection
+type MongoLocker struct {
+	locks *mongo.Collection
+}
+
+// NewMongoLocker creates a locker backed by the given database
+func NewMongoLocker(db *mongo.Database) *MongoLocker {
+	return &MongoLocker{locks: db.Collection("locks")}
+}
+
+func (l *MongoLocker) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	// The filter only matches a lease this owner holds or one that expired. When another owner holds it the
+	// upsert tries to insert a second document with the same _id, which fails as a duplicate key.
+	_, err := l.locks.UpdateOne(
+		ctx,
+		bson.M{"_id": name, "$or": bson.A{bson.M{"owner": owner}, bson.M{"expires_at": bson.M{"$lt": now}}}},
+		bson.M{"$set": bson.M{"owner": owner, "expires_at": now.Add(ttl)}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (l *MongoLocker) Release(ctx context.Context, name, owner string) error {
+	_, err := l.locks.DeleteOne(ctx, bson.M{"_id": name, "owner": owner})
+	return err
+}
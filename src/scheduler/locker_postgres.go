@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresLocker keeps leases in the locks table
+type PostgresLocker struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresLocker creates a locker backed by the given connection pool
+func NewPostgresLocker(pool *pgxpool.Pool) *PostgresLocker {
+	return &PostgresLocker{pool: pool}
+}
+
+func (l *PostgresLocker) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	// The conditional update leaves a lease held by another owner untouched, returning no row
+	var holder string
+	err := l.pool.QueryRow(ctx, `INSERT INTO locks (name, owner, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET owner = EXCLUDED.owner, expires_at = EXCLUDED.expires_at
+		WHERE locks.owner = EXCLUDED.owner OR locks.expires_at < $4
+		RETURNING owner`,
+		name, owner, now.Add(ttl), now).Scan(&holder)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (l *PostgresLocker) Release(ctx context.Context, name, owner string) error {
+	_, err := l.pool.Exec(ctx, "DELETE FROM locks WHERE name = $1 AND owner = $2", name, owner)
+	return err
+}
@@ -0,0 +1,123 @@
+// Package scheduler runs periodic tasks on cron schedules. When several replicas share a database only the one
+// holding the leader lease runs tasks, so each run happens once.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"profile-api/config"
+	"profile-api/utils"
+
+	"github.com/robfig/cron/v3"
+)
+
+// leaderLock is the name of the lease held by the replica running tasks
+const leaderLock = "scheduler-leader"
+
+// Task is the work done on each run of a periodic task
+type Task func(ctx context.Context) error
+
+type task struct {
+	name     string
+	schedule string
+	run      Task
+}
+
+var (
+	tasks  []task
+	leader atomic.Bool
+)
+
+// Register adds a periodic task run on the default schedule unless the config overrides it.
+// Tasks must be registered before Start.
+func Register(name, defaultSchedule string, run Task) {
+	tasks = append(tasks, task{name: name, schedule: defaultSchedule, run: run})
+}
+
+// Start schedules every enabled task and campaigns for the leader lease until the context is cancelled.
+// It returns an error when a configured schedule is invalid.
+func Start(ctx context.Context, locker Locker, cfg config.SchedulerConfig) error {
+	known := map[string]bool{}
+	c := cron.New(cron.WithChain(cron.Recover(cron.DefaultLogger), cron.SkipIfStillRunning(cron.DefaultLogger)))
+	for _, t := range tasks {
+		known[t.name] = true
+		taskCfg := cfg.Tasks[t.name]
+		if taskCfg.Enabled != nil && !*taskCfg.Enabled {
+			slog.Info("Scheduled task disabled", "task", t.name)
+			continue
+		}
+		schedule := t.schedule
+		if taskCfg.Schedule != "" {
+			schedule = taskCfg.Schedule
+		}
+		if _, err := c.AddFunc(schedule, runner(ctx, t)); err != nil {
+			return fmt.Errorf("invalid schedule %q for task %s: %w", schedule, t.name, err)
+		}
+		slog.Info("Scheduled task", "task", t.name, "schedule", schedule)
+	}
+	for name := range cfg.Tasks {
+		if !known[name] {
+			slog.Warn("Ignoring config for unknown scheduled task", "task", name)
+		}
+	}
+
+	go campaign(ctx, locker, cfg.LeaderLease.Std())
+	c.Start()
+	go func() {
+		<-ctx.Done()
+		c.Stop()
+	}()
+	return nil
+}
+
+// runner returns the cron job running the task, skipped on replicas that are not the leader
+func runner(ctx context.Context, t task) func() {
+	return func() {
+		if !leader.Load() || ctx.Err() != nil {
+			return
+		}
+		start := time.Now()
+		if err := t.run(ctx); err != nil {
+			slog.Error("Scheduled task failed", "task", t.name, "error", err, "duration", time.Since(start))
+			return
+		}
+		slog.Debug("Scheduled task finished", "task", t.name, "duration", time.Since(start))
+	}
+}
+
+// campaign acquires and renews the leader lease until the context is cancelled, then releases it so
+// another replica can take over without waiting for the lease to expire
+func campaign(ctx context.Context, locker Locker, lease time.Duration) {
+	owner := utils.GenerateID()
+	for {
+		opCtx, cancel := utils.WithOperationTimeout(ctx)
+		acquired, err := locker.Acquire(opCtx, leaderLock, owner, lease)
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			slog.Error("Error acquiring scheduler leader lease", "error", err)
+		}
+		if leader.Swap(acquired) != acquired {
+			if acquired {
+				slog.Info("Became scheduler leader")
+			} else {
+				slog.Info("No longer scheduler leader")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			leader.Store(false)
+			opCtx, cancel := utils.WithOperationTimeout(context.WithoutCancel(ctx))
+			if err := locker.Release(opCtx, leaderLock, owner); err != nil {
+				slog.Error("Error releasing scheduler leader lease", "error", err)
+			}
+			cancel()
+			return
+		case <-time.After(lease / 3):
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package scraping
+
+import "time"
+
+// Patterns of scraping detected
+const (
+	// PatternBurst is too many requests to the public journal from one address within the burst window
+	PatternBurst = "burst"
+	// PatternWalk is a run of journal entries looked up in the order of their IDs, as a client enumerating
+	// them does
+	PatternWalk = "walk"
+)
+
+// Actions taken on the requests of an address detected scraping
+const (
+	ActionReport = "report"
+	ActionSlow   = "slow"
+	ActionBlock  = "block"
+)
+
+// Detection records an address detected scraping the public journal
+type Detection struct {
+	ID string `bson:"_id" json:"id"`
+	// Client identifies the address without revealing it, see clientKey
+	Client  string `bson:"client" json:"client"`
+	Pattern string `bson:"pattern" json:"pattern"`
+	// Route is the route of the request that tripped the detection
+	Route string `bson:"route" json:"route"`
+	// Requests is how many requests made up the burst or the walk
+	Requests  int    `bson:"requests" json:"requests"`
+	UserAgent string `bson:"user_agent" json:"userAgent"`
+	// Action is what was done with the address's requests until ThrottledUntil
+	Action         string    `bson:"action" json:"action"`
+	DetectedAt     time.Time `bson:"detected_at" json:"detectedAt"`
+	ThrottledUntil time.Time `bson:"throttled_until" json:"throttledUntil"`
+}
+
+// ClientReport sums up the detections of one address
+type ClientReport struct {
+	Client     string `json:"client"`
+	Detections int    `json:"detections"`
+	// Patterns are the patterns the address was detected by
+	Patterns       []string  `json:"patterns"`
+	LastUserAgent  string    `json:"lastUserAgent"`
+	LastDetectedAt time.Time `json:"lastDetectedAt"`
+	ThrottledUntil time.Time `json:"throttledUntil"`
+}
+
+// Report is the scraping detected in the last days, for admins
+type Report struct {
+	Days int `json:"days"`
+	// Action is what is currently done with the requests of addresses detected scraping
+	Action string `json:"action"`
+	// Clients holds the addresses detected, most recently detected first
+	Clients []ClientReport `json:"clients"`
+	// Detections holds the latest detections, newest first
+	Detections []Detection `json:"detections"`
+}
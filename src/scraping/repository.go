@@ -0,0 +1,16 @@
+package scraping
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores the detections of scraping
+type Repository interface {
+	// Create stores a detection
+	Create(ctx context.Context, d Detection) error
+	// List returns the detections made since the time, newest first, at most limit of them
+	List(ctx context.Context, since time.Time, limit int) ([]Detection, error)
+	// Purge removes the detections made before the time, returning how many were removed
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,50 @@
+package scraping
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryRepository keeps detections in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu         sync.Mutex
+	detections []Detection
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, d Detection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.detections = append(r.detections, d)
+	return nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, since time.Time, limit int) ([]Detection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []Detection
+	for _, d := range r.detections {
+		if !d.DetectedAt.Before(since) {
+			found = append(found, d)
+		}
+	}
+	slices.SortFunc(found, func(a, b Detection) int { return b.DetectedAt.Compare(a.DetectedAt) })
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found, nil
+}
+
+func (r *MemoryRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(r.detections)
+	r.detections = slices.DeleteFunc(r.detections, func(d Detection) bool { return d.DetectedAt.Before(before) })
+	return int64(n - len(r.detections)), nil
+}
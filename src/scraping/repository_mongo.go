@@ -0,0 +1,44 @@
+package scraping
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores detections in the scraping_detections collection
+type MongoRepository struct {
+	detections *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{detections: db.Collection("scraping_detections")}
+}
+
+func (r *MongoRepository) Create(ctx context.Context, d Detection) error {
+	_, err := r.detections.InsertOne(ctx, d)
+	return err
+}
+
+func (r *MongoRepository) List(ctx context.Context, since time.Time, limit int) ([]Detection, error) {
+	cursor, err := r.detections.Find(ctx, bson.M{"detected_at": bson.M{"$gte": since}},
+		options.Find().SetSort(bson.D{{Key: "detected_at", Value: -1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	var found []Detection
+	err = cursor.All(ctx, &found)
+	return found, err
+}
+
+func (r *MongoRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.detections.DeleteMany(ctx, bson.M{"detected_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
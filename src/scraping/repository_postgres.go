@@ -0,0 +1,47 @@
+package scraping
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const detectionColumns = "id, client, pattern, route, requests, user_agent, action, detected_at, throttled_until"
+
+// PostgresRepository stores detections in the scraping_detections table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, d Detection) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO scraping_detections ("+detectionColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		d.ID, d.Client, d.Pattern, d.Route, d.Requests, d.UserAgent, d.Action, d.DetectedAt, d.ThrottledUntil)
+	return err
+}
+
+func (r *PostgresRepository) List(ctx context.Context, since time.Time, limit int) ([]Detection, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+detectionColumns+" FROM scraping_detections WHERE detected_at >= $1 ORDER BY detected_at DESC LIMIT $2", since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Detection, error) {
+		var d Detection
+		err := row.Scan(&d.ID, &d.Client, &d.Pattern, &d.Route, &d.Requests, &d.UserAgent, &d.Action, &d.DetectedAt, &d.ThrottledUntil)
+		return d, err
+	})
+}
+
+func (r *PostgresRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM scraping_detections WHERE detected_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
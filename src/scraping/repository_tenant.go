@@ -0,0 +1,30 @@
+package scraping
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, d Detection) error {
+	return r.repos.For(ctx).Create(ctx, d)
+}
+
+func (r *TenantRepository) List(ctx context.Context, since time.Time, limit int) ([]Detection, error) {
+	return r.repos.For(ctx).List(ctx, since, limit)
+}
+
+func (r *TenantRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return r.repos.For(ctx).Purge(ctx, before)
+}
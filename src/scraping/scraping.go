@@ -0,0 +1,304 @@
+// Package scraping detects clients scraping the public journal, apart from any limit on requests in general:
+// an address making a burst of requests to the feed and its entries, or looking entries up in the order of
+// their IDs, which are random, as a client enumerating them does. An address detected is recorded for the
+// admin report, and its requests are slowed down or refused as configured until the penalty has passed.
+//
+// Addresses are tracked in memory on each replica, so a scraper spreading its requests over several replicas
+// is detected later. They are identified by a MAC keyed with the server's secret and never stored as they
+// are, as a plain hash of an address is undone by hashing every address there is.
+package scraping
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/logging"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultDays  = 7
+	defaultLimit = 100
+	maxLimit     = 500
+	// maxReported bounds the detections the clients of the admin report are summed up from
+	maxReported = 5000
+	// maxUserAgent bounds the user agent recorded with a detection
+	maxUserAgent = 500
+	// sweepInterval is how often addresses no longer tracked are forgotten
+	sweepInterval = time.Minute
+)
+
+var repo Repository
+var settings config.ScrapingConfig
+
+// client tracks the recent requests of an address
+type client struct {
+	// requests holds when the latest requests were made, within the burst window
+	requests []time.Time
+	// last is the ID of the entry looked up last
+	last string
+	// direction is the order of the IDs in the walk: 1 ascending, -1 descending, 0 before the walk has one
+	direction int
+	// walk holds when the entries of the walk were looked up, within the walk window
+	walk []time.Time
+	// throttledUntil is when the penalty of the address's last detection ends
+	throttledUntil time.Time
+	seen           time.Time
+}
+
+var (
+	mu sync.Mutex
+	// clients holds the addresses tracked, by tenant and client key
+	clients = map[string]*client{}
+	swept   time.Time
+)
+
+// Configure sets where detections are stored and how scraping is detected and throttled. Until it is called
+// nothing is detected. The addresses tracked so far are forgotten, as they were counted by other windows.
+func Configure(r Repository, cfg config.ScrapingConfig) {
+	mu.Lock()
+	defer mu.Unlock()
+	repo = r
+	settings = cfg
+	clients = map[string]*client{}
+	swept = time.Time{}
+}
+
+// clientKey identifies the address of the request without revealing it
+func clientKey(c *gin.Context) string {
+	return auth.Sign("scraping-client", c.ClientIP())[:16]
+}
+
+// Watch tracks the requests of each address to a public journal route, detecting scraping and throttling the
+// addresses detected. param names the route parameter holding the ID of the entry looked up, empty for routes
+// listing entries.
+func Watch(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !settings.Enabled || repo == nil {
+			c.Next()
+			return
+		}
+		key := clientKey(c)
+		var id string
+		if param != "" {
+			id = c.Param(param)
+		}
+		d, throttled := observe(tenant.ID(c.Request.Context())+"/"+key, id, time.Now())
+		if d.Pattern != "" {
+			d.Client = key
+			d.Route = c.FullPath()
+			record(c, d)
+		}
+		if throttled {
+			switch settings.Action {
+			case ActionSlow:
+				select {
+				case <-time.After(settings.Delay.Std()):
+				case <-c.Request.Context().Done():
+					c.Abort()
+					return
+				}
+			case ActionBlock:
+				c.Header("Retry-After", strconv.Itoa(int(max(time.Until(d.ThrottledUntil), time.Second).Seconds())))
+				apierror.Abort(c, apierror.TooManyRequests("Too many requests to the journal, try again later"))
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// observe counts a request of the address, looking up the entry with the ID unless it is empty. It returns the
+// detection the request trips, with no pattern when it trips none, and whether the address is throttled.
+func observe(key, id string, now time.Time) (Detection, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if now.Sub(swept) >= sweepInterval {
+		sweep(now)
+	}
+	cl, ok := clients[key]
+	if !ok {
+		cl = &client{}
+		clients[key] = cl
+	}
+	cl.seen = now
+
+	// Keeping one more request than a burst allows is enough to tell one
+	cl.requests = append(within(cl.requests, now, settings.BurstWindow.Std()), now)
+	if len(cl.requests) > settings.BurstRequests+1 {
+		cl.requests = cl.requests[1:]
+	}
+	if id != "" && id != cl.last {
+		direction := 1
+		if id < cl.last {
+			direction = -1
+		}
+		if cl.last == "" || (cl.direction != 0 && direction != cl.direction) {
+			// The walk starts again from the previous entry, or from this one when there is none
+			cl.walk = cl.walk[:0]
+			if cl.last != "" {
+				cl.walk = append(cl.walk, now)
+				cl.direction = direction
+			} else {
+				cl.direction = 0
+			}
+		} else {
+			cl.direction = direction
+		}
+		cl.walk = append(within(cl.walk, now, settings.WalkWindow.Std()), now)
+		if len(cl.walk) > settings.WalkLength {
+			cl.walk = cl.walk[1:]
+		}
+		cl.last = id
+	}
+
+	throttled := now.Before(cl.throttledUntil)
+	if throttled {
+		return Detection{ThrottledUntil: cl.throttledUntil}, true
+	}
+	var d Detection
+	switch {
+	case len(cl.requests) > settings.BurstRequests:
+		d = Detection{Pattern: PatternBurst, Requests: len(cl.requests)}
+	case len(cl.walk) >= settings.WalkLength:
+		d = Detection{Pattern: PatternWalk, Requests: len(cl.walk)}
+	default:
+		return d, false
+	}
+	// The address has to start a new burst or walk to be detected again once its penalty has passed
+	cl.requests, cl.walk, cl.direction = nil, nil, 0
+	cl.throttledUntil = now.Add(settings.Penalty.Std())
+	d.Action, d.DetectedAt, d.ThrottledUntil = settings.Action, now, cl.throttledUntil
+	return d, true
+}
+
+// within drops the times before the window ending now
+func within(times []time.Time, now time.Time, window time.Duration) []time.Time {
+	i := 0
+	for i < len(times) && now.Sub(times[i]) >= window {
+		i++
+	}
+	return times[i:]
+}
+
+// sweep forgets the addresses with no request left in either window and no penalty running
+func sweep(now time.Time) {
+	idle := max(settings.BurstWindow.Std(), settings.WalkWindow.Std())
+	for key, cl := range clients {
+		if now.Sub(cl.seen) >= idle && !now.Before(cl.throttledUntil) {
+			delete(clients, key)
+		}
+	}
+	swept = now
+}
+
+// record stores a detection for the admin report
+func record(c *gin.Context, d Detection) {
+	d.ID = utils.GenerateID()
+	d.UserAgent = c.Request.UserAgent()
+	if len(d.UserAgent) > maxUserAgent {
+		d.UserAgent = d.UserAgent[:maxUserAgent]
+	}
+	logging.Logger(c).Warn("Detected scraping of the journal", "client", d.Client, "pattern", d.Pattern, "requests", d.Requests, "action", d.Action)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Create(ctx, d); err != nil {
+		logging.Logger(c).Error("Could not record scraping detection", "client", d.Client, "error", err)
+	}
+}
+
+// Purge removes the detections older than the retention
+func Purge(ctx context.Context) error {
+	removed, err := repo.Purge(ctx, time.Now().Add(-settings.Retention.Std()))
+	if err != nil {
+		return err
+	}
+	slog.Info("Purged scraping detections", "removed", removed)
+	return nil
+}
+
+// maxDays is the most days detections are kept for
+func maxDays() int {
+	return max(1, int(settings.Retention.Std()/(24*time.Hour)))
+}
+
+// GetReport returns the scraping of the journal detected lately
+//
+//	@Summary		Get the journal scraping report
+//	@Description	Returns the addresses detected scraping the public journal in the last days, by a burst of requests or by looking entries up in the order of their IDs, most recently detected first, with the latest detections. Addresses are identified by a hash. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			days	query		int	false	"Number of days to report (default 7, at most the retention)"
+//	@Param			limit	query		int	false	"Maximum number of detections to return (default 100, max 500)"
+//	@Success		200		{object}	Report
+//	@Failure		400		{object}	apierror.Response	"Invalid number of days"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve scraping report"
+//	@Router			/admin/scraping [get]
+func GetReport(c *gin.Context) {
+	days := min(defaultDays, maxDays())
+	if d := c.Query("days"); d != "" {
+		var err error
+		days, err = strconv.Atoi(d)
+		if err != nil || days < 1 || days > maxDays() {
+			apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("days must be between 1 and %d", maxDays())))
+			return
+		}
+	}
+	limit := defaultLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, maxLimit)
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	detections, err := repo.List(ctx, time.Now().AddDate(0, 0, -days), maxReported)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve scraping report"))
+		return
+	}
+
+	report := Report{Days: days, Action: settings.Action, Clients: []ClientReport{}, Detections: []Detection{}}
+	byClient := map[string]*ClientReport{}
+	for _, d := range detections {
+		cr, ok := byClient[d.Client]
+		if !ok {
+			// Detections are newest first, so the first of each address is its latest
+			cr = &ClientReport{Client: d.Client, LastUserAgent: d.UserAgent, LastDetectedAt: d.DetectedAt, ThrottledUntil: d.ThrottledUntil}
+			byClient[d.Client] = cr
+		}
+		cr.Detections++
+		if !slices.Contains(cr.Patterns, d.Pattern) {
+			cr.Patterns = append(cr.Patterns, d.Pattern)
+		}
+	}
+	for _, cr := range byClient {
+		report.Clients = append(report.Clients, *cr)
+	}
+	slices.SortFunc(report.Clients, func(a, b ClientReport) int {
+		return cmp.Or(b.LastDetectedAt.Compare(a.LastDetectedAt), cmp.Compare(a.Client, b.Client))
+	})
+	report.Detections = append(report.Detections, detections[:min(limit, len(detections))]...)
+
+	c.JSON(http.StatusOK, report)
+}
+
+// InitializeAdminRoutes registers the scraping report. The router must only admit admins.
+func InitializeAdminRoutes(router *gin.RouterGroup) {
+	router.GET("", GetReport)
+}
@@ -0,0 +1,127 @@
+package search
+
+import "time"
+
+// Kinds of indexed documents
+const (
+	KindProfile       = "profile"
+	KindSkill         = "skill"
+	KindExperience    = "experience"
+	KindQualification = "qualification"
+	KindCertificate   = "certificate"
+	KindJournal       = "journal"
+)
+
+// Document is a searchable copy of a profile, CV item or public journal entry
+type Document struct {
+	// ID combines the kind and the resource ID, unique across kinds
+	ID         string `bson:"_id" json:"id"`
+	Kind       string `bson:"kind" json:"kind"`
+	UserID     string `bson:"user_id" json:"userID"`
+	ResourceID string `bson:"resource_id" json:"resourceID"`
+	Title      string `bson:"title" json:"title"`
+	Body       string `bson:"body" json:"body"`
+	// Tags holds the journal taxonomy terms
+	Tags []string `bson:"tags" json:"tags"`
+	// Skills holds the skill names of a skill document, or every skill of a profile's owner
+	Skills []string `bson:"skills" json:"skills"`
+	// Institutions holds the institution of a qualification or certificate, or every institution of a profile's owner
+	Institutions []string `bson:"institutions" json:"institutions"`
+	// Availability is the status of a profile whose owner is open to work and shows it publicly, with the
+	// date they can start, the roles and locations they are after and their remote work preference
+	Availability  string    `bson:"availability" json:"availability"`
+	AvailableFrom string    `bson:"available_from" json:"availableFrom"`
+	Roles         []string  `bson:"roles" json:"roles"`
+	Locations     []string  `bson:"locations" json:"locations"`
+	Remote        string    `bson:"remote" json:"remote"`
+	UpdatedAt     time.Time `bson:"updated_at" json:"updatedAt"`
+}
+
+// Query selects documents. Text is matched against titles and bodies; the other fields filter by facet.
+type Query struct {
+	Text        string
+	Kind        string
+	Tag         string
+	Skill       string
+	Institution string
+	// Availability, Role, Location and Remote only match profiles whose owner is open to work, and
+	// AvailableBy those whose owner can start on or before the date
+	Availability string
+	Role         string
+	Location     string
+	Remote       string
+	AvailableBy  string
+	Limit        int
+	Offset       int
+}
+
+// Hit is a document matching a query
+type Hit struct {
+	Kind         string   `json:"kind"`
+	UserID       string   `json:"userID"`
+	ResourceID   string   `json:"resourceID"`
+	Title        string   `json:"title"`
+	Snippet      string   `json:"snippet"`
+	Tags         []string `json:"tags"`
+	Skills       []string `json:"skills"`
+	Institutions []string `json:"institutions"`
+	// Availability is the status of a profile whose owner is open to work
+	Availability string  `json:"availability,omitempty"`
+	Score        float64 `json:"score"`
+}
+
+// FacetValue counts the matching documents with a facet value
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facets counts the matching documents by kind, taxonomy term, skill and institution, most common first
+type Facets struct {
+	Kinds        []FacetValue `json:"kinds"`
+	Tags         []FacetValue `json:"tags"`
+	Skills       []FacetValue `json:"skills"`
+	Institutions []FacetValue `json:"institutions"`
+}
+
+// Results is a page of hits along with the total number of matches and the facets of every match
+type Results struct {
+	Total  int    `json:"total"`
+	Hits   []Hit  `json:"hits"`
+	Facets Facets `json:"facets"`
+}
+
+// Types of suggestions
+const (
+	SuggestNames        = "names"
+	SuggestSkills       = "skills"
+	SuggestTags         = "tags"
+	SuggestInstitutions = "institutions"
+)
+
+// SuggestQuery asks for the values completing a prefix typed into the search box. A value completes it
+// when the value, or one of its words, starts with the prefix, ignoring case.
+type SuggestQuery struct {
+	Prefix string
+	// Limits holds the most suggestions of each type to return. Types missing from it are not suggested.
+	Limits map[string]int
+}
+
+// Suggestion is a value completing the prefix
+type Suggestion struct {
+	Text string `json:"text"`
+	// Count is how many documents have the skill, tag or institution
+	Count int `json:"count,omitempty"`
+	// UserID is the user a name belongs to
+	UserID string `json:"userID,omitempty"`
+}
+
+// Suggestions holds the suggestions of each type, values starting with the prefix before those with a
+// later word starting with it. Names are then ranked by the most recently updated profile, and the others
+// by the number of documents having them.
+type Suggestions struct {
+	Names        []Suggestion `json:"names"`
+	Skills       []Suggestion `json:"skills"`
+	Tags         []Suggestion `json:"tags"`
+	Institutions []Suggestion `json:"institutions"`
+}
@@ -0,0 +1,17 @@
+package search
+
+import "context"
+
+// maxFacetValues is the most values returned for each facet
+const maxFacetValues = 20
+
+// Repository stores the search index
+type Repository interface {
+	// ReplaceUser replaces every indexed document of the user with docs
+	ReplaceUser(ctx context.Context, userID string, docs []Document) error
+	// Search returns the page of documents matching the query, best matches first, or most recently
+	// updated first when the query has no text
+	Search(ctx context.Context, q Query) (Results, error)
+	// Suggest returns the names of profiles, skills, taxonomy terms and institutions completing the prefix
+	Suggest(ctx context.Context, q SuggestQuery) (Suggestions, error)
+}
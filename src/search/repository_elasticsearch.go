@@ -0,0 +1,380 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"profile-api/config"
+	"profile-api/tenant"
+)
+
+var elasticsearchHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// indexProperties keeps the facet and filter fields whole, so values such as "Go" and "Google Cloud" are
+// counted apart
+const indexProperties = `{
+	"properties": {
+		"kind":           {"type": "keyword"},
+		"user_id":        {"type": "keyword"},
+		"resource_id":    {"type": "keyword"},
+		"title":          {"type": "text"},
+		"body":           {"type": "text"},
+		"tags":           {"type": "keyword"},
+		"skills":         {"type": "keyword"},
+		"institutions":   {"type": "keyword"},
+		"availability":   {"type": "keyword"},
+		"available_from": {"type": "keyword"},
+		"roles":          {"type": "keyword"},
+		"locations":      {"type": "keyword"},
+		"remote":         {"type": "keyword"},
+		"updated_at":     {"type": "date"}
+	}
+}`
+
+// indexMapping creates an index with the properties
+const indexMapping = `{"mappings": ` + indexProperties + `}`
+
+// ElasticsearchRepository keeps the search index in an Elasticsearch cluster, one index per tenant. It is
+// not wrapped per tenant like the storage repositories, as it picks the tenant's index itself.
+type ElasticsearchRepository struct {
+	cfg config.ElasticsearchConfig
+
+	mu      sync.Mutex
+	created map[string]bool
+}
+
+// NewElasticsearchRepository creates a repository using the configured cluster. Indexes are created on first use.
+func NewElasticsearchRepository(cfg config.ElasticsearchConfig) *ElasticsearchRepository {
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+	return &ElasticsearchRepository{cfg: cfg, created: map[string]bool{}}
+}
+
+// index returns the name of the tenant's index, creating it if it does not exist yet
+func (r *ElasticsearchRepository) index(ctx context.Context) (string, error) {
+	id := tenant.ID(ctx)
+	if id == tenant.Default {
+		id = "default"
+	}
+	name := r.cfg.IndexPrefix + id
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.created[name] {
+		return name, nil
+	}
+	status, _, err := r.do(ctx, http.MethodHead, "/"+name, "", nil)
+	if err != nil {
+		return "", err
+	}
+	if status == http.StatusNotFound {
+		if _, _, err := r.do(ctx, http.MethodPut, "/"+name, "application/json", []byte(indexMapping)); err != nil {
+			return "", err
+		}
+	} else if _, _, err := r.do(ctx, http.MethodPut, "/"+name+"/_mapping", "application/json", []byte(indexProperties)); err != nil {
+		// Indexes created by earlier versions lack the fields added since
+		return "", err
+	}
+	r.created[name] = true
+	return name, nil
+}
+
+// do sends a request to the cluster, returning an error for any response other than success or 404
+func (r *ElasticsearchRepository) do(ctx context.Context, method, path, contentType string, body []byte) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, r.cfg.URL+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if r.cfg.Username != "" {
+		req.SetBasicAuth(r.cfg.Username, r.cfg.Password)
+	}
+
+	resp, err := elasticsearchHTTPClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotFound {
+		return 0, nil, fmt.Errorf("elasticsearch returned %s: %s", resp.Status, bytes.TrimSpace(data[:min(len(data), 1024)]))
+	}
+	return resp.StatusCode, data, nil
+}
+
+func (r *ElasticsearchRepository) ReplaceUser(ctx context.Context, userID string, docs []Document) error {
+	index, err := r.index(ctx)
+	if err != nil {
+		return err
+	}
+
+	query, err := json.Marshal(map[string]any{"query": map[string]any{"term": map[string]any{"user_id": userID}}})
+	if err != nil {
+		return err
+	}
+	if _, _, err := r.do(ctx, http.MethodPost, "/"+index+"/_delete_by_query?refresh=true&conflicts=proceed", "application/json", query); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	var bulk bytes.Buffer
+	enc := json.NewEncoder(&bulk)
+	for _, doc := range docs {
+		if err := enc.Encode(map[string]any{"index": map[string]any{"_id": doc.ID}}); err != nil {
+			return err
+		}
+		if err := enc.Encode(newElasticsearchDocument(doc)); err != nil {
+			return err
+		}
+	}
+	_, data, err := r.do(ctx, http.MethodPost, "/"+index+"/_bulk?refresh=true", "application/x-ndjson", bulk.Bytes())
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Errors bool `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+	if resp.Errors {
+		return fmt.Errorf("elasticsearch rejected some of the documents of user %s", userID)
+	}
+	return nil
+}
+
+func (r *ElasticsearchRepository) Search(ctx context.Context, q Query) (Results, error) {
+	index, err := r.index(ctx)
+	if err != nil {
+		return Results{}, err
+	}
+
+	var filters []any
+	terms := map[string]string{
+		"kind": q.Kind, "tags": q.Tag, "skills": q.Skill, "institutions": q.Institution,
+		"availability": q.Availability, "roles": q.Role, "locations": q.Location, "remote": q.Remote,
+	}
+	for field, value := range terms {
+		if value != "" {
+			filters = append(filters, map[string]any{"term": map[string]any{field: value}})
+		}
+	}
+	if q.AvailableBy != "" {
+		// Users who can start straight away have no date, which sorts before any other
+		filters = append(filters,
+			map[string]any{"range": map[string]any{"available_from": map[string]any{"lte": q.AvailableBy}}},
+			map[string]any{"range": map[string]any{"availability": map[string]any{"gt": ""}}})
+	}
+	boolQuery := map[string]any{"filter": filters}
+	sort := []any{"_score", map[string]any{"updated_at": "desc"}}
+	if q.Text != "" {
+		boolQuery["must"] = map[string]any{"multi_match": map[string]any{
+			"query":    q.Text,
+			"fields":   []string{"title^3", "body"},
+			"operator": "and",
+		}}
+	} else {
+		sort = []any{map[string]any{"updated_at": "desc"}}
+	}
+	aggs := map[string]any{}
+	for _, field := range []string{"kind", "tags", "skills", "institutions"} {
+		aggs[field] = map[string]any{"terms": map[string]any{"field": field, "size": maxFacetValues}}
+	}
+	body, err := json.Marshal(map[string]any{
+		"query":            map[string]any{"bool": boolQuery},
+		"sort":             sort,
+		"from":             q.Offset,
+		"size":             q.Limit,
+		"track_total_hits": true,
+		"aggs":             aggs,
+	})
+	if err != nil {
+		return Results{}, err
+	}
+
+	_, data, err := r.do(ctx, http.MethodPost, "/"+index+"/_search", "application/json", body)
+	if err != nil {
+		return Results{}, err
+	}
+	var resp struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Score  *float64              `json:"_score"`
+				Source elasticsearchDocument `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+		Aggregations map[string]struct {
+			Buckets []struct {
+				Key      string `json:"key"`
+				DocCount int    `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"aggregations"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return Results{}, err
+	}
+
+	results := Results{Total: resp.Hits.Total.Value, Hits: []Hit{}}
+	for _, h := range resp.Hits.Hits {
+		var score float64
+		if h.Score != nil {
+			score = *h.Score
+		}
+		results.Hits = append(results.Hits, newHit(h.Source.document(), score))
+	}
+	buckets := func(field string) []FacetValue {
+		values := []FacetValue{}
+		for _, b := range resp.Aggregations[field].Buckets {
+			values = append(values, FacetValue{Value: b.Key, Count: b.DocCount})
+		}
+		return values
+	}
+	results.Facets = Facets{
+		Kinds:        buckets("kind"),
+		Tags:         buckets("tags"),
+		Skills:       buckets("skills"),
+		Institutions: buckets("institutions"),
+	}
+	return results, nil
+}
+
+// suggestCandidates is how many values of each type are fetched for ranking by how they complete the
+// prefix, as Elasticsearch orders them by recency or count alone
+const suggestCandidates = 100
+
+func (r *ElasticsearchRepository) Suggest(ctx context.Context, q SuggestQuery) (Suggestions, error) {
+	index, err := r.index(ctx)
+	if err != nil {
+		return Suggestions{}, err
+	}
+
+	search := map[string]any{"size": 0}
+	if q.Limits[SuggestNames] > 0 {
+		search["size"] = suggestCandidates
+		search["query"] = map[string]any{"bool": map[string]any{
+			"filter": map[string]any{"term": map[string]any{"kind": KindProfile}},
+			"must":   map[string]any{"match_phrase_prefix": map[string]any{"title": q.Prefix}},
+		}}
+		search["sort"] = []any{map[string]any{"updated_at": "desc"}}
+	}
+	// Keyword values are matched whole and case-sensitively, so the pattern spells out both cases
+	include := "(.*[ \t\n])?" + caseInsensitivePattern(q.Prefix) + ".*"
+	aggs := map[string]any{}
+	for _, field := range []string{"skills", "tags", "institutions"} {
+		if q.Limits[field] > 0 {
+			aggs[field] = map[string]any{"terms": map[string]any{"field": field, "size": suggestCandidates, "include": include}}
+		}
+	}
+	search["aggs"] = aggs
+	body, err := json.Marshal(search)
+	if err != nil {
+		return Suggestions{}, err
+	}
+
+	_, data, err := r.do(ctx, http.MethodPost, "/"+index+"/_search", "application/json", body)
+	if err != nil {
+		return Suggestions{}, err
+	}
+	var resp struct {
+		Hits struct {
+			Hits []struct {
+				Source elasticsearchDocument `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+		Aggregations map[string]struct {
+			Buckets []struct {
+				Key      string `json:"key"`
+				DocCount int    `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"aggregations"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return Suggestions{}, err
+	}
+
+	var suggestions Suggestions
+	var names []Suggestion
+	for _, h := range resp.Hits.Hits {
+		if prefixRank(h.Source.Title, q.Prefix) > 0 {
+			names = append(names, Suggestion{Text: h.Source.Title, UserID: h.Source.UserID})
+		}
+	}
+	// The hits are the most recently updated first, which is kept among names ranking the same
+	slices.SortStableFunc(names, func(a, b Suggestion) int {
+		return prefixRank(b.Text, q.Prefix) - prefixRank(a.Text, q.Prefix)
+	})
+	suggestions.Names = names[:min(q.Limits[SuggestNames], len(names))]
+
+	buckets := func(field string) []Suggestion {
+		var values []Suggestion
+		for _, b := range resp.Aggregations[field].Buckets {
+			values = append(values, Suggestion{Text: b.Key, Count: b.DocCount})
+		}
+		return rankSuggestions(values, q.Prefix, q.Limits[field])
+	}
+	suggestions.Skills = buckets("skills")
+	suggestions.Tags = buckets("tags")
+	suggestions.Institutions = buckets("institutions")
+	return suggestions, nil
+}
+
+// caseInsensitivePattern returns a Lucene regular expression matching the text in any case
+func caseInsensitivePattern(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case unicode.ToLower(r) != unicode.ToUpper(r):
+			b.WriteString("[" + string(unicode.ToLower(r)) + string(unicode.ToUpper(r)) + "]")
+		case strings.ContainsRune(`.?+*|{}[]()"\#@&<>~`, r):
+			b.WriteString(`\` + string(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// elasticsearchDocument is the source of an indexed document, named as in the mapping
+type elasticsearchDocument struct {
+	ID            string    `json:"id"`
+	Kind          string    `json:"kind"`
+	UserID        string    `json:"user_id"`
+	ResourceID    string    `json:"resource_id"`
+	Title         string    `json:"title"`
+	Body          string    `json:"body"`
+	Tags          []string  `json:"tags"`
+	Skills        []string  `json:"skills"`
+	Institutions  []string  `json:"institutions"`
+	Availability  string    `json:"availability"`
+	AvailableFrom string    `json:"available_from"`
+	Roles         []string  `json:"roles"`
+	Locations     []string  `json:"locations"`
+	Remote        string    `json:"remote"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func newElasticsearchDocument(doc Document) elasticsearchDocument {
+	return elasticsearchDocument(doc)
+}
+
+func (d elasticsearchDocument) document() Document {
+	return Document(d)
+}
@@ -0,0 +1,178 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// MemoryRepository keeps the search index in memory, for tests and demo mode. Every term of the text
+// must appear in a document for it to match.
+type MemoryRepository struct {
+	mu   sync.RWMutex
+	docs map[string][]Document
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{docs: map[string][]Document{}}
+}
+
+func (r *MemoryRepository) ReplaceUser(ctx context.Context, userID string, docs []Document) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(docs) == 0 {
+		delete(r.docs, userID)
+		return nil
+	}
+	r.docs[userID] = docs
+	return nil
+}
+
+func (r *MemoryRepository) Search(ctx context.Context, q Query) (Results, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	terms := strings.Fields(strings.ToLower(q.Text))
+	type match struct {
+		doc   Document
+		score float64
+	}
+	var matches []match
+	var matched []Document
+	for _, docs := range r.docs {
+		for _, doc := range docs {
+			if !matchesFilters(doc, q) {
+				continue
+			}
+			if score, ok := memoryScore(doc, terms); ok {
+				matches = append(matches, match{doc, score})
+				matched = append(matched, doc)
+			}
+		}
+	}
+	slices.SortFunc(matches, func(a, b match) int {
+		if a.score != b.score {
+			return cmp.Compare(b.score, a.score)
+		}
+		return b.doc.UpdatedAt.Compare(a.doc.UpdatedAt)
+	})
+
+	results := Results{Total: len(matches), Hits: []Hit{}, Facets: countFacets(matched)}
+	start := min(q.Offset, len(matches))
+	for _, m := range matches[start : start+min(q.Limit, len(matches)-start)] {
+		results.Hits = append(results.Hits, newHit(m.doc, m.score))
+	}
+	return results, nil
+}
+
+// matchesFilters reports whether the document has every facet value the query filters by
+func matchesFilters(doc Document, q Query) bool {
+	return (q.Kind == "" || doc.Kind == q.Kind) &&
+		(q.Tag == "" || slices.Contains(doc.Tags, q.Tag)) &&
+		(q.Skill == "" || slices.Contains(doc.Skills, q.Skill)) &&
+		(q.Institution == "" || slices.Contains(doc.Institutions, q.Institution)) &&
+		(q.Availability == "" || doc.Availability == q.Availability) &&
+		(q.Role == "" || slices.Contains(doc.Roles, q.Role)) &&
+		(q.Location == "" || slices.Contains(doc.Locations, q.Location)) &&
+		(q.Remote == "" || doc.Remote == q.Remote) &&
+		(q.AvailableBy == "" || (doc.Availability != "" && doc.AvailableFrom <= q.AvailableBy))
+}
+
+// memoryScore counts the occurrences of the terms, weighting the title three times the body. The document
+// only matches when every term occurs.
+func memoryScore(doc Document, terms []string) (float64, bool) {
+	title := strings.ToLower(doc.Title)
+	body := strings.ToLower(doc.Body)
+	var score float64
+	for _, term := range terms {
+		n := 3*strings.Count(title, term) + strings.Count(body, term)
+		if n == 0 {
+			return 0, false
+		}
+		score += float64(n)
+	}
+	return score, true
+}
+
+// countFacets counts the documents by each facet's values
+func countFacets(docs []Document) Facets {
+	kinds, tags, skills, institutions := map[string]int{}, map[string]int{}, map[string]int{}, map[string]int{}
+	for _, doc := range docs {
+		kinds[doc.Kind]++
+		for _, v := range doc.Tags {
+			tags[v]++
+		}
+		for _, v := range doc.Skills {
+			skills[v]++
+		}
+		for _, v := range doc.Institutions {
+			institutions[v]++
+		}
+	}
+	return Facets{
+		Kinds:        topValues(kinds),
+		Tags:         topValues(tags),
+		Skills:       topValues(skills),
+		Institutions: topValues(institutions),
+	}
+}
+
+// topValues returns the most common values, most common first
+func topValues(counts map[string]int) []FacetValue {
+	values := make([]FacetValue, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, FacetValue{Value: value, Count: count})
+	}
+	slices.SortFunc(values, func(a, b FacetValue) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+	if len(values) > maxFacetValues {
+		values = values[:maxFacetValues]
+	}
+	return values
+}
+
+func (r *MemoryRepository) Suggest(ctx context.Context, q SuggestQuery) (Suggestions, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var names []Document
+	tags, skills, institutions := map[string]int{}, map[string]int{}, map[string]int{}
+	for _, docs := range r.docs {
+		for _, doc := range docs {
+			if doc.Kind == KindProfile && prefixRank(doc.Title, q.Prefix) > 0 {
+				names = append(names, doc)
+			}
+			for _, v := range doc.Tags {
+				tags[v]++
+			}
+			for _, v := range doc.Skills {
+				skills[v]++
+			}
+			for _, v := range doc.Institutions {
+				institutions[v]++
+			}
+		}
+	}
+	slices.SortFunc(names, func(a, b Document) int {
+		if ra, rb := prefixRank(a.Title, q.Prefix), prefixRank(b.Title, q.Prefix); ra != rb {
+			return rb - ra
+		}
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+
+	var suggestions Suggestions
+	for _, doc := range names[:min(q.Limits[SuggestNames], len(names))] {
+		suggestions.Names = append(suggestions.Names, Suggestion{Text: doc.Title, UserID: doc.UserID})
+	}
+	suggestions.Skills = rankSuggestions(countedSuggestions(skills), q.Prefix, q.Limits[SuggestSkills])
+	suggestions.Tags = rankSuggestions(countedSuggestions(tags), q.Prefix, q.Limits[SuggestTags])
+	suggestions.Institutions = rankSuggestions(countedSuggestions(institutions), q.Prefix, q.Limits[SuggestInstitutions])
+	return suggestions, nil
+}
@@ -0,0 +1,248 @@
+package search
+
+import (
+	"context"
+	"regexp"
+	"sync"
+
+	"profile-api/store"
+	"profile-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository keeps the search index in the search_index collection, matching text with a Mongo text
+// index, where a document matches when any term of the text occurs in it
+type MongoRepository struct {
+	db   *mongo.Database
+	docs *mongo.Collection
+
+	mu      sync.Mutex
+	indexed bool
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{db: db, docs: db.Collection("search_index")}
+}
+
+// ensureIndexes creates the text index the first time the collection is used
+func (r *MongoRepository) ensureIndexes(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.indexed {
+		return nil
+	}
+	_, err := r.docs.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "title", Value: "text"}, {Key: "body", Value: "text"}},
+			Options: options.Index().SetName("search_text").SetWeights(bson.M{"title": 3, "body": 1}),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	})
+	r.indexed = err == nil
+	return err
+}
+
+func (r *MongoRepository) ReplaceUser(ctx context.Context, userID string, docs []Document) error {
+	if err := r.ensureIndexes(ctx); err != nil {
+		return err
+	}
+	return utils.RunInTransaction(ctx, r.db.Client(), func(ctx context.Context) error {
+		if _, err := r.docs.DeleteMany(ctx, bson.M{"user_id": userID}); err != nil {
+			return err
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+		inserts := make([]any, len(docs))
+		for i, doc := range docs {
+			inserts[i] = doc
+		}
+		_, err := r.docs.InsertMany(ctx, inserts)
+		return err
+	})
+}
+
+// facetBucket is a value counted by $sortByCount
+type facetBucket struct {
+	Value string `bson:"_id"`
+	Count int    `bson:"count"`
+}
+
+func (r *MongoRepository) Search(ctx context.Context, q Query) (Results, error) {
+	if err := r.ensureIndexes(ctx); err != nil {
+		return Results{}, err
+	}
+
+	filter := bson.M{}
+	sort := bson.D{{Key: "updated_at", Value: -1}}
+	if q.Text != "" {
+		filter["$text"] = bson.M{"$search": q.Text}
+		sort = append(bson.D{{Key: "score", Value: -1}}, sort...)
+	}
+	if q.Kind != "" {
+		filter["kind"] = q.Kind
+	}
+	if q.Tag != "" {
+		filter["tags"] = q.Tag
+	}
+	if q.Skill != "" {
+		filter["skills"] = q.Skill
+	}
+	if q.Institution != "" {
+		filter["institutions"] = q.Institution
+	}
+	if q.Availability != "" {
+		filter["availability"] = q.Availability
+	}
+	if q.Role != "" {
+		filter["roles"] = q.Role
+	}
+	if q.Location != "" {
+		filter["locations"] = q.Location
+	}
+	if q.Remote != "" {
+		filter["remote"] = q.Remote
+	}
+	if q.AvailableBy != "" {
+		// Users who can start straight away have no date, which sorts before any other
+		filter["available_from"] = bson.M{"$lte": q.AvailableBy}
+		if q.Availability == "" {
+			filter["availability"] = bson.M{"$gt": ""}
+		}
+	}
+	countBy := func(field string) bson.A {
+		return bson.A{bson.M{"$unwind": "$" + field}, bson.M{"$sortByCount": "$" + field}, bson.M{"$limit": maxFacetValues}}
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	if q.Text != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"hits":         bson.A{bson.M{"$sort": sort}, bson.M{"$skip": q.Offset}, bson.M{"$limit": q.Limit}},
+		"total":        bson.A{bson.M{"$count": "n"}},
+		"kinds":        bson.A{bson.M{"$sortByCount": "$kind"}, bson.M{"$limit": maxFacetValues}},
+		"tags":         countBy("tags"),
+		"skills":       countBy("skills"),
+		"institutions": countBy("institutions"),
+	}}})
+
+	cursor, err := store.MongoReader(ctx, r.docs).Aggregate(ctx, pipeline)
+	if err != nil {
+		return Results{}, err
+	}
+	var out []struct {
+		Hits []struct {
+			Document `bson:",inline"`
+			Score    float64 `bson:"score"`
+		} `bson:"hits"`
+		Total []struct {
+			N int `bson:"n"`
+		} `bson:"total"`
+		Kinds        []facetBucket `bson:"kinds"`
+		Tags         []facetBucket `bson:"tags"`
+		Skills       []facetBucket `bson:"skills"`
+		Institutions []facetBucket `bson:"institutions"`
+	}
+	if err := cursor.All(ctx, &out); err != nil {
+		return Results{}, err
+	}
+
+	results := Results{Hits: []Hit{}}
+	if len(out) == 0 {
+		return results, nil
+	}
+	page := out[0]
+	if len(page.Total) > 0 {
+		results.Total = page.Total[0].N
+	}
+	for _, h := range page.Hits {
+		results.Hits = append(results.Hits, newHit(h.Document, h.Score))
+	}
+	results.Facets = Facets{
+		Kinds:        facetValues(page.Kinds),
+		Tags:         facetValues(page.Tags),
+		Skills:       facetValues(page.Skills),
+		Institutions: facetValues(page.Institutions),
+	}
+	return results, nil
+}
+
+func facetValues(buckets []facetBucket) []FacetValue {
+	values := make([]FacetValue, len(buckets))
+	for i, b := range buckets {
+		values[i] = FacetValue(b)
+	}
+	return values
+}
+
+func (r *MongoRepository) Suggest(ctx context.Context, q SuggestQuery) (Suggestions, error) {
+	// A value completes the prefix when it, or a later word, starts with it; those starting with it rank first
+	completes := primitive.Regex{Pattern: `(^|\s)` + regexp.QuoteMeta(q.Prefix), Options: "i"}
+	starts := bson.M{"$regexMatch": bson.M{"input": "$value", "regex": "^" + regexp.QuoteMeta(q.Prefix), "options": "i"}}
+
+	var suggestions Suggestions
+	if limit := q.Limits[SuggestNames]; limit > 0 {
+		cursor, err := store.MongoReader(ctx, r.docs).Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"kind": KindProfile, "title": completes}}},
+			{{Key: "$project", Value: bson.M{"_id": 0, "value": "$title", "user_id": 1, "updated_at": 1}}},
+			{{Key: "$addFields", Value: bson.M{"starts": starts}}},
+			{{Key: "$sort", Value: bson.D{{Key: "starts", Value: -1}, {Key: "updated_at", Value: -1}}}},
+			{{Key: "$limit", Value: limit}},
+		})
+		if err != nil {
+			return Suggestions{}, err
+		}
+		var names []struct {
+			Value  string `bson:"value"`
+			UserID string `bson:"user_id"`
+		}
+		if err := cursor.All(ctx, &names); err != nil {
+			return Suggestions{}, err
+		}
+		for _, n := range names {
+			suggestions.Names = append(suggestions.Names, Suggestion{Text: n.Value, UserID: n.UserID})
+		}
+	}
+
+	values := []struct {
+		field string
+		limit int
+		dst   *[]Suggestion
+	}{
+		{"skills", q.Limits[SuggestSkills], &suggestions.Skills},
+		{"tags", q.Limits[SuggestTags], &suggestions.Tags},
+		{"institutions", q.Limits[SuggestInstitutions], &suggestions.Institutions},
+	}
+	for _, v := range values {
+		if v.limit == 0 {
+			continue
+		}
+		cursor, err := store.MongoReader(ctx, r.docs).Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{v.field: completes}}},
+			{{Key: "$unwind", Value: "$" + v.field}},
+			{{Key: "$match", Value: bson.M{v.field: completes}}},
+			{{Key: "$group", Value: bson.M{"_id": "$" + v.field, "count": bson.M{"$sum": 1}}}},
+			{{Key: "$addFields", Value: bson.M{"value": "$_id"}}},
+			{{Key: "$addFields", Value: bson.M{"starts": starts}}},
+			{{Key: "$sort", Value: bson.D{{Key: "starts", Value: -1}, {Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+			{{Key: "$limit", Value: v.limit}},
+		})
+		if err != nil {
+			return Suggestions{}, err
+		}
+		var buckets []facetBucket
+		if err := cursor.All(ctx, &buckets); err != nil {
+			return Suggestions{}, err
+		}
+		for _, b := range buckets {
+			*v.dst = append(*v.dst, Suggestion{Text: b.Value, Count: b.Count})
+		}
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,186 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository keeps the search index in the search_index table, matching text with PostgreSQL full
+// text search, where a document matches when every term of the text occurs in it
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) ReplaceUser(ctx context.Context, userID string, docs []Document) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "DELETE FROM search_index WHERE user_id = $1", userID); err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			_, err := tx.Exec(ctx, `INSERT INTO search_index (id, kind, user_id, resource_id, title, body, tags, skills, institutions,
+					availability, available_from, roles, locations, remote, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+				doc.ID, doc.Kind, doc.UserID, doc.ResourceID, doc.Title, doc.Body,
+				nonNil(doc.Tags), nonNil(doc.Skills), nonNil(doc.Institutions),
+				doc.Availability, doc.AvailableFrom, nonNil(doc.Roles), nonNil(doc.Locations), doc.Remote, doc.UpdatedAt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *PostgresRepository) Search(ctx context.Context, q Query) (Results, error) {
+	where := "TRUE"
+	var args []any
+	score := "0"
+	if q.Text != "" {
+		args = append(args, q.Text)
+		where += fmt.Sprintf(" AND document @@ websearch_to_tsquery('english', $%d)", len(args))
+		score = fmt.Sprintf("ts_rank(document, websearch_to_tsquery('english', $%d))", len(args))
+	}
+	if q.Kind != "" {
+		args = append(args, q.Kind)
+		where += fmt.Sprintf(" AND kind = $%d", len(args))
+	}
+	for _, facet := range []struct{ column, value string }{
+		{"tags", q.Tag}, {"skills", q.Skill}, {"institutions", q.Institution}, {"roles", q.Role}, {"locations", q.Location},
+	} {
+		if facet.value != "" {
+			args = append(args, facet.value)
+			where += fmt.Sprintf(" AND $%d = ANY(%s)", len(args), facet.column)
+		}
+	}
+	for _, field := range []struct{ column, value string }{{"availability", q.Availability}, {"remote", q.Remote}} {
+		if field.value != "" {
+			args = append(args, field.value)
+			where += fmt.Sprintf(" AND %s = $%d", field.column, len(args))
+		}
+	}
+	if q.AvailableBy != "" {
+		// Users who can start straight away have no date, which sorts before any other
+		args = append(args, q.AvailableBy)
+		where += fmt.Sprintf(" AND availability <> '' AND available_from <= $%d", len(args))
+	}
+
+	results := Results{Hits: []Hit{}}
+	err := r.pool.QueryRow(ctx, "SELECT count(*) FROM search_index WHERE "+where, args...).Scan(&results.Total)
+	if err != nil {
+		return Results{}, err
+	}
+
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`SELECT id, kind, user_id, resource_id, title, body, tags, skills, institutions,
+			availability, available_from, roles, locations, remote, updated_at, %s AS score
+		FROM search_index WHERE %s ORDER BY score DESC, updated_at DESC LIMIT %d OFFSET %d`, score, where, q.Limit, q.Offset), args...)
+	if err != nil {
+		return Results{}, err
+	}
+	hits, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (Hit, error) {
+		var doc Document
+		var score float64
+		err := row.Scan(&doc.ID, &doc.Kind, &doc.UserID, &doc.ResourceID, &doc.Title, &doc.Body,
+			&doc.Tags, &doc.Skills, &doc.Institutions,
+			&doc.Availability, &doc.AvailableFrom, &doc.Roles, &doc.Locations, &doc.Remote, &doc.UpdatedAt, &score)
+		return newHit(doc, score), err
+	})
+	if err != nil {
+		return Results{}, err
+	}
+	results.Hits = append(results.Hits, hits...)
+
+	facets := []struct {
+		expr string
+		dst  *[]FacetValue
+	}{
+		{"kind", &results.Facets.Kinds},
+		{"unnest(tags)", &results.Facets.Tags},
+		{"unnest(skills)", &results.Facets.Skills},
+		{"unnest(institutions)", &results.Facets.Institutions},
+	}
+	for _, facet := range facets {
+		rows, err := r.pool.Query(ctx, fmt.Sprintf(`SELECT value, count(*) FROM (SELECT %s AS value FROM search_index WHERE %s) matches
+			GROUP BY value ORDER BY count(*) DESC, value LIMIT %d`, facet.expr, where, maxFacetValues), args...)
+		if err != nil {
+			return Results{}, err
+		}
+		*facet.dst, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (FacetValue, error) {
+			var v FacetValue
+			err := row.Scan(&v.Value, &v.Count)
+			return v, err
+		})
+		if err != nil {
+			return Results{}, err
+		}
+	}
+	return results, nil
+}
+
+func (r *PostgresRepository) Suggest(ctx context.Context, q SuggestQuery) (Suggestions, error) {
+	// A value completes the prefix when it, or a later word, starts with it; those starting with it rank first
+	completes := `(^|\s)` + regexp.QuoteMeta(q.Prefix)
+	starts := "^" + regexp.QuoteMeta(q.Prefix)
+
+	var suggestions Suggestions
+	if limit := q.Limits[SuggestNames]; limit > 0 {
+		rows, err := r.pool.Query(ctx, fmt.Sprintf(`SELECT title, user_id FROM search_index WHERE kind = $1 AND title ~* $2
+			ORDER BY title ~* $3 DESC, updated_at DESC LIMIT %d`, limit), KindProfile, completes, starts)
+		if err != nil {
+			return Suggestions{}, err
+		}
+		suggestions.Names, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (Suggestion, error) {
+			var s Suggestion
+			err := row.Scan(&s.Text, &s.UserID)
+			return s, err
+		})
+		if err != nil {
+			return Suggestions{}, err
+		}
+	}
+
+	values := []struct {
+		column string
+		limit  int
+		dst    *[]Suggestion
+	}{
+		{"skills", q.Limits[SuggestSkills], &suggestions.Skills},
+		{"tags", q.Limits[SuggestTags], &suggestions.Tags},
+		{"institutions", q.Limits[SuggestInstitutions], &suggestions.Institutions},
+	}
+	for _, v := range values {
+		if v.limit == 0 {
+			continue
+		}
+		rows, err := r.pool.Query(ctx, fmt.Sprintf(`SELECT value, count(*) FROM search_index, unnest(%s) AS value WHERE value ~* $1
+			GROUP BY value ORDER BY value ~* $2 DESC, count(*) DESC, value LIMIT %d`, v.column, v.limit), completes, starts)
+		if err != nil {
+			return Suggestions{}, err
+		}
+		*v.dst, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (Suggestion, error) {
+			var s Suggestion
+			err := row.Scan(&s.Text, &s.Count)
+			return s, err
+		})
+		if err != nil {
+			return Suggestions{}, err
+		}
+	}
+	return suggestions, nil
+}
+
+// nonNil returns an empty list for nil, as the array columns are not nullable
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,29 @@
+package search
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) ReplaceUser(ctx context.Context, userID string, docs []Document) error {
+	return r.repos.For(ctx).ReplaceUser(ctx, userID, docs)
+}
+
+func (r *TenantRepository) Search(ctx context.Context, q Query) (Results, error) {
+	return r.repos.For(ctx).Search(ctx, q)
+}
+
+func (r *TenantRepository) Suggest(ctx context.Context, q SuggestQuery) (Suggestions, error) {
+	return r.repos.For(ctx).Suggest(ctx, q)
+}
@@ -0,0 +1,464 @@
+// Package search serves full-text search across public profiles, CV sections and public journal entries,
+// with counts of the matches by kind, taxonomy term, skill and institution for narrowing a search down, and
+// suggests names, skills, taxonomy terms and institutions for completing what is typed into the search box.
+//
+// Searches read a separate index rather than the modules' storage. Each user's documents are rebuilt by a
+// background job whenever the audit log records a change to their data, so results may trail a write by
+// a moment, and the admin reindex endpoint rebuilds every user's documents after the index is lost or the
+// backend changes. The index is kept in the storage backend's database by default, or in Elasticsearch.
+//
+// When an embedding provider is configured, profile summaries and public journal entries are also found by
+// meaning. Their vectors are rebuilt by another job after each change, embedding only the texts that
+// changed, and are kept in the storage backend's database or in Qdrant.
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"profile-api/ai"
+	"profile-api/apierror"
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/certificates"
+	"profile-api/experience"
+	"profile-api/jobs"
+	"profile-api/journal"
+	"profile-api/moderation"
+	"profile-api/privacy"
+	"profile-api/profile"
+	"profile-api/qualifications"
+	"profile-api/skills"
+	"profile-api/store"
+	"profile-api/utils"
+	"profile-api/visibility"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Types of the background jobs maintaining the index
+const (
+	IndexUserJob = "search.index_user"
+	ReindexJob   = "search.reindex"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+
+	// snippetLength is the longest snippet of a document's body shown with a hit, in bytes
+	snippetLength = 200
+)
+
+// indexedResources are the audit log resources whose changes alter a user's documents
+var indexedResources = []string{"user", "profile", "skill", "experience", "qualification", "certificate", "journal", "privacy", "hide"}
+
+// Sources holds the storage documents are built from. Certificates and Journals are nil when their module is
+// disabled, leaving their documents out of those indexed from then on.
+type Sources struct {
+	Users          auth.Repository
+	Profiles       profile.Repository
+	Skills         skills.Repository
+	Experience     experience.Repository
+	Qualifications qualifications.Repository
+	Certificates   certificates.Repository
+	Journals       journal.Repository
+	Privacy        privacy.Repository
+}
+
+var repo Repository
+var sources Sources
+
+type indexUserPayload struct {
+	UserID string `json:"userID"`
+}
+
+// Configure sets the index and the storage it is built from, registers the indexing jobs and starts
+// reindexing users whose data changes. It must be called before the job workers start.
+func Configure(r Repository, s Sources) {
+	repo = r
+	sources = s
+
+	jobs.Register(IndexUserJob, func(ctx context.Context, job jobs.Job) error {
+		var payload indexUserPayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return err
+		}
+		return IndexUser(ctx, payload.UserID)
+	})
+	jobs.Register(ReindexJob, func(ctx context.Context, job jobs.Job) error {
+		return Reindex(ctx)
+	})
+	audit.Subscribe(func(ctx context.Context, entry audit.Entry) {
+		if !slices.Contains(indexedResources, entry.Resource) {
+			return
+		}
+		if err := jobs.Enqueue(ctx, IndexUserJob, indexUserPayload{UserID: entry.UserID}); err != nil {
+			slog.ErrorContext(ctx, "Could not queue search indexing", "user_id", entry.UserID, "error", err)
+		}
+	})
+}
+
+// IndexUser rebuilds the user's documents from their current data. Users who can no longer be found, such
+// as deleted users and those whose profile was hidden, are removed from the index.
+func IndexUser(ctx context.Context, userID string) error {
+	docs, err := userDocuments(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return repo.ReplaceUser(ctx, userID, docs)
+}
+
+// Reindex rebuilds the documents of every user of the context's tenant, and their vectors while an
+// embedding provider is configured
+func Reindex(ctx context.Context) error {
+	users, err := sources.Users.List(ctx, auth.UserFilter{})
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, user := range users {
+		if err := IndexUser(ctx, user.ID); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", user.ID, err))
+		}
+		if ai.EmbeddingsEnabled() {
+			if err := EmbedUser(ctx, user.ID); err != nil {
+				errs = append(errs, fmt.Errorf("user %s: %w", user.ID, err))
+			}
+		}
+	}
+	slog.InfoContext(ctx, "Rebuilt search index", "users", len(users), "failed", len(errs))
+	return errors.Join(errs...)
+}
+
+// findable reports whether the user's documents may be found: not when they are deleted or disabled, their
+// profile was hidden by moderation, or they restricted it to their allowlist
+func findable(ctx context.Context, userID string) (bool, error) {
+	user, err := sources.Users.FindByID(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && user.Disabled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if hidden, err := moderation.ProfileHidden(ctx, userID); err != nil || hidden {
+		return false, err
+	}
+	privacySettings, err := sources.Privacy.GetSettings(ctx, userID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return false, err
+	}
+	return !privacySettings.Restricted, nil
+}
+
+// userDocuments builds the documents of the user's profile, CV items and public journal entries
+func userDocuments(ctx context.Context, userID string) ([]Document, error) {
+	if ok, err := findable(ctx, userID); err != nil || !ok {
+		return nil, err
+	}
+
+	userSkills, err := sources.Skills.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	userExperience, err := sources.Experience.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	userQualifications, err := sources.Qualifications.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var userCertificates []certificates.Certificate
+	if sources.Certificates != nil {
+		if userCertificates, err = sources.Certificates.List(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	var entries []journal.JournalEntry
+	if sources.Journals != nil {
+		if entries, err = sources.Journals.List(ctx, journal.Filter{UserID: userID, Status: journal.StatusPublic}); err != nil {
+			return nil, err
+		}
+	}
+
+	// CV items carry no update time, so they take the time they were indexed
+	now := time.Now()
+	var docs []Document
+	var skillNames, institutions []string
+	for _, item := range userSkills {
+		skillNames = appendUnique(skillNames, item.Name)
+		docs = append(docs, Document{
+			Kind:       KindSkill,
+			ResourceID: item.SkillID,
+			Title:      item.Name,
+			Body:       joinText(item.ProficiencyLevel, item.Description),
+			Skills:     []string{item.Name},
+		})
+	}
+	for _, item := range userExperience {
+		docs = append(docs, Document{
+			Kind:       KindExperience,
+			ResourceID: item.ExperienceID,
+			Title:      item.Position + " at " + item.Company,
+			Body:       item.Description,
+		})
+	}
+	for _, item := range userQualifications {
+		institutions = appendUnique(institutions, item.Institution)
+		docs = append(docs, Document{
+			Kind:         KindQualification,
+			ResourceID:   item.QualificationID,
+			Title:        item.Title,
+			Body:         item.Description,
+			Institutions: []string{item.Institution},
+		})
+	}
+	for _, item := range userCertificates {
+		if item.Restricted != "" {
+			// Archived and hidden from the public
+			continue
+		}
+		institutions = appendUnique(institutions, item.Institution)
+		docs = append(docs, Document{
+			Kind:         KindCertificate,
+			ResourceID:   item.CertificateID,
+			Title:        item.Title,
+			Body:         item.Description,
+			Institutions: []string{item.Institution},
+		})
+	}
+	for i := range docs {
+		docs[i].UpdatedAt = now
+	}
+
+	for _, entry := range entries {
+		var latest journal.Entry
+		if len(entry.Entries) > 0 {
+			latest = entry.Entries[len(entry.Entries)-1]
+		}
+		var tags []string
+		for _, terms := range [][]string{entry.Taxonomy.Categories, entry.Taxonomy.Subcategories, entry.Taxonomy.Topics, entry.Taxonomy.Tags} {
+			for _, term := range terms {
+				tags = appendUnique(tags, term)
+			}
+		}
+		docs = append(docs, Document{
+			Kind:       KindJournal,
+			ResourceID: entry.JournalID,
+			Title:      latest.Title,
+			Body:       joinText(entry.Summary, latest.Content),
+			Tags:       tags,
+			UpdatedAt:  entry.UpdatedAt,
+		})
+	}
+
+	// The profile carries every skill and institution so people can be found by them. Email addresses and
+	// phone numbers are never indexed.
+	p, err := sources.Profiles.Get(ctx, userID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		updatedAt := now
+		if p.UpdatedAt != nil {
+			updatedAt = *p.UpdatedAt
+		}
+		doc := Document{
+			Kind:         KindProfile,
+			ResourceID:   userID,
+			Title:        deref(p.Name),
+			Body:         joinText(deref(p.Bio), deref(p.Interests)),
+			Skills:       skillNames,
+			Institutions: institutions,
+			UpdatedAt:    updatedAt,
+		}
+		// Only users open to work who show it to everyone are found by the talent filters
+		if level, ok := p.FieldVisibility()["availability"]; p.Availability.Searchable() && (!ok || level == visibility.Public) {
+			doc.Availability = p.Availability.Status
+			doc.AvailableFrom = p.Availability.AvailableFrom
+			doc.Roles = p.Availability.Roles
+			doc.Locations = p.Availability.Locations
+			doc.Remote = p.Availability.Remote
+		}
+		docs = append(docs, doc)
+	}
+
+	for i := range docs {
+		docs[i].ID = docs[i].Kind + ":" + docs[i].ResourceID
+		docs[i].UserID = userID
+	}
+	return docs, nil
+}
+
+// newHit returns the hit for a matching document, with the start of its body as the snippet
+func newHit(doc Document, score float64) Hit {
+	return Hit{
+		Kind:         doc.Kind,
+		UserID:       doc.UserID,
+		ResourceID:   doc.ResourceID,
+		Title:        doc.Title,
+		Snippet:      truncate(doc.Body, snippetLength, "…"),
+		Tags:         nonNil(doc.Tags),
+		Skills:       nonNil(doc.Skills),
+		Institutions: nonNil(doc.Institutions),
+		Availability: doc.Availability,
+		Score:        score,
+	}
+}
+
+// truncate cuts the text to at most max bytes without splitting a character, marking the cut with ellipsis
+func truncate(text string, max int, ellipsis string) string {
+	if len(text) <= max {
+		return text
+	}
+	end := max
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end] + ellipsis
+}
+
+func appendUnique(values []string, value string) []string {
+	if value == "" || slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+// joinText joins the non-empty parts into paragraphs
+func joinText(parts ...string) string {
+	return strings.Join(slices.DeleteFunc(parts, func(s string) bool { return s == "" }), "\n\n")
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// Search finds profiles, CV items and public journal entries
+//
+//	@Summary		Search profiles and journals
+//	@Description	Searches the titles and text of public profiles, skills, experience, qualifications, certificates and public journal entries, best matches first, or most recently updated first without a query. Facets count every match by kind, taxonomy term, skill and institution. The availability, role, location, remote and available_by filters find profiles of users open to work who show their availability publicly. Results may trail changes by a few seconds.
+//	@Tags			search
+//	@Produce		json
+//	@Param			q			query		string	false	"Words to find in titles and text"
+//	@Param			kind		query		string	false	"Only match this kind of document"	Enums(profile, skill, experience, qualification, certificate, journal)
+//	@Param			tag			query		string	false	"Only match journal entries with this taxonomy term"
+//	@Param			skill		query		string	false	"Only match skills and profiles with this skill"
+//	@Param			institution	query		string	false	"Only match qualifications, certificates and profiles with this institution"
+//	@Param			availability	query	string	false	"Only match profiles whose owner is open to work with this status"	Enums(open, passive)
+//	@Param			role		query		string	false	"Only match profiles whose owner is open to this role"
+//	@Param			location	query		string	false	"Only match profiles whose owner is open to work in this location"
+//	@Param			remote		query		string	false	"Only match profiles whose owner prefers this way of working"	Enums(onsite, hybrid, remote)
+//	@Param			available_by	query	string	false	"Only match profiles whose owner is open to work and can start on or before this date (YYYY-MM-DD)"
+//	@Param			limit		query		int		false	"Maximum number of hits to return (default 20, max 100)"
+//	@Param			offset		query		int		false	"Number of hits to skip"
+//	@Success		200			{object}	Results
+//	@Failure		400			{object}	apierror.Response	"Invalid kind, availability, remote, available_by, limit or offset"
+//	@Failure		500			{object}	apierror.Response	"Could not search"
+//	@Router			/search [get]
+func Search(c *gin.Context) {
+	q := Query{
+		Text:         strings.TrimSpace(c.Query("q")),
+		Kind:         c.Query("kind"),
+		Tag:          c.Query("tag"),
+		Skill:        c.Query("skill"),
+		Institution:  c.Query("institution"),
+		Availability: c.Query("availability"),
+		Role:         c.Query("role"),
+		Location:     c.Query("location"),
+		Remote:       c.Query("remote"),
+		AvailableBy:  c.Query("available_by"),
+		Limit:        defaultLimit,
+	}
+	kinds := []string{KindProfile, KindSkill, KindExperience, KindQualification, KindCertificate, KindJournal}
+	if q.Kind != "" && !slices.Contains(kinds, q.Kind) {
+		apierror.Abort(c, apierror.BadRequest("kind must be one of "+strings.Join(kinds, ", ")))
+		return
+	}
+	statuses := []string{profile.AvailabilityOpen, profile.AvailabilityPassive}
+	if q.Availability != "" && !slices.Contains(statuses, q.Availability) {
+		apierror.Abort(c, apierror.BadRequest("availability must be one of "+strings.Join(statuses, ", ")))
+		return
+	}
+	remote := []string{profile.RemoteOnsite, profile.RemoteHybrid, profile.RemoteOnly}
+	if q.Remote != "" && !slices.Contains(remote, q.Remote) {
+		apierror.Abort(c, apierror.BadRequest("remote must be one of "+strings.Join(remote, ", ")))
+		return
+	}
+	if q.AvailableBy != "" {
+		if _, err := time.Parse(time.DateOnly, q.AvailableBy); err != nil {
+			apierror.Abort(c, apierror.BadRequest("available_by must be a date such as 2025-01-31"))
+			return
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		var err error
+		q.Limit, err = strconv.Atoi(l)
+		if err != nil || q.Limit < 1 || q.Limit > maxLimit {
+			apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("limit must be between 1 and %d", maxLimit)))
+			return
+		}
+	}
+	if o := c.Query("offset"); o != "" {
+		var err error
+		q.Offset, err = strconv.Atoi(o)
+		if err != nil || q.Offset < 0 {
+			apierror.Abort(c, apierror.BadRequest("offset must not be negative"))
+			return
+		}
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	results, err := repo.Search(store.PublicRead(ctx), q)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not search"))
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// StartReindex queues a rebuild of the whole index
+//
+//	@Summary		Rebuild the search index
+//	@Description	Queues a background job rebuilding every user's search documents, and their vectors when an embedding provider is configured, from their current data, for use after the index is lost or the search backend changes. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		202	{object}	map[string]string
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		403	{object}	apierror.Response	"Admin access required"
+//	@Failure		500	{object}	apierror.Response	"Could not queue reindex"
+//	@Router			/admin/search/reindex [post]
+func StartReindex(c *gin.Context) {
+	if err := jobs.Enqueue(c.Request.Context(), ReindexJob, struct{}{}); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not queue reindex"))
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Reindex queued"})
+}
+
+// InitializeRoutes registers the public search endpoints
+func InitializeRoutes(router *gin.RouterGroup) {
+	router.GET("", Search)
+	router.GET("/semantic", SemanticSearch)
+	router.GET("/suggest", Suggest)
+}
+
+// InitializeAdminRoutes registers the reindex endpoint. The router must only admit admins.
+func InitializeAdminRoutes(router *gin.RouterGroup) {
+	router.POST("/reindex", StartReindex)
+}
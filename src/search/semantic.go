@@ -0,0 +1,245 @@
+package search
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"profile-api/ai"
+	"profile-api/apierror"
+	"profile-api/audit"
+	"profile-api/jobs"
+	"profile-api/journal"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmbedUserJob is the type of the background job embedding a user's profile summary and public journal entries
+const EmbedUserJob = "search.embed_user"
+
+const (
+	// maxEmbeddedText bounds the text embedded for each document, within what embedding models accept
+	maxEmbeddedText = 8000
+	// embedBatchSize is how many texts are sent to the embedding provider at once
+	embedBatchSize = 64
+	// maxSemanticLimit bounds the hits of a semantic search, which are not paged
+	maxSemanticLimit = 50
+)
+
+// embeddedResources are the audit log resources whose changes alter a user's vectors
+var embeddedResources = []string{"user", "profile", "journal", "privacy", "hide"}
+
+var vectors VectorRepository
+
+// ConfigureSemantic sets where the vectors of semantic search are kept, registers the embedding job and
+// starts embedding the texts of users whose data changes while an embedding provider is configured. It
+// must be called after Configure and before the job workers start.
+func ConfigureSemantic(r VectorRepository) {
+	vectors = r
+
+	jobs.Register(EmbedUserJob, func(ctx context.Context, job jobs.Job) error {
+		var payload indexUserPayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return err
+		}
+		return EmbedUser(ctx, payload.UserID)
+	})
+	audit.Subscribe(func(ctx context.Context, entry audit.Entry) {
+		if !ai.EmbeddingsEnabled() || !slices.Contains(embeddedResources, entry.Resource) {
+			return
+		}
+		if err := jobs.Enqueue(ctx, EmbedUserJob, indexUserPayload{UserID: entry.UserID}); err != nil {
+			slog.ErrorContext(ctx, "Could not queue embedding", "user_id", entry.UserID, "error", err)
+		}
+	})
+}
+
+// EmbedUser replaces the user's vectors with those of their current profile summary and public journal
+// entries, embedding only the texts that changed since they were last embedded. Users who can no
+// longer be found are removed.
+func EmbedUser(ctx context.Context, userID string) error {
+	docs, err := semanticDocuments(ctx, userID)
+	if err != nil {
+		return err
+	}
+	stored, err := vectors.UserVectors(ctx, userID)
+	if err != nil {
+		return err
+	}
+	byHash := map[string][]float32{}
+	for _, v := range stored {
+		byHash[v.Hash] = v.Values
+	}
+
+	model := ai.EmbeddingModel()
+	result := make([]Vector, len(docs))
+	var texts []string
+	var pending []int
+	for i, doc := range docs {
+		text := truncate(joinText(doc.Title, doc.Body), maxEmbeddedText, "")
+		hash := sha256.Sum256([]byte(model + "\n" + text))
+		result[i] = Vector{
+			ID:         doc.ID,
+			Kind:       doc.Kind,
+			UserID:     userID,
+			ResourceID: doc.ResourceID,
+			Title:      doc.Title,
+			Snippet:    truncate(doc.Body, snippetLength, "…"),
+			Hash:       hex.EncodeToString(hash[:]),
+			UpdatedAt:  doc.UpdatedAt,
+		}
+		if values, ok := byHash[result[i].Hash]; ok {
+			result[i].Values = values
+			continue
+		}
+		texts = append(texts, text)
+		pending = append(pending, i)
+	}
+
+	for start := 0; start < len(texts); start += embedBatchSize {
+		end := min(start+embedBatchSize, len(texts))
+		embedded, err := ai.Embed(ctx, texts[start:end], ai.EmbedDocument)
+		if err != nil {
+			return fmt.Errorf("could not embed the texts of user %s: %w", userID, err)
+		}
+		for j, values := range embedded {
+			result[pending[start+j]].Values = values
+		}
+	}
+	return vectors.ReplaceUserVectors(ctx, userID, result)
+}
+
+// semanticDocuments builds the documents embedded for the user: their profile, when it has a bio, and
+// their public journal entries
+func semanticDocuments(ctx context.Context, userID string) ([]Document, error) {
+	if ok, err := findable(ctx, userID); err != nil || !ok {
+		return nil, err
+	}
+
+	var docs []Document
+	p, err := sources.Profiles.Get(ctx, userID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, err
+	}
+	if err == nil && strings.TrimSpace(deref(p.Bio)) != "" {
+		updatedAt := time.Now()
+		if p.UpdatedAt != nil {
+			updatedAt = *p.UpdatedAt
+		}
+		docs = append(docs, Document{
+			Kind:       KindProfile,
+			ResourceID: userID,
+			Title:      deref(p.Name),
+			Body:       deref(p.Bio),
+			UpdatedAt:  updatedAt,
+		})
+	}
+
+	entries, err := sources.Journals.List(ctx, journal.Filter{UserID: userID, Status: journal.StatusPublic})
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		var latest journal.Entry
+		if len(entry.Entries) > 0 {
+			latest = entry.Entries[len(entry.Entries)-1]
+		}
+		docs = append(docs, Document{
+			Kind:       KindJournal,
+			ResourceID: entry.JournalID,
+			Title:      latest.Title,
+			Body:       joinText(entry.Summary, latest.Content),
+			UpdatedAt:  entry.UpdatedAt,
+		})
+	}
+	for i := range docs {
+		docs[i].ID = docs[i].Kind + ":" + docs[i].ResourceID
+		docs[i].UserID = userID
+	}
+	return docs, nil
+}
+
+// SemanticResults holds the documents closest in meaning to a query, closest first
+type SemanticResults struct {
+	Hits []Hit `json:"hits"`
+}
+
+// SemanticSearch finds profiles and public journal entries by meaning
+//
+//	@Summary		Search profiles and journals by meaning
+//	@Description	Finds the profile summaries and public journal entries closest in meaning to the query, even when they share none of its words, closest first. The score is the cosine similarity of the query and the document. Only available when an embedding provider is configured. Results may trail changes while they are embedded in the background.
+//	@Tags			search
+//	@Produce		json
+//	@Param			q		query		string	true	"What to find"
+//	@Param			kind	query		string	false	"Only match this kind of document"	Enums(profile, journal)
+//	@Param			limit	query		int		false	"Maximum number of hits to return (default 20, max 50)"
+//	@Success		200		{object}	SemanticResults
+//	@Failure		400		{object}	apierror.Response	"Missing query, or invalid kind or limit"
+//	@Failure		500		{object}	apierror.Response	"Could not search"
+//	@Failure		503		{object}	apierror.Response	"No embedding provider is configured"
+//	@Router			/search/semantic [get]
+func SemanticSearch(c *gin.Context) {
+	if !ai.EmbeddingsEnabled() {
+		apierror.Abort(c, apierror.New(http.StatusServiceUnavailable, apierror.CodeServiceUnavailable, "No embedding provider is configured"))
+		return
+	}
+	text := strings.TrimSpace(c.Query("q"))
+	if text == "" {
+		apierror.Abort(c, apierror.BadRequest("q is required"))
+		return
+	}
+	kind := c.Query("kind")
+	if kind != "" && kind != KindProfile && kind != KindJournal {
+		apierror.Abort(c, apierror.BadRequest("kind must be one of profile, journal"))
+		return
+	}
+	limit := defaultLimit
+	if l := c.Query("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > maxSemanticLimit {
+			apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("limit must be between 1 and %d", maxSemanticLimit)))
+			return
+		}
+	}
+
+	embedded, err := ai.Embed(c.Request.Context(), []string{truncate(text, maxEmbeddedText, "")}, ai.EmbedQuery)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not search"))
+		return
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	matches, err := vectors.Nearest(store.PublicRead(ctx), embedded[0], kind, limit)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not search"))
+		return
+	}
+
+	results := SemanticResults{Hits: []Hit{}}
+	for _, m := range matches {
+		results.Hits = append(results.Hits, Hit{
+			Kind:         m.Vector.Kind,
+			UserID:       m.Vector.UserID,
+			ResourceID:   m.Vector.ResourceID,
+			Title:        m.Vector.Title,
+			Snippet:      m.Vector.Snippet,
+			Tags:         []string{},
+			Skills:       []string{},
+			Institutions: []string{},
+			Score:        m.Score,
+		})
+	}
+	c.JSON(http.StatusOK, results)
+}
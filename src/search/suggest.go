@@ -0,0 +1,135 @@
+package search
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"profile-api/apierror"
+	"profile-api/store"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSuggestLimit = 5
+	maxSuggestLimit     = 20
+	maxSuggestPrefix    = 100
+)
+
+// suggestTypes are the types of suggestions, in the order they are shown
+var suggestTypes = []string{SuggestNames, SuggestSkills, SuggestTags, SuggestInstitutions}
+
+// prefixRank tells how well a value completes the prefix: 2 when the value starts with it, 1 when a later
+// word does and 0 when it does not complete it
+func prefixRank(value, prefix string) int {
+	value, prefix = strings.ToLower(value), strings.ToLower(prefix)
+	if strings.HasPrefix(value, prefix) {
+		return 2
+	}
+	for i, r := range value {
+		if i > 0 && strings.ContainsRune(" \t\n", r) && strings.HasPrefix(value[i+1:], prefix) {
+			return 1
+		}
+	}
+	return 0
+}
+
+// rankSuggestions drops the values not completing the prefix and returns the best of the others
+func rankSuggestions(values []Suggestion, prefix string, limit int) []Suggestion {
+	type ranked struct {
+		Suggestion
+		rank int
+	}
+	var matches []ranked
+	for _, v := range values {
+		if rank := prefixRank(v.Text, prefix); rank > 0 {
+			matches = append(matches, ranked{v, rank})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b ranked) int {
+		if a.rank != b.rank {
+			return b.rank - a.rank
+		}
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Text, b.Text)
+	})
+	suggestions := []Suggestion{}
+	for _, m := range matches[:min(limit, len(matches))] {
+		suggestions = append(suggestions, m.Suggestion)
+	}
+	return suggestions
+}
+
+// countedSuggestions turns counted values into suggestions, in any order
+func countedSuggestions(counts map[string]int) []Suggestion {
+	values := make([]Suggestion, 0, len(counts))
+	for text, count := range counts {
+		values = append(values, Suggestion{Text: text, Count: count})
+	}
+	return values
+}
+
+// Suggest completes what has been typed into the search box
+//
+//	@Summary		Suggest search terms
+//	@Description	Returns the names of public profiles, skills, journal taxonomy terms and institutions that start with the prefix, or have a word starting with it, ignoring case, for completing the global search box. Each type has its own limit. Values starting with the prefix come first, then names of the most recently updated profiles and the skills, terms and institutions found in the most documents. Suggestions may trail changes by a few seconds.
+//	@Tags			search
+//	@Produce		json
+//	@Param			q		query		string	true	"Prefix typed so far (at most 100 characters)"
+//	@Param			types	query		string	false	"Comma-separated types to suggest (default all)"	Enums(names, skills, tags, institutions)
+//	@Param			limit	query		int		false	"Maximum number of suggestions of each type (default 5, max 20)"
+//	@Success		200		{object}	Suggestions
+//	@Failure		400		{object}	apierror.Response	"Missing or too long prefix, or invalid types or limit"
+//	@Failure		500		{object}	apierror.Response	"Could not suggest"
+//	@Router			/search/suggest [get]
+func Suggest(c *gin.Context) {
+	prefix := strings.TrimSpace(c.Query("q"))
+	if prefix == "" || utf8.RuneCountInString(prefix) > maxSuggestPrefix {
+		apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("q must be between 1 and %d characters", maxSuggestPrefix)))
+		return
+	}
+	limit := defaultSuggestLimit
+	if l := c.Query("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > maxSuggestLimit {
+			apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("limit must be between 1 and %d", maxSuggestLimit)))
+			return
+		}
+	}
+	types := suggestTypes
+	if t := c.Query("types"); t != "" {
+		types = strings.Split(t, ",")
+	}
+	q := SuggestQuery{Prefix: prefix, Limits: map[string]int{}}
+	for _, t := range types {
+		t = strings.TrimSpace(t)
+		if !slices.Contains(suggestTypes, t) {
+			apierror.Abort(c, apierror.BadRequest("types must be among "+strings.Join(suggestTypes, ", ")))
+			return
+		}
+		q.Limits[t] = limit
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	suggestions, err := repo.Suggest(store.PublicRead(ctx), q)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not suggest"))
+		return
+	}
+
+	for _, list := range []*[]Suggestion{&suggestions.Names, &suggestions.Skills, &suggestions.Tags, &suggestions.Institutions} {
+		if *list == nil {
+			*list = []Suggestion{}
+		}
+	}
+	c.JSON(http.StatusOK, suggestions)
+}
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+)
+
+// Vector is the embedding of a profile summary or public journal entry, for finding them by meaning
+type Vector struct {
+	// ID combines the kind and the resource ID, like the ID of the document
+	ID         string `bson:"_id"`
+	Kind       string `bson:"kind"`
+	UserID     string `bson:"user_id"`
+	ResourceID string `bson:"resource_id"`
+	Title      string `bson:"title"`
+	Snippet    string `bson:"snippet"`
+	// Hash identifies the text embedded and the model embedding it, so unchanged texts are not embedded again
+	Hash      string    `bson:"hash"`
+	Values    []float32 `bson:"values"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// VectorMatch is a vector similar to the one searched for, with their cosine similarity
+type VectorMatch struct {
+	Vector Vector
+	Score  float64
+}
+
+// VectorRepository stores the vectors of semantic search
+type VectorRepository interface {
+	// UserVectors returns every vector of the user
+	UserVectors(ctx context.Context, userID string) ([]Vector, error)
+	// ReplaceUserVectors replaces every vector of the user with vectors
+	ReplaceUserVectors(ctx context.Context, userID string, vectors []Vector) error
+	// Nearest returns the limit vectors most similar to values, of the kind unless it is empty, most similar
+	// first
+	Nearest(ctx context.Context, values []float32, kind string, limit int) ([]VectorMatch, error)
+}
+
+// cosine returns the cosine similarity of two vectors, or false when they cannot be compared
+func cosine(a, b []float32) (float64, bool) {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0, false
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0, false
+	}
+	return dot / math.Sqrt(normA*normB), true
+}
+
+// nearest keeps the limit vectors most similar to the one searched for, for the backends comparing every
+// stored vector
+type nearest struct {
+	values  []float32
+	limit   int
+	matches []VectorMatch
+}
+
+// add compares the vector with the one searched for, keeping it if it is among the most similar so far
+func (n *nearest) add(v Vector) {
+	score, ok := cosine(n.values, v.Values)
+	if !ok {
+		return
+	}
+	if len(n.matches) == n.limit && score <= n.matches[len(n.matches)-1].Score {
+		return
+	}
+	i, _ := slices.BinarySearchFunc(n.matches, score, func(m VectorMatch, score float64) int {
+		// Sorted by descending score
+		switch {
+		case m.Score > score:
+			return -1
+		case m.Score < score:
+			return 1
+		}
+		return 0
+	})
+	n.matches = slices.Insert(n.matches, i, VectorMatch{Vector: v, Score: score})
+	if len(n.matches) > n.limit {
+		n.matches = n.matches[:n.limit]
+	}
+}
@@ -0,0 +1,49 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// MemoryVectorRepository keeps vectors in memory, for tests and demo mode
+type MemoryVectorRepository struct {
+	mu      sync.RWMutex
+	vectors map[string][]Vector
+}
+
+// NewMemoryVectorRepository creates an empty in-memory repository
+func NewMemoryVectorRepository() *MemoryVectorRepository {
+	return &MemoryVectorRepository{vectors: map[string][]Vector{}}
+}
+
+func (r *MemoryVectorRepository) UserVectors(ctx context.Context, userID string) ([]Vector, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.vectors[userID]), nil
+}
+
+func (r *MemoryVectorRepository) ReplaceUserVectors(ctx context.Context, userID string, vectors []Vector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(vectors) == 0 {
+		delete(r.vectors, userID)
+		return nil
+	}
+	r.vectors[userID] = slices.Clone(vectors)
+	return nil
+}
+
+func (r *MemoryVectorRepository) Nearest(ctx context.Context, values []float32, kind string, limit int) ([]VectorMatch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n := nearest{values: values, limit: limit}
+	for _, vectors := range r.vectors {
+		for _, v := range vectors {
+			if kind == "" || v.Kind == kind {
+				n.add(v)
+			}
+		}
+	}
+	return n.matches, nil
+}
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"context"
+	"sync"
+
+	"profile-api/store"
+	"profile-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoVectorRepository keeps vectors in the search_vectors collection, comparing the vector searched for
+// with every stored one
+type MongoVectorRepository struct {
+	db      *mongo.Database
+	vectors *mongo.Collection
+
+	mu      sync.Mutex
+	indexed bool
+}
+
+// NewMongoVectorRepository creates a repository backed by the given database
+func NewMongoVectorRepository(db *mongo.Database) *MongoVectorRepository {
+	return &MongoVectorRepository{db: db, vectors: db.Collection("search_vectors")}
+}
+
+// ensureIndexes creates the index of each user's vectors the first time the collection is used
+func (r *MongoVectorRepository) ensureIndexes(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.indexed {
+		return nil
+	}
+	_, err := r.vectors.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}}})
+	r.indexed = err == nil
+	return err
+}
+
+func (r *MongoVectorRepository) UserVectors(ctx context.Context, userID string) ([]Vector, error) {
+	cursor, err := r.vectors.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	var vectors []Vector
+	err = cursor.All(ctx, &vectors)
+	return vectors, err
+}
+
+func (r *MongoVectorRepository) ReplaceUserVectors(ctx context.Context, userID string, vectors []Vector) error {
+	if err := r.ensureIndexes(ctx); err != nil {
+		return err
+	}
+	return utils.RunInTransaction(ctx, r.db.Client(), func(ctx context.Context) error {
+		if _, err := r.vectors.DeleteMany(ctx, bson.M{"user_id": userID}); err != nil {
+			return err
+		}
+		if len(vectors) == 0 {
+			return nil
+		}
+		inserts := make([]any, len(vectors))
+		for i, v := range vectors {
+			inserts[i] = v
+		}
+		_, err := r.vectors.InsertMany(ctx, inserts)
+		return err
+	})
+}
+
+func (r *MongoVectorRepository) Nearest(ctx context.Context, values []float32, kind string, limit int) ([]VectorMatch, error) {
+	filter := bson.M{}
+	if kind != "" {
+		filter["kind"] = kind
+	}
+	cursor, err := store.MongoReader(ctx, r.vectors).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	n := nearest{values: values, limit: limit}
+	for cursor.Next(ctx) {
+		var v Vector
+		if err := cursor.Decode(&v); err != nil {
+			return nil, err
+		}
+		n.add(v)
+	}
+	return n.matches, cursor.Err()
+}
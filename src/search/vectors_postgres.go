@@ -0,0 +1,68 @@
+package search
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const vectorColumns = "id, kind, user_id, resource_id, title, snippet, hash, vals, updated_at"
+
+// PostgresVectorRepository keeps vectors in the search_vectors table, comparing the vector searched for
+// with every stored one
+type PostgresVectorRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresVectorRepository creates a repository backed by the given connection pool
+func NewPostgresVectorRepository(pool *pgxpool.Pool) *PostgresVectorRepository {
+	return &PostgresVectorRepository{pool: pool}
+}
+
+func (r *PostgresVectorRepository) UserVectors(ctx context.Context, userID string) ([]Vector, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+vectorColumns+" FROM search_vectors WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanVector)
+}
+
+func (r *PostgresVectorRepository) ReplaceUserVectors(ctx context.Context, userID string, vectors []Vector) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "DELETE FROM search_vectors WHERE user_id = $1", userID); err != nil {
+			return err
+		}
+		for _, v := range vectors {
+			_, err := tx.Exec(ctx, "INSERT INTO search_vectors ("+vectorColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+				v.ID, v.Kind, v.UserID, v.ResourceID, v.Title, v.Snippet, v.Hash, v.Values, v.UpdatedAt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *PostgresVectorRepository) Nearest(ctx context.Context, values []float32, kind string, limit int) ([]VectorMatch, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+vectorColumns+" FROM search_vectors WHERE $1 = '' OR kind = $1", kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	n := nearest{values: values, limit: limit}
+	for rows.Next() {
+		v, err := scanVector(rows)
+		if err != nil {
+			return nil, err
+		}
+		n.add(v)
+	}
+	return n.matches, rows.Err()
+}
+
+func scanVector(row pgx.CollectableRow) (Vector, error) {
+	var v Vector
+	err := row.Scan(&v.ID, &v.Kind, &v.UserID, &v.ResourceID, &v.Title, &v.Snippet, &v.Hash, &v.Values, &v.UpdatedAt)
+	return v, err
+}
@@ -0,0 +1,246 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"profile-api/config"
+	"profile-api/tenant"
+)
+
+var qdrantHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// QdrantVectorRepository keeps vectors in a Qdrant database, one collection per tenant. Like the
+// Elasticsearch repository it picks the tenant's collection itself. A collection is created with the size
+// of the first vectors stored in it, so it must be deleted when the embedding model changes to one with
+// vectors of another size.
+type QdrantVectorRepository struct {
+	cfg config.QdrantConfig
+
+	mu      sync.Mutex
+	created map[string]bool
+}
+
+// NewQdrantVectorRepository creates a repository using the configured database. Collections are created
+// when vectors are first stored.
+func NewQdrantVectorRepository(cfg config.QdrantConfig) *QdrantVectorRepository {
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+	return &QdrantVectorRepository{cfg: cfg, created: map[string]bool{}}
+}
+
+// qdrantPayload is what is stored with each point, from which the vector is rebuilt
+type qdrantPayload struct {
+	ID         string    `json:"id"`
+	Kind       string    `json:"kind"`
+	UserID     string    `json:"user_id"`
+	ResourceID string    `json:"resource_id"`
+	Title      string    `json:"title"`
+	Snippet    string    `json:"snippet"`
+	Hash       string    `json:"hash"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type qdrantPoint struct {
+	ID      string        `json:"id"`
+	Vector  []float32     `json:"vector,omitempty"`
+	Payload qdrantPayload `json:"payload"`
+	Score   float64       `json:"score,omitempty"`
+}
+
+func (p qdrantPoint) vector() Vector {
+	return Vector{
+		ID:         p.Payload.ID,
+		Kind:       p.Payload.Kind,
+		UserID:     p.Payload.UserID,
+		ResourceID: p.Payload.ResourceID,
+		Title:      p.Payload.Title,
+		Snippet:    p.Payload.Snippet,
+		Hash:       p.Payload.Hash,
+		Values:     p.Vector,
+		UpdatedAt:  p.Payload.UpdatedAt,
+	}
+}
+
+// pointID derives the UUID Qdrant requires of point IDs from the vector's ID
+func pointID(id string) string {
+	h := sha1.Sum([]byte(id))
+	h[6] = h[6]&0x0f | 0x50
+	h[8] = h[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+}
+
+// collection returns the name of the tenant's collection
+func (r *QdrantVectorRepository) collection(ctx context.Context) string {
+	id := tenant.ID(ctx)
+	if id == tenant.Default {
+		id = "default"
+	}
+	return r.cfg.CollectionPrefix + id
+}
+
+// ensureCollection creates the collection for vectors of the given size if it does not exist yet
+func (r *QdrantVectorRepository) ensureCollection(ctx context.Context, name string, size int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.created[name] {
+		return nil
+	}
+	status, _, err := r.do(ctx, http.MethodGet, "/collections/"+name, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		body := map[string]any{"vectors": map[string]any{"size": size, "distance": "Cosine"}}
+		if _, _, err := r.do(ctx, http.MethodPut, "/collections/"+name, body); err != nil {
+			return err
+		}
+		index := map[string]any{"field_name": "user_id", "field_schema": "keyword"}
+		if _, _, err := r.do(ctx, http.MethodPut, "/collections/"+name+"/index?wait=true", index); err != nil {
+			return err
+		}
+	}
+	r.created[name] = true
+	return nil
+}
+
+// do sends a request to the database, returning an error for any response other than success or 404
+func (r *QdrantVectorRepository) do(ctx context.Context, method, path string, body any) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, r.cfg.URL+path, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if r.cfg.APIKey != "" {
+		req.Header.Set("api-key", r.cfg.APIKey)
+	}
+
+	resp, err := qdrantHTTPClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotFound {
+		return 0, nil, fmt.Errorf("qdrant returned %s: %s", resp.Status, bytes.TrimSpace(data[:min(len(data), 1024)]))
+	}
+	return resp.StatusCode, data, nil
+}
+
+// userFilter matches the points of the user
+func userFilter(userID string) map[string]any {
+	return map[string]any{"must": []any{map[string]any{"key": "user_id", "match": map[string]any{"value": userID}}}}
+}
+
+func (r *QdrantVectorRepository) UserVectors(ctx context.Context, userID string) ([]Vector, error) {
+	name := r.collection(ctx)
+	var vectors []Vector
+	var offset any
+	for {
+		body := map[string]any{"filter": userFilter(userID), "limit": 100, "with_payload": true, "with_vector": true}
+		if offset != nil {
+			body["offset"] = offset
+		}
+		status, data, err := r.do(ctx, http.MethodPost, "/collections/"+name+"/points/scroll", body)
+		if err != nil {
+			return nil, err
+		}
+		if status == http.StatusNotFound {
+			return nil, nil
+		}
+		var resp struct {
+			Result struct {
+				Points         []qdrantPoint `json:"points"`
+				NextPageOffset any           `json:"next_page_offset"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, err
+		}
+		for _, p := range resp.Result.Points {
+			vectors = append(vectors, p.vector())
+		}
+		if resp.Result.NextPageOffset == nil {
+			return vectors, nil
+		}
+		offset = resp.Result.NextPageOffset
+	}
+}
+
+func (r *QdrantVectorRepository) ReplaceUserVectors(ctx context.Context, userID string, vectors []Vector) error {
+	name := r.collection(ctx)
+	if len(vectors) > 0 {
+		if err := r.ensureCollection(ctx, name, len(vectors[0].Values)); err != nil {
+			return err
+		}
+	}
+	status, _, err := r.do(ctx, http.MethodPost, "/collections/"+name+"/points/delete?wait=true", map[string]any{"filter": userFilter(userID)})
+	if err != nil || len(vectors) == 0 {
+		return err
+	}
+	if status == http.StatusNotFound {
+		return fmt.Errorf("qdrant collection %s does not exist", name)
+	}
+
+	points := make([]qdrantPoint, len(vectors))
+	for i, v := range vectors {
+		points[i] = qdrantPoint{
+			ID:     pointID(v.ID),
+			Vector: v.Values,
+			Payload: qdrantPayload{
+				ID:         v.ID,
+				Kind:       v.Kind,
+				UserID:     v.UserID,
+				ResourceID: v.ResourceID,
+				Title:      v.Title,
+				Snippet:    v.Snippet,
+				Hash:       v.Hash,
+				UpdatedAt:  v.UpdatedAt,
+			},
+		}
+	}
+	_, _, err = r.do(ctx, http.MethodPut, "/collections/"+name+"/points?wait=true", map[string]any{"points": points})
+	return err
+}
+
+func (r *QdrantVectorRepository) Nearest(ctx context.Context, values []float32, kind string, limit int) ([]VectorMatch, error) {
+	body := map[string]any{"vector": values, "limit": limit, "with_payload": true}
+	if kind != "" {
+		body["filter"] = map[string]any{"must": []any{map[string]any{"key": "kind", "match": map[string]any{"value": kind}}}}
+	}
+	status, data, err := r.do(ctx, http.MethodPost, "/collections/"+r.collection(ctx)+"/points/search", body)
+	if err != nil || status == http.StatusNotFound {
+		return nil, err
+	}
+	var resp struct {
+		Result []qdrantPoint `json:"result"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	matches := make([]VectorMatch, len(resp.Result))
+	for i, p := range resp.Result {
+		matches[i] = VectorMatch{Vector: p.vector(), Score: p.Score}
+	}
+	return matches, nil
+}
@@ -0,0 +1,29 @@
+package search
+
+import (
+	"context"
+
+	"profile-api/tenant"
+)
+
+// TenantVectorRepository routes each call to the vector repository of the tenant the context belongs to
+type TenantVectorRepository struct {
+	repos tenant.Set[VectorRepository]
+}
+
+// NewTenantVectorRepository creates a repository routing calls between the tenants' repositories
+func NewTenantVectorRepository(repos tenant.Set[VectorRepository]) *TenantVectorRepository {
+	return &TenantVectorRepository{repos: repos}
+}
+
+func (r *TenantVectorRepository) UserVectors(ctx context.Context, userID string) ([]Vector, error) {
+	return r.repos.For(ctx).UserVectors(ctx, userID)
+}
+
+func (r *TenantVectorRepository) ReplaceUserVectors(ctx context.Context, userID string, vectors []Vector) error {
+	return r.repos.For(ctx).ReplaceUserVectors(ctx, userID, vectors)
+}
+
+func (r *TenantVectorRepository) Nearest(ctx context.Context, values []float32, kind string, limit int) ([]VectorMatch, error) {
+	return r.repos.For(ctx).Nearest(ctx, values, kind, limit)
+}
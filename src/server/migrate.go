@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"profile-api/mongodb"
+	"profile-api/postgres"
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// database is a database whose schema is migrated, named main or after its tenant
+type database struct {
+	name     string
+	migrate  func(ctx context.Context) error
+	rollback func(ctx context.Context, target string) error
+	status   func(ctx context.Context) ([]store.Migration, error)
+}
+
+// DatabaseMigrations lists the migrations of one database, named main or after its tenant
+type DatabaseMigrations struct {
+	Database   string
+	Migrations []store.Migration
+}
+
+func (d *Deps) addPostgres(name string, pool *pgxpool.Pool) {
+	d.databases = append(d.databases, database{
+		name:     name,
+		migrate:  func(ctx context.Context) error { return postgres.Migrate(ctx, pool) },
+		rollback: func(ctx context.Context, target string) error { return postgres.Rollback(ctx, pool, target) },
+		status:   func(ctx context.Context) ([]store.Migration, error) { return postgres.Status(ctx, pool) },
+	})
+}
+
+func (d *Deps) addMongo(name string, db *mongo.Database) {
+	d.databases = append(d.databases, database{
+		name:     name,
+		migrate:  func(ctx context.Context) error { return mongodb.Migrate(ctx, db) },
+		rollback: func(ctx context.Context, target string) error { return mongodb.Rollback(ctx, db, target) },
+		status:   func(ctx context.Context) ([]store.Migration, error) { return mongodb.Status(ctx, db) },
+	})
+}
+
+// Migrate applies the pending migrations of every database
+func (d *Deps) Migrate(ctx context.Context) error {
+	for _, db := range d.databases {
+		if err := db.migrate(ctx); err != nil {
+			return fmt.Errorf("error migrating %s database: %w", db.name, err)
+		}
+	}
+	return nil
+}
+
+// Rollback rolls back the migrations numbered after the target in every database, tenants' first
+func (d *Deps) Rollback(ctx context.Context, target string) error {
+	for i := len(d.databases) - 1; i >= 0; i-- {
+		db := d.databases[i]
+		if err := db.rollback(ctx, target); err != nil {
+			return fmt.Errorf("error rolling back %s database: %w", db.name, err)
+		}
+	}
+	return nil
+}
+
+// MigrationStatus lists the migrations of every database with when they were applied
+func (d *Deps) MigrationStatus(ctx context.Context) ([]DatabaseMigrations, error) {
+	var status []DatabaseMigrations
+	for _, db := range d.databases {
+		migrations, err := db.status(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error reading migrations of %s database: %w", db.name, err)
+		}
+		status = append(status, DatabaseMigrations{Database: db.name, Migrations: migrations})
+	}
+	return status, nil
+}
+
+// migrateOnStart applies or verifies the migrations as configured
+func (d *Deps) migrateOnStart(ctx context.Context, onStart string) error {
+	switch onStart {
+	case "apply":
+		return d.Migrate(ctx)
+	case "verify":
+		status, err := d.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		var errs []error
+		for _, db := range status {
+			if pending := store.Pending(db.Migrations); len(pending) > 0 {
+				errs = append(errs, fmt.Errorf("%s database has %d pending migrations, from %s", db.Database, len(pending), pending[0].Version))
+			}
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("%w; run profile-api admin migrate", errors.Join(errs...))
+		}
+	default:
+		slog.Info("Skipping database migrations as configured")
+	}
+	return nil
+}
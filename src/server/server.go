@@ -0,0 +1,894 @@
+// Package server assembles the API: it opens the configured storage and builds the router serving every
+// module, so the API can run as the profile-api binary, be embedded in another Go program or be served by
+// httptest in integration tests.
+//
+// The modules keep their settings and storage in package variables, so only one server can be assembled
+// per process; calling New again reconfigures every module.
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+
+	"profile-api/activity"
+	"profile-api/activitypub"
+	"profile-api/admin"
+	"profile-api/ai"
+	"profile-api/apierror"
+	"profile-api/apiusage"
+	"profile-api/apiversion"
+	"profile-api/attachments"
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/automation"
+	"profile-api/awards"
+	"profile-api/batch"
+	"profile-api/billing"
+	"profile-api/bodylimit"
+	"profile-api/cache"
+	"profile-api/certificates"
+	"profile-api/changelog"
+	"profile-api/changes"
+	"profile-api/clientip"
+	"profile-api/config"
+	"profile-api/demo"
+	"profile-api/domains"
+	"profile-api/dryrun"
+	"profile-api/email"
+	"profile-api/events"
+	"profile-api/experience"
+	"profile-api/features"
+	"profile-api/gql"
+	"profile-api/grpcapi"
+	"profile-api/health"
+	"profile-api/idempotency"
+	"profile-api/images"
+	"profile-api/inbox"
+	"profile-api/integrations"
+	"profile-api/jobs"
+	"profile-api/journal"
+	"profile-api/languages"
+	"profile-api/logging"
+	"profile-api/moderation"
+	"profile-api/notifications"
+	"profile-api/openapi"
+	"profile-api/organizations"
+	"profile-api/postgres"
+	"profile-api/privacy"
+	"profile-api/profile"
+	"profile-api/qualifications"
+	"profile-api/quota"
+	"profile-api/readonly"
+	"profile-api/recommendations"
+	"profile-api/recovery"
+	"profile-api/requestid"
+	"profile-api/resume"
+	"profile-api/sanitize"
+	"profile-api/scan"
+	"profile-api/scheduler"
+	"profile-api/scraping"
+	"profile-api/search"
+	"profile-api/shares"
+	"profile-api/skills"
+	"profile-api/store"
+	"profile-api/subscriptions"
+	"profile-api/tenant"
+	"profile-api/timeouts"
+	"profile-api/tracing"
+	"profile-api/utils"
+	"profile-api/validation"
+	"profile-api/webhooks"
+	"profile-api/widgets"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"google.golang.org/grpc"
+)
+
+// Deps holds the storage the server runs on and the connections behind it. Connections not used by the
+// configuration are nil, so tests can build Deps around NewMemoryRepositories alone.
+type Deps struct {
+	Repos Repositories
+
+	Mongo       *mongo.Client
+	Postgres    *pgxpool.Pool
+	TenantPools []*pgxpool.Pool
+	Cache       *cache.Cache
+	RedisQueue  *jobs.RedisQueue
+
+	// databases are the main database and each tenant's, whose schemas are migrated
+	databases []database
+	// mongoDatabases are the Mongo databases of each tenant, whose changes are watched
+	mongoDatabases map[string]*mongo.Database
+}
+
+// Open connects to the configured storage, and to each tenant's storage in multi-tenant deployments, and
+// migrates or verifies the databases as configured by cfg.Migrations
+func Open(ctx context.Context, cfg *config.Config) (deps *Deps, err error) {
+	deps = &Deps{}
+	defer func() {
+		if err != nil {
+			deps.Close(context.WithoutCancel(ctx))
+		}
+	}()
+
+	// Connect to the database, unless everything is kept in memory
+	switch cfg.Storage {
+	case store.Memory:
+		slog.Warn("Using in-memory storage, all data will be lost when the server stops")
+		deps.Repos = NewMemoryRepositories()
+	case store.Postgres:
+		deps.Postgres, err = postgres.Connect(ctx, cfg.Postgres.URL)
+		if err != nil {
+			return deps, fmt.Errorf("error connecting to PostgreSQL: %w", err)
+		}
+		deps.addPostgres("main", deps.Postgres)
+		deps.Repos = NewPostgresRepositories(deps.Postgres)
+	default:
+		publicReads, err := mongoReadPreference(cfg.Mongo)
+		if err != nil {
+			return deps, err
+		}
+		store.SetPublicReadPreference(publicReads)
+		retry := utils.ConnectRetry{
+			Retries: cfg.Mongo.ConnectRetries,
+			Backoff: cfg.Mongo.ConnectRetryBackoff.Std(),
+			Timeout: cfg.Mongo.ConnectTimeout.Std(),
+		}
+		deps.Mongo, err = utils.ConnectDB(ctx, cfg.Mongo.URI, retry, mongoClientOptions(cfg.Mongo).SetMonitor(tracing.CommandMonitor()))
+		if err != nil {
+			return deps, fmt.Errorf("error connecting to MongoDB: %w", err)
+		}
+		db := deps.Mongo.Database(cfg.Mongo.Database)
+		deps.addMongo("main", db)
+		deps.mongoDatabases = map[string]*mongo.Database{tenant.Default: db}
+		deps.Repos = NewMongoRepositories(db)
+	}
+
+	// Cache hot public reads in Redis when it is configured
+	if cfg.Cache.RedisURL != "" {
+		deps.Cache, err = cache.New(ctx, cfg.Cache)
+		if err != nil {
+			return deps, fmt.Errorf("error connecting to Redis: %w", err)
+		}
+		deps.Repos.withCache(deps.Cache, cfg.Cache)
+	}
+
+	// Give each tenant its own database, keeping the job queue and scheduler locks in the main one
+	if len(cfg.Tenants) > 0 {
+		err = deps.Repos.withTenants(cfg.Tenants, func(t config.TenantConfig) (Repositories, error) {
+			var rs Repositories
+			switch cfg.Storage {
+			case store.Memory:
+				rs = NewMemoryRepositories()
+			case store.Postgres:
+				tenantPool, err := postgres.Connect(ctx, t.PostgresURL)
+				if err != nil {
+					return rs, err
+				}
+				deps.TenantPools = append(deps.TenantPools, tenantPool)
+				deps.addPostgres(t.ID, tenantPool)
+				rs = NewPostgresRepositories(tenantPool)
+			default:
+				db := deps.Mongo.Database(t.Database)
+				deps.addMongo(t.ID, db)
+				deps.mongoDatabases[t.ID] = db
+				rs = NewMongoRepositories(db)
+			}
+			if deps.Cache != nil {
+				rs.withCache(deps.Cache, cfg.Cache)
+			}
+			return rs, nil
+		})
+		if err != nil {
+			return deps, fmt.Errorf("error opening tenant storage: %w", err)
+		}
+		slog.Info("Serving multiple tenants", "tenants", len(cfg.Tenants))
+	}
+
+	// Keep the search index in Elasticsearch and its vectors in Qdrant when configured, which pick each
+	// tenant's index and collection themselves
+	if cfg.Search.Backend == "elasticsearch" {
+		deps.Repos.Search = search.NewElasticsearchRepository(cfg.Search.Elasticsearch)
+	}
+	if cfg.Search.Vectors.Backend == "qdrant" {
+		deps.Repos.Vectors = search.NewQdrantVectorRepository(cfg.Search.Vectors.Qdrant)
+	}
+
+	// Run background jobs from the storage backend's queue, or Redis when configured
+	if cfg.Jobs.Backend == "redis" {
+		deps.RedisQueue, err = jobs.NewRedisQueue(ctx, cfg.Jobs.RedisURL)
+		if err != nil {
+			return deps, fmt.Errorf("error connecting to the Redis job queue: %w", err)
+		}
+		deps.Repos.Jobs = deps.RedisQueue
+	}
+
+	if err := deps.migrateOnStart(ctx, cfg.Migrations.OnStart); err != nil {
+		return deps, err
+	}
+	return deps, nil
+}
+
+// mongoClientOptions returns the connection pool and timeout settings of the Mongo client. Anything the
+// URI sets is overridden.
+func mongoClientOptions(cfg config.MongoConfig) *options.ClientOptions {
+	return options.Client().
+		SetMaxPoolSize(uint64(cfg.MaxPoolSize)).
+		SetMinPoolSize(uint64(cfg.MinPoolSize)).
+		SetMaxConnIdleTime(cfg.MaxConnIdleTime.Std()).
+		SetConnectTimeout(cfg.ConnectTimeout.Std()).
+		SetServerSelectionTimeout(cfg.ServerSelectionTimeout.Std())
+}
+
+// mongoReadPreference returns the read preference heavy public reads are served with
+func mongoReadPreference(cfg config.MongoConfig) (*readpref.ReadPref, error) {
+	mode, err := readpref.ModeFromString(cfg.PublicReadPreference)
+	if err != nil {
+		return nil, err
+	}
+	var opts []readpref.Option
+	if cfg.MaxStaleness > 0 {
+		opts = append(opts, readpref.WithMaxStaleness(cfg.MaxStaleness.Std()))
+	}
+	return readpref.New(mode, opts...)
+}
+
+// Close closes the connections. Job workers must have finished first, see jobs.Wait.
+func (d *Deps) Close(ctx context.Context) {
+	if d.RedisQueue != nil {
+		if err := d.RedisQueue.Close(); err != nil {
+			slog.Error("Error closing Redis job queue", "error", err)
+		}
+	}
+	if d.Mongo != nil {
+		if err := d.Mongo.Disconnect(ctx); err != nil {
+			slog.Error("Error disconnecting from MongoDB", "error", err)
+		}
+	}
+	for _, tenantPool := range d.TenantPools {
+		tenantPool.Close()
+	}
+	if d.Postgres != nil {
+		d.Postgres.Close()
+	}
+	if d.Cache != nil {
+		if err := d.Cache.Close(); err != nil {
+			slog.Error("Error closing Redis connection", "error", err)
+		}
+	}
+}
+
+// New configures every module from the config and returns the router serving the API. Background jobs
+// are queued but not run until StartBackground is called.
+func New(cfg *config.Config, deps *Deps) (*gin.Engine, error) {
+	if err := validation.Register(); err != nil {
+		return nil, fmt.Errorf("failed to register validators: %w", err)
+	}
+	tenant.Configure(cfg.Tenants, cfg.Branding)
+	auth.Configure(cfg.JWT)
+	if err := auth.ConfigureRegistration(cfg.Registration); err != nil {
+		return nil, err
+	}
+	if err := email.InitSender(context.Background(), cfg.Email); err != nil {
+		return nil, fmt.Errorf("failed to initialize email sender: %w", err)
+	}
+	subscriptions.SetBaseURL(cfg.PublicBaseURL)
+	admin.SetBaseURL(cfg.PublicBaseURL)
+	recommendations.SetBaseURL(cfg.PublicBaseURL)
+	privacy.SetBaseURL(cfg.PublicBaseURL)
+	shares.SetBaseURL(cfg.PublicBaseURL)
+	attachments.SetBaseURL(cfg.PublicBaseURL)
+	domains.SetBaseURL(cfg.PublicBaseURL)
+	widgets.SetBaseURL(cfg.PublicBaseURL)
+	utils.SetOperationTimeout(cfg.Mongo.OperationTimeout.Std())
+	utils.SetRequireIfMatch(cfg.RequireIfMatch)
+	images.Configure(cfg.ImageStore)
+	ai.Configure(cfg.AI, deps.Repos.AIUsage)
+	ai.ConfigureEmbeddings(cfg.AI.Embeddings)
+	if err := profile.InitImageStore(cfg.ImageStore, cfg.Tenants); err != nil {
+		return nil, fmt.Errorf("failed to initialize image store: %w", err)
+	}
+
+	// Record every change to user data in the audit log
+	audit.Configure(deps.Repos.Audit)
+	// Append the changes to profile data to each user's change log, for external systems to consume
+	changelog.Configure(deps.Repos.Changelog)
+	repos := deps.Repos.Audited()
+
+	// Clean user-supplied rich text before it is stored and recorded in the audit log
+	sanitize.Configure(cfg.Sanitize)
+	repos = repos.Sanitized()
+	// Skip the writes of requests sent as dry runs
+	repos = repos.DryRun()
+
+	// Features reading the documents of the modules leave out those of disabled modules
+	modules := enabled(cfg.Modules, repos)
+
+	// Scan uploads for malware in the background. The targets read image URLs as stored, unsigned.
+	scan.Configure(cfg.Scan, repos.Users)
+	scan.RegisterTarget(profile.ScanKind, profile.NewScanTarget(repos.Profiles))
+	scan.RegisterTarget(certificates.ScanKind, certificates.NewScanTarget(repos.Certificates))
+	scan.RegisterTarget(qualifications.ScanKind, qualifications.NewScanTarget(repos.Qualifications))
+	scan.RegisterTarget(awards.ScanKind, awards.NewScanTarget(repos.Awards))
+
+	// Hand out signed URLs of the images served by a private CDN
+	if cfg.ImageStore.CDN.Private {
+		signer, err := profile.NewURLSigner(cfg.ImageStore, cfg.Tenants)
+		if err != nil {
+			return nil, err
+		}
+		repos.Profiles = profile.NewSignedRepository(repos.Profiles, signer)
+	}
+
+	// Switch subsystems on and off per the configured flags and the admins' overrides
+	features.Configure(repos.Features, cfg.Features)
+
+	// Replay the stored response to retried POST requests repeating an Idempotency-Key
+	idempotency.Configure(repos.Idempotency, cfg.Idempotency)
+
+	// Count the API calls each user makes, as the groundwork for fair-use limits
+	apiusage.Configure(repos.APIUsage, cfg.APIUsage)
+	scraping.Configure(repos.Scraping, cfg.Scraping)
+
+	jobs.Configure(repos.Jobs, cfg.Jobs)
+	email.RegisterJobs()
+	scan.RegisterJobs()
+	webhooks.Configure(repos.Webhooks, cfg.Webhooks)
+	notifications.Configure(repos.Notifications, repos.Users, repos.Certificates, cfg.Notifications)
+	certificates.ConfigureArchival(cfg.Archival)
+	// Alert admins of requests failing on a panic
+	recovery.Configure(cfg.Recovery, func(ctx context.Context, p recovery.Panic) error {
+		message := fmt.Sprintf("%s %s failed on a panic: %s", p.Method, p.Path, p.Value)
+		return notifications.NotifyAdmins(ctx, notifications.KindServerError, message, p)
+	})
+	organizations.Configure(repos.Organizations, repos.Users)
+	recommendations.Configure(repos.Recommendations, repos.Users, cfg.Recommendations)
+	inbox.Configure(repos.Inbox, repos.Users, cfg.Inbox)
+	privacy.Configure(repos.Privacy, repos.Users, cfg.Privacy)
+	shares.Configure(repos.Shares, shares.Sources{
+		Users:          repos.Users,
+		Profiles:       repos.Profiles,
+		Experience:     repos.Experience,
+		Qualifications: repos.Qualifications,
+		Certificates:   modules.Certificates,
+	}, cfg.Shares)
+	domains.Configure(repos.Domains, cfg.Domains)
+	moderation.Configure(repos.Moderation, repos.Users, repos.Journals, cfg.Moderation)
+	attachments.Configure(repos.Attachments, repos.Journals, cfg.Attachments)
+	integrations.Configure(repos.Integrations, repos.Users, repos.Certificates)
+	automation.Configure(repos.APIKeys, repos.Users, modules.Journals, modules.Certificates)
+	activity.Configure(repos.Activity, activity.Sources{
+		Users:        repos.Users,
+		Journals:     modules.Journals,
+		Certificates: modules.Certificates,
+		Experience:   repos.Experience,
+	})
+	search.Configure(repos.Search, search.Sources{
+		Users:          repos.Users,
+		Profiles:       repos.Profiles,
+		Skills:         repos.Skills,
+		Experience:     repos.Experience,
+		Qualifications: repos.Qualifications,
+		Certificates:   modules.Certificates,
+		Journals:       modules.Journals,
+		Privacy:        repos.Privacy,
+	})
+	search.ConfigureSemantic(repos.Vectors)
+	widgets.Configure(widgets.Sources{
+		Users:    repos.Users,
+		Profiles: repos.Profiles,
+		Skills:   repos.Skills,
+		Journals: modules.Journals,
+	}, cfg.Embed)
+	if cfg.ActivityPub.Enabled {
+		activitypub.Configure(repos.ActivityPub, repos.Users, repos.Profiles, repos.Journals, cfg.ActivityPub)
+		activitypub.SetBaseURL(cfg.PublicBaseURL)
+	}
+	if cfg.Billing.Enabled {
+		billing.Configure(repos.Billing, repos.Users, cfg.Billing)
+	}
+	quota.Configure(repos.Uploads, cfg.Quotas)
+
+	demo.Configure(demo.Repositories{
+		Users:          repos.Users,
+		Profiles:       repos.Profiles,
+		Skills:         repos.Skills,
+		Experience:     repos.Experience,
+		Qualifications: repos.Qualifications,
+		Certificates:   modules.Certificates,
+		Awards:         repos.Awards,
+		Languages:      repos.Languages,
+		Journals:       modules.Journals,
+	}, cfg.Demo)
+
+	router := gin.New()
+	router.MaxMultipartMemory = int64(cfg.BodyLimits.MultipartMemory)
+	// Derive the client address from the forwarding headers of trusted proxies only
+	if err := clientip.Configure(router, cfg); err != nil {
+		return nil, err
+	}
+	router.Use(clientip.Middleware(), requestid.Middleware(cfg.TrustedProxies), tracing.Middleware(), logging.AccessLog(health.LivenessPath, health.ReadinessPath), apiusage.Middleware(), recovery.Middleware(), apierror.Middleware(), timeouts.Middleware(cfg.Timeouts))
+
+	// Health probes for load balancers and Kubernetes, registered before any auth
+	health.InitializeRoutes(router, deps.Mongo, deps.Postgres, deps.Cache)
+	// Fail fast with 503 while MongoDB is away rather than letting each request time out against it
+	if deps.Mongo != nil && cfg.Mongo.HealthCheckInterval > 0 {
+		router.Use(health.RequireMongo())
+	}
+	// A read-only replica serves public reads only, leaving writes to the main instance
+	if cfg.ReadOnly.Enabled {
+		router.Use(readonly.Middleware(cfg.ReadOnly))
+	}
+	router.Use(corsMiddleware(cfg.CORS))
+	router.Use(extractIdentifierMiddleware())
+	router.Use(bodylimit.Middleware(cfg.BodyLimits), dryrun.Middleware(), idempotency.Middleware())
+
+	// Validate requests against the OpenAPI document, built from the routes once they are all registered
+	openapi.Configure(cfg.OpenAPI)
+	router.Use(openapi.Middleware())
+
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	openapi.InitializeRoutes(router)
+
+	// Initialize the site branding route
+	tenant.InitializeRoutes(router.Group("/api/v1"))
+
+	// Initialize authentication routes
+	authRouter := router.Group("/api/v1/auth")
+	auth.InitializeRoutes(authRouter, repos.Users)
+	quota.InitializeRoutes(authRouter, repos.Users)
+	apiusage.InitializeRoutes(authRouter, repos.Users)
+
+	// Initialize profile routes. The profile and the CV sections shown on it are hidden from requesters off
+	// the allowlist of users who restricted their profile.
+	profileRouter := router.Group("/api/v1/profile", privacy.Restrict(), moderation.Restrict())
+	profile.InitializeRoutes(profileRouter, repos.Profiles, repos.Users)
+	profile.InitializeImageRoutes(router)
+	profile.SetSummarySources(profile.SummarySources{
+		Experience:     repos.Experience,
+		Qualifications: repos.Qualifications,
+		Skills:         repos.Skills,
+	})
+	profile.SetCalendarSources(profile.CalendarSources{
+		Experience:     repos.Experience,
+		Qualifications: repos.Qualifications,
+		Certificates:   modules.Certificates,
+		Awards:         repos.Awards,
+	})
+	profile.SetAggregateSources(profile.AggregateSources{
+		Experience:      modules.Experience,
+		Qualifications:  modules.Qualifications,
+		Certificates:    modules.Certificates,
+		Awards:          modules.Awards,
+		Languages:       modules.Languages,
+		Skills:          modules.Skills,
+		Recommendations: modules.Recommendations,
+	})
+	// Signed image URLs are good for at least half their expiry, so full profiles are not kept longer
+	aggregateTTL := cfg.Cache.AggregateTTL.Std()
+	if cfg.ImageStore.CDN.Private {
+		aggregateTTL = min(aggregateTTL, cfg.ImageStore.CDN.URLExpiry.Std()/2)
+	}
+	profile.ConfigureAggregateCache(deps.Cache, aggregateTTL, cfg.Cache.AggregateSize)
+	if cfg.Modules.Resume {
+		resume.Configure(cfg.Resume)
+		resume.InitializeRoutes(profileRouter, resume.Repositories{
+			Experience:     repos.Experience,
+			Qualifications: repos.Qualifications,
+			Skills:         repos.Skills,
+		}, repos.Users)
+	}
+
+	// Initialize experience routes
+	if cfg.Modules.Experience {
+		experienceRouter := router.Group("/api/v1/experience", apiversion.Deprecate(), privacy.Restrict(), moderation.Restrict())
+		experience.InitializeRoutes(experienceRouter, repos.Experience, repos.Users)
+	}
+
+	// Initialize qualifications routes
+	if cfg.Modules.Qualifications {
+		qualificationsRouter := router.Group("/api/v1/qualifications", apiversion.Deprecate(), privacy.Restrict(), moderation.Restrict())
+		qualifications.InitializeRoutes(qualificationsRouter, repos.Qualifications, repos.Users)
+	}
+
+	// Initialize certificates routes
+	if cfg.Modules.Certificates {
+		certificatesRouter := router.Group("/api/v1/certificates", apiversion.Deprecate(), privacy.Restrict(), moderation.Restrict())
+		certificates.InitializeRoutes(certificatesRouter, repos.Certificates, repos.Users)
+	}
+
+	// Initialize awards routes
+	if cfg.Modules.Awards {
+		awardsRouter := router.Group("/api/v1/awards", apiversion.Deprecate(), privacy.Restrict(), moderation.Restrict())
+		awards.InitializeRoutes(awardsRouter, repos.Awards, repos.Users)
+	}
+
+	// Initialize languages routes
+	if cfg.Modules.Languages {
+		languagesRouter := router.Group("/api/v1/languages", apiversion.Deprecate(), privacy.Restrict(), moderation.Restrict())
+		languages.InitializeRoutes(languagesRouter, repos.Languages, repos.Users)
+	}
+
+	// Initialize skills routes. Skills are suggested from experience, when that module is enabled.
+	if cfg.Modules.Skills {
+		skillsRouter := router.Group("/api/v1/skills", apiversion.Deprecate(), privacy.Restrict(), moderation.Restrict())
+		skills.InitializeRoutes(skillsRouter, repos.Skills, repos.Users)
+		skills.SetExperience(modules.Experience)
+	}
+
+	// Initialize the v2 routes of the modules that have moved to the v2 response conventions, sharing their v1 handlers
+	v2Router := router.Group("/api/v2", apiversion.Middleware(apiversion.V2), privacy.Restrict(), moderation.Restrict())
+	if cfg.Modules.Experience {
+		experience.InitializeRoutes(v2Router.Group("/experience"), repos.Experience, repos.Users)
+	}
+	if cfg.Modules.Qualifications {
+		qualifications.InitializeRoutes(v2Router.Group("/qualifications"), repos.Qualifications, repos.Users)
+	}
+	if cfg.Modules.Certificates {
+		certificates.InitializeRoutes(v2Router.Group("/certificates"), repos.Certificates, repos.Users)
+	}
+	if cfg.Modules.Awards {
+		awards.InitializeRoutes(v2Router.Group("/awards"), repos.Awards, repos.Users)
+	}
+	if cfg.Modules.Skills {
+		skills.InitializeRoutes(v2Router.Group("/skills"), repos.Skills, repos.Users)
+	}
+	if cfg.Modules.Languages {
+		languages.InitializeRoutes(v2Router.Group("/languages"), repos.Languages, repos.Users)
+	}
+
+	// Initialize journal routes
+	if cfg.Modules.Journal {
+		journalRouter := router.Group("/api/v1/journal", privacy.Restrict(), moderation.Restrict())
+		journal.InitializeRoutes(journalRouter, repos.Journals, repos.Users)
+		attachments.InitializeRoutes(journalRouter, repos.Users)
+	}
+
+	// Initialize real-time event routes
+	eventsRouter := router.Group("/api/v1")
+	eventsRouter.Use(auth.AuthMiddleware(repos.Users, true))
+	events.InitializeRoutes(eventsRouter, cfg.CORS.AllowedOrigins)
+
+	// Initialize audit log routes
+	auditRouter := router.Group("/api/v1/audit")
+	auditRouter.Use(auth.AuthMiddleware(repos.Users, true))
+	audit.InitializeRoutes(auditRouter)
+
+	// Initialize change log routes
+	changelogRouter := router.Group("/api/v1/changes")
+	changelog.InitializeRoutes(changelogRouter, repos.Users)
+
+	// Initialize admin routes
+	adminRouter := router.Group("/api/v1/admin")
+	adminRouter.Use(auth.AuthMiddleware(repos.Users, true), auth.RequireAdmin())
+	admin.InitializeRoutes(adminRouter, repos.Stats, repos.Users)
+	audit.InitializeAdminRoutes(adminRouter.Group("/audit"))
+	jobs.InitializeRoutes(adminRouter.Group("/jobs"))
+	email.InitializeRoutes(adminRouter.Group("/email-log"), repos.EmailLog)
+	search.InitializeAdminRoutes(adminRouter.Group("/search"))
+	features.InitializeAdminRoutes(adminRouter.Group("/features"))
+	apiusage.InitializeAdminRoutes(adminRouter.Group("/usage"))
+	moderation.InitializeAdminRoutes(adminRouter.Group("/moderation"))
+	if cfg.Modules.Journal {
+		attachments.InitializeAdminRoutes(adminRouter.Group("/attachments"))
+		scraping.InitializeAdminRoutes(adminRouter.Group("/scraping"))
+	}
+	if cfg.Modules.Certificates {
+		integrations.InitializeAdminRoutes(adminRouter.Group("/integrations"))
+	}
+	if demo.Enabled() {
+		demo.InitializeAdminRoutes(adminRouter.Group("/demo"))
+	}
+
+	// Initialize search routes
+	searchRouter := router.Group("/api/v1/search")
+	searchRouter.Use(auth.AuthMiddleware(repos.Users, false), features.Require(features.Search))
+	search.InitializeRoutes(searchRouter)
+
+	// Initialize journal digest subscription routes
+	if cfg.Modules.Subscriptions {
+		subscriptionsRouter := router.Group("/api/v1/subscriptions")
+		subscriptions.InitializeRoutes(subscriptionsRouter, repos.Subscriptions, repos.Journals, repos.Users)
+	}
+
+	// Initialize the GraphQL API, reading from the same repositories as the REST routes
+	err := gql.InitializeRoutes(router, gql.Repositories{
+		Profiles:        repos.Profiles,
+		Skills:          modules.Skills,
+		Experience:      modules.Experience,
+		Qualifications:  modules.Qualifications,
+		Certificates:    modules.Certificates,
+		Awards:          modules.Awards,
+		Languages:       modules.Languages,
+		Journals:        modules.Journals,
+		Recommendations: modules.Recommendations,
+	}, repos.Users)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize GraphQL schema: %w", err)
+	}
+
+	// Federate public journal entries with Mastodon and other ActivityPub servers
+	if cfg.ActivityPub.Enabled {
+		activitypub.InitializeRoutes(router)
+	}
+
+	// Sell premium plans through Stripe
+	if cfg.Billing.Enabled {
+		billing.InitializeRoutes(router.Group("/api/v1/billing"), repos.Users)
+	}
+
+	// Initialize outbound webhook routes
+	webhooksRouter := router.Group("/api/v1/webhooks")
+	webhooks.InitializeRoutes(webhooksRouter, repos.Users)
+
+	// Receive events pushed by external systems such as learning platforms, which award certificates
+	if cfg.Modules.Certificates {
+		integrations.InitializeRoutes(router.Group("/api/v1/integrations"))
+	}
+
+	// Let no-code tools such as Zapier and n8n work with a user's data through API keys
+	automation.InitializeRoutes(router.Group("/api/v1/automation"), repos.Users)
+
+	// Initialize notification center routes
+	notificationsRouter := router.Group("/api/v1/notifications")
+	notifications.InitializeRoutes(notificationsRouter, repos.Users)
+
+	// Initialize public activity feed routes
+	activityRouter := router.Group("/api/v1/activity", privacy.Restrict(), moderation.Restrict())
+	activity.InitializeRoutes(activityRouter)
+
+	// Initialize organization routes
+	organizationsRouter := router.Group("/api/v1/organizations")
+	organizations.InitializeRoutes(organizationsRouter)
+
+	// Initialize recommendation routes
+	if cfg.Modules.Recommendations {
+		recommendationsRouter := router.Group("/api/v1/recommendations", privacy.Restrict(), moderation.Restrict())
+		recommendations.InitializeRoutes(recommendationsRouter)
+	}
+
+	// Initialize the inbox routes, including the contact form
+	inboxRouter := router.Group("/api/v1/inbox")
+	inbox.InitializeRoutes(inboxRouter)
+
+	// Initialize the privacy routes, blocking users and managing the allowlist
+	privacyRouter := router.Group("/api/v1/privacy")
+	privacy.InitializeRoutes(privacyRouter)
+
+	// Initialize the routes of the packages users share with recruiters, and the links recruiters follow
+	sharesRouter := router.Group("/api/v1/shares")
+	shares.InitializeRoutes(sharesRouter)
+
+	// Initialize the routes of the domains users serve their profile on
+	domainsRouter := router.Group("/api/v1/domains")
+	domains.InitializeRoutes(domainsRouter, repos.Users)
+
+	// Initialize the route for reporting abuse, open to visitors
+	reportRouter := router.Group("/api/v1/report")
+	moderation.InitializeRoutes(reportRouter, repos.Users)
+
+	// Initialize the embeddable widget routes, which any site may fetch
+	embedRouter := router.Group("/api/v1/embed")
+	widgets.InitializeRoutes(embedRouter)
+
+	// Initialize the batch route, running each request through the router like any other. Batches and the
+	// event streams cannot be batched.
+	batchRouter := router.Group("/api/v1")
+	batchRouter.Use(auth.AuthMiddleware(repos.Users, true))
+	batch.InitializeRoutes(batchRouter, router, cfg.Batch, "/api/v1/batch", "/api/v1/ws", "/api/v1/events")
+
+	if err := openapi.Build(router.Routes()); err != nil {
+		return nil, fmt.Errorf("failed to build OpenAPI document: %w", err)
+	}
+
+	router.NoRoute(func(c *gin.Context) {
+		slog.Debug("No route for request", "path", c.Request.URL.Path)
+		apierror.Abort(c, apierror.NotFound("Route not found"))
+	})
+	return router, nil
+}
+
+// StartBackground seeds the demo data when enabled and runs the job workers, the periodic tasks and the
+// MongoDB health check until ctx is cancelled. A read-only replica only follows the writes of the other replicas. It must be called
+// after New.
+func StartBackground(ctx context.Context, cfg *config.Config, deps *Deps) error {
+	if !cfg.ReadOnly.Enabled {
+		if err := startWorkers(ctx, cfg, deps); err != nil {
+			return err
+		}
+	}
+
+	if deps.Mongo != nil && cfg.Mongo.HealthCheckInterval > 0 {
+		go health.MonitorMongo(ctx, deps.Mongo, cfg.Mongo.HealthCheckInterval.Std())
+	}
+
+	// Follow the writes of every replica, so this one drops its stale cached copies and tells its clients
+	if cfg.Mongo.ChangeStreams {
+		if deps.Cache != nil {
+			changes.Subscribe(invalidateCache(deps.Cache))
+		}
+		changes.Subscribe(invalidateAggregates)
+		for id, db := range deps.mongoDatabases {
+			go changes.Watch(ctx, db, id)
+		}
+	}
+	return nil
+}
+
+// startWorkers seeds the demo data when enabled and runs the job workers and the periodic tasks
+func startWorkers(ctx context.Context, cfg *config.Config, deps *Deps) error {
+	if err := demo.Seed(ctx); err != nil {
+		return fmt.Errorf("failed to seed demo data: %w", err)
+	}
+	jobs.Start(ctx)
+	apiusage.Start(ctx)
+
+	// Run periodic tasks on the replica holding the scheduler leader lease
+	if cfg.Modules.Subscriptions {
+		scheduler.Register("journal-digests", "@hourly", tenant.Each(subscriptions.SendDigests))
+	}
+	if cfg.Modules.Journal {
+		scheduler.Register("collect-journal-files", "@daily", tenant.Each(attachments.Collect))
+		scheduler.Register("purge-scraping-detections", "@daily", tenant.Each(scraping.Purge))
+	}
+	if cfg.Modules.Certificates {
+		scheduler.Register("notify-expiring-certificates", "@daily", tenant.Each(notifications.NotifyExpiringCertificates))
+		scheduler.Register("archive-certificates", "@daily", tenant.Each(certificates.ArchiveEnded))
+	}
+	scheduler.Register("purge-jobs", "@daily", jobs.Purge)
+	scheduler.Register("purge-webhook-deliveries", "@daily", tenant.Each(webhooks.PurgeDeliveries))
+	scheduler.Register("purge-idempotency-keys", "@hourly", tenant.Each(idempotency.Purge))
+	scheduler.Register("purge-notifications", "@daily", tenant.Each(notifications.Purge))
+	scheduler.Register("purge-spam-contact-requests", "@daily", tenant.Each(inbox.PurgeSpam))
+	scheduler.Register("purge-api-usage", "@daily", tenant.Each(apiusage.Purge))
+	scheduler.Register("check-domains", "@daily", tenant.Each(domains.Check))
+	if err := scheduler.Start(ctx, deps.Repos.Locker, cfg.Scheduler); err != nil {
+		return fmt.Errorf("failed to start scheduler: %w", err)
+	}
+	return nil
+}
+
+// NewGRPC returns the gRPC server for internal services, reading from the same storage as the API
+func NewGRPC(cfg *config.Config, deps *Deps) *grpc.Server {
+	modules := enabled(cfg.Modules, deps.Repos)
+	return grpcapi.NewServer(grpcapi.Repositories{
+		Profiles:        deps.Repos.Profiles,
+		Skills:          modules.Skills,
+		Experience:      modules.Experience,
+		Qualifications:  modules.Qualifications,
+		Certificates:    modules.Certificates,
+		Awards:          modules.Awards,
+		Languages:       modules.Languages,
+		Recommendations: modules.Recommendations,
+		Journals:        modules.Journals,
+	}, deps.Repos.Users)
+}
+
+// enabled returns the repositories with those of the modules the configuration disables set to nil
+func enabled(cfg config.ModulesConfig, r Repositories) Repositories {
+	if !cfg.Journal {
+		r.Journals = nil
+	}
+	if !cfg.Certificates {
+		r.Certificates = nil
+	}
+	if !cfg.Experience {
+		r.Experience = nil
+	}
+	if !cfg.Qualifications {
+		r.Qualifications = nil
+	}
+	if !cfg.Skills {
+		r.Skills = nil
+	}
+	if !cfg.Awards {
+		r.Awards = nil
+	}
+	if !cfg.Languages {
+		r.Languages = nil
+	}
+	if !cfg.Recommendations {
+		r.Recommendations = nil
+	}
+	return r
+}
+
+// corsMiddleware allows browser clients from the configured origins to call the API.
+func corsMiddleware(cfg config.CORSConfig) gin.HandlerFunc {
+	allowed := map[string]bool{}
+	for _, origin := range cfg.AllowedOrigins {
+		allowed[origin] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin != "" && (allowed[origin] || allowed["*"]) {
+			if allowed["*"] && !cfg.AllowCredentials {
+				c.Header("Access-Control-Allow-Origin", "*")
+			} else {
+				c.Header("Access-Control-Allow-Origin", origin)
+				c.Header("Vary", "Origin")
+			}
+			c.Header("Access-Control-Expose-Headers", requestid.Header+", "+idempotency.ReplayedHeader+", "+dryrun.Header)
+			if cfg.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+			if c.Request.Method == http.MethodOptions {
+				c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+				c.Header("Access-Control-Allow-Headers", c.GetHeader("Access-Control-Request-Headers"))
+				c.Header("Access-Control-Max-Age", "600")
+				c.AbortWithStatus(http.StatusNoContent)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// extractIdentifierMiddleware is a middleware that extracts the subdomain or email from the request and stores it in the Gin context.
+// On a verified custom domain the identifier is the ID of the user who attached it instead.
+// In multi-tenant deployments it also assigns the request to the tenant served on its host, or to the tenant of the custom
+// domain's owner, rejecting unknown hosts.
+func extractIdentifierMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		host := c.Request.Host
+		if tenant.Enabled() {
+			t, ok := tenant.Resolve(host)
+			id := t.ID
+			if !ok {
+				site, custom := domains.Resolve(c.Request.Context(), host)
+				if !custom {
+					apierror.Abort(c, apierror.NotFound("Unknown site"))
+					return
+				}
+				id = site.Tenant
+			}
+			c.Set("tenant", id)
+			c.Request = c.Request.WithContext(tenant.WithID(c.Request.Context(), id))
+		}
+
+		site, custom := domains.Resolve(c.Request.Context(), host)
+		subdomain := extractSubdomain(host)
+		email := c.Param("email")
+
+		if custom {
+			c.Set("identifier", site.UserID)
+		} else if subdomain != "" {
+			c.Set("identifier", subdomain)
+		} else if email != "" {
+			c.Set("identifier", email)
+		}
+		c.Next()
+	}
+}
+
+// extractSubdomain takes the host and returns the subdomain part.
+// It will only return a subdomain if the host contains a valid subdomain.
+func extractSubdomain(host string) string {
+	// Remove the port if present
+	hostname, _, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname = host // If no port, use the original host
+	}
+
+	// Split the hostname into parts
+	parts := strings.Split(hostname, ".")
+
+	// Ensure the hostname has at least three parts for a subdomain (e.g., sub.domain.com)
+	if len(parts) > 2 {
+		return parts[0] // Return the first part as the subdomain
+	}
+
+	// Return empty string if no subdomain is found
+	return ""
+}
@@ -0,0 +1,385 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"profile-api/activity"
+	"profile-api/activitypub"
+	"profile-api/admin"
+	"profile-api/ai"
+	"profile-api/apiusage"
+	"profile-api/attachments"
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/automation"
+	"profile-api/awards"
+	"profile-api/billing"
+	"profile-api/cache"
+	"profile-api/certificates"
+	"profile-api/changelog"
+	"profile-api/changes"
+	"profile-api/config"
+	"profile-api/domains"
+	"profile-api/email"
+	"profile-api/experience"
+	"profile-api/features"
+	"profile-api/idempotency"
+	"profile-api/inbox"
+	"profile-api/integrations"
+	"profile-api/jobs"
+	"profile-api/journal"
+	"profile-api/languages"
+	"profile-api/moderation"
+	"profile-api/notifications"
+	"profile-api/organizations"
+	"profile-api/privacy"
+	"profile-api/profile"
+	"profile-api/qualifications"
+	"profile-api/quota"
+	"profile-api/recommendations"
+	"profile-api/scheduler"
+	"profile-api/scraping"
+	"profile-api/search"
+	"profile-api/shares"
+	"profile-api/skills"
+	"profile-api/subscriptions"
+	"profile-api/tenant"
+	"profile-api/webhooks"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Repositories holds the storage behind each module
+type Repositories struct {
+	Users           auth.Repository
+	Profiles        profile.Repository
+	Experience      experience.Repository
+	Qualifications  qualifications.Repository
+	Certificates    certificates.Repository
+	Awards          awards.Repository
+	Languages       languages.Repository
+	Skills          skills.Repository
+	Journals        journal.Repository
+	Subscriptions   subscriptions.Repository
+	EmailLog        email.Repository
+	Webhooks        webhooks.Repository
+	Notifications   notifications.Repository
+	Activity        activity.Repository
+	Organizations   organizations.Repository
+	Recommendations recommendations.Repository
+	Inbox           inbox.Repository
+	Privacy         privacy.Repository
+	Shares          shares.Repository
+	Domains         domains.Repository
+	Moderation      moderation.Repository
+	Attachments     attachments.Repository
+	Integrations    integrations.Repository
+	APIKeys         automation.Repository
+	Stats           admin.Repository
+	Audit           audit.Repository
+	Changelog       changelog.Repository
+	Search          search.Repository
+	Vectors         search.VectorRepository
+	ActivityPub     activitypub.Repository
+	Billing         billing.Repository
+	Uploads         quota.Repository
+	Idempotency     idempotency.Repository
+	Features        features.Repository
+	AIUsage         ai.UsageRepository
+	APIUsage        apiusage.Repository
+	Scraping        scraping.Repository
+	Jobs            jobs.Queue
+	Locker          scheduler.Locker
+}
+
+// NewMongoRepositories creates repositories storing everything in the given Mongo database
+func NewMongoRepositories(db *mongo.Database) Repositories {
+	return Repositories{
+		Users:           auth.NewMongoRepository(db),
+		Profiles:        profile.NewMongoRepository(db),
+		Experience:      experience.NewMongoRepository(db),
+		Qualifications:  qualifications.NewMongoRepository(db),
+		Certificates:    certificates.NewMongoRepository(db),
+		Awards:          awards.NewMongoRepository(db),
+		Languages:       languages.NewMongoRepository(db),
+		Skills:          skills.NewMongoRepository(db),
+		Journals:        journal.NewMongoRepository(db),
+		Subscriptions:   subscriptions.NewMongoRepository(db),
+		EmailLog:        email.NewMongoRepository(db),
+		Webhooks:        webhooks.NewMongoRepository(db),
+		Notifications:   notifications.NewMongoRepository(db),
+		Activity:        activity.NewMongoRepository(db),
+		Organizations:   organizations.NewMongoRepository(db),
+		Recommendations: recommendations.NewMongoRepository(db),
+		Inbox:           inbox.NewMongoRepository(db),
+		Privacy:         privacy.NewMongoRepository(db),
+		Shares:          shares.NewMongoRepository(db),
+		Domains:         domains.NewMongoRepository(db),
+		Moderation:      moderation.NewMongoRepository(db),
+		Attachments:     attachments.NewMongoRepository(db),
+		Integrations:    integrations.NewMongoRepository(db),
+		APIKeys:         automation.NewMongoRepository(db),
+		Stats:           admin.NewMongoRepository(db),
+		Audit:           audit.NewMongoRepository(db),
+		Changelog:       changelog.NewMongoRepository(db),
+		Search:          search.NewMongoRepository(db),
+		Vectors:         search.NewMongoVectorRepository(db),
+		ActivityPub:     activitypub.NewMongoRepository(db),
+		Billing:         billing.NewMongoRepository(db),
+		Uploads:         quota.NewMongoRepository(db),
+		Idempotency:     idempotency.NewMongoRepository(db),
+		Features:        features.NewMongoRepository(db),
+		AIUsage:         ai.NewMongoRepository(db),
+		APIUsage:        apiusage.NewMongoRepository(db),
+		Scraping:        scraping.NewMongoRepository(db),
+		Jobs:            jobs.NewMongoQueue(db),
+		Locker:          scheduler.NewMongoLocker(db),
+	}
+}
+
+// NewPostgresRepositories creates repositories storing everything in the given PostgreSQL database
+func NewPostgresRepositories(pool *pgxpool.Pool) Repositories {
+	return Repositories{
+		Users:           auth.NewPostgresRepository(pool),
+		Profiles:        profile.NewPostgresRepository(pool),
+		Experience:      experience.NewPostgresRepository(pool),
+		Qualifications:  qualifications.NewPostgresRepository(pool),
+		Certificates:    certificates.NewPostgresRepository(pool),
+		Awards:          awards.NewPostgresRepository(pool),
+		Languages:       languages.NewPostgresRepository(pool),
+		Skills:          skills.NewPostgresRepository(pool),
+		Journals:        journal.NewPostgresRepository(pool),
+		Subscriptions:   subscriptions.NewPostgresRepository(pool),
+		EmailLog:        email.NewPostgresRepository(pool),
+		Webhooks:        webhooks.NewPostgresRepository(pool),
+		Notifications:   notifications.NewPostgresRepository(pool),
+		Activity:        activity.NewPostgresRepository(pool),
+		Organizations:   organizations.NewPostgresRepository(pool),
+		Recommendations: recommendations.NewPostgresRepository(pool),
+		Inbox:           inbox.NewPostgresRepository(pool),
+		Privacy:         privacy.NewPostgresRepository(pool),
+		Shares:          shares.NewPostgresRepository(pool),
+		Domains:         domains.NewPostgresRepository(pool),
+		Moderation:      moderation.NewPostgresRepository(pool),
+		Attachments:     attachments.NewPostgresRepository(pool),
+		Integrations:    integrations.NewPostgresRepository(pool),
+		APIKeys:         automation.NewPostgresRepository(pool),
+		Stats:           admin.NewPostgresRepository(pool),
+		Audit:           audit.NewPostgresRepository(pool),
+		Changelog:       changelog.NewPostgresRepository(pool),
+		Search:          search.NewPostgresRepository(pool),
+		Vectors:         search.NewPostgresVectorRepository(pool),
+		ActivityPub:     activitypub.NewPostgresRepository(pool),
+		Billing:         billing.NewPostgresRepository(pool),
+		Uploads:         quota.NewPostgresRepository(pool),
+		Idempotency:     idempotency.NewPostgresRepository(pool),
+		Features:        features.NewPostgresRepository(pool),
+		AIUsage:         ai.NewPostgresRepository(pool),
+		APIUsage:        apiusage.NewPostgresRepository(pool),
+		Scraping:        scraping.NewPostgresRepository(pool),
+		Jobs:            jobs.NewPostgresQueue(pool),
+		Locker:          scheduler.NewPostgresLocker(pool),
+	}
+}
+
+// NewMemoryRepositories creates repositories keeping everything in memory, lost when the server stops
+func NewMemoryRepositories() Repositories {
+	return Repositories{
+		Users:           auth.NewMemoryRepository(),
+		Profiles:        profile.NewMemoryRepository(),
+		Experience:      experience.NewMemoryRepository(),
+		Qualifications:  qualifications.NewMemoryRepository(),
+		Certificates:    certificates.NewMemoryRepository(),
+		Awards:          awards.NewMemoryRepository(),
+		Languages:       languages.NewMemoryRepository(),
+		Skills:          skills.NewMemoryRepository(),
+		Journals:        journal.NewMemoryRepository(),
+		Subscriptions:   subscriptions.NewMemoryRepository(),
+		EmailLog:        email.NewMemoryRepository(),
+		Webhooks:        webhooks.NewMemoryRepository(),
+		Notifications:   notifications.NewMemoryRepository(),
+		Activity:        activity.NewMemoryRepository(),
+		Organizations:   organizations.NewMemoryRepository(),
+		Recommendations: recommendations.NewMemoryRepository(),
+		Inbox:           inbox.NewMemoryRepository(),
+		Privacy:         privacy.NewMemoryRepository(),
+		Shares:          shares.NewMemoryRepository(),
+		Domains:         domains.NewMemoryRepository(),
+		Moderation:      moderation.NewMemoryRepository(),
+		Attachments:     attachments.NewMemoryRepository(),
+		Integrations:    integrations.NewMemoryRepository(),
+		APIKeys:         automation.NewMemoryRepository(),
+		Stats:           admin.NewMemoryRepository(),
+		Audit:           audit.NewMemoryRepository(),
+		Changelog:       changelog.NewMemoryRepository(),
+		Search:          search.NewMemoryRepository(),
+		Vectors:         search.NewMemoryVectorRepository(),
+		ActivityPub:     activitypub.NewMemoryRepository(),
+		Billing:         billing.NewMemoryRepository(),
+		Uploads:         quota.NewMemoryRepository(),
+		Idempotency:     idempotency.NewMemoryRepository(),
+		Features:        features.NewMemoryRepository(),
+		AIUsage:         ai.NewMemoryRepository(),
+		APIUsage:        apiusage.NewMemoryRepository(),
+		Scraping:        scraping.NewMemoryRepository(),
+		Jobs:            jobs.NewMemoryQueue(),
+		Locker:          scheduler.NewMemoryLocker(),
+	}
+}
+
+// withCache wraps the repositories behind hot public reads with the Redis cache
+func (r *Repositories) withCache(c *cache.Cache, cfg config.CacheConfig) {
+	r.Profiles = profile.NewCachedRepository(r.Profiles, c, cfg.ProfileTTL.Std())
+	r.Skills = skills.NewCachedRepository(r.Skills, c, cfg.SkillsTTL.Std())
+	r.Journals = journal.NewCachedRepository(r.Journals, c, cfg.JournalListTTL.Std())
+}
+
+// invalidateCache drops the cached copies of documents changed by any replica, seen through change streams
+func invalidateCache(c *cache.Cache) changes.Handler {
+	return func(ctx context.Context, change changes.Change) {
+		switch change.Resource {
+		case "profile":
+			profile.InvalidateCache(ctx, c, change.UserID)
+		case "skill":
+			skills.InvalidateCache(ctx, c, change.UserID)
+		case "journal":
+			journal.InvalidateCache(ctx, c)
+		}
+	}
+}
+
+// invalidateAggregates drops the cached full profiles of users whose profile data any replica changed, seen
+// through change streams
+func invalidateAggregates(ctx context.Context, change changes.Change) {
+	if change.Resource != "journal" {
+		profile.InvalidateAggregate(ctx, change.UserID)
+	}
+}
+
+// withTenants replaces the repositories of user data with ones routing each call to the repositories of
+// the context's tenant, opened by open. The job queue and scheduler locks stay shared by every tenant.
+func (r *Repositories) withTenants(tenants []config.TenantConfig, open func(config.TenantConfig) (Repositories, error)) error {
+	sets := map[string]Repositories{}
+	for _, t := range tenants {
+		rs, err := open(t)
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", t.ID, err)
+		}
+		sets[t.ID] = rs
+	}
+
+	r.Users = auth.NewTenantRepository(perTenant(sets, func(rs Repositories) auth.Repository { return rs.Users }))
+	r.Profiles = profile.NewTenantRepository(perTenant(sets, func(rs Repositories) profile.Repository { return rs.Profiles }))
+	r.Experience = experience.NewTenantRepository(perTenant(sets, func(rs Repositories) experience.Repository { return rs.Experience }))
+	r.Qualifications = qualifications.NewTenantRepository(perTenant(sets, func(rs Repositories) qualifications.Repository { return rs.Qualifications }))
+	r.Certificates = certificates.NewTenantRepository(perTenant(sets, func(rs Repositories) certificates.Repository { return rs.Certificates }))
+	r.Awards = awards.NewTenantRepository(perTenant(sets, func(rs Repositories) awards.Repository { return rs.Awards }))
+	r.Languages = languages.NewTenantRepository(perTenant(sets, func(rs Repositories) languages.Repository { return rs.Languages }))
+	r.Skills = skills.NewTenantRepository(perTenant(sets, func(rs Repositories) skills.Repository { return rs.Skills }))
+	r.Journals = journal.NewTenantRepository(perTenant(sets, func(rs Repositories) journal.Repository { return rs.Journals }))
+	r.Subscriptions = subscriptions.NewTenantRepository(perTenant(sets, func(rs Repositories) subscriptions.Repository { return rs.Subscriptions }))
+	r.EmailLog = email.NewTenantRepository(perTenant(sets, func(rs Repositories) email.Repository { return rs.EmailLog }))
+	r.Webhooks = webhooks.NewTenantRepository(perTenant(sets, func(rs Repositories) webhooks.Repository { return rs.Webhooks }))
+	r.Notifications = notifications.NewTenantRepository(perTenant(sets, func(rs Repositories) notifications.Repository { return rs.Notifications }))
+	r.Activity = activity.NewTenantRepository(perTenant(sets, func(rs Repositories) activity.Repository { return rs.Activity }))
+	r.Organizations = organizations.NewTenantRepository(perTenant(sets, func(rs Repositories) organizations.Repository { return rs.Organizations }))
+	r.Recommendations = recommendations.NewTenantRepository(perTenant(sets, func(rs Repositories) recommendations.Repository { return rs.Recommendations }))
+	r.Inbox = inbox.NewTenantRepository(perTenant(sets, func(rs Repositories) inbox.Repository { return rs.Inbox }))
+	r.Privacy = privacy.NewTenantRepository(perTenant(sets, func(rs Repositories) privacy.Repository { return rs.Privacy }))
+	r.Shares = shares.NewTenantRepository(perTenant(sets, func(rs Repositories) shares.Repository { return rs.Shares }))
+	r.Domains = domains.NewTenantRepository(perTenant(sets, func(rs Repositories) domains.Repository { return rs.Domains }))
+	r.Moderation = moderation.NewTenantRepository(perTenant(sets, func(rs Repositories) moderation.Repository { return rs.Moderation }))
+	r.Attachments = attachments.NewTenantRepository(perTenant(sets, func(rs Repositories) attachments.Repository { return rs.Attachments }))
+	r.Integrations = integrations.NewTenantRepository(perTenant(sets, func(rs Repositories) integrations.Repository { return rs.Integrations }))
+	r.APIKeys = automation.NewTenantRepository(perTenant(sets, func(rs Repositories) automation.Repository { return rs.APIKeys }))
+	r.Stats = admin.NewTenantRepository(perTenant(sets, func(rs Repositories) admin.Repository { return rs.Stats }))
+	r.Audit = audit.NewTenantRepository(perTenant(sets, func(rs Repositories) audit.Repository { return rs.Audit }))
+	r.Changelog = changelog.NewTenantRepository(perTenant(sets, func(rs Repositories) changelog.Repository { return rs.Changelog }))
+	r.Search = search.NewTenantRepository(perTenant(sets, func(rs Repositories) search.Repository { return rs.Search }))
+	r.Vectors = search.NewTenantVectorRepository(perTenant(sets, func(rs Repositories) search.VectorRepository { return rs.Vectors }))
+	r.ActivityPub = activitypub.NewTenantRepository(perTenant(sets, func(rs Repositories) activitypub.Repository { return rs.ActivityPub }))
+	r.Billing = billing.NewTenantRepository(perTenant(sets, func(rs Repositories) billing.Repository { return rs.Billing }))
+	r.Uploads = quota.NewTenantRepository(perTenant(sets, func(rs Repositories) quota.Repository { return rs.Uploads }))
+	r.Idempotency = idempotency.NewTenantRepository(perTenant(sets, func(rs Repositories) idempotency.Repository { return rs.Idempotency }))
+	r.Features = features.NewTenantRepository(perTenant(sets, func(rs Repositories) features.Repository { return rs.Features }))
+	r.AIUsage = ai.NewTenantRepository(perTenant(sets, func(rs Repositories) ai.UsageRepository { return rs.AIUsage }))
+	r.APIUsage = apiusage.NewTenantRepository(perTenant(sets, func(rs Repositories) apiusage.Repository { return rs.APIUsage }))
+	r.Scraping = scraping.NewTenantRepository(perTenant(sets, func(rs Repositories) scraping.Repository { return rs.Scraping }))
+	return nil
+}
+
+// withAudit wraps the repositories of user data so every change made through them is recorded in the audit log
+func (r *Repositories) withAudit() {
+	r.Users = auth.NewAuditedRepository(r.Users)
+	r.Profiles = profile.NewAuditedRepository(r.Profiles)
+	r.Experience = experience.NewAuditedRepository(r.Experience)
+	r.Qualifications = qualifications.NewAuditedRepository(r.Qualifications)
+	r.Certificates = certificates.NewAuditedRepository(r.Certificates)
+	r.Awards = awards.NewAuditedRepository(r.Awards)
+	r.Languages = languages.NewAuditedRepository(r.Languages)
+	r.Skills = skills.NewAuditedRepository(r.Skills)
+	r.Journals = journal.NewAuditedRepository(r.Journals)
+	r.Webhooks = webhooks.NewAuditedRepository(r.Webhooks)
+	r.Privacy = privacy.NewAuditedRepository(r.Privacy)
+	r.Shares = shares.NewAuditedRepository(r.Shares)
+	r.Recommendations = recommendations.NewAuditedRepository(r.Recommendations)
+}
+
+// Audited returns the repositories recording every change to user data in the audit log, which must be
+// configured first
+func (r Repositories) Audited() Repositories {
+	r.withAudit()
+	return r
+}
+
+// withSanitize wraps the repositories of user-supplied text so it is cleaned before being stored
+func (r *Repositories) withSanitize() {
+	r.Profiles = profile.NewSanitizedRepository(r.Profiles)
+	r.Experience = experience.NewSanitizedRepository(r.Experience)
+	r.Qualifications = qualifications.NewSanitizedRepository(r.Qualifications)
+	r.Certificates = certificates.NewSanitizedRepository(r.Certificates)
+	r.Awards = awards.NewSanitizedRepository(r.Awards)
+	r.Skills = skills.NewSanitizedRepository(r.Skills)
+	r.Journals = journal.NewSanitizedRepository(r.Journals)
+	r.Organizations = organizations.NewSanitizedRepository(r.Organizations)
+	r.Recommendations = recommendations.NewSanitizedRepository(r.Recommendations)
+	r.Inbox = inbox.NewSanitizedRepository(r.Inbox)
+}
+
+// Sanitized returns the repositories cleaning user-supplied text with the configured sanitize policies
+// before storing it
+func (r Repositories) Sanitized() Repositories {
+	r.withSanitize()
+	return r
+}
+
+// withDryRun wraps the repositories written by the routes supporting dry runs, so their writes are skipped
+// in one
+func (r *Repositories) withDryRun() {
+	r.Profiles = profile.NewDryRunRepository(r.Profiles)
+	r.Experience = experience.NewDryRunRepository(r.Experience)
+	r.Qualifications = qualifications.NewDryRunRepository(r.Qualifications)
+	r.Certificates = certificates.NewDryRunRepository(r.Certificates)
+	r.Awards = awards.NewDryRunRepository(r.Awards)
+	r.Languages = languages.NewDryRunRepository(r.Languages)
+	r.Skills = skills.NewDryRunRepository(r.Skills)
+	r.Journals = journal.NewDryRunRepository(r.Journals)
+}
+
+// DryRun returns the repositories skipping their writes in dry runs. It must wrap every other decorator, so
+// a dry run is neither audited nor sanitized.
+func (r Repositories) DryRun() Repositories {
+	r.withDryRun()
+	return r
+}
+
+// perTenant picks one repository out of each tenant's repositories
+func perTenant[R any](sets map[string]Repositories, pick func(Repositories) R) tenant.Set[R] {
+	set := tenant.Set[R]{}
+	for id, rs := range sets {
+		set[id] = pick(rs)
+	}
+	return set
+}
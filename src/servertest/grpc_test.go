@@ -0,0 +1,91 @@
+package servertest_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"profile-api/awards"
+	"profile-api/grpcapi"
+	"profile-api/grpcapi/profilev1"
+	"profile-api/languages"
+	"profile-api/profile"
+	"profile-api/recommendations"
+	"profile-api/servertest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestGRPCProfileAggregate(t *testing.T) {
+	srv := servertest.New()
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+	ctx := context.Background()
+	if err := srv.Repos.Profiles.Save(ctx, profile.Profile{UserID: alice.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Repos.Awards.Create(ctx, awards.Award{UserID: alice.ID, AwardID: "award", Title: "Best paper", Issuer: "Conference"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Repos.Languages.Create(ctx, languages.Language{UserID: alice.ID, LanguageID: "language", Name: "French", Proficiency: "B2"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range []string{recommendations.StatusApproved, recommendations.StatusPending} {
+		err := srv.Repos.Recommendations.Create(ctx, recommendations.Recommendation{
+			ID:          status,
+			UserID:      alice.ID,
+			AuthorName:  "Bob",
+			AuthorEmail: "bob@example.com",
+			Content:     "Great to work with",
+			Status:      status,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	aggregate, err := grpcClient(t, srv).GetProfile(ctx, &profilev1.GetProfileRequest{UserId: alice.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aggregate.GetAwards()) != 1 || aggregate.GetAwards()[0].GetTitle() != "Best paper" {
+		t.Errorf("got awards %v, want the one stored", aggregate.GetAwards())
+	}
+	if len(aggregate.GetLanguages()) != 1 || aggregate.GetLanguages()[0].GetName() != "French" {
+		t.Errorf("got languages %v, want the one stored", aggregate.GetLanguages())
+	}
+	if len(aggregate.GetRecommendations()) != 1 || aggregate.GetRecommendations()[0].GetRecommendationId() != recommendations.StatusApproved {
+		t.Errorf("got recommendations %v, want only the approved one", aggregate.GetRecommendations())
+	}
+}
+
+// grpcClient serves the gRPC API from the test server's storage until the test ends
+func grpcClient(t *testing.T, srv *servertest.Server) profilev1.ProfileServiceClient {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpc := grpcapi.NewServer(grpcapi.Repositories{
+		Profiles:        srv.Repos.Profiles,
+		Skills:          srv.Repos.Skills,
+		Experience:      srv.Repos.Experience,
+		Qualifications:  srv.Repos.Qualifications,
+		Awards:          srv.Repos.Awards,
+		Languages:       srv.Repos.Languages,
+		Recommendations: srv.Repos.Recommendations,
+		Journals:        srv.Repos.Journals,
+	}, srv.Repos.Users)
+	go rpc.Serve(listener)
+	t.Cleanup(rpc.Stop)
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return profilev1.NewProfileServiceClient(conn)
+}
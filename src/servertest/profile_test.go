@@ -0,0 +1,118 @@
+package servertest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"profile-api/config"
+	"profile-api/profile"
+	"profile-api/servertest"
+)
+
+func TestFullProfileShowsApprovedRecommendations(t *testing.T) {
+	srv := servertest.New()
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+	bob := signUp(t, srv, "Bob")
+	if err := srv.Repos.Profiles.Save(context.Background(), profile.Profile{UserID: alice.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	// recommendations reads the full profile, which is cached between the reads
+	recommendations := func() []map[string]any {
+		t.Helper()
+		resp := send(t, srv.Client(), http.MethodGet, srv.API("/profile/"+alice.ID+"/full"), nil, nil)
+		if resp.Status != http.StatusOK {
+			t.Fatalf("reading the full profile: got %d: %s", resp.Status, resp.Body)
+		}
+		var full struct {
+			Recommendations []map[string]any `json:"recommendations"`
+		}
+		if err := json.Unmarshal(resp.Body, &full); err != nil {
+			t.Fatal(err)
+		}
+		return full.Recommendations
+	}
+	if got := recommendations(); len(got) != 0 {
+		t.Fatalf("got %d recommendations before any was written", len(got))
+	}
+
+	written := send(t, bob.Client, http.MethodPost, srv.API("/recommendations/u/"+alice.ID), map[string]string{"relationship": "Worked together", "content": "Great to work with"}, nil)
+	if written.Status != http.StatusCreated {
+		t.Fatalf("writing a recommendation: got %d: %s", written.Status, written.Body)
+	}
+	var rec struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(written.Body, &rec); err != nil {
+		t.Fatal(err)
+	}
+	if got := recommendations(); len(got) != 0 {
+		t.Errorf("got %d recommendations before approval, want none", len(got))
+	}
+
+	approved := send(t, alice.Client, http.MethodPut, srv.API("/recommendations/"+rec.ID+"/status"), map[string]string{"status": "approved"}, nil)
+	if approved.Status != http.StatusOK {
+		t.Fatalf("approving the recommendation: got %d: %s", approved.Status, approved.Body)
+	}
+	if got := recommendations(); len(got) != 1 || got[0]["id"] != rec.ID {
+		t.Errorf("got recommendations %v after approval, want the approved one", got)
+	}
+
+	deleted := send(t, bob.Client, http.MethodDelete, srv.API("/recommendations/"+rec.ID), nil, nil)
+	if deleted.Status != http.StatusOK {
+		t.Fatalf("deleting the recommendation: got %d: %s", deleted.Status, deleted.Body)
+	}
+	if got := recommendations(); len(got) != 0 {
+		t.Errorf("got %d recommendations after deletion, want none", len(got))
+	}
+}
+
+func TestDisabledModulesAreLeftOut(t *testing.T) {
+	srv := servertest.New(func(cfg *config.Config) {
+		cfg.Modules.Experience = false
+		cfg.Modules.Recommendations = false
+		cfg.Modules.Resume = false
+	})
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+	if err := srv.Repos.Profiles.Save(context.Background(), profile.Profile{UserID: alice.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		method, url string
+	}{
+		{http.MethodGet, srv.API("/experience/" + alice.ID)},
+		{http.MethodGet, srv.APIv2("/experience/" + alice.ID)},
+		{http.MethodGet, srv.API("/recommendations/u/" + alice.ID)},
+		{http.MethodPost, srv.API("/profile/" + alice.ID + "/import/resume/confirm")},
+		{http.MethodPost, srv.API("/skills/" + alice.ID + "/suggestions")},
+	} {
+		if resp := send(t, alice.Client, tc.method, tc.url, map[string]any{}, nil); resp.Status != http.StatusNotFound {
+			t.Errorf("%s %s: got %d, want 404: %s", tc.method, tc.url, resp.Status, resp.Body)
+		}
+	}
+	if resp := send(t, alice.Client, http.MethodGet, srv.API("/skills/"+alice.ID), nil, nil); resp.Status != http.StatusOK {
+		t.Errorf("skills of an enabled module: got %d: %s", resp.Status, resp.Body)
+	}
+
+	resp := send(t, srv.Client(), http.MethodGet, srv.API("/profile/"+alice.ID+"/full"), nil, nil)
+	if resp.Status != http.StatusOK {
+		t.Fatalf("reading the full profile: got %d: %s", resp.Status, resp.Body)
+	}
+	var full map[string]any
+	if err := json.Unmarshal(resp.Body, &full); err != nil {
+		t.Fatal(err)
+	}
+	for _, section := range []string{"experience", "recommendations"} {
+		if _, ok := full[section]; ok {
+			t.Errorf("the full profile has the %s of a disabled module", section)
+		}
+	}
+	if _, ok := full["skills"]; !ok {
+		t.Error("the full profile is missing the skills of an enabled module")
+	}
+}
@@ -0,0 +1,685 @@
+package servertest_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"profile-api/audit"
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/email"
+	"profile-api/grpcapi/profilev1"
+	"profile-api/journal"
+	"profile-api/moderation"
+	"profile-api/profile"
+	"profile-api/recommendations"
+	"profile-api/servertest"
+	"profile-api/subscriptions"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const password = "correct horse battery"
+
+// response is what a request to the test server returned
+type response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// send makes a request with a JSON body, unless body is nil, and the headers
+func send(t *testing.T, client *http.Client, method, url string, body any, headers map[string]string) response {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	return do(t, client, req)
+}
+
+// do sends the request, reading the whole response
+func do(t *testing.T, client *http.Client, req *http.Request) response {
+	t.Helper()
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response{Status: resp.StatusCode, Header: resp.Header, Body: data}
+}
+
+// signUp registers a user, failing the test when it can't
+func signUp(t *testing.T, srv *servertest.Server, name string) *servertest.User {
+	t.Helper()
+	user, err := srv.SignUp(name, strings.ToLower(name)+"@example.com", password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// publish stores a public journal entry of the user
+func publish(t *testing.T, srv *servertest.Server, userID, journalID string) {
+	t.Helper()
+	write(t, srv, userID, journalID, journal.StatusPublic)
+}
+
+// write stores a journal entry of the user with the status
+func write(t *testing.T, srv *servertest.Server, userID, journalID, status string) {
+	t.Helper()
+	now := time.Now()
+	err := srv.Repos.Journals.Create(context.Background(), journal.JournalEntry{
+		JournalID: journalID,
+		UserID:    userID,
+		Version:   1,
+		Entries:   []journal.Entry{{Version: 1, Title: "Notes", Content: "Some notes", Attachments: []string{}, UpdatedAt: now}},
+		Status:    status,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWritesRequireOwner(t *testing.T) {
+	srv := servertest.New()
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+	bob := signUp(t, srv, "Bob")
+
+	award := map[string]string{"title": "Best paper", "issuer": "Conference"}
+	created := send(t, alice.Client, http.MethodPost, srv.API("/awards/"+alice.ID), award, nil)
+	if created.Status >= 300 {
+		t.Fatalf("owner creating an award: got %d: %s", created.Status, created.Body)
+	}
+
+	qualification := map[string]string{"title": "BSc", "institution": "University"}
+	for _, tc := range []struct {
+		method, path string
+		body         any
+	}{
+		{http.MethodPost, "/awards/" + alice.ID, award},
+		{http.MethodPut, "/awards/" + alice.ID + "/any", award},
+		{http.MethodDelete, "/awards/" + alice.ID + "/any", nil},
+		{http.MethodPost, "/languages/" + alice.ID, map[string]string{"name": "French", "proficiency": "B2"}},
+		{http.MethodDelete, "/languages/" + alice.ID + "/any", nil},
+		{http.MethodPost, "/skills/" + alice.ID, map[string]string{"name": "Go"}},
+		{http.MethodPost, "/experience/" + alice.ID, map[string]string{"company": "Acme", "position": "Engineer", "start": "2020-01"}},
+		{http.MethodPost, "/qualifications/" + alice.ID, qualification},
+		{http.MethodPost, "/certificates/" + alice.ID, qualification},
+		{http.MethodPut, "/profile/" + alice.ID + "/availability", map[string]string{"status": "open"}},
+		{http.MethodDelete, "/profile/" + alice.ID + "/availability", nil},
+		{http.MethodPost, "/profile/" + alice.ID + "/import/resume/confirm", map[string]any{"skills": []map[string]string{{"name": "Go"}}}},
+	} {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			resp := send(t, bob.Client, tc.method, srv.API(tc.path), tc.body, nil)
+			if resp.Status != http.StatusForbidden {
+				t.Errorf("another user: got %d, want %d: %s", resp.Status, http.StatusForbidden, resp.Body)
+			}
+			// A dry run must not tell another user whether the write would have succeeded
+			resp = send(t, bob.Client, tc.method, srv.API(tc.path), tc.body, map[string]string{"X-Dry-Run": "true"})
+			if resp.Status != http.StatusForbidden {
+				t.Errorf("another user's dry run: got %d, want %d: %s", resp.Status, http.StatusForbidden, resp.Body)
+			}
+		})
+	}
+
+	var stored struct {
+		AwardID string `json:"award_id"`
+	}
+	if err := json.Unmarshal(created.Body, &stored); err != nil {
+		t.Fatal(err)
+	}
+	resp := upload(t, bob.Client, http.MethodPut, srv.API("/awards/"+alice.ID+"/"+stored.AwardID+"/image"), "file", "medal.png", []byte("\x89PNG\r\n\x1a\n"))
+	if resp.Status != http.StatusForbidden {
+		t.Errorf("another user uploading an award image: got %d, want %d: %s", resp.Status, http.StatusForbidden, resp.Body)
+	}
+	resp = upload(t, bob.Client, http.MethodPost, srv.API("/profile/"+alice.ID+"/import/resume"), "file", "cv.pdf", []byte("%PDF-1.4"))
+	if resp.Status != http.StatusForbidden {
+		t.Errorf("another user importing a CV: got %d, want %d: %s", resp.Status, http.StatusForbidden, resp.Body)
+	}
+}
+
+// upload sends a file in a multipart form
+func upload(t *testing.T, client *http.Client, method, url, field, filename string, data []byte) response {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile(field, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := form.Close(); err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(method, url, &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return do(t, client, req)
+}
+
+func TestRestrictedProfileHidesJournal(t *testing.T) {
+	srv := servertest.New()
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+	publish(t, srv, alice.ID, "restricted-entry")
+
+	restrict(t, srv, alice)
+
+	if resp := send(t, srv.Client(), http.MethodGet, srv.API("/journal/restricted-entry"), nil, nil); resp.Status != http.StatusNotFound {
+		t.Errorf("anonymous read of the entry: got %d, want %d", resp.Status, http.StatusNotFound)
+	}
+	if resp := send(t, alice.Client, http.MethodGet, srv.API("/journal/restricted-entry"), nil, nil); resp.Status != http.StatusOK {
+		t.Errorf("owner's read of the entry: got %d, want %d: %s", resp.Status, http.StatusOK, resp.Body)
+	}
+	feed := send(t, srv.Client(), http.MethodGet, srv.API("/journal/"), nil, nil)
+	if feed.Status != http.StatusOK {
+		t.Fatalf("reading the public feed: got %d: %s", feed.Status, feed.Body)
+	}
+	if bytes.Contains(feed.Body, []byte("restricted-entry")) {
+		t.Errorf("public feed lists the entry of a restricted profile: %s", feed.Body)
+	}
+}
+
+func TestUnpublishedEntryIsOwnerOnly(t *testing.T) {
+	srv := servertest.New()
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+	bob := signUp(t, srv, "Bob")
+	write(t, srv, alice.ID, "draft-entry", journal.StatusPrivate)
+
+	for _, path := range []string{"/journal/draft-entry", "/journal/draft-entry/meta", "/journal/draft-entry/versions"} {
+		if resp := send(t, bob.Client, http.MethodGet, srv.API(path), nil, nil); resp.Status != http.StatusNotFound {
+			t.Errorf("another user reading %s: got %d, want %d: %s", path, resp.Status, http.StatusNotFound, resp.Body)
+		}
+		if resp := send(t, alice.Client, http.MethodGet, srv.API(path), nil, nil); resp.Status != http.StatusOK {
+			t.Errorf("owner reading %s: got %d, want %d: %s", path, resp.Status, http.StatusOK, resp.Body)
+		}
+	}
+	if resp := send(t, srv.Client(), http.MethodGet, srv.API("/journal/draft-entry"), nil, nil); resp.Status != http.StatusNotFound {
+		t.Errorf("anonymous read of the entry: got %d, want %d", resp.Status, http.StatusNotFound)
+	}
+}
+
+func TestHiddenProfileIsNotServed(t *testing.T) {
+	srv := servertest.New()
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+	publish(t, srv, alice.ID, "hidden-entry")
+	hide(t, srv, alice.ID)
+
+	if resp := send(t, srv.Client(), http.MethodGet, srv.API("/journal/hidden-entry"), nil, nil); resp.Status != http.StatusNotFound {
+		t.Errorf("anonymous read of the entry: got %d, want %d", resp.Status, http.StatusNotFound)
+	}
+	if resp := send(t, alice.Client, http.MethodGet, srv.API("/journal/hidden-entry"), nil, nil); resp.Status != http.StatusOK {
+		t.Errorf("owner's read of the entry: got %d, want %d: %s", resp.Status, http.StatusOK, resp.Body)
+	}
+	feed := send(t, srv.Client(), http.MethodGet, srv.API("/journal/"), nil, nil)
+	if feed.Status != http.StatusOK {
+		t.Fatalf("reading the public feed: got %d: %s", feed.Status, feed.Body)
+	}
+	if bytes.Contains(feed.Body, []byte("hidden-entry")) {
+		t.Errorf("public feed lists the entry of a hidden profile: %s", feed.Body)
+	}
+
+	query := map[string]string{"query": `{ journal(journalID: "hidden-entry") { journalID } journals { journalID } }`}
+	resp := send(t, srv.Client(), http.MethodPost, srv.URL+"/graphql", query, nil)
+	if resp.Status != http.StatusOK {
+		t.Fatalf("querying GraphQL: got %d: %s", resp.Status, resp.Body)
+	}
+	if bytes.Contains(resp.Body, []byte("hidden-entry")) {
+		t.Errorf("GraphQL serves the entry of a hidden profile: %s", resp.Body)
+	}
+}
+
+func TestHiddenProfileIsNotServedOverGRPC(t *testing.T) {
+	srv := servertest.New()
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+	publish(t, srv, alice.ID, "hidden-entry")
+	if err := srv.Repos.Profiles.Save(context.Background(), profile.Profile{UserID: alice.ID}); err != nil {
+		t.Fatal(err)
+	}
+	hide(t, srv, alice.ID)
+
+	client := grpcClient(t, srv)
+	ctx := context.Background()
+
+	if _, err := client.GetProfile(ctx, &profilev1.GetProfileRequest{UserId: alice.ID}); status.Code(err) != codes.NotFound {
+		t.Errorf("getting a hidden profile: got %v, want %s", err, codes.NotFound)
+	}
+	list, err := client.ListJournal(ctx, &profilev1.ListJournalRequest{UserId: alice.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.GetEntries()) > 0 {
+		t.Errorf("listing the journal of a hidden profile: got %d entries, want none", len(list.GetEntries()))
+	}
+	found, err := client.SearchJournal(ctx, &profilev1.SearchJournalRequest{Query: "notes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found.GetEntries()) > 0 {
+		t.Errorf("searching the journal of a hidden profile: got %d entries, want none", len(found.GetEntries()))
+	}
+}
+
+func TestDigestSkipsWithheldProfiles(t *testing.T) {
+	srv := servertest.New()
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+	bob := signUp(t, srv, "Bob")
+	carol := signUp(t, srv, "Carol")
+	ctx := context.Background()
+	for _, user := range []*servertest.User{alice, bob, carol} {
+		publish(t, srv, user.ID, "entry-of-"+user.ID)
+		err := srv.Repos.Subscriptions.Replace(ctx, subscriptions.Subscription{
+			SubscriptionID: "subscription-to-" + user.ID,
+			UserID:         user.ID,
+			Email:          "reader@example.com",
+			Frequency:      subscriptions.FrequencyDaily,
+			Confirmed:      true,
+			LastSentAt:     time.Now().Add(-48 * time.Hour),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	hide(t, srv, alice.ID)
+	restrict(t, srv, carol)
+
+	if err := subscriptions.SendDigests(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		user *servertest.User
+		sent bool
+	}{{alice, false}, {bob, true}, {carol, false}} {
+		sent, err := srv.Repos.EmailLog.List(ctx, tc.user.ID, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := slices.ContainsFunc(sent, func(entry email.LogEntry) bool { return entry.Template == "journal_digest" })
+		if digest != tc.sent {
+			t.Errorf("digest of %s's journal: got sent %t, want %t", tc.user.Email, digest, tc.sent)
+		}
+	}
+}
+
+func TestSubscriptionLinksWaitForTheVisitor(t *testing.T) {
+	srv := servertest.New()
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+	ctx := context.Background()
+	// Only the hashes of the link tokens are stored
+	hash := func(token string) string {
+		sum := sha256.Sum256([]byte(token))
+		return hex.EncodeToString(sum[:])
+	}
+	err := srv.Repos.Subscriptions.Replace(ctx, subscriptions.Subscription{
+		SubscriptionID:   "subscription",
+		UserID:           alice.ID,
+		Email:            "reader@example.com",
+		Frequency:        subscriptions.FrequencyDaily,
+		ConfirmTokenHash: hash("confirm-token"),
+		UnsubTokenHash:   hash("unsubscribe-token"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	confirmed := func() int {
+		t.Helper()
+		subs, err := srv.Repos.Subscriptions.ListConfirmed(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(subs)
+	}
+	// submit posts the form of a link's page, as a browser does
+	submit := func(path string) response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, srv.API(path), strings.NewReader(""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+		return do(t, srv.Client(), req)
+	}
+
+	for _, path := range []string{"/subscriptions/confirm/confirm-token", "/subscriptions/unsubscribe/unsubscribe-token"} {
+		page := send(t, srv.Client(), http.MethodGet, srv.API(path), nil, nil)
+		if page.Status != http.StatusOK || !strings.Contains(string(page.Body), `<form method="post">`) {
+			t.Fatalf("opening %s: got %d: %s", path, page.Status, page.Body)
+		}
+	}
+	if n := confirmed(); n != 0 {
+		t.Fatalf("opening the confirmation link confirmed %d subscriptions", n)
+	}
+
+	if resp := send(t, srv.Client(), http.MethodPost, srv.API("/subscriptions/confirm/"+hash("confirm-token")), nil, nil); resp.Status != http.StatusNotFound {
+		t.Errorf("confirming with the stored hash: got %d, want %d: %s", resp.Status, http.StatusNotFound, resp.Body)
+	}
+	if resp := submit("/subscriptions/confirm/confirm-token"); resp.Status != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Fatalf("submitting the confirmation page: got %d %s: %s", resp.Status, resp.Header.Get("Content-Type"), resp.Body)
+	}
+	if n := confirmed(); n != 1 {
+		t.Fatalf("got %d confirmed subscriptions after submitting the confirmation page, want 1", n)
+	}
+
+	if resp := send(t, srv.Client(), http.MethodPost, srv.API("/subscriptions/unsubscribe/unsubscribe-token"), nil, nil); resp.Status != http.StatusOK {
+		t.Fatalf("unsubscribing: got %d: %s", resp.Status, resp.Body)
+	}
+	if n := confirmed(); n != 0 {
+		t.Errorf("got %d confirmed subscriptions after unsubscribing, want none", n)
+	}
+}
+
+func TestSubscribeRefusesRestrictedProfiles(t *testing.T) {
+	srv := servertest.New()
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+	restrict(t, srv, alice)
+
+	resp := send(t, srv.Client(), http.MethodPost, srv.API("/subscriptions/"+alice.ID), map[string]string{"email": "reader@example.com"}, nil)
+	if resp.Status != http.StatusNotFound {
+		t.Errorf("subscribing to a restricted profile: got %d, want %d: %s", resp.Status, http.StatusNotFound, resp.Body)
+	}
+}
+
+// restrict restricts the user's profile to their allowlist
+func restrict(t *testing.T, srv *servertest.Server, user *servertest.User) {
+	t.Helper()
+	resp := send(t, user.Client, http.MethodPut, srv.API("/privacy/settings"), map[string]bool{"restricted": true}, nil)
+	if resp.Status != http.StatusOK {
+		t.Fatalf("restricting the profile: got %d: %s", resp.Status, resp.Body)
+	}
+}
+
+// hide hides the user's profile as a moderator would
+func hide(t *testing.T, srv *servertest.Server, userID string) {
+	t.Helper()
+	err := srv.Repos.Moderation.Hide(context.Background(), moderation.Hidden{
+		TargetType: moderation.TargetProfile,
+		TargetID:   userID,
+		OwnerID:    userID,
+		HiddenAt:   time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadOnlyRefusesTokenLinks(t *testing.T) {
+	srv := servertest.New(func(cfg *config.Config) {
+		cfg.ReadOnly.Enabled = true
+		cfg.Migrations.OnStart = "skip"
+	})
+	defer srv.Close()
+
+	for _, link := range []struct {
+		method, path string
+	}{
+		{http.MethodGet, "/privacy/access/some-token"},
+		{http.MethodPost, "/subscriptions/confirm/some-token"},
+		{http.MethodPost, "/subscriptions/unsubscribe/some-token"},
+		{http.MethodGet, "/shares/view/some-token"},
+		{http.MethodGet, "/shares/view/some-token/pdf"},
+	} {
+		resp := send(t, srv.Client(), link.method, srv.API(link.path), nil, nil)
+		if resp.Status != http.StatusMethodNotAllowed {
+			t.Errorf("following %s %s on a replica: got %d, want %d: %s", link.method, link.path, resp.Status, http.StatusMethodNotAllowed, resp.Body)
+		}
+	}
+	ctx := context.Background()
+	if err := srv.Repos.Users.Create(ctx, auth.User{ID: "alice", Name: "Alice", Email: "alice@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	err := srv.Repos.Recommendations.CreateInvitation(ctx, recommendations.Invitation{
+		ID:        "invitation",
+		UserID:    "alice",
+		Name:      "Bob",
+		Email:     "bob@example.com",
+		Token:     "some-token",
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := send(t, srv.Client(), http.MethodGet, srv.API("/recommendations/referee/some-token"), nil, nil)
+	if resp.Status != http.StatusOK {
+		t.Fatalf("reading a referee invitation on a replica: got %d: %s", resp.Status, resp.Body)
+	}
+	if cacheControl := resp.Header.Get("Cache-Control"); strings.Contains(cacheControl, "public") {
+		t.Errorf("reading a referee invitation on a replica: got Cache-Control %q, want it kept out of shared caches", cacheControl)
+	}
+}
+
+func TestBatchRefusesClientAddressHeaders(t *testing.T) {
+	srv := servertest.New(func(cfg *config.Config) {
+		cfg.ClientIPHeaders = []string{"X-Client-Address"}
+		cfg.TrustedPlatform = "cloudflare"
+		cfg.TrustedProxies = []string{"127.0.0.1"}
+	})
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+
+	for _, header := range []string{"X-Client-Address", "CF-Connecting-IP", "Authorization", "Cookie"} {
+		batch := map[string]any{"requests": []map[string]any{{
+			"method":  http.MethodGet,
+			"path":    "/api/v1/profile/" + alice.ID,
+			"headers": map[string]string{strings.ToLower(header): "203.0.113.7"},
+		}}}
+		resp := send(t, alice.Client, http.MethodPost, srv.API("/batch"), batch, nil)
+		if resp.Status != http.StatusBadRequest {
+			t.Errorf("batch setting %s: got %d, want %d: %s", header, resp.Status, http.StatusBadRequest, resp.Body)
+		}
+	}
+}
+
+func TestPlatformHeaderIsOnlyBelievedFromTrustedProxies(t *testing.T) {
+	for _, tc := range []struct {
+		proxies []string
+		want    string
+	}{
+		{[]string{"10.0.0.0/8"}, "127.0.0.1"},
+		{[]string{"127.0.0.1"}, "203.0.113.7"},
+	} {
+		srv := servertest.New(func(cfg *config.Config) {
+			cfg.TrustedPlatform = "cloudflare"
+			cfg.TrustedProxies = tc.proxies
+		})
+		register := map[string]string{"name": "Alice", "email": "alice@example.com", "password": password}
+		resp := send(t, srv.Client(), http.MethodPost, srv.API("/auth/register"), register, map[string]string{"CF-Connecting-IP": "203.0.113.7"})
+		if resp.Status != http.StatusCreated {
+			srv.Close()
+			t.Fatalf("registering: got %d: %s", resp.Status, resp.Body)
+		}
+		entries, err := srv.Repos.Audit.List(context.Background(), audit.Filter{Resource: "user"})
+		srv.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].ClientIP != tc.want {
+			t.Errorf("registering through proxies %v: got audit entries %+v, want one from %s", tc.proxies, entries, tc.want)
+		}
+	}
+}
+
+func TestBatchRequiresJSON(t *testing.T) {
+	// The batch checks its content type itself, for when requests are not validated against the OpenAPI document
+	srv := servertest.New(func(cfg *config.Config) {
+		cfg.OpenAPI.Validation = "off"
+	})
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+
+	// A form a page on another site submits is sent without a preflight, with the visitor's cookie
+	body := `{"requests":[{"method":"DELETE","path":"/api/v1/auth/account"}]}`
+	for _, contentType := range []string{"text/plain", "application/x-www-form-urlencoded", ""} {
+		req, err := http.NewRequest(http.MethodPost, srv.API("/batch"), strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if resp := do(t, alice.Client, req); resp.Status != http.StatusUnsupportedMediaType {
+			t.Errorf("batch sent as %q: got %d, want %d: %s", contentType, resp.Status, http.StatusUnsupportedMediaType, resp.Body)
+		}
+	}
+}
+
+func TestTokenCookieIsSameSite(t *testing.T) {
+	srv := servertest.New()
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+
+	resp := send(t, srv.Client(), http.MethodPost, srv.API("/auth/login"), map[string]string{"email": alice.Email, "password": password}, nil)
+	if resp.Status != http.StatusOK {
+		t.Fatalf("signing in: got %d: %s", resp.Status, resp.Body)
+	}
+	cookie := resp.Header.Get("Set-Cookie")
+	if !strings.Contains(cookie, "SameSite=Lax") {
+		t.Errorf("got Set-Cookie %q, want SameSite=Lax", cookie)
+	}
+	if strings.Contains(cookie, "Secure") {
+		t.Errorf("got Set-Cookie %q over plain HTTP, want it usable without TLS", cookie)
+	}
+
+	resp = send(t, srv.Client(), http.MethodPost, srv.API("/auth/login"), map[string]string{"email": alice.Email, "password": password}, map[string]string{"X-Forwarded-Proto": "https"})
+	if cookie := resp.Header.Get("Set-Cookie"); !strings.Contains(cookie, "Secure") {
+		t.Errorf("got Set-Cookie %q behind a TLS proxy, want Secure", cookie)
+	}
+}
+
+func TestCORSAddsToVary(t *testing.T) {
+	const origin = "https://app.example.com"
+	srv := servertest.New(func(cfg *config.Config) {
+		cfg.CORS.AllowedOrigins = []string{origin}
+	})
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+	publish(t, srv, alice.ID, "cors-entry")
+
+	resp := send(t, srv.Client(), http.MethodGet, srv.API("/journal/cors-entry"), nil, map[string]string{"Origin": origin})
+	if resp.Status != http.StatusOK {
+		t.Fatalf("reading the entry: got %d: %s", resp.Status, resp.Body)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != origin {
+		t.Errorf("Access-Control-Allow-Origin: got %q, want %q", got, origin)
+	}
+	var vary []string
+	for _, value := range resp.Header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			vary = append(vary, strings.TrimSpace(name))
+		}
+	}
+	for _, want := range []string{"Origin", "Cookie"} {
+		if !slices.Contains(vary, want) {
+			t.Errorf("Vary %v is missing %s", vary, want)
+		}
+	}
+}
+
+func TestImportRefusesPrivateAddresses(t *testing.T) {
+	var fetched atomic.Int32
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched.Add(1)
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("\x89PNG\r\n\x1a\n"))
+	}))
+	defer internal.Close()
+
+	srv := servertest.New()
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+
+	export := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<rss xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:wp="http://wordpress.org/export/1.2/">
+<channel><item>
+<title>Internal image</title>
+<content:encoded><![CDATA[<p><img src="%s/secret.png"></p>]]></content:encoded>
+<wp:status>publish</wp:status>
+<wp:post_type>post</wp:post_type>
+</item></channel>
+</rss>`, internal.URL)
+	resp := upload(t, alice.Client, http.MethodPost, srv.API("/journal/import"), "file", "export.xml", []byte(export))
+	if resp.Status != http.StatusOK {
+		t.Fatalf("importing: got %d: %s", resp.Status, resp.Body)
+	}
+	var results []journal.ImportResult
+	if err := json.Unmarshal(resp.Body, &results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Images != 0 || len(results[0].Errors) == 0 {
+		t.Errorf("import of an image on a private address: got %+v", results)
+	}
+	if n := fetched.Load(); n != 0 {
+		t.Errorf("the private address was fetched %d times", n)
+	}
+}
+
+func TestSubscribeRefusesUnknownUsers(t *testing.T) {
+	srv := servertest.New()
+	defer srv.Close()
+
+	resp := send(t, srv.Client(), http.MethodPost, srv.API("/subscriptions/nobody"), map[string]string{"email": "reader@example.com"}, nil)
+	if resp.Status != http.StatusNotFound {
+		t.Errorf("subscribing to an unknown user: got %d, want %d: %s", resp.Status, http.StatusNotFound, resp.Body)
+	}
+}
+
+func TestSubscribeIsRateLimited(t *testing.T) {
+	srv := servertest.New()
+	defer srv.Close()
+	alice := signUp(t, srv, "Alice")
+
+	var last response
+	for range 4 {
+		last = send(t, srv.Client(), http.MethodPost, srv.API("/subscriptions/"+alice.ID), map[string]string{"email": "target@example.com"}, nil)
+	}
+	if last.Status != http.StatusTooManyRequests {
+		t.Errorf("repeated subscriptions of one email: got %d, want %d: %s", last.Status, http.StatusTooManyRequests, last.Body)
+	}
+}
@@ -0,0 +1,200 @@
+// Package servertest runs the API on in-memory storage behind an httptest server, so services and
+// frontends depending on the API can run contract tests against the real handlers without MongoDB,
+// PostgreSQL or any other service:
+//
+//	srv := servertest.New()
+//	defer srv.Close()
+//	alice, err := srv.SignUp("Alice", "alice@example.com", "correct horse battery")
+//	resp, err := alice.Get(srv.API("/profile/" + alice.ID))
+//
+// The modules keep their settings and storage in package variables, as described in package server, so
+// only one test server may run at a time in a process; tests using it must not run in parallel.
+package servertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/jobs"
+	"profile-api/server"
+	"profile-api/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// shutdownTimeout bounds how long Close waits for background jobs to finish
+const shutdownTimeout = 5 * time.Second
+
+// Server is the API served from memory
+type Server struct {
+	*httptest.Server
+	// APIConfig is the configuration the API runs with
+	APIConfig *config.Config
+	// Repos is the storage behind the API, for seeding data and checking what requests stored. Writes made
+	// through it bypass the audit log, sanitizing and caching the handlers go through.
+	Repos server.Repositories
+
+	deps      *server.Deps
+	imagePath string
+	cancel    context.CancelFunc
+}
+
+// Option changes the configuration of the test server, such as turning on optional modules
+type Option func(cfg *config.Config)
+
+// User is a registered user signed in to the test server
+type User struct {
+	// Client sends the user's session cookie with every request
+	*http.Client
+	ID    string
+	Email string
+}
+
+// New starts the API on in-memory storage, with the default configuration changed by the options. Like
+// httptest.NewServer it panics when the server cannot start, and must be closed once the test is done.
+func New(opts ...Option) *Server {
+	srv, err := start(opts)
+	if err != nil {
+		panic("servertest: " + err.Error())
+	}
+	return srv
+}
+
+func start(opts []Option) (*Server, error) {
+	gin.SetMode(gin.TestMode)
+	imagePath, err := os.MkdirTemp("", "profile-api-images-")
+	if err != nil {
+		return nil, err
+	}
+	srv := &Server{Server: httptest.NewUnstartedServer(nil), imagePath: imagePath}
+
+	cfg := config.Default()
+	cfg.Storage = store.Memory
+	cfg.PublicBaseURL = "http://" + srv.Listener.Addr().String()
+	cfg.JWT.Secret = "servertest"
+	cfg.ImageStore.LocalPath = imagePath
+	// Variants need the encoders installed, which test machines may lack
+	cfg.ImageStore.Variants.Formats = nil
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		os.RemoveAll(imagePath)
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	srv.APIConfig = cfg
+
+	ctx, cancel := context.WithCancel(context.Background())
+	srv.cancel = cancel
+	if srv.deps, err = server.Open(ctx, cfg); err != nil {
+		srv.cleanup()
+		return nil, err
+	}
+	srv.Repos = srv.deps.Repos
+	router, err := server.New(cfg, srv.deps)
+	if err != nil {
+		srv.cleanup()
+		return nil, err
+	}
+	if err := server.StartBackground(ctx, cfg, srv.deps); err != nil {
+		srv.cleanup()
+		return nil, err
+	}
+
+	srv.Config.Handler = router
+	srv.Start()
+	return srv, nil
+}
+
+// API returns the URL of a path of the version 1 API, such as /profile/{userid}
+func (s *Server) API(path string) string {
+	return s.URL + "/api/v1" + path
+}
+
+// APIv2 returns the URL of a path of the version 2 API
+func (s *Server) APIv2(path string) string {
+	return s.URL + "/api/v2" + path
+}
+
+// Close stops the server and its background jobs, dropping everything it stored
+func (s *Server) Close() {
+	s.Server.Close()
+	s.cleanup()
+}
+
+func (s *Server) cleanup() {
+	s.cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	jobs.Wait(ctx)
+	if s.deps != nil {
+		s.deps.Close(ctx)
+	}
+	os.RemoveAll(s.imagePath)
+}
+
+// SignUp registers a user and signs them in
+func (s *Server) SignUp(name, email, password string) (*User, error) {
+	if _, err := s.post("/auth/register", nil, map[string]string{"name": name, "email": email, "password": password}); err != nil {
+		return nil, fmt.Errorf("registering %s: %w", email, err)
+	}
+	return s.SignIn(email, password)
+}
+
+// SignIn signs in a registered user
+func (s *Server) SignIn(email, password string) (*User, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Transport: s.Client().Transport, Jar: jar}
+
+	body, err := s.post("/auth/login", client, map[string]string{"email": email, "password": password})
+	if err != nil {
+		return nil, fmt.Errorf("signing in %s: %w", email, err)
+	}
+	var login struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(body, &login); err != nil {
+		return nil, fmt.Errorf("signing in %s: %w", email, err)
+	}
+	user, err := auth.Authenticate(context.Background(), s.Repos.Users, login.Token)
+	if err != nil {
+		return nil, fmt.Errorf("signing in %s: %w", email, err)
+	}
+	return &User{Client: client, ID: user.ID, Email: email}, nil
+}
+
+// post sends a JSON body to a path of the API, failing unless it succeeds
+func (s *Server) post(path string, client *http.Client, body any) ([]byte, error) {
+	if client == nil {
+		client = s.Client()
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Post(s.API(path), "application/json", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s: %s", resp.Status, buf.String())
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,95 @@
+package shares
+
+import (
+	"time"
+
+	"profile-api/certificates"
+	"profile-api/experience"
+	"profile-api/profile"
+	"profile-api/qualifications"
+)
+
+// View kinds, the part of a package that was viewed
+const (
+	ViewBundle = "bundle"
+	ViewPDF    = "pdf"
+)
+
+// Package bundles a user's profile with the CV records they chose, shared with a recruiter through a
+// read-only link until it expires or is revoked
+type Package struct {
+	ID     string `bson:"_id" json:"id"`
+	UserID string `bson:"user_id" json:"userID"`
+	// Recipient names the recruiter the package was made for
+	Recipient string `bson:"recipient" json:"recipient"`
+	// Message is shown to the recipient with the package
+	Message        string   `bson:"message" json:"message"`
+	Experience     []string `bson:"experience" json:"experience"`
+	Qualifications []string `bson:"qualifications" json:"qualifications"`
+	Certificates   []string `bson:"certificates" json:"certificates"`
+	// PDF offers the recipient the package as a PDF too
+	PDF bool `bson:"pdf" json:"pdf"`
+	// TokenHash is the SHA-256 of the token in the link, which is itself only shown when the package is created
+	TokenHash    string     `bson:"token_hash" json:"-"`
+	CreatedAt    time.Time  `bson:"created_at" json:"createdAt"`
+	ExpiresAt    time.Time  `bson:"expires_at" json:"expiresAt"`
+	RevokedAt    *time.Time `bson:"revoked_at,omitempty" json:"revokedAt,omitempty"`
+	Views        int        `bson:"views" json:"views"`
+	LastViewedAt *time.Time `bson:"last_viewed_at,omitempty" json:"lastViewedAt,omitempty"`
+}
+
+// Active reports whether the package's link still works at the time
+func (p Package) Active(at time.Time) bool {
+	return p.RevokedAt == nil && at.Before(p.ExpiresAt)
+}
+
+// View records a recipient opening a package
+type View struct {
+	ID        string `bson:"_id" json:"id"`
+	PackageID string `bson:"package_id" json:"packageID"`
+	UserID    string `bson:"user_id" json:"-"`
+	// Kind is what was viewed: the bundle or the PDF
+	Kind      string    `bson:"kind" json:"kind"`
+	UserAgent string    `bson:"user_agent" json:"userAgent"`
+	ViewedAt  time.Time `bson:"viewed_at" json:"viewedAt"`
+}
+
+// PackageRequest represents the request body for creating a share package
+type PackageRequest struct {
+	Recipient      string   `json:"recipient" binding:"required,notblank,max=200"`
+	Message        string   `json:"message" binding:"max=2000"`
+	Experience     []string `json:"experience" binding:"max=100,dive,notblank,max=100"`
+	Qualifications []string `json:"qualifications" binding:"max=100,dive,notblank,max=100"`
+	Certificates   []string `json:"certificates" binding:"max=100,dive,notblank,max=100"`
+	PDF            bool     `json:"pdf"`
+	// ExpiresInDays is how long the link works, or the configured default when 0
+	ExpiresInDays int `json:"expiresInDays" binding:"omitempty,min=1"`
+}
+
+// CreatedPackage is returned when a package is created, the only time its link is shown
+type CreatedPackage struct {
+	Package
+	URL string `json:"url"`
+}
+
+// Bundle is a package as its recipient sees it, each document without the fields its visibility keeps from
+// signed in members
+type Bundle struct {
+	Recipient      string           `json:"recipient"`
+	Message        string           `json:"message"`
+	ExpiresAt      time.Time        `json:"expiresAt"`
+	Profile        map[string]any   `json:"profile"`
+	Experience     []map[string]any `json:"experience"`
+	Qualifications []map[string]any `json:"qualifications"`
+	Certificates   []map[string]any `json:"certificates"`
+	// PDFURL is where the package may be downloaded as a PDF, when it is offered
+	PDFURL string `json:"pdfURL,omitempty"`
+}
+
+// contents are the documents of a package, as they are now
+type contents struct {
+	profile        profile.Profile
+	experience     []experience.Experience
+	qualifications []qualifications.Qualification
+	certificates   []certificates.Certificate
+}
@@ -0,0 +1,139 @@
+package shares
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 pages, in points
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	pageMargin = 50.0
+)
+
+// pdfDocument lays out lines of text on A4 pages in the standard Helvetica fonts, which every PDF reader
+// has, so a plain printable package needs no PDF library
+type pdfDocument struct {
+	pages []*bytes.Buffer
+	// y is where the baseline of the next line goes on the last page
+	y float64
+}
+
+// text writes the text in the font size, wrapping it at the margins and onto new pages. Each line of the
+// text starts a new line.
+func (d *pdfDocument) text(s string, size float64, bold bool) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	// Helvetica averages about half its size per character
+	width := int((pageWidth - 2*pageMargin) / (size * 0.52))
+	for _, paragraph := range strings.Split(s, "\n") {
+		for _, line := range wrap(paragraph, width) {
+			d.advance(size * 1.35)
+			fmt.Fprintf(d.pages[len(d.pages)-1], "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, size, pageMargin, d.y, pdfString(line))
+		}
+	}
+}
+
+// gap leaves space before the next line
+func (d *pdfDocument) gap(height float64) {
+	d.advance(height)
+}
+
+// advance moves down by the height, starting a new page when the line would not fit on the last one
+func (d *pdfDocument) advance(height float64) {
+	if len(d.pages) == 0 || d.y-height < pageMargin {
+		d.pages = append(d.pages, &bytes.Buffer{})
+		d.y = pageHeight - pageMargin
+	}
+	d.y -= height
+}
+
+// bytes returns the document as a PDF file
+func (d *pdfDocument) bytes() []byte {
+	if len(d.pages) == 0 {
+		d.advance(0)
+	}
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+	// Objects 1 to 4 are the catalog, the page tree and the fonts, followed by each page and its content
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// wrap breaks the text into lines of at most width characters, between words where it can
+func wrap(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	var lines []string
+	line := ""
+	for _, word := range words {
+		for len([]rune(word)) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case len([]rune(line))+1+len([]rune(word)) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	return append(lines, line)
+}
+
+// pdfString escapes the text for a PDF string in WinAnsiEncoding, replacing the characters it lacks
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || (r >= 0x7f && r < 0xa0) || r > 0xff:
+			b.WriteByte('?')
+		default:
+			// Latin-1 characters have the same codes in WinAnsiEncoding
+			b.WriteByte(byte(r))
+		}
+	}
+	return b.String()
+}
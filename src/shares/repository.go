@@ -0,0 +1,24 @@
+package shares
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores share packages and their views
+type Repository interface {
+	// Create stores a new package
+	Create(ctx context.Context, p Package) error
+	// List returns the user's packages, newest first
+	List(ctx context.Context, userID string) ([]Package, error)
+	// Get returns one of the user's packages, or store.ErrNotFound
+	Get(ctx context.Context, userID, packageID string) (Package, error)
+	// GetByToken returns the package whose link token has the hash, or store.ErrNotFound
+	GetByToken(ctx context.Context, tokenHash string) (Package, error)
+	// Revoke stops the link of one of the user's packages from working, or returns store.ErrNotFound
+	Revoke(ctx context.Context, userID, packageID string, at time.Time) error
+	// RecordView stores a view of a package and counts it on the package
+	RecordView(ctx context.Context, view View) error
+	// ListViews returns the views of one of the user's packages, newest first
+	ListViews(ctx context.Context, userID, packageID string) ([]View, error)
+}
@@ -0,0 +1,34 @@
+package shares
+
+import (
+	"context"
+	"time"
+
+	"profile-api/audit"
+)
+
+// AuditedRepository records the creation and revocation of share packages in the audit log
+type AuditedRepository struct {
+	Repository
+}
+
+// NewAuditedRepository wraps the repository to record its changes in the audit log
+func NewAuditedRepository(r Repository) *AuditedRepository {
+	return &AuditedRepository{Repository: r}
+}
+
+func (r *AuditedRepository) Create(ctx context.Context, p Package) error {
+	if err := r.Repository.Create(ctx, p); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionCreate, "share_package", p.UserID, p.ID, nil, p)
+	return nil
+}
+
+func (r *AuditedRepository) Revoke(ctx context.Context, userID, packageID string, at time.Time) error {
+	if err := r.Repository.Revoke(ctx, userID, packageID, at); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionUpdate, "share_package", userID, packageID, nil, map[string]time.Time{"revoked_at": at})
+	return nil
+}
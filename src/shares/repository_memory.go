@@ -0,0 +1,115 @@
+package shares
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps share packages and their views in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	packages []Package
+	views    []View
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+// index returns the position of one of the user's packages, or -1. The caller must hold the lock.
+func (r *MemoryRepository) index(userID, packageID string) int {
+	return slices.IndexFunc(r.packages, func(p Package) bool { return p.ID == packageID && p.UserID == userID })
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, p Package) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.ContainsFunc(r.packages, func(existing Package) bool { return existing.ID == p.ID }) {
+		return store.ErrConflict
+	}
+	r.packages = append(r.packages, clonePackage(p))
+	return nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string) ([]Package, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []Package
+	for i := len(r.packages) - 1; i >= 0; i-- {
+		if r.packages[i].UserID == userID {
+			list = append(list, clonePackage(r.packages[i]))
+		}
+	}
+	return list, nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, userID, packageID string) (Package, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.index(userID, packageID)
+	if i < 0 {
+		return Package{}, store.ErrNotFound
+	}
+	return clonePackage(r.packages[i]), nil
+}
+
+func (r *MemoryRepository) GetByToken(ctx context.Context, tokenHash string) (Package, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := slices.IndexFunc(r.packages, func(p Package) bool { return p.TokenHash == tokenHash })
+	if i < 0 {
+		return Package{}, store.ErrNotFound
+	}
+	return clonePackage(r.packages[i]), nil
+}
+
+func (r *MemoryRepository) Revoke(ctx context.Context, userID, packageID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, packageID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	if r.packages[i].RevokedAt == nil {
+		r.packages[i].RevokedAt = &at
+	}
+	return nil
+}
+
+func (r *MemoryRepository) RecordView(ctx context.Context, view View) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(view.UserID, view.PackageID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.views = append(r.views, view)
+	r.packages[i].Views++
+	r.packages[i].LastViewedAt = &view.ViewedAt
+	return nil
+}
+
+func (r *MemoryRepository) ListViews(ctx context.Context, userID, packageID string) ([]View, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []View
+	for i := len(r.views) - 1; i >= 0; i-- {
+		if r.views[i].UserID == userID && r.views[i].PackageID == packageID {
+			list = append(list, r.views[i])
+		}
+	}
+	return list, nil
+}
+
+// clonePackage copies a package so callers cannot modify the stored copy through its slices
+func clonePackage(p Package) Package {
+	p.Experience = slices.Clone(p.Experience)
+	p.Qualifications = slices.Clone(p.Qualifications)
+	p.Certificates = slices.Clone(p.Certificates)
+	return p
+}
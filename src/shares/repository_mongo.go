@@ -0,0 +1,96 @@
+package shares
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores share packages in the share_packages collection and their views in share_views
+type MongoRepository struct {
+	packages *mongo.Collection
+	views    *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{packages: db.Collection("share_packages"), views: db.Collection("share_views")}
+}
+
+func (r *MongoRepository) Create(ctx context.Context, p Package) error {
+	_, err := r.packages.InsertOne(ctx, p)
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	}
+	return err
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string) ([]Package, error) {
+	cursor, err := r.packages.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	var list []Package
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *MongoRepository) Get(ctx context.Context, userID, packageID string) (Package, error) {
+	var p Package
+	err := r.packages.FindOne(ctx, bson.M{"_id": packageID, "user_id": userID}).Decode(&p)
+	return p, store.MongoErr(err)
+}
+
+func (r *MongoRepository) GetByToken(ctx context.Context, tokenHash string) (Package, error) {
+	var p Package
+	err := r.packages.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&p)
+	return p, store.MongoErr(err)
+}
+
+func (r *MongoRepository) Revoke(ctx context.Context, userID, packageID string, at time.Time) error {
+	_, err := r.packages.UpdateOne(ctx, bson.M{"_id": packageID, "user_id": userID, "revoked_at": nil}, bson.M{"$set": bson.M{"revoked_at": at}})
+	if err != nil {
+		return err
+	}
+	// A package revoked before is left as it was
+	n, err := r.packages.CountDocuments(ctx, bson.M{"_id": packageID, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) RecordView(ctx context.Context, view View) error {
+	res, err := r.packages.UpdateOne(ctx, bson.M{"_id": view.PackageID, "user_id": view.UserID},
+		bson.M{"$inc": bson.M{"views": 1}, "$set": bson.M{"last_viewed_at": view.ViewedAt}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	_, err = r.views.InsertOne(ctx, view)
+	return err
+}
+
+func (r *MongoRepository) ListViews(ctx context.Context, userID, packageID string) ([]View, error) {
+	cursor, err := r.views.Find(ctx, bson.M{"package_id": packageID, "user_id": userID}, options.Find().SetSort(bson.D{{Key: "viewed_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	var list []View
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,104 @@
+package shares
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const packageColumns = "id, user_id, recipient, message, experience, qualifications, certificates, pdf, token_hash, created_at, expires_at, revoked_at, views, last_viewed_at"
+
+const viewColumns = "id, package_id, user_id, kind, user_agent, viewed_at"
+
+// PostgresRepository stores share packages in the share_packages table and their views in share_views
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, p Package) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO share_packages ("+packageColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)",
+		p.ID, p.UserID, p.Recipient, p.Message, p.Experience, p.Qualifications, p.Certificates, p.PDF, p.TokenHash, p.CreatedAt, p.ExpiresAt, p.RevokedAt, p.Views, p.LastViewedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string) ([]Package, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+packageColumns+" FROM share_packages WHERE user_id = $1 ORDER BY created_at DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanPackage)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, userID, packageID string) (Package, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+packageColumns+" FROM share_packages WHERE id = $1 AND user_id = $2", packageID, userID)
+	if err != nil {
+		return Package{}, err
+	}
+	p, err := pgx.CollectExactlyOneRow(rows, scanPackage)
+	return p, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) GetByToken(ctx context.Context, tokenHash string) (Package, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+packageColumns+" FROM share_packages WHERE token_hash = $1", tokenHash)
+	if err != nil {
+		return Package{}, err
+	}
+	p, err := pgx.CollectExactlyOneRow(rows, scanPackage)
+	return p, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Revoke(ctx context.Context, userID, packageID string, at time.Time) error {
+	// A package revoked before is left as it was
+	tag, err := r.pool.Exec(ctx, "UPDATE share_packages SET revoked_at = COALESCE(revoked_at, $3) WHERE id = $1 AND user_id = $2", packageID, userID, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) RecordView(ctx context.Context, view View) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, "UPDATE share_packages SET views = views + 1, last_viewed_at = $3 WHERE id = $1 AND user_id = $2",
+			view.PackageID, view.UserID, view.ViewedAt)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return store.ErrNotFound
+		}
+		_, err = tx.Exec(ctx, "INSERT INTO share_views ("+viewColumns+") VALUES ($1, $2, $3, $4, $5, $6)",
+			view.ID, view.PackageID, view.UserID, view.Kind, view.UserAgent, view.ViewedAt)
+		return err
+	})
+}
+
+func (r *PostgresRepository) ListViews(ctx context.Context, userID, packageID string) ([]View, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+viewColumns+" FROM share_views WHERE package_id = $1 AND user_id = $2 ORDER BY viewed_at DESC", packageID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (View, error) {
+		var v View
+		err := row.Scan(&v.ID, &v.PackageID, &v.UserID, &v.Kind, &v.UserAgent, &v.ViewedAt)
+		return v, err
+	})
+}
+
+func scanPackage(row pgx.CollectableRow) (Package, error) {
+	var p Package
+	err := row.Scan(&p.ID, &p.UserID, &p.Recipient, &p.Message, &p.Experience, &p.Qualifications, &p.Certificates, &p.PDF, &p.TokenHash,
+		&p.CreatedAt, &p.ExpiresAt, &p.RevokedAt, &p.Views, &p.LastViewedAt)
+	return p, err
+}
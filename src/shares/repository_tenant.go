@@ -0,0 +1,46 @@
+package shares
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, p Package) error {
+	return r.repos.For(ctx).Create(ctx, p)
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID string) ([]Package, error) {
+	return r.repos.For(ctx).List(ctx, userID)
+}
+
+func (r *TenantRepository) Get(ctx context.Context, userID, packageID string) (Package, error) {
+	return r.repos.For(ctx).Get(ctx, userID, packageID)
+}
+
+func (r *TenantRepository) GetByToken(ctx context.Context, tokenHash string) (Package, error) {
+	return r.repos.For(ctx).GetByToken(ctx, tokenHash)
+}
+
+func (r *TenantRepository) Revoke(ctx context.Context, userID, packageID string, at time.Time) error {
+	return r.repos.For(ctx).Revoke(ctx, userID, packageID, at)
+}
+
+func (r *TenantRepository) RecordView(ctx context.Context, view View) error {
+	return r.repos.For(ctx).RecordView(ctx, view)
+}
+
+func (r *TenantRepository) ListViews(ctx context.Context, userID, packageID string) ([]View, error) {
+	return r.repos.For(ctx).ListViews(ctx, userID, packageID)
+}
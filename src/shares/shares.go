@@ -0,0 +1,566 @@
+// Package shares lets users share a package with a recruiter: their profile with the experience,
+// qualifications and certificates they pick, and optionally a PDF of them, behind a read-only link that works
+// until it expires or the user revokes it. The documents are read when the link is followed, so the
+// recipient sees them as they are then, and each view is recorded for the user to see.
+package shares
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/certificates"
+	"profile-api/config"
+	"profile-api/experience"
+	"profile-api/logging"
+	"profile-api/moderation"
+	"profile-api/profile"
+	"profile-api/qualifications"
+	"profile-api/readonly"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+	"profile-api/visibility"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxUserAgent bounds the user agent recorded with a view
+const maxUserAgent = 500
+
+// Sources are the repositories of the documents packages bundle
+type Sources struct {
+	Users          auth.Repository
+	Profiles       profile.Repository
+	Experience     experience.Repository
+	Qualifications qualifications.Repository
+	// Certificates is nil when the certificates module is disabled
+	Certificates certificates.Repository
+}
+
+var repo Repository
+var sources Sources
+var settings config.SharesConfig
+
+var publicBaseURL = "http://localhost:8080"
+
+// Configure sets where packages and the documents they bundle are stored, and how long their links work
+func Configure(r Repository, s Sources, cfg config.SharesConfig) {
+	repo = r
+	sources = s
+	settings = cfg
+}
+
+// SetBaseURL sets the public base URL of the links to packages
+func SetBaseURL(url string) {
+	publicBaseURL = strings.TrimSuffix(url, "/")
+}
+
+// baseURL returns the public base URL of the links to packages, which tenants may override
+func baseURL(ctx context.Context) string {
+	if t, ok := tenant.Current(ctx); ok && t.PublicBaseURL != "" {
+		return strings.TrimSuffix(t.PublicBaseURL, "/")
+	}
+	return publicBaseURL
+}
+
+// hashToken returns the hash a link token is stored and looked up by
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// viewerOf returns who the recipient of the package is shown its documents as: a signed in member, never
+// their owner
+func viewerOf(p Package) visibility.Viewer {
+	return visibility.Viewer{UserID: "share:" + p.ID}
+}
+
+// checkDocuments returns an error to respond with when the user has no such experience, qualification or
+// certificate as the request names
+func checkDocuments(ctx context.Context, userID string, req PackageRequest) error {
+	if sources.Certificates == nil && len(req.Certificates) > 0 {
+		return apierror.BadRequest("Certificates are not enabled on this server")
+	}
+	checks := []struct {
+		kind string
+		ids  []string
+		get  func(ctx context.Context, id string) error
+	}{
+		{"experience", req.Experience, func(ctx context.Context, id string) error {
+			_, err := sources.Experience.Get(ctx, userID, id)
+			return err
+		}},
+		{"qualification", req.Qualifications, func(ctx context.Context, id string) error {
+			_, err := sources.Qualifications.Get(ctx, userID, id)
+			return err
+		}},
+		{"certificate", req.Certificates, func(ctx context.Context, id string) error {
+			_, err := sources.Certificates.Get(ctx, userID, id)
+			return err
+		}},
+	}
+	for _, check := range checks {
+		for _, id := range check.ids {
+			err := check.get(ctx, id)
+			if errors.Is(err, store.ErrNotFound) {
+				return apierror.BadRequest(fmt.Sprintf("You have no %s %s", check.kind, id))
+			}
+			if err != nil {
+				return apierror.Wrap(err, "Could not check the documents to share")
+			}
+		}
+	}
+	return nil
+}
+
+// CreatePackage shares a package with a recruiter
+//
+//	@Summary		Create a share package
+//	@Description	Bundles the current user's profile with the experience, qualifications and certificates named, and optionally a PDF of them, behind a read-only link for a recruiter. The link is only returned now. It works for the days given, or the configured default, until it expires or is revoked. The recipient sees the documents as they are when they follow the link, as a signed in member would, even while the profile is restricted to its allowlist.
+//	@Tags			shares
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			package	body		PackageRequest	true	"The recipient and the documents to share"
+//	@Success		201		{object}	CreatedPackage
+//	@Failure		400		{object}	apierror.Response	"Invalid request body, a document the user does not have, or a link lasting too long"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		413		{object}	apierror.Response	"The user has too many packages whose link works"
+//	@Failure		500		{object}	apierror.Response	"Could not create package"
+//	@Router			/shares [post]
+func CreatePackage(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req PackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	ttl := settings.DefaultTTL.Std()
+	if req.ExpiresInDays > 0 {
+		ttl = time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	}
+	if ttl > settings.MaxTTL.Std() {
+		apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("expiresInDays may be at most %d", int(settings.MaxTTL.Std().Hours()/24))))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	now := time.Now()
+	existing, err := repo.List(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create package"))
+		return
+	}
+	active := 0
+	for _, p := range existing {
+		if p.Active(now) {
+			active++
+		}
+	}
+	if active >= settings.MaxActive {
+		apierror.Abort(c, apierror.QuotaExceeded(fmt.Sprintf("You may have at most %d packages whose link works, revoke one first", settings.MaxActive)))
+		return
+	}
+	if err := checkDocuments(ctx, user.ID, req); err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create package"))
+		return
+	}
+	token := hex.EncodeToString(random)
+	p := Package{
+		ID:             utils.GenerateID(),
+		UserID:         user.ID,
+		Recipient:      req.Recipient,
+		Message:        req.Message,
+		Experience:     append([]string{}, req.Experience...),
+		Qualifications: append([]string{}, req.Qualifications...),
+		Certificates:   append([]string{}, req.Certificates...),
+		PDF:            req.PDF,
+		TokenHash:      hashToken(token),
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(ttl),
+	}
+	if err := repo.Create(ctx, p); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create package"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreatedPackage{Package: p, URL: fmt.Sprintf("%s/api/v1/shares/view/%s", baseURL(ctx), token)})
+}
+
+// ListPackages lists the current user's packages
+//
+//	@Summary		List share packages
+//	@Description	Lists the current user's packages, newest first, with how often and when each was last viewed. Expired and revoked packages are listed too.
+//	@Tags			shares
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		Package
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve packages"
+//	@Router			/shares [get]
+func ListPackages(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	list, err := repo.List(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve packages"))
+		return
+	}
+	if list == nil {
+		list = []Package{}
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// ListPackageViews lists the views of one of the current user's packages
+//
+//	@Summary		List the views of a share package
+//	@Description	Lists each time the recipient opened one of the current user's packages or downloaded its PDF, newest first
+//	@Tags			shares
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			shareid	path		string	true	"Package ID"
+//	@Success		200		{array}		View
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		404		{object}	apierror.Response	"Package not found"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve views"
+//	@Router			/shares/{shareid}/views [get]
+func ListPackageViews(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+	packageID := c.Param("shareid")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if _, err := repo.Get(ctx, user.ID, packageID); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Package not found"))
+		return
+	}
+	list, err := repo.ListViews(ctx, user.ID, packageID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve views"))
+		return
+	}
+	if list == nil {
+		list = []View{}
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// RevokePackage stops the link of one of the current user's packages from working
+//
+//	@Summary		Revoke a share package
+//	@Description	Stops the link of one of the current user's packages from working straight away. The package stays listed with its views.
+//	@Tags			shares
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			shareid	path		string	true	"Package ID"
+//	@Success		200		{object}	map[string]string
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		404		{object}	apierror.Response	"Package not found"
+//	@Failure		500		{object}	apierror.Response	"Could not revoke package"
+//	@Router			/shares/{shareid} [delete]
+func RevokePackage(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	err := repo.Revoke(ctx, user.ID, c.Param("shareid"), time.Now())
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Package not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not revoke package"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Package revoked"})
+}
+
+// ViewPackage shows a package to its recipient
+//
+//	@Summary		View a share package
+//	@Description	Returns the package the link was made for: the profile and the documents chosen, without the fields their visibility keeps from signed in members, and where to download its PDF when one is offered. Documents deleted since the package was made are left out. Each view is recorded for the package's owner.
+//	@Tags			shares
+//	@Produce		json
+//	@Param			token	path		string	true	"Token from the package's link"
+//	@Success		200		{object}	Bundle
+//	@Failure		404		{object}	apierror.Response	"Link not found, expired or revoked"
+//	@Failure		500		{object}	apierror.Response	"Could not open package"
+//	@Router			/shares/view/{token} [get]
+func ViewPackage(c *gin.Context) {
+	p, docs, ok := openPackage(c, ViewBundle)
+	if !ok {
+		return
+	}
+
+	viewer := viewerOf(p)
+	bundle := Bundle{Recipient: p.Recipient, Message: p.Message, ExpiresAt: p.ExpiresAt}
+	var err error
+	bundle.Profile, err = visibility.Redact(viewer, docs.profile)
+	if err == nil {
+		bundle.Experience, err = visibility.RedactAll(viewer, docs.experience)
+	}
+	if err == nil {
+		bundle.Qualifications, err = visibility.RedactAll(viewer, docs.qualifications)
+	}
+	if err == nil {
+		bundle.Certificates, err = visibility.RedactAll(viewer, docs.certificates)
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not encode response"))
+		return
+	}
+	if p.PDF {
+		bundle.PDFURL = fmt.Sprintf("%s/api/v1/shares/view/%s/pdf", baseURL(c.Request.Context()), c.Param("token"))
+	}
+
+	c.JSON(http.StatusOK, bundle)
+}
+
+// GetPackagePDF downloads a package as a PDF
+//
+//	@Summary		Download a share package as a PDF
+//	@Description	Returns the package the link was made for as a printable PDF, when its owner offered one, without the fields their visibility keeps from signed in members. Each download is recorded for the package's owner.
+//	@Tags			shares
+//	@Produce		application/pdf
+//	@Param			token	path		string	true	"Token from the package's link"
+//	@Success		200		{file}		file
+//	@Failure		404		{object}	apierror.Response	"Link not found, expired or revoked, or no PDF offered"
+//	@Failure		500		{object}	apierror.Response	"Could not open package"
+//	@Router			/shares/view/{token}/pdf [get]
+func GetPackagePDF(c *gin.Context) {
+	p, docs, ok := openPackage(c, ViewPDF)
+	if !ok {
+		return
+	}
+
+	viewer := viewerOf(p)
+	var err error
+	docs.profile, err = visibility.Strip(viewer, docs.profile)
+	if err == nil {
+		docs.experience, err = visibility.StripAll(viewer, docs.experience)
+	}
+	if err == nil {
+		docs.qualifications, err = visibility.StripAll(viewer, docs.qualifications)
+	}
+	if err == nil {
+		docs.certificates, err = visibility.StripAll(viewer, docs.certificates)
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not render PDF"))
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="profile.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", renderPDF(p, docs))
+}
+
+// openPackage returns the package named by the link's token with its documents as they are now, recording
+// the view, or responds 404 when the link does not work or the package offers no such view
+func openPackage(c *gin.Context, kind string) (Package, contents, bool) {
+	// Keep the link out of caches, search engines and the referrers of links followed from the package
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex")
+	c.Header("Referrer-Policy", "no-referrer")
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	p, err := repo.GetByToken(ctx, hashToken(c.Param("token")))
+	if err == nil && (!p.Active(time.Now()) || (kind == ViewPDF && !p.PDF)) {
+		err = store.ErrNotFound
+	}
+	if err == nil {
+		err = checkOwner(ctx, p.UserID)
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Link not found"))
+		return Package{}, contents{}, false
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not open package"))
+		return Package{}, contents{}, false
+	}
+
+	docs, err := load(ctx, p)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not open package"))
+		return Package{}, contents{}, false
+	}
+
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > maxUserAgent {
+		userAgent = userAgent[:maxUserAgent]
+	}
+	view := View{ID: utils.GenerateID(), PackageID: p.ID, UserID: p.UserID, Kind: kind, UserAgent: userAgent, ViewedAt: time.Now()}
+	if err := repo.RecordView(ctx, view); err != nil {
+		logging.Logger(c).Error("Could not record package view", "package_id", p.ID, "error", err)
+	}
+	return p, docs, true
+}
+
+// checkOwner returns store.ErrNotFound when the owner of a package may no longer share it: their account
+// was disabled or deleted, or a moderator hid their profile
+func checkOwner(ctx context.Context, userID string) error {
+	owner, err := sources.Users.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if owner.Disabled {
+		return store.ErrNotFound
+	}
+	hidden, err := moderation.ProfileHidden(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if hidden {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// load reads the documents of the package, leaving out those deleted since it was made
+func load(ctx context.Context, p Package) (contents, error) {
+	docs := contents{profile: profile.Profile{UserID: p.UserID}}
+	var err error
+	if docs.profile, err = sources.Profiles.Get(ctx, p.UserID); errors.Is(err, store.ErrNotFound) {
+		docs.profile, err = profile.Profile{UserID: p.UserID}, nil
+	}
+	if err != nil {
+		return docs, err
+	}
+	if docs.experience, err = each(ctx, p.UserID, p.Experience, sources.Experience.Get); err != nil {
+		return docs, err
+	}
+	if docs.qualifications, err = each(ctx, p.UserID, p.Qualifications, sources.Qualifications.Get); err != nil {
+		return docs, err
+	}
+	if sources.Certificates != nil {
+		docs.certificates, err = each(ctx, p.UserID, p.Certificates, sources.Certificates.Get)
+	}
+	return docs, err
+}
+
+// each returns the user's documents with the IDs, in their order, leaving out those not found
+func each[T any](ctx context.Context, userID string, ids []string, get func(ctx context.Context, userID, id string) (T, error)) ([]T, error) {
+	docs := make([]T, 0, len(ids))
+	for _, id := range ids {
+		doc, err := get(ctx, userID, id)
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// renderPDF lays out the package's documents, already stripped of what the recipient may not see, as a PDF
+func renderPDF(p Package, docs contents) []byte {
+	var d pdfDocument
+	value := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return strings.TrimSpace(*s)
+	}
+	period := func(start, end, open string) string {
+		switch {
+		case start == "" && end == "":
+			return ""
+		case end == "":
+			return start + " - " + open
+		case start == "":
+			return end
+		}
+		return start + " - " + end
+	}
+	entry := func(title, subtitle, dates, description string) {
+		d.gap(6)
+		d.text(title, 11, true)
+		for _, line := range []string{subtitle, dates} {
+			if line != "" {
+				d.text(line, 9, false)
+			}
+		}
+		if description != "" {
+			d.text(description, 10, false)
+		}
+	}
+	section := func(title string) {
+		d.gap(14)
+		d.text(title, 14, true)
+	}
+
+	name := value(docs.profile.Name)
+	if name == "" {
+		name = "Profile"
+	}
+	d.text(name, 20, true)
+	for _, line := range []string{value(docs.profile.Email), value(docs.profile.Number)} {
+		if line != "" {
+			d.text(line, 10, false)
+		}
+	}
+	if bio := value(docs.profile.Bio); bio != "" {
+		d.gap(8)
+		d.text(bio, 10, false)
+	}
+	if len(docs.experience) > 0 {
+		section("Experience")
+		for _, item := range docs.experience {
+			entry(item.Position, item.Company, period(item.Start, item.End, "present"), item.Description)
+		}
+	}
+	if len(docs.qualifications) > 0 {
+		section("Qualifications")
+		for _, item := range docs.qualifications {
+			entry(item.Title, item.Institution, period(item.Start, item.End, ""), item.Description)
+		}
+	}
+	if len(docs.certificates) > 0 {
+		section("Certificates")
+		for _, item := range docs.certificates {
+			entry(item.Title, item.Institution, period(item.Start, item.End, ""), item.Description)
+		}
+	}
+	d.gap(20)
+	d.text(fmt.Sprintf("Shared with %s until %s", p.Recipient, p.ExpiresAt.UTC().Format("2 January 2006")), 8, false)
+	return d.bytes()
+}
+
+// InitializeRoutes initializes the share package routes. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup) {
+	router.GET("/view/:token", readonly.Writes(), ViewPackage)
+	router.GET("/view/:token/pdf", readonly.Writes(), GetPackagePDF)
+
+	protected := router.Group("")
+	protected.Use(auth.AuthMiddleware(sources.Users, true))
+	protected.GET("", ListPackages)
+	protected.POST("", CreatePackage)
+	protected.GET("/:shareid/views", ListPackageViews)
+	protected.DELETE("/:shareid", RevokePackage)
+}
@@ -1,12 +1,26 @@
 package skills
 
+import "profile-api/visibility"
+
 // Skill represents a user's skill
 type Skill struct {
 	UserID           string `bson:"user_id" json:"user_id"`
 	SkillID          string `bson:"skill_id" json:"skill_id"`
-	Name             string `bson:"name" json:"name"`
-	ProficiencyLevel string `bson:"proficiency_level" json:"proficiency_level"`
-	StartedAt        string `bson:"started_at" json:"started_at"`
-	LastUsed         string `bson:"last_used" json:"last_used"`
-	Description      string `bson:"description" json:"description"`
+	Name             string `bson:"name" json:"name" binding:"required,notblank,max=100"`
+	ProficiencyLevel string `bson:"proficiency_level" json:"proficiency_level" binding:"max=50"`
+	StartedAt        string `bson:"started_at" json:"started_at" binding:"omitempty,date"`
+	LastUsed         string `bson:"last_used" json:"last_used" binding:"omitempty,date"`
+	Description      string `bson:"description" json:"description" binding:"max=5000"`
+	// Visibility maps fields to who they are shown to: public, members or private
+	Visibility visibility.Rules `bson:"visibility" json:"visibility,omitempty" binding:"omitempty,visibility"`
+}
+
+// Owner returns the ID of the user the skill belongs to
+func (s Skill) Owner() string {
+	return s.UserID
+}
+
+// FieldVisibility returns the rules of the skill's fields
+func (s Skill) FieldVisibility() visibility.Rules {
+	return s.Visibility
 }
@@ -0,0 +1,19 @@
+package skills
+
+import "context"
+
+// Repository stores skills
+type Repository interface {
+	// List returns every skill belonging to the user
+	List(ctx context.Context, userID string) ([]Skill, error)
+	// ListByUsers returns every skill belonging to any of the users
+	ListByUsers(ctx context.Context, userIDs []string) ([]Skill, error)
+	// Get returns a single skill, or store.ErrNotFound
+	Get(ctx context.Context, userID, skillID string) (Skill, error)
+	// Create stores a new skill
+	Create(ctx context.Context, item Skill) error
+	// Save replaces a skill, creating it if it does not exist
+	Save(ctx context.Context, item Skill) error
+	// Delete removes a skill, or returns store.ErrNotFound when the user has no such skill
+	Delete(ctx context.Context, userID, skillID string) error
+}
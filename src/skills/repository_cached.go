@@ -0,0 +1,58 @@
+package skills
+
+import (
+	"context"
+	"time"
+
+	"profile-api/cache"
+)
+
+// CachedRepository serves each user's list of skills from the cache, dropping it whenever one of their
+// skills is written
+type CachedRepository struct {
+	Repository
+	cache *cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedRepository wraps the repository with a cache keeping skill lists for ttl
+func NewCachedRepository(r Repository, c *cache.Cache, ttl time.Duration) *CachedRepository {
+	return &CachedRepository{Repository: r, cache: c, ttl: ttl}
+}
+
+func (r *CachedRepository) List(ctx context.Context, userID string) ([]Skill, error) {
+	key := cache.Key("skills", userID)
+	var items []Skill
+	if r.cache.Get(ctx, key, &items) {
+		return items, nil
+	}
+	items, err := r.Repository.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.Set(ctx, key, items, r.ttl)
+	return items, nil
+}
+
+func (r *CachedRepository) Create(ctx context.Context, item Skill) error {
+	err := r.Repository.Create(ctx, item)
+	InvalidateCache(ctx, r.cache, item.UserID)
+	return err
+}
+
+func (r *CachedRepository) Save(ctx context.Context, item Skill) error {
+	err := r.Repository.Save(ctx, item)
+	InvalidateCache(ctx, r.cache, item.UserID)
+	return err
+}
+
+func (r *CachedRepository) Delete(ctx context.Context, userID, skillID string) error {
+	err := r.Repository.Delete(ctx, userID, skillID)
+	InvalidateCache(ctx, r.cache, userID)
+	return err
+}
+
+// InvalidateCache drops the user's cached skill list, such as after a write seen through change streams
+func InvalidateCache(ctx context.Context, c *cache.Cache, userID string) {
+	c.Delete(ctx, cache.Key("skills", userID))
+}
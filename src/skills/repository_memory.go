@@ -0,0 +1,96 @@
+package skills
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps skills in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu    sync.RWMutex
+	items []Skill
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+// index returns the position of a skill in items, or -1. The caller must hold the lock.
+func (r *MemoryRepository) index(userID, skillID string) int {
+	for i, item := range r.items {
+		if item.UserID == userID && item.SkillID == skillID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string) ([]Skill, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Skill
+	for _, item := range r.items {
+		if item.UserID == userID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Skill, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var items []Skill
+	for _, item := range r.items {
+		if slices.Contains(userIDs, item.UserID) {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, userID, skillID string) (Skill, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.index(userID, skillID)
+	if i < 0 {
+		return Skill{}, store.ErrNotFound
+	}
+	return r.items[i], nil
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, item Skill) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.index(item.UserID, item.SkillID) >= 0 {
+		return store.ErrConflict
+	}
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, item Skill) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(item.UserID, item.SkillID); i >= 0 {
+		r.items[i] = item
+		return nil
+	}
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, userID, skillID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(userID, skillID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.items = append(r.items[:i], r.items[i+1:]...)
+	return nil
+}
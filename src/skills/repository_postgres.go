@@ -0,0 +1,78 @@
+package skills
+
+import (
+	"context"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const skillsColumns = "user_id, skill_id, name, proficiency_level, started_at, last_used, description, visibility"
+
+// PostgresRepository stores skills in the skills table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string) ([]Skill, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+skillsColumns+" FROM skills WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanSkill)
+}
+
+func (r *PostgresRepository) ListByUsers(ctx context.Context, userIDs []string) ([]Skill, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+skillsColumns+" FROM skills WHERE user_id = ANY($1) ORDER BY created_at", userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanSkill)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, userID, skillID string) (Skill, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+skillsColumns+" FROM skills WHERE user_id = $1 AND skill_id = $2", userID, skillID)
+	if err != nil {
+		return Skill{}, err
+	}
+	item, err := pgx.CollectExactlyOneRow(rows, scanSkill)
+	return item, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, item Skill) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO skills ("+skillsColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		item.UserID, item.SkillID, item.Name, item.ProficiencyLevel, item.StartedAt, item.LastUsed, item.Description, item.Visibility)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, item Skill) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO skills ("+skillsColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8) "+
+		"ON CONFLICT (user_id, skill_id) DO UPDATE SET name = EXCLUDED.name, proficiency_level = EXCLUDED.proficiency_level, started_at = EXCLUDED.started_at, last_used = EXCLUDED.last_used, description = EXCLUDED.description, visibility = EXCLUDED.visibility",
+		item.UserID, item.SkillID, item.Name, item.ProficiencyLevel, item.StartedAt, item.LastUsed, item.Description, item.Visibility)
+	return err
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, userID, skillID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM skills WHERE user_id = $1 AND skill_id = $2", userID, skillID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// scanSkill reads a row selected with skillsColumns
+func scanSkill(row pgx.CollectableRow) (Skill, error) {
+	var item Skill
+	err := row.Scan(&item.UserID, &item.SkillID, &item.Name, &item.ProficiencyLevel, &item.StartedAt, &item.LastUsed, &item.Description, &item.Visibility)
+	return item, err
+}
@@ -0,0 +1,30 @@
+package skills
+
+import (
+	"context"
+
+	"profile-api/sanitize"
+)
+
+// SanitizedRepository cleans the user-supplied text of skills before storing them
+type SanitizedRepository struct {
+	Repository
+}
+
+// NewSanitizedRepository wraps the repository to clean skills with the configured sanitize policies
+func NewSanitizedRepository(r Repository) *SanitizedRepository {
+	return &SanitizedRepository{Repository: r}
+}
+
+func (r *SanitizedRepository) Create(ctx context.Context, item Skill) error {
+	return r.Repository.Create(ctx, sanitized(item))
+}
+
+func (r *SanitizedRepository) Save(ctx context.Context, item Skill) error {
+	return r.Repository.Save(ctx, sanitized(item))
+}
+
+func sanitized(item Skill) Skill {
+	item.Description = sanitize.Field(sanitize.SkillDescription, item.Description)
+	return item
+}
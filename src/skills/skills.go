@@ -2,86 +2,84 @@ package skills
 
 import (
 	"context"
-	"net/http"
+	"errors"
 
+	"profile-api/apierror"
+	"profile-api/apiversion"
 	"profile-api/auth"
+	"profile-api/billing"
+	"profile-api/bulk"
+	"profile-api/dryrun"
+	"profile-api/features"
+	"profile-api/quota"
+	"profile-api/store"
+	"profile-api/utils"
+	"profile-api/visibility"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var skillsCollection *mongo.Collection
+var repo Repository
 
-// Skill represents a user's skill
+// JSONResponse represents a message response
 type JSONResponse struct {
 	Message string `json:"message"`
-	Error   string `json:"error"`
 }
 
 // GetSkills retrieves all skills for a specific user
 //
 //	@Summary		Retrieve all skills for a specific user
-//	@Description	Retrieve all skills for a specific user
+//	@Description	Retrieve all skills for a specific user, without the fields their visibility hides from the requester
 //	@Tags			Skills
 //	@Produce		json
 //	@Param			userid	path		string			true	"User ID"
 //	@Success		200		{array}		Skill			"Skills retrieved"
-//	@Failure		401		{object}	JSONResponse	"error":	"Unauthorized"
-//	@Failure		403		{object}	JSONResponse	"error":	"Forbidden"
-//	@Failure		404		{object}	JSONResponse	"error":	"Skill not found"
-//	@Failure		500		{object}	JSONResponse	"error":	"Could not retrieve skills"
+//	@Failure		401		{object}	apierror.Response	"error":	"Unauthorized"
+//	@Failure		403		{object}	apierror.Response	"error":	"Forbidden"
+//	@Failure		404		{object}	apierror.Response	"error":	"Skill not found"
+//	@Failure		500		{object}	apierror.Response	"error":	"Could not retrieve skills"
 //	@Router			/skills/{userid} [get]
 func GetSkills(c *gin.Context) {
 	userID := c.Param("userid")
 
-	var skills []Skill
-	cursor, err := skillsCollection.Find(context.Background(), bson.M{"user_id": userID})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	skills, err := repo.List(ctx, userID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve skills"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve skills"))
 		return
 	}
-	defer cursor.Close(context.Background())
-	for cursor.Next(context.Background()) {
-		var skill Skill
-		err := cursor.Decode(&skill)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve skills"})
-			return
-		}
-		skills = append(skills, skill)
-	}
 
-	c.JSON(http.StatusOK, skills)
+	visibility.List(c, skills)
 }
 
 // GetSkill retrieves a specific skill for a specific user
 //
 //	@Summary		Retrieve a specific skill for a specific user
-//	@Description	Retrieve a specific skill for a specific user
+//	@Description	Retrieve a specific skill for a specific user, without the fields its visibility hides from the requester
 //	@Tags			Skills
 //	@Produce		json
 //	@Param			userid		path		string			true	"User ID"
 //	@Param			skillid	path		string			true	"Skill ID"
 //	@Success		200			{object}	Skill			"Skill retrieved"
-//	@Failure		404			{object}	JSONResponse	"error":	"Skill not found"
-//	@Failure		401			{object}	JSONResponse	"error":	"Unauthorized"
-//	@Failure		500			{object}	JSONResponse	"error":	"Could not retrieve skill"
+//	@Failure		404			{object}	apierror.Response	"error":	"Skill not found"
+//	@Failure		401			{object}	apierror.Response	"error":	"Unauthorized"
+//	@Failure		500			{object}	apierror.Response	"error":	"Could not retrieve skill"
 //	@Router			/skills/{userid}/{skillid} [get]
 func GetSkill(c *gin.Context) {
 	userID := c.Param("userid")
 	skillID := c.Param("skillid")
 
-	var skill Skill
-	err := skillsCollection.FindOne(context.Background(), bson.M{"user_id": userID, "skill_id": skillID}).Decode(&skill)
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	skill, err := repo.Get(ctx, userID, skillID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve skill"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve skill"))
 		return
 	}
 
-	c.JSON(http.StatusOK, skill)
+	visibility.OK(c, skill)
 }
 
 // PostSkill creates a new skill for a specific user
@@ -93,32 +91,34 @@ func GetSkill(c *gin.Context) {
 //	@Produce		json
 //	@Param			userid	path		string			true	"User ID"
 //	@Param			req		body		Skill			true	"Skill details"
-//	@Success		200		{object}	JSONResponse	"Skill created"
-//	@Failure		400		{object}	JSONResponse	"Invalid request body"
-//	@Failure		401		{object}	JSONResponse	"Unauthorized"
-//	@Failure		403		{object}	JSONResponse	"Forbidden"
-//	@Failure		404		{object}	JSONResponse	"Skill not found"
-//	@Failure		409		{object}	JSONResponse	"Skill already exists"
-//	@Failure		500		{object}	JSONResponse	"Could not create skill"
+//	@Success		200		{object}	Skill	"The created skill with its ID, and a message"
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"Unauthorized"
+//	@Failure		403		{object}	apierror.Response	"Forbidden"
+//	@Failure		404		{object}	apierror.Response	"Skill not found"
+//	@Failure		409		{object}	apierror.Response	"Skill already exists"
+//	@Failure		413		{object}	apierror.Response	"Skill limit reached"
+//	@Failure		500		{object}	apierror.Response	"Could not create skill"
 //	@Router			/skills/{userid} [post]
 func PostSkill(c *gin.Context) {
 	userID := c.Param("userid")
 
 	var req Skill
-	if err := c.BindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 	req.UserID = userID
 	req.SkillID = primitive.NewObjectID().Hex()
 
-	_, err := skillsCollection.InsertOne(context.Background(), req)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not create skill"})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Create(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create skill"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Skill created"})
+	apiversion.Created(c, req.SkillID, req, apiversion.Announce("Skill created", req))
 }
 
 // PutSkill updates a specific skill for a specific user
@@ -132,31 +132,32 @@ func PostSkill(c *gin.Context) {
 //	@Param			skillname	path		string			true	"Skill Name"
 //	@Param			req			body		Skill			true	"Skill details"
 //	@Success		200			{object}	JSONResponse	"Skill updated"
-//	@Failure		400			{object}	JSONResponse	"Invalid request body"
-//	@Failure		401			{object}	JSONResponse	"Unauthorized"
-//	@Failure		403			{object}	JSONResponse	"Forbidden"
-//	@Failure		404			{object}	JSONResponse	"Skill not found"
-//	@Failure		500			{object}	JSONResponse	"Could not update skill"
+//	@Failure		400			{object}	apierror.Response	"Invalid request body"
+//	@Failure		401			{object}	apierror.Response	"Unauthorized"
+//	@Failure		403			{object}	apierror.Response	"Forbidden"
+//	@Failure		404			{object}	apierror.Response	"Skill not found"
+//	@Failure		500			{object}	apierror.Response	"Could not update skill"
 //	@Router			/skills/{userid}/{skillId} [put]
 func PutSkill(c *gin.Context) {
 	userID := c.Param("userid")
 	skillID := c.Param("skillid")
 
 	var req Skill
-	if err := c.BindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
 		return
 	}
 	req.UserID = userID
 	req.SkillID = skillID
 
-	_, err := skillsCollection.UpdateOne(context.Background(), bson.M{"user_id": userID, "skill_id": skillID}, bson.M{"$set": req}, options.Update().SetUpsert(true))
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not update skill"})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Save(ctx, req); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not update skill"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Skill updated"})
+	apiversion.Updated(c, req, gin.H{"message": "Skill updated"})
 }
 
 // DeleteSkill deletes a specific skill for a specific user
@@ -169,36 +170,94 @@ func PutSkill(c *gin.Context) {
 //	@Param			userid		path		string			true	"User ID"
 //	@Param			skillid	path		string			true	"Skill ID"
 //	@Success		200			{object}	JSONResponse	"Skill deleted"
-//	@Failure		400			{object}	JSONResponse	"Invalid request body"
-//	@Failure		404			{object}	JSONResponse	"Skill not found"
-//	@Failure		401			{object}	JSONResponse	"Unauthorized"
-//	@Failure		403			{object}	JSONResponse	"Forbidden"
-//	@Failure		422			{object}	JSONResponse	"Invalid request body"
-//	@Failure		429			{object}	JSONResponse	"Too many requests"
-//	@Failure		500			{object}	JSONResponse	"Could not delete skill"
+//	@Failure		400			{object}	apierror.Response	"Invalid request body"
+//	@Failure		404			{object}	apierror.Response	"Skill not found"
+//	@Failure		401			{object}	apierror.Response	"Unauthorized"
+//	@Failure		403			{object}	apierror.Response	"Forbidden"
+//	@Failure		422			{object}	apierror.Response	"Invalid request body"
+//	@Failure		429			{object}	apierror.Response	"Too many requests"
+//	@Failure		500			{object}	apierror.Response	"Could not delete skill"
 //	@Router			/skills/{userid}/{skillid} [delete]
 func DeleteSkill(c *gin.Context) {
 	userID := c.Param("userid")
 	skillID := c.Param("skillid")
 
-	_, err := skillsCollection.DeleteOne(context.Background(), bson.M{"user_id": userID, "skill_id": skillID})
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	err := repo.Delete(ctx, userID, skillID)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Skill not found"))
+		return
+	}
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not delete skill"})
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete skill"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Skill deleted"})
+	apiversion.NoContent(c, gin.H{"message": "Skill deleted"})
+}
+
+// BulkDeleteSkills deletes many of a user's skills at once.
+//
+//	@Summary		Delete skills in bulk
+//	@Description	Deletes the user's skills with the given IDs, or those whose fields match every value of the filter, such as {"proficiency_level": "Beginner"}. A dry run returns what would be deleted without deleting anything. Deleting by filter must be confirmed.
+//	@Tags			Skills
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string				true	"User ID"
+//	@Param			body	body		bulk.DeleteRequest	true	"The skills to delete"
+//	@Success		200		{object}	bulk.DeleteResult
+//	@Failure		400		{object}	apierror.Response	"Invalid request, or a filter that is not confirmed"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Not the owner of the skills"
+//	@Failure		500		{object}	apierror.Response	"Could not delete skills"
+//	@Router			/skills/{userid}/bulk-delete [post]
+func BulkDeleteSkills(c *gin.Context) {
+	userID := c.Param("userid")
+
+	var req bulk.DeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	items, err := repo.List(ctx, userID)
+	cancel()
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve skills"))
+		return
+	}
+	result, err := bulk.Delete(c.Request.Context(), req, items, func(item Skill) string { return item.SkillID }, func(ctx context.Context, id string) error {
+		return repo.Delete(ctx, userID, id)
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not delete skills"))
+		return
+	}
+
+	apiversion.OK(c, result)
+}
+
+// countSkills counts the user's skills against their document quota
+func countSkills(ctx context.Context, userID string) (int, error) {
+	items, err := repo.List(ctx, userID)
+	return len(items), err
 }
 
 // InitializeRoutes initializes the skills routes
-func InitializeRoutes(router *gin.RouterGroup, db *mongo.Client, db_name string) {
-	skillsCollection = db.Database(db_name).Collection("skills")
-	router.GET("/:userid", GetSkills)
-	router.GET("/:userid/:skillid", GetSkill)
+func InitializeRoutes(router *gin.RouterGroup, r Repository, users auth.Repository) {
+	repo = r
+	authOptional := auth.AuthMiddleware(users, false)
+	router.GET("/:userid", authOptional, GetSkills)
+	router.GET("/:userid/:skillid", authOptional, GetSkill)
 
 	protected := router.Group("/")
-	protected.Use(auth.AuthMiddleware(db, db_name, true))
-	protected.POST("/:userid", PostSkill)
-	protected.PUT("/:userid/:skillid", PutSkill)
-	protected.DELETE("/:userid/:skillid", DeleteSkill)
+	protected.Use(auth.AuthMiddleware(users, true))
+	protected.POST("/:userid", dryrun.Supported(), auth.RequireOwner(), quota.LimitDocuments("skills", countSkills), PostSkill)
+	protected.PUT("/:userid/:skillid", dryrun.Supported(), auth.RequireOwner(), PutSkill)
+	protected.DELETE("/:userid/:skillid", dryrun.Supported(), auth.RequireOwner(), DeleteSkill)
+	protected.POST("/:userid/bulk-delete", dryrun.Supported(), auth.RequireOwner(), BulkDeleteSkills)
+	protected.POST("/:userid/suggestions", auth.RequireOwner(), features.Require(features.AIProcessing), billing.Require(billing.AI), SuggestSkills)
 }
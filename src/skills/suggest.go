@@ -0,0 +1,172 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"profile-api/ai"
+	"profile-api/apierror"
+	"profile-api/experience"
+	"profile-api/utils"
+	"profile-api/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Proficiency levels suggested skills are estimated at
+var proficiencyLevels = []string{"beginner", "intermediate", "advanced", "expert"}
+
+const suggestInstructions = `You suggest skills for a person's profile from the descriptions of their roles. Answer with a JSON array of objects with:
+"name", the skill, such as a technology, method or domain, named as it is commonly written;
+"proficiency_level", estimated from how long and how deeply it was used: beginner, intermediate, advanced or expert;
+"started_at" and "last_used", the dates of the first and last roles using it as YYYY-MM or YYYY, with "last_used" empty when it is used in a current role;
+"reason", one sentence on what in the roles shows the skill;
+"experience_ids", the IDs of the roles using it.
+Only suggest skills the roles give evidence of, and none the person already lists.`
+
+// maxSuggestions bounds the skills suggested at once
+const maxSuggestions = 30
+
+var experienceRepo experience.Repository
+
+// SetExperience sets where the experience skills are suggested from is read, nil when the experience module
+// is disabled
+func SetExperience(r experience.Repository) {
+	experienceRepo = r
+}
+
+// Suggestion is a skill suggested from a user's experience, for them to accept by creating it
+type Suggestion struct {
+	Skill Skill `json:"skill"`
+	// Reason explains what in the user's experience shows the skill
+	Reason string `json:"reason"`
+	// ExperienceIDs are the experience records the skill was found in
+	ExperienceIDs []string `json:"experience_ids"`
+}
+
+// SuggestSkills suggests skills from the descriptions of a user's experience
+//
+//	@Summary		Suggest skills from experience
+//	@Description	Has the AI provider read the user's experience and suggest the skills it shows that are not yet in their skills, with an estimated proficiency and the dates they were used. Nothing is stored: a suggestion is accepted by creating its skill. Each request counts against the user's AI request quota. Only available when an AI provider is configured, the ai-processing feature is enabled for the user and their plan includes AI features.
+//	@Tags			Skills
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			userid	path		string			true	"User ID"
+//	@Success		200		{array}		Suggestion		"Suggested skills"
+//	@Failure		401		{object}	apierror.Response	"error":	"Unauthorized"
+//	@Failure		402		{object}	apierror.Response	"error":	"The user's plan does not include AI features"
+//	@Failure		403		{object}	apierror.Response	"error":	"Forbidden"
+//	@Failure		404		{object}	apierror.Response	"error":	"Not found, when the feature or the experience module is disabled"
+//	@Failure		429		{object}	apierror.Response	"error":	"AI request quota used up"
+//	@Failure		500		{object}	apierror.Response	"error":	"Could not suggest skills"
+//	@Failure		503		{object}	apierror.Response	"error":	"No AI provider is configured"
+//	@Router			/skills/{userid}/suggestions [post]
+func SuggestSkills(c *gin.Context) {
+	userID := c.Param("userid")
+	if experienceRepo == nil {
+		apierror.Abort(c, apierror.NotFound("Skill suggestions are unavailable, as the experience module is disabled"))
+		return
+	}
+	if !ai.Enabled() {
+		apierror.Abort(c, apierror.New(http.StatusServiceUnavailable, apierror.CodeServiceUnavailable, "No AI provider is configured"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	roles, err := experienceRepo.List(ctx, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve experience"))
+		return
+	}
+	existing, err := repo.List(ctx, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve skills"))
+		return
+	}
+	if err := ai.Use(ctx, userID); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not check AI request quota"))
+		return
+	}
+	cancel()
+
+	suggestions, err := suggest(c.Request.Context(), roles, existing)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not suggest skills"))
+		return
+	}
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// suggest asks the AI provider for the skills the roles show, leaving out those the user has
+func suggest(ctx context.Context, roles []experience.Experience, existing []Skill) ([]Suggestion, error) {
+	suggestions := []Suggestion{}
+	var prompt strings.Builder
+	for _, role := range roles {
+		if strings.TrimSpace(role.Description) == "" {
+			continue
+		}
+		fmt.Fprintf(&prompt, "Role %s: %s at %s, from %s to %s\n%s\n\n", role.ExperienceID, role.Position, role.Company, role.Start, orPresent(role.End), role.Description)
+	}
+	if prompt.Len() == 0 {
+		return suggestions, nil
+	}
+	have := map[string]bool{}
+	var names []string
+	for _, skill := range existing {
+		have[strings.ToLower(skill.Name)] = true
+		names = append(names, skill.Name)
+	}
+	if len(names) > 0 {
+		prompt.WriteString("Skills already listed: " + strings.Join(names, ", ") + "\n")
+	}
+
+	var answer []struct {
+		Name             string   `json:"name"`
+		ProficiencyLevel string   `json:"proficiency_level"`
+		StartedAt        string   `json:"started_at"`
+		LastUsed         string   `json:"last_used"`
+		Reason           string   `json:"reason"`
+		ExperienceIDs    []string `json:"experience_ids"`
+	}
+	if err := ai.CompleteJSON(ctx, ai.Request{System: suggestInstructions, Prompt: prompt.String()}, &answer); err != nil {
+		return nil, err
+	}
+
+	// The answer is checked as the model may not follow the instructions
+	for _, a := range answer {
+		name := strings.TrimSpace(a.Name)
+		if name == "" || len(name) > 100 || have[strings.ToLower(name)] || len(suggestions) == maxSuggestions {
+			continue
+		}
+		have[strings.ToLower(name)] = true
+		skill := Skill{Name: name}
+		if level := strings.ToLower(a.ProficiencyLevel); slices.Contains(proficiencyLevels, level) {
+			skill.ProficiencyLevel = level
+		}
+		if validation.IsDate(a.StartedAt) {
+			skill.StartedAt = a.StartedAt
+		}
+		if validation.IsDate(a.LastUsed) {
+			skill.LastUsed = a.LastUsed
+		}
+		ids := []string{}
+		for _, id := range a.ExperienceIDs {
+			if slices.ContainsFunc(roles, func(role experience.Experience) bool { return role.ExperienceID == id }) {
+				ids = append(ids, id)
+			}
+		}
+		suggestions = append(suggestions, Suggestion{Skill: skill, Reason: a.Reason, ExperienceIDs: ids})
+	}
+	return suggestions, nil
+}
+
+func orPresent(end string) string {
+	if end == "" {
+		return "present"
+	}
+	return end
+}
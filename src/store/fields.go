@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type fieldsKey struct{}
+
+// WithFields returns a context asking reads to load only the fields, named as in the JSON of the documents
+// read, along with the fields the caller needs itself. Without fields every field is loaded.
+func WithFields(ctx context.Context, fields []string, needed ...string) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, fieldsKey{}, append(append([]string{}, fields...), needed...))
+}
+
+// Fields returns the fields reads made with the context should load, or nil for every field
+func Fields(ctx context.Context) []string {
+	fields, _ := ctx.Value(fieldsKey{}).([]string)
+	return fields
+}
+
+// MongoFindOne returns the options of a FindOne loading the fields the context asks for of the document,
+// which is decoded into the model's type
+func MongoFindOne(ctx context.Context, model any) *options.FindOneOptions {
+	opts := options.FindOne()
+	if projection := mongoProjection(ctx, model); projection != nil {
+		opts.SetProjection(projection)
+	}
+	return opts
+}
+
+// MongoFind returns the options of a Find loading the fields the context asks for of the documents, which
+// are decoded into the model's type
+func MongoFind(ctx context.Context, model any) *options.FindOptions {
+	opts := options.Find()
+	if projection := mongoProjection(ctx, model); projection != nil {
+		opts.SetProjection(projection)
+	}
+	return opts
+}
+
+// mongoProjection returns the projection loading the fields the context asks for, or nil for every field
+func mongoProjection(ctx context.Context, model any) bson.D {
+	fields := Fields(ctx)
+	if fields == nil {
+		return nil
+	}
+	names := map[string]string{}
+	t := reflect.TypeOf(model)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		bsonName, _, _ := strings.Cut(field.Tag.Get("bson"), ",")
+		if jsonName != "" && jsonName != "-" && bsonName != "" && bsonName != "-" {
+			names[jsonName] = bsonName
+		}
+	}
+	projection := bson.D{}
+	for _, field := range fields {
+		if name, ok := names[field]; ok {
+			projection = append(projection, bson.E{Key: name, Value: 1})
+		}
+	}
+	return projection
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+type publicReadKey struct{}
+
+// publicReadPreference is where Mongo serves public reads from, nil to serve them like any other read
+var publicReadPreference *readpref.ReadPref
+
+// SetPublicReadPreference sets where Mongo serves the reads of contexts marked by PublicRead from
+func SetPublicReadPreference(rp *readpref.ReadPref) {
+	publicReadPreference = rp
+}
+
+// PublicRead returns a context marking the reads made with it as heavy public reads, such as listings and
+// searches anyone can make, which may be served by secondaries and so trail the latest writes slightly
+func PublicRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, publicReadKey{}, true)
+}
+
+// MongoReader returns the collection reads made with the context should go through: a copy of the
+// collection reading with the public read preference for public reads, or the collection itself
+func MongoReader(ctx context.Context, coll *mongo.Collection) *mongo.Collection {
+	if publicReadPreference == nil || ctx.Value(publicReadKey{}) == nil {
+		return coll
+	}
+	clone, err := coll.Clone(options.Collection().SetReadPreference(publicReadPreference))
+	if err != nil {
+		return coll
+	}
+	return clone
+}
@@ -0,0 +1,88 @@
+// Package store holds what the storage backends behind each module's repository have in common.
+package store
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Storage backends selectable in the config
+const (
+	Mongo    = "mongo"
+	Postgres = "postgres"
+	Memory   = "memory"
+)
+
+var (
+	// ErrNotFound is returned when the requested document does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when a write lost a race with another write to the same document
+	ErrConflict = errors.New("conflict")
+)
+
+// MongoErr translates the errors of the Mongo driver into the errors shared by every backend
+func MongoErr(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// MongoRevision returns the filter on a document's revision field matching the revision. Documents written
+// before revisions were recorded have no revision field and count as revision 0.
+func MongoRevision(revision int) any {
+	if revision == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return revision
+}
+
+// uniqueViolation is the Postgres error code for a unique constraint violation
+const uniqueViolation = "23505"
+
+// PostgresErr translates the errors of the Postgres driver into the errors shared by every backend
+func PostgresErr(err error) error {
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return ErrNotFound
+	case errors.As(err, &pgErr) && pgErr.Code == uniqueViolation:
+		return ErrConflict
+	}
+	return err
+}
+
+// Migration is a schema migration of a database, numbered like 0012_feature_flags
+type Migration struct {
+	Version string `json:"version"`
+	// AppliedAt is nil while the migration is pending
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+	// Reversible migrations can be rolled back
+	Reversible bool `json:"reversible"`
+}
+
+// After reports whether the migration is numbered after the target, given as a version or just its number
+func (m Migration) After(target string) bool {
+	number := func(version string) string {
+		n, _, _ := strings.Cut(version, "_")
+		return n
+	}
+	return number(m.Version) > number(target)
+}
+
+// Pending returns the migrations that have not been applied
+func Pending(migrations []Migration) []Migration {
+	var pending []Migration
+	for _, m := range migrations {
+		if m.AppliedAt == nil {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
@@ -0,0 +1,102 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"profile-api/email"
+	"profile-api/journal"
+	"profile-api/utils"
+)
+
+// digestInterval returns how long to wait between digests for the given frequency
+func digestInterval(frequency string) time.Duration {
+	if frequency == FrequencyDaily {
+		return 24 * time.Hour
+	}
+	return 7 * 24 * time.Hour
+}
+
+// SendDigests emails every confirmed subscriber whose digest is due with the public entries published since their last digest
+func SendDigests(ctx context.Context) error {
+	listCtx, cancel := utils.WithOperationTimeout(ctx)
+	defer cancel()
+	subs, err := repo.ListConfirmed(listCtx)
+	if err != nil {
+		return fmt.Errorf("could not retrieve subscriptions: %w", err)
+	}
+
+	now := time.Now()
+	for _, sub := range subs {
+		if now.Sub(sub.LastSentAt) < digestInterval(sub.Frequency) {
+			continue
+		}
+		if err := sendDigest(ctx, sub, now); err != nil {
+			slog.Error("Error sending digest", "subscription_id", sub.SubscriptionID, "error", err)
+		}
+	}
+	return nil
+}
+
+// sendDigest sends a single digest and records when it was sent
+func sendDigest(ctx context.Context, sub Subscription, now time.Time) error {
+	ctx, cancel := utils.WithOperationTimeout(ctx)
+	defer cancel()
+
+	// The profile is no longer public, so nothing is sent while the window still moves forward
+	hidden, err := withheld(ctx, sub.UserID)
+	if err != nil {
+		return err
+	}
+	if hidden {
+		return repo.MarkSent(ctx, sub.SubscriptionID, now)
+	}
+
+	entries, err := journals.List(ctx, journal.Filter{
+		UserID: sub.UserID,
+		Status: journal.StatusPublic,
+		// Entries are mostly created pending and published later, so they are picked by when they were made
+		// public rather than created
+		StatusChangedAfter: sub.LastSentAt,
+		StatusChangedUntil: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Nothing new, skip the email but move the window forward
+	if len(entries) > 0 {
+		data := digestEmail{
+			Frequency:      sub.Frequency,
+			Since:          sub.LastSentAt,
+			UnsubscribeURL: fmt.Sprintf("%s/api/v1/subscriptions/unsubscribe/%s", baseURL(ctx), unsubscribeToken(ctx, sub.SubscriptionID)),
+		}
+		for _, entry := range entries {
+			// Entries are listed by the title of the version shown, not the latest one written
+			title := "Untitled"
+			for _, e := range entry.Entries {
+				if e.Version == entry.Version && e.Title != "" {
+					title = e.Title
+				}
+			}
+			data.Entries = append(data.Entries, digestEntry{
+				Title: title,
+				URL:   fmt.Sprintf("%s/api/v1/journal/%s", baseURL(ctx), entry.JournalID),
+			})
+		}
+
+		msg, err := email.Render("journal_digest", data)
+		if err != nil {
+			return err
+		}
+		msg.To = sub.Email
+		msg.UserID = sub.UserID
+		if err := email.Send(ctx, msg); err != nil {
+			return err
+		}
+	}
+
+	return repo.MarkSent(ctx, sub.SubscriptionID, now)
+}
@@ -0,0 +1,72 @@
+package subscriptions
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Subscribing is open to anyone and sends a confirmation email, so the requests from one address and for
+// one email are limited within a window, keeping the route from being used to flood inboxes
+const (
+	limitWindow   = time.Hour
+	maxPerAddress = 10
+	maxPerEmail   = 3
+)
+
+// window counts the requests of a key since it started
+type window struct {
+	start time.Time
+	count int
+}
+
+var (
+	limitMu sync.Mutex
+	// windows holds the requests counted, by tenant and address or email
+	windows = map[string]*window{}
+	swept   time.Time
+)
+
+// allow counts a request of the key, reporting whether it is within the limit and, when not, when the
+// window ends
+func allow(key string, limit int, now time.Time) (bool, time.Time) {
+	limitMu.Lock()
+	defer limitMu.Unlock()
+	if now.Sub(swept) >= limitWindow {
+		for k, w := range windows {
+			if now.Sub(w.start) >= limitWindow {
+				delete(windows, k)
+			}
+		}
+		swept = now
+	}
+	w, ok := windows[key]
+	if !ok || now.Sub(w.start) >= limitWindow {
+		w = &window{start: now}
+		windows[key] = w
+	}
+	w.count++
+	return w.count <= limit, w.start.Add(limitWindow)
+}
+
+// abortLimited responds 429 to a request over the limit, until the window ends
+func abortLimited(c *gin.Context, until time.Time) {
+	c.Header("Retry-After", strconv.Itoa(int(max(time.Until(until), time.Second).Seconds())))
+	apierror.Abort(c, apierror.TooManyRequests("Too many subscription requests, try again later"))
+}
+
+// limitAddress limits the subscription requests from the client's address
+func limitAddress() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, until := allow(tenant.ID(c.Request.Context())+"/address/"+c.ClientIP(), maxPerAddress, time.Now()); !ok {
+			abortLimited(c, until)
+			return
+		}
+		c.Next()
+	}
+}
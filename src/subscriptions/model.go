@@ -0,0 +1,46 @@
+package subscriptions
+
+import "time"
+
+// Subscription represents a visitor's email subscription to a user's public journal digest
+type Subscription struct {
+	SubscriptionID string `bson:"subscription_id" json:"subscription_id"`
+	UserID         string `bson:"user_id" json:"user_id"`
+	Email          string `bson:"email" json:"email"`
+	Frequency      string `bson:"frequency" json:"frequency"`
+	Confirmed      bool   `bson:"confirmed" json:"confirmed"`
+	// ConfirmTokenHash is the SHA-256 of the token in the confirmation link, which is itself only emailed
+	ConfirmTokenHash string `bson:"confirm_token" json:"-"`
+	// UnsubTokenHash is the SHA-256 of the token in the unsubscribe link of every digest, which is derived
+	// from the subscription ID rather than stored
+	UnsubTokenHash string     `bson:"unsubscribe_token" json:"-"`
+	CreatedAt      time.Time  `bson:"created_at" json:"created_at"`
+	ConfirmedAt    *time.Time `bson:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
+	LastSentAt     time.Time  `bson:"last_sent_at" json:"last_sent_at"`
+}
+
+// SubscribeRequest represents the request body for subscribing to a digest
+type SubscribeRequest struct {
+	Email     string `json:"email" binding:"required,email,max=254"`
+	Frequency string `json:"frequency" binding:"omitempty,oneof=daily weekly"`
+}
+
+// confirmEmail is the data for the subscription_confirm email template
+type confirmEmail struct {
+	Frequency  string
+	ConfirmURL string
+}
+
+// digestEmail is the data for the journal_digest email template
+type digestEmail struct {
+	Frequency      string
+	Since          time.Time
+	Entries        []digestEntry
+	UnsubscribeURL string
+}
+
+// digestEntry is a journal entry listed in a digest email
+type digestEntry struct {
+	Title string
+	URL   string
+}
@@ -0,0 +1,42 @@
+package subscriptions
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// page is the page the links of subscription emails open. Its form posts back to the link, so the change is
+// only made once the visitor asks for it.
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="referrer" content="no-referrer">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Action}}<form method="post"><button type="submit">{{.Action}}</button></form>{{end}}
+</body>
+</html>
+`))
+
+// renderPage writes the page with the title, and a button submitting it when action is set
+func renderPage(c *gin.Context, status int, title, action string) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	_ = page.Execute(c.Writer, struct{ Title, Action string }{title, action})
+}
+
+// respond reports the outcome of a submitted link as a page to browsers and as JSON to other clients
+func respond(c *gin.Context, message string) {
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		renderPage(c, http.StatusOK, message, "")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
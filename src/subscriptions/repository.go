@@ -0,0 +1,21 @@
+package subscriptions
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores journal digest subscriptions
+type Repository interface {
+	// Replace stores the subscription, removing any earlier unconfirmed subscription of the same email to the
+	// same user. It returns store.ErrConflict, storing nothing, when the email is already confirmed.
+	Replace(ctx context.Context, sub Subscription) error
+	// Confirm activates the subscription with the hash of the confirmation token, or returns store.ErrNotFound
+	Confirm(ctx context.Context, tokenHash string, at time.Time) error
+	// Unsubscribe removes the subscription with the hash of the unsubscribe token, or returns store.ErrNotFound
+	Unsubscribe(ctx context.Context, tokenHash string) error
+	// ListConfirmed returns every confirmed subscription
+	ListConfirmed(ctx context.Context) ([]Subscription, error)
+	// MarkSent records when the subscription's last digest was sent
+	MarkSent(ctx context.Context, subscriptionID string, at time.Time) error
+}
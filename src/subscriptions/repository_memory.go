@@ -0,0 +1,83 @@
+package subscriptions
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps subscriptions in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu            sync.RWMutex
+	subscriptions []Subscription
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) Replace(ctx context.Context, sub Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.ContainsFunc(r.subscriptions, func(s Subscription) bool {
+		return s.UserID == sub.UserID && s.Email == sub.Email && s.Confirmed
+	}) {
+		return store.ErrConflict
+	}
+	r.subscriptions = slices.DeleteFunc(r.subscriptions, func(s Subscription) bool {
+		return s.UserID == sub.UserID && s.Email == sub.Email
+	})
+	r.subscriptions = append(r.subscriptions, sub)
+	return nil
+}
+
+func (r *MemoryRepository) Confirm(ctx context.Context, tokenHash string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.subscriptions, func(s Subscription) bool { return s.ConfirmTokenHash == tokenHash })
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.subscriptions[i].Confirmed = true
+	r.subscriptions[i].ConfirmedAt = &at
+	r.subscriptions[i].LastSentAt = at
+	return nil
+}
+
+func (r *MemoryRepository) Unsubscribe(ctx context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.subscriptions, func(s Subscription) bool { return s.UnsubTokenHash == tokenHash })
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.subscriptions = slices.Delete(r.subscriptions, i, i+1)
+	return nil
+}
+
+func (r *MemoryRepository) ListConfirmed(ctx context.Context) ([]Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var subs []Subscription
+	for _, sub := range r.subscriptions {
+		if sub.Confirmed {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+func (r *MemoryRepository) MarkSent(ctx context.Context, subscriptionID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.subscriptions {
+		if r.subscriptions[i].SubscriptionID == subscriptionID {
+			r.subscriptions[i].LastSentAt = at
+		}
+	}
+	return nil
+}
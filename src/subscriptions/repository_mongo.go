@@ -0,0 +1,86 @@
+package subscriptions
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoRepository stores subscriptions in the subscriptions collection
+type MongoRepository struct {
+	subscriptions *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{subscriptions: db.Collection("subscriptions")}
+}
+
+func (r *MongoRepository) Replace(ctx context.Context, sub Subscription) error {
+	confirmed, err := r.subscriptions.CountDocuments(ctx, bson.M{"user_id": sub.UserID, "email": sub.Email, "confirmed": true})
+	if err != nil {
+		return err
+	}
+	if confirmed > 0 {
+		return store.ErrConflict
+	}
+	_, err = r.subscriptions.DeleteMany(ctx, bson.M{"user_id": sub.UserID, "email": sub.Email, "confirmed": false})
+	if err != nil {
+		return err
+	}
+	_, err = r.subscriptions.InsertOne(ctx, sub)
+	return err
+}
+
+func (r *MongoRepository) Confirm(ctx context.Context, tokenHash string, at time.Time) error {
+	res, err := r.subscriptions.UpdateOne(
+		ctx,
+		bson.M{"confirm_token": tokenHash},
+		bson.M{"$set": bson.M{"confirmed": true, "confirmed_at": at, "last_sent_at": at}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) Unsubscribe(ctx context.Context, tokenHash string) error {
+	res, err := r.subscriptions.DeleteOne(ctx, bson.M{"unsubscribe_token": tokenHash})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) ListConfirmed(ctx context.Context) ([]Subscription, error) {
+	cursor, err := r.subscriptions.Find(ctx, bson.M{"confirmed": true})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var subs []Subscription
+	if err := cursor.All(ctx, &subs); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *MongoRepository) MarkSent(ctx context.Context, subscriptionID string, at time.Time) error {
+	_, err := r.subscriptions.UpdateOne(
+		ctx,
+		bson.M{"subscription_id": subscriptionID},
+		bson.M{"$set": bson.M{"last_sent_at": at}},
+	)
+	return err
+}
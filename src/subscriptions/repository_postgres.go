@@ -0,0 +1,90 @@
+package subscriptions
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepository stores subscriptions in the subscriptions table
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+// Replace swaps the earlier subscription for the new one in a single transaction
+func (r *PostgresRepository) Replace(ctx context.Context, sub Subscription) error {
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var confirmed bool
+		err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM subscriptions WHERE user_id = $1 AND email = $2 AND confirmed FOR UPDATE)",
+			sub.UserID, sub.Email).Scan(&confirmed)
+		if err != nil {
+			return err
+		}
+		if confirmed {
+			return store.ErrConflict
+		}
+		_, err = tx.Exec(ctx, "DELETE FROM subscriptions WHERE user_id = $1 AND email = $2 AND NOT confirmed", sub.UserID, sub.Email)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `INSERT INTO subscriptions (subscription_id, user_id, email, frequency, confirmed,
+			confirm_token, unsubscribe_token, created_at, confirmed_at, last_sent_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			sub.SubscriptionID, sub.UserID, sub.Email, sub.Frequency, sub.Confirmed,
+			sub.ConfirmTokenHash, sub.UnsubTokenHash, sub.CreatedAt, sub.ConfirmedAt, sub.LastSentAt)
+		return err
+	})
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Confirm(ctx context.Context, tokenHash string, at time.Time) error {
+	tag, err := r.pool.Exec(ctx, "UPDATE subscriptions SET confirmed = TRUE, confirmed_at = $2, last_sent_at = $2 WHERE confirm_token = $1",
+		tokenHash, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Unsubscribe(ctx context.Context, tokenHash string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM subscriptions WHERE unsubscribe_token = $1", tokenHash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListConfirmed(ctx context.Context) ([]Subscription, error) {
+	rows, err := r.pool.Query(ctx, `SELECT subscription_id, user_id, email, frequency, confirmed,
+		confirm_token, unsubscribe_token, created_at, confirmed_at, last_sent_at
+		FROM subscriptions WHERE confirmed`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Subscription, error) {
+		var sub Subscription
+		err := row.Scan(&sub.SubscriptionID, &sub.UserID, &sub.Email, &sub.Frequency, &sub.Confirmed,
+			&sub.ConfirmTokenHash, &sub.UnsubTokenHash, &sub.CreatedAt, &sub.ConfirmedAt, &sub.LastSentAt)
+		return sub, err
+	})
+}
+
+func (r *PostgresRepository) MarkSent(ctx context.Context, subscriptionID string, at time.Time) error {
+	_, err := r.pool.Exec(ctx, "UPDATE subscriptions SET last_sent_at = $2 WHERE subscription_id = $1", subscriptionID, at)
+	return err
+}
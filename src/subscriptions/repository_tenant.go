@@ -0,0 +1,38 @@
+package subscriptions
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Replace(ctx context.Context, sub Subscription) error {
+	return r.repos.For(ctx).Replace(ctx, sub)
+}
+
+func (r *TenantRepository) Confirm(ctx context.Context, tokenHash string, at time.Time) error {
+	return r.repos.For(ctx).Confirm(ctx, tokenHash, at)
+}
+
+func (r *TenantRepository) Unsubscribe(ctx context.Context, tokenHash string) error {
+	return r.repos.For(ctx).Unsubscribe(ctx, tokenHash)
+}
+
+func (r *TenantRepository) ListConfirmed(ctx context.Context) ([]Subscription, error) {
+	return r.repos.For(ctx).ListConfirmed(ctx)
+}
+
+func (r *TenantRepository) MarkSent(ctx context.Context, subscriptionID string, at time.Time) error {
+	return r.repos.For(ctx).MarkSent(ctx, subscriptionID, at)
+}
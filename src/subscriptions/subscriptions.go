@@ -0,0 +1,279 @@
+package subscriptions
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/email"
+	"profile-api/journal"
+	"profile-api/moderation"
+	"profile-api/privacy"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var repo Repository
+var journals journal.Repository
+var users auth.Repository
+
+// Digest frequencies
+const (
+	FrequencyDaily  = "daily"
+	FrequencyWeekly = "weekly"
+)
+
+// JSONResponse represents a message response
+type JSONResponse struct {
+	Message string `json:"message"`
+}
+
+// Subscribe registers an email address for a digest of a user's public journal entries.
+//
+//	@Summary		Subscribe to a journal digest
+//	@Description	Registers an email for a daily or weekly digest of a user's new public journal entries. A confirmation email is sent and the subscription is only active once confirmed. Hidden and restricted profiles can't be subscribed to. An email already confirmed keeps its subscription and is sent nothing. Requests are limited per address and per email.
+//	@Tags			Subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Param			userid	path		string				true	"User ID"
+//	@Param			req		body		SubscribeRequest	true	"Subscription details"
+//	@Success		200		{object}	JSONResponse		"Already subscribed"
+//	@Success		202		{object}	JSONResponse		"Confirmation email sent"
+//	@Failure		400		{object}	apierror.Response		"Invalid request body"
+//	@Failure		404		{object}	apierror.Response		"User not found, or their profile is hidden or restricted"
+//	@Failure		429		{object}	apierror.Response		"Too many subscription requests"
+//	@Failure		500		{object}	apierror.Response		"Could not create subscription"
+//	@Router			/subscriptions/{userid} [post]
+func Subscribe(c *gin.Context) {
+	userID := c.Param("userid")
+
+	var req SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	addr, err := mail.ParseAddress(req.Email)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid email address"))
+		return
+	}
+	if req.Frequency == "" {
+		req.Frequency = FrequencyWeekly
+	}
+	if ok, until := allow(tenant.ID(c.Request.Context())+"/email/"+strings.ToLower(addr.Address), maxPerEmail, time.Now()); !ok {
+		abortLimited(c, until)
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if _, err := users.FindByID(ctx, userID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			apierror.Abort(c, apierror.NotFound("User not found"))
+		} else {
+			apierror.Abort(c, apierror.Wrap(err, "Could not create subscription"))
+		}
+		return
+	}
+	// Profiles that are not public are reported as not found, as they are to every other anonymous read
+	hidden, err := withheld(ctx, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create subscription"))
+		return
+	}
+	if hidden {
+		apierror.Abort(c, apierror.NotFound("User not found"))
+		return
+	}
+
+	// Re-subscribing replaces a subscription not confirmed yet. A confirmed one is kept as it is, so nobody
+	// but the subscriber can cancel it. Only the hashes of the link tokens are stored.
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create subscription"))
+		return
+	}
+	confirmToken := hex.EncodeToString(random)
+	sub := Subscription{
+		SubscriptionID:   utils.GenerateID(),
+		UserID:           userID,
+		Email:            addr.Address,
+		Frequency:        req.Frequency,
+		ConfirmTokenHash: hashToken(confirmToken),
+		CreatedAt:        time.Now(),
+		LastSentAt:       time.Now(),
+	}
+	sub.UnsubTokenHash = hashToken(unsubscribeToken(ctx, sub.SubscriptionID))
+	err = repo.Replace(ctx, sub)
+	if errors.Is(err, store.ErrConflict) {
+		c.JSON(http.StatusOK, gin.H{"message": "Already subscribed"})
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create subscription"))
+		return
+	}
+
+	msg, err := email.Render("subscription_confirm", confirmEmail{
+		Frequency:  sub.Frequency,
+		ConfirmURL: fmt.Sprintf("%s/api/v1/subscriptions/confirm/%s", baseURL(ctx), confirmToken),
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not render confirmation email"))
+		return
+	}
+	msg.To = sub.Email
+	msg.UserID = userID
+	if err := email.Enqueue(ctx, msg); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not queue confirmation email"))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Confirmation email sent"})
+}
+
+// ConfirmPage serves the page the confirmation email links to, which confirms the subscription once the
+// visitor submits it. Opening the link changes nothing, so mail scanners following it don't confirm anything.
+//
+//	@Summary		Show the confirmation page of a journal digest subscription
+//	@Description	Serves the page the confirmation email links to, with a button posting the token back to confirm the subscription. Opening the page changes nothing.
+//	@Tags			Subscriptions
+//	@Produce		html
+//	@Param			token	path		string	true	"Confirmation token"
+//	@Success		200		{string}	string	"Confirmation page"
+//	@Router			/subscriptions/confirm/{token} [get]
+func ConfirmPage(c *gin.Context) {
+	renderPage(c, http.StatusOK, "Confirm your journal digest subscription", "Confirm subscription")
+}
+
+// ConfirmSubscription activates a subscription using the token from the confirmation email.
+//
+//	@Summary		Confirm a journal digest subscription
+//	@Description	Activates a pending subscription using the token sent in the confirmation email. Browsers submitting the confirmation page are answered with a page, other clients with JSON.
+//	@Tags			Subscriptions
+//	@Produce		json
+//	@Produce		html
+//	@Param			token	path		string			true	"Confirmation token"
+//	@Success		200		{object}	JSONResponse	"Subscription confirmed"
+//	@Failure		404		{object}	apierror.Response	"Subscription not found"
+//	@Failure		500		{object}	apierror.Response	"Could not confirm subscription"
+//	@Router			/subscriptions/confirm/{token} [post]
+func ConfirmSubscription(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	err := repo.Confirm(ctx, hashToken(c.Param("token")), time.Now())
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Subscription not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not confirm subscription"))
+		return
+	}
+
+	respond(c, "Subscription confirmed")
+}
+
+// UnsubscribePage serves the page the unsubscribe link of digest emails opens, which removes the subscription
+// once the visitor submits it. Opening the link changes nothing.
+//
+//	@Summary		Show the unsubscribe page of a journal digest subscription
+//	@Description	Serves the page the unsubscribe link of every digest email opens, with a button posting the token back to unsubscribe. Opening the page changes nothing.
+//	@Tags			Subscriptions
+//	@Produce		html
+//	@Param			token	path		string	true	"Unsubscribe token"
+//	@Success		200		{string}	string	"Unsubscribe page"
+//	@Router			/subscriptions/unsubscribe/{token} [get]
+func UnsubscribePage(c *gin.Context) {
+	renderPage(c, http.StatusOK, "Unsubscribe from this journal digest", "Unsubscribe")
+}
+
+// Unsubscribe removes a subscription using the token included in every digest email.
+//
+//	@Summary		Unsubscribe from a journal digest
+//	@Description	Removes a subscription using the unsubscribe token included in every digest email. Browsers submitting the unsubscribe page are answered with a page, other clients with JSON.
+//	@Tags			Subscriptions
+//	@Produce		json
+//	@Produce		html
+//	@Param			token	path		string			true	"Unsubscribe token"
+//	@Success		200		{object}	JSONResponse	"Unsubscribed"
+//	@Failure		404		{object}	apierror.Response	"Subscription not found"
+//	@Failure		500		{object}	apierror.Response	"Could not unsubscribe"
+//	@Router			/subscriptions/unsubscribe/{token} [post]
+func Unsubscribe(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	err := repo.Unsubscribe(ctx, hashToken(c.Param("token")))
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Abort(c, apierror.NotFound("Subscription not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not unsubscribe"))
+		return
+	}
+
+	respond(c, "Unsubscribed")
+}
+
+var publicBaseURL = "http://localhost:8080"
+
+// hashToken returns the hash a link token is stored and looked up by
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// unsubscribeToken returns the token of the subscription's unsubscribe link. It is derived from the
+// subscription rather than stored, as every digest repeats the link.
+func unsubscribeToken(ctx context.Context, subscriptionID string) string {
+	return auth.Sign("journal-unsubscribe", tenant.ID(ctx)+"/"+subscriptionID)
+}
+
+// withheld reports whether a moderator hid the user's profile or the user restricted it to their allowlist,
+// so nobody may subscribe to their journal and no digests of it are sent
+func withheld(ctx context.Context, userID string) (bool, error) {
+	hidden, err := moderation.ProfileHidden(ctx, userID)
+	if err != nil || hidden {
+		return hidden, err
+	}
+	return privacy.Restricted(ctx, userID)
+}
+
+// SetBaseURL sets the public base URL used for links in emails
+func SetBaseURL(url string) {
+	publicBaseURL = strings.TrimSuffix(url, "/")
+}
+
+// baseURL returns the public base URL used for links in emails, which tenants may override
+func baseURL(ctx context.Context) string {
+	if t, ok := tenant.Current(ctx); ok && t.PublicBaseURL != "" {
+		return strings.TrimSuffix(t.PublicBaseURL, "/")
+	}
+	return publicBaseURL
+}
+
+// InitializeRoutes initializes the subscription routes
+func InitializeRoutes(router *gin.RouterGroup, r Repository, journalRepo journal.Repository, u auth.Repository) {
+	repo = r
+	journals = journalRepo
+	users = u
+
+	router.POST("/:userid", limitAddress(), Subscribe)
+	router.GET("/confirm/:token", ConfirmPage)
+	router.POST("/confirm/:token", ConfirmSubscription)
+	router.GET("/unsubscribe/:token", UnsubscribePage)
+	router.POST("/unsubscribe/:token", Unsubscribe)
+}
@@ -0,0 +1,155 @@
+// Package tenant lets one deployment serve several independent profile sites. Each request is assigned the
+// tenant matching its Host header, which travels in the context to the repositories, background jobs and
+// event listeners so every tenant's data stays in its own database. Without configured tenants the server
+// runs as a single site and every context belongs to the default tenant, whose ID is empty.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"profile-api/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Default is the ID of the only tenant of a single-site deployment
+const Default = ""
+
+var (
+	tenants  []config.TenantConfig
+	byID     = map[string]config.TenantConfig{}
+	byDomain = map[string]config.TenantConfig{}
+	branding config.BrandingConfig
+)
+
+type contextKey struct{}
+
+// Configure sets the tenants served by the deployment, and the branding used when there are none
+func Configure(cfgs []config.TenantConfig, defaultBranding config.BrandingConfig) {
+	tenants = cfgs
+	branding = defaultBranding
+	for _, t := range cfgs {
+		byID[t.ID] = t
+		for _, domain := range t.Domains {
+			byDomain[strings.ToLower(domain)] = t
+		}
+	}
+}
+
+// Enabled reports whether the deployment serves several tenants
+func Enabled() bool {
+	return len(tenants) > 0
+}
+
+// All returns the configured tenants
+func All() []config.TenantConfig {
+	return tenants
+}
+
+// Resolve returns the tenant served on the host, matching its domains first and then its ID against the subdomain
+func Resolve(host string) (config.TenantConfig, bool) {
+	hostname, _, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname = host
+	}
+	hostname = strings.ToLower(hostname)
+
+	if t, ok := byDomain[hostname]; ok {
+		return t, true
+	}
+	if parts := strings.Split(hostname, "."); len(parts) > 2 {
+		t, ok := byID[parts[0]]
+		return t, ok
+	}
+	return config.TenantConfig{}, false
+}
+
+// WithID returns a context belonging to the tenant
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// ID returns the ID of the tenant the context belongs to
+func ID(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Current returns the configuration of the tenant the context belongs to, false for the default tenant
+func Current(ctx context.Context) (config.TenantConfig, bool) {
+	t, ok := byID[ID(ctx)]
+	return t, ok
+}
+
+// Each adapts a task, such as a scheduled job, to run once for every tenant
+func Each(task func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if !Enabled() {
+			return task(ctx)
+		}
+		var errs []error
+		for _, t := range tenants {
+			if err := task(WithID(ctx, t.ID)); err != nil {
+				errs = append(errs, fmt.Errorf("tenant %s: %w", t.ID, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// Set holds a value per tenant, such as each tenant's repository
+type Set[V any] map[string]V
+
+// For returns the value of the tenant the context belongs to. A context without a known tenant is a
+// programming error, so it panics rather than risk reading or writing another tenant's data.
+func (s Set[V]) For(ctx context.Context) V {
+	v, ok := s[ID(ctx)]
+	if !ok {
+		panic(fmt.Sprintf("no storage for tenant %q", ID(ctx)))
+	}
+	return v
+}
+
+// Branding is the presentation of the current site
+type Branding struct {
+	Tenant       string `json:"tenant"`
+	Name         string `json:"name"`
+	LogoURL      string `json:"logoURL"`
+	PrimaryColor string `json:"primaryColor"`
+}
+
+// GetBranding returns the branding of the site the request was made to.
+//
+//	@Summary		Get site branding
+//	@Description	Returns the name, logo and colour of the site serving the request, chosen by its host in multi-tenant deployments
+//	@Tags			site
+//	@Produce		json
+//	@Success		200	{object}	Branding
+//	@Router			/site [get]
+func GetBranding(c *gin.Context) {
+	b := BrandingOf(c.Request.Context())
+	c.JSON(http.StatusOK, Branding{
+		Tenant:       ID(c.Request.Context()),
+		Name:         b.Name,
+		LogoURL:      b.LogoURL,
+		PrimaryColor: b.PrimaryColor,
+	})
+}
+
+// BrandingOf returns the branding of the site the context belongs to
+func BrandingOf(ctx context.Context) config.BrandingConfig {
+	if t, ok := Current(ctx); ok {
+		return t.Branding
+	}
+	return branding
+}
+
+// InitializeRoutes registers the site routes
+func InitializeRoutes(router gin.IRoutes) {
+	router.GET("/site", GetBranding)
+}
@@ -0,0 +1,89 @@
+// Package timeouts gives the routes of the uploads and exports classes their own read and write timeouts,
+// extending the server's default ones, so a large upload or response isn't cut off by the timeout suited
+// to ordinary requests.
+package timeouts
+
+import (
+	"net/http"
+	"time"
+
+	"profile-api/config"
+	"profile-api/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// chunkSize is the most of an export written under one write deadline
+const chunkSize = 64 << 10
+
+// Middleware sets the read and write deadlines of requests to the routes of the uploads and exports
+// classes. Other routes keep the server's, which are the default class's. It must run before any
+// middleware replacing the response writer.
+func Middleware(cfg config.TimeoutsConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		class := cfg.Routes[c.Request.Method+" "+c.FullPath()]
+		var timeouts config.RouteTimeouts
+		switch class {
+		case "uploads":
+			timeouts = cfg.Uploads
+		case "exports":
+			timeouts = cfg.Exports
+		default:
+			c.Next()
+			return
+		}
+
+		rc := http.NewResponseController(c.Writer)
+		if err := rc.SetReadDeadline(deadline(timeouts.Read)); err != nil {
+			logging.Logger(c).Warn("Could not set read deadline", "error", err)
+		}
+		if err := rc.SetWriteDeadline(deadline(timeouts.Write)); err != nil {
+			logging.Logger(c).Warn("Could not set write deadline", "error", err)
+		}
+		if class == "exports" && timeouts.Write > 0 {
+			c.Writer = &progressWriter{ResponseWriter: c.Writer, rc: rc, timeout: timeouts.Write.Std()}
+		}
+		c.Next()
+	}
+}
+
+// deadline returns when a timeout running from now expires, or no deadline when it is zero
+func deadline(timeout config.Duration) time.Time {
+	if timeout <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(timeout.Std())
+}
+
+// progressWriter streams the response in chunks, moving the write deadline forward before each one, so
+// the timeout bounds how long the client takes to accept a chunk rather than the whole response
+type progressWriter struct {
+	gin.ResponseWriter
+	rc      *http.ResponseController
+	timeout time.Duration
+}
+
+func (w *progressWriter) Write(data []byte) (int, error) {
+	written := 0
+	for len(data) > 0 {
+		chunk := data[:min(len(data), chunkSize)]
+		// The deadline only moves when the connection supports it, otherwise the server's stays
+		w.rc.SetWriteDeadline(time.Now().Add(w.timeout))
+		n, err := w.ResponseWriter.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		data = data[n:]
+	}
+	return written, nil
+}
+
+func (w *progressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Unwrap lets handlers reach the connection's deadlines through their own response controller
+func (w *progressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
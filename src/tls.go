@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"profile-api/config"
+	"profile-api/domains"
+	"profile-api/server"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// tlsEnabled reports whether the server should serve HTTPS
+func tlsEnabled(cfg config.TLSConfig) bool {
+	return cfg.Autocert.Enabled || (cfg.CertFile != "" && cfg.KeyFile != "")
+}
+
+// autocertManager creates the ACME manager for the configured domains, keeping its account key and
+// certificates in the cache. Certificates are obtained on the first handshake naming a host, through
+// the TLS-ALPN-01 challenge or HTTP-01 on the plain HTTP listener, and renewed before they expire.
+func autocertManager(cfg config.AutocertConfig, cache autocert.Cache) *autocert.Manager {
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: hostPolicy(cfg.Domains, cfg.CustomDomains),
+		Cache:      cache,
+		Email:      cfg.Email,
+	}
+}
+
+// autocertCache returns where certificates are kept: the cache directory, which may be a mounted volume,
+// or the main Mongo database
+func autocertCache(cfg *config.Config, deps *server.Deps) autocert.Cache {
+	if cfg.TLS.Autocert.Cache == "mongo" {
+		return domains.NewMongoCertCache(deps.Mongo.Database(cfg.Mongo.Database))
+	}
+	return autocert.DirCache(cfg.TLS.Autocert.CacheDir)
+}
+
+// hostPolicy allows the configured hosts, and the verified custom domains when customDomains is set. An
+// entry of the form "*.example.com" allows any single-level subdomain so each user's profile subdomain
+// gets its own certificate.
+func hostPolicy(hosts []string, customDomains bool) autocert.HostPolicy {
+	exact := map[string]bool{}
+	var wildcards []string
+	for _, d := range hosts {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if strings.HasPrefix(d, "*.") {
+			wildcards = append(wildcards, d[1:])
+		} else if d != "" {
+			exact[d] = true
+		}
+	}
+
+	return func(ctx context.Context, host string) error {
+		host = strings.ToLower(host)
+		if exact[host] {
+			return nil
+		}
+		for _, suffix := range wildcards {
+			if strings.HasSuffix(host, suffix) && !strings.Contains(strings.TrimSuffix(host, suffix), ".") {
+				return nil
+			}
+		}
+		if customDomains {
+			if _, ok := domains.Resolve(ctx, host); ok {
+				return nil
+			}
+		}
+		return fmt.Errorf("host %q is not allowed", host)
+	}
+}
+
+// configureTLS sets up the server's TLS config and returns the handler for the plain HTTP listener,
+// or nil when no HTTP listener is required.
+func configureTLS(s *http.Server, cfg config.TLSConfig, httpsPort int, cache autocert.Cache) http.Handler {
+	var redirect http.Handler
+	if cfg.RedirectHTTP {
+		redirect = redirectToHTTPS(httpsPort)
+	}
+
+	if cfg.Autocert.Enabled {
+		m := autocertManager(cfg.Autocert, cache)
+		s.TLSConfig = m.TLSConfig()
+		if cfg.Autocert.CustomDomains {
+			// Certificates of custom domains detached since they were obtained are no longer served
+			getCertificate := s.TLSConfig.GetCertificate
+			s.TLSConfig.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+				if err := m.HostPolicy(hello.Context(), hello.ServerName); err != nil {
+					return nil, err
+				}
+				return getCertificate(hello)
+			}
+		}
+		// The HTTP listener is required to answer HTTP-01 challenges. Without a fallback the manager redirects
+		// every other request to HTTPS, so one is always given.
+		if redirect == nil {
+			redirect = http.NotFoundHandler()
+		}
+		return m.HTTPHandler(redirect)
+	}
+
+	s.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	return redirect
+}
+
+// redirectToHTTPS permanently redirects plain HTTP requests to the HTTPS listener
+func redirectToHTTPS(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requireIfMatch makes writes to resources with revisions fail without an If-Match header
+var requireIfMatch bool
+
+// SetRequireIfMatch sets whether writes to resources with revisions must send an If-Match header
+func SetRequireIfMatch(required bool) {
+	requireIfMatch = required
+}
+
+// ConditionalJSON responds with the body as JSON along with an ETag and, when known, a Last-Modified header.
+// If the request's If-None-Match or If-Modified-Since header shows the client already has this version,
+// a 304 Not Modified is sent instead of the body.
+func ConditionalJSON(c *gin.Context, body any, lastModified time.Time) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not encode response"))
+		return
+	}
+	sum := sha256.Sum256(data)
+	conditionalData(c, data, `W/"`+hex.EncodeToString(sum[:16])+`"`, lastModified)
+}
+
+// RevisionJSON is ConditionalJSON for a resource with a revision, tagging it with RevisionETag so clients
+// can send the tag back in If-Match when writing the resource
+func RevisionJSON(c *gin.Context, body any, revision int, lastModified time.Time) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not encode response"))
+		return
+	}
+	conditionalData(c, data, RevisionETag(revision), lastModified)
+}
+
+// RevisionETag is the strong entity tag of a resource at the revision
+func RevisionETag(revision int) string {
+	return `"` + strconv.Itoa(revision) + `"`
+}
+
+// Precondition is the revision a write requires the resource to be at, from the If-Match header
+type Precondition struct {
+	Revision int
+	// Conditional is false when any revision may be written, as for If-Match: * or no If-Match at all
+	Conditional bool
+}
+
+// Allows reports whether the resource at the revision may be written
+func (p Precondition) Allows(revision int) bool {
+	return !p.Conditional || p.Revision == revision
+}
+
+// IfMatch reads the request's If-Match header, failing when it is required but missing. A header holding
+// anything but a single revision tag can never match.
+func IfMatch(c *gin.Context) (Precondition, *apierror.Error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	switch header {
+	case "":
+		if requireIfMatch {
+			return Precondition{}, apierror.PreconditionRequired("If-Match is required, send the ETag of the resource being changed")
+		}
+		return Precondition{}, nil
+	case "*":
+		return Precondition{}, nil
+	}
+	// Weak tags never match for If-Match
+	if len(header) > 2 && header[0] == '"' && header[len(header)-1] == '"' {
+		if revision, err := strconv.Atoi(header[1 : len(header)-1]); err == nil && revision >= 0 {
+			return Precondition{Revision: revision, Conditional: true}, nil
+		}
+	}
+	return Precondition{}, apierror.PreconditionFailed("If-Match does not match the current version")
+}
+
+// conditionalData responds with the JSON data and its ETag, or with 304 Not Modified. HEAD requests get
+// the same headers, including the length of the data, without the data itself.
+func conditionalData(c *gin.Context, data []byte, etag string, lastModified time.Time) {
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if NotModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Header("Content-Length", strconv.Itoa(len(data)))
+	if c.Request.Method == http.MethodHead {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		c.Status(http.StatusOK)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// NotModified reports whether the request's conditional headers match the current ETag or modification time.
+// If-None-Match takes precedence over If-Modified-Since as required by RFC 9110.
+func NotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return ETagMatches(inm, etag)
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		// HTTP dates have second precision
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// ETagMatches reports whether a comma separated list of entity tags contains the given tag, using weak comparison
+func ETagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var operationTimeout = 5 * time.Second
+
+// SetOperationTimeout sets how long a single database operation may run while serving a request
+func SetOperationTimeout(d time.Duration) {
+	operationTimeout = d
+}
+
+// DBContext returns a context for database calls made while serving the request. It is
+// cancelled when the client disconnects or the operation timeout elapses, whichever is first.
+func DBContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	return WithOperationTimeout(c.Request.Context())
+}
+
+// WithOperationTimeout derives a context bounded by the operation timeout, for work that is
+// not tied to a single request such as background workers or per-item operations in a loop
+func WithOperationTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, operationTimeout)
+}
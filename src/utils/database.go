@@ -2,29 +2,61 @@ package utils
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// ConnectDB creates a connection to the MongoDB database and returns a reference to the client
-func ConnectDB(uri string) (*mongo.Client, error) {
-	//clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
+// maxConnectBackoff caps the wait between attempts to reach MongoDB at startup
+const maxConnectBackoff = 30 * time.Second
+
+// ConnectRetry is how connecting to MongoDB is retried at startup while it can't be reached
+type ConnectRetry struct {
+	// Retries is how many more times it is tried after the first attempt
+	Retries int
+	// Backoff is the wait before the first retry, doubled for each following one
+	Backoff time.Duration
+	// Timeout bounds each attempt
+	Timeout time.Duration
+}
+
+// ConnectDB creates a connection to the MongoDB database and returns a reference to the client.
+// Any extra options are applied after the URI, such as a command monitor. A server that can't be reached
+// is tried again as set by retry, until ctx is cancelled.
+func ConnectDB(ctx context.Context, uri string, retry ConnectRetry, opts ...*options.ClientOptions) (*mongo.Client, error) {
 	clientOptions := options.Client().ApplyURI(uri).SetServerAPIOptions(options.ServerAPI(options.ServerAPIVersion1))
-	client, err := mongo.Connect(context.Background(), clientOptions)
+	// The client connects lazily, so this only fails on invalid options, which no retry fixes
+	client, err := mongo.Connect(ctx, append([]*options.ClientOptions{clientOptions}, opts...)...)
 	if err != nil {
-		log.Fatalf("Error connecting to MongoDB: %v", err)
-		panic(err)
+		return nil, err
 	}
-	//defer client.Disconnect(context.Background())
-	err = client.Ping(context.Background(), nil)
-	if err != nil {
-		log.Fatalf("Error pinging MongoDB: %v", err)
-		panic(err)
+
+	backoff := retry.Backoff
+	for attempt := 0; ; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, retry.Timeout)
+		err = client.Ping(pingCtx, nil)
+		cancel()
+		if err == nil {
+			break
+		}
+		if attempt >= retry.Retries {
+			client.Disconnect(context.WithoutCancel(ctx))
+			return nil, fmt.Errorf("error pinging MongoDB after %d attempts: %w", attempt+1, err)
+		}
+		slog.Warn("Could not reach MongoDB, retrying", "attempt", attempt+1, "retry_in", backoff.String(), "error", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			client.Disconnect(context.WithoutCancel(ctx))
+			return nil, fmt.Errorf("error pinging MongoDB: %w", err)
+		}
+		backoff = min(2*backoff, maxConnectBackoff)
 	}
-	log.Println("Connected to MongoDB!")
+	slog.Info("Connected to MongoDB")
 	return client, nil
 }
 
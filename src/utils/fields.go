@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+
+	"profile-api/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Fields reads the fields query parameter, a comma-separated list of the JSON fields of the model to respond
+// with, such as ?fields=name,bio,profile_img. It returns nil when every field is wanted.
+func Fields(c *gin.Context, model any) ([]string, *apierror.Error) {
+	query := c.Query("fields")
+	if query == "" {
+		return nil, nil
+	}
+	known := jsonFields(reflect.TypeOf(model))
+	var fields []string
+	for _, field := range strings.Split(query, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || slices.Contains(fields, field) {
+			continue
+		}
+		if !slices.Contains(known, field) {
+			return nil, apierror.BadRequest("Unknown field " + field).WithDetails(gin.H{"fields": known})
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// Project reduces the body, an object or an array of objects, to the fields. The body is returned as it
+// is when fields is nil.
+func Project(body any, fields []string) (any, error) {
+	if fields == nil {
+		return body, nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Keep numbers as they were written rather than converting them to float64
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	project := func(value any) any {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		projected := make(map[string]any, len(fields))
+		for _, field := range fields {
+			if v, ok := object[field]; ok {
+				projected[field] = v
+			}
+		}
+		return projected
+	}
+	if items, ok := decoded.([]any); ok {
+		for i, item := range items {
+			items[i] = project(item)
+		}
+		return items, nil
+	}
+	return project(decoded), nil
+}
+
+// jsonFields returns the names the struct type's fields are given in JSON
+func jsonFields(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.IsExported() && name != "-" {
+			if name == "" {
+				name = field.Name
+			}
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// illegalOperation is the server error code returned when a standalone server is asked to start a transaction
+const illegalOperation = 20
+
+// RunInTransaction runs fn inside a multi-document transaction so its writes are applied together or not at all.
+// fn must use the context it is given and may be retried on transient errors. Standalone servers do not
+// support transactions, so there fn is run once without one.
+func RunInTransaction(ctx context.Context, client *mongo.Client, fn func(ctx context.Context) error) error {
+	session, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	if isTransactionUnsupported(err) {
+		slog.Debug("Transactions are not supported by the server, writing without one")
+		return fn(ctx)
+	}
+	return err
+}
+
+// isTransactionUnsupported reports whether the error is a standalone server refusing a transaction
+func isTransactionUnsupported(err error) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.Code == illegalOperation && strings.Contains(cmdErr.Message, "Transaction numbers")
+	}
+	return false
+}
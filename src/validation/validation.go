@@ -0,0 +1,104 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"profile-api/visibility"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// dateLayouts are the accepted formats for partial dates such as the start of a role
+var dateLayouts = []string{"2006-01-02", "2006-01", "2006"}
+
+// Register adds the custom validators to Gin's binding engine and reports field names
+// using their JSON names. It must be called before any routes are served.
+func Register() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unexpected validator engine %T", binding.Validator.Engine())
+	}
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	validators := map[string]validator.Func{
+		"date":       isDate,
+		"weburl":     isWebURL,
+		"notblank":   isNotBlank,
+		"visibility": isVisibility,
+	}
+	for tag, fn := range validators {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return fmt.Errorf("error registering %s validator: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// isDate accepts a full or partial ISO 8601 date (YYYY-MM-DD, YYYY-MM or YYYY)
+func isDate(fl validator.FieldLevel) bool {
+	return IsDate(fl.Field().String())
+}
+
+// IsDate reports whether the value is a full or partial ISO 8601 date, as accepted by the date validator
+func IsDate(value string) bool {
+	_, ok := ParseDate(value)
+	return ok
+}
+
+// ParseDate parses a full or partial ISO 8601 date, placing a partial date on the first day of its month or
+// year
+func ParseDate(value string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isWebURL accepts absolute http and https URLs, or site-relative paths such as those returned by the local image store
+func isWebURL(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if strings.HasPrefix(value, "/") && !strings.HasPrefix(value, "//") {
+		return true
+	}
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isNotBlank rejects strings made up only of whitespace
+func isNotBlank(fl validator.FieldLevel) bool {
+	return strings.TrimSpace(fl.Field().String()) != ""
+}
+
+// isVisibility accepts visibility rules naming fields of the struct holding them and known levels
+func isVisibility(fl validator.FieldLevel) bool {
+	rules, ok := fl.Field().Interface().(visibility.Rules)
+	if !ok {
+		return false
+	}
+	var fields []string
+	t := fl.Parent().Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if t.Field(i).IsExported() && name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return visibility.Valid(rules, fields)
+}
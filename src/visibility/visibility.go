@@ -0,0 +1,214 @@
+// Package visibility hides the fields of users' documents from requesters who may not see them. Each
+// document can carry rules naming, for any of its fields, who it is shown to: everyone, signed in members,
+// or only its owner. Handlers write documents through this package, which removes the fields the requester
+// may not see before responding, so every module applies the rules the same way. Fields without a rule
+// are public unless the module's defaults say otherwise. Some documents can also be hidden as a whole, and
+// are then left out of lists.
+package visibility
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"profile-api/apierror"
+	"profile-api/apiversion"
+	"profile-api/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Levels of visibility, from the widest
+const (
+	// Public fields are shown to everyone
+	Public = "public"
+	// Members fields are shown to signed in users
+	Members = "members"
+	// Private fields are shown to the owner of the document and admins
+	Private = "private"
+)
+
+// Levels lists the levels a rule may name
+var Levels = []string{Public, Members, Private}
+
+// field is the JSON name of the field of a document holding its rules, which only the owner and admins see
+const field = "visibility"
+
+// Rules maps the JSON names of a document's fields to the level of requester they are shown to
+type Rules map[string]string
+
+// With returns the rules overridden by those of the document
+func (r Rules) With(overrides Rules) Rules {
+	merged := make(Rules, len(r)+len(overrides))
+	for name, level := range r {
+		merged[name] = level
+	}
+	for name, level := range overrides {
+		merged[name] = level
+	}
+	return merged
+}
+
+// Document is a user's document whose fields carry visibility rules
+type Document interface {
+	// Owner returns the ID of the user the document belongs to
+	Owner() string
+	// FieldVisibility returns the rules of the document's fields, including the module's defaults
+	FieldVisibility() Rules
+}
+
+// Restrictable is a document that may also be hidden as a whole, rather than field by field
+type Restrictable interface {
+	// DocumentVisibility returns the level of requester the document is shown to at all, or "" for everyone
+	DocumentVisibility() string
+}
+
+// ErrHidden is returned for a document hidden as a whole from the viewer
+var ErrHidden = errors.New("document hidden")
+
+// Viewer is who a document is shown to
+type Viewer struct {
+	// UserID is empty for anonymous requesters
+	UserID string
+	Admin  bool
+}
+
+// ViewerOf returns the requester, as authenticated by an earlier middleware
+func ViewerOf(c *gin.Context) Viewer {
+	user, _ := c.Get("user")
+	u, ok := user.(auth.User)
+	if !ok {
+		return Viewer{}
+	}
+	return Viewer{UserID: u.ID, Admin: u.Admin}
+}
+
+// Sees reports whether the viewer may see a field at the level of a document owned by the user. Unknown
+// levels are treated as private.
+func (v Viewer) Sees(ownerID, level string) bool {
+	switch level {
+	case Public:
+		return true
+	case Members:
+		return v.UserID != ""
+	default:
+		return v.Admin || (v.UserID != "" && v.UserID == ownerID)
+	}
+}
+
+// Redact returns the document as a JSON object without the fields the viewer may not see, or ErrHidden
+// when the viewer may not see the document at all
+func Redact(viewer Viewer, doc Document) (map[string]any, error) {
+	if r, ok := doc.(Restrictable); ok {
+		if level := r.DocumentVisibility(); level != "" && !viewer.Sees(doc.Owner(), level) {
+			return nil, ErrHidden
+		}
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Keep numbers as they were written rather than converting them to float64
+	decoder.UseNumber()
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+
+	owner := doc.Owner()
+	for name, level := range doc.FieldVisibility() {
+		if !viewer.Sees(owner, level) {
+			delete(object, name)
+		}
+	}
+	if !viewer.Sees(owner, Private) {
+		delete(object, field)
+	}
+	return object, nil
+}
+
+// RedactAll redacts each of the documents for the viewer, leaving out those hidden from them
+func RedactAll[T Document](viewer Viewer, docs []T) ([]map[string]any, error) {
+	redacted := make([]map[string]any, 0, len(docs))
+	for _, doc := range docs {
+		object, err := Redact(viewer, doc)
+		if errors.Is(err, ErrHidden) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		redacted = append(redacted, object)
+	}
+	return redacted, nil
+}
+
+// Strip returns a copy of the document with the fields the viewer may not see emptied, for responses built
+// from the document's fields rather than written as JSON
+func Strip[T Document](viewer Viewer, doc T) (T, error) {
+	var stripped T
+	object, err := Redact(viewer, doc)
+	if err != nil {
+		return stripped, err
+	}
+	data, err := json.Marshal(object)
+	if err != nil {
+		return stripped, err
+	}
+	err = json.Unmarshal(data, &stripped)
+	return stripped, err
+}
+
+// StripAll strips each of the documents for the viewer, leaving out those hidden from them
+func StripAll[T Document](viewer Viewer, docs []T) ([]T, error) {
+	stripped := make([]T, 0, len(docs))
+	for _, doc := range docs {
+		item, err := Strip(viewer, doc)
+		if errors.Is(err, ErrHidden) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		stripped = append(stripped, item)
+	}
+	return stripped, nil
+}
+
+// Valid reports whether every rule names one of the fields and a known level
+func Valid(rules Rules, fields []string) bool {
+	for name, level := range rules {
+		if name == field || !slices.Contains(fields, name) || !slices.Contains(Levels, level) {
+			return false
+		}
+	}
+	return true
+}
+
+// OK responds 200 with the document as the requester may see it, or 404 when it is hidden from them
+func OK(c *gin.Context, doc Document) {
+	c.Writer.Header().Add("Vary", "Cookie")
+	object, err := Redact(ViewerOf(c), doc)
+	if errors.Is(err, ErrHidden) {
+		apierror.Abort(c, apierror.NotFound("Not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not encode response"))
+		return
+	}
+	apiversion.OK(c, object)
+}
+
+// List responds 200 with the documents as the requester may see them
+func List[T Document](c *gin.Context, docs []T) {
+	c.Writer.Header().Add("Vary", "Cookie")
+	objects, err := RedactAll(ViewerOf(c), docs)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not encode response"))
+		return
+	}
+	apiversion.List(c, objects)
+}
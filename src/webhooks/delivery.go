@@ -0,0 +1,200 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"profile-api/config"
+	"profile-api/events"
+	"profile-api/jobs"
+	"profile-api/netutil"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+)
+
+// DeliverJob is the type of the background job delivering an event to a webhook
+const DeliverJob = "webhooks.deliver"
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+var repo Repository
+var settings = config.WebhooksConfig{Timeout: config.Duration(10 * time.Second)}
+var client = newClient(settings)
+
+type deliverPayload struct {
+	DeliveryID string `json:"deliveryID"`
+}
+
+// Configure sets the repository and delivery settings, registers the delivery job and starts forwarding
+// published events. It must be called before the job workers start.
+func Configure(r Repository, cfg config.WebhooksConfig) {
+	repo = r
+	settings = cfg
+	client = newClient(cfg)
+
+	jobs.Register(DeliverJob, func(ctx context.Context, job jobs.Job) error {
+		var payload deliverPayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return err
+		}
+		return deliver(ctx, payload.DeliveryID, job.Attempts >= job.MaxAttempts)
+	})
+	events.Listen(dispatch)
+}
+
+// newClient creates the client used for deliveries, refusing to connect to private addresses unless allowed
+func newClient(cfg config.WebhooksConfig) *http.Client {
+	return netutil.SafeClient(cfg.Timeout.Std(), cfg.AllowPrivateNetworks)
+}
+
+// dispatch queues a delivery of the event to every webhook subscribed to it
+func dispatch(event events.Event) {
+	ctx, cancel := utils.WithOperationTimeout(tenant.WithID(context.Background(), event.Tenant))
+	defer cancel()
+
+	hooks, err := repo.Matching(ctx, event.UserID, event.Type)
+	if err != nil {
+		slog.Error("Could not find webhooks for event", "event", event.Type, "user_id", event.UserID, "error", err)
+		return
+	}
+	for _, hook := range hooks {
+		if err := queueDelivery(ctx, hook, event); err != nil {
+			slog.Error("Could not queue webhook delivery", "webhook_id", hook.ID, "event", event.Type, "error", err)
+		}
+	}
+}
+
+// queueDelivery records a pending delivery and queues the job sending it
+func queueDelivery(ctx context.Context, hook Webhook, event events.Event) error {
+	deliveryID := utils.GenerateID()
+	payload, err := json.Marshal(envelope{
+		ID:        deliveryID,
+		Event:     event.Type,
+		UserID:    event.UserID,
+		CreatedAt: event.Time,
+		Data:      event.Data,
+	})
+	if err != nil {
+		return err
+	}
+	delivery := Delivery{
+		ID:        deliveryID,
+		WebhookID: hook.ID,
+		UserID:    event.UserID,
+		Event:     event.Type,
+		Payload:   payload,
+		Status:    StatusPending,
+		CreatedAt: event.Time,
+		UpdatedAt: event.Time,
+	}
+	if err := repo.SaveDelivery(ctx, delivery); err != nil {
+		return err
+	}
+	return jobs.Enqueue(ctx, DeliverJob, deliverPayload{DeliveryID: deliveryID})
+}
+
+// deliver posts the delivery to its webhook and records the outcome. An error is returned for the job
+// queue to retry the delivery with backoff; final marks the last attempt the queue will make.
+func deliver(ctx context.Context, deliveryID string, final bool) error {
+	delivery, err := repo.GetDelivery(ctx, deliveryID)
+	if errors.Is(err, store.ErrNotFound) {
+		// Purged or removed with its user, nothing left to send
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	hook, err := repo.Get(ctx, delivery.WebhookID)
+	if errors.Is(err, store.ErrNotFound) {
+		delivery.Status = StatusFailed
+		delivery.LastError = "webhook deleted"
+		delivery.UpdatedAt = time.Now()
+		return repo.SaveDelivery(ctx, delivery)
+	}
+	if err != nil {
+		return err
+	}
+
+	delivery.Attempts++
+	delivery.ResponseStatus, err = post(ctx, hook, delivery)
+	delivery.UpdatedAt = time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = StatusSucceeded
+		delivery.LastError = ""
+	case final:
+		delivery.Status = StatusFailed
+		delivery.LastError = err.Error()
+	default:
+		delivery.Status = StatusPending
+		delivery.LastError = err.Error()
+	}
+	if saveErr := repo.SaveDelivery(ctx, delivery); saveErr != nil {
+		slog.Error("Could not record webhook delivery", "delivery_id", delivery.ID, "error", saveErr)
+	}
+	return err
+}
+
+// post sends the signed delivery, returning the response status and an error unless it is 2xx
+func post(ctx context.Context, hook Webhook, delivery Delivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "profile-api-webhooks")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(hook.Secret, timestamp, delivery.Payload))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the hex HMAC-SHA256 of the timestamp and body, joined by a dot, keyed with the webhook's secret.
+// Receivers recompute it to verify a delivery and reject stale timestamps to prevent replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// PurgeDeliveries removes delivery history older than the configured retention
+func PurgeDeliveries(ctx context.Context) error {
+	removed, err := repo.PurgeDeliveries(ctx, time.Now().Add(-settings.Retention.Std()))
+	if err != nil {
+		return err
+	}
+	slog.Info("Purged webhook deliveries", "removed", removed)
+	return nil
+}
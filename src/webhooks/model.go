@@ -0,0 +1,63 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Webhook is a URL subscribed to some of its owner's events. Global webhooks, which only admins
+// may create, receive the events of every user.
+type Webhook struct {
+	ID        string    `bson:"_id" json:"id"`
+	UserID    string    `bson:"user_id" json:"userID"`
+	URL       string    `bson:"url" json:"url"`
+	Events    []string  `bson:"events" json:"events"`
+	Global    bool      `bson:"global" json:"global"`
+	Secret    string    `bson:"secret" json:"-"`
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+}
+
+// CreatedWebhook is returned when a webhook is created, the only time its signing secret is shown
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// CreateWebhookRequest represents the request body for subscribing a URL to events
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=profile.updated certificate.created certificate.archived user.registered journal.status_changed journal.processed document.changed notification.created"`
+	Global bool     `json:"global"`
+}
+
+// Delivery records the attempts to deliver one event to one webhook
+type Delivery struct {
+	ID        string `bson:"_id" json:"id"`
+	WebhookID string `bson:"webhook_id" json:"webhookID"`
+	// UserID is the user the event is about
+	UserID         string          `bson:"user_id" json:"userID"`
+	Event          string          `bson:"event" json:"event"`
+	Payload        json.RawMessage `bson:"payload" json:"payload" swaggertype:"object"`
+	Status         string          `bson:"status" json:"status"`
+	Attempts       int             `bson:"attempts" json:"attempts"`
+	ResponseStatus int             `bson:"response_status,omitempty" json:"responseStatus,omitempty"`
+	LastError      string          `bson:"last_error,omitempty" json:"lastError,omitempty"`
+	CreatedAt      time.Time       `bson:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time       `bson:"updated_at" json:"updatedAt"`
+}
+
+// envelope is the body posted to a webhook
+type envelope struct {
+	ID        string    `json:"id"`
+	Event     string    `json:"event"`
+	UserID    string    `json:"userID"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
@@ -0,0 +1,28 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores webhooks and their delivery history
+type Repository interface {
+	// Create stores a new webhook
+	Create(ctx context.Context, hook Webhook) error
+	// Get returns the webhook with the given ID, or store.ErrNotFound
+	Get(ctx context.Context, webhookID string) (Webhook, error)
+	// List returns the webhooks owned by the user
+	List(ctx context.Context, userID string) ([]Webhook, error)
+	// Delete removes the webhook, or returns store.ErrNotFound
+	Delete(ctx context.Context, webhookID string) error
+	// Matching returns the user's own and the global webhooks subscribed to the event
+	Matching(ctx context.Context, userID, event string) ([]Webhook, error)
+	// SaveDelivery inserts or replaces a delivery
+	SaveDelivery(ctx context.Context, delivery Delivery) error
+	// GetDelivery returns the delivery with the given ID, or store.ErrNotFound
+	GetDelivery(ctx context.Context, deliveryID string) (Delivery, error)
+	// ListDeliveries returns up to limit deliveries to the webhook, newest first
+	ListDeliveries(ctx context.Context, webhookID string, limit int) ([]Delivery, error)
+	// PurgeDeliveries removes deliveries created before the given time, returning how many were removed
+	PurgeDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,37 @@
+package webhooks
+
+import (
+	"context"
+
+	"profile-api/audit"
+)
+
+// AuditedRepository records every change to webhooks in the audit log. Deliveries are not recorded.
+type AuditedRepository struct {
+	Repository
+}
+
+// NewAuditedRepository wraps the repository to record its changes in the audit log
+func NewAuditedRepository(r Repository) *AuditedRepository {
+	return &AuditedRepository{Repository: r}
+}
+
+func (r *AuditedRepository) Create(ctx context.Context, hook Webhook) error {
+	if err := r.Repository.Create(ctx, hook); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionCreate, "webhook", hook.UserID, hook.ID, nil, hook)
+	return nil
+}
+
+func (r *AuditedRepository) Delete(ctx context.Context, webhookID string) error {
+	before, err := r.Repository.Get(ctx, webhookID)
+	if err != nil {
+		return r.Repository.Delete(ctx, webhookID)
+	}
+	if err := r.Repository.Delete(ctx, webhookID); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionDelete, "webhook", before.UserID, webhookID, before, nil)
+	return nil
+}
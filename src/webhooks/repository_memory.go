@@ -0,0 +1,116 @@
+package webhooks
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"profile-api/store"
+)
+
+// MemoryRepository keeps webhooks and deliveries in memory, for tests and demo mode
+type MemoryRepository struct {
+	mu         sync.RWMutex
+	webhooks   []Webhook
+	deliveries []Delivery
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, hook Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.webhooks = append(r.webhooks, hook)
+	return nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, webhookID string) (Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := slices.IndexFunc(r.webhooks, func(h Webhook) bool { return h.ID == webhookID })
+	if i < 0 {
+		return Webhook{}, store.ErrNotFound
+	}
+	return r.webhooks[i], nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, userID string) ([]Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var hooks []Webhook
+	for _, hook := range r.webhooks {
+		if hook.UserID == userID {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks, nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, webhookID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.webhooks, func(h Webhook) bool { return h.ID == webhookID })
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	r.webhooks = slices.Delete(r.webhooks, i, i+1)
+	return nil
+}
+
+func (r *MemoryRepository) Matching(ctx context.Context, userID, event string) ([]Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var hooks []Webhook
+	for _, hook := range r.webhooks {
+		if (hook.UserID == userID || hook.Global) && slices.Contains(hook.Events, event) {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks, nil
+}
+
+func (r *MemoryRepository) SaveDelivery(ctx context.Context, delivery Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.deliveries, func(d Delivery) bool { return d.ID == delivery.ID })
+	if i < 0 {
+		r.deliveries = append(r.deliveries, delivery)
+	} else {
+		r.deliveries[i] = delivery
+	}
+	return nil
+}
+
+func (r *MemoryRepository) GetDelivery(ctx context.Context, deliveryID string) (Delivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := slices.IndexFunc(r.deliveries, func(d Delivery) bool { return d.ID == deliveryID })
+	if i < 0 {
+		return Delivery{}, store.ErrNotFound
+	}
+	return r.deliveries[i], nil
+}
+
+func (r *MemoryRepository) ListDeliveries(ctx context.Context, webhookID string, limit int) ([]Delivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var deliveries []Delivery
+	for i := len(r.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if r.deliveries[i].WebhookID == webhookID {
+			deliveries = append(deliveries, r.deliveries[i])
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *MemoryRepository) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(r.deliveries)
+	r.deliveries = slices.DeleteFunc(r.deliveries, func(d Delivery) bool { return d.CreatedAt.Before(before) })
+	return int64(n - len(r.deliveries)), nil
+}
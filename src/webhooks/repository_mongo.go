@@ -0,0 +1,108 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository stores webhooks in the webhooks collection and deliveries in webhook_deliveries
+type MongoRepository struct {
+	webhooks   *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		webhooks:   db.Collection("webhooks"),
+		deliveries: db.Collection("webhook_deliveries"),
+	}
+}
+
+func (r *MongoRepository) Create(ctx context.Context, hook Webhook) error {
+	_, err := r.webhooks.InsertOne(ctx, hook)
+	return err
+}
+
+func (r *MongoRepository) Get(ctx context.Context, webhookID string) (Webhook, error) {
+	var hook Webhook
+	err := r.webhooks.FindOne(ctx, bson.M{"_id": webhookID}).Decode(&hook)
+	return hook, store.MongoErr(err)
+}
+
+func (r *MongoRepository) List(ctx context.Context, userID string) ([]Webhook, error) {
+	return r.find(ctx, bson.M{"user_id": userID})
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, webhookID string) error {
+	res, err := r.webhooks.DeleteOne(ctx, bson.M{"_id": webhookID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRepository) Matching(ctx context.Context, userID, event string) ([]Webhook, error) {
+	return r.find(ctx, bson.M{
+		"events": event,
+		"$or":    bson.A{bson.M{"user_id": userID}, bson.M{"global": true}},
+	})
+}
+
+// find returns the webhooks matching the filter, oldest first
+func (r *MongoRepository) find(ctx context.Context, filter bson.M) ([]Webhook, error) {
+	cursor, err := r.webhooks.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var hooks []Webhook
+	if err := cursor.All(ctx, &hooks); err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+func (r *MongoRepository) SaveDelivery(ctx context.Context, delivery Delivery) error {
+	_, err := r.deliveries.ReplaceOne(ctx, bson.M{"_id": delivery.ID}, delivery, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepository) GetDelivery(ctx context.Context, deliveryID string) (Delivery, error) {
+	var delivery Delivery
+	err := r.deliveries.FindOne(ctx, bson.M{"_id": deliveryID}).Decode(&delivery)
+	return delivery, store.MongoErr(err)
+}
+
+func (r *MongoRepository) ListDeliveries(ctx context.Context, webhookID string, limit int) ([]Delivery, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.deliveries.Find(ctx, bson.M{"webhook_id": webhookID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []Delivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *MongoRepository) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.deliveries.DeleteMany(ctx, bson.M{"created_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
@@ -0,0 +1,118 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"profile-api/store"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const webhookColumns = "id, user_id, url, events, global, secret, created_at"
+
+const deliveryColumns = `id, webhook_id, user_id, event, payload, status, attempts, response_status, last_error,
+	created_at, updated_at`
+
+// PostgresRepository stores webhooks in the webhooks table and deliveries in webhook_deliveries
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a repository backed by the given connection pool
+func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, hook Webhook) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO webhooks ("+webhookColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		hook.ID, hook.UserID, hook.URL, hook.Events, hook.Global, hook.Secret, hook.CreatedAt)
+	return store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, webhookID string) (Webhook, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE id = $1", webhookID)
+	if err != nil {
+		return Webhook{}, err
+	}
+	hook, err := pgx.CollectExactlyOneRow(rows, scanWebhook)
+	return hook, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) List(ctx context.Context, userID string) ([]Webhook, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanWebhook)
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, webhookID string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM webhooks WHERE id = $1", webhookID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Matching(ctx context.Context, userID, event string) ([]Webhook, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+webhookColumns+` FROM webhooks
+		WHERE (user_id = $1 OR global) AND $2 = ANY(events) ORDER BY created_at`, userID, event)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanWebhook)
+}
+
+func (r *PostgresRepository) SaveDelivery(ctx context.Context, d Delivery) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO webhook_deliveries ("+deliveryColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO UPDATE SET status = EXCLUDED.status, attempts = EXCLUDED.attempts,
+			response_status = EXCLUDED.response_status, last_error = EXCLUDED.last_error, updated_at = EXCLUDED.updated_at`,
+		d.ID, d.WebhookID, d.UserID, d.Event, d.Payload, d.Status, d.Attempts, d.ResponseStatus, d.LastError,
+		d.CreatedAt, d.UpdatedAt)
+	return err
+}
+
+func (r *PostgresRepository) GetDelivery(ctx context.Context, deliveryID string) (Delivery, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE id = $1", deliveryID)
+	if err != nil {
+		return Delivery{}, err
+	}
+	delivery, err := pgx.CollectExactlyOneRow(rows, scanDelivery)
+	return delivery, store.PostgresErr(err)
+}
+
+func (r *PostgresRepository) ListDeliveries(ctx context.Context, webhookID string, limit int) ([]Delivery, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+deliveryColumns+` FROM webhook_deliveries
+		WHERE webhook_id = $1 ORDER BY created_at DESC LIMIT $2`, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanDelivery)
+}
+
+func (r *PostgresRepository) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM webhook_deliveries WHERE created_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanWebhook(row pgx.CollectableRow) (Webhook, error) {
+	var hook Webhook
+	err := row.Scan(&hook.ID, &hook.UserID, &hook.URL, &hook.Events, &hook.Global, &hook.Secret, &hook.CreatedAt)
+	return hook, err
+}
+
+func scanDelivery(row pgx.CollectableRow) (Delivery, error) {
+	var d Delivery
+	err := row.Scan(&d.ID, &d.WebhookID, &d.UserID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.ResponseStatus,
+		&d.LastError, &d.CreatedAt, &d.UpdatedAt)
+	return d, err
+}
@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"profile-api/tenant"
+)
+
+// TenantRepository routes each call to the repository of the tenant the context belongs to
+type TenantRepository struct {
+	repos tenant.Set[Repository]
+}
+
+// NewTenantRepository creates a repository routing calls between the tenants' repositories
+func NewTenantRepository(repos tenant.Set[Repository]) *TenantRepository {
+	return &TenantRepository{repos: repos}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, hook Webhook) error {
+	return r.repos.For(ctx).Create(ctx, hook)
+}
+
+func (r *TenantRepository) Get(ctx context.Context, webhookID string) (Webhook, error) {
+	return r.repos.For(ctx).Get(ctx, webhookID)
+}
+
+func (r *TenantRepository) List(ctx context.Context, userID string) ([]Webhook, error) {
+	return r.repos.For(ctx).List(ctx, userID)
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, webhookID string) error {
+	return r.repos.For(ctx).Delete(ctx, webhookID)
+}
+
+func (r *TenantRepository) Matching(ctx context.Context, userID, event string) ([]Webhook, error) {
+	return r.repos.For(ctx).Matching(ctx, userID, event)
+}
+
+func (r *TenantRepository) SaveDelivery(ctx context.Context, delivery Delivery) error {
+	return r.repos.For(ctx).SaveDelivery(ctx, delivery)
+}
+
+func (r *TenantRepository) GetDelivery(ctx context.Context, deliveryID string) (Delivery, error) {
+	return r.repos.For(ctx).GetDelivery(ctx, deliveryID)
+}
+
+func (r *TenantRepository) ListDeliveries(ctx context.Context, webhookID string, limit int) ([]Delivery, error) {
+	return r.repos.For(ctx).ListDeliveries(ctx, webhookID, limit)
+}
+
+func (r *TenantRepository) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	return r.repos.For(ctx).PurgeDeliveries(ctx, before)
+}
@@ -0,0 +1,195 @@
+// Package webhooks delivers a user's events to the URLs they subscribe, signing each delivery with
+// the webhook's secret and retrying failed deliveries through the background job queue.
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// CreateWebhook subscribes a URL to some of the current user's events
+//
+//	@Summary		Create a webhook
+//	@Description	Subscribes a URL to events. Each delivery is a JSON POST signed with the returned secret: the X-Webhook-Signature header is "sha256=" followed by the hex HMAC-SHA256 of the X-Webhook-Timestamp header, a dot and the body. Failed deliveries are retried with backoff. Admins may create global webhooks receiving the events of every user.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		CreateWebhookRequest	true	"Webhook URL and events"
+//	@Success		201		{object}	CreatedWebhook
+//	@Failure		400		{object}	apierror.Response	"Invalid request body"
+//	@Failure		401		{object}	apierror.Response	"Not authenticated"
+//	@Failure		403		{object}	apierror.Response	"Admin access required for global webhooks"
+//	@Failure		500		{object}	apierror.Response	"Could not create webhook"
+//	@Router			/webhooks [post]
+func CreateWebhook(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Invalid(err))
+		return
+	}
+	if u, err := url.Parse(req.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		apierror.Abort(c, apierror.BadRequest("Webhook URL must be http or https"))
+		return
+	}
+	if req.Global && !user.Admin {
+		apierror.Abort(c, apierror.Forbidden("Admin access required for global webhooks"))
+		return
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create webhook"))
+		return
+	}
+	hook := Webhook{
+		ID:        utils.GenerateID(),
+		UserID:    user.ID,
+		URL:       req.URL,
+		Events:    req.Events,
+		Global:    req.Global,
+		Secret:    hex.EncodeToString(secret),
+		CreatedAt: time.Now(),
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Create(ctx, hook); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not create webhook"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreatedWebhook{Webhook: hook, Secret: hook.Secret})
+}
+
+// ListWebhooks lists the current user's webhooks
+//
+//	@Summary		List webhooks
+//	@Description	Lists the webhooks created by the current user. Secrets are not included.
+//	@Tags			webhooks
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		Webhook
+//	@Failure		401	{object}	apierror.Response	"Not authenticated"
+//	@Failure		500	{object}	apierror.Response	"Could not retrieve webhooks"
+//	@Router			/webhooks [get]
+func ListWebhooks(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	hooks, err := repo.List(ctx, user.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve webhooks"))
+		return
+	}
+	if hooks == nil {
+		hooks = []Webhook{}
+	}
+
+	c.JSON(http.StatusOK, hooks)
+}
+
+// DeleteWebhook removes one of the current user's webhooks
+//
+//	@Summary		Delete a webhook
+//	@Description	Removes a webhook. Deliveries still queued for it are abandoned.
+//	@Tags			webhooks
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			webhookid	path		string	true	"Webhook ID"
+//	@Success		200			{object}	map[string]string
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		404			{object}	apierror.Response	"Webhook not found"
+//	@Router			/webhooks/{webhookid} [delete]
+func DeleteWebhook(c *gin.Context) {
+	hook, ok := ownWebhook(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	if err := repo.Delete(ctx, hook.ID); err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Webhook not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted"})
+}
+
+// ListDeliveries lists the delivery history of one of the current user's webhooks
+//
+//	@Summary		List webhook deliveries
+//	@Description	Lists the deliveries made to a webhook, newest first, with their status, attempts and the last response status or error
+//	@Tags			webhooks
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			webhookid	path		string	true	"Webhook ID"
+//	@Param			limit		query		int		false	"Maximum number of deliveries to return (default 50, max 500)"
+//	@Success		200			{array}		Delivery
+//	@Failure		401			{object}	apierror.Response	"Not authenticated"
+//	@Failure		404			{object}	apierror.Response	"Webhook not found"
+//	@Failure		500			{object}	apierror.Response	"Could not retrieve deliveries"
+//	@Router			/webhooks/{webhookid}/deliveries [get]
+func ListDeliveries(c *gin.Context) {
+	limit := defaultDeliveryLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, maxDeliveryLimit)
+	}
+
+	hook, ok := ownWebhook(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	deliveries, err := repo.ListDeliveries(ctx, hook.ID, limit)
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not retrieve deliveries"))
+		return
+	}
+	if deliveries == nil {
+		deliveries = []Delivery{}
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// ownWebhook loads the webhook in the path, aborting with 404 unless it belongs to the current user
+func ownWebhook(c *gin.Context) (Webhook, bool) {
+	user := c.MustGet("user").(auth.User)
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	hook, err := repo.Get(ctx, c.Param("webhookid"))
+	if err != nil || hook.UserID != user.ID {
+		apierror.Abort(c, apierror.NotFound("Webhook not found"))
+		return Webhook{}, false
+	}
+	return hook, true
+}
+
+// InitializeRoutes initializes the webhook routes. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup, users auth.Repository) {
+	router.Use(auth.AuthMiddleware(users, true))
+	router.POST("", CreateWebhook)
+	router.GET("", ListWebhooks)
+	router.DELETE("/:webhookid", DeleteWebhook)
+	router.GET("/:webhookid/deliveries", ListDeliveries)
+}
@@ -0,0 +1,50 @@
+package widgets
+
+import "time"
+
+// SkillsWidget is the skills matrix of a user, for embedding on other sites
+type SkillsWidget struct {
+	UserID string `json:"userID"`
+	// Name is the user's name when their profile shows it to everyone
+	Name   string        `json:"name,omitempty"`
+	Skills []WidgetSkill `json:"skills"`
+}
+
+// WidgetSkill is a skill in the skills widget
+type WidgetSkill struct {
+	Name  string `json:"name"`
+	Level string `json:"level,omitempty"`
+}
+
+// JournalWidget lists the latest public journal entries of a user, for embedding on other sites
+type JournalWidget struct {
+	UserID string `json:"userID"`
+	// Name is the user's name when their profile shows it to everyone
+	Name    string        `json:"name,omitempty"`
+	Entries []WidgetEntry `json:"entries"`
+}
+
+// WidgetEntry is a journal entry in the journal widget
+type WidgetEntry struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Summary   string    `json:"summary,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	URL       string    `json:"url"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// OEmbed is the oEmbed response for a widget, which sites fetch to show it inline
+type OEmbed struct {
+	Type         string `json:"type"`
+	Version      string `json:"version"`
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name,omitempty"`
+	ProviderName string `json:"provider_name,omitempty"`
+	ProviderURL  string `json:"provider_url"`
+	// CacheAge is how long, in seconds, the response may be cached
+	CacheAge int    `json:"cache_age"`
+	HTML     string `json:"html"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
@@ -0,0 +1,384 @@
+// Package widgets serves the skills matrix and latest journal entries of users as compact JSON for embedding
+// on other sites, along with an oEmbed endpoint returning them as ready-made HTML. Widgets only show what
+// everyone may see, so any site may fetch them and shared caches may keep them for a while. Users who
+// restricted their profile to their allowlist have no widgets.
+package widgets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"profile-api/apierror"
+	"profile-api/auth"
+	"profile-api/config"
+	"profile-api/journal"
+	"profile-api/moderation"
+	"profile-api/privacy"
+	"profile-api/profile"
+	"profile-api/sanitize"
+	"profile-api/skills"
+	"profile-api/store"
+	"profile-api/tenant"
+	"profile-api/utils"
+	"profile-api/visibility"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Widget kinds, named by the last segment of their path
+const (
+	KindSkills  = "skills"
+	KindJournal = "journal"
+)
+
+const (
+	// summaryLength is the longest summary of a journal entry without one of its own, cut from its content
+	summaryLength = 280
+	// defaultWidth and defaultHeight size the oEmbed HTML when the consumer sets no maximum
+	defaultWidth  = 400
+	defaultHeight = 300
+)
+
+// Sources are the repositories the widgets are built from. Journals is nil when the journal module is
+// disabled, leaving out the journal widget.
+type Sources struct {
+	Users    auth.Repository
+	Profiles profile.Repository
+	Skills   skills.Repository
+	Journals journal.Repository
+}
+
+var sources Sources
+var settings config.EmbedConfig
+
+var publicBaseURL = "http://localhost:8080"
+
+// Configure sets where the widgets are built from and how long they may be cached
+func Configure(s Sources, cfg config.EmbedConfig) {
+	sources = s
+	settings = cfg
+}
+
+// SetBaseURL sets the public base URL of the widgets, which the oEmbed endpoint accepts
+func SetBaseURL(url string) {
+	publicBaseURL = strings.TrimSuffix(url, "/")
+}
+
+// baseURL returns the public base URL of the widgets, which tenants may override
+func baseURL(ctx context.Context) string {
+	if t, ok := tenant.Current(ctx); ok && t.PublicBaseURL != "" {
+		return strings.TrimSuffix(t.PublicBaseURL, "/")
+	}
+	return publicBaseURL
+}
+
+// widgetURL returns the URL of the user's widget of the kind
+func widgetURL(ctx context.Context, userID, kind string) string {
+	return baseURL(ctx) + "/api/v1/embed/" + url.PathEscape(userID) + "/" + kind
+}
+
+// GetSkillsWidget returns a user's skills widget
+//
+//	@Summary		Get a user's skills widget
+//	@Description	Returns the user's skills with their proficiency, in compact JSON for embedding on other sites. Only what everyone may see is included. Any origin may fetch it, and caches may keep it for the configured time.
+//	@Tags			embed
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Success		200		{object}	SkillsWidget
+//	@Success		304		"Not modified"
+//	@Failure		404		{object}	apierror.Response	"User not found"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve skills"
+//	@Router			/embed/{userid}/skills [get]
+func GetSkillsWidget(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	widget, err := skillsWidget(store.PublicRead(ctx), c.Param("userid"))
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	link(c, widgetURL(ctx, widget.UserID, KindSkills))
+	utils.ConditionalJSON(c, widget, time.Time{})
+}
+
+// GetJournalWidget returns a user's journal widget
+//
+//	@Summary		Get a user's journal widget
+//	@Description	Returns the user's latest public journal entries, newest first, in compact JSON for embedding on other sites. Any origin may fetch it, and caches may keep it for the configured time.
+//	@Tags			embed
+//	@Produce		json
+//	@Param			userid	path		string	true	"User ID"
+//	@Success		200		{object}	JournalWidget
+//	@Success		304		"Not modified"
+//	@Failure		404		{object}	apierror.Response	"User not found"
+//	@Failure		500		{object}	apierror.Response	"Could not retrieve journal entries"
+//	@Router			/embed/{userid}/journal [get]
+func GetJournalWidget(c *gin.Context) {
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	widget, err := journalWidget(store.PublicRead(ctx), c.Param("userid"))
+	if err != nil {
+		apierror.Abort(c, err)
+		return
+	}
+
+	var lastModified time.Time
+	if len(widget.Entries) > 0 {
+		lastModified = widget.Entries[0].UpdatedAt
+	}
+	link(c, widgetURL(ctx, widget.UserID, KindJournal))
+	utils.ConditionalJSON(c, widget, lastModified)
+}
+
+// GetOEmbed returns the oEmbed response for a widget
+//
+//	@Summary		Get the oEmbed response for a widget
+//	@Description	Returns a widget as HTML to show inline, for sites and editors supporting oEmbed. The url parameter is the URL of a skills or journal widget. Only the JSON format is supported.
+//	@Tags			embed
+//	@Produce		json
+//	@Param			url			query		string	true	"URL of the widget"
+//	@Param			format		query		string	false	"Response format"	Enums(json)
+//	@Param			maxwidth	query		int		false	"Maximum width of the HTML, in pixels"
+//	@Param			maxheight	query		int		false	"Maximum height of the HTML, in pixels"
+//	@Success		200			{object}	OEmbed
+//	@Failure		404			{object}	apierror.Response	"Not a widget URL, or user not found"
+//	@Failure		500			{object}	apierror.Response	"Could not retrieve widget"
+//	@Failure		501			{object}	apierror.Response	"Format not supported"
+//	@Router			/embed/oembed [get]
+func GetOEmbed(c *gin.Context) {
+	if format := c.Query("format"); format != "" && format != "json" {
+		apierror.Abort(c, apierror.New(http.StatusNotImplemented, apierror.CodeNotImplemented, "Only the json format is supported"))
+		return
+	}
+
+	ctx, cancel := utils.DBContext(c)
+	defer cancel()
+	userID, kind, ok := parseWidgetURL(ctx, c.Query("url"))
+	if !ok {
+		apierror.Abort(c, apierror.NotFound("Not a widget URL"))
+		return
+	}
+	width := dimension(c.Query("maxwidth"), defaultWidth)
+	height := dimension(c.Query("maxheight"), defaultHeight)
+
+	var title, name string
+	var data any
+	switch kind {
+	case KindSkills:
+		widget, err := skillsWidget(store.PublicRead(ctx), userID)
+		if err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+		title, name, data = "Skills", widget.Name, widget
+	default:
+		widget, err := journalWidget(store.PublicRead(ctx), userID)
+		if err != nil {
+			apierror.Abort(c, err)
+			return
+		}
+		title, name, data = "Latest posts", widget.Name, widget
+	}
+	if name != "" {
+		title = fmt.Sprintf("%s of %s", title, name)
+	}
+	var html bytes.Buffer
+	err := htmlTemplates.ExecuteTemplate(&html, kind, struct {
+		Title         string
+		Width, Height int
+		Widget        any
+	}{title, width, height, data})
+	if err != nil {
+		apierror.Abort(c, apierror.Wrap(err, "Could not render widget"))
+		return
+	}
+
+	c.JSON(http.StatusOK, OEmbed{
+		Type:         "rich",
+		Version:      "1.0",
+		Title:        title,
+		AuthorName:   name,
+		ProviderName: tenant.BrandingOf(ctx).Name,
+		ProviderURL:  baseURL(ctx),
+		CacheAge:     int(settings.MaxAge.Std().Seconds()),
+		HTML:         html.String(),
+		Width:        width,
+		Height:       height,
+	})
+}
+
+// skillsWidget builds the user's skills widget, or returns an error to respond with
+func skillsWidget(ctx context.Context, userID string) (SkillsWidget, error) {
+	name, err := owner(ctx, userID)
+	if err != nil {
+		return SkillsWidget{}, err
+	}
+	list, err := sources.Skills.List(ctx, userID)
+	if err == nil {
+		list, err = visibility.StripAll(visibility.Viewer{}, list)
+	}
+	if err != nil {
+		return SkillsWidget{}, apierror.Wrap(err, "Could not retrieve skills")
+	}
+
+	widget := SkillsWidget{UserID: userID, Name: name, Skills: make([]WidgetSkill, 0, len(list))}
+	for _, s := range list {
+		if s.Name != "" {
+			widget.Skills = append(widget.Skills, WidgetSkill{Name: s.Name, Level: s.ProficiencyLevel})
+		}
+	}
+	return widget, nil
+}
+
+// journalWidget builds the user's journal widget, or returns an error to respond with
+func journalWidget(ctx context.Context, userID string) (JournalWidget, error) {
+	name, err := owner(ctx, userID)
+	if err != nil {
+		return JournalWidget{}, err
+	}
+	entries, err := sources.Journals.List(ctx, journal.Filter{UserID: userID, Status: journal.StatusPublic})
+	if err != nil {
+		return JournalWidget{}, apierror.Wrap(err, "Could not retrieve journal entries")
+	}
+	slices.SortFunc(entries, func(a, b journal.JournalEntry) int { return b.UpdatedAt.Compare(a.UpdatedAt) })
+	if len(entries) > settings.JournalEntries {
+		entries = entries[:settings.JournalEntries]
+	}
+
+	widget := JournalWidget{UserID: userID, Name: name, Entries: make([]WidgetEntry, 0, len(entries))}
+	for _, entry := range entries {
+		var latest journal.Entry
+		if len(entry.Entries) > 0 {
+			latest = entry.Entries[len(entry.Entries)-1]
+		}
+		summary := strings.TrimSpace(entry.Summary)
+		if summary == "" {
+			summary = strings.TrimSpace(sanitize.StripTags(latest.Content))
+			if len(summary) > summaryLength {
+				summary = strings.TrimSpace(strings.ToValidUTF8(summary[:summaryLength], "")) + "…"
+			}
+		}
+		widget.Entries = append(widget.Entries, WidgetEntry{
+			ID:        entry.JournalID,
+			Title:     latest.Title,
+			Summary:   summary,
+			Tags:      entry.Taxonomy.Tags,
+			URL:       baseURL(ctx) + "/api/v1/journal/" + url.PathEscape(entry.JournalID),
+			UpdatedAt: entry.UpdatedAt,
+		})
+	}
+	return widget, nil
+}
+
+// owner returns the name the user shows everyone, or an error to respond with when they do not exist, are
+// disabled, had their profile hidden by moderation or restricted it
+func owner(ctx context.Context, userID string) (string, error) {
+	user, err := sources.Users.FindByID(ctx, userID)
+	if err == nil && user.Disabled {
+		err = store.ErrNotFound
+	}
+	if err == nil {
+		var hidden bool
+		if hidden, err = moderation.ProfileHidden(ctx, userID); err == nil && hidden {
+			err = store.ErrNotFound
+		}
+	}
+	if err == nil {
+		var restricted bool
+		if restricted, err = privacy.Restricted(ctx, userID); err == nil && restricted {
+			err = store.ErrNotFound
+		}
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		return "", apierror.NotFound("User not found")
+	}
+	if err != nil {
+		return "", apierror.Wrap(err, "Could not retrieve user")
+	}
+
+	p, err := sources.Profiles.Get(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return "", nil
+	}
+	if err == nil {
+		p, err = visibility.Strip(visibility.Viewer{}, p)
+	}
+	if err != nil {
+		return "", apierror.Wrap(err, "Could not retrieve profile")
+	}
+	if p.Name == nil {
+		return "", nil
+	}
+	return *p.Name, nil
+}
+
+// parseWidgetURL returns the user and kind of the widget at the URL, which must be on this site
+func parseWidgetURL(ctx context.Context, raw string) (userID, kind string, ok bool) {
+	prefix := baseURL(ctx) + "/api/v1/embed/"
+	if !strings.HasPrefix(raw, prefix) {
+		return "", "", false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", false
+	}
+	userID, kind, found := strings.Cut(strings.TrimPrefix(u.Path, strings.TrimPrefix(prefix, baseURL(ctx))), "/")
+	if !found || userID == "" || (kind != KindSkills && (kind != KindJournal || sources.Journals == nil)) {
+		return "", "", false
+	}
+	return userID, kind, true
+}
+
+// dimension returns the maximum size the consumer asked for, or the default when it is missing or larger
+func dimension(value string, def int) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 || n > def {
+		return def
+	}
+	return n
+}
+
+// link advertises the oEmbed endpoint of the widget at the URL, for oEmbed discovery
+func link(c *gin.Context, widget string) {
+	oembed := baseURL(c.Request.Context()) + "/api/v1/embed/oembed?format=json&url=" + url.QueryEscape(widget)
+	c.Header("Link", fmt.Sprintf(`<%s>; rel="alternate"; type="application/json+oembed"`, oembed))
+}
+
+// public lets any site fetch the widgets, without the credentials of its visitors, and lets caches keep them
+func public() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Writer.Header().Del("Access-Control-Allow-Credentials")
+		c.Writer.Header().Del("Vary")
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(settings.MaxAge.Std().Seconds())))
+		c.Next()
+	}
+}
+
+// htmlTemplates render the widgets for the oEmbed endpoint, one template per kind, escaping the users' text
+var htmlTemplates = template.Must(template.New("widgets").Parse(
+	`{{define "skills"}}<div class="profile-widget profile-widget-skills" style="max-width:{{.Width}}px;max-height:{{.Height}}px;overflow:auto">` +
+		`<h3>{{.Title}}</h3><ul>{{range .Widget.Skills}}<li>{{.Name}}{{if .Level}} <small>{{.Level}}</small>{{end}}</li>{{end}}</ul></div>{{end}}` +
+		`{{define "journal"}}<div class="profile-widget profile-widget-journal" style="max-width:{{.Width}}px;max-height:{{.Height}}px;overflow:auto">` +
+		`<h3>{{.Title}}</h3><ul>{{range .Widget.Entries}}<li><a href="{{.URL}}">{{.Title}}</a>{{if .Summary}}<p>{{.Summary}}</p>{{end}}</li>{{end}}</ul></div>{{end}}`,
+))
+
+// InitializeRoutes initializes the widget routes. Configure must have been called first.
+func InitializeRoutes(router *gin.RouterGroup) {
+	router.Use(public())
+	router.GET("/oembed", GetOEmbed)
+	router.GET("/:userid/skills", GetSkillsWidget)
+	if sources.Journals != nil {
+		router.GET("/:userid/journal", GetJournalWidget)
+	}
+}